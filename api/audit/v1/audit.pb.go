@@ -0,0 +1,438 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: api/audit/v1/audit.proto
+
+package auditv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType     string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`             // e.g. order.placed, trade.executed, account.deposit
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                                    // Reporting service instance
+	CorrelationId string                 `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // Shared by the events one request caused
+	AccountId     string                 `protobuf:"bytes,5,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,6,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId       string                 `protobuf:"bytes,7,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TradeId       string                 `protobuf:"bytes,8,opt,name=trade_id,json=tradeId,proto3" json:"trade_id,omitempty"`
+	OccurredAtMs  int64                  `protobuf:"varint,9,opt,name=occurred_at_ms,json=occurredAtMs,proto3" json:"occurred_at_ms,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,10,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_api_audit_v1_audit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_audit_v1_audit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_api_audit_v1_audit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuditEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTradeId() string {
+	if x != nil {
+		return x.TradeId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOccurredAtMs() int64 {
+	if x != nil {
+		return x.OccurredAtMs
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type SubmitEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitEventsRequest) Reset() {
+	*x = SubmitEventsRequest{}
+	mi := &file_api_audit_v1_audit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitEventsRequest) ProtoMessage() {}
+
+func (x *SubmitEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_audit_v1_audit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubmitEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_audit_v1_audit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitEventsRequest) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type SubmitEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      int32                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"` // Events recorded; duplicates are not counted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitEventsResponse) Reset() {
+	*x = SubmitEventsResponse{}
+	mi := &file_api_audit_v1_audit_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitEventsResponse) ProtoMessage() {}
+
+func (x *SubmitEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_audit_v1_audit_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitEventsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitEventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_audit_v1_audit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitEventsResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // Increases by one per batch on the stream, from 1
+	Events        []*AuditEvent          `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_api_audit_v1_audit_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_audit_v1_audit_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_audit_v1_audit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamEventsRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamEventsRequest) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type StreamEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // The batch acknowledged
+	Accepted      int32                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"` // Events recorded; duplicates are not counted
+	Window        int32                  `protobuf:"varint,3,opt,name=window,proto3" json:"window,omitempty"`     // Batches the client may leave unacknowledged from now on (0 = unchanged)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsResponse) Reset() {
+	*x = StreamEventsResponse{}
+	mi := &file_api_audit_v1_audit_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsResponse) ProtoMessage() {}
+
+func (x *StreamEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_audit_v1_audit_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_audit_v1_audit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamEventsResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamEventsResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *StreamEventsResponse) GetWindow() int32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+var File_api_audit_v1_audit_proto protoreflect.FileDescriptor
+
+const file_api_audit_v1_audit_proto_rawDesc = "" +
+	"\n" +
+	"\x18api/audit/v1/audit.proto\x12\baudit.v1\"\x9d\x03\n" +
+	"\n" +
+	"AuditEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12%\n" +
+	"\x0ecorrelation_id\x18\x04 \x01(\tR\rcorrelationId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x05 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x06 \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\a \x01(\tR\aorderId\x12\x19\n" +
+	"\btrade_id\x18\b \x01(\tR\atradeId\x12$\n" +
+	"\x0eoccurred_at_ms\x18\t \x01(\x03R\foccurredAtMs\x12D\n" +
+	"\n" +
+	"attributes\x18\n" +
+	" \x03(\v2$.audit.v1.AuditEvent.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"C\n" +
+	"\x13SubmitEventsRequest\x12,\n" +
+	"\x06events\x18\x01 \x03(\v2\x14.audit.v1.AuditEventR\x06events\"2\n" +
+	"\x14SubmitEventsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x05R\baccepted\"_\n" +
+	"\x13StreamEventsRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12,\n" +
+	"\x06events\x18\x02 \x03(\v2\x14.audit.v1.AuditEventR\x06events\"f\n" +
+	"\x14StreamEventsResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x05R\baccepted\x12\x16\n" +
+	"\x06window\x18\x03 \x01(\x05R\x06window2\xb0\x01\n" +
+	"\fAuditService\x12M\n" +
+	"\fSubmitEvents\x12\x1d.audit.v1.SubmitEventsRequest\x1a\x1e.audit.v1.SubmitEventsResponse\x12Q\n" +
+	"\fStreamEvents\x12\x1d.audit.v1.StreamEventsRequest\x1a\x1e.audit.v1.StreamEventsResponse(\x010\x01BXZVgithub.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1;auditv1b\x06proto3"
+
+var (
+	file_api_audit_v1_audit_proto_rawDescOnce sync.Once
+	file_api_audit_v1_audit_proto_rawDescData []byte
+)
+
+func file_api_audit_v1_audit_proto_rawDescGZIP() []byte {
+	file_api_audit_v1_audit_proto_rawDescOnce.Do(func() {
+		file_api_audit_v1_audit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_audit_v1_audit_proto_rawDesc), len(file_api_audit_v1_audit_proto_rawDesc)))
+	})
+	return file_api_audit_v1_audit_proto_rawDescData
+}
+
+var file_api_audit_v1_audit_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_audit_v1_audit_proto_goTypes = []any{
+	(*AuditEvent)(nil),           // 0: audit.v1.AuditEvent
+	(*SubmitEventsRequest)(nil),  // 1: audit.v1.SubmitEventsRequest
+	(*SubmitEventsResponse)(nil), // 2: audit.v1.SubmitEventsResponse
+	(*StreamEventsRequest)(nil),  // 3: audit.v1.StreamEventsRequest
+	(*StreamEventsResponse)(nil), // 4: audit.v1.StreamEventsResponse
+	nil,                          // 5: audit.v1.AuditEvent.AttributesEntry
+}
+var file_api_audit_v1_audit_proto_depIdxs = []int32{
+	5, // 0: audit.v1.AuditEvent.attributes:type_name -> audit.v1.AuditEvent.AttributesEntry
+	0, // 1: audit.v1.SubmitEventsRequest.events:type_name -> audit.v1.AuditEvent
+	0, // 2: audit.v1.StreamEventsRequest.events:type_name -> audit.v1.AuditEvent
+	1, // 3: audit.v1.AuditService.SubmitEvents:input_type -> audit.v1.SubmitEventsRequest
+	3, // 4: audit.v1.AuditService.StreamEvents:input_type -> audit.v1.StreamEventsRequest
+	2, // 5: audit.v1.AuditService.SubmitEvents:output_type -> audit.v1.SubmitEventsResponse
+	4, // 6: audit.v1.AuditService.StreamEvents:output_type -> audit.v1.StreamEventsResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_audit_v1_audit_proto_init() }
+func file_api_audit_v1_audit_proto_init() {
+	if File_api_audit_v1_audit_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_audit_v1_audit_proto_rawDesc), len(file_api_audit_v1_audit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_audit_v1_audit_proto_goTypes,
+		DependencyIndexes: file_api_audit_v1_audit_proto_depIdxs,
+		MessageInfos:      file_api_audit_v1_audit_proto_msgTypes,
+	}.Build()
+	File_api_audit_v1_audit_proto = out.File
+	file_api_audit_v1_audit_proto_goTypes = nil
+	file_api_audit_v1_audit_proto_depIdxs = nil
+}
@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: api/audit/v1/audit.proto
+
+package auditv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AuditService_SubmitEvents_FullMethodName = "/audit.v1.AuditService/SubmitEvents"
+	AuditService_StreamEvents_FullMethodName = "/audit.v1.AuditService/StreamEvents"
+)
+
+// AuditServiceClient is the client API for AuditService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuditServiceClient interface {
+	// SubmitEvents records a batch of events
+	SubmitEvents(ctx context.Context, in *SubmitEventsRequest, opts ...grpc.CallOption) (*SubmitEventsResponse, error)
+	// StreamEvents records batches sent over one long-lived stream, acknowledging each
+	// by sequence. The client keeps at most window batches unacknowledged, so a
+	// correlator that falls behind slows the client rather than buffering without end.
+	StreamEvents(ctx context.Context, opts ...grpc.CallOption) (AuditService_StreamEventsClient, error)
+}
+
+type auditServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuditServiceClient(cc grpc.ClientConnInterface) AuditServiceClient {
+	return &auditServiceClient{cc}
+}
+
+func (c *auditServiceClient) SubmitEvents(ctx context.Context, in *SubmitEventsRequest, opts ...grpc.CallOption) (*SubmitEventsResponse, error) {
+	out := new(SubmitEventsResponse)
+	err := c.cc.Invoke(ctx, AuditService_SubmitEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditServiceClient) StreamEvents(ctx context.Context, opts ...grpc.CallOption) (AuditService_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AuditService_ServiceDesc.Streams[0], AuditService_StreamEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &auditServiceStreamEventsClient{stream}
+	return x, nil
+}
+
+type AuditService_StreamEventsClient interface {
+	Send(*StreamEventsRequest) error
+	Recv() (*StreamEventsResponse, error)
+	grpc.ClientStream
+}
+
+type auditServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *auditServiceStreamEventsClient) Send(m *StreamEventsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *auditServiceStreamEventsClient) Recv() (*StreamEventsResponse, error) {
+	m := new(StreamEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AuditServiceServer is the server API for AuditService service.
+// All implementations must embed UnimplementedAuditServiceServer
+// for forward compatibility
+type AuditServiceServer interface {
+	// SubmitEvents records a batch of events
+	SubmitEvents(context.Context, *SubmitEventsRequest) (*SubmitEventsResponse, error)
+	// StreamEvents records batches sent over one long-lived stream, acknowledging each
+	// by sequence. The client keeps at most window batches unacknowledged, so a
+	// correlator that falls behind slows the client rather than buffering without end.
+	StreamEvents(AuditService_StreamEventsServer) error
+	mustEmbedUnimplementedAuditServiceServer()
+}
+
+// UnimplementedAuditServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAuditServiceServer struct {
+}
+
+func (UnimplementedAuditServiceServer) SubmitEvents(context.Context, *SubmitEventsRequest) (*SubmitEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitEvents not implemented")
+}
+func (UnimplementedAuditServiceServer) StreamEvents(AuditService_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedAuditServiceServer) mustEmbedUnimplementedAuditServiceServer() {}
+
+// UnsafeAuditServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuditServiceServer will
+// result in compilation errors.
+type UnsafeAuditServiceServer interface {
+	mustEmbedUnimplementedAuditServiceServer()
+}
+
+func RegisterAuditServiceServer(s grpc.ServiceRegistrar, srv AuditServiceServer) {
+	s.RegisterService(&AuditService_ServiceDesc, srv)
+}
+
+func _AuditService_SubmitEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).SubmitEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_SubmitEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).SubmitEvents(ctx, req.(*SubmitEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuditServiceServer).StreamEvents(&auditServiceStreamEventsServer{stream})
+}
+
+type AuditService_StreamEventsServer interface {
+	Send(*StreamEventsResponse) error
+	Recv() (*StreamEventsRequest, error)
+	grpc.ServerStream
+}
+
+type auditServiceStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *auditServiceStreamEventsServer) Send(m *StreamEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *auditServiceStreamEventsServer) Recv() (*StreamEventsRequest, error) {
+	m := new(StreamEventsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AuditService_ServiceDesc is the grpc.ServiceDesc for AuditService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuditService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "audit.v1.AuditService",
+	HandlerType: (*AuditServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitEvents",
+			Handler:    _AuditService_SubmitEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _AuditService_StreamEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/audit/v1/audit.proto",
+}
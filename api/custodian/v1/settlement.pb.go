@@ -0,0 +1,490 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: api/custodian/v1/settlement.proto
+
+package custodianv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SettlementDirection int32
+
+const (
+	SettlementDirection_SETTLEMENT_DIRECTION_UNSPECIFIED SettlementDirection = 0
+	SettlementDirection_SETTLEMENT_DIRECTION_DELIVER     SettlementDirection = 1 // The account delivers the asset to its counterparty
+	SettlementDirection_SETTLEMENT_DIRECTION_RECEIVE     SettlementDirection = 2 // The account receives the asset from its counterparty
+)
+
+// Enum value maps for SettlementDirection.
+var (
+	SettlementDirection_name = map[int32]string{
+		0: "SETTLEMENT_DIRECTION_UNSPECIFIED",
+		1: "SETTLEMENT_DIRECTION_DELIVER",
+		2: "SETTLEMENT_DIRECTION_RECEIVE",
+	}
+	SettlementDirection_value = map[string]int32{
+		"SETTLEMENT_DIRECTION_UNSPECIFIED": 0,
+		"SETTLEMENT_DIRECTION_DELIVER":     1,
+		"SETTLEMENT_DIRECTION_RECEIVE":     2,
+	}
+)
+
+func (x SettlementDirection) Enum() *SettlementDirection {
+	p := new(SettlementDirection)
+	*p = x
+	return p
+}
+
+func (x SettlementDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SettlementDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_custodian_v1_settlement_proto_enumTypes[0].Descriptor()
+}
+
+func (SettlementDirection) Type() protoreflect.EnumType {
+	return &file_api_custodian_v1_settlement_proto_enumTypes[0]
+}
+
+func (x SettlementDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SettlementDirection.Descriptor instead.
+func (SettlementDirection) EnumDescriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{0}
+}
+
+type SettlementAckStatus int32
+
+const (
+	SettlementAckStatus_SETTLEMENT_ACK_STATUS_UNSPECIFIED SettlementAckStatus = 0
+	SettlementAckStatus_SETTLEMENT_ACK_STATUS_ACCEPTED    SettlementAckStatus = 1
+	SettlementAckStatus_SETTLEMENT_ACK_STATUS_REJECTED    SettlementAckStatus = 2
+)
+
+// Enum value maps for SettlementAckStatus.
+var (
+	SettlementAckStatus_name = map[int32]string{
+		0: "SETTLEMENT_ACK_STATUS_UNSPECIFIED",
+		1: "SETTLEMENT_ACK_STATUS_ACCEPTED",
+		2: "SETTLEMENT_ACK_STATUS_REJECTED",
+	}
+	SettlementAckStatus_value = map[string]int32{
+		"SETTLEMENT_ACK_STATUS_UNSPECIFIED": 0,
+		"SETTLEMENT_ACK_STATUS_ACCEPTED":    1,
+		"SETTLEMENT_ACK_STATUS_REJECTED":    2,
+	}
+)
+
+func (x SettlementAckStatus) Enum() *SettlementAckStatus {
+	p := new(SettlementAckStatus)
+	*p = x
+	return p
+}
+
+func (x SettlementAckStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SettlementAckStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_custodian_v1_settlement_proto_enumTypes[1].Descriptor()
+}
+
+func (SettlementAckStatus) Type() protoreflect.EnumType {
+	return &file_api_custodian_v1_settlement_proto_enumTypes[1]
+}
+
+func (x SettlementAckStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SettlementAckStatus.Descriptor instead.
+func (SettlementAckStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{1}
+}
+
+type SettlementInstruction struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	InstructionId         string                 `protobuf:"bytes,1,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"` // Unique per venue; resubmissions repeat it
+	AccountId             string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Asset                 string                 `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount                float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"` // Always positive; the direction says which way it moves
+	Direction             SettlementDirection    `protobuf:"varint,5,opt,name=direction,proto3,enum=custodian.v1.SettlementDirection" json:"direction,omitempty"`
+	CounterpartyAccountId string                 `protobuf:"bytes,6,opt,name=counterparty_account_id,json=counterpartyAccountId,proto3" json:"counterparty_account_id,omitempty"`
+	TradeIds              []string               `protobuf:"bytes,7,rep,name=trade_ids,json=tradeIds,proto3" json:"trade_ids,omitempty"`
+	CreatedAtMs           int64                  `protobuf:"varint,8,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *SettlementInstruction) Reset() {
+	*x = SettlementInstruction{}
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SettlementInstruction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementInstruction) ProtoMessage() {}
+
+func (x *SettlementInstruction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementInstruction.ProtoReflect.Descriptor instead.
+func (*SettlementInstruction) Descriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SettlementInstruction) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *SettlementInstruction) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SettlementInstruction) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *SettlementInstruction) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SettlementInstruction) GetDirection() SettlementDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return SettlementDirection_SETTLEMENT_DIRECTION_UNSPECIFIED
+}
+
+func (x *SettlementInstruction) GetCounterpartyAccountId() string {
+	if x != nil {
+		return x.CounterpartyAccountId
+	}
+	return ""
+}
+
+func (x *SettlementInstruction) GetTradeIds() []string {
+	if x != nil {
+		return x.TradeIds
+	}
+	return nil
+}
+
+func (x *SettlementInstruction) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+type SubmitSettlementInstructionsRequest struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Venue         string                   `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	BatchId       string                   `protobuf:"bytes,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Instructions  []*SettlementInstruction `protobuf:"bytes,3,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitSettlementInstructionsRequest) Reset() {
+	*x = SubmitSettlementInstructionsRequest{}
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitSettlementInstructionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSettlementInstructionsRequest) ProtoMessage() {}
+
+func (x *SubmitSettlementInstructionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSettlementInstructionsRequest.ProtoReflect.Descriptor instead.
+func (*SubmitSettlementInstructionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitSettlementInstructionsRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *SubmitSettlementInstructionsRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *SubmitSettlementInstructionsRequest) GetInstructions() []*SettlementInstruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+type SettlementAck struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	InstructionId      string                 `protobuf:"bytes,1,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
+	Status             SettlementAckStatus    `protobuf:"varint,2,opt,name=status,proto3,enum=custodian.v1.SettlementAckStatus" json:"status,omitempty"`
+	CustodianReference string                 `protobuf:"bytes,3,opt,name=custodian_reference,json=custodianReference,proto3" json:"custodian_reference,omitempty"`
+	Reason             string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"` // Why the instruction was rejected
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SettlementAck) Reset() {
+	*x = SettlementAck{}
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SettlementAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementAck) ProtoMessage() {}
+
+func (x *SettlementAck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementAck.ProtoReflect.Descriptor instead.
+func (*SettlementAck) Descriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SettlementAck) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *SettlementAck) GetStatus() SettlementAckStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SettlementAckStatus_SETTLEMENT_ACK_STATUS_UNSPECIFIED
+}
+
+func (x *SettlementAck) GetCustodianReference() string {
+	if x != nil {
+		return x.CustodianReference
+	}
+	return ""
+}
+
+func (x *SettlementAck) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SubmitSettlementInstructionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Acks          []*SettlementAck       `protobuf:"bytes,1,rep,name=acks,proto3" json:"acks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitSettlementInstructionsResponse) Reset() {
+	*x = SubmitSettlementInstructionsResponse{}
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitSettlementInstructionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSettlementInstructionsResponse) ProtoMessage() {}
+
+func (x *SubmitSettlementInstructionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_custodian_v1_settlement_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSettlementInstructionsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitSettlementInstructionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_custodian_v1_settlement_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitSettlementInstructionsResponse) GetAcks() []*SettlementAck {
+	if x != nil {
+		return x.Acks
+	}
+	return nil
+}
+
+var File_api_custodian_v1_settlement_proto protoreflect.FileDescriptor
+
+const file_api_custodian_v1_settlement_proto_rawDesc = "" +
+	"\n" +
+	"!api/custodian/v1/settlement.proto\x12\fcustodian.v1\"\xc5\x02\n" +
+	"\x15SettlementInstruction\x12%\n" +
+	"\x0einstruction_id\x18\x01 \x01(\tR\rinstructionId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05asset\x18\x03 \x01(\tR\x05asset\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12?\n" +
+	"\tdirection\x18\x05 \x01(\x0e2!.custodian.v1.SettlementDirectionR\tdirection\x126\n" +
+	"\x17counterparty_account_id\x18\x06 \x01(\tR\x15counterpartyAccountId\x12\x1b\n" +
+	"\ttrade_ids\x18\a \x03(\tR\btradeIds\x12\"\n" +
+	"\rcreated_at_ms\x18\b \x01(\x03R\vcreatedAtMs\"\x9f\x01\n" +
+	"#SubmitSettlementInstructionsRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12G\n" +
+	"\finstructions\x18\x03 \x03(\v2#.custodian.v1.SettlementInstructionR\finstructions\"\xba\x01\n" +
+	"\rSettlementAck\x12%\n" +
+	"\x0einstruction_id\x18\x01 \x01(\tR\rinstructionId\x129\n" +
+	"\x06status\x18\x02 \x01(\x0e2!.custodian.v1.SettlementAckStatusR\x06status\x12/\n" +
+	"\x13custodian_reference\x18\x03 \x01(\tR\x12custodianReference\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"W\n" +
+	"$SubmitSettlementInstructionsResponse\x12/\n" +
+	"\x04acks\x18\x01 \x03(\v2\x1b.custodian.v1.SettlementAckR\x04acks*\x7f\n" +
+	"\x13SettlementDirection\x12$\n" +
+	" SETTLEMENT_DIRECTION_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cSETTLEMENT_DIRECTION_DELIVER\x10\x01\x12 \n" +
+	"\x1cSETTLEMENT_DIRECTION_RECEIVE\x10\x02*\x84\x01\n" +
+	"\x13SettlementAckStatus\x12%\n" +
+	"!SETTLEMENT_ACK_STATUS_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eSETTLEMENT_ACK_STATUS_ACCEPTED\x10\x01\x12\"\n" +
+	"\x1eSETTLEMENT_ACK_STATUS_REJECTED\x10\x022\x9b\x01\n" +
+	"\x11SettlementService\x12\x85\x01\n" +
+	"\x1cSubmitSettlementInstructions\x121.custodian.v1.SubmitSettlementInstructionsRequest\x1a2.custodian.v1.SubmitSettlementInstructionsResponseB`Z^github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/custodian/v1;custodianv1b\x06proto3"
+
+var (
+	file_api_custodian_v1_settlement_proto_rawDescOnce sync.Once
+	file_api_custodian_v1_settlement_proto_rawDescData []byte
+)
+
+func file_api_custodian_v1_settlement_proto_rawDescGZIP() []byte {
+	file_api_custodian_v1_settlement_proto_rawDescOnce.Do(func() {
+		file_api_custodian_v1_settlement_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_custodian_v1_settlement_proto_rawDesc), len(file_api_custodian_v1_settlement_proto_rawDesc)))
+	})
+	return file_api_custodian_v1_settlement_proto_rawDescData
+}
+
+var file_api_custodian_v1_settlement_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_custodian_v1_settlement_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_api_custodian_v1_settlement_proto_goTypes = []any{
+	(SettlementDirection)(0),                     // 0: custodian.v1.SettlementDirection
+	(SettlementAckStatus)(0),                     // 1: custodian.v1.SettlementAckStatus
+	(*SettlementInstruction)(nil),                // 2: custodian.v1.SettlementInstruction
+	(*SubmitSettlementInstructionsRequest)(nil),  // 3: custodian.v1.SubmitSettlementInstructionsRequest
+	(*SettlementAck)(nil),                        // 4: custodian.v1.SettlementAck
+	(*SubmitSettlementInstructionsResponse)(nil), // 5: custodian.v1.SubmitSettlementInstructionsResponse
+}
+var file_api_custodian_v1_settlement_proto_depIdxs = []int32{
+	0, // 0: custodian.v1.SettlementInstruction.direction:type_name -> custodian.v1.SettlementDirection
+	2, // 1: custodian.v1.SubmitSettlementInstructionsRequest.instructions:type_name -> custodian.v1.SettlementInstruction
+	1, // 2: custodian.v1.SettlementAck.status:type_name -> custodian.v1.SettlementAckStatus
+	4, // 3: custodian.v1.SubmitSettlementInstructionsResponse.acks:type_name -> custodian.v1.SettlementAck
+	3, // 4: custodian.v1.SettlementService.SubmitSettlementInstructions:input_type -> custodian.v1.SubmitSettlementInstructionsRequest
+	5, // 5: custodian.v1.SettlementService.SubmitSettlementInstructions:output_type -> custodian.v1.SubmitSettlementInstructionsResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_api_custodian_v1_settlement_proto_init() }
+func file_api_custodian_v1_settlement_proto_init() {
+	if File_api_custodian_v1_settlement_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_custodian_v1_settlement_proto_rawDesc), len(file_api_custodian_v1_settlement_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_custodian_v1_settlement_proto_goTypes,
+		DependencyIndexes: file_api_custodian_v1_settlement_proto_depIdxs,
+		EnumInfos:         file_api_custodian_v1_settlement_proto_enumTypes,
+		MessageInfos:      file_api_custodian_v1_settlement_proto_msgTypes,
+	}.Build()
+	File_api_custodian_v1_settlement_proto = out.File
+	file_api_custodian_v1_settlement_proto_goTypes = nil
+	file_api_custodian_v1_settlement_proto_depIdxs = nil
+}
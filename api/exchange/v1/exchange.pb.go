@@ -0,0 +1,5031 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: api/exchange/v1/exchange.proto
+
+package exchangev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_BUY         Side = 1
+	Side_SIDE_SELL        Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_BUY",
+		2: "SIDE_SELL",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_BUY":         1,
+		"SIDE_SELL":        2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+type OrderType int32
+
+const (
+	OrderType_ORDER_TYPE_UNSPECIFIED OrderType = 0 // Treated as limit
+	OrderType_ORDER_TYPE_LIMIT       OrderType = 1
+	OrderType_ORDER_TYPE_MARKET      OrderType = 2
+)
+
+// Enum value maps for OrderType.
+var (
+	OrderType_name = map[int32]string{
+		0: "ORDER_TYPE_UNSPECIFIED",
+		1: "ORDER_TYPE_LIMIT",
+		2: "ORDER_TYPE_MARKET",
+	}
+	OrderType_value = map[string]int32{
+		"ORDER_TYPE_UNSPECIFIED": 0,
+		"ORDER_TYPE_LIMIT":       1,
+		"ORDER_TYPE_MARKET":      2,
+	}
+)
+
+func (x OrderType) Enum() *OrderType {
+	p := new(OrderType)
+	*p = x
+	return p
+}
+
+func (x OrderType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderType) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[1]
+}
+
+func (x OrderType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderType.Descriptor instead.
+func (OrderType) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{1}
+}
+
+type TimeInForce int32
+
+const (
+	TimeInForce_TIME_IN_FORCE_UNSPECIFIED TimeInForce = 0 // Treated as GTC
+	TimeInForce_TIME_IN_FORCE_GTC         TimeInForce = 1
+	TimeInForce_TIME_IN_FORCE_IOC         TimeInForce = 2
+	TimeInForce_TIME_IN_FORCE_FOK         TimeInForce = 3
+	TimeInForce_TIME_IN_FORCE_GTD         TimeInForce = 4 // Expires at expire_time_ms
+)
+
+// Enum value maps for TimeInForce.
+var (
+	TimeInForce_name = map[int32]string{
+		0: "TIME_IN_FORCE_UNSPECIFIED",
+		1: "TIME_IN_FORCE_GTC",
+		2: "TIME_IN_FORCE_IOC",
+		3: "TIME_IN_FORCE_FOK",
+		4: "TIME_IN_FORCE_GTD",
+	}
+	TimeInForce_value = map[string]int32{
+		"TIME_IN_FORCE_UNSPECIFIED": 0,
+		"TIME_IN_FORCE_GTC":         1,
+		"TIME_IN_FORCE_IOC":         2,
+		"TIME_IN_FORCE_FOK":         3,
+		"TIME_IN_FORCE_GTD":         4,
+	}
+)
+
+func (x TimeInForce) Enum() *TimeInForce {
+	p := new(TimeInForce)
+	*p = x
+	return p
+}
+
+func (x TimeInForce) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimeInForce) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[2].Descriptor()
+}
+
+func (TimeInForce) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[2]
+}
+
+func (x TimeInForce) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimeInForce.Descriptor instead.
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED      OrderStatus = 0
+	OrderStatus_ORDER_STATUS_NEW              OrderStatus = 1
+	OrderStatus_ORDER_STATUS_PARTIALLY_FILLED OrderStatus = 2
+	OrderStatus_ORDER_STATUS_FILLED           OrderStatus = 3
+	OrderStatus_ORDER_STATUS_CANCELED         OrderStatus = 4
+	OrderStatus_ORDER_STATUS_REJECTED         OrderStatus = 5
+	OrderStatus_ORDER_STATUS_EXPIRED          OrderStatus = 6
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_NEW",
+		2: "ORDER_STATUS_PARTIALLY_FILLED",
+		3: "ORDER_STATUS_FILLED",
+		4: "ORDER_STATUS_CANCELED",
+		5: "ORDER_STATUS_REJECTED",
+		6: "ORDER_STATUS_EXPIRED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED":      0,
+		"ORDER_STATUS_NEW":              1,
+		"ORDER_STATUS_PARTIALLY_FILLED": 2,
+		"ORDER_STATUS_FILLED":           3,
+		"ORDER_STATUS_CANCELED":         4,
+		"ORDER_STATUS_REJECTED":         5,
+		"ORDER_STATUS_EXPIRED":          6,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[3].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[3]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+// SortOrder is the direction history is paged in
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0 // Oldest first
+	SortOrder_SORT_ORDER_ASCENDING   SortOrder = 1
+	SortOrder_SORT_ORDER_DESCENDING  SortOrder = 2 // Newest first
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASCENDING",
+		2: "SORT_ORDER_DESCENDING",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASCENDING":   1,
+		"SORT_ORDER_DESCENDING":  2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[4].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[4]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+// RejectReason is a stable cause for refusing a request
+type RejectReason int32
+
+const (
+	RejectReason_REJECT_REASON_UNSPECIFIED                 RejectReason = 0
+	RejectReason_REJECT_REASON_INVALID_REQUEST             RejectReason = 1
+	RejectReason_REJECT_REASON_INVALID_ACCOUNT             RejectReason = 2
+	RejectReason_REJECT_REASON_INVALID_SIDE                RejectReason = 3
+	RejectReason_REJECT_REASON_INVALID_ORDER_TYPE          RejectReason = 4
+	RejectReason_REJECT_REASON_INVALID_QUANTITY            RejectReason = 5
+	RejectReason_REJECT_REASON_INVALID_PRICE               RejectReason = 6
+	RejectReason_REJECT_REASON_PRICE_OUT_OF_BAND           RejectReason = 7
+	RejectReason_REJECT_REASON_UNKNOWN_INSTRUMENT          RejectReason = 8
+	RejectReason_REJECT_REASON_INSTRUMENT_HALTED           RejectReason = 9
+	RejectReason_REJECT_REASON_MARKET_CLOSED               RejectReason = 10
+	RejectReason_REJECT_REASON_INVALID_PHASE               RejectReason = 11
+	RejectReason_REJECT_REASON_ORDER_NOT_FOUND             RejectReason = 12
+	RejectReason_REJECT_REASON_ORDER_NOT_ACTIVE            RejectReason = 13
+	RejectReason_REJECT_REASON_INVALID_AMEND               RejectReason = 14
+	RejectReason_REJECT_REASON_DUPLICATE_CLIENT_ORDER_ID   RejectReason = 15
+	RejectReason_REJECT_REASON_INSUFFICIENT_BALANCE        RejectReason = 16
+	RejectReason_REJECT_REASON_ENGINE_UNAVAILABLE          RejectReason = 17
+	RejectReason_REJECT_REASON_IDEMPOTENCY_KEY_REUSED      RejectReason = 18
+	RejectReason_REJECT_REASON_SUBSCRIPTION_LIMIT_EXCEEDED RejectReason = 19
+	RejectReason_REJECT_REASON_PERMISSION_DENIED           RejectReason = 20
+	RejectReason_REJECT_REASON_INSUFFICIENT_MARGIN         RejectReason = 21
+	RejectReason_REJECT_REASON_UNAUTHENTICATED             RejectReason = 22
+	RejectReason_REJECT_REASON_RATE_LIMITED                RejectReason = 23
+)
+
+// Enum value maps for RejectReason.
+var (
+	RejectReason_name = map[int32]string{
+		0:  "REJECT_REASON_UNSPECIFIED",
+		1:  "REJECT_REASON_INVALID_REQUEST",
+		2:  "REJECT_REASON_INVALID_ACCOUNT",
+		3:  "REJECT_REASON_INVALID_SIDE",
+		4:  "REJECT_REASON_INVALID_ORDER_TYPE",
+		5:  "REJECT_REASON_INVALID_QUANTITY",
+		6:  "REJECT_REASON_INVALID_PRICE",
+		7:  "REJECT_REASON_PRICE_OUT_OF_BAND",
+		8:  "REJECT_REASON_UNKNOWN_INSTRUMENT",
+		9:  "REJECT_REASON_INSTRUMENT_HALTED",
+		10: "REJECT_REASON_MARKET_CLOSED",
+		11: "REJECT_REASON_INVALID_PHASE",
+		12: "REJECT_REASON_ORDER_NOT_FOUND",
+		13: "REJECT_REASON_ORDER_NOT_ACTIVE",
+		14: "REJECT_REASON_INVALID_AMEND",
+		15: "REJECT_REASON_DUPLICATE_CLIENT_ORDER_ID",
+		16: "REJECT_REASON_INSUFFICIENT_BALANCE",
+		17: "REJECT_REASON_ENGINE_UNAVAILABLE",
+		18: "REJECT_REASON_IDEMPOTENCY_KEY_REUSED",
+		19: "REJECT_REASON_SUBSCRIPTION_LIMIT_EXCEEDED",
+		20: "REJECT_REASON_PERMISSION_DENIED",
+		21: "REJECT_REASON_INSUFFICIENT_MARGIN",
+		22: "REJECT_REASON_UNAUTHENTICATED",
+		23: "REJECT_REASON_RATE_LIMITED",
+	}
+	RejectReason_value = map[string]int32{
+		"REJECT_REASON_UNSPECIFIED":                 0,
+		"REJECT_REASON_INVALID_REQUEST":             1,
+		"REJECT_REASON_INVALID_ACCOUNT":             2,
+		"REJECT_REASON_INVALID_SIDE":                3,
+		"REJECT_REASON_INVALID_ORDER_TYPE":          4,
+		"REJECT_REASON_INVALID_QUANTITY":            5,
+		"REJECT_REASON_INVALID_PRICE":               6,
+		"REJECT_REASON_PRICE_OUT_OF_BAND":           7,
+		"REJECT_REASON_UNKNOWN_INSTRUMENT":          8,
+		"REJECT_REASON_INSTRUMENT_HALTED":           9,
+		"REJECT_REASON_MARKET_CLOSED":               10,
+		"REJECT_REASON_INVALID_PHASE":               11,
+		"REJECT_REASON_ORDER_NOT_FOUND":             12,
+		"REJECT_REASON_ORDER_NOT_ACTIVE":            13,
+		"REJECT_REASON_INVALID_AMEND":               14,
+		"REJECT_REASON_DUPLICATE_CLIENT_ORDER_ID":   15,
+		"REJECT_REASON_INSUFFICIENT_BALANCE":        16,
+		"REJECT_REASON_ENGINE_UNAVAILABLE":          17,
+		"REJECT_REASON_IDEMPOTENCY_KEY_REUSED":      18,
+		"REJECT_REASON_SUBSCRIPTION_LIMIT_EXCEEDED": 19,
+		"REJECT_REASON_PERMISSION_DENIED":           20,
+		"REJECT_REASON_INSUFFICIENT_MARGIN":         21,
+		"REJECT_REASON_UNAUTHENTICATED":             22,
+		"REJECT_REASON_RATE_LIMITED":                23,
+	}
+)
+
+func (x RejectReason) Enum() *RejectReason {
+	p := new(RejectReason)
+	*p = x
+	return p
+}
+
+func (x RejectReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RejectReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[5].Descriptor()
+}
+
+func (RejectReason) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[5]
+}
+
+func (x RejectReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RejectReason.Descriptor instead.
+func (RejectReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+type SessionEventType int32
+
+const (
+	SessionEventType_SESSION_EVENT_TYPE_UNSPECIFIED SessionEventType = 0
+	SessionEventType_SESSION_EVENT_TYPE_OPENED      SessionEventType = 1 // First event on every session
+	SessionEventType_SESSION_EVENT_TYPE_HEARTBEAT   SessionEventType = 2
+)
+
+// Enum value maps for SessionEventType.
+var (
+	SessionEventType_name = map[int32]string{
+		0: "SESSION_EVENT_TYPE_UNSPECIFIED",
+		1: "SESSION_EVENT_TYPE_OPENED",
+		2: "SESSION_EVENT_TYPE_HEARTBEAT",
+	}
+	SessionEventType_value = map[string]int32{
+		"SESSION_EVENT_TYPE_UNSPECIFIED": 0,
+		"SESSION_EVENT_TYPE_OPENED":      1,
+		"SESSION_EVENT_TYPE_HEARTBEAT":   2,
+	}
+)
+
+func (x SessionEventType) Enum() *SessionEventType {
+	p := new(SessionEventType)
+	*p = x
+	return p
+}
+
+func (x SessionEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_exchange_v1_exchange_proto_enumTypes[6].Descriptor()
+}
+
+func (SessionEventType) Type() protoreflect.EnumType {
+	return &file_api_exchange_v1_exchange_proto_enumTypes[6]
+}
+
+func (x SessionEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionEventType.Descriptor instead.
+func (SessionEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+// OrderSpec describes an order as submitted by a client
+type OrderSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          Side                   `protobuf:"varint,3,opt,name=side,proto3,enum=exchange.v1.Side" json:"side,omitempty"`
+	Type          OrderType              `protobuf:"varint,4,opt,name=type,proto3,enum=exchange.v1.OrderType" json:"type,omitempty"`
+	TimeInForce   TimeInForce            `protobuf:"varint,5,opt,name=time_in_force,json=timeInForce,proto3,enum=exchange.v1.TimeInForce" json:"time_in_force,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,7,opt,name=price,proto3" json:"price,omitempty"`                                      // Ignored for market orders
+	ClientOrderId string                 `protobuf:"bytes,8,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"` // Optional; unique per account, resubmission is idempotent
+	ExpireTimeMs  int64                  `protobuf:"varint,9,opt,name=expire_time_ms,json=expireTimeMs,proto3" json:"expire_time_ms,omitempty"`   // Venue time in Unix milliseconds; required for GTD only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderSpec) Reset() {
+	*x = OrderSpec{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderSpec) ProtoMessage() {}
+
+func (x *OrderSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderSpec.ProtoReflect.Descriptor instead.
+func (*OrderSpec) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderSpec) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OrderSpec) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OrderSpec) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *OrderSpec) GetType() OrderType {
+	if x != nil {
+		return x.Type
+	}
+	return OrderType_ORDER_TYPE_UNSPECIFIED
+}
+
+func (x *OrderSpec) GetTimeInForce() TimeInForce {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return TimeInForce_TIME_IN_FORCE_UNSPECIFIED
+}
+
+func (x *OrderSpec) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderSpec) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderSpec) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *OrderSpec) GetExpireTimeMs() int64 {
+	if x != nil {
+		return x.ExpireTimeMs
+	}
+	return 0
+}
+
+// Order is the venue's view of an order
+type Order struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClientOrderId  string                 `protobuf:"bytes,2,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	AccountId      string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol         string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side           Side                   `protobuf:"varint,5,opt,name=side,proto3,enum=exchange.v1.Side" json:"side,omitempty"`
+	Type           OrderType              `protobuf:"varint,6,opt,name=type,proto3,enum=exchange.v1.OrderType" json:"type,omitempty"`
+	TimeInForce    TimeInForce            `protobuf:"varint,7,opt,name=time_in_force,json=timeInForce,proto3,enum=exchange.v1.TimeInForce" json:"time_in_force,omitempty"`
+	Price          float64                `protobuf:"fixed64,8,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity       float64                `protobuf:"fixed64,9,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	FilledQuantity float64                `protobuf:"fixed64,10,opt,name=filled_quantity,json=filledQuantity,proto3" json:"filled_quantity,omitempty"`
+	AveragePrice   float64                `protobuf:"fixed64,11,opt,name=average_price,json=averagePrice,proto3" json:"average_price,omitempty"`
+	Status         OrderStatus            `protobuf:"varint,12,opt,name=status,proto3,enum=exchange.v1.OrderStatus" json:"status,omitempty"`
+	ExpireTimeMs   int64                  `protobuf:"varint,13,opt,name=expire_time_ms,json=expireTimeMs,proto3" json:"expire_time_ms,omitempty"` // GTD orders only
+	CreatedTimeMs  int64                  `protobuf:"varint,14,opt,name=created_time_ms,json=createdTimeMs,proto3" json:"created_time_ms,omitempty"`
+	UpdatedTimeMs  int64                  `protobuf:"varint,15,opt,name=updated_time_ms,json=updatedTimeMs,proto3" json:"updated_time_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *Order) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Order) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Order) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Order) GetType() OrderType {
+	if x != nil {
+		return x.Type
+	}
+	return OrderType_ORDER_TYPE_UNSPECIFIED
+}
+
+func (x *Order) GetTimeInForce() TimeInForce {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return TimeInForce_TIME_IN_FORCE_UNSPECIFIED
+}
+
+func (x *Order) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Order) GetFilledQuantity() float64 {
+	if x != nil {
+		return x.FilledQuantity
+	}
+	return 0
+}
+
+func (x *Order) GetAveragePrice() float64 {
+	if x != nil {
+		return x.AveragePrice
+	}
+	return 0
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetExpireTimeMs() int64 {
+	if x != nil {
+		return x.ExpireTimeMs
+	}
+	return 0
+}
+
+func (x *Order) GetCreatedTimeMs() int64 {
+	if x != nil {
+		return x.CreatedTimeMs
+	}
+	return 0
+}
+
+func (x *Order) GetUpdatedTimeMs() int64 {
+	if x != nil {
+		return x.UpdatedTimeMs
+	}
+	return 0
+}
+
+// Trade is one execution between a buy and a sell order
+type Trade struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol         string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price          float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity       float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	BuyOrderId     string                 `protobuf:"bytes,5,opt,name=buy_order_id,json=buyOrderId,proto3" json:"buy_order_id,omitempty"`
+	SellOrderId    string                 `protobuf:"bytes,6,opt,name=sell_order_id,json=sellOrderId,proto3" json:"sell_order_id,omitempty"`
+	BuyAccountId   string                 `protobuf:"bytes,7,opt,name=buy_account_id,json=buyAccountId,proto3" json:"buy_account_id,omitempty"`
+	SellAccountId  string                 `protobuf:"bytes,8,opt,name=sell_account_id,json=sellAccountId,proto3" json:"sell_account_id,omitempty"`
+	TakerSide      Side                   `protobuf:"varint,9,opt,name=taker_side,json=takerSide,proto3,enum=exchange.v1.Side" json:"taker_side,omitempty"` // UNSPECIFIED for auction trades
+	Auction        bool                   `protobuf:"varint,10,opt,name=auction,proto3" json:"auction,omitempty"`
+	ExecutedTimeMs int64                  `protobuf:"varint,11,opt,name=executed_time_ms,json=executedTimeMs,proto3" json:"executed_time_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Trade) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Trade) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Trade) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Trade) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Trade) GetBuyOrderId() string {
+	if x != nil {
+		return x.BuyOrderId
+	}
+	return ""
+}
+
+func (x *Trade) GetSellOrderId() string {
+	if x != nil {
+		return x.SellOrderId
+	}
+	return ""
+}
+
+func (x *Trade) GetBuyAccountId() string {
+	if x != nil {
+		return x.BuyAccountId
+	}
+	return ""
+}
+
+func (x *Trade) GetSellAccountId() string {
+	if x != nil {
+		return x.SellAccountId
+	}
+	return ""
+}
+
+func (x *Trade) GetTakerSide() Side {
+	if x != nil {
+		return x.TakerSide
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Trade) GetAuction() bool {
+	if x != nil {
+		return x.Auction
+	}
+	return false
+}
+
+func (x *Trade) GetExecutedTimeMs() int64 {
+	if x != nil {
+		return x.ExecutedTimeMs
+	}
+	return 0
+}
+
+type PlaceOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *OrderSpec             `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Run every check and estimate fills against the current book without placing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlaceOrderRequest) GetOrder() *OrderSpec {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type PlaceOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Trades        []*Trade               `protobuf:"bytes,2,rep,name=trades,proto3" json:"trades,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`         // Resubmitted client order ID; order is the existing order
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Nothing was placed; order and trades are what placing would produce, without IDs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderResponse) Reset() {
+	*x = PlaceOrderResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderResponse) ProtoMessage() {}
+
+func (x *PlaceOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderResponse.ProtoReflect.Descriptor instead.
+func (*PlaceOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PlaceOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *PlaceOrderResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type SubmitOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *OrderSpec             `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitOrderRequest) Reset() {
+	*x = SubmitOrderRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitOrderRequest) ProtoMessage() {}
+
+func (x *SubmitOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitOrderRequest.ProtoReflect.Descriptor instead.
+func (*SubmitOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubmitOrderRequest) GetOrder() *OrderSpec {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type SubmitOrderResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RequestSequence uint64                 `protobuf:"varint,1,opt,name=request_sequence,json=requestSequence,proto3" json:"request_sequence,omitempty"` // Echoed by the order's ack
+	ReceivedTimeMs  int64                  `protobuf:"varint,2,opt,name=received_time_ms,json=receivedTimeMs,proto3" json:"received_time_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SubmitOrderResponse) Reset() {
+	*x = SubmitOrderResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitOrderResponse) ProtoMessage() {}
+
+func (x *SubmitOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitOrderResponse.ProtoReflect.Descriptor instead.
+func (*SubmitOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitOrderResponse) GetRequestSequence() uint64 {
+	if x != nil {
+		return x.RequestSequence
+	}
+	return 0
+}
+
+func (x *SubmitOrderResponse) GetReceivedTimeMs() int64 {
+	if x != nil {
+		return x.ReceivedTimeMs
+	}
+	return 0
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListOpenOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Optional
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                        // Optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOpenOrdersRequest) Reset() {
+	*x = ListOpenOrdersRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOpenOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOpenOrdersRequest) ProtoMessage() {}
+
+func (x *ListOpenOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOpenOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOpenOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListOpenOrdersRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListOpenOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ListOpenOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOpenOrdersResponse) Reset() {
+	*x = ListOpenOrdersResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOpenOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOpenOrdersResponse) ProtoMessage() {}
+
+func (x *ListOpenOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOpenOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOpenOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListOpenOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type GetTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Optional; matches either side
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                        // Optional
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                         // Zero returns every trade kept
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradesRequest) Reset() {
+	*x = GetTradesRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradesRequest) ProtoMessage() {}
+
+func (x *GetTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradesRequest.ProtoReflect.Descriptor instead.
+func (*GetTradesRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTradesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetTradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetTradesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetTradesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trades        []*Trade               `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradesResponse) Reset() {
+	*x = GetTradesResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradesResponse) ProtoMessage() {}
+
+func (x *GetTradesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradesResponse.ProtoReflect.Descriptor instead.
+func (*GetTradesResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTradesResponse) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+type ListOrderHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`                   // Optional
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                                          // Optional
+	Statuses      []OrderStatus          `protobuf:"varint,3,rep,packed,name=statuses,proto3,enum=exchange.v1.OrderStatus" json:"statuses,omitempty"` // Any of them; empty matches every status
+	StartTimeMs   int64                  `protobuf:"varint,4,opt,name=start_time_ms,json=startTimeMs,proto3" json:"start_time_ms,omitempty"`          // Created at or after; zero is unbounded
+	EndTimeMs     int64                  `protobuf:"varint,5,opt,name=end_time_ms,json=endTimeMs,proto3" json:"end_time_ms,omitempty"`                // Created before; zero is unbounded
+	Sort          SortOrder              `protobuf:"varint,6,opt,name=sort,proto3,enum=exchange.v1.SortOrder" json:"sort,omitempty"`
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`  // Zero returns 100; at most 1000
+	Cursor        string                 `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderHistoryRequest) Reset() {
+	*x = ListOrderHistoryRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderHistoryRequest) ProtoMessage() {}
+
+func (x *ListOrderHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListOrderHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListOrderHistoryRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListOrderHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListOrderHistoryRequest) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListOrderHistoryRequest) GetStartTimeMs() int64 {
+	if x != nil {
+		return x.StartTimeMs
+	}
+	return 0
+}
+
+func (x *ListOrderHistoryRequest) GetEndTimeMs() int64 {
+	if x != nil {
+		return x.EndTimeMs
+	}
+	return 0
+}
+
+func (x *ListOrderHistoryRequest) GetSort() SortOrder {
+	if x != nil {
+		return x.Sort
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListOrderHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrderHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListOrderHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderHistoryResponse) Reset() {
+	*x = ListOrderHistoryResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderHistoryResponse) ProtoMessage() {}
+
+func (x *ListOrderHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListOrderHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListOrderHistoryResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrderHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListTradeHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`          // Optional; matches either side
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                                 // Optional
+	StartTimeMs   int64                  `protobuf:"varint,3,opt,name=start_time_ms,json=startTimeMs,proto3" json:"start_time_ms,omitempty"` // Executed at or after; zero is unbounded
+	EndTimeMs     int64                  `protobuf:"varint,4,opt,name=end_time_ms,json=endTimeMs,proto3" json:"end_time_ms,omitempty"`       // Executed before; zero is unbounded
+	Sort          SortOrder              `protobuf:"varint,5,opt,name=sort,proto3,enum=exchange.v1.SortOrder" json:"sort,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`  // Zero returns 100; at most 1000
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTradeHistoryRequest) Reset() {
+	*x = ListTradeHistoryRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTradeHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTradeHistoryRequest) ProtoMessage() {}
+
+func (x *ListTradeHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTradeHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListTradeHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListTradeHistoryRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListTradeHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListTradeHistoryRequest) GetStartTimeMs() int64 {
+	if x != nil {
+		return x.StartTimeMs
+	}
+	return 0
+}
+
+func (x *ListTradeHistoryRequest) GetEndTimeMs() int64 {
+	if x != nil {
+		return x.EndTimeMs
+	}
+	return 0
+}
+
+func (x *ListTradeHistoryRequest) GetSort() SortOrder {
+	if x != nil {
+		return x.Sort
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListTradeHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTradeHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListTradeHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trades        []*Trade               `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTradeHistoryResponse) Reset() {
+	*x = ListTradeHistoryResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTradeHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTradeHistoryResponse) ProtoMessage() {}
+
+func (x *ListTradeHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTradeHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListTradeHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListTradeHistoryResponse) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+func (x *ListTradeHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"` // Levels per side; zero returns all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookRequest) Reset() {
+	*x = GetOrderBookRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookRequest) ProtoMessage() {}
+
+func (x *GetOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetOrderBookRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type PriceLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OrderCount    int32                  `protobuf:"varint,3,opt,name=order_count,json=orderCount,proto3" json:"order_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceLevel) Reset() {
+	*x = PriceLevel{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceLevel) ProtoMessage() {}
+
+func (x *PriceLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceLevel.ProtoReflect.Descriptor instead.
+func (*PriceLevel) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PriceLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceLevel) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PriceLevel) GetOrderCount() int32 {
+	if x != nil {
+		return x.OrderCount
+	}
+	return 0
+}
+
+type GetOrderBookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Phase         string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`        // continuous, auction, closed or halted
+	Bids          []*PriceLevel          `protobuf:"bytes,3,rep,name=bids,proto3" json:"bids,omitempty"`          // Best first
+	Asks          []*PriceLevel          `protobuf:"bytes,4,rep,name=asks,proto3" json:"asks,omitempty"`          // Best first
+	Sequence      uint64                 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"` // Book sequence the levels are as of
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookResponse) Reset() {
+	*x = GetOrderBookResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookResponse) ProtoMessage() {}
+
+func (x *GetOrderBookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderBookResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetOrderBookResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetOrderBookResponse) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *GetOrderBookResponse) GetBids() []*PriceLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *GetOrderBookResponse) GetAsks() []*PriceLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *GetOrderBookResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type GetTickerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTickerRequest) Reset() {
+	*x = GetTickerRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTickerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTickerRequest) ProtoMessage() {}
+
+func (x *GetTickerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTickerRequest.ProtoReflect.Descriptor instead.
+func (*GetTickerRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetTickerRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type GetTickerResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Symbol             string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	LastPrice          float64                `protobuf:"fixed64,2,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	BidPrice           float64                `protobuf:"fixed64,3,opt,name=bid_price,json=bidPrice,proto3" json:"bid_price,omitempty"` // Zero when the side is empty
+	BidQuantity        float64                `protobuf:"fixed64,4,opt,name=bid_quantity,json=bidQuantity,proto3" json:"bid_quantity,omitempty"`
+	AskPrice           float64                `protobuf:"fixed64,5,opt,name=ask_price,json=askPrice,proto3" json:"ask_price,omitempty"` // Zero when the side is empty
+	AskQuantity        float64                `protobuf:"fixed64,6,opt,name=ask_quantity,json=askQuantity,proto3" json:"ask_quantity,omitempty"`
+	OpenPrice          float64                `protobuf:"fixed64,7,opt,name=open_price,json=openPrice,proto3" json:"open_price,omitempty"`
+	HighPrice          float64                `protobuf:"fixed64,8,opt,name=high_price,json=highPrice,proto3" json:"high_price,omitempty"`
+	LowPrice           float64                `protobuf:"fixed64,9,opt,name=low_price,json=lowPrice,proto3" json:"low_price,omitempty"`
+	PriceChange        float64                `protobuf:"fixed64,10,opt,name=price_change,json=priceChange,proto3" json:"price_change,omitempty"`
+	PriceChangePercent float64                `protobuf:"fixed64,11,opt,name=price_change_percent,json=priceChangePercent,proto3" json:"price_change_percent,omitempty"`
+	WeightedAvgPrice   float64                `protobuf:"fixed64,12,opt,name=weighted_avg_price,json=weightedAvgPrice,proto3" json:"weighted_avg_price,omitempty"` // VWAP over the window
+	Volume             float64                `protobuf:"fixed64,13,opt,name=volume,proto3" json:"volume,omitempty"`
+	QuoteVolume        float64                `protobuf:"fixed64,14,opt,name=quote_volume,json=quoteVolume,proto3" json:"quote_volume,omitempty"`
+	TradeCount         int32                  `protobuf:"varint,15,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	LastTradeTimeMs    int64                  `protobuf:"varint,16,opt,name=last_trade_time_ms,json=lastTradeTimeMs,proto3" json:"last_trade_time_ms,omitempty"` // Zero before the first trade
+	WindowStartMs      int64                  `protobuf:"varint,17,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	WindowEndMs        int64                  `protobuf:"varint,18,opt,name=window_end_ms,json=windowEndMs,proto3" json:"window_end_ms,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetTickerResponse) Reset() {
+	*x = GetTickerResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTickerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTickerResponse) ProtoMessage() {}
+
+func (x *GetTickerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTickerResponse.ProtoReflect.Descriptor instead.
+func (*GetTickerResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetTickerResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetTickerResponse) GetLastPrice() float64 {
+	if x != nil {
+		return x.LastPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetBidPrice() float64 {
+	if x != nil {
+		return x.BidPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetBidQuantity() float64 {
+	if x != nil {
+		return x.BidQuantity
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetAskPrice() float64 {
+	if x != nil {
+		return x.AskPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetAskQuantity() float64 {
+	if x != nil {
+		return x.AskQuantity
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetOpenPrice() float64 {
+	if x != nil {
+		return x.OpenPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetHighPrice() float64 {
+	if x != nil {
+		return x.HighPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetLowPrice() float64 {
+	if x != nil {
+		return x.LowPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetPriceChange() float64 {
+	if x != nil {
+		return x.PriceChange
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetPriceChangePercent() float64 {
+	if x != nil {
+		return x.PriceChangePercent
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetWeightedAvgPrice() float64 {
+	if x != nil {
+		return x.WeightedAvgPrice
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetQuoteVolume() float64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetTradeCount() int32 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetLastTradeTimeMs() int64 {
+	if x != nil {
+		return x.LastTradeTimeMs
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetWindowStartMs() int64 {
+	if x != nil {
+		return x.WindowStartMs
+	}
+	return 0
+}
+
+func (x *GetTickerResponse) GetWindowEndMs() int64 {
+	if x != nil {
+		return x.WindowEndMs
+	}
+	return 0
+}
+
+type GetCandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`                             // 1s, 1m, 5m, 15m, 1h, 4h or 1d; empty is 1m
+	StartTimeMs   int64                  `protobuf:"varint,3,opt,name=start_time_ms,json=startTimeMs,proto3" json:"start_time_ms,omitempty"` // With a start, the earliest bars from it; otherwise the latest
+	EndTimeMs     int64                  `protobuf:"varint,4,opt,name=end_time_ms,json=endTimeMs,proto3" json:"end_time_ms,omitempty"`       // Zero is now
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                  // Zero returns 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesRequest) Reset() {
+	*x = GetCandlesRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesRequest) ProtoMessage() {}
+
+func (x *GetCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetCandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetStartTimeMs() int64 {
+	if x != nil {
+		return x.StartTimeMs
+	}
+	return 0
+}
+
+func (x *GetCandlesRequest) GetEndTimeMs() int64 {
+	if x != nil {
+		return x.EndTimeMs
+	}
+	return 0
+}
+
+func (x *GetCandlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OpenTimeMs    int64                  `protobuf:"varint,1,opt,name=open_time_ms,json=openTimeMs,proto3" json:"open_time_ms,omitempty"`
+	CloseTimeMs   int64                  `protobuf:"varint,2,opt,name=close_time_ms,json=closeTimeMs,proto3" json:"close_time_ms,omitempty"`
+	Open          float64                `protobuf:"fixed64,3,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,6,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,7,opt,name=volume,proto3" json:"volume,omitempty"`
+	QuoteVolume   float64                `protobuf:"fixed64,8,opt,name=quote_volume,json=quoteVolume,proto3" json:"quote_volume,omitempty"`
+	TradeCount    int32                  `protobuf:"varint,9,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Candle) GetOpenTimeMs() int64 {
+	if x != nil {
+		return x.OpenTimeMs
+	}
+	return 0
+}
+
+func (x *Candle) GetCloseTimeMs() int64 {
+	if x != nil {
+		return x.CloseTimeMs
+	}
+	return 0
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Candle) GetQuoteVolume() float64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *Candle) GetTradeCount() int32 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+type GetCandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Candles       []*Candle              `protobuf:"bytes,3,rep,name=candles,proto3" json:"candles,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesResponse) Reset() {
+	*x = GetCandlesResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesResponse) ProtoMessage() {}
+
+func (x *GetCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetCandlesResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCandlesResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetCandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetBalancesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// Balance is a net position built from fills; the venue does not hold deposits
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // Opening plus received minus delivered; negative is a short
+	Received      float64                `protobuf:"fixed64,3,opt,name=received,proto3" json:"received,omitempty"`
+	Delivered     float64                `protobuf:"fixed64,4,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Opening       float64                `protobuf:"fixed64,5,opt,name=opening,proto3" json:"opening,omitempty"` // Funded when the account was provisioned
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Balance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Balance) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Balance) GetReceived() float64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *Balance) GetDelivered() float64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *Balance) GetOpening() float64 {
+	if x != nil {
+		return x.Opening
+	}
+	return 0
+}
+
+type GetBalancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Balances      []*Balance             `protobuf:"bytes,2,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetBalancesResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetBalancesResponse) GetBalances() []*Balance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type GetAccountSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountSnapshotRequest) Reset() {
+	*x = GetAccountSnapshotRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountSnapshotRequest) ProtoMessage() {}
+
+func (x *GetAccountSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetAccountSnapshotRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// AccountBalance is what an account holds of an asset, split by what working orders commit
+type AccountBalance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Available     float64                `protobuf:"fixed64,2,opt,name=available,proto3" json:"available,omitempty"`
+	Held          float64                `protobuf:"fixed64,3,opt,name=held,proto3" json:"held,omitempty"`
+	Total         float64                `protobuf:"fixed64,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountBalance) Reset() {
+	*x = AccountBalance{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountBalance) ProtoMessage() {}
+
+func (x *AccountBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountBalance.ProtoReflect.Descriptor instead.
+func (*AccountBalance) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AccountBalance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *AccountBalance) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetHeld() float64 {
+	if x != nil {
+		return x.Held
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Position is a derivative position netted from fills and valued at the mark
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // Negative is a short
+	EntryPrice    float64                `protobuf:"fixed64,3,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	MarkPrice     float64                `protobuf:"fixed64,4,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,5,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,6,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	AccountId     string                 `protobuf:"bytes,7,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`                // Set by GetPositions
+	Asset         string                 `protobuf:"bytes,8,opt,name=asset,proto3" json:"asset,omitempty"`                                         // Quote asset the prices and PnL are in; set by GetPositions
+	UpdatedTimeMs int64                  `protobuf:"varint,9,opt,name=updated_time_ms,json=updatedTimeMs,proto3" json:"updated_time_ms,omitempty"` // Last fill; set by GetPositions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Position) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Position) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Position) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *Position) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *Position) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *Position) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *Position) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Position) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Position) GetUpdatedTimeMs() int64 {
+	if x != nil {
+		return x.UpdatedTimeMs
+	}
+	return 0
+}
+
+type GetAccountSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"` // Last engine event every part reflects; 0 while no event log is recorded
+	TakenTimeMs   int64                  `protobuf:"varint,3,opt,name=taken_time_ms,json=takenTimeMs,proto3" json:"taken_time_ms,omitempty"`
+	Balances      []*AccountBalance      `protobuf:"bytes,4,rep,name=balances,proto3" json:"balances,omitempty"`
+	Positions     []*Position            `protobuf:"bytes,5,rep,name=positions,proto3" json:"positions,omitempty"`
+	OpenOrders    []*Order               `protobuf:"bytes,6,rep,name=open_orders,json=openOrders,proto3" json:"open_orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountSnapshotResponse) Reset() {
+	*x = GetAccountSnapshotResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountSnapshotResponse) ProtoMessage() {}
+
+func (x *GetAccountSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetAccountSnapshotResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetAccountSnapshotResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *GetAccountSnapshotResponse) GetTakenTimeMs() int64 {
+	if x != nil {
+		return x.TakenTimeMs
+	}
+	return 0
+}
+
+func (x *GetAccountSnapshotResponse) GetBalances() []*AccountBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *GetAccountSnapshotResponse) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *GetAccountSnapshotResponse) GetOpenOrders() []*Order {
+	if x != nil {
+		return x.OpenOrders
+	}
+	return nil
+}
+
+type GetPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Empty for every account
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                        // Empty for every symbol
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsRequest) Reset() {
+	*x = GetPositionsRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsRequest) ProtoMessage() {}
+
+func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetPositionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type GetPositionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsResponse) Reset() {
+	*x = GetPositionsResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsResponse) ProtoMessage() {}
+
+func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsResponse.ProtoReflect.Descriptor instead.
+func (*GetPositionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetPositionsResponse) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type CheckOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *OrderSpec             `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckOrderRequest) Reset() {
+	*x = CheckOrderRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckOrderRequest) ProtoMessage() {}
+
+func (x *CheckOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckOrderRequest.ProtoReflect.Descriptor instead.
+func (*CheckOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CheckOrderRequest) GetOrder() *OrderSpec {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CheckOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Reasons       []string               `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`       // Every failed check; empty when accepted
+	Rejections    []*Rejection           `protobuf:"bytes,3,rep,name=rejections,proto3" json:"rejections,omitempty"` // Typed form of reasons, in the same order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckOrderResponse) Reset() {
+	*x = CheckOrderResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckOrderResponse) ProtoMessage() {}
+
+func (x *CheckOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckOrderResponse.ProtoReflect.Descriptor instead.
+func (*CheckOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CheckOrderResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *CheckOrderResponse) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *CheckOrderResponse) GetRejections() []*Rejection {
+	if x != nil {
+		return x.Rejections
+	}
+	return nil
+}
+
+// Rejection explains a refused request. Failed RPCs attach one to their status
+// details, and CheckOrder lists one per failed check.
+type Rejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        RejectReason           `protobuf:"varint,1,opt,name=reason,proto3,enum=exchange.v1.RejectReason" json:"reason,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rejection) Reset() {
+	*x = Rejection{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rejection) ProtoMessage() {}
+
+func (x *Rejection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rejection.ProtoReflect.Descriptor instead.
+func (*Rejection) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Rejection) GetReason() RejectReason {
+	if x != nil {
+		return x.Reason
+	}
+	return RejectReason_REJECT_REASON_UNSPECIFIED
+}
+
+func (x *Rejection) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type OpenSessionRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AccountId          string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CancelOnDisconnect bool                   `protobuf:"varint,2,opt,name=cancel_on_disconnect,json=cancelOnDisconnect,proto3" json:"cancel_on_disconnect,omitempty"`
+	GracePeriodMs      int64                  `protobuf:"varint,3,opt,name=grace_period_ms,json=gracePeriodMs,proto3" json:"grace_period_ms,omitempty"` // Zero uses the venue default
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *OpenSessionRequest) Reset() {
+	*x = OpenSessionRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSessionRequest) ProtoMessage() {}
+
+func (x *OpenSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSessionRequest.ProtoReflect.Descriptor instead.
+func (*OpenSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *OpenSessionRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OpenSessionRequest) GetCancelOnDisconnect() bool {
+	if x != nil {
+		return x.CancelOnDisconnect
+	}
+	return false
+}
+
+func (x *OpenSessionRequest) GetGracePeriodMs() int64 {
+	if x != nil {
+		return x.GracePeriodMs
+	}
+	return 0
+}
+
+// SessionEvent is streamed to a session's holder; heartbeats let it detect a silent drop
+type SessionEvent struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Type               SessionEventType       `protobuf:"varint,1,opt,name=type,proto3,enum=exchange.v1.SessionEventType" json:"type,omitempty"`
+	SessionId          string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AccountId          string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CancelOnDisconnect bool                   `protobuf:"varint,4,opt,name=cancel_on_disconnect,json=cancelOnDisconnect,proto3" json:"cancel_on_disconnect,omitempty"`
+	GracePeriodMs      int64                  `protobuf:"varint,5,opt,name=grace_period_ms,json=gracePeriodMs,proto3" json:"grace_period_ms,omitempty"`
+	TimestampMs        int64                  `protobuf:"varint,6,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SessionEvent) GetType() SessionEventType {
+	if x != nil {
+		return x.Type
+	}
+	return SessionEventType_SESSION_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *SessionEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetCancelOnDisconnect() bool {
+	if x != nil {
+		return x.CancelOnDisconnect
+	}
+	return false
+}
+
+func (x *SessionEvent) GetGracePeriodMs() int64 {
+	if x != nil {
+		return x.GracePeriodMs
+	}
+	return 0
+}
+
+func (x *SessionEvent) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+// Order updates and trades share one venue-wide sequence, so a filtered stream skips
+// the numbers that belong to other accounts, symbols or the other stream
+type StreamOrderUpdatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`           // Optional
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                                  // Optional
+	FromSequence  uint64                 `protobuf:"varint,3,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"` // First sequence wanted; zero streams live updates only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderUpdatesRequest) Reset() {
+	*x = StreamOrderUpdatesRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderUpdatesRequest) ProtoMessage() {}
+
+func (x *StreamOrderUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *StreamOrderUpdatesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *StreamOrderUpdatesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamOrderUpdatesRequest) GetFromSequence() uint64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
+type OrderUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	Order         *Order                 `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"` // Unset on acks
+	Ack           *OrderAck              `protobuf:"bytes,4,opt,name=ack,proto3" json:"ack,omitempty"`     // Set only on acks for SubmitOrder
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderUpdate) Reset() {
+	*x = OrderUpdate{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderUpdate) ProtoMessage() {}
+
+func (x *OrderUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderUpdate.ProtoReflect.Descriptor instead.
+func (*OrderUpdate) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *OrderUpdate) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *OrderUpdate) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *OrderUpdate) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderUpdate) GetAck() *OrderAck {
+	if x != nil {
+		return x.Ack
+	}
+	return nil
+}
+
+// OrderAck answers a SubmitOrder. Accepted acks precede the order's first update and
+// any trades it printed.
+type OrderAck struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RequestSequence uint64                 `protobuf:"varint,1,opt,name=request_sequence,json=requestSequence,proto3" json:"request_sequence,omitempty"`
+	AccountId       string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ClientOrderId   string                 `protobuf:"bytes,3,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	Symbol          string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId         string                 `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Empty when rejected before entry
+	Accepted        bool                   `protobuf:"varint,6,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejection       *Rejection             `protobuf:"bytes,7,opt,name=rejection,proto3" json:"rejection,omitempty"` // Set when not accepted
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderAck) Reset() {
+	*x = OrderAck{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAck) ProtoMessage() {}
+
+func (x *OrderAck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAck.ProtoReflect.Descriptor instead.
+func (*OrderAck) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *OrderAck) GetRequestSequence() uint64 {
+	if x != nil {
+		return x.RequestSequence
+	}
+	return 0
+}
+
+func (x *OrderAck) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OrderAck) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *OrderAck) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OrderAck) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderAck) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *OrderAck) GetRejection() *Rejection {
+	if x != nil {
+		return x.Rejection
+	}
+	return nil
+}
+
+type StreamTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`           // Optional; matches either side
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                                  // Optional
+	FromSequence  uint64                 `protobuf:"varint,3,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"` // First sequence wanted; zero streams live trades only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTradesRequest) Reset() {
+	*x = StreamTradesRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTradesRequest) ProtoMessage() {}
+
+func (x *StreamTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTradesRequest.ProtoReflect.Descriptor instead.
+func (*StreamTradesRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *StreamTradesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *StreamTradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamTradesRequest) GetFromSequence() uint64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
+type TradeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	Trade         *Trade                 `protobuf:"bytes,3,opt,name=trade,proto3" json:"trade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeEvent) Reset() {
+	*x = TradeEvent{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeEvent) ProtoMessage() {}
+
+func (x *TradeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeEvent.ProtoReflect.Descriptor instead.
+func (*TradeEvent) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TradeEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetTrade() *Trade {
+	if x != nil {
+		return x.Trade
+	}
+	return nil
+}
+
+type StreamOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"` // Levels per side in the snapshot; zero sends all. Updates cover every level.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderBookRequest) Reset() {
+	*x = StreamOrderBookRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderBookRequest) ProtoMessage() {}
+
+func (x *StreamOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *StreamOrderBookRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type OrderBookUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"` // One more than the previous update for the symbol
+	Snapshot      bool                   `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // Set on the first message, which replaces the local book
+	Phase         string                 `protobuf:"bytes,4,opt,name=phase,proto3" json:"phase,omitempty"`
+	Bids          []*PriceLevel          `protobuf:"bytes,5,rep,name=bids,proto3" json:"bids,omitempty"` // Best first; on updates only changed levels, zero quantity removes one
+	Asks          []*PriceLevel          `protobuf:"bytes,6,rep,name=asks,proto3" json:"asks,omitempty"` // Best first; on updates only changed levels, zero quantity removes one
+	TimestampMs   int64                  `protobuf:"varint,7,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookUpdate) Reset() {
+	*x = OrderBookUpdate{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookUpdate) ProtoMessage() {}
+
+func (x *OrderBookUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookUpdate.ProtoReflect.Descriptor instead.
+func (*OrderBookUpdate) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *OrderBookUpdate) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OrderBookUpdate) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *OrderBookUpdate) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *OrderBookUpdate) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *OrderBookUpdate) GetBids() []*PriceLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *OrderBookUpdate) GetAsks() []*PriceLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *OrderBookUpdate) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+type FaultSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// reject_orders, delay_fills, drop_market_data, corrupt_balances or halt_symbol, or
+	// the misbehaviours overfill, misreport_balances, duplicate_reports or stall_cancels
+	Kind          string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Symbols       []string `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`                               // Empty applies to every symbol; halt_symbol needs at least one
+	Rate          float64  `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`                                   // Share of orders or updates hit, above 0 up to 1; zero means all
+	DelayMs       int64    `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`               // delay_fills; stall_cancels, where zero holds acknowledgements until the fault ends
+	Reason        string   `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`                                 // Message of orders rejected by reject_orders
+	AccountId     string   `protobuf:"bytes,6,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`          // corrupt_balances; misreport_balances, where empty is every account
+	Asset         string   `protobuf:"bytes,7,opt,name=asset,proto3" json:"asset,omitempty"`                                   // corrupt_balances; misreport_balances, where empty is every asset
+	Amount        float64  `protobuf:"fixed64,8,opt,name=amount,proto3" json:"amount,omitempty"`                               // corrupt_balances and misreport_balances; negative removes
+	DurationMs    int64    `protobuf:"varint,9,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`      // Zero lasts until cleared
+	OverfillPct   float64  `protobuf:"fixed64,10,opt,name=overfill_pct,json=overfillPct,proto3" json:"overfill_pct,omitempty"` // overfill: percent added to the filled quantity reported
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultSpec) Reset() {
+	*x = FaultSpec{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultSpec) ProtoMessage() {}
+
+func (x *FaultSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultSpec.ProtoReflect.Descriptor instead.
+func (*FaultSpec) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *FaultSpec) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *FaultSpec) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *FaultSpec) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *FaultSpec) GetDelayMs() int64 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *FaultSpec) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FaultSpec) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *FaultSpec) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *FaultSpec) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *FaultSpec) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *FaultSpec) GetOverfillPct() float64 {
+	if x != nil {
+		return x.OverfillPct
+	}
+	return 0
+}
+
+type Fault struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FaultId        string                 `protobuf:"bytes,1,opt,name=fault_id,json=faultId,proto3" json:"fault_id,omitempty"`
+	Spec           *FaultSpec             `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	InjectedTimeMs int64                  `protobuf:"varint,3,opt,name=injected_time_ms,json=injectedTimeMs,proto3" json:"injected_time_ms,omitempty"`
+	ExpireTimeMs   int64                  `protobuf:"varint,4,opt,name=expire_time_ms,json=expireTimeMs,proto3" json:"expire_time_ms,omitempty"` // Zero lasts until cleared
+	Hits           int64                  `protobuf:"varint,5,opt,name=hits,proto3" json:"hits,omitempty"`                                       // Orders rejected or delayed, or updates dropped
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Fault) Reset() {
+	*x = Fault{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fault) ProtoMessage() {}
+
+func (x *Fault) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fault.ProtoReflect.Descriptor instead.
+func (*Fault) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Fault) GetFaultId() string {
+	if x != nil {
+		return x.FaultId
+	}
+	return ""
+}
+
+func (x *Fault) GetSpec() *FaultSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *Fault) GetInjectedTimeMs() int64 {
+	if x != nil {
+		return x.InjectedTimeMs
+	}
+	return 0
+}
+
+func (x *Fault) GetExpireTimeMs() int64 {
+	if x != nil {
+		return x.ExpireTimeMs
+	}
+	return 0
+}
+
+func (x *Fault) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type InjectFaultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spec          *FaultSpec             `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InjectFaultRequest) Reset() {
+	*x = InjectFaultRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InjectFaultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InjectFaultRequest) ProtoMessage() {}
+
+func (x *InjectFaultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InjectFaultRequest.ProtoReflect.Descriptor instead.
+func (*InjectFaultRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *InjectFaultRequest) GetSpec() *FaultSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type InjectFaultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fault         *Fault                 `protobuf:"bytes,1,opt,name=fault,proto3" json:"fault,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InjectFaultResponse) Reset() {
+	*x = InjectFaultResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InjectFaultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InjectFaultResponse) ProtoMessage() {}
+
+func (x *InjectFaultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InjectFaultResponse.ProtoReflect.Descriptor instead.
+func (*InjectFaultResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *InjectFaultResponse) GetFault() *Fault {
+	if x != nil {
+		return x.Fault
+	}
+	return nil
+}
+
+type ListFaultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultsRequest) Reset() {
+	*x = ListFaultsRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFaultsRequest) ProtoMessage() {}
+
+func (x *ListFaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFaultsRequest.ProtoReflect.Descriptor instead.
+func (*ListFaultsRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{52}
+}
+
+type ListFaultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Faults        []*Fault               `protobuf:"bytes,1,rep,name=faults,proto3" json:"faults,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultsResponse) Reset() {
+	*x = ListFaultsResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFaultsResponse) ProtoMessage() {}
+
+func (x *ListFaultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFaultsResponse.ProtoReflect.Descriptor instead.
+func (*ListFaultsResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListFaultsResponse) GetFaults() []*Fault {
+	if x != nil {
+		return x.Faults
+	}
+	return nil
+}
+
+type ClearFaultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FaultId       string                 `protobuf:"bytes,1,opt,name=fault_id,json=faultId,proto3" json:"fault_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearFaultRequest) Reset() {
+	*x = ClearFaultRequest{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearFaultRequest) ProtoMessage() {}
+
+func (x *ClearFaultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearFaultRequest.ProtoReflect.Descriptor instead.
+func (*ClearFaultRequest) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ClearFaultRequest) GetFaultId() string {
+	if x != nil {
+		return x.FaultId
+	}
+	return ""
+}
+
+type ClearFaultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fault         *Fault                 `protobuf:"bytes,1,opt,name=fault,proto3" json:"fault,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearFaultResponse) Reset() {
+	*x = ClearFaultResponse{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearFaultResponse) ProtoMessage() {}
+
+func (x *ClearFaultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearFaultResponse.ProtoReflect.Descriptor instead.
+func (*ClearFaultResponse) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ClearFaultResponse) GetFault() *Fault {
+	if x != nil {
+		return x.Fault
+	}
+	return nil
+}
+
+// OrderFlowEvent is one captured order entry request. A recording is a file of
+// length-delimited OrderFlowEvents, which the venue's order flow replayer resubmits.
+type OrderFlowEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	TimeMs  int64                  `protobuf:"varint,1,opt,name=time_ms,json=timeMs,proto3" json:"time_ms,omitempty"`   // When the venue accepted the request, Unix milliseconds
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // The order placed, canceled or amended, as the recorded venue identified it
+	// Types that are valid to be assigned to Request:
+	//
+	//	*OrderFlowEvent_Place
+	//	*OrderFlowEvent_Cancel
+	//	*OrderFlowEvent_Amend
+	Request       isOrderFlowEvent_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFlowEvent) Reset() {
+	*x = OrderFlowEvent{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFlowEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFlowEvent) ProtoMessage() {}
+
+func (x *OrderFlowEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFlowEvent.ProtoReflect.Descriptor instead.
+func (*OrderFlowEvent) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *OrderFlowEvent) GetTimeMs() int64 {
+	if x != nil {
+		return x.TimeMs
+	}
+	return 0
+}
+
+func (x *OrderFlowEvent) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderFlowEvent) GetRequest() isOrderFlowEvent_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *OrderFlowEvent) GetPlace() *OrderSpec {
+	if x != nil {
+		if x, ok := x.Request.(*OrderFlowEvent_Place); ok {
+			return x.Place
+		}
+	}
+	return nil
+}
+
+func (x *OrderFlowEvent) GetCancel() *OrderFlowCancel {
+	if x != nil {
+		if x, ok := x.Request.(*OrderFlowEvent_Cancel); ok {
+			return x.Cancel
+		}
+	}
+	return nil
+}
+
+func (x *OrderFlowEvent) GetAmend() *OrderFlowAmend {
+	if x != nil {
+		if x, ok := x.Request.(*OrderFlowEvent_Amend); ok {
+			return x.Amend
+		}
+	}
+	return nil
+}
+
+type isOrderFlowEvent_Request interface {
+	isOrderFlowEvent_Request()
+}
+
+type OrderFlowEvent_Place struct {
+	Place *OrderSpec `protobuf:"bytes,3,opt,name=place,proto3,oneof"`
+}
+
+type OrderFlowEvent_Cancel struct {
+	Cancel *OrderFlowCancel `protobuf:"bytes,4,opt,name=cancel,proto3,oneof"`
+}
+
+type OrderFlowEvent_Amend struct {
+	Amend *OrderFlowAmend `protobuf:"bytes,5,opt,name=amend,proto3,oneof"`
+}
+
+func (*OrderFlowEvent_Place) isOrderFlowEvent_Request() {}
+
+func (*OrderFlowEvent_Cancel) isOrderFlowEvent_Request() {}
+
+func (*OrderFlowEvent_Amend) isOrderFlowEvent_Request() {}
+
+type OrderFlowCancel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFlowCancel) Reset() {
+	*x = OrderFlowCancel{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFlowCancel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFlowCancel) ProtoMessage() {}
+
+func (x *OrderFlowCancel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFlowCancel.ProtoReflect.Descriptor instead.
+func (*OrderFlowCancel) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{57}
+}
+
+type OrderFlowAmend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`       // Zero keeps the price
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // New total quantity including fills; zero keeps it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFlowAmend) Reset() {
+	*x = OrderFlowAmend{}
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFlowAmend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFlowAmend) ProtoMessage() {}
+
+func (x *OrderFlowAmend) ProtoReflect() protoreflect.Message {
+	mi := &file_api_exchange_v1_exchange_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFlowAmend.ProtoReflect.Descriptor instead.
+func (*OrderFlowAmend) Descriptor() ([]byte, []int) {
+	return file_api_exchange_v1_exchange_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *OrderFlowAmend) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderFlowAmend) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+var File_api_exchange_v1_exchange_proto protoreflect.FileDescriptor
+
+const file_api_exchange_v1_exchange_proto_rawDesc = "" +
+	"\n" +
+	"\x1eapi/exchange/v1/exchange.proto\x12\vexchange.v1\"\xd3\x02\n" +
+	"\tOrderSpec\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12%\n" +
+	"\x04side\x18\x03 \x01(\x0e2\x11.exchange.v1.SideR\x04side\x12*\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x16.exchange.v1.OrderTypeR\x04type\x12<\n" +
+	"\rtime_in_force\x18\x05 \x01(\x0e2\x18.exchange.v1.TimeInForceR\vtimeInForce\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\a \x01(\x01R\x05price\x12&\n" +
+	"\x0fclient_order_id\x18\b \x01(\tR\rclientOrderId\x12$\n" +
+	"\x0eexpire_time_ms\x18\t \x01(\x03R\fexpireTimeMs\"\xaf\x04\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0fclient_order_id\x18\x02 \x01(\tR\rclientOrderId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12%\n" +
+	"\x04side\x18\x05 \x01(\x0e2\x11.exchange.v1.SideR\x04side\x12*\n" +
+	"\x04type\x18\x06 \x01(\x0e2\x16.exchange.v1.OrderTypeR\x04type\x12<\n" +
+	"\rtime_in_force\x18\a \x01(\x0e2\x18.exchange.v1.TimeInForceR\vtimeInForce\x12\x14\n" +
+	"\x05price\x18\b \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\t \x01(\x01R\bquantity\x12'\n" +
+	"\x0ffilled_quantity\x18\n" +
+	" \x01(\x01R\x0efilledQuantity\x12#\n" +
+	"\raverage_price\x18\v \x01(\x01R\faveragePrice\x120\n" +
+	"\x06status\x18\f \x01(\x0e2\x18.exchange.v1.OrderStatusR\x06status\x12$\n" +
+	"\x0eexpire_time_ms\x18\r \x01(\x03R\fexpireTimeMs\x12&\n" +
+	"\x0fcreated_time_ms\x18\x0e \x01(\x03R\rcreatedTimeMs\x12&\n" +
+	"\x0fupdated_time_ms\x18\x0f \x01(\x03R\rupdatedTimeMs\"\xeb\x02\n" +
+	"\x05Trade\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12 \n" +
+	"\fbuy_order_id\x18\x05 \x01(\tR\n" +
+	"buyOrderId\x12\"\n" +
+	"\rsell_order_id\x18\x06 \x01(\tR\vsellOrderId\x12$\n" +
+	"\x0ebuy_account_id\x18\a \x01(\tR\fbuyAccountId\x12&\n" +
+	"\x0fsell_account_id\x18\b \x01(\tR\rsellAccountId\x120\n" +
+	"\n" +
+	"taker_side\x18\t \x01(\x0e2\x11.exchange.v1.SideR\ttakerSide\x12\x18\n" +
+	"\aauction\x18\n" +
+	" \x01(\bR\aauction\x12(\n" +
+	"\x10executed_time_ms\x18\v \x01(\x03R\x0eexecutedTimeMs\"Z\n" +
+	"\x11PlaceOrderRequest\x12,\n" +
+	"\x05order\x18\x01 \x01(\v2\x16.exchange.v1.OrderSpecR\x05order\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xa1\x01\n" +
+	"\x12PlaceOrderResponse\x12(\n" +
+	"\x05order\x18\x01 \x01(\v2\x12.exchange.v1.OrderR\x05order\x12*\n" +
+	"\x06trades\x18\x02 \x03(\v2\x12.exchange.v1.TradeR\x06trades\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"B\n" +
+	"\x12SubmitOrderRequest\x12,\n" +
+	"\x05order\x18\x01 \x01(\v2\x16.exchange.v1.OrderSpecR\x05order\"j\n" +
+	"\x13SubmitOrderResponse\x12)\n" +
+	"\x10request_sequence\x18\x01 \x01(\x04R\x0frequestSequence\x12(\n" +
+	"\x10received_time_ms\x18\x02 \x01(\x03R\x0ereceivedTimeMs\"/\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"?\n" +
+	"\x13CancelOrderResponse\x12(\n" +
+	"\x05order\x18\x01 \x01(\v2\x12.exchange.v1.OrderR\x05order\",\n" +
+	"\x0fGetOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"<\n" +
+	"\x10GetOrderResponse\x12(\n" +
+	"\x05order\x18\x01 \x01(\v2\x12.exchange.v1.OrderR\x05order\"N\n" +
+	"\x15ListOpenOrdersRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"D\n" +
+	"\x16ListOpenOrdersResponse\x12*\n" +
+	"\x06orders\x18\x01 \x03(\v2\x12.exchange.v1.OrderR\x06orders\"_\n" +
+	"\x10GetTradesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"?\n" +
+	"\x11GetTradesResponse\x12*\n" +
+	"\x06trades\x18\x01 \x03(\v2\x12.exchange.v1.TradeR\x06trades\"\xa4\x02\n" +
+	"\x17ListOrderHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x124\n" +
+	"\bstatuses\x18\x03 \x03(\x0e2\x18.exchange.v1.OrderStatusR\bstatuses\x12\"\n" +
+	"\rstart_time_ms\x18\x04 \x01(\x03R\vstartTimeMs\x12\x1e\n" +
+	"\vend_time_ms\x18\x05 \x01(\x03R\tendTimeMs\x12*\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\x16.exchange.v1.SortOrderR\x04sort\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\b \x01(\tR\x06cursor\"g\n" +
+	"\x18ListOrderHistoryResponse\x12*\n" +
+	"\x06orders\x18\x01 \x03(\v2\x12.exchange.v1.OrderR\x06orders\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xee\x01\n" +
+	"\x17ListTradeHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\"\n" +
+	"\rstart_time_ms\x18\x03 \x01(\x03R\vstartTimeMs\x12\x1e\n" +
+	"\vend_time_ms\x18\x04 \x01(\x03R\tendTimeMs\x12*\n" +
+	"\x04sort\x18\x05 \x01(\x0e2\x16.exchange.v1.SortOrderR\x04sort\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\"g\n" +
+	"\x18ListTradeHistoryResponse\x12*\n" +
+	"\x06trades\x18\x01 \x03(\v2\x12.exchange.v1.TradeR\x06trades\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"C\n" +
+	"\x13GetOrderBookRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"_\n" +
+	"\n" +
+	"PriceLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x1f\n" +
+	"\vorder_count\x18\x03 \x01(\x05R\n" +
+	"orderCount\"\xba\x01\n" +
+	"\x14GetOrderBookResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x12+\n" +
+	"\x04bids\x18\x03 \x03(\v2\x17.exchange.v1.PriceLevelR\x04bids\x12+\n" +
+	"\x04asks\x18\x04 \x03(\v2\x17.exchange.v1.PriceLevelR\x04asks\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x04R\bsequence\"*\n" +
+	"\x10GetTickerRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\xfd\x04\n" +
+	"\x11GetTickerResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"last_price\x18\x02 \x01(\x01R\tlastPrice\x12\x1b\n" +
+	"\tbid_price\x18\x03 \x01(\x01R\bbidPrice\x12!\n" +
+	"\fbid_quantity\x18\x04 \x01(\x01R\vbidQuantity\x12\x1b\n" +
+	"\task_price\x18\x05 \x01(\x01R\baskPrice\x12!\n" +
+	"\fask_quantity\x18\x06 \x01(\x01R\vaskQuantity\x12\x1d\n" +
+	"\n" +
+	"open_price\x18\a \x01(\x01R\topenPrice\x12\x1d\n" +
+	"\n" +
+	"high_price\x18\b \x01(\x01R\thighPrice\x12\x1b\n" +
+	"\tlow_price\x18\t \x01(\x01R\blowPrice\x12!\n" +
+	"\fprice_change\x18\n" +
+	" \x01(\x01R\vpriceChange\x120\n" +
+	"\x14price_change_percent\x18\v \x01(\x01R\x12priceChangePercent\x12,\n" +
+	"\x12weighted_avg_price\x18\f \x01(\x01R\x10weightedAvgPrice\x12\x16\n" +
+	"\x06volume\x18\r \x01(\x01R\x06volume\x12!\n" +
+	"\fquote_volume\x18\x0e \x01(\x01R\vquoteVolume\x12\x1f\n" +
+	"\vtrade_count\x18\x0f \x01(\x05R\n" +
+	"tradeCount\x12+\n" +
+	"\x12last_trade_time_ms\x18\x10 \x01(\x03R\x0flastTradeTimeMs\x12&\n" +
+	"\x0fwindow_start_ms\x18\x11 \x01(\x03R\rwindowStartMs\x12\"\n" +
+	"\rwindow_end_ms\x18\x12 \x01(\x03R\vwindowEndMs\"\xa1\x01\n" +
+	"\x11GetCandlesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\"\n" +
+	"\rstart_time_ms\x18\x03 \x01(\x03R\vstartTimeMs\x12\x1e\n" +
+	"\vend_time_ms\x18\x04 \x01(\x03R\tendTimeMs\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xfa\x01\n" +
+	"\x06Candle\x12 \n" +
+	"\fopen_time_ms\x18\x01 \x01(\x03R\n" +
+	"openTimeMs\x12\"\n" +
+	"\rclose_time_ms\x18\x02 \x01(\x03R\vcloseTimeMs\x12\x12\n" +
+	"\x04open\x18\x03 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x04 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x05 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x06 \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\a \x01(\x01R\x06volume\x12!\n" +
+	"\fquote_volume\x18\b \x01(\x01R\vquoteVolume\x12\x1f\n" +
+	"\vtrade_count\x18\t \x01(\x05R\n" +
+	"tradeCount\"w\n" +
+	"\x12GetCandlesResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12-\n" +
+	"\acandles\x18\x03 \x03(\v2\x13.exchange.v1.CandleR\acandles\"3\n" +
+	"\x12GetBalancesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"\x8f\x01\n" +
+	"\aBalance\x12\x14\n" +
+	"\x05asset\x18\x01 \x01(\tR\x05asset\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x1a\n" +
+	"\breceived\x18\x03 \x01(\x01R\breceived\x12\x1c\n" +
+	"\tdelivered\x18\x04 \x01(\x01R\tdelivered\x12\x18\n" +
+	"\aopening\x18\x05 \x01(\x01R\aopening\"f\n" +
+	"\x13GetBalancesResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x120\n" +
+	"\bbalances\x18\x02 \x03(\v2\x14.exchange.v1.BalanceR\bbalances\":\n" +
+	"\x19GetAccountSnapshotRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"n\n" +
+	"\x0eAccountBalance\x12\x14\n" +
+	"\x05asset\x18\x01 \x01(\tR\x05asset\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\x01R\tavailable\x12\x12\n" +
+	"\x04held\x18\x03 \x01(\x01R\x04held\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x01R\x05total\"\xa5\x02\n" +
+	"\bPosition\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x1f\n" +
+	"\ventry_price\x18\x03 \x01(\x01R\n" +
+	"entryPrice\x12\x1d\n" +
+	"\n" +
+	"mark_price\x18\x04 \x01(\x01R\tmarkPrice\x12%\n" +
+	"\x0eunrealized_pnl\x18\x05 \x01(\x01R\runrealizedPnl\x12!\n" +
+	"\frealized_pnl\x18\x06 \x01(\x01R\vrealizedPnl\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\a \x01(\tR\taccountId\x12\x14\n" +
+	"\x05asset\x18\b \x01(\tR\x05asset\x12&\n" +
+	"\x0fupdated_time_ms\x18\t \x01(\x03R\rupdatedTimeMs\"\x9e\x02\n" +
+	"\x1aGetAccountSnapshotResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12\"\n" +
+	"\rtaken_time_ms\x18\x03 \x01(\x03R\vtakenTimeMs\x127\n" +
+	"\bbalances\x18\x04 \x03(\v2\x1b.exchange.v1.AccountBalanceR\bbalances\x123\n" +
+	"\tpositions\x18\x05 \x03(\v2\x15.exchange.v1.PositionR\tpositions\x123\n" +
+	"\vopen_orders\x18\x06 \x03(\v2\x12.exchange.v1.OrderR\n" +
+	"openOrders\"L\n" +
+	"\x13GetPositionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"K\n" +
+	"\x14GetPositionsResponse\x123\n" +
+	"\tpositions\x18\x01 \x03(\v2\x15.exchange.v1.PositionR\tpositions\"A\n" +
+	"\x11CheckOrderRequest\x12,\n" +
+	"\x05order\x18\x01 \x01(\v2\x16.exchange.v1.OrderSpecR\x05order\"\x82\x01\n" +
+	"\x12CheckOrderResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\tR\areasons\x126\n" +
+	"\n" +
+	"rejections\x18\x03 \x03(\v2\x16.exchange.v1.RejectionR\n" +
+	"rejections\"X\n" +
+	"\tRejection\x121\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x19.exchange.v1.RejectReasonR\x06reason\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x8d\x01\n" +
+	"\x12OpenSessionRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x120\n" +
+	"\x14cancel_on_disconnect\x18\x02 \x01(\bR\x12cancelOnDisconnect\x12&\n" +
+	"\x0fgrace_period_ms\x18\x03 \x01(\x03R\rgracePeriodMs\"\xfc\x01\n" +
+	"\fSessionEvent\x121\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1d.exchange.v1.SessionEventTypeR\x04type\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x120\n" +
+	"\x14cancel_on_disconnect\x18\x04 \x01(\bR\x12cancelOnDisconnect\x12&\n" +
+	"\x0fgrace_period_ms\x18\x05 \x01(\x03R\rgracePeriodMs\x12!\n" +
+	"\ftimestamp_ms\x18\x06 \x01(\x03R\vtimestampMs\"w\n" +
+	"\x19StreamOrderUpdatesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12#\n" +
+	"\rfrom_sequence\x18\x03 \x01(\x04R\ffromSequence\"\x9f\x01\n" +
+	"\vOrderUpdate\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12!\n" +
+	"\ftimestamp_ms\x18\x02 \x01(\x03R\vtimestampMs\x12(\n" +
+	"\x05order\x18\x03 \x01(\v2\x12.exchange.v1.OrderR\x05order\x12'\n" +
+	"\x03ack\x18\x04 \x01(\v2\x15.exchange.v1.OrderAckR\x03ack\"\x81\x02\n" +
+	"\bOrderAck\x12)\n" +
+	"\x10request_sequence\x18\x01 \x01(\x04R\x0frequestSequence\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12&\n" +
+	"\x0fclient_order_id\x18\x03 \x01(\tR\rclientOrderId\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\tR\aorderId\x12\x1a\n" +
+	"\baccepted\x18\x06 \x01(\bR\baccepted\x124\n" +
+	"\trejection\x18\a \x01(\v2\x16.exchange.v1.RejectionR\trejection\"q\n" +
+	"\x13StreamTradesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12#\n" +
+	"\rfrom_sequence\x18\x03 \x01(\x04R\ffromSequence\"u\n" +
+	"\n" +
+	"TradeEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12!\n" +
+	"\ftimestamp_ms\x18\x02 \x01(\x03R\vtimestampMs\x12(\n" +
+	"\x05trade\x18\x03 \x01(\v2\x12.exchange.v1.TradeR\x05trade\"F\n" +
+	"\x16StreamOrderBookRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"\xf4\x01\n" +
+	"\x0fOrderBookUpdate\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\bR\bsnapshot\x12\x14\n" +
+	"\x05phase\x18\x04 \x01(\tR\x05phase\x12+\n" +
+	"\x04bids\x18\x05 \x03(\v2\x17.exchange.v1.PriceLevelR\x04bids\x12+\n" +
+	"\x04asks\x18\x06 \x03(\v2\x17.exchange.v1.PriceLevelR\x04asks\x12!\n" +
+	"\ftimestamp_ms\x18\a \x01(\x03R\vtimestampMs\"\x91\x02\n" +
+	"\tFaultSpec\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x12\x19\n" +
+	"\bdelay_ms\x18\x04 \x01(\x03R\adelayMs\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x06 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05asset\x18\a \x01(\tR\x05asset\x12\x16\n" +
+	"\x06amount\x18\b \x01(\x01R\x06amount\x12\x1f\n" +
+	"\vduration_ms\x18\t \x01(\x03R\n" +
+	"durationMs\x12!\n" +
+	"\foverfill_pct\x18\n" +
+	" \x01(\x01R\voverfillPct\"\xb2\x01\n" +
+	"\x05Fault\x12\x19\n" +
+	"\bfault_id\x18\x01 \x01(\tR\afaultId\x12*\n" +
+	"\x04spec\x18\x02 \x01(\v2\x16.exchange.v1.FaultSpecR\x04spec\x12(\n" +
+	"\x10injected_time_ms\x18\x03 \x01(\x03R\x0einjectedTimeMs\x12$\n" +
+	"\x0eexpire_time_ms\x18\x04 \x01(\x03R\fexpireTimeMs\x12\x12\n" +
+	"\x04hits\x18\x05 \x01(\x03R\x04hits\"@\n" +
+	"\x12InjectFaultRequest\x12*\n" +
+	"\x04spec\x18\x01 \x01(\v2\x16.exchange.v1.FaultSpecR\x04spec\"?\n" +
+	"\x13InjectFaultResponse\x12(\n" +
+	"\x05fault\x18\x01 \x01(\v2\x12.exchange.v1.FaultR\x05fault\"\x13\n" +
+	"\x11ListFaultsRequest\"@\n" +
+	"\x12ListFaultsResponse\x12*\n" +
+	"\x06faults\x18\x01 \x03(\v2\x12.exchange.v1.FaultR\x06faults\".\n" +
+	"\x11ClearFaultRequest\x12\x19\n" +
+	"\bfault_id\x18\x01 \x01(\tR\afaultId\">\n" +
+	"\x12ClearFaultResponse\x12(\n" +
+	"\x05fault\x18\x01 \x01(\v2\x12.exchange.v1.FaultR\x05fault\"\xec\x01\n" +
+	"\x0eOrderFlowEvent\x12\x17\n" +
+	"\atime_ms\x18\x01 \x01(\x03R\x06timeMs\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12.\n" +
+	"\x05place\x18\x03 \x01(\v2\x16.exchange.v1.OrderSpecH\x00R\x05place\x126\n" +
+	"\x06cancel\x18\x04 \x01(\v2\x1c.exchange.v1.OrderFlowCancelH\x00R\x06cancel\x123\n" +
+	"\x05amend\x18\x05 \x01(\v2\x1b.exchange.v1.OrderFlowAmendH\x00R\x05amendB\t\n" +
+	"\arequest\"\x11\n" +
+	"\x0fOrderFlowCancel\"B\n" +
+	"\x0eOrderFlowAmend\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity*9\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSIDE_BUY\x10\x01\x12\r\n" +
+	"\tSIDE_SELL\x10\x02*T\n" +
+	"\tOrderType\x12\x1a\n" +
+	"\x16ORDER_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10ORDER_TYPE_LIMIT\x10\x01\x12\x15\n" +
+	"\x11ORDER_TYPE_MARKET\x10\x02*\x88\x01\n" +
+	"\vTimeInForce\x12\x1d\n" +
+	"\x19TIME_IN_FORCE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11TIME_IN_FORCE_GTC\x10\x01\x12\x15\n" +
+	"\x11TIME_IN_FORCE_IOC\x10\x02\x12\x15\n" +
+	"\x11TIME_IN_FORCE_FOK\x10\x03\x12\x15\n" +
+	"\x11TIME_IN_FORCE_GTD\x10\x04*\xcd\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10ORDER_STATUS_NEW\x10\x01\x12!\n" +
+	"\x1dORDER_STATUS_PARTIALLY_FILLED\x10\x02\x12\x17\n" +
+	"\x13ORDER_STATUS_FILLED\x10\x03\x12\x19\n" +
+	"\x15ORDER_STATUS_CANCELED\x10\x04\x12\x19\n" +
+	"\x15ORDER_STATUS_REJECTED\x10\x05\x12\x18\n" +
+	"\x14ORDER_STATUS_EXPIRED\x10\x06*\\\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14SORT_ORDER_ASCENDING\x10\x01\x12\x19\n" +
+	"\x15SORT_ORDER_DESCENDING\x10\x02*\xfb\x06\n" +
+	"\fRejectReason\x12\x1d\n" +
+	"\x19REJECT_REASON_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dREJECT_REASON_INVALID_REQUEST\x10\x01\x12!\n" +
+	"\x1dREJECT_REASON_INVALID_ACCOUNT\x10\x02\x12\x1e\n" +
+	"\x1aREJECT_REASON_INVALID_SIDE\x10\x03\x12$\n" +
+	" REJECT_REASON_INVALID_ORDER_TYPE\x10\x04\x12\"\n" +
+	"\x1eREJECT_REASON_INVALID_QUANTITY\x10\x05\x12\x1f\n" +
+	"\x1bREJECT_REASON_INVALID_PRICE\x10\x06\x12#\n" +
+	"\x1fREJECT_REASON_PRICE_OUT_OF_BAND\x10\a\x12$\n" +
+	" REJECT_REASON_UNKNOWN_INSTRUMENT\x10\b\x12#\n" +
+	"\x1fREJECT_REASON_INSTRUMENT_HALTED\x10\t\x12\x1f\n" +
+	"\x1bREJECT_REASON_MARKET_CLOSED\x10\n" +
+	"\x12\x1f\n" +
+	"\x1bREJECT_REASON_INVALID_PHASE\x10\v\x12!\n" +
+	"\x1dREJECT_REASON_ORDER_NOT_FOUND\x10\f\x12\"\n" +
+	"\x1eREJECT_REASON_ORDER_NOT_ACTIVE\x10\r\x12\x1f\n" +
+	"\x1bREJECT_REASON_INVALID_AMEND\x10\x0e\x12+\n" +
+	"'REJECT_REASON_DUPLICATE_CLIENT_ORDER_ID\x10\x0f\x12&\n" +
+	"\"REJECT_REASON_INSUFFICIENT_BALANCE\x10\x10\x12$\n" +
+	" REJECT_REASON_ENGINE_UNAVAILABLE\x10\x11\x12(\n" +
+	"$REJECT_REASON_IDEMPOTENCY_KEY_REUSED\x10\x12\x12-\n" +
+	")REJECT_REASON_SUBSCRIPTION_LIMIT_EXCEEDED\x10\x13\x12#\n" +
+	"\x1fREJECT_REASON_PERMISSION_DENIED\x10\x14\x12%\n" +
+	"!REJECT_REASON_INSUFFICIENT_MARGIN\x10\x15\x12!\n" +
+	"\x1dREJECT_REASON_UNAUTHENTICATED\x10\x16\x12\x1e\n" +
+	"\x1aREJECT_REASON_RATE_LIMITED\x10\x17*w\n" +
+	"\x10SessionEventType\x12\"\n" +
+	"\x1eSESSION_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19SESSION_EVENT_TYPE_OPENED\x10\x01\x12 \n" +
+	"\x1cSESSION_EVENT_TYPE_HEARTBEAT\x10\x022\x86\n" +
+	"\n" +
+	"\x0eTradingService\x12M\n" +
+	"\n" +
+	"PlaceOrder\x12\x1e.exchange.v1.PlaceOrderRequest\x1a\x1f.exchange.v1.PlaceOrderResponse\x12P\n" +
+	"\vSubmitOrder\x12\x1f.exchange.v1.SubmitOrderRequest\x1a .exchange.v1.SubmitOrderResponse\x12P\n" +
+	"\vCancelOrder\x12\x1f.exchange.v1.CancelOrderRequest\x1a .exchange.v1.CancelOrderResponse\x12G\n" +
+	"\bGetOrder\x12\x1c.exchange.v1.GetOrderRequest\x1a\x1d.exchange.v1.GetOrderResponse\x12Y\n" +
+	"\x0eListOpenOrders\x12\".exchange.v1.ListOpenOrdersRequest\x1a#.exchange.v1.ListOpenOrdersResponse\x12J\n" +
+	"\tGetTrades\x12\x1d.exchange.v1.GetTradesRequest\x1a\x1e.exchange.v1.GetTradesResponse\x12_\n" +
+	"\x10ListOrderHistory\x12$.exchange.v1.ListOrderHistoryRequest\x1a%.exchange.v1.ListOrderHistoryResponse\x12_\n" +
+	"\x10ListTradeHistory\x12$.exchange.v1.ListTradeHistoryRequest\x1a%.exchange.v1.ListTradeHistoryResponse\x12P\n" +
+	"\vGetBalances\x12\x1f.exchange.v1.GetBalancesRequest\x1a .exchange.v1.GetBalancesResponse\x12e\n" +
+	"\x12GetAccountSnapshot\x12&.exchange.v1.GetAccountSnapshotRequest\x1a'.exchange.v1.GetAccountSnapshotResponse\x12S\n" +
+	"\fGetPositions\x12 .exchange.v1.GetPositionsRequest\x1a!.exchange.v1.GetPositionsResponse\x12M\n" +
+	"\n" +
+	"CheckOrder\x12\x1e.exchange.v1.CheckOrderRequest\x1a\x1f.exchange.v1.CheckOrderResponse\x12K\n" +
+	"\vOpenSession\x12\x1f.exchange.v1.OpenSessionRequest\x1a\x19.exchange.v1.SessionEvent0\x01\x12X\n" +
+	"\x12StreamOrderUpdates\x12&.exchange.v1.StreamOrderUpdatesRequest\x1a\x18.exchange.v1.OrderUpdate0\x01\x12K\n" +
+	"\fStreamTrades\x12 .exchange.v1.StreamTradesRequest\x1a\x17.exchange.v1.TradeEvent0\x012\xdb\x02\n" +
+	"\x11MarketDataService\x12S\n" +
+	"\fGetOrderBook\x12 .exchange.v1.GetOrderBookRequest\x1a!.exchange.v1.GetOrderBookResponse\x12J\n" +
+	"\tGetTicker\x12\x1d.exchange.v1.GetTickerRequest\x1a\x1e.exchange.v1.GetTickerResponse\x12M\n" +
+	"\n" +
+	"GetCandles\x12\x1e.exchange.v1.GetCandlesRequest\x1a\x1f.exchange.v1.GetCandlesResponse\x12V\n" +
+	"\x0fStreamOrderBook\x12#.exchange.v1.StreamOrderBookRequest\x1a\x1c.exchange.v1.OrderBookUpdate0\x012\xfe\x01\n" +
+	"\fChaosService\x12P\n" +
+	"\vInjectFault\x12\x1f.exchange.v1.InjectFaultRequest\x1a .exchange.v1.InjectFaultResponse\x12M\n" +
+	"\n" +
+	"ListFaults\x12\x1e.exchange.v1.ListFaultsRequest\x1a\x1f.exchange.v1.ListFaultsResponse\x12M\n" +
+	"\n" +
+	"ClearFault\x12\x1e.exchange.v1.ClearFaultRequest\x1a\x1f.exchange.v1.ClearFaultResponseB^Z\\github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1;exchangev1b\x06proto3"
+
+var (
+	file_api_exchange_v1_exchange_proto_rawDescOnce sync.Once
+	file_api_exchange_v1_exchange_proto_rawDescData []byte
+)
+
+func file_api_exchange_v1_exchange_proto_rawDescGZIP() []byte {
+	file_api_exchange_v1_exchange_proto_rawDescOnce.Do(func() {
+		file_api_exchange_v1_exchange_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_exchange_v1_exchange_proto_rawDesc), len(file_api_exchange_v1_exchange_proto_rawDesc)))
+	})
+	return file_api_exchange_v1_exchange_proto_rawDescData
+}
+
+var file_api_exchange_v1_exchange_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_api_exchange_v1_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
+var file_api_exchange_v1_exchange_proto_goTypes = []any{
+	(Side)(0),                          // 0: exchange.v1.Side
+	(OrderType)(0),                     // 1: exchange.v1.OrderType
+	(TimeInForce)(0),                   // 2: exchange.v1.TimeInForce
+	(OrderStatus)(0),                   // 3: exchange.v1.OrderStatus
+	(SortOrder)(0),                     // 4: exchange.v1.SortOrder
+	(RejectReason)(0),                  // 5: exchange.v1.RejectReason
+	(SessionEventType)(0),              // 6: exchange.v1.SessionEventType
+	(*OrderSpec)(nil),                  // 7: exchange.v1.OrderSpec
+	(*Order)(nil),                      // 8: exchange.v1.Order
+	(*Trade)(nil),                      // 9: exchange.v1.Trade
+	(*PlaceOrderRequest)(nil),          // 10: exchange.v1.PlaceOrderRequest
+	(*PlaceOrderResponse)(nil),         // 11: exchange.v1.PlaceOrderResponse
+	(*SubmitOrderRequest)(nil),         // 12: exchange.v1.SubmitOrderRequest
+	(*SubmitOrderResponse)(nil),        // 13: exchange.v1.SubmitOrderResponse
+	(*CancelOrderRequest)(nil),         // 14: exchange.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),        // 15: exchange.v1.CancelOrderResponse
+	(*GetOrderRequest)(nil),            // 16: exchange.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),           // 17: exchange.v1.GetOrderResponse
+	(*ListOpenOrdersRequest)(nil),      // 18: exchange.v1.ListOpenOrdersRequest
+	(*ListOpenOrdersResponse)(nil),     // 19: exchange.v1.ListOpenOrdersResponse
+	(*GetTradesRequest)(nil),           // 20: exchange.v1.GetTradesRequest
+	(*GetTradesResponse)(nil),          // 21: exchange.v1.GetTradesResponse
+	(*ListOrderHistoryRequest)(nil),    // 22: exchange.v1.ListOrderHistoryRequest
+	(*ListOrderHistoryResponse)(nil),   // 23: exchange.v1.ListOrderHistoryResponse
+	(*ListTradeHistoryRequest)(nil),    // 24: exchange.v1.ListTradeHistoryRequest
+	(*ListTradeHistoryResponse)(nil),   // 25: exchange.v1.ListTradeHistoryResponse
+	(*GetOrderBookRequest)(nil),        // 26: exchange.v1.GetOrderBookRequest
+	(*PriceLevel)(nil),                 // 27: exchange.v1.PriceLevel
+	(*GetOrderBookResponse)(nil),       // 28: exchange.v1.GetOrderBookResponse
+	(*GetTickerRequest)(nil),           // 29: exchange.v1.GetTickerRequest
+	(*GetTickerResponse)(nil),          // 30: exchange.v1.GetTickerResponse
+	(*GetCandlesRequest)(nil),          // 31: exchange.v1.GetCandlesRequest
+	(*Candle)(nil),                     // 32: exchange.v1.Candle
+	(*GetCandlesResponse)(nil),         // 33: exchange.v1.GetCandlesResponse
+	(*GetBalancesRequest)(nil),         // 34: exchange.v1.GetBalancesRequest
+	(*Balance)(nil),                    // 35: exchange.v1.Balance
+	(*GetBalancesResponse)(nil),        // 36: exchange.v1.GetBalancesResponse
+	(*GetAccountSnapshotRequest)(nil),  // 37: exchange.v1.GetAccountSnapshotRequest
+	(*AccountBalance)(nil),             // 38: exchange.v1.AccountBalance
+	(*Position)(nil),                   // 39: exchange.v1.Position
+	(*GetAccountSnapshotResponse)(nil), // 40: exchange.v1.GetAccountSnapshotResponse
+	(*GetPositionsRequest)(nil),        // 41: exchange.v1.GetPositionsRequest
+	(*GetPositionsResponse)(nil),       // 42: exchange.v1.GetPositionsResponse
+	(*CheckOrderRequest)(nil),          // 43: exchange.v1.CheckOrderRequest
+	(*CheckOrderResponse)(nil),         // 44: exchange.v1.CheckOrderResponse
+	(*Rejection)(nil),                  // 45: exchange.v1.Rejection
+	(*OpenSessionRequest)(nil),         // 46: exchange.v1.OpenSessionRequest
+	(*SessionEvent)(nil),               // 47: exchange.v1.SessionEvent
+	(*StreamOrderUpdatesRequest)(nil),  // 48: exchange.v1.StreamOrderUpdatesRequest
+	(*OrderUpdate)(nil),                // 49: exchange.v1.OrderUpdate
+	(*OrderAck)(nil),                   // 50: exchange.v1.OrderAck
+	(*StreamTradesRequest)(nil),        // 51: exchange.v1.StreamTradesRequest
+	(*TradeEvent)(nil),                 // 52: exchange.v1.TradeEvent
+	(*StreamOrderBookRequest)(nil),     // 53: exchange.v1.StreamOrderBookRequest
+	(*OrderBookUpdate)(nil),            // 54: exchange.v1.OrderBookUpdate
+	(*FaultSpec)(nil),                  // 55: exchange.v1.FaultSpec
+	(*Fault)(nil),                      // 56: exchange.v1.Fault
+	(*InjectFaultRequest)(nil),         // 57: exchange.v1.InjectFaultRequest
+	(*InjectFaultResponse)(nil),        // 58: exchange.v1.InjectFaultResponse
+	(*ListFaultsRequest)(nil),          // 59: exchange.v1.ListFaultsRequest
+	(*ListFaultsResponse)(nil),         // 60: exchange.v1.ListFaultsResponse
+	(*ClearFaultRequest)(nil),          // 61: exchange.v1.ClearFaultRequest
+	(*ClearFaultResponse)(nil),         // 62: exchange.v1.ClearFaultResponse
+	(*OrderFlowEvent)(nil),             // 63: exchange.v1.OrderFlowEvent
+	(*OrderFlowCancel)(nil),            // 64: exchange.v1.OrderFlowCancel
+	(*OrderFlowAmend)(nil),             // 65: exchange.v1.OrderFlowAmend
+}
+var file_api_exchange_v1_exchange_proto_depIdxs = []int32{
+	0,  // 0: exchange.v1.OrderSpec.side:type_name -> exchange.v1.Side
+	1,  // 1: exchange.v1.OrderSpec.type:type_name -> exchange.v1.OrderType
+	2,  // 2: exchange.v1.OrderSpec.time_in_force:type_name -> exchange.v1.TimeInForce
+	0,  // 3: exchange.v1.Order.side:type_name -> exchange.v1.Side
+	1,  // 4: exchange.v1.Order.type:type_name -> exchange.v1.OrderType
+	2,  // 5: exchange.v1.Order.time_in_force:type_name -> exchange.v1.TimeInForce
+	3,  // 6: exchange.v1.Order.status:type_name -> exchange.v1.OrderStatus
+	0,  // 7: exchange.v1.Trade.taker_side:type_name -> exchange.v1.Side
+	7,  // 8: exchange.v1.PlaceOrderRequest.order:type_name -> exchange.v1.OrderSpec
+	8,  // 9: exchange.v1.PlaceOrderResponse.order:type_name -> exchange.v1.Order
+	9,  // 10: exchange.v1.PlaceOrderResponse.trades:type_name -> exchange.v1.Trade
+	7,  // 11: exchange.v1.SubmitOrderRequest.order:type_name -> exchange.v1.OrderSpec
+	8,  // 12: exchange.v1.CancelOrderResponse.order:type_name -> exchange.v1.Order
+	8,  // 13: exchange.v1.GetOrderResponse.order:type_name -> exchange.v1.Order
+	8,  // 14: exchange.v1.ListOpenOrdersResponse.orders:type_name -> exchange.v1.Order
+	9,  // 15: exchange.v1.GetTradesResponse.trades:type_name -> exchange.v1.Trade
+	3,  // 16: exchange.v1.ListOrderHistoryRequest.statuses:type_name -> exchange.v1.OrderStatus
+	4,  // 17: exchange.v1.ListOrderHistoryRequest.sort:type_name -> exchange.v1.SortOrder
+	8,  // 18: exchange.v1.ListOrderHistoryResponse.orders:type_name -> exchange.v1.Order
+	4,  // 19: exchange.v1.ListTradeHistoryRequest.sort:type_name -> exchange.v1.SortOrder
+	9,  // 20: exchange.v1.ListTradeHistoryResponse.trades:type_name -> exchange.v1.Trade
+	27, // 21: exchange.v1.GetOrderBookResponse.bids:type_name -> exchange.v1.PriceLevel
+	27, // 22: exchange.v1.GetOrderBookResponse.asks:type_name -> exchange.v1.PriceLevel
+	32, // 23: exchange.v1.GetCandlesResponse.candles:type_name -> exchange.v1.Candle
+	35, // 24: exchange.v1.GetBalancesResponse.balances:type_name -> exchange.v1.Balance
+	38, // 25: exchange.v1.GetAccountSnapshotResponse.balances:type_name -> exchange.v1.AccountBalance
+	39, // 26: exchange.v1.GetAccountSnapshotResponse.positions:type_name -> exchange.v1.Position
+	8,  // 27: exchange.v1.GetAccountSnapshotResponse.open_orders:type_name -> exchange.v1.Order
+	39, // 28: exchange.v1.GetPositionsResponse.positions:type_name -> exchange.v1.Position
+	7,  // 29: exchange.v1.CheckOrderRequest.order:type_name -> exchange.v1.OrderSpec
+	45, // 30: exchange.v1.CheckOrderResponse.rejections:type_name -> exchange.v1.Rejection
+	5,  // 31: exchange.v1.Rejection.reason:type_name -> exchange.v1.RejectReason
+	6,  // 32: exchange.v1.SessionEvent.type:type_name -> exchange.v1.SessionEventType
+	8,  // 33: exchange.v1.OrderUpdate.order:type_name -> exchange.v1.Order
+	50, // 34: exchange.v1.OrderUpdate.ack:type_name -> exchange.v1.OrderAck
+	45, // 35: exchange.v1.OrderAck.rejection:type_name -> exchange.v1.Rejection
+	9,  // 36: exchange.v1.TradeEvent.trade:type_name -> exchange.v1.Trade
+	27, // 37: exchange.v1.OrderBookUpdate.bids:type_name -> exchange.v1.PriceLevel
+	27, // 38: exchange.v1.OrderBookUpdate.asks:type_name -> exchange.v1.PriceLevel
+	55, // 39: exchange.v1.Fault.spec:type_name -> exchange.v1.FaultSpec
+	55, // 40: exchange.v1.InjectFaultRequest.spec:type_name -> exchange.v1.FaultSpec
+	56, // 41: exchange.v1.InjectFaultResponse.fault:type_name -> exchange.v1.Fault
+	56, // 42: exchange.v1.ListFaultsResponse.faults:type_name -> exchange.v1.Fault
+	56, // 43: exchange.v1.ClearFaultResponse.fault:type_name -> exchange.v1.Fault
+	7,  // 44: exchange.v1.OrderFlowEvent.place:type_name -> exchange.v1.OrderSpec
+	64, // 45: exchange.v1.OrderFlowEvent.cancel:type_name -> exchange.v1.OrderFlowCancel
+	65, // 46: exchange.v1.OrderFlowEvent.amend:type_name -> exchange.v1.OrderFlowAmend
+	10, // 47: exchange.v1.TradingService.PlaceOrder:input_type -> exchange.v1.PlaceOrderRequest
+	12, // 48: exchange.v1.TradingService.SubmitOrder:input_type -> exchange.v1.SubmitOrderRequest
+	14, // 49: exchange.v1.TradingService.CancelOrder:input_type -> exchange.v1.CancelOrderRequest
+	16, // 50: exchange.v1.TradingService.GetOrder:input_type -> exchange.v1.GetOrderRequest
+	18, // 51: exchange.v1.TradingService.ListOpenOrders:input_type -> exchange.v1.ListOpenOrdersRequest
+	20, // 52: exchange.v1.TradingService.GetTrades:input_type -> exchange.v1.GetTradesRequest
+	22, // 53: exchange.v1.TradingService.ListOrderHistory:input_type -> exchange.v1.ListOrderHistoryRequest
+	24, // 54: exchange.v1.TradingService.ListTradeHistory:input_type -> exchange.v1.ListTradeHistoryRequest
+	34, // 55: exchange.v1.TradingService.GetBalances:input_type -> exchange.v1.GetBalancesRequest
+	37, // 56: exchange.v1.TradingService.GetAccountSnapshot:input_type -> exchange.v1.GetAccountSnapshotRequest
+	41, // 57: exchange.v1.TradingService.GetPositions:input_type -> exchange.v1.GetPositionsRequest
+	43, // 58: exchange.v1.TradingService.CheckOrder:input_type -> exchange.v1.CheckOrderRequest
+	46, // 59: exchange.v1.TradingService.OpenSession:input_type -> exchange.v1.OpenSessionRequest
+	48, // 60: exchange.v1.TradingService.StreamOrderUpdates:input_type -> exchange.v1.StreamOrderUpdatesRequest
+	51, // 61: exchange.v1.TradingService.StreamTrades:input_type -> exchange.v1.StreamTradesRequest
+	26, // 62: exchange.v1.MarketDataService.GetOrderBook:input_type -> exchange.v1.GetOrderBookRequest
+	29, // 63: exchange.v1.MarketDataService.GetTicker:input_type -> exchange.v1.GetTickerRequest
+	31, // 64: exchange.v1.MarketDataService.GetCandles:input_type -> exchange.v1.GetCandlesRequest
+	53, // 65: exchange.v1.MarketDataService.StreamOrderBook:input_type -> exchange.v1.StreamOrderBookRequest
+	57, // 66: exchange.v1.ChaosService.InjectFault:input_type -> exchange.v1.InjectFaultRequest
+	59, // 67: exchange.v1.ChaosService.ListFaults:input_type -> exchange.v1.ListFaultsRequest
+	61, // 68: exchange.v1.ChaosService.ClearFault:input_type -> exchange.v1.ClearFaultRequest
+	11, // 69: exchange.v1.TradingService.PlaceOrder:output_type -> exchange.v1.PlaceOrderResponse
+	13, // 70: exchange.v1.TradingService.SubmitOrder:output_type -> exchange.v1.SubmitOrderResponse
+	15, // 71: exchange.v1.TradingService.CancelOrder:output_type -> exchange.v1.CancelOrderResponse
+	17, // 72: exchange.v1.TradingService.GetOrder:output_type -> exchange.v1.GetOrderResponse
+	19, // 73: exchange.v1.TradingService.ListOpenOrders:output_type -> exchange.v1.ListOpenOrdersResponse
+	21, // 74: exchange.v1.TradingService.GetTrades:output_type -> exchange.v1.GetTradesResponse
+	23, // 75: exchange.v1.TradingService.ListOrderHistory:output_type -> exchange.v1.ListOrderHistoryResponse
+	25, // 76: exchange.v1.TradingService.ListTradeHistory:output_type -> exchange.v1.ListTradeHistoryResponse
+	36, // 77: exchange.v1.TradingService.GetBalances:output_type -> exchange.v1.GetBalancesResponse
+	40, // 78: exchange.v1.TradingService.GetAccountSnapshot:output_type -> exchange.v1.GetAccountSnapshotResponse
+	42, // 79: exchange.v1.TradingService.GetPositions:output_type -> exchange.v1.GetPositionsResponse
+	44, // 80: exchange.v1.TradingService.CheckOrder:output_type -> exchange.v1.CheckOrderResponse
+	47, // 81: exchange.v1.TradingService.OpenSession:output_type -> exchange.v1.SessionEvent
+	49, // 82: exchange.v1.TradingService.StreamOrderUpdates:output_type -> exchange.v1.OrderUpdate
+	52, // 83: exchange.v1.TradingService.StreamTrades:output_type -> exchange.v1.TradeEvent
+	28, // 84: exchange.v1.MarketDataService.GetOrderBook:output_type -> exchange.v1.GetOrderBookResponse
+	30, // 85: exchange.v1.MarketDataService.GetTicker:output_type -> exchange.v1.GetTickerResponse
+	33, // 86: exchange.v1.MarketDataService.GetCandles:output_type -> exchange.v1.GetCandlesResponse
+	54, // 87: exchange.v1.MarketDataService.StreamOrderBook:output_type -> exchange.v1.OrderBookUpdate
+	58, // 88: exchange.v1.ChaosService.InjectFault:output_type -> exchange.v1.InjectFaultResponse
+	60, // 89: exchange.v1.ChaosService.ListFaults:output_type -> exchange.v1.ListFaultsResponse
+	62, // 90: exchange.v1.ChaosService.ClearFault:output_type -> exchange.v1.ClearFaultResponse
+	69, // [69:91] is the sub-list for method output_type
+	47, // [47:69] is the sub-list for method input_type
+	47, // [47:47] is the sub-list for extension type_name
+	47, // [47:47] is the sub-list for extension extendee
+	0,  // [0:47] is the sub-list for field type_name
+}
+
+func init() { file_api_exchange_v1_exchange_proto_init() }
+func file_api_exchange_v1_exchange_proto_init() {
+	if File_api_exchange_v1_exchange_proto != nil {
+		return
+	}
+	file_api_exchange_v1_exchange_proto_msgTypes[56].OneofWrappers = []any{
+		(*OrderFlowEvent_Place)(nil),
+		(*OrderFlowEvent_Cancel)(nil),
+		(*OrderFlowEvent_Amend)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_exchange_v1_exchange_proto_rawDesc), len(file_api_exchange_v1_exchange_proto_rawDesc)),
+			NumEnums:      7,
+			NumMessages:   59,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_api_exchange_v1_exchange_proto_goTypes,
+		DependencyIndexes: file_api_exchange_v1_exchange_proto_depIdxs,
+		EnumInfos:         file_api_exchange_v1_exchange_proto_enumTypes,
+		MessageInfos:      file_api_exchange_v1_exchange_proto_msgTypes,
+	}.Build()
+	File_api_exchange_v1_exchange_proto = out.File
+	file_api_exchange_v1_exchange_proto_goTypes = nil
+	file_api_exchange_v1_exchange_proto_depIdxs = nil
+}
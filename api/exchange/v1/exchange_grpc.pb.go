@@ -0,0 +1,1178 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: api/exchange/v1/exchange.proto
+
+package exchangev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TradingService_PlaceOrder_FullMethodName         = "/exchange.v1.TradingService/PlaceOrder"
+	TradingService_SubmitOrder_FullMethodName        = "/exchange.v1.TradingService/SubmitOrder"
+	TradingService_CancelOrder_FullMethodName        = "/exchange.v1.TradingService/CancelOrder"
+	TradingService_GetOrder_FullMethodName           = "/exchange.v1.TradingService/GetOrder"
+	TradingService_ListOpenOrders_FullMethodName     = "/exchange.v1.TradingService/ListOpenOrders"
+	TradingService_GetTrades_FullMethodName          = "/exchange.v1.TradingService/GetTrades"
+	TradingService_ListOrderHistory_FullMethodName   = "/exchange.v1.TradingService/ListOrderHistory"
+	TradingService_ListTradeHistory_FullMethodName   = "/exchange.v1.TradingService/ListTradeHistory"
+	TradingService_GetBalances_FullMethodName        = "/exchange.v1.TradingService/GetBalances"
+	TradingService_GetAccountSnapshot_FullMethodName = "/exchange.v1.TradingService/GetAccountSnapshot"
+	TradingService_GetPositions_FullMethodName       = "/exchange.v1.TradingService/GetPositions"
+	TradingService_CheckOrder_FullMethodName         = "/exchange.v1.TradingService/CheckOrder"
+	TradingService_OpenSession_FullMethodName        = "/exchange.v1.TradingService/OpenSession"
+	TradingService_StreamOrderUpdates_FullMethodName = "/exchange.v1.TradingService/StreamOrderUpdates"
+	TradingService_StreamTrades_FullMethodName       = "/exchange.v1.TradingService/StreamTrades"
+)
+
+// TradingServiceClient is the client API for TradingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TradingServiceClient interface {
+	// PlaceOrder validates an order against instrument rules and submits it for matching
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	// SubmitOrder places an order without waiting for it. The response carries only a
+	// request sequence; the ack, accepted or rejected, follows on StreamOrderUpdates.
+	SubmitOrder(ctx context.Context, in *SubmitOrderRequest, opts ...grpc.CallOption) (*SubmitOrderResponse, error)
+	// CancelOrder cancels a working order
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	// GetOrder returns the current state of any order the venue knows
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
+	// ListOpenOrders lists working orders, optionally for one account and/or symbol
+	ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersResponse, error)
+	// GetTrades returns the latest executions, oldest first
+	GetTrades(ctx context.Context, in *GetTradesRequest, opts ...grpc.CallOption) (*GetTradesResponse, error)
+	// ListOrderHistory pages through orders, working and terminal, filtered by account,
+	// symbol, status and creation time; each page's cursor continues from its last order
+	ListOrderHistory(ctx context.Context, in *ListOrderHistoryRequest, opts ...grpc.CallOption) (*ListOrderHistoryResponse, error)
+	// ListTradeHistory pages through the trade tape, filtered by account, symbol and
+	// execution time; each page's cursor continues from its last trade
+	ListTradeHistory(ctx context.Context, in *ListTradeHistoryRequest, opts ...grpc.CallOption) (*ListTradeHistoryResponse, error)
+	// GetBalances returns an account's net position in every asset it has traded
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
+	// GetAccountSnapshot returns an account's balances, positions and open orders as of
+	// one engine sequence, so they reconcile against each other without racing matching
+	GetAccountSnapshot(ctx context.Context, in *GetAccountSnapshotRequest, opts ...grpc.CallOption) (*GetAccountSnapshotResponse, error)
+	// GetPositions returns net positions with average entry and realized and unrealized
+	// PnL, optionally for one account and/or one symbol
+	GetPositions(ctx context.Context, in *GetPositionsRequest, opts ...grpc.CallOption) (*GetPositionsResponse, error)
+	// CheckOrder runs every check PlaceOrder applies, balance and margin included, without
+	// placing the order or holding its balance
+	CheckOrder(ctx context.Context, in *CheckOrderRequest, opts ...grpc.CallOption) (*CheckOrderResponse, error)
+	// OpenSession holds a trading session for an account open for as long as the stream lasts.
+	// With cancel_on_disconnect set, the account's open orders are canceled when the stream
+	// drops and no session for the account is reopened within the grace period.
+	OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (TradingService_OpenSessionClient, error)
+	// StreamOrderUpdates streams every change to an order's state as it happens. Passing the
+	// sequence after the last one received resumes without gaps while the venue still
+	// retains it; otherwise the stream fails with OUT_OF_RANGE and the client resyncs.
+	StreamOrderUpdates(ctx context.Context, in *StreamOrderUpdatesRequest, opts ...grpc.CallOption) (TradingService_StreamOrderUpdatesClient, error)
+	// StreamTrades streams executions as they print, resumable the same way
+	StreamTrades(ctx context.Context, in *StreamTradesRequest, opts ...grpc.CallOption) (TradingService_StreamTradesClient, error)
+}
+
+type tradingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTradingServiceClient(cc grpc.ClientConnInterface) TradingServiceClient {
+	return &tradingServiceClient{cc}
+}
+
+func (c *tradingServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error) {
+	out := new(PlaceOrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_PlaceOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) SubmitOrder(ctx context.Context, in *SubmitOrderRequest, opts ...grpc.CallOption) (*SubmitOrderResponse, error) {
+	out := new(SubmitOrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_SubmitOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_CancelOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error) {
+	out := new(GetOrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_GetOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersResponse, error) {
+	out := new(ListOpenOrdersResponse)
+	err := c.cc.Invoke(ctx, TradingService_ListOpenOrders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetTrades(ctx context.Context, in *GetTradesRequest, opts ...grpc.CallOption) (*GetTradesResponse, error) {
+	out := new(GetTradesResponse)
+	err := c.cc.Invoke(ctx, TradingService_GetTrades_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) ListOrderHistory(ctx context.Context, in *ListOrderHistoryRequest, opts ...grpc.CallOption) (*ListOrderHistoryResponse, error) {
+	out := new(ListOrderHistoryResponse)
+	err := c.cc.Invoke(ctx, TradingService_ListOrderHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) ListTradeHistory(ctx context.Context, in *ListTradeHistoryRequest, opts ...grpc.CallOption) (*ListTradeHistoryResponse, error) {
+	out := new(ListTradeHistoryResponse)
+	err := c.cc.Invoke(ctx, TradingService_ListTradeHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, TradingService_GetBalances_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetAccountSnapshot(ctx context.Context, in *GetAccountSnapshotRequest, opts ...grpc.CallOption) (*GetAccountSnapshotResponse, error) {
+	out := new(GetAccountSnapshotResponse)
+	err := c.cc.Invoke(ctx, TradingService_GetAccountSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetPositions(ctx context.Context, in *GetPositionsRequest, opts ...grpc.CallOption) (*GetPositionsResponse, error) {
+	out := new(GetPositionsResponse)
+	err := c.cc.Invoke(ctx, TradingService_GetPositions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) CheckOrder(ctx context.Context, in *CheckOrderRequest, opts ...grpc.CallOption) (*CheckOrderResponse, error) {
+	out := new(CheckOrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_CheckOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (TradingService_OpenSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TradingService_ServiceDesc.Streams[0], TradingService_OpenSession_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tradingServiceOpenSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TradingService_OpenSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type tradingServiceOpenSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *tradingServiceOpenSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tradingServiceClient) StreamOrderUpdates(ctx context.Context, in *StreamOrderUpdatesRequest, opts ...grpc.CallOption) (TradingService_StreamOrderUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TradingService_ServiceDesc.Streams[1], TradingService_StreamOrderUpdates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tradingServiceStreamOrderUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TradingService_StreamOrderUpdatesClient interface {
+	Recv() (*OrderUpdate, error)
+	grpc.ClientStream
+}
+
+type tradingServiceStreamOrderUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *tradingServiceStreamOrderUpdatesClient) Recv() (*OrderUpdate, error) {
+	m := new(OrderUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tradingServiceClient) StreamTrades(ctx context.Context, in *StreamTradesRequest, opts ...grpc.CallOption) (TradingService_StreamTradesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TradingService_ServiceDesc.Streams[2], TradingService_StreamTrades_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tradingServiceStreamTradesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TradingService_StreamTradesClient interface {
+	Recv() (*TradeEvent, error)
+	grpc.ClientStream
+}
+
+type tradingServiceStreamTradesClient struct {
+	grpc.ClientStream
+}
+
+func (x *tradingServiceStreamTradesClient) Recv() (*TradeEvent, error) {
+	m := new(TradeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TradingServiceServer is the server API for TradingService service.
+// All implementations must embed UnimplementedTradingServiceServer
+// for forward compatibility
+type TradingServiceServer interface {
+	// PlaceOrder validates an order against instrument rules and submits it for matching
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	// SubmitOrder places an order without waiting for it. The response carries only a
+	// request sequence; the ack, accepted or rejected, follows on StreamOrderUpdates.
+	SubmitOrder(context.Context, *SubmitOrderRequest) (*SubmitOrderResponse, error)
+	// CancelOrder cancels a working order
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	// GetOrder returns the current state of any order the venue knows
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	// ListOpenOrders lists working orders, optionally for one account and/or symbol
+	ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersResponse, error)
+	// GetTrades returns the latest executions, oldest first
+	GetTrades(context.Context, *GetTradesRequest) (*GetTradesResponse, error)
+	// ListOrderHistory pages through orders, working and terminal, filtered by account,
+	// symbol, status and creation time; each page's cursor continues from its last order
+	ListOrderHistory(context.Context, *ListOrderHistoryRequest) (*ListOrderHistoryResponse, error)
+	// ListTradeHistory pages through the trade tape, filtered by account, symbol and
+	// execution time; each page's cursor continues from its last trade
+	ListTradeHistory(context.Context, *ListTradeHistoryRequest) (*ListTradeHistoryResponse, error)
+	// GetBalances returns an account's net position in every asset it has traded
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
+	// GetAccountSnapshot returns an account's balances, positions and open orders as of
+	// one engine sequence, so they reconcile against each other without racing matching
+	GetAccountSnapshot(context.Context, *GetAccountSnapshotRequest) (*GetAccountSnapshotResponse, error)
+	// GetPositions returns net positions with average entry and realized and unrealized
+	// PnL, optionally for one account and/or one symbol
+	GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error)
+	// CheckOrder runs every check PlaceOrder applies, balance and margin included, without
+	// placing the order or holding its balance
+	CheckOrder(context.Context, *CheckOrderRequest) (*CheckOrderResponse, error)
+	// OpenSession holds a trading session for an account open for as long as the stream lasts.
+	// With cancel_on_disconnect set, the account's open orders are canceled when the stream
+	// drops and no session for the account is reopened within the grace period.
+	OpenSession(*OpenSessionRequest, TradingService_OpenSessionServer) error
+	// StreamOrderUpdates streams every change to an order's state as it happens. Passing the
+	// sequence after the last one received resumes without gaps while the venue still
+	// retains it; otherwise the stream fails with OUT_OF_RANGE and the client resyncs.
+	StreamOrderUpdates(*StreamOrderUpdatesRequest, TradingService_StreamOrderUpdatesServer) error
+	// StreamTrades streams executions as they print, resumable the same way
+	StreamTrades(*StreamTradesRequest, TradingService_StreamTradesServer) error
+	mustEmbedUnimplementedTradingServiceServer()
+}
+
+// UnimplementedTradingServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTradingServiceServer struct {
+}
+
+func (UnimplementedTradingServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) SubmitOrder(context.Context, *SubmitOrderRequest) (*SubmitOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOpenOrders not implemented")
+}
+func (UnimplementedTradingServiceServer) GetTrades(context.Context, *GetTradesRequest) (*GetTradesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrades not implemented")
+}
+func (UnimplementedTradingServiceServer) ListOrderHistory(context.Context, *ListOrderHistoryRequest) (*ListOrderHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrderHistory not implemented")
+}
+func (UnimplementedTradingServiceServer) ListTradeHistory(context.Context, *ListTradeHistoryRequest) (*ListTradeHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTradeHistory not implemented")
+}
+func (UnimplementedTradingServiceServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedTradingServiceServer) GetAccountSnapshot(context.Context, *GetAccountSnapshotRequest) (*GetAccountSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountSnapshot not implemented")
+}
+func (UnimplementedTradingServiceServer) GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPositions not implemented")
+}
+func (UnimplementedTradingServiceServer) CheckOrder(context.Context, *CheckOrderRequest) (*CheckOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) OpenSession(*OpenSessionRequest, TradingService_OpenSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method OpenSession not implemented")
+}
+func (UnimplementedTradingServiceServer) StreamOrderUpdates(*StreamOrderUpdatesRequest, TradingService_StreamOrderUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderUpdates not implemented")
+}
+func (UnimplementedTradingServiceServer) StreamTrades(*StreamTradesRequest, TradingService_StreamTradesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTrades not implemented")
+}
+func (UnimplementedTradingServiceServer) mustEmbedUnimplementedTradingServiceServer() {}
+
+// UnsafeTradingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TradingServiceServer will
+// result in compilation errors.
+type UnsafeTradingServiceServer interface {
+	mustEmbedUnimplementedTradingServiceServer()
+}
+
+func RegisterTradingServiceServer(s grpc.ServiceRegistrar, srv TradingServiceServer) {
+	s.RegisterService(&TradingService_ServiceDesc, srv)
+}
+
+func _TradingService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_SubmitOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).SubmitOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_SubmitOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).SubmitOrder(ctx, req.(*SubmitOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_ListOpenOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).ListOpenOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_ListOpenOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).ListOpenOrders(ctx, req.(*ListOpenOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetTrades_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetTrades(ctx, req.(*GetTradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_ListOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrderHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).ListOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_ListOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).ListOrderHistory(ctx, req.(*ListOrderHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_ListTradeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTradeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).ListTradeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_ListTradeHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).ListTradeHistory(ctx, req.(*ListTradeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetAccountSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetAccountSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetAccountSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetAccountSnapshot(ctx, req.(*GetAccountSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPositionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetPositions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetPositions(ctx, req.(*GetPositionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_CheckOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).CheckOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_CheckOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).CheckOrder(ctx, req.(*CheckOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_OpenSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TradingServiceServer).OpenSession(m, &tradingServiceOpenSessionServer{stream})
+}
+
+type TradingService_OpenSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type tradingServiceOpenSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *tradingServiceOpenSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TradingService_StreamOrderUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrderUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TradingServiceServer).StreamOrderUpdates(m, &tradingServiceStreamOrderUpdatesServer{stream})
+}
+
+type TradingService_StreamOrderUpdatesServer interface {
+	Send(*OrderUpdate) error
+	grpc.ServerStream
+}
+
+type tradingServiceStreamOrderUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *tradingServiceStreamOrderUpdatesServer) Send(m *OrderUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TradingService_StreamTrades_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTradesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TradingServiceServer).StreamTrades(m, &tradingServiceStreamTradesServer{stream})
+}
+
+type TradingService_StreamTradesServer interface {
+	Send(*TradeEvent) error
+	grpc.ServerStream
+}
+
+type tradingServiceStreamTradesServer struct {
+	grpc.ServerStream
+}
+
+func (x *tradingServiceStreamTradesServer) Send(m *TradeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TradingService_ServiceDesc is the grpc.ServiceDesc for TradingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TradingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.v1.TradingService",
+	HandlerType: (*TradingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _TradingService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "SubmitOrder",
+			Handler:    _TradingService_SubmitOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _TradingService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _TradingService_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOpenOrders",
+			Handler:    _TradingService_ListOpenOrders_Handler,
+		},
+		{
+			MethodName: "GetTrades",
+			Handler:    _TradingService_GetTrades_Handler,
+		},
+		{
+			MethodName: "ListOrderHistory",
+			Handler:    _TradingService_ListOrderHistory_Handler,
+		},
+		{
+			MethodName: "ListTradeHistory",
+			Handler:    _TradingService_ListTradeHistory_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _TradingService_GetBalances_Handler,
+		},
+		{
+			MethodName: "GetAccountSnapshot",
+			Handler:    _TradingService_GetAccountSnapshot_Handler,
+		},
+		{
+			MethodName: "GetPositions",
+			Handler:    _TradingService_GetPositions_Handler,
+		},
+		{
+			MethodName: "CheckOrder",
+			Handler:    _TradingService_CheckOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "OpenSession",
+			Handler:       _TradingService_OpenSession_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrderUpdates",
+			Handler:       _TradingService_StreamOrderUpdates_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTrades",
+			Handler:       _TradingService_StreamTrades_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/exchange/v1/exchange.proto",
+}
+
+const (
+	MarketDataService_GetOrderBook_FullMethodName    = "/exchange.v1.MarketDataService/GetOrderBook"
+	MarketDataService_GetTicker_FullMethodName       = "/exchange.v1.MarketDataService/GetTicker"
+	MarketDataService_GetCandles_FullMethodName      = "/exchange.v1.MarketDataService/GetCandles"
+	MarketDataService_StreamOrderBook_FullMethodName = "/exchange.v1.MarketDataService/StreamOrderBook"
+)
+
+// MarketDataServiceClient is the client API for MarketDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MarketDataServiceClient interface {
+	// GetOrderBook returns aggregated price levels for a symbol
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error)
+	// GetTicker returns a symbol's rolling 24h statistics and current top of book
+	GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*GetTickerResponse, error)
+	// GetCandles returns OHLCV bars built from executions, including archived history
+	// older than the venue keeps in memory
+	GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error)
+	// StreamOrderBook streams a symbol's book: a snapshot, then the levels each change
+	// moved. Updates are numbered in the same per-symbol sequence as GetOrderBook, so a
+	// local book seeded from either stays current by applying updates above its
+	// sequence; a jump in the sequence means an update was missed and the book must be
+	// reseeded.
+	StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (MarketDataService_StreamOrderBookClient, error)
+}
+
+type marketDataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMarketDataServiceClient(cc grpc.ClientConnInterface) MarketDataServiceClient {
+	return &marketDataServiceClient{cc}
+}
+
+func (c *marketDataServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error) {
+	out := new(GetOrderBookResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_GetOrderBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*GetTickerResponse, error) {
+	out := new(GetTickerResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_GetTicker_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error) {
+	out := new(GetCandlesResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_GetCandles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (MarketDataService_StreamOrderBookClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[0], MarketDataService_StreamOrderBook_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketDataServiceStreamOrderBookClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketDataService_StreamOrderBookClient interface {
+	Recv() (*OrderBookUpdate, error)
+	grpc.ClientStream
+}
+
+type marketDataServiceStreamOrderBookClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketDataServiceStreamOrderBookClient) Recv() (*OrderBookUpdate, error) {
+	m := new(OrderBookUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarketDataServiceServer is the server API for MarketDataService service.
+// All implementations must embed UnimplementedMarketDataServiceServer
+// for forward compatibility
+type MarketDataServiceServer interface {
+	// GetOrderBook returns aggregated price levels for a symbol
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error)
+	// GetTicker returns a symbol's rolling 24h statistics and current top of book
+	GetTicker(context.Context, *GetTickerRequest) (*GetTickerResponse, error)
+	// GetCandles returns OHLCV bars built from executions, including archived history
+	// older than the venue keeps in memory
+	GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error)
+	// StreamOrderBook streams a symbol's book: a snapshot, then the levels each change
+	// moved. Updates are numbered in the same per-symbol sequence as GetOrderBook, so a
+	// local book seeded from either stays current by applying updates above its
+	// sequence; a jump in the sequence means an update was missed and the book must be
+	// reseeded.
+	StreamOrderBook(*StreamOrderBookRequest, MarketDataService_StreamOrderBookServer) error
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+// UnimplementedMarketDataServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMarketDataServiceServer struct {
+}
+
+func (UnimplementedMarketDataServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetTicker(context.Context, *GetTickerRequest) (*GetTickerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicker not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
+func (UnimplementedMarketDataServiceServer) StreamOrderBook(*StreamOrderBookRequest, MarketDataService_StreamOrderBookServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderBook not implemented")
+}
+func (UnimplementedMarketDataServiceServer) mustEmbedUnimplementedMarketDataServiceServer() {}
+
+// UnsafeMarketDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MarketDataServiceServer will
+// result in compilation errors.
+type UnsafeMarketDataServiceServer interface {
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+func RegisterMarketDataServiceServer(s grpc.ServiceRegistrar, srv MarketDataServiceServer) {
+	s.RegisterService(&MarketDataService_ServiceDesc, srv)
+}
+
+func _MarketDataService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_GetTicker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTickerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetTicker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetTicker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetTicker(ctx, req.(*GetTickerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetCandles(ctx, req.(*GetCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_StreamOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).StreamOrderBook(m, &marketDataServiceStreamOrderBookServer{stream})
+}
+
+type MarketDataService_StreamOrderBookServer interface {
+	Send(*OrderBookUpdate) error
+	grpc.ServerStream
+}
+
+type marketDataServiceStreamOrderBookServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketDataServiceStreamOrderBookServer) Send(m *OrderBookUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// MarketDataService_ServiceDesc is the grpc.ServiceDesc for MarketDataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MarketDataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.v1.MarketDataService",
+	HandlerType: (*MarketDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _MarketDataService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "GetTicker",
+			Handler:    _MarketDataService_GetTicker_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _MarketDataService_GetCandles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrderBook",
+			Handler:       _MarketDataService_StreamOrderBook_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/exchange/v1/exchange.proto",
+}
+
+const (
+	ChaosService_InjectFault_FullMethodName = "/exchange.v1.ChaosService/InjectFault"
+	ChaosService_ListFaults_FullMethodName  = "/exchange.v1.ChaosService/ListFaults"
+	ChaosService_ClearFault_FullMethodName  = "/exchange.v1.ChaosService/ClearFault"
+)
+
+// ChaosServiceClient is the client API for ChaosService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChaosServiceClient interface {
+	// InjectFault starts a fault: rejecting or delaying orders, dropping market data,
+	// corrupting a balance or halting symbols
+	InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*InjectFaultResponse, error)
+	// ListFaults returns the faults in force
+	ListFaults(ctx context.Context, in *ListFaultsRequest, opts ...grpc.CallOption) (*ListFaultsResponse, error)
+	// ClearFault ends a fault early, resuming symbols it halted
+	ClearFault(ctx context.Context, in *ClearFaultRequest, opts ...grpc.CallOption) (*ClearFaultResponse, error)
+}
+
+type chaosServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChaosServiceClient(cc grpc.ClientConnInterface) ChaosServiceClient {
+	return &chaosServiceClient{cc}
+}
+
+func (c *chaosServiceClient) InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*InjectFaultResponse, error) {
+	out := new(InjectFaultResponse)
+	err := c.cc.Invoke(ctx, ChaosService_InjectFault_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosServiceClient) ListFaults(ctx context.Context, in *ListFaultsRequest, opts ...grpc.CallOption) (*ListFaultsResponse, error) {
+	out := new(ListFaultsResponse)
+	err := c.cc.Invoke(ctx, ChaosService_ListFaults_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosServiceClient) ClearFault(ctx context.Context, in *ClearFaultRequest, opts ...grpc.CallOption) (*ClearFaultResponse, error) {
+	out := new(ClearFaultResponse)
+	err := c.cc.Invoke(ctx, ChaosService_ClearFault_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChaosServiceServer is the server API for ChaosService service.
+// All implementations must embed UnimplementedChaosServiceServer
+// for forward compatibility
+type ChaosServiceServer interface {
+	// InjectFault starts a fault: rejecting or delaying orders, dropping market data,
+	// corrupting a balance or halting symbols
+	InjectFault(context.Context, *InjectFaultRequest) (*InjectFaultResponse, error)
+	// ListFaults returns the faults in force
+	ListFaults(context.Context, *ListFaultsRequest) (*ListFaultsResponse, error)
+	// ClearFault ends a fault early, resuming symbols it halted
+	ClearFault(context.Context, *ClearFaultRequest) (*ClearFaultResponse, error)
+	mustEmbedUnimplementedChaosServiceServer()
+}
+
+// UnimplementedChaosServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedChaosServiceServer struct {
+}
+
+func (UnimplementedChaosServiceServer) InjectFault(context.Context, *InjectFaultRequest) (*InjectFaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectFault not implemented")
+}
+func (UnimplementedChaosServiceServer) ListFaults(context.Context, *ListFaultsRequest) (*ListFaultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFaults not implemented")
+}
+func (UnimplementedChaosServiceServer) ClearFault(context.Context, *ClearFaultRequest) (*ClearFaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearFault not implemented")
+}
+func (UnimplementedChaosServiceServer) mustEmbedUnimplementedChaosServiceServer() {}
+
+// UnsafeChaosServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChaosServiceServer will
+// result in compilation errors.
+type UnsafeChaosServiceServer interface {
+	mustEmbedUnimplementedChaosServiceServer()
+}
+
+func RegisterChaosServiceServer(s grpc.ServiceRegistrar, srv ChaosServiceServer) {
+	s.RegisterService(&ChaosService_ServiceDesc, srv)
+}
+
+func _ChaosService_InjectFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosServiceServer).InjectFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosService_InjectFault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosServiceServer).InjectFault(ctx, req.(*InjectFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosService_ListFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosServiceServer).ListFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosService_ListFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosServiceServer).ListFaults(ctx, req.(*ListFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosService_ClearFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosServiceServer).ClearFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosService_ClearFault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosServiceServer).ClearFault(ctx, req.(*ClearFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ChaosService_ServiceDesc is the grpc.ServiceDesc for ChaosService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChaosService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.v1.ChaosService",
+	HandlerType: (*ChaosServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InjectFault",
+			Handler:    _ChaosService_InjectFault_Handler,
+		},
+		{
+			MethodName: "ListFaults",
+			Handler:    _ChaosService_ListFaults_Handler,
+		},
+		{
+			MethodName: "ClearFault",
+			Handler:    _ChaosService_ClearFault_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/exchange/v1/exchange.proto",
+}
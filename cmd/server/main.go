@@ -2,25 +2,14 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"net"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
-	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/app"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
-	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
-	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
-	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
-	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
 )
 
 func main() {
@@ -39,108 +28,10 @@ func main() {
 
 	logger.Info("Starting exchange-simulator service")
 
-	// Initialize Prometheus Metrics Adapter
-	constantLabels := (&ports.MetricsLabels{
-		Service:  cfg.ServiceName,
-		Instance: cfg.ServiceInstanceName,
-		Version:  cfg.ServiceVersion,
-	}).ConstantLabels()
-	metricsPort := observability.NewPrometheusMetricsAdapter(constantLabels)
-	cfg.SetMetricsPort(metricsPort)
-	logger.Info("Prometheus metrics adapter initialized")
-
-	// Initialize DataAdapter
-	ctx := context.Background()
-	if err := cfg.InitializeDataAdapter(ctx, logger); err != nil {
-		logger.WithError(err).Warn("Failed to initialize data adapter, continuing in stub mode")
-	} else {
-		logger.Info("Data adapter initialized successfully")
+	// Serve until SIGINT or SIGTERM, then drain
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := app.New(cfg, logger, buildRevision()).Run(ctx); err != nil {
+		logger.WithError(err).Fatal("Exchange simulator failed")
 	}
-
-	exchangeService := services.NewExchangeService(cfg, logger)
-
-	grpcServer := setupGRPCServer(cfg, exchangeService, logger)
-	httpServer := setupHTTPServer(cfg, exchangeService, logger)
-
-	go func() {
-		logger.WithField("port", cfg.GRPCPort).Info("Starting gRPC server")
-		if err := startGRPCServer(grpcServer, cfg.GRPCPort); err != nil {
-			logger.WithError(err).Fatal("Failed to start gRPC server")
-		}
-	}()
-
-	go func() {
-		logger.WithField("port", cfg.HTTPPort).Info("Starting HTTP server")
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.WithError(err).Fatal("Failed to start HTTP server")
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	logger.Info("Shutting down servers...")
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	// Disconnect DataAdapter
-	if err := cfg.DisconnectDataAdapter(shutdownCtx); err != nil {
-		logger.WithError(err).Error("Failed to disconnect data adapter")
-	}
-
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		logger.WithError(err).Error("HTTP server forced to shutdown")
-	}
-
-	grpcServer.GracefulStop()
-	logger.Info("Servers shutdown complete")
 }
-
-func setupGRPCServer(cfg *config.Config, exchangeService *services.ExchangeService, logger *logrus.Logger) *grpc.Server {
-	server := grpc.NewServer()
-
-	healthServer := health.NewServer()
-	grpc_health_v1.RegisterHealthServer(server, healthServer)
-	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
-
-	return server
-}
-
-func setupHTTPServer(cfg *config.Config, exchangeService *services.ExchangeService, logger *logrus.Logger) *http.Server {
-	router := gin.New()
-	router.Use(gin.Recovery())
-
-	// Add RED metrics middleware for all routes
-	metricsPort := cfg.GetMetricsPort()
-	if metricsPort != nil {
-		router.Use(observability.REDMetricsMiddleware(metricsPort))
-		router.Use(observability.HealthMetricsMiddleware(metricsPort, "exchange-simulator"))
-	}
-
-	healthHandler := handlers.NewHealthHandlerWithConfig(cfg, logger)
-	metricsHandler := handlers.NewMetricsHandler(metricsPort)
-
-	v1 := router.Group("/api/v1")
-	{
-		v1.GET("/health", healthHandler.Health)
-		v1.GET("/ready", healthHandler.Ready)
-	}
-
-	// Metrics endpoint (outside v1 group, at root level)
-	router.GET("/metrics", metricsHandler.Metrics)
-
-	return &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler: router,
-	}
-}
-
-func startGRPCServer(server *grpc.Server, port int) error {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		return err
-	}
-	return server.Serve(lis)
-}
\ No newline at end of file
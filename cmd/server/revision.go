@@ -0,0 +1,32 @@
+package main
+
+import "runtime/debug"
+
+// gitSHA is the commit the binary was built from, set with
+// -ldflags "-X main.gitSHA=<sha>"; builds from a checkout record it themselves
+var gitSHA string
+
+// buildRevision returns the commit the binary was built from, or "" if unknown
+func buildRevision() string {
+	if gitSHA != "" {
+		return gitSHA
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		// Uncommitted changes mean the commit alone does not reproduce the binary
+		revision += "-dirty"
+	}
+	return revision
+}
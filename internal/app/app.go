@@ -0,0 +1,853 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/candlestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/orderflow"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
+	fixpresentation "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/presentation/fix"
+	grpcpresentation "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/presentation/grpc"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// shutdownTimeout limits the drain once the application is told to stop
+const shutdownTimeout = 30 * time.Second
+
+// Application is the exchange simulator: the exchange service, the gRPC, HTTP and
+// FIX servers in front of it and the infrastructure behind it. Start brings it up,
+// Shutdown drains it; Run does both around a signal.
+type Application struct {
+	cfg      *config.Config
+	logger   *logrus.Logger
+	revision string // Commit the binary was built from, recorded in the run manifest
+
+	exchangeService *services.ExchangeService
+	coordinator     *startup.Coordinator
+	logControl      *observability.LogControl // Log level and sampling, changed at runtime
+	discovery       *infrastructure.ServiceDiscoveryClient
+	configuration   *infrastructure.ConfigurationClient // Shared by everything reading the configuration service once
+	storage         *scenarioStorage
+	caches          map[string]ports.CacheReporter // Configuration clients the admin debug API reports on
+
+	grpcServer  *grpcpresentation.ExchangeGRPCServer
+	httpServer  *http.Server
+	fixAcceptor *fixpresentation.Acceptor // nil without FIX_PORT
+
+	// Loops that run until shutdown: the scheduler, feeds, market maker and the like
+	background     context.Context
+	stopBackground context.CancelFunc
+
+	flushes   []flush           // Persistence loops stopped and flushed in order once the servers drain
+	stopAudit func()            // Ends the audit trail or outbox once its last changes are reported; nil when off
+	closers   []func()          // Run last-first at the end of shutdown, as defers would
+	runFiles  map[string][]byte // Inputs recorded in the run manifest, by path or key
+	script    *scenario.Script
+	recording *replay.Recording
+}
+
+// flush stops one persistence loop and saves what it has not
+type flush struct {
+	cancel  context.CancelFunc
+	done    <-chan struct{} // Closed once the loop has returned
+	save    func() error
+	failure string // Logged when save fails
+}
+
+// stop ends the loop and waits for it to return, so a tick still running does not
+// race the final save
+func (f flush) stop() {
+	f.cancel()
+	<-f.done
+}
+
+// New creates the application; nothing is opened until Start
+func New(cfg *config.Config, logger *logrus.Logger, revision string) *Application {
+	background, stopBackground := context.WithCancel(context.Background())
+	return &Application{
+		cfg:            cfg,
+		logger:         logger,
+		revision:       revision,
+		caches:         make(map[string]ports.CacheReporter),
+		runFiles:       make(map[string][]byte),
+		background:     background,
+		stopBackground: stopBackground,
+	}
+}
+
+// Run starts the application, serves until ctx ends and then drains it within
+// the shutdown timeout
+func (a *Application) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return a.Shutdown(shutdownCtx)
+}
+
+// Start opens every subsystem, brings dependencies up in order, restores state and
+// starts the servers. ctx limits startup only: what Start launches runs until
+// Shutdown. On error everything opened so far is closed again.
+func (a *Application) Start(ctx context.Context) error {
+	if err := a.start(ctx); err != nil {
+		a.close()
+		return err
+	}
+	return nil
+}
+
+func (a *Application) start(ctx context.Context) error {
+	if err := a.openObservability(ctx); err != nil {
+		return err
+	}
+	if err := a.openExchange(ctx); err != nil {
+		return err
+	}
+	if err := a.configureExchange(ctx); err != nil {
+		return err
+	}
+	if err := a.restoreState(ctx); err != nil {
+		return err
+	}
+	if err := a.seedFixture(ctx); err != nil {
+		return err
+	}
+	if err := a.startBackground(); err != nil {
+		return err
+	}
+	if err := a.startRun(ctx); err != nil {
+		return err
+	}
+	return a.startServers()
+}
+
+// openObservability sets up log control, metrics, tracing and the venue clock
+func (a *Application) openObservability(ctx context.Context) error {
+	cfg, logger := a.cfg, a.logger
+
+	// Levels and sampling change at runtime through the admin API and LOG_CONFIG_KEY
+	a.logControl = observability.NewLogControl(logger)
+	if cfg.LogLevel != "" {
+		if _, err := a.logControl.Set(observability.LogSettings{Level: cfg.LogLevel}); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+	}
+
+	// Prometheus, or OpenTelemetry pushing to a collector
+	metricsPort, flushMetrics, err := openMetrics(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up metrics: %w", err)
+	}
+	cfg.SetMetricsPort(metricsPort)
+	a.onClose(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := flushMetrics(ctx); err != nil {
+			logger.WithError(err).Warn("Failed to flush metrics")
+		}
+	})
+	logger.WithField("backend", cfg.MetricsBackend).Info("Metrics adapter initialized")
+
+	// Tracing is off without a collector
+	if cfg.TracingEndpoint != "" {
+		exporter, err := observability.NewOTLPTraceExporter(ctx, cfg.TracingEndpoint)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_EXPORTER_OTLP_ENDPOINT: %w", err)
+		}
+		tracingPort := observability.NewOTelTracingAdapter(exporter, ports.MetricsLabels{
+			Service:  cfg.ServiceName,
+			Instance: cfg.ServiceInstanceName,
+			Version:  cfg.ServiceVersion,
+		}, cfg.TracingSampleRatio)
+		cfg.SetTracingPort(tracingPort)
+		a.onClose(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := tracingPort.Shutdown(ctx); err != nil {
+				logger.WithError(err).Warn("Failed to flush traces")
+			}
+		})
+		logger.WithField("endpoint", cfg.TracingEndpoint).Info("OpenTelemetry tracing adapter initialized")
+	}
+
+	venueClock, err := openClock(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up venue clock: %w", err)
+	}
+	cfg.SetClock(venueClock)
+	logger.WithField("mode", cfg.ClockMode).Info("Venue clock initialized")
+	return nil
+}
+
+// openExchange secures gRPC, creates the exchange service and brings its
+// dependencies up in order
+func (a *Application) openExchange(ctx context.Context) error {
+	cfg, logger := a.cfg, a.logger
+	a.discovery = infrastructure.NewServiceDiscoveryClient(cfg, logger)
+	a.onClose(func() { a.discovery.Stop() })
+	a.configuration = infrastructure.NewConfigurationClient(cfg, logger)
+	a.caches["configuration"] = a.configuration
+
+	// Secure gRPC before any server starts or service is dialled
+	if err := infrastructure.ResolveTLSMaterial(ctx, cfg, a.configuration); err != nil {
+		return fmt.Errorf("failed to load gRPC TLS material: %w", err)
+	}
+	serverCredentials, err := infrastructure.ServerCredentials(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid GRPC_TLS_* settings: %w", err)
+	}
+	if _, err := infrastructure.ClientCredentials(cfg); err != nil {
+		return fmt.Errorf("invalid GRPC_TLS_* settings: %w", err)
+	}
+	logger.WithField("mode", cfg.TLSMode).Info("gRPC transport security initialized")
+
+	a.exchangeService = services.NewExchangeService(cfg, logger)
+
+	// Bring dependencies up in order before anything relies on them; readiness
+	// stays off until the servers are started
+	a.coordinator = a.newStartup()
+	if err := a.coordinator.Run(ctx); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
+	startupReport := a.coordinator.Report()
+	for _, component := range startupReport.Components {
+		if component.Name == "data_adapter" && component.State == startup.StateStub {
+			a.exchangeService.ReportHealth(ctx, services.ComponentDataAdapter, incidents.StatusDown, "stub mode: "+component.Error)
+		}
+	}
+	a.onClose(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := cfg.DisconnectDataAdapter(ctx); err != nil {
+			logger.WithError(err).Error("Failed to disconnect data adapter")
+		}
+	})
+	logger.WithField("phase", startupReport.Phase).Info("Dependencies started")
+
+	a.grpcServer = grpcpresentation.NewExchangeGRPCServer(cfg, a.exchangeService, logger).
+		WithCredentials(serverCredentials).
+		WithStartup(a.coordinator)
+	return nil
+}
+
+// configureExchange applies the venue settings: the personality, public IDs, the
+// scenario, account profiles, the insurance fund, API versions, stream entitlements
+// and rate limits
+func (a *Application) configureExchange(ctx context.Context) error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+
+	// The personality goes first, so every other setting sees the venue's own listings
+	if cfg.PersonalityConfigKey != "" {
+		personality, origin, data, err := loadPersonality(ctx, cfg, a.configuration)
+		if err != nil {
+			return fmt.Errorf("failed to load venue personality: %w", err)
+		}
+		a.runFiles[origin] = data
+		if _, err := exchangeService.SetPersonality(ctx, personality); err != nil {
+			return fmt.Errorf("invalid venue personality: %w", err)
+		}
+	}
+
+	publicIDs, err := openIDObfuscator(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to set up public IDs: %w", err)
+	}
+	exchangeService.SetIDObfuscator(publicIDs)
+
+	if cfg.ScenarioPath != "" {
+		loaded, data, err := loadScenario(cfg.ScenarioPath)
+		if err != nil {
+			return fmt.Errorf("failed to load scenario: %w", err)
+		}
+		a.runFiles[cfg.ScenarioPath] = data
+		exchangeService.SetScenario(loaded)
+		logger.WithFields(logrus.Fields{
+			"scenario":   loaded.Name,
+			"assertions": len(loaded.Assertions),
+		}).Info("Scenario assertions loaded")
+	}
+
+	profiles, err := services.ParseAccountProfiles(cfg.AccountProfiles)
+	if err != nil {
+		return fmt.Errorf("invalid ACCOUNT_PROFILES: %w", err)
+	}
+	for _, profile := range profiles {
+		if _, err := exchangeService.SetAccountProfile(ctx, profile); err != nil {
+			return fmt.Errorf("invalid ACCOUNT_PROFILES: %w", err)
+		}
+	}
+
+	insuranceFund, err := services.ParseInsuranceFund(cfg.InsuranceFund)
+	if err != nil {
+		return fmt.Errorf("invalid INSURANCE_FUND: %w", err)
+	}
+	exchangeService.SetInsuranceFund(insuranceFund)
+
+	if lifecycle, ok, err := services.APILifecycleFromConfig(cfg, exchangeService.Now()); err != nil {
+		return fmt.Errorf("invalid API_V1_* settings: %w", err)
+	} else if ok {
+		if _, err := exchangeService.SetAPILifecycle(ctx, lifecycle); err != nil {
+			return fmt.Errorf("invalid API_V1_* settings: %w", err)
+		}
+	}
+
+	streamLimits, err := entitlements.ParseLimits(cfg.StreamKeyLimits)
+	if err != nil {
+		return fmt.Errorf("invalid STREAM_KEY_LIMITS: %w", err)
+	}
+	exchangeService.SetStreamLimits(streamLimits)
+
+	endpointWeights, err := ratelimit.ParseWeights(cfg.RateLimitWeights)
+	if err != nil {
+		return fmt.Errorf("invalid RATE_LIMIT_WEIGHTS: %w", err)
+	}
+	rateLimits := exchangeService.RateLimits()
+	rateLimits.Weights = endpointWeights
+	if _, err := exchangeService.SetRateLimits(ctx, rateLimits); err != nil {
+		return fmt.Errorf("invalid RATE_LIMIT_* settings: %w", err)
+	}
+	return nil
+}
+
+// restoreState opens storage, migrating its schema if asked to, and restores the
+// engine, idempotency keys, accounts and statistics from it, persisting each from
+// then on
+func (a *Application) restoreState(ctx context.Context) error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+
+	storage, err := openStorage(ctx, cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to open scenario storage: %w", err)
+	}
+	a.storage = storage
+	a.onClose(storage.Close)
+
+	// Set before orders and accounts are restored, so the rebuilt journal is persisted too
+	if storage.balanceStore != nil {
+		exchangeService.SetBalanceStore(storage.balanceStore)
+	}
+
+	if storage.stateStore != nil {
+		// The saved state stands in for the journal up to its sequence
+		state, err := storage.stateStore.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load matching engine state: %w", err)
+		}
+		var events []matching.Event
+		if storage.source != nil {
+			if events, err = storage.source.ReadAll(); err != nil {
+				return fmt.Errorf("failed to read matching engine event log: %w", err)
+			}
+		}
+		eventLog := storage.eventLog
+		if eventLog == nil && cfg.RunBundlePath != "" {
+			// As below, the bundle needs the journal even when nothing persists it
+			eventLog = matching.NewMemoryEventLog()
+		}
+		if err := exchangeService.RestoreFromState(state, events, eventLog); err != nil {
+			return fmt.Errorf("failed to restore matching engine state: %w", err)
+		}
+	} else if storage.source != nil {
+		events, err := storage.source.ReadAll()
+		if err != nil {
+			return fmt.Errorf("failed to read matching engine event log: %w", err)
+		}
+		if err := exchangeService.RestoreFromEventLog(events, storage.eventLog); err != nil {
+			return fmt.Errorf("failed to replay matching engine event log: %w", err)
+		}
+	} else if cfg.RunBundlePath != "" {
+		// The bundle needs the journal even when nothing persists it
+		if err := exchangeService.RestoreFromEventLog(nil, matching.NewMemoryEventLog()); err != nil {
+			return fmt.Errorf("failed to start the in-memory event journal: %w", err)
+		}
+	}
+
+	if storage.idempotency != nil {
+		if err := exchangeService.SetIdempotencyStore(storage.idempotency); err != nil {
+			return fmt.Errorf("failed to restore idempotency keys: %w", err)
+		}
+	}
+
+	if storage.accountStore != nil {
+		if err := exchangeService.SetAccountStore(storage.accountStore); err != nil {
+			return fmt.Errorf("failed to restore accounts: %w", err)
+		}
+	}
+
+	if storage.statsStore != nil {
+		if err := exchangeService.RestoreStatistics(storage.statsStore); err != nil {
+			logger.WithError(err).Warn("Failed to restore market data statistics, starting empty")
+		}
+		a.persist(func() error { return exchangeService.SaveStatistics(storage.statsStore) }, "Failed to persist market data statistics",
+			func(ctx context.Context) {
+				exchangeService.PersistStatistics(ctx, storage.statsStore, cfg.StatsSnapshotInterval)
+			})
+	}
+
+	if storage.stateStore != nil {
+		logger.WithField("interval", cfg.StateSnapshotInterval).Info("Matching engine state snapshotted for crash recovery")
+		// Saved again at shutdown, so a clean restart replays nothing
+		a.persist(func() error { return exchangeService.SaveState(storage.stateStore) }, "Failed to persist matching engine state",
+			func(ctx context.Context) {
+				exchangeService.PersistState(ctx, storage.stateStore, cfg.StateSnapshotInterval)
+			})
+	}
+
+	if storage.metricsStore != nil {
+		exchangeService.SetMetricsStore(storage.metricsStore)
+		logger.WithFields(logrus.Fields{
+			"run_id":   exchangeService.RunID(),
+			"interval": cfg.MetricsSnapshotInterval,
+		}).Info("Metrics snapshots persisted to Postgres")
+		// The final snapshot covers the tail of the run after the last tick
+		a.persist(exchangeService.SaveMetricsSnapshot, "Failed to persist metrics snapshot",
+			func(ctx context.Context) { exchangeService.PersistMetricsSnapshots(ctx, cfg.MetricsSnapshotInterval) })
+	}
+
+	if storage.tradeStore != nil {
+		exchangeService.SetTradeStore(storage.tradeStore)
+		logger.WithField("interval", cfg.TradeTapeInterval).Info("Trade tape persisted to Postgres")
+		// Trades executed after the last tick, including during the drain
+		a.persist(exchangeService.FlushTradeTape, "Failed to persist trades",
+			func(ctx context.Context) { exchangeService.PersistTrades(ctx, cfg.TradeTapeInterval) })
+	}
+
+	if storage.orderStore != nil {
+		exchangeService.SetOrderStore(storage.orderStore)
+		logger.WithField("interval", cfg.OrderStoreInterval).Info("Orders persisted to Postgres")
+		// Order updates made after the last tick, including during the drain
+		a.persist(exchangeService.FlushOrders, "Failed to persist orders",
+			func(ctx context.Context) { exchangeService.PersistOrders(ctx, cfg.OrderStoreInterval) })
+	}
+
+	if storage.balanceStore != nil {
+		logger.WithField("interval", cfg.BalanceJournalInterval).Info("Balance journal persisted to Postgres")
+		// Postings made after the last tick, including during the drain
+		a.persist(exchangeService.FlushBalanceJournal, "Failed to persist balance journal",
+			func(ctx context.Context) { exchangeService.PersistBalanceJournal(ctx, cfg.BalanceJournalInterval) })
+	}
+
+	if cfg.GetDataAdapter() != nil && cfg.CandleArchiveInterval > 0 {
+		prefix := "exchange:" + cfg.ServiceInstanceName + ":candles"
+		exchangeService.SetCandleArchive(candlestore.NewCacheStore(cfg.GetDataAdapter().CacheRepository(), prefix, cfg.CandleArchiveTTL, cfg.RequestTimeout))
+		logger.WithField("interval", cfg.CandleArchiveInterval).Info("Closed candles archived via the data adapter")
+		a.persist(exchangeService.ArchiveCandles, "Failed to archive candles",
+			func(ctx context.Context) { exchangeService.PersistCandles(ctx, cfg.CandleArchiveInterval) })
+	}
+	return nil
+}
+
+// seedFixture seeds the fixture named by SEED_FIXTURE_PATH or SEED_FIXTURE_KEY, once
+// state is restored so a warm venue keeps its own accounts and orders
+func (a *Application) seedFixture(ctx context.Context) error {
+	cfg := a.cfg
+	if cfg.SeedFixturePath == "" && cfg.SeedFixtureKey == "" {
+		return nil
+	}
+	loaded, origin, data, err := loadFixture(ctx, cfg, a.configuration)
+	if err != nil {
+		return fmt.Errorf("failed to load fixture: %w", err)
+	}
+	a.runFiles[origin] = data
+	if _, err := a.exchangeService.SeedFixture(ctx, loaded); err != nil {
+		return fmt.Errorf("failed to seed fixture: %w", err)
+	}
+	return nil
+}
+
+// startBackground starts the work running beside the servers: settings reloads,
+// the scheduler, the price feed, settlement, the audit trail or outbox, chaos,
+// the synthetic market and the market maker
+func (a *Application) startBackground() error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+
+	// Rate limits, fee rates, chaos and log settings reload as the configuration
+	// service changes them
+	hotSettings := services.HotSettings{RateLimits: cfg.RateLimitConfigKey, FeeSchedule: cfg.FeeScheduleConfigKey, Chaos: cfg.ChaosConfigKey}
+	if hotSettings != (services.HotSettings{}) || cfg.LogConfigKey != "" {
+		// Its own client: watching refreshes the cache the others read through
+		settings := infrastructure.NewConfigurationClient(cfg, logger)
+		a.caches["settings"] = settings
+		a.onClose(exchangeService.ReloadSettings(settings, hotSettings))
+		if cfg.LogConfigKey != "" {
+			a.onClose(settings.Subscribe(cfg.LogConfigKey, a.logControl.Apply))
+		}
+		logger.WithFields(logrus.Fields{
+			"rate_limits":  hotSettings.RateLimits,
+			"fee_schedule": hotSettings.FeeSchedule,
+			"chaos":        hotSettings.Chaos,
+			"logging":      cfg.LogConfigKey,
+			"refresh":      cfg.ConfigWatchInterval,
+		}).Info("Settings reloaded from the configuration service as they change")
+		go settings.Watch(a.background, cfg.ConfigWatchInterval)
+	}
+
+	go exchangeService.RunScheduler(a.background, cfg.SchedulerInterval)
+
+	if cfg.PriceFeedChannel != "" {
+		source, closeSource, err := openPriceSource(cfg, logger)
+		if err != nil {
+			return fmt.Errorf("invalid PRICE_FEED_* settings: %w", err)
+		}
+		a.onClose(func() { closeSource() })
+		logger.WithFields(logrus.Fields{
+			"channel": cfg.PriceFeedChannel,
+			"max_age": cfg.PriceFeedMaxAge,
+		}).Info("Anchoring marks to the external price feed")
+		go exchangeService.FollowPriceFeed(a.background, source, cfg.PriceFeedRetryInterval)
+	}
+
+	if cfg.SettlementEnabled {
+		gateway, store, closeSettlement, err := openSettlement(cfg, logger)
+		if err != nil {
+			return fmt.Errorf("failed to open the settlement outbox: %w", err)
+		}
+		a.onClose(func() { closeSettlement() })
+		if err := exchangeService.EnableSettlement(gateway, store); err != nil {
+			return fmt.Errorf("failed to restore the settlement outbox: %w", err)
+		}
+		logger.WithFields(logrus.Fields{
+			"netting": cfg.SettlementNetting,
+			"retry":   cfg.SettlementRetryInterval,
+			"outbox":  cfg.SettlementOutboxPath,
+		}).Info("Spot fills settled with the custodian-simulator")
+		go exchangeService.RunSettlement(a.background)
+	}
+	if cfg.SettlementCutoff != "" {
+		schedule, err := settlement.ParseSchedule(cfg.SettlementCutoff, cfg.SettlementCycle)
+		if err != nil {
+			return fmt.Errorf("invalid SETTLEMENT_CUTOFF or SETTLEMENT_CYCLE: %w", err)
+		}
+		exchangeService.EnableSettlementCycles(schedule)
+		logger.WithField("schedule", schedule.String()).Info("Spot trades netted at the daily settlement cutoff")
+	}
+
+	if err := a.startAuditTrail(); err != nil {
+		return err
+	}
+
+	if cfg.ChaosEnabled {
+		exchangeService.EnableChaos(cfg.ChaosSeed)
+		logger.WithField("seed", cfg.ChaosSeed).Warn("Chaos injection API enabled")
+	}
+
+	if synthetic, ok := services.SyntheticMarketFromConfig(cfg); ok {
+		if err := exchangeService.EnableSyntheticMarket(synthetic); err != nil {
+			return fmt.Errorf("invalid SYNTHETIC_* settings: %w", err)
+		}
+		logger.WithFields(logrus.Fields{
+			"symbols":  synthetic.Symbols,
+			"model":    synthetic.Dynamics.Model,
+			"seed":     synthetic.Seed,
+			"interval": cfg.SyntheticInterval,
+		}).Info("Synthetic market enabled")
+		if cfg.SyntheticInterval > 0 {
+			go exchangeService.RunSyntheticMarket(a.background, cfg.SyntheticInterval)
+		}
+	}
+
+	makerQuotes, err := services.ParseMarketMakerQuotes(cfg.MarketMakerQuotes)
+	if err != nil {
+		return fmt.Errorf("invalid MARKET_MAKER_QUOTES: %w", err)
+	}
+	if len(makerQuotes) > 0 {
+		maker := services.MarketMakerConfig{Account: cfg.MarketMakerAccount, Quotes: makerQuotes, RequoteBps: cfg.MarketMakerRequoteBps}
+		if err := exchangeService.EnableMarketMaker(maker); err != nil {
+			return fmt.Errorf("invalid MARKET_MAKER_* settings: %w", err)
+		}
+		logger.WithFields(logrus.Fields{
+			"account":  maker.Account,
+			"symbols":  len(makerQuotes),
+			"interval": cfg.MarketMakerInterval,
+		}).Info("Background market maker quoting")
+		exchangeService.RefreshMarketMaker(a.background)
+		go exchangeService.RunMarketMaker(a.background, cfg.MarketMakerInterval)
+	}
+	return nil
+}
+
+// startAuditTrail reports order, trade and funding changes to the
+// audit-correlator directly, or through the Postgres outbox when one is open
+func (a *Application) startAuditTrail() error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+
+	var auditSink *infrastructure.AuditCorrelatorSink
+	if cfg.AuditEnabled {
+		clients := infrastructure.NewInterServiceClientManager(cfg, logger, a.discovery, a.configuration)
+		a.onClose(func() { clients.Close() })
+		auditSink = infrastructure.NewAuditCorrelatorSink(clients, cfg.AuditStreamWindow)
+		a.onClose(func() { auditSink.Close() })
+	}
+
+	var run func(ctx context.Context)
+	switch {
+	case auditSink != nil && a.storage.outboxStore == nil:
+		exchangeService.EnableAuditTrail(auditSink)
+		logger.WithFields(logrus.Fields{
+			"buffer": cfg.AuditBufferSize,
+			"batch":  cfg.AuditBatchSize,
+		}).Info("Order, trade and funding changes reported to the audit-correlator")
+		run = exchangeService.RunAuditTrail
+	case a.storage.outboxStore != nil:
+		publishers, closePublishers, err := openOutboxPublishers(cfg, auditSink)
+		if err != nil {
+			return fmt.Errorf("invalid OUTBOX_* settings: %w", err)
+		}
+		a.onClose(func() { closePublishers() })
+		exchangeService.EnableOutbox(a.storage.outboxStore, publishers)
+		logger.WithFields(logrus.Fields{
+			"destinations":  len(publishers),
+			"redis_channel": cfg.OutboxRedisChannel,
+			"interval":      cfg.OutboxInterval,
+		}).Info("Order, trade and funding changes written to the Postgres outbox before dispatch")
+		run = exchangeService.RunOutbox
+	default:
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		run(ctx)
+		close(done)
+	}()
+	a.stopAudit = func() {
+		cancel()
+		<-done
+	}
+	return nil
+}
+
+// startRun loads the scenario script and order flow recording, records the run
+// manifest and starts both
+func (a *Application) startRun(ctx context.Context) error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+
+	runFiles := a.runFiles
+	if cfg.ScenarioScriptPath != "" || cfg.ScenarioScriptKey != "" {
+		loaded, origin, data, err := loadScript(ctx, cfg, a.configuration)
+		if err != nil {
+			return fmt.Errorf("failed to load scenario script: %w", err)
+		}
+		runFiles[origin] = data
+		a.script = &loaded
+	}
+	if cfg.ReplayPath != "" {
+		data, err := os.ReadFile(cfg.ReplayPath)
+		if err != nil {
+			return fmt.Errorf("failed to read order flow recording: %w", err)
+		}
+		loaded, err := orderflow.Parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse order flow recording: %w", err)
+		}
+		runFiles[cfg.ReplayPath] = data
+		a.recording = &loaded
+	}
+	if cfg.ScenarioProgressURL != "" {
+		exchangeService.SetScenarioReporter(infrastructure.NewOrchestratorReporter(cfg.ScenarioProgressURL, cfg.RequestTimeout))
+	}
+
+	manifest := exchangeService.StartRun(a.revision, runFiles)
+	if cfg.RunManifestPath != "" {
+		if err := writeManifest(cfg.RunManifestPath, manifest); err != nil {
+			return fmt.Errorf("failed to write run manifest: %w", err)
+		}
+	}
+	logger.WithFields(logrus.Fields{
+		"run_id":  manifest.RunID,
+		"git_sha": manifest.GitSHA,
+		"seed":    manifest.Seed,
+	}).Info("Run manifest recorded")
+
+	if a.script != nil {
+		if _, err := exchangeService.StartScript(ctx, *a.script); err != nil {
+			return fmt.Errorf("failed to start scenario script: %w", err)
+		}
+	}
+	if a.recording != nil {
+		if _, err := exchangeService.StartReplay(ctx, *a.recording, cfg.ReplayPath, cfg.ReplaySpeed); err != nil {
+			return fmt.Errorf("failed to start order flow replay: %w", err)
+		}
+	}
+	return nil
+}
+
+// startServers starts the gRPC, FIX and HTTP servers and then reports the
+// service ready. Each listens before Start returns, so a port in use fails startup.
+func (a *Application) startServers() error {
+	cfg, logger := a.cfg, a.logger
+
+	httpServer, err := a.setupHTTPServer()
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("port", cfg.GRPCPort).Info("Starting gRPC server")
+	if err := a.grpcServer.Start(a.background); err != nil {
+		return fmt.Errorf("failed to start gRPC server: %w", err)
+	}
+
+	if cfg.FIXPort > 0 {
+		counterparties, err := fixpresentation.ParseCounterparties(cfg.FIXSessions)
+		if err != nil {
+			return fmt.Errorf("invalid FIX_SESSIONS: %w", err)
+		}
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.FIXPort))
+		if err != nil {
+			return fmt.Errorf("failed to start FIX gateway: %w", err)
+		}
+		a.fixAcceptor = fixpresentation.NewAcceptor(cfg.FIXCompID, counterparties, a.exchangeService, logger)
+		logger.WithFields(logrus.Fields{"port": cfg.FIXPort, "counterparties": len(counterparties)}).Info("Starting FIX gateway")
+		go func() {
+			if err := a.fixAcceptor.Serve(listener); err != nil {
+				logger.WithError(err).Error("FIX gateway stopped")
+			}
+		}()
+	}
+
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start HTTP server: %w", err)
+	}
+	a.httpServer = httpServer
+	logger.WithField("port", cfg.HTTPPort).Info("Starting HTTP server")
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.WithError(err).Error("HTTP server stopped")
+		}
+	}()
+
+	a.coordinator.Serve()
+	return nil
+}
+
+// Shutdown drains the application: asynchronous orders are acknowledged, the
+// servers stop, the run bundle is written, persistence is flushed, the audit
+// trail catches up and the scenario verdict is reported before everything
+// opened is closed
+func (a *Application) Shutdown(ctx context.Context) error {
+	cfg, logger, exchangeService := a.cfg, a.logger, a.exchangeService
+	logger.Info("Shutting down servers...")
+
+	// Ack every asynchronous order already received before the streams end. WebSocket
+	// streams are hijacked connections Shutdown does not track, and execution streams
+	// would hold GracefulStop open; end them first.
+	exchangeService.WaitAsyncOrders()
+	if a.fixAcceptor != nil {
+		// Log FIX sessions out before their feeds close under them
+		if err := a.fixAcceptor.Shutdown(ctx); err != nil {
+			logger.WithError(err).Error("FIX gateway forced to shutdown")
+		}
+	}
+	exchangeService.CloseFeeds()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		logger.WithError(err).Error("HTTP server forced to shutdown")
+	}
+
+	// Session streams stay open until told to end, which the graceful stop waits for
+	a.grpcServer.Stop(ctx)
+
+	if cfg.RunBundlePath != "" {
+		// Bundled before the final metrics snapshot starts a new latency interval
+		bundle, err := exchangeService.RunBundle(context.Background())
+		if err == nil {
+			err = writeRunBundle(cfg.RunBundlePath, bundle)
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to write run bundle")
+		} else {
+			logger.WithFields(logrus.Fields{"path": cfg.RunBundlePath, "events": len(bundle.Journal)}).Info("Run bundle written")
+		}
+	}
+
+	for _, flush := range a.flushes {
+		flush.stop()
+		if err := flush.save(); err != nil {
+			logger.WithError(err).Error(flush.failure)
+		}
+	}
+	if a.stopAudit != nil {
+		// Events of changes made during the drain, then whatever is still buffered
+		a.stopAudit()
+	}
+	if cfg.ScenarioPath != "" {
+		a.reportVerdict()
+	}
+
+	a.close()
+	logger.Info("Servers shutdown complete")
+	return nil
+}
+
+// reportVerdict logs the scenario verdict, writing it to SCENARIO_VERDICT_PATH
+func (a *Application) reportVerdict() {
+	verdict, _ := a.exchangeService.ScenarioVerdict(context.Background(), true)
+	entry := a.logger.WithFields(logrus.Fields{
+		"scenario": verdict.Scenario,
+		"passed":   verdict.Passed,
+		"events":   verdict.Events,
+	})
+	for _, result := range verdict.Results {
+		if !result.Passed {
+			entry.WithFields(logrus.Fields{"assertion": result.Name, "detail": result.Detail}).Warn("Scenario assertion did not pass")
+		}
+	}
+	entry.Info("Scenario verdict")
+	if a.cfg.ScenarioVerdictPath != "" {
+		if err := writeVerdict(a.cfg.ScenarioVerdictPath, verdict); err != nil {
+			a.logger.WithError(err).Error("Failed to write scenario verdict")
+		}
+	}
+}
+
+// persist runs a persistence loop until shutdown, which stops it and then flushes
+// with save what it has not
+func (a *Application) persist(save func() error, failure string, loop func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		loop(ctx)
+	}()
+	a.flushes = append(a.flushes, flush{cancel: cancel, done: done, save: save, failure: failure})
+}
+
+// onClose registers fn to run at the end of shutdown, after those registered later
+func (a *Application) onClose(fn func()) {
+	a.closers = append(a.closers, fn)
+}
+
+// close stops the background loops and runs the closers last-first
+func (a *Application) close() {
+	a.stopBackground()
+	for _, flush := range a.flushes {
+		flush.stop()
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+	a.closers = nil
+}
@@ -0,0 +1,196 @@
+//go:build unit
+
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func TestApplication(t *testing.T) {
+	newApplication := func(t *testing.T) (*Application, *config.Config) {
+		cfg := config.Load()
+		cfg.HTTPPort, cfg.GRPCPort, cfg.FIXPort = 0, 0, 0
+		cfg.ReadinessTimeout = 100 * time.Millisecond
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		return New(cfg, logger, "test"), cfg
+	}
+
+	t.Run("starts_serves_and_drains", func(t *testing.T) {
+		// Given: An application in stub mode
+		application, _ := newApplication(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// When: It starts
+		if err := application.Start(ctx); err != nil {
+			t.Fatalf("Expected the application to start, got %v", err)
+		}
+
+		// Then: Startup is finished, the gRPC server runs and HTTP readiness passes
+		if !application.coordinator.Ready() || !application.grpcServer.IsRunning() {
+			t.Fatal("Expected the servers to be serving")
+		}
+		w := httptest.NewRecorder()
+		application.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ready", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected ready, got %d %s", w.Code, w.Body.String())
+		}
+
+		// And: It drains, closing what it opened
+		if err := application.Shutdown(ctx); err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+		if application.grpcServer.IsRunning() || application.closers != nil {
+			t.Error("Expected the gRPC server stopped and every closer run")
+		}
+	})
+
+	t.Run("restarts_with_the_saved_engine_state", func(t *testing.T) {
+		// Given: An application saving its state and journal to disk, with an order resting
+		dir := t.TempDir()
+		application, cfg := newApplication(t)
+		cfg.StateSnapshotInterval, cfg.StateSnapshotPath = time.Hour, filepath.Join(dir, "state.json")
+		cfg.EventLogPath = filepath.Join(dir, "events.jsonl")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := application.Start(ctx); err != nil {
+			t.Fatalf("Expected the application to start, got %v", err)
+		}
+		report, err := application.exchangeService.PlaceOrder(ctx, services.OrderRequest{
+			AccountID: "acct-1", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000,
+		})
+		if err != nil {
+			t.Fatalf("Expected the order placed, got %v", err)
+		}
+
+		// When: It shuts down and starts again on the same files
+		if err := application.Shutdown(ctx); err != nil {
+			t.Fatalf("Expected a clean shutdown, got %v", err)
+		}
+		restarted, restartedCfg := newApplication(t)
+		restartedCfg.StateSnapshotInterval, restartedCfg.StateSnapshotPath, restartedCfg.EventLogPath = cfg.StateSnapshotInterval, cfg.StateSnapshotPath, cfg.EventLogPath
+		if err := restarted.Start(ctx); err != nil {
+			t.Fatalf("Expected the application to restart, got %v", err)
+		}
+		defer restarted.Shutdown(ctx)
+
+		// Then: The order is resting again
+		if order, err := restarted.exchangeService.GetOrder(ctx, report.Order.ID); err != nil || order.Status != models.OrderStatusNew {
+			t.Errorf("Expected %s restored, got %+v, %v", report.Order.ID, order, err)
+		}
+	})
+
+	t.Run("failed_start_closes_what_it_opened", func(t *testing.T) {
+		// Given: An application with invalid account profiles
+		application, cfg := newApplication(t)
+		cfg.AccountProfiles = "not-a-profile"
+
+		// When: It starts
+		err := application.Start(context.Background())
+
+		// Then: Startup fails naming the setting, and nothing is left open
+		if err == nil {
+			t.Fatal("Expected startup to fail")
+		}
+		if application.closers != nil || application.background.Err() == nil {
+			t.Error("Expected the closers run and the background stopped")
+		}
+	})
+
+	t.Run("checks_signatures_before_charging_rate_limits", func(t *testing.T) {
+		// Given: An application limiting each account to two requests, and an account
+		// holding an issued key
+		cfg := config.Load()
+		cfg.HTTPPort, cfg.GRPCPort, cfg.FIXPort = 0, 0, 0
+		cfg.ReadinessTimeout = 100 * time.Millisecond
+		cfg.RateLimitWeight = 2
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		application := New(cfg, logger, "test")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := application.Start(ctx); err != nil {
+			t.Fatalf("Expected the application to start, got %v", err)
+		}
+		defer application.Shutdown(ctx)
+		account, _ := application.exchangeService.CreateAccount(ctx, services.NewAccountRequest{})
+		issued, err := application.exchangeService.CreateAPIKey(ctx, account.ID, services.NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionRead}})
+		if err != nil {
+			t.Fatalf("Failed to issue key: %v", err)
+		}
+		get := func(signature string) int {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			if signature == "" {
+				signature = accounts.Key{Secret: issued.Secret}.Sign(accounts.SigningPayload(timestamp, http.MethodGet, "/api/v1/instruments", nil))
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/instruments", nil)
+			req.Header.Set(observability.APIKeyHeader, issued.ID)
+			req.Header.Set(handlers.TimestampHeader, timestamp)
+			req.Header.Set(handlers.SignatureHeader, signature)
+			w := httptest.NewRecorder()
+			application.httpServer.Handler.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		// When: Another caller names the key with forged signatures, then the owner calls
+		forged := []int{get(strings.Repeat("ab", 32)), get(strings.Repeat("ab", 32)), get(strings.Repeat("ab", 32))}
+		owner := get("")
+
+		// Then: The forgeries fail authentication without being charged, so the owner
+		// still has its limit
+		for i, code := range forged {
+			if code != http.StatusUnauthorized {
+				t.Errorf("Expected forged request %d refused with 401, got %d", i+1, code)
+			}
+		}
+		if owner != http.StatusOK {
+			t.Errorf("Expected the owner's request within its limit, got %d", owner)
+		}
+	})
+}
+
+func TestApplication_Persist(t *testing.T) {
+	t.Run("stopping_waits_for_the_loop_before_the_final_save", func(t *testing.T) {
+		// Given: A persistence loop whose last tick is still running when it is stopped
+		a := New(config.Load(), logrus.New(), "test")
+		var ticked, savedAfterTick atomic.Bool
+		a.persist(func() error {
+			savedAfterTick.Store(ticked.Load())
+			return nil
+		}, "Failed to persist", func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			ticked.Store(true)
+		})
+
+		// When: It is stopped and flushed as shutdown does
+		for _, flush := range a.flushes {
+			flush.stop()
+			flush.save()
+		}
+
+		// Then: The final save ran after the loop returned
+		if !savedAfterTick.Load() {
+			t.Error("Expected the final save to wait for the loop's last tick")
+		}
+	})
+}
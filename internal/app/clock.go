@@ -0,0 +1,29 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/clock"
+)
+
+// openClock builds the venue clock selected by CLOCK_MODE
+func openClock(cfg *config.Config) (ports.Clock, error) {
+	switch clock.Mode(cfg.ClockMode) {
+	case clock.ModeWall, "":
+		return clock.NewWallClock(), nil
+	case clock.ModeSimulated:
+		start := time.Now()
+		if cfg.ClockStart != "" {
+			parsed, err := time.Parse(time.RFC3339, cfg.ClockStart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CLOCK_START: %w", err)
+			}
+			start = parsed
+		}
+		return clock.NewSimulatedClock(start, cfg.ClockSpeed)
+	}
+	return nil, fmt.Errorf("unknown clock mode %q", cfg.ClockMode)
+}
@@ -0,0 +1,253 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// setupHTTPServer builds the REST, WebSocket, admin and metrics routes in front of
+// the exchange service
+func (a *Application) setupHTTPServer() (*http.Server, error) {
+	cfg, exchangeService, logger := a.cfg, a.exchangeService, a.logger
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Continue callers' traces before anything else handles the request
+	if tracingPort := cfg.GetTracingPort(); tracingPort != nil {
+		router.Use(observability.TracingMiddleware(tracingPort))
+	}
+
+	// Add RED metrics middleware for all routes
+	metricsPort := cfg.GetMetricsPort()
+	if metricsPort != nil {
+		router.Use(observability.REDMetricsMiddleware(metricsPort))
+		router.Use(observability.HealthMetricsMiddleware(metricsPort, "exchange-simulator"))
+	}
+	router.Use(observability.RequestLoggingMiddleware(logger))
+	router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()))
+	router.Use(handlers.OperatorIdentity(cfg.AdminToken))
+	// Signatures are checked before limits are charged, so a caller naming another
+	// account's key cannot spend that account's limit
+	apiKeyHandler := handlers.NewAPIKeyHandler(exchangeService, logger)
+	router.Use(apiKeyHandler.Authenticate)
+	rateLimitHandler := handlers.NewRateLimitHandler(exchangeService, logger)
+	router.Use(rateLimitHandler.Limit)
+	degradationHandler := handlers.NewDegradationHandler(exchangeService, logger)
+	router.Use(degradationHandler.Guard)
+
+	healthHandler := handlers.NewHealthHandlerWithConfig(cfg, logger).WithExchange(exchangeService).WithStartup(a.coordinator).WithDependencies(cfg.ReadinessTimeout, a.readinessChecks()...).WithSchema(a.storage.schema)
+	metricsHandler := handlers.NewMetricsHandler(metricsPort)
+	previewHandler := handlers.NewPreviewHandler(services.NewPreviewService(exchangeService.Instruments(), logger), logger)
+	positionHandler := handlers.NewPositionHandler(exchangeService.Positions(), logger)
+	auctionHandler := handlers.NewAuctionHandler(exchangeService, logger)
+	haltHandler := handlers.NewHaltHandler(exchangeService, logger)
+	marketDataHandler := handlers.NewMarketDataHandler(exchangeService, logger)
+	storageHandler := handlers.NewStorageHandler(a.storage.migration, logger)
+	accountHandler := handlers.NewAccountHandler(exchangeService, logger)
+	settlementHandler := handlers.NewSettlementHandler(exchangeService, logger)
+	auditHandler := handlers.NewAuditHandler(exchangeService, logger)
+	outboxHandler := handlers.NewOutboxHandler(exchangeService, logger)
+	chaosHandler := handlers.NewChaosHandler(exchangeService, logger)
+	surveillanceHandler := handlers.NewSurveillanceHandler(exchangeService, logger)
+	runMetricsHandler := handlers.NewRunMetricsHandler(exchangeService, logger)
+	incidentHandler := handlers.NewIncidentHandler(exchangeService, logger)
+	scenarioHandler := handlers.NewScenarioHandler(exchangeService, logger)
+	replayHandler := handlers.NewReplayHandler(exchangeService, logger)
+	syntheticHandler := handlers.NewSyntheticHandler(exchangeService, logger)
+	marketMakerHandler := handlers.NewMarketMakerHandler(exchangeService, logger)
+	referencePriceHandler := handlers.NewReferencePriceHandler(exchangeService, logger)
+	instrumentHandler := handlers.NewInstrumentHandler(exchangeService, logger)
+	venueHandler := handlers.NewVenueHandler(exchangeService, logger)
+	sessionHandler := handlers.NewSessionHandler(exchangeService, logger)
+	streamHandler := handlers.NewStreamHandler(exchangeService, logger)
+	subscriptionHandler := handlers.NewSubscriptionHandler(exchangeService, logger)
+	apiVersionHandler := handlers.NewAPIVersionHandler(exchangeService, logger)
+	clockHandler := handlers.NewClockHandler(cfg.GetClock(), exchangeService, logger)
+	scheduleHandler := handlers.NewScheduleHandler(exchangeService, logger)
+	orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+	logHandler := handlers.NewLogHandler(a.logControl, logger)
+	debugHandler := handlers.NewDebugHandler(exchangeService, logger)
+	for name, cache := range a.caches {
+		debugHandler.WithCache(name, cache)
+	}
+
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/health", healthHandler.Health)
+		v1.GET("/ready", healthHandler.Ready)
+	}
+
+	// The public API is served as v1 and v2 alike, so v1 can be deprecated and
+	// retired on the venue clock while clients migrate
+	for _, version := range []string{"v1", "v2"} {
+		api := router.Group("/api/"+version, apiVersionHandler.Enforce(version))
+		{
+			api.POST("/orders", orderHandler.Place)
+			api.GET("/orders", orderHandler.List)
+			api.GET("/orders/history", orderHandler.OrderHistory)
+			api.GET("/orders/:order_id", orderHandler.Get)
+			api.PATCH("/orders/:order_id", orderHandler.Amend)
+			api.DELETE("/orders/:order_id", orderHandler.Cancel)
+			api.GET("/trades", orderHandler.Trades)
+			api.GET("/trades/history", orderHandler.History)
+			api.GET("/book/:symbol", orderHandler.Book)
+			api.GET("/balances", orderHandler.Balances)
+			api.GET("/positions", positionHandler.List)
+			api.POST("/preview", previewHandler.Preview)
+			api.GET("/auctions/:symbol", auctionHandler.Indicative)
+			api.GET("/halts", haltHandler.List)
+			api.GET("/tickers", marketDataHandler.Tickers)
+			api.GET("/tickers/:symbol", marketDataHandler.Ticker)
+			api.GET("/klines", marketDataHandler.Klines)
+			api.GET("/klines/:symbol", marketDataHandler.Klines)
+			api.GET("/stats", marketDataHandler.ExchangeStatistics)
+			api.GET("/instruments", instrumentHandler.List)
+			api.GET("/instruments/changes", instrumentHandler.Changes)
+			api.GET("/instruments/events", instrumentHandler.Events)
+			api.GET("/venue", venueHandler.Get)
+			api.POST("/accounts", accountHandler.Create)
+			api.GET("/accounts", accountHandler.List)
+			api.GET("/accounts/:account_id", accountHandler.Get)
+			api.PATCH("/accounts/:account_id", accountHandler.Update)
+			api.DELETE("/accounts/:account_id", accountHandler.Close)
+			api.GET("/accounts/:account_id/ledger", accountHandler.Ledger)
+			api.GET("/accounts/:account_id/balances", accountHandler.Balances)
+			api.GET("/accounts/:account_id/journal", accountHandler.Journal)
+			api.GET("/accounts/:account_id/margin", accountHandler.Margin)
+			api.GET("/accounts/:account_id/funding", accountHandler.Funding)
+			api.GET("/accounts/:account_id/snapshot", accountHandler.Snapshot)
+			api.GET("/liquidations", accountHandler.Liquidations)
+			api.GET("/insurance", accountHandler.Insurance)
+			api.GET("/accounts/:account_id/valuation", accountHandler.Valuation)
+			api.GET("/accounts/:account_id/settlements", settlementHandler.Account)
+			api.GET("/accounts/:account_id/settlement-cycles", settlementHandler.AccountCycles)
+			api.POST("/accounts/:account_id/api-keys", apiKeyHandler.Create)
+			api.GET("/accounts/:account_id/api-keys", apiKeyHandler.Keys)
+			api.DELETE("/accounts/:account_id/api-keys/:key_id", apiKeyHandler.Revoke)
+			api.GET("/clock", clockHandler.Get)
+			api.GET("/funding", scheduleHandler.Funding)
+			api.GET("/subscriptions", subscriptionHandler.Usage)
+		}
+	}
+
+	// A venue with the Binance flavor speaks it whatever BINANCE_COMPAT_ENABLED says
+	if cfg.BinanceCompatEnabled || exchangeService.Personality(context.Background()).APIFlavor == services.APIFlavorBinance {
+		credentials, err := handlers.ParseBinanceCredentials(cfg.BinanceAPIKeys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BINANCE_API_KEYS: %w", err)
+		}
+		binanceHandler := handlers.NewBinanceHandler(exchangeService, credentials, logger)
+		v3 := router.Group("/api/v3")
+		{
+			v3.GET("/ping", binanceHandler.Ping)
+			v3.GET("/time", binanceHandler.Time)
+			v3.GET("/exchangeInfo", binanceHandler.ExchangeInfo)
+			v3.GET("/depth", binanceHandler.Depth)
+			v3.POST("/order", binanceHandler.PlaceOrder)
+			v3.POST("/order/test", binanceHandler.TestOrder)
+			v3.GET("/order", binanceHandler.QueryOrder)
+			v3.DELETE("/order", binanceHandler.CancelOrder)
+			v3.GET("/openOrders", binanceHandler.OpenOrders)
+			v3.GET("/account", binanceHandler.Account)
+		}
+		logger.WithField("signed", len(credentials) > 0).Info("Binance-compatible API enabled")
+	}
+
+	// Operator routes need ADMIN_TOKEN once one is set
+	if cfg.AdminToken == "" {
+		logger.Warn("ADMIN_TOKEN not set; /api/v1/admin is open to any caller")
+	}
+	admin := v1.Group("/admin", handlers.AdminAuthorization(cfg.AdminToken))
+	{
+		admin.POST("/auctions/:symbol", auctionHandler.Start)
+		admin.POST("/auctions/:symbol/uncross", auctionHandler.Uncross)
+		admin.POST("/halts/:symbol", haltHandler.Halt)
+		admin.POST("/halts/:symbol/resume", haltHandler.Resume)
+		admin.GET("/storage/migration", storageHandler.Verify)
+		admin.POST("/accounts/bulk", accountHandler.Provision)
+		admin.GET("/accounts/purges", accountHandler.Tombstones)
+		admin.POST("/accounts/:account_id/purge", accountHandler.Purge)
+		admin.GET("/accounts/profiles", accountHandler.Profiles)
+		admin.GET("/accounts/activity", accountHandler.Activity)
+		admin.GET("/balances/trial", accountHandler.TrialBalance)
+		admin.PUT("/accounts/:account_id/profile", accountHandler.SetProfile)
+		admin.DELETE("/accounts/:account_id/profile", accountHandler.RemoveProfile)
+		admin.GET("/surveillance/flags", surveillanceHandler.Flags)
+		admin.GET("/surveillance/accounts", surveillanceHandler.Accounts)
+		admin.GET("/api-keys", apiKeyHandler.List)
+		admin.GET("/api-keys/:api_key", apiKeyHandler.Get)
+		admin.GET("/access-denials", apiKeyHandler.Denials)
+		admin.GET("/subscriptions", subscriptionHandler.List)
+		admin.PUT("/subscriptions/:api_key", subscriptionHandler.SetLimits)
+		admin.GET("/settlements", settlementHandler.List)
+		admin.POST("/settlements/run", settlementHandler.Run)
+		admin.GET("/settlement-cycles", settlementHandler.Cycles)
+		admin.GET("/settlement-cycles/:cycle_id", settlementHandler.Cycle)
+		admin.GET("/audit-trail", auditHandler.Stats)
+		admin.GET("/outbox", outboxHandler.Stats)
+		admin.POST("/outbox/dispatch", outboxHandler.Dispatch)
+		admin.GET("/chaos/faults", chaosHandler.List)
+		admin.POST("/chaos/faults", chaosHandler.Inject)
+		admin.DELETE("/chaos/faults/:fault_id", chaosHandler.Clear)
+		admin.GET("/degradation", degradationHandler.Get)
+		admin.PUT("/degradation", degradationHandler.Set)
+		admin.GET("/rate-limits", rateLimitHandler.Get)
+		admin.PUT("/rate-limits", rateLimitHandler.Set)
+		admin.GET("/logging", logHandler.Get)
+		admin.PUT("/logging", logHandler.Set)
+		admin.GET("/api-versions", apiVersionHandler.List)
+		admin.PUT("/api-versions/:version", apiVersionHandler.Update)
+		admin.GET("/metrics/current", runMetricsHandler.Current)
+		admin.GET("/metrics/runs", runMetricsHandler.Runs)
+		admin.GET("/metrics/runs/:run_id/snapshots", runMetricsHandler.Snapshots)
+		admin.GET("/run/manifest", runMetricsHandler.Manifest)
+		admin.GET("/run/bundle", runMetricsHandler.Bundle)
+		admin.GET("/incidents", incidentHandler.List)
+		admin.GET("/storage/latency", incidentHandler.StorageLatency)
+		admin.GET("/storage/write-behind", incidentHandler.WriteBehind)
+		admin.GET("/scenario/verdict", scenarioHandler.Verdict)
+		admin.GET("/scenario/script", scenarioHandler.Script)
+		admin.POST("/scenario/script", scenarioHandler.StartScript)
+		admin.DELETE("/scenario/script", scenarioHandler.StopScript)
+		admin.GET("/replay", replayHandler.Progress)
+		admin.POST("/replay", replayHandler.Start)
+		admin.DELETE("/replay", replayHandler.Stop)
+		admin.GET("/synthetic", syntheticHandler.List)
+		admin.POST("/synthetic/step", syntheticHandler.Step)
+		admin.GET("/market-maker", marketMakerHandler.List)
+		admin.GET("/reference-prices", referencePriceHandler.List)
+		admin.PUT("/reference-prices/:symbol", referencePriceHandler.Update)
+		admin.POST("/instruments/:symbol/changes", instrumentHandler.Schedule)
+		admin.DELETE("/instruments/changes/:change_id", instrumentHandler.Cancel)
+		admin.GET("/sessions", sessionHandler.List)
+		admin.PUT("/clock", clockHandler.Update)
+		admin.POST("/clock/advance", clockHandler.Advance)
+		admin.GET("/schedule/transitions", scheduleHandler.Transitions)
+		admin.POST("/schedule/transitions", scheduleHandler.ScheduleTransition)
+		admin.GET("/debug/books/:symbol", debugHandler.Book)
+		admin.GET("/debug/engine", debugHandler.Engine)
+		admin.GET("/debug/state", debugHandler.State)
+		admin.GET("/debug/caches", debugHandler.Caches)
+		admin.GET("/debug/runtime", debugHandler.Runtime)
+		admin.GET("/debug/pprof/*profile", debugHandler.Profile)
+	}
+
+	// Push feed of market data and order updates
+	router.GET("/ws/v1/stream", streamHandler.Stream)
+
+	// Metrics endpoint (outside v1 group, at root level)
+	router.GET("/metrics", metricsHandler.Metrics)
+
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.HTTPPort),
+		Handler: router,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// openMetrics builds the metrics adapter selected by METRICS_BACKEND, and a
+// function flushing it at shutdown
+func openMetrics(ctx context.Context, cfg *config.Config) (ports.MetricsPort, func(context.Context) error, error) {
+	service := ports.MetricsLabels{
+		Service:  cfg.ServiceName,
+		Instance: cfg.ServiceInstanceName,
+		Version:  cfg.ServiceVersion,
+	}
+
+	switch cfg.MetricsBackend {
+	case "prometheus", "":
+		metricsPort := observability.NewPrometheusMetricsAdapter(service.ConstantLabels())
+		return metricsPort, func(context.Context) error { return nil }, nil
+	case "otel":
+		if cfg.MetricsEndpoint == "" {
+			return nil, nil, fmt.Errorf("METRICS_BACKEND=otel needs OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT")
+		}
+		if cfg.MetricsExportInterval <= 0 {
+			return nil, nil, fmt.Errorf("invalid OTEL_METRIC_EXPORT_INTERVAL: must be positive")
+		}
+		reader, err := observability.NewOTLPMetricReader(ctx, cfg.MetricsEndpoint, cfg.MetricsExportInterval)
+		if err != nil {
+			return nil, nil, err
+		}
+		metricsPort := observability.NewOTelMetricsAdapter(reader, service)
+		return metricsPort, metricsPort.Shutdown, nil
+	}
+	return nil, nil, fmt.Errorf("unknown metrics backend %q", cfg.MetricsBackend)
+}
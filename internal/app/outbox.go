@@ -0,0 +1,31 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure"
+)
+
+// openOutboxPublishers returns a publisher for the audit-correlator when auditSink is
+// set and for OUTBOX_REDIS_CHANNEL on the ecosystem's Redis when configured; the
+// returned close releases the Redis connection
+func openOutboxPublishers(cfg *config.Config, auditSink *infrastructure.AuditCorrelatorSink) (map[outbox.Destination]outbox.Publisher, func() error, error) {
+	publishers := make(map[outbox.Destination]outbox.Publisher)
+	if auditSink != nil {
+		publishers[outbox.DestinationAudit] = auditSink
+	}
+	if cfg.OutboxRedisChannel == "" {
+		return publishers, func() error { return nil }, nil
+	}
+	opt, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(opt)
+	publishers[outbox.DestinationRedis] = infrastructure.NewRedisOutboxPublisher(client, cfg.OutboxRedisChannel)
+	return publishers, client.Close, nil
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// loadPersonality fetches the venue personality from the configuration service under
+// PERSONALITY_CONFIG_KEY followed by SERVICE_INSTANCE_NAME, e.g.
+// "personality.exchange-OKX". It also returns the key and raw value, for the run
+// manifest.
+func loadPersonality(ctx context.Context, cfg *config.Config, source ports.SettingSource) (services.Personality, string, []byte, error) {
+	key := cfg.PersonalityConfigKey + cfg.ServiceInstanceName
+	data, err := source.Setting(ctx, key)
+	if err != nil {
+		return services.Personality{}, "", nil, fmt.Errorf("failed to fetch venue personality %s: %w", key, err)
+	}
+	personality, err := services.ParsePersonality(data)
+	return personality, "config:" + key, data, err
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/pricesource"
+)
+
+// openPriceSource subscribes to PRICE_FEED_CHANNEL on the ecosystem's Redis; the
+// returned close releases the connection
+func openPriceSource(cfg *config.Config, logger *logrus.Logger) (*pricesource.RedisSource, func() error, error) {
+	symbols, err := pricesource.ParseSymbolMap(cfg.PriceFeedSymbolMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	opt, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(opt)
+	return pricesource.NewRedisSource(client, cfg.PriceFeedChannel, symbols, logger), client.Close, nil
+}
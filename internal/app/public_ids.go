@@ -0,0 +1,32 @@
+package app
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/publicid"
+)
+
+// openIDObfuscator builds the public ID mapping selected by PUBLIC_ID_MODE
+func openIDObfuscator(cfg *config.Config, logger *logrus.Logger) (ports.IDObfuscator, error) {
+	switch publicid.Mode(cfg.PublicIDMode) {
+	case publicid.ModeInternal, "":
+		return publicid.NewInternal(), nil
+	case publicid.ModeHMAC:
+		key := []byte(cfg.PublicIDKey)
+		if len(key) == 0 {
+			// Public IDs then change on every restart
+			key = make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return nil, fmt.Errorf("failed to generate public ID key: %w", err)
+			}
+			logger.Warn("PUBLIC_ID_KEY not set, public IDs will not survive a restart")
+		}
+		return publicid.NewHMAC(key)
+	}
+	return nil, fmt.Errorf("unknown public ID mode %q", cfg.PublicIDMode)
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure"
+)
+
+// readinessChecks builds the dependency checks /api/v1/ready runs; those named in
+// READINESS_CRITICAL_DEPENDENCIES fail readiness, the rest are reported only
+func (a *Application) readinessChecks() []handlers.DependencyCheck {
+	cfg := a.cfg
+	critical := criticalDependencies(cfg)
+
+	instance := cfg.ServiceInstanceName
+	if instance == "" {
+		instance = cfg.ServiceName
+	}
+	checks := []handlers.DependencyCheck{
+		{Name: "matching_engine", Check: func(ctx context.Context) error {
+			return a.exchangeService.Engine().Ping(ctx)
+		}},
+		{Name: "data_adapter", Check: func(ctx context.Context) error {
+			return infrastructure.PingDataAdapter(ctx, cfg.GetDataAdapter(), "readiness:"+instance)
+		}},
+		{Name: "redis_discovery", Check: a.discovery.Ping},
+		{Name: "configuration_service", Check: a.configuration.Ping},
+	}
+	for i := range checks {
+		checks[i].Critical = critical[checks[i].Name]
+	}
+	return checks
+}
+
+// criticalDependencies returns the dependencies named in READINESS_CRITICAL_DEPENDENCIES
+func criticalDependencies(cfg *config.Config) map[string]bool {
+	critical := make(map[string]bool)
+	for _, name := range strings.Split(cfg.ReadinessCritical, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			critical[name] = true
+		}
+	}
+	return critical
+}
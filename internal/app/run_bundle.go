@@ -0,0 +1,37 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+)
+
+// writeManifest saves the run manifest named by RUN_MANIFEST_PATH
+func writeManifest(path string, manifest runbundle.Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write run manifest: %w", err)
+	}
+	return nil
+}
+
+// writeRunBundle archives the run to the file named by RUN_BUNDLE_PATH
+func writeRunBundle(path string, bundle runbundle.Bundle) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create run bundle: %w", err)
+	}
+	if err := runbundle.Write(file, bundle); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write run bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/fixture"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// loadScenario reads the scenario file named by SCENARIO_PATH, returning its raw
+// contents for the run manifest too
+func loadScenario(path string) (scenario.Scenario, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return scenario.Scenario{}, nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	loaded, err := scenario.Parse(data)
+	return loaded, data, err
+}
+
+// loadScript reads the scenario script at SCENARIO_SCRIPT_PATH, or else the one the
+// orchestrator left in the configuration service under SCENARIO_SCRIPT_KEY. It also
+// returns where the script came from and its raw contents, for the run manifest.
+func loadScript(ctx context.Context, cfg *config.Config, source ports.SettingSource) (scenario.Script, string, []byte, error) {
+	origin, data, err := readRunInput(ctx, cfg.ScenarioScriptPath, cfg.ScenarioScriptKey, source, "scenario script")
+	if err != nil {
+		return scenario.Script{}, "", nil, err
+	}
+	script, err := scenario.ParseScript(data)
+	return script, origin, data, err
+}
+
+// loadFixture reads the fixture at SEED_FIXTURE_PATH, or else the one under
+// SEED_FIXTURE_KEY, with where it came from and its raw contents
+func loadFixture(ctx context.Context, cfg *config.Config, source ports.SettingSource) (fixture.Fixture, string, []byte, error) {
+	origin, data, err := readRunInput(ctx, cfg.SeedFixturePath, cfg.SeedFixtureKey, source, "fixture")
+	if err != nil {
+		return fixture.Fixture{}, "", nil, err
+	}
+	loaded, err := fixture.Parse(data)
+	return loaded, origin, data, err
+}
+
+// readRunInput reads a run input from path, or else from the configuration service
+// under key, returning the path or "config:<key>" as its origin
+func readRunInput(ctx context.Context, path, key string, source ports.SettingSource, what string) (string, []byte, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s: %w", what, err)
+		}
+		return path, data, nil
+	}
+	data, err := source.Setting(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	return "config:" + key, data, nil
+}
+
+// writeVerdict saves the final verdict as the run's pass/fail artifact
+func writeVerdict(path string, verdict scenario.Verdict) error {
+	data, err := json.MarshalIndent(verdict, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode verdict: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write verdict: %w", err)
+	}
+	return nil
+}
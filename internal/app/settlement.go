@@ -0,0 +1,32 @@
+package app
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/settlementstore"
+)
+
+// openSettlement connects to the custodian-simulator through service discovery and
+// opens the outbox at SETTLEMENT_OUTBOX_PATH, if set; the returned close releases both
+func openSettlement(cfg *config.Config, logger *logrus.Logger) (*infrastructure.CustodianSettlementGateway, settlement.Store, func() error, error) {
+	var store settlement.Store
+	closeStore := func() error { return nil }
+	if cfg.SettlementOutboxPath != "" {
+		fileStore, err := settlementstore.OpenFileStore(cfg.SettlementOutboxPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		store, closeStore = fileStore, fileStore.Close
+	}
+
+	discovery := infrastructure.NewServiceDiscoveryClient(cfg, logger)
+	clients := infrastructure.NewInterServiceClientManager(cfg, logger, discovery, infrastructure.NewConfigurationClient(cfg, logger))
+	closeAll := func() error {
+		clients.Close()
+		return closeStore()
+	}
+	return infrastructure.NewCustodianSettlementGateway(clients), store, closeAll, nil
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
+)
+
+// newStartup builds the coordinator bringing the data adapter, the discovery
+// registry, the configuration service and the matching engine up in that order,
+// named as their readiness checks are. Those named in
+// READINESS_CRITICAL_DEPENDENCIES are waited for until STARTUP_TIMEOUT; the rest
+// are tried once and left in stub mode. Each attempt is limited to
+// READINESS_CHECK_TIMEOUT. Once serving, the instance registers with discovery if
+// the registry came up.
+func (a *Application) newStartup() *startup.Coordinator {
+	cfg, logger := a.cfg, a.logger
+	critical := criticalDependencies(cfg)
+
+	coordinator := startup.NewCoordinator(cfg.StartupTimeout, cfg.StartupRetryInterval, cfg.ReadinessTimeout, logger)
+	components := []startup.Component{
+		{Name: "data_adapter", Start: func(ctx context.Context) error {
+			return cfg.InitializeDataAdapter(ctx, logger)
+		}},
+		{Name: "redis_discovery", Start: a.discovery.Ping},
+		{Name: "configuration_service", Start: a.configuration.Ping},
+		{Name: "matching_engine", Start: func(ctx context.Context) error {
+			return a.exchangeService.Engine().Ping(ctx)
+		}},
+	}
+	for i := range components {
+		components[i].Critical = critical[components[i].Name]
+	}
+	coordinator.Add(components...)
+	coordinator.OnServing(func() {
+		if coordinator.State("redis_discovery") != startup.StateUp {
+			return
+		}
+		if err := a.discovery.Start(); err != nil {
+			logger.WithError(err).Warn("Failed to register with service discovery")
+		}
+	})
+	return coordinator
+}
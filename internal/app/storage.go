@@ -0,0 +1,391 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/accountstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/balancestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/eventlog"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/idempotencystore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/metricsstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/migrations"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/orderstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/outboxstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/statestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/statsstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/tradestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// scenarioStorage holds the backends that persist engine events and state, market
+// data statistics, idempotency keys, run metrics, the trade tape, orders, accounts,
+// the balance journal and the event outbox
+type scenarioStorage struct {
+	source       services.EventLogBackend   // nil when event logging is disabled
+	eventLog     matching.EventLog          // what the engine appends to
+	stateStore   matching.StateStore        // nil when engine state snapshots are disabled; never migrated
+	statsStore   marketdata.Store           // nil when statistics persistence is disabled
+	idempotency  idempotency.Store          // nil keeps keys in memory only; never migrated
+	metricsStore runmetrics.Store           // nil when metrics snapshots are disabled; always Postgres
+	tradeStore   tradetape.Store            // nil when the trade tape is disabled; always Postgres
+	orderStore   orderhistory.Store         // nil when orders are not persisted; always Postgres
+	accountStore accounts.Store             // nil keeps accounts in memory only; always Postgres
+	balanceStore ledger.Store               // nil keeps the balance journal in memory only; always Postgres
+	outboxStore  outbox.Store               // nil when the outbox is disabled; always Postgres
+	migration    *services.StorageMigration // nil unless dual-writing to a migration target
+	postgres     *sql.DB                    // Shared by the Postgres stores; opened on first use
+	schema       *migrations.Status         // Version of the Postgres schema at startup; nil without Postgres
+	migrated     bool                       // The schema is migrated, so stores leave their tables to the migrations
+	closers      []func() error
+}
+
+// backendStores are what one backend persists; any may be nil
+type backendStores struct {
+	eventLog    services.EventLogBackend
+	statsStore  marketdata.Store
+	idempotency idempotency.Store
+}
+
+// openStorage migrates the Postgres schema when RUN_MIGRATIONS is set, opens the
+// configured backend and, when a migration target is set, backfills it and wraps
+// both in dual-writers
+func openStorage(ctx context.Context, cfg *config.Config, logger *logrus.Logger) (*scenarioStorage, error) {
+	storage := &scenarioStorage{}
+
+	if cfg.RunMigrations {
+		if err := storage.migrate(ctx, cfg, logger); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	source, err := storage.openBackend(cfg, cfg.StorageBackend)
+	if err != nil {
+		storage.Close()
+		return nil, err
+	}
+	sourceLog, sourceStats := source.eventLog, source.statsStore
+	storage.source = sourceLog
+	if sourceLog != nil {
+		storage.eventLog = sourceLog
+	}
+	storage.statsStore = sourceStats
+	storage.idempotency = source.idempotency
+
+	if cfg.StateSnapshotInterval > 0 {
+		if storage.stateStore, err = storage.openStateStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.MetricsSnapshotInterval > 0 {
+		if storage.metricsStore, err = storage.openMetricsStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.OutboxEnabled {
+		if storage.outboxStore, err = storage.openOutboxStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.TradeTapeInterval > 0 {
+		if storage.tradeStore, err = storage.openTradeStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.OrderStoreInterval > 0 {
+		if storage.orderStore, err = storage.openOrderStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.PersistAccounts {
+		if storage.accountStore, err = storage.openAccountStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.BalanceJournalInterval > 0 {
+		if storage.balanceStore, err = storage.openBalanceStore(cfg); err != nil {
+			storage.Close()
+			return nil, err
+		}
+	}
+
+	if !storage.migrated && storage.postgres != nil {
+		storage.readSchemaVersion(ctx, logger)
+	}
+
+	if cfg.StorageMigrationTarget == "" {
+		return storage, nil
+	}
+	if cfg.StorageMigrationTarget == cfg.StorageBackend {
+		storage.Close()
+		return nil, fmt.Errorf("storage migration target %q is the current backend", cfg.StorageMigrationTarget)
+	}
+
+	target, err := storage.openBackend(cfg, cfg.StorageMigrationTarget)
+	if err != nil {
+		storage.Close()
+		return nil, err
+	}
+	targetLog, targetStats := target.eventLog, target.statsStore
+	if sourceLog == nil || targetLog == nil {
+		storage.Close()
+		return nil, fmt.Errorf("storage migration requires an event log on both %s and %s", cfg.StorageBackend, cfg.StorageMigrationTarget)
+	}
+
+	storage.migration = services.NewStorageMigration(sourceLog, targetLog, sourceStats, targetStats, logger)
+	if _, err := storage.migration.Backfill(); err != nil {
+		storage.Close()
+		return nil, err
+	}
+	storage.eventLog = storage.migration.EventLog()
+	storage.statsStore = storage.migration.StatisticsStore()
+
+	logger.WithFields(logrus.Fields{
+		"source": cfg.StorageBackend,
+		"target": cfg.StorageMigrationTarget,
+	}).Info("Storage migration dual-write enabled")
+	return storage, nil
+}
+
+// openBackend returns the stores of one backend
+func (s *scenarioStorage) openBackend(cfg *config.Config, backend string) (backendStores, error) {
+	switch backend {
+	case "file":
+		var stores backendStores
+		if cfg.EventLogPath != "" {
+			fileLog, err := eventlog.OpenFileLog(cfg.EventLogPath)
+			if err != nil {
+				return backendStores{}, err
+			}
+			s.closers = append(s.closers, fileLog.Close)
+			stores.eventLog = fileLog
+		}
+		if cfg.StatsSnapshotPath != "" {
+			stores.statsStore = statsstore.NewFileStore(cfg.StatsSnapshotPath)
+		}
+		if cfg.IdempotencyPath != "" {
+			fileStore, err := idempotencystore.OpenFileStore(cfg.IdempotencyPath)
+			if err != nil {
+				return backendStores{}, err
+			}
+			s.closers = append(s.closers, fileStore.Close)
+			stores.idempotency = fileStore
+		}
+		return stores, nil
+
+	case "redis":
+		opt, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return backendStores{}, fmt.Errorf("invalid redis url: %w", err)
+		}
+		client := redis.NewClient(opt)
+		s.closers = append(s.closers, client.Close)
+
+		prefix := fmt.Sprintf("exchange-simulator:%s", cfg.ServiceInstanceName)
+		return backendStores{
+			eventLog:    eventlog.NewRedisLog(client, prefix+":event-log", cfg.RequestTimeout),
+			statsStore:  statsstore.NewRedisStore(client, prefix+":statistics", cfg.RequestTimeout),
+			idempotency: idempotencystore.NewRedisStore(client, prefix+":idempotency", cfg.RequestTimeout),
+		}, nil
+
+	default:
+		return backendStores{}, fmt.Errorf("unknown storage backend: %q", backend)
+	}
+}
+
+// openStateStore returns the file store at STATE_SNAPSHOT_PATH, or else connects to
+// Postgres and creates the state table if needed
+func (s *scenarioStorage) openStateStore(cfg *config.Config) (matching.StateStore, error) {
+	if cfg.StateSnapshotPath != "" {
+		return statestore.NewFileStore(cfg.StateSnapshotPath), nil
+	}
+	db, err := s.openPostgres(cfg, "engine state snapshots")
+	if err != nil {
+		return nil, err
+	}
+	store := statestore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openMetricsStore connects to Postgres and creates the snapshot table if needed
+func (s *scenarioStorage) openMetricsStore(cfg *config.Config) (runmetrics.Store, error) {
+	db, err := s.openPostgres(cfg, "metrics snapshots")
+	if err != nil {
+		return nil, err
+	}
+	store := metricsstore.NewPostgresStore(db, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openTradeStore connects to Postgres and creates the trade table if needed
+func (s *scenarioStorage) openTradeStore(cfg *config.Config) (tradetape.Store, error) {
+	db, err := s.openPostgres(cfg, "the trade tape")
+	if err != nil {
+		return nil, err
+	}
+	store := tradestore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	// Both tables live in the same database, so trades commit with their events
+	if outboxStore, ok := s.outboxStore.(*outboxstore.PostgresStore); ok {
+		store.SetOutbox(outboxStore)
+	}
+	return store, nil
+}
+
+// openOrderStore connects to Postgres and creates the order table if needed
+func (s *scenarioStorage) openOrderStore(cfg *config.Config) (orderhistory.Store, error) {
+	db, err := s.openPostgres(cfg, "the order store")
+	if err != nil {
+		return nil, err
+	}
+	store := orderstore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openOutboxStore connects to Postgres and creates the outbox table if needed
+func (s *scenarioStorage) openOutboxStore(cfg *config.Config) (outbox.Store, error) {
+	db, err := s.openPostgres(cfg, "the outbox")
+	if err != nil {
+		return nil, err
+	}
+	store := outboxstore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openAccountStore connects to Postgres and creates the account table if needed
+func (s *scenarioStorage) openAccountStore(cfg *config.Config) (accounts.Store, error) {
+	db, err := s.openPostgres(cfg, "account persistence")
+	if err != nil {
+		return nil, err
+	}
+	store := accountstore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openBalanceStore connects to Postgres and creates the balance journal table if needed
+func (s *scenarioStorage) openBalanceStore(cfg *config.Config) (ledger.Store, error) {
+	db, err := s.openPostgres(cfg, "the balance journal")
+	if err != nil {
+		return nil, err
+	}
+	store := balancestore.NewPostgresStore(db, cfg.ServiceInstanceName, cfg.RequestTimeout)
+	if err := s.ensureSchema(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// migrate brings the Postgres schema to the latest version this build has
+func (s *scenarioStorage) migrate(ctx context.Context, cfg *config.Config, logger *logrus.Logger) error {
+	db, err := s.openPostgres(cfg, "RUN_MIGRATIONS")
+	if err != nil {
+		return err
+	}
+	runner, err := migrations.NewRunner(db)
+	if err != nil {
+		return err
+	}
+	status, err := runner.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate schema: %w", err)
+	}
+	s.schema, s.migrated = &status, true
+	logger.WithFields(logrus.Fields{
+		"version": status.Version,
+		"applied": status.Applied,
+	}).Info("Schema migrated")
+	return nil
+}
+
+// readSchemaVersion records the version of a schema the stores created themselves,
+// for health; failing to read it is only logged
+func (s *scenarioStorage) readSchemaVersion(ctx context.Context, logger *logrus.Logger) {
+	runner, err := migrations.NewRunner(s.postgres)
+	if err == nil {
+		var status migrations.Status
+		if status, err = runner.Status(ctx); err == nil {
+			s.schema = &status
+			return
+		}
+	}
+	logger.WithError(err).Warn("Failed to read schema version")
+}
+
+// ensureSchema creates a store's tables, unless the migrations already have
+func (s *scenarioStorage) ensureSchema(store interface{ EnsureSchema() error }) error {
+	if s.migrated {
+		return nil
+	}
+	return store.EnsureSchema()
+}
+
+// openPostgres returns the connection pool shared by the Postgres stores
+func (s *scenarioStorage) openPostgres(cfg *config.Config, feature string) (*sql.DB, error) {
+	if s.postgres != nil {
+		return s.postgres, nil
+	}
+	if cfg.PostgresURL == "" {
+		return nil, fmt.Errorf("%s requires POSTGRES_URL", feature)
+	}
+	db, err := sql.Open("postgres", cfg.PostgresURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres url: %w", err)
+	}
+	s.closers = append(s.closers, db.Close)
+	s.postgres = db
+	return db, nil
+}
+
+// Close releases every backend connection
+func (s *scenarioStorage) Close() {
+	for _, closer := range s.closers {
+		closer()
+	}
+	s.closers = nil
+	s.postgres = nil
+}
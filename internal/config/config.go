@@ -24,21 +24,289 @@ type Config struct {
 	// Network
 	HTTPPort                int
 	GRPCPort                int
+	GRPCTradingAPIKeys      string        // "key,..." allowed on the gRPC TradingService (empty = unauthenticated)
+	AuthRecvWindow          time.Duration // How old a request signed with an account API key may be
+	AdvertisedHost          string        // Host registered for other services to reach this instance (empty = detected)
+	PodIP                   string        // Address the orchestrator assigned, used when no host is advertised
 
 	// Configuration
 	LogLevel                string
 	PostgresURL             string
+	RunMigrations           bool // Migrate the Postgres schema at startup instead of each store creating its tables
 	RedisURL                string
 	ConfigurationServiceURL string
 	RequestTimeout          time.Duration
 	CacheTTL                time.Duration
-	HealthCheckInterval     time.Duration
+	ConfigCacheSize         int           // Most configuration service keys cached at once
+	ConfigNegativeCacheTTL  time.Duration // How long a key the configuration service does not hold is cached as missing
+	HealthCheckInterval     time.Duration // How often cached inter-service connections are health-checked (0 = never)
+	ConfigWatchInterval     time.Duration // How often watched configuration service keys are read for changes
+	FeeScheduleConfigKey    string        // Configuration service key holding fee rates by symbol (empty = not watched)
+	ChaosConfigKey          string        // Configuration service key holding chaos settings (empty = not watched)
+	LogConfigKey            string        // Configuration service key holding log level and sampling settings (empty = not watched)
+	PersonalityConfigKey    string        // Configuration service key prefix the venue personality is read under, followed by the instance name (empty = none)
+	ConfigSnapshotPath      string        // File keeping last-known-good configuration service values (empty = none)
+
+	// Inter-Service gRPC Clients
+	ClientKeepaliveTime     time.Duration // Ping interval on idle connections (0 = no keepalive)
+	ClientKeepaliveTimeout  time.Duration // Wait for a ping ack before the connection is closed
+	ClientPoolSize          int           // Connections per target service
+	ClientPoolSizes         string        // Per-service overrides, "service=size,...", e.g. "audit-correlator=4"
+	ClientBreakerThreshold  int           // Consecutive failed calls that open a service's circuit breaker (0 = never)
+	ClientBreakerBackoff    time.Duration // Time a breaker stays open after tripping, before a probe call
+	ClientBreakerMaxBackoff time.Duration // Longest time open; each failed probe doubles the last
+	ClientRetryAttempts     int           // Attempts at a unary call, the first included (1 = no retries)
+	ClientRetryBackoff      time.Duration // Wait before the first retry, doubling for each after, with jitter
+	ClientRetryMaxBackoff   time.Duration // Longest wait between attempts
+	ClientRetryCodes        string        // Status codes retried, "CODE,...", e.g. "UNAVAILABLE,ABORTED"
+
+	// Service Discovery
+	DiscoveryStrategy       string        // How an instance is picked: round_robin, random or least_recent_failure
+	DiscoveryStrategies     string        // Per-service overrides, "service=strategy,...", e.g. "audit-correlator=random"
+	DiscoveryWatchInterval  time.Duration // How often watched services are re-read, besides on keyspace notifications
+
+	// Transport Security (gRPC server and inter-service clients)
+	TLSMode                 string // off, tls (servers present certificates) or mtls (both sides do)
+	TLSCertFile             string // PEM certificate chain presented to peers
+	TLSKeyFile              string // PEM private key of the certificate
+	TLSCAFile               string // PEM bundle peers' certificates are verified against
+	TLSCert                 string // Inline PEM certificate chain, used over TLSCertFile
+	TLSKey                  string // Inline PEM private key, used over TLSKeyFile
+	TLSCA                   string // Inline PEM CA bundle, used over TLSCAFile
+	TLSConfigKey            string // Configuration service key prefix for PEM set neither inline nor as a file
+	TLSPeerIDs              string // SPIFFE IDs or trust domains peers must present, "spiffe://...,..." (empty = any)
+
+	// Circuit Breakers (volatility halts)
+	CircuitBreakerEnabled   bool
+	CircuitBreakerThreshold float64       // Percent move that triggers a halt
+	CircuitBreakerWindow    time.Duration // Lookback window for the price move
+	CircuitBreakerCooldown  time.Duration // Halt duration before automatic resume (0 = manual only)
+
+	// Matching Engine Event Log
+	EventLogPath            string // JSON lines log replayed on startup and appended to (empty = disabled)
+
+	// Matching Engine State Snapshots
+	StateSnapshotInterval   time.Duration // How often books and orders are saved for crash recovery (0 = disabled)
+	StateSnapshotPath       string        // File the state is kept in (empty = Postgres via POSTGRES_URL)
+
+	// Market Data Statistics
+	StatsSnapshotPath       string        // Ticker/candle snapshot restored on startup (empty = disabled)
+	StatsSnapshotInterval   time.Duration // How often the snapshot is rewritten
+	ExchangeStatsInterval   time.Duration // How often open interest and volume are pushed to stream clients (0 = disabled)
+
+	// Candle Archive
+	CandleArchiveInterval   time.Duration // How often closed candles are archived via the data adapter (0 = disabled)
+	CandleArchiveTTL        time.Duration // How long archived candles are kept (0 = until evicted)
+
+	// Metrics Snapshots
+	MetricsSnapshotInterval time.Duration // How often business metrics are saved to Postgres (0 = disabled)
+	ScenarioRunID           string        // Run the snapshots are keyed by (default: derived from the start time)
+
+	// Tracing
+	TracingEndpoint         string  // OTLP/HTTP collector spans are exported to, e.g. "http://otel-collector:4318" (empty = tracing off)
+	TracingSampleRatio      float64 // Share of new traces sampled, 0 to 1; calls continuing a trace follow the caller's choice
+
+	// Metrics Backend
+	MetricsBackend          string        // "prometheus" (scraped at /metrics) or "otel" (pushed over OTLP)
+	MetricsEndpoint         string        // OTLP/HTTP collector metrics are pushed to with the otel backend (default: OTEL_EXPORTER_OTLP_ENDPOINT)
+	MetricsExportInterval   time.Duration // How often the otel backend pushes metrics
+
+	// Readiness
+	ReadinessTimeout        time.Duration // Limit on each dependency check /api/v1/ready runs
+	ReadinessCritical       string        // Dependencies failing readiness, "name,...", e.g. "matching_engine,data_adapter"; others are reported only
+
+	// Startup
+	StartupTimeout          time.Duration // How long critical dependencies (READINESS_CRITICAL_DEPENDENCIES) may take to start before the service exits
+	StartupRetryInterval    time.Duration // Wait between attempts to start a critical dependency
+
+	// Trade Tape
+	TradeTapeInterval       time.Duration // How often executed trades are flushed to Postgres (0 = disabled)
+
+	// Order Store
+	OrderStoreInterval      time.Duration // How often changed orders are flushed to Postgres (0 = disabled)
+
+	// Write-Behind Queues (trade tape and order store)
+	WriteBehindQueueSize    int           // Writes held before producers wait for room
+	WriteBehindBatchSize    int           // Most writes flushed at once; a full batch flushes early
+	WriteBehindMaxWait      time.Duration // Longest a producer waits for room before queueing past the size (0 = until there is room)
+
+	// Accounts
+	PersistAccounts         bool // Keep accounts opened through the API in Postgres across restarts
+
+	// Balance Journal
+	BalanceJournalInterval  time.Duration // How often balance journal postings are flushed to Postgres (0 = disabled)
+
+	// Persistence Latency
+	SlowQueryThreshold      time.Duration // Persistence calls at least this slow are logged (0 = disabled)
+	PersistenceP99Limit     time.Duration // p99 persistence latency above which storage is reported degraded (0 = disabled)
+
+	// Perpetual Funding
+	FundingInterval         time.Duration // Between funding transfers on every perpetual (0 = each instrument's own)
+
+	// Insurance Fund
+	InsuranceFund           string // Starting balances "asset=amount,...", e.g. "USD=1000000" (empty = none, so shortfalls deleverage)
+
+	// Custodian Settlement
+	SettlementEnabled       bool          // Send spot fills to the custodian-simulator as settlement instructions
+	SettlementNetting       time.Duration // Between netted batches per account and asset (0 = gross instructions per fill)
+	SettlementRetryInterval time.Duration // Between outbox dispatches; also the first retry backoff, doubling per failure
+	SettlementRetryMax      time.Duration // Longest wait between retries of a failed instruction
+	SettlementBatchSize     int           // Instructions submitted per call (0 = all that are due)
+	SettlementOutboxPath    string        // JSON lines outbox restored on startup (empty = memory only)
+	SettlementCutoff        string        // Daily UTC cutoff netting the day's trades, "17:00" (empty = no settlement cycles)
+	SettlementCycle         string        // Days from trade date to settlement date, "T+0" or "T+1"
+
+	// Audit Trail
+	AuditEnabled            bool          // Send every order, trade and funding change to the audit-correlator
+	AuditBufferSize         int           // Events held awaiting delivery; further events are dropped and counted
+	AuditBatchSize          int           // Events sent per call
+	AuditFlushInterval      time.Duration // Longest an event waits for its batch; also the first retry backoff
+	AuditMaxAttempts        int           // Calls made for a batch before its events are counted as failed
+	AuditStreamWindow       int           // Batches left unacknowledged on the StreamEvents stream (0 = one SubmitEvents call per batch)
+
+	// Transactional Outbox
+	OutboxEnabled           bool          // Write audit events to a Postgres outbox, with the trades they announce, before sending them
+	OutboxInterval          time.Duration // How often queued events are written and due messages dispatched
+	OutboxBatchSize         int           // Messages dispatched per run
+	OutboxRetryMax          time.Duration // Longest wait between attempts at a message; the first is OutboxInterval
+	OutboxRedisChannel      string        // Also publish every event on this Redis channel (empty = audit-correlator only)
+
+	// Chaos Injection
+	ChaosEnabled            bool          // Serve the chaos API that injects faults at runtime
+	ChaosSeed               int64         // Fixed seed deciding which orders and updates faults hit (0 = random)
+
+	// Admin API
+	AdminToken              string // Bearer token /api/v1/admin requires (empty = admin routes open)
+
+	// Scenario Assertions
+	ScenarioPath            string // JSON scenario whose assertions are judged at shutdown (empty = none)
+	ScenarioVerdictPath     string // Where the final verdict is written (empty = logged only)
+	ScenarioEventHistory    int    // Events kept for the assertions
+
+	// Scenario Scripts
+	ScenarioScriptPath      string // YAML or JSON script of market events played from startup (empty = none)
+	ScenarioScriptKey       string // Configuration service key holding the script, when no path is set
+	ScenarioProgressURL     string // Orchestrator endpoint script progress is posted to (empty = API only)
+
+	// Fixture Seeding
+	SeedFixturePath         string // YAML or JSON fixture of instruments, accounts and resting orders seeded at startup (empty = none)
+	SeedFixtureKey          string // Configuration service key holding the fixture, when no path is set
+
+	// Order Flow Replay
+	ReplayPath              string  // Captured order flow resubmitted from startup (empty = none)
+	ReplaySpeed             float64 // Multiple of the recorded pace (0 = as fast as possible)
+
+	// Run Reproducibility
+	RunManifestPath         string // Where the run manifest is written at startup (empty = served by the API only)
+	RunBundlePath           string // Where the manifest, journal and metrics are archived at shutdown (empty = no bundle)
+
+	// Incident Timeline
+	IncidentHistorySize     int // Health transitions kept for GET /api/v1/admin/incidents
+
+	// External Price Feed
+	PriceFeedChannel        string        // Redis pub/sub channel, or pattern with '*', carrying JSON prices (empty = off)
+	PriceFeedSymbolMap      string        // "external=listed,..." for feeds naming symbols differently
+	PriceFeedMaxAge         time.Duration // How long a price anchors the venue after it arrives
+	PriceFeedRetryInterval  time.Duration // Before resubscribing after the feed fails
+
+	// Synthetic Market Data
+	SyntheticSymbols        string        // Symbols given a synthetic price path and liquidity, "BTC-USD,ETH-USD" (empty = off)
+	SyntheticModel          string        // "gbm" or "random_walk"
+	SyntheticDrift          float64       // Expected return per year
+	SyntheticVolatility     float64       // Standard deviation of returns per year
+	SyntheticJumpIntensity  float64       // Expected jumps per day (0 = none)
+	SyntheticJumpMean       float64       // Mean log-return of a jump
+	SyntheticJumpStdDev     float64       // Standard deviation of a jump's log-return
+	SyntheticInterval       time.Duration // Wall time between steps (0 = stepped only via the admin API)
+	SyntheticLevels         int           // Ladder levels per side
+	SyntheticSpreadBps      float64       // Between the best synthetic bid and ask
+	SyntheticStepBps        float64       // Between ladder levels
+	SyntheticLevelNotional  float64       // Quote currency resting per level
+	SyntheticTradeNotional  float64       // Quote currency printed per step (0 = quotes only)
+	SyntheticSeed           int64         // Fixed seed for reproducible paths (0 = random)
+
+	// Background Market Maker
+	MarketMakerQuotes       string        // "symbol=spread_bps:quantity[:levels[:step_bps]],..." quoted around the mark (empty = off)
+	MarketMakerAccount      string        // Account the quotes rest under
+	MarketMakerInterval     time.Duration // Between refreshes that replenish filled quotes
+	MarketMakerRequoteBps   float64       // Mark price move that moves the ladder
+
+	// Venue Surveillance
+	SurveillanceCancelRatio float64       // Cancel-to-order ratio that flags an account
+	SurveillanceMinOrders   int           // Orders in the window before the ratio is judged
+	SurveillanceWindow      time.Duration // Lookback for the cancel ratio
+	SurveillanceProximity   float64       // Fraction of the circuit breaker threshold that flags a print
+
+	// Messaging Policy (per API key, 0 = not enforced)
+	MessageRateLimit        float64 // Messages per second over the last minute
+	OrderToTradeLimit       float64 // Orders placed per trade
+
+	// Stream Entitlements (per API key, 0 = not enforced)
+	StreamLimit             int    // WebSocket and gRPC streams open at once
+	SubscriptionLimit       int    // Topics subscribed across the key's streams
+	StreamKeyLimits         string // Per-key overrides, "api_key=max_streams:max_subscriptions,..."
+
+	// Request Rate Limits (0 = not enforced)
+	RateLimitWeight         int           // Request weight each API key or account may spend per window
+	RateLimitIPWeight       int           // Request weight each client IP may spend per window
+	RateLimitWindow         time.Duration // Time for an empty bucket to refill
+	RateLimitWeights        string        // Per-endpoint weights, "GET /api/v1/trades/:symbol=5,..." (others weigh 1)
+	RateLimitConfigKey      string        // Configuration service key holding the limits (empty = not polled)
+	RateLimitRefresh        time.Duration // How often the key is read while CONFIG_WATCH_INTERVAL is unset
+
+	// API Versioning
+	APIV1DeprecatedAt       string // When REST v1 starts warning, RFC 3339 or a duration after startup (empty = never)
+	APIV1SunsetAt           string // When REST v1 calls are refused in favour of v2 (empty = never)
+
+	// Trading Sessions
+	SessionGracePeriod      time.Duration // Default wait before cancel-on-disconnect fires
+	SessionHeartbeat        time.Duration // Interval between heartbeats on open sessions
+
+	// Venue Clock
+	ClockMode               string        // "wall" or "simulated"
+	ClockStart              string        // RFC 3339 start of simulated time (empty = now)
+	ClockSpeed              float64       // Simulated seconds per wall second
+	SchedulerInterval       time.Duration // How often expiries, transitions and funding are checked
+	ExpiryPrecision         time.Duration // Slot width of the GTD expiry timer wheel
+
+	// Colocation Simulation
+	AccountProfiles         string // "account=latency[:priority],...", e.g. "mm-colo=50us:1,retail-1=20ms"
+
+	// Portfolio Valuation
+	ReportingCurrency       string // Asset valuations are reported in unless a request names one
+
+	// Order Entry Replay Protection
+	IdempotencyWindow       time.Duration // How long idempotency keys are remembered
+	IdempotencyPath         string        // JSON lines store of keys for the file backend (empty = memory only)
+
+	// Binance-Compatible REST API
+	BinanceCompatEnabled    bool   // Serve /api/v3 with Binance field names and signatures
+	BinanceAPIKeys          string // "key:secret[:account],..." (empty = any key, unsigned, trades as itself)
+
+	// Public Feed IDs
+	PublicIDMode            string // "internal" publishes stored IDs, "hmac" keyed hashes of them
+	PublicIDKey             string // HMAC key for public IDs (empty = random per process)
+
+	// FIX Gateway
+	FIXPort                 int    // FIX 4.4 acceptor port (0 = disabled)
+	FIXCompID               string // SenderCompID the venue uses on every session
+	FIXSessions             string // "compid=account[:reset][:nopersist],..." allowed to log on
+
+	// Storage Backends
+	StorageBackend          string // "file" (EVENT_LOG_PATH / STATS_SNAPSHOT_PATH) or "redis" (REDIS_URL)
+	StorageMigrationTarget  string // Backend to dual-write to while migrating (empty = no migration)
 
 	// Data Adapter
 	dataAdapter adapters.DataAdapter
 
 	// Metrics
 	metricsPort ports.MetricsPort
+
+	// Tracing (nil = not traced)
+	tracingPort ports.TracingPort
+
+	// Venue Clock (nil = wall clock)
+	clock ports.Clock
 }
 
 func Load() *Config {
@@ -52,13 +320,177 @@ func Load() *Config {
 		Environment:             getEnv("ENVIRONMENT", "development"),
 		HTTPPort:                getEnvAsInt("HTTP_PORT", 8080),
 		GRPCPort:                getEnvAsInt("GRPC_PORT", 50051),
+		GRPCTradingAPIKeys:      getEnv("GRPC_TRADING_API_KEYS", ""),
+		AuthRecvWindow:          getEnvAsDuration("AUTH_RECV_WINDOW", 5*time.Second),
+		AdvertisedHost:          getEnv("ADVERTISED_HOST", ""),
+		PodIP:                   getEnv("POD_IP", ""),
 		LogLevel:                getEnv("LOG_LEVEL", "info"),
 		PostgresURL:             getEnv("POSTGRES_URL", ""),
+		RunMigrations:           getEnvAsBool("RUN_MIGRATIONS", false),
 		RedisURL:                getEnv("REDIS_URL", "redis://localhost:6379"),
 		ConfigurationServiceURL: getEnv("CONFIG_SERVICE_URL", "http://localhost:8090"),
 		RequestTimeout:          getEnvAsDuration("REQUEST_TIMEOUT", 5*time.Second),
 		CacheTTL:                getEnvAsDuration("CACHE_TTL", 5*time.Minute),
+		ConfigCacheSize:         getEnvAsInt("CONFIG_CACHE_SIZE", 1000),
+		ConfigNegativeCacheTTL:  getEnvAsDuration("CONFIG_NEGATIVE_CACHE_TTL", 30*time.Second),
 		HealthCheckInterval:     getEnvAsDuration("HEALTH_CHECK_INTERVAL", 30*time.Second),
+		ConfigWatchInterval:     getEnvAsDuration("CONFIG_WATCH_INTERVAL", getEnvAsDuration("RATE_LIMIT_REFRESH", 30*time.Second)),
+		FeeScheduleConfigKey:    getEnv("FEE_SCHEDULE_CONFIG_KEY", ""),
+		ChaosConfigKey:          getEnv("CHAOS_CONFIG_KEY", ""),
+		LogConfigKey:            getEnv("LOG_CONFIG_KEY", ""),
+		PersonalityConfigKey:    getEnv("PERSONALITY_CONFIG_KEY", ""),
+		ConfigSnapshotPath:      getEnv("CONFIG_SNAPSHOT_PATH", ""),
+		ClientKeepaliveTime:     getEnvAsDuration("GRPC_CLIENT_KEEPALIVE_TIME", 0),
+		ClientKeepaliveTimeout:  getEnvAsDuration("GRPC_CLIENT_KEEPALIVE_TIMEOUT", 20*time.Second),
+		ClientPoolSize:          getEnvAsInt("GRPC_CLIENT_POOL_SIZE", 1),
+		ClientPoolSizes:         getEnv("GRPC_CLIENT_POOL_SIZES", ""),
+		ClientBreakerThreshold:  getEnvAsInt("GRPC_CLIENT_BREAKER_THRESHOLD", 5),
+		ClientBreakerBackoff:    getEnvAsDuration("GRPC_CLIENT_BREAKER_BACKOFF", time.Second),
+		ClientBreakerMaxBackoff: getEnvAsDuration("GRPC_CLIENT_BREAKER_MAX_BACKOFF", time.Minute),
+		ClientRetryAttempts:     getEnvAsInt("GRPC_CLIENT_RETRY_ATTEMPTS", 3),
+		ClientRetryBackoff:      getEnvAsDuration("GRPC_CLIENT_RETRY_BACKOFF", 100*time.Millisecond),
+		ClientRetryMaxBackoff:   getEnvAsDuration("GRPC_CLIENT_RETRY_MAX_BACKOFF", 2*time.Second),
+		ClientRetryCodes:        getEnv("GRPC_CLIENT_RETRY_CODES", "UNAVAILABLE,RESOURCE_EXHAUSTED,ABORTED"),
+		DiscoveryStrategy:       getEnv("DISCOVERY_LOAD_BALANCING", "round_robin"),
+		DiscoveryStrategies:     getEnv("DISCOVERY_LOAD_BALANCING_SERVICES", ""),
+		DiscoveryWatchInterval:  getEnvAsDuration("DISCOVERY_WATCH_INTERVAL", 5*time.Second),
+		TLSMode:                 getEnv("GRPC_TLS_MODE", "off"),
+		TLSCertFile:             getEnv("GRPC_TLS_CERT_FILE", ""),
+		TLSKeyFile:              getEnv("GRPC_TLS_KEY_FILE", ""),
+		TLSCAFile:               getEnv("GRPC_TLS_CA_FILE", ""),
+		TLSCert:                 getEnv("GRPC_TLS_CERT", ""),
+		TLSKey:                  getEnv("GRPC_TLS_KEY", ""),
+		TLSCA:                   getEnv("GRPC_TLS_CA", ""),
+		TLSConfigKey:            getEnv("GRPC_TLS_CONFIG_KEY", ""),
+		TLSPeerIDs:              getEnv("GRPC_TLS_PEER_IDS", ""),
+		CircuitBreakerEnabled:   getEnvAsBool("CIRCUIT_BREAKER_ENABLED", true),
+		CircuitBreakerThreshold: getEnvAsFloat("CIRCUIT_BREAKER_THRESHOLD_PCT", 10),
+		CircuitBreakerWindow:    getEnvAsDuration("CIRCUIT_BREAKER_WINDOW", 5*time.Minute),
+		CircuitBreakerCooldown:  getEnvAsDuration("CIRCUIT_BREAKER_COOLDOWN", 2*time.Minute),
+		EventLogPath:            getEnv("EVENT_LOG_PATH", ""),
+		StateSnapshotInterval:   getEnvAsDuration("STATE_SNAPSHOT_INTERVAL", 0),
+		StateSnapshotPath:       getEnv("STATE_SNAPSHOT_PATH", ""),
+		StatsSnapshotPath:       getEnv("STATS_SNAPSHOT_PATH", ""),
+		StatsSnapshotInterval:   getEnvAsDuration("STATS_SNAPSHOT_INTERVAL", 30*time.Second),
+		ExchangeStatsInterval:   getEnvAsDuration("EXCHANGE_STATS_INTERVAL", time.Minute),
+		CandleArchiveInterval:   getEnvAsDuration("CANDLE_ARCHIVE_INTERVAL", 10*time.Second),
+		CandleArchiveTTL:        getEnvAsDuration("CANDLE_ARCHIVE_TTL", 0),
+		MetricsSnapshotInterval: getEnvAsDuration("METRICS_SNAPSHOT_INTERVAL", 0),
+		ScenarioRunID:           getEnv("SCENARIO_RUN_ID", ""),
+		TracingEndpoint:         getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		TracingSampleRatio:      getEnvAsFloat("OTEL_TRACES_SAMPLER_ARG", 1),
+		MetricsBackend:          getEnv("METRICS_BACKEND", "prometheus"),
+		MetricsEndpoint:         getEnv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")),
+		MetricsExportInterval:   time.Duration(getEnvAsInt("OTEL_METRIC_EXPORT_INTERVAL", 60000)) * time.Millisecond,
+		ReadinessTimeout:        getEnvAsDuration("READINESS_CHECK_TIMEOUT", 2*time.Second),
+		ReadinessCritical:       getEnv("READINESS_CRITICAL_DEPENDENCIES", "matching_engine"),
+		StartupTimeout:          getEnvAsDuration("STARTUP_TIMEOUT", 60*time.Second),
+		StartupRetryInterval:    getEnvAsDuration("STARTUP_RETRY_INTERVAL", time.Second),
+		TradeTapeInterval:       getEnvAsDuration("TRADE_TAPE_INTERVAL", 0),
+		OrderStoreInterval:      getEnvAsDuration("ORDER_STORE_INTERVAL", 0),
+		WriteBehindQueueSize:    getEnvAsInt("WRITE_BEHIND_QUEUE_SIZE", 10000),
+		WriteBehindBatchSize:    getEnvAsInt("WRITE_BEHIND_BATCH_SIZE", 500),
+		WriteBehindMaxWait:      getEnvAsDuration("WRITE_BEHIND_MAX_WAIT", time.Second),
+		PersistAccounts:         getEnvAsBool("PERSIST_ACCOUNTS", false),
+		BalanceJournalInterval:  getEnvAsDuration("BALANCE_JOURNAL_INTERVAL", 0),
+		SlowQueryThreshold:      getEnvAsDuration("SLOW_QUERY_THRESHOLD", 250*time.Millisecond),
+		PersistenceP99Limit:     getEnvAsDuration("PERSISTENCE_P99_LIMIT", time.Second),
+		FundingInterval:         getEnvAsDuration("FUNDING_INTERVAL", 0),
+		InsuranceFund:           getEnv("INSURANCE_FUND", ""),
+		SettlementEnabled:       getEnvAsBool("SETTLEMENT_ENABLED", false),
+		SettlementNetting:       getEnvAsDuration("SETTLEMENT_NETTING", 0),
+		SettlementRetryInterval: getEnvAsDuration("SETTLEMENT_RETRY_INTERVAL", time.Second),
+		SettlementRetryMax:      getEnvAsDuration("SETTLEMENT_RETRY_MAX", time.Minute),
+		SettlementBatchSize:     getEnvAsInt("SETTLEMENT_BATCH_SIZE", 100),
+		SettlementOutboxPath:    getEnv("SETTLEMENT_OUTBOX_PATH", ""),
+		SettlementCutoff:        getEnv("SETTLEMENT_CUTOFF", ""),
+		SettlementCycle:         getEnv("SETTLEMENT_CYCLE", "T+0"),
+		AuditEnabled:            getEnvAsBool("AUDIT_ENABLED", false),
+		AuditBufferSize:         getEnvAsInt("AUDIT_BUFFER_SIZE", 10000),
+		AuditBatchSize:          getEnvAsInt("AUDIT_BATCH_SIZE", 100),
+		AuditFlushInterval:      getEnvAsDuration("AUDIT_FLUSH_INTERVAL", time.Second),
+		AuditMaxAttempts:        getEnvAsInt("AUDIT_MAX_ATTEMPTS", 5),
+		AuditStreamWindow:       getEnvAsInt("AUDIT_STREAM_WINDOW", 4),
+		OutboxEnabled:           getEnvAsBool("OUTBOX_ENABLED", false),
+		OutboxInterval:          getEnvAsDuration("OUTBOX_INTERVAL", time.Second),
+		OutboxBatchSize:         getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxRetryMax:          getEnvAsDuration("OUTBOX_RETRY_MAX", time.Minute),
+		OutboxRedisChannel:      getEnv("OUTBOX_REDIS_CHANNEL", ""),
+		ChaosEnabled:            getEnvAsBool("CHAOS_ENABLED", false),
+		ChaosSeed:               int64(getEnvAsInt("CHAOS_SEED", 0)),
+		AdminToken:              getEnv("ADMIN_TOKEN", ""),
+		ScenarioPath:            getEnv("SCENARIO_PATH", ""),
+		ScenarioVerdictPath:     getEnv("SCENARIO_VERDICT_PATH", ""),
+		ScenarioEventHistory:    getEnvAsInt("SCENARIO_EVENT_HISTORY", 100000),
+		ScenarioScriptPath:      getEnv("SCENARIO_SCRIPT_PATH", ""),
+		ScenarioScriptKey:       getEnv("SCENARIO_SCRIPT_KEY", ""),
+		ScenarioProgressURL:     getEnv("SCENARIO_PROGRESS_URL", ""),
+		SeedFixturePath:         getEnv("SEED_FIXTURE_PATH", ""),
+		SeedFixtureKey:          getEnv("SEED_FIXTURE_KEY", ""),
+		ReplayPath:              getEnv("REPLAY_PATH", ""),
+		ReplaySpeed:             getEnvAsFloat("REPLAY_SPEED", 1),
+		RunManifestPath:         getEnv("RUN_MANIFEST_PATH", ""),
+		RunBundlePath:           getEnv("RUN_BUNDLE_PATH", ""),
+		IncidentHistorySize:     getEnvAsInt("INCIDENT_HISTORY_SIZE", 1000),
+		PriceFeedChannel:        getEnv("PRICE_FEED_CHANNEL", ""),
+		PriceFeedSymbolMap:      getEnv("PRICE_FEED_SYMBOL_MAP", ""),
+		PriceFeedMaxAge:         getEnvAsDuration("PRICE_FEED_MAX_AGE", 30*time.Second),
+		PriceFeedRetryInterval:  getEnvAsDuration("PRICE_FEED_RETRY_INTERVAL", 5*time.Second),
+		SyntheticSymbols:        getEnv("SYNTHETIC_SYMBOLS", ""),
+		SyntheticModel:          getEnv("SYNTHETIC_MODEL", "gbm"),
+		SyntheticDrift:          getEnvAsFloat("SYNTHETIC_DRIFT", 0),
+		SyntheticVolatility:     getEnvAsFloat("SYNTHETIC_VOLATILITY", 0.6),
+		SyntheticJumpIntensity:  getEnvAsFloat("SYNTHETIC_JUMP_INTENSITY", 0),
+		SyntheticJumpMean:       getEnvAsFloat("SYNTHETIC_JUMP_MEAN", 0),
+		SyntheticJumpStdDev:     getEnvAsFloat("SYNTHETIC_JUMP_STDDEV", 0.02),
+		SyntheticInterval:       getEnvAsDuration("SYNTHETIC_INTERVAL", time.Second),
+		SyntheticLevels:         getEnvAsInt("SYNTHETIC_LEVELS", 5),
+		SyntheticSpreadBps:      getEnvAsFloat("SYNTHETIC_SPREAD_BPS", 10),
+		SyntheticStepBps:        getEnvAsFloat("SYNTHETIC_STEP_BPS", 5),
+		SyntheticLevelNotional:  getEnvAsFloat("SYNTHETIC_LEVEL_NOTIONAL", 10000),
+		SyntheticTradeNotional:  getEnvAsFloat("SYNTHETIC_TRADE_NOTIONAL", 1000),
+		SyntheticSeed:           int64(getEnvAsInt("SYNTHETIC_SEED", 0)),
+		MarketMakerQuotes:       getEnv("MARKET_MAKER_QUOTES", ""),
+		MarketMakerAccount:      getEnv("MARKET_MAKER_ACCOUNT", "liquidity-provider"),
+		MarketMakerInterval:     getEnvAsDuration("MARKET_MAKER_INTERVAL", 250*time.Millisecond),
+		MarketMakerRequoteBps:   getEnvAsFloat("MARKET_MAKER_REQUOTE_BPS", 5),
+		SurveillanceCancelRatio: getEnvAsFloat("SURVEILLANCE_CANCEL_RATIO", 0.9),
+		SurveillanceMinOrders:   getEnvAsInt("SURVEILLANCE_CANCEL_MIN_ORDERS", 20),
+		SurveillanceWindow:      getEnvAsDuration("SURVEILLANCE_CANCEL_WINDOW", time.Minute),
+		SurveillanceProximity:   getEnvAsFloat("SURVEILLANCE_BAND_PROXIMITY", 0.8),
+		MessageRateLimit:        getEnvAsFloat("MESSAGE_RATE_LIMIT", 0),
+		OrderToTradeLimit:       getEnvAsFloat("ORDER_TO_TRADE_LIMIT", 0),
+		StreamLimit:             getEnvAsInt("STREAM_LIMIT", 0),
+		SubscriptionLimit:       getEnvAsInt("SUBSCRIPTION_LIMIT", 0),
+		StreamKeyLimits:         getEnv("STREAM_KEY_LIMITS", ""),
+		RateLimitWeight:         getEnvAsInt("RATE_LIMIT_WEIGHT", 0),
+		RateLimitIPWeight:       getEnvAsInt("RATE_LIMIT_IP_WEIGHT", 0),
+		RateLimitWindow:         getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
+		RateLimitWeights:        getEnv("RATE_LIMIT_WEIGHTS", ""),
+		RateLimitConfigKey:      getEnv("RATE_LIMIT_CONFIG_KEY", ""),
+		RateLimitRefresh:        getEnvAsDuration("RATE_LIMIT_REFRESH", 30*time.Second),
+		APIV1DeprecatedAt:       getEnv("API_V1_DEPRECATED_AT", ""),
+		APIV1SunsetAt:           getEnv("API_V1_SUNSET_AT", ""),
+		SessionGracePeriod:      getEnvAsDuration("SESSION_GRACE_PERIOD", 5*time.Second),
+		SessionHeartbeat:        getEnvAsDuration("SESSION_HEARTBEAT", 10*time.Second),
+		ClockMode:               getEnv("CLOCK_MODE", "wall"),
+		ClockStart:              getEnv("CLOCK_START", ""),
+		ClockSpeed:              getEnvAsFloat("CLOCK_SPEED", 1),
+		SchedulerInterval:       getEnvAsDuration("SCHEDULER_INTERVAL", 250*time.Millisecond),
+		ExpiryPrecision:         getEnvAsDuration("EXPIRY_PRECISION", time.Millisecond),
+		AccountProfiles:         getEnv("ACCOUNT_PROFILES", ""),
+		ReportingCurrency:       getEnv("REPORTING_CURRENCY", "USD"),
+		IdempotencyWindow:       getEnvAsDuration("IDEMPOTENCY_WINDOW", 24*time.Hour),
+		IdempotencyPath:         getEnv("IDEMPOTENCY_PATH", ""),
+		BinanceCompatEnabled:    getEnvAsBool("BINANCE_COMPAT_ENABLED", false),
+		BinanceAPIKeys:          getEnv("BINANCE_API_KEYS", ""),
+		PublicIDMode:            getEnv("PUBLIC_ID_MODE", "internal"),
+		PublicIDKey:             getEnv("PUBLIC_ID_KEY", ""),
+		FIXPort:                 getEnvAsInt("FIX_PORT", 0),
+		FIXCompID:               getEnv("FIX_COMP_ID", "EXSIM"),
+		FIXSessions:             getEnv("FIX_SESSIONS", ""),
+		StorageBackend:          getEnv("STORAGE_BACKEND", "file"),
+		StorageMigrationTarget:  getEnv("STORAGE_MIGRATION_TARGET", ""),
 	}
 
 	// Backward compatibility: Default ServiceInstanceName to ServiceName
@@ -66,6 +498,11 @@ func Load() *Config {
 		cfg.ServiceInstanceName = cfg.ServiceName
 	}
 
+	// Unnamed runs are told apart by when the venue started
+	if cfg.ScenarioRunID == "" {
+		cfg.ScenarioRunID = time.Now().UTC().Format("run-20060102T150405Z")
+	}
+
 	// Validate instance name
 	if err := ValidateInstanceName(cfg.ServiceInstanceName); err != nil {
 		// Log warning but don't fail - allow backward compatibility
@@ -133,6 +570,23 @@ func (c *Config) GetMetricsPort() ports.MetricsPort {
 	return c.metricsPort
 }
 
+func (c *Config) SetTracingPort(tracingPort ports.TracingPort) {
+	c.tracingPort = tracingPort
+}
+
+func (c *Config) GetTracingPort() ports.TracingPort {
+	return c.tracingPort
+}
+
+func (c *Config) SetClock(clock ports.Clock) {
+	c.clock = clock
+}
+
+// GetClock returns the venue clock, or nil when the wall clock is used implicitly
+func (c *Config) GetClock() ports.Clock {
+	return c.clock
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -156,4 +610,22 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
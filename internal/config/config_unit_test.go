@@ -6,6 +6,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestConfig_GetDataAdapter(t *testing.T) {
@@ -83,4 +84,50 @@ func TestConfig_Load(t *testing.T) {
 			t.Errorf("Expected ServiceName 'test-service', got %s", cfg.ServiceName)
 		}
 	})
+	t.Run("loads_circuit_breaker_settings", func(t *testing.T) {
+		// Given: Circuit breaker overrides
+		os.Setenv("CIRCUIT_BREAKER_ENABLED", "false")
+		os.Setenv("CIRCUIT_BREAKER_THRESHOLD_PCT", "7.5")
+		os.Setenv("CIRCUIT_BREAKER_COOLDOWN", "30s")
+		defer os.Unsetenv("CIRCUIT_BREAKER_ENABLED")
+		defer os.Unsetenv("CIRCUIT_BREAKER_THRESHOLD_PCT")
+		defer os.Unsetenv("CIRCUIT_BREAKER_COOLDOWN")
+
+		// When: Loading config
+		cfg := Load()
+
+		// Then: Overrides are parsed and unset values keep defaults
+		if cfg.CircuitBreakerEnabled {
+			t.Error("Expected circuit breaker disabled")
+		}
+		if cfg.CircuitBreakerThreshold != 7.5 {
+			t.Errorf("Expected threshold 7.5, got %v", cfg.CircuitBreakerThreshold)
+		}
+		if cfg.CircuitBreakerCooldown != 30*time.Second {
+			t.Errorf("Expected cooldown 30s, got %v", cfg.CircuitBreakerCooldown)
+		}
+		if cfg.CircuitBreakerWindow != 5*time.Minute {
+			t.Errorf("Expected default window 5m, got %v", cfg.CircuitBreakerWindow)
+		}
+	})
+}
+
+func TestConfig_Snapshot(t *testing.T) {
+	t.Run("redacts_keys_tokens_and_urls", func(t *testing.T) {
+		// Given: A config holding secrets and plain settings
+		cfg := &Config{ServiceName: "exchange-simulator", AdminToken: "s3cret", RedisURL: "redis://:pw@redis:6379", PublicIDKey: "k"}
+
+		// When: It is snapshotted
+		snapshot := cfg.Snapshot()
+
+		// Then: Only the secrets are redacted
+		for _, secret := range []string{"AdminToken", "RedisURL", "PublicIDKey"} {
+			if snapshot[secret] != Redacted {
+				t.Errorf("Expected %s redacted, got %q", secret, snapshot[secret])
+			}
+		}
+		if snapshot["ServiceName"] != "exchange-simulator" || snapshot["BinanceAPIKeys"] != "" {
+			t.Errorf("Expected plain and empty settings kept, got %q and %q", snapshot["ServiceName"], snapshot["BinanceAPIKeys"])
+		}
+	})
 }
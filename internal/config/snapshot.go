@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces a secret setting's value in a snapshot
+const Redacted = "[redacted]"
+
+// secretSuffixes mark settings that hold keys, tokens or credential-bearing URLs
+var secretSuffixes = []string{"Key", "Keys", "Token", "URL"}
+
+// Snapshot lists every exported setting by field name with its value as text.
+// Non-empty secrets are replaced by Redacted, so the snapshot can be shared.
+func (c *Config) Snapshot() map[string]string {
+	snapshot := make(map[string]string)
+	value := reflect.ValueOf(c).Elem()
+	fields := value.Type()
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		text := fmt.Sprint(value.Field(i).Interface())
+		if text != "" && isSecret(field.Name) {
+			text = Redacted
+		}
+		snapshot[field.Name] = text
+	}
+	return snapshot
+}
+
+func isSecret(name string) bool {
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package accounts
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for an account ID the venue never issued
+	ErrNotFound = errors.New("account not found")
+	// ErrClosed refuses changes to, and orders from, a closed account
+	ErrClosed = errors.New("account closed")
+)
+
+// Status is where an account is in its lifecycle
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusClosed Status = "closed"
+)
+
+// DefaultTier is assigned to accounts created without one
+const DefaultTier = "standard"
+
+// Permission is what an account's API key may do
+type Permission string
+
+const (
+	PermissionRead     Permission = "read"
+	PermissionTrade    Permission = "trade"    // Place, amend and cancel the account's orders
+	PermissionWithdraw Permission = "withdraw" // Move the account's funds off the venue
+)
+
+// DefaultPermissions are granted to keys provisioned without any
+var DefaultPermissions = []Permission{PermissionRead, PermissionTrade}
+
+const (
+	maxTierLength   = 32
+	maxMetadataKeys = 32
+	maxMetadataSize = 256 // Per key and per value
+	maxLeverage     = 125
+)
+
+// Account is a trading account opened on the venue
+type Account struct {
+	ID          string             `json:"account_id"`
+	Tier        string             `json:"tier"`
+	Metadata    map[string]string  `json:"metadata"`
+	Balances    map[string]float64 `json:"balances,omitempty"`    // Opening balance per asset
+	Permissions []Permission       `json:"permissions,omitempty"` // Granted to the account's API key
+	Leverage    float64            `json:"leverage,omitempty"`    // Highest leverage on derivatives; 0 = each instrument's maximum
+	APIKeyHash  string             `json:"-"`                     // SHA-256 of the key; empty without one
+	Keys        []Key              `json:"-"`                     // Issued through the key API, revoked ones included
+	Status      Status             `json:"status"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ClosedAt    *time.Time         `json:"closed_at,omitempty"`
+}
+
+// Validate checks the fields an account takes from its template
+func (a Account) Validate() error {
+	return Template{Tier: a.Tier, Metadata: a.Metadata, Balances: a.Balances, Permissions: a.Permissions, Leverage: a.Leverage}.Validate()
+}
+
+// Can reports whether the account's API key holds permission
+func (a Account) Can(permission Permission) bool {
+	for _, granted := range a.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Template is what every account provisioned from it starts with
+type Template struct {
+	Tier        string             `json:"tier"`
+	Metadata    map[string]string  `json:"metadata"`
+	Balances    map[string]float64 `json:"balances"`
+	Permissions []Permission       `json:"permissions"`
+	Leverage    float64            `json:"leverage"`
+}
+
+// Validate checks the tier, metadata, balances, permissions and leverage
+func (t Template) Validate() error {
+	if t.Tier == "" || len(t.Tier) > maxTierLength {
+		return fmt.Errorf("tier must be 1 to %d characters", maxTierLength)
+	}
+	if len(t.Metadata) > maxMetadataKeys {
+		return fmt.Errorf("metadata holds at most %d keys", maxMetadataKeys)
+	}
+	for key, value := range t.Metadata {
+		if key == "" || len(key) > maxMetadataSize || len(value) > maxMetadataSize {
+			return fmt.Errorf("metadata keys must be 1 to %d characters and values at most %d", maxMetadataSize, maxMetadataSize)
+		}
+	}
+	for asset, amount := range t.Balances {
+		if asset == "" || amount < 0 {
+			return fmt.Errorf("opening balance of %q must not be negative", asset)
+		}
+	}
+	if err := validatePermissions(t.Permissions); err != nil {
+		return err
+	}
+	if t.Leverage != 0 && (t.Leverage < 1 || t.Leverage > maxLeverage) {
+		return fmt.Errorf("leverage must be 0 (instrument maximum) or between 1 and %d", maxLeverage)
+	}
+	return nil
+}
+
+func validatePermissions(permissions []Permission) error {
+	for _, permission := range permissions {
+		if permission != PermissionRead && permission != PermissionTrade && permission != PermissionWithdraw {
+			return fmt.Errorf("unknown permission %q", permission)
+		}
+	}
+	return nil
+}
+
+// Store persists accounts across restarts
+type Store interface {
+	Save(batch ...Account) error // Inserts or replaces by ID, all or none
+	Delete(id string) error      // Deleting an unknown ID is not an error
+	Load() ([]Account, error)
+}
+
+// NewID generates a random (version 4) UUID for a new account
+func NewID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate account id: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// NewAPIKey generates a random API key; only its hash is kept
+func NewAPIKey() (string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return "sk_" + hex.EncodeToString(b[:]), nil
+}
+
+// HashAPIKey is how an API key is recognised without storing it
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Directory holds the accounts opened on the venue. Accounts never opened through it
+// are unknown to it, not closed, so they keep trading.
+type Directory struct {
+	accounts map[string]Account
+	byKey    map[string]string // API key hash to account ID
+	byKeyID  map[string]string // Key ID to account ID, for keys issued through the key API
+	mu       sync.RWMutex
+}
+
+func NewDirectory() *Directory {
+	return &Directory{accounts: make(map[string]Account), byKey: make(map[string]string), byKeyID: make(map[string]string)}
+}
+
+// Get returns an account by ID
+func (d *Directory) Get(id string) (Account, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	account, ok := d.accounts[id]
+	if !ok {
+		return Account{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return account, nil
+}
+
+// Put adds or replaces an account
+func (d *Directory) Put(account Account) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.accounts[account.ID] = account
+	if account.APIKeyHash != "" {
+		d.byKey[account.APIKeyHash] = account.ID
+	}
+	for _, key := range account.Keys {
+		d.byKeyID[key.ID] = account.ID
+	}
+}
+
+// ByAPIKey returns the account an API key was issued to
+func (d *Directory) ByAPIKey(key string) (Account, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	id, ok := d.byKey[HashAPIKey(key)]
+	if !ok {
+		return Account{}, false
+	}
+	return d.accounts[id], true
+}
+
+// ByKeyID returns a key issued through the key API, revoked or not, and its account
+func (d *Directory) ByKeyID(id string) (Account, Key, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	account := d.accounts[d.byKeyID[id]]
+	for _, key := range account.Keys {
+		if key.ID == id {
+			return account, key, true
+		}
+	}
+	return Account{}, Key{}, false
+}
+
+// Remove forgets an account, reporting whether it was known
+func (d *Directory) Remove(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	account, ok := d.accounts[id]
+	delete(d.accounts, id)
+	delete(d.byKey, account.APIKeyHash)
+	for _, key := range account.Keys {
+		delete(d.byKeyID, key.ID)
+	}
+	return ok
+}
+
+// IsClosed reports whether id was opened on the venue and has since been closed
+func (d *Directory) IsClosed(id string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.accounts[id].Status == StatusClosed
+}
+
+// List returns the accounts matching the filter, oldest first; empty fields match anything
+func (d *Directory) List(status Status, tier string) []Account {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	list := make([]Account, 0, len(d.accounts))
+	for _, account := range d.accounts {
+		if (status == "" || account.Status == status) && (tier == "" || account.Tier == tier) {
+			list = append(list, account)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,108 @@
+//go:build unit
+
+package accounts
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDirectory(t *testing.T) {
+	t.Run("generates_distinct_version_4_uuids", func(t *testing.T) {
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		seen := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			id, err := NewID()
+			if err != nil || !uuid.MatchString(id) || seen[id] {
+				t.Fatalf("Expected a fresh v4 UUID, got %q, %v", id, err)
+			}
+			seen[id] = true
+		}
+	})
+
+	t.Run("lists_by_status_and_tier_oldest_first", func(t *testing.T) {
+		// Given: Three accounts, one closed
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		directory := NewDirectory()
+		directory.Put(Account{ID: "c", Tier: "vip", Status: StatusActive, CreatedAt: start.Add(time.Minute)})
+		directory.Put(Account{ID: "a", Tier: DefaultTier, Status: StatusActive, CreatedAt: start.Add(2 * time.Minute)})
+		directory.Put(Account{ID: "b", Tier: "vip", Status: StatusClosed, CreatedAt: start})
+
+		// When: Active accounts and vip accounts are listed
+		active := directory.List(StatusActive, "")
+		vip := directory.List("", "vip")
+
+		// Then: Each list is filtered and ordered by creation
+		if len(active) != 2 || active[0].ID != "c" || active[1].ID != "a" {
+			t.Errorf("Expected c then a, got %+v", active)
+		}
+		if len(vip) != 2 || vip[0].ID != "b" || vip[1].ID != "c" {
+			t.Errorf("Expected b then c, got %+v", vip)
+		}
+		if !directory.IsClosed("b") || directory.IsClosed("a") || directory.IsClosed("never-opened") {
+			t.Error("Expected only b to be closed")
+		}
+	})
+
+	t.Run("bounds_tier_metadata_and_leverage", func(t *testing.T) {
+		if err := (Account{Tier: ""}).Validate(); err == nil {
+			t.Error("Expected an empty tier to be rejected")
+		}
+		if err := (Account{Tier: "vip", Metadata: map[string]string{"desk": strings.Repeat("x", 257)}}).Validate(); err == nil {
+			t.Error("Expected an oversized metadata value to be rejected")
+		}
+		if err := (Account{Tier: "vip", Leverage: 0.5}).Validate(); err == nil {
+			t.Error("Expected leverage below 1 to be rejected")
+		}
+		if err := (Account{Tier: "vip", Metadata: map[string]string{"desk": "rates"}, Leverage: 10}).Validate(); err != nil {
+			t.Errorf("Expected a valid account, got %v", err)
+		}
+	})
+}
+
+func TestKey(t *testing.T) {
+	t.Run("signs_and_verifies_payloads_under_its_secret", func(t *testing.T) {
+		key, err := NewKey("bot", []Permission{PermissionRead, PermissionWithdraw}, time.Unix(0, 0))
+		if err != nil || !IsKeyID(key.ID) || key.Secret == "" {
+			t.Fatalf("Expected a key with an ak_ ID and a secret, got %+v, %v", key, err)
+		}
+		payload := SigningPayload("1700000000000", "GET", "/api/v1/accounts/a/balances", nil)
+		signature := key.Sign(payload)
+
+		if !key.Verify(payload, signature) {
+			t.Error("Expected the key's own signature to verify")
+		}
+		if key.Verify(payload+"x", signature) || key.Verify(payload, "zz") {
+			t.Error("Expected a tampered payload or malformed signature to fail")
+		}
+		if !key.Can(PermissionWithdraw) || key.Can(PermissionTrade) {
+			t.Errorf("Expected only the granted permissions, got %v", key.Permissions)
+		}
+	})
+
+	t.Run("rejects_unknown_permissions", func(t *testing.T) {
+		if _, err := NewKey("", []Permission{"admin"}, time.Unix(0, 0)); err == nil {
+			t.Error("Expected an unknown permission to be rejected")
+		}
+		if _, err := NewKey("", nil, time.Unix(0, 0)); err == nil {
+			t.Error("Expected a key without permissions to be rejected")
+		}
+	})
+
+	t.Run("directory_finds_accounts_by_key_id", func(t *testing.T) {
+		key, _ := NewKey("", DefaultPermissions, time.Unix(0, 0))
+		directory := NewDirectory()
+		directory.Put(Account{ID: "a", Tier: "standard", Keys: []Key{key}})
+
+		account, found, ok := directory.ByKeyID(key.ID)
+		if !ok || account.ID != "a" || found.Secret != key.Secret {
+			t.Fatalf("Expected the key's account, got %+v, %+v, %v", account, found, ok)
+		}
+		directory.Remove("a")
+		if _, _, ok := directory.ByKeyID(key.ID); ok {
+			t.Error("Expected the key to go with its account")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package accounts
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrKeyNotFound is returned for a key ID the account was never issued
+var ErrKeyNotFound = errors.New("api key not found")
+
+// KeyIDPrefix starts the ID of every key issued through the key API, which tells
+// signed keys apart from unsigned ones
+const KeyIDPrefix = "ak_"
+
+const maxKeyLabelLength = 64
+
+// Key is one of an account's API keys. Its ID is sent with every request; its
+// secret signs the request and is never sent.
+type Key struct {
+	ID          string       `json:"key_id"`
+	Label       string       `json:"label,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	Secret      string       `json:"-"`
+	CreatedAt   time.Time    `json:"created_at"`
+	RevokedAt   *time.Time   `json:"revoked_at,omitempty"`
+}
+
+// NewKey issues a key with a random ID and secret
+func NewKey(label string, permissions []Permission, at time.Time) (Key, error) {
+	if len(label) > maxKeyLabelLength {
+		return Key{}, fmt.Errorf("label must be at most %d characters", maxKeyLabelLength)
+	}
+	if len(permissions) == 0 {
+		return Key{}, errors.New("a key needs at least one permission")
+	}
+	if err := validatePermissions(permissions); err != nil {
+		return Key{}, err
+	}
+	var id [12]byte
+	var secret [32]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return Key{}, fmt.Errorf("failed to generate key id: %w", err)
+	}
+	if _, err := rand.Read(secret[:]); err != nil {
+		return Key{}, fmt.Errorf("failed to generate key secret: %w", err)
+	}
+	return Key{
+		ID:          KeyIDPrefix + hex.EncodeToString(id[:]),
+		Label:       label,
+		Permissions: permissions,
+		Secret:      hex.EncodeToString(secret[:]),
+		CreatedAt:   at,
+	}, nil
+}
+
+// IsKeyID reports whether id has the form of a key issued through the key API
+func IsKeyID(id string) bool {
+	return strings.HasPrefix(id, KeyIDPrefix)
+}
+
+// Active reports whether the key has not been revoked
+func (k Key) Active() bool {
+	return k.RevokedAt == nil
+}
+
+// Can reports whether the key holds permission
+func (k Key) Can(permission Permission) bool {
+	for _, granted := range k.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the hex HMAC-SHA256 of payload under the key's secret
+func (k Key) Sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(k.Secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a hex signature of payload in constant time
+func (k Key) Verify(payload, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(k.Secret))
+	mac.Write([]byte(payload))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// SigningPayload is what a request signature covers: the timestamp, then the
+// method, path with query string and body as sent. gRPC calls sign their full
+// method name in place of the method and path, and their request message in
+// deterministic protobuf encoding as the body.
+func SigningPayload(timestamp, method, path string, body []byte) string {
+	return timestamp + method + path + string(body)
+}
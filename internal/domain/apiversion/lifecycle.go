@@ -0,0 +1,65 @@
+package apiversion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Phase is where an API version is in its lifecycle
+type Phase string
+
+const (
+	PhaseActive     Phase = "active"     // Served normally
+	PhaseDeprecated Phase = "deprecated" // Served with deprecation headers
+	PhaseRetired    Phase = "retired"    // Refused
+)
+
+// Lifecycle schedules an API version's deprecation and retirement on the venue
+// clock; zero times never arrive
+type Lifecycle struct {
+	Version      string    `json:"version"`
+	DeprecatedAt time.Time `json:"deprecated_at"`
+	SunsetAt     time.Time `json:"sunset_at"`           // Calls are refused from then on
+	Successor    string    `json:"successor,omitempty"` // Version clients should move to, e.g. "v2"
+}
+
+// Validate checks the version is named and retires no earlier than it is deprecated
+func (l Lifecycle) Validate() error {
+	if l.Version == "" {
+		return errors.New("version is required")
+	}
+	if !l.DeprecatedAt.IsZero() && !l.SunsetAt.IsZero() && l.SunsetAt.Before(l.DeprecatedAt) {
+		return fmt.Errorf("sunset %s is before deprecation %s", l.SunsetAt.Format(time.RFC3339), l.DeprecatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Phase is where the version is at now
+func (l Lifecycle) Phase(now time.Time) Phase {
+	switch {
+	case !l.SunsetAt.IsZero() && !now.Before(l.SunsetAt):
+		return PhaseRetired
+	case !l.DeprecatedAt.IsZero() && !now.Before(l.DeprecatedAt):
+		return PhaseDeprecated
+	}
+	return PhaseActive
+}
+
+// ParseTime reads a schedule time as RFC 3339 or as a duration after start, e.g.
+// "2024-01-01T09:30:00Z" or "15m"; empty is the zero time
+func ParseTime(spec string, start time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return time.Time{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339, spec); err == nil {
+		return at, nil
+	}
+	offset, err := time.ParseDuration(spec)
+	if err != nil || offset < 0 {
+		return time.Time{}, fmt.Errorf("invalid schedule time %q: expected RFC 3339 or a duration after start", spec)
+	}
+	return start.Add(offset), nil
+}
@@ -0,0 +1,52 @@
+//go:build unit
+
+package apiversion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLifecycle(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("moves_through_phases_on_the_clock", func(t *testing.T) {
+		// Given: v1 deprecated after ten minutes and retired after twenty
+		deprecatedAt, _ := ParseTime("10m", start)
+		sunsetAt, _ := ParseTime("2024-01-01T09:20:00Z", start)
+		lifecycle := Lifecycle{Version: "v1", DeprecatedAt: deprecatedAt, SunsetAt: sunsetAt, Successor: "v2"}
+
+		// When: Its phase is read before, at and after each time
+		phases := []Phase{
+			lifecycle.Phase(start),
+			lifecycle.Phase(start.Add(10 * time.Minute)),
+			lifecycle.Phase(start.Add(19 * time.Minute)),
+			lifecycle.Phase(start.Add(20 * time.Minute)),
+		}
+
+		// Then: It is active, deprecated from its deprecation time and retired from its sunset
+		expected := []Phase{PhaseActive, PhaseDeprecated, PhaseDeprecated, PhaseRetired}
+		for i := range expected {
+			if phases[i] != expected[i] {
+				t.Errorf("Phase %d: expected %s, got %s", i, expected[i], phases[i])
+			}
+		}
+		if err := lifecycle.Validate(); err != nil {
+			t.Errorf("Expected a valid lifecycle, got %v", err)
+		}
+	})
+
+	t.Run("rejects_sunset_before_deprecation", func(t *testing.T) {
+		lifecycle := Lifecycle{Version: "v1", DeprecatedAt: start.Add(time.Hour), SunsetAt: start}
+
+		if err := lifecycle.Validate(); err == nil {
+			t.Error("Expected a sunset before deprecation to be rejected")
+		}
+		if _, err := ParseTime("soon", start); err == nil {
+			t.Error("Expected an unreadable time to be rejected")
+		}
+		if at, err := ParseTime("", start); err != nil || !at.IsZero() {
+			t.Errorf("Expected an empty time to never arrive, got %v, %v", at, err)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Event types for the venue's order, trade and account mutations
+const (
+	TypeOrderPlaced   = "order.placed"
+	TypeOrderAmended  = "order.amended"
+	TypeOrderCanceled = "order.canceled"
+	TypeOrderExpired  = "order.expired"
+	TypeTradeExecuted = "trade.executed"
+	TypeDeposit       = "account.deposit"
+)
+
+// Event types flagging when the venue misbehaves on purpose, so reconciliation breaks
+// can be told apart from real ones
+const (
+	TypeMisbehaviorStarted = "venue.misbehavior_started"
+	TypeMisbehaviorEnded   = "venue.misbehavior_ended"
+	TypeMisbehaved         = "venue.misbehaved" // One falsified report, under the correlation ID of the request it answered
+)
+
+// Event is one state change the audit-correlator is told about. Events sharing a
+// correlation ID were caused by the same request.
+type Event struct {
+	ID            string            `json:"event_id"`
+	Type          string            `json:"event_type"`
+	Source        string            `json:"source"`
+	CorrelationID string            `json:"correlation_id"`
+	AccountID     string            `json:"account_id,omitempty"`
+	Symbol        string            `json:"symbol,omitempty"`
+	OrderID       string            `json:"order_id,omitempty"`
+	TradeID       string            `json:"trade_id,omitempty"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+type contextKey struct{}
+
+// WithCorrelationID tags a request context with the correlation ID its audit events carry
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, correlationID)
+}
+
+// CorrelationID returns the ID a request was tagged with, or "" when it was not
+func CorrelationID(ctx context.Context) string {
+	correlationID, _ := ctx.Value(contextKey{}).(string)
+	return correlationID
+}
+
+// NewID generates a random event or correlation ID
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// Unique enough within one process when the system's randomness fails
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// drainTimeout bounds the last delivery attempt made at shutdown
+const drainTimeout = 5 * time.Second
+
+// Sink delivers batches of events, e.g. the audit-correlator client
+type Sink interface {
+	SubmitAuditEvents(ctx context.Context, events []Event) error
+}
+
+// Config sizes the pipeline between the venue and its sink
+type Config struct {
+	BufferSize    int           // Events held awaiting delivery; beyond it new events are dropped
+	BatchSize     int           // Most events sent in one call
+	FlushInterval time.Duration // Longest an event waits for its batch to fill, and the first retry delay
+	MaxAttempts   int           // Calls made for a batch before its events are given up as failed
+}
+
+// DefaultConfig buffers ten thousand events and sends them a hundred at a time
+func DefaultConfig() Config {
+	return Config{BufferSize: 10000, BatchSize: 100, FlushInterval: time.Second, MaxAttempts: 5}
+}
+
+// Stats counts the events that passed through the pipeline
+type Stats struct {
+	Published int64  `json:"published"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"` // Refused because the buffer was full
+	Failed    int64  `json:"failed"`  // Given up after MaxAttempts failed calls
+	Pending   int    `json:"pending"` // Buffered, not yet sent
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Pipeline buffers events so the venue never waits on its sink: publishing never
+// blocks, and a worker delivers the buffer in batches, retrying failed calls with
+// exponential backoff
+type Pipeline struct {
+	sink    Sink
+	config  Config
+	metrics ports.MetricsPort
+	events  chan Event
+
+	published atomic.Int64
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+
+	mu        sync.Mutex
+	lastError string
+}
+
+// NewPipeline accepts a nil metrics port when metrics are disabled; zero config
+// fields take their defaults
+func NewPipeline(sink Sink, config Config, metrics ports.MetricsPort) *Pipeline {
+	defaults := DefaultConfig()
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaults.BufferSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	return &Pipeline{
+		sink:    sink,
+		config:  config,
+		metrics: metrics,
+		events:  make(chan Event, config.BufferSize),
+	}
+}
+
+// Publish queues an event for delivery, dropping it when the buffer is full
+func (p *Pipeline) Publish(event Event) bool {
+	select {
+	case p.events <- event:
+		p.published.Add(1)
+		p.count("audit_events_published_total", event)
+		return true
+	default:
+		p.dropped.Add(1)
+		p.count("audit_events_dropped_total", event)
+		return false
+	}
+}
+
+// Stats returns the pipeline's counts so far
+func (p *Pipeline) Stats() Stats {
+	p.mu.Lock()
+	lastError := p.lastError
+	p.mu.Unlock()
+	return Stats{
+		Published: p.published.Load(),
+		Delivered: p.delivered.Load(),
+		Dropped:   p.dropped.Load(),
+		Failed:    p.failed.Load(),
+		Pending:   len(p.events),
+		LastError: lastError,
+	}
+}
+
+// Run delivers buffered events until ctx is done, then makes one last attempt at
+// whatever is left. report, if set, is told the outcome of every call to the sink.
+func (p *Pipeline) Run(ctx context.Context, report func(error)) {
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, p.config.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			p.drain(batch, report)
+			return
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) < p.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if p.deliver(ctx, batch, report) {
+			batch = make([]Event, 0, p.config.BatchSize)
+		}
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff. It returns false when
+// ctx ended first, leaving the batch to the shutdown drain.
+func (p *Pipeline) deliver(ctx context.Context, batch []Event, report func(error)) bool {
+	backoff := p.config.FlushInterval
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return false
+		}
+		err := p.submit(ctx, batch, report)
+		if err == nil {
+			return true
+		}
+		if attempt >= p.config.MaxAttempts {
+			p.fail(batch)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// drain sends the unsent batch and everything still buffered once, in batches
+func (p *Pipeline) drain(batch []Event, report func(error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for {
+		for len(batch) < p.config.BatchSize && len(p.events) > 0 {
+			batch = append(batch, <-p.events)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.submit(ctx, batch, report); err != nil {
+			p.fail(batch)
+		}
+		batch = make([]Event, 0, p.config.BatchSize)
+	}
+}
+
+func (p *Pipeline) submit(ctx context.Context, batch []Event, report func(error)) error {
+	err := p.sink.SubmitAuditEvents(ctx, batch)
+	if report != nil {
+		report(err)
+	}
+	if err != nil {
+		p.mu.Lock()
+		p.lastError = err.Error()
+		p.mu.Unlock()
+		return err
+	}
+	p.delivered.Add(int64(len(batch)))
+	for _, event := range batch {
+		p.count("audit_events_delivered_total", event)
+	}
+	return nil
+}
+
+func (p *Pipeline) fail(batch []Event) {
+	p.failed.Add(int64(len(batch)))
+	for _, event := range batch {
+		p.count("audit_events_failed_total", event)
+	}
+}
+
+func (p *Pipeline) count(counter string, event Event) {
+	if p.metrics != nil {
+		p.metrics.IncCounter(counter, map[string]string{"event_type": event.Type})
+	}
+}
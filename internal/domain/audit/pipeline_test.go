@@ -0,0 +1,153 @@
+//go:build unit
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]Event
+	fail    int // Calls to fail before succeeding
+}
+
+func (s *recordingSink) SubmitAuditEvents(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("audit-correlator unavailable")
+	}
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *recordingSink) delivered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivered := 0
+	for _, batch := range s.batches {
+		delivered += len(batch)
+	}
+	return delivered
+}
+
+func TestPipeline(t *testing.T) {
+	t.Run("drops_events_beyond_the_buffer", func(t *testing.T) {
+		// Given: A pipeline buffering two events with no worker running
+		pipeline := NewPipeline(&recordingSink{}, Config{BufferSize: 2}, nil)
+
+		// When: Three events are published
+		accepted := 0
+		for i := 0; i < 3; i++ {
+			if pipeline.Publish(Event{ID: NewID(), Type: TypeOrderPlaced}) {
+				accepted++
+			}
+		}
+
+		// Then: The third is dropped rather than blocking the caller
+		stats := pipeline.Stats()
+		if accepted != 2 || stats.Published != 2 || stats.Dropped != 1 || stats.Pending != 2 {
+			t.Errorf("Expected two published and one dropped, got %d accepted and %+v", accepted, stats)
+		}
+	})
+
+	t.Run("retries_failed_batches_then_delivers", func(t *testing.T) {
+		// Given: A sink failing its first call
+		sink := &recordingSink{fail: 1}
+		pipeline := NewPipeline(sink, Config{BatchSize: 2, FlushInterval: time.Millisecond}, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		failures := 0
+		go func() {
+			pipeline.Run(ctx, func(err error) {
+				if err != nil {
+					failures++
+				}
+			})
+			close(done)
+		}()
+
+		// When: A full batch is published
+		pipeline.Publish(Event{ID: "e-1", Type: TypeOrderPlaced})
+		pipeline.Publish(Event{ID: "e-2", Type: TypeTradeExecuted})
+		deadline := time.Now().Add(time.Second)
+		for sink.delivered() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		<-done
+
+		// Then: The retry delivers both events in one batch
+		stats := pipeline.Stats()
+		if stats.Delivered != 2 || stats.Failed != 0 || failures != 1 || stats.LastError == "" {
+			t.Errorf("Expected both events delivered after one failure, got %+v with %d failures", stats, failures)
+		}
+	})
+
+	t.Run("gives_up_after_max_attempts", func(t *testing.T) {
+		// Given: A sink failing more often than the pipeline retries
+		sink := &recordingSink{fail: 10}
+		pipeline := NewPipeline(sink, Config{BatchSize: 1, FlushInterval: time.Millisecond, MaxAttempts: 2}, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			pipeline.Run(ctx, nil)
+			close(done)
+		}()
+
+		// When: One event is published
+		pipeline.Publish(Event{ID: "e-1", Type: TypeOrderCanceled})
+		deadline := time.Now().Add(time.Second)
+		for pipeline.Stats().Failed == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		<-done
+
+		// Then: It is counted as failed rather than retried forever
+		if stats := pipeline.Stats(); stats.Failed != 1 || stats.Delivered != 0 {
+			t.Errorf("Expected the event to fail after two attempts, got %+v", stats)
+		}
+	})
+
+	t.Run("drains_the_buffer_at_shutdown", func(t *testing.T) {
+		// Given: Events buffered below a batch, with a long flush interval
+		sink := &recordingSink{}
+		pipeline := NewPipeline(sink, Config{BatchSize: 100, FlushInterval: time.Hour}, nil)
+		for i := 0; i < 3; i++ {
+			pipeline.Publish(Event{ID: NewID(), Type: TypeDeposit})
+		}
+
+		// When: The worker is stopped
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		pipeline.Run(ctx, nil)
+
+		// Then: The buffer is sent before it returns
+		if delivered := sink.delivered(); delivered != 3 {
+			t.Errorf("Expected three events drained at shutdown, got %d", delivered)
+		}
+	})
+}
+
+func TestCorrelationID(t *testing.T) {
+	t.Run("round_trips_through_the_context", func(t *testing.T) {
+		ctx := WithCorrelationID(context.Background(), "req-1")
+
+		if id := CorrelationID(ctx); id != "req-1" {
+			t.Errorf("Expected req-1, got %q", id)
+		}
+		if id := CorrelationID(context.Background()); id != "" {
+			t.Errorf("Expected no correlation ID on an untagged context, got %q", id)
+		}
+		if NewID() == NewID() {
+			t.Error("Expected distinct generated IDs")
+		}
+	})
+}
@@ -0,0 +1,308 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for a fault ID that is not active
+var ErrNotFound = errors.New("fault not found")
+
+// Kind is the venue behaviour a fault breaks
+type Kind string
+
+const (
+	KindRejectOrders    Kind = "reject_orders"    // Refuse a share of new orders
+	KindDelayFills      Kind = "delay_fills"      // Hold new orders back before they reach matching
+	KindDropMarketData  Kind = "drop_market_data" // Lose a share of public trade, ticker and book updates
+	KindCorruptBalances Kind = "corrupt_balances" // Post an unbalanced amount to an account, once
+	KindHaltSymbol      Kind = "halt_symbol"      // Halt symbols until the fault ends
+
+	// Misbehaviours: the venue keeps its books right but tells clients something else
+	KindOverfill          Kind = "overfill"           // Report orders filled beyond what traded
+	KindMisreportBalances Kind = "misreport_balances" // Report balances off by an amount
+	KindDuplicateReports  Kind = "duplicate_reports"  // Send order updates twice
+	KindStallCancels      Kind = "stall_cancels"      // Cancel orders but hold back the acknowledgement
+)
+
+// Misbehavior reports whether the kind falsifies what the venue tells clients rather
+// than breaking the venue itself
+func (k Kind) Misbehavior() bool {
+	switch k {
+	case KindOverfill, KindMisreportBalances, KindDuplicateReports, KindStallCancels:
+		return true
+	}
+	return false
+}
+
+// Spec describes a fault to inject
+type Spec struct {
+	Kind        Kind     `json:"kind"`
+	Symbols     []string `json:"symbols,omitempty"`      // Empty applies to every symbol; halt_symbol needs at least one
+	Rate        float64  `json:"rate,omitempty"`         // Share of orders or updates hit, above 0 up to 1; 0 means all
+	DelayMs     int64    `json:"delay_ms,omitempty"`     // delay_fills; stall_cancels, where 0 holds acknowledgements until the fault ends
+	Reason      string   `json:"reason,omitempty"`       // Message of orders rejected by reject_orders
+	AccountID   string   `json:"account_id,omitempty"`   // corrupt_balances; misreport_balances, where empty is every account
+	Asset       string   `json:"asset,omitempty"`        // corrupt_balances; misreport_balances, where empty is every asset
+	Amount      float64  `json:"amount,omitempty"`       // Added to the available balance by corrupt_balances, or to reported balances by misreport_balances; negative removes
+	OverfillPct float64  `json:"overfill_pct,omitempty"` // overfill: percent added to the filled quantity reported
+	DurationMs  int64    `json:"duration_ms,omitempty"`  // How long the fault lasts; 0 until cleared
+}
+
+// Validate checks the spec has what its kind needs
+func (s Spec) Validate() error {
+	if s.Rate < 0 || s.Rate > 1 {
+		return fmt.Errorf("rate must be between 0 and 1, got %v", s.Rate)
+	}
+	if s.DelayMs < 0 || s.DurationMs < 0 {
+		return errors.New("delay_ms and duration_ms must not be negative")
+	}
+	switch s.Kind {
+	case KindRejectOrders, KindDropMarketData:
+	case KindDelayFills:
+		if s.DelayMs == 0 {
+			return errors.New("delay_fills needs delay_ms")
+		}
+	case KindCorruptBalances:
+		if s.AccountID == "" || s.Asset == "" || s.Amount == 0 {
+			return errors.New("corrupt_balances needs account_id, asset and a non-zero amount")
+		}
+		if s.DurationMs != 0 {
+			return errors.New("corrupt_balances is applied once and takes no duration_ms")
+		}
+	case KindHaltSymbol:
+		if len(s.Symbols) == 0 {
+			return errors.New("halt_symbol needs symbols")
+		}
+	case KindOverfill:
+		if s.OverfillPct <= 0 {
+			return errors.New("overfill needs a positive overfill_pct")
+		}
+	case KindMisreportBalances:
+		if s.Amount == 0 {
+			return errors.New("misreport_balances needs a non-zero amount")
+		}
+	case KindDuplicateReports, KindStallCancels:
+	default:
+		return fmt.Errorf("unknown fault kind: %q", s.Kind)
+	}
+	return nil
+}
+
+// Fault is an injected fault
+type Fault struct {
+	Spec
+	ID         string     `json:"fault_id"`
+	InjectedAt time.Time  `json:"injected_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil lasts until cleared
+	Hits       int64      `json:"hits"`                 // Orders, updates, balance reports or cancels the fault hit
+}
+
+func (f *Fault) expired(now time.Time) bool {
+	return f.ExpiresAt != nil && !now.Before(*f.ExpiresAt)
+}
+
+// coversBalance reports whether a misreport_balances fault applies to an account's asset
+func (f *Fault) coversBalance(accountID, asset string) bool {
+	return (f.AccountID == "" || f.AccountID == accountID) && (f.Asset == "" || f.Asset == asset)
+}
+
+func (f *Fault) covers(symbol string) bool {
+	if len(f.Symbols) == 0 {
+		return true
+	}
+	for _, covered := range f.Symbols {
+		if covered == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// Injector holds the active faults and decides which orders and updates they hit.
+// Draws come from one seeded source, so a run with the same seed and order flow hits
+// the same orders.
+type Injector struct {
+	faults map[string]*Fault
+	nextID uint64
+	rng    *rand.Rand
+	mu     sync.Mutex
+}
+
+func NewInjector(seed int64) *Injector {
+	return &Injector{faults: make(map[string]*Fault), rng: rand.New(rand.NewSource(seed))}
+}
+
+// Inject validates spec and activates it from now. Corrupting balances is applied
+// once by the caller, so it is returned without being kept.
+func (i *Injector) Inject(spec Spec, now time.Time) (Fault, error) {
+	if err := spec.Validate(); err != nil {
+		return Fault{}, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.nextID++
+	fault := &Fault{Spec: spec, ID: fmt.Sprintf("fault-%d", i.nextID), InjectedAt: now}
+	if spec.DurationMs > 0 {
+		expiresAt := now.Add(time.Duration(spec.DurationMs) * time.Millisecond)
+		fault.ExpiresAt = &expiresAt
+	}
+	if spec.Kind == KindCorruptBalances {
+		fault.Hits = 1
+		return *fault, nil
+	}
+	i.faults[fault.ID] = fault
+	return *fault, nil
+}
+
+// Clear ends a fault early
+func (i *Injector) Clear(id string) (Fault, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	fault, ok := i.faults[id]
+	if !ok {
+		return Fault{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(i.faults, id)
+	return *fault, nil
+}
+
+// Expire removes and returns the faults that have run their course
+func (i *Injector) Expire(now time.Time) []Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	expired := make([]Fault, 0)
+	for id, fault := range i.faults {
+		if fault.expired(now) {
+			expired = append(expired, *fault)
+			delete(i.faults, id)
+		}
+	}
+	sortFaults(expired)
+	return expired
+}
+
+// Active lists the faults in force at now, oldest first
+func (i *Injector) Active(now time.Time) []Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	active := make([]Fault, 0, len(i.faults))
+	for _, fault := range i.faults {
+		if !fault.expired(now) {
+			active = append(active, *fault)
+		}
+	}
+	sortFaults(active)
+	return active
+}
+
+// Reject returns the fault refusing an order for symbol, if one draws it
+func (i *Injector) Reject(symbol string, now time.Time) (Fault, bool) {
+	return i.hit(KindRejectOrders, symbol, now)
+}
+
+// Drop reports whether a market data update for symbol is lost
+func (i *Injector) Drop(symbol string, now time.Time) bool {
+	_, dropped := i.hit(KindDropMarketData, symbol, now)
+	return dropped
+}
+
+// Overfill returns the fault over-reporting the fills of an order for symbol, if one
+// draws it
+func (i *Injector) Overfill(symbol string, now time.Time) (Fault, bool) {
+	return i.hit(KindOverfill, symbol, now)
+}
+
+// Duplicate returns the fault sending an order update for symbol twice, if one draws it
+func (i *Injector) Duplicate(symbol string, now time.Time) (Fault, bool) {
+	return i.hit(KindDuplicateReports, symbol, now)
+}
+
+// Stall returns the fault holding back the acknowledgement of a cancel for symbol, if
+// one draws it
+func (i *Injector) Stall(symbol string, now time.Time) (Fault, bool) {
+	return i.hit(KindStallCancels, symbol, now)
+}
+
+// Misreport returns what to add to the balance of an account's asset as reported:
+// the sum of the misreport_balances faults covering it that draw this report
+func (i *Injector) Misreport(accountID, asset string, now time.Time) (float64, []Fault) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var amount float64
+	var hit []Fault
+	for _, fault := range i.matching(KindMisreportBalances, "", now) {
+		if fault.coversBalance(accountID, asset) && i.draw(fault) {
+			fault.Hits++
+			amount += fault.Amount
+			hit = append(hit, *fault)
+		}
+	}
+	return amount, hit
+}
+
+// Delay returns how long an order for symbol is held back: the longest delay among
+// the faults that draw it
+func (i *Injector) Delay(symbol string, now time.Time) time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var delay time.Duration
+	for _, fault := range i.matching(KindDelayFills, symbol, now) {
+		if i.draw(fault) {
+			fault.Hits++
+			if d := time.Duration(fault.DelayMs) * time.Millisecond; d > delay {
+				delay = d
+			}
+		}
+	}
+	return delay
+}
+
+func (i *Injector) hit(kind Kind, symbol string, now time.Time) (Fault, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, fault := range i.matching(kind, symbol, now) {
+		if i.draw(fault) {
+			fault.Hits++
+			return *fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// matching returns the active faults of kind covering symbol in injection order, or
+// every one of kind when symbol is empty; hold mu
+func (i *Injector) matching(kind Kind, symbol string, now time.Time) []*Fault {
+	if len(i.faults) == 0 {
+		return nil
+	}
+	matched := make([]*Fault, 0)
+	for _, fault := range i.faults {
+		if fault.Kind == kind && !fault.expired(now) && (symbol == "" || fault.covers(symbol)) {
+			matched = append(matched, fault)
+		}
+	}
+	sort.Slice(matched, func(a, b int) bool { return idLess(matched[a].ID, matched[b].ID) })
+	return matched
+}
+
+// draw decides whether a fault hits; hold mu
+func (i *Injector) draw(fault *Fault) bool {
+	return fault.Rate == 0 || fault.Rate >= 1 || i.rng.Float64() < fault.Rate
+}
+
+func sortFaults(faults []Fault) {
+	sort.Slice(faults, func(a, b int) bool { return idLess(faults[a].ID, faults[b].ID) })
+}
+
+// idLess orders fault IDs by the sequence number they were issued with
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
@@ -0,0 +1,144 @@
+//go:build unit
+
+package chaos
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInjector(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	t.Run("hits_the_configured_share_reproducibly", func(t *testing.T) {
+		// Given: Two injectors with the same seed rejecting 30% of BTC-USD orders
+		draws := func() (hits int, pattern []bool) {
+			injector := NewInjector(42)
+			if _, err := injector.Inject(Spec{Kind: KindRejectOrders, Symbols: []string{"BTC-USD"}, Rate: 0.3}, now); err != nil {
+				t.Fatalf("Expected the fault injected, got %v", err)
+			}
+			for i := 0; i < 1000; i++ {
+				_, rejected := injector.Reject("BTC-USD", now)
+				pattern = append(pattern, rejected)
+				if rejected {
+					hits++
+				}
+			}
+			if _, rejected := injector.Reject("ETH-USD", now); rejected {
+				t.Error("Expected other symbols untouched")
+			}
+			return hits, pattern
+		}
+
+		// When: Each sees the same order flow
+		hits, first := draws()
+		_, second := draws()
+
+		// Then: About 30% are hit, the same ones both times
+		if hits < 250 || hits > 350 {
+			t.Errorf("Expected about 300 rejections, got %d", hits)
+		}
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("Expected identical draws, diverged at %d", i)
+			}
+		}
+	})
+
+	t.Run("expires_and_clears_faults", func(t *testing.T) {
+		// Given: A delay that lasts a minute and a drop until cleared
+		injector := NewInjector(1)
+		delay, _ := injector.Inject(Spec{Kind: KindDelayFills, DelayMs: 250, DurationMs: 60000}, now)
+		drop, _ := injector.Inject(Spec{Kind: KindDropMarketData}, now)
+
+		// When: The minute passes and the drop is cleared
+		during := injector.Delay("BTC-USD", now.Add(time.Second))
+		expired := injector.Expire(now.Add(time.Minute))
+		_, err := injector.Clear(drop.ID)
+		_, again := injector.Clear(drop.ID)
+
+		// Then: Orders were held while the delay lasted, and nothing is left
+		if during != 250*time.Millisecond || len(expired) != 1 || expired[0].ID != delay.ID || expired[0].Hits != 1 {
+			t.Errorf("Expected a 250ms delay that expired after one hit, got %v and %+v", during, expired)
+		}
+		if err != nil || !errors.Is(again, ErrNotFound) || len(injector.Active(now)) != 0 {
+			t.Errorf("Expected the drop cleared once, got %v then %v", err, again)
+		}
+	})
+
+	t.Run("validates_specs", func(t *testing.T) {
+		injector := NewInjector(1)
+		for _, spec := range []Spec{
+			{Kind: "meteor_strike"},
+			{Kind: KindRejectOrders, Rate: 1.5},
+			{Kind: KindDelayFills},
+			{Kind: KindHaltSymbol},
+			{Kind: KindCorruptBalances, AccountID: "a", Asset: "USD"},
+			{Kind: KindCorruptBalances, AccountID: "a", Asset: "USD", Amount: 1, DurationMs: 10},
+			{Kind: KindOverfill},
+			{Kind: KindMisreportBalances, AccountID: "a"},
+		} {
+			if _, err := injector.Inject(spec, now); err == nil {
+				t.Errorf("Expected %+v to be refused", spec)
+			}
+		}
+		corrupt, err := injector.Inject(Spec{Kind: KindCorruptBalances, AccountID: "a", Asset: "USD", Amount: -5}, now)
+		if err != nil || corrupt.Hits != 1 || len(injector.Active(now)) != 0 {
+			t.Errorf("Expected corruption applied once and not kept, got %+v, %v", corrupt, err)
+		}
+	})
+
+	t.Run("misreports_only_the_balances_covered", func(t *testing.T) {
+		// Given: Faults misreporting account a's USD and every account's BTC
+		injector := NewInjector(1)
+		injector.Inject(Spec{Kind: KindMisreportBalances, AccountID: "a", Asset: "USD", Amount: 100}, now)
+		injector.Inject(Spec{Kind: KindMisreportBalances, Asset: "BTC", Amount: -0.5}, now)
+
+		// When: Balances of two accounts are reported
+		usd, usdFaults := injector.Misreport("a", "USD", now)
+		otherUSD, _ := injector.Misreport("b", "USD", now)
+		btc, _ := injector.Misreport("b", "BTC", now)
+
+		// Then: Each fault falsifies only what it covers
+		if usd != 100 || len(usdFaults) != 1 || otherUSD != 0 || btc != -0.5 {
+			t.Errorf("Expected +100 USD for a only and -0.5 BTC for anyone, got %v, %v and %v", usd, otherUSD, btc)
+		}
+		if !KindStallCancels.Misbehavior() || KindCorruptBalances.Misbehavior() {
+			t.Error("Expected stalled cancels to be a misbehaviour and corrupt balances not")
+		}
+	})
+}
+
+func TestMode(t *testing.T) {
+	t.Run("serves_what_each_mode_allows", func(t *testing.T) {
+		// Given: Every mode by name
+		expected := map[Mode][3]bool{ // read, cancel, change
+			ModeNormal:          {true, true, true},
+			ModeReadOnly:        {true, false, false},
+			ModeCancelOnly:      {true, true, false},
+			ModeBlackout:        {false, false, false},
+			ModeStaleMarketData: {true, true, true},
+		}
+
+		for _, name := range []string{"normal", "read_only", "cancel_only", "blackout", "stale_market_data"} {
+			// When: The mode is parsed and asked about each operation
+			mode, err := ParseMode(name)
+			if err != nil {
+				t.Fatalf("Expected %s parsed, got %v", name, err)
+			}
+			served := [3]bool{mode.Allows(OperationRead), mode.Allows(OperationCancel), mode.Allows(OperationChange)}
+
+			// Then: It serves exactly its operations, freezing market data while stale or dark
+			if served != expected[mode] {
+				t.Errorf("Expected %s to serve %v, got %v", mode, expected[mode], served)
+			}
+			if frozen := mode == ModeStaleMarketData || mode == ModeBlackout; mode.FreezesMarketData() != frozen {
+				t.Errorf("Expected %s freezing market data to be %v", mode, frozen)
+			}
+		}
+		if _, err := ParseMode("maintenance"); err == nil {
+			t.Error("Expected an unknown mode refused")
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package chaos
+
+import "fmt"
+
+// Mode is a venue-wide partial outage, switched at runtime
+type Mode string
+
+const (
+	ModeNormal          Mode = "normal"
+	ModeReadOnly        Mode = "read_only"         // Every change refused; reads served
+	ModeCancelOnly      Mode = "cancel_only"       // Cancels accepted; new orders, amends and other changes refused
+	ModeBlackout        Mode = "blackout"          // Every request refused, reads included
+	ModeStaleMarketData Mode = "stale_market_data" // Trading goes on while public market data stays as it was
+)
+
+// Modes lists every mode, normal first
+var Modes = []Mode{ModeNormal, ModeReadOnly, ModeCancelOnly, ModeBlackout, ModeStaleMarketData}
+
+// ParseMode reads a mode by name
+func ParseMode(name string) (Mode, error) {
+	for _, mode := range Modes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown mode %q: expected normal, read_only, cancel_only, blackout or stale_market_data", name)
+}
+
+// Operation is what a request does, as far as a mode is concerned
+type Operation string
+
+const (
+	OperationRead   Operation = "read"
+	OperationCancel Operation = "cancel"
+	OperationChange Operation = "change" // New orders, amends and any other change
+)
+
+// Allows reports whether the mode serves op
+func (m Mode) Allows(op Operation) bool {
+	switch m {
+	case ModeReadOnly:
+		return op == OperationRead
+	case ModeCancelOnly:
+		return op == OperationRead || op == OperationCancel
+	case ModeBlackout:
+		return false
+	}
+	return true
+}
+
+// Trading reports whether new orders are accepted
+func (m Mode) Trading() bool {
+	return m.Allows(OperationChange)
+}
+
+// FreezesMarketData reports whether public market data stops moving
+func (m Mode) FreezesMarketData() bool {
+	return m == ModeStaleMarketData || m == ModeBlackout
+}
@@ -0,0 +1,213 @@
+package entitlements
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrStreamLimit refuses a stream beyond the API key's open stream limit
+	ErrStreamLimit = errors.New("stream limit reached")
+	// ErrSubscriptionLimit refuses a subscription beyond the API key's limit across its streams
+	ErrSubscriptionLimit = errors.New("subscription limit reached")
+)
+
+// Limits caps what one API key may hold open at once; zero limits are not enforced
+type Limits struct {
+	MaxStreams       int `json:"max_streams"`
+	MaxSubscriptions int `json:"max_subscriptions"`
+}
+
+// Usage is what one API key holds open against its limits
+type Usage struct {
+	APIKey        string `json:"api_key"`
+	Streams       int    `json:"streams"`
+	Subscriptions int    `json:"subscriptions"`
+	Limits
+}
+
+type keyUsage struct {
+	streams       int
+	subscriptions int
+}
+
+// Registry counts the streams and subscriptions each API key holds open and
+// refuses those beyond its limits. Keys without their own limits get the defaults.
+type Registry struct {
+	defaults Limits
+	byKey    map[string]Limits
+	usage    map[string]*keyUsage // Dropped once a key closes its last stream
+	mu       sync.Mutex
+}
+
+func NewRegistry(defaults Limits) *Registry {
+	return &Registry{
+		defaults: defaults,
+		byKey:    make(map[string]Limits),
+		usage:    make(map[string]*keyUsage),
+	}
+}
+
+// SetLimits gives apiKey its own limits. Streams and subscriptions already open
+// are kept; only new ones are checked.
+func (r *Registry) SetLimits(apiKey string, limits Limits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byKey[apiKey] = limits
+}
+
+// Open starts a stream for apiKey, failing with ErrStreamLimit when it already
+// holds its limit. Close the lease when the stream ends.
+func (r *Registry) Open(apiKey string) (*Lease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limits := r.limits(apiKey)
+	usage := r.usage[apiKey]
+	if usage == nil {
+		usage = &keyUsage{}
+	}
+	if limits.MaxStreams > 0 && usage.streams >= limits.MaxStreams {
+		return nil, fmt.Errorf("%w: api key %s may hold %d open streams", ErrStreamLimit, apiKey, limits.MaxStreams)
+	}
+	usage.streams++
+	r.usage[apiKey] = usage
+	return &Lease{registry: r, apiKey: apiKey, topics: make(map[string]bool)}, nil
+}
+
+// Usage reports what apiKey holds open and its limits
+func (r *Registry) Usage(apiKey string) Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(apiKey)
+}
+
+// All reports every key holding a stream open or with its own limits, sorted by key
+func (r *Registry) All() []Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make(map[string]bool, len(r.usage)+len(r.byKey))
+	for apiKey := range r.usage {
+		keys[apiKey] = true
+	}
+	for apiKey := range r.byKey {
+		keys[apiKey] = true
+	}
+	all := make([]Usage, 0, len(keys))
+	for apiKey := range keys {
+		all = append(all, r.snapshot(apiKey))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].APIKey < all[j].APIKey })
+	return all
+}
+
+func (r *Registry) limits(apiKey string) Limits {
+	if limits, ok := r.byKey[apiKey]; ok {
+		return limits
+	}
+	return r.defaults
+}
+
+func (r *Registry) snapshot(apiKey string) Usage {
+	usage := Usage{APIKey: apiKey, Limits: r.limits(apiKey)}
+	if held := r.usage[apiKey]; held != nil {
+		usage.Streams, usage.Subscriptions = held.streams, held.subscriptions
+	}
+	return usage
+}
+
+// Lease is one open stream and the topics subscribed on it
+type Lease struct {
+	registry *Registry
+	apiKey   string
+	topics   map[string]bool
+	closed   bool
+}
+
+// APIKey is the key the stream counts against
+func (l *Lease) APIKey() string {
+	return l.apiKey
+}
+
+// Subscribe counts topic against the key's subscription limit, failing with
+// ErrSubscriptionLimit when it is reached. A topic already held is not counted twice.
+func (l *Lease) Subscribe(topic string) error {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l.closed || l.topics[topic] {
+		return nil
+	}
+	limits := r.limits(l.apiKey)
+	usage := r.usage[l.apiKey]
+	if limits.MaxSubscriptions > 0 && usage.subscriptions >= limits.MaxSubscriptions {
+		return fmt.Errorf("%w: api key %s may hold %d subscriptions", ErrSubscriptionLimit, l.apiKey, limits.MaxSubscriptions)
+	}
+	l.topics[topic] = true
+	usage.subscriptions++
+	return nil
+}
+
+// Unsubscribe releases topic, if held
+func (l *Lease) Unsubscribe(topic string) {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l.closed || !l.topics[topic] {
+		return
+	}
+	delete(l.topics, topic)
+	r.usage[l.apiKey].subscriptions--
+}
+
+// Close releases the stream and every topic on it; closing twice is harmless
+func (l *Lease) Close() {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l.closed {
+		return
+	}
+	l.closed = true
+	usage := r.usage[l.apiKey]
+	usage.streams--
+	usage.subscriptions -= len(l.topics)
+	if usage.streams == 0 {
+		delete(r.usage, l.apiKey)
+	}
+}
+
+// ParseLimits reads per-key limits in the STREAM_KEY_LIMITS form
+// "api_key=max_streams:max_subscriptions,...", e.g. "bot-1=2:10,bot-2=1:0"
+func ParseLimits(spec string) (map[string]Limits, error) {
+	limits := make(map[string]Limits)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		apiKey, values, ok := strings.Cut(entry, "=")
+		streams, subscriptions, valid := strings.Cut(values, ":")
+		if !ok || !valid || strings.TrimSpace(apiKey) == "" {
+			return nil, fmt.Errorf("invalid stream limits %q: expected api_key=max_streams:max_subscriptions", entry)
+		}
+		maxStreams, err := strconv.Atoi(strings.TrimSpace(streams))
+		if err != nil || maxStreams < 0 {
+			return nil, fmt.Errorf("invalid stream limit in %q", entry)
+		}
+		maxSubscriptions, err := strconv.Atoi(strings.TrimSpace(subscriptions))
+		if err != nil || maxSubscriptions < 0 {
+			return nil, fmt.Errorf("invalid subscription limit in %q", entry)
+		}
+		limits[strings.TrimSpace(apiKey)] = Limits{MaxStreams: maxStreams, MaxSubscriptions: maxSubscriptions}
+	}
+	return limits, nil
+}
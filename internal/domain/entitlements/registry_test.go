@@ -0,0 +1,106 @@
+//go:build unit
+
+package entitlements
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("limits_streams_and_subscriptions_per_key", func(t *testing.T) {
+		// Given: Keys limited to two streams and three subscriptions
+		registry := NewRegistry(Limits{MaxStreams: 2, MaxSubscriptions: 3})
+		first, err := registry.Open("bot")
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		second, err := registry.Open("bot")
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		// When: The key opens a third stream and subscribes across the two it holds
+		_, streamErr := registry.Open("bot")
+		for _, topic := range []string{"trades:BTC-USD", "book:BTC-USD"} {
+			if err := first.Subscribe(topic); err != nil {
+				t.Fatalf("Subscribe %s failed: %v", topic, err)
+			}
+		}
+		first.Subscribe("trades:BTC-USD")
+		if err := second.Subscribe("ticker:ETH-USD"); err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		subscriptionErr := second.Subscribe("trades:ETH-USD")
+
+		// Then: Both limits are enforced across streams, and other keys are unaffected
+		if !errors.Is(streamErr, ErrStreamLimit) {
+			t.Errorf("Expected ErrStreamLimit, got %v", streamErr)
+		}
+		if !errors.Is(subscriptionErr, ErrSubscriptionLimit) {
+			t.Errorf("Expected ErrSubscriptionLimit, got %v", subscriptionErr)
+		}
+		usage := registry.Usage("bot")
+		if usage.Streams != 2 || usage.Subscriptions != 3 || usage.MaxStreams != 2 || usage.MaxSubscriptions != 3 {
+			t.Errorf("Unexpected usage: %+v", usage)
+		}
+		if _, err := registry.Open("other"); err != nil {
+			t.Errorf("Expected another key to open a stream, got %v", err)
+		}
+	})
+
+	t.Run("closing_releases_what_the_stream_held", func(t *testing.T) {
+		// Given: A key with one subscription on each of two streams
+		registry := NewRegistry(Limits{MaxStreams: 2, MaxSubscriptions: 2})
+		first, _ := registry.Open("bot")
+		second, _ := registry.Open("bot")
+		first.Subscribe("trades:BTC-USD")
+		second.Subscribe("trades:ETH-USD")
+
+		// When: One topic is unsubscribed and the first stream closed, twice
+		second.Unsubscribe("trades:ETH-USD")
+		second.Unsubscribe("trades:ETH-USD")
+		first.Close()
+		first.Close()
+
+		// Then: Only the second stream remains counted, and it is gone once closed
+		if usage := registry.Usage("bot"); usage.Streams != 1 || usage.Subscriptions != 0 {
+			t.Errorf("Unexpected usage: %+v", usage)
+		}
+		second.Close()
+		if all := registry.All(); len(all) != 0 {
+			t.Errorf("Expected no key holding streams, got %+v", all)
+		}
+	})
+
+	t.Run("per_key_limits_override_the_defaults", func(t *testing.T) {
+		// Given: Unlimited defaults and one key limited to a single stream
+		limits, err := ParseLimits("bot=1:0, ")
+		if err != nil {
+			t.Fatalf("ParseLimits failed: %v", err)
+		}
+		registry := NewRegistry(Limits{})
+		for apiKey, keyLimits := range limits {
+			registry.SetLimits(apiKey, keyLimits)
+		}
+
+		// When: Both keys open two streams
+		registry.Open("bot")
+		_, limitedErr := registry.Open("bot")
+		registry.Open("other")
+		_, otherErr := registry.Open("other")
+
+		// Then: Only the limited key is refused
+		if !errors.Is(limitedErr, ErrStreamLimit) {
+			t.Errorf("Expected ErrStreamLimit, got %v", limitedErr)
+		}
+		if otherErr != nil {
+			t.Errorf("Expected the default to be unlimited, got %v", otherErr)
+		}
+		for _, spec := range []string{"bot", "bot=1", "bot=x:1", "=1:1", "bot=-1:1"} {
+			if _, err := ParseLimits(spec); err == nil {
+				t.Errorf("Expected %q to be rejected", spec)
+			}
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package feed
+
+import (
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// BookDelta lists the price levels that changed between two snapshots of a book.
+// A level with zero quantity was removed.
+type BookDelta struct {
+	Symbol string                `json:"symbol"`
+	Phase  matching.Phase        `json:"phase"`
+	Bids   []matching.PriceLevel `json:"bids"`
+	Asks   []matching.PriceLevel `json:"asks"`
+}
+
+// Empty reports whether no level changed
+func (d BookDelta) Empty() bool {
+	return len(d.Bids) == 0 && len(d.Asks) == 0
+}
+
+// DiffBook returns the levels that take prev to next, best prices first
+func DiffBook(prev, next matching.BookSnapshot) BookDelta {
+	return BookDelta{
+		Symbol: next.Symbol,
+		Phase:  next.Phase,
+		Bids:   diffLevels(prev.Bids, next.Bids, func(a, b float64) bool { return a > b }),
+		Asks:   diffLevels(prev.Asks, next.Asks, func(a, b float64) bool { return a < b }),
+	}
+}
+
+// diffLevels merges two sides sorted best first; better reports whether price a
+// sorts ahead of price b on this side
+func diffLevels(prev, next []matching.PriceLevel, better func(a, b float64) bool) []matching.PriceLevel {
+	changed := make([]matching.PriceLevel, 0)
+	i, j := 0, 0
+	for i < len(prev) || j < len(next) {
+		switch {
+		case j == len(next) || (i < len(prev) && better(prev[i].Price, next[j].Price)):
+			changed = append(changed, matching.PriceLevel{Price: prev[i].Price})
+			i++
+		case i == len(prev) || better(next[j].Price, prev[i].Price):
+			changed = append(changed, next[j])
+			j++
+		default:
+			if prev[i] != next[j] {
+				changed = append(changed, next[j])
+			}
+			i++
+			j++
+		}
+	}
+	return changed
+}
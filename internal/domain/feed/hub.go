@@ -0,0 +1,259 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSlowConsumer ends a subscription whose buffer filled up; the client has
+	// missed messages and must resubscribe to resynchronize
+	ErrSlowConsumer = errors.New("subscriber fell behind and was disconnected")
+	// ErrHubClosed ends every subscription when the venue shuts down
+	ErrHubClosed = errors.New("market data feed closed")
+	// ErrUnknownChannel is returned for channels the feed does not publish
+	ErrUnknownChannel = errors.New("unknown channel")
+
+	// errClosedBySubscriber marks a subscription the client ended itself
+	errClosedBySubscriber = errors.New("subscription closed")
+)
+
+// Channel names a stream clients subscribe to
+type Channel string
+
+const (
+	ChannelTrades        Channel = "trades"         // Executions per symbol
+	ChannelBook          Channel = "book"           // Level changes per symbol, after an initial snapshot
+	ChannelBookSnapshot  Channel = "book_snapshot"  // Top of book per symbol, after every change
+	ChannelTicker        Channel = "ticker"         // Rolling 24h statistics per symbol, after every trade
+	ChannelOrders        Channel = "orders"         // Order updates per account
+	ChannelExchangeStats Channel = "exchange_stats" // Open interest and volume across the venue, on an interval
+)
+
+// ParseChannel validates a channel name
+func ParseChannel(s string) (Channel, error) {
+	switch channel := Channel(s); channel {
+	case ChannelTrades, ChannelBook, ChannelBookSnapshot, ChannelTicker, ChannelOrders, ChannelExchangeStats:
+		return channel, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownChannel, s)
+}
+
+// Topic is one channel for one symbol, for one account on the orders channel, or
+// for the whole venue (no key) on the exchange stats channel
+type Topic struct {
+	Channel Channel `json:"channel"`
+	Key     string  `json:"key"`
+}
+
+// MessageType tells a snapshot of current state from an incremental update
+type MessageType string
+
+const (
+	MessageSnapshot MessageType = "snapshot"
+	MessageUpdate   MessageType = "update"
+)
+
+// Message is one push to subscribers of a topic
+type Message struct {
+	Type     MessageType `json:"type"`
+	Channel  Channel     `json:"channel"`
+	Key      string      `json:"key"`
+	Sequence uint64      `json:"sequence,omitempty"` // Per-symbol book sequence on book channels
+	Time     time.Time   `json:"time"`
+	Data     interface{} `json:"data"`
+}
+
+// Hub fans published messages out to the subscribers of each topic. Publishing never
+// blocks: a subscriber whose buffer is full is disconnected with ErrSlowConsumer.
+type Hub struct {
+	buffer      int
+	subscribers map[*Subscriber]struct{}
+	topics      map[Topic]map[*Subscriber]struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer:      buffer,
+		subscribers: make(map[*Subscriber]struct{}),
+		topics:      make(map[Topic]map[*Subscriber]struct{}),
+	}
+}
+
+// Subscriber is one client connection's view of the hub
+type Subscriber struct {
+	hub      *Hub
+	messages chan Message
+	topics   map[Topic]struct{}
+	done     chan struct{}
+	err      error
+}
+
+// Subscribe registers a subscriber with no topics; on a closed hub it is already done
+func (h *Hub) Subscribe() *Subscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscriber{
+		hub:      h,
+		messages: make(chan Message, h.buffer),
+		topics:   make(map[Topic]struct{}),
+		done:     make(chan struct{}),
+	}
+	if h.closed {
+		sub.err = ErrHubClosed
+		close(sub.done)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Publish delivers msg to every subscriber of its topic
+func (h *Hub) Publish(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.topics[Topic{Channel: msg.Channel, Key: msg.Key}] {
+		h.deliver(sub, msg)
+	}
+}
+
+// Subscribed reports whether anyone is listening to a topic, so publishers can skip
+// building messages nobody will receive
+func (h *Hub) Subscribed(topic Topic) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.topics[topic]) > 0
+}
+
+// Subscribers returns how many subscribers are connected
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// Close ends every subscription with ErrHubClosed and refuses new ones
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.drop(sub, ErrHubClosed)
+	}
+}
+
+// deliver sends without blocking; callers hold mu
+func (h *Hub) deliver(sub *Subscriber, msg Message) bool {
+	if sub.err != nil {
+		return false
+	}
+	select {
+	case sub.messages <- msg:
+		return true
+	default:
+		h.drop(sub, ErrSlowConsumer)
+		return false
+	}
+}
+
+// drop ends a subscription; callers hold mu
+func (h *Hub) drop(sub *Subscriber, err error) {
+	if sub.err != nil {
+		return
+	}
+	for topic := range sub.topics {
+		h.unsubscribe(sub, topic)
+	}
+	delete(h.subscribers, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// unsubscribe removes one topic; callers hold mu
+func (h *Hub) unsubscribe(sub *Subscriber, topic Topic) {
+	delete(sub.topics, topic)
+	if subs := h.topics[topic]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// Add subscribes to a topic; it is a no-op once the subscription has ended
+func (s *Subscriber) Add(topic Topic) {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+	s.topics[topic] = struct{}{}
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*Subscriber]struct{})
+	}
+	h.topics[topic][s] = struct{}{}
+}
+
+// Remove unsubscribes from a topic and reports whether it was subscribed
+func (s *Subscriber) Remove(topic Topic) bool {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := s.topics[topic]; !exists {
+		return false
+	}
+	h.unsubscribe(s, topic)
+	return true
+}
+
+// Topics returns how many topics the subscriber is on
+func (s *Subscriber) Topics() int {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return len(s.topics)
+}
+
+// Deliver sends msg to this subscriber alone, e.g. the initial snapshot of a topic,
+// under the same slow-consumer rule as Publish
+func (s *Subscriber) Deliver(msg Message) bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.hub.deliver(s, msg)
+}
+
+// Messages returns the subscriber's buffered messages
+func (s *Subscriber) Messages() <-chan Message {
+	return s.messages
+}
+
+// Done is closed when the subscription ends; Err then says why
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscription ended, or nil while it is live or after Close
+func (s *Subscriber) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if errors.Is(s.err, errClosedBySubscriber) {
+		return nil
+	}
+	return s.err
+}
+
+// Close ends the subscription and releases its topics
+func (s *Subscriber) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, errClosedBySubscriber)
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+package feed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+func TestHub(t *testing.T) {
+	trades := Topic{Channel: ChannelTrades, Key: "BTC-USD"}
+
+	t.Run("delivers_only_subscribed_topics", func(t *testing.T) {
+		// Given: A subscriber on BTC-USD trades
+		hub := NewHub(8)
+		sub := hub.Subscribe()
+		sub.Add(trades)
+
+		// When: Trades print on two symbols
+		hub.Publish(Message{Type: MessageUpdate, Channel: ChannelTrades, Key: "ETH-USD", Data: 1})
+		hub.Publish(Message{Type: MessageUpdate, Channel: ChannelTrades, Key: "BTC-USD", Data: 2})
+
+		// Then: Only the BTC-USD trade arrives
+		if got := <-sub.Messages(); got.Key != "BTC-USD" || got.Data != 2 {
+			t.Errorf("Expected the BTC-USD trade, got %+v", got)
+		}
+		if len(sub.Messages()) != 0 {
+			t.Errorf("Expected nothing else buffered, got %d", len(sub.Messages()))
+		}
+	})
+
+	t.Run("slow_consumer_is_dropped", func(t *testing.T) {
+		// Given: A subscriber with room for one message
+		hub := NewHub(1)
+		sub := hub.Subscribe()
+		sub.Add(trades)
+
+		// When: Two are published without it reading
+		hub.Publish(Message{Channel: ChannelTrades, Key: "BTC-USD"})
+		hub.Publish(Message{Channel: ChannelTrades, Key: "BTC-USD"})
+
+		// Then: It is disconnected rather than blocking the publisher
+		<-sub.Done()
+		if !errors.Is(sub.Err(), ErrSlowConsumer) {
+			t.Errorf("Expected ErrSlowConsumer, got %v", sub.Err())
+		}
+		if hub.Subscribed(trades) || hub.Subscribers() != 0 {
+			t.Error("Expected the dropped subscriber to release its topics")
+		}
+	})
+
+	t.Run("unsubscribe_and_close", func(t *testing.T) {
+		hub := NewHub(8)
+		sub := hub.Subscribe()
+		sub.Add(trades)
+
+		if !sub.Remove(trades) || hub.Subscribed(trades) {
+			t.Error("Expected the topic to be removed")
+		}
+		sub.Close()
+		if sub.Err() != nil {
+			t.Errorf("Expected no error after the subscriber closed itself, got %v", sub.Err())
+		}
+
+		hub.Close()
+		if late := hub.Subscribe(); !errors.Is(late.Err(), ErrHubClosed) {
+			t.Errorf("Expected ErrHubClosed for a subscriber after close, got %v", late.Err())
+		}
+	})
+}
+
+func TestDiffBook(t *testing.T) {
+	t.Run("lists_changed_added_and_removed_levels", func(t *testing.T) {
+		// Given: A book before and after a fill, a new bid and a canceled ask
+		prev := matching.BookSnapshot{
+			Symbol: "BTC-USD",
+			Bids:   []matching.PriceLevel{{Price: 100, Quantity: 2, OrderCount: 1}, {Price: 99, Quantity: 1, OrderCount: 1}},
+			Asks:   []matching.PriceLevel{{Price: 101, Quantity: 1, OrderCount: 1}, {Price: 102, Quantity: 3, OrderCount: 2}},
+		}
+		next := matching.BookSnapshot{
+			Symbol: "BTC-USD",
+			Bids:   []matching.PriceLevel{{Price: 100, Quantity: 1, OrderCount: 1}, {Price: 99.5, Quantity: 4, OrderCount: 1}, {Price: 99, Quantity: 1, OrderCount: 1}},
+			Asks:   []matching.PriceLevel{{Price: 102, Quantity: 3, OrderCount: 2}},
+		}
+
+		// When: They are diffed
+		delta := DiffBook(prev, next)
+
+		// Then: Only changed levels are listed, removals with zero quantity
+		wantBids := []matching.PriceLevel{{Price: 100, Quantity: 1, OrderCount: 1}, {Price: 99.5, Quantity: 4, OrderCount: 1}}
+		wantAsks := []matching.PriceLevel{{Price: 101}}
+		if len(delta.Bids) != len(wantBids) || delta.Bids[0] != wantBids[0] || delta.Bids[1] != wantBids[1] {
+			t.Errorf("Expected bids %+v, got %+v", wantBids, delta.Bids)
+		}
+		if len(delta.Asks) != 1 || delta.Asks[0] != wantAsks[0] {
+			t.Errorf("Expected asks %+v, got %+v", wantAsks, delta.Asks)
+		}
+		if !DiffBook(next, next).Empty() {
+			t.Error("Expected no change between identical books")
+		}
+	})
+}
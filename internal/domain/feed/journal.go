@@ -0,0 +1,202 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var (
+	// ErrSequenceExpired is returned when resuming from a sequence the journal no
+	// longer retains; the client must resynchronize from a snapshot
+	ErrSequenceExpired = errors.New("sequence no longer retained")
+	// ErrSequenceAhead is returned when resuming past the latest sequence, e.g. with
+	// a sequence from before a venue restart
+	ErrSequenceAhead = errors.New("sequence not yet reached")
+)
+
+// Execution is one journal entry: an order update, a trade or an order ack
+type Execution struct {
+	Sequence uint64        `json:"sequence"`
+	Time     time.Time     `json:"time"`
+	Order    *models.Order `json:"order,omitempty"`
+	Trade    *models.Trade `json:"trade,omitempty"`
+	Ack      *OrderAck     `json:"ack,omitempty"`
+}
+
+// OrderAck answers an order submitted asynchronously, matched to it by the request
+// sequence the submission returned
+type OrderAck struct {
+	RequestSequence uint64 `json:"request_sequence"`
+	AccountID       string `json:"account_id"`
+	ClientOrderID   string `json:"client_order_id,omitempty"`
+	Symbol          string `json:"symbol"`
+	OrderID         string `json:"order_id,omitempty"` // Empty when the order was rejected before entry
+	Accepted        bool   `json:"accepted"`
+	Reason          string `json:"reason,omitempty"` // Reject code when not accepted
+	Message         string `json:"message,omitempty"`
+}
+
+// Journal numbers order updates and trades with one venue-wide sequence and keeps
+// the latest of them, so a subscriber that reconnects can resume where it left off
+type Journal struct {
+	capacity int
+	buffer   int
+	entries  []Execution // Ring of the latest capacity entries
+	start    int         // Index of the oldest entry
+	sequence uint64
+	subs     map[*JournalSubscription]struct{}
+	closed   bool
+	mu       sync.Mutex
+}
+
+func NewJournal(capacity, buffer int) *Journal {
+	return &Journal{
+		capacity: capacity,
+		buffer:   buffer,
+		entries:  make([]Execution, 0, capacity),
+		subs:     make(map[*JournalSubscription]struct{}),
+	}
+}
+
+// JournalSubscription receives executions as they are appended
+type JournalSubscription struct {
+	journal    *Journal
+	executions chan Execution
+	done       chan struct{}
+	err        error
+}
+
+// AppendOrder journals an order update
+func (j *Journal) AppendOrder(at time.Time, order models.Order) Execution {
+	return j.append(Execution{Time: at, Order: &order})
+}
+
+// AppendTrade journals a trade
+func (j *Journal) AppendTrade(at time.Time, trade models.Trade) Execution {
+	return j.append(Execution{Time: at, Trade: &trade})
+}
+
+// AppendAck journals the answer to an asynchronous order submission
+func (j *Journal) AppendAck(at time.Time, ack OrderAck) Execution {
+	return j.append(Execution{Time: at, Ack: &ack})
+}
+
+func (j *Journal) append(execution Execution) Execution {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.sequence++
+	execution.Sequence = j.sequence
+	if len(j.entries) < j.capacity {
+		j.entries = append(j.entries, execution)
+	} else {
+		j.entries[j.start] = execution
+		j.start = (j.start + 1) % j.capacity
+	}
+
+	for sub := range j.subs {
+		select {
+		case sub.executions <- execution:
+		default:
+			j.drop(sub, ErrSlowConsumer)
+		}
+	}
+	return execution
+}
+
+// Sequence returns the sequence of the latest execution
+func (j *Journal) Sequence() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.sequence
+}
+
+// Subscribe returns the retained executions from sequence from onwards, then delivers
+// every later one to the subscription with no gap in between. From 0 starts live.
+func (j *Journal) Subscribe(from uint64) (*JournalSubscription, []Execution, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return nil, nil, ErrHubClosed
+	}
+	backlog := make([]Execution, 0)
+	if from > 0 {
+		if from > j.sequence+1 {
+			return nil, nil, fmt.Errorf("%w: %d is past the latest sequence %d", ErrSequenceAhead, from, j.sequence)
+		}
+		oldest := j.sequence + 1
+		if len(j.entries) > 0 {
+			oldest = j.entries[j.start].Sequence
+		}
+		if from < oldest {
+			return nil, nil, fmt.Errorf("%w: %d is older than %d", ErrSequenceExpired, from, oldest)
+		}
+		for i := 0; i < len(j.entries); i++ {
+			entry := j.entries[(j.start+i)%len(j.entries)]
+			if entry.Sequence >= from {
+				backlog = append(backlog, entry)
+			}
+		}
+	}
+
+	sub := &JournalSubscription{
+		journal:    j,
+		executions: make(chan Execution, j.buffer),
+		done:       make(chan struct{}),
+	}
+	j.subs[sub] = struct{}{}
+	return sub, backlog, nil
+}
+
+// Close ends every subscription with ErrHubClosed and refuses new ones
+func (j *Journal) Close() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.closed = true
+	for sub := range j.subs {
+		j.drop(sub, ErrHubClosed)
+	}
+}
+
+// drop ends a subscription; callers hold mu
+func (j *Journal) drop(sub *JournalSubscription, err error) {
+	if _, live := j.subs[sub]; !live {
+		return
+	}
+	delete(j.subs, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// Executions returns the subscription's buffered executions
+func (s *JournalSubscription) Executions() <-chan Execution {
+	return s.executions
+}
+
+// Done is closed when the subscription ends; Err then says why
+func (s *JournalSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscription ended, or nil while it is live or after Close
+func (s *JournalSubscription) Err() error {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	if errors.Is(s.err, errClosedBySubscriber) {
+		return nil
+	}
+	return s.err
+}
+
+// Close ends the subscription
+func (s *JournalSubscription) Close() {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	s.journal.drop(s, errClosedBySubscriber)
+}
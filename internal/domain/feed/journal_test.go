@@ -0,0 +1,96 @@
+//go:build unit
+
+package feed
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestJournal(t *testing.T) {
+	now := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	t.Run("resumes_from_a_retained_sequence", func(t *testing.T) {
+		// Given: A journal holding three executions
+		journal := NewJournal(8, 8)
+		journal.AppendOrder(now, models.Order{ID: "o-1"})
+		journal.AppendTrade(now, models.Trade{ID: "t-1"})
+		journal.AppendOrder(now, models.Order{ID: "o-2"})
+
+		// When: A subscriber resumes from sequence 2 and another execution follows
+		sub, backlog, err := journal.Subscribe(2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		journal.AppendTrade(now, models.Trade{ID: "t-2"})
+
+		// Then: It replays 2 and 3, then receives 4 live with no gap
+		if len(backlog) != 2 || backlog[0].Sequence != 2 || backlog[0].Trade == nil || backlog[1].Order.ID != "o-2" {
+			t.Fatalf("Expected sequences 2 and 3 replayed, got %+v", backlog)
+		}
+		if live := <-sub.Executions(); live.Sequence != 4 || live.Trade.ID != "t-2" {
+			t.Errorf("Expected sequence 4 live, got %+v", live)
+		}
+	})
+
+	t.Run("zero_starts_live", func(t *testing.T) {
+		journal := NewJournal(8, 8)
+		journal.AppendOrder(now, models.Order{ID: "o-1"})
+
+		_, backlog, err := journal.Subscribe(0)
+
+		if err != nil || len(backlog) != 0 {
+			t.Errorf("Expected no backlog, got %+v, %v", backlog, err)
+		}
+	})
+
+	t.Run("rejects_sequences_outside_the_ring", func(t *testing.T) {
+		// Given: A journal of two that has wrapped past sequence 1
+		journal := NewJournal(2, 8)
+		for i := 0; i < 3; i++ {
+			journal.AppendOrder(now, models.Order{})
+		}
+
+		// When: Resuming before the oldest and beyond the next sequence
+		_, _, expired := journal.Subscribe(1)
+		_, _, ahead := journal.Subscribe(5)
+		_, backlog, next := journal.Subscribe(4)
+
+		// Then: Both fail, while the next sequence waits for live executions
+		if !errors.Is(expired, ErrSequenceExpired) {
+			t.Errorf("Expected ErrSequenceExpired, got %v", expired)
+		}
+		if !errors.Is(ahead, ErrSequenceAhead) {
+			t.Errorf("Expected ErrSequenceAhead, got %v", ahead)
+		}
+		if next != nil || len(backlog) != 0 {
+			t.Errorf("Expected an empty backlog from the next sequence, got %+v, %v", backlog, next)
+		}
+	})
+
+	t.Run("slow_subscriber_is_dropped_and_close_ends_the_rest", func(t *testing.T) {
+		journal := NewJournal(8, 1)
+		slow, _, _ := journal.Subscribe(0)
+		idle, _, _ := journal.Subscribe(0)
+
+		journal.AppendOrder(now, models.Order{})
+		<-idle.Executions()
+		journal.AppendOrder(now, models.Order{})
+		journal.Close()
+
+		<-slow.Done()
+		<-idle.Done()
+		if !errors.Is(slow.Err(), ErrSlowConsumer) {
+			t.Errorf("Expected ErrSlowConsumer, got %v", slow.Err())
+		}
+		if !errors.Is(idle.Err(), ErrHubClosed) {
+			t.Errorf("Expected ErrHubClosed, got %v", idle.Err())
+		}
+		if _, _, err := journal.Subscribe(0); !errors.Is(err, ErrHubClosed) {
+			t.Errorf("Expected subscribing after close to fail, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package feed
+
+import (
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// PublicTrade is an execution as printed on the public trades channel: no accounts,
+// and order and trade IDs as published rather than as stored
+type PublicTrade struct {
+	ID          string      `json:"id"`
+	Symbol      string      `json:"symbol"`
+	Price       float64     `json:"price"`
+	Quantity    float64     `json:"quantity"`
+	BuyOrderID  string      `json:"buy_order_id"`
+	SellOrderID string      `json:"sell_order_id"`
+	TakerSide   models.Side `json:"taker_side,omitempty"` // Empty for auction trades
+	Auction     bool        `json:"auction"`
+	ExecutedAt  time.Time   `json:"executed_at"`
+}
+
+// NewPublicTrade strips a trade's accounts and maps its IDs through external
+func NewPublicTrade(trade models.Trade, external func(id string) string) PublicTrade {
+	return PublicTrade{
+		ID:          external(trade.ID),
+		Symbol:      trade.Symbol,
+		Price:       trade.Price,
+		Quantity:    trade.Quantity,
+		BuyOrderID:  external(trade.BuyOrderID),
+		SellOrderID: external(trade.SellOrderID),
+		TakerSide:   trade.TakerSide,
+		Auction:     trade.Auction,
+		ExecutedAt:  trade.ExecutedAt,
+	}
+}
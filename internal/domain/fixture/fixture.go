@@ -0,0 +1,196 @@
+// Package fixture reads the market states an orchestrator seeds a cold venue with:
+// instruments to list, accounts with their opening balances, and orders to rest on
+// the books, so every run of a scenario starts from the same market.
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Fixture is one market state, applied in order: instruments, accounts, then orders
+type Fixture struct {
+	Name        string       `json:"name"`
+	Instruments []Instrument `json:"instruments,omitempty"` // Listed, or redefined when already listed
+	Accounts    []Account    `json:"accounts,omitempty"`
+	Orders      []Order      `json:"orders,omitempty"` // Placed in file order, so queue priority is the file's
+}
+
+// Instrument is an instrument definition; the funding interval is written as a
+// duration, e.g. "8h"
+type Instrument struct {
+	models.Instrument
+	FundingInterval string `json:"funding_interval,omitempty"`
+
+	interval time.Duration
+}
+
+// Definition returns the instrument as the venue lists it
+func (i Instrument) Definition() models.Instrument {
+	instrument := i.Instrument
+	instrument.FundingInterval = i.interval
+	return instrument
+}
+
+// Account is an account opened under a fixed ID, so orders and later scenario steps
+// can name it
+type Account struct {
+	ID          string                `json:"account_id"`
+	Tier        string                `json:"tier"` // Default: standard
+	Metadata    map[string]string     `json:"metadata,omitempty"`
+	Balances    map[string]float64    `json:"balances,omitempty"`    // Opening balance per asset; none trades on unlimited credit
+	Permissions []accounts.Permission `json:"permissions,omitempty"` // Default: read and trade
+	Leverage    float64               `json:"leverage,omitempty"`
+	APIKey      string                `json:"api_key,omitempty"` // Issued to the account, so clients can sign in with a known key
+}
+
+// Template returns the account's template, defaults applied
+func (a Account) Template() accounts.Template {
+	template := accounts.Template{
+		Tier:        a.Tier,
+		Metadata:    a.Metadata,
+		Balances:    a.Balances,
+		Permissions: a.Permissions,
+		Leverage:    a.Leverage,
+	}
+	if template.Tier == "" {
+		template.Tier = accounts.DefaultTier
+	}
+	if template.Metadata == nil {
+		template.Metadata = map[string]string{}
+	}
+	if len(template.Permissions) == 0 {
+		template.Permissions = accounts.DefaultPermissions
+	}
+	return template
+}
+
+// Order is a limit order left resting on a book
+type Order struct {
+	AccountID     string             `json:"account_id"`
+	ClientOrderID string             `json:"client_order_id,omitempty"`
+	Symbol        string             `json:"symbol"`
+	Side          models.Side        `json:"side"`
+	TimeInForce   models.TimeInForce `json:"time_in_force,omitempty"` // GTC or GTD; default GTC
+	Quantity      float64            `json:"quantity"`
+	Price         float64            `json:"price"`
+	ExpiresAt     time.Time          `json:"expires_at,omitempty"` // Required for GTD
+}
+
+// Parse reads a fixture as YAML or JSON and checks it is consistent: instruments are
+// well formed, account IDs unique, and every order rests for a fixture account.
+// Instruments and orders may name symbols the venue already lists.
+func Parse(data []byte) (Fixture, error) {
+	// YAML is read into plain values and decoded as JSON, so both share the JSON names
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+	}
+	normalised, err := json.Marshal(document)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(normalised, &fixture); err != nil {
+		return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+	}
+	if len(fixture.Instruments)+len(fixture.Accounts)+len(fixture.Orders) == 0 {
+		return Fixture{}, errors.New("invalid fixture: nothing to seed")
+	}
+
+	symbols := make(map[string]bool, len(fixture.Instruments))
+	for i := range fixture.Instruments {
+		instrument := &fixture.Instruments[i]
+		if err := instrument.validate(); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: instrument %d: %w", i+1, err)
+		}
+		if symbols[instrument.Symbol] {
+			return Fixture{}, fmt.Errorf("invalid fixture: instrument %s listed twice", instrument.Symbol)
+		}
+		symbols[instrument.Symbol] = true
+	}
+
+	ids := make(map[string]bool, len(fixture.Accounts))
+	for i, account := range fixture.Accounts {
+		if account.ID == "" {
+			return Fixture{}, fmt.Errorf("invalid fixture: account %d: account_id is required", i+1)
+		}
+		if ids[account.ID] {
+			return Fixture{}, fmt.Errorf("invalid fixture: account %s listed twice", account.ID)
+		}
+		if err := account.Template().Validate(); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: account %s: %w", account.ID, err)
+		}
+		ids[account.ID] = true
+	}
+
+	for i := range fixture.Orders {
+		order := &fixture.Orders[i]
+		if !ids[order.AccountID] {
+			return Fixture{}, fmt.Errorf("invalid fixture: order %d: account %q is not in the fixture", i+1, order.AccountID)
+		}
+		if err := order.validate(); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: order %d: %w", i+1, err)
+		}
+	}
+	return fixture, nil
+}
+
+func (i *Instrument) validate() error {
+	switch {
+	case i.Symbol == "" || i.BaseAsset == "" || i.QuoteAsset == "":
+		return errors.New("symbol, base_asset and quote_asset are required")
+	case i.TickSize <= 0 || i.LotSize <= 0:
+		return fmt.Errorf("%s: tick_size and lot_size must be positive", i.Symbol)
+	case i.ReferencePrice <= 0:
+		return fmt.Errorf("%s: reference_price must be positive", i.Symbol)
+	}
+	if i.Kind == "" {
+		i.Kind = models.InstrumentKindSpot
+	}
+	if i.MinQuantity == 0 {
+		i.MinQuantity = i.LotSize
+	}
+	switch i.Kind {
+	case models.InstrumentKindSpot:
+		if i.FundingInterval != "" {
+			return fmt.Errorf("%s: only perpetuals have a funding_interval", i.Symbol)
+		}
+	case models.InstrumentKindPerpetual:
+		if i.InitialMarginRate <= 0 || i.MaintenanceMarginRate <= 0 || i.MaintenanceMarginRate > i.InitialMarginRate {
+			return fmt.Errorf("%s: perpetuals need a maintenance_margin_rate no higher than a positive initial_margin_rate", i.Symbol)
+		}
+		interval, err := time.ParseDuration(i.FundingInterval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("%s: perpetuals need a positive funding_interval, e.g. \"8h\"", i.Symbol)
+		}
+		i.interval = interval
+	default:
+		return fmt.Errorf("%s: kind must be %s or %s", i.Symbol, models.InstrumentKindSpot, models.InstrumentKindPerpetual)
+	}
+	return nil
+}
+
+func (o *Order) validate() error {
+	if o.TimeInForce == "" {
+		o.TimeInForce = models.TimeInForceGTC
+	}
+	switch {
+	case o.Symbol == "":
+		return errors.New("symbol is required")
+	case o.Side != models.SideBuy && o.Side != models.SideSell:
+		return fmt.Errorf("side must be %s or %s", models.SideBuy, models.SideSell)
+	case !o.TimeInForce.Rests():
+		return fmt.Errorf("time_in_force must be %s or %s, so the order rests", models.TimeInForceGTC, models.TimeInForceGTD)
+	case o.Quantity <= 0 || o.Price <= 0:
+		return errors.New("quantity and price must be positive")
+	}
+	return nil
+}
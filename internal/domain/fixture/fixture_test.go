@@ -0,0 +1,93 @@
+//go:build unit
+
+package fixture
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("parses_yaml_and_json_alike", func(t *testing.T) {
+		// Given: The same fixture in YAML and in JSON
+		yamlFixture := `
+name: thin-book
+instruments:
+  - symbol: SOL-USD-PERP
+    base_asset: SOL
+    quote_asset: USD
+    kind: perpetual
+    tick_size: 0.01
+    lot_size: 0.1
+    initial_margin_rate: 0.1
+    maintenance_margin_rate: 0.05
+    funding_interval: 8h
+    reference_price: 150
+accounts:
+  - account_id: mm-1
+    balances: {USD: 100000, SOL: 500}
+orders:
+  - {account_id: mm-1, symbol: SOL-USD-PERP, side: sell, quantity: 10, price: 151}
+  - {account_id: mm-1, symbol: SOL-USD-PERP, side: buy, quantity: 10, price: 149, client_order_id: bid-1}
+`
+		jsonFixture := `{"name": "thin-book",
+			"instruments": [{"symbol": "SOL-USD-PERP", "base_asset": "SOL", "quote_asset": "USD", "kind": "perpetual",
+				"tick_size": 0.01, "lot_size": 0.1, "initial_margin_rate": 0.1, "maintenance_margin_rate": 0.05,
+				"funding_interval": "8h", "reference_price": 150}],
+			"accounts": [{"account_id": "mm-1", "balances": {"USD": 100000, "SOL": 500}}],
+			"orders": [
+				{"account_id": "mm-1", "symbol": "SOL-USD-PERP", "side": "sell", "quantity": 10, "price": 151},
+				{"account_id": "mm-1", "symbol": "SOL-USD-PERP", "side": "buy", "quantity": 10, "price": 149, "client_order_id": "bid-1"}
+			]}`
+
+		// When: Both are parsed
+		fromYAML, yamlErr := Parse([]byte(yamlFixture))
+		fromJSON, jsonErr := Parse([]byte(jsonFixture))
+
+		// Then: They agree, with defaults filled in
+		if yamlErr != nil || jsonErr != nil {
+			t.Fatalf("Expected both to parse, got %v and %v", yamlErr, jsonErr)
+		}
+		for _, fixture := range []Fixture{fromYAML, fromJSON} {
+			instrument := fixture.Instruments[0].Definition()
+			if instrument.FundingInterval != 8*time.Hour || instrument.MinQuantity != 0.1 {
+				t.Errorf("Expected the funding interval and minimum quantity, got %+v", instrument)
+			}
+			template := fixture.Accounts[0].Template()
+			if template.Tier != accounts.DefaultTier || len(template.Permissions) != len(accounts.DefaultPermissions) || template.Balances["SOL"] != 500 {
+				t.Errorf("Expected the default tier and permissions with the balances, got %+v", template)
+			}
+			if len(fixture.Orders) != 2 || fixture.Orders[0].TimeInForce != models.TimeInForceGTC || fixture.Orders[1].ClientOrderID != "bid-1" {
+				t.Errorf("Expected both orders in file order, GTC by default, got %+v", fixture.Orders)
+			}
+		}
+	})
+
+	t.Run("refuses_inconsistent_fixtures", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"empty":           `{"name": "nothing"}`,
+			"unknown_account": `{"accounts": [{"account_id": "a"}], "orders": [{"account_id": "b", "symbol": "BTC-USD", "side": "buy", "quantity": 1, "price": 100}]}`,
+			"duplicate_id":    `{"accounts": [{"account_id": "a"}, {"account_id": "a"}]}`,
+			"missing_id":      `{"accounts": [{"tier": "vip"}]}`,
+			"negative_lever":  `{"accounts": [{"account_id": "a", "leverage": -1}]}`,
+			"ioc_order":       `{"accounts": [{"account_id": "a"}], "orders": [{"account_id": "a", "symbol": "BTC-USD", "side": "buy", "time_in_force": "IOC", "quantity": 1, "price": 100}]}`,
+			"no_price":        `{"accounts": [{"account_id": "a"}], "orders": [{"account_id": "a", "symbol": "BTC-USD", "side": "buy", "quantity": 1}]}`,
+			"bad_side":        `{"accounts": [{"account_id": "a"}], "orders": [{"account_id": "a", "symbol": "BTC-USD", "side": "long", "quantity": 1, "price": 100}]}`,
+			"no_tick":         `{"instruments": [{"symbol": "X-USD", "base_asset": "X", "quote_asset": "USD", "lot_size": 1, "reference_price": 1}]}`,
+			"perp_no_funding": `{"instruments": [{"symbol": "X-PERP", "base_asset": "X", "quote_asset": "USD", "kind": "perpetual", "tick_size": 1, "lot_size": 1, "initial_margin_rate": 0.1, "maintenance_margin_rate": 0.05, "reference_price": 1}]}`,
+			"not_a_document":  `instruments: [`,
+		} {
+			// When: The fixture is parsed
+			_, err := Parse([]byte(data))
+
+			// Then: It is refused
+			if err == nil || !strings.HasPrefix(err.Error(), "invalid fixture") {
+				t.Errorf("%s: expected the fixture refused, got %v", name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package funding
+
+import "math"
+
+const (
+	// InterestClamp bounds how far the interest rate can pull funding away from the
+	// premium, per funding interval
+	InterestClamp = 0.0005
+
+	// MaxRate caps the funding rate per interval in either direction
+	MaxRate = 0.0075
+)
+
+// Premium is how far a perpetual's fair price trades from its index, as a fraction
+// of the index. Positive when the perpetual is rich, so longs pay shorts.
+func Premium(fair, index float64) float64 {
+	if fair <= 0 || index <= 0 {
+		return 0
+	}
+	return (fair - index) / index
+}
+
+// Rate is the funding rate for an interval: the premium index plus the interest
+// rate's difference from it, clamped to InterestClamp, capped at MaxRate
+func Rate(premiumIndex, interest float64) float64 {
+	rate := premiumIndex + clamp(interest-premiumIndex, InterestClamp)
+	return clamp(rate, MaxRate)
+}
+
+// Payment is what a position pays at a funding time: positive is paid by the
+// holder, negative is received. Longs pay a positive rate and shorts receive it.
+func Payment(quantity, mark, rate float64) float64 {
+	return quantity * mark * rate
+}
+
+// Index averages premium samples taken between funding times
+type Index struct {
+	sum     float64
+	samples int
+}
+
+// Sample adds one premium observation
+func (i *Index) Sample(premium float64) {
+	i.sum += premium
+	i.samples++
+}
+
+// Value is the average premium sampled, or 0 before any sample
+func (i *Index) Value() float64 {
+	if i.samples == 0 {
+		return 0
+	}
+	return i.sum / float64(i.samples)
+}
+
+// Samples is how many premiums have been sampled since the last reset
+func (i *Index) Samples() int {
+	return i.samples
+}
+
+// Reset starts the next interval's average
+func (i *Index) Reset() {
+	i.sum, i.samples = 0, 0
+}
+
+func clamp(value, limit float64) float64 {
+	return math.Max(-limit, math.Min(limit, value))
+}
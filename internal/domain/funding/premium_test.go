@@ -0,0 +1,54 @@
+//go:build unit
+
+package funding
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	t.Run("follows_the_premium_index_within_the_interest_clamp_and_cap", func(t *testing.T) {
+		// Given: A 0.01% interest rate per interval
+		interest := 0.0001
+
+		// When: The perpetual trades flat, slightly rich, far rich and far cheap
+		flat := Rate(0, interest)
+		rich := Rate(0.002, interest)
+		extreme := Rate(Premium(110, 100), interest)
+		cheap := Rate(-0.05, interest)
+
+		// Then: Flat pays the interest, the premium moves it by at most the clamp,
+		// and the cap bounds both directions
+		if math.Abs(flat-0.0001) > 1e-12 {
+			t.Errorf("Expected the interest rate when flat, got %v", flat)
+		}
+		if math.Abs(rich-0.0015) > 1e-12 {
+			t.Errorf("Expected 0.2%% less the 0.05%% clamp, got %v", rich)
+		}
+		if extreme != MaxRate || cheap != -MaxRate {
+			t.Errorf("Expected the rate to be capped at ±%v, got %v and %v", MaxRate, extreme, cheap)
+		}
+	})
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("averages_samples_until_reset", func(t *testing.T) {
+		// Given: An index sampling premiums of 0.1% and 0.3%
+		var index Index
+		index.Sample(0.001)
+		index.Sample(0.003)
+
+		// When: The interval's value is read, then the index is reset
+		value := index.Value()
+		index.Reset()
+
+		// Then: The average was 0.2%, and a reset index reads zero
+		if math.Abs(value-0.002) > 1e-12 || index.Value() != 0 || index.Samples() != 0 {
+			t.Errorf("Expected 0.002 then an empty index, got %v and %+v", value, index)
+		}
+		if payment := Payment(-2, 100, 0.001); payment != -0.2 {
+			t.Errorf("Expected a short of 2 at 100 to receive 0.2, got %v", payment)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoRate is returned when no chain of marks links two assets
+var ErrNoRate = errors.New("no conversion rate")
+
+// Mark is the current price of one unit of Base in Quote, taken from a book
+type Mark struct {
+	Symbol string
+	Base   string
+	Quote  string
+	Price  float64
+}
+
+// Conversion is a rate between two assets and the books it was derived through
+type Conversion struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Rate float64  `json:"rate"` // Units of To per unit of From
+	Path []string `json:"path"` // Symbols used, in order; empty when From equals To
+}
+
+type edge struct {
+	to     string
+	rate   float64
+	symbol string
+}
+
+// Converter values assets in one another through a graph of marks. Each book links
+// its base and quote both ways, so EUR converts to USD through EUR-USD and BTC to EUR
+// through BTC-USD then EUR-USD when no BTC-EUR book is marked.
+type Converter struct {
+	edges map[string][]edge
+}
+
+// NewConverter ignores marks without a positive price
+func NewConverter(marks ...Mark) *Converter {
+	c := &Converter{edges: make(map[string][]edge)}
+	for _, mark := range marks {
+		if mark.Price <= 0 || mark.Base == "" || mark.Quote == "" || mark.Base == mark.Quote {
+			continue
+		}
+		c.edges[mark.Base] = append(c.edges[mark.Base], edge{to: mark.Quote, rate: mark.Price, symbol: mark.Symbol})
+		c.edges[mark.Quote] = append(c.edges[mark.Quote], edge{to: mark.Base, rate: 1 / mark.Price, symbol: mark.Symbol})
+	}
+	// Sorted edges make the chosen path deterministic when several are equally short
+	for asset := range c.edges {
+		edges := c.edges[asset]
+		sort.Slice(edges, func(i, j int) bool { return edges[i].symbol < edges[j].symbol })
+	}
+	return c
+}
+
+// Knows reports whether any mark quotes asset
+func (c *Converter) Knows(asset string) bool {
+	_, exists := c.edges[asset]
+	return exists
+}
+
+// Rate converts from one asset to another through the fewest books
+func (c *Converter) Rate(from, to string) (Conversion, error) {
+	if from == to {
+		return Conversion{From: from, To: to, Rate: 1, Path: []string{}}, nil
+	}
+
+	type step struct {
+		asset string
+		rate  float64
+		path  []string
+	}
+	visited := map[string]bool{from: true}
+	queue := []step{{asset: from, rate: 1}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range c.edges[current.asset] {
+			if visited[e.to] {
+				continue
+			}
+			visited[e.to] = true
+			next := step{
+				asset: e.to,
+				rate:  current.rate * e.rate,
+				path:  append(append(make([]string, 0, len(current.path)+1), current.path...), e.symbol),
+			}
+			if e.to == to {
+				return Conversion{From: from, To: to, Rate: next.rate, Path: next.path}, nil
+			}
+			queue = append(queue, next)
+		}
+	}
+	return Conversion{}, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+}
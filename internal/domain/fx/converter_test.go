@@ -0,0 +1,66 @@
+//go:build unit
+
+package fx
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestConverter_Rate(t *testing.T) {
+	converter := NewConverter(
+		Mark{Symbol: "BTC-USD", Base: "BTC", Quote: "USD", Price: 60000},
+		Mark{Symbol: "EUR-USD", Base: "EUR", Quote: "USD", Price: 1.2},
+		Mark{Symbol: "ETH-BTC", Base: "ETH", Quote: "BTC", Price: 0.05},
+		Mark{Symbol: "XRP-JPY", Base: "XRP", Quote: "JPY", Price: 0},
+	)
+
+	t.Run("converts_directly_and_inversely", func(t *testing.T) {
+		// Given: A BTC-USD mark
+		// When: Rates are taken both ways
+		direct, err := converter.Rate("BTC", "USD")
+		if err != nil {
+			t.Fatalf("Expected a rate, got %v", err)
+		}
+		inverse, _ := converter.Rate("USD", "BTC")
+
+		// Then: The inverse is the reciprocal of the mark
+		if direct.Rate != 60000 || !reflect.DeepEqual(direct.Path, []string{"BTC-USD"}) {
+			t.Errorf("Expected 60000 via BTC-USD, got %+v", direct)
+		}
+		if math.Abs(inverse.Rate-1.0/60000) > 1e-15 {
+			t.Errorf("Expected 1/60000, got %v", inverse.Rate)
+		}
+	})
+
+	t.Run("chains_books_through_shared_assets", func(t *testing.T) {
+		// Given: No direct ETH-EUR book
+		// When: ETH is valued in EUR
+		conversion, err := converter.Rate("ETH", "EUR")
+		if err != nil {
+			t.Fatalf("Expected a chained rate, got %v", err)
+		}
+
+		// Then: ETH goes through BTC and USD to EUR
+		if math.Abs(conversion.Rate-0.05*60000/1.2) > 1e-9 {
+			t.Errorf("Expected 2500, got %v", conversion.Rate)
+		}
+		if !reflect.DeepEqual(conversion.Path, []string{"ETH-BTC", "BTC-USD", "EUR-USD"}) {
+			t.Errorf("Unexpected path %v", conversion.Path)
+		}
+	})
+
+	t.Run("fails_without_a_priced_path", func(t *testing.T) {
+		if _, err := converter.Rate("XRP", "USD"); !errors.Is(err, ErrNoRate) {
+			t.Errorf("Expected ErrNoRate for an unmarked book, got %v", err)
+		}
+		if converter.Knows("JPY") {
+			t.Error("Expected assets of unpriced marks to be unknown")
+		}
+		if same, _ := converter.Rate("GBP", "GBP"); same.Rate != 1 {
+			t.Errorf("Expected an asset to convert to itself at 1, got %+v", same)
+		}
+	})
+}
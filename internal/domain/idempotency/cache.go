@@ -0,0 +1,176 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrKeyReused is returned when a key comes back with a different request
+var ErrKeyReused = errors.New("idempotency key reused for a different request")
+
+// MaxRecords bounds the keys remembered at once; the oldest are dropped first
+const MaxRecords = 100000
+
+type contextKey struct{}
+
+// WithKey tags a request context with the caller's idempotency key
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFrom returns the idempotency key a request was tagged with, if any
+func KeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(contextKey{}).(string)
+	return key
+}
+
+// Record is the remembered outcome of one keyed request
+type Record struct {
+	Key         string    `json:"key"`         // Scoped by the caller's API key
+	Fingerprint string    `json:"fingerprint"` // Identifies the request the key was first used for
+	OrderID     string    `json:"order_id"`    // Order the request placed or acted on
+	SeenAt      time.Time `json:"seen_at"`
+}
+
+// Store persists records so a restarted venue still recognizes keys
+type Store interface {
+	Append(record Record) error
+	Load() ([]Record, error)
+	Replace(records []Record) error // Compacts the store down to records
+}
+
+// entry is claimed before the request runs; done closes once it finishes, with
+// ok false when the request failed and the key is free again
+type entry struct {
+	record Record
+	ok     bool
+	done   chan struct{}
+}
+
+// Cache remembers keyed requests for a window so retries are answered with the
+// original outcome instead of being executed twice
+type Cache struct {
+	window  time.Duration
+	entries map[string]*entry
+	order   []string // Completed keys, oldest first
+	mu      sync.Mutex
+}
+
+func NewCache(window time.Duration) *Cache {
+	return &Cache{window: window, entries: make(map[string]*entry)}
+}
+
+// Claim is a request that holds its key until it finishes
+type Claim struct {
+	cache *Cache
+	key   string
+	entry *entry
+}
+
+// Begin claims key for a request. It returns the remembered record when the same
+// request already completed, waits while it is still running, and fails with
+// ErrKeyReused when the key belongs to a different request.
+func (c *Cache) Begin(key, fingerprint string, now time.Time) (*Claim, *Record, error) {
+	for {
+		c.mu.Lock()
+		existing, exists := c.entries[key]
+		if exists && existing.ok && now.Sub(existing.record.SeenAt) > c.window {
+			delete(c.entries, key)
+			exists = false
+		}
+		if !exists {
+			claimed := &entry{record: Record{Key: key, Fingerprint: fingerprint, SeenAt: now}, done: make(chan struct{})}
+			c.entries[key] = claimed
+			c.mu.Unlock()
+			return &Claim{cache: c, key: key, entry: claimed}, nil, nil
+		}
+		c.mu.Unlock()
+
+		<-existing.done
+		if !existing.ok {
+			continue // The earlier request failed, so the key is free again
+		}
+		if existing.record.Fingerprint != fingerprint {
+			return nil, nil, fmt.Errorf("%w: %s", ErrKeyReused, key)
+		}
+		record := existing.record
+		return nil, &record, nil
+	}
+}
+
+// Complete remembers the request's outcome and returns the record to persist
+func (cl *Claim) Complete(orderID string) Record {
+	c := cl.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl.entry.record.OrderID = orderID
+	cl.entry.ok = true
+	close(cl.entry.done)
+	c.order = append(c.order, cl.key)
+	c.evict(cl.entry.record.SeenAt)
+	return cl.entry.record
+}
+
+// Abandon frees the key of a failed request so a retry runs again
+func (cl *Claim) Abandon() {
+	c := cl.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[cl.key] == cl.entry {
+		delete(c.entries, cl.key)
+	}
+	close(cl.entry.done)
+}
+
+// Restore loads persisted records, skipping any older than the window, and returns
+// the ones kept
+func (c *Cache) Restore(records []Record, now time.Time) []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := make([]Record, 0, len(records))
+	for _, record := range records {
+		if now.Sub(record.SeenAt) > c.window {
+			continue
+		}
+		done := make(chan struct{})
+		close(done)
+		if _, exists := c.entries[record.Key]; !exists {
+			c.order = append(c.order, record.Key)
+		}
+		c.entries[record.Key] = &entry{record: record, ok: true, done: done}
+		kept = append(kept, record)
+	}
+	c.evict(now)
+	return kept
+}
+
+// Len returns how many completed keys are remembered
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.order)
+}
+
+// evict drops completed keys past the window or over MaxRecords; callers hold mu
+func (c *Cache) evict(now time.Time) {
+	drop := 0
+	for drop < len(c.order) {
+		key := c.order[drop]
+		existing, exists := c.entries[key]
+		stale := !exists || !existing.ok
+		if !stale && now.Sub(existing.record.SeenAt) <= c.window && len(c.order)-drop <= MaxRecords {
+			break
+		}
+		if exists && existing.ok {
+			delete(c.entries, key)
+		}
+		drop++
+	}
+	c.order = c.order[drop:]
+}
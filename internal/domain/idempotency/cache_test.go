@@ -0,0 +1,99 @@
+//go:build unit
+
+package idempotency
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("replays_completed_request", func(t *testing.T) {
+		// Given: A request that completed under a key
+		cache := NewCache(time.Hour)
+		claim, _, err := cache.Begin("api/k1", "place|a", start)
+		if err != nil || claim == nil {
+			t.Fatalf("Expected to claim the key, got %v", err)
+		}
+		claim.Complete("ord-1")
+
+		// When: The same request is retried
+		retry, record, err := cache.Begin("api/k1", "place|a", start.Add(time.Minute))
+
+		// Then: It gets the original outcome instead of a claim
+		if err != nil || retry != nil || record == nil || record.OrderID != "ord-1" {
+			t.Errorf("Expected the remembered record, got claim %v record %+v err %v", retry, record, err)
+		}
+	})
+
+	t.Run("rejects_key_reused_for_another_request", func(t *testing.T) {
+		cache := NewCache(time.Hour)
+		claim, _, _ := cache.Begin("api/k1", "place|a", start)
+		claim.Complete("ord-1")
+
+		_, _, err := cache.Begin("api/k1", "place|b", start)
+		if !errors.Is(err, ErrKeyReused) {
+			t.Errorf("Expected ErrKeyReused, got %v", err)
+		}
+	})
+
+	t.Run("abandoned_key_runs_again", func(t *testing.T) {
+		cache := NewCache(time.Hour)
+		claim, _, _ := cache.Begin("api/k1", "place|a", start)
+		claim.Abandon()
+
+		retry, record, err := cache.Begin("api/k1", "place|a", start)
+		if err != nil || retry == nil || record != nil {
+			t.Errorf("Expected a fresh claim, got claim %v record %+v err %v", retry, record, err)
+		}
+	})
+
+	t.Run("concurrent_retry_waits_for_first", func(t *testing.T) {
+		// Given: A request still in flight
+		cache := NewCache(time.Hour)
+		claim, _, _ := cache.Begin("api/k1", "place|a", start)
+
+		// When: A retry arrives before it finishes
+		var wg sync.WaitGroup
+		var record *Record
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, record, _ = cache.Begin("api/k1", "place|a", start)
+		}()
+		claim.Complete("ord-1")
+		wg.Wait()
+
+		// Then: The retry sees the first request's outcome
+		if record == nil || record.OrderID != "ord-1" {
+			t.Errorf("Expected the retry to see ord-1, got %+v", record)
+		}
+	})
+
+	t.Run("restore_drops_expired_records", func(t *testing.T) {
+		// Given: Persisted records, one older than the window
+		cache := NewCache(time.Hour)
+		records := []Record{
+			{Key: "api/old", Fingerprint: "place|a", OrderID: "ord-1", SeenAt: start.Add(-2 * time.Hour)},
+			{Key: "api/live", Fingerprint: "place|b", OrderID: "ord-2", SeenAt: start.Add(-time.Minute)},
+		}
+
+		// When: They are restored after a restart
+		kept := cache.Restore(records, start)
+
+		// Then: Only the live key is remembered
+		if len(kept) != 1 || kept[0].Key != "api/live" || cache.Len() != 1 {
+			t.Fatalf("Expected only the live record, got %+v", kept)
+		}
+		if _, record, _ := cache.Begin("api/live", "place|b", start); record == nil || record.OrderID != "ord-2" {
+			t.Errorf("Expected the restored key to replay ord-2, got %+v", record)
+		}
+		if claim, _, _ := cache.Begin("api/old", "place|a", start); claim == nil {
+			t.Error("Expected the expired key to be claimable again")
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package incidents
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the health of one venue component
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded" // Working, but something it does is failing
+	StatusDown     Status = "down"
+	StatusHalted   Status = "halted" // Instruments only: matching paused
+)
+
+// Transition is a component changing health
+type Transition struct {
+	Sequence  uint64    `json:"sequence"`
+	Component string    `json:"component"` // "<kind>:<name>", e.g. "dependency:data-adapter", "instrument:BTC-USD"
+	Status    Status    `json:"status"`
+	Previous  Status    `json:"previous"`
+	Detail    string    `json:"detail,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// Component is the latest health of a component that has had a transition
+type Component struct {
+	Component string    `json:"component"`
+	Status    Status    `json:"status"`
+	Detail    string    `json:"detail,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
+// Query selects transitions oldest first; zero fields match everything. Limit keeps
+// the latest matches.
+type Query struct {
+	Since     time.Time
+	Component string // Exact component, or a kind prefix ending in ':'
+	Limit     int
+}
+
+// Timeline keeps the latest health transitions in a ring buffer. Components are
+// assumed up until they report otherwise, so a healthy venue has an empty timeline.
+type Timeline struct {
+	entries  []Transition // Ring of at most capacity entries
+	start    int          // Index of the oldest entry once the ring is full
+	capacity int
+	sequence uint64
+	current  map[string]Component
+	mu       sync.Mutex
+}
+
+func NewTimeline(capacity int) *Timeline {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Timeline{
+		entries:  make([]Transition, 0, capacity),
+		capacity: capacity,
+		current:  make(map[string]Component),
+	}
+}
+
+// Record notes a component's status and reports whether it was a transition.
+// Repeating the current status, or first reporting up, records nothing.
+func (t *Timeline) Record(component string, status Status, detail string, at time.Time) (Transition, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := StatusUp
+	if known, exists := t.current[component]; exists {
+		previous = known.Status
+	}
+	if status == previous {
+		return Transition{}, false
+	}
+
+	t.sequence++
+	transition := Transition{
+		Sequence:  t.sequence,
+		Component: component,
+		Status:    status,
+		Previous:  previous,
+		Detail:    detail,
+		At:        at,
+	}
+	t.current[component] = Component{Component: component, Status: status, Detail: detail, Since: at}
+	if len(t.entries) < t.capacity {
+		t.entries = append(t.entries, transition)
+	} else {
+		t.entries[t.start] = transition
+		t.start = (t.start + 1) % t.capacity
+	}
+	return transition, true
+}
+
+// Status returns a component's latest status
+func (t *Timeline) Status(component string) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if known, exists := t.current[component]; exists {
+		return known.Status
+	}
+	return StatusUp
+}
+
+// Unhealthy lists components not currently up, sorted by name
+func (t *Timeline) Unhealthy() []Component {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	unhealthy := make([]Component, 0)
+	for _, component := range t.current {
+		if component.Status != StatusUp {
+			unhealthy = append(unhealthy, component)
+		}
+	}
+	sort.Slice(unhealthy, func(i, j int) bool { return unhealthy[i].Component < unhealthy[j].Component })
+	return unhealthy
+}
+
+// Transitions returns the retained transitions matching query, oldest first
+func (t *Timeline) Transitions(query Query) []Transition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	matched := make([]Transition, 0)
+	for i := range t.entries {
+		transition := t.entries[(t.start+i)%len(t.entries)]
+		if transition.At.Before(query.Since) || !matches(transition.Component, query.Component) {
+			continue
+		}
+		matched = append(matched, transition)
+	}
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[len(matched)-query.Limit:]
+	}
+	return matched
+}
+
+// Capacity is how many transitions the timeline retains
+func (t *Timeline) Capacity() int {
+	return t.capacity
+}
+
+func matches(component, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if strings.HasSuffix(filter, ":") {
+		return strings.HasPrefix(component, filter)
+	}
+	return component == filter
+}
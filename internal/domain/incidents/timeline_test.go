@@ -0,0 +1,76 @@
+//go:build unit
+
+package incidents
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("records_only_transitions", func(t *testing.T) {
+		// Given: A component that reports up, goes down twice in a row and recovers
+		timeline := NewTimeline(10)
+		timeline.Record("dependency:redis", StatusUp, "", start)
+		timeline.Record("dependency:redis", StatusDown, "dial tcp: refused", start.Add(time.Second))
+		timeline.Record("dependency:redis", StatusDown, "dial tcp: refused", start.Add(2*time.Second))
+		recovered, changed := timeline.Record("dependency:redis", StatusUp, "", start.Add(3*time.Second))
+
+		// When: The timeline is read
+		transitions := timeline.Transitions(Query{})
+
+		// Then: Only the outage and the recovery are recorded, each with what came before
+		if !changed || recovered.Previous != StatusDown || recovered.Sequence != 2 {
+			t.Errorf("Expected the recovery as the second transition, got %+v", recovered)
+		}
+		if len(transitions) != 2 || transitions[0].Status != StatusDown || transitions[0].Previous != StatusUp {
+			t.Errorf("Expected down then up, got %+v", transitions)
+		}
+		if len(timeline.Unhealthy()) != 0 || timeline.Status("dependency:redis") != StatusUp {
+			t.Errorf("Expected nothing unhealthy, got %+v", timeline.Unhealthy())
+		}
+	})
+
+	t.Run("keeps_the_latest_transitions_when_full", func(t *testing.T) {
+		// Given: Five transitions on a timeline holding three
+		timeline := NewTimeline(3)
+		for i := 0; i < 5; i++ {
+			status := StatusHalted
+			if i%2 == 1 {
+				status = StatusUp
+			}
+			timeline.Record("instrument:BTC-USD", status, "", start.Add(time.Duration(i)*time.Minute))
+		}
+
+		// When: Everything is read
+		transitions := timeline.Transitions(Query{})
+
+		// Then: The oldest two were dropped and order is preserved
+		if len(transitions) != 3 || transitions[0].Sequence != 3 || transitions[2].Sequence != 5 {
+			t.Errorf("Expected transitions 3 to 5, got %+v", transitions)
+		}
+		if unhealthy := timeline.Unhealthy(); len(unhealthy) != 1 || !unhealthy[0].Since.Equal(start.Add(4*time.Minute)) {
+			t.Errorf("Expected BTC-USD halted since the last transition, got %+v", unhealthy)
+		}
+	})
+
+	t.Run("filters_by_time_component_and_limit", func(t *testing.T) {
+		timeline := NewTimeline(10)
+		timeline.Record("instrument:BTC-USD", StatusHalted, "", start)
+		timeline.Record("storage:statistics", StatusDegraded, "disk full", start.Add(time.Minute))
+		timeline.Record("instrument:ETH-USD", StatusHalted, "", start.Add(2*time.Minute))
+		timeline.Record("instrument:BTC-USD", StatusUp, "", start.Add(3*time.Minute))
+
+		if got := timeline.Transitions(Query{Component: "instrument:"}); len(got) != 3 {
+			t.Errorf("Expected three instrument transitions, got %+v", got)
+		}
+		if got := timeline.Transitions(Query{Component: "instrument:BTC-USD", Since: start.Add(time.Second)}); len(got) != 1 || got[0].Status != StatusUp {
+			t.Errorf("Expected only the BTC-USD resumption, got %+v", got)
+		}
+		if got := timeline.Transitions(Query{Limit: 2}); len(got) != 2 || got[0].Component != "instrument:ETH-USD" {
+			t.Errorf("Expected the latest two, got %+v", got)
+		}
+	})
+}
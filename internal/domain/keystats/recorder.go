@@ -0,0 +1,219 @@
+package keystats
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// Anonymous is the key traffic is counted under when a caller sends none
+const Anonymous = "anonymous"
+
+// rateWindow is the lookback for message rates, kept as one bucket per second
+const rateWindow = 60
+
+type contextKey struct{}
+
+// WithAPIKey tags a request context with the caller's API key
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, contextKey{}, apiKey)
+}
+
+// Tagged reports whether a context was tagged with its caller's key, as every request
+// arriving through a transport is; the venue's own calls are not
+func Tagged(ctx context.Context) bool {
+	_, ok := ctx.Value(contextKey{}).(string)
+	return ok
+}
+
+// APIKey returns the key a request was tagged with, or Anonymous
+func APIKey(ctx context.Context) string {
+	if apiKey, ok := ctx.Value(contextKey{}).(string); ok && apiKey != "" {
+		return apiKey
+	}
+	return Anonymous
+}
+
+// Policy is the venue's messaging policy; zero limits are not enforced
+type Policy struct {
+	MaxMessageRate       float64 // Messages per second over the last minute
+	MaxOrderToTradeRatio float64 // Orders placed per trade
+}
+
+// Stats is the activity of one API key
+type Stats struct {
+	APIKey            string    `json:"api_key"`
+	Messages          int64     `json:"messages"`
+	Orders            int64     `json:"orders"`
+	Cancels           int64     `json:"cancels"`
+	Amends            int64     `json:"amends"`
+	Trades            int64     `json:"trades"`
+	Rejections        int64     `json:"rejections"`
+	MessageRate       float64   `json:"message_rate"`         // Per second over the last minute
+	OrderToTradeRatio float64   `json:"order_to_trade_ratio"` // Orders when no trades yet
+	RejectionRatio    float64   `json:"rejection_ratio"`      // Rejections per order action
+	PolicyBreaches    []string  `json:"policy_breaches"`
+	FirstSeenAt       time.Time `json:"first_seen_at"`
+	LastSeenAt        time.Time `json:"last_seen_at"`
+}
+
+type keyActivity struct {
+	stats   Stats
+	buckets [rateWindow]int64
+	seconds [rateWindow]int64 // Unix second each bucket currently counts
+}
+
+// Recorder counts messages, orders, trades and rejections per API key and
+// mirrors them to metrics labelled by key
+type Recorder struct {
+	policy  Policy
+	metrics ports.MetricsPort
+	keys    map[string]*keyActivity
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// NewRecorder accepts a nil metrics port when metrics are disabled
+func NewRecorder(policy Policy, metrics ports.MetricsPort) *Recorder {
+	return &Recorder{
+		policy:  policy,
+		metrics: metrics,
+		keys:    make(map[string]*keyActivity),
+		now:     time.Now,
+	}
+}
+
+// Message counts one inbound API message on a channel ("http" or "grpc")
+func (r *Recorder) Message(apiKey, channel string) {
+	r.mu.Lock()
+	activity := r.touch(apiKey)
+	activity.stats.Messages++
+	now := r.now().Unix()
+	slot := now % rateWindow
+	if activity.seconds[slot] != now {
+		activity.seconds[slot] = now
+		activity.buckets[slot] = 0
+	}
+	activity.buckets[slot]++
+	rate := activity.messageRate(now)
+	r.mu.Unlock()
+
+	if r.metrics != nil {
+		r.metrics.IncCounter("api_key_messages_total", map[string]string{"api_key": apiKey, "channel": channel})
+		r.metrics.SetGauge("api_key_message_rate", rate, map[string]string{"api_key": apiKey})
+	}
+}
+
+// OrderPlaced counts an accepted order and the trades it executed
+func (r *Recorder) OrderPlaced(apiKey string, trades int) {
+	r.update(apiKey, "api_key_orders_total", func(stats *Stats) {
+		stats.Orders++
+		stats.Trades += int64(trades)
+	})
+}
+
+// OrderCanceled counts a successful cancel
+func (r *Recorder) OrderCanceled(apiKey string) {
+	r.update(apiKey, "api_key_cancels_total", func(stats *Stats) { stats.Cancels++ })
+}
+
+// OrderAmended counts a successful amend and the trades it executed
+func (r *Recorder) OrderAmended(apiKey string, trades int) {
+	r.update(apiKey, "api_key_amends_total", func(stats *Stats) {
+		stats.Amends++
+		stats.Trades += int64(trades)
+	})
+}
+
+// Rejected counts an order action the venue refused
+func (r *Recorder) Rejected(apiKey string) {
+	r.update(apiKey, "api_key_rejections_total", func(stats *Stats) { stats.Rejections++ })
+}
+
+// Stats returns the activity of one key
+func (r *Recorder) Stats(apiKey string) (Stats, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	activity, exists := r.keys[apiKey]
+	if !exists {
+		return Stats{}, false
+	}
+	return r.snapshot(activity), true
+}
+
+// All returns the activity of every key seen, sorted by key
+func (r *Recorder) All() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all := make([]Stats, 0, len(r.keys))
+	for _, activity := range r.keys {
+		all = append(all, r.snapshot(activity))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].APIKey < all[j].APIKey })
+	return all
+}
+
+func (r *Recorder) update(apiKey, counter string, apply func(stats *Stats)) {
+	r.mu.Lock()
+	activity := r.touch(apiKey)
+	apply(&activity.stats)
+	stats := r.snapshot(activity)
+	r.mu.Unlock()
+
+	if r.metrics != nil {
+		labels := map[string]string{"api_key": apiKey}
+		r.metrics.IncCounter(counter, labels)
+		r.metrics.SetGauge("api_key_order_to_trade_ratio", stats.OrderToTradeRatio, labels)
+		r.metrics.SetGauge("api_key_rejection_ratio", stats.RejectionRatio, labels)
+	}
+}
+
+func (r *Recorder) touch(apiKey string) *keyActivity {
+	now := r.now()
+	activity, exists := r.keys[apiKey]
+	if !exists {
+		activity = &keyActivity{stats: Stats{APIKey: apiKey, FirstSeenAt: now}}
+		r.keys[apiKey] = activity
+	}
+	activity.stats.LastSeenAt = now
+	return activity
+}
+
+// snapshot derives rates and ratios from the raw counters
+func (r *Recorder) snapshot(activity *keyActivity) Stats {
+	stats := activity.stats
+	stats.MessageRate = activity.messageRate(r.now().Unix())
+
+	stats.OrderToTradeRatio = float64(stats.Orders)
+	if stats.Trades > 0 {
+		stats.OrderToTradeRatio = float64(stats.Orders) / float64(stats.Trades)
+	}
+	if actions := stats.Orders + stats.Cancels + stats.Amends + stats.Rejections; actions > 0 {
+		stats.RejectionRatio = float64(stats.Rejections) / float64(actions)
+	}
+
+	stats.PolicyBreaches = make([]string, 0)
+	if r.policy.MaxMessageRate > 0 && stats.MessageRate > r.policy.MaxMessageRate {
+		stats.PolicyBreaches = append(stats.PolicyBreaches, "message_rate")
+	}
+	if r.policy.MaxOrderToTradeRatio > 0 && stats.OrderToTradeRatio > r.policy.MaxOrderToTradeRatio {
+		stats.PolicyBreaches = append(stats.PolicyBreaches, "order_to_trade_ratio")
+	}
+	return stats
+}
+
+// messageRate averages the buckets still inside the window ending at now
+func (a *keyActivity) messageRate(now int64) float64 {
+	total := int64(0)
+	for i, second := range a.seconds {
+		if now-second < rateWindow {
+			total += a.buckets[i]
+		}
+	}
+	return float64(total) / rateWindow
+}
@@ -0,0 +1,75 @@
+//go:build unit
+
+package keystats
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("derives_ratios_from_counters", func(t *testing.T) {
+		// Given: A key that placed four orders, traded twice and was rejected once
+		recorder := NewRecorder(Policy{}, nil)
+		for i := 0; i < 3; i++ {
+			recorder.OrderPlaced("bot", 0)
+		}
+		recorder.OrderPlaced("bot", 2)
+		recorder.Rejected("bot")
+
+		// When: Its statistics are read
+		stats, exists := recorder.Stats("bot")
+
+		// Then: The ratios reflect the counters
+		if !exists {
+			t.Fatal("Expected statistics for bot")
+		}
+		if stats.Orders != 4 || stats.Trades != 2 || stats.Rejections != 1 {
+			t.Errorf("Unexpected counters: %+v", stats)
+		}
+		if stats.OrderToTradeRatio != 2 {
+			t.Errorf("Expected order-to-trade ratio 2, got %v", stats.OrderToTradeRatio)
+		}
+		if stats.RejectionRatio != 0.2 {
+			t.Errorf("Expected rejection ratio 0.2, got %v", stats.RejectionRatio)
+		}
+	})
+
+	t.Run("message_rate_covers_the_last_minute", func(t *testing.T) {
+		// Given: 120 messages two minutes ago and 30 just now
+		recorder := NewRecorder(Policy{MaxMessageRate: 0.25}, nil)
+		now := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+		recorder.now = func() time.Time { return now.Add(-2 * time.Minute) }
+		for i := 0; i < 120; i++ {
+			recorder.Message("bot", "http")
+		}
+		recorder.now = func() time.Time { return now }
+		for i := 0; i < 30; i++ {
+			recorder.Message("bot", "grpc")
+		}
+
+		// When: Its statistics are read
+		stats, _ := recorder.Stats("bot")
+
+		// Then: Only the recent messages count toward the rate, which breaches the policy
+		if stats.Messages != 150 {
+			t.Errorf("Expected 150 messages, got %d", stats.Messages)
+		}
+		if stats.MessageRate != 0.5 {
+			t.Errorf("Expected 0.5 messages per second, got %v", stats.MessageRate)
+		}
+		if len(stats.PolicyBreaches) != 1 || stats.PolicyBreaches[0] != "message_rate" {
+			t.Errorf("Expected a message rate breach, got %v", stats.PolicyBreaches)
+		}
+	})
+
+	t.Run("context_defaults_to_anonymous", func(t *testing.T) {
+		if key := APIKey(context.Background()); key != Anonymous {
+			t.Errorf("Expected %q, got %q", Anonymous, key)
+		}
+		if key := APIKey(WithAPIKey(context.Background(), "bot")); key != "bot" {
+			t.Errorf("Expected bot, got %q", key)
+		}
+	})
+}
@@ -0,0 +1,423 @@
+package ledger
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrUnbalanced refuses a transaction whose postings do not sum to zero per asset
+var ErrUnbalanced = errors.New("unbalanced journal transaction")
+
+// Contra accounts balance the postings that move assets into or out of the venue.
+// Their balances are the negative of what they put into trading accounts.
+const (
+	FundingAccount    = "venue:funding"    // Opening balances are drawn from it
+	ClearingAccount   = "venue:clearing"   // Restored fills settle through it; nets to zero once both sides are posted
+	SettlementAccount = "venue:settlement" // Funding settles through it; nets to zero when every position holder is funded
+
+	// InsuranceFundAccount is a venue account rather than a contra account: it is funded
+	// like a trading account and pays liquidation shortfalls out of its balance
+	InsuranceFundAccount = "venue:insurance"
+)
+
+// Bucket splits an account's holding of an asset
+type Bucket string
+
+const (
+	BucketAvailable Bucket = "available" // Free to commit to new orders
+	BucketHeld      Bucket = "held"      // Committed to working orders
+)
+
+// Kind is the mutation a journal transaction records
+type Kind string
+
+const (
+	KindFund      Kind = "fund"      // Opening balance credited from the funding account
+	KindHold      Kind = "hold"      // Available moved to held as an order rests or grows
+	KindRelease   Kind = "release"   // Held moved back to available as an order shrinks or leaves the book
+	KindFill      Kind = "fill"      // A trade delivered from held and received into available
+	KindRestore   Kind = "restore"   // A fill replayed from order state when the journal is rebuilt
+	KindFunding   Kind = "funding"   // Perpetual funding paid by one side of a contract to the other
+	KindInsurance Kind = "insurance" // A liquidation's loss beyond its bankruptcy price paid by the insurance fund
+	KindCorrupt   Kind = "corrupt"   // A single unbalanced leg injected to test reconciliation
+)
+
+// maxRecentPostings is how many postings are kept in memory for inspection; up to
+// twice as many are kept between trims
+const maxRecentPostings = 10000
+
+// Posting is one journal row: a signed amount into (positive) or out of (negative)
+// one bucket of an account's asset. The postings of a transaction sum to zero per asset.
+type Posting struct {
+	JournalID   string    `json:"journal_id"`
+	Sequence    uint64    `json:"sequence"`
+	Transaction uint64    `json:"transaction"`
+	Kind        Kind      `json:"kind"`
+	Reference   string    `json:"reference"` // Order ID for holds and releases, trade ID for fills
+	AccountID   string    `json:"account_id"`
+	Asset       string    `json:"asset"`
+	Bucket      Bucket    `json:"bucket"`
+	Amount      float64   `json:"amount"`
+	PostedAt    time.Time `json:"posted_at"`
+}
+
+// Leg is one side of a transaction before it is posted
+type Leg struct {
+	AccountID string
+	Asset     string
+	Bucket    Bucket
+	Amount    float64
+}
+
+// Balance is an account's holding of one asset
+type Balance struct {
+	Asset     string  `json:"asset"`
+	Available float64 `json:"available"`
+	Held      float64 `json:"held"`
+	Total     float64 `json:"total"` // Available plus held
+}
+
+// Hold is what a working order has committed of its account's balance
+type Hold struct {
+	OrderID   string  `json:"order_id"`
+	AccountID string  `json:"account_id"`
+	Asset     string  `json:"asset"`
+	Amount    float64 `json:"amount"`
+}
+
+// Fill is one spot trade as the journal settles it
+type Fill struct {
+	TradeID       string
+	BuyOrderID    string
+	SellOrderID   string
+	BuyAccountID  string
+	SellAccountID string
+	Base          string
+	Quote         string
+	Quantity      float64 // Base asset delivered by the seller
+	Notional      float64 // Quote asset delivered by the buyer
+}
+
+// Store persists journal postings. Balances are never read back: a restarted venue
+// rebuilds them from order state under a new journal ID.
+type Store interface {
+	Append(postings []Posting) error        // Postings already written are skipped
+	Reassign(accountID, alias string) error // Moves an erased account's postings to its alias
+}
+
+// Journal keeps double-entry balances per account and asset. Every change is a
+// transaction of postings summing to zero per asset, so the trial balance over
+// every account, contra accounts included, is always flat.
+type Journal struct {
+	id          string
+	balances    map[string]map[string]*Balance // Account ID, then asset
+	holds       map[string]*Hold               // By order ID
+	recent      []Posting                      // Newest last
+	sequence    uint64
+	transaction uint64
+	mu          sync.Mutex
+}
+
+// NewJournal starts an empty journal; id tells its postings apart from other runs'
+func NewJournal(id string) *Journal {
+	return &Journal{id: id, balances: make(map[string]map[string]*Balance), holds: make(map[string]*Hold)}
+}
+
+// ID identifies the journal's postings
+func (j *Journal) ID() string {
+	return j.id
+}
+
+// Post records one transaction and returns its postings. Legs of zero are dropped.
+func (j *Journal) Post(kind Kind, reference string, at time.Time, legs ...Leg) ([]Posting, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.post(kind, reference, at, legs)
+}
+
+// Fund credits an opening balance from the funding account
+func (j *Journal) Fund(accountID, asset string, amount float64, at time.Time) []Posting {
+	postings, _ := j.Post(KindFund, accountID, at,
+		Leg{AccountID: accountID, Asset: asset, Bucket: BucketAvailable, Amount: amount},
+		Leg{AccountID: FundingAccount, Asset: asset, Bucket: BucketAvailable, Amount: -amount})
+	return postings
+}
+
+// Corrupt posts amount to an account's available balance with no offsetting leg,
+// breaking the trial balance on purpose so reconciliation has something to find
+func (j *Journal) Corrupt(accountID, asset string, amount float64, at time.Time) []Posting {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.transaction++
+	j.sequence++
+	posting := Posting{
+		JournalID:   j.id,
+		Sequence:    j.sequence,
+		Transaction: j.transaction,
+		Kind:        KindCorrupt,
+		Reference:   accountID,
+		AccountID:   accountID,
+		Asset:       asset,
+		Bucket:      BucketAvailable,
+		Amount:      amount,
+		PostedAt:    at,
+	}
+	j.apply(Leg{AccountID: accountID, Asset: asset, Bucket: BucketAvailable, Amount: amount})
+	j.recent = append(j.recent, posting)
+	return []Posting{posting}
+}
+
+// Hold sets what an order holds of its account's asset, holding more or releasing
+// the excess. Zero releases the hold and forgets it.
+func (j *Journal) Hold(orderID, accountID, asset string, amount float64, at time.Time) []Posting {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	hold, exists := j.holds[orderID]
+	if !exists {
+		if amount <= 0 {
+			return nil
+		}
+		hold = &Hold{OrderID: orderID, AccountID: accountID, Asset: asset}
+		j.holds[orderID] = hold
+	}
+	delta := math.Max(amount, 0) - hold.Amount
+	if amount <= 0 {
+		delete(j.holds, orderID)
+	}
+	if isZero(delta) {
+		return nil
+	}
+	hold.Amount += delta
+
+	kind := KindHold
+	if delta < 0 {
+		kind = KindRelease
+	}
+	postings, _ := j.post(kind, orderID, at, []Leg{
+		{AccountID: hold.AccountID, Asset: hold.Asset, Bucket: BucketAvailable, Amount: -delta},
+		{AccountID: hold.AccountID, Asset: hold.Asset, Bucket: BucketHeld, Amount: delta},
+	})
+	return postings
+}
+
+// Settle posts a trade: each side delivers from its order's hold, then from
+// available for any shortfall, and receives into available
+func (j *Journal) Settle(fill Fill, at time.Time) []Posting {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	legs := make([]Leg, 0, 6)
+	legs = append(legs, j.deliver(fill.BuyOrderID, fill.BuyAccountID, fill.Quote, fill.Notional)...)
+	legs = append(legs, Leg{AccountID: fill.SellAccountID, Asset: fill.Quote, Bucket: BucketAvailable, Amount: fill.Notional})
+	legs = append(legs, j.deliver(fill.SellOrderID, fill.SellAccountID, fill.Base, fill.Quantity)...)
+	legs = append(legs, Leg{AccountID: fill.BuyAccountID, Asset: fill.Base, Bucket: BucketAvailable, Amount: fill.Quantity})
+	postings, _ := j.post(KindFill, fill.TradeID, at, legs)
+	return postings
+}
+
+// Restore posts an order's fills against the clearing account, for rebuilding a
+// journal from order state whose individual trades are no longer known
+func (j *Journal) Restore(orderID, accountID, base, quote string, side models.Side, quantity, notional float64, at time.Time) []Posting {
+	sign := side.Sign()
+	postings, _ := j.Post(KindRestore, orderID, at,
+		Leg{AccountID: accountID, Asset: base, Bucket: BucketAvailable, Amount: sign * quantity},
+		Leg{AccountID: ClearingAccount, Asset: base, Bucket: BucketAvailable, Amount: -sign * quantity},
+		Leg{AccountID: accountID, Asset: quote, Bucket: BucketAvailable, Amount: -sign * notional},
+		Leg{AccountID: ClearingAccount, Asset: quote, Bucket: BucketAvailable, Amount: sign * notional})
+	return postings
+}
+
+// Available is what an account may still commit of an asset
+func (j *Journal) Available(accountID, asset string) float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if balance, ok := j.balances[accountID][asset]; ok {
+		return balance.Available
+	}
+	return 0
+}
+
+// Balances returns every asset an account has held, sorted by asset
+func (j *Journal) Balances(accountID string) []Balance {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	balances := make([]Balance, 0, len(j.balances[accountID]))
+	for _, balance := range j.balances[accountID] {
+		balances = append(balances, *balance)
+	}
+	sort.Slice(balances, func(i, k int) bool { return balances[i].Asset < balances[k].Asset })
+	return balances
+}
+
+// TrialBalance sums every account's balances by asset; each total is zero when the
+// journal balances
+func (j *Journal) TrialBalance() []Balance {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	totals := make(map[string]*Balance)
+	for _, assets := range j.balances {
+		for asset, balance := range assets {
+			total, ok := totals[asset]
+			if !ok {
+				total = &Balance{Asset: asset}
+				totals[asset] = total
+			}
+			total.Available += balance.Available
+			total.Held += balance.Held
+			total.Total += balance.Total
+		}
+	}
+	trial := make([]Balance, 0, len(totals))
+	for _, total := range totals {
+		trial = append(trial, *total)
+	}
+	sort.Slice(trial, func(i, k int) bool { return trial[i].Asset < trial[k].Asset })
+	return trial
+}
+
+// Holds returns an account's working holds, sorted by order ID
+func (j *Journal) Holds(accountID string) []Hold {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	holds := make([]Hold, 0)
+	for _, hold := range j.holds {
+		if hold.AccountID == accountID {
+			holds = append(holds, *hold)
+		}
+	}
+	sort.Slice(holds, func(i, k int) bool { return holds[i].OrderID < holds[k].OrderID })
+	return holds
+}
+
+// Held is what an order currently holds, zero without a hold
+func (j *Journal) Held(orderID string) float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if hold, ok := j.holds[orderID]; ok {
+		return hold.Amount
+	}
+	return 0
+}
+
+// Postings returns up to limit of an account's most recent postings still in memory,
+// newest first (0 = all)
+func (j *Journal) Postings(accountID string, limit int) []Posting {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	postings := make([]Posting, 0)
+	for i := len(j.recent) - 1; i >= 0; i-- {
+		if limit > 0 && len(postings) == limit {
+			break
+		}
+		if accountID == "" || j.recent[i].AccountID == accountID {
+			postings = append(postings, j.recent[i])
+		}
+	}
+	return postings
+}
+
+// Reassign moves an erased account's balances, holds and postings to its alias
+func (j *Journal) Reassign(accountID, alias string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if assets, ok := j.balances[accountID]; ok {
+		j.balances[alias] = assets
+		delete(j.balances, accountID)
+	}
+	for _, hold := range j.holds {
+		if hold.AccountID == accountID {
+			hold.AccountID = alias
+		}
+	}
+	for i := range j.recent {
+		if j.recent[i].AccountID == accountID {
+			j.recent[i].AccountID = alias
+		}
+	}
+}
+
+// deliver is what an account gives up for a trade: from the order's hold first,
+// then from available
+func (j *Journal) deliver(orderID, accountID, asset string, amount float64) []Leg {
+	fromHeld := 0.0
+	if hold, ok := j.holds[orderID]; ok {
+		fromHeld = math.Min(hold.Amount, amount)
+		hold.Amount -= fromHeld
+	}
+	return []Leg{
+		{AccountID: accountID, Asset: asset, Bucket: BucketHeld, Amount: -fromHeld},
+		{AccountID: accountID, Asset: asset, Bucket: BucketAvailable, Amount: fromHeld - amount},
+	}
+}
+
+// post checks and applies a transaction; hold the journal lock
+func (j *Journal) post(kind Kind, reference string, at time.Time, legs []Leg) ([]Posting, error) {
+	sums := make(map[string]float64)
+	scale := make(map[string]float64)
+	for _, leg := range legs {
+		sums[leg.Asset] += leg.Amount
+		scale[leg.Asset] += math.Abs(leg.Amount)
+	}
+	for asset, sum := range sums {
+		if math.Abs(sum) > 1e-9*math.Max(1, scale[asset]) {
+			return nil, ErrUnbalanced
+		}
+	}
+
+	j.transaction++
+	postings := make([]Posting, 0, len(legs))
+	for _, leg := range legs {
+		if isZero(leg.Amount) {
+			continue
+		}
+		j.sequence++
+		postings = append(postings, Posting{
+			JournalID:   j.id,
+			Sequence:    j.sequence,
+			Transaction: j.transaction,
+			Kind:        kind,
+			Reference:   reference,
+			AccountID:   leg.AccountID,
+			Asset:       leg.Asset,
+			Bucket:      leg.Bucket,
+			Amount:      leg.Amount,
+			PostedAt:    at,
+		})
+		j.apply(leg)
+	}
+
+	j.recent = append(j.recent, postings...)
+	if len(j.recent) > 2*maxRecentPostings {
+		j.recent = append(j.recent[:0:0], j.recent[len(j.recent)-maxRecentPostings:]...)
+	}
+	return postings, nil
+}
+
+func (j *Journal) apply(leg Leg) {
+	assets, ok := j.balances[leg.AccountID]
+	if !ok {
+		assets = make(map[string]*Balance)
+		j.balances[leg.AccountID] = assets
+	}
+	balance, ok := assets[leg.Asset]
+	if !ok {
+		balance = &Balance{Asset: leg.Asset}
+		assets[leg.Asset] = balance
+	}
+	if leg.Bucket == BucketHeld {
+		balance.Held += leg.Amount
+	} else {
+		balance.Available += leg.Amount
+	}
+	balance.Total += leg.Amount
+}
+
+func isZero(amount float64) bool {
+	return math.Abs(amount) < 1e-12
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJournal(t *testing.T) {
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("fills_draw_on_the_hold_before_available", func(t *testing.T) {
+		// Given: A buyer holding 600 USD for a bid, and a seller without a hold
+		journal := NewJournal("j")
+		journal.Fund("buyer", "USD", 1000, at)
+		journal.Hold("bid", "buyer", "USD", 600, at)
+
+		// When: The bid buys 1 BTC at 590, then its 10 USD left over is released
+		fill := journal.Settle(Fill{TradeID: "t-1", BuyOrderID: "bid", SellOrderID: "ask", BuyAccountID: "buyer", SellAccountID: "seller",
+			Base: "BTC", Quote: "USD", Quantity: 1, Notional: 590}, at)
+		journal.Hold("bid", "buyer", "USD", 0, at)
+
+		// Then: The buyer paid from the hold, the seller went short the BTC it
+		// delivered, and the trade's postings net to zero per asset
+		usd := journal.Balances("buyer")[1]
+		if usd.Available != 410 || usd.Held != 0 || usd.Total != 410 {
+			t.Errorf("Expected 410 USD available and nothing held, got %+v", usd)
+		}
+		if btc := journal.Balances("seller")[0]; btc.Asset != "BTC" || btc.Available != -1 {
+			t.Errorf("Expected the seller short 1 BTC, got %+v", btc)
+		}
+		sums := make(map[string]float64)
+		for _, posting := range fill {
+			if posting.Transaction != fill[0].Transaction || posting.Kind != KindFill {
+				t.Fatalf("Expected one fill transaction, got %+v", fill)
+			}
+			sums[posting.Asset] += posting.Amount
+		}
+		if len(fill) != 4 || sums["USD"] != 0 || sums["BTC"] != 0 {
+			t.Errorf("Expected four postings netting to zero, got %+v", fill)
+		}
+		for _, total := range journal.TrialBalance() {
+			if total.Total != 0 {
+				t.Errorf("Expected a flat trial balance, got %+v", total)
+			}
+		}
+		if len(journal.Holds("buyer")) != 0 {
+			t.Errorf("Expected the released hold to be forgotten, got %+v", journal.Holds("buyer"))
+		}
+	})
+
+	t.Run("refuses_unbalanced_transactions", func(t *testing.T) {
+		journal := NewJournal("j")
+		_, err := journal.Post(KindFund, "x", at, Leg{AccountID: "a", Asset: "USD", Bucket: BucketAvailable, Amount: 1})
+		if !errors.Is(err, ErrUnbalanced) || len(journal.Postings("", 0)) != 0 {
+			t.Errorf("Expected an unbalanced transaction to post nothing, got %v", err)
+		}
+	})
+
+	t.Run("reassigns_an_erased_account_to_its_alias", func(t *testing.T) {
+		journal := NewJournal("j")
+		journal.Fund("a", "USD", 100, at)
+		journal.Hold("o-1", "a", "USD", 40, at)
+
+		journal.Reassign("a", "anon-1")
+
+		if len(journal.Balances("a")) != 0 || len(journal.Postings("a", 0)) != 0 {
+			t.Error("Expected nothing left under the erased account")
+		}
+		if holds := journal.Holds("anon-1"); len(holds) != 1 || journal.Available("anon-1", "USD") != 60 {
+			t.Errorf("Expected the alias to hold 40 of 100 USD, got %+v", holds)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package ledger
+
+import (
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Position is an account's net holding of one asset, summed over every book that moves it
+type Position struct {
+	Asset     string   `json:"asset"`
+	Quantity  float64  `json:"quantity"` // Opening plus received minus delivered; negative is a short
+	Opening   float64  `json:"opening"`  // Funded when the account was provisioned
+	Received  float64  `json:"received"`
+	Delivered float64  `json:"delivered"`
+	Symbols   []string `json:"symbols"` // Books that moved the asset, sorted
+}
+
+type entry struct {
+	opening   float64
+	received  float64
+	delivered float64
+	symbols   map[string]struct{}
+}
+
+// Ledger accumulates spot fills into per-asset positions. A buy receives the base
+// asset and delivers the notional in the quote asset; a sell does the reverse. Books
+// sharing an asset post to the same position, so BTC bought for USD and sold for EUR
+// leaves a flat BTC position with the two cash legs open.
+type Ledger struct {
+	entries map[string]*entry
+}
+
+func New() *Ledger {
+	return &Ledger{entries: make(map[string]*entry)}
+}
+
+// Post books a fill of quantity base asset for notional quote asset on symbol
+func (l *Ledger) Post(symbol, base, quote string, side models.Side, quantity, notional float64) {
+	received, delivered := base, quote
+	if side == models.SideSell {
+		received, delivered = quote, base
+	}
+	receivedAmount, deliveredAmount := quantity, notional
+	if side == models.SideSell {
+		receivedAmount, deliveredAmount = notional, quantity
+	}
+
+	l.entry(received, symbol).received += receivedAmount
+	l.entry(delivered, symbol).delivered += deliveredAmount
+}
+
+// Fund books an opening balance of an asset, moved by no book
+func (l *Ledger) Fund(asset string, amount float64) {
+	l.asset(asset).opening += amount
+}
+
+// Positions returns every asset the ledger has moved, sorted by asset
+func (l *Ledger) Positions() []Position {
+	positions := make([]Position, 0, len(l.entries))
+	for asset := range l.entries {
+		positions = append(positions, l.Position(asset))
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Asset < positions[j].Asset })
+	return positions
+}
+
+// Position returns the net holding of one asset, zero if it never moved
+func (l *Ledger) Position(asset string) Position {
+	position := Position{Asset: asset, Symbols: make([]string, 0)}
+	e, exists := l.entries[asset]
+	if !exists {
+		return position
+	}
+	position.Opening = e.opening
+	position.Received = e.received
+	position.Delivered = e.delivered
+	position.Quantity = e.opening + e.received - e.delivered
+	for symbol := range e.symbols {
+		position.Symbols = append(position.Symbols, symbol)
+	}
+	sort.Strings(position.Symbols)
+	return position
+}
+
+func (l *Ledger) entry(asset, symbol string) *entry {
+	e := l.asset(asset)
+	e.symbols[symbol] = struct{}{}
+	return e
+}
+
+func (l *Ledger) asset(asset string) *entry {
+	e, exists := l.entries[asset]
+	if !exists {
+		e = &entry{symbols: make(map[string]struct{})}
+		l.entries[asset] = e
+	}
+	return e
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestLedger(t *testing.T) {
+	t.Run("books_sharing_a_base_asset_net_into_one_position", func(t *testing.T) {
+		// Given: BTC bought for USD and sold for EUR
+		l := New()
+		l.Post("BTC-USD", "BTC", "USD", models.SideBuy, 2, 120000)
+		l.Post("BTC-EUR", "BTC", "EUR", models.SideSell, 1.5, 82500)
+
+		// When: The BTC position is read
+		btc := l.Position("BTC")
+
+		// Then: Both books contribute to it and the cash legs stay separate
+		if btc.Quantity != 0.5 || btc.Received != 2 || btc.Delivered != 1.5 {
+			t.Errorf("Unexpected BTC position: %+v", btc)
+		}
+		if len(btc.Symbols) != 2 || btc.Symbols[0] != "BTC-EUR" || btc.Symbols[1] != "BTC-USD" {
+			t.Errorf("Expected both books, got %v", btc.Symbols)
+		}
+		if usd := l.Position("USD"); usd.Quantity != -120000 {
+			t.Errorf("Expected -120000 USD, got %v", usd.Quantity)
+		}
+		if eur := l.Position("EUR"); eur.Quantity != 82500 {
+			t.Errorf("Expected 82500 EUR, got %v", eur.Quantity)
+		}
+	})
+
+	t.Run("lists_positions_by_asset", func(t *testing.T) {
+		l := New()
+		l.Post("ETH-BTC", "ETH", "BTC", models.SideBuy, 10, 0.5)
+
+		positions := l.Positions()
+		if len(positions) != 2 || positions[0].Asset != "BTC" || positions[1].Asset != "ETH" {
+			t.Errorf("Expected BTC then ETH, got %+v", positions)
+		}
+		if untouched := l.Position("SOL"); untouched.Quantity != 0 || len(untouched.Symbols) != 0 {
+			t.Errorf("Expected an empty position, got %+v", untouched)
+		}
+	})
+
+	t.Run("opening_balances_fund_positions_without_a_book", func(t *testing.T) {
+		// Given: An account funded with USD that then buys BTC
+		l := New()
+		l.Fund("USD", 100000)
+		l.Post("BTC-USD", "BTC", "USD", models.SideBuy, 1, 60000)
+
+		// When: The USD position is read
+		usd := l.Position("USD")
+
+		// Then: The opening balance is netted with the fill's cash leg
+		if usd.Quantity != 40000 || usd.Opening != 100000 || usd.Delivered != 60000 || len(usd.Symbols) != 1 {
+			t.Errorf("Unexpected USD position: %+v", usd)
+		}
+	})
+}
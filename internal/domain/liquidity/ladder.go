@@ -0,0 +1,95 @@
+package liquidity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Ladder shapes the resting liquidity quoted around a reference price
+type Ladder struct {
+	Levels        int     `json:"levels"`                   // Per side
+	SpreadBps     float64 `json:"spread_bps"`               // Between the best bid and the best ask
+	StepBps       float64 `json:"step_bps"`                 // Between levels on a side
+	LevelNotional float64 `json:"level_notional,omitempty"` // Quote currency resting at each level
+	LevelQuantity float64 `json:"level_quantity,omitempty"` // Base quantity resting at each level, instead of a notional
+}
+
+// Quote is one resting order of a ladder
+type Quote struct {
+	Side     models.Side
+	Price    float64
+	Quantity float64
+}
+
+// Validate checks the ladder quotes at least one level of positive size
+func (l Ladder) Validate() error {
+	if l.Levels < 1 {
+		return errors.New("ladder needs at least one level per side")
+	}
+	if l.SpreadBps <= 0 || l.StepBps < 0 {
+		return errors.New("spread must be positive and step not negative")
+	}
+	if (l.LevelNotional > 0) == (l.LevelQuantity > 0) {
+		return errors.New("ladder needs exactly one of a positive level notional or level quantity")
+	}
+	return nil
+}
+
+// Quotes lays the ladder around mid on an instrument's tick and lot grid, best
+// levels first. Bids round down and asks up, so the book never crosses mid; levels
+// the tick collapses onto one price are quoted once.
+func (l Ladder) Quotes(mid float64, instrument models.Instrument) []Quote {
+	quotes := make([]Quote, 0, 2*l.Levels)
+	for _, side := range []models.Side{models.SideBuy, models.SideSell} {
+		last := math.NaN()
+		for level := 0; level < l.Levels; level++ {
+			offset := (l.SpreadBps/2 + float64(level)*l.StepBps) / 10000
+			var price float64
+			if side == models.SideBuy {
+				price = floorTo(mid*(1-offset), instrument.TickSize)
+			} else {
+				price = ceilTo(mid*(1+offset), instrument.TickSize)
+			}
+			if price <= 0 || price == last {
+				continue
+			}
+			last = price
+			quotes = append(quotes, Quote{Side: side, Price: price, Quantity: l.quantity(price, instrument)})
+		}
+	}
+	return quotes
+}
+
+// quantity is the size of a level at price
+func (l Ladder) quantity(price float64, instrument models.Instrument) float64 {
+	if l.LevelQuantity > 0 {
+		return math.Max(floorTo(l.LevelQuantity, instrument.LotSize), instrument.MinQuantity)
+	}
+	return Quantity(l.LevelNotional, price, instrument)
+}
+
+// Quantity is notional at price in the instrument's lots, at least its minimum
+func Quantity(notional, price float64, instrument models.Instrument) float64 {
+	return math.Max(floorTo(notional/price, instrument.LotSize), instrument.MinQuantity)
+}
+
+// gridEpsilon keeps values already on a grid from rounding to the next step
+const gridEpsilon = 1e-9
+
+// floorTo rounds value down onto a grid of step; no step leaves it
+func floorTo(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Floor(value/step+gridEpsilon) * step
+}
+
+// ceilTo rounds value up onto a grid of step; no step leaves it
+func ceilTo(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Ceil(value/step-gridEpsilon) * step
+}
@@ -0,0 +1,69 @@
+//go:build unit
+
+package liquidity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestLadder(t *testing.T) {
+	instrument := models.Instrument{Symbol: "BTC-USD", TickSize: 0.01, LotSize: 0.0001, MinQuantity: 0.0001}
+
+	t.Run("quotes_on_the_grid_around_mid", func(t *testing.T) {
+		// Given: Two levels a side, 10bps wide with 5bps between levels
+		ladder := Ladder{Levels: 2, SpreadBps: 10, StepBps: 5, LevelNotional: 6000}
+
+		// When: It is laid around a mid between ticks
+		quotes := ladder.Quotes(60000.005, instrument)
+
+		// Then: Bids round down and asks up onto the tick, sized to the notional in lots
+		want := []Quote{
+			{Side: models.SideBuy, Price: 59970.00, Quantity: 0.1},
+			{Side: models.SideBuy, Price: 59940.00, Quantity: 0.1001},
+			{Side: models.SideSell, Price: 60030.01, Quantity: 0.0999},
+			{Side: models.SideSell, Price: 60060.01, Quantity: 0.0999},
+		}
+		if len(quotes) != len(want) {
+			t.Fatalf("Expected %d quotes, got %+v", len(want), quotes)
+		}
+		for i := range want {
+			if quotes[i].Side != want[i].Side || math.Abs(quotes[i].Price-want[i].Price) > 1e-9 || math.Abs(quotes[i].Quantity-want[i].Quantity) > 1e-12 {
+				t.Errorf("Quote %d: expected %+v, got %+v", i, want[i], quotes[i])
+			}
+		}
+	})
+
+	t.Run("coarse_ticks_collapse_levels", func(t *testing.T) {
+		// Given: Levels a fraction of a tick apart
+		coarse := models.Instrument{Symbol: "X", TickSize: 1, LotSize: 1, MinQuantity: 1}
+		ladder := Ladder{Levels: 3, SpreadBps: 10, StepBps: 1, LevelNotional: 1}
+
+		// When: It is laid around 100
+		quotes := ladder.Quotes(100, coarse)
+
+		// Then: Each side quotes one price, at the minimum size
+		if len(quotes) != 2 || quotes[0].Price != 99 || quotes[1].Price != 101 || quotes[0].Quantity != 1 {
+			t.Errorf("Expected one bid at 99 and one ask at 101, got %+v", quotes)
+		}
+	})
+	t.Run("sizes_levels_in_base_quantity", func(t *testing.T) {
+		// Given: A ladder sized at 0.55 BTC a level, and ones sized both ways or neither
+		ladder := Ladder{Levels: 1, SpreadBps: 10, LevelQuantity: 0.55}
+
+		// When: It is laid around 60000
+		quotes := ladder.Quotes(60000, instrument)
+
+		// Then: Each level rests the quantity whatever its price
+		if len(quotes) != 2 || quotes[0].Quantity != 0.55 || quotes[1].Quantity != 0.55 {
+			t.Errorf("Expected 0.55 a level, got %+v", quotes)
+		}
+		for _, invalid := range []Ladder{{Levels: 1, SpreadBps: 10}, {Levels: 1, SpreadBps: 10, LevelNotional: 1, LevelQuantity: 1}} {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("Expected %+v to be rejected", invalid)
+			}
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Package logfields carries the identifiers a request's log entries are tagged with
+// through its context, so every component handling one order logs the same IDs and
+// log aggregation can follow it across them.
+package logfields
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+)
+
+// Field names, shared by every component's log entries
+const (
+	RequestID     = "request_id"
+	CorrelationID = "correlation_id"
+	AccountID     = "account_id"
+	OrderID       = "order_id"
+	Component     = "component" // Part of the venue logging, so its level and sampling can be set apart
+)
+
+// Components tagging their high-volume entries
+const (
+	ComponentMatchingEngine = "matching_engine" // Orders placed, canceled and amended
+	ComponentHTTP           = "http"            // REST request outcomes
+	ComponentGRPC           = "grpc"            // gRPC call outcomes
+	ComponentFIX            = "fix"             // FIX sessions
+)
+
+// ids is what a context has been tagged with; contexts hold it by value, so tagging
+// a child never changes its parent
+type ids struct {
+	requestID string
+	accountID string
+	orderID   string
+}
+
+type contextKey struct{}
+
+func from(ctx context.Context) ids {
+	tagged, _ := ctx.Value(contextKey{}).(ids)
+	return tagged
+}
+
+// WithRequestID tags a request context with the ID of the one call it serves
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	tagged := from(ctx)
+	tagged.requestID = requestID
+	return context.WithValue(ctx, contextKey{}, tagged)
+}
+
+// WithAccountID tags a request context with the account it acts for; empty leaves it as is
+func WithAccountID(ctx context.Context, accountID string) context.Context {
+	if accountID == "" {
+		return ctx
+	}
+	tagged := from(ctx)
+	tagged.accountID = accountID
+	return context.WithValue(ctx, contextKey{}, tagged)
+}
+
+// WithOrderID tags a request context with the order it acts on; empty leaves it as is
+func WithOrderID(ctx context.Context, orderID string) context.Context {
+	if orderID == "" {
+		return ctx
+	}
+	tagged := from(ctx)
+	tagged.orderID = orderID
+	return context.WithValue(ctx, contextKey{}, tagged)
+}
+
+// RequestIDOf returns the request ID a context was tagged with, or ""
+func RequestIDOf(ctx context.Context) string {
+	return from(ctx).requestID
+}
+
+// Fields returns the IDs a context was tagged with by field name, the correlation
+// ID its audit events carry included; IDs it was not tagged with are left out
+func Fields(ctx context.Context) map[string]string {
+	tagged := from(ctx)
+	fields := make(map[string]string, 4)
+	for name, value := range map[string]string{
+		RequestID:     tagged.requestID,
+		CorrelationID: audit.CorrelationID(ctx),
+		AccountID:     tagged.accountID,
+		OrderID:       tagged.orderID,
+	} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	return fields
+}
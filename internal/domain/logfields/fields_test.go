@@ -0,0 +1,44 @@
+//go:build unit
+
+package logfields
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+)
+
+func TestFields(t *testing.T) {
+	t.Run("collects_every_id_a_context_was_tagged_with", func(t *testing.T) {
+		// Given: A request context tagged step by step as a request is handled
+		ctx := WithRequestID(context.Background(), "req-1")
+		ctx = audit.WithCorrelationID(ctx, "corr-1")
+		parent := WithAccountID(ctx, "acct-1")
+		child := WithOrderID(parent, "BTC-USD-1")
+
+		// When: The fields are read at each step
+		fields := Fields(child)
+
+		// Then: The child has all four, without tagging its parent
+		expected := map[string]string{RequestID: "req-1", CorrelationID: "corr-1", AccountID: "acct-1", OrderID: "BTC-USD-1"}
+		for name, value := range expected {
+			if fields[name] != value {
+				t.Errorf("Expected %s=%s, got %v", name, value, fields)
+			}
+		}
+		if _, tagged := Fields(parent)[OrderID]; tagged {
+			t.Error("Expected the parent not to have the order ID")
+		}
+	})
+
+	t.Run("leaves_out_ids_never_tagged", func(t *testing.T) {
+		// Given: A context tagged with an empty account ID only
+		ctx := WithAccountID(context.Background(), "")
+
+		// When/Then: No fields are returned
+		if fields := Fields(ctx); len(fields) != 0 {
+			t.Errorf("Expected no fields, got %v", fields)
+		}
+	})
+}
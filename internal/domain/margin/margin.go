@@ -0,0 +1,135 @@
+package margin
+
+import (
+	"math"
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// epsilon is below any lot size; smaller positions are flat
+const epsilon = 1e-12
+
+// Position is an account's net holding in one derivative, valued at the mark
+type Position struct {
+	Symbol            string  `json:"symbol"`
+	Quantity          float64 `json:"quantity"`    // Contracts; negative is a short
+	EntryPrice        float64 `json:"entry_price"` // Average price the open quantity was taken at
+	MarkPrice         float64 `json:"mark_price"`
+	Notional          float64 `json:"notional"` // Absolute quantity at the mark
+	UnrealizedPnL     float64 `json:"unrealized_pnl"`
+	RealizedPnL       float64 `json:"realized_pnl"` // From quantity closed so far
+	Leverage          float64 `json:"leverage"`     // Applied to the initial margin
+	InitialMargin     float64 `json:"initial_margin"`
+	MaintenanceMargin float64 `json:"maintenance_margin"`
+}
+
+// Value marks the position and sets its margin at leverage and the maintenance rate
+func (p *Position) Value(mark, leverage, maintenanceRate float64) {
+	p.MarkPrice = mark
+	p.Notional = math.Abs(p.Quantity) * mark
+	p.UnrealizedPnL = p.Quantity * (mark - p.EntryPrice)
+	p.Leverage = leverage
+	p.InitialMargin = p.Notional / leverage
+	p.MaintenanceMargin = p.Notional * maintenanceRate
+}
+
+// Book accumulates derivative fills into positions. Adding to a position moves its
+// entry price to the average; reducing it realizes the PnL of the closed quantity,
+// and reversing it opens the remainder at the fill price.
+type Book struct {
+	positions map[string]*Position
+}
+
+func NewBook() *Book {
+	return &Book{positions: make(map[string]*Position)}
+}
+
+// Fill books quantity contracts bought or sold on symbol at price
+func (b *Book) Fill(symbol string, side models.Side, quantity, price float64) {
+	position, ok := b.positions[symbol]
+	if !ok {
+		position = &Position{Symbol: symbol}
+		b.positions[symbol] = position
+	}
+	signed := side.Sign() * quantity
+	held := position.Quantity
+
+	if math.Abs(held) < epsilon || (held > 0) == (signed > 0) {
+		total := math.Abs(held) + quantity
+		position.EntryPrice = (math.Abs(held)*position.EntryPrice + quantity*price) / total
+		position.Quantity = held + signed
+		return
+	}
+
+	closed := math.Min(quantity, math.Abs(held))
+	position.RealizedPnL += closed * (price - position.EntryPrice) * math.Copysign(1, held)
+	position.Quantity = held + signed
+	switch {
+	case math.Abs(position.Quantity) < epsilon:
+		position.Quantity, position.EntryPrice = 0, 0
+	case (position.Quantity > 0) != (held > 0):
+		position.EntryPrice = price
+	}
+}
+
+// Positions returns every symbol the book has filled, flat ones included for their
+// realized PnL, sorted by symbol
+func (b *Book) Positions() []Position {
+	positions := make([]Position, 0, len(b.positions))
+	for _, position := range b.positions {
+		positions = append(positions, *position)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+	return positions
+}
+
+// Summary is one collateral pool: an account's balance in a settlement asset and the
+// positions margined against it
+type Summary struct {
+	Asset             string     `json:"asset"`
+	Collateral        float64    `json:"collateral"` // Available balance in the asset
+	RealizedPnL       float64    `json:"realized_pnl"`
+	UnrealizedPnL     float64    `json:"unrealized_pnl"`
+	Equity            float64    `json:"equity"` // Collateral plus realized and unrealized PnL
+	Notional          float64    `json:"notional"`
+	Borrowed          float64    `json:"borrowed"` // Notional financed beyond equity
+	Leverage          float64    `json:"leverage"` // Notional over equity; 0 without equity
+	InitialMargin     float64    `json:"initial_margin"`
+	MaintenanceMargin float64    `json:"maintenance_margin"`
+	MarginRatio       float64    `json:"margin_ratio"` // Equity over maintenance margin; 0 without positions
+	Breached          bool       `json:"breached"`     // Equity below maintenance margin
+	Positions         []Position `json:"positions"`    // Open positions only
+}
+
+// Summarize totals valued positions against collateral in asset
+func Summarize(asset string, collateral float64, positions []Position) Summary {
+	summary := Summary{Asset: asset, Collateral: collateral, Positions: make([]Position, 0, len(positions))}
+	for _, position := range positions {
+		summary.RealizedPnL += position.RealizedPnL
+		if position.Quantity == 0 {
+			continue
+		}
+		summary.UnrealizedPnL += position.UnrealizedPnL
+		summary.Notional += position.Notional
+		summary.InitialMargin += position.InitialMargin
+		summary.MaintenanceMargin += position.MaintenanceMargin
+		summary.Positions = append(summary.Positions, position)
+	}
+	summary.Equity = collateral + summary.RealizedPnL + summary.UnrealizedPnL
+	summary.Borrowed = math.Max(0, summary.Notional-summary.Equity)
+	if summary.Equity > 0 {
+		summary.Leverage = summary.Notional / summary.Equity
+	}
+	if summary.MaintenanceMargin > 0 {
+		summary.MarginRatio = summary.Equity / summary.MaintenanceMargin
+		summary.Breached = summary.Equity < summary.MaintenanceMargin
+	}
+	return summary
+}
+
+// FreeMargin is the equity left to open positions with once the open positions and
+// commitments (the initial margin of working orders) are covered
+func (s Summary) FreeMargin(committed float64) float64 {
+	return s.Equity - s.InitialMargin - committed
+}
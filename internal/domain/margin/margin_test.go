@@ -0,0 +1,58 @@
+//go:build unit
+
+package margin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestBook(t *testing.T) {
+	t.Run("averages_entries_and_realizes_closed_quantity", func(t *testing.T) {
+		// Given: A long of 1 at 100 and 1 at 110
+		book := NewBook()
+		book.Fill("BTC-USD-PERP", models.SideBuy, 1, 100)
+		book.Fill("BTC-USD-PERP", models.SideBuy, 1, 110)
+
+		// When: 3 are sold at 120, closing the long and opening a short of 1
+		book.Fill("BTC-USD-PERP", models.SideSell, 3, 120)
+
+		// Then: The long realized 2 × (120 - 105), and the short entered at 120
+		positions := book.Positions()
+		if len(positions) != 1 {
+			t.Fatalf("Expected one position, got %+v", positions)
+		}
+		position := positions[0]
+		if position.Quantity != -1 || position.EntryPrice != 120 || math.Abs(position.RealizedPnL-30) > 1e-9 {
+			t.Errorf("Expected short 1 at 120 with 30 realized, got %+v", position)
+		}
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	t.Run("breaches_when_equity_falls_below_maintenance", func(t *testing.T) {
+		// Given: A 10 contract long at 100 against 100 of collateral
+		book := NewBook()
+		book.Fill("X-PERP", models.SideBuy, 10, 100)
+		position := book.Positions()[0]
+
+		// When: The mark falls from 100 to 95, then to 91
+		position.Value(95, 10, 0.05)
+		healthy := Summarize("USD", 100, []Position{position})
+		position.Value(91, 10, 0.05)
+		breached := Summarize("USD", 100, []Position{position})
+
+		// Then: Equity 50 covers the 47.5 maintenance, equity 10 does not cover 45.5
+		if healthy.Equity != 50 || healthy.Breached || healthy.Borrowed != 900 || healthy.Leverage != 19 {
+			t.Errorf("Expected a healthy 19x account, got %+v", healthy)
+		}
+		if breached.Equity != 10 || !breached.Breached || breached.MarginRatio >= 1 {
+			t.Errorf("Expected a breach, got %+v", breached)
+		}
+		if free := healthy.FreeMargin(0); free != 50-95 {
+			t.Errorf("Expected free margin of -45, got %v", free)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package marketdata
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interval is a kline bar width
+type Interval string
+
+const (
+	Interval1s  Interval = "1s"
+	Interval1m  Interval = "1m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval1h  Interval = "1h"
+	Interval4h  Interval = "4h"
+	Interval1d  Interval = "1d"
+)
+
+// Intervals lists every supported kline interval, narrowest first
+var Intervals = []Interval{Interval1s, Interval1m, Interval5m, Interval15m, Interval1h, Interval4h, Interval1d}
+
+var intervalDurations = map[Interval]time.Duration{
+	Interval1s:  time.Second,
+	Interval1m:  time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval1d:  24 * time.Hour,
+}
+
+// ParseInterval validates an interval string
+func ParseInterval(s string) (Interval, error) {
+	interval := Interval(s)
+	if _, ok := intervalDurations[interval]; !ok {
+		return "", fmt.Errorf("invalid interval: %q", s)
+	}
+	return interval, nil
+}
+
+func (i Interval) Duration() time.Duration {
+	return intervalDurations[i]
+}
+
+// CandleArchive keeps closed bars for longer than the tracker retains them
+type CandleArchive interface {
+	// Append stores closed bars of one series, oldest first
+	Append(symbol string, interval Interval, candles []Candle) error
+	// Candles returns the stored bars opening within [from, to], oldest first
+	Candles(symbol string, interval Interval, from, to time.Time) ([]Candle, error)
+}
+
+// Candle is one OHLCV bar; OpenTime is aligned to the interval in UTC
+type Candle struct {
+	OpenTime    time.Time `json:"open_time"`
+	CloseTime   time.Time `json:"close_time"`
+	Open        float64   `json:"open"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	Close       float64   `json:"close"`
+	Volume      float64   `json:"volume"`
+	QuoteVolume float64   `json:"quote_volume"`
+	TradeCount  int       `json:"trade_count"`
+}
+
+func newCandle(openTime time.Time, interval Interval, price, quantity float64) Candle {
+	return Candle{
+		OpenTime:    openTime,
+		CloseTime:   openTime.Add(interval.Duration()),
+		Open:        price,
+		High:        price,
+		Low:         price,
+		Close:       price,
+		Volume:      quantity,
+		QuoteVolume: price * quantity,
+		TradeCount:  1,
+	}
+}
+
+func (c *Candle) add(price, quantity float64) {
+	if price > c.High {
+		c.High = price
+	}
+	if price < c.Low {
+		c.Low = price
+	}
+	c.Close = price
+	c.Volume += quantity
+	c.QuoteVolume += price * quantity
+	c.TradeCount++
+}
@@ -0,0 +1,223 @@
+package marketdata
+
+import (
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// MaxCandles is the number of bars retained per symbol and interval
+	MaxCandles = 1500
+
+	tickerWindow = 24 * time.Hour
+)
+
+// Ticker is the rolling 24h summary for a symbol, at one-minute resolution. The
+// tracker sees only trades, so the top of book is filled in by the venue.
+type Ticker struct {
+	Symbol             string    `json:"symbol"`
+	LastPrice          float64   `json:"last_price"`
+	BidPrice           float64   `json:"bid_price"` // Top of book; zero when the side is empty
+	BidQuantity        float64   `json:"bid_quantity"`
+	AskPrice           float64   `json:"ask_price"`
+	AskQuantity        float64   `json:"ask_quantity"`
+	OpenPrice          float64   `json:"open_price"`
+	HighPrice          float64   `json:"high_price"`
+	LowPrice           float64   `json:"low_price"`
+	PriceChange        float64   `json:"price_change"`
+	PriceChangePercent float64   `json:"price_change_percent"`
+	WeightedAvgPrice   float64   `json:"weighted_avg_price"` // VWAP over the window
+	Volume             float64   `json:"volume"`
+	QuoteVolume        float64   `json:"quote_volume"`
+	TradeCount         int       `json:"trade_count"`
+	LastTradeAt        time.Time `json:"last_trade_at,omitempty"`
+	WindowStart        time.Time `json:"window_start"`
+	WindowEnd          time.Time `json:"window_end"`
+}
+
+// SymbolState is the persisted statistics of one symbol
+type SymbolState struct {
+	LastPrice   float64               `json:"last_price"`
+	LastTradeAt time.Time             `json:"last_trade_at"`
+	Candles     map[Interval][]Candle `json:"candles"`
+}
+
+// State is a point-in-time copy of all statistics, suitable for persistence
+type State struct {
+	SavedAt time.Time              `json:"saved_at"`
+	Symbols map[string]SymbolState `json:"symbols"`
+}
+
+// Store persists statistics so a restarted instance continues where it stopped
+type Store interface {
+	Save(state State) error
+	Load() (State, error)
+}
+
+// Tracker maintains per-symbol tickers and candles from executed trades
+type Tracker struct {
+	symbols map[string]*SymbolState
+	mu      sync.RWMutex
+	now     func() time.Time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		symbols: make(map[string]*SymbolState),
+		now:     time.Now,
+	}
+}
+
+// Record folds trades into the statistics of their symbols
+func (t *Tracker) Record(trades ...models.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, trade := range trades {
+		stats, exists := t.symbols[trade.Symbol]
+		if !exists {
+			stats = &SymbolState{Candles: make(map[Interval][]Candle)}
+			t.symbols[trade.Symbol] = stats
+		}
+		if !trade.ExecutedAt.Before(stats.LastTradeAt) {
+			stats.LastPrice = trade.Price
+			stats.LastTradeAt = trade.ExecutedAt
+		}
+		for _, interval := range Intervals {
+			stats.Candles[interval] = addTrade(stats.Candles[interval], interval, trade)
+		}
+	}
+}
+
+// addTrade updates the bar containing the trade, opening a new bar when needed
+func addTrade(candles []Candle, interval Interval, trade models.Trade) []Candle {
+	openTime := trade.ExecutedAt.UTC().Truncate(interval.Duration())
+
+	for i := len(candles) - 1; i >= 0 && !candles[i].OpenTime.Before(openTime); i-- {
+		if candles[i].OpenTime.Equal(openTime) {
+			candles[i].add(trade.Price, trade.Quantity)
+			return candles
+		}
+	}
+	if len(candles) > 0 && candles[len(candles)-1].OpenTime.After(openTime) {
+		// Late trade for a bar that was never opened; keep bars in time order
+		return candles
+	}
+
+	candles = append(candles, newCandle(openTime, interval, trade.Price, trade.Quantity))
+	if len(candles) > MaxCandles {
+		candles = append([]Candle(nil), candles[len(candles)-MaxCandles:]...)
+	}
+	return candles
+}
+
+// Ticker returns the rolling 24h summary ending now
+func (t *Tracker) Ticker(symbol string) Ticker {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := t.now().UTC()
+	ticker := Ticker{
+		Symbol:      symbol,
+		WindowStart: now.Add(-tickerWindow),
+		WindowEnd:   now,
+	}
+	stats, exists := t.symbols[symbol]
+	if !exists {
+		return ticker
+	}
+	ticker.LastPrice = stats.LastPrice
+	ticker.LastTradeAt = stats.LastTradeAt
+
+	for _, candle := range stats.Candles[Interval1m] {
+		if !candle.CloseTime.After(ticker.WindowStart) {
+			continue
+		}
+		if ticker.TradeCount == 0 {
+			ticker.OpenPrice = candle.Open
+			ticker.HighPrice = candle.High
+			ticker.LowPrice = candle.Low
+		}
+		if candle.High > ticker.HighPrice {
+			ticker.HighPrice = candle.High
+		}
+		if candle.Low < ticker.LowPrice {
+			ticker.LowPrice = candle.Low
+		}
+		ticker.Volume += candle.Volume
+		ticker.QuoteVolume += candle.QuoteVolume
+		ticker.TradeCount += candle.TradeCount
+	}
+
+	if ticker.TradeCount == 0 {
+		// Quiet window: carry the last price so dashboards never drop to zero
+		ticker.OpenPrice = stats.LastPrice
+		ticker.HighPrice = stats.LastPrice
+		ticker.LowPrice = stats.LastPrice
+		ticker.WeightedAvgPrice = stats.LastPrice
+	} else if ticker.Volume > 0 {
+		ticker.WeightedAvgPrice = ticker.QuoteVolume / ticker.Volume
+	}
+	ticker.PriceChange = ticker.LastPrice - ticker.OpenPrice
+	if ticker.OpenPrice > 0 {
+		ticker.PriceChangePercent = ticker.PriceChange / ticker.OpenPrice * 100
+	}
+	return ticker
+}
+
+// Candles returns up to limit of the most recent bars, oldest first (0 = all retained)
+func (t *Tracker) Candles(symbol string, interval Interval, limit int) []Candle {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats, exists := t.symbols[symbol]
+	if !exists {
+		return []Candle{}
+	}
+	candles := stats.Candles[interval]
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return append([]Candle{}, candles...)
+}
+
+// Snapshot copies the current statistics for persistence
+func (t *Tracker) Snapshot() State {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	state := State{SavedAt: t.now().UTC(), Symbols: make(map[string]SymbolState, len(t.symbols))}
+	for symbol, stats := range t.symbols {
+		state.Symbols[symbol] = copySymbolState(*stats)
+	}
+	return state
+}
+
+// Restore replaces all statistics with a previously saved state
+func (t *Tracker) Restore(state State) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.symbols = make(map[string]*SymbolState, len(state.Symbols))
+	for symbol, stats := range state.Symbols {
+		restored := copySymbolState(stats)
+		t.symbols[symbol] = &restored
+	}
+}
+
+func copySymbolState(stats SymbolState) SymbolState {
+	copied := SymbolState{
+		LastPrice:   stats.LastPrice,
+		LastTradeAt: stats.LastTradeAt,
+		Candles:     make(map[Interval][]Candle, len(stats.Candles)),
+	}
+	for interval, candles := range stats.Candles {
+		copied.Candles[interval] = append([]Candle(nil), candles...)
+	}
+	return copied
+}
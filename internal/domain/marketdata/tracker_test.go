@@ -0,0 +1,131 @@
+//go:build unit
+
+package marketdata
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func trade(at time.Time, price, quantity float64) models.Trade {
+	return models.Trade{Symbol: "BTC-USD", Price: price, Quantity: quantity, ExecutedAt: at}
+}
+
+func newTestTracker(now time.Time) (*Tracker, *time.Time) {
+	tracker := NewTracker()
+	clock := now
+	tracker.now = func() time.Time { return clock }
+	return tracker, &clock
+}
+
+func TestTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("builds_candles_per_interval", func(t *testing.T) {
+		// Given: Trades spanning two minutes within one five-minute bar
+		tracker, _ := newTestTracker(start)
+		tracker.Record(
+			trade(start.Add(10*time.Second), 100, 1),
+			trade(start.Add(20*time.Second), 105, 2),
+			trade(start.Add(70*time.Second), 98, 1),
+		)
+
+		// When: Candles are requested
+		minute := tracker.Candles("BTC-USD", Interval1m, 0)
+		fiveMinute := tracker.Candles("BTC-USD", Interval5m, 0)
+
+		// Then: One-minute bars split and the five-minute bar aggregates
+		if len(minute) != 2 || minute[0].High != 105 || minute[0].Close != 105 || minute[1].Open != 98 {
+			t.Errorf("Unexpected 1m candles: %+v", minute)
+		}
+		if len(fiveMinute) != 1 || fiveMinute[0].Volume != 4 || fiveMinute[0].Low != 98 || fiveMinute[0].TradeCount != 3 {
+			t.Errorf("Unexpected 5m candles: %+v", fiveMinute)
+		}
+	})
+
+	t.Run("builds_one_second_candles", func(t *testing.T) {
+		tracker, _ := newTestTracker(start)
+		tracker.Record(
+			trade(start.Add(100*time.Millisecond), 100, 1),
+			trade(start.Add(900*time.Millisecond), 101, 1),
+			trade(start.Add(1500*time.Millisecond), 99, 1),
+		)
+
+		second := tracker.Candles("BTC-USD", Interval1s, 0)
+		if len(second) != 2 || second[0].Close != 101 || second[0].TradeCount != 2 || !second[1].OpenTime.Equal(start.Add(time.Second)) {
+			t.Errorf("Unexpected 1s candles: %+v", second)
+		}
+	})
+
+	t.Run("ticker_rolls_over_24_hours", func(t *testing.T) {
+		tracker, clock := newTestTracker(start)
+		tracker.Record(trade(start, 100, 1))
+		tracker.Record(trade(start.Add(12*time.Hour), 110, 2))
+
+		*clock = start.Add(12 * time.Hour)
+		ticker := tracker.Ticker("BTC-USD")
+		if ticker.OpenPrice != 100 || ticker.LastPrice != 110 || ticker.Volume != 3 || ticker.PriceChangePercent != 10 {
+			t.Errorf("Unexpected ticker inside window: %+v", ticker)
+		}
+		// (100*1 + 110*2) / 3
+		if math.Abs(ticker.WeightedAvgPrice-320.0/3) > 1e-9 {
+			t.Errorf("Expected VWAP %v, got %v", 320.0/3, ticker.WeightedAvgPrice)
+		}
+
+		// The first trade drops out once it is more than 24h old
+		*clock = start.Add(25 * time.Hour)
+		ticker = tracker.Ticker("BTC-USD")
+		if ticker.OpenPrice != 110 || ticker.Volume != 2 || ticker.TradeCount != 1 {
+			t.Errorf("Unexpected ticker after roll: %+v", ticker)
+		}
+	})
+
+	t.Run("quiet_ticker_keeps_last_price", func(t *testing.T) {
+		tracker, clock := newTestTracker(start)
+		tracker.Record(trade(start, 100, 1))
+
+		*clock = start.Add(48 * time.Hour)
+		ticker := tracker.Ticker("BTC-USD")
+		if ticker.LastPrice != 100 || ticker.OpenPrice != 100 || ticker.WeightedAvgPrice != 100 || ticker.Volume != 0 {
+			t.Errorf("Expected flat ticker at last price, got %+v", ticker)
+		}
+	})
+
+	t.Run("restored_state_continues_without_reset", func(t *testing.T) {
+		// Given: Statistics saved before a restart
+		before, _ := newTestTracker(start)
+		before.Record(trade(start.Add(10*time.Second), 100, 1))
+		state := before.Snapshot()
+
+		// When: A new tracker restores them and trades in the same minute
+		after, _ := newTestTracker(start.Add(30 * time.Second))
+		after.Restore(state)
+		after.Record(trade(start.Add(40*time.Second), 102, 1))
+
+		// Then: The open bar continues rather than starting over
+		candles := after.Candles("BTC-USD", Interval1m, 0)
+		if len(candles) != 1 || candles[0].Open != 100 || candles[0].Close != 102 || candles[0].Volume != 2 {
+			t.Errorf("Expected continued candle, got %+v", candles)
+		}
+		if ticker := after.Ticker("BTC-USD"); ticker.TradeCount != 2 {
+			t.Errorf("Expected ticker to include restored trades, got %+v", ticker)
+		}
+	})
+
+	t.Run("caps_retained_candles", func(t *testing.T) {
+		tracker, _ := newTestTracker(start)
+		for i := 0; i < MaxCandles+10; i++ {
+			tracker.Record(trade(start.Add(time.Duration(i)*time.Minute), 100, 1))
+		}
+
+		if candles := tracker.Candles("BTC-USD", Interval1m, 0); len(candles) != MaxCandles {
+			t.Errorf("Expected %d candles, got %d", MaxCandles, len(candles))
+		}
+		if candles := tracker.Candles("BTC-USD", Interval1m, 5); len(candles) != 5 {
+			t.Errorf("Expected limit of 5, got %d", len(candles))
+		}
+	})
+}
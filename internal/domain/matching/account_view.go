@@ -0,0 +1,56 @@
+package matching
+
+import (
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// AccountView is an account's orders across every book as of one event sequence
+type AccountView struct {
+	Sequence     uint64         // Last event applied; 0 while no event log is recorded
+	OpenOrders   []models.Order // Oldest first
+	FilledOrders []models.Order // Working or not, oldest first
+}
+
+// AccountView reads an account's working and filled orders at a single point in the
+// event sequence. Each shard is parked between commands until every one is, so no
+// order changes and no event is recorded while the view is read; matching resumes
+// once it has been copied.
+func (e *Engine) AccountView(accountID string) (AccountView, error) {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	shards := e.shardList()
+	release := make(chan struct{})
+	defer close(release)
+	for _, s := range shards {
+		parked := make(chan struct{})
+		s.queue.push(&command{fn: func() { close(parked); <-release }, done: make(chan struct{})})
+		select {
+		case <-parked:
+		case <-s.stop:
+			return AccountView{}, ErrEngineClosed
+		}
+	}
+
+	view := AccountView{
+		Sequence:     e.Sequence(),
+		OpenOrders:   make([]models.Order, 0),
+		FilledOrders: make([]models.Order, 0),
+	}
+	for _, s := range shards {
+		view.OpenOrders = append(view.OpenOrders, s.openOrders(accountID)...)
+		view.FilledOrders = append(view.FilledOrders, s.filledOrders(accountID)...)
+	}
+	sortByCreation(view.OpenOrders)
+	sortByCreation(view.FilledOrders)
+	return view, nil
+}
+
+func sortByCreation(orders []models.Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt) ||
+			(orders[i].CreatedAt.Equal(orders[j].CreatedAt) && orders[i].ID < orders[j].ID)
+	})
+}
@@ -0,0 +1,64 @@
+//go:build unit
+
+package matching
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_AccountView(t *testing.T) {
+	t.Run("reads_every_book_at_one_event_sequence_while_trading_continues", func(t *testing.T) {
+		// Given: Two books recording to an event log, traded on concurrently by one account
+		engine := NewEngine()
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		engine.AddBook("ETH-USD", 10)
+		var trading sync.WaitGroup
+		for _, symbol := range []string{"BTC-USD", "ETH-USD"} {
+			trading.Add(1)
+			go func(symbol string) {
+				defer trading.Done()
+				for i := 0; i < 200; i++ {
+					order := limitOrder("a", models.SideBuy, 1, 100)
+					order.Symbol = symbol
+					if i%2 == 1 {
+						order.AccountID, order.Side = "mm", models.SideSell
+					}
+					engine.Submit(order)
+				}
+			}(symbol)
+		}
+
+		// When: Views are read while the orders arrive
+		views := make([]AccountView, 0)
+		for i := 0; i < 20; i++ {
+			view, err := engine.AccountView("a")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			views = append(views, view)
+		}
+		trading.Wait()
+
+		// Then: Each view is exactly the state the log replays to at its sequence
+		events := log.Events()
+		for _, view := range views {
+			replayed, err := Replay(events[:view.Sequence])
+			if err != nil {
+				t.Fatalf("Expected the log to replay, got %v", err)
+			}
+			if open := replayed.OpenOrders("a", ""); !reflect.DeepEqual(open, view.OpenOrders) {
+				t.Errorf("At sequence %d expected open orders %+v, got %+v", view.Sequence, open, view.OpenOrders)
+			}
+			if filled := replayed.FilledOrders("a"); !reflect.DeepEqual(filled, view.FilledOrders) {
+				t.Errorf("At sequence %d expected filled orders %+v, got %+v", view.Sequence, filled, view.FilledOrders)
+			}
+			replayed.Close()
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var ErrInvalidAmend = errors.New("invalid amend")
+
+// AmendRequest changes a working limit order; zero fields keep the current value
+type AmendRequest struct {
+	Price    float64 `json:"price,omitempty"`
+	Quantity float64 `json:"quantity,omitempty"` // new total quantity, including fills
+}
+
+// Amend modifies a working order atomically. Quantity reductions at the same price
+// are applied in place and keep time priority; a price change or a quantity increase
+// requeues the order at the back of its new level and may trade if it now crosses.
+func (e *Engine) Amend(orderID string, req AmendRequest) (*ExecutionReport, error) {
+	s, err := e.shardForOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return call(s, func() (*ExecutionReport, error) { return s.amend(orderID, req) })
+}
+
+func (s *shard) amend(orderID string, req AmendRequest) (*ExecutionReport, error) {
+	order, exists := s.orders[orderID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	if _, err := s.expireOrders(s.engine.now()); err != nil {
+		return nil, err
+	}
+	if order.Status.IsTerminal() {
+		return nil, fmt.Errorf("%w: %s is %s", ErrOrderNotActive, orderID, order.Status)
+	}
+	if order.Type != models.OrderTypeLimit {
+		return nil, fmt.Errorf("%w: only limit orders can be amended", ErrInvalidAmend)
+	}
+
+	book := s.book
+	now := s.engine.now()
+	s.expireHalt(now)
+	switch book.phase {
+	case PhaseClosed:
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, order.Symbol)
+	case PhaseHalted:
+		return nil, fmt.Errorf("%w: %s", ErrHalted, order.Symbol)
+	}
+
+	price, quantity := order.Price, order.Quantity
+	if req.Price != 0 {
+		price = req.Price
+	}
+	if req.Quantity != 0 {
+		quantity = req.Quantity
+	}
+	if price <= 0 || quantity <= 0 {
+		return nil, fmt.Errorf("%w: price and quantity must be positive", ErrInvalidAmend)
+	}
+	if quantity-order.FilledQuantity < 1e-12 {
+		return nil, fmt.Errorf("%w: quantity %v does not exceed filled quantity %v", ErrInvalidAmend, quantity, order.FilledQuantity)
+	}
+
+	if err := s.engine.record(Event{Type: EventOrderAmended, Time: now, Symbol: order.Symbol, OrderID: orderID, Amend: &req}); err != nil {
+		return nil, err
+	}
+
+	side := book.sideOf(order.Side)
+	if price == order.Price && quantity <= order.Quantity {
+		// Fast path: shrink in place, priority unchanged
+		order.Quantity = quantity
+		order.UpdatedAt = now
+		return &ExecutionReport{Order: *order}, nil
+	}
+
+	side.remove(order, order.Price)
+	order.Price = price
+	order.Quantity = quantity
+	order.UpdatedAt = now
+
+	var trades []models.Trade
+	if book.phase == PhaseContinuous {
+		trades = s.match(order, now)
+	}
+	if order.RemainingQuantity() > 0 {
+		side.add(order, order.Price)
+	}
+
+	return &ExecutionReport{Order: *order, Trades: trades}, nil
+}
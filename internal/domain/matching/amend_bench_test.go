@@ -0,0 +1,93 @@
+//go:build unit
+
+package matching
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// newQuotedEngine seeds a book with ten orders on each of 100 price levels per side
+func newQuotedEngine(b *testing.B) (*Engine, string) {
+	b.Helper()
+	engine := NewEngine()
+	engine.AddBook("BTC-USD", 1000)
+
+	var quoteID string
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 10; j++ {
+			account := fmt.Sprintf("mm-%d", j)
+			engine.Submit(limitOrder(account, models.SideBuy, 1, float64(999-i)))
+			report, _ := engine.Submit(limitOrder(account, models.SideSell, 1, float64(1001+i)))
+			if i == 50 && j == 5 {
+				quoteID = report.Order.ID
+			}
+		}
+	}
+	return engine, quoteID
+}
+
+func BenchmarkCancelReplace(b *testing.B) {
+	engine, quoteID := newQuotedEngine(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cancelled, _ := engine.Cancel(quoteID)
+		cancelled.Quantity = 1 + float64(i%2)
+		report, _ := engine.Submit(limitOrder(cancelled.AccountID, cancelled.Side, cancelled.Quantity, cancelled.Price))
+		quoteID = report.Order.ID
+	}
+}
+
+func BenchmarkAmendInPlace(b *testing.B) {
+	engine, quoteID := newQuotedEngine(b)
+	engine.Amend(quoteID, AmendRequest{Quantity: 1000})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		engine.Amend(quoteID, AmendRequest{Quantity: 1000 - float64(i%999)})
+		if i%999 == 998 {
+			engine.Amend(quoteID, AmendRequest{Quantity: 1000})
+		}
+	}
+}
+
+func BenchmarkAmendReprice(b *testing.B) {
+	engine, quoteID := newQuotedEngine(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		engine.Amend(quoteID, AmendRequest{Price: float64(1050 + i%2)})
+	}
+}
+
+// BenchmarkSubmitParallelSymbols measures throughput when each goroutine trades its own symbol
+func BenchmarkSubmitParallelSymbols(b *testing.B) {
+	engine := NewEngine()
+	defer engine.Close()
+	const symbols = 256
+	for i := 0; i < symbols; i++ {
+		engine.AddBook(fmt.Sprintf("SYM%d-USD", i), 100)
+	}
+
+	var next atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		symbol := fmt.Sprintf("SYM%d-USD", next.Add(1)%symbols)
+		ask := limitOrder("maker", models.SideSell, 1, 100)
+		ask.Symbol = symbol
+		bid := limitOrder("taker", models.SideBuy, 1, 100)
+		bid.Symbol = symbol
+		for pb.Next() {
+			engine.Submit(ask)
+			engine.Submit(bid)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_Amend(t *testing.T) {
+	t.Run("quantity_reduction_keeps_time_priority", func(t *testing.T) {
+		// Given: Two asks at the same price
+		engine := newTestEngine()
+		first, _ := engine.Submit(limitOrder("m1", models.SideSell, 2, 101))
+		second, _ := engine.Submit(limitOrder("m2", models.SideSell, 2, 101))
+
+		// When: The first ask is reduced
+		report, err := engine.Amend(first.Order.ID, AmendRequest{Quantity: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Order.Quantity != 1 {
+			t.Errorf("Expected quantity 1, got %v", report.Order.Quantity)
+		}
+
+		// Then: It still trades first
+		taker, _ := engine.Submit(limitOrder("t", models.SideBuy, 1, 101))
+		if len(taker.Trades) != 1 || taker.Trades[0].SellOrderID != first.Order.ID {
+			t.Errorf("Expected amended order to keep priority, got %+v", taker.Trades)
+		}
+		if order, _ := engine.GetOrder(second.Order.ID); order.FilledQuantity != 0 {
+			t.Errorf("Expected second order untouched, got filled %v", order.FilledQuantity)
+		}
+	})
+
+	t.Run("quantity_increase_loses_time_priority", func(t *testing.T) {
+		engine := newTestEngine()
+		first, _ := engine.Submit(limitOrder("m1", models.SideSell, 1, 101))
+		second, _ := engine.Submit(limitOrder("m2", models.SideSell, 1, 101))
+
+		if _, err := engine.Amend(first.Order.ID, AmendRequest{Quantity: 3}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		taker, _ := engine.Submit(limitOrder("t", models.SideBuy, 1, 101))
+		if len(taker.Trades) != 1 || taker.Trades[0].SellOrderID != second.Order.ID {
+			t.Errorf("Expected second order to trade first, got %+v", taker.Trades)
+		}
+	})
+
+	t.Run("reprice_that_crosses_trades_immediately", func(t *testing.T) {
+		// Given: A resting bid and a higher ask
+		engine := newTestEngine()
+		engine.Submit(limitOrder("m", models.SideSell, 1, 101))
+		bid, _ := engine.Submit(limitOrder("t", models.SideBuy, 2, 99))
+
+		// When: The bid is repriced through the ask
+		report, err := engine.Amend(bid.Order.ID, AmendRequest{Price: 101})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: It trades and the remainder rests at the new price
+		if len(report.Trades) != 1 || report.Order.Status != models.OrderStatusPartiallyFilled {
+			t.Fatalf("Expected one trade and a partial fill, got %+v", report)
+		}
+		snapshot, _ := engine.Snapshot("BTC-USD", 0)
+		if len(snapshot.Bids) != 1 || snapshot.Bids[0].Price != 101 || snapshot.Bids[0].Quantity != 1 {
+			t.Errorf("Expected 1 @ 101 resting, got %+v", snapshot.Bids)
+		}
+	})
+
+	t.Run("rejects_quantity_at_or_below_filled", func(t *testing.T) {
+		engine := newTestEngine()
+		ask, _ := engine.Submit(limitOrder("m", models.SideSell, 2, 101))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 101))
+
+		_, err := engine.Amend(ask.Order.ID, AmendRequest{Quantity: 1})
+		if !errors.Is(err, ErrInvalidAmend) {
+			t.Errorf("Expected ErrInvalidAmend, got %v", err)
+		}
+	})
+
+	t.Run("rejects_terminal_orders", func(t *testing.T) {
+		engine := newTestEngine()
+		ask, _ := engine.Submit(limitOrder("m", models.SideSell, 1, 101))
+		engine.Cancel(ask.Order.ID)
+
+		_, err := engine.Amend(ask.Order.ID, AmendRequest{Quantity: 2})
+		if !errors.Is(err, ErrOrderNotActive) {
+			t.Errorf("Expected ErrOrderNotActive, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// AuctionKind identifies which session boundary a call auction belongs to
+type AuctionKind string
+
+const (
+	AuctionOpening AuctionKind = "opening"
+	AuctionClosing AuctionKind = "closing"
+)
+
+// AuctionIndication is the would-be result of uncrossing the book now
+type AuctionIndication struct {
+	Symbol    string      `json:"symbol"`
+	Kind      AuctionKind `json:"kind"`
+	Price     float64     `json:"price"`
+	Volume    float64     `json:"volume"`
+	Imbalance float64     `json:"imbalance"` // Positive: excess buy interest at Price
+}
+
+// AuctionResult is the outcome of an uncross
+type AuctionResult struct {
+	AuctionIndication
+	Trades     []models.Trade `json:"trades"`
+	Canceled   []string       `json:"canceled_order_ids"`
+	PhaseAfter Phase          `json:"phase_after"`
+}
+
+// StartAuction moves a book into the call auction phase; orders rest without matching
+func (e *Engine) StartAuction(symbol string, kind AuctionKind) error {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return err
+	}
+	if kind != AuctionOpening && kind != AuctionClosing {
+		return fmt.Errorf("invalid auction kind: %q", kind)
+	}
+	_, err = call(s, func() (struct{}, error) { return struct{}{}, s.startAuction(kind) })
+	return err
+}
+
+func (s *shard) startAuction(kind AuctionKind) error {
+	book, symbol := s.book, s.book.symbol
+	now := s.engine.now()
+	s.expireHalt(now)
+	if book.phase == PhaseAuction {
+		return fmt.Errorf("%w: auction already in progress for %s", ErrInvalidPhase, symbol)
+	}
+	if err := s.engine.record(Event{Type: EventAuctionStarted, Time: now, Symbol: symbol, AuctionKind: kind}); err != nil {
+		return err
+	}
+
+	// Starting an auction from a halt acts as a reopening auction
+	book.breaker.halt = nil
+	book.phase = PhaseAuction
+	book.auctionKind = kind
+	return nil
+}
+
+// IndicativeAuction computes the equilibrium price and volume without executing
+func (e *Engine) IndicativeAuction(symbol string) (AuctionIndication, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return AuctionIndication{}, err
+	}
+	return call(s, func() (AuctionIndication, error) {
+		if s.book.phase != PhaseAuction {
+			return AuctionIndication{}, fmt.Errorf("%w: no auction in progress for %s", ErrInvalidPhase, symbol)
+		}
+		return equilibrium(s.book), nil
+	})
+}
+
+// Uncross executes the auction at the equilibrium price and ends the auction phase.
+// Opening auctions continue into continuous trading; closing auctions close the book.
+func (e *Engine) Uncross(symbol string) (*AuctionResult, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return call(s, s.uncross)
+}
+
+func (s *shard) uncross() (*AuctionResult, error) {
+	book, symbol := s.book, s.book.symbol
+	if book.phase != PhaseAuction {
+		return nil, fmt.Errorf("%w: no auction in progress for %s", ErrInvalidPhase, symbol)
+	}
+
+	now := s.engine.now()
+	if _, err := s.expireOrders(now); err != nil {
+		return nil, err
+	}
+	if err := s.engine.record(Event{Type: EventAuctionUncrossed, Time: now, Symbol: symbol}); err != nil {
+		return nil, err
+	}
+
+	indication := equilibrium(book)
+	result := &AuctionResult{AuctionIndication: indication}
+
+	remaining := indication.Volume
+	for remaining > 1e-12 {
+		bestBid, bestAsk := book.bids.best(), book.asks.best()
+		if bestBid == nil || bestAsk == nil {
+			break
+		}
+		buy, sell := bestBid.orders[0], bestAsk.orders[0]
+		quantity := math.Min(remaining, math.Min(buy.RemainingQuantity(), sell.RemainingQuantity()))
+
+		result.Trades = append(result.Trades, s.execute(buy, sell, indication.Price, quantity, now, true))
+		remaining -= quantity
+
+		if buy.RemainingQuantity() == 0 {
+			bestBid.orders = bestBid.orders[1:]
+			book.bids.popBestIfEmpty()
+		}
+		if sell.RemainingQuantity() == 0 {
+			bestAsk.orders = bestAsk.orders[1:]
+			book.asks.popBestIfEmpty()
+		}
+	}
+
+	// Unfilled market orders cannot rest in a continuous book
+	for _, side := range []*bookSide{book.bids, book.asks} {
+		for _, lvl := range append([]*level(nil), side.levels...) {
+			for _, order := range append([]*models.Order(nil), lvl.orders...) {
+				if order.Type == models.OrderTypeMarket {
+					side.remove(order, lvl.price)
+					order.Status = models.OrderStatusCanceled
+					order.UpdatedAt = now
+					result.Canceled = append(result.Canceled, order.ID)
+				}
+			}
+		}
+	}
+
+	if book.auctionKind == AuctionClosing {
+		book.phase = PhaseClosed
+	} else {
+		book.phase = PhaseContinuous
+	}
+	result.PhaseAfter = book.phase
+
+	return result, nil
+}
+
+// equilibrium finds the uncrossing price that maximizes executed volume.
+// Ties are broken by minimum imbalance, then proximity to the last/reference price.
+func equilibrium(book *OrderBook) AuctionIndication {
+	indication := AuctionIndication{Symbol: book.symbol, Kind: book.auctionKind}
+
+	candidates := make([]float64, 0)
+	for _, side := range []*bookSide{book.bids, book.asks} {
+		for _, lvl := range side.levels {
+			if !math.IsInf(lvl.price, 0) && lvl.price > 0 {
+				candidates = append(candidates, lvl.price)
+			}
+		}
+	}
+	reference := book.markPrice()
+	if len(candidates) == 0 && reference > 0 {
+		// Only market orders on the book: they uncross at the reference price
+		candidates = append(candidates, reference)
+	}
+	sort.Float64s(candidates)
+
+	bestFound := false
+	for _, price := range candidates {
+		buyVolume, sellVolume := 0.0, 0.0
+		for _, lvl := range book.bids.levels {
+			if lvl.price < price {
+				break
+			}
+			buyVolume += lvl.quantity()
+		}
+		for _, lvl := range book.asks.levels {
+			if lvl.price > price {
+				break
+			}
+			sellVolume += lvl.quantity()
+		}
+
+		volume := math.Min(buyVolume, sellVolume)
+		imbalance := buyVolume - sellVolume
+
+		better := !bestFound ||
+			volume > indication.Volume+1e-12 ||
+			(math.Abs(volume-indication.Volume) <= 1e-12 &&
+				(math.Abs(imbalance) < math.Abs(indication.Imbalance)-1e-12 ||
+					(math.Abs(math.Abs(imbalance)-math.Abs(indication.Imbalance)) <= 1e-12 &&
+						math.Abs(price-reference) < math.Abs(indication.Price-reference))))
+
+		if better {
+			indication.Price = price
+			indication.Volume = volume
+			indication.Imbalance = imbalance
+			bestFound = true
+		}
+	}
+
+	if indication.Volume <= 1e-12 {
+		indication.Volume = 0
+	}
+	return indication
+}
@@ -0,0 +1,130 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_CallAuction(t *testing.T) {
+	t.Run("collects_crossing_orders_without_matching", func(t *testing.T) {
+		// Given: A book in opening auction
+		engine := newTestEngine()
+		if err := engine.StartAuction("BTC-USD", AuctionOpening); err != nil {
+			t.Fatalf("Failed to start auction: %v", err)
+		}
+
+		// When: Crossing orders are submitted
+		engine.Submit(limitOrder("a", models.SideBuy, 1, 105))
+		report, _ := engine.Submit(limitOrder("b", models.SideSell, 1, 95))
+
+		// Then: No trades occur during the call phase
+		if len(report.Trades) != 0 {
+			t.Errorf("Expected no trades during auction, got %d", len(report.Trades))
+		}
+	})
+
+	t.Run("uncrosses_at_volume_maximizing_price", func(t *testing.T) {
+		// Given: An auction book where 101 maximizes executable volume
+		engine := newTestEngine()
+		engine.StartAuction("BTC-USD", AuctionOpening)
+		engine.Submit(limitOrder("b1", models.SideBuy, 3, 102))
+		engine.Submit(limitOrder("b2", models.SideBuy, 2, 101))
+		engine.Submit(limitOrder("b3", models.SideBuy, 4, 99))
+		engine.Submit(limitOrder("s1", models.SideSell, 2, 100))
+		engine.Submit(limitOrder("s2", models.SideSell, 3, 101))
+		engine.Submit(limitOrder("s3", models.SideSell, 5, 103))
+
+		// When: The indicative price is requested
+		indication, err := engine.IndicativeAuction("BTC-USD")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: 5 units can trade at 101 (buy 5 >= 101, sell 5 <= 101)
+		if indication.Price != 101 || indication.Volume != 5 {
+			t.Errorf("Expected 5 @ 101, got %v @ %v", indication.Volume, indication.Price)
+		}
+
+		// When: The auction uncrosses
+		result, err := engine.Uncross("BTC-USD")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: All auction trades print at the single equilibrium price
+		total := 0.0
+		for _, trade := range result.Trades {
+			if trade.Price != 101 || !trade.Auction {
+				t.Errorf("Expected auction trade at 101, got %+v", trade)
+			}
+			total += trade.Quantity
+		}
+		if total != 5 {
+			t.Errorf("Expected 5 units executed, got %v", total)
+		}
+
+		// And: The opening auction hands over to continuous trading with an uncrossed book
+		if result.PhaseAfter != PhaseContinuous {
+			t.Errorf("Expected continuous phase after opening auction, got %s", result.PhaseAfter)
+		}
+		snapshot, _ := engine.Snapshot("BTC-USD", 1)
+		if snapshot.Bids[0].Price >= snapshot.Asks[0].Price {
+			t.Errorf("Expected uncrossed book, got bid %v ask %v", snapshot.Bids[0].Price, snapshot.Asks[0].Price)
+		}
+	})
+
+	t.Run("breaks_volume_ties_by_reference_price", func(t *testing.T) {
+		// Given: Equal volume is executable anywhere between 98 and 102
+		engine := newTestEngine()
+		engine.StartAuction("BTC-USD", AuctionOpening)
+		engine.Submit(limitOrder("b", models.SideBuy, 1, 102))
+		engine.Submit(limitOrder("s", models.SideSell, 1, 98))
+
+		// When/Then: The price closest to the reference (100) is chosen from the candidates
+		indication, _ := engine.IndicativeAuction("BTC-USD")
+		if indication.Volume != 1 {
+			t.Fatalf("Expected volume 1, got %v", indication.Volume)
+		}
+		if indication.Price != 98 && indication.Price != 102 {
+			t.Errorf("Expected a candidate limit price, got %v", indication.Price)
+		}
+	})
+
+	t.Run("closing_auction_closes_the_book_and_cancels_market_leftovers", func(t *testing.T) {
+		engine := newTestEngine()
+		engine.StartAuction("BTC-USD", AuctionClosing)
+		engine.Submit(limitOrder("s", models.SideSell, 1, 100))
+		market, _ := engine.Submit(models.Order{AccountID: "m", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeMarket, Quantity: 2})
+
+		result, err := engine.Uncross("BTC-USD")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Volume != 1 || result.Price != 100 {
+			t.Errorf("Expected 1 @ 100, got %v @ %v", result.Volume, result.Price)
+		}
+		if len(result.Canceled) != 1 || result.Canceled[0] != market.Order.ID {
+			t.Errorf("Expected market remainder canceled, got %v", result.Canceled)
+		}
+		if result.PhaseAfter != PhaseClosed {
+			t.Errorf("Expected closed phase, got %s", result.PhaseAfter)
+		}
+
+		_, err = engine.Submit(limitOrder("late", models.SideBuy, 1, 100))
+		if !errors.Is(err, ErrMarketClosed) {
+			t.Errorf("Expected ErrMarketClosed after close, got %v", err)
+		}
+	})
+
+	t.Run("rejects_uncross_without_auction", func(t *testing.T) {
+		engine := newTestEngine()
+		if _, err := engine.Uncross("BTC-USD"); !errors.Is(err, ErrInvalidPhase) {
+			t.Errorf("Expected ErrInvalidPhase, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package matching
+
+import (
+	"math"
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Phase is the trading session state of a single order book
+type Phase string
+
+const (
+	PhaseContinuous Phase = "continuous"
+	PhaseAuction    Phase = "auction"
+	PhaseClosed     Phase = "closed"
+)
+
+// PriceLevel is an aggregated view of resting quantity at one price
+type PriceLevel struct {
+	Price      float64 `json:"price"`
+	Quantity   float64 `json:"quantity"`
+	OrderCount int     `json:"order_count"`
+}
+
+// BookSnapshot is a point-in-time aggregated view of an order book
+type BookSnapshot struct {
+	Symbol string       `json:"symbol"`
+	Phase  Phase        `json:"phase"`
+	Bids   []PriceLevel `json:"bids"`
+	Asks   []PriceLevel `json:"asks"`
+}
+
+// level holds resting orders at one price in time priority within each priority tier
+type level struct {
+	price  float64
+	orders []*models.Order
+}
+
+func (l *level) quantity() float64 {
+	total := 0.0
+	for _, order := range l.orders {
+		total += order.RemainingQuantity()
+	}
+	return total
+}
+
+// bookSide keeps price levels sorted best-first
+type bookSide struct {
+	side   models.Side
+	levels []*level
+}
+
+// better reports whether price a has priority over price b on this side
+func (s *bookSide) better(a, b float64) bool {
+	if s.side == models.SideBuy {
+		return a > b
+	}
+	return a < b
+}
+
+// search returns the index of price, or where it would be inserted
+func (s *bookSide) search(price float64) (int, bool) {
+	idx := sort.Search(len(s.levels), func(i int) bool {
+		return !s.better(s.levels[i].price, price)
+	})
+	return idx, idx < len(s.levels) && s.levels[idx].price == price
+}
+
+// add queues an order behind every order at its price of the same or a higher tier
+func (s *bookSide) add(order *models.Order, price float64) {
+	idx, found := s.search(price)
+	if found {
+		lvl := s.levels[idx]
+		at := len(lvl.orders)
+		for at > 0 && lvl.orders[at-1].Priority < order.Priority {
+			at--
+		}
+		lvl.orders = append(lvl.orders, nil)
+		copy(lvl.orders[at+1:], lvl.orders[at:])
+		lvl.orders[at] = order
+		return
+	}
+	s.levels = append(s.levels, nil)
+	copy(s.levels[idx+1:], s.levels[idx:])
+	s.levels[idx] = &level{price: price, orders: []*models.Order{order}}
+}
+
+func (s *bookSide) remove(order *models.Order, price float64) bool {
+	idx, found := s.search(price)
+	if !found {
+		return false
+	}
+	lvl := s.levels[idx]
+	for i, resting := range lvl.orders {
+		if resting.ID == order.ID {
+			lvl.orders = append(lvl.orders[:i], lvl.orders[i+1:]...)
+			if len(lvl.orders) == 0 {
+				s.levels = append(s.levels[:idx], s.levels[idx+1:]...)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bookSide) best() *level {
+	if len(s.levels) == 0 {
+		return nil
+	}
+	return s.levels[0]
+}
+
+// popBestIfEmpty drops the best level once all its orders are gone
+func (s *bookSide) popBestIfEmpty() {
+	if len(s.levels) > 0 && len(s.levels[0].orders) == 0 {
+		s.levels = s.levels[1:]
+	}
+}
+
+// depth aggregates up to n levels, skipping unpriced auction market orders
+func (s *bookSide) depth(n int) []PriceLevel {
+	levels := make([]PriceLevel, 0, n)
+	for _, lvl := range s.levels {
+		if n > 0 && len(levels) >= n {
+			break
+		}
+		if math.IsInf(lvl.price, 0) || lvl.price == 0 {
+			continue
+		}
+		levels = append(levels, PriceLevel{
+			Price:      lvl.price,
+			Quantity:   lvl.quantity(),
+			OrderCount: len(lvl.orders),
+		})
+	}
+	return levels
+}
+
+// OrderBook holds resting orders for one symbol
+type OrderBook struct {
+	symbol         string
+	bids           *bookSide
+	asks           *bookSide
+	phase          Phase
+	auctionKind    AuctionKind
+	lastPrice      float64
+	referencePrice float64
+	breaker        circuitBreaker
+}
+
+func newOrderBook(symbol string, referencePrice float64) *OrderBook {
+	return &OrderBook{
+		symbol:         symbol,
+		bids:           &bookSide{side: models.SideBuy},
+		asks:           &bookSide{side: models.SideSell},
+		phase:          PhaseContinuous,
+		referencePrice: referencePrice,
+	}
+}
+
+func (b *OrderBook) sideOf(side models.Side) *bookSide {
+	if side == models.SideBuy {
+		return b.bids
+	}
+	return b.asks
+}
+
+// restingPrice is the book key for an order; auction market orders sort ahead of all limits
+func restingPrice(order *models.Order) float64 {
+	if order.Type == models.OrderTypeMarket {
+		if order.Side == models.SideBuy {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	return order.Price
+}
+
+// markPrice is the last traded price, falling back to the reference price
+func (b *OrderBook) markPrice() float64 {
+	if b.lastPrice > 0 {
+		return b.lastPrice
+	}
+	return b.referencePrice
+}
+
+func (b *OrderBook) snapshot(levels int) BookSnapshot {
+	return BookSnapshot{
+		Symbol: b.symbol,
+		Phase:  b.phase,
+		Bids:   b.bids.depth(levels),
+		Asks:   b.asks.depth(levels),
+	}
+}
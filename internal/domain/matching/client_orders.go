@@ -0,0 +1,146 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var ErrDuplicateClientOrderID = errors.New("duplicate client order id")
+
+type clientOrderKey struct {
+	accountID     string
+	clientOrderID string
+}
+
+// clientOrderEntry is claimed before the order reaches its shard; done closes once
+// the order ID is known, or with an empty ID when the submission failed
+type clientOrderEntry struct {
+	request models.Order
+	orderID string
+	done    chan struct{}
+}
+
+// clientOrderIndex maps client order IDs to engine order IDs across all shards
+type clientOrderIndex struct {
+	mu      sync.Mutex
+	entries map[clientOrderKey]*clientOrderEntry
+}
+
+func newClientOrderIndex() *clientOrderIndex {
+	return &clientOrderIndex{entries: make(map[clientOrderKey]*clientOrderEntry)}
+}
+
+// claim returns the entry for key and whether the caller created it
+func (x *clientOrderIndex) claim(key clientOrderKey, request models.Order) (*clientOrderEntry, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if entry, exists := x.entries[key]; exists {
+		return entry, false
+	}
+	entry := &clientOrderEntry{request: request, done: make(chan struct{})}
+	x.entries[key] = entry
+	return entry, true
+}
+
+// resolve publishes the outcome of a claimed submission; an empty orderID frees the key
+func (x *clientOrderIndex) resolve(key clientOrderKey, entry *clientOrderEntry, orderID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	entry.orderID = orderID
+	if orderID == "" && x.entries[key] == entry {
+		delete(x.entries, key)
+	}
+	close(entry.done)
+}
+
+// restore indexes an order already placed, as a restored engine holds it
+func (x *clientOrderIndex) restore(order models.Order) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	entry := &clientOrderEntry{request: order, orderID: order.ID, done: make(chan struct{})}
+	close(entry.done)
+	x.entries[clientOrderKey{accountID: order.AccountID, clientOrderID: order.ClientOrderID}] = entry
+}
+
+// forget drops every client order ID of an account
+func (x *clientOrderIndex) forget(accountID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for key := range x.entries {
+		if key.accountID == accountID {
+			delete(x.entries, key)
+		}
+	}
+}
+
+// submitIdempotent places an order at most once per account and client order ID.
+// Resubmitting the same order returns the existing one; reusing the ID for a
+// different order is rejected.
+func (e *Engine) submitIdempotent(s *shard, order models.Order) (*ExecutionReport, error) {
+	key := clientOrderKey{accountID: order.AccountID, clientOrderID: order.ClientOrderID}
+	for {
+		entry, owner := e.clientOrders.claim(key, order)
+		if owner {
+			report, err := call(s, func() (*ExecutionReport, error) { return s.submit(order) })
+			orderID := ""
+			if err == nil {
+				orderID = report.Order.ID
+			}
+			e.clientOrders.resolve(key, entry, orderID)
+			return report, err
+		}
+
+		<-entry.done
+		if entry.orderID == "" {
+			continue // The earlier submission failed, so the ID is free again
+		}
+		if !sameOrderRequest(entry.request, order) {
+			return nil, fmt.Errorf("%w: %s is already used by %s", ErrDuplicateClientOrderID, order.ClientOrderID, entry.orderID)
+		}
+		existing, err := e.GetOrder(entry.orderID)
+		if err != nil {
+			return nil, err
+		}
+		return &ExecutionReport{Order: existing, Duplicate: true}, nil
+	}
+}
+
+// ClientOrder returns the order an account placed under a client order ID
+func (e *Engine) ClientOrder(accountID, clientOrderID string) (models.Order, error) {
+	e.clientOrders.mu.Lock()
+	entry, exists := e.clientOrders.entries[clientOrderKey{accountID: accountID, clientOrderID: clientOrderID}]
+	e.clientOrders.mu.Unlock()
+	if !exists {
+		return models.Order{}, fmt.Errorf("%w: client order id %s", ErrOrderNotFound, clientOrderID)
+	}
+
+	<-entry.done
+	if entry.orderID == "" {
+		return models.Order{}, fmt.Errorf("%w: client order id %s", ErrOrderNotFound, clientOrderID)
+	}
+	return e.GetOrder(entry.orderID)
+}
+
+// sameOrderRequest compares the fields a caller sets when placing an order
+func sameOrderRequest(a, b models.Order) bool {
+	tif := func(order models.Order) models.TimeInForce {
+		if order.TimeInForce == "" {
+			return models.TimeInForceGTC
+		}
+		return order.TimeInForce
+	}
+	return a.Symbol == b.Symbol &&
+		a.Side == b.Side &&
+		a.Type == b.Type &&
+		tif(a) == tif(b) &&
+		a.Quantity == b.Quantity &&
+		a.Price == b.Price &&
+		a.ExpiresAt.Equal(b.ExpiresAt)
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func clientOrder(account, clientOrderID string, quantity, price float64) models.Order {
+	order := limitOrder(account, models.SideBuy, quantity, price)
+	order.ClientOrderID = clientOrderID
+	return order
+}
+
+func TestEngine_ClientOrderID(t *testing.T) {
+	t.Run("resubmission_returns_existing_order", func(t *testing.T) {
+		// Given: An order placed with a client order ID
+		engine := newTestEngine()
+		first, err := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: The same order is retried
+		retry, err := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The existing order comes back and only one rests
+		if !retry.Duplicate || retry.Order.ID != first.Order.ID {
+			t.Errorf("Expected duplicate of %s, got %+v", first.Order.ID, retry)
+		}
+		if retry.Order.ClientOrderID != "bot-1" {
+			t.Errorf("Expected client order ID on order, got %q", retry.Order.ClientOrderID)
+		}
+		if open := engine.OpenOrders("a", ""); len(open) != 1 {
+			t.Errorf("Expected one open order, got %d", len(open))
+		}
+	})
+
+	t.Run("rejects_reuse_for_a_different_order", func(t *testing.T) {
+		// Given: An order placed with a client order ID
+		engine := newTestEngine()
+		engine.Submit(clientOrder("a", "bot-1", 1, 99))
+
+		// When: The ID is reused with a different price
+		_, err := engine.Submit(clientOrder("a", "bot-1", 1, 98))
+
+		// Then: The submission is rejected as a duplicate
+		if !errors.Is(err, ErrDuplicateClientOrderID) {
+			t.Errorf("Expected ErrDuplicateClientOrderID, got %v", err)
+		}
+	})
+
+	t.Run("ids_are_scoped_per_account", func(t *testing.T) {
+		engine := newTestEngine()
+		first, _ := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+
+		second, err := engine.Submit(clientOrder("b", "bot-1", 1, 99))
+
+		if err != nil || second.Duplicate || second.Order.ID == first.Order.ID {
+			t.Errorf("Expected a new order for another account, got %+v, %v", second, err)
+		}
+	})
+
+	t.Run("failed_submission_frees_the_id", func(t *testing.T) {
+		// Given: A halted book rejects an order with a client order ID
+		engine := newTestEngine()
+		engine.Halt("BTC-USD")
+		if _, err := engine.Submit(clientOrder("a", "bot-1", 1, 99)); !errors.Is(err, ErrHalted) {
+			t.Fatalf("Expected ErrHalted, got %v", err)
+		}
+
+		// When: Trading resumes and the order is retried
+		engine.Resume("BTC-USD")
+		report, err := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+
+		// Then: It is placed as a new order
+		if err != nil || report.Duplicate {
+			t.Errorf("Expected a new order, got %+v, %v", report, err)
+		}
+	})
+
+	t.Run("concurrent_retries_place_one_order", func(t *testing.T) {
+		// Given: Many retries of one order racing each other
+		engine := newTestEngine()
+		ids := make([]string, 16)
+		var wg sync.WaitGroup
+		for i := range ids {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				report, err := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+				if err == nil {
+					ids[i] = report.Order.ID
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// Then: Every retry sees the same order
+		for _, id := range ids {
+			if id != ids[0] || id == "" {
+				t.Fatalf("Expected every retry to return one order, got %v", ids)
+			}
+		}
+		if open := engine.OpenOrders("a", ""); len(open) != 1 {
+			t.Errorf("Expected one open order, got %d", len(open))
+		}
+	})
+
+	t.Run("replay_restores_the_index", func(t *testing.T) {
+		// Given: A recorded order with a client order ID
+		log := NewMemoryEventLog()
+		engine := NewEngine()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		first, _ := engine.Submit(clientOrder("a", "bot-1", 1, 99))
+
+		// When: The engine is replayed and the order retried
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected replay to succeed, got %v", err)
+		}
+		retry, err := replayed.Submit(clientOrder("a", "bot-1", 1, 99))
+
+		// Then: The retry is recognized after the restart
+		if err != nil || !retry.Duplicate || retry.Order.ID != first.Order.ID {
+			t.Errorf("Expected duplicate of %s, got %+v, %v", first.Order.ID, retry, err)
+		}
+	})
+}
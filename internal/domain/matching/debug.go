@@ -0,0 +1,83 @@
+package matching
+
+import (
+	"math"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// BookDump is every order resting in a book, in matching priority, for operators
+// debugging a run; unlike a snapshot it is not aggregated
+type BookDump struct {
+	Symbol         string      `json:"symbol"`
+	Phase          Phase       `json:"phase"`
+	LastPrice      float64     `json:"last_price"`
+	ReferencePrice float64     `json:"reference_price"`
+	Halt           *HaltInfo   `json:"halt,omitempty"`
+	Bids           []DumpLevel `json:"bids"`
+	Asks           []DumpLevel `json:"asks"`
+}
+
+// DumpLevel is the orders resting at one price, first to match first
+type DumpLevel struct {
+	Price    float64        `json:"price"`
+	Unpriced bool           `json:"unpriced,omitempty"` // Auction market orders, which have no price yet
+	Orders   []models.Order `json:"orders"`
+}
+
+// QueueDepth is how far one book's goroutine is behind
+type QueueDepth struct {
+	Symbol string `json:"symbol"`
+	Queued int64  `json:"queued"` // Commands waiting behind the one running
+}
+
+// Dump copies every order resting in a book
+func (e *Engine) Dump(symbol string) (BookDump, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return BookDump{}, err
+	}
+	return call(s, func() (BookDump, error) {
+		s.expireHalt(s.engine.now())
+		book := s.book
+		dump := BookDump{
+			Symbol:         book.symbol,
+			Phase:          book.phase,
+			LastPrice:      book.lastPrice,
+			ReferencePrice: book.referencePrice,
+			Bids:           book.bids.dump(),
+			Asks:           book.asks.dump(),
+		}
+		if halt := book.breaker.halt; halt != nil {
+			copied := *halt
+			dump.Halt = &copied
+		}
+		return dump, nil
+	})
+}
+
+// QueueDepths reports how many commands wait on each book's goroutine, by symbol;
+// a growing depth is a book falling behind its order flow
+func (e *Engine) QueueDepths() []QueueDepth {
+	shards := e.shardList()
+	depths := make([]QueueDepth, 0, len(shards))
+	for _, s := range shards {
+		depths = append(depths, QueueDepth{Symbol: s.book.symbol, Queued: s.queue.len()})
+	}
+	return depths
+}
+
+func (s *bookSide) dump() []DumpLevel {
+	levels := make([]DumpLevel, 0, len(s.levels))
+	for _, lvl := range s.levels {
+		dumped := DumpLevel{Price: lvl.price, Orders: make([]models.Order, 0, len(lvl.orders))}
+		if math.IsInf(lvl.price, 0) || lvl.price == 0 {
+			dumped.Price, dumped.Unpriced = 0, true
+		}
+		for _, order := range lvl.orders {
+			dumped.Orders = append(dumped.Orders, *order)
+		}
+		levels = append(levels, dumped)
+	}
+	return levels
+}
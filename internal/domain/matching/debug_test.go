@@ -0,0 +1,67 @@
+//go:build unit
+
+package matching
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_Dump(t *testing.T) {
+	t.Run("lists_every_resting_order_in_matching_priority", func(t *testing.T) {
+		// Given: Two bids at one price and an ask
+		engine := newTestEngine()
+		defer engine.Close()
+		first, _ := engine.Submit(limitOrder("a", models.SideBuy, 1, 99))
+		second, _ := engine.Submit(limitOrder("b", models.SideBuy, 2, 99))
+		engine.Submit(limitOrder("c", models.SideSell, 1, 101))
+
+		// When: The book is dumped
+		dump, err := engine.Dump("BTC-USD")
+
+		// Then: Each order is listed at its price, first to match first
+		if err != nil || len(dump.Bids) != 1 || len(dump.Asks) != 1 {
+			t.Fatalf("Expected one level per side, got %+v (err %v)", dump, err)
+		}
+		bids := dump.Bids[0].Orders
+		if dump.Bids[0].Price != 99 || len(bids) != 2 || bids[0].ID != first.Order.ID || bids[1].ID != second.Order.ID {
+			t.Errorf("Expected both bids at 99 in time priority, got %+v", dump.Bids[0])
+		}
+		if dump.Phase != PhaseContinuous || dump.ReferencePrice != 100 {
+			t.Errorf("Expected the book's state, got %+v", dump)
+		}
+	})
+}
+
+func TestEngine_QueueDepths(t *testing.T) {
+	t.Run("counts_commands_waiting_on_a_busy_book", func(t *testing.T) {
+		// Given: A book whose goroutine is busy, with commands behind it
+		engine := newTestEngine()
+		defer engine.Close()
+		s, _ := engine.shardFor("BTC-USD")
+		release, blocked := make(chan struct{}), make(chan struct{})
+		go s.exec(func() {
+			close(blocked)
+			<-release
+		})
+		<-blocked
+		for i := 0; i < 3; i++ {
+			go s.exec(func() {})
+		}
+		for engine.QueueDepths()[0].Queued < 3 {
+			runtime.Gosched()
+		}
+
+		// When: The book catches up
+		close(release)
+		engine.Ping(context.Background())
+
+		// Then: Nothing waits any more
+		if depths := engine.QueueDepths(); len(depths) != 1 || depths[0].Symbol != "BTC-USD" || depths[0].Queued != 0 {
+			t.Errorf("Expected an empty queue, got %+v", depths)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var ErrInvalidDeleverage = errors.New("invalid deleverage")
+
+// EventDeleveraged records a position closed against a counterparty outside the book
+const EventDeleveraged EventType = "deleveraged"
+
+// Deleverage closes quantity of a bankrupt account's position against the opposing
+// position of a counterparty at the bankruptcy price
+type Deleverage struct {
+	AccountID    string      `json:"account_id"`   // The bankrupt account
+	Counterparty string      `json:"counterparty"` // The account whose opposing position is reduced
+	Side         models.Side `json:"side"`         // The bankrupt account's side; sell closes a long
+	Quantity     float64     `json:"quantity"`
+	Price        float64     `json:"price"`
+}
+
+// Deleverage fills a bankrupt account and a counterparty against each other at the
+// given price without touching the book: no resting order trades, and the last
+// price and circuit breaker reference are left alone, since the price was not
+// discovered by the market. Each side gets a filled IOC order, so positions derived
+// from fills reflect it; the report carries the bankrupt account's order and the trade.
+func (e *Engine) Deleverage(symbol string, deleverage Deleverage) (*ExecutionReport, error) {
+	switch {
+	case deleverage.AccountID == "" || deleverage.Counterparty == "" || deleverage.AccountID == deleverage.Counterparty:
+		return nil, fmt.Errorf("%w: two different accounts are required", ErrInvalidDeleverage)
+	case deleverage.Side != models.SideBuy && deleverage.Side != models.SideSell:
+		return nil, fmt.Errorf("%w: invalid side %q", ErrInvalidDeleverage, deleverage.Side)
+	case deleverage.Quantity <= 0 || deleverage.Price <= 0:
+		return nil, fmt.Errorf("%w: quantity and price must be positive", ErrInvalidDeleverage)
+	}
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return call(s, func() (*ExecutionReport, error) { return s.deleverage(deleverage) })
+}
+
+func (s *shard) deleverage(deleverage Deleverage) (*ExecutionReport, error) {
+	now := s.engine.now()
+	if err := s.engine.record(Event{Type: EventDeleveraged, Time: now, Symbol: s.book.symbol, Deleverage: &deleverage}); err != nil {
+		return nil, err
+	}
+
+	bankrupt := s.deleverageOrder(deleverage.AccountID, deleverage.Side, deleverage, now)
+	counterparty := s.deleverageOrder(deleverage.Counterparty, deleverage.Side.Opposite(), deleverage, now)
+	buy, sell := bankrupt, counterparty
+	if bankrupt.Side == models.SideSell {
+		buy, sell = counterparty, bankrupt
+	}
+
+	s.nextTradeID++
+	trade := models.Trade{
+		ID:            fmt.Sprintf("%s%s-%d", tradeIDPrefix, s.book.symbol, s.nextTradeID),
+		Symbol:        s.book.symbol,
+		Price:         deleverage.Price,
+		Quantity:      deleverage.Quantity,
+		BuyOrderID:    buy.ID,
+		SellOrderID:   sell.ID,
+		BuyAccountID:  buy.AccountID,
+		SellAccountID: sell.AccountID,
+		Deleverage:    true,
+		ExecutedAt:    now,
+	}
+	s.recordTrade(trade)
+	return &ExecutionReport{Order: *bankrupt, Trades: []models.Trade{trade}}, nil
+}
+
+// deleverageOrder books one side of a deleverage as an IOC order filled in full
+func (s *shard) deleverageOrder(accountID string, side models.Side, deleverage Deleverage, now time.Time) *models.Order {
+	s.nextOrderID++
+	order := &models.Order{
+		ID:          OrderID(s.book.symbol, s.nextOrderID),
+		AccountID:   accountID,
+		Symbol:      s.book.symbol,
+		Side:        side,
+		Type:        models.OrderTypeLimit,
+		TimeInForce: models.TimeInForceIOC,
+		Quantity:    deleverage.Quantity,
+		Price:       deleverage.Price,
+		Status:      models.OrderStatusNew,
+		CreatedAt:   now,
+	}
+	order.ApplyFill(deleverage.Quantity, deleverage.Price, now)
+	s.orders[order.ID] = order
+	return order
+}
@@ -0,0 +1,78 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_Deleverage(t *testing.T) {
+	t.Run("fills_both_accounts_at_the_price_without_touching_the_book", func(t *testing.T) {
+		// Given: A book that last traded at 100 with a bid resting at 99
+		engine := newTestEngine()
+		engine.Submit(limitOrder("mm", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("a", models.SideBuy, 1, 100))
+		engine.Submit(limitOrder("mm", models.SideBuy, 1, 99))
+
+		// When: A long is deleveraged against a short at a bankruptcy price of 90
+		report, err := engine.Deleverage("BTC-USD", Deleverage{AccountID: "a", Counterparty: "b", Side: models.SideSell, Quantity: 0.5, Price: 90})
+
+		// Then: Both sides are filled at 90 and the bid and last price are untouched
+		if err != nil || report.Order.Status != models.OrderStatusFilled || len(report.Trades) != 1 {
+			t.Fatalf("Expected a filled deleverage, got %+v, %v", report, err)
+		}
+		trade := report.Trades[0]
+		if !trade.Deleverage || trade.SellAccountID != "a" || trade.BuyAccountID != "b" || trade.Price != 90 || trade.Quantity != 0.5 {
+			t.Errorf("Expected b to buy 0.5 from a at 90, got %+v", trade)
+		}
+		if filled := engine.FilledOrders("b"); len(filled) != 1 || filled[0].Side != models.SideBuy || filled[0].AveragePrice != 90 {
+			t.Errorf("Expected the counterparty's filled buy, got %+v", filled)
+		}
+		if last, _ := engine.LastPrice("BTC-USD"); last != 100 {
+			t.Errorf("Expected the last price to stay 100, got %v", last)
+		}
+		if snapshot, _ := engine.Snapshot("BTC-USD", 0); len(snapshot.Bids) != 1 || snapshot.Bids[0].Quantity != 1 {
+			t.Errorf("Expected the bid to keep resting, got %+v", snapshot.Bids)
+		}
+	})
+
+	t.Run("refuses_a_deleverage_against_the_same_account", func(t *testing.T) {
+		// Given: An engine with a book
+		engine := newTestEngine()
+
+		// When: An account is deleveraged against itself
+		_, err := engine.Deleverage("BTC-USD", Deleverage{AccountID: "a", Counterparty: "a", Side: models.SideSell, Quantity: 1, Price: 90})
+
+		// Then: It is refused
+		if !errors.Is(err, ErrInvalidDeleverage) {
+			t.Errorf("Expected ErrInvalidDeleverage, got %v", err)
+		}
+	})
+
+	t.Run("replay_reproduces_deleverages", func(t *testing.T) {
+		// Given: A recorded session with a deleverage
+		engine := NewEngine()
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		engine.Submit(limitOrder("mm", models.SideSell, 1, 100))
+		engine.Deleverage("BTC-USD", Deleverage{AccountID: "a", Counterparty: "b", Side: models.SideBuy, Quantity: 1, Price: 110})
+
+		// When: The log is replayed
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The deleverage orders are rebuilt identically
+		originalOrders, originalTrades := shardState(t, engine, "BTC-USD")
+		replayedOrders, replayedTrades := shardState(t, replayed, "BTC-USD")
+		if !reflect.DeepEqual(originalOrders, replayedOrders) || originalTrades != replayedTrades {
+			t.Errorf("Orders diverged:\n%+v\n%+v", originalOrders, replayedOrders)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// DryRun reports what Submit would do with order against the book as it stands,
+// without recording, matching or resting anything. The order and its trades carry
+// no IDs, and the book may have moved by the time a real order arrives.
+func (e *Engine) DryRun(order models.Order) (*ExecutionReport, error) {
+	s, err := e.shardFor(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if order.ClientOrderID != "" {
+		if report, err := e.dryRunDuplicate(order); report != nil || err != nil {
+			return report, err
+		}
+	}
+	return call(s, func() (*ExecutionReport, error) { return s.dryRun(order) })
+}
+
+// dryRunDuplicate is what resubmitting a client order ID already placed would return
+func (e *Engine) dryRunDuplicate(order models.Order) (*ExecutionReport, error) {
+	e.clientOrders.mu.Lock()
+	entry, exists := e.clientOrders.entries[clientOrderKey{accountID: order.AccountID, clientOrderID: order.ClientOrderID}]
+	e.clientOrders.mu.Unlock()
+	if !exists {
+		return nil, nil
+	}
+	select {
+	case <-entry.done:
+	default:
+		return nil, nil // Still being placed; the real submission would wait for it
+	}
+	if entry.orderID == "" {
+		return nil, nil
+	}
+	if !sameOrderRequest(entry.request, order) {
+		return nil, fmt.Errorf("%w: %s is already used by %s", ErrDuplicateClientOrderID, order.ClientOrderID, entry.orderID)
+	}
+	existing, err := e.GetOrder(entry.orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionReport{Order: existing, Duplicate: true}, nil
+}
+
+// dryRun mirrors submit on a copy of the order, reading the book without changing it
+func (s *shard) dryRun(order models.Order) (*ExecutionReport, error) {
+	book := s.book
+	now := s.engine.now()
+	if book.phase == PhaseClosed {
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, order.Symbol)
+	}
+	if halt := book.breaker.halt; book.phase == PhaseHalted && (halt == nil || halt.ResumesAt.IsZero() || now.Before(halt.ResumesAt)) {
+		// A halt whose cooldown has run out would be lifted by the real submission
+		return nil, fmt.Errorf("%w: %s", ErrHalted, order.Symbol)
+	}
+
+	order.ID = ""
+	order.FilledQuantity = 0
+	order.AveragePrice = 0
+	order.Status = models.OrderStatusNew
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if order.TimeInForce == "" {
+		order.TimeInForce = models.TimeInForceGTC
+	}
+	report := &ExecutionReport{Order: order}
+
+	if order.TimeInForce == models.TimeInForceGTD && !order.ExpiresAt.IsZero() && !now.Before(order.ExpiresAt) {
+		report.Order.Status = models.OrderStatusExpired
+		return report, nil
+	}
+	if book.phase == PhaseAuction {
+		if !order.TimeInForce.Rests() {
+			report.Order.Status = models.OrderStatusRejected
+		}
+		return report, nil
+	}
+
+	live := &report.Order
+	contra := book.sideOf(order.Side.Opposite())
+	if live.TimeInForce == models.TimeInForceFOK && s.liveQuantity(live, now) < live.Quantity-1e-12 {
+		live.Status = models.OrderStatusCanceled
+		return report, nil
+	}
+
+	var trades []models.Trade
+sweep:
+	for _, lvl := range contra.levels {
+		if live.RemainingQuantity() <= 0 || !crosses(live, lvl.price) {
+			break
+		}
+		if book.breaker.wouldTrip(lvl.price, now) {
+			break
+		}
+		for _, resting := range lvl.orders {
+			if live.RemainingQuantity() <= 0 {
+				break sweep
+			}
+			if expired(resting, now) {
+				continue // The real submission sweeps it off the book first
+			}
+			quantity := math.Min(live.RemainingQuantity(), resting.RemainingQuantity())
+			live.ApplyFill(quantity, lvl.price, now)
+			trades = append(trades, dryRunTrade(live, resting, lvl.price, quantity, now))
+		}
+	}
+
+	if live.RemainingQuantity() > 0 && (live.Type == models.OrderTypeMarket || !live.TimeInForce.Rests()) {
+		live.Status = models.OrderStatusCanceled
+	}
+	report.Trades = trades
+	return report, nil
+}
+
+// liveQuantity is availableQuantity without the resting orders due to expire
+func (s *shard) liveQuantity(order *models.Order, now time.Time) float64 {
+	total := 0.0
+	for _, lvl := range s.book.sideOf(order.Side.Opposite()).levels {
+		if !crosses(order, lvl.price) {
+			break
+		}
+		for _, resting := range lvl.orders {
+			if !expired(resting, now) {
+				total += resting.RemainingQuantity()
+			}
+		}
+	}
+	return total
+}
+
+func expired(order *models.Order, now time.Time) bool {
+	return order.TimeInForce == models.TimeInForceGTD && !order.ExpiresAt.IsZero() && !now.Before(order.ExpiresAt)
+}
+
+// dryRunTrade is the trade a dry run would execute, without an ID
+func dryRunTrade(taker, maker *models.Order, price, quantity float64, now time.Time) models.Trade {
+	buy, sell := taker, maker
+	if taker.Side == models.SideSell {
+		buy, sell = maker, taker
+	}
+	return models.Trade{
+		Symbol:        taker.Symbol,
+		Price:         price,
+		Quantity:      quantity,
+		BuyOrderID:    buy.ID,
+		SellOrderID:   sell.ID,
+		BuyAccountID:  buy.AccountID,
+		SellAccountID: sell.AccountID,
+		TakerSide:     taker.Side,
+		ExecutedAt:    now,
+	}
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+package matching
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_DryRun(t *testing.T) {
+	t.Run("estimates_fills_without_touching_the_book", func(t *testing.T) {
+		// Given: Asks at two prices
+		engine := newTestEngine()
+		engine.Submit(limitOrder("m1", models.SideSell, 1, 101))
+		engine.Submit(limitOrder("m2", models.SideSell, 2, 102))
+		before, _ := engine.Snapshot("BTC-USD", 0)
+		sequence := engine.Sequence()
+
+		// When: A bid through both levels is dry-run
+		report, err := engine.DryRun(limitOrder("t", models.SideBuy, 2, 102))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: It would take one from each level, without an order or trade ID
+		if len(report.Trades) != 2 || report.Trades[0].Price != 101 || report.Trades[1].Price != 102 ||
+			report.Trades[0].ID != "" || report.Order.ID != "" {
+			t.Errorf("Expected fills at 101 and 102 without IDs, got %+v", report)
+		}
+		if report.Order.Status != models.OrderStatusFilled || report.Order.AveragePrice != 101.5 {
+			t.Errorf("Expected a fill averaging 101.5, got %+v", report.Order)
+		}
+
+		// And: Nothing was recorded and the book is as it was
+		after, _ := engine.Snapshot("BTC-USD", 0)
+		if engine.Sequence() != sequence || !reflect.DeepEqual(before, after) {
+			t.Errorf("Expected the book untouched, got %+v after %+v", after, before)
+		}
+	})
+
+	t.Run("reports_what_would_rest_or_be_killed", func(t *testing.T) {
+		engine := newTestEngine()
+		engine.Submit(limitOrder("m", models.SideSell, 1, 101))
+
+		resting, _ := engine.DryRun(limitOrder("t", models.SideBuy, 3, 101))
+		fok := limitOrder("t", models.SideBuy, 3, 101)
+		fok.TimeInForce = models.TimeInForceFOK
+		killed, _ := engine.DryRun(fok)
+
+		if resting.Order.Status != models.OrderStatusPartiallyFilled || resting.Order.RemainingQuantity() != 2 {
+			t.Errorf("Expected 2 left resting, got %+v", resting.Order)
+		}
+		if killed.Order.Status != models.OrderStatusCanceled || len(killed.Trades) != 0 {
+			t.Errorf("Expected the FOK to be canceled without trading, got %+v", killed)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package matching
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var (
+	ErrUnknownSymbol  = errors.New("unknown symbol")
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrOrderNotActive = errors.New("order is not active")
+	ErrMarketClosed   = errors.New("market is closed")
+	ErrInvalidPhase   = errors.New("operation not allowed in current phase")
+	ErrHalted         = errors.New("trading is halted")
+	ErrEngineClosed   = errors.New("matching engine is closed")
+)
+
+// ExecutionReport is the outcome of submitting an order to the engine
+type ExecutionReport struct {
+	Order     models.Order   `json:"order"`
+	Trades    []models.Trade `json:"trades"`
+	Duplicate bool           `json:"duplicate,omitempty"` // Resubmitted client order ID; Order is the existing order
+}
+
+// Engine matches orders using FIFO price-time priority. Each symbol is a shard
+// owned by its own goroutine, so symbols match in parallel without a global lock.
+// Order and trade IDs embed the symbol, which routes order lookups to their shard.
+type Engine struct {
+	shards   atomic.Pointer[map[string]*shard] // copy-on-write; readers never lock
+	registry sync.Mutex                        // serializes listing, default breaker changes and purges
+
+	now             func() time.Time
+	defaultBreaker  CircuitBreakerConfig
+	expiryPrecision time.Duration // Slot width of each book's expiry wheel
+	tombstones      []Tombstone
+	clientOrders    *clientOrderIndex
+
+	logging  atomic.Bool
+	logMu    sync.Mutex
+	events   EventLog
+	sequence uint64
+}
+
+func NewEngine() *Engine {
+	e := &Engine{
+		now:            time.Now,
+		defaultBreaker: DefaultCircuitBreakerConfig(),
+		clientOrders:   newClientOrderIndex(),
+	}
+	shards := make(map[string]*shard)
+	e.shards.Store(&shards)
+	return e
+}
+
+// Close stops every shard goroutine; later calls fail with ErrEngineClosed
+func (e *Engine) Close() {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	for _, s := range *e.shards.Load() {
+		s.close()
+	}
+}
+
+// SetDefaultCircuitBreaker sets the breaker applied to books added afterwards
+func (e *Engine) SetDefaultCircuitBreaker(config CircuitBreakerConfig) error {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	if err := e.record(Event{Type: EventDefaultBreakerSet, Time: e.now(), CircuitBreaker: &config}); err != nil {
+		return err
+	}
+	e.defaultBreaker = config
+	return nil
+}
+
+// AddBook lists a symbol; referencePrice seeds auction tie-breaks before the first trade
+func (e *Engine) AddBook(symbol string, referencePrice float64) error {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	current := *e.shards.Load()
+	if _, exists := current[symbol]; exists {
+		return nil
+	}
+	if err := e.record(Event{Type: EventBookAdded, Time: e.now(), Symbol: symbol, ReferencePrice: referencePrice}); err != nil {
+		return err
+	}
+
+	book := newOrderBook(symbol, referencePrice)
+	book.breaker.config = e.defaultBreaker
+
+	next := make(map[string]*shard, len(current)+1)
+	for existing, s := range current {
+		next[existing] = s
+	}
+	next[symbol] = newShard(e, book)
+	e.shards.Store(&next)
+	return nil
+}
+
+// Submit accepts an order and matches it according to the book's session phase.
+// Orders with a client order ID are placed at most once per account.
+func (e *Engine) Submit(order models.Order) (*ExecutionReport, error) {
+	s, err := e.shardFor(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if order.ClientOrderID != "" {
+		return e.submitIdempotent(s, order)
+	}
+	return call(s, func() (*ExecutionReport, error) { return s.submit(order) })
+}
+
+// Cancel removes a working order from its book
+func (e *Engine) Cancel(orderID string) (models.Order, error) {
+	s, err := e.shardForOrder(orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return call(s, func() (models.Order, error) { return s.cancel(orderID) })
+}
+
+// GetOrder returns a snapshot of any order known to the engine
+func (e *Engine) GetOrder(orderID string) (models.Order, error) {
+	s, err := e.shardForOrder(orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return call(s, func() (models.Order, error) { return s.getOrder(orderID) })
+}
+
+// OpenOrders returns working orders, optionally filtered by account and symbol
+func (e *Engine) OpenOrders(accountID, symbol string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, s := range e.shardList() {
+		if symbol != "" && s.book.symbol != symbol {
+			continue
+		}
+		found, _ := call(s, func() ([]models.Order, error) { return s.openOrders(accountID), nil })
+		orders = append(orders, found...)
+	}
+	sortByCreation(orders)
+	return orders
+}
+
+// Orders returns every order the books know, working or not, optionally filtered by
+// account and symbol, oldest first
+func (e *Engine) Orders(accountID, symbol string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, s := range e.shardList() {
+		if symbol != "" && s.book.symbol != symbol {
+			continue
+		}
+		found, _ := call(s, func() ([]models.Order, error) { return s.accountOrders(accountID), nil })
+		orders = append(orders, found...)
+	}
+	sortByCreation(orders)
+	return orders
+}
+
+// FilledOrders returns every order with at least one fill, working or not, optionally
+// for one account, oldest first
+func (e *Engine) FilledOrders(accountID string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, s := range e.shardList() {
+		found, _ := call(s, func() ([]models.Order, error) { return s.filledOrders(accountID), nil })
+		orders = append(orders, found...)
+	}
+	sortByCreation(orders)
+	return orders
+}
+
+// Snapshot returns up to levels aggregated price levels per side (0 = all)
+func (e *Engine) Snapshot(symbol string, levels int) (BookSnapshot, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return BookSnapshot{}, err
+	}
+	return call(s, func() (BookSnapshot, error) {
+		s.expireHalt(s.engine.now())
+		return s.book.snapshot(levels), nil
+	})
+}
+
+// Phase returns the session phase of a book
+func (e *Engine) Phase(symbol string) (Phase, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return "", err
+	}
+	return call(s, func() (Phase, error) {
+		s.expireHalt(s.engine.now())
+		return s.book.phase, nil
+	})
+}
+
+// LastPrice returns the last traded price, or the reference price before any trade
+func (e *Engine) LastPrice(symbol string) (float64, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return call(s, func() (float64, error) { return s.book.markPrice(), nil })
+}
+
+// Ping checks that every book's goroutine still takes commands, failing on the
+// first that does not answer before ctx ends
+func (e *Engine) Ping(ctx context.Context) error {
+	for _, s := range e.shardList() {
+		if err := s.ping(ctx); err != nil {
+			return fmt.Errorf("book %s: %w", s.book.symbol, err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) shardFor(symbol string) (*shard, error) {
+	s, exists := (*e.shards.Load())[symbol]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+	return s, nil
+}
+
+// shardForOrder routes an order ID of the form ord-<symbol>-<n> to its shard
+func (e *Engine) shardForOrder(orderID string) (*shard, error) {
+	if symbol, _, ok := ParseOrderID(orderID); ok {
+		if s, exists := (*e.shards.Load())[symbol]; exists {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+}
+
+// OrderID formats the ID of the nth order placed on a symbol's book
+func OrderID(symbol string, n uint64) string {
+	return fmt.Sprintf("%s%s-%d", orderIDPrefix, symbol, n)
+}
+
+// ParseOrderID splits an order ID into its symbol and per-book number
+func ParseOrderID(orderID string) (symbol string, n uint64, ok bool) {
+	rest, ok := strings.CutPrefix(orderID, orderIDPrefix)
+	idx := strings.LastIndex(rest, "-")
+	if !ok || idx <= 0 {
+		return "", 0, false
+	}
+	n, err := strconv.ParseUint(rest[idx+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return rest[:idx], n, true
+}
+
+// shardList returns all shards sorted by symbol
+func (e *Engine) shardList() []*shard {
+	current := *e.shards.Load()
+	shards := make([]*shard, 0, len(current))
+	for _, s := range current {
+		shards = append(shards, s)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].book.symbol < shards[j].book.symbol })
+	return shards
+}
+
+// crosses reports whether an order is willing to trade at price
+func crosses(order *models.Order, price float64) bool {
+	if order.Type == models.OrderTypeMarket {
+		return true
+	}
+	if order.Side == models.SideBuy {
+		return order.Price >= price
+	}
+	return order.Price <= price
+}
@@ -0,0 +1,176 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func limitOrder(account string, side models.Side, quantity, price float64) models.Order {
+	return models.Order{
+		AccountID: account,
+		Symbol:    "BTC-USD",
+		Side:      side,
+		Type:      models.OrderTypeLimit,
+		Quantity:  quantity,
+		Price:     price,
+	}
+}
+
+func newTestEngine() *Engine {
+	engine := NewEngine()
+	engine.AddBook("BTC-USD", 100)
+	return engine
+}
+
+func TestEngine_Submit(t *testing.T) {
+	t.Run("rests_non_crossing_limit_orders", func(t *testing.T) {
+		// Given: An empty book
+		engine := newTestEngine()
+
+		// When: A bid and a higher ask are submitted
+		bid, _ := engine.Submit(limitOrder("a", models.SideBuy, 1, 99))
+		ask, _ := engine.Submit(limitOrder("b", models.SideSell, 1, 101))
+
+		// Then: Neither trades and both rest on the book
+		if len(bid.Trades) != 0 || len(ask.Trades) != 0 {
+			t.Fatal("Expected no trades for non-crossing orders")
+		}
+		snapshot, _ := engine.Snapshot("BTC-USD", 0)
+		if len(snapshot.Bids) != 1 || len(snapshot.Asks) != 1 {
+			t.Errorf("Expected one level per side, got %d bids %d asks", len(snapshot.Bids), len(snapshot.Asks))
+		}
+	})
+
+	t.Run("matches_in_price_time_priority", func(t *testing.T) {
+		// Given: Two asks at the same price and one better ask
+		engine := newTestEngine()
+		first, _ := engine.Submit(limitOrder("m1", models.SideSell, 1, 101))
+		second, _ := engine.Submit(limitOrder("m2", models.SideSell, 1, 101))
+		best, _ := engine.Submit(limitOrder("m3", models.SideSell, 1, 100))
+
+		// When: A buy sweeps two units
+		report, err := engine.Submit(limitOrder("t", models.SideBuy, 2, 101))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The better price fills first, then the earlier order at 101
+		if len(report.Trades) != 2 {
+			t.Fatalf("Expected 2 trades, got %d", len(report.Trades))
+		}
+		if report.Trades[0].SellOrderID != best.Order.ID || report.Trades[0].Price != 100 {
+			t.Errorf("Expected first fill against best ask at 100, got %+v", report.Trades[0])
+		}
+		if report.Trades[1].SellOrderID != first.Order.ID {
+			t.Errorf("Expected second fill against earliest order at 101")
+		}
+		if report.Order.Status != models.OrderStatusFilled {
+			t.Errorf("Expected taker filled, got %s", report.Order.Status)
+		}
+		if report.Trades[0].TakerSide != models.SideBuy {
+			t.Errorf("Expected taker side buy, got %s", report.Trades[0].TakerSide)
+		}
+
+		// And: The later order at the same price keeps resting
+		remaining, _ := engine.GetOrder(second.Order.ID)
+		if remaining.Status != models.OrderStatusNew {
+			t.Errorf("Expected second order still new, got %s", remaining.Status)
+		}
+	})
+
+	t.Run("cancels_unfilled_remainder_of_market_and_ioc_orders", func(t *testing.T) {
+		engine := newTestEngine()
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+
+		market := models.Order{AccountID: "t", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeMarket, Quantity: 3}
+		report, _ := engine.Submit(market)
+
+		if report.Order.FilledQuantity != 1 || report.Order.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected 1 filled and remainder canceled, got %+v", report.Order)
+		}
+		if len(engine.OpenOrders("", "BTC-USD")) != 0 {
+			t.Error("Expected no resting orders after market sweep")
+		}
+	})
+
+	t.Run("fill_or_kill_does_not_trade_without_full_liquidity", func(t *testing.T) {
+		engine := newTestEngine()
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+
+		fok := limitOrder("t", models.SideBuy, 2, 100)
+		fok.TimeInForce = models.TimeInForceFOK
+		report, _ := engine.Submit(fok)
+
+		if len(report.Trades) != 0 || report.Order.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected FOK to be killed without trades, got %+v", report)
+		}
+	})
+
+	t.Run("fills_higher_priority_tiers_first_within_a_level", func(t *testing.T) {
+		// Given: Two plain asks at 101, then a tier-1 ask at 101 and a tier-1 ask at 102
+		engine := newTestEngine()
+		remote, _ := engine.Submit(limitOrder("remote", models.SideSell, 1, 101))
+		engine.Submit(limitOrder("remote-2", models.SideSell, 1, 101))
+		colocated := limitOrder("colo", models.SideSell, 1, 101)
+		colocated.Priority = 1
+		colo, _ := engine.Submit(colocated)
+		worse := limitOrder("colo", models.SideSell, 1, 102)
+		worse.Priority = 1
+		engine.Submit(worse)
+
+		// When: A buy takes two units at 102
+		report, _ := engine.Submit(limitOrder("t", models.SideBuy, 2, 102))
+
+		// Then: The tier jumps the queue at its own price but never ahead of a better price
+		if len(report.Trades) != 2 {
+			t.Fatalf("Expected 2 trades, got %d", len(report.Trades))
+		}
+		if report.Trades[0].SellOrderID != colo.Order.ID || report.Trades[1].SellOrderID != remote.Order.ID {
+			t.Errorf("Expected the tier-1 ask then the earliest plain ask, got %s and %s",
+				report.Trades[0].SellOrderID, report.Trades[1].SellOrderID)
+		}
+	})
+
+	t.Run("rejects_unknown_symbol", func(t *testing.T) {
+		engine := newTestEngine()
+		order := limitOrder("a", models.SideBuy, 1, 1)
+		order.Symbol = "XYZ"
+
+		if _, err := engine.Submit(order); !errors.Is(err, ErrUnknownSymbol) {
+			t.Errorf("Expected ErrUnknownSymbol, got %v", err)
+		}
+	})
+}
+
+func TestEngine_Cancel(t *testing.T) {
+	t.Run("removes_resting_order_from_book", func(t *testing.T) {
+		engine := newTestEngine()
+		report, _ := engine.Submit(limitOrder("a", models.SideBuy, 1, 99))
+
+		canceled, err := engine.Cancel(report.Order.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if canceled.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected canceled status, got %s", canceled.Status)
+		}
+		snapshot, _ := engine.Snapshot("BTC-USD", 0)
+		if len(snapshot.Bids) != 0 {
+			t.Error("Expected bid level removed after cancel")
+		}
+	})
+
+	t.Run("rejects_cancel_of_filled_order", func(t *testing.T) {
+		engine := newTestEngine()
+		resting, _ := engine.Submit(limitOrder("a", models.SideBuy, 1, 100))
+		engine.Submit(limitOrder("b", models.SideSell, 1, 100))
+
+		if _, err := engine.Cancel(resting.Order.ID); !errors.Is(err, ErrOrderNotActive) {
+			t.Errorf("Expected ErrOrderNotActive, got %v", err)
+		}
+	})
+}
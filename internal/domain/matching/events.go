@@ -0,0 +1,216 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var ErrReplayDiverged = errors.New("replay diverged from event log")
+
+// EventType identifies an engine mutation recorded in the event log
+type EventType string
+
+const (
+	EventBookAdded         EventType = "book_added"
+	EventDefaultBreakerSet EventType = "default_circuit_breaker_set"
+	EventCircuitBreakerSet EventType = "circuit_breaker_set"
+	EventOrderSubmitted    EventType = "order_submitted"
+	EventOrderCanceled     EventType = "order_canceled"
+	EventOrderAmended      EventType = "order_amended"
+	EventAuctionStarted    EventType = "auction_started"
+	EventAuctionUncrossed  EventType = "auction_uncrossed"
+	EventTradingHalted     EventType = "trading_halted"
+	EventTradingResumed    EventType = "trading_resumed"
+)
+
+// Event is one accepted engine command. Replaying events in sequence with their
+// recorded times rebuilds identical book state, including order and trade IDs.
+type Event struct {
+	Sequence       uint64                `json:"sequence"`
+	Type           EventType             `json:"type"`
+	Time           time.Time             `json:"time"`
+	Symbol         string                `json:"symbol,omitempty"`
+	OrderID        string                `json:"order_id,omitempty"`
+	Order          *models.Order         `json:"order,omitempty"`
+	Amend          *AmendRequest         `json:"amend,omitempty"`
+	AuctionKind    AuctionKind           `json:"auction_kind,omitempty"`
+	ReferencePrice float64               `json:"reference_price,omitempty"`
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker,omitempty"`
+	AccountAlias   string                `json:"account_alias,omitempty"`
+	Deleverage     *Deleverage           `json:"deleverage,omitempty"`
+}
+
+// EventLog is an append-only sink for engine events
+type EventLog interface {
+	Append(event Event) error
+}
+
+// MemoryEventLog keeps events in memory, mainly for tests and short-lived sessions
+type MemoryEventLog struct {
+	events []Event
+	mu     sync.Mutex
+}
+
+func NewMemoryEventLog() *MemoryEventLog {
+	return &MemoryEventLog{events: make([]Event, 0)}
+}
+
+func (l *MemoryEventLog) Append(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+// Events returns a copy of the recorded events in sequence order
+func (l *MemoryEventLog) Events() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+// Redact rewrites recorded events in place and returns how many changed
+func (l *MemoryEventLog) Redact(rewrite func(Event) (Event, bool)) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	changed := 0
+	for i, event := range l.events {
+		if rewritten, ok := rewrite(event); ok {
+			l.events[i] = rewritten
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// SetEventLog starts recording every accepted mutation to log; nil disables recording
+func (e *Engine) SetEventLog(log EventLog) {
+	e.logMu.Lock()
+	defer e.logMu.Unlock()
+	e.events = log
+	e.logging.Store(log != nil)
+}
+
+// Sequence returns the sequence number of the last recorded or replayed event
+func (e *Engine) Sequence() uint64 {
+	e.logMu.Lock()
+	defer e.logMu.Unlock()
+	return e.sequence
+}
+
+// record writes an event ahead of applying it; a failed append rejects the command.
+// Shards record concurrently, so sequencing and appending happen under one lock to
+// keep the log in sequence order; per-symbol ordering is all replay depends on.
+func (e *Engine) record(event Event) error {
+	if !e.logging.Load() {
+		return nil
+	}
+	e.logMu.Lock()
+	defer e.logMu.Unlock()
+
+	if e.events == nil {
+		return nil
+	}
+	event.Sequence = e.sequence + 1
+	if err := e.events.Append(event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", event.Type, err)
+	}
+	e.sequence = event.Sequence
+	return nil
+}
+
+// Replay rebuilds an engine by re-applying events in order with their recorded clock
+func Replay(events []Event) (*Engine, error) {
+	engine := NewEngine()
+	var clock time.Time
+	engine.now = func() time.Time { return clock }
+
+	if err := engine.replay(events, &clock); err != nil {
+		engine.Close()
+		return nil, err
+	}
+	engine.now = time.Now
+	return engine, nil
+}
+
+// replay applies events following on from the engine's sequence, setting clock,
+// which the engine reads as its time, to each event's
+func (e *Engine) replay(events []Event, clock *time.Time) error {
+	for _, event := range events {
+		if event.Sequence != e.sequence+1 {
+			return fmt.Errorf("%w: expected sequence %d, got %d", ErrReplayDiverged, e.sequence+1, event.Sequence)
+		}
+		*clock = event.Time
+		if err := e.apply(event); err != nil {
+			return fmt.Errorf("replay of event %d (%s) failed: %w", event.Sequence, event.Type, err)
+		}
+		e.sequence = event.Sequence
+	}
+	return nil
+}
+
+// apply dispatches a recorded event to the command that produced it
+func (e *Engine) apply(event Event) error {
+	switch event.Type {
+	case EventBookAdded:
+		e.AddBook(event.Symbol, event.ReferencePrice)
+	case EventDefaultBreakerSet:
+		if event.CircuitBreaker == nil {
+			return fmt.Errorf("missing circuit breaker config")
+		}
+		e.SetDefaultCircuitBreaker(*event.CircuitBreaker)
+	case EventCircuitBreakerSet:
+		if event.CircuitBreaker == nil {
+			return fmt.Errorf("missing circuit breaker config")
+		}
+		return e.SetCircuitBreaker(event.Symbol, *event.CircuitBreaker)
+	case EventOrderSubmitted:
+		if event.Order == nil {
+			return fmt.Errorf("missing order")
+		}
+		report, err := e.Submit(*event.Order)
+		if err != nil {
+			return err
+		}
+		if report.Order.ID != event.OrderID {
+			return fmt.Errorf("%w: order assigned %s, log recorded %s", ErrReplayDiverged, report.Order.ID, event.OrderID)
+		}
+	case EventOrderCanceled:
+		_, err := e.Cancel(event.OrderID)
+		return err
+	case EventOrderAmended:
+		if event.Amend == nil {
+			return fmt.Errorf("missing amend request")
+		}
+		_, err := e.Amend(event.OrderID, *event.Amend)
+		return err
+	case EventAuctionStarted:
+		return e.StartAuction(event.Symbol, event.AuctionKind)
+	case EventAuctionUncrossed:
+		_, err := e.Uncross(event.Symbol)
+		return err
+	case EventTradingHalted:
+		_, err := e.Halt(event.Symbol)
+		return err
+	case EventTradingResumed:
+		return e.Resume(event.Symbol)
+	case EventOrderExpired:
+		return e.expireOrder(event.OrderID)
+	case EventDeleveraged:
+		if event.Deleverage == nil {
+			return fmt.Errorf("missing deleverage")
+		}
+		_, err := e.Deleverage(event.Symbol, *event.Deleverage)
+		return err
+	case EventAccountPurged:
+		_, err := e.PurgeAccount(event.AccountAlias, event.AccountAlias)
+		return err
+	default:
+		return fmt.Errorf("unknown event type: %q", event.Type)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// shardState copies every order and the trade counter of one symbol's shard
+func shardState(t *testing.T, engine *Engine, symbol string) (map[string]models.Order, uint64) {
+	t.Helper()
+	s, err := engine.shardFor(symbol)
+	if err != nil {
+		t.Fatalf("Expected shard for %s, got %v", symbol, err)
+	}
+	orders := make(map[string]models.Order)
+	var trades uint64
+	s.exec(func() {
+		for id, order := range s.orders {
+			orders[id] = *order
+		}
+		trades = s.nextTradeID
+	})
+	return orders, trades
+}
+
+func TestEngine_Replay(t *testing.T) {
+	t.Run("rebuilds_identical_state", func(t *testing.T) {
+		// Given: A recorded session with matching, amends, cancels, an auction and a halt
+		clock := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		engine := NewEngine()
+		engine.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+
+		engine.AddBook("BTC-USD", 100)
+		engine.StartAuction("BTC-USD", AuctionOpening)
+		engine.Submit(limitOrder("a", models.SideBuy, 2, 101))
+		engine.Submit(limitOrder("b", models.SideSell, 1, 100))
+		engine.Uncross("BTC-USD")
+		ask, _ := engine.Submit(limitOrder("c", models.SideSell, 3, 102))
+		engine.Amend(ask.Order.ID, AmendRequest{Price: 101})
+		engine.Submit(limitOrder("d", models.SideBuy, 1, 101))
+		bid, _ := engine.Submit(limitOrder("e", models.SideBuy, 1, 95))
+		engine.Cancel(bid.Order.ID)
+		engine.Halt("BTC-USD")
+		engine.Resume("BTC-USD")
+		engine.Submit(limitOrder("f", models.SideBuy, 1, 99))
+
+		// When: The log is replayed into a fresh engine
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Books, orders and sequence match the original
+		original, _ := engine.Snapshot("BTC-USD", 0)
+		rebuilt, _ := replayed.Snapshot("BTC-USD", 0)
+		if !reflect.DeepEqual(original, rebuilt) {
+			t.Errorf("Expected identical snapshots:\n%+v\n%+v", original, rebuilt)
+		}
+		if !reflect.DeepEqual(engine.OpenOrders("", ""), replayed.OpenOrders("", "")) {
+			t.Error("Expected identical open orders")
+		}
+		originalOrders, originalTrades := shardState(t, engine, "BTC-USD")
+		replayedOrders, replayedTrades := shardState(t, replayed, "BTC-USD")
+		if !reflect.DeepEqual(originalOrders, replayedOrders) {
+			t.Errorf("Orders diverged:\n%+v\n%+v", originalOrders, replayedOrders)
+		}
+		if replayed.Sequence() != engine.Sequence() || replayedTrades != originalTrades {
+			t.Errorf("Expected sequence %d and %d trades, got %d and %d",
+				engine.Sequence(), originalTrades, replayed.Sequence(), replayedTrades)
+		}
+	})
+
+	t.Run("does_not_record_rejected_commands", func(t *testing.T) {
+		engine := newTestEngine()
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+
+		engine.Cancel("ord-404")
+		engine.Resume("BTC-USD")
+
+		if len(log.Events()) != 0 {
+			t.Errorf("Expected no events, got %d", len(log.Events()))
+		}
+	})
+
+	t.Run("detects_sequence_gaps", func(t *testing.T) {
+		events := []Event{
+			{Sequence: 1, Type: EventBookAdded, Symbol: "BTC-USD", ReferencePrice: 100},
+			{Sequence: 3, Type: EventTradingHalted, Symbol: "BTC-USD"},
+		}
+
+		_, err := Replay(events)
+		if !errors.Is(err, ErrReplayDiverged) {
+			t.Errorf("Expected ErrReplayDiverged, got %v", err)
+		}
+	})
+
+	t.Run("detects_divergent_order_ids", func(t *testing.T) {
+		order := limitOrder("a", models.SideBuy, 1, 99)
+		events := []Event{
+			{Sequence: 1, Type: EventBookAdded, Symbol: "BTC-USD", ReferencePrice: 100},
+			{Sequence: 2, Type: EventOrderSubmitted, Symbol: "BTC-USD", OrderID: "ord-7", Order: &order},
+		}
+
+		_, err := Replay(events)
+		if !errors.Is(err, ErrReplayDiverged) {
+			t.Errorf("Expected ErrReplayDiverged, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package matching
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// EventOrderExpired records a GTD order reaching its expiry time
+const EventOrderExpired EventType = "order_expired"
+
+// SetClock makes the engine read venue time from clock; call it before trading starts
+func (e *Engine) SetClock(clock ports.Clock) {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+	e.now = clock.Now
+}
+
+// SetExpiryPrecision sets the width of the slots GTD expiries are kept in, rebuilding
+// every book's expiry wheel from the engine clock's current time, so call it after
+// SetClock. Expiries still happen at their exact times; coarser slots trade more work
+// per scheduler sweep for less per order.
+func (e *Engine) SetExpiryPrecision(precision time.Duration) {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	e.expiryPrecision = precision
+	for _, s := range e.shardList() {
+		s.exec(func() { s.expiring = s.expiring.Rescale(precision, e.now()) })
+	}
+}
+
+// ExpireOrders expires every GTD order whose expiry time has passed and returns them.
+// Books also sweep on their own before matching, so an expired order never trades
+// even between scheduler runs.
+func (e *Engine) ExpireOrders() ([]models.Order, error) {
+	expired := make([]models.Order, 0)
+	for _, s := range e.shardList() {
+		found, err := call(s, func() ([]models.Order, error) { return s.expireOrders(s.engine.now()) })
+		expired = append(expired, found...)
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// expireOrder applies a recorded expiry during replay
+func (e *Engine) expireOrder(orderID string) error {
+	s, err := e.shardForOrder(orderID)
+	if err != nil {
+		return err
+	}
+	_, err = call(s, func() (struct{}, error) {
+		order, exists := s.orders[orderID]
+		if !exists {
+			return struct{}{}, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+		}
+		if !order.Status.IsTerminal() {
+			s.expire(order, s.engine.now())
+		}
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// trackExpiry queues a resting GTD order by expiry time
+func (s *shard) trackExpiry(order *models.Order) {
+	if order.TimeInForce != models.TimeInForceGTD || order.ExpiresAt.IsZero() {
+		return
+	}
+	s.expiring.Schedule(order.ExpiresAt, order)
+}
+
+// expireOrders turns the expiry wheel to now and expires the orders that came due;
+// orders that ended another way are skipped. If an expiry cannot be recorded, it and
+// the orders due after it are queued again.
+func (s *shard) expireOrders(now time.Time) ([]models.Order, error) {
+	expired := make([]models.Order, 0)
+	due := s.expiring.Advance(now)
+	for i, order := range due {
+		if order.Status.IsTerminal() {
+			continue
+		}
+		if err := s.engine.record(Event{Type: EventOrderExpired, Time: now, Symbol: order.Symbol, OrderID: order.ID}); err != nil {
+			for _, retry := range due[i:] {
+				s.expiring.Schedule(retry.ExpiresAt, retry)
+			}
+			return expired, err
+		}
+		s.expire(order, now)
+		expired = append(expired, *order)
+	}
+	return expired, nil
+}
+
+func (s *shard) expire(order *models.Order, now time.Time) {
+	s.book.sideOf(order.Side).remove(order, restingPrice(order))
+	order.Status = models.OrderStatusExpired
+	order.UpdatedAt = now
+}
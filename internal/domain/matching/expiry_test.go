@@ -0,0 +1,118 @@
+//go:build unit
+
+package matching
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	return c.now
+}
+
+func gtdOrder(account string, side models.Side, quantity, price float64, expiresAt time.Time) models.Order {
+	order := limitOrder(account, side, quantity, price)
+	order.TimeInForce = models.TimeInForceGTD
+	order.ExpiresAt = expiresAt
+	return order
+}
+
+func TestEngine_ExpireOrders(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("expires_gtd_orders_once_the_clock_passes_their_expiry", func(t *testing.T) {
+		// Given: A GTD bid expiring in a minute on an engine driven by a stepped clock
+		clock := &stepClock{now: start}
+		engine := newTestEngine()
+		engine.SetClock(clock)
+		report, _ := engine.Submit(gtdOrder("a", models.SideBuy, 1, 99, start.Add(time.Minute)))
+
+		// When: The sweep runs before and after the expiry
+		early, _ := engine.ExpireOrders()
+		clock.now = start.Add(time.Minute)
+		late, _ := engine.ExpireOrders()
+
+		// Then: It works until its expiry time, then leaves the book as expired
+		if len(early) != 0 {
+			t.Errorf("Expected nothing to expire early, got %d", len(early))
+		}
+		if len(late) != 1 || late[0].ID != report.Order.ID || late[0].Status != models.OrderStatusExpired {
+			t.Fatalf("Expected the GTD order to expire, got %+v", late)
+		}
+		if snapshot, _ := engine.Snapshot("BTC-USD", 0); len(snapshot.Bids) != 0 {
+			t.Errorf("Expected an empty bid side, got %+v", snapshot.Bids)
+		}
+	})
+
+	t.Run("expired_orders_never_trade_between_sweeps", func(t *testing.T) {
+		// Given: A GTD ask whose expiry passed without a sweep
+		clock := &stepClock{now: start}
+		engine := newTestEngine()
+		engine.SetClock(clock)
+		engine.Submit(gtdOrder("a", models.SideSell, 1, 100, start.Add(time.Second)))
+		clock.now = start.Add(time.Hour)
+
+		// When: A crossing bid arrives
+		report, err := engine.Submit(limitOrder("b", models.SideBuy, 1, 100))
+
+		// Then: It rests instead of trading with the expired ask
+		if err != nil || len(report.Trades) != 0 {
+			t.Errorf("Expected no trades, got %v (%v)", report, err)
+		}
+	})
+
+	t.Run("expires_at_the_exact_time_after_the_precision_is_coarsened", func(t *testing.T) {
+		// Given: Two GTD bids a second apart, then the expiry slots widened to a minute
+		clock := &stepClock{now: start}
+		engine := NewEngine()
+		engine.SetClock(clock)
+		engine.AddBook("BTC-USD", 100)
+		first, _ := engine.Submit(gtdOrder("a", models.SideBuy, 1, 99, start.Add(10*time.Second)))
+		engine.Submit(gtdOrder("a", models.SideBuy, 1, 98, start.Add(11*time.Second)))
+		engine.SetExpiryPrecision(time.Minute)
+
+		// When: The sweep runs between the two expiries
+		clock.now = start.Add(10500 * time.Millisecond)
+		expired, _ := engine.ExpireOrders()
+
+		// Then: Only the first has expired, though both share a slot
+		if len(expired) != 1 || expired[0].ID != first.Order.ID {
+			t.Errorf("Expected only the first bid to expire, got %+v", expired)
+		}
+	})
+
+	t.Run("replay_reproduces_expiries", func(t *testing.T) {
+		// Given: A recorded session where a GTD order expired
+		clock := &stepClock{now: start}
+		engine := NewEngine()
+		engine.SetClock(clock)
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		engine.Submit(gtdOrder("a", models.SideBuy, 1, 99, start.Add(time.Minute)))
+		engine.Submit(limitOrder("b", models.SideBuy, 1, 98))
+		clock.now = start.Add(2 * time.Minute)
+		engine.ExpireOrders()
+
+		// When: The log is replayed
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The expired order is expired in the rebuilt engine too
+		originalOrders, _ := shardState(t, engine, "BTC-USD")
+		replayedOrders, _ := shardState(t, replayed, "BTC-USD")
+		if !reflect.DeepEqual(originalOrders, replayedOrders) {
+			t.Errorf("Orders diverged:\n%+v\n%+v", originalOrders, replayedOrders)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package matching
+
+import (
+	"fmt"
+	"time"
+)
+
+// PhaseHalted pauses all matching for a book until it is resumed
+const PhaseHalted Phase = "halted"
+
+// CircuitBreakerConfig defines when a volatility halt is triggered
+type CircuitBreakerConfig struct {
+	Enabled          bool          `json:"enabled"`
+	ThresholdPercent float64       `json:"threshold_percent"` // Max move within Window, e.g. 10 = 10%
+	Window           time.Duration `json:"window"`
+	Cooldown         time.Duration `json:"cooldown"` // Zero means manual resume only
+}
+
+// DefaultCircuitBreakerConfig halts on a 10% move within 5 minutes for 2 minutes
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Enabled:          true,
+		ThresholdPercent: 10,
+		Window:           5 * time.Minute,
+		Cooldown:         2 * time.Minute,
+	}
+}
+
+// HaltReason explains why a book was halted
+type HaltReason string
+
+const (
+	HaltReasonVolatility HaltReason = "volatility"
+	HaltReasonManual     HaltReason = "manual"
+)
+
+// HaltInfo describes an active trading halt
+type HaltInfo struct {
+	Symbol         string     `json:"symbol"`
+	Reason         HaltReason `json:"reason"`
+	TriggerPrice   float64    `json:"trigger_price,omitempty"`
+	ReferencePrice float64    `json:"reference_price,omitempty"`
+	MovePercent    float64    `json:"move_percent,omitempty"`
+	HaltedAt       time.Time  `json:"halted_at"`
+	ResumesAt      time.Time  `json:"resumes_at,omitempty"` // Zero when manual resume is required
+}
+
+type pricePoint struct {
+	price float64
+	at    time.Time
+}
+
+// circuitBreaker tracks recent trade prices for one book
+type circuitBreaker struct {
+	config  CircuitBreakerConfig
+	history []pricePoint
+	halt    *HaltInfo
+}
+
+// check returns the reference price and move if trading at price would breach the threshold
+func (b *circuitBreaker) check(price float64, now time.Time) (float64, float64, bool) {
+	if !b.config.Enabled || b.config.ThresholdPercent <= 0 {
+		return 0, 0, false
+	}
+	b.trim(now)
+
+	for _, point := range b.history {
+		move := (price - point.price) / point.price * 100
+		if move > b.config.ThresholdPercent || -move > b.config.ThresholdPercent {
+			return point.price, move, true
+		}
+	}
+	return 0, 0, false
+}
+
+// wouldTrip is check without trimming the price history
+func (b *circuitBreaker) wouldTrip(price float64, now time.Time) bool {
+	if !b.config.Enabled || b.config.ThresholdPercent <= 0 {
+		return false
+	}
+	cutoff := now.Add(-b.config.Window)
+	for _, point := range b.history {
+		if point.at.Before(cutoff) {
+			continue
+		}
+		move := (price - point.price) / point.price * 100
+		if move > b.config.ThresholdPercent || -move > b.config.ThresholdPercent {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *circuitBreaker) record(price float64, now time.Time) {
+	b.trim(now)
+	b.history = append(b.history, pricePoint{price: price, at: now})
+}
+
+func (b *circuitBreaker) trim(now time.Time) {
+	cutoff := now.Add(-b.config.Window)
+	idx := 0
+	for idx < len(b.history) && b.history[idx].at.Before(cutoff) {
+		idx++
+	}
+	b.history = b.history[idx:]
+}
+
+// SetCircuitBreaker configures the volatility halt rules for a symbol
+func (e *Engine) SetCircuitBreaker(symbol string, config CircuitBreakerConfig) error {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return err
+	}
+	_, err = call(s, func() (struct{}, error) {
+		if err := e.record(Event{Type: EventCircuitBreakerSet, Time: e.now(), Symbol: symbol, CircuitBreaker: &config}); err != nil {
+			return struct{}{}, err
+		}
+		s.book.breaker.config = config
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// CircuitBreaker returns the volatility halt rules in force for a symbol
+func (e *Engine) CircuitBreaker(symbol string) (CircuitBreakerConfig, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return CircuitBreakerConfig{}, err
+	}
+	return call(s, func() (CircuitBreakerConfig, error) { return s.book.breaker.config, nil })
+}
+
+// Halt manually pauses matching for a symbol until Resume is called
+func (e *Engine) Halt(symbol string) (HaltInfo, error) {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return HaltInfo{}, err
+	}
+	return call(s, s.halt)
+}
+
+func (s *shard) halt() (HaltInfo, error) {
+	book, symbol := s.book, s.book.symbol
+	now := s.engine.now()
+	s.expireHalt(now)
+	if book.phase == PhaseHalted {
+		return HaltInfo{}, fmt.Errorf("%w: %s is already halted", ErrInvalidPhase, symbol)
+	}
+	if book.phase != PhaseContinuous {
+		return HaltInfo{}, fmt.Errorf("%w: cannot halt %s during %s", ErrInvalidPhase, symbol, book.phase)
+	}
+
+	if err := s.engine.record(Event{Type: EventTradingHalted, Time: now, Symbol: symbol}); err != nil {
+		return HaltInfo{}, err
+	}
+	info := HaltInfo{Symbol: symbol, Reason: HaltReasonManual, HaltedAt: now}
+	book.halt(info)
+	return info, nil
+}
+
+// Resume ends a halt and returns the book to continuous trading
+func (e *Engine) Resume(symbol string) error {
+	s, err := e.shardFor(symbol)
+	if err != nil {
+		return err
+	}
+	_, err = call(s, func() (struct{}, error) { return struct{}{}, s.resume() })
+	return err
+}
+
+func (s *shard) resume() error {
+	book, symbol := s.book, s.book.symbol
+	now := s.engine.now()
+	s.expireHalt(now)
+	if book.phase != PhaseHalted {
+		return fmt.Errorf("%w: %s is not halted", ErrInvalidPhase, symbol)
+	}
+	if err := s.engine.record(Event{Type: EventTradingResumed, Time: now, Symbol: symbol}); err != nil {
+		return err
+	}
+	book.resume()
+	return nil
+}
+
+// Halts returns all active halts sorted by symbol
+func (e *Engine) Halts() []HaltInfo {
+	halts := make([]HaltInfo, 0)
+	for _, s := range e.shardList() {
+		info, _ := call(s, func() (*HaltInfo, error) {
+			s.expireHalt(s.engine.now())
+			return s.book.breaker.halt, nil
+		})
+		if info != nil {
+			halts = append(halts, *info)
+		}
+	}
+	return halts
+}
+
+func (b *OrderBook) halt(info HaltInfo) {
+	b.phase = PhaseHalted
+	b.breaker.halt = &info
+}
+
+func (b *OrderBook) resume() {
+	b.phase = PhaseContinuous
+	b.breaker.halt = nil
+	// Start a fresh window so the pre-halt price does not immediately re-trip
+	b.breaker.history = nil
+}
+
+// expireHalt resumes a volatility halt once its cooldown has elapsed
+func (s *shard) expireHalt(now time.Time) {
+	halt := s.book.breaker.halt
+	if halt == nil || halt.ResumesAt.IsZero() || now.Before(halt.ResumesAt) {
+		return
+	}
+	s.book.resume()
+}
+
+// tripBreaker halts a book after a price move beyond the configured threshold
+func (s *shard) tripBreaker(price, reference, move float64, now time.Time) {
+	book := s.book
+	info := HaltInfo{
+		Symbol:         book.symbol,
+		Reason:         HaltReasonVolatility,
+		TriggerPrice:   price,
+		ReferencePrice: reference,
+		MovePercent:    move,
+		HaltedAt:       now,
+	}
+	if book.breaker.config.Cooldown > 0 {
+		info.ResumesAt = now.Add(book.breaker.config.Cooldown)
+	}
+	book.halt(info)
+}
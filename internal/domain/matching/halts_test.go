@@ -0,0 +1,133 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// newBreakerEngine returns an engine with a 5% / 1m breaker and a controllable clock
+func newBreakerEngine(cooldown time.Duration) (*Engine, *time.Time) {
+	current := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	engine := NewEngine()
+	engine.now = func() time.Time { return current }
+	engine.SetDefaultCircuitBreaker(CircuitBreakerConfig{
+		Enabled:          true,
+		ThresholdPercent: 5,
+		Window:           time.Minute,
+		Cooldown:         cooldown,
+	})
+	engine.AddBook("BTC-USD", 100)
+	return engine, &current
+}
+
+func TestEngine_CircuitBreaker(t *testing.T) {
+	t.Run("halts_before_trading_beyond_threshold", func(t *testing.T) {
+		// Given: A trade at 100 establishes the window
+		engine, _ := newBreakerEngine(time.Minute)
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 100))
+
+		// And: The only remaining ask is 10% higher
+		engine.Submit(limitOrder("m", models.SideSell, 1, 110))
+
+		// When: A buy would lift the 110 offer
+		report, err := engine.Submit(limitOrder("t", models.SideBuy, 1, 110))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: No trade prints and the book is halted
+		if len(report.Trades) != 0 {
+			t.Errorf("Expected no trade beyond the band, got %d", len(report.Trades))
+		}
+		phase, _ := engine.Phase("BTC-USD")
+		if phase != PhaseHalted {
+			t.Errorf("Expected halted phase, got %s", phase)
+		}
+
+		halts := engine.Halts()
+		if len(halts) != 1 || halts[0].Reason != HaltReasonVolatility || halts[0].ReferencePrice != 100 {
+			t.Errorf("Expected volatility halt referencing 100, got %+v", halts)
+		}
+
+		// And: New orders are rejected while halted
+		if _, err := engine.Submit(limitOrder("t", models.SideBuy, 1, 100)); !errors.Is(err, ErrHalted) {
+			t.Errorf("Expected ErrHalted, got %v", err)
+		}
+	})
+
+	t.Run("resumes_automatically_after_cooldown", func(t *testing.T) {
+		engine, current := newBreakerEngine(time.Minute)
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 100))
+		engine.Submit(limitOrder("m", models.SideSell, 1, 110))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 110))
+
+		// When: The cooldown elapses
+		*current = current.Add(61 * time.Second)
+
+		// Then: The book is back in continuous trading and the resting order can trade
+		phase, _ := engine.Phase("BTC-USD")
+		if phase != PhaseContinuous {
+			t.Fatalf("Expected continuous after cooldown, got %s", phase)
+		}
+		report, err := engine.Submit(limitOrder("t2", models.SideSell, 1, 110))
+		if err != nil {
+			t.Fatalf("Expected no error after resume, got %v", err)
+		}
+		if len(report.Trades) != 1 {
+			t.Errorf("Expected the resting 110 bid to trade after resume, got %d trades", len(report.Trades))
+		}
+	})
+
+	t.Run("manual_halt_requires_manual_resume", func(t *testing.T) {
+		engine, current := newBreakerEngine(time.Minute)
+
+		if _, err := engine.Halt("BTC-USD"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		*current = current.Add(time.Hour)
+
+		if phase, _ := engine.Phase("BTC-USD"); phase != PhaseHalted {
+			t.Errorf("Expected manual halt to persist, got %s", phase)
+		}
+		if err := engine.Resume("BTC-USD"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if phase, _ := engine.Phase("BTC-USD"); phase != PhaseContinuous {
+			t.Errorf("Expected continuous after resume, got %s", phase)
+		}
+	})
+
+	t.Run("moves_outside_the_window_do_not_trip", func(t *testing.T) {
+		engine, current := newBreakerEngine(time.Minute)
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 100))
+
+		*current = current.Add(2 * time.Minute)
+		engine.Submit(limitOrder("m", models.SideSell, 1, 110))
+		report, _ := engine.Submit(limitOrder("t", models.SideBuy, 1, 110))
+
+		if len(report.Trades) != 1 {
+			t.Errorf("Expected trade once old price left the window, got %d", len(report.Trades))
+		}
+	})
+
+	t.Run("disabled_breaker_never_halts", func(t *testing.T) {
+		engine, _ := newBreakerEngine(time.Minute)
+		engine.SetCircuitBreaker("BTC-USD", CircuitBreakerConfig{Enabled: false})
+		engine.Submit(limitOrder("m", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("t", models.SideBuy, 1, 100))
+		engine.Submit(limitOrder("m", models.SideSell, 1, 200))
+		report, _ := engine.Submit(limitOrder("t", models.SideBuy, 1, 200))
+
+		if len(report.Trades) != 1 {
+			t.Errorf("Expected trade with breaker disabled, got %d", len(report.Trades))
+		}
+	})
+}
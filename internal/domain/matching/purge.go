@@ -0,0 +1,118 @@
+package matching
+
+import (
+	"errors"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var ErrInvalidPurge = errors.New("invalid account purge")
+
+// EventAccountPurged is the tombstone left in the event log by an account purge.
+// It carries only the alias, never the erased account ID.
+const EventAccountPurged EventType = "account_purged"
+
+// Tombstone records that an account's data was erased and under which alias
+type Tombstone struct {
+	Alias            string    `json:"alias"`
+	PurgedAt         time.Time `json:"purged_at"`
+	OrdersCanceled   int       `json:"orders_canceled"`
+	OrdersAnonymized int       `json:"orders_anonymized"`
+}
+
+// EventRedactor is implemented by event logs that can rewrite recorded events,
+// which erasure needs because the log is otherwise append-only
+type EventRedactor interface {
+	Redact(rewrite func(Event) (Event, bool)) (int, error)
+}
+
+// AnonymizeAccount returns a rewrite for EventRedactor that replaces accountID with alias
+func AnonymizeAccount(accountID, alias string) func(Event) (Event, bool) {
+	return func(event Event) (Event, bool) {
+		if event.Deleverage != nil && (event.Deleverage.AccountID == accountID || event.Deleverage.Counterparty == accountID) {
+			deleverage := *event.Deleverage
+			if deleverage.AccountID == accountID {
+				deleverage.AccountID = alias
+			}
+			if deleverage.Counterparty == accountID {
+				deleverage.Counterparty = alias
+			}
+			event.Deleverage = &deleverage
+			return event, true
+		}
+		if event.Order == nil || event.Order.AccountID != accountID {
+			return event, false
+		}
+		order := *event.Order
+		order.AccountID = alias
+		event.Order = &order
+		return event, true
+	}
+}
+
+// PurgeAccount cancels the account's working orders and replaces its ID with alias
+// on every order and trade the engine holds. Only the alias is recorded, so once the
+// event log is redacted with AnonymizeAccount, replay reaches the same state.
+func (e *Engine) PurgeAccount(accountID, alias string) (Tombstone, error) {
+	if accountID == "" || alias == "" {
+		return Tombstone{}, ErrInvalidPurge
+	}
+
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	now := e.now()
+	if err := e.record(Event{Type: EventAccountPurged, Time: now, AccountAlias: alias}); err != nil {
+		return Tombstone{}, err
+	}
+
+	tombstone := Tombstone{Alias: alias, PurgedAt: now}
+	for _, s := range e.shardList() {
+		var canceled, anonymized int
+		if err := s.exec(func() { canceled, anonymized = s.purgeAccount(accountID, alias, now) }); err != nil {
+			return tombstone, err
+		}
+		tombstone.OrdersCanceled += canceled
+		tombstone.OrdersAnonymized += anonymized
+	}
+	e.clientOrders.forget(accountID)
+	e.tombstones = append(e.tombstones, tombstone)
+	return tombstone, nil
+}
+
+// Tombstones returns every account purge in the order it happened
+func (e *Engine) Tombstones() []Tombstone {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+	return append(make([]Tombstone, 0, len(e.tombstones)), e.tombstones...)
+}
+
+// purgeAccount matches the alias as well, so replaying a redacted log cancels the same orders
+func (s *shard) purgeAccount(accountID, alias string, now time.Time) (int, int) {
+	canceled, anonymized := 0, 0
+	for _, order := range s.orders {
+		if order.AccountID != accountID && order.AccountID != alias {
+			continue
+		}
+		if order.AccountID == accountID && accountID != alias {
+			order.AccountID = alias
+			anonymized++
+		}
+		if !order.Status.IsTerminal() {
+			s.book.sideOf(order.Side).remove(order, restingPrice(order))
+			order.Status = models.OrderStatusCanceled
+			order.UpdatedAt = now
+			canceled++
+		}
+	}
+	for i := range s.trades {
+		if s.trades[i].BuyAccountID == accountID {
+			s.trades[i].BuyAccountID = alias
+		}
+		if s.trades[i].SellAccountID == accountID {
+			s.trades[i].SellAccountID = alias
+		}
+	}
+	return canceled, anonymized
+}
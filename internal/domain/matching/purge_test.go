@@ -0,0 +1,100 @@
+//go:build unit
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_PurgeAccount(t *testing.T) {
+	t.Run("cancels_working_orders_and_anonymizes_history", func(t *testing.T) {
+		// Given: An account with one filled and one resting order
+		engine := newTestEngine()
+		engine.Submit(limitOrder("maker", models.SideSell, 1, 100))
+		filled, _ := engine.Submit(limitOrder("erased", models.SideBuy, 1, 100))
+		resting, _ := engine.Submit(limitOrder("erased", models.SideBuy, 2, 99))
+		other, _ := engine.Submit(limitOrder("kept", models.SideBuy, 1, 98))
+
+		// When: The account is purged
+		tombstone, err := engine.PurgeAccount("erased", "anon-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both orders carry the alias and only the resting one was canceled
+		if tombstone.OrdersAnonymized != 2 || tombstone.OrdersCanceled != 1 {
+			t.Errorf("Unexpected tombstone: %+v", tombstone)
+		}
+		for _, id := range []string{filled.Order.ID, resting.Order.ID} {
+			order, _ := engine.GetOrder(id)
+			if order.AccountID != "anon-1" {
+				t.Errorf("Expected %s to be anonymized, got account %q", id, order.AccountID)
+			}
+		}
+		if order, _ := engine.GetOrder(resting.Order.ID); order.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected resting order to be canceled, got %s", order.Status)
+		}
+		if len(engine.OpenOrders("erased", "")) != 0 {
+			t.Error("Expected no open orders left under the erased account")
+		}
+		snapshot, _ := engine.Snapshot("BTC-USD", 0)
+		if len(snapshot.Bids) != 1 || snapshot.Bids[0].Price != 98 {
+			t.Errorf("Expected only the other account's bid to rest, got %+v", snapshot.Bids)
+		}
+		if order, _ := engine.GetOrder(other.Order.ID); order.AccountID != "kept" {
+			t.Errorf("Expected other accounts untouched, got %q", order.AccountID)
+		}
+	})
+
+	t.Run("replays_a_redacted_log_to_the_same_state", func(t *testing.T) {
+		// Given: A recorded session ending in a purge
+		log := NewMemoryEventLog()
+		engine := NewEngine()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		resting, _ := engine.Submit(limitOrder("erased", models.SideBuy, 1, 99))
+		engine.PurgeAccount("erased", "anon-1")
+
+		// When: The log is redacted and replayed
+		redacted, err := log.Redact(AnonymizeAccount("erased", "anon-1"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected replay to succeed, got %v", err)
+		}
+
+		// Then: No event mentions the account and the replayed order is canceled under the alias
+		if redacted != 1 {
+			t.Errorf("Expected 1 redacted event, got %d", redacted)
+		}
+		for _, event := range log.Events() {
+			if event.Order != nil && event.Order.AccountID == "erased" {
+				t.Errorf("Event %d still holds the erased account", event.Sequence)
+			}
+		}
+		order, _ := replayed.GetOrder(resting.Order.ID)
+		if order.AccountID != "anon-1" || order.Status != models.OrderStatusCanceled {
+			t.Errorf("Unexpected replayed order: %+v", order)
+		}
+		if tombstones := replayed.Tombstones(); len(tombstones) != 1 || tombstones[0].Alias != "anon-1" {
+			t.Errorf("Expected the tombstone to be replayed, got %+v", tombstones)
+		}
+	})
+
+	t.Run("rejects_empty_account", func(t *testing.T) {
+		// Given: An engine
+		engine := newTestEngine()
+
+		// When: An empty account is purged
+		_, err := engine.PurgeAccount("", "anon-1")
+
+		// Then: The purge is rejected
+		if err != ErrInvalidPurge {
+			t.Errorf("Expected ErrInvalidPurge, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package matching
+
+import "sync/atomic"
+
+// Command states; a queued command is claimed once, to be run or rejected
+const (
+	commandQueued int32 = iota
+	commandRunning
+	commandRejected
+)
+
+// command is one unit of work executed on a shard's goroutine
+type command struct {
+	fn    func()
+	done  chan struct{} // Closed once fn has run, or the command was rejected
+	err   error         // ErrEngineClosed when rejected; read after done
+	state atomic.Int32
+	next  atomic.Pointer[command]
+}
+
+// claim moves a queued command to state, reporting whether it was still queued
+func (c *command) claim(state int32) bool {
+	return c.state.CompareAndSwap(commandQueued, state)
+}
+
+// commandQueue is a lock-free multi-producer, single-consumer queue (Vyukov's
+// intrusive MPSC design). Producers only swap the head pointer; the shard
+// goroutine is the sole consumer and owns the tail.
+type commandQueue struct {
+	head  atomic.Pointer[command]
+	tail  *command
+	stub  command
+	wake  chan struct{}
+	depth atomic.Int64 // Commands pushed and not yet popped
+}
+
+func newCommandQueue() *commandQueue {
+	q := &commandQueue{wake: make(chan struct{}, 1)}
+	q.head.Store(&q.stub)
+	q.tail = &q.stub
+	return q
+}
+
+// push enqueues cmd and wakes the consumer if it is idle
+func (q *commandQueue) push(cmd *command) {
+	q.depth.Add(1)
+	q.enqueue(cmd)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *commandQueue) enqueue(cmd *command) {
+	cmd.next.Store(nil)
+	prev := q.head.Swap(cmd)
+	prev.next.Store(cmd)
+}
+
+// pop returns the oldest command, or nil when the queue is empty or a producer is
+// between swapping the head and linking its command; that producer wakes us after
+func (q *commandQueue) pop() *command {
+	tail := q.tail
+	next := tail.next.Load()
+	if tail == &q.stub {
+		if next == nil {
+			return nil
+		}
+		q.tail = next
+		tail = next
+		next = next.next.Load()
+	}
+	if next != nil {
+		q.tail = next
+		q.depth.Add(-1)
+		return tail
+	}
+	if tail != q.head.Load() {
+		return nil
+	}
+
+	// tail is the last command: re-insert the stub so tail can be handed out
+	q.enqueue(&q.stub)
+	next = tail.next.Load()
+	if next != nil {
+		q.tail = next
+		q.depth.Add(-1)
+		return tail
+	}
+	return nil
+}
+
+// len returns how many commands wait to be run
+func (q *commandQueue) len() int64 {
+	return q.depth.Load()
+}
@@ -0,0 +1,370 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/timerwheel"
+)
+
+const (
+	orderIDPrefix = "ord-"
+	tradeIDPrefix = "trd-"
+
+	// idleSpins is how many times an idle shard yields before parking, which keeps
+	// request/response round trips off the scheduler's slow wake-up path under load
+	idleSpins = 64
+)
+
+// shard owns one order book. All of its state is touched only by its goroutine,
+// which drains the inbound command queue in arrival order.
+type shard struct {
+	engine *Engine
+	book   *OrderBook
+	orders map[string]*models.Order
+
+	nextOrderID uint64
+	nextTradeID uint64
+	expiring    *timerwheel.Wheel[*models.Order] // Resting GTD orders by expiry time
+	trades      []models.Trade                   // Recent executions, oldest first
+
+	queue   *commandQueue
+	stop    chan struct{}
+	stopped bool
+}
+
+func newShard(engine *Engine, book *OrderBook) *shard {
+	s := &shard{
+		engine:   engine,
+		book:     book,
+		orders:   make(map[string]*models.Order),
+		expiring: timerwheel.New[*models.Order](engine.expiryPrecision, engine.now()),
+		queue:    newCommandQueue(),
+		stop:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *shard) run() {
+	for {
+		for spins := 0; spins < idleSpins; spins++ {
+			for {
+				select {
+				case <-s.stop:
+					s.rejectQueued()
+					return
+				default:
+				}
+				cmd := s.queue.pop()
+				if cmd == nil {
+					break
+				}
+				if cmd.claim(commandRunning) {
+					cmd.fn()
+					close(cmd.done)
+				}
+				spins = 0
+			}
+			runtime.Gosched()
+		}
+		select {
+		case <-s.queue.wake:
+		case <-s.stop:
+			s.rejectQueued()
+			return
+		}
+	}
+}
+
+// rejectQueued rejects the commands still queued when the shard stops, without
+// running them. One pushed later is rejected by the caller waiting on it.
+func (s *shard) rejectQueued() {
+	for cmd := s.queue.pop(); cmd != nil; cmd = s.queue.pop() {
+		if cmd.claim(commandRejected) {
+			cmd.err = ErrEngineClosed
+			close(cmd.done)
+		}
+	}
+}
+
+// close stops the goroutine; callers hold the engine registry lock
+func (s *shard) close() {
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+	}
+}
+
+// exec runs fn on the shard goroutine and waits for it to finish. Once the shard
+// stops, fn either has run or never will by the time exec returns.
+func (s *shard) exec(fn func()) error {
+	cmd := &command{fn: fn, done: make(chan struct{})}
+	s.queue.push(cmd)
+	return s.await(context.Background(), cmd)
+}
+
+// ping waits for the shard goroutine to run a command that does nothing; a stuck
+// shard runs it whenever it recovers, so nothing is left waiting on it
+func (s *shard) ping(ctx context.Context) error {
+	cmd := &command{fn: func() {}, done: make(chan struct{})}
+	s.queue.push(cmd)
+	return s.await(ctx, cmd)
+}
+
+// await waits until cmd has run or been rejected. When the shard stops first, the
+// caller rejects cmd itself unless the goroutine has already claimed it, in which
+// case it waits for the goroutine to finish with it. Giving up when ctx is done
+// leaves cmd queued, so it may still run.
+func (s *shard) await(ctx context.Context, cmd *command) error {
+	select {
+	case <-cmd.done:
+	case <-s.stop:
+		if cmd.claim(commandRejected) {
+			return ErrEngineClosed
+		}
+		<-cmd.done
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return cmd.err
+}
+
+// call runs fn on the shard goroutine and returns its result
+func call[T any](s *shard, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	if execErr := s.exec(func() { result, err = fn() }); execErr != nil {
+		return result, execErr
+	}
+	return result, err
+}
+
+func (s *shard) submit(order models.Order) (*ExecutionReport, error) {
+	book := s.book
+	now := s.engine.now()
+	s.expireHalt(now)
+	if _, err := s.expireOrders(now); err != nil {
+		return nil, err
+	}
+	if book.phase == PhaseClosed {
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, order.Symbol)
+	}
+	if book.phase == PhaseHalted {
+		return nil, fmt.Errorf("%w: %s", ErrHalted, order.Symbol)
+	}
+
+	id := OrderID(book.symbol, s.nextOrderID+1)
+	input := order
+	if err := s.engine.record(Event{
+		Type:    EventOrderSubmitted,
+		Time:    now,
+		Symbol:  order.Symbol,
+		OrderID: id,
+		Order:   &input,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.nextOrderID++
+	order.ID = id
+	order.FilledQuantity = 0
+	order.AveragePrice = 0
+	order.Status = models.OrderStatusNew
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if order.TimeInForce == "" {
+		order.TimeInForce = models.TimeInForceGTC
+	}
+
+	live := &order
+	s.orders[live.ID] = live
+
+	if live.TimeInForce == models.TimeInForceGTD && !live.ExpiresAt.IsZero() && !now.Before(live.ExpiresAt) {
+		live.Status = models.OrderStatusExpired
+		return &ExecutionReport{Order: *live}, nil
+	}
+
+	if book.phase == PhaseAuction {
+		// Call auction: collect orders without matching until the uncross
+		if !live.TimeInForce.Rests() {
+			live.Status = models.OrderStatusRejected
+			return &ExecutionReport{Order: *live}, nil
+		}
+		book.sideOf(live.Side).add(live, restingPrice(live))
+		s.trackExpiry(live)
+		return &ExecutionReport{Order: *live}, nil
+	}
+
+	if live.TimeInForce == models.TimeInForceFOK && s.availableQuantity(live) < live.Quantity-1e-12 {
+		live.Status = models.OrderStatusCanceled
+		return &ExecutionReport{Order: *live}, nil
+	}
+
+	trades := s.match(live, now)
+
+	if live.RemainingQuantity() > 0 {
+		// A halt mid-sweep leaves limit remainders resting like any other GTC or GTD order
+		if live.Type == models.OrderTypeMarket || !live.TimeInForce.Rests() {
+			live.Status = models.OrderStatusCanceled
+			live.UpdatedAt = now
+		} else {
+			book.sideOf(live.Side).add(live, live.Price)
+			s.trackExpiry(live)
+		}
+	}
+
+	return &ExecutionReport{Order: *live, Trades: trades}, nil
+}
+
+func (s *shard) cancel(orderID string) (models.Order, error) {
+	order, exists := s.orders[orderID]
+	if !exists {
+		return models.Order{}, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	now := s.engine.now()
+	if _, err := s.expireOrders(now); err != nil {
+		return *order, err
+	}
+	if order.Status.IsTerminal() {
+		return *order, fmt.Errorf("%w: %s is %s", ErrOrderNotActive, orderID, order.Status)
+	}
+
+	if err := s.engine.record(Event{Type: EventOrderCanceled, Time: now, Symbol: order.Symbol, OrderID: orderID}); err != nil {
+		return *order, err
+	}
+
+	s.book.sideOf(order.Side).remove(order, restingPrice(order))
+	order.Status = models.OrderStatusCanceled
+	order.UpdatedAt = now
+
+	return *order, nil
+}
+
+func (s *shard) getOrder(orderID string) (models.Order, error) {
+	order, exists := s.orders[orderID]
+	if !exists {
+		return models.Order{}, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	return *order, nil
+}
+
+func (s *shard) openOrders(accountID string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, order := range s.orders {
+		if order.Status.IsTerminal() {
+			continue
+		}
+		if accountID != "" && order.AccountID != accountID {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+func (s *shard) accountOrders(accountID string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, order := range s.orders {
+		if accountID != "" && order.AccountID != accountID {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+func (s *shard) filledOrders(accountID string) []models.Order {
+	orders := make([]models.Order, 0)
+	for _, order := range s.orders {
+		if order.FilledQuantity <= 0 {
+			continue
+		}
+		if accountID != "" && order.AccountID != accountID {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+// match executes an incoming order against the contra side while prices cross
+func (s *shard) match(incoming *models.Order, now time.Time) []models.Trade {
+	book := s.book
+	contra := book.sideOf(incoming.Side.Opposite())
+	var trades []models.Trade
+
+	for incoming.RemainingQuantity() > 0 {
+		best := contra.best()
+		if best == nil || !crosses(incoming, best.price) {
+			break
+		}
+		if reference, move, trip := book.breaker.check(best.price, now); trip {
+			s.tripBreaker(best.price, reference, move, now)
+			break
+		}
+
+		for len(best.orders) > 0 && incoming.RemainingQuantity() > 0 {
+			resting := best.orders[0]
+			quantity := math.Min(incoming.RemainingQuantity(), resting.RemainingQuantity())
+
+			trades = append(trades, s.execute(incoming, resting, best.price, quantity, now, false))
+
+			if resting.RemainingQuantity() == 0 {
+				best.orders = best.orders[1:]
+			}
+		}
+		contra.popBestIfEmpty()
+	}
+
+	return trades
+}
+
+// availableQuantity sums contra liquidity the order could trade against
+func (s *shard) availableQuantity(order *models.Order) float64 {
+	total := 0.0
+	for _, lvl := range s.book.sideOf(order.Side.Opposite()).levels {
+		if !crosses(order, lvl.price) {
+			break
+		}
+		total += lvl.quantity()
+	}
+	return total
+}
+
+// execute fills both orders and records the trade at price
+func (s *shard) execute(taker, maker *models.Order, price, quantity float64, now time.Time, auction bool) models.Trade {
+	book := s.book
+	taker.ApplyFill(quantity, price, now)
+	maker.ApplyFill(quantity, price, now)
+	book.lastPrice = price
+	book.breaker.record(price, now)
+
+	buy, sell := taker, maker
+	if taker.Side == models.SideSell {
+		buy, sell = maker, taker
+	}
+
+	s.nextTradeID++
+	trade := models.Trade{
+		ID:            fmt.Sprintf("%s%s-%d", tradeIDPrefix, book.symbol, s.nextTradeID),
+		Symbol:        book.symbol,
+		Price:         price,
+		Quantity:      quantity,
+		BuyOrderID:    buy.ID,
+		SellOrderID:   sell.ID,
+		BuyAccountID:  buy.AccountID,
+		SellAccountID: sell.AccountID,
+		Auction:       auction,
+		ExecutedAt:    now,
+	}
+	if !auction {
+		trade.TakerSide = taker.Side
+	}
+	s.recordTrade(trade)
+	return trade
+}
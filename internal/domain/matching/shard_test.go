@@ -0,0 +1,218 @@
+//go:build unit
+
+package matching
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestCommandQueue(t *testing.T) {
+	t.Run("delivers_every_command_in_per_producer_order", func(t *testing.T) {
+		// Given: A queue with several concurrent producers
+		queue := newCommandQueue()
+		const producers, perProducer = 8, 1000
+		seen := make([][]int, producers)
+
+		var wg sync.WaitGroup
+		for p := 0; p < producers; p++ {
+			wg.Add(1)
+			go func(p int) {
+				defer wg.Done()
+				for i := 0; i < perProducer; i++ {
+					i := i
+					queue.push(&command{fn: func() { seen[p] = append(seen[p], i) }})
+				}
+			}(p)
+		}
+
+		// When: A single consumer drains it until everything has arrived
+		done := make(chan struct{})
+		go func() { wg.Wait(); close(done) }()
+		received := 0
+		for received < producers*perProducer {
+			if cmd := queue.pop(); cmd != nil {
+				cmd.fn()
+				received++
+				continue
+			}
+			select {
+			case <-queue.wake:
+			case <-done:
+			}
+		}
+
+		// Then: Each producer's commands arrived exactly once and in order
+		for p, values := range seen {
+			if len(values) != perProducer {
+				t.Fatalf("Producer %d: expected %d commands, got %d", p, perProducer, len(values))
+			}
+			for i, value := range values {
+				if value != i {
+					t.Fatalf("Producer %d: command %d arrived out of order", p, i)
+				}
+			}
+		}
+	})
+}
+
+func TestEngine_Shards(t *testing.T) {
+	t.Run("symbols_match_concurrently", func(t *testing.T) {
+		// Given: Many listed symbols
+		engine := NewEngine()
+		defer engine.Close()
+		symbols := make([]string, 32)
+		for i := range symbols {
+			symbols[i] = fmt.Sprintf("SYM%d-USD", i)
+			engine.AddBook(symbols[i], 100)
+		}
+
+		// When: Each symbol is traded from its own goroutine
+		var wg sync.WaitGroup
+		for _, symbol := range symbols {
+			wg.Add(1)
+			go func(symbol string) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					ask := limitOrder("maker", models.SideSell, 1, 100)
+					ask.Symbol = symbol
+					engine.Submit(ask)
+					bid := limitOrder("taker", models.SideBuy, 1, 100)
+					bid.Symbol = symbol
+					engine.Submit(bid)
+				}
+			}(symbol)
+		}
+		wg.Wait()
+
+		// Then: Every book fully traded and nothing is left resting
+		for _, symbol := range symbols {
+			if orders := engine.OpenOrders("", symbol); len(orders) != 0 {
+				t.Errorf("%s: expected empty book, got %d open orders", symbol, len(orders))
+			}
+			if price, _ := engine.LastPrice(symbol); price != 100 {
+				t.Errorf("%s: expected last price 100, got %v", symbol, price)
+			}
+		}
+	})
+
+	t.Run("order_ids_route_to_their_symbol", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+		engine.AddBook("BTC-USD", 100)
+		engine.AddBook("ETH-USD", 10)
+
+		order := limitOrder("a", models.SideBuy, 1, 9)
+		order.Symbol = "ETH-USD"
+		report, _ := engine.Submit(order)
+
+		found, err := engine.GetOrder(report.Order.ID)
+		if err != nil || found.Symbol != "ETH-USD" {
+			t.Errorf("Expected ETH-USD order, got %+v (err %v)", found, err)
+		}
+		if _, err := engine.GetOrder("ord-DOGE-USD-1"); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("Expected ErrOrderNotFound for unknown symbol, got %v", err)
+		}
+		if _, err := engine.Cancel("garbage"); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("Expected ErrOrderNotFound for malformed id, got %v", err)
+		}
+	})
+
+	t.Run("closed_engine_rejects_commands", func(t *testing.T) {
+		engine := newTestEngine()
+		engine.Close()
+
+		if _, err := engine.Submit(limitOrder("a", models.SideBuy, 1, 99)); !errors.Is(err, ErrEngineClosed) {
+			t.Errorf("Expected ErrEngineClosed, got %v", err)
+		}
+	})
+
+	t.Run("closing_rejects_queued_commands_without_running_them", func(t *testing.T) {
+		// Given: A book busy with one command and an order queued behind it
+		engine := newTestEngine()
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+		recorded := len(log.Events())
+		s := engine.shardList()[0]
+		busy, release := make(chan struct{}), make(chan struct{})
+		go s.exec(func() { close(busy); <-release })
+		<-busy
+		submitted := make(chan error, 1)
+		go func() {
+			_, err := engine.Submit(limitOrder("a", models.SideBuy, 1, 99))
+			submitted <- err
+		}()
+		for s.queue.len() == 0 {
+			runtime.Gosched()
+		}
+
+		// When: The engine closes before the book gets to the order
+		engine.Close()
+		close(release)
+
+		// Then: The order is refused and never reaches the book or the journal
+		if err := <-submitted; !errors.Is(err, ErrEngineClosed) {
+			t.Errorf("Expected ErrEngineClosed, got %v", err)
+		}
+		if events := log.Events(); len(events) != recorded {
+			t.Errorf("Expected nothing journaled after the close, got %+v", events[recorded:])
+		}
+	})
+}
+
+func TestEngine_Ping(t *testing.T) {
+	t.Run("answers_while_every_book_takes_commands", func(t *testing.T) {
+		// Given: An engine with listed books
+		engine := newTestEngine()
+		defer engine.Close()
+
+		// When/Then: It is pinged
+		if err := engine.Ping(context.Background()); err != nil {
+			t.Errorf("Expected a live engine, got %v", err)
+		}
+	})
+
+	t.Run("fails_when_a_book_is_stuck", func(t *testing.T) {
+		// Given: A book whose goroutine is blocked
+		engine := newTestEngine()
+		defer engine.Close()
+		release := make(chan struct{})
+		defer close(release)
+		s, _ := engine.shardFor("BTC-USD")
+		blocked := make(chan struct{})
+		go s.exec(func() {
+			close(blocked)
+			<-release
+		})
+		<-blocked
+
+		// When: It is pinged with a deadline
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := engine.Ping(ctx)
+
+		// Then: The stuck book is named
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "BTC-USD") {
+			t.Errorf("Expected the stuck book to time out, got %v", err)
+		}
+	})
+
+	t.Run("fails_once_closed", func(t *testing.T) {
+		// Given: A closed engine
+		engine := newTestEngine()
+		engine.Close()
+
+		// When/Then: It is pinged
+		if err := engine.Ping(context.Background()); !errors.Is(err, ErrEngineClosed) {
+			t.Errorf("Expected ErrEngineClosed, got %v", err)
+		}
+	})
+}
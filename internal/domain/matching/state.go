@@ -0,0 +1,224 @@
+package matching
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// EngineState is the whole engine at one event sequence: every book with its
+// orders, queues, breaker and trade history. Restoring it and replaying the events
+// recorded after Sequence rebuilds the engine without replaying the whole log.
+type EngineState struct {
+	Sequence       uint64               `json:"sequence"` // Last event the state includes
+	TakenAt        time.Time            `json:"taken_at"`
+	DefaultBreaker CircuitBreakerConfig `json:"default_circuit_breaker"`
+	Tombstones     []Tombstone          `json:"tombstones,omitempty"`
+	Books          []BookState          `json:"books"`
+}
+
+// BookState is one book as the engine holds it
+type BookState struct {
+	Symbol         string               `json:"symbol"`
+	Phase          Phase                `json:"phase"`
+	AuctionKind    AuctionKind          `json:"auction_kind,omitempty"`
+	LastPrice      float64              `json:"last_price"`
+	ReferencePrice float64              `json:"reference_price"`
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker"`
+	BreakerPrices  []BreakerPrice       `json:"breaker_prices,omitempty"` // Within the breaker window
+	Halt           *HaltInfo            `json:"halt,omitempty"`
+	NextOrderID    uint64               `json:"next_order_id"` // Last number assigned
+	NextTradeID    uint64               `json:"next_trade_id"`
+	Orders         []models.Order       `json:"orders"`         // Every order the book knows, terminal ones included
+	Bids           []string             `json:"bids,omitempty"` // Resting order IDs, first to match first
+	Asks           []string             `json:"asks,omitempty"`
+	Trades         []models.Trade       `json:"trades,omitempty"`
+}
+
+// BreakerPrice is a trade price the circuit breaker measures moves from
+type BreakerPrice struct {
+	Price float64   `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// StateStore keeps the latest engine state so a restarted instance continues where it stopped
+type StateStore interface {
+	Save(state EngineState) error
+	Load() (EngineState, error) // A store that holds none returns the zero state
+}
+
+// State captures every book at one sequence. It pauses all books until each has
+// been copied, so the state never includes half of a command; the pause lasts as
+// long as the copy.
+func (e *Engine) State() (EngineState, error) {
+	e.registry.Lock()
+	defer e.registry.Unlock()
+
+	shards := e.shardList()
+	for _, s := range shards {
+		if s.stopped {
+			return EngineState{}, ErrEngineClosed
+		}
+	}
+
+	// Each book runs a command that waits until every book has been copied
+	var paused sync.WaitGroup
+	release := make(chan struct{})
+	defer close(release)
+	for _, s := range shards {
+		paused.Add(1)
+		s.queue.push(&command{fn: func() { paused.Done(); <-release }, done: make(chan struct{})})
+	}
+	paused.Wait()
+
+	state := EngineState{
+		TakenAt:        e.now(),
+		DefaultBreaker: e.defaultBreaker,
+		Tombstones:     append([]Tombstone(nil), e.tombstones...),
+		Books:          make([]BookState, 0, len(shards)),
+	}
+	for _, s := range shards {
+		state.Books = append(state.Books, s.state())
+	}
+	e.logMu.Lock()
+	state.Sequence = e.sequence
+	e.logMu.Unlock()
+	return state, nil
+}
+
+// state copies the book; callers hold the book paused
+func (s *shard) state() BookState {
+	book := s.book
+	state := BookState{
+		Symbol:         book.symbol,
+		Phase:          book.phase,
+		AuctionKind:    book.auctionKind,
+		LastPrice:      book.lastPrice,
+		ReferencePrice: book.referencePrice,
+		CircuitBreaker: book.breaker.config,
+		NextOrderID:    s.nextOrderID,
+		NextTradeID:    s.nextTradeID,
+		Orders:         make([]models.Order, 0, len(s.orders)),
+		Bids:           book.bids.queue(),
+		Asks:           book.asks.queue(),
+		Trades:         append([]models.Trade(nil), s.trades...),
+	}
+	for _, point := range book.breaker.history {
+		state.BreakerPrices = append(state.BreakerPrices, BreakerPrice{Price: point.price, At: point.at})
+	}
+	if halt := book.breaker.halt; halt != nil {
+		copied := *halt
+		state.Halt = &copied
+	}
+	for _, order := range s.orders {
+		state.Orders = append(state.Orders, *order)
+	}
+	sort.Slice(state.Orders, func(i, j int) bool {
+		_, a, _ := ParseOrderID(state.Orders[i].ID)
+		_, b, _ := ParseOrderID(state.Orders[j].ID)
+		return a < b
+	})
+	return state
+}
+
+// queue lists resting order IDs best level first, first to match first within a level
+func (s *bookSide) queue() []string {
+	ids := make([]string, 0)
+	for _, lvl := range s.levels {
+		for _, order := range lvl.orders {
+			ids = append(ids, order.ID)
+		}
+	}
+	return ids
+}
+
+// Restore rebuilds an engine from state, then replays the events recorded after it
+// with their recorded clock. Events up to the state's sequence are skipped, so the
+// whole log can be passed; the rest must follow on from it without a gap.
+func Restore(state EngineState, events []Event) (*Engine, error) {
+	engine := NewEngine()
+	clock := state.TakenAt
+	engine.now = func() time.Time { return clock }
+	engine.defaultBreaker = state.DefaultBreaker
+	engine.tombstones = append([]Tombstone(nil), state.Tombstones...)
+	engine.sequence = state.Sequence
+
+	shards := make(map[string]*shard, len(state.Books))
+	engine.shards.Store(&shards)
+	for _, book := range state.Books {
+		s := newShard(engine, newOrderBook(book.Symbol, book.ReferencePrice))
+		shards[book.Symbol] = s
+		if _, err := call(s, func() (struct{}, error) { return struct{}{}, s.restore(book) }); err != nil {
+			engine.Close()
+			return nil, fmt.Errorf("invalid state of %s: %w", book.Symbol, err)
+		}
+		for _, order := range book.Orders {
+			if order.ClientOrderID != "" {
+				engine.clientOrders.restore(order)
+			}
+		}
+	}
+
+	tail := events
+	for len(tail) > 0 && tail[0].Sequence <= state.Sequence {
+		tail = tail[1:]
+	}
+	if err := engine.replay(tail, &clock); err != nil {
+		engine.Close()
+		return nil, err
+	}
+	engine.now = time.Now
+	return engine, nil
+}
+
+// restore loads a book's state into the new shard
+func (s *shard) restore(state BookState) error {
+	book := s.book
+	book.phase = state.Phase
+	book.auctionKind = state.AuctionKind
+	book.lastPrice = state.LastPrice
+	book.breaker.config = state.CircuitBreaker
+	for _, point := range state.BreakerPrices {
+		book.breaker.history = append(book.breaker.history, pricePoint{price: point.Price, at: point.At})
+	}
+	if state.Halt != nil {
+		copied := *state.Halt
+		book.breaker.halt = &copied
+	}
+	s.nextOrderID = state.NextOrderID
+	s.nextTradeID = state.NextTradeID
+
+	for i := range state.Orders {
+		order := state.Orders[i]
+		s.orders[order.ID] = &order
+	}
+	for _, queue := range []struct {
+		side *bookSide
+		ids  []string
+	}{{book.bids, state.Bids}, {book.asks, state.Asks}} {
+		for _, id := range queue.ids {
+			order, exists := s.orders[id]
+			if !exists || order.Status.IsTerminal() {
+				return fmt.Errorf("%w: resting %s", ErrOrderNotActive, id)
+			}
+			queue.side.push(order)
+			s.trackExpiry(order)
+		}
+	}
+	s.trades = append([]models.Trade(nil), state.Trades...)
+	return nil
+}
+
+// push queues an order behind every order on the side, which rebuilds a queue
+// listed best level first
+func (s *bookSide) push(order *models.Order) {
+	price := restingPrice(order)
+	if n := len(s.levels); n > 0 && s.levels[n-1].price == price {
+		s.levels[n-1].orders = append(s.levels[n-1].orders, order)
+		return
+	}
+	s.levels = append(s.levels, &level{price: price, orders: []*models.Order{order}})
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package matching
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_State(t *testing.T) {
+	// session is a recorded engine part way through a session, with resting orders of
+	// both sides, a client order ID and trades
+	session := func(t *testing.T) (*Engine, *MemoryEventLog) {
+		t.Helper()
+		clock := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		engine := NewEngine()
+		engine.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+
+		engine.AddBook("BTC-USD", 100)
+		engine.Submit(limitOrder("a", models.SideSell, 2, 101))
+		engine.Submit(limitOrder("b", models.SideSell, 1, 101))
+		engine.Submit(limitOrder("c", models.SideBuy, 1, 101))
+		tagged := limitOrder("d", models.SideBuy, 1, 99)
+		tagged.ClientOrderID = "d-1"
+		engine.Submit(tagged)
+		engine.Submit(limitOrder("e", models.SideBuy, 1, 98))
+		return engine, log
+	}
+
+	t.Run("restores_books_and_replays_the_events_after_it", func(t *testing.T) {
+		// Given: The state taken part way through, written out and read back
+		engine, log := session(t)
+		state, err := engine.State()
+		if err != nil {
+			t.Fatalf("Expected the state, got %v", err)
+		}
+		data, _ := json.Marshal(state)
+		var loaded EngineState
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			t.Fatalf("Expected the state to decode, got %v", err)
+		}
+
+		// And: Trading carried on after it
+		engine.Submit(limitOrder("f", models.SideBuy, 1, 101))
+		engine.Cancel(OrderID("BTC-USD", 5))
+
+		// When: The engine is restored from the state and the whole log
+		restored, err := Restore(loaded, log.Events())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer restored.Close()
+
+		// Then: Books, orders, trades and sequence match the original
+		original, _ := engine.Dump("BTC-USD")
+		rebuilt, _ := restored.Dump("BTC-USD")
+		if !reflect.DeepEqual(original, rebuilt) {
+			t.Errorf("Expected identical books:\n%+v\n%+v", original, rebuilt)
+		}
+		originalOrders, originalTrades := shardState(t, engine, "BTC-USD")
+		restoredOrders, restoredTrades := shardState(t, restored, "BTC-USD")
+		if !reflect.DeepEqual(originalOrders, restoredOrders) {
+			t.Errorf("Orders diverged:\n%+v\n%+v", originalOrders, restoredOrders)
+		}
+		if restored.Sequence() != engine.Sequence() || restoredTrades != originalTrades {
+			t.Errorf("Expected sequence %d and %d trades, got %d and %d",
+				engine.Sequence(), originalTrades, restored.Sequence(), restoredTrades)
+		}
+
+		// And: Client order IDs still resolve, and new orders continue the numbering
+		if order, err := restored.ClientOrder("d", "d-1"); err != nil || order.ID != OrderID("BTC-USD", 4) {
+			t.Errorf("Expected the client order ID restored, got %+v, %v", order, err)
+		}
+		if report, err := restored.Submit(limitOrder("g", models.SideBuy, 1, 97)); err != nil || report.Order.ID != OrderID("BTC-USD", 7) {
+			t.Errorf("Expected the next order ID, got %+v, %v", report, err)
+		}
+	})
+
+	t.Run("rejects_a_gap_after_the_state", func(t *testing.T) {
+		// Given: A state and a log missing the event that follows it
+		engine, log := session(t)
+		state, _ := engine.State()
+		engine.Submit(limitOrder("f", models.SideBuy, 1, 97))
+		engine.Submit(limitOrder("g", models.SideBuy, 1, 96))
+		events := log.Events()
+		gapped := append(events[:state.Sequence:state.Sequence], events[state.Sequence+1:]...)
+
+		// When: The engine is restored
+		_, err := Restore(state, gapped)
+
+		// Then: The gap is reported
+		if !errors.Is(err, ErrReplayDiverged) {
+			t.Errorf("Expected ErrReplayDiverged, got %v", err)
+		}
+	})
+}
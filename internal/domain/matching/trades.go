@@ -0,0 +1,54 @@
+package matching
+
+import (
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// maxTradeHistory bounds the executions kept per book; the oldest are dropped first
+const maxTradeHistory = 10000
+
+// Trades returns recent executions oldest first, optionally for one symbol and/or
+// one account on either side, keeping the latest limit (0 = all kept). History is
+// rebuilt by replay, so it survives a restart from the event log.
+func (e *Engine) Trades(symbol, accountID string, limit int) ([]models.Trade, error) {
+	shards := e.shardList()
+	if symbol != "" {
+		s, err := e.shardFor(symbol)
+		if err != nil {
+			return nil, err
+		}
+		shards = []*shard{s}
+	}
+
+	trades := make([]models.Trade, 0)
+	for _, s := range shards {
+		found, _ := call(s, func() ([]models.Trade, error) { return s.tradeHistory(accountID), nil })
+		trades = append(trades, found...)
+	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].ExecutedAt.Before(trades[j].ExecutedAt) })
+	if limit > 0 && len(trades) > limit {
+		trades = trades[len(trades)-limit:]
+	}
+	return trades, nil
+}
+
+// recordTrade keeps an execution in the book's bounded history
+func (s *shard) recordTrade(trade models.Trade) {
+	s.trades = append(s.trades, trade)
+	if len(s.trades) > maxTradeHistory {
+		s.trades = append(s.trades[:0:0], s.trades[len(s.trades)-maxTradeHistory:]...)
+	}
+}
+
+func (s *shard) tradeHistory(accountID string) []models.Trade {
+	trades := make([]models.Trade, 0)
+	for _, trade := range s.trades {
+		if accountID != "" && trade.BuyAccountID != accountID && trade.SellAccountID != accountID {
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	return trades
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package matching
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestEngine_Trades(t *testing.T) {
+	t.Run("keeps_executions_per_account_and_symbol", func(t *testing.T) {
+		// Given: Two trades on BTC-USD between different accounts
+		engine := newTestEngine()
+		engine.AddBook("ETH-USD", 10)
+		engine.Submit(limitOrder("a", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("b", models.SideBuy, 1, 100))
+		engine.Submit(limitOrder("c", models.SideSell, 1, 101))
+		engine.Submit(limitOrder("a", models.SideBuy, 1, 101))
+
+		// When: History is queried by account, by symbol and with a limit
+		forA, _ := engine.Trades("", "a", 0)
+		forB, _ := engine.Trades("BTC-USD", "b", 0)
+		latest, _ := engine.Trades("", "", 1)
+		eth, _ := engine.Trades("ETH-USD", "", 0)
+
+		// Then: Account filters match either side and the limit keeps the newest
+		if len(forA) != 2 || len(forB) != 1 || forB[0].BuyAccountID != "b" {
+			t.Errorf("Expected 2 trades for a and 1 for b, got %+v and %+v", forA, forB)
+		}
+		if len(latest) != 1 || latest[0].Price != 101 {
+			t.Errorf("Expected the 101 print as the latest trade, got %+v", latest)
+		}
+		if len(eth) != 0 {
+			t.Errorf("Expected no ETH-USD trades, got %+v", eth)
+		}
+		if _, err := engine.Trades("XRP-USD", "", 0); !errors.Is(err, ErrUnknownSymbol) {
+			t.Errorf("Expected ErrUnknownSymbol, got %v", err)
+		}
+	})
+
+	t.Run("purge_anonymizes_trades_and_replay_rebuilds_history", func(t *testing.T) {
+		// Given: A recorded session with a trade, then a purge of one side
+		engine := NewEngine()
+		log := NewMemoryEventLog()
+		engine.SetEventLog(log)
+		engine.AddBook("BTC-USD", 100)
+		engine.Submit(limitOrder("a", models.SideSell, 1, 100))
+		engine.Submit(limitOrder("b", models.SideBuy, 1, 100))
+		engine.PurgeAccount("a", "anon-1")
+
+		// When: The log is redacted and replayed
+		if _, err := log.Redact(AnonymizeAccount("a", "anon-1")); err != nil {
+			t.Fatalf("Expected redaction to succeed, got %v", err)
+		}
+		replayed, err := Replay(log.Events())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both engines hold the same trade under the alias
+		original, _ := engine.Trades("", "", 0)
+		rebuilt, _ := replayed.Trades("", "", 0)
+		if len(original) != 1 || original[0].SellAccountID != "anon-1" {
+			t.Fatalf("Expected the sell side to be anonymized, got %+v", original)
+		}
+		if !reflect.DeepEqual(original, rebuilt) {
+			t.Errorf("Trade history diverged:\n%+v\n%+v", original, rebuilt)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// InstrumentKind distinguishes cash (spot) instruments from derivatives
+type InstrumentKind string
+
+const (
+	InstrumentKindSpot      InstrumentKind = "spot"
+	InstrumentKindPerpetual InstrumentKind = "perpetual"
+)
+
+// Instrument describes a tradable symbol and the venue rules that apply to it
+// Fee rates are expressed as fractions of notional (0.001 = 10 bps)
+type Instrument struct {
+	Symbol     string         `json:"symbol"`
+	BaseAsset  string         `json:"base_asset"`
+	QuoteAsset string         `json:"quote_asset"`
+	Kind       InstrumentKind `json:"kind"`
+
+	// Contract specification
+	TickSize    float64 `json:"tick_size"`
+	LotSize     float64 `json:"lot_size"`
+	MinQuantity float64 `json:"min_quantity"`
+
+	// Fee schedule
+	MakerFeeRate float64 `json:"maker_fee_rate"`
+	TakerFeeRate float64 `json:"taker_fee_rate"`
+
+	// Margin requirements (derivatives only, fractions of notional)
+	InitialMarginRate     float64 `json:"initial_margin_rate,omitempty"`
+	MaintenanceMarginRate float64 `json:"maintenance_margin_rate,omitempty"`
+
+	// Funding (perpetuals only); FundingRate is the interest rate per interval that the
+	// premium-driven rate is pulled towards
+	FundingRate     float64       `json:"funding_rate,omitempty"`
+	FundingInterval time.Duration `json:"funding_interval,omitempty"`
+
+	// ReferencePrice is used when no better price source (book, mark) is available
+	ReferencePrice float64 `json:"reference_price"`
+}
+
+// IsDerivative reports whether the instrument is margined rather than fully funded
+func (i *Instrument) IsDerivative() bool {
+	return i.Kind == InstrumentKindPerpetual
+}
+
+// MaxLeverage returns the highest leverage permitted by the initial margin rate
+func (i *Instrument) MaxLeverage() float64 {
+	if !i.IsDerivative() || i.InitialMarginRate <= 0 {
+		return 1
+	}
+	return 1 / i.InitialMarginRate
+}
+
+// ValidatePrice checks that a price is positive and aligned to the tick size
+func (i *Instrument) ValidatePrice(price float64) error {
+	if price <= 0 {
+		return fmt.Errorf("price must be positive (got: %v)", price)
+	}
+	if !isMultiple(price, i.TickSize) {
+		return fmt.Errorf("price %v is not a multiple of tick size %v", price, i.TickSize)
+	}
+	return nil
+}
+
+// ValidateQuantity checks that a quantity meets the minimum and is aligned to the lot size
+func (i *Instrument) ValidateQuantity(quantity float64) error {
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive (got: %v)", quantity)
+	}
+	if quantity < i.MinQuantity {
+		return fmt.Errorf("quantity %v is below minimum %v", quantity, i.MinQuantity)
+	}
+	if !isMultiple(quantity, i.LotSize) {
+		return fmt.Errorf("quantity %v is not a multiple of lot size %v", quantity, i.LotSize)
+	}
+	return nil
+}
+
+// isMultiple reports whether value is an integer multiple of step, tolerating float rounding
+func isMultiple(value, step float64) bool {
+	if step <= 0 {
+		return true
+	}
+	ratio := value / step
+	return math.Abs(ratio-math.Round(ratio)) < 1e-6
+}
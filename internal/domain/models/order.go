@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Side is the direction of an order or position
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// ParseSide normalizes a side string (accepts buy/sell and long/short)
+func ParseSide(value string) (Side, error) {
+	switch value {
+	case "buy", "BUY", "long", "LONG":
+		return SideBuy, nil
+	case "sell", "SELL", "short", "SHORT":
+		return SideSell, nil
+	}
+	return "", fmt.Errorf("invalid side: %q", value)
+}
+
+// Opposite returns the contra side
+func (s Side) Opposite() Side {
+	if s == SideBuy {
+		return SideSell
+	}
+	return SideBuy
+}
+
+// Sign returns +1 for buys and -1 for sells
+func (s Side) Sign() float64 {
+	if s == SideBuy {
+		return 1
+	}
+	return -1
+}
+
+// OrderType is the execution style of an order
+type OrderType string
+
+const (
+	OrderTypeMarket OrderType = "market"
+	OrderTypeLimit  OrderType = "limit"
+)
+
+// ParseOrderType normalizes an order type string, defaulting to limit
+func ParseOrderType(value string) (OrderType, error) {
+	switch value {
+	case "", "limit", "LIMIT":
+		return OrderTypeLimit, nil
+	case "market", "MARKET":
+		return OrderTypeMarket, nil
+	}
+	return "", fmt.Errorf("invalid order type: %q", value)
+}
+
+// LiquidityRole identifies whether an execution added or removed liquidity
+type LiquidityRole string
+
+const (
+	LiquidityMaker LiquidityRole = "maker"
+	LiquidityTaker LiquidityRole = "taker"
+)
+
+// TimeInForce controls how long an order remains working
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // Good till canceled
+	TimeInForceIOC TimeInForce = "IOC" // Immediate or cancel
+	TimeInForceFOK TimeInForce = "FOK" // Fill or kill
+	TimeInForceGTD TimeInForce = "GTD" // Good till date; expires at the order's ExpiresAt
+)
+
+// Rests reports whether an unfilled remainder stays on the book
+func (t TimeInForce) Rests() bool {
+	return t == TimeInForceGTC || t == TimeInForceGTD
+}
+
+// ParseTimeInForce normalizes a time-in-force string, defaulting to GTC
+func ParseTimeInForce(value string) (TimeInForce, error) {
+	switch value {
+	case "", "GTC", "gtc":
+		return TimeInForceGTC, nil
+	case "IOC", "ioc":
+		return TimeInForceIOC, nil
+	case "FOK", "fok":
+		return TimeInForceFOK, nil
+	case "GTD", "gtd":
+		return TimeInForceGTD, nil
+	}
+	return "", fmt.Errorf("invalid time in force: %q", value)
+}
+
+// OrderStatus is the lifecycle state of an order
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "new"
+	OrderStatusPartiallyFilled OrderStatus = "partially_filled"
+	OrderStatusFilled          OrderStatus = "filled"
+	OrderStatusCanceled        OrderStatus = "canceled"
+	OrderStatusRejected        OrderStatus = "rejected"
+	OrderStatusExpired         OrderStatus = "expired"
+)
+
+// IsTerminal reports whether the order can no longer trade
+func (s OrderStatus) IsTerminal() bool {
+	return s == OrderStatusFilled || s == OrderStatusCanceled || s == OrderStatusRejected || s == OrderStatusExpired
+}
+
+// Order is a single order as tracked by the matching engine
+type Order struct {
+	ID             string      `json:"id"`
+	ClientOrderID  string      `json:"client_order_id,omitempty"` // Caller-assigned, unique per account
+	AccountID      string      `json:"account_id"`
+	Symbol         string      `json:"symbol"`
+	Side           Side        `json:"side"`
+	Type           OrderType   `json:"type"`
+	TimeInForce    TimeInForce `json:"time_in_force"`
+	Price          float64     `json:"price,omitempty"`
+	Quantity       float64     `json:"quantity"`
+	FilledQuantity float64     `json:"filled_quantity"`
+	AveragePrice   float64     `json:"average_price,omitempty"`
+	Status         OrderStatus `json:"status"`
+	ExpiresAt      time.Time   `json:"expires_at,omitempty"` // GTD orders only
+	Priority       int         `json:"priority,omitempty"`   // Queue tier within a price level; higher fills first
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// RemainingQuantity returns the unfilled quantity
+func (o *Order) RemainingQuantity() float64 {
+	remaining := o.Quantity - o.FilledQuantity
+	if remaining < quantityEpsilon {
+		return 0
+	}
+	return remaining
+}
+
+// ApplyFill records an execution against the order and updates its status
+func (o *Order) ApplyFill(quantity, price float64, at time.Time) {
+	notional := o.AveragePrice*o.FilledQuantity + price*quantity
+	o.FilledQuantity += quantity
+	o.AveragePrice = notional / o.FilledQuantity
+	o.UpdatedAt = at
+
+	if o.RemainingQuantity() == 0 {
+		o.Status = OrderStatusFilled
+	} else {
+		o.Status = OrderStatusPartiallyFilled
+	}
+}
+
+// quantityEpsilon absorbs float rounding when comparing quantities
+const quantityEpsilon = 1e-12
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Trade is a single execution between a buy and a sell order
+type Trade struct {
+	ID            string    `json:"id"`
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	Quantity      float64   `json:"quantity"`
+	BuyOrderID    string    `json:"buy_order_id"`
+	SellOrderID   string    `json:"sell_order_id"`
+	BuyAccountID  string    `json:"buy_account_id"`
+	SellAccountID string    `json:"sell_account_id"`
+	TakerSide     Side      `json:"taker_side,omitempty"` // Empty for auction trades
+	Auction       bool      `json:"auction"`
+	Deleverage    bool      `json:"deleverage,omitempty"` // Closed against a bankrupt position at its bankruptcy price, outside the book
+	ExecutedAt    time.Time `json:"executed_at"`
+}
+
+// Notional returns price * quantity
+func (t *Trade) Notional() float64 {
+	return t.Price * t.Quantity
+}
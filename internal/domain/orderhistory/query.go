@@ -0,0 +1,161 @@
+package orderhistory
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrInvalidCursor is returned for a cursor this venue did not issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position after an order in creation order: creation time, then order ID
+type Cursor struct {
+	CreatedAt time.Time
+	OrderID   string
+}
+
+// IsZero reports whether the cursor is unset, starting from the first order
+func (c Cursor) IsZero() bool {
+	return c.CreatedAt.IsZero() && c.OrderID == ""
+}
+
+// String encodes the cursor opaquely for clients to send back
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.OrderID))
+}
+
+// ParseCursor decodes a cursor from a previous page; empty is the zero cursor
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	nanos, orderID, ok := strings.Cut(string(raw), ":")
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil || orderID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: time.Unix(0, createdAt).UTC(), OrderID: orderID}, nil
+}
+
+// CursorOf is the position after order
+func CursorOf(order models.Order) Cursor {
+	return Cursor{CreatedAt: order.CreatedAt, OrderID: order.ID}
+}
+
+// before reports whether a comes before b in creation order
+func before(a, b Cursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.OrderID < b.OrderID
+}
+
+// Query selects orders in creation order, or its reverse; empty fields match anything
+type Query struct {
+	AccountID  string
+	Symbol     string
+	Statuses   []models.OrderStatus // Any of them
+	From       time.Time            // Created at or after
+	To         time.Time            // Created before
+	Descending bool                 // Newest first
+	After      Cursor               // Continues a previous page
+	Limit      int
+}
+
+// Validate checks the statuses, time range and limit
+func (q Query) Validate() error {
+	if q.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return errors.New("start time must be before end time")
+	}
+	for _, status := range q.Statuses {
+		switch status {
+		case models.OrderStatusNew, models.OrderStatusPartiallyFilled, models.OrderStatusFilled,
+			models.OrderStatusCanceled, models.OrderStatusRejected, models.OrderStatusExpired:
+		default:
+			return errors.New("unknown order status " + strconv.Quote(string(status)))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether order is selected, ignoring the cursor and limit
+func (q Query) Matches(order models.Order) bool {
+	if q.AccountID != "" && order.AccountID != q.AccountID {
+		return false
+	}
+	if q.Symbol != "" && order.Symbol != q.Symbol {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		matched := false
+		for _, status := range q.Statuses {
+			matched = matched || order.Status == status
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !q.From.IsZero() && order.CreatedAt.Before(q.From) {
+		return false
+	}
+	return q.To.IsZero() || order.CreatedAt.Before(q.To)
+}
+
+// Continues reports whether order comes after the query's cursor in its direction
+func (q Query) Continues(order models.Order) bool {
+	if q.After.IsZero() {
+		return true
+	}
+	if q.Descending {
+		return before(CursorOf(order), q.After)
+	}
+	return before(q.After, CursorOf(order))
+}
+
+// Page is one page of orders, oldest first unless the query was descending
+type Page struct {
+	Orders     []models.Order `json:"orders"`
+	NextCursor string         `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// Paginate pages through orders held in memory the way a store pages through its own
+func Paginate(orders []models.Order, query Query) Page {
+	sorted := make([]models.Order, 0, len(orders))
+	for _, order := range orders {
+		if query.Matches(order) && query.Continues(order) {
+			sorted = append(sorted, order)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if query.Descending {
+			return before(CursorOf(sorted[j]), CursorOf(sorted[i]))
+		}
+		return before(CursorOf(sorted[i]), CursorOf(sorted[j]))
+	})
+	return NewPage(sorted, query.Limit)
+}
+
+// NewPage cuts orders fetched in the query's order, up to one beyond limit, into a page
+func NewPage(orders []models.Order, limit int) Page {
+	if len(orders) <= limit {
+		return Page{Orders: orders}
+	}
+	orders = orders[:limit]
+	return Page{Orders: orders, NextCursor: CursorOf(orders[limit-1]).String()}
+}
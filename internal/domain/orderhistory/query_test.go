@@ -0,0 +1,88 @@
+//go:build unit
+
+package orderhistory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestPaginate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	orders := []models.Order{
+		{ID: "BTC-USD-3", AccountID: "a", Symbol: "BTC-USD", Status: models.OrderStatusFilled, CreatedAt: start.Add(time.Second)},
+		{ID: "BTC-USD-1", AccountID: "a", Symbol: "BTC-USD", Status: models.OrderStatusNew, CreatedAt: start},
+		{ID: "BTC-USD-2", AccountID: "a", Symbol: "BTC-USD", Status: models.OrderStatusCanceled, CreatedAt: start},
+		{ID: "ETH-USD-1", AccountID: "a", Symbol: "ETH-USD", Status: models.OrderStatusFilled, CreatedAt: start.Add(2 * time.Second)},
+		{ID: "BTC-USD-4", AccountID: "b", Symbol: "BTC-USD", Status: models.OrderStatusNew, CreatedAt: start.Add(3 * time.Second)},
+	}
+
+	walk := func(t *testing.T, query Query) ([]string, int) {
+		t.Helper()
+		var ids []string
+		pages := 0
+		for {
+			page := Paginate(orders, query)
+			pages++
+			for _, order := range page.Orders {
+				ids = append(ids, order.ID)
+			}
+			if page.NextCursor == "" {
+				return ids, pages
+			}
+			cursor, err := ParseCursor(page.NextCursor)
+			if err != nil {
+				t.Fatalf("Expected the issued cursor to parse, got %v", err)
+			}
+			query.After = cursor
+		}
+	}
+
+	t.Run("cursors_walk_every_matching_order_once_in_creation_order", func(t *testing.T) {
+		// Given: Account a's BTC-USD orders, two created at the same time
+		query := Query{AccountID: "a", Symbol: "BTC-USD", Limit: 2}
+
+		// When: The pages are followed through their cursors, oldest then newest first
+		ascending, pages := walk(t, query)
+		query.Descending = true
+		descending, _ := walk(t, query)
+
+		// Then: Each order appears once, ordered by creation time then ID
+		if pages != 2 || len(ascending) != 3 || ascending[0] != "BTC-USD-1" || ascending[1] != "BTC-USD-2" || ascending[2] != "BTC-USD-3" {
+			t.Errorf("Expected BTC-USD-1, -2 then -3 over two pages, got %v over %d", ascending, pages)
+		}
+		if len(descending) != 3 || descending[0] != "BTC-USD-3" || descending[2] != "BTC-USD-1" {
+			t.Errorf("Expected the reverse newest first, got %v", descending)
+		}
+	})
+
+	t.Run("filters_by_status_and_half_open_time_range", func(t *testing.T) {
+		page := Paginate(orders, Query{
+			Statuses: []models.OrderStatus{models.OrderStatusFilled, models.OrderStatusNew},
+			From:     start.Add(time.Second),
+			To:       start.Add(3 * time.Second),
+			Limit:    10,
+		})
+
+		if len(page.Orders) != 2 || page.Orders[0].ID != "BTC-USD-3" || page.Orders[1].ID != "ETH-USD-1" || page.NextCursor != "" {
+			t.Errorf("Expected BTC-USD-3 and ETH-USD-1 on one page, got %+v", page)
+		}
+	})
+
+	t.Run("rejects_foreign_cursors_and_bad_queries", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm9wZQ"} {
+			if _, err := ParseCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected %q to be rejected, got %v", cursor, err)
+			}
+		}
+		if err := (Query{Statuses: []models.OrderStatus{"open"}, Limit: 1}).Validate(); err == nil {
+			t.Error("Expected an unknown status to be rejected")
+		}
+		if err := (Query{From: start, To: start, Limit: 1}).Validate(); err == nil {
+			t.Error("Expected an empty time range to be rejected")
+		}
+	})
+}
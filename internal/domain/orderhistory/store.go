@@ -0,0 +1,27 @@
+// Package orderhistory keeps the latest state of every order outside the engine,
+// which holds orders only for the life of the process
+package orderhistory
+
+import "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+
+// Store persists the latest state of each order
+type Store interface {
+	Save(orders []models.Order) error       // Replaces older states of the same orders
+	Reassign(accountID, alias string) error // Moves an erased account's orders to its alias
+	Query(query Query) (Page, error)
+}
+
+// Latest keeps the last state of each order in orders, in the order first seen
+func Latest(orders []models.Order) []models.Order {
+	index := make(map[string]int, len(orders))
+	latest := make([]models.Order, 0, len(orders))
+	for _, order := range orders {
+		if i, seen := index[order.ID]; seen {
+			latest[i] = order
+			continue
+		}
+		index[order.ID] = len(latest)
+		latest = append(latest, order)
+	}
+	return latest
+}
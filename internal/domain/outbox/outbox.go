@@ -0,0 +1,229 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Destination is where a message is published
+type Destination string
+
+const (
+	DestinationAudit Destination = "audit-correlator"
+	DestinationRedis Destination = "redis"
+)
+
+// Message is one event waiting to be published to one destination. It is kept until
+// the destination takes it, so it may be published more than once; consumers drop
+// repeats by DedupKey.
+type Message struct {
+	ID            string          `json:"message_id"` // Unique per event and destination
+	DedupKey      string          `json:"dedup_key"`  // The event's ID, the same at every destination
+	Destination   Destination     `json:"destination"`
+	Topic         string          `json:"topic"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty"`
+}
+
+// NewMessage encodes an event for one destination, due straight away
+func NewMessage(dedupKey string, destination Destination, topic string, event interface{}, at time.Time) (Message, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode %s event %s: %w", topic, dedupKey, err)
+	}
+	return Message{
+		ID:            dedupKey + "@" + string(destination),
+		DedupKey:      dedupKey,
+		Destination:   destination,
+		Topic:         topic,
+		Payload:       payload,
+		CreatedAt:     at,
+		NextAttemptAt: at,
+	}, nil
+}
+
+// Store keeps messages until they are dispatched
+type Store interface {
+	// Write adds messages; those already written, by ID, are skipped
+	Write(messages []Message) error
+	// Due returns up to limit undispatched messages due by now, oldest first
+	Due(now time.Time, limit int) ([]Message, error)
+	// Dispatched marks messages taken by their destination
+	Dispatched(ids []string, at time.Time) error
+	// Failed records each message's attempts, last error and next attempt
+	Failed(messages []Message) error
+}
+
+// TradeStore writes trades and the messages announcing them in one transaction, so
+// neither is kept without the other
+type TradeStore interface {
+	AppendWithMessages(trades []models.Trade, messages []Message) error
+}
+
+// Publisher delivers messages to one destination, all or none
+type Publisher interface {
+	Publish(ctx context.Context, messages []Message) error
+}
+
+// Backoff is how long a failed message waits before its next attempt: Base after the
+// first failure, doubling up to Max
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b Backoff) after(attempts int) time.Duration {
+	wait := b.Base
+	for i := 1; i < attempts && wait < b.Max; i++ {
+		wait *= 2
+	}
+	if b.Max > 0 && wait > b.Max {
+		wait = b.Max
+	}
+	return wait
+}
+
+// Run is what one dispatch published
+type Run struct {
+	Due        int `json:"due"`
+	Dispatched int `json:"dispatched"`
+	Failed     int `json:"failed"`
+}
+
+// Dispatch publishes up to limit messages due by now, one batch per destination,
+// marking each batch dispatched or failed. Messages for a destination without a
+// publisher fail and are retried in case one is configured later.
+func Dispatch(ctx context.Context, store Store, publishers map[Destination]Publisher, backoff Backoff, now time.Time, limit int) (Run, error) {
+	due, err := store.Due(now, limit)
+	if err != nil {
+		return Run{}, err
+	}
+	run := Run{Due: len(due)}
+
+	batches := make(map[Destination][]Message)
+	destinations := make([]Destination, 0)
+	for _, message := range due {
+		if _, ok := batches[message.Destination]; !ok {
+			destinations = append(destinations, message.Destination)
+		}
+		batches[message.Destination] = append(batches[message.Destination], message)
+	}
+	sort.Slice(destinations, func(i, j int) bool { return destinations[i] < destinations[j] })
+
+	for _, destination := range destinations {
+		batch := batches[destination]
+		publishErr := fmt.Errorf("no publisher for %s", destination)
+		if publisher, ok := publishers[destination]; ok {
+			publishErr = publisher.Publish(ctx, batch)
+		}
+		if publishErr == nil {
+			ids := make([]string, 0, len(batch))
+			for _, message := range batch {
+				ids = append(ids, message.ID)
+			}
+			if err := store.Dispatched(ids, now); err != nil {
+				// Published but not marked: the batch is published again, and deduplicated downstream
+				return run, err
+			}
+			run.Dispatched += len(batch)
+			continue
+		}
+
+		for i := range batch {
+			batch[i].Attempts++
+			batch[i].LastError = publishErr.Error()
+			batch[i].NextAttemptAt = now.Add(backoff.after(batch[i].Attempts))
+		}
+		if err := store.Failed(batch); err != nil {
+			return run, err
+		}
+		run.Failed += len(batch)
+	}
+	return run, nil
+}
+
+// MemoryStore keeps messages in memory, for tests and single-process runs
+type MemoryStore struct {
+	messages map[string]*Message
+	order    []string // IDs in write order
+	mu       sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: make(map[string]*Message)}
+}
+
+func (s *MemoryStore) Write(messages []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, message := range messages {
+		if _, ok := s.messages[message.ID]; ok {
+			continue
+		}
+		message := message
+		s.messages[message.ID] = &message
+		s.order = append(s.order, message.ID)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Due(now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := make([]Message, 0)
+	for _, id := range s.order {
+		message := s.messages[id]
+		if message.DispatchedAt != nil || message.NextAttemptAt.After(now) {
+			continue
+		}
+		due = append(due, *message)
+		if limit > 0 && len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (s *MemoryStore) Dispatched(ids []string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if message, ok := s.messages[id]; ok {
+			dispatchedAt := at
+			message.DispatchedAt = &dispatchedAt
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Failed(messages []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, failed := range messages {
+		if message, ok := s.messages[failed.ID]; ok {
+			message.Attempts, message.LastError, message.NextAttemptAt = failed.Attempts, failed.LastError, failed.NextAttemptAt
+		}
+	}
+	return nil
+}
+
+// Messages returns every message held, in write order
+func (s *MemoryStore) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make([]Message, 0, len(s.order))
+	for _, id := range s.order {
+		messages = append(messages, *s.messages[id])
+	}
+	return messages
+}
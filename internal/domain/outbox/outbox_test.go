@@ -0,0 +1,96 @@
+//go:build unit
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingPublisher keeps what it was given, failing while down
+type recordingPublisher struct {
+	down      bool
+	published []Message
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, messages []Message) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	p.published = append(p.published, messages...)
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	message := func(t *testing.T, id string, destination Destination) Message {
+		m, err := NewMessage(id, destination, "order.placed", map[string]string{"event_id": id}, now)
+		if err != nil {
+			t.Fatalf("Expected a message, got %v", err)
+		}
+		return m
+	}
+
+	t.Run("publishes_each_destination_once", func(t *testing.T) {
+		// Given: One event for two destinations, written twice
+		store := NewMemoryStore()
+		batch := []Message{message(t, "e-1", DestinationAudit), message(t, "e-1", DestinationRedis)}
+		store.Write(batch)
+		store.Write(batch)
+		audit, redis := &recordingPublisher{}, &recordingPublisher{}
+		publishers := map[Destination]Publisher{DestinationAudit: audit, DestinationRedis: redis}
+
+		// When: The outbox is dispatched twice
+		first, err := Dispatch(context.Background(), store, publishers, Backoff{Base: time.Second}, now, 0)
+		second, _ := Dispatch(context.Background(), store, publishers, Backoff{Base: time.Second}, now, 0)
+
+		// Then: Each destination receives the event once, under one dedup key
+		if err != nil || first.Due != 2 || first.Dispatched != 2 || second.Due != 0 {
+			t.Fatalf("Expected two messages dispatched once, got %+v then %+v, %v", first, second, err)
+		}
+		if len(audit.published) != 1 || len(redis.published) != 1 || audit.published[0].DedupKey != redis.published[0].DedupKey {
+			t.Errorf("Expected one message per destination sharing a dedup key, got %+v and %+v", audit.published, redis.published)
+		}
+		if string(audit.published[0].Payload) != `{"event_id":"e-1"}` {
+			t.Errorf("Unexpected payload %s", audit.published[0].Payload)
+		}
+	})
+
+	t.Run("backs_off_failed_messages_until_published", func(t *testing.T) {
+		// Given: A destination that is down
+		store := NewMemoryStore()
+		store.Write([]Message{message(t, "e-1", DestinationAudit)})
+		publisher := &recordingPublisher{down: true}
+		publishers := map[Destination]Publisher{DestinationAudit: publisher}
+		backoff := Backoff{Base: time.Second, Max: 3 * time.Second}
+
+		// When: Dispatch fails twice, then runs before and after the backoff
+		Dispatch(context.Background(), store, publishers, backoff, now, 0)
+		Dispatch(context.Background(), store, publishers, backoff, now.Add(time.Second), 0)
+		failed := store.Messages()[0]
+		publisher.down = false
+		early, _ := Dispatch(context.Background(), store, publishers, backoff, now.Add(2*time.Second), 0)
+		late, _ := Dispatch(context.Background(), store, publishers, backoff, now.Add(3*time.Second), 0)
+
+		// Then: The wait doubles, and the message goes out once it is due
+		if failed.Attempts != 2 || failed.LastError == "" || !failed.NextAttemptAt.Equal(now.Add(3*time.Second)) {
+			t.Errorf("Expected two attempts with a doubled backoff, got %+v", failed)
+		}
+		if early.Due != 0 || late.Dispatched != 1 || len(publisher.published) != 1 {
+			t.Errorf("Expected the message published once due, got %+v then %+v", early, late)
+		}
+	})
+
+	t.Run("fails_messages_without_a_publisher", func(t *testing.T) {
+		store := NewMemoryStore()
+		store.Write([]Message{message(t, "e-1", DestinationRedis)})
+
+		run, err := Dispatch(context.Background(), store, nil, Backoff{Base: time.Second}, now, 0)
+
+		if err != nil || run.Failed != 1 || store.Messages()[0].LastError != "no publisher for redis" {
+			t.Errorf("Expected the message failed for want of a publisher, got %+v, %v", run, err)
+		}
+	})
+}
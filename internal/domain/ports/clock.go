@@ -0,0 +1,11 @@
+package ports
+
+import "time"
+
+// Clock is the source of venue time. The matching engine, order expiry, session
+// transitions and funding all read it, so an orchestrator can swap the wall clock
+// for an accelerated simulated one without the domain noticing.
+type Clock interface {
+	// Now returns the current venue time
+	Now() time.Time
+}
@@ -0,0 +1,11 @@
+package ports
+
+// IDObfuscator maps the venue's internal order and trade IDs to the IDs published on
+// public market data. Internal IDs stay stable for persistence and private channels;
+// a keyed mapping stops a public feed from being joined to the orders an account
+// sees on its own channels.
+type IDObfuscator interface {
+	// External returns the public ID for an internal one; the same input always
+	// yields the same output
+	External(id string) string
+}
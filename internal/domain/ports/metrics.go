@@ -13,12 +13,40 @@ type MetricsPort interface {
 	// labels: key-value pairs (e.g., {"method": "GET", "route": "/api/v1/health", "code": "200"})
 	IncCounter(name string, labels map[string]string)
 
+	// AddCounter increases a counter metric by value, which must not be negative
+	// name: metric name (e.g., "exchange_trade_volume_total")
+	// value: amount to add (e.g., 0.5 for a trade of 0.5 BTC)
+	// labels: key-value pairs
+	AddCounter(name string, value float64, labels map[string]string)
+
 	// ObserveHistogram records a value in a histogram metric
 	// name: metric name (e.g., "request_duration_seconds")
 	// value: observed value (e.g., 0.123 for 123ms)
 	// labels: key-value pairs
 	ObserveHistogram(name string, value float64, labels map[string]string)
 
+	// ObserveHistogramWithExemplar records a value like ObserveHistogram and attaches
+	// an exemplar linking it to the request it came from
+	// exemplar: key-value pairs (e.g., {"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"});
+	// empty attaches none, and exemplars the backend cannot hold are dropped
+	ObserveHistogramWithExemplar(name string, value float64, labels map[string]string, exemplar map[string]string)
+
+	// SetHistogramBuckets sets the bucket upper bounds of a histogram in place of
+	// DefaultDurationBuckets, which only suit durations in seconds. It must be called
+	// before the histogram is first observed; afterwards it fails unless the buckets
+	// are unchanged.
+	// name: metric name (e.g., "exchange_trade_notional")
+	// buckets: increasing upper bounds (e.g., ExponentialBuckets(10, 10, 7))
+	SetHistogramBuckets(name string, buckets []float64) error
+
+	// ObserveSummary records a value in a summary metric, which reports its 50th,
+	// 90th and 99th percentiles over the last ten minutes. Unlike a histogram's
+	// buckets, the percentiles cannot be aggregated across instances.
+	// name: metric name (e.g., "exchange_matching_seconds")
+	// value: observed value
+	// labels: key-value pairs
+	ObserveSummary(name string, value float64, labels map[string]string)
+
 	// SetGauge sets a gauge metric to a specific value
 	// name: metric name (e.g., "service_dependency_ready")
 	// value: gauge value
@@ -32,6 +60,21 @@ type MetricsPort interface {
 	GetHTTPHandler() http.Handler
 }
 
+// DefaultDurationBuckets are the bucket upper bounds of histograms whose buckets were
+// not set: 5ms to 10s, for request and call durations in seconds
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// ExponentialBuckets returns count bucket upper bounds, the first start and each
+// factor times the one before, e.g. ExponentialBuckets(1, 2, 4) is 1, 2, 4, 8
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
 // MetricsLabels defines standard labels used across all metrics
 // These should have LOW CARDINALITY to avoid metric explosion
 type MetricsLabels struct {
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+)
+
+// PriceSource delivers external prices, such as the ecosystem's market data service
+// publishing the scenario an orchestrator runs, for the venue to anchor its books to
+type PriceSource interface {
+	// Follow calls handle with each price until ctx is done or the source fails;
+	// it returns nil only when ctx is done
+	Follow(ctx context.Context, handle func(pricefeed.Price)) error
+}
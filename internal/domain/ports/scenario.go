@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// ScenarioReporter tells the orchestrator that provided a scenario script how its run
+// is going
+type ScenarioReporter interface {
+	// ReportProgress sends the run's progress after steps execute and when it ends
+	ReportProgress(ctx context.Context, progress scenario.Progress) error
+}
@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// SettingSource reads settings an operator may change while the venue runs, such as
+// the ecosystem's configuration service
+type SettingSource interface {
+	// Setting returns the current JSON value of key
+	Setting(ctx context.Context, key string) (json.RawMessage, error)
+}
+
+// SettingWatcher tells subscribers when settings change, so they apply while the
+// venue runs rather than at the next restart
+type SettingWatcher interface {
+	// Subscribe calls apply with key's value once it is first read and again each
+	// time it changes, until unsubscribe is called
+	Subscribe(key string, apply func(ctx context.Context, value json.RawMessage) error) (unsubscribe func())
+}
+
+// CacheStats is how a client's cache has served it since the client was created
+type CacheStats struct {
+	Entries       int       `json:"entries"`        // Keys cached now, missing keys included
+	Capacity      int       `json:"capacity"`       // Most keys cached at once
+	Hits          int64     `json:"hits"`           // Reads answered from the cache
+	Misses        int64     `json:"misses"`         // Reads that went to the source
+	Evictions     int64     `json:"evictions"`      // Keys evicted to stay within capacity
+	FallbackReads int64     `json:"fallback_reads"` // Reads answered from a fallback while the source failed
+	Connected     bool      `json:"connected"`      // Whether the source answered last
+	LastUpdate    time.Time `json:"last_update"`    // When the cache last took a value
+}
+
+// CacheReporter is a client whose cache operators can inspect while the venue
+// runs, such as the configuration service client
+type CacheReporter interface {
+	CacheStats() CacheStats
+}
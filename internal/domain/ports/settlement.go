@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+// SettlementGateway submits settlement instructions to the custodian holding the
+// venue's accounts
+type SettlementGateway interface {
+	// Submit sends one batch and returns the custodian's answer for each instruction
+	// it answered; an error means the batch may not have arrived and is resubmitted
+	Submit(ctx context.Context, batch settlement.Batch) ([]settlement.Ack, error)
+}
@@ -0,0 +1,54 @@
+package ports
+
+import "context"
+
+// TracingPort defines the interface for distributed tracing
+// This port abstracts the tracing implementation (OpenTelemetry, etc.) so that
+// trace context flows between services without the domain depending on it
+type TracingPort interface {
+	// StartSpan starts a span as a child of the span in ctx, if any
+	// name: span name (e.g., "/exchange.v1.TradingService/PlaceOrder" or "GET /api/v1/health")
+	// kind: the span's role in the call (server, client or internal)
+	// attributes: key-value pairs recorded on the span
+	// Returns ctx carrying the new span; the caller must End it
+	StartSpan(ctx context.Context, name string, kind SpanKind, attributes map[string]string) (context.Context, Span)
+
+	// Propagation
+
+	// Inject writes the trace context in ctx into carrier, e.g. outgoing gRPC metadata
+	Inject(ctx context.Context, carrier TraceCarrier)
+
+	// Extract returns ctx continuing the trace context carrier holds, e.g. incoming HTTP headers
+	Extract(ctx context.Context, carrier TraceCarrier) context.Context
+
+	// Shutdown flushes spans not yet exported and stops exporting
+	Shutdown(ctx context.Context) error
+}
+
+// Span is one timed operation within a trace
+type Span interface {
+	// SetAttribute records a key-value pair on the span
+	SetAttribute(key, value string)
+
+	// RecordError records err on the span and marks it failed
+	RecordError(err error)
+
+	// End completes the span; later calls are ignored
+	End()
+}
+
+// SpanKind is the role a span plays in a call
+type SpanKind string
+
+const (
+	SpanKindInternal SpanKind = "internal" // Work within this service
+	SpanKindServer   SpanKind = "server"   // Handling a call from another service
+	SpanKindClient   SpanKind = "client"   // Calling another service
+)
+
+// TraceCarrier holds trace context on the wire, such as HTTP headers or gRPC metadata
+type TraceCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+	Keys() []string
+}
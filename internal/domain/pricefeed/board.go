@@ -0,0 +1,123 @@
+package pricefeed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Price is one external observation of where a symbol trades
+type Price struct {
+	Symbol     string    `json:"symbol"`
+	Price      float64   `json:"price"`
+	Source     string    `json:"source,omitempty"`
+	Time       time.Time `json:"time"`        // When the source observed it; zero when it sent none
+	ReceivedAt time.Time `json:"received_at"` // On the venue clock
+}
+
+// message is the JSON a market data publisher sends; a bid and ask without a price
+// quote the mid
+type message struct {
+	Symbol      string    `json:"symbol"`
+	Price       float64   `json:"price"`
+	Bid         float64   `json:"bid"`
+	Ask         float64   `json:"ask"`
+	Source      string    `json:"source"`
+	Timestamp   time.Time `json:"timestamp"`
+	TimestampMs int64     `json:"timestamp_ms"`
+}
+
+// Decode reads a published price, e.g. {"symbol":"BTC-USD","price":60000.5,"timestamp":"2024-01-02T03:04:05Z"}
+func Decode(payload []byte) (Price, error) {
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return Price{}, fmt.Errorf("invalid price message: %w", err)
+	}
+	price := Price{Symbol: strings.TrimSpace(msg.Symbol), Price: msg.Price, Source: msg.Source, Time: msg.Timestamp}
+	if price.Price == 0 && msg.Bid > 0 && msg.Ask > 0 {
+		price.Price = (msg.Bid + msg.Ask) / 2
+	}
+	if price.Time.IsZero() && msg.TimestampMs > 0 {
+		price.Time = time.UnixMilli(msg.TimestampMs).UTC()
+	}
+	if price.Symbol == "" {
+		return Price{}, errors.New("price message has no symbol")
+	}
+	if price.Price <= 0 {
+		return Price{}, fmt.Errorf("price message for %s has no positive price", price.Symbol)
+	}
+	return price, nil
+}
+
+// Quote is a symbol's latest external price and whether it still anchors the venue
+type Quote struct {
+	Symbol     string    `json:"symbol"`
+	Price      float64   `json:"price"`
+	Source     string    `json:"source,omitempty"`
+	Time       time.Time `json:"time"`
+	ReceivedAt time.Time `json:"received_at"`
+	Fresh      bool      `json:"fresh"` // Received within the board's maximum age
+}
+
+// Board keeps the latest external price per symbol. Prices older than the maximum
+// age stop anchoring the venue until the next one arrives.
+type Board struct {
+	maxAge time.Duration
+	prices map[string]Price
+	mu     sync.RWMutex
+}
+
+func NewBoard(maxAge time.Duration) *Board {
+	return &Board{maxAge: maxAge, prices: make(map[string]Price)}
+}
+
+// Update records price unless the source already sent a later one, reporting
+// whether it was recorded
+func (b *Board) Update(price Price) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if current, exists := b.prices[price.Symbol]; exists && !price.Time.IsZero() && price.Time.Before(current.Time) {
+		return false
+	}
+	b.prices[price.Symbol] = price
+	return true
+}
+
+// Latest returns a symbol's price if one arrived within the maximum age of now
+func (b *Board) Latest(symbol string, now time.Time) (Price, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	price, exists := b.prices[symbol]
+	if !exists || !b.fresh(price, now) {
+		return Price{}, false
+	}
+	return price, true
+}
+
+// Quotes returns every symbol's latest price, fresh or not, by symbol
+func (b *Board) Quotes(now time.Time) []Quote {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	quotes := make([]Quote, 0, len(b.prices))
+	for _, price := range b.prices {
+		quotes = append(quotes, Quote{
+			Symbol:     price.Symbol,
+			Price:      price.Price,
+			Source:     price.Source,
+			Time:       price.Time,
+			ReceivedAt: price.ReceivedAt,
+			Fresh:      b.fresh(price, now),
+		})
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Symbol < quotes[j].Symbol })
+	return quotes
+}
+
+// fresh reports whether price is within the maximum age; no maximum keeps every price
+func (b *Board) fresh(price Price, now time.Time) bool {
+	return b.maxAge <= 0 || now.Sub(price.ReceivedAt) <= b.maxAge
+}
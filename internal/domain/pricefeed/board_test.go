@@ -0,0 +1,74 @@
+//go:build unit
+
+package pricefeed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	t.Run("reads_prices_and_mids", func(t *testing.T) {
+		// Given: A traded price with an RFC 3339 time and a quote with a millisecond time
+		traded := `{"symbol":"BTC-USD","price":60000.5,"source":"mds","timestamp":"2024-01-02T03:04:05Z"}`
+		quoted := `{"symbol":"ETH-USD","bid":2999,"ask":3001,"timestamp_ms":1704164645000}`
+
+		// When: Both are decoded
+		price, err := Decode([]byte(traded))
+		mid, midErr := Decode([]byte(quoted))
+
+		// Then: The quote is priced at its mid and both carry their times
+		if err != nil || price.Price != 60000.5 || price.Source != "mds" || !price.Time.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("Unexpected price: %+v (%v)", price, err)
+		}
+		if midErr != nil || mid.Price != 3000 || !mid.Time.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("Unexpected mid: %+v (%v)", mid, midErr)
+		}
+	})
+
+	t.Run("rejects_unusable_messages", func(t *testing.T) {
+		for _, payload := range []string{`not json`, `{"price":1}`, `{"symbol":"BTC-USD"}`, `{"symbol":"BTC-USD","price":-1}`} {
+			if _, err := Decode([]byte(payload)); err == nil {
+				t.Errorf("Expected %s to be rejected", payload)
+			}
+		}
+	})
+}
+
+func TestBoard(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("prices_anchor_until_they_age_out", func(t *testing.T) {
+		// Given: A board keeping prices for 30s and one price received at start
+		board := NewBoard(30 * time.Second)
+		board.Update(Price{Symbol: "BTC-USD", Price: 60000, ReceivedAt: start})
+
+		// When: It is read within and past the maximum age
+		fresh, freshOK := board.Latest("BTC-USD", start.Add(30*time.Second))
+		_, staleOK := board.Latest("BTC-USD", start.Add(31*time.Second))
+
+		// Then: Only the first read uses it, though it is still listed as stale
+		if !freshOK || fresh.Price != 60000 || staleOK {
+			t.Errorf("Expected the price fresh at 30s and stale at 31s, got %+v %v %v", fresh, freshOK, staleOK)
+		}
+		if quotes := board.Quotes(start.Add(time.Minute)); len(quotes) != 1 || quotes[0].Fresh {
+			t.Errorf("Expected one stale quote, got %+v", quotes)
+		}
+	})
+
+	t.Run("ignores_prices_older_than_the_latest", func(t *testing.T) {
+		// Given: A price observed at start
+		board := NewBoard(0)
+		board.Update(Price{Symbol: "BTC-USD", Price: 60000, Time: start, ReceivedAt: start})
+
+		// When: A price observed earlier arrives late, then one without a time
+		late := board.Update(Price{Symbol: "BTC-USD", Price: 59000, Time: start.Add(-time.Second), ReceivedAt: start.Add(time.Second)})
+		untimed := board.Update(Price{Symbol: "BTC-USD", Price: 61000, ReceivedAt: start.Add(2 * time.Second)})
+
+		// Then: The late price is dropped and the untimed one taken
+		price, _ := board.Latest("BTC-USD", start.Add(time.Hour))
+		if late || !untimed || price.Price != 61000 {
+			t.Errorf("Expected only the untimed price to be recorded, got %v %v %+v", late, untimed, price)
+		}
+	})
+}
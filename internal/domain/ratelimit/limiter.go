@@ -0,0 +1,258 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLimited refuses a request beyond its caller's rate limit
+var ErrLimited = errors.New("rate limit exceeded")
+
+// Scopes a bucket can belong to
+const (
+	ScopeKey = "key" // An API key, or the account it was issued to
+	ScopeIP  = "ip"  // A client address
+)
+
+// DefaultWindow is how long an empty bucket takes to refill when unconfigured
+const DefaultWindow = time.Minute
+
+// sweepThreshold is how many buckets are held before full ones are dropped
+const sweepThreshold = 4096
+
+// Limits is how much request weight callers may spend. Each caller holds a bucket of
+// its weight that refills evenly over the window; zero weights are not enforced.
+type Limits struct {
+	KeyWeight int            // Per API key, or per account for keys the venue issued
+	IPWeight  int            // Per client address, keyed or not
+	Window    time.Duration  // Time for an empty bucket to refill
+	Weights   map[string]int // Per endpoint, "GET /api/v1/trades/:symbol"; others weigh 1
+}
+
+type limitsJSON struct {
+	KeyWeight int            `json:"key_weight"`
+	IPWeight  int            `json:"ip_weight"`
+	Window    string         `json:"window"`
+	Weights   map[string]int `json:"weights"`
+}
+
+// MarshalJSON writes the window as a duration string, "1m0s"
+func (l Limits) MarshalJSON() ([]byte, error) {
+	weights := l.Weights
+	if weights == nil {
+		weights = map[string]int{}
+	}
+	return json.Marshal(limitsJSON{KeyWeight: l.KeyWeight, IPWeight: l.IPWeight, Window: l.Window.String(), Weights: weights})
+}
+
+// UnmarshalJSON reads the window as a duration string; an empty one is DefaultWindow
+func (l *Limits) UnmarshalJSON(data []byte) error {
+	var raw limitsJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	window := DefaultWindow
+	if raw.Window != "" {
+		parsed, err := time.ParseDuration(raw.Window)
+		if err != nil {
+			return fmt.Errorf("invalid window %q: %w", raw.Window, err)
+		}
+		window = parsed
+	}
+	*l = Limits{KeyWeight: raw.KeyWeight, IPWeight: raw.IPWeight, Window: window, Weights: raw.Weights}
+	return nil
+}
+
+// Validate checks the weights are not negative and the window is positive
+func (l Limits) Validate() error {
+	if l.KeyWeight < 0 || l.IPWeight < 0 {
+		return errors.New("weights must not be negative")
+	}
+	if l.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+	for endpoint, weight := range l.Weights {
+		if weight < 0 {
+			return fmt.Errorf("weight of %s must not be negative", endpoint)
+		}
+	}
+	return nil
+}
+
+// Weight is what one request to endpoint costs
+func (l Limits) Weight(endpoint string) int {
+	if weight, ok := l.Weights[endpoint]; ok {
+		return weight
+	}
+	return 1
+}
+
+// Decision is the outcome of one request against its tightest bucket
+type Decision struct {
+	Allowed    bool
+	Scope      string        // Which bucket the numbers are for; empty when nothing is enforced
+	Limit      int           // The bucket's capacity
+	Remaining  int           // Whole weight left after this request
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the request's weight is available; zero when allowed
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter keeps a token bucket per API key and per client address
+type Limiter struct {
+	limits  Limits
+	buckets map[string]*bucket
+	mu      sync.Mutex
+}
+
+func NewLimiter(limits Limits) *Limiter {
+	if limits.Window <= 0 {
+		limits.Window = DefaultWindow
+	}
+	return &Limiter{limits: limits, buckets: make(map[string]*bucket)}
+}
+
+// Limits returns the limits in force
+func (l *Limiter) Limits() Limits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limits
+}
+
+// SetLimits replaces the limits. Buckets keep what they hold, capped at the new
+// capacity, and refill at the new rate from now on.
+func (l *Limiter) SetLimits(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+}
+
+// Take charges a request to endpoint against key's and ip's buckets; an empty key or
+// ip is not limited. The request is admitted only when every bucket holds its
+// weight, and then is charged to all of them. A weight above a bucket's capacity is
+// charged as the capacity.
+func (l *Limiter) Take(key, ip, endpoint string, now time.Time) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	type charge struct {
+		scope    string
+		capacity int
+		bucket   *bucket
+	}
+	charges := make([]charge, 0, 2)
+	if key != "" && l.limits.KeyWeight > 0 {
+		charges = append(charges, charge{ScopeKey, l.limits.KeyWeight, l.bucket(ScopeKey+":"+key, l.limits.KeyWeight, now)})
+	}
+	if ip != "" && l.limits.IPWeight > 0 {
+		charges = append(charges, charge{ScopeIP, l.limits.IPWeight, l.bucket(ScopeIP+":"+ip, l.limits.IPWeight, now)})
+	}
+	if len(charges) == 0 {
+		return Decision{Allowed: true}
+	}
+
+	weight := l.limits.Weight(endpoint)
+	var denied *charge
+	var retryAfter time.Duration
+	for i := range charges {
+		c := &charges[i]
+		need := math.Min(float64(weight), float64(c.capacity))
+		if c.bucket.tokens >= need {
+			continue
+		}
+		if wait := l.refillTime(need-c.bucket.tokens, c.capacity); wait > retryAfter {
+			denied, retryAfter = c, wait
+		}
+	}
+	if denied != nil {
+		return l.decision(denied.scope, denied.capacity, denied.bucket, false, retryAfter)
+	}
+
+	tightest := &charges[0]
+	for i := range charges {
+		c := &charges[i]
+		c.bucket.tokens = math.Max(0, c.bucket.tokens-math.Min(float64(weight), float64(c.capacity)))
+		if c.bucket.tokens < tightest.bucket.tokens {
+			tightest = c
+		}
+	}
+	return l.decision(tightest.scope, tightest.capacity, tightest.bucket, true, 0)
+}
+
+// bucket returns the named bucket refilled up to now, starting new ones full
+func (l *Limiter) bucket(name string, capacity int, now time.Time) *bucket {
+	b, ok := l.buckets[name]
+	if !ok {
+		if len(l.buckets) >= sweepThreshold {
+			l.sweep(now)
+		}
+		b = &bucket{tokens: float64(capacity), updated: now}
+		l.buckets[name] = b
+		return b
+	}
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += float64(capacity) * elapsed.Seconds() / l.limits.Window.Seconds()
+		b.updated = now
+	}
+	b.tokens = math.Min(b.tokens, float64(capacity))
+	return b
+}
+
+// sweep drops buckets that would have refilled by now; they start full again anyway
+func (l *Limiter) sweep(now time.Time) {
+	for name, b := range l.buckets {
+		if now.Sub(b.updated) >= l.limits.Window {
+			delete(l.buckets, name)
+		}
+	}
+}
+
+// refillTime is how long a bucket of capacity takes to gain tokens
+func (l *Limiter) refillTime(tokens float64, capacity int) time.Duration {
+	return time.Duration(math.Ceil(tokens / float64(capacity) * float64(l.limits.Window)))
+}
+
+func (l *Limiter) decision(scope string, capacity int, b *bucket, allowed bool, retryAfter time.Duration) Decision {
+	return Decision{
+		Allowed:    allowed,
+		Scope:      scope,
+		Limit:      capacity,
+		Remaining:  int(math.Floor(b.tokens)),
+		Reset:      l.refillTime(float64(capacity)-b.tokens, capacity),
+		RetryAfter: retryAfter,
+	}
+}
+
+// ParseWeights reads endpoint weights in the RATE_LIMIT_WEIGHTS form
+// "endpoint=weight,...", where an endpoint is an HTTP method and route,
+// "GET /api/v1/trades/:symbol", or a gRPC full method name
+func ParseWeights(spec string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		endpoint, value, ok := strings.Cut(entry, "=")
+		endpoint = strings.Join(strings.Fields(endpoint), " ")
+		if !ok || endpoint == "" {
+			return nil, fmt.Errorf("invalid endpoint weight %q: expected endpoint=weight", entry)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in %q", entry)
+		}
+		weights[endpoint] = weight
+	}
+	return weights, nil
+}
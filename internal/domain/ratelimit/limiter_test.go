@@ -0,0 +1,90 @@
+//go:build unit
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("charges_endpoint_weights_and_refills_over_the_window", func(t *testing.T) {
+		// Given: Keys may spend 10 per minute and trade history weighs 4
+		limiter := NewLimiter(Limits{KeyWeight: 10, Window: time.Minute, Weights: map[string]int{"GET /api/v1/trades/:symbol": 4}})
+
+		// When: The key reads trades twice, then once more
+		first := limiter.Take("bot", "", "GET /api/v1/trades/:symbol", start)
+		second := limiter.Take("bot", "", "GET /api/v1/trades/:symbol", start)
+		third := limiter.Take("bot", "", "GET /api/v1/trades/:symbol", start)
+
+		// Then: The third is refused until two more tokens have refilled
+		if !first.Allowed || !second.Allowed || second.Remaining != 2 || second.Limit != 10 || second.Scope != ScopeKey {
+			t.Fatalf("Expected two reads admitted leaving 2, got %+v then %+v", first, second)
+		}
+		if third.Allowed || third.RetryAfter != 12*time.Second || third.Reset != 48*time.Second {
+			t.Errorf("Expected a refusal retrying after 12s, got %+v", third)
+		}
+		if later := limiter.Take("bot", "", "GET /api/v1/trades/:symbol", start.Add(12*time.Second)); !later.Allowed || later.Remaining != 0 {
+			t.Errorf("Expected the read admitted once refilled, got %+v", later)
+		}
+		if other := limiter.Take("other", "", "POST /api/v1/orders", start); !other.Allowed || other.Remaining != 9 {
+			t.Errorf("Expected another key to have its own bucket, got %+v", other)
+		}
+	})
+
+	t.Run("admits_only_when_key_and_address_both_have_weight", func(t *testing.T) {
+		// Given: A generous key limit and a tight per-address limit
+		limiter := NewLimiter(Limits{KeyWeight: 100, IPWeight: 2, Window: time.Minute})
+
+		// When: Two keys share one address, and an anonymous caller uses another
+		limiter.Take("bot-1", "10.0.0.1", "GET /api/v1/book/:symbol", start)
+		shared := limiter.Take("bot-2", "10.0.0.1", "GET /api/v1/book/:symbol", start)
+		refused := limiter.Take("bot-1", "10.0.0.1", "GET /api/v1/book/:symbol", start)
+		anonymous := limiter.Take("", "10.0.0.2", "GET /api/v1/book/:symbol", start)
+
+		// Then: The address runs out first and is reported as the tighter bucket
+		if !shared.Allowed || shared.Scope != ScopeIP || shared.Remaining != 0 {
+			t.Errorf("Expected the address bucket reported, got %+v", shared)
+		}
+		if refused.Allowed || refused.Scope != ScopeIP {
+			t.Errorf("Expected the address to be refused, got %+v", refused)
+		}
+		if !anonymous.Allowed || anonymous.Limit != 2 {
+			t.Errorf("Expected an anonymous caller limited by address, got %+v", anonymous)
+		}
+
+		// And: The refused request was not charged to the key
+		if key := limiter.Take("bot-1", "", "GET /api/v1/book/:symbol", start); key.Remaining != 98 {
+			t.Errorf("Expected only the two admitted requests charged to bot-1, got %+v", key)
+		}
+	})
+
+	t.Run("nothing_is_enforced_without_weights", func(t *testing.T) {
+		limiter := NewLimiter(Limits{})
+
+		decision := limiter.Take("bot", "10.0.0.1", "POST /api/v1/orders", start)
+
+		if !decision.Allowed || decision.Scope != "" {
+			t.Errorf("Expected an unlimited decision, got %+v", decision)
+		}
+	})
+
+	t.Run("reads_limits_as_json_and_weights_from_the_environment", func(t *testing.T) {
+		var limits Limits
+		err := json.Unmarshal([]byte(`{"key_weight": 1200, "window": "30s", "weights": {"GET /api/v1/trades/:symbol": 5}}`), &limits)
+		if err != nil || limits.KeyWeight != 1200 || limits.Window != 30*time.Second || limits.Weight("GET /api/v1/trades/:symbol") != 5 || limits.Weight("POST /api/v1/orders") != 1 {
+			t.Fatalf("Unexpected limits: %+v, %v", limits, err)
+		}
+
+		weights, err := ParseWeights("GET  /api/v1/trades/:symbol=5, /exchange.v1.TradingService/PlaceOrder=2")
+		if err != nil || weights["GET /api/v1/trades/:symbol"] != 5 || weights["/exchange.v1.TradingService/PlaceOrder"] != 2 {
+			t.Errorf("Unexpected weights: %v, %v", weights, err)
+		}
+		if _, err := ParseWeights("GET /api/v1/orders"); err == nil {
+			t.Error("Expected an entry without a weight to be rejected")
+		}
+	})
+}
@@ -0,0 +1,257 @@
+// Package replay resubmits a captured order flow to the live venue, at its original
+// pace or faster, so matching changes can be regression-tested under realistic load.
+package replay
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var (
+	ErrNoReplay       = errors.New("no order flow replay started")
+	ErrEmptyRecording = errors.New("recording holds no order entry")
+)
+
+// Kind is the order entry request an entry captured
+type Kind string
+
+const (
+	KindPlace  Kind = "place"
+	KindCancel Kind = "cancel"
+	KindAmend  Kind = "amend"
+)
+
+// Entry is one captured order entry request
+type Entry struct {
+	Time    time.Time
+	Kind    Kind
+	OrderID string                // place: the ID the recorded venue gave the order; cancel and amend: the order acted on
+	Order   models.Order          // place
+	Amend   matching.AmendRequest // amend
+}
+
+// Recording is a captured order flow in time order
+type Recording struct {
+	Entries []Entry
+}
+
+// NewRecording orders entries by time, keeping the captured order for entries made
+// together
+func NewRecording(entries []Entry) (Recording, error) {
+	if len(entries) == 0 {
+		return Recording{}, ErrEmptyRecording
+	}
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return Recording{Entries: sorted}, nil
+}
+
+// FromJournal takes the order entry out of a matching engine event journal. Book
+// listings, halts, auctions and the other engine events are left to the live venue.
+func FromJournal(events []matching.Event) (Recording, error) {
+	entries := make([]Entry, 0, len(events))
+	for _, event := range events {
+		switch event.Type {
+		case matching.EventOrderSubmitted:
+			if event.Order == nil {
+				continue
+			}
+			entries = append(entries, Entry{Time: event.Time, Kind: KindPlace, OrderID: event.Order.ID, Order: *event.Order})
+		case matching.EventOrderCanceled:
+			entries = append(entries, Entry{Time: event.Time, Kind: KindCancel, OrderID: event.OrderID})
+		case matching.EventOrderAmended:
+			if event.Amend == nil {
+				continue
+			}
+			entries = append(entries, Entry{Time: event.Time, Kind: KindAmend, OrderID: event.OrderID, Amend: *event.Amend})
+		}
+	}
+	return NewRecording(entries)
+}
+
+// Offset is how long after the first entry the entry at index was made
+func (r Recording) Offset(index int) time.Duration {
+	return r.Entries[index].Time.Sub(r.Entries[0].Time)
+}
+
+// State is where a replay has got to
+type State string
+
+const (
+	StateRunning   State = "running"
+	StateCompleted State = "completed" // Every entry was resubmitted, some perhaps refused
+	StateStopped   State = "stopped"   // Stopped before its last entry
+)
+
+// Outcome is what resubmitting one entry came to
+type Outcome struct {
+	LiveOrderID string        // place: the ID the live venue gave the order
+	Trades      int           // Trades the request produced
+	Duplicate   bool          // place: the client order ID was already used on the live venue
+	Rejection   string        // Reason the live venue refused the request; empty when accepted
+	Skipped     bool          // cancel and amend: the replay never placed the order acted on
+	Behind      time.Duration // How late against its schedule the entry was resubmitted
+}
+
+// Progress is a replay's state and what the live venue made of the flow so far
+type Progress struct {
+	Source     string         `json:"source"`
+	Speed      float64        `json:"speed"` // Multiple of the recorded pace; 0 = as fast as possible
+	State      State          `json:"state"`
+	StartedAt  time.Time      `json:"started_at"`
+	EndedAt    *time.Time     `json:"ended_at,omitempty"`
+	Entries    int            `json:"entries"`
+	Span       string         `json:"span"` // Recorded time from the first entry to the last
+	Replayed   int            `json:"replayed"`
+	Placed     int            `json:"placed"`
+	Canceled   int            `json:"canceled"`
+	Amended    int            `json:"amended"`
+	Duplicates int            `json:"duplicates"`
+	Rejected   int            `json:"rejected"`
+	Skipped    int            `json:"skipped"`
+	Trades     int            `json:"trades"`
+	Rejections map[string]int `json:"rejections"` // By reason
+	MaxBehind  string         `json:"max_behind"` // Latest any entry was resubmitted against its schedule
+}
+
+// Run tracks one replay of a recording, mapping the recorded order IDs to the IDs
+// the live venue gives the same orders
+type Run struct {
+	recording Recording
+	source    string
+	speed     float64
+	started   time.Time
+	ended     *time.Time
+	stopped   bool
+	replayed  int
+	liveIDs   map[string]string
+	progress  Progress
+	maxBehind time.Duration
+	mu        sync.Mutex
+}
+
+func NewRun(recording Recording, source string, speed float64, start time.Time) *Run {
+	return &Run{
+		recording: recording,
+		source:    source,
+		speed:     speed,
+		started:   start,
+		liveIDs:   make(map[string]string),
+		progress:  Progress{Rejections: make(map[string]int)},
+	}
+}
+
+// Recording is the flow being replayed
+func (r *Run) Recording() Recording {
+	return r.recording
+}
+
+// Due is how long after the replay starts the entry at index is resubmitted
+func (r *Run) Due(index int) time.Duration {
+	if r.speed == 0 {
+		return 0
+	}
+	return time.Duration(float64(r.recording.Offset(index)) / r.speed)
+}
+
+// Lifetime scales how long an order was recorded to live by the replay's speed
+func (r *Run) Lifetime(recorded time.Duration) time.Duration {
+	if r.speed == 0 {
+		return recorded
+	}
+	return time.Duration(float64(recorded) / r.speed)
+}
+
+// LiveOrderID is the ID the live venue gave the order recorded as recorded
+func (r *Run) LiveOrderID(recorded string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	live, ok := r.liveIDs[recorded]
+	return live, ok
+}
+
+// Record notes what resubmitting the entry at index came to; the run completes once
+// every entry is recorded
+func (r *Run) Record(index int, outcome Outcome, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.recording.Entries[index]
+	r.replayed++
+	p := &r.progress
+	p.Trades += outcome.Trades
+	if outcome.Behind > r.maxBehind {
+		r.maxBehind = outcome.Behind
+	}
+	switch {
+	case outcome.Skipped:
+		p.Skipped++
+	case outcome.Rejection != "":
+		p.Rejected++
+		p.Rejections[outcome.Rejection]++
+	case outcome.Duplicate:
+		p.Duplicates++
+	case entry.Kind == KindPlace:
+		p.Placed++
+		r.liveIDs[entry.OrderID] = outcome.LiveOrderID
+	case entry.Kind == KindCancel:
+		p.Canceled++
+	case entry.Kind == KindAmend:
+		p.Amended++
+	}
+	if r.replayed == len(r.recording.Entries) && r.ended == nil {
+		r.ended = &now
+	}
+}
+
+// Stop ends the run before its remaining entries; it reports whether the run was
+// still going
+func (r *Run) Stop(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ended != nil {
+		return false
+	}
+	r.stopped = true
+	r.ended = &now
+	return true
+}
+
+// Done reports whether the run has completed or been stopped
+func (r *Run) Done() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ended != nil
+}
+
+// Progress reports the run so far
+func (r *Run) Progress() Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress := r.progress
+	progress.Source = r.source
+	progress.Speed = r.speed
+	progress.StartedAt = r.started
+	progress.EndedAt = r.ended
+	progress.Entries = len(r.recording.Entries)
+	progress.Span = r.recording.Offset(len(r.recording.Entries) - 1).String()
+	progress.Replayed = r.replayed
+	progress.MaxBehind = r.maxBehind.String()
+	progress.Rejections = make(map[string]int, len(r.progress.Rejections))
+	for reason, count := range r.progress.Rejections {
+		progress.Rejections[reason] = count
+	}
+	switch {
+	case r.stopped:
+		progress.State = StateStopped
+	case r.ended != nil:
+		progress.State = StateCompleted
+	default:
+		progress.State = StateRunning
+	}
+	return progress
+}
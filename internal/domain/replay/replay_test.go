@@ -0,0 +1,70 @@
+//go:build unit
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestRecording(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("takes_order_entry_out_of_a_journal", func(t *testing.T) {
+		// Given: A journal listing a book, then placing, amending and canceling an order
+		order := &models.Order{ID: "BTC-USD-1", AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Quantity: 1, Price: 60000}
+		events := []matching.Event{
+			{Sequence: 1, Type: matching.EventBookAdded, Time: start, Symbol: "BTC-USD"},
+			{Sequence: 2, Type: matching.EventOrderSubmitted, Time: start.Add(time.Second), Order: order},
+			{Sequence: 3, Type: matching.EventOrderCanceled, Time: start.Add(4 * time.Second), OrderID: "BTC-USD-1"},
+			{Sequence: 4, Type: matching.EventOrderAmended, Time: start.Add(3 * time.Second), OrderID: "BTC-USD-1", Amend: &matching.AmendRequest{Price: 60100}},
+		}
+
+		// When: The order entry is taken out
+		recording, err := FromJournal(events)
+
+		// Then: The three requests are kept in time order, spanning three seconds
+		if err != nil || len(recording.Entries) != 3 {
+			t.Fatalf("Expected three entries, got %+v, %v", recording.Entries, err)
+		}
+		kinds := [3]Kind{recording.Entries[0].Kind, recording.Entries[1].Kind, recording.Entries[2].Kind}
+		if kinds != [3]Kind{KindPlace, KindAmend, KindCancel} || recording.Offset(2) != 3*time.Second {
+			t.Errorf("Expected place, amend, cancel over 3s, got %v over %v", kinds, recording.Offset(2))
+		}
+		if _, err := FromJournal(events[:1]); err != ErrEmptyRecording {
+			t.Errorf("Expected a journal without orders refused, got %v", err)
+		}
+	})
+
+	t.Run("follows_live_order_ids_and_tallies_outcomes", func(t *testing.T) {
+		// Given: A recording replayed at ten times its pace
+		recording, _ := NewRecording([]Entry{
+			{Time: start, Kind: KindPlace, OrderID: "BTC-USD-1"},
+			{Time: start.Add(10 * time.Second), Kind: KindPlace, OrderID: "BTC-USD-2"},
+			{Time: start.Add(20 * time.Second), Kind: KindCancel, OrderID: "BTC-USD-1"},
+		})
+		run := NewRun(recording, "capture.jsonl", 10, start)
+
+		// When: The first order is placed under a new ID and the second refused
+		run.Record(0, Outcome{LiveOrderID: "BTC-USD-7", Trades: 2}, start)
+		run.Record(1, Outcome{Rejection: "INSUFFICIENT_BALANCE", Behind: time.Millisecond}, start)
+		live, placed := run.LiveOrderID("BTC-USD-1")
+		_, refused := run.LiveOrderID("BTC-USD-2")
+		running := run.Progress()
+		run.Record(2, Outcome{}, start.Add(2*time.Second))
+
+		// Then: The cancel is due after 2s against the live ID, and the run completes
+		if !placed || live != "BTC-USD-7" || refused || run.Due(2) != 2*time.Second {
+			t.Errorf("Expected BTC-USD-1 live as BTC-USD-7 and the cancel due at 2s, got %q and %v", live, run.Due(2))
+		}
+		if running.State != StateRunning || running.Rejections["INSUFFICIENT_BALANCE"] != 1 || running.Trades != 2 || running.MaxBehind != "1ms" {
+			t.Errorf("Expected a running tally, got %+v", running)
+		}
+		if done := run.Progress(); done.State != StateCompleted || done.Placed != 1 || done.Canceled != 1 || run.Stop(start) {
+			t.Errorf("Expected the run completed, got %+v", done)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+// Package runbundle describes a simulation run well enough to reproduce it: a
+// manifest written when the run starts, and an archive of the manifest, the engine's
+// event journal and the final metrics written when it ends.
+package runbundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+)
+
+// Names of the archive members
+const (
+	ManifestName = "manifest.json"
+	JournalName  = "journal.jsonl" // One matching engine event per line, in sequence order
+	MetricsName  = "metrics.json"
+	FilesDir     = "files" // Copies of the scenario files, by base name
+)
+
+var (
+	ErrNotStarted = errors.New("run has not started")
+	ErrNoJournal  = errors.New("run has no event journal to bundle")
+	ErrNoManifest = errors.New("run bundle has no manifest")
+)
+
+// Manifest pins down everything a run's outcome depends on besides the orders sent to it
+type Manifest struct {
+	RunID          string              `json:"run_id"`
+	Instance       string              `json:"instance"`
+	StartedAt      time.Time           `json:"started_at"` // Venue clock
+	GitSHA         string              `json:"git_sha"`    // Empty when the build recorded none
+	ServiceVersion string              `json:"service_version"`
+	Config         map[string]string   `json:"config"`         // Every setting, secrets redacted
+	Seed           int64               `json:"seed,omitempty"` // Synthetic market seed actually used (0 = none)
+	Files          []File              `json:"files"`          // Scenario files the run read
+	Instruments    []models.Instrument `json:"instruments"`
+}
+
+// File is an input file as the run read it
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// NewFile fingerprints the contents read from path
+func NewFile(path string, data []byte) File {
+	sum := sha256.Sum256(data)
+	return File{Path: path, SHA256: hex.EncodeToString(sum[:]), Size: len(data)}
+}
+
+// Bundle is the archive written when a run ends
+type Bundle struct {
+	Manifest Manifest
+	Journal  []matching.Event
+	Metrics  runmetrics.Snapshot
+	Files    map[string][]byte // Scenario file contents keyed by manifest path
+}
+
+// Write archives the bundle as a gzipped tar. Members are written in a fixed order
+// and stamped with the run's start time, so the same run always archives to the
+// same bytes.
+func Write(w io.Writer, bundle Bundle) error {
+	members := make([]member, 0, 3+len(bundle.Files))
+
+	manifest, err := json.MarshalIndent(bundle.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	members = append(members, member{ManifestName, append(manifest, '\n')})
+
+	var journal bytes.Buffer
+	encoder := json.NewEncoder(&journal)
+	for _, event := range bundle.Journal {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode journal event %d: %w", event.Sequence, err)
+		}
+	}
+	members = append(members, member{JournalName, journal.Bytes()})
+
+	metrics, err := json.MarshalIndent(bundle.Metrics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	members = append(members, member{MetricsName, append(metrics, '\n')})
+
+	paths := make([]string, 0, len(bundle.Files))
+	for filePath := range bundle.Files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		members = append(members, member{path.Join(FilesDir, path.Base(filePath)), bundle.Files[filePath]})
+	}
+
+	zipped := gzip.NewWriter(w)
+	zipped.ModTime = bundle.Manifest.StartedAt
+	archive := tar.NewWriter(zipped)
+	for _, m := range members {
+		header := &tar.Header{
+			Name:    m.name,
+			Mode:    0o644,
+			Size:    int64(len(m.data)),
+			ModTime: bundle.Manifest.StartedAt,
+			Format:  tar.FormatPAX,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", m.name, err)
+		}
+		if _, err := archive.Write(m.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", m.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	return zipped.Close()
+}
+
+// Read opens an archive written by Write. Scenario files come back keyed by their
+// manifest path.
+func Read(r io.Reader) (Bundle, error) {
+	zipped, err := gzip.NewReader(r)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("failed to open run bundle: %w", err)
+	}
+	defer zipped.Close()
+
+	bundle := Bundle{Journal: make([]matching.Event, 0), Files: make(map[string][]byte)}
+	contents := make(map[string][]byte)
+	found := false
+	archive := tar.NewReader(zipped)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to read run bundle: %w", err)
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		switch header.Name {
+		case ManifestName:
+			if err := json.Unmarshal(data, &bundle.Manifest); err != nil {
+				return Bundle{}, fmt.Errorf("failed to decode manifest: %w", err)
+			}
+			found = true
+		case JournalName:
+			scanner := bufio.NewScanner(bytes.NewReader(data))
+			scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+			for scanner.Scan() {
+				var event matching.Event
+				if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+					return Bundle{}, fmt.Errorf("failed to decode journal event: %w", err)
+				}
+				bundle.Journal = append(bundle.Journal, event)
+			}
+			if err := scanner.Err(); err != nil {
+				return Bundle{}, fmt.Errorf("failed to read journal: %w", err)
+			}
+		case MetricsName:
+			if err := json.Unmarshal(data, &bundle.Metrics); err != nil {
+				return Bundle{}, fmt.Errorf("failed to decode metrics: %w", err)
+			}
+		default:
+			contents[header.Name] = data
+		}
+	}
+	if !found {
+		return Bundle{}, ErrNoManifest
+	}
+
+	for _, file := range bundle.Manifest.Files {
+		if data, ok := contents[path.Join(FilesDir, path.Base(file.Path))]; ok {
+			bundle.Files[file.Path] = data
+		}
+	}
+	return bundle, nil
+}
+
+type member struct {
+	name string
+	data []byte
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+package runbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+)
+
+func TestBundle(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	scenario := []byte(`{"name":"smoke"}`)
+	bundle := Bundle{
+		Manifest: Manifest{
+			RunID:       "run-1",
+			StartedAt:   started,
+			GitSHA:      "abc123",
+			Config:      map[string]string{"SyntheticSeed": "0", "PostgresURL": "[redacted]"},
+			Seed:        42,
+			Files:       []File{NewFile("/scenarios/smoke.json", scenario)},
+			Instruments: []models.Instrument{{Symbol: "BTC-USD", BaseAsset: "BTC", QuoteAsset: "USD"}},
+		},
+		Journal: []matching.Event{
+			{Sequence: 1, Type: matching.EventBookAdded, Time: started, Symbol: "BTC-USD", ReferencePrice: 60000},
+			{Sequence: 2, Type: matching.EventOrderCanceled, Time: started.Add(time.Second), Symbol: "BTC-USD", OrderID: "ord-BTC-USD-1"},
+		},
+		Metrics: runmetrics.Snapshot{RunID: "run-1", TakenAt: started.Add(time.Minute)},
+		Files:   map[string][]byte{"/scenarios/smoke.json": scenario},
+	}
+
+	t.Run("reads_back_what_was_written", func(t *testing.T) {
+		// Given: An archived run
+		var archive bytes.Buffer
+		if err := Write(&archive, bundle); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: It is read back
+		read, err := Read(&archive)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The manifest, journal, metrics and scenario file all survive
+		if read.Manifest.Seed != 42 || read.Manifest.GitSHA != "abc123" || len(read.Manifest.Instruments) != 1 {
+			t.Errorf("Unexpected manifest: %+v", read.Manifest)
+		}
+		if len(read.Journal) != 2 || read.Journal[1].OrderID != "ord-BTC-USD-1" || !read.Journal[0].Time.Equal(started) {
+			t.Errorf("Unexpected journal: %+v", read.Journal)
+		}
+		if read.Metrics.RunID != "run-1" || !bytes.Equal(read.Files["/scenarios/smoke.json"], scenario) {
+			t.Errorf("Unexpected metrics or files: %+v %q", read.Metrics, read.Files)
+		}
+		if read.Manifest.Files[0] != NewFile("/scenarios/smoke.json", scenario) {
+			t.Errorf("Expected the scenario fingerprint to match its contents, got %+v", read.Manifest.Files[0])
+		}
+	})
+
+	t.Run("archives_the_same_run_to_the_same_bytes", func(t *testing.T) {
+		// Given: The same run archived twice
+		var first, second bytes.Buffer
+		if err := Write(&first, bundle); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := Write(&second, bundle); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The archives are identical
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Error("Expected identical archives")
+		}
+	})
+
+	t.Run("rejects_an_archive_without_a_manifest", func(t *testing.T) {
+		// Given: A gzipped tar holding only a journal
+		var archive bytes.Buffer
+		zipped := gzip.NewWriter(&archive)
+		writer := tar.NewWriter(zipped)
+		journal := []byte("{}\n")
+		if err := writer.WriteHeader(&tar.Header{Name: JournalName, Mode: 0o644, Size: int64(len(journal))}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		writer.Write(journal)
+		writer.Close()
+		zipped.Close()
+
+		// When: It is read
+		_, err := Read(&archive)
+
+		// Then: It is not taken for a run bundle
+		if !errors.Is(err, ErrNoManifest) {
+			t.Errorf("Expected ErrNoManifest, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package runmetrics
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrNotPersisted is returned by queries when no snapshot store is configured
+var ErrNotPersisted = errors.New("metrics snapshots are not persisted")
+
+// maxLatencySamples bounds the placements kept per interval; beyond it a uniform
+// reservoir keeps the percentiles representative
+const maxLatencySamples = 10000
+
+// Snapshot is the venue's business metrics at one point of a scenario run. Counts
+// and volumes are totals since the venue started; latency covers the order
+// placements since the previous snapshot.
+type Snapshot struct {
+	RunID         string            `json:"run_id"`
+	Instance      string            `json:"instance"`
+	TakenAt       time.Time         `json:"taken_at"`
+	Orders        int64             `json:"orders"`
+	Cancels       int64             `json:"cancels"`
+	Amends        int64             `json:"amends"`
+	Trades        int64             `json:"trades"`
+	Rejections    int64             `json:"rejections"`
+	RejectionRate float64           `json:"rejection_rate"` // Rejections per order action
+	Volumes       map[string]Volume `json:"volumes"`        // By symbol
+	OrderLatency  Latency           `json:"order_latency"`
+}
+
+// Volume is what traded on one symbol
+type Volume struct {
+	Trades   int64   `json:"trades"`
+	Quantity float64 `json:"quantity"`
+	Notional float64 `json:"notional"`
+}
+
+// Latency summarizes order placement times in milliseconds
+type Latency struct {
+	Count int64   `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// Counts are the order action totals a snapshot reports
+type Counts struct {
+	Orders     int64
+	Cancels    int64
+	Amends     int64
+	Trades     int64
+	Rejections int64
+}
+
+// Run summarizes the snapshots saved under one run ID
+type Run struct {
+	RunID     string    `json:"run_id"`
+	Instance  string    `json:"instance"`
+	FirstAt   time.Time `json:"first_at"`
+	LastAt    time.Time `json:"last_at"`
+	Snapshots int       `json:"snapshots"`
+}
+
+// Query selects a run's snapshots, oldest first; zero bounds are open
+type Query struct {
+	RunID string
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// Store persists snapshots for analysis after the run
+type Store interface {
+	Save(snapshot Snapshot) error
+	Snapshots(query Query) ([]Snapshot, error)
+	Runs() ([]Run, error)
+}
+
+// Collector accumulates traded volume and placement latency between snapshots
+type Collector struct {
+	volumes  map[string]Volume
+	samples  []float64 // Milliseconds, this interval
+	observed int64     // Placements this interval, including those not sampled
+	max      float64
+	random   *rand.Rand
+	mu       sync.Mutex
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		volumes: make(map[string]Volume),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ObserveOrder records how long one order placement took
+func (c *Collector) ObserveOrder(latency time.Duration) {
+	ms := float64(latency) / float64(time.Millisecond)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observed++
+	if ms > c.max {
+		c.max = ms
+	}
+	if len(c.samples) < maxLatencySamples {
+		c.samples = append(c.samples, ms)
+		return
+	}
+	if i := c.random.Int63n(c.observed); i < maxLatencySamples {
+		c.samples[i] = ms
+	}
+}
+
+// ObserveTrades adds executions to the traded volume
+func (c *Collector) ObserveTrades(trades ...models.Trade) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, trade := range trades {
+		volume := c.volumes[trade.Symbol]
+		volume.Trades++
+		volume.Quantity += trade.Quantity
+		volume.Notional += trade.Notional()
+		c.volumes[trade.Symbol] = volume
+	}
+}
+
+// Current builds a snapshot without starting a new latency interval
+func (c *Collector) Current(at time.Time, counts Counts) Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapshot(at, counts)
+}
+
+// Take builds a snapshot and starts the next latency interval
+func (c *Collector) Take(at time.Time, counts Counts) Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := c.snapshot(at, counts)
+	c.samples = c.samples[:0]
+	c.observed = 0
+	c.max = 0
+	return snapshot
+}
+
+// snapshot fills everything but the run identity; callers hold mu
+func (c *Collector) snapshot(at time.Time, counts Counts) Snapshot {
+	volumes := make(map[string]Volume, len(c.volumes))
+	for symbol, volume := range c.volumes {
+		volumes[symbol] = volume
+	}
+	snapshot := Snapshot{
+		TakenAt:    at,
+		Orders:     counts.Orders,
+		Cancels:    counts.Cancels,
+		Amends:     counts.Amends,
+		Trades:     counts.Trades,
+		Rejections: counts.Rejections,
+		Volumes:    volumes,
+		OrderLatency: Latency{
+			Count: c.observed,
+			MaxMs: c.max,
+		},
+	}
+	if actions := counts.Orders + counts.Cancels + counts.Amends + counts.Rejections; actions > 0 {
+		snapshot.RejectionRate = float64(counts.Rejections) / float64(actions)
+	}
+	if len(c.samples) > 0 {
+		sorted := append([]float64(nil), c.samples...)
+		sort.Float64s(sorted)
+		snapshot.OrderLatency.P50Ms = percentile(sorted, 0.50)
+		snapshot.OrderLatency.P95Ms = percentile(sorted, 0.95)
+		snapshot.OrderLatency.P99Ms = percentile(sorted, 0.99)
+	}
+	return snapshot
+}
+
+// percentile uses the nearest-rank method on sorted samples
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
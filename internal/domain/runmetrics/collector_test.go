@@ -0,0 +1,73 @@
+//go:build unit
+
+package runmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("summarizes_volume_latency_and_rejections", func(t *testing.T) {
+		// Given: A hundred placements taking 1ms to 100ms and two trades on one symbol
+		collector := NewCollector()
+		for i := 1; i <= 100; i++ {
+			collector.ObserveOrder(time.Duration(i) * time.Millisecond)
+		}
+		collector.ObserveTrades(
+			models.Trade{Symbol: "BTC-USD", Price: 60000, Quantity: 0.5},
+			models.Trade{Symbol: "BTC-USD", Price: 60010, Quantity: 1},
+		)
+
+		// When: A snapshot is taken with one rejection in four order actions
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		snapshot := collector.Take(at, Counts{Orders: 2, Cancels: 1, Rejections: 1, Trades: 2})
+
+		// Then: Volumes, nearest-rank percentiles and the rejection rate are reported
+		volume := snapshot.Volumes["BTC-USD"]
+		if volume.Trades != 2 || volume.Quantity != 1.5 || volume.Notional != 90010 {
+			t.Errorf("Unexpected volume: %+v", volume)
+		}
+		latency := snapshot.OrderLatency
+		if latency.Count != 100 || latency.P50Ms != 50 || latency.P95Ms != 95 || latency.P99Ms != 99 || latency.MaxMs != 100 {
+			t.Errorf("Unexpected latency: %+v", latency)
+		}
+		if snapshot.RejectionRate != 0.25 || !snapshot.TakenAt.Equal(at) {
+			t.Errorf("Expected a 0.25 rejection rate at %s, got %+v", at, snapshot)
+		}
+	})
+
+	t.Run("take_starts_a_new_latency_interval_but_keeps_volume", func(t *testing.T) {
+		// Given: A snapshot already taken over one placement and trade
+		collector := NewCollector()
+		collector.ObserveOrder(7 * time.Millisecond)
+		collector.ObserveTrades(models.Trade{Symbol: "ETH-USD", Price: 3000, Quantity: 2})
+		collector.Take(time.Now(), Counts{})
+
+		// When: The metrics are read again
+		current := collector.Current(time.Now(), Counts{})
+
+		// Then: Latency covers nothing new while volume is cumulative
+		if current.OrderLatency != (Latency{}) {
+			t.Errorf("Expected an empty latency interval, got %+v", current.OrderLatency)
+		}
+		if current.Volumes["ETH-USD"].Notional != 6000 || current.RejectionRate != 0 {
+			t.Errorf("Expected cumulative volume and no rejection rate, got %+v", current)
+		}
+	})
+
+	t.Run("bounds_latency_samples", func(t *testing.T) {
+		collector := NewCollector()
+		for i := 0; i < maxLatencySamples+500; i++ {
+			collector.ObserveOrder(time.Millisecond)
+		}
+		if len(collector.samples) != maxLatencySamples {
+			t.Errorf("Expected %d samples, got %d", maxLatencySamples, len(collector.samples))
+		}
+		if latency := collector.Current(time.Now(), Counts{}).OrderLatency; latency.Count != maxLatencySamples+500 {
+			t.Errorf("Expected every placement counted, got %+v", latency)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoScenario is returned when a verdict is requested but no scenario was loaded
+var ErrNoScenario = errors.New("no scenario loaded")
+
+// Kind names how an assertion judges the recorded events
+type Kind string
+
+const (
+	KindCount   Kind = "count"   // The number of matching events is between min and max
+	KindFollows Kind = "follows" // Every trigger event is followed by a matching event within a window
+)
+
+// Matcher selects events; empty fields match anything
+type Matcher struct {
+	Type      string `json:"type"`
+	Symbol    string `json:"symbol,omitempty"`
+	Account   string `json:"account,omitempty"`   // Any of the event's accounts
+	Component string `json:"component,omitempty"` // Exact, or a kind prefix ending in ':'
+	Status    string `json:"status,omitempty"`
+}
+
+// Matches reports whether event is selected
+func (m Matcher) Matches(event Event) bool {
+	if m.Type != "" && m.Type != event.Type {
+		return false
+	}
+	if m.Symbol != "" && m.Symbol != event.Symbol {
+		return false
+	}
+	if m.Status != "" && m.Status != event.Status {
+		return false
+	}
+	if m.Component != "" {
+		if strings.HasSuffix(m.Component, ":") {
+			if !strings.HasPrefix(event.Component, m.Component) {
+				return false
+			}
+		} else if m.Component != event.Component {
+			return false
+		}
+	}
+	if m.Account == "" {
+		return true
+	}
+	for _, account := range event.Accounts {
+		if account == m.Account {
+			return true
+		}
+	}
+	return false
+}
+
+// Assertion is one expectation a scenario declares about the venue's events
+type Assertion struct {
+	Name    string   `json:"name"`
+	Kind    Kind     `json:"kind"`
+	Event   Matcher  `json:"event"`
+	Trigger *Matcher `json:"trigger,omitempty"` // Follows only
+	Within  string   `json:"within,omitempty"`  // Follows only, e.g. "5s"
+	Min     int      `json:"min,omitempty"`     // Count only
+	Max     *int     `json:"max,omitempty"`     // Count only; unset is unbounded
+
+	within time.Duration
+}
+
+// Scenario is the assertions a scenario file declares
+type Scenario struct {
+	Name       string      `json:"name"`
+	Assertions []Assertion `json:"assertions"`
+}
+
+// Parse reads a scenario file's JSON and checks every assertion is well formed
+func Parse(data []byte) (Scenario, error) {
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return Scenario{}, fmt.Errorf("invalid scenario: %w", err)
+	}
+	if len(scenario.Assertions) == 0 {
+		return Scenario{}, errors.New("invalid scenario: no assertions")
+	}
+	names := make(map[string]bool, len(scenario.Assertions))
+	for i := range scenario.Assertions {
+		assertion := &scenario.Assertions[i]
+		if assertion.Name == "" {
+			return Scenario{}, fmt.Errorf("invalid scenario: assertion %d has no name", i+1)
+		}
+		if names[assertion.Name] {
+			return Scenario{}, fmt.Errorf("invalid scenario: assertion %q declared twice", assertion.Name)
+		}
+		names[assertion.Name] = true
+		if err := assertion.validate(); err != nil {
+			return Scenario{}, fmt.Errorf("invalid scenario: assertion %q: %w", assertion.Name, err)
+		}
+	}
+	return scenario, nil
+}
+
+func (a *Assertion) validate() error {
+	switch a.Kind {
+	case KindCount:
+		if a.Min < 0 || (a.Max != nil && *a.Max < a.Min) {
+			return errors.New("min must not be negative or above max")
+		}
+	case KindFollows:
+		if a.Trigger == nil {
+			return errors.New("trigger is required")
+		}
+		within, err := time.ParseDuration(a.Within)
+		if err != nil || within <= 0 {
+			return errors.New("within must be a positive duration")
+		}
+		a.within = within
+	default:
+		return fmt.Errorf("unknown kind %q: expected count or follows", a.Kind)
+	}
+	return nil
+}
+
+// Result is the outcome of one assertion
+type Result struct {
+	Name     string `json:"name"`
+	Kind     Kind   `json:"kind"`
+	Passed   bool   `json:"passed"`
+	Observed int    `json:"observed"`          // Matching events (count) or trigger events (follows)
+	Failed   int    `json:"failed,omitempty"`  // Follows: triggers not followed in time
+	Pending  int    `json:"pending,omitempty"` // Follows: triggers whose window is still open
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Verdict is a scenario's machine-readable pass/fail report
+type Verdict struct {
+	Scenario    string    `json:"scenario"`
+	RunID       string    `json:"run_id"`
+	Instance    string    `json:"instance"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	Final       bool      `json:"final"` // Taken at scenario end rather than mid-run
+	Passed      bool      `json:"passed"`
+	Events      int       `json:"events"`
+	Dropped     int       `json:"dropped,omitempty"` // Oldest events lost to the recorder's capacity
+	Results     []Result  `json:"results"`
+}
+
+// Evaluate judges every assertion against events as of now. A follows trigger whose
+// window is still open is pending, which keeps the assertion from passing yet.
+func (s Scenario) Evaluate(events []Event, now time.Time) Verdict {
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	verdict := Verdict{Scenario: s.Name, EvaluatedAt: now, Passed: true, Events: len(sorted), Results: make([]Result, 0, len(s.Assertions))}
+	for _, assertion := range s.Assertions {
+		var result Result
+		if assertion.Kind == KindFollows {
+			result = assertion.follows(sorted, now)
+		} else {
+			result = assertion.count(sorted)
+		}
+		result.Name, result.Kind = assertion.Name, assertion.Kind
+		verdict.Passed = verdict.Passed && result.Passed
+		verdict.Results = append(verdict.Results, result)
+	}
+	return verdict
+}
+
+func (a Assertion) count(events []Event) Result {
+	var result Result
+	for _, event := range events {
+		if a.Event.Matches(event) {
+			result.Observed++
+		}
+	}
+	result.Passed = result.Observed >= a.Min && (a.Max == nil || result.Observed <= *a.Max)
+	if !result.Passed {
+		bound := fmt.Sprintf("at least %d", a.Min)
+		if a.Max != nil {
+			bound = fmt.Sprintf("between %d and %d", a.Min, *a.Max)
+		}
+		result.Detail = fmt.Sprintf("expected %s matching events, saw %d", bound, result.Observed)
+	}
+	return result
+}
+
+// follows checks each trigger against the events at or after it; events are sorted by time
+func (a Assertion) follows(events []Event, now time.Time) Result {
+	var result Result
+	var firstFailure time.Time
+	for i, trigger := range events {
+		if !a.Trigger.Matches(trigger) {
+			continue
+		}
+		result.Observed++
+		deadline := trigger.Time.Add(a.within)
+		followed := false
+		// Events at the trigger's own time may sort either side of it
+		start := sort.Search(len(events), func(j int) bool { return !events[j].Time.Before(trigger.Time) })
+		for j := start; j < len(events) && !events[j].Time.After(deadline); j++ {
+			if j != i && a.Event.Matches(events[j]) {
+				followed = true
+				break
+			}
+		}
+		switch {
+		case followed:
+		case now.Before(deadline):
+			result.Pending++
+		default:
+			if result.Failed == 0 {
+				firstFailure = trigger.Time
+			}
+			result.Failed++
+		}
+	}
+	result.Passed = result.Failed == 0 && result.Pending == 0
+	if result.Failed > 0 {
+		result.Detail = fmt.Sprintf("%d of %d triggers not followed within %s, first at %s",
+			result.Failed, result.Observed, a.within, firstFailure.Format(time.RFC3339Nano))
+	} else if result.Pending > 0 {
+		result.Detail = fmt.Sprintf("%d triggers still within %s", result.Pending, a.within)
+	}
+	return result
+}
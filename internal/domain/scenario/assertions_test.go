@@ -0,0 +1,102 @@
+//go:build unit
+
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, data string) Scenario {
+	t.Helper()
+	scenario, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Expected the scenario to parse, got %v", err)
+	}
+	return scenario
+}
+
+func TestScenario(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	halt := Event{Type: EventHealth, Time: start, Symbol: "BTC-USD", Component: "instrument:BTC-USD", Status: "halted"}
+	resume := Event{Type: EventHealth, Time: start.Add(2 * time.Second), Symbol: "BTC-USD", Component: "instrument:BTC-USD", Status: "up"}
+	trade := Event{Type: EventTrade, Time: start.Add(time.Second), Symbol: "BTC-USD", Accounts: []string{"mm-1", "taker-1"}}
+
+	t.Run("counts_matching_events", func(t *testing.T) {
+		// Given: Assertions on trades by one account and on surveillance flags
+		scenario := mustParse(t, `{"name": "smoke", "assertions": [
+			{"name": "mm trades", "kind": "count", "event": {"type": "trade", "account": "mm-1"}, "min": 1},
+			{"name": "no flags", "kind": "count", "event": {"type": "surveillance_flag"}, "max": 0}
+		]}`)
+
+		// When: A trade and a flag are judged
+		flag := Event{Type: EventSurveillanceFlag, Time: start, Accounts: []string{"taker-1"}, Status: "cancel_ratio"}
+		verdict := scenario.Evaluate([]Event{trade, flag}, start.Add(time.Minute))
+
+		// Then: The trade assertion passes and the flag assertion fails with its count
+		if verdict.Passed || !verdict.Results[0].Passed || verdict.Results[0].Observed != 1 {
+			t.Errorf("Unexpected verdict: %+v", verdict)
+		}
+		if verdict.Results[1].Passed || !strings.Contains(verdict.Results[1].Detail, "saw 1") {
+			t.Errorf("Expected the flag assertion to fail, got %+v", verdict.Results[1])
+		}
+	})
+
+	t.Run("follows_within_a_window", func(t *testing.T) {
+		// Given: Halts must be followed by a resumption within 5s
+		scenario := mustParse(t, `{"name": "halts", "assertions": [
+			{"name": "halts resume", "kind": "follows", "within": "5s",
+			 "trigger": {"type": "health", "component": "instrument:", "status": "halted"},
+			 "event": {"type": "health", "component": "instrument:", "status": "up"}}
+		]}`)
+
+		// When: One halt resumes in time, and another is judged before and after its window
+		late := halt
+		late.Time = start.Add(time.Minute)
+		followed := scenario.Evaluate([]Event{resume, halt}, start.Add(time.Minute))
+		pending := scenario.Evaluate([]Event{halt, resume, late}, late.Time.Add(time.Second))
+		failed := scenario.Evaluate([]Event{halt, resume, late}, late.Time.Add(time.Hour))
+
+		// Then: Only a closed window without a resumption fails
+		if !followed.Passed || followed.Results[0].Observed != 1 {
+			t.Errorf("Expected the halt to be followed, got %+v", followed.Results[0])
+		}
+		if pending.Passed || pending.Results[0].Pending != 1 || pending.Results[0].Failed != 0 {
+			t.Errorf("Expected one pending trigger, got %+v", pending.Results[0])
+		}
+		if failed.Passed || failed.Results[0].Failed != 1 || !strings.Contains(failed.Results[0].Detail, "1 of 2") {
+			t.Errorf("Expected one failed trigger, got %+v", failed.Results[0])
+		}
+	})
+
+	t.Run("rejects_malformed_assertions", func(t *testing.T) {
+		for _, data := range []string{
+			`{"name": "empty", "assertions": []}`,
+			`{"assertions": [{"kind": "count", "event": {}}]}`,
+			`{"assertions": [{"name": "a", "kind": "sometimes", "event": {}}]}`,
+			`{"assertions": [{"name": "a", "kind": "count", "event": {}, "min": 3, "max": 1}]}`,
+			`{"assertions": [{"name": "a", "kind": "follows", "event": {}, "within": "5s"}]}`,
+			`{"assertions": [{"name": "a", "kind": "follows", "event": {}, "trigger": {}, "within": "soon"}]}`,
+			`{"assertions": [{"name": "a", "kind": "count", "event": {}}, {"name": "a", "kind": "count", "event": {}}]}`,
+		} {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Errorf("Expected %s to be rejected", data)
+			}
+		}
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("keeps_the_latest_events", func(t *testing.T) {
+		recorder := NewRecorder(2)
+		for _, status := range []string{"new", "filled", "canceled"} {
+			recorder.Record(Event{Type: EventOrder, Status: status})
+		}
+
+		events, dropped := recorder.Events()
+		if dropped != 1 || len(events) != 2 || events[0].Status != "filled" || events[1].Status != "canceled" {
+			t.Errorf("Expected the last two events and one dropped, got %+v and %d", events, dropped)
+		}
+	})
+}
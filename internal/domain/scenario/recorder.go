@@ -0,0 +1,67 @@
+package scenario
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types recorded for assertions
+const (
+	EventOrder            = "order"             // Status is the order's new status
+	EventTrade            = "trade"             // Accounts are the buyer and the seller
+	EventHealth           = "health"            // Component changed health; Status is the new one
+	EventSurveillanceFlag = "surveillance_flag" // Status is the flag type
+	EventAPICall          = "api_call"          // Call to a deprecated or retired API; Component is "api:<version>", Status its phase
+)
+
+// Event is one thing the venue did, in the terms assertions match on
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Symbol    string    `json:"symbol,omitempty"`
+	Accounts  []string  `json:"accounts,omitempty"`
+	Component string    `json:"component,omitempty"` // Health events only
+	Status    string    `json:"status,omitempty"`
+}
+
+// Recorder keeps the latest events of a run in a ring buffer
+type Recorder struct {
+	events   []Event
+	start    int // Index of the oldest event once the ring is full
+	capacity int
+	dropped  int
+	mu       sync.Mutex
+}
+
+func NewRecorder(capacity int) *Recorder {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Recorder{events: make([]Event, 0, capacity), capacity: capacity}
+}
+
+// Record appends an event, dropping the oldest once the recorder is full
+func (r *Recorder) Record(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) < r.capacity {
+		r.events = append(r.events, event)
+		return
+	}
+	r.events[r.start] = event
+	r.start = (r.start + 1) % r.capacity
+	r.dropped++
+}
+
+// Events returns the retained events in recording order and how many were dropped
+func (r *Recorder) Events() ([]Event, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]Event, 0, len(r.events))
+	for i := range r.events {
+		events = append(events, r.events[(r.start+i)%len(r.events)])
+	}
+	return events, r.dropped
+}
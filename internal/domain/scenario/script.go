@@ -0,0 +1,247 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+)
+
+// ErrNoScript is returned when progress is requested but no script was started
+var ErrNoScript = errors.New("no scenario script started")
+
+// Action is what a script step does to the venue
+type Action string
+
+const (
+	ActionPriceMove Action = "price_move" // Set the symbol's reference price, or move it by move_pct
+	ActionHalt      Action = "halt"       // Halt the symbol
+	ActionResume    Action = "resume"     // Resume the symbol
+	ActionLiquidity Action = "liquidity"  // Rescale the market maker's ladder on the symbol
+	ActionOutage    Action = "outage"     // Inject a chaos fault
+)
+
+// Step is one timed change a script makes
+type Step struct {
+	At        string      `json:"at"` // Offset from the script's start on the venue clock, e.g. "90s"
+	Action    Action      `json:"action"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Price     float64     `json:"price,omitempty"`      // price_move: the new reference price
+	MovePct   float64     `json:"move_pct,omitempty"`   // price_move: percent change from the mark, instead of a price
+	Scale     float64     `json:"scale,omitempty"`      // liquidity: factor on the configured level size; 1 restores it
+	SpreadBps float64     `json:"spread_bps,omitempty"` // liquidity: spread to quote (0 = configured)
+	Fault     *chaos.Spec `json:"fault,omitempty"`      // outage
+
+	offset time.Duration
+}
+
+// Offset is when the step is due after the script starts
+func (s Step) Offset() time.Duration {
+	return s.offset
+}
+
+// Script is a timed sequence of market events an orchestrator has the venue play
+type Script struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// ParseScript reads a script as YAML or JSON and checks every step is well formed.
+// Steps are ordered by offset, keeping the file's order for steps due together.
+func ParseScript(data []byte) (Script, error) {
+	// YAML is read into plain values and decoded as JSON, so both share the JSON names
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Script{}, fmt.Errorf("invalid scenario script: %w", err)
+	}
+	normalised, err := json.Marshal(document)
+	if err != nil {
+		return Script{}, fmt.Errorf("invalid scenario script: %w", err)
+	}
+	var script Script
+	if err := json.Unmarshal(normalised, &script); err != nil {
+		return Script{}, fmt.Errorf("invalid scenario script: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return Script{}, errors.New("invalid scenario script: no steps")
+	}
+	for i := range script.Steps {
+		if err := script.Steps[i].validate(); err != nil {
+			return Script{}, fmt.Errorf("invalid scenario script: step %d: %w", i+1, err)
+		}
+	}
+	sort.SliceStable(script.Steps, func(i, j int) bool { return script.Steps[i].offset < script.Steps[j].offset })
+	return script, nil
+}
+
+func (s *Step) validate() error {
+	offset, err := time.ParseDuration(s.At)
+	if s.At == "" {
+		offset, err = 0, nil
+	}
+	if err != nil || offset < 0 {
+		return fmt.Errorf("at must be a duration from the start, got %q", s.At)
+	}
+	s.offset = offset
+
+	if s.Action != ActionOutage && s.Symbol == "" {
+		return fmt.Errorf("%s needs a symbol", s.Action)
+	}
+	switch s.Action {
+	case ActionPriceMove:
+		if (s.Price > 0) == (s.MovePct != 0) || s.Price < 0 || s.MovePct <= -100 {
+			return errors.New("price_move needs a positive price or a move_pct above -100, not both")
+		}
+	case ActionHalt, ActionResume:
+	case ActionLiquidity:
+		if s.Scale <= 0 || s.SpreadBps < 0 {
+			return errors.New("liquidity needs a positive scale and a spread_bps that is not negative")
+		}
+	case ActionOutage:
+		if s.Fault == nil {
+			return errors.New("outage needs a fault")
+		}
+		if err := s.Fault.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
+
+// ScriptState is where a script run has got to
+type ScriptState string
+
+const (
+	ScriptRunning   ScriptState = "running"
+	ScriptCompleted ScriptState = "completed" // Every step was executed, some perhaps failing
+	ScriptStopped   ScriptState = "stopped"   // Stopped before its last step
+)
+
+// StepResult is the outcome of one executed step
+type StepResult struct {
+	Index      int       `json:"index"` // Position in the ordered script, from 0
+	At         string    `json:"at"`
+	Action     Action    `json:"action"`
+	Symbol     string    `json:"symbol,omitempty"`
+	ExecutedAt time.Time `json:"executed_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Progress is a script run's state as reported to the orchestrator
+type Progress struct {
+	Script    string       `json:"script"`
+	RunID     string       `json:"run_id"`
+	Instance  string       `json:"instance"`
+	State     ScriptState  `json:"state"`
+	StartedAt time.Time    `json:"started_at"`
+	EndedAt   *time.Time   `json:"ended_at,omitempty"`
+	Steps     int          `json:"steps"`
+	Executed  int          `json:"executed"`
+	Failed    int          `json:"failed"`
+	NextAt    *time.Time   `json:"next_at,omitempty"` // When the next step is due
+	Results   []StepResult `json:"results"`
+}
+
+// ScriptRun plays a script from a start time, handing out steps as they come due
+type ScriptRun struct {
+	script  Script
+	started time.Time
+	next    int // Index of the next step to hand out
+	ended   *time.Time
+	stopped bool
+	results []StepResult
+	mu      sync.Mutex
+}
+
+func NewScriptRun(script Script, start time.Time) *ScriptRun {
+	return &ScriptRun{script: script, started: start, results: make([]StepResult, 0, len(script.Steps))}
+}
+
+// Due hands out the steps due by now, in order, each only once. The index of each is
+// its position in the ordered script, for Record.
+func (r *ScriptRun) Due(now time.Time) ([]Step, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return nil, r.next
+	}
+	first := r.next
+	for r.next < len(r.script.Steps) && !now.Before(r.started.Add(r.script.Steps[r.next].offset)) {
+		r.next++
+	}
+	return r.script.Steps[first:r.next], first
+}
+
+// Record notes that the step at index ran at executedAt, failing with err when not
+// nil. The run completes once every step is recorded.
+func (r *ScriptRun) Record(index int, executedAt time.Time, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	step := r.script.Steps[index]
+	result := StepResult{Index: index, At: step.At, Action: step.Action, Symbol: step.Symbol, ExecutedAt: executedAt}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.results = append(r.results, result)
+	if len(r.results) == len(r.script.Steps) && r.ended == nil {
+		r.ended = &executedAt
+	}
+}
+
+// Stop ends the run before its remaining steps; it reports whether the run was
+// still going
+func (r *ScriptRun) Stop(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ended != nil || r.stopped {
+		return false
+	}
+	r.stopped = true
+	r.ended = &now
+	return true
+}
+
+// Done reports whether the run has completed or been stopped
+func (r *ScriptRun) Done() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ended != nil
+}
+
+// Progress reports the run so far
+func (r *ScriptRun) Progress() Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress := Progress{
+		Script:    r.script.Name,
+		State:     ScriptRunning,
+		StartedAt: r.started,
+		EndedAt:   r.ended,
+		Steps:     len(r.script.Steps),
+		Executed:  len(r.results),
+		Results:   append([]StepResult(nil), r.results...),
+	}
+	for _, result := range r.results {
+		if result.Error != "" {
+			progress.Failed++
+		}
+	}
+	switch {
+	case r.stopped:
+		progress.State = ScriptStopped
+	case r.ended != nil:
+		progress.State = ScriptCompleted
+	case r.next < len(r.script.Steps):
+		nextAt := r.started.Add(r.script.Steps[r.next].offset)
+		progress.NextAt = &nextAt
+	}
+	return progress
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+package scenario
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScript(t *testing.T) {
+	t.Run("parses_yaml_and_json_into_offset_order", func(t *testing.T) {
+		// Given: The same script in YAML and in JSON, written out of order
+		yamlScript := `
+name: flash-crash
+steps:
+  - at: 2m
+    action: resume
+    symbol: BTC-USD
+  - at: 30s
+    action: price_move
+    symbol: BTC-USD
+    move_pct: -12.5
+  - at: 30s
+    action: halt
+    symbol: BTC-USD
+  - at: 1m
+    action: outage
+    fault: {kind: reject_orders, rate: 0.5, duration_ms: 60000}
+`
+		jsonScript := `{"name": "flash-crash", "steps": [
+			{"at": "2m", "action": "resume", "symbol": "BTC-USD"},
+			{"at": "30s", "action": "price_move", "symbol": "BTC-USD", "move_pct": -12.5},
+			{"at": "30s", "action": "halt", "symbol": "BTC-USD"},
+			{"at": "1m", "action": "outage", "fault": {"kind": "reject_orders", "rate": 0.5, "duration_ms": 60000}}
+		]}`
+
+		// When: Both are parsed
+		fromYAML, yamlErr := ParseScript([]byte(yamlScript))
+		fromJSON, jsonErr := ParseScript([]byte(jsonScript))
+
+		// Then: They agree, with steps due together kept in file order
+		if yamlErr != nil || jsonErr != nil {
+			t.Fatalf("Expected both to parse, got %v and %v", yamlErr, jsonErr)
+		}
+		for _, script := range []Script{fromYAML, fromJSON} {
+			actions := [4]Action{script.Steps[0].Action, script.Steps[1].Action, script.Steps[2].Action, script.Steps[3].Action}
+			if actions != [4]Action{ActionPriceMove, ActionHalt, ActionOutage, ActionResume} {
+				t.Errorf("Expected steps ordered by offset, got %v", actions)
+			}
+			if script.Steps[0].MovePct != -12.5 || script.Steps[2].Fault.Rate != 0.5 || script.Steps[2].Fault.DurationMs != 60000 {
+				t.Errorf("Expected step fields decoded, got %+v and %+v", script.Steps[0], script.Steps[2].Fault)
+			}
+		}
+	})
+
+	t.Run("refuses_malformed_steps", func(t *testing.T) {
+		for _, data := range []string{
+			`{"name": "empty", "steps": []}`,
+			`{"steps": [{"at": "soon", "action": "halt", "symbol": "BTC-USD"}]}`,
+			`{"steps": [{"action": "halt"}]}`,
+			`{"steps": [{"action": "price_move", "symbol": "BTC-USD", "price": 100, "move_pct": 5}]}`,
+			`{"steps": [{"action": "liquidity", "symbol": "BTC-USD"}]}`,
+			`{"steps": [{"action": "outage", "fault": {"kind": "meteor_strike"}}]}`,
+			`{"steps": [{"action": "rewind", "symbol": "BTC-USD"}]}`,
+		} {
+			if _, err := ParseScript([]byte(data)); err == nil {
+				t.Errorf("Expected %s to be refused", data)
+			}
+		}
+	})
+
+	t.Run("hands_out_steps_once_as_they_come_due", func(t *testing.T) {
+		// Given: A three-step script started at noon
+		start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		script, err := ParseScript([]byte(`{"name": "drill", "steps": [
+			{"action": "halt", "symbol": "BTC-USD"},
+			{"at": "1m", "action": "resume", "symbol": "BTC-USD"},
+			{"at": "5m", "action": "halt", "symbol": "ETH-USD"}
+		]}`))
+		if err != nil {
+			t.Fatalf("Expected the script to parse, got %v", err)
+		}
+		run := NewScriptRun(script, start)
+
+		// When: Steps are taken at the start and two minutes in, then it is stopped
+		first, firstIndex := run.Due(start)
+		run.Record(firstIndex, start, nil)
+		again, _ := run.Due(start)
+		second, secondIndex := run.Due(start.Add(2 * time.Minute))
+		run.Record(secondIndex, start.Add(2*time.Minute), errors.New("halt refused"))
+		midway := run.Progress()
+		stopped := run.Stop(start.Add(3 * time.Minute))
+		late, _ := run.Due(start.Add(10 * time.Minute))
+
+		// Then: Each step was handed out once, and the stop kept the last from running
+		if len(first) != 1 || len(again) != 0 || len(second) != 1 || secondIndex != 1 || len(late) != 0 {
+			t.Errorf("Expected one step at a time, got %d, %d, %d, %d", len(first), len(again), len(second), len(late))
+		}
+		if midway.State != ScriptRunning || midway.Executed != 2 || midway.Failed != 1 || midway.NextAt == nil || !midway.NextAt.Equal(start.Add(5*time.Minute)) {
+			t.Errorf("Expected two of three executed and the next at 12:05, got %+v", midway)
+		}
+		if progress := run.Progress(); !stopped || progress.State != ScriptStopped || !run.Done() || run.Stop(start.Add(4*time.Minute)) {
+			t.Errorf("Expected the run stopped once, got %+v", progress)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package settlement
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is how trade and settlement dates are written
+const dateLayout = "2006-01-02"
+
+// Position is what one account receives (positive) or delivers (negative) of one
+// asset once its obligations are netted
+type Position struct {
+	AccountID string   `json:"account_id"`
+	Asset     string   `json:"asset"`
+	Amount    float64  `json:"amount"`
+	TradeIDs  []string `json:"trade_ids"`
+}
+
+// NetPositions folds obligations into one position per account and asset, sorted by
+// account then asset, dropping those that net to zero
+func NetPositions(obligations []Obligation) []Position {
+	type key struct {
+		accountID string
+		asset     string
+	}
+	positions := make(map[key]*Position)
+	keys := make([]key, 0)
+	for _, obligation := range obligations {
+		k := key{obligation.AccountID, obligation.Asset}
+		position, ok := positions[k]
+		if !ok {
+			position = &Position{AccountID: obligation.AccountID, Asset: obligation.Asset}
+			positions[k] = position
+			keys = append(keys, k)
+		}
+		position.Amount += obligation.Amount
+		if ids := position.TradeIDs; len(ids) == 0 || ids[len(ids)-1] != obligation.TradeID {
+			position.TradeIDs = append(ids, obligation.TradeID)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].accountID != keys[j].accountID {
+			return keys[i].accountID < keys[j].accountID
+		}
+		return keys[i].asset < keys[j].asset
+	})
+
+	netted := make([]Position, 0, len(keys))
+	for _, k := range keys {
+		if math.Abs(positions[k].Amount) >= amountEpsilon {
+			netted = append(netted, *positions[k])
+		}
+	}
+	return netted
+}
+
+// Schedule is when the venue closes each trading day for settlement and how many
+// days later the day's net positions settle
+type Schedule struct {
+	Cutoff time.Duration // Time of day, UTC, at which the day's trades are netted
+	Lag    int           // Days from trade date to settlement date: 0 for T+0, 1 for T+1
+}
+
+// ParseSchedule reads a cutoff written "17:00" and a cycle written "T+1"
+func ParseSchedule(cutoff, cycle string) (Schedule, error) {
+	at, err := time.Parse("15:04", strings.TrimSpace(cutoff))
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid settlement cutoff %q: want HH:MM", cutoff)
+	}
+	days, ok := strings.CutPrefix(strings.ToUpper(strings.TrimSpace(cycle)), "T+")
+	lag, err := strconv.Atoi(days)
+	if !ok || err != nil || lag < 0 {
+		return Schedule{}, fmt.Errorf("invalid settlement cycle %q: want T+N", cycle)
+	}
+	return Schedule{Cutoff: time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, Lag: lag}, nil
+}
+
+// NextCutoff returns the first cutoff after t
+func (s Schedule) NextCutoff(t time.Time) time.Time {
+	t = t.UTC()
+	cutoff := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(s.Cutoff)
+	if !cutoff.After(t) {
+		cutoff = cutoff.AddDate(0, 0, 1)
+	}
+	return cutoff
+}
+
+// SettlesAt returns when trades netted at cutoff settle: the same time of day, Lag
+// days later
+func (s Schedule) SettlesAt(cutoff time.Time) time.Time {
+	return cutoff.AddDate(0, 0, s.Lag)
+}
+
+func (s Schedule) String() string {
+	return fmt.Sprintf("T+%d, cutoff %02d:%02d UTC", s.Lag, int(s.Cutoff.Hours()), int(s.Cutoff.Minutes())%60)
+}
+
+// CycleStatus is where a trading day's settlement stands
+type CycleStatus string
+
+const (
+	CycleNetted     CycleStatus = "netted"     // Trades up to the cutoff are netted; waiting for the settlement date
+	CycleInstructed CycleStatus = "instructed" // Instructions are with the custodian
+	CycleSettled    CycleStatus = "settled"    // Every position settled
+	CycleFailed     CycleStatus = "failed"     // The custodian rejected at least one instruction
+)
+
+// CycleTransition is one change of a cycle's status
+type CycleTransition struct {
+	Status CycleStatus `json:"status"`
+	At     time.Time   `json:"at"`
+	Detail string      `json:"detail,omitempty"`
+}
+
+// Cycle is one trading day's settlement: the net positions of the trades up to its
+// cutoff and every status it passed through
+type Cycle struct {
+	ID             string            `json:"cycle_id"`
+	TradeDate      string            `json:"trade_date"`
+	Cutoff         time.Time         `json:"cutoff"`
+	SettlementDate string            `json:"settlement_date"`
+	SettlesAt      time.Time         `json:"settles_at"`
+	Status         CycleStatus       `json:"status"`
+	Trades         int               `json:"trades"`
+	Positions      []Position        `json:"positions"`
+	InstructionIDs []string          `json:"instruction_ids,omitempty"`
+	Transitions    []CycleTransition `json:"transitions"`
+}
+
+// NewCycle nets the obligations of the trading day ending at cutoff
+func NewCycle(id string, schedule Schedule, cutoff time.Time, obligations []Obligation) Cycle {
+	trades := make(map[string]bool)
+	for _, obligation := range obligations {
+		trades[obligation.TradeID] = true
+	}
+	settlesAt := schedule.SettlesAt(cutoff)
+	cycle := Cycle{
+		ID:             id,
+		TradeDate:      cutoff.Format(dateLayout),
+		Cutoff:         cutoff,
+		SettlementDate: settlesAt.Format(dateLayout),
+		SettlesAt:      settlesAt,
+		Trades:         len(trades),
+		Positions:      NetPositions(obligations),
+	}
+	cycle.Transition(CycleNetted, cutoff, fmt.Sprintf("%d trades netted into %d positions", cycle.Trades, len(cycle.Positions)))
+	return cycle
+}
+
+// Transition moves the cycle to status, recording when and why
+func (c *Cycle) Transition(status CycleStatus, at time.Time, detail string) {
+	c.Status = status
+	c.Transitions = append(c.Transitions, CycleTransition{Status: status, At: at, Detail: detail})
+}
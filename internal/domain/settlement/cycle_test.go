@@ -0,0 +1,67 @@
+//go:build unit
+
+package settlement
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Run("parses_cutoff_and_cycle", func(t *testing.T) {
+		schedule, err := ParseSchedule("17:30", "t+1")
+
+		if err != nil || schedule.Cutoff != 17*time.Hour+30*time.Minute || schedule.Lag != 1 {
+			t.Fatalf("Expected a 17:30 cutoff settling T+1, got %+v, %v", schedule, err)
+		}
+		for _, bad := range [][2]string{{"5pm", "T+0"}, {"17:00", "T1"}, {"17:00", "T+-1"}} {
+			if _, err := ParseSchedule(bad[0], bad[1]); err == nil {
+				t.Errorf("Expected %q %q to be rejected", bad[0], bad[1])
+			}
+		}
+	})
+
+	t.Run("next_cutoff_is_today_until_it_passes", func(t *testing.T) {
+		schedule := Schedule{Cutoff: 17 * time.Hour, Lag: 1}
+		morning := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+		cutoff := time.Date(2024, 1, 2, 17, 0, 0, 0, time.UTC)
+
+		if next := schedule.NextCutoff(morning); !next.Equal(cutoff) {
+			t.Errorf("Expected today's cutoff, got %s", next)
+		}
+		if next := schedule.NextCutoff(cutoff); !next.Equal(cutoff.AddDate(0, 0, 1)) {
+			t.Errorf("Expected tomorrow's cutoff once today's is reached, got %s", next)
+		}
+		if settles := schedule.SettlesAt(cutoff); !settles.Equal(cutoff.AddDate(0, 0, 1)) {
+			t.Errorf("Expected T+1 to settle a day after the cutoff, got %s", settles)
+		}
+	})
+}
+
+func TestCycle(t *testing.T) {
+	t.Run("nets_the_days_trades_per_account_and_asset", func(t *testing.T) {
+		// Given: Two trades between alice and bob, one of them alice's sale back
+		cutoff := time.Date(2024, 1, 2, 17, 0, 0, 0, time.UTC)
+		obligations := append(FillObligations("t-1", "alice", "bob", "BTC", "USD", 1, 60000, cutoff),
+			FillObligations("t-2", "bob", "alice", "BTC", "USD", 1, 60500, cutoff)...)
+
+		// When: The day is netted for T+1
+		cycle := NewCycle("eod-1", Schedule{Cutoff: 17 * time.Hour, Lag: 1}, cutoff, obligations)
+
+		// Then: Only the USD difference remains, settling the next day
+		if cycle.Trades != 2 || len(cycle.Positions) != 2 || cycle.Status != CycleNetted {
+			t.Fatalf("Expected two trades netted into two positions, got %+v", cycle)
+		}
+		if alice := cycle.Positions[0]; alice.AccountID != "alice" || alice.Asset != "USD" || alice.Amount != 500 || len(alice.TradeIDs) != 2 {
+			t.Errorf("Expected alice to receive 500 USD over both trades, got %+v", alice)
+		}
+		if cycle.TradeDate != "2024-01-02" || cycle.SettlementDate != "2024-01-03" {
+			t.Errorf("Expected trade date 2024-01-02 settling 2024-01-03, got %s and %s", cycle.TradeDate, cycle.SettlementDate)
+		}
+
+		cycle.Transition(CycleSettled, cycle.SettlesAt, "")
+		if cycle.Status != CycleSettled || len(cycle.Transitions) != 2 {
+			t.Errorf("Expected a settled cycle with two transitions, got %+v", cycle.Transitions)
+		}
+	})
+}
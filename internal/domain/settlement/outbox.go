@@ -0,0 +1,341 @@
+package settlement
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Direction is which way an instruction moves its asset, seen from its account
+type Direction string
+
+const (
+	DirectionDeliver Direction = "deliver"
+	DirectionReceive Direction = "receive"
+)
+
+// Status is where an instruction stands with the custodian
+type Status string
+
+const (
+	StatusPending      Status = "pending"      // Not yet submitted
+	StatusFailed       Status = "failed"       // The last submission failed; it is retried
+	StatusAcknowledged Status = "acknowledged" // The custodian booked it
+	StatusRejected     Status = "rejected"     // The custodian refused it; it is not retried
+)
+
+// amountEpsilon is below what a netted amount is taken to be zero
+const amountEpsilon = 1e-9
+
+// VenueAccount is the counterparty of netted instructions: the venue settles with each
+// account, not between them
+const VenueAccount = "venue:clearing"
+
+// Obligation is what one fill owes one side: positive amounts are received, negative
+// ones delivered
+type Obligation struct {
+	TradeID      string
+	AccountID    string
+	Counterparty string
+	Asset        string
+	Amount       float64
+	At           time.Time
+}
+
+// FillObligations are a spot fill's four movements: the buyer receives the base and
+// delivers the quote, the seller the reverse
+func FillObligations(tradeID, buyer, seller, base, quote string, quantity, notional float64, at time.Time) []Obligation {
+	return []Obligation{
+		{TradeID: tradeID, AccountID: buyer, Counterparty: seller, Asset: base, Amount: quantity, At: at},
+		{TradeID: tradeID, AccountID: buyer, Counterparty: seller, Asset: quote, Amount: -notional, At: at},
+		{TradeID: tradeID, AccountID: seller, Counterparty: buyer, Asset: base, Amount: -quantity, At: at},
+		{TradeID: tradeID, AccountID: seller, Counterparty: buyer, Asset: quote, Amount: notional, At: at},
+	}
+}
+
+// Instruction is one asset movement the custodian is asked to book
+type Instruction struct {
+	ID             string     `json:"instruction_id"`
+	BatchID        string     `json:"batch_id"`
+	AccountID      string     `json:"account_id"`
+	Asset          string     `json:"asset"`
+	Amount         float64    `json:"amount"`
+	Direction      Direction  `json:"direction"`
+	Counterparty   string     `json:"counterparty_account_id"`
+	TradeIDs       []string   `json:"trade_ids"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Status         Status     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	Reference      string     `json:"custodian_reference,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// Batch is instructions submitted together
+type Batch struct {
+	ID           string
+	Venue        string
+	Instructions []Instruction
+}
+
+// Ack is the custodian's answer for one instruction
+type Ack struct {
+	InstructionID string
+	Accepted      bool
+	Reference     string
+	Reason        string
+}
+
+// Store keeps the outbox across restarts. Every change to an instruction is saved
+// whole; the latest saved state of each ID wins.
+type Store interface {
+	Save(instructions ...Instruction) error
+	Load() ([]Instruction, error)
+}
+
+// Backoff is how long the outbox waits before retrying a failed submission: Base
+// after the first failure, doubling up to Max
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b Backoff) after(attempts int) time.Duration {
+	wait := b.Base
+	for i := 1; i < attempts && wait < b.Max; i++ {
+		wait *= 2
+	}
+	if b.Max > 0 && wait > b.Max {
+		wait = b.Max
+	}
+	return wait
+}
+
+// Outbox holds every instruction the venue has generated and tracks it until the
+// custodian acknowledges or rejects it
+type Outbox struct {
+	backoff      Backoff
+	instructions map[string]*Instruction
+	order        []string // IDs in creation order
+	sequence     int64    // Last instruction and batch number issued
+	mu           sync.Mutex
+}
+
+func NewOutbox(backoff Backoff) *Outbox {
+	return &Outbox{backoff: backoff, instructions: make(map[string]*Instruction)}
+}
+
+// Restore replaces the outbox with saved instructions, keeping the latest state of
+// each, and continues numbering after the highest ID
+func (o *Outbox) Restore(saved []Instruction) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.instructions = make(map[string]*Instruction, len(saved))
+	o.order = o.order[:0]
+	o.sequence = 0
+	for _, instruction := range saved {
+		instruction := instruction
+		if _, known := o.instructions[instruction.ID]; !known {
+			o.order = append(o.order, instruction.ID)
+		}
+		o.instructions[instruction.ID] = &instruction
+		for _, id := range []string{instruction.ID, instruction.BatchID} {
+			if n := sequenceOf(id); n > o.sequence {
+				o.sequence = n
+			}
+		}
+	}
+}
+
+// Gross turns each obligation into its own instruction against the fill's
+// counterparty, all in one batch
+func (o *Outbox) Gross(obligations []Obligation, at time.Time) []Instruction {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	batchID := o.next("batch")
+	created := make([]Instruction, 0, len(obligations))
+	for _, obligation := range obligations {
+		if math.Abs(obligation.Amount) < amountEpsilon || obligation.AccountID == obligation.Counterparty {
+			continue
+		}
+		created = append(created, o.add(batchID, obligation.AccountID, obligation.Asset, obligation.Counterparty, obligation.Amount, []string{obligation.TradeID}, at))
+	}
+	return created
+}
+
+// Net folds obligations into one instruction per account and asset against the
+// venue, dropping those that net to zero, all in one batch
+func (o *Outbox) Net(obligations []Obligation, at time.Time) []Instruction {
+	return o.Instruct(NetPositions(obligations), at)
+}
+
+// Instruct turns net positions into one instruction each against the venue, all in
+// one batch
+func (o *Outbox) Instruct(positions []Position, at time.Time) []Instruction {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	batchID := o.next("batch")
+	created := make([]Instruction, 0, len(positions))
+	for _, position := range positions {
+		created = append(created, o.add(batchID, position.AccountID, position.Asset, VenueAccount, position.Amount, position.TradeIDs, at))
+	}
+	return created
+}
+
+// Status returns where the instruction with id stands
+func (o *Outbox) Status(id string) (Status, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	instruction, ok := o.instructions[id]
+	if !ok {
+		return "", false
+	}
+	return instruction.Status, true
+}
+
+// Due returns up to limit instructions awaiting submission at now, oldest first;
+// zero means no limit
+func (o *Outbox) Due(now time.Time, limit int) []Instruction {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	due := make([]Instruction, 0)
+	for _, id := range o.order {
+		instruction := o.instructions[id]
+		if instruction.Status != StatusPending && instruction.Status != StatusFailed {
+			continue
+		}
+		if instruction.NextAttemptAt.After(now) {
+			continue
+		}
+		due = append(due, *instruction)
+		if limit > 0 && len(due) == limit {
+			break
+		}
+	}
+	return due
+}
+
+// Acknowledge applies the custodian's answers; instructions it did not answer for
+// are failed and retried
+func (o *Outbox) Acknowledge(submitted []Instruction, acks []Ack, at time.Time) []Instruction {
+	answers := make(map[string]Ack, len(acks))
+	for _, ack := range acks {
+		answers[ack.InstructionID] = ack
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	changed := make([]Instruction, 0, len(submitted))
+	for _, sent := range submitted {
+		instruction, ok := o.instructions[sent.ID]
+		if !ok {
+			continue
+		}
+		instruction.Attempts++
+		ack, answered := answers[sent.ID]
+		switch {
+		case !answered:
+			o.fail(instruction, "no acknowledgement for the instruction", at)
+		case ack.Accepted:
+			acknowledgedAt := at
+			instruction.Status, instruction.Reference, instruction.LastError = StatusAcknowledged, ack.Reference, ""
+			instruction.AcknowledgedAt = &acknowledgedAt
+		default:
+			instruction.Status, instruction.LastError = StatusRejected, ack.Reason
+		}
+		changed = append(changed, *instruction)
+	}
+	return changed
+}
+
+// Fail records a submission that did not reach the custodian and schedules a retry
+func (o *Outbox) Fail(submitted []Instruction, err error, at time.Time) []Instruction {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	changed := make([]Instruction, 0, len(submitted))
+	for _, sent := range submitted {
+		if instruction, ok := o.instructions[sent.ID]; ok {
+			instruction.Attempts++
+			o.fail(instruction, err.Error(), at)
+			changed = append(changed, *instruction)
+		}
+	}
+	return changed
+}
+
+// List returns instructions newest first, optionally with one status or for one
+// account, up to limit; zero means no limit
+func (o *Outbox) List(status Status, accountID string, limit int) []Instruction {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	listed := make([]Instruction, 0)
+	for i := len(o.order) - 1; i >= 0; i-- {
+		instruction := o.instructions[o.order[i]]
+		if (status != "" && instruction.Status != status) || (accountID != "" && instruction.AccountID != accountID) {
+			continue
+		}
+		listed = append(listed, *instruction)
+		if limit > 0 && len(listed) == limit {
+			break
+		}
+	}
+	return listed
+}
+
+// Counts returns how many instructions are in each status
+func (o *Outbox) Counts() map[Status]int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	counts := map[Status]int{StatusPending: 0, StatusFailed: 0, StatusAcknowledged: 0, StatusRejected: 0}
+	for _, instruction := range o.instructions {
+		counts[instruction.Status]++
+	}
+	return counts
+}
+
+func (o *Outbox) add(batchID, accountID, asset, counterparty string, amount float64, tradeIDs []string, at time.Time) Instruction {
+	direction := DirectionReceive
+	if amount < 0 {
+		direction = DirectionDeliver
+	}
+	instruction := &Instruction{
+		ID:            o.next("stl"),
+		BatchID:       batchID,
+		AccountID:     accountID,
+		Asset:         asset,
+		Amount:        math.Abs(amount),
+		Direction:     direction,
+		Counterparty:  counterparty,
+		TradeIDs:      tradeIDs,
+		CreatedAt:     at,
+		Status:        StatusPending,
+		NextAttemptAt: at,
+	}
+	o.instructions[instruction.ID] = instruction
+	o.order = append(o.order, instruction.ID)
+	return *instruction
+}
+
+func (o *Outbox) fail(instruction *Instruction, reason string, at time.Time) {
+	instruction.Status, instruction.LastError = StatusFailed, reason
+	instruction.NextAttemptAt = at.Add(o.backoff.after(instruction.Attempts))
+}
+
+// next issues the next ID with prefix, "stl-42"; callers hold mu
+func (o *Outbox) next(prefix string) string {
+	o.sequence++
+	return fmt.Sprintf("%s-%d", prefix, o.sequence)
+}
+
+func sequenceOf(id string) int64 {
+	_, number, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(number, 10, 64)
+	return n
+}
@@ -0,0 +1,109 @@
+//go:build unit
+
+package settlement
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutbox(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fills := func() []Obligation {
+		obligations := FillObligations("t-1", "alice", "bob", "BTC", "USD", 1, 60000, start)
+		return append(obligations, FillObligations("t-2", "bob", "alice", "BTC", "USD", 0.25, 15500, start)...)
+	}
+
+	t.Run("nets_fills_per_account_and_asset_against_the_venue", func(t *testing.T) {
+		// Given: Alice buys 1 BTC from Bob for 60000 and sells 0.25 back for 15500
+		outbox := NewOutbox(Backoff{Base: time.Second, Max: time.Minute})
+
+		// When: The window is netted
+		netted := outbox.Net(fills(), start)
+
+		// Then: Each account moves one net amount per asset, all in one batch
+		if len(netted) != 4 {
+			t.Fatalf("Expected 4 netted instructions, got %+v", netted)
+		}
+		alice := netted[0]
+		if alice.AccountID != "alice" || alice.Asset != "BTC" || alice.Amount != 0.75 || alice.Direction != DirectionReceive || alice.Counterparty != VenueAccount {
+			t.Errorf("Unexpected instruction for alice's BTC: %+v", alice)
+		}
+		if usd := netted[1]; usd.Asset != "USD" || usd.Amount != 44500 || usd.Direction != DirectionDeliver || len(usd.TradeIDs) != 2 {
+			t.Errorf("Unexpected instruction for alice's USD: %+v", usd)
+		}
+		if netted[0].BatchID != netted[3].BatchID || netted[0].ID == netted[1].ID {
+			t.Errorf("Expected distinct instructions in one batch, got %+v", netted)
+		}
+	})
+
+	t.Run("gross_instructions_name_the_fill_counterparty", func(t *testing.T) {
+		outbox := NewOutbox(Backoff{Base: time.Second})
+		selfTrade := FillObligations("t-3", "carol", "carol", "BTC", "USD", 1, 60000, start)
+
+		gross := outbox.Gross(append(fills()[:4], selfTrade...), start)
+
+		if len(gross) != 4 || gross[0].Counterparty != "bob" || gross[0].Amount != 1 || gross[2].AccountID != "bob" || gross[2].Direction != DirectionDeliver {
+			t.Errorf("Expected one instruction per side of the fill and none for the self-trade, got %+v", gross)
+		}
+	})
+
+	t.Run("retries_failures_with_backoff_until_acknowledged", func(t *testing.T) {
+		// Given: A netted batch whose first submission fails
+		outbox := NewOutbox(Backoff{Base: time.Second, Max: 4 * time.Second})
+		outbox.Net(fills()[:4], start)
+		due := outbox.Due(start, 0)
+		outbox.Fail(due, errors.New("connection refused"), start)
+
+		// When: It is retried once the backoff passes, and the custodian answers
+		early := outbox.Due(start.Add(500*time.Millisecond), 0)
+		retry := outbox.Due(start.Add(time.Second), 0)
+		acks := []Ack{
+			{InstructionID: retry[0].ID, Accepted: true, Reference: "cust-1"},
+			{InstructionID: retry[1].ID, Accepted: false, Reason: "unknown account"},
+		}
+		changed := outbox.Acknowledge(retry, acks, start.Add(time.Second))
+
+		// Then: Accepted and rejected instructions are final, and unanswered ones retry later
+		if len(early) != 0 || len(retry) != 4 {
+			t.Fatalf("Expected nothing due during the backoff and all four after, got %d and %d", len(early), len(retry))
+		}
+		if changed[0].Status != StatusAcknowledged || changed[0].Reference != "cust-1" || changed[0].Attempts != 2 {
+			t.Errorf("Unexpected acknowledged instruction: %+v", changed[0])
+		}
+		if changed[1].Status != StatusRejected || changed[1].LastError != "unknown account" {
+			t.Errorf("Unexpected rejected instruction: %+v", changed[1])
+		}
+		if changed[2].Status != StatusFailed || !changed[2].NextAttemptAt.Equal(start.Add(3*time.Second)) {
+			t.Errorf("Expected an unanswered instruction retried after 2s, got %+v", changed[2])
+		}
+		counts := outbox.Counts()
+		if counts[StatusAcknowledged] != 1 || counts[StatusRejected] != 1 || counts[StatusFailed] != 2 {
+			t.Errorf("Unexpected counts: %v", counts)
+		}
+	})
+
+	t.Run("restores_the_latest_state_and_keeps_numbering", func(t *testing.T) {
+		// Given: An instruction saved pending, then saved acknowledged
+		outbox := NewOutbox(Backoff{Base: time.Second})
+		created := outbox.Net(fills()[:4], start)
+		saved := append([]Instruction(nil), created...)
+		acked := outbox.Acknowledge(created[:1], []Ack{{InstructionID: created[0].ID, Accepted: true}}, start)
+		saved = append(saved, acked...)
+
+		// When: A new outbox restores them
+		restored := NewOutbox(Backoff{Base: time.Second})
+		restored.Restore(saved)
+		due := restored.Due(start, 0)
+		next := restored.Net(FillObligations("t-9", "alice", "bob", "BTC", "USD", 1, 60000, start), start)
+
+		// Then: The acknowledgement stands and new IDs follow the saved ones
+		if len(due) != 3 || restored.List(StatusAcknowledged, "", 0)[0].ID != created[0].ID {
+			t.Errorf("Expected three due and the first acknowledged, got %+v", restored.List("", "", 0))
+		}
+		if next[0].ID == created[0].ID || next[0].BatchID == created[0].BatchID || sequenceOf(next[0].ID) <= sequenceOf(created[3].ID) {
+			t.Errorf("Expected fresh IDs after restore, got %s in %s", next[0].ID, next[0].BatchID)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package storagelatency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+const (
+	// windowSize is how many of a component's latest calls its percentiles cover
+	windowSize = 1000
+	// minSamples is how many calls a component needs before its p99 is judged, so one
+	// slow call after startup is logged but does not degrade health
+	minSamples = 20
+)
+
+// Config sets when persistence calls are slow and when a component's p99 breaches
+type Config struct {
+	SlowThreshold time.Duration // Calls at or above it are slow (0 = none are)
+	P99Limit      time.Duration // A p99 above it breaches (0 = never)
+}
+
+// Observation is what one timed call showed
+type Observation struct {
+	Slow     bool
+	P99      time.Duration
+	Breached bool // The component's p99 is above the limit
+	Changed  bool // Breached differs from before the call
+}
+
+// Stats summarizes one component's recent persistence calls in milliseconds
+type Stats struct {
+	Component string  `json:"component"`
+	Calls     int64   `json:"calls"` // Since startup
+	Slow      int64   `json:"slow"`
+	Failed    int64   `json:"failed"`
+	Window    int     `json:"window"` // Latest calls the percentiles cover
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	Breached  bool    `json:"breached"`
+}
+
+type component struct {
+	window   []time.Duration // Ring buffer of the latest calls
+	next     int
+	calls    int64
+	slow     int64
+	failed   int64
+	breached bool
+}
+
+// Monitor times persistence calls per component, mirroring each to a duration
+// histogram labelled by component, operation and outcome
+type Monitor struct {
+	config     Config
+	metrics    ports.MetricsPort
+	components map[string]*component
+	mu         sync.Mutex
+}
+
+// NewMonitor accepts a nil metrics port when metrics are disabled
+func NewMonitor(config Config, metrics ports.MetricsPort) *Monitor {
+	return &Monitor{config: config, metrics: metrics, components: make(map[string]*component)}
+}
+
+// Observe records how long one call to a component took
+func (m *Monitor) Observe(name, operation string, latency time.Duration, err error) Observation {
+	if m.metrics != nil {
+		outcome := "ok"
+		if err != nil {
+			outcome = "error"
+		}
+		m.metrics.ObserveHistogram("persistence_call_duration_seconds", latency.Seconds(),
+			map[string]string{"component": name, "operation": operation, "outcome": outcome})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.components[name]
+	if !ok {
+		c = &component{window: make([]time.Duration, 0, windowSize)}
+		m.components[name] = c
+	}
+	if len(c.window) < windowSize {
+		c.window = append(c.window, latency)
+	} else {
+		c.window[c.next] = latency
+		c.next = (c.next + 1) % windowSize
+	}
+	c.calls++
+	if err != nil {
+		c.failed++
+	}
+
+	observation := Observation{
+		Slow: m.config.SlowThreshold > 0 && latency >= m.config.SlowThreshold,
+		P99:  percentile(sorted(c.window), 0.99),
+	}
+	if observation.Slow {
+		c.slow++
+	}
+	before := c.breached
+	c.breached = m.config.P99Limit > 0 && len(c.window) >= minSamples && observation.P99 > m.config.P99Limit
+	observation.Breached, observation.Changed = c.breached, c.breached != before
+	return observation
+}
+
+// Breached lists the components whose p99 is above the limit, sorted
+func (m *Monitor) Breached() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	breached := make([]string, 0)
+	for name, c := range m.components {
+		if c.breached {
+			breached = append(breached, name)
+		}
+	}
+	sort.Strings(breached)
+	return breached
+}
+
+// Stats summarizes every component timed so far, sorted by name
+func (m *Monitor) Stats() []Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make([]Stats, 0, len(m.components))
+	for name, c := range m.components {
+		window := sorted(c.window)
+		stats = append(stats, Stats{
+			Component: name,
+			Calls:     c.calls,
+			Slow:      c.slow,
+			Failed:    c.failed,
+			Window:    len(window),
+			P50Ms:     milliseconds(percentile(window, 0.50)),
+			P95Ms:     milliseconds(percentile(window, 0.95)),
+			P99Ms:     milliseconds(percentile(window, 0.99)),
+			MaxMs:     milliseconds(percentile(window, 1)),
+			Breached:  c.breached,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Component < stats[j].Component })
+	return stats
+}
+
+// Config returns the thresholds calls are judged by
+func (m *Monitor) Config() Config {
+	return m.config
+}
+
+func sorted(window []time.Duration) []time.Duration {
+	copied := append([]time.Duration(nil), window...)
+	sort.Slice(copied, func(i, j int) bool { return copied[i] < copied[j] })
+	return copied
+}
+
+// percentile is the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,38 @@
+//go:build unit
+
+package storagelatency
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	t.Run("flags_slow_calls_and_breaches_once_p99_exceeds_the_limit", func(t *testing.T) {
+		// Given: A monitor calling 100ms slow and a p99 above 50ms a breach
+		monitor := NewMonitor(Config{SlowThreshold: 100 * time.Millisecond, P99Limit: 50 * time.Millisecond}, nil)
+
+		// When: A slow call arrives first, then 19 fast ones, the last failing
+		first := monitor.Observe("storage:trades", "append", 200*time.Millisecond, nil)
+		for i := 0; i < 18; i++ {
+			monitor.Observe("storage:trades", "append", time.Millisecond, nil)
+		}
+		breach := monitor.Observe("storage:trades", "append", time.Millisecond, errors.New("timeout"))
+
+		// Then: The first is slow without breaching, and the p99 only breaches with enough samples
+		if !first.Slow || first.Breached {
+			t.Errorf("Expected a slow call without a breach, got %+v", first)
+		}
+		if breach.Slow || !breach.Breached || !breach.Changed || breach.P99 != 200*time.Millisecond {
+			t.Errorf("Expected the p99 to breach, got %+v", breach)
+		}
+		stats := monitor.Stats()
+		if len(stats) != 1 || stats[0].Calls != 20 || stats[0].Slow != 1 || stats[0].Failed != 1 || stats[0].P50Ms != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+		if breached := monitor.Breached(); len(breached) != 1 || breached[0] != "storage:trades" {
+			t.Errorf("Expected storage:trades to be breached, got %v", breached)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package surveillance
+
+import "context"
+
+// AuditEventType identifies surveillance flags among other audit events
+const AuditEventType = "surveillance.flag"
+
+// AuditEvent wraps a flag for the audit trail
+type AuditEvent struct {
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	Flag   Flag   `json:"flag"`
+}
+
+// AuditSink receives surveillance flags, e.g. the audit-correlator client
+type AuditSink interface {
+	SubmitFlag(ctx context.Context, event AuditEvent) error
+}
@@ -0,0 +1,294 @@
+package surveillance
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// FlagType names the detector that raised a flag
+type FlagType string
+
+const (
+	FlagSelfCross   FlagType = "self_cross"   // Account traded with itself
+	FlagRapidCancel FlagType = "rapid_cancel" // Account cancels most of what it places
+	FlagBandPrint   FlagType = "band_print"   // Trade printed close to the circuit breaker band
+)
+
+// MaxFlags bounds the flags kept in memory; the oldest are dropped first
+const MaxFlags = 10000
+
+// Flag is one surveillance alert against an account
+type Flag struct {
+	ID        string    `json:"id"`
+	Type      FlagType  `json:"type"`
+	AccountID string    `json:"account_id"`
+	Symbol    string    `json:"symbol"`
+	OrderID   string    `json:"order_id,omitempty"`
+	TradeID   string    `json:"trade_id,omitempty"`
+	Value     float64   `json:"value"`     // Measured cancel ratio or band move percent
+	Threshold float64   `json:"threshold"` // Level the value crossed
+	Detail    string    `json:"detail"`
+	RaisedAt  time.Time `json:"raised_at"`
+}
+
+// Config tunes the detectors
+type Config struct {
+	CancelRatio     float64       // Cancels per order placed that flags an account, e.g. 0.9
+	CancelMinOrders int           // Orders placed in the window before the ratio is judged
+	CancelWindow    time.Duration // Lookback for the cancel ratio
+
+	BandThresholdPercent float64       // The circuit breaker threshold being watched
+	BandWindow           time.Duration // The circuit breaker lookback window
+	BandProximity        float64       // Fraction of the threshold that counts as at the band, e.g. 0.8
+}
+
+// DefaultConfig flags 90% cancel ratios over 20+ orders a minute and prints within 80% of a 10% band
+func DefaultConfig() Config {
+	return Config{
+		CancelRatio:          0.9,
+		CancelMinOrders:      20,
+		CancelWindow:         time.Minute,
+		BandThresholdPercent: 10,
+		BandWindow:           5 * time.Minute,
+		BandProximity:        0.8,
+	}
+}
+
+type accountActivity struct {
+	placed     []time.Time
+	canceled   []time.Time
+	lastFlagAt time.Time
+}
+
+type pricePoint struct {
+	price float64
+	at    time.Time
+}
+
+// Monitor runs the detectors over order and trade activity. It observes and
+// flags only; it never blocks or alters an order.
+type Monitor struct {
+	config   Config
+	accounts map[string]*accountActivity
+	prices   map[string][]pricePoint
+	flags    []Flag
+	nextID   uint64
+	mu       sync.RWMutex
+}
+
+func NewMonitor(config Config) *Monitor {
+	return &Monitor{
+		config:   config,
+		accounts: make(map[string]*accountActivity),
+		prices:   make(map[string][]pricePoint),
+		flags:    make([]Flag, 0),
+	}
+}
+
+// OnOrderPlaced counts an accepted order toward its account's cancel ratio
+func (m *Monitor) OnOrderPlaced(order models.Order) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	activity := m.activity(order.AccountID)
+	activity.placed = append(trimBefore(activity.placed, order.CreatedAt.Add(-m.config.CancelWindow)), order.CreatedAt)
+}
+
+// OnOrderCanceled checks the account's cancel ratio and returns any flag raised
+func (m *Monitor) OnOrderCanceled(order models.Order) []Flag {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	at := order.UpdatedAt
+	cutoff := at.Add(-m.config.CancelWindow)
+	activity := m.activity(order.AccountID)
+	activity.placed = trimBefore(activity.placed, cutoff)
+	activity.canceled = append(trimBefore(activity.canceled, cutoff), at)
+
+	placed := len(activity.placed)
+	if placed < m.config.CancelMinOrders || m.config.CancelRatio <= 0 {
+		return nil
+	}
+	ratio := float64(len(activity.canceled)) / float64(placed)
+	if ratio < m.config.CancelRatio || at.Sub(activity.lastFlagAt) < m.config.CancelWindow {
+		return nil
+	}
+	activity.lastFlagAt = at
+
+	return []Flag{m.raise(Flag{
+		Type:      FlagRapidCancel,
+		AccountID: order.AccountID,
+		Symbol:    order.Symbol,
+		OrderID:   order.ID,
+		Value:     ratio,
+		Threshold: m.config.CancelRatio,
+		Detail:    fmt.Sprintf("%d cancels against %d orders in %s", len(activity.canceled), placed, m.config.CancelWindow),
+		RaisedAt:  at,
+	})}
+}
+
+// OnTrades checks executions for self-crosses and prints near the price band
+func (m *Monitor) OnTrades(trades ...models.Trade) []Flag {
+	if len(trades) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var raised []Flag
+	for _, trade := range trades {
+		if trade.BuyAccountID != "" && trade.BuyAccountID == trade.SellAccountID {
+			raised = append(raised, m.raise(Flag{
+				Type:      FlagSelfCross,
+				AccountID: trade.BuyAccountID,
+				Symbol:    trade.Symbol,
+				TradeID:   trade.ID,
+				Detail:    fmt.Sprintf("orders %s and %s of the same account crossed", trade.BuyOrderID, trade.SellOrderID),
+				RaisedAt:  trade.ExecutedAt,
+			}))
+		}
+		raised = append(raised, m.checkBand(trade)...)
+	}
+	return raised
+}
+
+// checkBand compares the print with every price in the breaker window
+func (m *Monitor) checkBand(trade models.Trade) []Flag {
+	history := m.prices[trade.Symbol]
+	cutoff := trade.ExecutedAt.Add(-m.config.BandWindow)
+	for len(history) > 0 && history[0].at.Before(cutoff) {
+		history = history[1:]
+	}
+	m.prices[trade.Symbol] = append(history, pricePoint{price: trade.Price, at: trade.ExecutedAt})
+
+	level := m.config.BandThresholdPercent * m.config.BandProximity
+	if level <= 0 {
+		return nil
+	}
+	move := 0.0
+	for _, point := range history {
+		if candidate := (trade.Price - point.price) / point.price * 100; math.Abs(candidate) > math.Abs(move) {
+			move = candidate
+		}
+	}
+	if math.Abs(move) < level {
+		return nil
+	}
+
+	// The aggressor set the price; auction prints have none, so both sides are flagged
+	accounts := []string{trade.BuyAccountID, trade.SellAccountID}
+	switch trade.TakerSide {
+	case models.SideBuy:
+		accounts = accounts[:1]
+	case models.SideSell:
+		accounts = accounts[1:]
+	}
+
+	var raised []Flag
+	for i, account := range accounts {
+		if i > 0 && account == accounts[0] {
+			continue
+		}
+		raised = append(raised, m.raise(Flag{
+			Type:      FlagBandPrint,
+			AccountID: account,
+			Symbol:    trade.Symbol,
+			TradeID:   trade.ID,
+			Value:     move,
+			Threshold: level,
+			Detail:    fmt.Sprintf("printed %.2f%% from a price within %s, band is %.2f%%", move, m.config.BandWindow, m.config.BandThresholdPercent),
+			RaisedAt:  trade.ExecutedAt,
+		}))
+	}
+	return raised
+}
+
+// Flags returns raised flags oldest first, optionally for one account
+func (m *Monitor) Flags(accountID string) []Flag {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	flags := make([]Flag, 0)
+	for _, flag := range m.flags {
+		if accountID == "" || flag.AccountID == accountID {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// AccountSummary counts the flags raised against one account
+type AccountSummary struct {
+	AccountID  string           `json:"account_id"`
+	Flags      map[FlagType]int `json:"flags"`
+	LastFlagAt time.Time        `json:"last_flag_at"`
+}
+
+// FlaggedAccounts summarizes every account with at least one flag, most recently flagged first
+func (m *Monitor) FlaggedAccounts() []AccountSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byAccount := make(map[string]*AccountSummary)
+	summaries := make([]AccountSummary, 0)
+	order := make([]string, 0)
+	for i := len(m.flags) - 1; i >= 0; i-- {
+		flag := m.flags[i]
+		summary, exists := byAccount[flag.AccountID]
+		if !exists {
+			summary = &AccountSummary{AccountID: flag.AccountID, Flags: make(map[FlagType]int), LastFlagAt: flag.RaisedAt}
+			byAccount[flag.AccountID] = summary
+			order = append(order, flag.AccountID)
+		}
+		summary.Flags[flag.Type]++
+	}
+	for _, accountID := range order {
+		summaries = append(summaries, *byAccount[accountID])
+	}
+	return summaries
+}
+
+// AnonymizeAccount reassigns an erased account's flags and activity to alias
+func (m *Monitor) AnonymizeAccount(accountID, alias string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.flags {
+		if m.flags[i].AccountID == accountID {
+			m.flags[i].AccountID = alias
+		}
+	}
+	delete(m.accounts, accountID)
+}
+
+func (m *Monitor) activity(accountID string) *accountActivity {
+	activity, exists := m.accounts[accountID]
+	if !exists {
+		activity = &accountActivity{}
+		m.accounts[accountID] = activity
+	}
+	return activity
+}
+
+func (m *Monitor) raise(flag Flag) Flag {
+	m.nextID++
+	flag.ID = fmt.Sprintf("flag-%d", m.nextID)
+	m.flags = append(m.flags, flag)
+	if len(m.flags) > MaxFlags {
+		m.flags = m.flags[len(m.flags)-MaxFlags:]
+	}
+	return flag
+}
+
+// trimBefore drops timestamps older than cutoff from a time-ordered slice
+func trimBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
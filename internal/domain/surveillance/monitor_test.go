@@ -0,0 +1,136 @@
+//go:build unit
+
+package surveillance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+var start = time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+func trade(id string, price float64, buyer, seller string, taker models.Side, at time.Time) models.Trade {
+	return models.Trade{
+		ID:            id,
+		Symbol:        "BTC-USD",
+		Price:         price,
+		Quantity:      1,
+		BuyAccountID:  buyer,
+		SellAccountID: seller,
+		TakerSide:     taker,
+		ExecutedAt:    at,
+	}
+}
+
+func TestMonitor_SelfCross(t *testing.T) {
+	t.Run("flags_trades_between_one_account", func(t *testing.T) {
+		// Given: A monitor
+		monitor := NewMonitor(DefaultConfig())
+
+		// When: An account trades with itself and two others trade normally
+		flags := monitor.OnTrades(
+			trade("trd-1", 100, "wash", "wash", models.SideBuy, start),
+			trade("trd-2", 100, "a", "b", models.SideBuy, start),
+		)
+
+		// Then: Only the self-cross is flagged
+		if len(flags) != 1 || flags[0].Type != FlagSelfCross || flags[0].AccountID != "wash" || flags[0].TradeID != "trd-1" {
+			t.Errorf("Expected one self-cross flag, got %+v", flags)
+		}
+	})
+}
+
+func TestMonitor_RapidCancel(t *testing.T) {
+	t.Run("flags_once_the_ratio_is_reached", func(t *testing.T) {
+		// Given: An account placing 20 orders in a minute
+		monitor := NewMonitor(DefaultConfig())
+		for i := 0; i < 20; i++ {
+			monitor.OnOrderPlaced(models.Order{AccountID: "spoofer", CreatedAt: start.Add(time.Duration(i) * time.Second)})
+		}
+
+		// When: It cancels them one by one
+		var flags []Flag
+		for i := 0; i < 20; i++ {
+			at := start.Add(30*time.Second + time.Duration(i)*time.Second)
+			flags = append(flags, monitor.OnOrderCanceled(models.Order{ID: "ord", AccountID: "spoofer", Symbol: "BTC-USD", UpdatedAt: at})...)
+		}
+
+		// Then: One flag is raised when 18 of 20 are canceled
+		if len(flags) != 1 {
+			t.Fatalf("Expected one flag, got %d", len(flags))
+		}
+		if flags[0].Type != FlagRapidCancel || flags[0].Value != 0.9 {
+			t.Errorf("Unexpected flag: %+v", flags[0])
+		}
+	})
+
+	t.Run("ignores_accounts_below_the_minimum", func(t *testing.T) {
+		monitor := NewMonitor(DefaultConfig())
+		monitor.OnOrderPlaced(models.Order{AccountID: "small", CreatedAt: start})
+
+		flags := monitor.OnOrderCanceled(models.Order{AccountID: "small", UpdatedAt: start.Add(time.Second)})
+
+		if len(flags) != 0 {
+			t.Errorf("Expected no flags, got %+v", flags)
+		}
+	})
+}
+
+func TestMonitor_BandPrint(t *testing.T) {
+	t.Run("flags_the_aggressor_near_the_band", func(t *testing.T) {
+		// Given: A print at 100
+		monitor := NewMonitor(DefaultConfig())
+		monitor.OnTrades(trade("trd-1", 100, "a", "b", models.SideBuy, start))
+
+		// When: A buyer lifts the price 7% then 9% within the window
+		quiet := monitor.OnTrades(trade("trd-2", 107, "c", "d", models.SideBuy, start.Add(time.Minute)))
+		loud := monitor.OnTrades(trade("trd-3", 109, "e", "f", models.SideBuy, start.Add(2*time.Minute)))
+
+		// Then: Only the move past 80% of the 10% band is flagged, against the buyer
+		if len(quiet) != 0 {
+			t.Errorf("Expected no flag for a 7%% move, got %+v", quiet)
+		}
+		if len(loud) != 1 || loud[0].Type != FlagBandPrint || loud[0].AccountID != "e" {
+			t.Fatalf("Expected a band flag against the buyer, got %+v", loud)
+		}
+		if loud[0].Value < 8.99 || loud[0].Value > 9.01 {
+			t.Errorf("Expected a 9%% move, got %v", loud[0].Value)
+		}
+	})
+
+	t.Run("ignores_prices_outside_the_window", func(t *testing.T) {
+		monitor := NewMonitor(DefaultConfig())
+		monitor.OnTrades(trade("trd-1", 100, "a", "b", models.SideBuy, start))
+
+		flags := monitor.OnTrades(trade("trd-2", 109, "c", "d", models.SideBuy, start.Add(10*time.Minute)))
+
+		if len(flags) != 0 {
+			t.Errorf("Expected no flag once the old price left the window, got %+v", flags)
+		}
+	})
+}
+
+func TestMonitor_Accounts(t *testing.T) {
+	t.Run("summarizes_and_anonymizes_flagged_accounts", func(t *testing.T) {
+		// Given: Two self-crosses by one account
+		monitor := NewMonitor(DefaultConfig())
+		monitor.OnTrades(
+			trade("trd-1", 100, "wash", "wash", models.SideBuy, start),
+			trade("trd-2", 100, "wash", "wash", models.SideBuy, start.Add(time.Second)),
+		)
+
+		// When: The account is summarized, then anonymized
+		before := monitor.FlaggedAccounts()
+		monitor.AnonymizeAccount("wash", "anon-1")
+
+		// Then: The summary counts both and the flags move to the alias
+		if len(before) != 1 || before[0].Flags[FlagSelfCross] != 2 {
+			t.Errorf("Unexpected summary: %+v", before)
+		}
+		if len(monitor.Flags("wash")) != 0 || len(monitor.Flags("anon-1")) != 2 {
+			t.Error("Expected flags to be reassigned to the alias")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package synthetic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Model names the process a synthetic price follows
+type Model string
+
+const (
+	ModelGBM        Model = "gbm"         // Geometric Brownian motion: returns are normal, prices stay positive
+	ModelRandomWalk Model = "random_walk" // Arithmetic Brownian motion: moves scale with the start price
+)
+
+const (
+	// year is the time base drift and volatility are quoted in
+	year = 365 * 24 * time.Hour
+	// day is the time base jump intensity is quoted in
+	day = 24 * time.Hour
+	// minPriceFraction floors a random walk above zero, as a fraction of its start
+	minPriceFraction = 1e-4
+)
+
+// Dynamics parameterise a price path. Drift and volatility are annualised, so a
+// path steps the same distance per unit of venue time at any step size.
+type Dynamics struct {
+	Model         Model   `json:"model"`
+	Drift         float64 `json:"drift"`          // Expected return per year
+	Volatility    float64 `json:"volatility"`     // Standard deviation of returns per year
+	JumpIntensity float64 `json:"jump_intensity"` // Expected jumps per day (0 = none)
+	JumpMean      float64 `json:"jump_mean"`      // Mean log-return of a jump
+	JumpStdDev    float64 `json:"jump_std_dev"`   // Standard deviation of a jump's log-return
+}
+
+// Validate checks the dynamics describe a process a path can follow
+func (d Dynamics) Validate() error {
+	switch d.Model {
+	case ModelGBM, ModelRandomWalk:
+	default:
+		return fmt.Errorf("unknown model %q: expected gbm or random_walk", d.Model)
+	}
+	if d.Volatility < 0 || d.JumpIntensity < 0 || d.JumpStdDev < 0 {
+		return errors.New("volatility, jump intensity and jump deviation must not be negative")
+	}
+	return nil
+}
+
+// Path is one instrument's synthetic price. It is not safe for concurrent use.
+type Path struct {
+	dynamics Dynamics
+	start    float64
+	price    float64
+	jumps    int
+	rng      *rand.Rand
+}
+
+// NewPath starts a path at start, drawing its shocks from rng
+func NewPath(start float64, dynamics Dynamics, rng *rand.Rand) *Path {
+	return &Path{dynamics: dynamics, start: start, price: start, rng: rng}
+}
+
+// Price is where the path is now
+func (p *Path) Price() float64 {
+	return p.price
+}
+
+// Jumps is how many jumps the path has taken
+func (p *Path) Jumps() int {
+	return p.jumps
+}
+
+// Step advances the path by dt of venue time and returns the new price
+func (p *Path) Step(dt time.Duration) float64 {
+	if dt <= 0 {
+		return p.price
+	}
+	t := dt.Seconds() / year.Seconds()
+	drift, volatility := p.dynamics.Drift, p.dynamics.Volatility
+	shock := p.rng.NormFloat64() * math.Sqrt(t)
+
+	switch p.dynamics.Model {
+	case ModelRandomWalk:
+		p.price += p.start * (drift*t + volatility*shock)
+	default:
+		p.price *= math.Exp((drift-volatility*volatility/2)*t + volatility*shock)
+	}
+
+	if p.dynamics.JumpIntensity > 0 {
+		for n := p.poisson(p.dynamics.JumpIntensity * dt.Seconds() / day.Seconds()); n > 0; n-- {
+			p.price *= math.Exp(p.dynamics.JumpMean + p.dynamics.JumpStdDev*p.rng.NormFloat64())
+			p.jumps++
+		}
+	}
+	if floor := p.start * minPriceFraction; p.price < floor {
+		p.price = floor
+	}
+	return p.price
+}
+
+// poisson draws the number of events at rate mean (Knuth's method; steps are
+// short, so mean is small)
+func (p *Path) poisson(mean float64) int {
+	limit := math.Exp(-mean)
+	n, product := 0, p.rng.Float64()
+	for product > limit {
+		n++
+		product *= p.rng.Float64()
+	}
+	return n
+}
@@ -0,0 +1,81 @@
+//go:build unit
+
+package synthetic
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPath(t *testing.T) {
+	gbm := Dynamics{Model: ModelGBM, Volatility: 0.6}
+
+	t.Run("same_seed_same_path", func(t *testing.T) {
+		// Given: Two paths drawing from identically seeded sources
+		first := NewPath(60000, gbm, rand.New(rand.NewSource(42)))
+		second := NewPath(60000, gbm, rand.New(rand.NewSource(42)))
+
+		// When: Both take the same steps
+		for i := 0; i < 100; i++ {
+			// Then: They stay together, and move
+			if a, b := first.Step(time.Second), second.Step(time.Second); a != b {
+				t.Fatalf("Expected identical paths, diverged at step %d: %v vs %v", i, a, b)
+			}
+		}
+		if first.Price() == 60000 {
+			t.Errorf("Expected the path to move")
+		}
+	})
+
+	t.Run("drift_compounds_without_volatility", func(t *testing.T) {
+		// Given: A GBM path with 10% drift and no volatility
+		path := NewPath(100, Dynamics{Model: ModelGBM, Drift: 0.1}, rand.New(rand.NewSource(1)))
+
+		// When: It steps a year in two halves
+		path.Step(year / 2)
+		price := path.Step(year / 2)
+
+		// Then: It grew continuously at the drift
+		if want := 100 * math.Exp(0.1); math.Abs(price-want) > 1e-9 {
+			t.Errorf("Expected %v, got %v", want, price)
+		}
+	})
+
+	t.Run("jumps_arrive_at_their_intensity", func(t *testing.T) {
+		// Given: A path without diffusion jumping twice a day on average
+		path := NewPath(100, Dynamics{Model: ModelGBM, JumpIntensity: 2, JumpStdDev: 0.01}, rand.New(rand.NewSource(3)))
+
+		// When: It steps through 100 days in minutes
+		for i := 0; i < 100*24*60; i++ {
+			path.Step(time.Minute)
+		}
+
+		// Then: About 200 jumps were taken
+		if jumps := path.Jumps(); jumps < 150 || jumps > 250 {
+			t.Errorf("Expected about 200 jumps, got %d", jumps)
+		}
+	})
+
+	t.Run("random_walk_stays_positive", func(t *testing.T) {
+		// Given: A random walk with a large negative drift
+		path := NewPath(100, Dynamics{Model: ModelRandomWalk, Drift: -10}, rand.New(rand.NewSource(1)))
+
+		// When: It steps a year
+		price := path.Step(year)
+
+		// Then: It is floored above zero
+		if price != 100*minPriceFraction {
+			t.Errorf("Expected the floor, got %v", price)
+		}
+	})
+
+	t.Run("validate_rejects_unknown_models", func(t *testing.T) {
+		for _, dynamics := range []Dynamics{{Model: "ou"}, {Model: ModelGBM, Volatility: -1}, {Model: ModelGBM, JumpIntensity: -1}} {
+			if err := dynamics.Validate(); err == nil {
+				t.Errorf("Expected %+v to be rejected", dynamics)
+			}
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package timerwheel
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+)
+
+const (
+	// slotBits sizes every level at 64 slots, so a level's occupancy fits one word
+	slotBits = 6
+	slots    = 1 << slotBits
+	slotMask = slots - 1
+
+	// levels covers 2^36 ticks (about two years at 1ms); later deadlines wait in an
+	// overflow list until the wheel turns far enough to hold them
+	levels = 6
+
+	// DefaultPrecision is the tick used when none is given
+	DefaultPrecision = time.Millisecond
+)
+
+// Timer is one scheduled deadline and the value it yields when due
+type Timer[T any] struct {
+	Deadline time.Time
+	Value    T
+	tick     uint64
+	seq      uint64
+	canceled bool
+}
+
+// Wheel is a hierarchical timing wheel driven by whatever clock calls Advance, so
+// deadlines follow simulated time and cost no goroutine each. Each level has 64
+// slots of 64 times the width of the level below; a timer sits at the level of the
+// highest tick digit where its deadline differs from the wheel's position and
+// cascades down as the wheel turns. Precision only sizes the slots: a timer is
+// never returned before its deadline. A Wheel is not safe for concurrent use.
+type Wheel[T any] struct {
+	precision time.Duration
+	origin    time.Time
+	cursor    uint64 // Last tick the wheel has turned to
+	wheel     [levels][slots][]*Timer[T]
+	occupied  [levels]uint64 // Bit per slot holding timers
+	overflow  []*Timer[T]
+	pending   []*Timer[T] // Timers at or behind the cursor, waiting for their exact deadline
+	seq       uint64
+	live      int
+}
+
+// New starts a wheel at origin with slots precision wide (DefaultPrecision if not positive)
+func New[T any](precision time.Duration, origin time.Time) *Wheel[T] {
+	if precision <= 0 {
+		precision = DefaultPrecision
+	}
+	return &Wheel[T]{precision: precision, origin: origin}
+}
+
+// Precision is the width of the wheel's finest slots
+func (w *Wheel[T]) Precision() time.Duration {
+	return w.precision
+}
+
+// Len is how many timers are scheduled and not yet due or canceled
+func (w *Wheel[T]) Len() int {
+	return w.live
+}
+
+// Schedule queues value to be returned by the first Advance at or after deadline
+func (w *Wheel[T]) Schedule(deadline time.Time, value T) *Timer[T] {
+	w.seq++
+	timer := &Timer[T]{Deadline: deadline, Value: value, tick: w.tickOf(deadline), seq: w.seq}
+	w.insert(timer)
+	w.live++
+	return timer
+}
+
+// Cancel drops a timer that has not fired; canceling it again, or after it fired, does nothing
+func (w *Wheel[T]) Cancel(timer *Timer[T]) {
+	if timer == nil || timer.canceled {
+		return
+	}
+	timer.canceled = true
+	w.live--
+}
+
+// Advance turns the wheel to now and returns the values of every timer due by then,
+// earliest deadline first and in scheduling order between equal deadlines. Only
+// occupied slots are visited, so a large jump of the clock costs no more than the
+// timers it passes.
+func (w *Wheel[T]) Advance(now time.Time) []T {
+	target := w.tickOf(now)
+	for w.cursor < target {
+		w.cursor = w.nextTick(target)
+		w.cascade()
+	}
+
+	due := make([]*Timer[T], 0)
+	kept := w.pending[:0]
+	for _, timer := range w.pending {
+		switch {
+		case timer.canceled:
+		case !timer.Deadline.After(now):
+			due = append(due, timer)
+		default:
+			kept = append(kept, timer)
+		}
+	}
+	w.pending = kept
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].Deadline.Equal(due[j].Deadline) {
+			return due[i].Deadline.Before(due[j].Deadline)
+		}
+		return due[i].seq < due[j].seq
+	})
+	values := make([]T, 0, len(due))
+	for _, timer := range due {
+		timer.canceled = true // Fired; a later Cancel is a no-op
+		values = append(values, timer.Value)
+	}
+	w.live -= len(due)
+	return values
+}
+
+// Rescale rebuilds the wheel at a new precision starting from origin, keeping every
+// live timer. Moving the origin to the current time keeps deadlines the wheel was
+// built behind, such as after its clock is replaced, out of the pending list.
+func (w *Wheel[T]) Rescale(precision time.Duration, origin time.Time) *Wheel[T] {
+	rescaled := New[T](precision, origin)
+	for _, timer := range w.timers() {
+		rescaled.Schedule(timer.Deadline, timer.Value)
+	}
+	return rescaled
+}
+
+func (w *Wheel[T]) tickOf(at time.Time) uint64 {
+	if !at.After(w.origin) {
+		return 0
+	}
+	return uint64(at.Sub(w.origin) / w.precision)
+}
+
+// insert places a timer by where its tick first differs from the cursor
+func (w *Wheel[T]) insert(timer *Timer[T]) {
+	if timer.tick <= w.cursor {
+		w.pending = append(w.pending, timer)
+		return
+	}
+	level := (bits.Len64(timer.tick^w.cursor) - 1) / slotBits
+	if level >= levels {
+		w.overflow = append(w.overflow, timer)
+		return
+	}
+	slot := (timer.tick >> (level * slotBits)) & slotMask
+	w.wheel[level][slot] = append(w.wheel[level][slot], timer)
+	w.occupied[level] |= 1 << slot
+}
+
+// nextTick is the first tick after the cursor, and no later than target, at which an
+// occupied slot is reached or the overflow list can be taken in
+func (w *Wheel[T]) nextTick(target uint64) uint64 {
+	next := target
+	for level := 0; level < levels; level++ {
+		shift := uint(level * slotBits)
+		current := (w.cursor >> shift) & slotMask
+		ahead := w.occupied[level] &^ (1<<(current+1) - 1)
+		if current == slotMask || ahead == 0 {
+			continue
+		}
+		slot := uint64(bits.TrailingZeros64(ahead))
+		rotation := w.cursor >> (shift + slotBits) << (shift + slotBits)
+		if tick := rotation | slot<<shift; tick < next {
+			next = tick
+		}
+	}
+	if len(w.overflow) > 0 {
+		span := uint(levels * slotBits)
+		if boundary := (w.cursor>>span + 1) << span; boundary < next {
+			next = boundary
+		}
+	}
+	return next
+}
+
+// cascade re-inserts the timers of every slot the cursor has just reached, highest
+// level first, so they settle at lower levels or become pending
+func (w *Wheel[T]) cascade() {
+	span := uint(levels * slotBits)
+	if w.cursor&(1<<span-1) == 0 && len(w.overflow) > 0 {
+		overflow := w.overflow
+		w.overflow = nil
+		for _, timer := range overflow {
+			if !timer.canceled {
+				w.insert(timer)
+			}
+		}
+	}
+	for level := levels - 1; level >= 0; level-- {
+		shift := uint(level * slotBits)
+		if w.cursor&(1<<shift-1) != 0 {
+			continue
+		}
+		slot := (w.cursor >> shift) & slotMask
+		timers := w.wheel[level][slot]
+		if len(timers) == 0 {
+			continue
+		}
+		w.wheel[level][slot] = nil
+		w.occupied[level] &^= 1 << slot
+		for _, timer := range timers {
+			if !timer.canceled {
+				w.insert(timer)
+			}
+		}
+	}
+}
+
+func (w *Wheel[T]) timers() []*Timer[T] {
+	timers := make([]*Timer[T], 0, w.live)
+	collect := func(list []*Timer[T]) {
+		for _, timer := range list {
+			if !timer.canceled {
+				timers = append(timers, timer)
+			}
+		}
+	}
+	collect(w.pending)
+	collect(w.overflow)
+	for level := range w.wheel {
+		for slot := range w.wheel[level] {
+			collect(w.wheel[level][slot])
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].seq < timers[j].seq })
+	return timers
+}
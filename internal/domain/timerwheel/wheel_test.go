@@ -0,0 +1,107 @@
+//go:build unit
+
+package timerwheel
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWheel(t *testing.T) {
+	origin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("never_fires_before_the_deadline_within_a_slot", func(t *testing.T) {
+		// Given: A one-second wheel with a timer half way through a slot
+		wheel := New[string](time.Second, origin)
+		wheel.Schedule(origin.Add(1500*time.Millisecond), "a")
+
+		// When: The wheel turns to the start of the slot, then past the deadline
+		early := wheel.Advance(origin.Add(1200 * time.Millisecond))
+		due := wheel.Advance(origin.Add(1500 * time.Millisecond))
+
+		// Then: The timer fires only once its deadline is reached
+		if len(early) != 0 || !reflect.DeepEqual(due, []string{"a"}) || wheel.Len() != 0 {
+			t.Errorf("Expected nothing early and a at its deadline, got %v then %v", early, due)
+		}
+	})
+
+	t.Run("matches_a_sorted_queue_across_random_deadlines_and_clock_jumps", func(t *testing.T) {
+		// Given: Timers from a millisecond to beyond the wheel's span, scheduled as the
+		// clock moves, one in ten canceled
+		random := rand.New(rand.NewSource(7))
+		wheel := New[int](time.Millisecond, origin)
+		type entry struct {
+			deadline time.Time
+			value    int
+		}
+		expected := make([]entry, 0)
+		schedule := func(now time.Time, value int) {
+			deadline := now.Add(time.Duration(random.Int63n(int64(time.Duration(1) << uint(random.Intn(58))))))
+			timer := wheel.Schedule(deadline, value)
+			if value%10 == 0 {
+				wheel.Cancel(timer)
+				return
+			}
+			at := sort.Search(len(expected), func(i int) bool { return expected[i].deadline.After(deadline) })
+			expected = append(expected, entry{})
+			copy(expected[at+1:], expected[at:])
+			expected[at] = entry{deadline, value}
+		}
+
+		// When: The clock advances in jumps of every size until every timer is due
+		now, scheduled := origin, 0
+		fired := make([]int, 0)
+		for scheduled < 5000 || wheel.Len() > 0 {
+			for i := 0; i < 50 && scheduled < 5000; i++ {
+				schedule(now, scheduled)
+				scheduled++
+			}
+			now = now.Add(time.Duration(random.Int63n(int64(time.Duration(1) << uint(random.Intn(60))))))
+			fired = append(fired, wheel.Advance(now)...)
+
+			// Then: Exactly the timers whose deadlines have passed fire, in deadline order
+			for len(expected) > 0 && !expected[0].deadline.After(now) {
+				if len(fired) == 0 || fired[0] != expected[0].value {
+					t.Fatalf("At %v expected %d to fire next, got %v", now, expected[0].value, fired)
+				}
+				fired, expected = fired[1:], expected[1:]
+			}
+			if len(fired) != 0 {
+				t.Fatalf("At %v fired %v before their deadlines", now, fired)
+			}
+		}
+		if len(expected) != 0 {
+			t.Errorf("Expected every timer to fire, %d left", len(expected))
+		}
+	})
+
+	t.Run("keeps_live_timers_when_rescaled", func(t *testing.T) {
+		// Given: A millisecond wheel with two timers, one canceled
+		wheel := New[string](time.Millisecond, origin)
+		wheel.Schedule(origin.Add(time.Hour), "kept")
+		wheel.Cancel(wheel.Schedule(origin.Add(time.Minute), "canceled"))
+
+		// When: It is rebuilt with one-second slots and turned past both deadlines
+		rescaled := wheel.Rescale(time.Second, origin.Add(time.Second))
+		due := rescaled.Advance(origin.Add(2 * time.Hour))
+
+		// Then: Only the live timer fires
+		if rescaled.Precision() != time.Second || !reflect.DeepEqual(due, []string{"kept"}) {
+			t.Errorf("Expected only kept to fire, got %v", due)
+		}
+	})
+}
+
+func BenchmarkWheel_ScheduleAndAdvance(b *testing.B) {
+	origin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wheel := New[int](time.Millisecond, origin)
+	for i := 0; i < b.N; i++ {
+		wheel.Schedule(origin.Add(time.Duration(i%100000)*time.Millisecond+time.Hour), i)
+		if i%1000 == 0 {
+			wheel.Advance(origin.Add(time.Duration(i) * time.Millisecond))
+		}
+	}
+}
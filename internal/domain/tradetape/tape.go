@@ -0,0 +1,165 @@
+package tradetape
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrInvalidCursor is returned for a cursor this venue did not issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Store persists every executed trade and pages through them
+type Store interface {
+	Append(trades []models.Trade) error
+	Query(query Query) (Page, error)
+}
+
+// Cursor is the position after a trade in tape order: execution time, then trade ID
+type Cursor struct {
+	ExecutedAt time.Time
+	TradeID    string
+}
+
+// IsZero reports whether the cursor is unset, starting from the oldest trade
+func (c Cursor) IsZero() bool {
+	return c.ExecutedAt.IsZero() && c.TradeID == ""
+}
+
+// String encodes the cursor opaquely for clients to send back
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.ExecutedAt.UnixNano(), 10) + ":" + c.TradeID))
+}
+
+// Precedes reports whether trade comes after the cursor in tape order
+func (c Cursor) Precedes(trade models.Trade) bool {
+	if c.IsZero() {
+		return true
+	}
+	if !trade.ExecutedAt.Equal(c.ExecutedAt) {
+		return trade.ExecutedAt.After(c.ExecutedAt)
+	}
+	return trade.ID > c.TradeID
+}
+
+// Follows reports whether trade comes before the cursor in tape order, which is
+// after it when paging newest first
+func (c Cursor) Follows(trade models.Trade) bool {
+	if c.IsZero() {
+		return true
+	}
+	if !trade.ExecutedAt.Equal(c.ExecutedAt) {
+		return trade.ExecutedAt.Before(c.ExecutedAt)
+	}
+	return trade.ID < c.TradeID
+}
+
+// ParseCursor decodes a cursor from a previous page; empty is the zero cursor
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	nanos, tradeID, ok := strings.Cut(string(raw), ":")
+	executedAt, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil || tradeID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{ExecutedAt: time.Unix(0, executedAt).UTC(), TradeID: tradeID}, nil
+}
+
+// CursorOf is the position after trade
+func CursorOf(trade models.Trade) Cursor {
+	return Cursor{ExecutedAt: trade.ExecutedAt, TradeID: trade.ID}
+}
+
+// Query selects trades in tape order, or its reverse; empty fields match anything
+type Query struct {
+	Symbol     string
+	AccountID  string    // Either side
+	From       time.Time // Executed at or after
+	To         time.Time // Executed before
+	Descending bool      // Newest first
+	After      Cursor    // Continues a previous page
+	Limit      int
+}
+
+// Validate checks the time range and limit
+func (q Query) Validate() error {
+	if q.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return errors.New("start time must be before end time")
+	}
+	return nil
+}
+
+// Matches reports whether trade is selected, ignoring the cursor and limit
+func (q Query) Matches(trade models.Trade) bool {
+	if q.Symbol != "" && trade.Symbol != q.Symbol {
+		return false
+	}
+	if q.AccountID != "" && trade.BuyAccountID != q.AccountID && trade.SellAccountID != q.AccountID {
+		return false
+	}
+	if !q.From.IsZero() && trade.ExecutedAt.Before(q.From) {
+		return false
+	}
+	return q.To.IsZero() || trade.ExecutedAt.Before(q.To)
+}
+
+// Continues reports whether trade comes after the query's cursor in its direction
+func (q Query) Continues(trade models.Trade) bool {
+	if q.Descending {
+		return q.After.Follows(trade)
+	}
+	return q.After.Precedes(trade)
+}
+
+// Page is one page of trades, oldest first unless the query was descending
+type Page struct {
+	Trades     []models.Trade `json:"trades"`
+	NextCursor string         `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// Paginate pages through trades held in memory the way a store pages through its own
+func Paginate(trades []models.Trade, query Query) Page {
+	sorted := make([]models.Trade, 0, len(trades))
+	for _, trade := range trades {
+		if query.Matches(trade) && query.Continues(trade) {
+			sorted = append(sorted, trade)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if query.Descending {
+			a, b = b, a
+		}
+		if !a.ExecutedAt.Equal(b.ExecutedAt) {
+			return a.ExecutedAt.Before(b.ExecutedAt)
+		}
+		return a.ID < b.ID
+	})
+	return NewPage(sorted, query.Limit)
+}
+
+// NewPage cuts trades fetched in the query's order, up to one beyond limit, into a page
+func NewPage(trades []models.Trade, limit int) Page {
+	if len(trades) <= limit {
+		return Page{Trades: trades}
+	}
+	trades = trades[:limit]
+	return Page{Trades: trades, NextCursor: CursorOf(trades[limit-1]).String()}
+}
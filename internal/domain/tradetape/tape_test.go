@@ -0,0 +1,91 @@
+//go:build unit
+
+package tradetape
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestPaginate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	trades := []models.Trade{
+		{ID: "t-3", Symbol: "BTC-USD", BuyAccountID: "a", SellAccountID: "b", ExecutedAt: start.Add(time.Second)},
+		{ID: "t-1", Symbol: "BTC-USD", BuyAccountID: "a", SellAccountID: "c", ExecutedAt: start},
+		{ID: "t-2", Symbol: "BTC-USD", BuyAccountID: "c", SellAccountID: "a", ExecutedAt: start},
+		{ID: "t-4", Symbol: "ETH-USD", BuyAccountID: "a", SellAccountID: "b", ExecutedAt: start.Add(2 * time.Second)},
+		{ID: "t-5", Symbol: "BTC-USD", BuyAccountID: "b", SellAccountID: "c", ExecutedAt: start.Add(3 * time.Second)},
+	}
+
+	t.Run("cursors_walk_every_matching_trade_once_in_tape_order", func(t *testing.T) {
+		// Given: Account a's BTC-USD trades, two sharing an execution time
+		query := Query{Symbol: "BTC-USD", AccountID: "a", Limit: 2}
+
+		// When: The pages are followed through their cursors
+		var ids []string
+		pages := 0
+		for {
+			page := Paginate(trades, query)
+			pages++
+			for _, trade := range page.Trades {
+				ids = append(ids, trade.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor, err := ParseCursor(page.NextCursor)
+			if err != nil {
+				t.Fatalf("Expected the issued cursor to parse, got %v", err)
+			}
+			query.After = cursor
+		}
+
+		// Then: Each trade appears once, ordered by time then ID
+		if pages != 2 || len(ids) != 3 || ids[0] != "t-1" || ids[1] != "t-2" || ids[2] != "t-3" {
+			t.Errorf("Expected t-1, t-2 then t-3 over two pages, got %v over %d", ids, pages)
+		}
+	})
+
+	t.Run("time_range_is_half_open", func(t *testing.T) {
+		page := Paginate(trades, Query{From: start.Add(time.Second), To: start.Add(3 * time.Second), Limit: 10})
+
+		if len(page.Trades) != 2 || page.Trades[0].ID != "t-3" || page.Trades[1].ID != "t-4" || page.NextCursor != "" {
+			t.Errorf("Expected t-3 and t-4 on one page, got %+v", page)
+		}
+	})
+
+	t.Run("descending_pages_walk_back_from_the_newest", func(t *testing.T) {
+		// Given: The first page of BTC-USD trades, newest first
+		query := Query{Symbol: "BTC-USD", Descending: true, Limit: 3}
+		first := Paginate(trades, query)
+
+		// When: Its cursor is followed
+		query.After, _ = ParseCursor(first.NextCursor)
+		second := Paginate(trades, query)
+
+		// Then: Trades sharing a time are ordered by ID descending too
+		if len(first.Trades) != 3 || first.Trades[0].ID != "t-5" || first.Trades[2].ID != "t-2" {
+			t.Errorf("Expected t-5, t-3, t-2 first, got %+v", first.Trades)
+		}
+		if len(second.Trades) != 1 || second.Trades[0].ID != "t-1" || second.NextCursor != "" {
+			t.Errorf("Expected t-1 alone on the last page, got %+v", second)
+		}
+	})
+
+	t.Run("rejects_foreign_cursors_and_bad_queries", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm9wZQ"} {
+			if _, err := ParseCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected %q to be rejected, got %v", cursor, err)
+			}
+		}
+		if err := (Query{Limit: 0}).Validate(); err == nil {
+			t.Error("Expected a zero limit to be rejected")
+		}
+		if err := (Query{From: start, To: start, Limit: 1}).Validate(); err == nil {
+			t.Error("Expected an empty time range to be rejected")
+		}
+	})
+}
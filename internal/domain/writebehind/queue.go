@@ -0,0 +1,211 @@
+// Package writebehind buffers writes to slow stores so the caller never waits on
+// the store, only, when the store falls behind, for room in a bounded queue.
+package writebehind
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Config bounds a queue and sets how much of it one write takes
+type Config struct {
+	Capacity  int           // Items held before producers wait for room
+	BatchSize int           // Most items one write takes; a full batch is written early
+	MaxWait   time.Duration // Longest a producer waits for room before queueing anyway (0 = until there is room)
+}
+
+// DefaultConfig holds 10000 items, writes 500 at a time and waits up to a second for room
+func DefaultConfig() Config {
+	return Config{Capacity: 10000, BatchSize: 500, MaxWait: time.Second}
+}
+
+// Stats is a queue's depth and what it has taken and written since startup
+type Stats struct {
+	Queued      int       `json:"queued"`
+	Capacity    int       `json:"capacity"`
+	HighWater   int       `json:"high_water"` // Deepest the queue has been
+	Enqueued    uint64    `json:"enqueued"`
+	Written     uint64    `json:"written"`
+	Batches     uint64    `json:"batches"`
+	Failed      uint64    `json:"failed_batches"` // Put back to be retried
+	Waits       uint64    `json:"waits"`          // Producers that found the queue full
+	WaitedMs    float64   `json:"waited_ms"`      // Spent by them waiting for room
+	Overflowed  uint64    `json:"overflowed"`     // Queued past capacity after MaxWait
+	LastWriteAt time.Time `json:"last_write_at,omitempty"`
+}
+
+// Wait is how long one producer waited for room
+type Wait struct {
+	Waited     time.Duration
+	Overflowed bool // It queued past capacity after MaxWait
+}
+
+// Queue holds items between writes in the order they were put. A failed write puts
+// its batch back at the front, so items are written in order and none are lost;
+// producers waiting for room are the backpressure that tells the store is behind.
+type Queue[T any] struct {
+	config Config
+	ready  chan struct{} // Signaled when a full batch is queued
+
+	mu    sync.Mutex
+	items []T
+	room  chan struct{} // Closed, and replaced, when items are taken
+	stats Stats
+}
+
+// NewQueue creates an empty queue, defaulting unset limits to DefaultConfig's
+func NewQueue[T any](config Config) *Queue[T] {
+	defaults := DefaultConfig()
+	if config.Capacity <= 0 {
+		config.Capacity = defaults.Capacity
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	return &Queue[T]{
+		config: config,
+		ready:  make(chan struct{}, 1),
+		room:   make(chan struct{}),
+		stats:  Stats{Capacity: config.Capacity},
+	}
+}
+
+// Config returns the queue's limits
+func (q *Queue[T]) Config() Config {
+	return q.config
+}
+
+// Put queues item, first waiting for room while the queue is full
+func (q *Queue[T]) Put(item T) Wait {
+	var wait Wait
+	var deadline <-chan time.Time
+	started := time.Now()
+	for {
+		q.mu.Lock()
+		if len(q.items) < q.config.Capacity || wait.Overflowed {
+			q.items = append(q.items, item)
+			q.stats.Enqueued++
+			if len(q.items) > q.stats.HighWater {
+				q.stats.HighWater = len(q.items)
+			}
+			if wait.Waited > 0 || wait.Overflowed {
+				q.stats.Waits++
+				q.stats.WaitedMs += float64(wait.Waited.Microseconds()) / 1000
+			}
+			if wait.Overflowed {
+				q.stats.Overflowed++
+			}
+			full := len(q.items) >= q.config.BatchSize
+			q.mu.Unlock()
+			if full {
+				select {
+				case q.ready <- struct{}{}:
+				default:
+				}
+			}
+			return wait
+		}
+		room := q.room
+		q.mu.Unlock()
+
+		if deadline == nil && q.config.MaxWait > 0 {
+			timer := time.NewTimer(q.config.MaxWait)
+			defer timer.Stop() // Set once: deadline is no longer nil
+			deadline = timer.C
+		}
+		select {
+		case <-room:
+		case <-deadline:
+			wait.Overflowed = true
+		}
+		wait.Waited = time.Since(started)
+	}
+}
+
+// Take removes up to a batch of items from the front
+func (q *Queue[T]) Take() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.items)
+	if n > q.config.BatchSize {
+		n = q.config.BatchSize
+	}
+	if n == 0 {
+		return nil
+	}
+	batch := append([]T(nil), q.items[:n]...)
+	q.items = append(q.items[:0:0], q.items[n:]...)
+	close(q.room)
+	q.room = make(chan struct{})
+	return batch
+}
+
+// Done records the outcome of writing a taken batch; a failed batch goes back to the
+// front, past capacity if need be, to be retried whole
+func (q *Queue[T]) Done(batch []T, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats.Batches++
+	if err != nil {
+		q.stats.Failed++
+		q.items = append(append(make([]T, 0, len(batch)+len(q.items)), batch...), q.items...)
+		return
+	}
+	q.stats.Written += uint64(len(batch))
+	q.stats.LastWriteAt = time.Now()
+}
+
+// Flush writes the items queued when it is called, a batch at a time, stopping at
+// the first failure. Called once producers have stopped, it drains the queue.
+func (q *Queue[T]) Flush(write func(batch []T) error) error {
+	for remaining := q.Len(); remaining > 0; {
+		batch := q.Take()
+		if len(batch) == 0 {
+			return nil
+		}
+		err := write(batch)
+		q.Done(batch, err)
+		if err != nil {
+			return err
+		}
+		remaining -= len(batch)
+	}
+	return nil
+}
+
+// Len returns how many items are queued
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Stats returns the queue's depth and counters
+func (q *Queue[T]) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Queued = len(q.items)
+	return stats
+}
+
+// Run calls write every interval, and as soon as a full batch is queued, until ctx is
+// done; failed is told of each error. The final drain is the caller's, once producers
+// have stopped.
+func (q *Queue[T]) Run(ctx context.Context, interval time.Duration, write func() error, failed func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.ready:
+		}
+		if err := write(); err != nil {
+			failed(err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package writebehind
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	t.Run("writes_in_batches_in_order", func(t *testing.T) {
+		// Given: Five items on a queue writing two at a time
+		queue := NewQueue[int](Config{Capacity: 10, BatchSize: 2})
+		for i := 1; i <= 5; i++ {
+			queue.Put(i)
+		}
+
+		// When: It is flushed
+		var batches [][]int
+		err := queue.Flush(func(batch []int) error {
+			batches = append(batches, batch)
+			return nil
+		})
+
+		// Then: Every item is written once, in order, in batches of two
+		if err != nil || !reflect.DeepEqual(batches, [][]int{{1, 2}, {3, 4}, {5}}) {
+			t.Errorf("Unexpected batches %v, %v", batches, err)
+		}
+		if stats := queue.Stats(); stats.Queued != 0 || stats.Written != 5 || stats.Batches != 3 || stats.HighWater != 5 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("failed_batch_is_retried_first", func(t *testing.T) {
+		// Given: A queued batch whose write fails, and an item put after it
+		queue := NewQueue[int](Config{Capacity: 10, BatchSize: 2})
+		queue.Put(1)
+		queue.Put(2)
+		err := queue.Flush(func(batch []int) error { return errors.New("store down") })
+		queue.Put(3)
+
+		// When: The store recovers
+		var written []int
+		queue.Flush(func(batch []int) error {
+			written = append(written, batch...)
+			return nil
+		})
+
+		// Then: Nothing was lost or reordered, and the failure was counted
+		if err == nil || !reflect.DeepEqual(written, []int{1, 2, 3}) {
+			t.Errorf("Expected the failure reported and 1, 2, 3 written, got %v, %v", err, written)
+		}
+		if stats := queue.Stats(); stats.Failed != 1 || stats.Written != 3 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("producer_waits_for_room", func(t *testing.T) {
+		// Given: A full queue
+		queue := NewQueue[int](Config{Capacity: 1, BatchSize: 1})
+		queue.Put(1)
+
+		// When: Another item is put and a batch is taken while it waits
+		done := make(chan Wait)
+		go func() { done <- queue.Put(2) }()
+		time.Sleep(20 * time.Millisecond)
+		taken := queue.Take()
+
+		// Then: The producer queued its item once there was room, and its wait counts
+		wait := <-done
+		if len(taken) != 1 || wait.Waited <= 0 || wait.Overflowed {
+			t.Errorf("Expected a wait for room, got %+v after taking %v", wait, taken)
+		}
+		if stats := queue.Stats(); stats.Queued != 1 || stats.Waits != 1 || stats.WaitedMs <= 0 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("overflows_after_max_wait", func(t *testing.T) {
+		// Given: A full queue nothing is writing
+		queue := NewQueue[int](Config{Capacity: 1, BatchSize: 1, MaxWait: 10 * time.Millisecond})
+		queue.Put(1)
+
+		// When: Another item is put
+		wait := queue.Put(2)
+
+		// Then: It is queued past capacity rather than lost
+		if !wait.Overflowed || queue.Len() != 2 {
+			t.Errorf("Expected an overflow, got %+v with %d queued", wait, queue.Len())
+		}
+		if stats := queue.Stats(); stats.Overflowed != 1 {
+			t.Errorf("Expected the overflow counted, got %+v", stats)
+		}
+	})
+}
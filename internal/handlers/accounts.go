@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// AccountHandler exposes the account lifecycle, positions, and data erasure for testing
+// purge workflows
+type AccountHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewAccountHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *AccountHandler {
+	return &AccountHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// AccountProfileRequest sets an account's simulated connectivity
+type AccountProfileRequest struct {
+	Latency  string `json:"latency"`  // Go duration, e.g. "50us" or "20ms"; empty means none
+	Priority int    `json:"priority"` // Queue tier for new orders; 0 is plain price-time
+}
+
+// Create opens an account under a venue-generated ID
+func (h *AccountHandler) Create(c *gin.Context) {
+	var req services.NewAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	account, err := h.exchangeService.CreateAccount(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// List returns opened accounts oldest first; query params: status, tier
+func (h *AccountHandler) List(c *gin.Context) {
+	list, err := h.exchangeService.Accounts(c.Request.Context(), accounts.Status(c.Query("status")), c.Query("tier"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"accounts": list})
+}
+
+// Get returns one opened account
+func (h *AccountHandler) Get(c *gin.Context) {
+	account, err := h.exchangeService.Account(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// Update reassigns an account's tier, metadata and leverage limit
+func (h *AccountHandler) Update(c *gin.Context) {
+	var update services.AccountUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	account, err := h.exchangeService.UpdateAccount(c.Request.Context(), c.Param("account_id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// Close closes an account and cancels its working orders
+func (h *AccountHandler) Close(c *gin.Context) {
+	closure, err := h.exchangeService.CloseAccount(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, closure)
+}
+
+// BulkAccountRequest provisions count accounts from one template
+type BulkAccountRequest struct {
+	Template accounts.Template `json:"template"`
+	Count    int               `json:"count"`
+}
+
+// Provision opens accounts in bulk and returns each with its API key, which is not
+// shown again
+func (h *AccountHandler) Provision(c *gin.Context) {
+	var req BulkAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	provisioned, err := h.exchangeService.ProvisionAccounts(c.Request.Context(), req.Template, req.Count)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"accounts": provisioned})
+}
+
+// Purge erases an account and returns its tombstone
+func (h *AccountHandler) Purge(c *gin.Context) {
+	purge, err := h.exchangeService.PurgeAccount(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Account purge failed")
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, purge)
+}
+
+// Tombstones lists completed account purges
+func (h *AccountHandler) Tombstones(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"tombstones": h.exchangeService.AccountTombstones(c.Request.Context()),
+	})
+}
+
+// Activity returns accounts ranked by what they have done since startup; query
+// params: sort (notional, trades, orders or rejection_ratio), limit
+func (h *AccountHandler) Activity(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			invalidRequest(c, fmt.Errorf("limit must be a number"))
+			return
+		}
+	}
+	accounts, err := h.exchangeService.AccountActivityLeaderboard(c.Request.Context(), c.Query("sort"), limit)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+}
+
+// Ledger returns an account's per-asset positions netted across books
+func (h *AccountHandler) Ledger(c *gin.Context) {
+	ledger, err := h.exchangeService.AccountLedger(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, ledger)
+}
+
+// Margin returns an account's derivative positions, equity, borrowing and margin
+// ratio per settlement asset
+func (h *AccountHandler) Margin(c *gin.Context) {
+	accountMargin, err := h.exchangeService.AccountMargin(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, accountMargin)
+}
+
+// Snapshot returns an account's balances, positions and open orders as of one
+// engine sequence
+func (h *AccountHandler) Snapshot(c *gin.Context) {
+	snapshot, err := h.exchangeService.AccountSnapshot(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// Funding lists an account's perpetual funding payments, filtered by the optional
+// symbol query parameter
+func (h *AccountHandler) Funding(c *gin.Context) {
+	payments, err := h.exchangeService.FundingPayments(c.Request.Context(), c.Param("account_id"), c.Query("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"payments": payments})
+}
+
+// Liquidations lists positions the venue closed out, filtered by the optional
+// account_id query parameter
+func (h *AccountHandler) Liquidations(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"liquidations": h.exchangeService.Liquidations(c.Request.Context(), c.Query("account_id")),
+	})
+}
+
+// Insurance returns the insurance fund's balances and the positions deleveraged once
+// it ran out, optionally for ?account_id= on either side
+func (h *AccountHandler) Insurance(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.InsuranceFund(c.Request.Context(), c.Query("account_id")))
+}
+
+// Balances returns an account's available and held balance per asset, with the
+// holds of its working orders
+func (h *AccountHandler) Balances(c *gin.Context) {
+	balances, err := h.exchangeService.AccountBalances(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// Journal returns an account's latest balance postings, newest first, up to ?limit=
+// (default 100)
+func (h *AccountHandler) Journal(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTradeLimit)))
+	if err != nil || limit <= 0 || limit > maxTradeLimit {
+		invalidRequest(c, fmt.Errorf("limit must be between 1 and %d", maxTradeLimit))
+		return
+	}
+	postings, err := h.exchangeService.BalanceJournal(c.Request.Context(), c.Param("account_id"), limit)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": c.Param("account_id"),
+		"postings":   postings,
+	})
+}
+
+// TrialBalance sums the balance journal by asset over every account
+func (h *AccountHandler) TrialBalance(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.TrialBalance(c.Request.Context()))
+}
+
+// Valuation values an account's positions in ?currency= (default: the reporting currency)
+func (h *AccountHandler) Valuation(c *gin.Context) {
+	valuation, err := h.exchangeService.AccountValuation(c.Request.Context(), c.Param("account_id"), c.Query("currency"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, valuation)
+}
+
+// Profiles lists accounts with a non-default latency or queue tier
+func (h *AccountHandler) Profiles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"profiles": h.exchangeService.AccountProfiles(c.Request.Context()),
+	})
+}
+
+// SetProfile simulates an account as colocated or remote
+func (h *AccountHandler) SetProfile(c *gin.Context) {
+	var body AccountProfileRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	profile := services.AccountProfile{AccountID: c.Param("account_id"), Priority: body.Priority}
+	if body.Latency != "" {
+		latency, err := time.ParseDuration(body.Latency)
+		if err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		profile.Latency = latency
+	}
+
+	profile, err := h.exchangeService.SetAccountProfile(c.Request.Context(), profile)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// RemoveProfile returns an account to the default profile
+func (h *AccountHandler) RemoveProfile(c *gin.Context) {
+	if err := h.exchangeService.RemoveAccountProfile(c.Request.Context(), c.Param("account_id")); err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,299 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func TestAccountHandler(t *testing.T) {
+	t.Run("creates_updates_and_closes_accounts", func(t *testing.T) {
+		// Given: The account and order routes
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		accountHandler := handlers.NewAccountHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/api/v1/accounts", accountHandler.Create)
+		router.GET("/api/v1/accounts", accountHandler.List)
+		router.GET("/api/v1/accounts/:account_id", accountHandler.Get)
+		router.PATCH("/api/v1/accounts/:account_id", accountHandler.Update)
+		router.DELETE("/api/v1/accounts/:account_id", accountHandler.Close)
+		router.POST("/api/v1/orders", orderHandler.Place)
+
+		// When: An account is created, retiered, closed, and then tries to trade
+		created := serve(router, http.MethodPost, "/api/v1/accounts", `{"metadata":{"desk":"rates"}}`)
+		var account accounts.Account
+		json.Unmarshal(created.Body.Bytes(), &account)
+		path := "/api/v1/accounts/" + account.ID
+		updated := serve(router, http.MethodPatch, path, `{"tier":"vip"}`)
+		closed := serve(router, http.MethodDelete, path, "")
+		closedAgain := serve(router, http.MethodDelete, path, "")
+		order := serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"`+account.ID+`","symbol":"BTC-USD","side":"BUY","type":"LIMIT","quantity":1,"price":60000}`)
+		listed := serve(router, http.MethodGet, "/api/v1/accounts?status=closed&tier=vip", "")
+		unknown := serve(router, http.MethodGet, "/api/v1/accounts/not-an-account", "")
+
+		// Then: The venue assigned the ID and default tier, kept the metadata, and
+		// refuses the closed account's orders and a second close
+		if created.Code != http.StatusCreated || account.ID == "" || account.Tier != accounts.DefaultTier || account.Metadata["desk"] != "rates" {
+			t.Fatalf("Expected a created standard account, got %d: %s", created.Code, created.Body.String())
+		}
+		if updated.Code != http.StatusOK || closed.Code != http.StatusOK {
+			t.Errorf("Expected the update and close to succeed, got %d and %d", updated.Code, closed.Code)
+		}
+		if closedAgain.Code != http.StatusConflict {
+			t.Errorf("Expected a second close to conflict, got %d", closedAgain.Code)
+		}
+		var body map[string]string
+		json.Unmarshal(order.Body.Bytes(), &body)
+		if order.Code != http.StatusConflict || body["code"] != string(services.RejectInvalidAccount) {
+			t.Errorf("Expected the closed account's order to be refused, got %d: %s", order.Code, order.Body.String())
+		}
+		var list struct {
+			Accounts []accounts.Account `json:"accounts"`
+		}
+		json.Unmarshal(listed.Body.Bytes(), &list)
+		if len(list.Accounts) != 1 || list.Accounts[0].ID != account.ID || list.Accounts[0].Metadata["desk"] != "rates" {
+			t.Errorf("Expected the closed vip account listed, got %s", listed.Body.String())
+		}
+		if unknown.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for an unknown account, got %d", unknown.Code)
+		}
+	})
+
+	t.Run("provisions_accounts_in_bulk_with_keys", func(t *testing.T) {
+		// Given: The bulk provisioning route
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		accountHandler := handlers.NewAccountHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/api/v1/admin/accounts/bulk", accountHandler.Provision)
+
+		// When: Two hundred accounts are provisioned, then a template with an unknown permission
+		w := serve(router, http.MethodPost, "/api/v1/admin/accounts/bulk",
+			`{"template":{"tier":"mm","balances":{"USD":50000},"permissions":["read","trade"]},"count":200}`)
+		invalid := serve(router, http.MethodPost, "/api/v1/admin/accounts/bulk",
+			`{"template":{"permissions":["admin"]},"count":1}`)
+
+		// Then: Every account comes back with its tier, balances and an API key
+		var body struct {
+			Accounts []struct {
+				accounts.Account
+				APIKey string `json:"api_key"`
+			} `json:"accounts"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != http.StatusCreated || len(body.Accounts) != 200 {
+			t.Fatalf("Expected 200 accounts created, got %d: %d", w.Code, len(body.Accounts))
+		}
+		first := body.Accounts[0]
+		if first.Tier != "mm" || first.Balances["USD"] != 50000 || first.APIKey == "" || strings.Contains(w.Body.String(), accounts.HashAPIKey(first.APIKey)) {
+			t.Errorf("Expected a funded mm account with a key and no key hash, got %+v", first)
+		}
+		if invalid.Code != http.StatusBadRequest {
+			t.Errorf("Expected an unknown permission to be rejected, got %d", invalid.Code)
+		}
+	})
+
+	t.Run("signs_requests_with_account_keys_and_audits_failures", func(t *testing.T) {
+		// Given: An account holding a read-only key, behind the signature middleware
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		apiKeyHandler := handlers.NewAPIKeyHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()), apiKeyHandler.Authenticate)
+		router.POST("/api/v1/accounts/:account_id/api-keys", apiKeyHandler.Create)
+		router.DELETE("/api/v1/accounts/:account_id/api-keys/:key_id", apiKeyHandler.Revoke)
+		router.POST("/api/v1/orders", orderHandler.Place)
+		router.GET("/api/v1/admin/access-denials", apiKeyHandler.Denials)
+		keyPath := "/api/v1/accounts/" + account.ID + "/api-keys"
+		created := serve(router, http.MethodPost, keyPath, `{"label":"reader","permissions":["read"]}`)
+		var issued services.IssuedKey
+		json.Unmarshal(created.Body.Bytes(), &issued)
+		signer := accounts.Key{Secret: issued.Secret}
+		order := `{"account_id":"` + account.ID + `","symbol":"BTC-USD","side":"BUY","type":"LIMIT","quantity":1,"price":60000}`
+		place := func(signature string) *httptest.ResponseRecorder {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			if signature == "" {
+				signature = signer.Sign(accounts.SigningPayload(timestamp, http.MethodPost, "/api/v1/orders", []byte(order)))
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(order))
+			req.Header.Set(observability.APIKeyHeader, issued.ID)
+			req.Header.Set(handlers.TimestampHeader, timestamp)
+			req.Header.Set(handlers.SignatureHeader, signature)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// When: It places an order signed correctly, then with a forged signature, then
+		// signed correctly after the key is revoked
+		signed := place("")
+		forged := place(strings.Repeat("ab", 32))
+		revoked := serve(router, http.MethodDelete, keyPath+"/"+issued.ID, "")
+		afterRevoke := place("")
+		denials := serve(router, http.MethodGet, "/api/v1/admin/access-denials", "")
+
+		// Then: The secret is returned once, the signed order lacks the trade permission,
+		// the others fail authentication, and every refusal is audited
+		if created.Code != http.StatusCreated || issued.Secret == "" || !accounts.IsKeyID(issued.ID) {
+			t.Fatalf("Expected a key issued with its secret, got %d: %s", created.Code, created.Body.String())
+		}
+		if signed.Code != http.StatusForbidden || forged.Code != http.StatusUnauthorized || afterRevoke.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 403, 401 and 401, got %d, %d and %d", signed.Code, forged.Code, afterRevoke.Code)
+		}
+		if revoked.Code != http.StatusOK {
+			t.Errorf("Expected the key to be revoked, got %d", revoked.Code)
+		}
+		var audit struct {
+			Denials []services.AccessDenial `json:"denials"`
+		}
+		json.Unmarshal(denials.Body.Bytes(), &audit)
+		if len(audit.Denials) != 3 || audit.Denials[0].Reason != services.RejectPermissionDenied ||
+			audit.Denials[1].Reason != services.RejectUnauthenticated || audit.Denials[2].AccountID != account.ID {
+			t.Errorf("Expected three audited denials, got %+v", audit.Denials)
+		}
+	})
+
+	t.Run("issues_keys_to_the_admin_token_once_set", func(t *testing.T) {
+		// Given: Key routes on a venue with an admin token
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator", AdminToken: "s3cret"}, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		apiKeyHandler := handlers.NewAPIKeyHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()), handlers.OperatorIdentity("s3cret"))
+		router.POST("/api/v1/accounts/:account_id/api-keys", apiKeyHandler.Create)
+		create := func(token string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/"+account.ID+"/api-keys", strings.NewReader(`{"label":"bot"}`))
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		// When: A key is requested without, with a wrong and with the admin token
+		missing, wrong, right := create(""), create("guess"), create("s3cret")
+
+		// Then: Only the operator gets one
+		if missing != http.StatusUnauthorized || wrong != http.StatusUnauthorized || right != http.StatusCreated {
+			t.Errorf("Expected 401, 401 and 201, got %d, %d and %d", missing, wrong, right)
+		}
+	})
+
+	t.Run("refuses_oversized_signed_bodies", func(t *testing.T) {
+		// Given: An issued key behind the signature middleware
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		issued, _ := exchangeService.CreateAPIKey(context.Background(), account.ID, services.NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionTrade}})
+		apiKeyHandler := handlers.NewAPIKeyHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()), apiKeyHandler.Authenticate)
+		router.POST("/api/v1/orders", orderHandler.Place)
+
+		// When: It sends a body over the limit
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(strings.Repeat(" ", 2<<20)))
+		req.Header.Set(observability.APIKeyHeader, issued.ID)
+		req.Header.Set(handlers.TimestampHeader, strconv.FormatInt(time.Now().UnixMilli(), 10))
+		req.Header.Set(handlers.SignatureHeader, strings.Repeat("ab", 32))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: It is refused before the signature is checked
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("checks_keys_sent_in_the_binance_header", func(t *testing.T) {
+		// Given: An issued key behind the signature middleware
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		issued, _ := exchangeService.CreateAPIKey(context.Background(), account.ID, services.NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionTrade}})
+		apiKeyHandler := handlers.NewAPIKeyHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()), apiKeyHandler.Authenticate)
+		router.POST("/api/v1/orders", orderHandler.Place)
+
+		// When: It places an order with the key in X-MBX-APIKEY and no signature
+		order := `{"account_id":"` + account.ID + `","symbol":"BTC-USD","side":"BUY","type":"LIMIT","quantity":1,"price":60000}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(order))
+		req.Header.Set(observability.BinanceAPIKeyHeader, issued.ID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: The missing signature is refused as it is under X-API-Key
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("ranks_account_activity", func(t *testing.T) {
+		// Given: The activity route over a venue where two accounts traded and one only quoted
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		accountHandler := handlers.NewAccountHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/api/v1/admin/accounts/activity", accountHandler.Activity)
+		ctx := context.Background()
+		exchangeService.PlaceOrder(ctx, services.OrderRequest{AccountID: "quoter", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 50000})
+		exchangeService.PlaceOrder(ctx, services.OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		exchangeService.PlaceOrder(ctx, services.OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+
+		// When: The top two are requested, and then with an unknown ordering
+		top := serve(router, http.MethodGet, "/api/v1/admin/accounts/activity?limit=2", "")
+		unknownSort := serve(router, http.MethodGet, "/api/v1/admin/accounts/activity?sort=pnl", "")
+
+		// Then: The accounts that traded are returned, and the unknown ordering is refused
+		var board struct {
+			Accounts []services.AccountActivity `json:"accounts"`
+		}
+		json.Unmarshal(top.Body.Bytes(), &board)
+		if top.Code != http.StatusOK || len(board.Accounts) != 2 || board.Accounts[0].AccountID != "maker" || board.Accounts[1].AccountID != "taker" {
+			t.Errorf("Expected maker and taker, got %d: %s", top.Code, top.Body.String())
+		}
+		if unknownSort.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an unknown sort, got %d", unknownSort.Code)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// Headers signing REST requests made with account API keys
+const (
+	TimestampHeader = "X-API-Timestamp" // Unix milliseconds on the caller's clock
+	SignatureHeader = "X-API-Signature" // Hex HMAC-SHA256 of timestamp, method, path with query and body
+)
+
+// maxSignedBodySize bounds the body read to check a signature, so a caller cannot
+// exhaust memory before it is authenticated
+const maxSignedBodySize = 1 << 20
+
+// APIKeyHandler issues, revokes and authenticates account API keys, and exposes
+// message and order statistics per API key
+type APIKeyHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewAPIKeyHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns the statistics of every key seen
+func (h *APIKeyHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": h.exchangeService.KeyStatistics().All(),
+	})
+}
+
+// Get returns the statistics of one key
+func (h *APIKeyHandler) Get(c *gin.Context) {
+	stats, exists := h.exchangeService.KeyStatistics().Stats(c.Param("api_key"))
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no activity for api key"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// Authenticate checks the signature of every request made with a key issued through
+// the account key API, refusing it with 401 and UNAUTHENTICATED when it fails, and
+// with 413 when its body is over 1 MiB. Other keys and anonymous requests pass unsigned.
+// It reads the key observability.APIKeyMiddleware tagged, from whichever header sent it.
+func (h *APIKeyHandler) Authenticate(c *gin.Context) {
+	keyID := keystats.APIKey(c.Request.Context())
+	if !accounts.IsKeyID(keyID) {
+		c.Next()
+		return
+	}
+
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodySize)); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, errorBody(services.NewRejection(services.RejectInvalidRequest,
+					fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))))
+				return
+			}
+			invalidRequest(c, fmt.Errorf("failed to read request body: %w", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	timestamp := c.GetHeader(TimestampHeader)
+	err := h.exchangeService.AuthenticateRequest(c.Request.Context(), services.SignedRequest{
+		KeyID:     keyID,
+		Timestamp: timestamp,
+		Signature: c.GetHeader(SignatureHeader),
+		Payload:   accounts.SigningPayload(timestamp, c.Request.Method, c.Request.URL.RequestURI(), body),
+		Transport: "http",
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.Next()
+}
+
+// Create issues a key to an account; its secret is in this response only
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req services.NewAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	key, err := h.exchangeService.CreateAPIKey(c.Request.Context(), c.Param("account_id"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+// Keys lists an account's keys, revoked ones included, without their secrets
+func (h *APIKeyHandler) Keys(c *gin.Context) {
+	keys, err := h.exchangeService.APIKeys(c.Request.Context(), c.Param("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": c.Param("account_id"),
+		"keys":       keys,
+	})
+}
+
+// Revoke stops a key authenticating
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	key, err := h.exchangeService.RevokeAPIKey(c.Request.Context(), c.Param("account_id"), c.Param("key_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// Denials returns the latest access-control failures oldest first; query params:
+// key_id, limit
+func (h *APIKeyHandler) Denials(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			invalidRequest(c, fmt.Errorf("limit must be a number"))
+			return
+		}
+	}
+	denials, err := h.exchangeService.AccessDenials(c.Request.Context(), c.Query("key_id"), limit)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"denials": denials})
+}
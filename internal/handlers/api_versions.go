@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/apiversion"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// APIVersionHandler deprecates and retires REST API versions on the venue clock
+type APIVersionHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+// APIVersionRequest schedules a version's lifecycle; omitted times never arrive
+type APIVersionRequest struct {
+	DeprecatedAt time.Time `json:"deprecated_at"`
+	SunsetAt     time.Time `json:"sunset_at"`
+	Successor    string    `json:"successor"`
+}
+
+func NewAPIVersionHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *APIVersionHandler {
+	return &APIVersionHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Enforce guards the routes of one API version. Once deprecated its responses
+// carry Deprecation, Sunset and successor Link headers; once retired its calls are
+// refused with 410 and API_VERSION_RETIRED.
+func (h *APIVersionHandler) Enforce(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := h.exchangeService.CheckAPIVersion(c.Request.Context(), version)
+		if status.Phase != apiversion.PhaseActive {
+			deprecationHeaders(c, status.Lifecycle)
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), errorBody(err))
+			return
+		}
+		c.Next()
+	}
+}
+
+// List reports every scheduled version's phase and the calls made since deprecation
+func (h *APIVersionHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"versions": h.exchangeService.APIVersions(c.Request.Context())})
+}
+
+// Update schedules a version's deprecation and retirement
+func (h *APIVersionHandler) Update(c *gin.Context) {
+	var req APIVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	status, err := h.exchangeService.SetAPILifecycle(c.Request.Context(), apiversion.Lifecycle{
+		Version:      c.Param("version"),
+		DeprecatedAt: req.DeprecatedAt,
+		SunsetAt:     req.SunsetAt,
+		Successor:    req.Successor,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// deprecationHeaders announces a version's deprecation (RFC 9745), sunset (RFC 8594)
+// and successor
+func deprecationHeaders(c *gin.Context, lifecycle apiversion.Lifecycle) {
+	if !lifecycle.DeprecatedAt.IsZero() {
+		c.Header("Deprecation", fmt.Sprintf("@%d", lifecycle.DeprecatedAt.Unix()))
+	} else {
+		c.Header("Deprecation", "true")
+	}
+	if !lifecycle.SunsetAt.IsZero() {
+		c.Header("Sunset", lifecycle.SunsetAt.UTC().Format(http.TimeFormat))
+	}
+	if lifecycle.Successor != "" {
+		c.Header("Link", fmt.Sprintf(`</api/%s>; rel="successor-version"`, lifecycle.Successor))
+	}
+}
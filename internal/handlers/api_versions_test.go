@@ -0,0 +1,80 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/clock"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func TestAPIVersionHandler(t *testing.T) {
+	t.Run("warns_then_retires_v1_while_v2_keeps_working", func(t *testing.T) {
+		// Given: The tickers served as v1 and v2 on a paused venue clock, with v1
+		// deprecated after ten minutes and retired after twenty
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		venueClock, _ := clock.NewSimulatedClock(start, 1)
+		venueClock.Pause()
+		cfg := &config.Config{ServiceName: "exchange-simulator"}
+		cfg.SetClock(venueClock)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(cfg, logger)
+		versionHandler := handlers.NewAPIVersionHandler(exchangeService, logger)
+		marketDataHandler := handlers.NewMarketDataHandler(exchangeService, logger)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		for _, version := range []string{"v1", "v2"} {
+			router.Group("/api/"+version, versionHandler.Enforce(version)).GET("/tickers", marketDataHandler.Tickers)
+		}
+		router.PUT("/api/v1/admin/api-versions/:version", versionHandler.Update)
+		w := serve(router, http.MethodPut, "/api/v1/admin/api-versions/v1",
+			`{"deprecated_at":"2024-01-01T09:10:00Z","sunset_at":"2024-01-01T09:20:00Z","successor":"v2"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected the lifecycle to be scheduled, got %d: %s", w.Code, w.Body.String())
+		}
+
+		// When: v1 is called before deprecation, while deprecated and after sunset
+		active := serve(router, http.MethodGet, "/api/v1/tickers", "")
+		venueClock.Advance(10 * time.Minute)
+		deprecated := serve(router, http.MethodGet, "/api/v1/tickers", "")
+		venueClock.Advance(10 * time.Minute)
+		retired := serve(router, http.MethodGet, "/api/v1/tickers", "")
+		successor := serve(router, http.MethodGet, "/api/v2/tickers", "")
+
+		// Then: Warnings start at deprecation, calls are refused at sunset, and v2 is unaffected
+		if active.Code != http.StatusOK || active.Header().Get("Deprecation") != "" {
+			t.Errorf("Expected a plain response before deprecation, got %d %v", active.Code, active.Header())
+		}
+		if deprecated.Code != http.StatusOK {
+			t.Errorf("Expected deprecated calls to be served, got %d", deprecated.Code)
+		}
+		if got := deprecated.Header().Get("Deprecation"); got != "@1704100200" {
+			t.Errorf("Expected the deprecation date header, got %q", got)
+		}
+		if got := deprecated.Header().Get("Sunset"); got != "Mon, 01 Jan 2024 09:20:00 GMT" {
+			t.Errorf("Expected the sunset header, got %q", got)
+		}
+		if got := deprecated.Header().Get("Link"); got != `</api/v2>; rel="successor-version"` {
+			t.Errorf("Expected the successor link, got %q", got)
+		}
+		var body map[string]string
+		json.Unmarshal(retired.Body.Bytes(), &body)
+		if retired.Code != http.StatusGone || body["code"] != string(services.RejectAPIVersionRetired) {
+			t.Errorf("Expected 410 API_VERSION_RETIRED, got %d: %s", retired.Code, retired.Body.String())
+		}
+		if successor.Code != http.StatusOK || successor.Header().Get("Deprecation") != "" {
+			t.Errorf("Expected v2 to be served plainly, got %d %v", successor.Code, successor.Header())
+		}
+	})
+}
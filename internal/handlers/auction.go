@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// AuctionHandler exposes call auction control and indicative pricing
+type AuctionHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+type startAuctionBody struct {
+	Kind string `json:"kind" binding:"required"` // opening or closing
+}
+
+func NewAuctionHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *AuctionHandler {
+	return &AuctionHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Start moves a symbol into a call auction phase
+func (h *AuctionHandler) Start(c *gin.Context) {
+	var body startAuctionBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	symbol := c.Param("symbol")
+	if err := h.exchangeService.StartAuction(c.Request.Context(), symbol, matching.AuctionKind(body.Kind)); err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"kind":   body.Kind,
+		"phase":  matching.PhaseAuction,
+	})
+}
+
+// Indicative returns the current indicative uncrossing price and volume
+func (h *AuctionHandler) Indicative(c *gin.Context) {
+	indication, err := h.exchangeService.IndicativeAuction(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, indication)
+}
+
+// Uncross executes the auction and prints the auction trades
+func (h *AuctionHandler) Uncross(c *gin.Context) {
+	result, err := h.exchangeService.UncrossAuction(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
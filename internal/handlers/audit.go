@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// AuditHandler serves the state of the audit event pipeline
+type AuditHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewAuditHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *AuditHandler {
+	return &AuditHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Stats returns how many audit events were published, delivered to the
+// audit-correlator, dropped on a full buffer and given up after failed deliveries
+func (h *AuditHandler) Stats(c *gin.Context) {
+	stats, err := h.exchangeService.AuditTrailStats(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
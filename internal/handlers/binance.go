@@ -0,0 +1,697 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+const (
+	defaultBinanceRecvWindow = 5000 // Milliseconds, as on Binance
+	maxBinanceRecvWindow     = 60000
+	defaultBinanceDepth      = 100
+	maxBinanceDepth          = 5000
+	binanceDecimals          = 8
+)
+
+// Binance error codes returned by the facade, with Binance's own meanings
+const (
+	binanceUnknown          = -1000
+	binanceDisconnected     = -1001
+	binanceInvalidTimestamp = -1021
+	binanceInvalidSignature = -1022
+	binanceMandatoryParam   = -1102
+	binanceInvalidOrderType = -1116
+	binanceInvalidSide      = -1117
+	binanceInvalidSymbol    = -1121
+	binanceInvalidRecv      = -1131
+	binanceFilterFailure    = -1013
+	binanceOrderRejected    = -2010
+	binanceCancelRejected   = -2011
+	binanceNoSuchOrder      = -2013
+	binanceBadAPIKeyFormat  = -2014
+	binanceRejectedAPIKey   = -2015
+)
+
+// BinanceCredential lets a Binance client trade as an account, signing requests
+// with the secret
+type BinanceCredential struct {
+	APIKey    string
+	Secret    string
+	AccountID string
+}
+
+// ParseBinanceCredentials reads credentials in the BINANCE_API_KEYS form
+// "key:secret[:account],...". The account defaults to the key.
+func ParseBinanceCredentials(spec string) ([]BinanceCredential, error) {
+	credentials := make([]BinanceCredential, 0)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid binance credential %q: expected key:secret[:account]", entry)
+		}
+		credential := BinanceCredential{APIKey: parts[0], Secret: parts[1], AccountID: parts[0]}
+		if len(parts) == 3 && parts[2] != "" {
+			credential.AccountID = parts[2]
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// BinanceHandler serves a subset of the Binance spot REST API (/api/v3) on top of
+// the venue, so unmodified Binance clients can trade against the simulator.
+// Symbols drop the dash (BTC-USD is BTCUSD) and order IDs are the per-book number.
+type BinanceHandler struct {
+	exchangeService *services.ExchangeService
+	credentials     map[string]BinanceCredential // Empty accepts any key unsigned, trading as itself
+	logger          *logrus.Logger
+}
+
+// binanceError is Binance's error envelope
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// binanceOrder is an order in Binance's shape; order placement and cancel fill in
+// transactTime, queries fill in time, updateTime and isWorking
+type binanceOrder struct {
+	Symbol              string        `json:"symbol"`
+	OrderID             uint64        `json:"orderId"`
+	OrderListID         int           `json:"orderListId"`
+	ClientOrderID       string        `json:"clientOrderId"`
+	TransactTime        int64         `json:"transactTime,omitempty"`
+	Price               string        `json:"price"`
+	OrigQty             string        `json:"origQty"`
+	ExecutedQty         string        `json:"executedQty"`
+	CummulativeQuoteQty string        `json:"cummulativeQuoteQty"`
+	Status              string        `json:"status"`
+	TimeInForce         string        `json:"timeInForce"`
+	Type                string        `json:"type"`
+	Side                string        `json:"side"`
+	Time                int64         `json:"time,omitempty"`
+	UpdateTime          int64         `json:"updateTime,omitempty"`
+	IsWorking           *bool         `json:"isWorking,omitempty"`
+	Fills               []binanceFill `json:"fills,omitempty"`
+}
+
+type binanceFill struct {
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	Commission      string `json:"commission"` // Fees are not charged by the venue
+	CommissionAsset string `json:"commissionAsset"`
+	TradeID         uint64 `json:"tradeId"`
+}
+
+func NewBinanceHandler(exchangeService *services.ExchangeService, credentials []BinanceCredential, logger *logrus.Logger) *BinanceHandler {
+	byKey := make(map[string]BinanceCredential, len(credentials))
+	for _, credential := range credentials {
+		byKey[credential.APIKey] = credential
+	}
+	return &BinanceHandler{
+		exchangeService: exchangeService,
+		credentials:     byKey,
+		logger:          logger,
+	}
+}
+
+// Ping answers GET /api/v3/ping
+func (h *BinanceHandler) Ping(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// Time answers GET /api/v3/time. Clients sync request timestamps to it, so it reports
+// the wall clock signed requests are checked against rather than the venue clock.
+func (h *BinanceHandler) Time(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"serverTime": time.Now().UnixMilli()})
+}
+
+// ExchangeInfo answers GET /api/v3/exchangeInfo with every spot instrument's rules
+func (h *BinanceHandler) ExchangeInfo(c *gin.Context) {
+	symbols := make([]gin.H, 0)
+	for _, instrument := range h.exchangeService.Instruments().List() {
+		if instrument.Kind != models.InstrumentKindSpot {
+			continue
+		}
+		status := "BREAK"
+		switch phase, _ := h.exchangeService.Engine().Phase(instrument.Symbol); phase {
+		case matching.PhaseContinuous:
+			status = "TRADING"
+		case matching.PhaseHalted:
+			status = "HALT"
+		}
+		symbols = append(symbols, gin.H{
+			"symbol":               binanceSymbol(instrument.Symbol),
+			"status":               status,
+			"baseAsset":            instrument.BaseAsset,
+			"baseAssetPrecision":   binanceDecimals,
+			"quoteAsset":           instrument.QuoteAsset,
+			"quotePrecision":       binanceDecimals,
+			"quoteAssetPrecision":  binanceDecimals,
+			"orderTypes":           []string{"LIMIT", "MARKET"},
+			"icebergAllowed":       false,
+			"ocoAllowed":           false,
+			"isSpotTradingAllowed": true,
+			"filters": []gin.H{
+				{"filterType": "PRICE_FILTER", "minPrice": binanceDecimal(instrument.TickSize), "maxPrice": binanceDecimal(0), "tickSize": binanceDecimal(instrument.TickSize)},
+				{"filterType": "LOT_SIZE", "minQty": binanceDecimal(instrument.MinQuantity), "maxQty": binanceDecimal(0), "stepSize": binanceDecimal(instrument.LotSize)},
+			},
+			"permissions": []string{"SPOT"},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"timezone":        "UTC",
+		"serverTime":      time.Now().UnixMilli(),
+		"rateLimits":      binanceRateLimits(h.exchangeService.RateLimits()),
+		"exchangeFilters": []gin.H{},
+		"symbols":         symbols,
+	})
+}
+
+// binanceRateLimits lists the per-key request weight limit as Binance's
+// REQUEST_WEIGHT entry; a window of no whole seconds is not expressible and omitted
+func binanceRateLimits(limits ratelimit.Limits) []gin.H {
+	rateLimits := make([]gin.H, 0, 1)
+	if limits.KeyWeight <= 0 || limits.Window%time.Second != 0 {
+		return rateLimits
+	}
+	interval, count := "SECOND", int(limits.Window/time.Second)
+	if limits.Window%time.Minute == 0 {
+		interval, count = "MINUTE", int(limits.Window/time.Minute)
+	}
+	return append(rateLimits, gin.H{"rateLimitType": "REQUEST_WEIGHT", "interval": interval, "intervalNum": count, "limit": limits.KeyWeight})
+}
+
+// Depth answers GET /api/v3/depth; query params: symbol, limit (default 100)
+func (h *BinanceHandler) Depth(c *gin.Context) {
+	instrument, ok := h.instrument(c, c.Query("symbol"))
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultBinanceDepth)))
+	if err != nil || limit <= 0 || limit > maxBinanceDepth {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, fmt.Sprintf("limit must be between 1 and %d.", maxBinanceDepth))
+		return
+	}
+	// The execution sequence advances with every order change, and is read first so it
+	// never runs ahead of the book
+	lastUpdateID := h.exchangeService.Executions().Sequence()
+	snapshot, err := h.exchangeService.OrderBook(c.Request.Context(), instrument.Symbol, limit)
+	if err != nil {
+		h.reject(c, err, binanceOrderRejected)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"lastUpdateId": lastUpdateID,
+		"bids":         binanceLevels(snapshot.Bids),
+		"asks":         binanceLevels(snapshot.Asks),
+	})
+}
+
+// PlaceOrder answers POST /api/v3/order (signed); newOrderRespType picks an ACK,
+// RESULT or FULL response, FULL by default
+func (h *BinanceHandler) PlaceOrder(c *gin.Context) {
+	params, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	instrument, ok := h.instrument(c, params.Get("symbol"))
+	if !ok {
+		return
+	}
+	req, ok := h.orderRequest(c, params, accountID, instrument.Symbol)
+	if !ok {
+		return
+	}
+
+	report, err := h.exchangeService.PlaceOrder(c.Request.Context(), req)
+	if err != nil {
+		h.reject(c, err, binanceOrderRejected)
+		return
+	}
+	transactTime := report.Order.UpdatedAt.UnixMilli()
+	switch strings.ToUpper(params.Get("newOrderRespType")) {
+	case "ACK":
+		c.JSON(http.StatusOK, gin.H{
+			"symbol":        binanceSymbol(report.Order.Symbol),
+			"orderId":       binanceOrderID(report.Order.ID),
+			"orderListId":   -1,
+			"clientOrderId": report.Order.ClientOrderID,
+			"transactTime":  transactTime,
+		})
+	case "RESULT":
+		order := toBinanceOrder(report.Order)
+		order.TransactTime = transactTime
+		c.JSON(http.StatusOK, order)
+	default:
+		order := toBinanceOrder(report.Order)
+		order.TransactTime = transactTime
+		order.Fills = make([]binanceFill, 0, len(report.Trades))
+		for _, trade := range report.Trades {
+			order.Fills = append(order.Fills, binanceFill{
+				Price:           binanceDecimal(trade.Price),
+				Qty:             binanceDecimal(trade.Quantity),
+				Commission:      binanceDecimal(0),
+				CommissionAsset: instrument.QuoteAsset,
+				TradeID:         binanceTradeID(trade.ID),
+			})
+		}
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+// TestOrder answers POST /api/v3/order/test (signed) by running the venue's checks
+// without placing the order
+func (h *BinanceHandler) TestOrder(c *gin.Context) {
+	params, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	instrument, ok := h.instrument(c, params.Get("symbol"))
+	if !ok {
+		return
+	}
+	req, ok := h.orderRequest(c, params, accountID, instrument.Symbol)
+	if !ok {
+		return
+	}
+	if result := h.exchangeService.CheckOrder(c.Request.Context(), req); !result.Accepted && len(result.Rejections) > 0 {
+		h.reject(c, &result.Rejections[0], binanceOrderRejected)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// QueryOrder answers GET /api/v3/order (signed); params: symbol and orderId or
+// origClientOrderId
+func (h *BinanceHandler) QueryOrder(c *gin.Context) {
+	params, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	order, ok := h.lookupOrder(c, params, accountID, binanceNoSuchOrder)
+	if !ok {
+		return
+	}
+	converted := toBinanceOrder(order)
+	converted.Time = order.CreatedAt.UnixMilli()
+	converted.UpdateTime = order.UpdatedAt.UnixMilli()
+	working := !order.Status.IsTerminal()
+	converted.IsWorking = &working
+	c.JSON(http.StatusOK, converted)
+}
+
+// CancelOrder answers DELETE /api/v3/order (signed); params as for QueryOrder
+func (h *BinanceHandler) CancelOrder(c *gin.Context) {
+	params, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	order, ok := h.lookupOrder(c, params, accountID, binanceCancelRejected)
+	if !ok {
+		return
+	}
+	canceled, err := h.exchangeService.CancelOrder(c.Request.Context(), order.ID)
+	if err != nil {
+		h.reject(c, err, binanceCancelRejected)
+		return
+	}
+	converted := toBinanceOrder(canceled)
+	converted.TransactTime = canceled.UpdatedAt.UnixMilli()
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":              converted.Symbol,
+		"origClientOrderId":   converted.ClientOrderID,
+		"orderId":             converted.OrderID,
+		"orderListId":         converted.OrderListID,
+		"clientOrderId":       converted.ClientOrderID,
+		"transactTime":        converted.TransactTime,
+		"price":               converted.Price,
+		"origQty":             converted.OrigQty,
+		"executedQty":         converted.ExecutedQty,
+		"cummulativeQuoteQty": converted.CummulativeQuoteQty,
+		"status":              converted.Status,
+		"timeInForce":         converted.TimeInForce,
+		"type":                converted.Type,
+		"side":                converted.Side,
+	})
+}
+
+// OpenOrders answers GET /api/v3/openOrders (signed); param: symbol (optional)
+func (h *BinanceHandler) OpenOrders(c *gin.Context) {
+	params, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	symbol := ""
+	if name := params.Get("symbol"); name != "" {
+		instrument, ok := h.instrument(c, name)
+		if !ok {
+			return
+		}
+		symbol = instrument.Symbol
+	}
+	orders, err := h.exchangeService.OpenOrders(c.Request.Context(), accountID, symbol)
+	if err != nil {
+		h.reject(c, err, binanceOrderRejected)
+		return
+	}
+	converted := make([]binanceOrder, 0, len(orders))
+	for _, order := range orders {
+		open := toBinanceOrder(order)
+		open.Time = order.CreatedAt.UnixMilli()
+		open.UpdateTime = order.UpdatedAt.UnixMilli()
+		working := true
+		open.IsWorking = &working
+		converted = append(converted, open)
+	}
+	c.JSON(http.StatusOK, converted)
+}
+
+// Account answers GET /api/v3/account (signed) with the account's net position per
+// asset as its free balance. The venue does not fund accounts, so a short shows as
+// a negative balance.
+func (h *BinanceHandler) Account(c *gin.Context) {
+	_, accountID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	accountLedger, err := h.exchangeService.AccountLedger(c.Request.Context(), accountID)
+	if err != nil {
+		h.reject(c, err, binanceOrderRejected)
+		return
+	}
+	balances := make([]gin.H, 0, len(accountLedger.Positions))
+	for _, position := range accountLedger.Positions {
+		balances = append(balances, gin.H{
+			"asset":  position.Asset,
+			"free":   binanceDecimal(position.Quantity),
+			"locked": binanceDecimal(0),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"makerCommission":  0,
+		"takerCommission":  0,
+		"buyerCommission":  0,
+		"sellerCommission": 0,
+		"canTrade":         true,
+		"canWithdraw":      false,
+		"canDeposit":       false,
+		"updateTime":       h.exchangeService.Now().UnixMilli(),
+		"accountType":      "SPOT",
+		"balances":         balances,
+		"permissions":      []string{"SPOT"},
+	})
+}
+
+// authenticate reads a signed request's parameters from the query string and form
+// body, checks its API key, signature and timestamp, and returns the account it
+// trades as
+func (h *BinanceHandler) authenticate(c *gin.Context) (url.Values, string, bool) {
+	rawQuery := c.Request.URL.RawQuery
+	rawBody := ""
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Malformed request body.")
+			return nil, "", false
+		}
+		rawBody = string(body)
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err == nil {
+		var form url.Values
+		if form, err = url.ParseQuery(rawBody); err == nil {
+			for key, values := range form {
+				params[key] = append(params[key], values...)
+			}
+		}
+	}
+	if err != nil {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Malformed request parameters.")
+		return nil, "", false
+	}
+
+	apiKey := c.GetHeader(observability.BinanceAPIKeyHeader)
+	if apiKey == "" {
+		h.fail(c, http.StatusUnauthorized, binanceBadAPIKeyFormat, "API-key format invalid.")
+		return nil, "", false
+	}
+	accountID := apiKey
+	if len(h.credentials) > 0 {
+		credential, exists := h.credentials[apiKey]
+		if !exists {
+			h.fail(c, http.StatusUnauthorized, binanceRejectedAPIKey, "Invalid API-key, IP, or permissions for action.")
+			return nil, "", false
+		}
+		signature := params.Get("signature")
+		if signature == "" {
+			h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed.")
+			return nil, "", false
+		}
+		if !validBinanceSignature(credential.Secret, unsigned(rawQuery)+unsigned(rawBody), signature) {
+			h.fail(c, http.StatusBadRequest, binanceInvalidSignature, "Signature for this request is not valid.")
+			return nil, "", false
+		}
+		accountID = credential.AccountID
+	}
+
+	timestamp, err := strconv.ParseInt(params.Get("timestamp"), 10, 64)
+	if err != nil {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed.")
+		return nil, "", false
+	}
+	recvWindow := int64(defaultBinanceRecvWindow)
+	if value := params.Get("recvWindow"); value != "" {
+		if recvWindow, err = strconv.ParseInt(value, 10, 64); err != nil || recvWindow <= 0 || recvWindow > maxBinanceRecvWindow {
+			h.fail(c, http.StatusBadRequest, binanceInvalidRecv, fmt.Sprintf("recvWindow must be less than %d.", maxBinanceRecvWindow))
+			return nil, "", false
+		}
+	}
+	// Clients stamp requests from their own wall clock, whatever the venue clock says
+	now := time.Now().UnixMilli()
+	if timestamp > now+1000 || now-timestamp > recvWindow {
+		h.fail(c, http.StatusBadRequest, binanceInvalidTimestamp, "Timestamp for this request is outside of the recvWindow.")
+		return nil, "", false
+	}
+	return params, accountID, true
+}
+
+// instrument resolves a Binance symbol to a listed spot instrument
+func (h *BinanceHandler) instrument(c *gin.Context, symbol string) (models.Instrument, bool) {
+	if symbol == "" {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Mandatory parameter 'symbol' was not sent, was empty/null, or malformed.")
+		return models.Instrument{}, false
+	}
+	for _, instrument := range h.exchangeService.Instruments().List() {
+		if instrument.Kind == models.InstrumentKindSpot && binanceSymbol(instrument.Symbol) == strings.ToUpper(symbol) {
+			return instrument, true
+		}
+	}
+	h.fail(c, http.StatusBadRequest, binanceInvalidSymbol, "Invalid symbol.")
+	return models.Instrument{}, false
+}
+
+// orderRequest builds a venue order from Binance order parameters. Without a
+// newClientOrderId one is generated, as Binance does, so the order can be queried by it.
+func (h *BinanceHandler) orderRequest(c *gin.Context, params url.Values, accountID, symbol string) (services.OrderRequest, bool) {
+	side, err := models.ParseSide(strings.ToUpper(params.Get("side")))
+	if err != nil {
+		h.fail(c, http.StatusBadRequest, binanceInvalidSide, "Invalid side.")
+		return services.OrderRequest{}, false
+	}
+	orderType, err := models.ParseOrderType(strings.ToUpper(params.Get("type")))
+	if err != nil || params.Get("type") == "" {
+		h.fail(c, http.StatusBadRequest, binanceInvalidOrderType, "Invalid orderType.")
+		return services.OrderRequest{}, false
+	}
+	req := services.OrderRequest{
+		AccountID:     accountID,
+		ClientOrderID: params.Get("newClientOrderId"),
+		Symbol:        symbol,
+		Side:          side,
+		Type:          orderType,
+	}
+	if req.TimeInForce, err = models.ParseTimeInForce(strings.ToUpper(params.Get("timeInForce"))); err != nil || req.TimeInForce == models.TimeInForceGTD {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Invalid timeInForce.")
+		return services.OrderRequest{}, false
+	}
+	if req.Quantity, err = strconv.ParseFloat(params.Get("quantity"), 64); err != nil {
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Mandatory parameter 'quantity' was not sent, was empty/null, or malformed.")
+		return services.OrderRequest{}, false
+	}
+	if orderType == models.OrderTypeLimit {
+		if req.Price, err = strconv.ParseFloat(params.Get("price"), 64); err != nil {
+			h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Mandatory parameter 'price' was not sent, was empty/null, or malformed.")
+			return services.OrderRequest{}, false
+		}
+	}
+	if req.ClientOrderID == "" {
+		suffix := make([]byte, 11)
+		rand.Read(suffix)
+		req.ClientOrderID = "sim_" + hex.EncodeToString(suffix)
+	}
+	return req, true
+}
+
+// lookupOrder finds one of the account's orders by orderId or origClientOrderId;
+// other accounts' orders are reported as not existing
+func (h *BinanceHandler) lookupOrder(c *gin.Context, params url.Values, accountID string, notFound int) (models.Order, bool) {
+	instrument, ok := h.instrument(c, params.Get("symbol"))
+	if !ok {
+		return models.Order{}, false
+	}
+
+	var order models.Order
+	var err error
+	switch {
+	case params.Get("orderId") != "":
+		number, parseErr := strconv.ParseUint(params.Get("orderId"), 10, 64)
+		if parseErr != nil {
+			h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Illegal characters found in parameter 'orderId'.")
+			return models.Order{}, false
+		}
+		order, err = h.exchangeService.GetOrder(c.Request.Context(), matching.OrderID(instrument.Symbol, number))
+	case params.Get("origClientOrderId") != "":
+		order, err = h.exchangeService.ClientOrder(c.Request.Context(), accountID, params.Get("origClientOrderId"))
+	default:
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, "Param 'origClientOrderId' or 'orderId' must be sent, but both were empty/null!")
+		return models.Order{}, false
+	}
+	if err != nil || order.AccountID != accountID || order.Symbol != instrument.Symbol {
+		message := "Order does not exist."
+		if notFound == binanceCancelRejected {
+			message = "Unknown order sent."
+		}
+		h.fail(c, http.StatusBadRequest, notFound, message)
+		return models.Order{}, false
+	}
+	return order, true
+}
+
+// reject answers a venue error with the closest Binance code; action is the code for
+// a refused order action (-2010 for new orders, -2011 for cancels)
+func (h *BinanceHandler) reject(c *gin.Context, err error, action int) {
+	rejection := services.RejectionOf(err)
+	switch rejection.Reason {
+	case services.RejectUnknownInstrument:
+		h.fail(c, http.StatusBadRequest, binanceInvalidSymbol, "Invalid symbol.")
+	case services.RejectOrderNotFound, services.RejectOrderNotActive:
+		if action == binanceCancelRejected {
+			h.fail(c, http.StatusBadRequest, binanceCancelRejected, "Unknown order sent.")
+			return
+		}
+		h.fail(c, http.StatusBadRequest, binanceNoSuchOrder, "Order does not exist.")
+	case services.RejectInvalidSide:
+		h.fail(c, http.StatusBadRequest, binanceInvalidSide, "Invalid side.")
+	case services.RejectInvalidOrderType:
+		h.fail(c, http.StatusBadRequest, binanceInvalidOrderType, "Invalid orderType.")
+	case services.RejectInvalidQuantity, services.RejectInvalidPrice, services.RejectPriceOutOfBand:
+		h.fail(c, http.StatusBadRequest, binanceFilterFailure, "Filter failure: "+rejection.Message)
+	case services.RejectDuplicateClientOrderID:
+		h.fail(c, http.StatusBadRequest, action, "Duplicate order sent.")
+	case services.RejectInvalidRequest, services.RejectInvalidAccount:
+		h.fail(c, http.StatusBadRequest, binanceMandatoryParam, rejection.Message)
+	case services.RejectEngineUnavailable:
+		h.fail(c, http.StatusServiceUnavailable, binanceDisconnected, "Internal error; unable to process your request. Please try again.")
+	case services.RejectUnknown:
+		h.logger.WithError(err).Error("Binance facade request failed")
+		h.fail(c, http.StatusInternalServerError, binanceUnknown, "An unknown error occurred while processing the request.")
+	default:
+		h.fail(c, http.StatusBadRequest, action, rejection.Message)
+	}
+}
+
+func (h *BinanceHandler) fail(c *gin.Context, status, code int, message string) {
+	c.AbortWithStatusJSON(status, binanceError{Code: code, Msg: message})
+}
+
+// validBinanceSignature checks a hex HMAC-SHA256 of the request's parameters
+func validBinanceSignature(secret, payload, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// unsigned drops the signature parameter from a raw query string or form body,
+// leaving the rest byte for byte as the client signed it
+func unsigned(raw string) string {
+	kept := make([]string, 0)
+	for _, pair := range strings.Split(raw, "&") {
+		if pair != "" && !strings.HasPrefix(pair, "signature=") {
+			kept = append(kept, pair)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+func toBinanceOrder(order models.Order) binanceOrder {
+	return binanceOrder{
+		Symbol:              binanceSymbol(order.Symbol),
+		OrderID:             binanceOrderID(order.ID),
+		OrderListID:         -1,
+		ClientOrderID:       order.ClientOrderID,
+		Price:               binanceDecimal(order.Price),
+		OrigQty:             binanceDecimal(order.Quantity),
+		ExecutedQty:         binanceDecimal(order.FilledQuantity),
+		CummulativeQuoteQty: binanceDecimal(order.FilledQuantity * order.AveragePrice),
+		Status:              strings.ToUpper(string(order.Status)),
+		TimeInForce:         strings.ToUpper(string(order.TimeInForce)),
+		Type:                strings.ToUpper(string(order.Type)),
+		Side:                strings.ToUpper(string(order.Side)),
+	}
+}
+
+func binanceLevels(levels []matching.PriceLevel) [][2]string {
+	converted := make([][2]string, 0, len(levels))
+	for _, level := range levels {
+		converted = append(converted, [2]string{binanceDecimal(level.Price), binanceDecimal(level.Quantity)})
+	}
+	return converted
+}
+
+// binanceSymbol drops the dash from a venue symbol, e.g. BTC-USD becomes BTCUSD
+func binanceSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "-", "")
+}
+
+func binanceDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', binanceDecimals, 64)
+}
+
+// binanceOrderID returns an order's per-book number, which Binance clients use as its ID
+func binanceOrderID(orderID string) uint64 {
+	_, number, _ := matching.ParseOrderID(orderID)
+	return number
+}
+
+// binanceTradeID returns a trade's per-book number
+func binanceTradeID(tradeID string) uint64 {
+	number, _ := strconv.ParseUint(tradeID[strings.LastIndex(tradeID, "-")+1:], 10, 64)
+	return number
+}
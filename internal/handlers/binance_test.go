@@ -0,0 +1,200 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newBinanceRouter(t *testing.T, keys string) *gin.Engine {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	credentials, err := handlers.ParseBinanceCredentials(keys)
+	if err != nil {
+		t.Fatalf("Expected valid credentials, got %v", err)
+	}
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+	binanceHandler := handlers.NewBinanceHandler(exchangeService, credentials, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v3/depth", binanceHandler.Depth)
+	router.POST("/api/v3/order", binanceHandler.PlaceOrder)
+	router.GET("/api/v3/order", binanceHandler.QueryOrder)
+	router.DELETE("/api/v3/order", binanceHandler.CancelOrder)
+	router.GET("/api/v3/openOrders", binanceHandler.OpenOrders)
+	router.GET("/api/v3/account", binanceHandler.Account)
+	return router
+}
+
+// signedRequest sends params the way Binance clients do: form-encoded in the body
+// for POST, in the query string otherwise, stamped and signed with secret
+func signedRequest(router *gin.Engine, method, path, apiKey, secret string, params url.Values) *httptest.ResponseRecorder {
+	if params.Get("timestamp") == "" {
+		params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	}
+	payload := params.Encode()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	payload += "&signature=" + hex.EncodeToString(mac.Sum(nil))
+
+	var req *http.Request
+	if method == http.MethodPost {
+		req = httptest.NewRequest(method, path, strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, path+"?"+payload, nil)
+	}
+	if apiKey != "" {
+		req.Header.Set("X-MBX-APIKEY", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type binanceErrorBody struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func TestBinanceHandler(t *testing.T) {
+	t.Run("trades_with_binance_requests_and_responses", func(t *testing.T) {
+		// Given: Two signed keys trading as their own accounts
+		router := newBinanceRouter(t, "maker-key:maker-secret:maker,taker-key:taker-secret:taker")
+
+		// When: The maker offers 1 BTC and the taker buys half of it
+		w := signedRequest(router, http.MethodPost, "/api/v3/order", "maker-key", "maker-secret", url.Values{
+			"symbol": {"BTCUSD"}, "side": {"SELL"}, "type": {"LIMIT"}, "timeInForce": {"GTC"},
+			"quantity": {"1"}, "price": {"60000"}, "newClientOrderId": {"my-ask"},
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected the ask to be placed, got %d: %s", w.Code, w.Body.String())
+		}
+		var ask struct {
+			OrderID       uint64 `json:"orderId"`
+			ClientOrderID string `json:"clientOrderId"`
+			Status        string `json:"status"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &ask)
+		w = signedRequest(router, http.MethodPost, "/api/v3/order", "taker-key", "taker-secret", url.Values{
+			"symbol": {"BTCUSD"}, "side": {"BUY"}, "type": {"LIMIT"}, "timeInForce": {"IOC"},
+			"quantity": {"0.5"}, "price": {"60000"},
+		})
+		var bid struct {
+			Symbol      string `json:"symbol"`
+			Status      string `json:"status"`
+			ExecutedQty string `json:"executedQty"`
+			Fills       []struct {
+				Price   string `json:"price"`
+				Qty     string `json:"qty"`
+				TradeID uint64 `json:"tradeId"`
+			} `json:"fills"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &bid)
+
+		// Then: Responses use Binance symbols, statuses, decimals and fills
+		if ask.OrderID != 1 || ask.ClientOrderID != "my-ask" || ask.Status != "NEW" {
+			t.Errorf("Expected order 1 NEW with its client order id, got %+v", ask)
+		}
+		if bid.Symbol != "BTCUSD" || bid.Status != "FILLED" || bid.ExecutedQty != "0.50000000" ||
+			len(bid.Fills) != 1 || bid.Fills[0].Price != "60000.00000000" || bid.Fills[0].TradeID != 1 {
+			t.Errorf("Expected a FULL response with one fill, got %s", w.Body.String())
+		}
+
+		// And: The maker sees its partly filled order, the book and its balances
+		w = signedRequest(router, http.MethodGet, "/api/v3/order", "maker-key", "maker-secret", url.Values{"symbol": {"BTCUSD"}, "orderId": {"1"}})
+		if !strings.Contains(w.Body.String(), `"status":"PARTIALLY_FILLED"`) || !strings.Contains(w.Body.String(), `"isWorking":true`) {
+			t.Errorf("Expected the ask partly filled and working, got %s", w.Body.String())
+		}
+		depth := httptest.NewRecorder()
+		router.ServeHTTP(depth, httptest.NewRequest(http.MethodGet, "/api/v3/depth?symbol=BTCUSD&limit=5", nil))
+		if !strings.Contains(depth.Body.String(), `"asks":[["60000.00000000","0.50000000"]]`) {
+			t.Errorf("Expected 0.5 offered at 60000, got %s", depth.Body.String())
+		}
+		w = signedRequest(router, http.MethodGet, "/api/v3/account", "maker-key", "maker-secret", url.Values{})
+		if !strings.Contains(w.Body.String(), `{"asset":"BTC","free":"-0.50000000","locked":"0.00000000"}`) {
+			t.Errorf("Expected the maker short 0.5 BTC, got %s", w.Body.String())
+		}
+
+		// And: Other accounts cannot see or cancel it, while the maker cancels by client id
+		w = signedRequest(router, http.MethodDelete, "/api/v3/order", "taker-key", "taker-secret", url.Values{"symbol": {"BTCUSD"}, "orderId": {"1"}})
+		var body binanceErrorBody
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != http.StatusBadRequest || body.Code != -2011 {
+			t.Errorf("Expected -2011 for another account's order, got %d: %s", w.Code, w.Body.String())
+		}
+		w = signedRequest(router, http.MethodDelete, "/api/v3/order", "maker-key", "maker-secret", url.Values{"symbol": {"BTCUSD"}, "origClientOrderId": {"my-ask"}})
+		if !strings.Contains(w.Body.String(), `"status":"CANCELED"`) {
+			t.Errorf("Expected the ask canceled, got %s", w.Body.String())
+		}
+		w = signedRequest(router, http.MethodGet, "/api/v3/openOrders", "maker-key", "maker-secret", url.Values{"symbol": {"BTCUSD"}})
+		if w.Body.String() != "[]" {
+			t.Errorf("Expected no open orders, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("refuses_requests_binance_would", func(t *testing.T) {
+		router := newBinanceRouter(t, "bot-key:bot-secret")
+		order := func() url.Values {
+			return url.Values{"symbol": {"BTCUSD"}, "side": {"BUY"}, "type": {"LIMIT"}, "timeInForce": {"GTC"}, "quantity": {"1"}, "price": {"1000"}}
+		}
+		stale := order()
+		stale.Set("timestamp", strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10))
+		unlisted := order()
+		unlisted.Set("symbol", "DOGEUSD")
+
+		cases := []struct {
+			name           string
+			apiKey, secret string
+			params         url.Values
+			status, code   int
+		}{
+			{"missing_key", "", "bot-secret", order(), http.StatusUnauthorized, -2014},
+			{"unknown_key", "other-key", "bot-secret", order(), http.StatusUnauthorized, -2015},
+			{"bad_signature", "bot-key", "wrong-secret", order(), http.StatusBadRequest, -1022},
+			{"stale_timestamp", "bot-key", "bot-secret", stale, http.StatusBadRequest, -1021},
+			{"unlisted_symbol", "bot-key", "bot-secret", unlisted, http.StatusBadRequest, -1121},
+		}
+		for _, tc := range cases {
+			w := signedRequest(router, http.MethodPost, "/api/v3/order", tc.apiKey, tc.secret, tc.params)
+			var body binanceErrorBody
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tc.status || body.Code != tc.code || body.Msg == "" {
+				t.Errorf("%s: expected %d with code %d, got %d: %s", tc.name, tc.status, tc.code, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("parses_credentials", func(t *testing.T) {
+		credentials, err := handlers.ParseBinanceCredentials("k1:s1, k2:s2:acct-2")
+		if err != nil || len(credentials) != 2 {
+			t.Fatalf("Expected two credentials, got %+v, %v", credentials, err)
+		}
+		if credentials[0].AccountID != "k1" || credentials[1].AccountID != "acct-2" {
+			t.Errorf("Expected accounts k1 and acct-2, got %+v", credentials)
+		}
+		if _, err := handlers.ParseBinanceCredentials("k1"); err == nil {
+			t.Error("Expected a key without a secret to fail")
+		}
+	})
+}
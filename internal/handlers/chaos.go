@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ChaosHandler injects, lists and clears faults in the running venue
+type ChaosHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewChaosHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *ChaosHandler {
+	return &ChaosHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Inject starts the fault described by the body
+func (h *ChaosHandler) Inject(c *gin.Context) {
+	var spec chaos.Spec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	fault, err := h.exchangeService.InjectFault(c.Request.Context(), spec)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, fault)
+}
+
+// List returns the faults in force
+func (h *ChaosHandler) List(c *gin.Context) {
+	faults, err := h.exchangeService.ChaosFaults(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"faults": faults})
+}
+
+// Clear ends a fault early
+func (h *ChaosHandler) Clear(c *gin.Context) {
+	fault, err := h.exchangeService.ClearFault(c.Request.Context(), c.Param("fault_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, fault)
+}
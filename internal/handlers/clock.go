@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/clock"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ClockHandler reports venue time and lets an orchestrator drive a simulated clock
+type ClockHandler struct {
+	clock           ports.Clock
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+// NewClockHandler accepts a nil clock, which reports the wall clock
+func NewClockHandler(venueClock ports.Clock, exchangeService *services.ExchangeService, logger *logrus.Logger) *ClockHandler {
+	if venueClock == nil {
+		venueClock = clock.NewWallClock()
+	}
+	return &ClockHandler{
+		clock:           venueClock,
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// ClockUpdateRequest changes a simulated clock; omitted fields are left as they are
+type ClockUpdateRequest struct {
+	Time   *time.Time `json:"time"`
+	Speed  float64    `json:"speed"`
+	Paused *bool      `json:"paused"`
+}
+
+// ClockAdvanceRequest steps a simulated clock forward
+type ClockAdvanceRequest struct {
+	Duration string `json:"duration" binding:"required"` // Go duration, e.g. "90s" or "8h"
+}
+
+// Get returns the current venue time and clock mode
+func (h *ClockHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.state())
+}
+
+// Update jumps, pauses, resumes or changes the speed of a simulated clock
+func (h *ClockHandler) Update(c *gin.Context) {
+	simulated, ok := h.simulated(c)
+	if !ok {
+		return
+	}
+	var body ClockUpdateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if body.Time != nil {
+		if err := simulated.Set(*body.Time); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+	}
+	if body.Speed != 0 {
+		if err := simulated.SetSpeed(body.Speed); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+	}
+	if body.Paused != nil {
+		if *body.Paused {
+			simulated.Pause()
+		} else {
+			simulated.Resume()
+		}
+	}
+	h.applied(c)
+}
+
+// Advance steps a simulated clock forward and runs everything that fell due
+func (h *ClockHandler) Advance(c *gin.Context) {
+	simulated, ok := h.simulated(c)
+	if !ok {
+		return
+	}
+	var body ClockAdvanceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	step, err := time.ParseDuration(body.Duration)
+	if err == nil {
+		err = simulated.Advance(step)
+	}
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	h.applied(c)
+}
+
+// applied runs scheduled work due at the new time before answering
+func (h *ClockHandler) applied(c *gin.Context) {
+	h.exchangeService.RunScheduledWork(c.Request.Context())
+	state := h.state()
+	h.logger.WithFields(logrus.Fields{
+		"now":    state.Now,
+		"speed":  state.Speed,
+		"paused": state.Paused,
+	}).Info("Simulated clock updated")
+	c.JSON(http.StatusOK, state)
+}
+
+func (h *ClockHandler) simulated(c *gin.Context) (*clock.SimulatedClock, bool) {
+	simulated, ok := h.clock.(*clock.SimulatedClock)
+	if !ok {
+		err := errors.New("venue runs on the wall clock; set CLOCK_MODE=simulated to control it")
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	}
+	return simulated, ok
+}
+
+func (h *ClockHandler) state() clock.State {
+	if reporter, ok := h.clock.(interface{ State() clock.State }); ok {
+		return reporter.State()
+	}
+	return clock.State{Mode: clock.ModeWall, Now: h.clock.Now(), Speed: 1}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// AdminAuthorization refuses requests without "Authorization: Bearer <token>" with
+// 401 and UNAUTHENTICATED; an empty token leaves the routes it guards open. Requests
+// it admits act as the operator on any account.
+func AdminAuthorization(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" {
+			presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				err := services.NewRejection(services.RejectUnauthenticated, errors.New("admin token required"))
+				c.AbortWithStatusJSON(errorStatus(err), errorBody(err))
+				return
+			}
+		}
+		c.Request = c.Request.WithContext(services.AsOperator(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// OperatorIdentity marks requests presenting "Authorization: Bearer <token>" as made
+// by an operator, who may manage any account on the public routes; others pass
+// unmarked
+func OperatorIdentity(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token != "" && ok && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+			c.Request = c.Request.WithContext(services.AsOperator(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// DebugHandler lets operators look inside a running venue: order book dumps,
+// engine queue depths, Go runtime profiles, client cache statistics and the chaos
+// and scenario state in force
+type DebugHandler struct {
+	exchangeService *services.ExchangeService
+	caches          map[string]ports.CacheReporter
+	started         time.Time
+	logger          *logrus.Logger
+}
+
+func NewDebugHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *DebugHandler {
+	return &DebugHandler{
+		exchangeService: exchangeService,
+		caches:          make(map[string]ports.CacheReporter),
+		started:         time.Now(),
+		logger:          logger,
+	}
+}
+
+// WithCache reports a client's cache statistics under name
+func (h *DebugHandler) WithCache(name string, cache ports.CacheReporter) *DebugHandler {
+	h.caches[name] = cache
+	return h
+}
+
+// Book dumps every order resting in a book, in priority order
+func (h *DebugHandler) Book(c *gin.Context) {
+	dump, err := h.exchangeService.DumpBook(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, dump)
+}
+
+// Engine reports the engine's sequence and each book's queue depth
+func (h *DebugHandler) Engine(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.EngineStats(c.Request.Context()))
+}
+
+// State reports the degradation mode, faults, halts, scenario, script and replay
+func (h *DebugHandler) State(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.DebugState(c.Request.Context()))
+}
+
+// Caches reports each registered client's cache statistics by name
+func (h *DebugHandler) Caches(c *gin.Context) {
+	caches := make(map[string]ports.CacheStats, len(h.caches))
+	for name, cache := range h.caches {
+		caches[name] = cache.CacheStats()
+	}
+	c.JSON(http.StatusOK, gin.H{"caches": caches})
+}
+
+// Runtime reports the process's goroutines, memory and uptime
+func (h *DebugHandler) Runtime(c *gin.Context) {
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	c.JSON(http.StatusOK, gin.H{
+		"go_version":     runtime.Version(),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"uptime_seconds": time.Since(h.started).Seconds(),
+		"memory": gin.H{
+			"heap_alloc_bytes":  memory.HeapAlloc,
+			"heap_objects":      memory.HeapObjects,
+			"heap_sys_bytes":    memory.HeapSys,
+			"stack_inuse_bytes": memory.StackInuse,
+			"gc_cycles":         memory.NumGC,
+			"gc_pause_total_ns": memory.PauseTotalNs,
+		},
+	})
+}
+
+// Profile serves the net/http/pprof index and profiles under the route's *profile,
+// e.g. goroutine?debug=2 for every goroutine's stack or profile?seconds=30 for CPU
+func (h *DebugHandler) Profile(c *gin.Context) {
+	switch name := strings.TrimPrefix(c.Param("profile"), "/"); name {
+	case "":
+		pprof.Index(c.Writer, c.Request)
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		if runtimepprof.Lookup(name) == nil {
+			err := services.NewRejection(services.RejectInvalidRequest, fmt.Errorf("unknown profile %q", name))
+			c.JSON(http.StatusNotFound, errorBody(err))
+			return
+		}
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
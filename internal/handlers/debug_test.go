@@ -0,0 +1,144 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+type fixedCache ports.CacheStats
+
+func (c fixedCache) CacheStats() ports.CacheStats { return ports.CacheStats(c) }
+
+// TestDebugHandler verifies operators can inspect a running venue
+// Following BDD Given/When/Then pattern
+func TestDebugHandler(t *testing.T) {
+	newDebugRouter := func(token string) (*gin.Engine, *services.ExchangeService) {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		debugHandler := handlers.NewDebugHandler(exchangeService, logger).WithCache("settings", fixedCache{Entries: 3, Hits: 7})
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		admin := router.Group("/api/v1/admin", handlers.AdminAuthorization(token))
+		admin.GET("/debug/books/:symbol", debugHandler.Book)
+		admin.GET("/debug/engine", debugHandler.Engine)
+		admin.GET("/debug/state", debugHandler.State)
+		admin.GET("/debug/caches", debugHandler.Caches)
+		admin.GET("/debug/runtime", debugHandler.Runtime)
+		admin.GET("/debug/pprof/*profile", debugHandler.Profile)
+		return router, exchangeService
+	}
+	get := func(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requires_the_admin_token_once_set", func(t *testing.T) {
+		// Given: Admin routes guarded by a token
+		router, _ := newDebugRouter("s3cret")
+
+		// When: They are called without, with a wrong and with the right token
+		missing := get(router, "/api/v1/admin/debug/engine", "")
+		wrong := get(router, "/api/v1/admin/debug/engine", "guess")
+		right := get(router, "/api/v1/admin/debug/engine", "s3cret")
+
+		// Then: Only the right token gets in
+		if missing.Code != http.StatusUnauthorized || wrong.Code != http.StatusUnauthorized || !strings.Contains(wrong.Body.String(), "UNAUTHENTICATED") {
+			t.Errorf("Expected 401 UNAUTHENTICATED, got %d and %d %s", missing.Code, wrong.Code, wrong.Body.String())
+		}
+		if right.Code != http.StatusOK {
+			t.Errorf("Expected the token accepted, got %d %s", right.Code, right.Body.String())
+		}
+	})
+
+	t.Run("dumps_every_resting_order", func(t *testing.T) {
+		// Given: Two resting bids at one price
+		router, exchangeService := newDebugRouter("")
+		for _, account := range []string{"a", "b"} {
+			if _, err := exchangeService.PlaceOrder(context.Background(), services.OrderRequest{AccountID: account, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000}); err != nil {
+				t.Fatalf("Failed to place order: %v", err)
+			}
+		}
+
+		// When: The book is dumped, and an unlisted one asked for
+		w := get(router, "/api/v1/admin/debug/books/BTC-USD", "")
+		unknown := get(router, "/api/v1/admin/debug/books/DOGE-USD", "")
+
+		// Then: Both orders are listed individually in time priority
+		var dump matching.BookDump
+		json.Unmarshal(w.Body.Bytes(), &dump)
+		if w.Code != http.StatusOK || len(dump.Bids) != 1 || len(dump.Bids[0].Orders) != 2 || dump.Bids[0].Orders[0].AccountID != "a" {
+			t.Errorf("Expected both bids at one level, got %d %s", w.Code, w.Body.String())
+		}
+		if unknown.Code != http.StatusNotFound {
+			t.Errorf("Expected an unlisted symbol not found, got %d", unknown.Code)
+		}
+	})
+
+	t.Run("reports_the_engine_state_and_caches", func(t *testing.T) {
+		// Given: A running venue with a registered cache
+		router, _ := newDebugRouter("")
+
+		// When: The engine, state and cache endpoints are read
+		engine := get(router, "/api/v1/admin/debug/engine", "")
+		state := get(router, "/api/v1/admin/debug/state", "")
+		caches := get(router, "/api/v1/admin/debug/caches", "")
+
+		// Then: Each book's queue, the degradation mode and the cache are reported
+		var stats services.EngineStats
+		json.Unmarshal(engine.Body.Bytes(), &stats)
+		if stats.Books == 0 || len(stats.Queues) != stats.Books || stats.Goroutines == 0 {
+			t.Errorf("Expected a queue per book, got %s", engine.Body.String())
+		}
+		var debugState services.DebugState
+		json.Unmarshal(state.Body.Bytes(), &debugState)
+		if state.Code != http.StatusOK || debugState.Degradation.Mode != "normal" {
+			t.Errorf("Expected the normal mode reported, got %s", state.Body.String())
+		}
+		if !strings.Contains(caches.Body.String(), `"settings":{"entries":3,`) || !strings.Contains(caches.Body.String(), `"hits":7`) {
+			t.Errorf("Expected the settings cache reported, got %s", caches.Body.String())
+		}
+	})
+
+	t.Run("serves_runtime_profiles", func(t *testing.T) {
+		// Given: A running venue
+		router, _ := newDebugRouter("")
+
+		// When: The profile index, goroutine stacks and an unknown profile are read
+		index := get(router, "/api/v1/admin/debug/pprof/", "")
+		goroutines := get(router, "/api/v1/admin/debug/pprof/goroutine?debug=1", "")
+		unknown := get(router, "/api/v1/admin/debug/pprof/nothing", "")
+
+		// Then: The profiles are served and the unknown one is not found
+		if index.Code != http.StatusOK || !strings.Contains(index.Body.String(), "goroutine") {
+			t.Errorf("Expected the profile index, got %d", index.Code)
+		}
+		if goroutines.Code != http.StatusOK || !strings.Contains(goroutines.Body.String(), "goroutine profile:") {
+			t.Errorf("Expected goroutine stacks, got %d %s", goroutines.Code, goroutines.Body.String())
+		}
+		if unknown.Code != http.StatusNotFound {
+			t.Errorf("Expected an unknown profile not found, got %d", unknown.Code)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// DegradationHandler switches the venue's partial outage mode and refuses the
+// requests it does not serve
+type DegradationHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewDegradationHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *DegradationHandler {
+	return &DegradationHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Guard answers 503 and ENGINE_UNAVAILABLE to requests the mode refuses. Admin
+// routes, health, readiness and metrics are always served, so the mode can be
+// switched back and watched.
+func (h *DegradationHandler) Guard(c *gin.Context) {
+	path := c.Request.URL.Path
+	switch {
+	case path == "/api/v1/health", path == "/api/v1/ready", path == "/metrics",
+		strings.HasPrefix(path, "/api/v1/admin/"):
+		c.Next()
+		return
+	}
+	if err := h.exchangeService.Admit(requestOperation(c)); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.Next()
+}
+
+// requestOperation is what a request does: lookups and dry runs read, deleting an
+// order cancels and the rest change the venue
+func requestOperation(c *gin.Context) chaos.Operation {
+	route := c.FullPath()
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return chaos.OperationRead
+	case http.MethodDelete:
+		if strings.HasSuffix(route, "/orders/:order_id") || route == "/api/v3/order" {
+			return chaos.OperationCancel
+		}
+	case http.MethodPost:
+		if strings.HasSuffix(route, "/preview") || route == "/api/v3/order/test" {
+			return chaos.OperationRead
+		}
+	}
+	return chaos.OperationChange
+}
+
+// Get reports the mode in force
+func (h *DegradationHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.DegradationMode(c.Request.Context()))
+}
+
+// Set switches the mode, {"mode": "read_only"}; "normal" ends the outage
+func (h *DegradationHandler) Set(c *gin.Context) {
+	var body struct {
+		Mode chaos.Mode `json:"mode" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	status, err := h.exchangeService.SetDegradationMode(c.Request.Context(), body.Mode)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
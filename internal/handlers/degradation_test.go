@@ -0,0 +1,68 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func TestDegradationHandler(t *testing.T) {
+	t.Run("guards_routes_and_reports_the_mode_in_readiness", func(t *testing.T) {
+		// Given: A router guarded by the venue's degradation mode
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		degradationHandler := handlers.NewDegradationHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		healthHandler := handlers.NewHealthHandler(logger).WithExchange(exchangeService)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(degradationHandler.Guard)
+		router.GET("/api/v1/ready", healthHandler.Ready)
+		router.POST("/api/v1/orders", orderHandler.Place)
+		router.GET("/api/v1/book/:symbol", orderHandler.Book)
+		router.PUT("/api/v1/admin/degradation", degradationHandler.Set)
+		order := `{"account_id":"a","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000}`
+
+		// When: The venue goes read-only, then dark
+		if w := serve(router, http.MethodPut, "/api/v1/admin/degradation", `{"mode": "read_only"}`); w.Code != http.StatusOK {
+			t.Fatalf("Expected the mode switched, got %d: %s", w.Code, w.Body.String())
+		}
+		refused := serve(router, http.MethodPost, "/api/v1/orders", order)
+		book := serve(router, http.MethodGet, "/api/v1/book/BTC-USD", "")
+		degraded := serve(router, http.MethodGet, "/api/v1/ready", "")
+		serve(router, http.MethodPut, "/api/v1/admin/degradation", `{"mode": "blackout"}`)
+		dark := serve(router, http.MethodGet, "/api/v1/book/BTC-USD", "")
+		notReady := serve(router, http.MethodGet, "/api/v1/ready", "")
+
+		// Then: Orders are refused while reads go on, until the blackout refuses those too
+		var body map[string]interface{}
+		json.Unmarshal(refused.Body.Bytes(), &body)
+		if refused.Code != http.StatusServiceUnavailable || body["code"] != "ENGINE_UNAVAILABLE" {
+			t.Errorf("Expected 503 ENGINE_UNAVAILABLE, got %d %s", refused.Code, refused.Body.String())
+		}
+		if book.Code != http.StatusOK || dark.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected the book served until the blackout, got %d then %d", book.Code, dark.Code)
+		}
+		var readiness map[string]interface{}
+		json.Unmarshal(degraded.Body.Bytes(), &readiness)
+		if degraded.Code != http.StatusOK || readiness["status"] != "degraded" || readiness["mode"] != "read_only" {
+			t.Errorf("Expected ready but degraded, got %d %s", degraded.Code, degraded.Body.String())
+		}
+		if notReady.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected not ready during the blackout, got %d %s", notReady.Code, notReady.Body.String())
+		}
+		if w := serve(router, http.MethodPut, "/api/v1/admin/degradation", `{"mode": "maintenance"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected an unknown mode refused, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// errorStatus maps domain errors to HTTP status codes, defaulting to 400
+func errorStatus(err error) int {
+	if errors.Is(err, services.ErrPurgeIncomplete) {
+		return http.StatusInternalServerError
+	}
+	if errors.Is(err, services.ErrInstrumentChangeNotFound) || errors.Is(err, services.ErrAccountProfileNotFound) || errors.Is(err, services.ErrSettlementCycleNotFound) ||
+		errors.Is(err, runmetrics.ErrNotPersisted) || errors.Is(err, scenario.ErrNoScenario) || errors.Is(err, scenario.ErrNoScript) || errors.Is(err, replay.ErrNoReplay) || errors.Is(err, accounts.ErrNotFound) || errors.Is(err, accounts.ErrKeyNotFound) ||
+		errors.Is(err, runbundle.ErrNotStarted) || errors.Is(err, runbundle.ErrNoJournal) || errors.Is(err, chaos.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, accounts.ErrClosed) {
+		return http.StatusConflict
+	}
+	switch services.RejectionOf(err).Reason {
+	case services.RejectUnknownInstrument,
+		services.RejectOrderNotFound:
+		return http.StatusNotFound
+	case services.RejectOrderNotActive,
+		services.RejectMarketClosed,
+		services.RejectInstrumentHalted,
+		services.RejectInvalidPhase,
+		services.RejectDuplicateClientOrderID,
+		services.RejectIdempotencyKeyReused:
+		return http.StatusConflict
+	case services.RejectEngineUnavailable:
+		return http.StatusServiceUnavailable
+	case services.RejectSubscriptionLimit,
+		services.RejectRateLimited:
+		return http.StatusTooManyRequests
+	case services.RejectAPIVersionRetired:
+		return http.StatusGone
+	case services.RejectPermissionDenied:
+		return http.StatusForbidden
+	case services.RejectUnauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusBadRequest
+}
+
+// invalidRequest answers a malformed request with the same envelope as a rejection
+func invalidRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, errorBody(services.NewRejection(services.RejectInvalidRequest, err)))
+}
+
+// errorBody is the JSON body of a failed request: the message plus its reject reason code
+func errorBody(err error) gin.H {
+	return gin.H{
+		"error": err.Error(),
+		"code":  services.RejectionOf(err).Reason,
+	}
+}
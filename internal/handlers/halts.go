@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// HaltHandler exposes trading halt status and manual halt/resume controls
+type HaltHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewHaltHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *HaltHandler {
+	return &HaltHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns all active halts
+func (h *HaltHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"halts": h.exchangeService.ActiveHalts(c.Request.Context()),
+	})
+}
+
+// Halt manually halts a symbol
+func (h *HaltHandler) Halt(c *gin.Context) {
+	info, err := h.exchangeService.HaltTrading(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// Resume ends a halt on a symbol
+func (h *HaltHandler) Resume(c *gin.Context) {
+	symbol := c.Param("symbol")
+	if err := h.exchangeService.ResumeTrading(c.Request.Context(), symbol); err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"phase":  matching.PhaseContinuous,
+	})
+}
@@ -1,17 +1,45 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/migrations"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
 	"github.com/sirupsen/logrus"
 )
 
+// defaultReadinessTimeout limits each dependency check when none is configured
+const defaultReadinessTimeout = 2 * time.Second
+
+// DependencyCheck is one dependency /api/v1/ready checks
+type DependencyCheck struct {
+	Name     string                          // Reported and labelled as, e.g. "matching_engine"
+	Critical bool                            // Failing it makes the service not ready; others are reported only
+	Check    func(ctx context.Context) error // Nil when the dependency is ready; ctx carries the timeout
+}
+
+// dependencyStatus is how one dependency answered its readiness check
+type dependencyStatus struct {
+	Status    string  `json:"status"` // "ok" or "failed"
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
 type HealthHandler struct {
-	config *config.Config
-	logger *logrus.Logger
+	config           *config.Config
+	exchangeService  *services.ExchangeService // nil leaves the degradation mode unreported
+	startup          *startup.Coordinator      // nil when readiness does not wait on startup
+	schema           *migrations.Status        // nil when the venue keeps nothing in Postgres
+	dependencies     []DependencyCheck
+	readinessTimeout time.Duration
+	logger           *logrus.Logger
 }
 
 // NewHealthHandler creates a basic health handler
@@ -29,6 +57,33 @@ func NewHealthHandlerWithConfig(cfg *config.Config, logger *logrus.Logger) *Heal
 	}
 }
 
+// WithExchange reports the venue's degradation mode in health and readiness
+func (h *HealthHandler) WithExchange(exchangeService *services.ExchangeService) *HealthHandler {
+	h.exchangeService = exchangeService
+	return h
+}
+
+// WithStartup holds readiness at 503 "starting" until coordinator is serving, and
+// reports its components' startup states
+func (h *HealthHandler) WithStartup(coordinator *startup.Coordinator) *HealthHandler {
+	h.startup = coordinator
+	return h
+}
+
+// WithSchema reports the Postgres schema version read at startup in health
+func (h *HealthHandler) WithSchema(status *migrations.Status) *HealthHandler {
+	h.schema = status
+	return h
+}
+
+// WithDependencies checks dependencies on every readiness probe, each within timeout
+// (0 = two seconds)
+func (h *HealthHandler) WithDependencies(timeout time.Duration, dependencies ...DependencyCheck) *HealthHandler {
+	h.readinessTimeout = timeout
+	h.dependencies = append(h.dependencies, dependencies...)
+	return h
+}
+
 func (h *HealthHandler) Health(c *gin.Context) {
 	response := gin.H{
 		"status":    "healthy",
@@ -47,15 +102,134 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		response["version"] = "1.0.0"
 	}
 
+	if h.exchangeService != nil {
+		mode := h.exchangeService.DegradationMode(c.Request.Context()).Mode
+		response["mode"] = mode
+		if mode != chaos.ModeNormal {
+			response["status"] = "degraded"
+		}
+	}
+
+	if h.schema != nil {
+		response["schema"] = h.schema
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// Ready checks every dependency concurrently, each within the readiness timeout,
+// and answers 503 when a critical one fails or during a blackout. Other
+// degradation modes and failing non-critical dependencies stay ready but are
+// reported. Each dependency's service_dependency_ready gauge is set as checked.
+// Until startup finishes it answers 503 "starting" without checking anything.
 func (h *HealthHandler) Ready(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ready",
-		"checks": gin.H{
-			"database": "ok",
-			"redis":    "ok",
-		},
-	})
+	if h.startup != nil && !h.startup.Ready() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "starting", "startup": h.startup.Report()})
+		return
+	}
+
+	dependencies, criticalFailed := h.checkDependencies(c.Request.Context())
+	code, status := http.StatusOK, "ready"
+	if criticalFailed {
+		code, status = http.StatusServiceUnavailable, "not_ready"
+	}
+	response := gin.H{"dependencies": dependencies}
+
+	if h.exchangeService != nil {
+		mode := h.exchangeService.DegradationMode(c.Request.Context()).Mode
+		checks := gin.H{"trading": "ok", "market_data": "ok"}
+		if !mode.Trading() {
+			checks["trading"] = "refused"
+		}
+		if mode.FreezesMarketData() {
+			checks["market_data"] = "stale"
+		}
+		switch {
+		case mode == chaos.ModeBlackout:
+			code, status = http.StatusServiceUnavailable, "not_ready"
+		case mode != chaos.ModeNormal && !criticalFailed:
+			status = "degraded"
+		}
+		response["mode"] = mode
+		response["checks"] = checks
+	}
+
+	if h.startup != nil {
+		response["startup"] = h.startup.Report()
+	}
+	response["status"] = status
+	c.JSON(code, response)
+}
+
+// checkDependencies runs every dependency check, reporting each by name and whether
+// a critical one failed
+func (h *HealthHandler) checkDependencies(ctx context.Context) (map[string]dependencyStatus, bool) {
+	statuses := make(map[string]dependencyStatus, len(h.dependencies))
+	if len(h.dependencies) == 0 {
+		return statuses, false
+	}
+	timeout := h.readinessTimeout
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		index   int
+		latency time.Duration
+		err     error
+	}
+	outcomes := make(chan outcome, len(h.dependencies))
+	for i, dependency := range h.dependencies {
+		go func(i int, check func(context.Context) error) {
+			start := time.Now()
+			err := check(ctx)
+			outcomes <- outcome{index: i, latency: time.Since(start), err: err}
+		}(i, dependency.Check)
+	}
+
+	// A check ignoring its deadline is reported failed rather than holding the probe
+	results := make([]*outcome, len(h.dependencies))
+	start := time.Now()
+collect:
+	for range h.dependencies {
+		select {
+		case result := <-outcomes:
+			results[result.index] = &result
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	criticalFailed := false
+	for i, dependency := range h.dependencies {
+		result := results[i]
+		if result == nil {
+			result = &outcome{index: i, latency: time.Since(start), err: ctx.Err()}
+		}
+		status := dependencyStatus{
+			Status:    "ok",
+			Critical:  dependency.Critical,
+			LatencyMs: float64(result.latency.Microseconds()) / 1000,
+		}
+		ready := float64(1)
+		if result.err != nil {
+			status.Status, status.Error, ready = "failed", result.err.Error(), 0
+			entry := h.logger.WithError(result.err).WithField("dependency", dependency.Name)
+			if dependency.Critical {
+				criticalFailed = true
+				entry.Warn("Critical dependency not ready")
+			} else {
+				entry.Info("Dependency not ready")
+			}
+		}
+		statuses[dependency.Name] = status
+		if h.config != nil {
+			if metricsPort := h.config.GetMetricsPort(); metricsPort != nil {
+				metricsPort.SetGauge("service_dependency_ready", ready, map[string]string{"dependency": dependency.Name})
+			}
+		}
+	}
+	return statuses, criticalFailed
 }
\ No newline at end of file
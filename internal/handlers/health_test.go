@@ -0,0 +1,201 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/migrations"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
+)
+
+// TestHealthHandler_Ready verifies readiness reflects real dependency checks
+// Following BDD Given/When/Then pattern
+func TestHealthHandler_Ready(t *testing.T) {
+	type readiness struct {
+		Status       string `json:"status"`
+		Dependencies map[string]struct {
+			Status    string  `json:"status"`
+			Critical  bool    `json:"critical"`
+			LatencyMs float64 `json:"latency_ms"`
+			Error     string  `json:"error"`
+		} `json:"dependencies"`
+	}
+	ready := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	probe := func(timeout time.Duration, dependencies ...handlers.DependencyCheck) (*httptest.ResponseRecorder, readiness, string) {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		cfg := &config.Config{ServiceName: "exchange-simulator"}
+		metricsPort := observability.NewPrometheusMetricsAdapter(map[string]string{"service": "exchange-simulator"})
+		cfg.SetMetricsPort(metricsPort)
+		healthHandler := handlers.NewHealthHandlerWithConfig(cfg, logger).WithDependencies(timeout, dependencies...)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/api/v1/ready", healthHandler.Ready)
+		w := serve(router, http.MethodGet, "/api/v1/ready", "")
+		var body readiness
+		json.Unmarshal(w.Body.Bytes(), &body)
+		metrics := httptest.NewRecorder()
+		metricsPort.GetHTTPHandler().ServeHTTP(metrics, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w, body, metrics.Body.String()
+	}
+	gauge := func(metrics, dependency string) string {
+		for _, line := range strings.Split(metrics, "\n") {
+			if strings.HasPrefix(line, `service_dependency_ready{dependency="`+dependency+`"`) {
+				return line[strings.LastIndex(line, " ")+1:]
+			}
+		}
+		return ""
+	}
+
+	t.Run("stays_ready_while_only_non_critical_dependencies_fail", func(t *testing.T) {
+		// Given: A live critical dependency and a failing non-critical one
+		// When: Readiness is probed
+		w, body, metrics := probe(time.Second,
+			handlers.DependencyCheck{Name: "matching_engine", Critical: true, Check: ready},
+			handlers.DependencyCheck{Name: "configuration_service", Check: down})
+
+		// Then: The service is ready, each dependency is reported and its gauge set
+		if w.Code != http.StatusOK || body.Status != "ready" {
+			t.Fatalf("Expected ready, got %d %s", w.Code, w.Body.String())
+		}
+		engine, configuration := body.Dependencies["matching_engine"], body.Dependencies["configuration_service"]
+		if engine.Status != "ok" || !engine.Critical || engine.LatencyMs < 0 {
+			t.Errorf("Expected the engine ok and critical, got %+v", engine)
+		}
+		if configuration.Status != "failed" || configuration.Critical || configuration.Error != "connection refused" {
+			t.Errorf("Expected the configuration service failed, got %+v", configuration)
+		}
+		if gauge(metrics, "matching_engine") != "1" || gauge(metrics, "configuration_service") != "0" {
+			t.Errorf("Expected the gauges set to 1 and 0, got\n%s", metrics)
+		}
+	})
+
+	t.Run("answers_503_when_a_critical_dependency_fails", func(t *testing.T) {
+		// Given: A failing critical dependency
+		// When: Readiness is probed
+		w, body, metrics := probe(time.Second,
+			handlers.DependencyCheck{Name: "data_adapter", Critical: true, Check: down})
+
+		// Then: The service is not ready
+		if w.Code != http.StatusServiceUnavailable || body.Status != "not_ready" {
+			t.Errorf("Expected not ready, got %d %s", w.Code, w.Body.String())
+		}
+		if gauge(metrics, "data_adapter") != "0" {
+			t.Errorf("Expected the gauge set to 0, got\n%s", metrics)
+		}
+	})
+
+	t.Run("fails_checks_that_outlive_the_timeout", func(t *testing.T) {
+		// Given: A critical dependency that never answers
+		hang := make(chan struct{})
+		defer close(hang)
+		stuck := func(ctx context.Context) error {
+			<-hang
+			return nil
+		}
+
+		// When: Readiness is probed with a short timeout
+		start := time.Now()
+		w, body, _ := probe(20*time.Millisecond,
+			handlers.DependencyCheck{Name: "redis_discovery", Critical: true, Check: stuck})
+
+		// Then: The probe answers on time, reporting the dependency timed out
+		if time.Since(start) > time.Second || w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected a prompt 503, got %d after %v", w.Code, time.Since(start))
+		}
+		if dependency := body.Dependencies["redis_discovery"]; dependency.Status != "failed" || dependency.Error != context.DeadlineExceeded.Error() {
+			t.Errorf("Expected a timed out dependency, got %+v", dependency)
+		}
+	})
+
+	t.Run("answers_starting_until_startup_is_serving", func(t *testing.T) {
+		// Given: A health handler waiting on a startup coordinator that has run
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		coordinator := startup.NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		coordinator.Add(startup.Component{Name: "matching_engine", Critical: true, Start: ready})
+		if err := coordinator.Run(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+		healthHandler := handlers.NewHealthHandler(logger).WithStartup(coordinator)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/api/v1/ready", healthHandler.Ready)
+
+		// When: Readiness is probed before the servers are marked serving
+		w := serve(router, http.MethodGet, "/api/v1/ready", "")
+
+		// Then: It answers 503 starting with the components' states
+		var body struct {
+			Status  string         `json:"status"`
+			Startup startup.Report `json:"startup"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != http.StatusServiceUnavailable || body.Status != "starting" {
+			t.Fatalf("Expected 503 starting, got %d %s", w.Code, w.Body.String())
+		}
+		if len(body.Startup.Components) != 1 || body.Startup.Components[0].State != startup.StateUp {
+			t.Errorf("Expected the engine reported up, got %+v", body.Startup.Components)
+		}
+
+		// And: Once serving, it is ready
+		coordinator.Serve()
+		if w := serve(router, http.MethodGet, "/api/v1/ready", ""); w.Code != http.StatusOK {
+			t.Errorf("Expected ready once serving, got %d %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestHealthHandler_Health(t *testing.T) {
+	health := func(healthHandler *handlers.HealthHandler) map[string]json.RawMessage {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/api/v1/health", healthHandler.Health)
+		w := serve(router, http.MethodGet, "/api/v1/health", "")
+		var body map[string]json.RawMessage
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return body
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	cfg := &config.Config{ServiceName: "exchange-simulator"}
+
+	t.Run("reports_the_schema_version", func(t *testing.T) {
+		// Given: A venue whose schema was migrated to version 7 at startup
+		status := &migrations.Status{Version: 7, Latest: 7, Applied: 2}
+
+		// When: Health is checked
+		body := health(handlers.NewHealthHandlerWithConfig(cfg, logger).WithSchema(status))
+
+		// Then: The version is reported
+		var schema migrations.Status
+		if err := json.Unmarshal(body["schema"], &schema); err != nil || schema.Version != 7 || schema.Applied != 2 {
+			t.Errorf("Expected schema version 7, got %s", body["schema"])
+		}
+	})
+
+	t.Run("leaves_the_schema_out_without_postgres", func(t *testing.T) {
+		// When: Health is checked on a venue keeping nothing in Postgres
+		body := health(handlers.NewHealthHandlerWithConfig(cfg, logger).WithSchema(nil))
+
+		// Then: No schema is reported
+		if _, reported := body["schema"]; reported {
+			t.Errorf("Expected no schema, got %s", body["schema"])
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// IncidentHandler exposes the venue's health transitions for post-scenario reports
+type IncidentHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewIncidentHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns unhealthy components and health transitions oldest first; query params:
+// since (RFC 3339), component (exact, or a kind prefix such as "instrument:"), limit
+func (h *IncidentHandler) List(c *gin.Context) {
+	query := incidents.Query{Component: c.Query("component")}
+	if since := c.Query("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			invalidRequest(c, errors.New("since must be an RFC 3339 time"))
+			return
+		}
+		query.Since = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			invalidRequest(c, errors.New("limit must be a number"))
+			return
+		}
+		query.Limit = parsed
+	}
+
+	report, err := h.exchangeService.Incidents(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// StorageLatency returns each storage component's call counts, slow calls and
+// latency percentiles over its most recent calls
+func (h *IncidentHandler) StorageLatency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.StorageLatency(c.Request.Context()))
+}
+
+// WriteBehind returns the depth, high-water mark and throughput of each queue
+// writing trades and orders behind matching
+func (h *IncidentHandler) WriteBehind(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.WriteBehindStats(c.Request.Context()))
+}
@@ -0,0 +1,111 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newIncidentRouter() (*gin.Engine, *services.ExchangeService) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+	incidentHandler := handlers.NewIncidentHandler(exchangeService, logger)
+	haltHandler := handlers.NewHaltHandler(exchangeService, logger)
+	orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/orders", orderHandler.Place)
+	router.POST("/api/v1/admin/halts/:symbol", haltHandler.Halt)
+	router.POST("/api/v1/admin/halts/:symbol/resume", haltHandler.Resume)
+	router.GET("/api/v1/admin/incidents", incidentHandler.List)
+	return router, exchangeService
+}
+
+func readIncidents(t *testing.T, router *gin.Engine, query string) services.IncidentReport {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/api/v1/admin/incidents"+query, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report services.IncidentReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Expected an incident report, got %s", w.Body.String())
+	}
+	return report
+}
+
+func TestIncidentHandler(t *testing.T) {
+	t.Run("reconstructs_halts_and_outages_in_order", func(t *testing.T) {
+		// Given: A venue whose data adapter is down and whose BTC-USD was halted and resumed
+		router, exchangeService := newIncidentRouter()
+		exchangeService.ReportHealth(context.Background(), services.ComponentDataAdapter, incidents.StatusDown, "connection refused")
+		serve(router, http.MethodPost, "/api/v1/admin/halts/BTC-USD", "")
+		serve(router, http.MethodPost, "/api/v1/admin/halts/BTC-USD/resume", "")
+
+		// When: The incident timeline is read
+		report := readIncidents(t, router, "")
+
+		// Then: The outage, halt and resumption appear in order and only the adapter is unhealthy
+		if len(report.Incidents) != 3 {
+			t.Fatalf("Expected three transitions, got %+v", report.Incidents)
+		}
+		halt, resume := report.Incidents[1], report.Incidents[2]
+		if halt.Component != "instrument:BTC-USD" || halt.Status != incidents.StatusHalted || halt.Detail != "manual halt" {
+			t.Errorf("Expected the manual BTC-USD halt, got %+v", halt)
+		}
+		if resume.Status != incidents.StatusUp || resume.Previous != incidents.StatusHalted {
+			t.Errorf("Expected BTC-USD to resume, got %+v", resume)
+		}
+		if len(report.Unhealthy) != 1 || report.Unhealthy[0].Component != services.ComponentDataAdapter {
+			t.Errorf("Expected only the data adapter unhealthy, got %+v", report.Unhealthy)
+		}
+	})
+
+	t.Run("notes_circuit_breaker_halts", func(t *testing.T) {
+		// Given: A trade that moves BTC-USD past the circuit breaker threshold
+		router, exchangeService := newIncidentRouter()
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"maker","symbol":"BTC-USD","side":"sell","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"taker","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"maker","symbol":"BTC-USD","side":"sell","quantity":1,"price":70000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"taker","symbol":"BTC-USD","side":"buy","quantity":1,"price":70000}`)
+		if len(exchangeService.ActiveHalts(context.Background())) != 1 {
+			t.Fatalf("Expected the circuit breaker to halt BTC-USD")
+		}
+
+		// When: Scheduled work runs and the instrument timeline is read
+		exchangeService.RunScheduledWork(context.Background())
+		report := readIncidents(t, router, "?component=instrument:")
+
+		// Then: The volatility halt is recorded with its move
+		if len(report.Incidents) != 1 || report.Incidents[0].Status != incidents.StatusHalted {
+			t.Fatalf("Expected the volatility halt, got %+v", report.Incidents)
+		}
+		if detail := report.Incidents[0].Detail; !strings.HasPrefix(detail, "volatility halt: ") {
+			t.Errorf("Expected a volatility halt detail, got %q", detail)
+		}
+	})
+
+	t.Run("rejects_invalid_filters", func(t *testing.T) {
+		router, _ := newIncidentRouter()
+		for _, query := range []string{"?since=yesterday", "?limit=ten", "?limit=-1", "?component=BTC-USD"} {
+			if w := serve(router, http.MethodGet, "/api/v1/admin/incidents"+query, ""); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+}
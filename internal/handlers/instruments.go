@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// InstrumentHandler exposes listed instruments, scheduled contract specification changes
+// and their announcements
+type InstrumentHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewInstrumentHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *InstrumentHandler {
+	return &InstrumentHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns listed instruments, limited to those trading the optional asset query parameter
+func (h *InstrumentHandler) List(c *gin.Context) {
+	h.exchangeService.ActivateInstrumentChanges(c.Request.Context())
+
+	registry := h.exchangeService.Instruments()
+	instruments := registry.List()
+	if asset := c.Query("asset"); asset != "" {
+		instruments = registry.ByAsset(asset)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"instruments": instruments,
+	})
+}
+
+// Changes lists scheduled and past changes, filtered by the optional symbol query parameter
+func (h *InstrumentHandler) Changes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"changes": h.exchangeService.InstrumentChanges(c.Request.Context(), c.Query("symbol")),
+	})
+}
+
+// Events returns the announcement feed after the optional since sequence
+func (h *InstrumentHandler) Events(c *gin.Context) {
+	since, err := strconv.ParseUint(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil {
+		invalidRequest(c, errors.New("since must be a non-negative sequence number"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"events": h.exchangeService.InstrumentEvents(c.Request.Context(), since),
+	})
+}
+
+// Schedule announces a tick size, lot size or price band change for a symbol
+func (h *InstrumentHandler) Schedule(c *gin.Context) {
+	var body services.InstrumentChangeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	change, err := h.exchangeService.ScheduleInstrumentChange(c.Request.Context(), c.Param("symbol"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, change)
+}
+
+// Cancel withdraws a change before it takes effect
+func (h *InstrumentHandler) Cancel(c *gin.Context) {
+	change, err := h.exchangeService.CancelInstrumentChange(c.Request.Context(), c.Param("change_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, change)
+}
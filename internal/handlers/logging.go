@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// LogHandler changes the log level, per-component levels and sampling at runtime
+type LogHandler struct {
+	control *observability.LogControl
+	logger  *logrus.Logger
+}
+
+func NewLogHandler(control *observability.LogControl, logger *logrus.Logger) *LogHandler {
+	return &LogHandler{
+		control: control,
+		logger:  logger,
+	}
+}
+
+// Get reports the settings in force and the entries sampling dropped
+func (h *LogHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.control.Status())
+}
+
+// Set replaces the settings, {"level": "info", "components": {"matching_engine":
+// "debug"}, "sampling": {"matching_engine": {"initial": 100, "thereafter": 50}}}
+func (h *LogHandler) Set(c *gin.Context) {
+	var settings observability.LogSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	updated, err := h.control.Set(settings)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+const (
+	defaultKlineLimit = 500
+	maxKlineLimit     = marketdata.MaxCandles
+)
+
+// MarketDataHandler serves 24h tickers, klines and exchange statistics
+type MarketDataHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewMarketDataHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *MarketDataHandler {
+	return &MarketDataHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Tickers returns 24h statistics for every listed symbol
+func (h *MarketDataHandler) Tickers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"tickers": h.exchangeService.Tickers(c.Request.Context()),
+	})
+}
+
+// Ticker returns 24h statistics for one symbol
+func (h *MarketDataHandler) Ticker(c *gin.Context) {
+	ticker, err := h.exchangeService.Ticker(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, ticker)
+}
+
+// ExchangeStatistics returns open interest per derivative and 24h volume across the venue
+func (h *MarketDataHandler) ExchangeStatistics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.ExchangeStatistics(c.Request.Context()))
+}
+
+// Klines returns candles for a symbol, from the path or the symbol query param; query
+// params: interval (default 1m), start_time and end_time (RFC 3339), limit (default 500).
+// With start_time the earliest bars from it are returned, otherwise the latest.
+func (h *MarketDataHandler) Klines(c *gin.Context) {
+	query := services.CandleQuery{Symbol: c.Param("symbol")}
+	if query.Symbol == "" {
+		query.Symbol = c.Query("symbol")
+	}
+	interval, err := marketdata.ParseInterval(c.DefaultQuery("interval", string(marketdata.Interval1m)))
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	query.Interval = interval
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultKlineLimit)))
+	if err != nil || limit <= 0 || limit > maxKlineLimit {
+		invalidRequest(c, fmt.Errorf("limit must be between 1 and %d", maxKlineLimit))
+		return
+	}
+	query.Limit = limit
+	for param, bound := range map[string]*time.Time{"start_time": &query.Start, "end_time": &query.End} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalidRequest(c, fmt.Errorf("%s must be an RFC 3339 time", param))
+			return
+		}
+		*bound = parsed
+	}
+
+	candles, err := h.exchangeService.CandleHistory(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":   query.Symbol,
+		"interval": interval,
+		"candles":  candles,
+	})
+}
@@ -0,0 +1,147 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newMarketDataRouter() (*gin.Engine, *services.ExchangeService) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+	marketDataHandler := handlers.NewMarketDataHandler(exchangeService, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/tickers", marketDataHandler.Tickers)
+	router.GET("/api/v1/tickers/:symbol", marketDataHandler.Ticker)
+	router.GET("/api/v1/klines", marketDataHandler.Klines)
+	router.GET("/api/v1/klines/:symbol", marketDataHandler.Klines)
+	return router, exchangeService
+}
+
+// fixedCandleArchive serves the same archived bars for every series
+type fixedCandleArchive struct {
+	candles []marketdata.Candle
+}
+
+func (a *fixedCandleArchive) Append(symbol string, interval marketdata.Interval, candles []marketdata.Candle) error {
+	return nil
+}
+
+func (a *fixedCandleArchive) Candles(symbol string, interval marketdata.Interval, from, to time.Time) ([]marketdata.Candle, error) {
+	matched := make([]marketdata.Candle, 0)
+	for _, candle := range a.candles {
+		if !candle.OpenTime.Before(from) && !candle.OpenTime.After(to) {
+			matched = append(matched, candle)
+		}
+	}
+	return matched, nil
+}
+
+func TestMarketDataHandler(t *testing.T) {
+	t.Run("ticker_reflects_trades", func(t *testing.T) {
+		// Given: One trade on BTC-USD
+		router, exchangeService := newMarketDataRouter()
+		ctx := context.Background()
+		order := services.OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		exchangeService.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "b", models.SideBuy
+		exchangeService.PlaceOrder(ctx, order)
+
+		// When: The ticker is requested
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickers/BTC-USD", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: It reports the trade
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var ticker marketdata.Ticker
+		json.Unmarshal(w.Body.Bytes(), &ticker)
+		if ticker.LastPrice != 60000 || ticker.Volume != 1 || ticker.TradeCount != 1 {
+			t.Errorf("Unexpected ticker: %+v", ticker)
+		}
+	})
+
+	t.Run("unknown_symbol_returns_404", func(t *testing.T) {
+		router, _ := newMarketDataRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickers/DOGE-USD", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+		var body struct {
+			Code services.RejectReason `json:"code"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body.Code != services.RejectUnknownInstrument {
+			t.Errorf("Expected code %s, got %q", services.RejectUnknownInstrument, body.Code)
+		}
+	})
+
+	t.Run("klines_merge_archived_history", func(t *testing.T) {
+		// Given: A bar archived two hours ago and a trade printing now
+		router, exchangeService := newMarketDataRouter()
+		ctx := context.Background()
+		archivedAt := time.Now().UTC().Truncate(time.Minute).Add(-2 * time.Hour)
+		exchangeService.SetCandleArchive(&fixedCandleArchive{candles: []marketdata.Candle{
+			{OpenTime: archivedAt, CloseTime: archivedAt.Add(time.Minute), Open: 59000, High: 59000, Low: 59000, Close: 59000, Volume: 2, TradeCount: 1},
+		}})
+		order := services.OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		exchangeService.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "b", models.SideBuy
+		exchangeService.PlaceOrder(ctx, order)
+
+		// When: Klines are requested from the archived bar onwards
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/klines?symbol=BTC-USD&start_time="+archivedAt.Format(time.RFC3339), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: The archived bar comes first, followed by the live one
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Candles []marketdata.Candle `json:"candles"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if len(body.Candles) != 2 || body.Candles[0].Close != 59000 || body.Candles[1].Close != 60000 {
+			t.Errorf("Unexpected candles: %+v", body.Candles)
+		}
+	})
+
+	t.Run("klines_validate_interval_and_limit", func(t *testing.T) {
+		router, _ := newMarketDataRouter()
+
+		for _, query := range []string{"?interval=2m", "?limit=0", "?limit=abc", "?start_time=yesterday", "?start_time=2024-01-02T00:00:00Z&end_time=2024-01-01T00:00:00Z"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/klines/BTC-USD"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// MarketMakerHandler reports the background liquidity the venue quotes
+type MarketMakerHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewMarketMakerHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *MarketMakerHandler {
+	return &MarketMakerHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List reports each quoted symbol's ladder, reference price and replenishment counts
+func (h *MarketMakerHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"symbols": h.exchangeService.MarketMaker(c.Request.Context())})
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+const (
+	defaultTradeLimit = 100
+	maxTradeLimit     = 1000
+	defaultBookDepth  = 20
+)
+
+// OrderHandler serves the REST trading API, mirroring the exchange gRPC service
+type OrderHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+// orderRequestBody is the JSON body accepted by POST /api/v1/orders. Fields are
+// validated by the venue, so a bad order gets the same reject codes as over gRPC.
+type orderRequestBody struct {
+	AccountID     string     `json:"account_id"`
+	ClientOrderID string     `json:"client_order_id"`
+	Symbol        string     `json:"symbol"`
+	Side          string     `json:"side"`
+	Type          string     `json:"type"`          // limit (default) or market
+	TimeInForce   string     `json:"time_in_force"` // GTC (default), IOC, FOK or GTD
+	Quantity      float64    `json:"quantity"`
+	Price         float64    `json:"price"`
+	ExpiresAt     *time.Time `json:"expires_at"` // Required for GTD
+	Async         bool       `json:"async"`      // Return a receipt at once; the ack follows on the gRPC order stream
+	DryRun        bool       `json:"dry_run"`    // Check the order and estimate its fills without placing it
+}
+
+// amendRequestBody is the JSON body accepted by PATCH /api/v1/orders/:order_id
+type amendRequestBody struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"` // New total quantity, including fills
+}
+
+func NewOrderHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *OrderHandler {
+	return &OrderHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Place submits an order and returns it with any trades it executed, or with async
+// set returns 202 and the receipt whose request sequence the ack will carry. With
+// dry_run set nothing is placed: 200 returns the order and trades placing it would
+// produce against the current book.
+func (h *OrderHandler) Place(c *gin.Context) {
+	var body orderRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	req, err := body.orderRequest()
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+
+	if body.DryRun {
+		if body.Async {
+			invalidRequest(c, errors.New("dry_run cannot be combined with async"))
+			return
+		}
+		result, err := h.exchangeService.DryRunOrder(c.Request.Context(), req)
+		if err != nil {
+			c.JSON(errorStatus(err), errorBody(err))
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	if body.Async {
+		receipt, err := h.exchangeService.SubmitOrder(c.Request.Context(), req)
+		if err != nil {
+			c.JSON(errorStatus(err), errorBody(err))
+			return
+		}
+		c.JSON(http.StatusAccepted, receipt)
+		return
+	}
+
+	report, err := h.exchangeService.PlaceOrder(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	status := http.StatusCreated
+	if report.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, report)
+}
+
+// Cancel cancels a working order
+func (h *OrderHandler) Cancel(c *gin.Context) {
+	order, err := h.exchangeService.CancelOrder(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// Amend changes the price and/or total quantity of a working limit order
+func (h *OrderHandler) Amend(c *gin.Context) {
+	var body amendRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	report, err := h.exchangeService.AmendOrder(c.Request.Context(), c.Param("order_id"), matching.AmendRequest{
+		Price:    body.Price,
+		Quantity: body.Quantity,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Get returns the current state of an order
+func (h *OrderHandler) Get(c *gin.Context) {
+	order, err := h.exchangeService.GetOrder(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// List returns working orders; query params: account_id, symbol
+func (h *OrderHandler) List(c *gin.Context) {
+	orders, err := h.exchangeService.OpenOrders(c.Request.Context(), c.Query("account_id"), c.Query("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+// Trades returns the latest executions oldest first; query params: account_id, symbol,
+// limit (default 100)
+func (h *OrderHandler) Trades(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTradeLimit)))
+	if err != nil || limit <= 0 || limit > maxTradeLimit {
+		invalidRequest(c, fmt.Errorf("limit must be between 1 and %d", maxTradeLimit))
+		return
+	}
+	trades, err := h.exchangeService.Trades(c.Request.Context(), c.Query("account_id"), c.Query("symbol"), limit)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"trades": trades})
+}
+
+// History pages through the trade tape oldest first; query params: symbol, account_id,
+// start_time and end_time (RFC 3339, end exclusive), sort (asc, or desc for newest
+// first), limit (default 100) and cursor, the next_cursor of the previous page
+func (h *OrderHandler) History(c *gin.Context) {
+	query := tradetape.Query{Symbol: c.Query("symbol"), AccountID: c.Query("account_id")}
+	var err error
+	if query.Limit, query.From, query.To, query.Descending, err = historyParams(c); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if query.After, err = services.ParseTradeCursor(c.Query("cursor")); err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+
+	page, err := h.exchangeService.TradeHistory(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// OrderHistory pages through orders, working and terminal, oldest first; query params:
+// account_id, symbol, status (comma-separated, any of them), start_time and end_time
+// (RFC 3339 creation time, end exclusive), sort, limit and cursor as for History
+func (h *OrderHandler) OrderHistory(c *gin.Context) {
+	query := orderhistory.Query{AccountID: c.Query("account_id"), Symbol: c.Query("symbol")}
+	var err error
+	if query.Limit, query.From, query.To, query.Descending, err = historyParams(c); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if statuses := c.Query("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			query.Statuses = append(query.Statuses, models.OrderStatus(strings.TrimSpace(status)))
+		}
+	}
+	if query.After, err = services.ParseOrderCursor(c.Query("cursor")); err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+
+	page, err := h.exchangeService.OrderHistory(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// historyParams reads the limit, time range and sort shared by the history endpoints
+func historyParams(c *gin.Context) (limit int, from, to time.Time, descending bool, err error) {
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTradeLimit)))
+	if err != nil || limit <= 0 || limit > maxTradeLimit {
+		return 0, from, to, false, fmt.Errorf("limit must be between 1 and %d", maxTradeLimit)
+	}
+	for param, bound := range map[string]*time.Time{"start_time": &from, "end_time": &to} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, from, to, false, fmt.Errorf("%s must be an RFC 3339 time", param)
+		}
+		*bound = parsed
+	}
+	switch c.DefaultQuery("sort", "asc") {
+	case "asc":
+	case "desc":
+		descending = true
+	default:
+		return 0, from, to, false, errors.New("sort must be asc or desc")
+	}
+	return limit, from, to, descending, nil
+}
+
+// Book returns aggregated price levels; query param: depth (default 20, 0 = all)
+func (h *OrderHandler) Book(c *gin.Context) {
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(defaultBookDepth)))
+	if err != nil {
+		invalidRequest(c, errors.New("depth must be a number"))
+		return
+	}
+	snapshot, err := h.exchangeService.OrderBook(c.Request.Context(), c.Param("symbol"), depth)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// Balances returns an account's net position per asset; query param: account_id
+func (h *OrderHandler) Balances(c *gin.Context) {
+	ledger, err := h.exchangeService.AccountLedger(c.Request.Context(), c.Query("account_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": ledger.AccountID,
+		"balances":   ledger.Positions,
+	})
+}
+
+func (b orderRequestBody) orderRequest() (services.OrderRequest, error) {
+	side, err := models.ParseSide(b.Side)
+	if err != nil {
+		return services.OrderRequest{}, services.NewRejection(services.RejectInvalidSide, err)
+	}
+	orderType, err := models.ParseOrderType(b.Type)
+	if err != nil {
+		return services.OrderRequest{}, services.NewRejection(services.RejectInvalidOrderType, err)
+	}
+	tif, err := models.ParseTimeInForce(b.TimeInForce)
+	if err != nil {
+		return services.OrderRequest{}, services.NewRejection(services.RejectInvalidRequest, err)
+	}
+
+	req := services.OrderRequest{
+		AccountID:     b.AccountID,
+		ClientOrderID: b.ClientOrderID,
+		Symbol:        b.Symbol,
+		Side:          side,
+		Type:          orderType,
+		TimeInForce:   tif,
+		Quantity:      b.Quantity,
+		Price:         b.Price,
+	}
+	if b.ExpiresAt != nil {
+		req.ExpiresAt = *b.ExpiresAt
+	}
+	return req, nil
+}
@@ -0,0 +1,222 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newOrderRouter() *gin.Engine {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+	orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/orders", orderHandler.Place)
+	router.GET("/api/v1/orders", orderHandler.List)
+	router.GET("/api/v1/orders/history", orderHandler.OrderHistory)
+	router.GET("/api/v1/orders/:order_id", orderHandler.Get)
+	router.PATCH("/api/v1/orders/:order_id", orderHandler.Amend)
+	router.DELETE("/api/v1/orders/:order_id", orderHandler.Cancel)
+	router.GET("/api/v1/trades", orderHandler.Trades)
+	router.GET("/api/v1/book/:symbol", orderHandler.Book)
+	router.GET("/api/v1/balances", orderHandler.Balances)
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrderHandler(t *testing.T) {
+	t.Run("trades_over_rest", func(t *testing.T) {
+		// Given: A resting ask placed over REST
+		router := newOrderRouter()
+		w := serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"maker","symbol":"BTC-USD","side":"sell","quantity":1,"price":60000}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var ask matching.ExecutionReport
+		json.Unmarshal(w.Body.Bytes(), &ask)
+
+		// When: Another account buys half of it and the rest is amended then canceled
+		w = serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"taker","symbol":"BTC-USD","side":"buy","time_in_force":"ioc","quantity":0.5,"price":60000}`)
+		var bid matching.ExecutionReport
+		json.Unmarshal(w.Body.Bytes(), &bid)
+		amended := serve(router, http.MethodPatch, "/api/v1/orders/"+ask.Order.ID, `{"price":60100}`)
+
+		// Then: The trade, book, open orders and balances all reflect it
+		if len(bid.Trades) != 1 || bid.Order.Status != models.OrderStatusFilled {
+			t.Fatalf("Expected the bid to fill, got %+v", bid)
+		}
+		if amended.Code != http.StatusOK {
+			t.Errorf("Expected amend to succeed, got %d: %s", amended.Code, amended.Body.String())
+		}
+		var book matching.BookSnapshot
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/book/BTC-USD?depth=1", "").Body.Bytes(), &book)
+		if len(book.Asks) != 1 || book.Asks[0].Price != 60100 || book.Asks[0].Quantity != 0.5 {
+			t.Errorf("Expected 0.5 offered at 60100, got %+v", book.Asks)
+		}
+		var open struct {
+			Orders []models.Order `json:"orders"`
+		}
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/orders?account_id=maker", "").Body.Bytes(), &open)
+		if len(open.Orders) != 1 || open.Orders[0].ID != ask.Order.ID {
+			t.Errorf("Expected maker's ask to be open, got %+v", open.Orders)
+		}
+		var trades struct {
+			Trades []models.Trade `json:"trades"`
+		}
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/trades?symbol=BTC-USD&limit=10", "").Body.Bytes(), &trades)
+		if len(trades.Trades) != 1 || trades.Trades[0].ID != bid.Trades[0].ID {
+			t.Errorf("Expected the trade in history, got %+v", trades.Trades)
+		}
+		var balances struct {
+			AccountID string            `json:"account_id"`
+			Balances  []ledger.Position `json:"balances"`
+		}
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/balances?account_id=taker", "").Body.Bytes(), &balances)
+		if len(balances.Balances) != 2 || balances.Balances[0].Asset != "BTC" || balances.Balances[0].Quantity != 0.5 {
+			t.Errorf("Expected +0.5 BTC for taker, got %+v", balances)
+		}
+
+		// And: The cancel returns the canceled order, and a second cancel conflicts
+		w = serve(router, http.MethodDelete, "/api/v1/orders/"+ask.Order.ID, "")
+		var canceled models.Order
+		json.Unmarshal(w.Body.Bytes(), &canceled)
+		if w.Code != http.StatusOK || canceled.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected the ask to be canceled, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := serve(router, http.MethodDelete, "/api/v1/orders/"+ask.Order.ID, ""); w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for a canceled order, got %d", w.Code)
+		}
+	})
+
+	t.Run("async_placement_returns_a_receipt", func(t *testing.T) {
+		router := newOrderRouter()
+
+		w := serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"a","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000,"async":true}`)
+
+		var receipt services.AsyncReceipt
+		json.Unmarshal(w.Body.Bytes(), &receipt)
+		if w.Code != http.StatusAccepted || receipt.RequestSequence != 1 {
+			t.Errorf("Expected 202 with request sequence 1, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("dry_run_estimates_without_placing", func(t *testing.T) {
+		// Given: A resting ask
+		router := newOrderRouter()
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"m","symbol":"BTC-USD","side":"sell","quantity":1,"price":60000}`)
+
+		// When: A crossing bid is dry-run
+		w := serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"t","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000,"dry_run":true}`)
+
+		// Then: The estimated fill is returned and the ask still rests
+		var result services.DryRunResult
+		json.Unmarshal(w.Body.Bytes(), &result)
+		if w.Code != http.StatusOK || !result.DryRun || len(result.Trades) != 1 || result.Order.ID != "" {
+			t.Errorf("Expected 200 with one estimated fill, got %d: %s", w.Code, w.Body.String())
+		}
+		var open struct {
+			Orders []models.Order `json:"orders"`
+		}
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/orders?symbol=BTC-USD", "").Body.Bytes(), &open)
+		if len(open.Orders) != 1 || open.Orders[0].FilledQuantity != 0 {
+			t.Errorf("Expected the ask untouched, got %+v", open.Orders)
+		}
+		if w := serve(router, http.MethodPost, "/api/v1/orders",
+			`{"account_id":"t","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000,"dry_run":true,"async":true}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected dry_run with async to be refused, got %d", w.Code)
+		}
+	})
+
+	t.Run("pages_through_order_history_by_status", func(t *testing.T) {
+		// Given: Three orders of one account, the first canceled
+		router := newOrderRouter()
+		for _, price := range []string{"59000", "59001", "59002"} {
+			serve(router, http.MethodPost, "/api/v1/orders",
+				`{"account_id":"hist","symbol":"BTC-USD","side":"buy","quantity":1,"price":`+price+`}`)
+		}
+		var first struct {
+			Orders []models.Order `json:"orders"`
+		}
+		json.Unmarshal(serve(router, http.MethodGet, "/api/v1/orders?account_id=hist", "").Body.Bytes(), &first)
+		serve(router, http.MethodDelete, "/api/v1/orders/"+first.Orders[0].ID, "")
+
+		// When: Its working orders are read newest first, one per page
+		type historyPage struct {
+			Orders     []models.Order `json:"orders"`
+			NextCursor string         `json:"next_cursor"`
+		}
+		path := "/api/v1/orders/history?account_id=hist&status=new,partially_filled&sort=desc&limit=1"
+		var newest, next historyPage
+		json.Unmarshal(serve(router, http.MethodGet, path, "").Body.Bytes(), &newest)
+		w := serve(router, http.MethodGet, path+"&cursor="+newest.NextCursor, "")
+		json.Unmarshal(w.Body.Bytes(), &next)
+
+		// Then: The two working orders come back newest first and the canceled one is left out
+		if len(newest.Orders) != 1 || newest.Orders[0].Price != 59002 {
+			t.Errorf("Expected 59002 first, got %+v", newest)
+		}
+		if w.Code != http.StatusOK || len(next.Orders) != 1 || next.Orders[0].Price != 59001 || next.NextCursor != "" {
+			t.Errorf("Expected 59001 alone on the last page, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("errors_share_one_envelope", func(t *testing.T) {
+		router := newOrderRouter()
+		cases := []struct {
+			method, path, body string
+			status             int
+			code               services.RejectReason
+		}{
+			{http.MethodPost, "/api/v1/orders", `{"account_id":`, http.StatusBadRequest, services.RejectInvalidRequest},
+			{http.MethodPost, "/api/v1/orders", `{"account_id":"a","symbol":"BTC-USD","side":"up","quantity":1,"price":1}`, http.StatusBadRequest, services.RejectInvalidSide},
+			{http.MethodPost, "/api/v1/orders", `{"symbol":"BTC-USD","side":"buy","quantity":1,"price":60000}`, http.StatusBadRequest, services.RejectInvalidAccount},
+			{http.MethodGet, "/api/v1/orders/ord-BTC-USD-7", "", http.StatusNotFound, services.RejectOrderNotFound},
+			{http.MethodGet, "/api/v1/book/DOGE-USD", "", http.StatusNotFound, services.RejectUnknownInstrument},
+			{http.MethodGet, "/api/v1/trades?limit=0", "", http.StatusBadRequest, services.RejectInvalidRequest},
+			{http.MethodGet, "/api/v1/orders/history?sort=up", "", http.StatusBadRequest, services.RejectInvalidRequest},
+			{http.MethodGet, "/api/v1/orders/history?status=open", "", http.StatusBadRequest, services.RejectInvalidRequest},
+			{http.MethodGet, "/api/v1/orders/history?cursor=bm9wZQ", "", http.StatusBadRequest, services.RejectInvalidRequest},
+			{http.MethodGet, "/api/v1/balances", "", http.StatusBadRequest, services.RejectInvalidAccount},
+		}
+		for _, tc := range cases {
+			w := serve(router, tc.method, tc.path, tc.body)
+			var body struct {
+				Error string                `json:"error"`
+				Code  services.RejectReason `json:"code"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tc.status || body.Code != tc.code || body.Error == "" {
+				t.Errorf("%s %s: expected %d %s, got %d %s", tc.method, tc.path, tc.status, tc.code, w.Code, w.Body.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// OutboxHandler serves the state of the event outbox and dispatches it on demand
+type OutboxHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewOutboxHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *OutboxHandler {
+	return &OutboxHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Stats returns how many messages are queued, written to the outbox, dispatched and
+// failed, with the last dispatch
+func (h *OutboxHandler) Stats(c *gin.Context) {
+	stats, err := h.exchangeService.OutboxStats(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// Dispatch writes queued events and publishes one batch of due messages now rather
+// than on the next tick
+func (h *OutboxHandler) Dispatch(c *gin.Context) {
+	if err := h.exchangeService.FlushOutbox(); err != nil {
+		h.logger.WithError(err).Warn("Failed to write outbox messages")
+	}
+	run, err := h.exchangeService.DispatchOutbox(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
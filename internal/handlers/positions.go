@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// PositionHandler serves net positions and PnL per account and symbol
+type PositionHandler struct {
+	positionService *services.PositionService
+	logger          *logrus.Logger
+}
+
+func NewPositionHandler(positionService *services.PositionService, logger *logrus.Logger) *PositionHandler {
+	return &PositionHandler{
+		positionService: positionService,
+		logger:          logger,
+	}
+}
+
+// List returns positions, optionally filtered by ?account_id= and ?symbol=
+func (h *PositionHandler) List(c *gin.Context) {
+	positions, err := h.positionService.Positions(c.Request.Context(), c.Query("account_id"), c.Query("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"positions": positions,
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// PreviewHandler serves the what-if endpoint for fees, margin and funding
+type PreviewHandler struct {
+	previewService *services.PreviewService
+	logger         *logrus.Logger
+}
+
+// previewRequestBody is the JSON body accepted by POST /api/v1/preview
+type previewRequestBody struct {
+	Symbol        string  `json:"symbol" binding:"required"`
+	Side          string  `json:"side" binding:"required"`
+	OrderType     string  `json:"order_type"`
+	Quantity      float64 `json:"quantity" binding:"required"`
+	Price         float64 `json:"price"`
+	Leverage      float64 `json:"leverage"`
+	Liquidity     string  `json:"liquidity"`
+	HoldingPeriod string  `json:"holding_period"` // Go duration, e.g. "24h"
+}
+
+func NewPreviewHandler(previewService *services.PreviewService, logger *logrus.Logger) *PreviewHandler {
+	return &PreviewHandler{
+		previewService: previewService,
+		logger:         logger,
+	}
+}
+
+// Preview evaluates a hypothetical order or position without executing it
+func (h *PreviewHandler) Preview(c *gin.Context) {
+	var body previewRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	side, err := models.ParseSide(body.Side)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	orderType, err := models.ParseOrderType(body.OrderType)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	var holdingPeriod time.Duration
+	if body.HoldingPeriod != "" {
+		holdingPeriod, err = time.ParseDuration(body.HoldingPeriod)
+		if err != nil {
+			invalidRequest(c, fmt.Errorf("invalid holding_period: %w", err))
+			return
+		}
+	}
+
+	liquidity := models.LiquidityRole(body.Liquidity)
+	if liquidity != "" && liquidity != models.LiquidityMaker && liquidity != models.LiquidityTaker {
+		invalidRequest(c, errors.New("liquidity must be maker or taker"))
+		return
+	}
+
+	result, err := h.previewService.Preview(services.PreviewRequest{
+		Symbol:        body.Symbol,
+		Side:          side,
+		OrderType:     orderType,
+		Quantity:      body.Quantity,
+		Price:         body.Price,
+		Leverage:      body.Leverage,
+		Liquidity:     liquidity,
+		HoldingPeriod: holdingPeriod,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
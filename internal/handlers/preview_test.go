@@ -0,0 +1,88 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newPreviewRouter() *gin.Engine {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	registry := services.NewInstrumentRegistry(services.DefaultInstruments()...)
+	previewHandler := handlers.NewPreviewHandler(services.NewPreviewService(registry, logger), logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/preview", previewHandler.Preview)
+	return router
+}
+
+func TestPreviewHandler_Preview(t *testing.T) {
+	t.Run("returns_fees_margin_and_funding", func(t *testing.T) {
+		// Given: A router with the preview endpoint
+		router := newPreviewRouter()
+
+		// When: A perpetual position is previewed with a holding period
+		body := `{"symbol":"BTC-USD-PERP","side":"long","quantity":1,"price":60000,"leverage":5,"holding_period":"16h"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: The response should be 200 with the computed preview
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var result services.PreviewResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if result.InitialMargin != 12000 {
+			t.Errorf("Expected initial margin 12000, got %v", result.InitialMargin)
+		}
+		if result.FundingIntervals != 2 {
+			t.Errorf("Expected 2 funding intervals, got %d", result.FundingIntervals)
+		}
+	})
+
+	t.Run("returns_404_for_unknown_symbol", func(t *testing.T) {
+		router := newPreviewRouter()
+
+		body := `{"symbol":"NOPE-USD","side":"buy","quantity":1,"price":1}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns_400_for_invalid_holding_period", func(t *testing.T) {
+		router := newPreviewRouter()
+
+		body := `{"symbol":"BTC-USD","side":"buy","quantity":1,"price":60000,"holding_period":"forever"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
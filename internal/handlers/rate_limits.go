@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// Headers reporting the caller's tightest rate limit bucket on every limited response
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset" // Seconds until the bucket is full again
+	RateLimitScopeHeader     = "X-RateLimit-Scope" // "key" or "ip"
+)
+
+// RateLimitHandler charges requests against their caller's rate limits and manages
+// the limits
+type RateLimitHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewRateLimitHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *RateLimitHandler {
+	return &RateLimitHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Limit charges each request its route's weight, answering 429 and RATE_LIMITED with
+// Retry-After once the caller's key or address has spent its limit. Health, readiness
+// and metrics are not charged.
+func (h *RateLimitHandler) Limit(c *gin.Context) {
+	switch c.Request.URL.Path {
+	case "/api/v1/health", "/api/v1/ready", "/metrics":
+		c.Next()
+		return
+	}
+
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	decision, err := h.exchangeService.ChargeRequest(c.Request.Context(), c.ClientIP(), c.Request.Method+" "+route)
+	if decision.Scope != "" {
+		c.Header(RateLimitLimitHeader, strconv.Itoa(decision.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(decision.Reset)))
+		c.Header(RateLimitScopeHeader, decision.Scope)
+	}
+	if err != nil {
+		retryAfter := ceilSeconds(decision.RetryAfter)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.Next()
+}
+
+// Get reports the limits in force
+func (h *RateLimitHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.RateLimits())
+}
+
+// Set replaces the limits, {"key_weight": 1200, "ip_weight": 6000, "window": "1m",
+// "weights": {"GET /api/v1/trades/:symbol": 5}}
+func (h *RateLimitHandler) Set(c *gin.Context) {
+	var limits ratelimit.Limits
+	if err := c.ShouldBindJSON(&limits); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	updated, err := h.exchangeService.SetRateLimits(c.Request.Context(), limits)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
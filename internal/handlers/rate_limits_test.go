@@ -0,0 +1,88 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+	t.Run("refuses_requests_beyond_the_key_weight_with_retry_after", func(t *testing.T) {
+		// Given: Keys may spend 5 per minute, the book weighs 2 and health is free
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator", RateLimitWeight: 5}, logger)
+		rateLimitHandler := handlers.NewRateLimitHandler(exchangeService, logger)
+		orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(exchangeService.KeyStatistics()), rateLimitHandler.Limit)
+		router.GET("/api/v1/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/api/v1/book/:symbol", orderHandler.Book)
+		router.PUT("/api/v1/admin/rate-limits", rateLimitHandler.Set)
+		limits := `{"key_weight": 5, "window": "1m", "weights": {"GET /api/v1/book/:symbol": 2}}`
+		if w := serve(router, http.MethodPut, "/api/v1/admin/rate-limits", limits); w.Code != http.StatusOK {
+			t.Fatalf("Expected the limits to be set, got %d: %s", w.Code, w.Body.String())
+		}
+		book := func(apiKey string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/book/BTC-USD", nil)
+			req.Header.Set(observability.APIKeyHeader, apiKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// When: One key reads the book three times
+		first := book("bot-1")
+		book("bot-1")
+		refused := book("bot-1")
+
+		// Then: The first reports what is left, and the third is refused until it refills
+		if first.Code != http.StatusOK || first.Header().Get(handlers.RateLimitLimitHeader) != "5" || first.Header().Get(handlers.RateLimitRemainingHeader) != "3" {
+			t.Errorf("Expected 3 of 5 left, got %d with %v", first.Code, first.Header())
+		}
+		var body map[string]interface{}
+		json.Unmarshal(refused.Body.Bytes(), &body)
+		if refused.Code != http.StatusTooManyRequests || body["code"] != "RATE_LIMITED" || refused.Header().Get("Retry-After") != "12" {
+			t.Errorf("Expected 429 RATE_LIMITED retrying after 12s, got %d %s with %v", refused.Code, refused.Body.String(), refused.Header())
+		}
+		if refused.Header().Get(handlers.RateLimitScopeHeader) != "key" {
+			t.Errorf("Expected the key bucket reported, got %q", refused.Header().Get(handlers.RateLimitScopeHeader))
+		}
+
+		// And: Other keys and health checks are unaffected
+		if other := book("bot-2"); other.Code != http.StatusOK {
+			t.Errorf("Expected another key to read the book, got %d", other.Code)
+		}
+		if health := serve(router, http.MethodGet, "/api/v1/health", ""); health.Code != http.StatusOK || health.Header().Get(handlers.RateLimitLimitHeader) != "" {
+			t.Errorf("Expected health checks to go uncharged, got %d with %v", health.Code, health.Header())
+		}
+	})
+
+	t.Run("rejects_invalid_limits", func(t *testing.T) {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		rateLimitHandler := handlers.NewRateLimitHandler(services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger), logger)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.PUT("/api/v1/admin/rate-limits", rateLimitHandler.Set)
+
+		negative := serve(router, http.MethodPut, "/api/v1/admin/rate-limits", `{"key_weight": -1}`)
+		badWindow := serve(router, http.MethodPut, "/api/v1/admin/rate-limits", `{"key_weight": 10, "window": "soon"}`)
+
+		if negative.Code != http.StatusBadRequest || badWindow.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for both, got %d and %d", negative.Code, badWindow.Code)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ReferencePriceRequest sets a symbol's external price by hand, for orchestrators
+// without a price feed
+type ReferencePriceRequest struct {
+	Price  float64   `json:"price" binding:"required"`
+	Source string    `json:"source"`
+	Time   time.Time `json:"time"` // Optional; a later price already received wins
+}
+
+// ReferencePriceHandler reports and sets the external prices anchoring each mark
+type ReferencePriceHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewReferencePriceHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *ReferencePriceHandler {
+	return &ReferencePriceHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns the latest external price per symbol and whether it is still fresh
+func (h *ReferencePriceHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"prices": h.exchangeService.ReferencePrices(c.Request.Context())})
+}
+
+// Update records an external price for the symbol in the path
+func (h *ReferencePriceHandler) Update(c *gin.Context) {
+	var body ReferencePriceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	source := body.Source
+	if source == "" {
+		source = "admin"
+	}
+
+	price, err := h.exchangeService.UpdateReferencePrice(c.Request.Context(), pricefeed.Price{
+		Symbol: c.Param("symbol"),
+		Price:  body.Price,
+		Source: source,
+		Time:   body.Time,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, price)
+}
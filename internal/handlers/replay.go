@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/orderflow"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ReplayHandler resubmits captured order flow to the venue
+type ReplayHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewReplayHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *ReplayHandler {
+	return &ReplayHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Start replays the recording in the body: an event journal, a run bundle or
+// length-delimited OrderFlowEvents. ?speed= multiplies the recorded pace (default 1,
+// 0 = as fast as possible) and ?source= names the recording in its progress.
+func (h *ReplayHandler) Start(c *gin.Context) {
+	speed := 1.0
+	if raw := c.Query("speed"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			invalidRequest(c, fmt.Errorf("invalid speed %q", raw))
+			return
+		}
+		speed = parsed
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	recording, err := orderflow.Parse(data)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	progress, err := h.exchangeService.StartReplay(c.Request.Context(), recording, c.DefaultQuery("source", "api"), speed)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, progress)
+}
+
+// Progress reports the replay running, or the last one
+func (h *ReplayHandler) Progress(c *gin.Context) {
+	progress, err := h.exchangeService.ReplayProgress(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// Stop ends the replay before its remaining entries
+func (h *ReplayHandler) Stop(c *gin.Context) {
+	progress, err := h.exchangeService.StopReplay(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
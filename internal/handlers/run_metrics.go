@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// RunMetricsHandler exposes business metrics snapshots per scenario run, and the
+// manifest and reproducibility bundle of the current one
+type RunMetricsHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewRunMetricsHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *RunMetricsHandler {
+	return &RunMetricsHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Current returns this run's metrics so far, whether or not they are persisted
+func (h *RunMetricsHandler) Current(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.CurrentMetrics(c.Request.Context()))
+}
+
+// Runs lists every run with persisted snapshots, most recent first
+func (h *RunMetricsHandler) Runs(c *gin.Context) {
+	runs, err := h.exchangeService.MetricsRuns(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"current_run_id": h.exchangeService.RunID(),
+		"runs":           runs,
+	})
+}
+
+// Snapshots returns a run's snapshots oldest first; query params: from, to (RFC 3339),
+// limit
+func (h *RunMetricsHandler) Snapshots(c *gin.Context) {
+	query := runmetrics.Query{RunID: c.Param("run_id")}
+	for param, bound := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalidRequest(c, fmt.Errorf("%s must be an RFC 3339 time", param))
+			return
+		}
+		*bound = parsed
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			invalidRequest(c, fmt.Errorf("limit must be a number"))
+			return
+		}
+		query.Limit = limit
+	}
+
+	snapshots, err := h.exchangeService.MetricsSnapshots(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"run_id":    query.RunID,
+		"snapshots": snapshots,
+	})
+}
+
+// Manifest returns what the current run was started with
+func (h *RunMetricsHandler) Manifest(c *gin.Context) {
+	manifest, err := h.exchangeService.RunManifest(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, manifest)
+}
+
+// Bundle downloads the current run's manifest, journal and metrics so far as a
+// gzipped tar
+func (h *RunMetricsHandler) Bundle(c *gin.Context) {
+	bundle, err := h.exchangeService.RunBundle(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	var archive bytes.Buffer
+	if err := runbundle.Write(&archive, bundle); err != nil {
+		h.logger.WithError(err).Error("Failed to archive run bundle")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", bundle.Manifest.RunID+".tar.gz"))
+	c.Data(http.StatusOK, "application/gzip", archive.Bytes())
+}
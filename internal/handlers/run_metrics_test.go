@@ -0,0 +1,147 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// memoryMetricsStore keeps snapshots in memory in save order
+type memoryMetricsStore struct {
+	snapshots []runmetrics.Snapshot
+}
+
+func (s *memoryMetricsStore) Save(snapshot runmetrics.Snapshot) error {
+	s.snapshots = append(s.snapshots, snapshot)
+	return nil
+}
+
+func (s *memoryMetricsStore) Snapshots(query runmetrics.Query) ([]runmetrics.Snapshot, error) {
+	var matched []runmetrics.Snapshot
+	for _, snapshot := range s.snapshots {
+		if snapshot.RunID != query.RunID || (!query.From.IsZero() && snapshot.TakenAt.Before(query.From)) {
+			continue
+		}
+		if len(matched) < query.Limit {
+			matched = append(matched, snapshot)
+		}
+	}
+	return matched, nil
+}
+
+func (s *memoryMetricsStore) Runs() ([]runmetrics.Run, error) {
+	return []runmetrics.Run{{RunID: s.snapshots[0].RunID, Snapshots: len(s.snapshots)}}, nil
+}
+
+func newRunMetricsRouter(store runmetrics.Store) (*gin.Engine, *services.ExchangeService) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.Config{ServiceName: "exchange-simulator", ServiceInstanceName: "exchange-OKX", ScenarioRunID: "run-1"}
+	exchangeService := services.NewExchangeService(cfg, logger)
+	if store != nil {
+		exchangeService.SetMetricsStore(store)
+	}
+	runMetricsHandler := handlers.NewRunMetricsHandler(exchangeService, logger)
+	orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/orders", orderHandler.Place)
+	router.GET("/api/v1/admin/metrics/current", runMetricsHandler.Current)
+	router.GET("/api/v1/admin/metrics/runs", runMetricsHandler.Runs)
+	router.GET("/api/v1/admin/metrics/runs/:run_id/snapshots", runMetricsHandler.Snapshots)
+	return router, exchangeService
+}
+
+func TestRunMetricsHandler(t *testing.T) {
+	t.Run("saves_and_queries_snapshots_by_run", func(t *testing.T) {
+		// Given: A venue persisting metrics that crossed one trade and rejected one order
+		store := &memoryMetricsStore{}
+		router, exchangeService := newRunMetricsRouter(store)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"maker","symbol":"BTC-USD","side":"sell","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"taker","symbol":"BTC-USD","side":"buy","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"taker","symbol":"NOPE","side":"buy","quantity":1,"price":1}`)
+
+		// When: A snapshot is saved and the run's snapshots are queried
+		if err := exchangeService.SaveMetricsSnapshot(); err != nil {
+			t.Fatalf("Expected the snapshot to save, got %v", err)
+		}
+		w := serve(router, http.MethodGet, "/api/v1/admin/metrics/runs/run-1/snapshots?from=2000-01-01T00:00:00Z", "")
+
+		// Then: The snapshot carries the run, instance, volume, latency and rejection rate
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Snapshots []runmetrics.Snapshot `json:"snapshots"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Snapshots) != 1 {
+			t.Fatalf("Expected one snapshot, got %s", w.Body.String())
+		}
+		snapshot := body.Snapshots[0]
+		if snapshot.RunID != "run-1" || snapshot.Instance != "exchange-OKX" {
+			t.Errorf("Expected the snapshot keyed by run-1 on exchange-OKX, got %+v", snapshot)
+		}
+		if snapshot.Orders != 2 || snapshot.Rejections != 1 || snapshot.Volumes["BTC-USD"].Notional != 60000 {
+			t.Errorf("Unexpected counts or volume: %+v", snapshot)
+		}
+		if snapshot.OrderLatency.Count != 2 || snapshot.RejectionRate <= 0 {
+			t.Errorf("Expected two timed placements and a rejection rate, got %+v", snapshot)
+		}
+
+		// And: The run is listed alongside the current run ID
+		w = serve(router, http.MethodGet, "/api/v1/admin/metrics/runs", "")
+		var runs struct {
+			CurrentRunID string           `json:"current_run_id"`
+			Runs         []runmetrics.Run `json:"runs"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &runs); err != nil || runs.CurrentRunID != "run-1" || len(runs.Runs) != 1 {
+			t.Errorf("Expected run-1 listed, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("reports_current_metrics_without_a_store", func(t *testing.T) {
+		// Given: A venue that does not persist metrics
+		router, _ := newRunMetricsRouter(nil)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"maker","symbol":"BTC-USD","side":"sell","quantity":1,"price":60000}`)
+
+		// When: Current metrics and persisted snapshots are requested
+		current := serve(router, http.MethodGet, "/api/v1/admin/metrics/current", "")
+		persisted := serve(router, http.MethodGet, "/api/v1/admin/metrics/runs", "")
+
+		// Then: Current metrics are served and the history is not found
+		var snapshot runmetrics.Snapshot
+		if err := json.Unmarshal(current.Body.Bytes(), &snapshot); err != nil || snapshot.Orders != 1 || snapshot.RunID != "run-1" {
+			t.Errorf("Expected one order so far in run-1, got %s", current.Body.String())
+		}
+		if persisted.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", persisted.Code, persisted.Body.String())
+		}
+	})
+
+	t.Run("rejects_invalid_bounds", func(t *testing.T) {
+		router, _ := newRunMetricsRouter(&memoryMetricsStore{})
+		for _, query := range []string{
+			"?from=yesterday",
+			"?limit=ten",
+			"?limit=-1",
+			"?from=" + time.Now().Format(time.RFC3339) + "&to=2000-01-01T00:00:00Z",
+		} {
+			if w := serve(router, http.MethodGet, "/api/v1/admin/metrics/runs/run-1/snapshots"+query, ""); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ScenarioHandler reports the loaded scenario's assertions while it runs and plays the
+// scripts an orchestrator provides
+type ScenarioHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewScenarioHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *ScenarioHandler {
+	return &ScenarioHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Verdict evaluates the assertions against the events so far; the final verdict is
+// taken at shutdown
+func (h *ScenarioHandler) Verdict(c *gin.Context) {
+	verdict, err := h.exchangeService.ScenarioVerdict(c.Request.Context(), false)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, verdict)
+}
+
+// StartScript plays the YAML or JSON script in the body from now
+func (h *ScenarioHandler) StartScript(c *gin.Context) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	script, err := scenario.ParseScript(data)
+	if err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	progress, err := h.exchangeService.StartScript(c.Request.Context(), script)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, progress)
+}
+
+// Script reports the progress of the script being played, or the last one
+func (h *ScenarioHandler) Script(c *gin.Context) {
+	progress, err := h.exchangeService.ScriptProgress(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// StopScript ends the script before its remaining steps
+func (h *ScenarioHandler) StopScript(c *gin.Context) {
+	progress, err := h.exchangeService.StopScript(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
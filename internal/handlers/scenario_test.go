@@ -0,0 +1,107 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newScenarioRouter() (*gin.Engine, *services.ExchangeService) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator", ScenarioRunID: "run-1"}, logger)
+	scenarioHandler := handlers.NewScenarioHandler(exchangeService, logger)
+	orderHandler := handlers.NewOrderHandler(exchangeService, logger)
+	haltHandler := handlers.NewHaltHandler(exchangeService, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/orders", orderHandler.Place)
+	router.POST("/api/v1/admin/halts/:symbol", haltHandler.Halt)
+	router.GET("/api/v1/admin/scenario/verdict", scenarioHandler.Verdict)
+	router.GET("/api/v1/admin/scenario/script", scenarioHandler.Script)
+	router.POST("/api/v1/admin/scenario/script", scenarioHandler.StartScript)
+	router.DELETE("/api/v1/admin/scenario/script", scenarioHandler.StopScript)
+	return router, exchangeService
+}
+
+func TestScenarioHandler(t *testing.T) {
+	t.Run("judges_assertions_against_venue_events", func(t *testing.T) {
+		// Given: A scenario expecting a fill for the maker and no halts
+		router, exchangeService := newScenarioRouter()
+		loaded, err := scenario.Parse([]byte(`{"name": "maker-fill", "assertions": [
+			{"name": "maker filled", "kind": "count", "event": {"type": "order", "account": "maker", "status": "filled"}, "min": 1},
+			{"name": "no halts", "kind": "count", "event": {"type": "health", "component": "instrument:", "status": "halted"}, "max": 0}
+		]}`))
+		if err != nil {
+			t.Fatalf("Expected the scenario to parse, got %v", err)
+		}
+		exchangeService.SetScenario(loaded)
+
+		// When: The maker's ask is lifted, then ETH-USD is halted
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"maker","symbol":"BTC-USD","side":"sell","type":"limit","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/orders", `{"account_id":"taker","symbol":"BTC-USD","side":"buy","type":"limit","quantity":1,"price":60000}`)
+		serve(router, http.MethodPost, "/api/v1/admin/halts/ETH-USD", "")
+		w := serve(router, http.MethodGet, "/api/v1/admin/scenario/verdict", "")
+
+		// Then: The fill passes, the halt fails the run, and the verdict is interim
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var verdict scenario.Verdict
+		json.Unmarshal(w.Body.Bytes(), &verdict)
+		if verdict.Passed || verdict.Final || verdict.RunID != "run-1" || len(verdict.Results) != 2 {
+			t.Fatalf("Unexpected verdict: %+v", verdict)
+		}
+		if !verdict.Results[0].Passed || verdict.Results[1].Passed || verdict.Results[1].Observed != 1 {
+			t.Errorf("Expected the fill to pass and the halt to fail, got %+v", verdict.Results)
+		}
+	})
+
+	t.Run("no_scenario_returns_404", func(t *testing.T) {
+		router, _ := newScenarioRouter()
+
+		w := serve(router, http.MethodGet, "/api/v1/admin/scenario/verdict", "")
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("plays_and_stops_a_posted_script", func(t *testing.T) {
+		// Given: No script yet
+		router, exchangeService := newScenarioRouter()
+		none := serve(router, http.MethodGet, "/api/v1/admin/scenario/script", "")
+
+		// When: A YAML script halting ETH-USD now and resuming it later is posted, then stopped
+		started := serve(router, http.MethodPost, "/api/v1/admin/scenario/script", "name: drill\nsteps:\n  - {action: halt, symbol: ETH-USD}\n  - {at: 10m, action: resume, symbol: ETH-USD}\n")
+		running := serve(router, http.MethodGet, "/api/v1/admin/scenario/script", "")
+		stopped := serve(router, http.MethodDelete, "/api/v1/admin/scenario/script", "")
+		invalid := serve(router, http.MethodPost, "/api/v1/admin/scenario/script", `{"steps": [{"action": "rewind"}]}`)
+
+		// Then: The halt ran at once and the resume never did
+		if none.Code != http.StatusNotFound || started.Code != http.StatusCreated || invalid.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 404, 201 and 400, got %d, %d and %d: %s", none.Code, started.Code, invalid.Code, started.Body.String())
+		}
+		var progress scenario.Progress
+		json.Unmarshal(running.Body.Bytes(), &progress)
+		if progress.Script != "drill" || progress.State != scenario.ScriptRunning || progress.Executed != 1 || progress.NextAt == nil || progress.RunID != "run-1" {
+			t.Errorf("Expected the drill running with one step executed, got %+v", progress)
+		}
+		json.Unmarshal(stopped.Body.Bytes(), &progress)
+		if stopped.Code != http.StatusOK || progress.State != scenario.ScriptStopped || len(exchangeService.ActiveHalts(context.Background())) != 1 {
+			t.Errorf("Expected the drill stopped with ETH-USD still halted, got %d: %+v", stopped.Code, progress)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ScheduleHandler exposes scheduled session transitions and published funding events
+type ScheduleHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewScheduleHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// TransitionRequest schedules a session transition at a venue time
+type TransitionRequest struct {
+	Symbol string                    `json:"symbol" binding:"required"`
+	Action services.TransitionAction `json:"action" binding:"required"`
+	At     time.Time                 `json:"at" binding:"required"`
+}
+
+// Transitions lists scheduled and past transitions, filtered by the optional symbol query parameter
+func (h *ScheduleHandler) Transitions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"transitions": h.exchangeService.PhaseTransitions(c.Request.Context(), c.Query("symbol")),
+	})
+}
+
+// ScheduleTransition queues an opening auction, closing auction or uncross
+func (h *ScheduleHandler) ScheduleTransition(c *gin.Context) {
+	var body TransitionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	transition, err := h.exchangeService.ScheduleTransition(c.Request.Context(), body.Symbol, body.Action, body.At)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, transition)
+}
+
+// Funding lists published funding events, filtered by the optional symbol query parameter
+func (h *ScheduleHandler) Funding(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"funding": h.exchangeService.FundingEvents(c.Request.Context(), c.Query("symbol")),
+	})
+}
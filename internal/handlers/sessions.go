@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// SessionHandler exposes the trading sessions currently open on the venue
+type SessionHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewSessionHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *SessionHandler {
+	return &SessionHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns open sessions with their cancel-on-disconnect settings
+func (h *SessionHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": h.exchangeService.Sessions(c.Request.Context()),
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// SettlementHandler serves the settlement instructions generated for the custodian
+type SettlementHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewSettlementHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *SettlementHandler {
+	return &SettlementHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List returns settlement instructions newest first, filtered by the optional status
+// and account_id query parameters, up to ?limit= (default 100)
+func (h *SettlementHandler) List(c *gin.Context) {
+	h.list(c, c.Query("account_id"))
+}
+
+// Account returns one account's settlement instructions, filtered by the optional
+// status query parameter
+func (h *SettlementHandler) Account(c *gin.Context) {
+	h.list(c, c.Param("account_id"))
+}
+
+func (h *SettlementHandler) list(c *gin.Context, accountID string) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTradeLimit)))
+	if err != nil || limit <= 0 || limit > maxTradeLimit {
+		invalidRequest(c, fmt.Errorf("limit must be between 1 and %d", maxTradeLimit))
+		return
+	}
+	report, err := h.exchangeService.Settlements(c.Request.Context(), settlement.Status(c.Query("status")), accountID, limit)
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Run turns queued fills into instructions and submits everything due now
+func (h *SettlementHandler) Run(c *gin.Context) {
+	run, err := h.exchangeService.SettleNow(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// Cycles returns settlement cycles newest first, filtered by the optional status,
+// account_id and trade_date query parameters
+func (h *SettlementHandler) Cycles(c *gin.Context) {
+	h.cycles(c, c.Query("account_id"))
+}
+
+// AccountCycles returns the settlement cycles an account has positions in, with only
+// its positions
+func (h *SettlementHandler) AccountCycles(c *gin.Context) {
+	h.cycles(c, c.Param("account_id"))
+}
+
+func (h *SettlementHandler) cycles(c *gin.Context, accountID string) {
+	cycles, err := h.exchangeService.SettlementCycles(c.Request.Context(), services.SettlementCycleQuery{
+		Status:    settlement.CycleStatus(c.Query("status")),
+		AccountID: accountID,
+		TradeDate: c.Query("trade_date"),
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cycles": cycles})
+}
+
+// Cycle returns one settlement cycle with its status transitions
+func (h *SettlementHandler) Cycle(c *gin.Context) {
+	cycle, err := h.exchangeService.SettlementCycle(c.Request.Context(), c.Param("cycle_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, cycle)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// StorageHandler reports on an in-progress storage backend migration
+type StorageHandler struct {
+	migration *services.StorageMigration
+	logger    *logrus.Logger
+}
+
+// NewStorageHandler accepts a nil migration when no migration is configured
+func NewStorageHandler(migration *services.StorageMigration, logger *logrus.Logger) *StorageHandler {
+	return &StorageHandler{
+		migration: migration,
+		logger:    logger,
+	}
+}
+
+// Verify compares the migration source and target and returns the report
+func (h *StorageHandler) Verify(c *gin.Context) {
+	if h.migration == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no storage migration in progress"})
+		return
+	}
+
+	report, err := h.migration.Verify()
+	if err != nil {
+		h.logger.WithError(err).Error("Storage migration verification failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+const (
+	maxStreamTopics    = 100
+	streamWriteTimeout = 10 * time.Second
+)
+
+// StreamHandler serves the WebSocket push feed of market data and order updates
+type StreamHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+// streamRequest is a client frame on the stream
+type streamRequest struct {
+	Op        string `json:"op"` // subscribe, unsubscribe or ping
+	Channel   string `json:"channel"`
+	Symbol    string `json:"symbol"`     // Market data channels
+	AccountID string `json:"account_id"` // Orders channel
+}
+
+// streamReply acknowledges a client frame or says why it was refused
+type streamReply struct {
+	Type    string                `json:"type"` // subscribed, unsubscribed, pong or error
+	Channel feed.Channel          `json:"channel,omitempty"`
+	Key     string                `json:"key,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Code    services.RejectReason `json:"code,omitempty"`
+}
+
+func NewStreamHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *StreamHandler {
+	return &StreamHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Stream upgrades to a WebSocket. Clients send {"op":"subscribe","channel":"trades",
+// "symbol":"BTC-USD"} frames, or "account_id" for the orders channel, and receive
+// pushes on every topic they subscribed to. A key already holding its stream limit
+// is refused with 429 before the upgrade.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	lease, err := h.exchangeService.OpenStream(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), errorBody(err))
+		return
+	}
+	defer lease.Close()
+
+	server := websocket.Server{
+		// Any origin may connect; the feed is authenticated by API key, not cookies
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serve(c.Request.Context(), conn, lease)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *StreamHandler) serve(ctx context.Context, conn *websocket.Conn, lease *entitlements.Lease) {
+	defer conn.Close()
+	sub := h.exchangeService.Feed().Subscribe()
+	defer sub.Close()
+
+	var writeMu sync.Mutex
+	write := func(v interface{}) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return websocket.JSON.Send(conn, v)
+	}
+
+	// Client frames are read on their own goroutine so pushes never wait on them
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			var frame string
+			if err := websocket.Message.Receive(conn, &frame); err != nil {
+				return
+			}
+			// Holding the write lock while subscribing sends the acknowledgement
+			// ahead of the topic's initial snapshot
+			writeMu.Lock()
+			err := write(h.handle(ctx, lease, sub, frame))
+			writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-sub.Messages():
+			writeMu.Lock()
+			err := write(msg)
+			writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				writeMu.Lock()
+				write(streamReply{Type: "error", Error: err.Error()})
+				writeMu.Unlock()
+				h.logger.WithError(err).Info("Stream subscriber disconnected")
+			}
+			return
+		case <-readerDone:
+			return
+		}
+	}
+}
+
+// handle applies one client frame and returns the reply to send
+func (h *StreamHandler) handle(ctx context.Context, lease *entitlements.Lease, sub *feed.Subscriber, frame string) streamReply {
+	var req streamRequest
+	if err := json.Unmarshal([]byte(frame), &req); err != nil {
+		return streamError(services.NewRejection(services.RejectInvalidRequest, err))
+	}
+	switch req.Op {
+	case "ping":
+		return streamReply{Type: "pong"}
+	case "subscribe", "unsubscribe":
+	default:
+		return streamError(services.NewRejection(services.RejectInvalidRequest,
+			fmt.Errorf("unknown op %q: expected subscribe, unsubscribe or ping", req.Op)))
+	}
+
+	channel, err := feed.ParseChannel(req.Channel)
+	if err != nil {
+		return streamError(services.NewRejection(services.RejectInvalidRequest, err))
+	}
+	topic := feed.Topic{Channel: channel, Key: req.Symbol}
+	switch channel {
+	case feed.ChannelOrders:
+		topic.Key = req.AccountID
+	case feed.ChannelExchangeStats:
+		topic.Key = "" // Venue-wide
+	}
+
+	if req.Op == "unsubscribe" {
+		h.exchangeService.UnsubscribeStream(lease, sub, topic)
+		return streamReply{Type: "unsubscribed", Channel: topic.Channel, Key: topic.Key}
+	}
+	if sub.Topics() >= maxStreamTopics {
+		return streamError(services.NewRejection(services.RejectInvalidRequest,
+			fmt.Errorf("at most %d topics per connection", maxStreamTopics)))
+	}
+	if err := h.exchangeService.SubscribeStream(ctx, lease, sub, topic); err != nil {
+		return streamError(err)
+	}
+	return streamReply{Type: "subscribed", Channel: topic.Channel, Key: topic.Key}
+}
+
+func streamError(err error) streamReply {
+	return streamReply{Type: "error", Error: err.Error(), Code: services.RejectionOf(err).Reason}
+}
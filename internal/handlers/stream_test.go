@@ -0,0 +1,200 @@
+//go:build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/handlers"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// streamFrame holds the fields of both replies and pushed messages
+type streamFrame struct {
+	Type     string          `json:"type"`
+	Channel  string          `json:"channel"`
+	Key      string          `json:"key"`
+	Sequence uint64          `json:"sequence"`
+	Code     string          `json:"code"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func dialStream(t *testing.T) (*services.ExchangeService, *websocket.Conn) {
+	t.Helper()
+	exchangeService, dial := streamServer(t, &config.Config{ServiceName: "exchange-simulator"})
+	conn, err := dial()
+	if err != nil {
+		t.Fatalf("Expected to connect, got %v", err)
+	}
+	return exchangeService, conn
+}
+
+// streamServer serves the stream for cfg and returns a dialer for it
+func streamServer(t *testing.T, cfg *config.Config) (*services.ExchangeService, func() (*websocket.Conn, error)) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	exchangeService := services.NewExchangeService(cfg, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/v1/stream", handlers.NewStreamHandler(exchangeService, logger).Stream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return exchangeService, func() (*websocket.Conn, error) {
+		conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/v1/stream", "", server.URL)
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+		}
+		return conn, err
+	}
+}
+
+func send(t *testing.T, conn *websocket.Conn, frame string) {
+	t.Helper()
+	if err := websocket.Message.Send(conn, frame); err != nil {
+		t.Fatalf("Expected to send %s, got %v", frame, err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) streamFrame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var frame streamFrame
+	if err := websocket.JSON.Receive(conn, &frame); err != nil {
+		t.Fatalf("Expected a frame, got %v", err)
+	}
+	return frame
+}
+
+func TestStreamHandler(t *testing.T) {
+	t.Run("pushes_trades_book_and_order_updates", func(t *testing.T) {
+		// Given: A client subscribed to BTC-USD trades and book, and to a maker's orders
+		exchangeService, conn := dialStream(t)
+		send(t, conn, `{"op":"subscribe","channel":"book","symbol":"BTC-USD"}`)
+		if ack := receive(t, conn); ack.Type != "subscribed" || ack.Channel != "book" {
+			t.Fatalf("Expected a book acknowledgement, got %+v", ack)
+		}
+		if snapshot := receive(t, conn); snapshot.Type != "snapshot" || snapshot.Sequence != 0 {
+			t.Fatalf("Expected the initial book snapshot, got %+v", snapshot)
+		}
+		send(t, conn, `{"op":"subscribe","channel":"trades","symbol":"BTC-USD"}`)
+		receive(t, conn)
+		send(t, conn, `{"op":"subscribe","channel":"orders","account_id":"maker"}`)
+		receive(t, conn)
+
+		// When: An ask rests and is then lifted by another account
+		ctx := context.Background()
+		ask, _ := exchangeService.PlaceOrder(ctx, services.OrderRequest{
+			AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000,
+		})
+		exchangeService.PlaceOrder(ctx, services.OrderRequest{
+			AccountID: "taker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000,
+		})
+
+		// Then: The maker sees its order twice, the trade prints and the book adds then removes the level
+		var orders []models.Order
+		var trades []models.Trade
+		var sequences []uint64
+		for len(orders) < 2 || len(trades) < 1 || len(sequences) < 2 {
+			frame := receive(t, conn)
+			switch frame.Channel {
+			case "orders":
+				var order models.Order
+				json.Unmarshal(frame.Data, &order)
+				orders = append(orders, order)
+			case "trades":
+				var trade models.Trade
+				json.Unmarshal(frame.Data, &trade)
+				trades = append(trades, trade)
+			case "book":
+				sequences = append(sequences, frame.Sequence)
+			}
+		}
+		if orders[0].ID != ask.Order.ID || orders[1].Status != models.OrderStatusFilled {
+			t.Errorf("Expected the ask to go out new then filled, got %+v", orders)
+		}
+		if trades[0].SellOrderID != ask.Order.ID || trades[0].Quantity != 1 {
+			t.Errorf("Unexpected trade: %+v", trades[0])
+		}
+		if sequences[0] != 1 || sequences[1] != 2 {
+			t.Errorf("Expected book sequences 1 and 2, got %v", sequences)
+		}
+	})
+
+	t.Run("refuses_bad_frames", func(t *testing.T) {
+		_, conn := dialStream(t)
+		cases := []struct {
+			frame string
+			code  services.RejectReason
+		}{
+			{`{"op":`, services.RejectInvalidRequest},
+			{`{"op":"watch","channel":"trades","symbol":"BTC-USD"}`, services.RejectInvalidRequest},
+			{`{"op":"subscribe","channel":"quotes","symbol":"BTC-USD"}`, services.RejectInvalidRequest},
+			{`{"op":"subscribe","channel":"trades","symbol":"DOGE-USD"}`, services.RejectUnknownInstrument},
+			{`{"op":"subscribe","channel":"orders"}`, services.RejectInvalidAccount},
+		}
+		for _, tc := range cases {
+			send(t, conn, tc.frame)
+			if reply := receive(t, conn); reply.Type != "error" || reply.Code != string(tc.code) {
+				t.Errorf("%s: expected error %s, got %+v", tc.frame, tc.code, reply)
+			}
+		}
+
+		send(t, conn, `{"op":"ping"}`)
+		if reply := receive(t, conn); reply.Type != "pong" {
+			t.Errorf("Expected pong, got %+v", reply)
+		}
+	})
+	t.Run("enforces_stream_and_subscription_limits", func(t *testing.T) {
+		// Given: Keys limited to one stream and two subscriptions, and a client holding the stream
+		exchangeService, dial := streamServer(t, &config.Config{ServiceName: "exchange-simulator", StreamLimit: 1, SubscriptionLimit: 2})
+		conn, err := dial()
+		if err != nil {
+			t.Fatalf("Expected to connect, got %v", err)
+		}
+
+		// When: It subscribes to three topics and opens a second stream
+		send(t, conn, `{"op":"subscribe","channel":"trades","symbol":"BTC-USD"}`)
+		if ack := receive(t, conn); ack.Type != "subscribed" {
+			t.Fatalf("Expected the first subscription, got %+v", ack)
+		}
+		send(t, conn, `{"op":"subscribe","channel":"trades","symbol":"ETH-USD"}`)
+		if ack := receive(t, conn); ack.Type != "subscribed" {
+			t.Fatalf("Expected the second subscription, got %+v", ack)
+		}
+		send(t, conn, `{"op":"subscribe","channel":"trades","symbol":"EUR-USD"}`)
+		refused := receive(t, conn)
+		_, dialErr := dial()
+
+		// Then: The third topic and second stream are refused, and unsubscribing frees a slot
+		if refused.Type != "error" || refused.Code != string(services.RejectSubscriptionLimit) {
+			t.Errorf("Expected a SUBSCRIPTION_LIMIT_EXCEEDED error, got %+v", refused)
+		}
+		if dialErr == nil {
+			t.Error("Expected the second stream to be refused")
+		}
+		usage := exchangeService.SubscriptionUsage(context.Background())
+		if usage.Streams != 1 || usage.Subscriptions != 2 {
+			t.Errorf("Expected one stream holding two subscriptions, got %+v", usage)
+		}
+		send(t, conn, `{"op":"unsubscribe","channel":"trades","symbol":"ETH-USD"}`)
+		receive(t, conn)
+		send(t, conn, `{"op":"subscribe","channel":"trades","symbol":"EUR-USD"}`)
+		if ack := receive(t, conn); ack.Type != "subscribed" {
+			t.Errorf("Expected the freed slot to be reused, got %+v", ack)
+		}
+	})
+}
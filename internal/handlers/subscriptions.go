@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// SubscriptionHandler reports the streams and subscriptions each API key holds
+// against its limits
+type SubscriptionHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewSubscriptionHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Usage reports the caller's own usage, by the API key it sent
+func (h *SubscriptionHandler) Usage(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.SubscriptionUsage(c.Request.Context()))
+}
+
+// List reports every key holding a stream open or with its own limits
+func (h *SubscriptionHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"api_keys": h.exchangeService.Entitlements().All()})
+}
+
+// SetLimits gives one key its own limits; streams already open are kept
+func (h *SubscriptionHandler) SetLimits(c *gin.Context) {
+	var limits entitlements.Limits
+	if err := c.ShouldBindJSON(&limits); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if limits.MaxStreams < 0 || limits.MaxSubscriptions < 0 {
+		invalidRequest(c, errors.New("limits must not be negative"))
+		return
+	}
+	apiKey := c.Param("api_key")
+	h.exchangeService.Entitlements().SetLimits(apiKey, limits)
+	c.JSON(http.StatusOK, h.exchangeService.Entitlements().Usage(apiKey))
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// SurveillanceHandler exposes the venue's built-in surveillance flags
+type SurveillanceHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewSurveillanceHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *SurveillanceHandler {
+	return &SurveillanceHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Flags lists raised flags, filtered by the optional account_id query parameter
+func (h *SurveillanceHandler) Flags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"flags": h.exchangeService.SurveillanceFlags(c.Request.Context(), c.Query("account_id")),
+	})
+}
+
+// Accounts lists flagged accounts with their flag counts
+func (h *SurveillanceHandler) Accounts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"accounts": h.exchangeService.FlaggedAccounts(c.Request.Context()),
+	})
+}
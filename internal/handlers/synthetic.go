@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// SyntheticHandler reports and steps the synthetic market's price paths
+type SyntheticHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewSyntheticHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *SyntheticHandler {
+	return &SyntheticHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// List reports each synthetic path and the liquidity resting for it
+func (h *SyntheticHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"instruments": h.exchangeService.SyntheticMarket(c.Request.Context())})
+}
+
+// Step advances the paths to the current venue time, for orchestrators driving a
+// simulated clock between steps
+func (h *SyntheticHandler) Step(c *gin.Context) {
+	h.exchangeService.StepSyntheticMarket(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"instruments": h.exchangeService.SyntheticMarket(c.Request.Context())})
+}
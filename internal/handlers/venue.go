@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// VenueHandler exposes the personality of the venue being simulated
+type VenueHandler struct {
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewVenueHandler(exchangeService *services.ExchangeService, logger *logrus.Logger) *VenueHandler {
+	return &VenueHandler{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// Get returns the venue's name, fee schedule, latency profile, listed instruments and
+// API flavor
+func (h *VenueHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exchangeService.Personality(c.Request.Context()))
+}
@@ -0,0 +1,188 @@
+package accountstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// table holds one row per account opened on a venue instance
+const table = "exchange_accounts"
+
+// Schema creates the table, as migration 000003_accounts does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance     TEXT        NOT NULL,
+	account_id   TEXT        NOT NULL,
+	tier         TEXT        NOT NULL,
+	metadata     JSONB       NOT NULL,
+	status       TEXT        NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL,
+	closed_at    TIMESTAMPTZ,
+	balances     JSONB       NOT NULL DEFAULT '{}',
+	permissions  JSONB       NOT NULL DEFAULT '[]',
+	api_key_hash TEXT        NOT NULL DEFAULT '',
+	leverage     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	api_keys     JSONB       NOT NULL DEFAULT '[]',
+	PRIMARY KEY (instance, account_id)
+);
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS balances JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS permissions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS api_key_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS leverage DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS api_keys JSONB NOT NULL DEFAULT '[]'`
+
+const accountColumns = `account_id, tier, metadata, status, created_at, closed_at, balances, permissions, api_key_hash, leverage, api_keys`
+
+// storedKey keeps the signing secret the API never returns
+type storedKey struct {
+	accounts.Key
+	Secret string `json:"secret"`
+}
+
+// PostgresStore keeps one venue instance's accounts in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the account table when it does not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create account table: %w", err)
+	}
+	return nil
+}
+
+// Save inserts the accounts or replaces the rows with their IDs in one statement
+func (s *PostgresStore) Save(batch ...accounts.Account) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	const columns = 12
+	values := make([]string, 0, len(batch))
+	args := make([]interface{}, 0, len(batch)*columns)
+	for i, account := range batch {
+		keys := make([]storedKey, len(account.Keys))
+		for j, key := range account.Keys {
+			keys[j] = storedKey{Key: key, Secret: key.Secret}
+		}
+		encoded, err := encodeJSON(account.Metadata, account.Balances, account.Permissions, keys)
+		if err != nil {
+			return fmt.Errorf("failed to encode account %s: %w", account.ID, err)
+		}
+		var closedAt interface{}
+		if account.ClosedAt != nil {
+			closedAt = account.ClosedAt.UTC()
+		}
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args, s.instance, account.ID, account.Tier, encoded[0], string(account.Status), account.CreatedAt.UTC(),
+			closedAt, encoded[1], encoded[2], account.APIKeyHash, account.Leverage, encoded[3])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, `+accountColumns+`) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (instance, account_id) DO UPDATE SET
+			tier = EXCLUDED.tier, metadata = EXCLUDED.metadata, status = EXCLUDED.status, closed_at = EXCLUDED.closed_at,
+			balances = EXCLUDED.balances, permissions = EXCLUDED.permissions, api_key_hash = EXCLUDED.api_key_hash,
+			leverage = EXCLUDED.leverage, api_keys = EXCLUDED.api_keys`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("failed to save %d accounts to postgres: %w", len(batch), err)
+	}
+	return nil
+}
+
+// Delete removes an account's row
+func (s *PostgresStore) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, `DELETE FROM `+table+` WHERE instance = $1 AND account_id = $2`, s.instance, id); err != nil {
+		return fmt.Errorf("failed to delete account %s from postgres: %w", id, err)
+	}
+	return nil
+}
+
+// Load returns every account saved for the instance
+func (s *PostgresStore) Load() ([]accounts.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx,
+		`SELECT `+accountColumns+` FROM `+table+` WHERE instance = $1`, s.instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	loaded := make([]accounts.Account, 0)
+	for rows.Next() {
+		var account accounts.Account
+		var metadata, balances, permissions, keys []byte
+		var stored []storedKey
+		var status string
+		var closedAt sql.NullTime
+		if err := rows.Scan(&account.ID, &account.Tier, &metadata, &status, &account.CreatedAt, &closedAt,
+			&balances, &permissions, &account.APIKeyHash, &account.Leverage, &keys); err != nil {
+			return nil, fmt.Errorf("failed to read account: %w", err)
+		}
+		for _, field := range []struct {
+			raw  []byte
+			into interface{}
+		}{{metadata, &account.Metadata}, {balances, &account.Balances}, {permissions, &account.Permissions}, {keys, &stored}} {
+			if err := json.Unmarshal(field.raw, field.into); err != nil {
+				return nil, fmt.Errorf("failed to decode account %s: %w", account.ID, err)
+			}
+		}
+		for _, key := range stored {
+			key.Key.Secret = key.Secret
+			account.Keys = append(account.Keys, key.Key)
+		}
+		account.Status = accounts.Status(status)
+		account.CreatedAt = account.CreatedAt.UTC()
+		if closedAt.Valid {
+			at := closedAt.Time.UTC()
+			account.ClosedAt = &at
+		}
+		loaded = append(loaded, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read accounts: %w", err)
+	}
+	return loaded, nil
+}
+
+// encodeJSON marshals each value for a JSONB column
+func encodeJSON(values ...interface{}) ([]string, error) {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = string(raw)
+	}
+	return encoded, nil
+}
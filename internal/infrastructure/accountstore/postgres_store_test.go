@@ -0,0 +1,71 @@
+//go:build integration
+
+package accountstore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("saves_closes_and_deletes_accounts", func(t *testing.T) {
+		// Given: Two accounts, one later closed
+		createdAt := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		kept := accounts.Account{ID: "acct-1", Tier: "vip", Metadata: map[string]string{"desk": "rates"}, Balances: map[string]float64{"USD": 1000},
+			Permissions: accounts.DefaultPermissions, Leverage: 5, APIKeyHash: accounts.HashAPIKey("sk_test"), Status: accounts.StatusActive, CreatedAt: createdAt}
+		key, _ := accounts.NewKey("bot", []accounts.Permission{accounts.PermissionRead}, createdAt)
+		kept.Keys = []accounts.Key{key}
+		erased := accounts.Account{ID: "acct-2", Tier: accounts.DefaultTier, Metadata: map[string]string{}, Status: accounts.StatusActive, CreatedAt: createdAt}
+		if err := store.Save(kept, erased); err != nil {
+			t.Fatalf("Expected to save both accounts, got %v", err)
+		}
+		closedAt := createdAt.Add(time.Hour)
+		kept.Status, kept.ClosedAt = accounts.StatusClosed, &closedAt
+
+		// When: The close is saved and the other account deleted
+		if err := store.Save(kept); err != nil {
+			t.Fatalf("Expected to save the close, got %v", err)
+		}
+		if err := store.Delete(erased.ID); err != nil {
+			t.Fatalf("Expected to delete, got %v", err)
+		}
+		loaded, err := store.Load()
+
+		// Then: Only the closed account is loaded, with every field round-tripped
+		if err != nil || len(loaded) != 1 {
+			t.Fatalf("Expected one account, got %+v, %v", loaded, err)
+		}
+		got := loaded[0]
+		if got.ID != kept.ID || got.Tier != "vip" || got.Metadata["desk"] != "rates" || got.Balances["USD"] != 1000 ||
+			!got.Can(accounts.PermissionTrade) || got.Leverage != 5 || got.APIKeyHash != kept.APIKeyHash || got.Status != accounts.StatusClosed ||
+			!got.CreatedAt.Equal(createdAt) || got.ClosedAt == nil || !got.ClosedAt.Equal(closedAt) {
+			t.Errorf("Unexpected account: %+v", got)
+		}
+		if len(got.Keys) != 1 || got.Keys[0].ID != key.ID || got.Keys[0].Secret != key.Secret || got.Keys[0].Can(accounts.PermissionTrade) {
+			t.Errorf("Expected the key and its secret round-tripped, got %+v", got.Keys)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package infrastructure
+
+import (
+	"net"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// interfaceAddrs lists the host's interface addresses; replaced in tests
+var interfaceAddrs = net.InterfaceAddrs
+
+// advertisedHost is the host other services are told to reach this instance at,
+// and where it came from: ADVERTISED_HOST, else the orchestrator's POD_IP, else
+// outside development the first non-loopback IPv4 address on the host's
+// interfaces, else localhost
+func advertisedHost(cfg *config.Config) (string, string) {
+	if cfg.AdvertisedHost != "" {
+		return cfg.AdvertisedHost, "configured"
+	}
+	if cfg.PodIP != "" {
+		return cfg.PodIP, "orchestrator"
+	}
+	if cfg.Environment == "" || cfg.Environment == "development" {
+		return "localhost", "default"
+	}
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return "localhost", "default"
+	}
+	for _, addr := range addrs {
+		network, ok := addr.(*net.IPNet)
+		if !ok || network.IP.IsLoopback() || network.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ip := network.IP.To4(); ip != nil {
+			return ip.String(), "interface"
+		}
+	}
+	return "localhost", "default"
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"net"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestAdvertisedHost(t *testing.T) {
+	original := interfaceAddrs
+	defer func() { interfaceAddrs = original }()
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+			&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+			&net.IPNet{IP: net.ParseIP("10.1.2.3"), Mask: net.CIDRMask(24, 32)},
+		}, nil
+	}
+
+	cases := []struct {
+		name   string
+		cfg    config.Config
+		host   string
+		source string
+	}{
+		{"advertised_host_wins", config.Config{AdvertisedHost: "exchange-okx.svc", PodIP: "10.9.9.9", Environment: "production"}, "exchange-okx.svc", "configured"},
+		{"pod_ip_from_the_orchestrator", config.Config{PodIP: "10.9.9.9", Environment: "development"}, "10.9.9.9", "orchestrator"},
+		{"interface_address_outside_development", config.Config{Environment: "staging"}, "10.1.2.3", "interface"},
+		{"localhost_in_development", config.Config{Environment: "development"}, "localhost", "default"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			host, source := advertisedHost(&tc.cfg)
+			if host != tc.host || source != tc.source {
+				t.Errorf("Expected %s from %s, got %s from %s", tc.host, tc.source, host, source)
+			}
+		})
+	}
+
+	t.Run("registration_carries_the_instance_and_environment", func(t *testing.T) {
+		// Given: A named instance in staging
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceInstanceName: "exchange-OKX", Environment: "staging", GRPCPort: 50051, RedisURL: "redis://localhost:6379"}
+
+		// When: Its discovery client is made
+		info := NewServiceDiscoveryClient(cfg, logger).serviceInfo
+
+		// Then: It registers under its interface address, with its name and environment
+		if info.Host != "10.1.2.3" || info.InstanceName != "exchange-OKX" || info.Environment != "staging" || info.Metadata["instance_id"] != "exchange-OKX" {
+			t.Errorf("Unexpected registration: %+v", info)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
+)
+
+// AuditCorrelatorSink delivers audit events to the audit-correlator found through
+// service discovery, connecting on first use. With a stream window, batches go over
+// one StreamEvents stream, reopened after it breaks; a correlator without streaming
+// is sent one SubmitEvents call per batch instead.
+type AuditCorrelatorSink struct {
+	clients *InterServiceClientManager
+	window  int // Batches left unacknowledged on the stream (0 = unary calls)
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stream  *AuditEventStream // nil until the first streamed batch, and after it breaks
+	unary   bool              // The correlator does not implement StreamEvents
+	mu      sync.Mutex
+}
+
+func NewAuditCorrelatorSink(clients *InterServiceClientManager, window int) *AuditCorrelatorSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AuditCorrelatorSink{clients: clients, window: window, ctx: ctx, cancel: cancel}
+}
+
+// SubmitAuditEvents sends a batch of events to the audit-correlator
+func (s *AuditCorrelatorSink) SubmitAuditEvents(ctx context.Context, events []audit.Event) error {
+	client, err := s.clients.GetAuditCorrelatorClient()
+	if err != nil {
+		return err
+	}
+	stream, err := s.openStream(client)
+	if err != nil || stream == nil {
+		return client.SubmitAuditEvents(ctx, events)
+	}
+	_, err = stream.Submit(ctx, events)
+	if err != nil && stream.Err() != nil {
+		s.dropStream(stream)
+		if status.Code(stream.Err()) == codes.Unimplemented {
+			s.mu.Lock()
+			s.unary = true
+			s.mu.Unlock()
+			return client.SubmitAuditEvents(ctx, events)
+		}
+	}
+	return err
+}
+
+// SubmitFlag sends a surveillance flag to the audit-correlator
+func (s *AuditCorrelatorSink) SubmitFlag(ctx context.Context, event surveillance.AuditEvent) error {
+	client, err := s.clients.GetAuditCorrelatorClient()
+	if err != nil {
+		return err
+	}
+	return client.SubmitFlag(ctx, event)
+}
+
+// openStream returns the open stream, opening one when there is none; it returns
+// nil when batches are sent as unary calls
+func (s *AuditCorrelatorSink) openStream(client AuditCorrelatorClient) (*AuditEventStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.window <= 0 || s.unary {
+		return nil, nil
+	}
+	if s.stream == nil {
+		stream, err := client.StreamAuditEvents(s.ctx, s.window)
+		if err != nil {
+			return nil, err
+		}
+		s.stream = stream
+	}
+	return s.stream, nil
+}
+
+// dropStream forgets stream once it has broken, so the next batch opens another
+func (s *AuditCorrelatorSink) dropStream(stream *AuditEventStream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == stream {
+		s.stream = nil
+	}
+}
+
+// Close ends the stream; call it once nothing more is submitted
+func (s *AuditCorrelatorSink) Close() error {
+	s.mu.Lock()
+	stream := s.stream
+	s.stream = nil
+	s.mu.Unlock()
+	defer s.cancel()
+	if stream == nil {
+		return nil
+	}
+	return stream.Close()
+}
+
+// Publish delivers outbox messages holding audit events to the audit-correlator
+func (s *AuditCorrelatorSink) Publish(ctx context.Context, messages []outbox.Message) error {
+	events := make([]audit.Event, 0, len(messages))
+	for _, message := range messages {
+		var event audit.Event
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return fmt.Errorf("outbox message %s is not an audit event: %w", message.ID, err)
+		}
+		events = append(events, event)
+	}
+	return s.SubmitAuditEvents(ctx, events)
+}
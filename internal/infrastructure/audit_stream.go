@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	auditv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+)
+
+// errAuditStreamClosed is returned for batches submitted after the stream was closed
+var errAuditStreamClosed = errors.New("audit event stream closed")
+
+// AuditEventStream submits batches over one StreamEvents call. At most window
+// batches are left unacknowledged: Submit blocks for a free slot, so a correlator
+// that falls behind slows its callers. The correlator may resize the window with
+// any acknowledgement.
+type AuditEventStream struct {
+	stream   auditv1.AuditService_StreamEventsClient
+	cancel   context.CancelFunc
+	window   int
+	sequence uint64
+	pending  map[uint64]chan streamAck // Unacknowledged batches by sequence
+	freed    chan struct{}             // Closed and replaced whenever a slot frees
+	err      error                     // Why the stream ended; nil while it is open
+	mu       sync.Mutex
+	sendMu   sync.Mutex // Held across Send, which a stream does not allow concurrently
+}
+
+type streamAck struct {
+	accepted int32
+	err      error
+}
+
+func newAuditEventStream(stream auditv1.AuditService_StreamEventsClient, cancel context.CancelFunc, window int) *AuditEventStream {
+	if window <= 0 {
+		window = 1
+	}
+	s := &AuditEventStream{
+		stream:  stream,
+		cancel:  cancel,
+		window:  window,
+		pending: make(map[uint64]chan streamAck),
+		freed:   make(chan struct{}),
+	}
+	go s.receive()
+	return s
+}
+
+// Submit sends a batch and waits for its acknowledgement, returning the events the
+// correlator recorded. An error from the stream ends it; open a new one to go on.
+func (s *AuditEventStream) Submit(ctx context.Context, events []audit.Event) (int32, error) {
+	ack, err := s.reserve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	req := &auditv1.StreamEventsRequest{Sequence: ack.sequence, Events: auditEventsProto(events)}
+	s.sendMu.Lock()
+	err = s.stream.Send(req)
+	s.sendMu.Unlock()
+	if err != nil {
+		s.fail(err)
+	}
+
+	select {
+	case result := <-ack.result:
+		if result.err != nil {
+			return 0, fmt.Errorf("failed to stream %d audit events: %w", len(events), result.err)
+		}
+		return result.accepted, nil
+	case <-ctx.Done():
+		// The batch keeps its slot until the correlator answers for it
+		return 0, ctx.Err()
+	}
+}
+
+type reservation struct {
+	sequence uint64
+	result   chan streamAck
+}
+
+// reserve waits for a free slot in the window and takes the next sequence
+func (s *AuditEventStream) reserve(ctx context.Context) (reservation, error) {
+	for {
+		s.mu.Lock()
+		if s.err != nil {
+			err := s.err
+			s.mu.Unlock()
+			return reservation{}, err
+		}
+		if len(s.pending) < s.window {
+			s.sequence++
+			r := reservation{sequence: s.sequence, result: make(chan streamAck, 1)}
+			s.pending[r.sequence] = r.result
+			s.mu.Unlock()
+			return r, nil
+		}
+		freed := s.freed
+		s.mu.Unlock()
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return reservation{}, ctx.Err()
+		}
+	}
+}
+
+// receive hands each acknowledgement to the batch it names until the stream ends
+func (s *AuditEventStream) receive() {
+	for {
+		resp, err := s.stream.Recv()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		s.mu.Lock()
+		result, ok := s.pending[resp.GetSequence()]
+		delete(s.pending, resp.GetSequence())
+		if resp.GetWindow() > 0 {
+			s.window = int(resp.GetWindow())
+		}
+		close(s.freed)
+		s.freed = make(chan struct{})
+		s.mu.Unlock()
+		if ok {
+			result <- streamAck{accepted: resp.GetAccepted()}
+		}
+	}
+}
+
+// fail ends the stream, failing every batch still awaiting acknowledgement
+func (s *AuditEventStream) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+		for sequence, result := range s.pending {
+			result <- streamAck{err: err}
+			delete(s.pending, sequence)
+		}
+		close(s.freed)
+	}
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Err reports why the stream ended, or nil while it is open
+func (s *AuditEventStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Window reports how many batches may be left unacknowledged
+func (s *AuditEventStream) Window() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.window
+}
+
+// Close ends the stream; batches awaiting acknowledgement fail
+func (s *AuditEventStream) Close() error {
+	s.sendMu.Lock()
+	err := s.stream.CloseSend()
+	s.sendMu.Unlock()
+	s.fail(errAuditStreamClosed)
+	return err
+}
+
+// auditEventsProto converts events to their wire form
+func auditEventsProto(events []audit.Event) []*auditv1.AuditEvent {
+	converted := make([]*auditv1.AuditEvent, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, &auditv1.AuditEvent{
+			EventId:       event.ID,
+			EventType:     event.Type,
+			Source:        event.Source,
+			CorrelationId: event.CorrelationID,
+			AccountId:     event.AccountID,
+			Symbol:        event.Symbol,
+			OrderId:       event.OrderID,
+			TradeId:       event.TradeID,
+			OccurredAtMs:  event.OccurredAt.UnixMilli(),
+			Attributes:    event.Attributes,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,243 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	auditv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+)
+
+// streamingAuditServer records every event it is sent. Streamed batches are
+// acknowledged as release allows, so tests can hold the correlator back; without
+// streaming it answers StreamEvents as unimplemented.
+type streamingAuditServer struct {
+	auditv1.UnimplementedAuditServiceServer
+	streaming bool
+	release   chan struct{} // One acknowledgement per receive; nil acknowledges at once
+	mu        sync.Mutex
+	events    []string
+}
+
+func (s *streamingAuditServer) SubmitEvents(ctx context.Context, req *auditv1.SubmitEventsRequest) (*auditv1.SubmitEventsResponse, error) {
+	s.record(req.GetEvents())
+	return &auditv1.SubmitEventsResponse{Accepted: int32(len(req.GetEvents()))}, nil
+}
+
+func (s *streamingAuditServer) StreamEvents(stream auditv1.AuditService_StreamEventsServer) error {
+	if !s.streaming {
+		return s.UnimplementedAuditServiceServer.StreamEvents(stream)
+	}
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		if s.release != nil {
+			<-s.release
+		}
+		s.record(req.GetEvents())
+		if err := stream.Send(&auditv1.StreamEventsResponse{Sequence: req.GetSequence(), Accepted: int32(len(req.GetEvents()))}); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *streamingAuditServer) record(events []*auditv1.AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		s.events = append(s.events, event.GetEventId())
+	}
+}
+
+func (s *streamingAuditServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.events...)
+}
+
+// dialAuditServer serves server and returns a client connected to it
+func dialAuditServer(t *testing.T, server *streamingAuditServer) *auditCorrelatorClientImpl {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen, got %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	auditv1.RegisterAuditServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Expected to dial, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return &auditCorrelatorClientImpl{conn: conn, auditClient: auditv1.NewAuditServiceClient(conn), logger: logger}
+}
+
+func (s *AuditEventStream) unacknowledged() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+func TestAuditEventStream(t *testing.T) {
+	t.Run("acknowledges_each_batch_in_turn", func(t *testing.T) {
+		// Given: A stream to a correlator acknowledging at once
+		server := &streamingAuditServer{streaming: true}
+		client := dialAuditServer(t, server)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stream, err := client.StreamAuditEvents(ctx, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer stream.Close()
+
+		// When: Two batches are submitted
+		first, err := stream.Submit(ctx, []audit.Event{{ID: "e-1"}, {ID: "e-2"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, err := stream.Submit(ctx, []audit.Event{{ID: "e-3"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Each is acknowledged with the events the correlator recorded
+		if first != 2 || second != 1 {
+			t.Errorf("Expected 2 then 1 events accepted, got %d and %d", first, second)
+		}
+		if received := server.received(); len(received) != 3 || received[2] != "e-3" {
+			t.Errorf("Expected the correlator to record e-1 to e-3, got %v", received)
+		}
+	})
+
+	t.Run("holds_batches_beyond_the_window_until_one_is_acknowledged", func(t *testing.T) {
+		// Given: A window of one batch and a correlator holding its acknowledgements
+		server := &streamingAuditServer{streaming: true, release: make(chan struct{})}
+		client := dialAuditServer(t, server)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stream, err := client.StreamAuditEvents(ctx, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer stream.Close()
+		firstDone := make(chan error, 1)
+		go func() {
+			_, err := stream.Submit(ctx, []audit.Event{{ID: "e-1"}})
+			firstDone <- err
+		}()
+		for deadline := time.Now().Add(time.Second); stream.unacknowledged() == 0 && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+
+		// When: A second batch is submitted while the first is unacknowledged
+		waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer waitCancel()
+		_, err = stream.Submit(waitCtx, []audit.Event{{ID: "e-2"}})
+
+		// Then: It waits for a slot instead of being sent, and goes once one frees
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected the second batch to wait out its deadline, got %v", err)
+		}
+		if received := server.received(); len(received) != 0 {
+			t.Errorf("Expected nothing recorded yet, got %v", received)
+		}
+		server.release <- struct{}{}
+		if err := <-firstDone; err != nil {
+			t.Fatalf("Expected the first batch to be acknowledged, got %v", err)
+		}
+		go func() { server.release <- struct{}{} }()
+		if _, err := stream.Submit(ctx, []audit.Event{{ID: "e-2"}}); err != nil {
+			t.Fatalf("Expected the retried batch to be acknowledged, got %v", err)
+		}
+		if received := server.received(); len(received) != 2 {
+			t.Errorf("Expected both batches recorded, got %v", received)
+		}
+	})
+
+	t.Run("fails_batches_once_closed", func(t *testing.T) {
+		// Given: A closed stream
+		client := dialAuditServer(t, &streamingAuditServer{streaming: true})
+		stream, err := client.StreamAuditEvents(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		stream.Close()
+
+		// When: A batch is submitted
+		_, err = stream.Submit(context.Background(), []audit.Event{{ID: "e-1"}})
+
+		// Then: It is refused
+		if !errors.Is(err, errAuditStreamClosed) {
+			t.Errorf("Expected the closed stream error, got %v", err)
+		}
+	})
+}
+
+func TestAuditCorrelatorSink(t *testing.T) {
+	newSink := func(t *testing.T, server *streamingAuditServer, window int) *AuditCorrelatorSink {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		manager := NewInterServiceClientManager(&config.Config{}, logger, &ServiceDiscoveryClient{}, &ConfigurationClient{})
+		manager.setClient("audit-correlator", dialAuditServer(t, server))
+		sink := NewAuditCorrelatorSink(manager, window)
+		t.Cleanup(func() { sink.Close() })
+		return sink
+	}
+
+	t.Run("streams_batches_with_a_window", func(t *testing.T) {
+		// Given: A sink with a stream window and a streaming correlator
+		server := &streamingAuditServer{streaming: true}
+		sink := newSink(t, server, 4)
+
+		// When: A batch is submitted
+		err := sink.SubmitAuditEvents(context.Background(), []audit.Event{{ID: "e-1"}})
+
+		// Then: It goes over the stream, which stays open for the next
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if sink.stream == nil || len(server.received()) != 1 {
+			t.Errorf("Expected the batch streamed on an open stream, got %v", server.received())
+		}
+	})
+
+	t.Run("falls_back_to_unary_calls_without_streaming", func(t *testing.T) {
+		// Given: A correlator that does not implement StreamEvents
+		server := &streamingAuditServer{}
+		sink := newSink(t, server, 4)
+
+		// When: Two batches are submitted
+		for _, id := range []string{"e-1", "e-2"} {
+			if err := sink.SubmitAuditEvents(context.Background(), []audit.Event{{ID: id}}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		// Then: Both are delivered with SubmitEvents, and no stream is tried again
+		if received := server.received(); len(received) != 2 {
+			t.Errorf("Expected both batches recorded, got %v", received)
+		}
+		if !sink.unary || sink.stream != nil {
+			t.Errorf("Expected the sink to keep to unary calls, got unary=%v", sink.unary)
+		}
+	})
+}
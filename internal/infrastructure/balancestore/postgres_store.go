@@ -0,0 +1,101 @@
+package balancestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// table holds one row per posting; sequences restart with each journal
+const table = "exchange_balance_journal"
+
+// Schema creates the table, as migration 000004_balance_journal does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance    TEXT             NOT NULL,
+	journal_id  TEXT             NOT NULL,
+	sequence    BIGINT           NOT NULL,
+	transaction BIGINT           NOT NULL,
+	kind        TEXT             NOT NULL,
+	reference   TEXT             NOT NULL,
+	account_id  TEXT             NOT NULL,
+	asset       TEXT             NOT NULL,
+	bucket      TEXT             NOT NULL,
+	amount      DOUBLE PRECISION NOT NULL,
+	posted_at   TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, journal_id, sequence)
+);
+CREATE INDEX IF NOT EXISTS ` + table + `_account ON ` + table + ` (instance, account_id, posted_at)`
+
+const postingColumns = `journal_id, sequence, transaction, kind, reference, account_id, asset, bucket, amount, posted_at`
+
+// PostgresStore keeps one venue instance's balance journal in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the journal table and its index when they do not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create balance journal table: %w", err)
+	}
+	return nil
+}
+
+// Append writes postings in one statement; postings already written are skipped, so
+// a failed batch can be retried whole
+func (s *PostgresStore) Append(postings []ledger.Posting) error {
+	if len(postings) == 0 {
+		return nil
+	}
+	const columns = 11
+	values := make([]string, 0, len(postings))
+	args := make([]interface{}, 0, len(postings)*columns)
+	for i, posting := range postings {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args, s.instance, posting.JournalID, int64(posting.Sequence), int64(posting.Transaction), string(posting.Kind),
+			posting.Reference, posting.AccountID, posting.Asset, string(posting.Bucket), posting.Amount, posting.PostedAt.UTC())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, `+postingColumns+`) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (instance, journal_id, sequence) DO NOTHING`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save %d postings to postgres: %w", len(postings), err)
+	}
+	return nil
+}
+
+// Reassign moves an erased account's postings, across every journal, to its alias
+func (s *PostgresStore) Reassign(accountID, alias string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`UPDATE `+table+` SET account_id = $3 WHERE instance = $1 AND account_id = $2`, s.instance, accountID, alias)
+	if err != nil {
+		return fmt.Errorf("failed to reassign postings in postgres: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build integration
+
+package balancestore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("appends_once_and_reassigns_to_an_alias", func(t *testing.T) {
+		// Given: A funding transaction and a hold, the hold appended twice
+		journal := ledger.NewJournal("journal-1")
+		at := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		funded := journal.Fund("a", "USD", 1000, at)
+		held := journal.Hold("o-1", "a", "USD", 400, at)
+		if err := store.Append(append(funded, held...)); err != nil {
+			t.Fatalf("Expected to append, got %v", err)
+		}
+		if err := store.Append(held); err != nil {
+			t.Fatalf("Expected a retried batch to append, got %v", err)
+		}
+
+		// When: Account a is erased
+		if err := store.Reassign("a", "anon-1"); err != nil {
+			t.Fatalf("Expected to reassign, got %v", err)
+		}
+
+		// Then: Every posting is stored once and a's now belong to the alias
+		var rows, aliased int
+		var sum float64
+		err := db.QueryRow(`SELECT COUNT(*), COUNT(*) FILTER (WHERE account_id = 'anon-1'), SUM(amount) FROM `+table+` WHERE instance = $1`,
+			instance).Scan(&rows, &aliased, &sum)
+		if err != nil || rows != 4 || aliased != 3 || sum != 0 {
+			t.Errorf("Expected 4 balanced rows, 3 aliased, got %d, %d, %g, %v", rows, aliased, sum, err)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package candlestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// Cache is the subset of the data adapter's cache repository used by CacheStore
+type Cache interface {
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// barsPerBucket is how many consecutive bar slots of one series share a cache entry
+const barsPerBucket = 1000
+
+// bucket is the cache entry the latest appends of a series went to
+type bucket struct {
+	key     string
+	candles []marketdata.Candle
+}
+
+// CacheStore archives closed candles through the data adapter's cache, one entry per
+// barsPerBucket slots of a symbol and interval. The cache reports a missing key as an
+// error, so any failed read is taken as an empty bucket; failed writes are returned.
+type CacheStore struct {
+	cache   Cache
+	prefix  string
+	ttl     time.Duration // Zero keeps entries until evicted
+	timeout time.Duration
+	latest  map[string]*bucket // By series, so appends don't reread their bucket
+	mu      sync.Mutex
+}
+
+func NewCacheStore(cache Cache, prefix string, ttl, timeout time.Duration) *CacheStore {
+	return &CacheStore{
+		cache:   cache,
+		prefix:  prefix,
+		ttl:     ttl,
+		timeout: timeout,
+		latest:  make(map[string]*bucket),
+	}
+}
+
+// Append merges bars into their buckets; a bar already stored is replaced
+func (s *CacheStore) Append(symbol string, interval marketdata.Interval, candles []marketdata.Candle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := symbol + ":" + string(interval)
+	for len(candles) > 0 {
+		key := s.key(symbol, interval, candles[0].OpenTime)
+		n := 1
+		for n < len(candles) && s.key(symbol, interval, candles[n].OpenTime) == key {
+			n++
+		}
+
+		current := s.latest[series]
+		if current == nil || current.key != key {
+			current = &bucket{key: key, candles: s.read(key)}
+		}
+		merged := merge(current.candles, candles[:n])
+		data, err := json.Marshal(merged)
+		if err != nil {
+			return fmt.Errorf("failed to encode candles: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err = s.cache.Set(ctx, key, string(data), s.ttl)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to archive candles to %s: %w", key, err)
+		}
+		current.candles = merged
+		s.latest[series] = current
+		candles = candles[n:]
+	}
+	return nil
+}
+
+// Candles returns the archived bars opening within [from, to], oldest first
+func (s *CacheStore) Candles(symbol string, interval marketdata.Interval, from, to time.Time) ([]marketdata.Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := interval.Duration() * barsPerBucket
+	candles := make([]marketdata.Candle, 0)
+	for start := from.UTC().Truncate(span); !start.After(to); start = start.Add(span) {
+		for _, candle := range s.read(s.key(symbol, interval, start)) {
+			if !candle.OpenTime.Before(from) && !candle.OpenTime.After(to) {
+				candles = append(candles, candle)
+			}
+		}
+	}
+	return candles, nil
+}
+
+// key names the bucket holding the bar opening at openTime
+func (s *CacheStore) key(symbol string, interval marketdata.Interval, openTime time.Time) string {
+	start := openTime.UTC().Truncate(interval.Duration() * barsPerBucket)
+	return fmt.Sprintf("%s:%s:%s:%d", s.prefix, symbol, interval, start.Unix())
+}
+
+// read loads a bucket, preferring the copy of a series' latest bucket; callers hold mu
+func (s *CacheStore) read(key string) []marketdata.Candle {
+	for _, latest := range s.latest {
+		if latest.key == key {
+			return latest.candles
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	data, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+	var candles []marketdata.Candle
+	if err := json.Unmarshal([]byte(data), &candles); err != nil {
+		return nil
+	}
+	return candles
+}
+
+// merge returns stored with bars replaced or inserted by open time
+func merge(stored, bars []marketdata.Candle) []marketdata.Candle {
+	byOpen := make(map[int64]marketdata.Candle, len(stored)+len(bars))
+	for _, candle := range stored {
+		byOpen[candle.OpenTime.UnixNano()] = candle
+	}
+	for _, candle := range bars {
+		byOpen[candle.OpenTime.UnixNano()] = candle
+	}
+	merged := make([]marketdata.Candle, 0, len(byOpen))
+	for _, candle := range byOpen {
+		merged = append(merged, candle)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].OpenTime.Before(merged[j].OpenTime) })
+	return merged
+}
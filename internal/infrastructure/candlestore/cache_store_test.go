@@ -0,0 +1,101 @@
+//go:build unit
+
+package candlestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// memoryCache stands in for the data adapter's cache, which fails reads of missing keys
+type memoryCache struct {
+	entries map[string]string
+	sets    int
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]string)}
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.entries[key] = value
+	c.sets++
+	return nil
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	value, exists := c.entries[key]
+	if !exists {
+		return "", errors.New("key not found")
+	}
+	return value, nil
+}
+
+func minuteBars(start time.Time, count int, price float64) []marketdata.Candle {
+	candles := make([]marketdata.Candle, 0, count)
+	for i := 0; i < count; i++ {
+		openTime := start.Add(time.Duration(i) * time.Minute)
+		candles = append(candles, marketdata.Candle{OpenTime: openTime, CloseTime: openTime.Add(time.Minute), Open: price, High: price, Low: price, Close: price, Volume: 1, TradeCount: 1})
+	}
+	return candles
+}
+
+func TestCacheStore(t *testing.T) {
+	// Buckets span 1000 minutes of 1m bars, so this start is 5 bars before a boundary
+	bucketStart := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Truncate(barsPerBucket * time.Minute)
+	start := bucketStart.Add(-5 * time.Minute)
+
+	t.Run("reads_back_across_buckets", func(t *testing.T) {
+		// Given: Ten bars straddling a bucket boundary
+		cache := newMemoryCache()
+		store := NewCacheStore(cache, "exchange:test:candles", 0, time.Second)
+
+		// When: They are appended and read back by a fresh store
+		if err := store.Append("BTC-USD", marketdata.Interval1m, minuteBars(start, 10, 100)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		candles, err := NewCacheStore(cache, "exchange:test:candles", 0, time.Second).Candles("BTC-USD", marketdata.Interval1m, start, start.Add(9*time.Minute))
+
+		// Then: Every bar comes back in order from two cache entries
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(cache.entries) != 2 {
+			t.Errorf("Expected 2 buckets, got %d", len(cache.entries))
+		}
+		if len(candles) != 10 || !candles[0].OpenTime.Equal(start) || !candles[9].OpenTime.Equal(start.Add(9*time.Minute)) {
+			t.Errorf("Unexpected candles: %+v", candles)
+		}
+	})
+
+	t.Run("appends_merge_into_stored_bars", func(t *testing.T) {
+		// Given: Bars already archived by an earlier process
+		cache := newMemoryCache()
+		NewCacheStore(cache, "exchange:test:candles", 0, time.Second).Append("BTC-USD", marketdata.Interval1m, minuteBars(bucketStart, 3, 100))
+
+		// When: A new store rewrites the last bar and adds one more
+		store := NewCacheStore(cache, "exchange:test:candles", 0, time.Second)
+		if err := store.Append("BTC-USD", marketdata.Interval1m, minuteBars(bucketStart.Add(2*time.Minute), 2, 101)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The earlier bars are kept and the rewritten one replaced
+		candles, _ := store.Candles("BTC-USD", marketdata.Interval1m, bucketStart, bucketStart.Add(time.Hour))
+		if len(candles) != 4 || candles[1].Close != 100 || candles[2].Close != 101 || candles[3].Close != 101 {
+			t.Errorf("Unexpected candles: %+v", candles)
+		}
+	})
+
+	t.Run("missing_buckets_read_empty", func(t *testing.T) {
+		store := NewCacheStore(newMemoryCache(), "exchange:test:candles", 0, time.Second)
+
+		candles, err := store.Candles("BTC-USD", marketdata.Interval1h, start, start.Add(24*time.Hour))
+		if err != nil || len(candles) != 0 {
+			t.Errorf("Expected no candles and no error, got %d and %v", len(candles), err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is wrapped by the errors returned while a service's breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is where a service's circuit breaker stands
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Calls go through
+	BreakerOpen     BreakerState = "open"      // Calls fail fast until the backoff ends
+	BreakerHalfOpen BreakerState = "half_open" // One probe call decides whether to close or reopen
+)
+
+// BreakerConfig sets when a breaker trips and how long it stays open
+type BreakerConfig struct {
+	FailureThreshold int           // Consecutive failures that trip the breaker (0 = never trips)
+	Backoff          time.Duration // Time open after the first trip
+	MaxBackoff       time.Duration // Longest time open; each failed probe doubles the last
+}
+
+// BreakerStatus is a breaker's state as exposed in the client metrics
+type BreakerStatus struct {
+	State     BreakerState `json:"state"`
+	Failures  int          `json:"failures"` // Consecutive, since the last success
+	Trips     int64        `json:"trips"`
+	OpenUntil *time.Time   `json:"open_until,omitempty"`
+}
+
+// circuitBreaker guards the calls to one service. After FailureThreshold failures
+// in a row it opens, failing calls without making them; once its backoff ends one
+// probe call is let through, closing it on success and reopening it for twice as
+// long on failure.
+type circuitBreaker struct {
+	config   BreakerConfig
+	state    BreakerState
+	failures int
+	trips    int64
+	backoff  time.Duration // Length of the current or last open period
+	openedAt time.Time
+	probing  bool // A half-open probe is in flight
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+func newCircuitBreaker(config BreakerConfig) *circuitBreaker {
+	if config.Backoff <= 0 {
+		config.Backoff = time.Second
+	}
+	if config.MaxBackoff < config.Backoff {
+		config.MaxBackoff = config.Backoff
+	}
+	return &circuitBreaker{config: config, state: BreakerClosed, now: time.Now}
+}
+
+// allow reports whether a call may be made. While half-open only the first caller
+// is let through, as the probe; it must record its outcome.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		remaining := b.openedAt.Add(b.backoff).Sub(b.now())
+		if remaining > 0 {
+			return fmt.Errorf("%w for another %s", ErrCircuitOpen, remaining.Round(time.Millisecond))
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w while a probe call is in flight", ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record notes a call's outcome and reports whether it tripped the breaker
+func (b *circuitBreaker) record(err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if status.Code(err) == codes.Canceled || errors.Is(err, context.Canceled) {
+		// The caller gave up, which says nothing of the service; another probe may go
+		b.probing = false
+		return false
+	}
+	if !breakerFailure(err) {
+		// A call let through before the breaker opened does not close it
+		if b.state != BreakerOpen {
+			b.state, b.failures, b.probing = BreakerClosed, 0, false
+		}
+		return false
+	}
+
+	b.failures++
+	switch b.state {
+	case BreakerHalfOpen:
+		b.backoff *= 2
+		if b.backoff > b.config.MaxBackoff {
+			b.backoff = b.config.MaxBackoff
+		}
+	case BreakerClosed:
+		if b.config.FailureThreshold <= 0 || b.failures < b.config.FailureThreshold {
+			return false
+		}
+		b.backoff = b.config.Backoff
+	default:
+		return false
+	}
+	b.state, b.openedAt, b.probing = BreakerOpen, b.now(), false
+	b.trips++
+	return true
+}
+
+func (b *circuitBreaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BreakerStatus{State: b.state, Failures: b.failures, Trips: b.trips}
+	if b.state == BreakerOpen {
+		until := b.openedAt.Add(b.backoff)
+		status.OpenUntil = &until
+	}
+	return status
+}
+
+// breakerFailure reports whether err says the service is unwell, as opposed to the
+// service refusing a particular request
+func breakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		return false
+	}
+	return true
+}
+
+// breakerStateValue is the breaker state as a gauge: 0 closed, 1 half-open, 2 open
+func breakerStateValue(state BreakerState) float64 {
+	switch state {
+	case BreakerHalfOpen:
+		return 1
+	case BreakerOpen:
+		return 2
+	}
+	return 0
+}
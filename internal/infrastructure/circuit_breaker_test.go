@@ -0,0 +1,129 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// newTestBreaker returns a breaker on a clock the test moves
+func newTestBreaker(threshold int) (*circuitBreaker, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(BreakerConfig{FailureThreshold: threshold, Backoff: time.Second, MaxBackoff: 3 * time.Second})
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("opens_after_consecutive_failures_and_fails_fast", func(t *testing.T) {
+		// Given: A breaker tripping on three failures in a row
+		breaker, _ := newTestBreaker(3)
+
+		// When: A success breaks up failures, then three follow in a row
+		breaker.record(unavailable)
+		breaker.record(unavailable)
+		breaker.record(nil)
+		tripped := false
+		for i := 0; i < 3; i++ {
+			tripped = breaker.record(unavailable)
+		}
+
+		// Then: Only the third in a row trips it, and calls are refused
+		if !tripped || breaker.status().State != BreakerOpen {
+			t.Fatalf("Expected the breaker open, got %+v", breaker.status())
+		}
+		if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Expected ErrCircuitOpen, got %v", err)
+		}
+	})
+
+	t.Run("ignores_requests_the_service_refused", func(t *testing.T) {
+		breaker, _ := newTestBreaker(1)
+		if breaker.record(status.Error(codes.InvalidArgument, "bad batch")) || breaker.status().State != BreakerClosed {
+			t.Errorf("Expected a refused request to leave the breaker closed, got %+v", breaker.status())
+		}
+	})
+
+	t.Run("probes_once_the_backoff_ends_and_backs_off_further_on_failure", func(t *testing.T) {
+		// Given: A tripped breaker
+		breaker, now := newTestBreaker(1)
+		breaker.record(unavailable)
+
+		// When: The backoff ends and the probe fails
+		*now = now.Add(time.Second)
+		if err := breaker.allow(); err != nil {
+			t.Fatalf("Expected a probe to be let through, got %v", err)
+		}
+		second := breaker.allow()
+		breaker.record(unavailable)
+
+		// Then: Only one probe went, and the breaker reopens for twice as long
+		if !errors.Is(second, ErrCircuitOpen) {
+			t.Errorf("Expected a second call during the probe refused, got %v", second)
+		}
+		status := breaker.status()
+		if status.State != BreakerOpen || status.Trips != 2 || !status.OpenUntil.Equal(now.Add(2*time.Second)) {
+			t.Errorf("Expected the breaker reopened for 2s, got %+v", status)
+		}
+
+		// When: Another probe fails, then one succeeds
+		*now = now.Add(2 * time.Second)
+		breaker.allow()
+		breaker.record(unavailable)
+		backoff := breaker.status().OpenUntil.Sub(*now)
+		*now = now.Add(backoff)
+		breaker.allow()
+		breaker.record(nil)
+
+		// Then: The backoff is capped, and the success closes the breaker
+		if backoff != 3*time.Second {
+			t.Errorf("Expected the backoff capped at 3s, got %s", backoff)
+		}
+		if status := breaker.status(); status.State != BreakerClosed || status.Failures != 0 {
+			t.Errorf("Expected the breaker closed, got %+v", status)
+		}
+	})
+}
+
+func TestInterServiceClientManager_CircuitBreaker(t *testing.T) {
+	t.Run("fails_calls_fast_while_open_and_reports_the_breaker", func(t *testing.T) {
+		// Given: A manager whose breaker trips on two failures
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		manager := NewInterServiceClientManager(&config.Config{ClientBreakerThreshold: 2, ClientBreakerBackoff: time.Minute}, logger, &ServiceDiscoveryClient{}, &ConfigurationClient{})
+		interceptor := manager.unaryInterceptor("audit-correlator")
+		calls := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+
+		// When: Three calls are made
+		var errs []error
+		for i := 0; i < 3; i++ {
+			errs = append(errs, interceptor(context.Background(), "/audit.v1.AuditService/SubmitEvents", nil, nil, nil, invoker))
+		}
+
+		// Then: The third fails without a call, as the service being unavailable
+		var unavailable *ServiceUnavailableError
+		if calls != 2 || !errors.As(errs[2], &unavailable) || !errors.Is(errs[2], ErrCircuitOpen) {
+			t.Errorf("Expected 2 calls and a fast ServiceUnavailableError, got %d calls and %v", calls, errs[2])
+		}
+		metrics := manager.GetMetrics()
+		if metrics.CircuitBreakerTrips != 1 || metrics.CircuitBreakers["audit-correlator"].State != BreakerOpen {
+			t.Errorf("Expected one trip and the breaker open in the metrics, got %+v", metrics)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds the infrastructure clients classify their failures by. Callers branch
+// with errors.Is, e.g. errors.Is(err, ErrUnavailable) to retry later, and read the
+// failed call with errors.As into a *ClientError.
+var (
+	ErrNotFound     = errors.New("not found")    // The key, service or record does not exist
+	ErrUnavailable  = errors.New("unavailable")  // The dependency cannot be reached or refused the call for now
+	ErrTimeout      = errors.New("timeout")      // The call ran out of time
+	ErrUnauthorized = errors.New("unauthorized") // The dependency rejected this service's identity
+	ErrConflict     = errors.New("conflict")     // The call clashes with the dependency's current state
+)
+
+// ClientError is a failed call to a dependency, classified by Kind. Its message is
+// Op followed by the cause, as the clients reported it before there were kinds.
+type ClientError struct {
+	Kind    error  // One of the Err kinds above; nil when the failure fits none
+	Service string // Dependency called, e.g. "configuration-service" or "redis"; empty if none was
+	Op      string // What failed, e.g. "failed to fetch configuration"; may be empty
+	Err     error  // Cause; may be nil
+}
+
+func (e *ClientError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Op
+	case e.Op == "":
+		return e.Err.Error()
+	default:
+		return e.Op + ": " + e.Err.Error()
+	}
+}
+
+func (e *ClientError) Unwrap() error { return e.Err }
+
+// Is reports whether target is the error's kind, so errors.Is matches kinds as well
+// as the causes Unwrap exposes
+func (e *ClientError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// errorKind returns the kind err is classified by, or nil if it has none
+func errorKind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrUnavailable, ErrTimeout, ErrUnauthorized, ErrConflict} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// newClientError classifies a failed call to service by kind
+func newClientError(kind error, service, op string, err error) *ClientError {
+	return &ClientError{Kind: kind, Service: service, Op: op, Err: err}
+}
+
+// transportErrorKind classifies an error from reaching a dependency: running out of
+// time is a timeout, anything else leaves it unavailable
+func transportErrorKind(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrTimeout
+	}
+	return ErrUnavailable
+}
+
+// httpStatusKind classifies an HTTP error status; nil for one fitting no kind
+func httpStatusKind(code int) error {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrUnauthorized
+	case code == http.StatusConflict || code == http.StatusPreconditionFailed:
+		return ErrConflict
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case code == http.StatusTooManyRequests || code >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// grpcErrorKind classifies an error from a gRPC call by its status code. An open
+// circuit breaker leaves the service unavailable.
+func grpcErrorKind(err error) error {
+	if errors.Is(err, ErrCircuitOpen) {
+		return ErrUnavailable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.Unavailable, codes.ResourceExhausted, codes.Canceled:
+		return ErrUnavailable
+	case codes.DeadlineExceeded:
+		return ErrTimeout
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ErrUnauthorized
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return ErrConflict
+	}
+	return nil
+}
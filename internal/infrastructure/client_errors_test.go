@@ -0,0 +1,142 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	auditv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+)
+
+// failingConn fails every call with err
+type failingConn struct {
+	err error
+}
+
+func (c *failingConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return c.err
+}
+
+func (c *failingConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, c.err
+}
+
+func TestClientError(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	t.Run("matches_its_kind_and_its_cause", func(t *testing.T) {
+		// Given: An unavailable error caused by an open breaker
+		cause := fmt.Errorf("%w for another 1s", ErrCircuitOpen)
+		err := fmt.Errorf("wrapped: %w", newClientError(ErrUnavailable, "audit-correlator", "failed to submit", cause))
+
+		// When/Then: It matches the kind and the cause, but no other kind
+		var clientErr *ClientError
+		if !errors.Is(err, ErrUnavailable) || !errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrTimeout) {
+			t.Errorf("Expected ErrUnavailable and ErrCircuitOpen only, got %v", err)
+		}
+		if !errors.As(err, &clientErr) || clientErr.Service != "audit-correlator" {
+			t.Errorf("Expected a ClientError for audit-correlator, got %+v", clientErr)
+		}
+		if err.Error() != "wrapped: failed to submit: circuit breaker open for another 1s" {
+			t.Errorf("Unexpected message %q", err.Error())
+		}
+	})
+
+	t.Run("classifies_configuration_service_responses", func(t *testing.T) {
+		// Given: A configuration service answering by key with different statuses
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/configuration/missing":
+				w.WriteHeader(http.StatusNotFound)
+			case "/api/v1/configuration/forbidden":
+				w.WriteHeader(http.StatusForbidden)
+			case "/api/v1/configuration/slow":
+				time.Sleep(50 * time.Millisecond)
+			default:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, ConfigurationClientOptions{
+			BaseURL: server.URL,
+			Timeout: 20 * time.Millisecond,
+		})
+		ctx := context.Background()
+
+		// When: Each key is read
+		_, missing := client.GetConfiguration(ctx, "missing")
+		_, forbidden := client.GetConfiguration(ctx, "forbidden")
+		_, slow := client.GetConfiguration(ctx, "slow")
+		_, down := client.GetConfiguration(ctx, "down")
+
+		// Then: Each failure has its kind
+		if !errors.Is(missing, ErrNotFound) || !errors.Is(missing, ErrConfigurationNotFound) {
+			t.Errorf("Expected not found, got %v", missing)
+		}
+		if !errors.Is(forbidden, ErrUnauthorized) {
+			t.Errorf("Expected unauthorized, got %v", forbidden)
+		}
+		if !errors.Is(slow, ErrTimeout) {
+			t.Errorf("Expected a timeout, got %v", slow)
+		}
+		if !errors.Is(down, ErrUnavailable) {
+			t.Errorf("Expected unavailable, got %v", down)
+		}
+	})
+
+	t.Run("classifies_discovery_failures", func(t *testing.T) {
+		// Given: A registry without the service, and one already started
+		client := NewServiceDiscoveryClient(&config.Config{ServiceName: "test-service"}, logger)
+		client.redisClient = newMockRedisClient()
+		client.isRunning = true
+
+		// When: The service is looked up and discovery started again
+		_, lookup := client.GetServiceEndpoint("custodian-simulator")
+		start := client.Start()
+
+		// Then: The service is unavailable and the start conflicts
+		if !errors.Is(lookup, ErrUnavailable) {
+			t.Errorf("Expected unavailable, got %v", lookup)
+		}
+		if !errors.Is(start, ErrConflict) {
+			t.Errorf("Expected a conflict, got %v", start)
+		}
+	})
+
+	t.Run("classifies_inter_service_call_failures_by_status_code", func(t *testing.T) {
+		cases := map[codes.Code]error{
+			codes.NotFound:           ErrNotFound,
+			codes.Unavailable:        ErrUnavailable,
+			codes.DeadlineExceeded:   ErrTimeout,
+			codes.PermissionDenied:   ErrUnauthorized,
+			codes.FailedPrecondition: ErrConflict,
+		}
+		for code, kind := range cases {
+			// Given: An audit-correlator failing calls with the code
+			conn := &failingConn{err: status.Error(code, "failed")}
+			client := &auditCorrelatorClientImpl{conn: conn, auditClient: auditv1.NewAuditServiceClient(conn), logger: logger}
+
+			// When: Events are submitted
+			err := client.SubmitAuditEvents(context.Background(), []audit.Event{{ID: "e-1"}})
+
+			// Then: The failure has the code's kind and keeps the status
+			if !errors.Is(err, kind) || status.Code(err) != code {
+				t.Errorf("Expected %v with code %s, got %v", kind, code, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package clock
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrClockBackwards = errors.New("simulated clock cannot move backwards")
+	ErrInvalidSpeed   = errors.New("clock speed must be positive")
+)
+
+// Mode names the clock driving the venue
+type Mode string
+
+const (
+	ModeWall      Mode = "wall"
+	ModeSimulated Mode = "simulated"
+)
+
+// State describes a clock for the orchestrator
+type State struct {
+	Mode   Mode      `json:"mode"`
+	Now    time.Time `json:"now"`
+	Speed  float64   `json:"speed"` // Simulated seconds per wall second
+	Paused bool      `json:"paused"`
+}
+
+// WallClock is the real time of the host
+type WallClock struct{}
+
+func NewWallClock() *WallClock {
+	return &WallClock{}
+}
+
+func (c *WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// State reports the wall clock as running at real speed
+func (c *WallClock) State() State {
+	return State{Mode: ModeWall, Now: time.Now(), Speed: 1}
+}
+
+// SimulatedClock runs from a chosen start time at speed times wall time. An
+// orchestrator can pause it, change its speed, jump it forward or advance it by
+// a step; it never moves backwards, so recorded events stay in time order.
+type SimulatedClock struct {
+	base   time.Time // Simulated time at anchor
+	anchor time.Time // Wall time base was taken at
+	speed  float64
+	paused bool
+	mu     sync.Mutex
+	wall   func() time.Time
+}
+
+// NewSimulatedClock starts a running clock at start; speed must be positive
+func NewSimulatedClock(start time.Time, speed float64) (*SimulatedClock, error) {
+	if speed <= 0 {
+		return nil, ErrInvalidSpeed
+	}
+	c := &SimulatedClock{base: start, speed: speed, wall: time.Now}
+	c.anchor = c.wall()
+	return c, nil
+}
+
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now()
+}
+
+// State returns the current simulated time, speed and whether the clock is paused
+func (c *SimulatedClock) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return State{Mode: ModeSimulated, Now: c.now(), Speed: c.speed, Paused: c.paused}
+}
+
+// Set jumps the clock to t
+func (c *SimulatedClock) Set(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.now()) {
+		return ErrClockBackwards
+	}
+	c.rebase(t)
+	return nil
+}
+
+// Advance moves the clock forward by d, typically while paused for stepped runs
+func (c *SimulatedClock) Advance(d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d < 0 {
+		return ErrClockBackwards
+	}
+	c.rebase(c.now().Add(d))
+	return nil
+}
+
+// SetSpeed changes how many simulated seconds pass per wall second
+func (c *SimulatedClock) SetSpeed(speed float64) error {
+	if speed <= 0 {
+		return ErrInvalidSpeed
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rebase(c.now())
+	c.speed = speed
+	return nil
+}
+
+// Pause freezes simulated time until Resume
+func (c *SimulatedClock) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rebase(c.now())
+	c.paused = true
+}
+
+// Resume lets simulated time run again from where it was paused
+func (c *SimulatedClock) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rebase(c.now())
+	c.paused = false
+}
+
+// now derives simulated time from wall time elapsed since the anchor; callers hold mu
+func (c *SimulatedClock) now() time.Time {
+	if c.paused {
+		return c.base
+	}
+	elapsed := c.wall().Sub(c.anchor)
+	return c.base.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// rebase restarts elapsed-time accounting at simulated time t; callers hold mu
+func (c *SimulatedClock) rebase(t time.Time) {
+	c.base = t
+	c.anchor = c.wall()
+}
@@ -0,0 +1,64 @@
+//go:build unit
+
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSimulatedClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	newClock := func(t *testing.T, speed float64) (*SimulatedClock, *time.Time) {
+		wall := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		c, err := NewSimulatedClock(start, speed)
+		if err != nil {
+			t.Fatalf("Expected clock, got %v", err)
+		}
+		c.wall = func() time.Time { return wall }
+		c.anchor = wall
+		return c, &wall
+	}
+
+	t.Run("runs_at_speed_times_wall_time", func(t *testing.T) {
+		// Given: A clock running sixty times faster than the wall
+		c, wall := newClock(t, 60)
+
+		// When: Ten wall seconds pass
+		*wall = wall.Add(10 * time.Second)
+
+		// Then: Ten simulated minutes have passed
+		if got := c.Now(); !got.Equal(start.Add(10 * time.Minute)) {
+			t.Errorf("Expected %s, got %s", start.Add(10*time.Minute), got)
+		}
+	})
+
+	t.Run("pauses_and_steps", func(t *testing.T) {
+		// Given: A paused clock
+		c, wall := newClock(t, 1)
+		c.Pause()
+
+		// When: Wall time passes and the clock is advanced by a step
+		*wall = wall.Add(time.Hour)
+		if err := c.Advance(8 * time.Hour); err != nil {
+			t.Fatalf("Expected advance to succeed, got %v", err)
+		}
+
+		// Then: Only the step counts
+		state := c.State()
+		if !state.Paused || !state.Now.Equal(start.Add(8*time.Hour)) {
+			t.Errorf("Expected paused at %s, got %+v", start.Add(8*time.Hour), state)
+		}
+	})
+
+	t.Run("never_moves_backwards", func(t *testing.T) {
+		c, _ := newClock(t, 1)
+		if err := c.Set(start.Add(-time.Second)); !errors.Is(err, ErrClockBackwards) {
+			t.Errorf("Expected ErrClockBackwards, got %v", err)
+		}
+		if err := c.SetSpeed(0); !errors.Is(err, ErrInvalidSpeed) {
+			t.Errorf("Expected ErrInvalidSpeed, got %v", err)
+		}
+	})
+}
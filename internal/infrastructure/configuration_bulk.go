@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// prefixCacheEntry is the keys a prefix fetch returned; their values are cached by key
+type prefixCacheEntry struct {
+	keys      []string
+	expiresAt time.Time
+}
+
+// fetchConfigurationsByPrefix reads the keys under prefix from the cache or the
+// configuration service
+func (c *ConfigurationClient) fetchConfigurationsByPrefix(ctx context.Context, prefix string) (map[string]ConfigurationValue, error) {
+	if values, found := c.getCachedPrefix(prefix); found {
+		c.incrementCacheHit()
+		c.logger.WithField("prefix", prefix).Debug("Configuration cache hit")
+		return values, nil
+	}
+	c.incrementCacheMiss()
+
+	values, err, shared := c.prefixFlights.do(ctx, prefix, c.timeout, func(ctx context.Context) (map[string]ConfigurationValue, error) {
+		return c.requestConfigurationsByPrefix(ctx, prefix)
+	})
+	if shared && values != nil {
+		values = maps.Clone(values)
+	}
+	return values, err
+}
+
+// requestConfigurationsByPrefix fetches the keys under prefix from the configuration
+// service and caches them
+func (c *ConfigurationClient) requestConfigurationsByPrefix(ctx context.Context, prefix string) (map[string]ConfigurationValue, error) {
+	start := time.Now()
+	defer func() {
+		c.updateMetrics(time.Since(start))
+	}()
+
+	endpoint := fmt.Sprintf("%s/api/v1/configuration?prefix=%s", c.baseURL, url.QueryEscape(prefix))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, newClientError(nil, configurationServiceName, "failed to create request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Service-Name", c.config.ServiceName)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.setConnectionStatus(false)
+		return nil, newClientError(transportErrorKind(err), configurationServiceName, "failed to fetch configuration", err)
+	}
+	defer resp.Body.Close()
+	c.setConnectionStatus(true)
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.cachePrefix(prefix, nil)
+		return map[string]ConfigurationValue{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, configurationStatusError(resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, newClientError(transportErrorKind(err), configurationServiceName, "failed to read response body", err)
+	}
+	var configResp ConfigurationResponse
+	if err := json.Unmarshal(body, &configResp); err != nil {
+		return nil, newClientError(nil, configurationServiceName, "failed to parse response", err)
+	}
+	if !configResp.Success {
+		return nil, newClientError(nil, configurationServiceName, "configuration service error: "+configResp.Error, nil)
+	}
+
+	values := make(map[string]ConfigurationValue, len(configResp.Data))
+	for _, value := range configResp.Data {
+		if strings.HasPrefix(value.Key, prefix) {
+			values[value.Key] = value
+		}
+	}
+	c.cachePrefix(prefix, values)
+
+	c.logger.WithFields(logrus.Fields{
+		"prefix": prefix,
+		"keys":   len(values),
+	}).Debug("Configuration fetched successfully")
+	return values, nil
+}
+
+// GetInt reads key as an integer, held as a JSON number or a decimal string
+func (c *ConfigurationClient) GetInt(ctx context.Context, key string) (int, error) {
+	value, err := c.GetConfiguration(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.Value.(type) {
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("configuration key %s is not an integer: %v", key, value.Value)
+}
+
+// GetDuration reads key as a duration, held as a string such as "250ms" or a JSON
+// number of seconds
+func (c *ConfigurationClient) GetDuration(ctx context.Context, key string) (time.Duration, error) {
+	value, err := c.GetConfiguration(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.Value.(type) {
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	case string:
+		if d, err := time.ParseDuration(strings.TrimSpace(v)); err == nil {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("configuration key %s is not a duration: %v", key, value.Value)
+}
+
+// GetBool reads key as a boolean, held as a JSON boolean or a string such as "true"
+func (c *ConfigurationClient) GetBool(ctx context.Context, key string) (bool, error) {
+	value, err := c.GetConfiguration(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	switch v := value.Value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("configuration key %s is not a boolean: %v", key, value.Value)
+}
+
+// GetJSON decodes key into out, a pointer such as to a struct. A string value is
+// taken to hold the JSON itself, as with Setting.
+func (c *ConfigurationClient) GetJSON(ctx context.Context, key string, out interface{}) error {
+	value, err := c.GetConfiguration(ctx, key)
+	if err != nil {
+		return err
+	}
+	raw, ok := value.Value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value.Value)
+		if err != nil {
+			return fmt.Errorf("configuration key %s: %w", key, err)
+		}
+		raw = string(encoded)
+	}
+	if err := json.Unmarshal([]byte(raw), out); err != nil {
+		return fmt.Errorf("configuration key %s: %w", key, err)
+	}
+	return nil
+}
+
+// getCachedPrefix returns a prefix's values while the fetch and every key it
+// returned are still cached
+func (c *ConfigurationClient) getCachedPrefix(prefix string) (map[string]ConfigurationValue, bool) {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	entry, exists := c.prefixCache[prefix]
+	now := time.Now()
+	if !exists || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	values := make(map[string]ConfigurationValue, len(entry.keys))
+	for _, key := range entry.keys {
+		cached, exists := c.cache.get(key, now)
+		if !exists || cached.missing {
+			return nil, false
+		}
+		values[key] = cached.value
+	}
+	return values, true
+}
+
+// invalidatePrefixes drops the cached prefix fetches covering key, so the next
+// fetch reads all their keys together again; callers hold cacheMutex
+func (c *ConfigurationClient) invalidatePrefixes(key string) {
+	for prefix := range c.prefixCache {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.prefixCache, prefix)
+		}
+	}
+}
+
+// cachePrefix caches a prefix fetch and each value it returned
+func (c *ConfigurationClient) cachePrefix(prefix string, values map[string]ConfigurationValue) {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	expiresAt := time.Now().Add(c.cacheTTL)
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		c.recordEvictions(c.cache.put(key, configCacheEntry{value: value, expiresAt: expiresAt}))
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if c.prefixCache == nil {
+		c.prefixCache = make(map[string]prefixCacheEntry)
+	}
+	c.prefixCache[prefix] = prefixCacheEntry{keys: keys, expiresAt: expiresAt}
+
+	c.metricsMutex.Lock()
+	c.metrics.LastCacheUpdate = time.Now()
+	c.metricsMutex.Unlock()
+}
@@ -0,0 +1,119 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestConfigurationClient_Bulk(t *testing.T) {
+	newClient := func(t *testing.T, values map[string]interface{}) (*ConfigurationClient, *configurationServer) {
+		source := &configurationServer{values: values}
+		server := httptest.NewServer(source)
+		t.Cleanup(server.Close)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, ConfigurationClientOptions{BaseURL: server.URL})
+		return client, source
+	}
+
+	t.Run("fetches_a_prefix_in_one_request_and_caches_every_key", func(t *testing.T) {
+		// Given: Two fee keys and an unrelated key
+		client, source := newClient(t, map[string]interface{}{
+			"exchange.fees.maker": 0.001,
+			"exchange.fees.taker": 0.002,
+			"exchange.halts":      true,
+		})
+		ctx := context.Background()
+
+		// When: The fee prefix is fetched twice, then one of its keys on its own
+		first, err := client.GetConfigurationsByPrefix(ctx, "exchange.fees.")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, _ := client.GetConfigurationsByPrefix(ctx, "exchange.fees.")
+		taker, err := client.GetConfiguration(ctx, "exchange.fees.taker")
+
+		// Then: Only the fee keys came back, from a single request
+		if len(first) != 2 || len(second) != 2 || first["exchange.fees.maker"].Value != 0.001 {
+			t.Errorf("Expected the two fee keys, got %v and %v", first, second)
+		}
+		if err != nil || taker.Value != 0.002 {
+			t.Errorf("Expected the cached taker fee, got %v, %v", taker, err)
+		}
+		if source.requests != 1 {
+			t.Errorf("Expected 1 request, got %d", source.requests)
+		}
+	})
+
+	t.Run("refetches_a_prefix_once_one_of_its_keys_is_invalidated", func(t *testing.T) {
+		// Given: A fetched prefix, one of whose keys has been read past the cache
+		client, source := newClient(t, map[string]interface{}{"exchange.fees.maker": 0.001})
+		ctx := context.Background()
+		client.GetConfigurationsByPrefix(ctx, "exchange.fees.")
+		source.set("exchange.fees.maker", 0.0005)
+		client.Setting(ctx, "exchange.fees.maker")
+
+		// When: The prefix is fetched again
+		values, err := client.GetConfigurationsByPrefix(ctx, "exchange.fees.")
+
+		// Then: It is fetched anew rather than half from the cache
+		if err != nil || values["exchange.fees.maker"].Value != 0.0005 || source.requests != 3 {
+			t.Errorf("Expected the new value from a third request, got %v, %v after %d requests", values, err, source.requests)
+		}
+	})
+
+	t.Run("reads_typed_values", func(t *testing.T) {
+		// Given: Values held in the forms operators write them
+		client, _ := newClient(t, map[string]interface{}{
+			"exchange.depth":         float64(20),
+			"exchange.depth_text":    "25",
+			"exchange.timeout":       "250ms",
+			"exchange.timeout_secs":  1.5,
+			"exchange.halts":         true,
+			"exchange.halts_text":    "false",
+			"exchange.limits":        `{"key_weight": 100}`,
+			"exchange.limits_object": map[string]interface{}{"key_weight": 200},
+			"exchange.name":          "okx",
+		})
+		ctx := context.Background()
+
+		// When: They are read through the typed accessors
+		depth, depthErr := client.GetInt(ctx, "exchange.depth")
+		depthText, _ := client.GetInt(ctx, "exchange.depth_text")
+		timeout, timeoutErr := client.GetDuration(ctx, "exchange.timeout")
+		timeoutSecs, _ := client.GetDuration(ctx, "exchange.timeout_secs")
+		halts, haltsErr := client.GetBool(ctx, "exchange.halts")
+		haltsText, _ := client.GetBool(ctx, "exchange.halts_text")
+		var limits, limitsObject struct {
+			KeyWeight int `json:"key_weight"`
+		}
+		limitsErr := client.GetJSON(ctx, "exchange.limits", &limits)
+		client.GetJSON(ctx, "exchange.limits_object", &limitsObject)
+		_, mistypedErr := client.GetInt(ctx, "exchange.name")
+
+		// Then: Each converts, and a value of the wrong type is an error
+		if depth != 20 || depthText != 25 || depthErr != nil {
+			t.Errorf("Expected 20 and 25, got %d and %d (%v)", depth, depthText, depthErr)
+		}
+		if timeout != 250*time.Millisecond || timeoutSecs != 1500*time.Millisecond || timeoutErr != nil {
+			t.Errorf("Expected 250ms and 1.5s, got %s and %s (%v)", timeout, timeoutSecs, timeoutErr)
+		}
+		if !halts || haltsText || haltsErr != nil {
+			t.Errorf("Expected true and false, got %v and %v (%v)", halts, haltsText, haltsErr)
+		}
+		if limits.KeyWeight != 100 || limitsObject.KeyWeight != 200 || limitsErr != nil {
+			t.Errorf("Expected 100 and 200, got %d and %d (%v)", limits.KeyWeight, limitsObject.KeyWeight, limitsErr)
+		}
+		if mistypedErr == nil {
+			t.Error("Expected a name not to read as an integer")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultConfigurationCacheSize   = 1000
+	defaultConfigurationNegativeTTL = 30 * time.Second
+)
+
+// configCache holds configuration values up to a capacity, evicting the least
+// recently used key to make room; callers hold ConfigurationClient.cacheMutex
+type configCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Of *configCacheItem, most recently used first
+}
+
+type configCacheItem struct {
+	key   string
+	entry configCacheEntry
+}
+
+func newConfigCache(capacity int) *configCache {
+	if capacity <= 0 {
+		capacity = defaultConfigurationCacheSize
+	}
+	return &configCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns key's entry unless it has expired by now, marking it recently used
+func (c *configCache) get(key string, now time.Time) (configCacheEntry, bool) {
+	element, exists := c.entries[key]
+	if !exists {
+		return configCacheEntry{}, false
+	}
+	item := element.Value.(*configCacheItem)
+	if now.After(item.entry.expiresAt) {
+		c.remove(key)
+		return configCacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return item.entry, true
+}
+
+// put stores key's entry and returns how many keys were evicted to make room
+func (c *configCache) put(key string, entry configCacheEntry) int {
+	if element, exists := c.entries[key]; exists {
+		element.Value.(*configCacheItem).entry = entry
+		c.order.MoveToFront(element)
+		return 0
+	}
+	c.entries[key] = c.order.PushFront(&configCacheItem{key: key, entry: entry})
+	evicted := 0
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*configCacheItem).key)
+		evicted++
+	}
+	return evicted
+}
+
+func (c *configCache) remove(key string) {
+	if element, exists := c.entries[key]; exists {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+func (c *configCache) len() int {
+	return c.order.Len()
+}
+
+// errConfigurationFlightAborted is what waiting callers get should a request panic
+var errConfigurationFlightAborted error = newClientError(ErrUnavailable, configurationServiceName, "configuration request aborted", nil)
+
+// configFlight is one request to the configuration service that callers wait on
+type configFlight[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// configFlights coalesces concurrent requests for the same key into one, whose
+// result every caller shares; the zero value is ready to use
+type configFlights[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*configFlight[T]
+}
+
+// do starts fetch for key unless a call for it is already in flight, then waits for
+// the call's result or for ctx to be done; shared reports the call was another
+// caller's. The call keeps ctx's values but not its cancellation, bounded by timeout
+// instead, so a caller giving up does not fail the others waiting on it.
+func (g *configFlights[T]) do(ctx context.Context, key string, timeout time.Duration, fetch func(context.Context) (T, error)) (value T, err error, shared bool) {
+	g.mu.Lock()
+	call, shared := g.calls[key]
+	if !shared {
+		if g.calls == nil {
+			g.calls = make(map[string]*configFlight[T])
+		}
+		call = &configFlight[T]{done: make(chan struct{}), err: errConfigurationFlightAborted}
+		g.calls[key] = call
+		go g.run(ctx, key, timeout, call, fetch)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err, shared
+	case <-ctx.Done():
+		return value, ctx.Err(), shared
+	}
+}
+
+// run makes the call and releases the callers waiting on it; a request that panics
+// leaves them errConfigurationFlightAborted
+func (g *configFlights[T]) run(ctx context.Context, key string, timeout time.Duration, call *configFlight[T], fetch func(context.Context) (T, error)) {
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	defer func() {
+		recover()
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.value, call.err = fetch(fetchCtx)
+}
@@ -0,0 +1,191 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestConfigurationClient_Cache(t *testing.T) {
+	newCachingClient := func(t *testing.T, handler http.Handler, opts ConfigurationClientOptions) *ConfigurationClient {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		opts.BaseURL = server.URL
+		return NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, opts)
+	}
+
+	t.Run("coalesces_concurrent_misses_for_a_key_into_one_request", func(t *testing.T) {
+		// Given: A service that answers once released
+		source := &configurationServer{values: map[string]interface{}{"exchange.halts": true}}
+		release := make(chan struct{})
+		client := newCachingClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			source.ServeHTTP(w, r)
+		}), ConfigurationClientOptions{})
+
+		// When: Ten callers miss the cache for the key at once
+		var wg sync.WaitGroup
+		results := make(chan interface{}, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := client.GetConfiguration(context.Background(), "exchange.halts")
+				if err != nil {
+					results <- err
+					return
+				}
+				results <- value.Value
+			}()
+		}
+		waitFor(t, func() bool { return client.GetMetrics().CacheMisses == 10 })
+		close(release)
+		wg.Wait()
+		close(results)
+
+		// Then: Every caller got the value from a single request
+		for result := range results {
+			if result != true {
+				t.Errorf("Expected true, got %v", result)
+			}
+		}
+		if source.requests != 1 {
+			t.Errorf("Expected 1 request, got %d", source.requests)
+		}
+	})
+
+	t.Run("a_caller_giving_up_leaves_the_shared_request_running", func(t *testing.T) {
+		// Given: A service that answers once released, and a miss waiting on it under a
+		// context its caller cancels
+		source := &configurationServer{values: map[string]interface{}{"exchange.halts": true}}
+		release := make(chan struct{})
+		client := newCachingClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			source.ServeHTTP(w, r)
+		}), ConfigurationClientOptions{})
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error, 1)
+		go func() {
+			_, err := client.GetConfiguration(ctx, "exchange.halts")
+			first <- err
+		}()
+		waitFor(t, func() bool { return client.GetMetrics().CacheMisses == 1 })
+		second := make(chan interface{}, 1)
+		go func() {
+			value, err := client.GetConfiguration(context.Background(), "exchange.halts")
+			if err != nil {
+				second <- err
+				return
+			}
+			second <- value.Value
+		}()
+		waitFor(t, func() bool { return client.GetMetrics().CacheMisses == 2 })
+
+		// When: The first caller gives up before the service answers
+		cancel()
+		var err error
+		select {
+		case err = <-first:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the canceled caller to return without waiting for the service")
+		}
+		close(release)
+
+		// Then: Only it fails; the other caller gets the value from the one request
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the canceled caller to get context.Canceled, got %v", err)
+		}
+		if result := <-second; result != true {
+			t.Errorf("Expected the waiting caller to get true, got %v", result)
+		}
+		if source.requests != 1 {
+			t.Errorf("Expected 1 request, got %d", source.requests)
+		}
+	})
+
+	t.Run("reports_its_cache_statistics", func(t *testing.T) {
+		// Given: A service holding one of two keys, and a client caching two keys at most
+		source := &configurationServer{values: map[string]interface{}{"exchange.halts": true}}
+		client := newCachingClient(t, source, ConfigurationClientOptions{CacheSize: 2})
+		ctx := context.Background()
+
+		// When: Both keys are read twice
+		for i := 0; i < 2; i++ {
+			client.GetConfiguration(ctx, "exchange.halts")
+			client.GetConfiguration(ctx, "exchange.fees")
+		}
+		stats := client.CacheStats()
+
+		// Then: The value and the missing key are cached, and the second reads were hits
+		if stats.Entries != 2 || stats.Capacity != 2 || stats.Hits != 2 || stats.Misses != 2 || !stats.Connected {
+			t.Errorf("Expected 2 entries of 2, 2 hits and 2 misses, got %+v", stats)
+		}
+	})
+
+	t.Run("caches_a_missing_key_for_the_negative_ttl", func(t *testing.T) {
+		// Given: A service without the key, and a short negative TTL
+		source := &configurationServer{values: map[string]interface{}{}}
+		client := newCachingClient(t, source, ConfigurationClientOptions{NegativeTTL: 50 * time.Millisecond})
+		ctx := context.Background()
+
+		// When: The key is read twice, then again once the negative TTL has passed
+		_, first := client.GetConfiguration(ctx, "exchange.halts")
+		_, second := client.GetConfiguration(ctx, "exchange.halts")
+		requestsBefore := source.requests
+		time.Sleep(60 * time.Millisecond)
+		source.set("exchange.halts", true)
+		value, err := client.GetConfiguration(ctx, "exchange.halts")
+
+		// Then: The second read was answered from the cache, and the key is found after
+		if !errors.Is(first, ErrConfigurationNotFound) || !errors.Is(second, ErrConfigurationNotFound) {
+			t.Errorf("Expected not found twice, got %v and %v", first, second)
+		}
+		if requestsBefore != 1 {
+			t.Errorf("Expected 1 request for two reads, got %d", requestsBefore)
+		}
+		if err != nil || value.Value != true {
+			t.Errorf("Expected true once the negative TTL passed, got %v, %v", value, err)
+		}
+	})
+
+	t.Run("evicts_the_least_recently_used_key_once_full", func(t *testing.T) {
+		// Given: A cache of two keys holding a and b, with a read since b
+		source := &configurationServer{values: map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0}}
+		client := newCachingClient(t, source, ConfigurationClientOptions{CacheSize: 2})
+		ctx := context.Background()
+		client.GetConfiguration(ctx, "a")
+		client.GetConfiguration(ctx, "b")
+		client.GetConfiguration(ctx, "a")
+
+		// When: A third key is read
+		client.GetConfiguration(ctx, "c")
+
+		// Then: b made room for it, while a is still cached
+		client.GetConfiguration(ctx, "a")
+		if source.requests != 3 {
+			t.Errorf("Expected a still cached after 3 requests, got %d requests", source.requests)
+		}
+		client.GetConfiguration(ctx, "b")
+		if source.requests != 4 {
+			t.Errorf("Expected b refetched, got %d requests", source.requests)
+		}
+		if evictions := client.GetMetrics().CacheEvictions; evictions != 2 {
+			t.Errorf("Expected 2 evictions, got %d", evictions)
+		}
+		if size := client.cache.len(); size != 2 {
+			t.Errorf("Expected 2 cached keys, got %d", size)
+		}
+	})
+}
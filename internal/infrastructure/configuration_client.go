@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
 )
 
 type ConfigurationValue struct {
@@ -37,10 +39,19 @@ type ConfigurationClientMetrics struct {
 	LastCacheUpdate  time.Time `json:"last_cache_update"`
 	IsConnected      bool      `json:"is_connected"`
 	ResponseTimeMs   int64     `json:"response_time_ms"`
+	FallbackReads    int64     `json:"fallback_reads"` // Values served from the snapshot or environment
+	CacheEvictions   int64     `json:"cache_evictions"` // Keys evicted to keep the cache within its capacity
 }
 
+const (
+	defaultConfigurationServiceURL = "http://configuration-service:8080"
+	defaultConfigurationTimeout    = 10 * time.Second
+	defaultConfigurationCacheTTL   = 5 * time.Minute
+)
+
 type configCacheEntry struct {
 	value     ConfigurationValue
+	missing   bool // The service does not hold the key
 	expiresAt time.Time
 }
 
@@ -49,24 +60,81 @@ type ConfigurationClient struct {
 	logger         *logrus.Logger
 	httpClient     *http.Client
 	baseURL        string
-	cache          map[string]configCacheEntry
+	cache          *configCache
+	prefixCache    map[string]prefixCacheEntry // Keys each prefix fetch returned; guarded by cacheMutex
 	cacheTTL       time.Duration
+	negativeTTL    time.Duration // How long a key the service does not hold is remembered as missing
+	timeout        time.Duration // Limit on a shared request, which outlives callers giving up on it
+	valueFlights   configFlights[*ConfigurationValue]
+	prefixFlights  configFlights[map[string]ConfigurationValue]
 	cacheMutex     sync.RWMutex
 	metrics        ConfigurationClientMetrics
 	metricsMutex   sync.RWMutex
 	isInitialized  bool
+	subscriptions  map[string]map[int]*settingSubscription // Watched keys and their subscribers
+	subscriptionID int
+	watchTrigger   chan struct{}
+	watchMutex     sync.Mutex
+}
+
+// ConfigurationClientOptions override what the client takes from config; fields
+// left zero keep it
+type ConfigurationClientOptions struct {
+	BaseURL     string        // Configuration service URL (default CONFIG_SERVICE_URL)
+	Timeout     time.Duration // Limit on each request (default REQUEST_TIMEOUT)
+	CacheTTL    time.Duration // How long values are cached (default CACHE_TTL; negative = not cached)
+	NegativeTTL time.Duration // How long a missing key is cached (default CONFIG_NEGATIVE_CACHE_TTL, at most CacheTTL)
+	CacheSize   int           // Most keys cached at once (default CONFIG_CACHE_SIZE)
+	HTTPClient  *http.Client  // Client making the requests, whose Timeout then stands
 }
 
+// NewConfigurationClient returns a client for the configuration service at
+// CONFIG_SERVICE_URL, with REQUEST_TIMEOUT and CACHE_TTL
 func NewConfigurationClient(cfg *config.Config, logger *logrus.Logger) *ConfigurationClient {
+	return NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{})
+}
+
+// NewConfigurationClientWithOptions returns a client configured by cfg as overridden by opts
+func NewConfigurationClientWithOptions(cfg *config.Config, logger *logrus.Logger, opts ConfigurationClientOptions) *ConfigurationClient {
+	if opts.BaseURL == "" {
+		opts.BaseURL = cfg.ConfigurationServiceURL
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = defaultConfigurationServiceURL
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = cfg.RequestTimeout
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultConfigurationTimeout
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = cfg.CacheTTL
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = defaultConfigurationCacheTTL
+	}
+	if opts.NegativeTTL == 0 {
+		opts.NegativeTTL = cfg.ConfigNegativeCacheTTL
+	}
+	if opts.NegativeTTL == 0 {
+		opts.NegativeTTL = defaultConfigurationNegativeTTL
+	}
+	if opts.CacheSize == 0 {
+		opts.CacheSize = cfg.ConfigCacheSize
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: opts.Timeout}
+	}
 	return &ConfigurationClient{
-		config: cfg,
-		logger: logger,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL:  "http://configuration-service:8080",
-		cache:    make(map[string]configCacheEntry),
-		cacheTTL: 5 * time.Minute,
+		config:      cfg,
+		logger:      logger,
+		httpClient:  opts.HTTPClient,
+		baseURL:     strings.TrimRight(opts.BaseURL, "/"),
+		cache:       newConfigCache(opts.CacheSize),
+		cacheTTL:    opts.CacheTTL,
+		negativeTTL: opts.NegativeTTL,
+		timeout:     opts.Timeout,
 		metrics: ConfigurationClientMetrics{
 			IsConnected: false,
 		},
@@ -74,26 +142,43 @@ func NewConfigurationClient(cfg *config.Config, logger *logrus.Logger) *Configur
 	}
 }
 
-func (c *ConfigurationClient) GetConfiguration(ctx context.Context, key string) (*ConfigurationValue, error) {
+// fetchConfiguration reads key from the cache or the configuration service
+func (c *ConfigurationClient) fetchConfiguration(ctx context.Context, key string) (*ConfigurationValue, error) {
 	start := time.Now()
 	defer func() {
 		c.updateMetrics(time.Since(start))
 	}()
 
 	// Check cache first
-	if cachedValue, found := c.getCachedValue(key); found {
+	if cached, found := c.getCachedEntry(key); found {
 		c.incrementCacheHit()
 		c.logger.WithField("key", key).Debug("Configuration cache hit")
-		return &cachedValue, nil
+		if cached.missing {
+			return nil, configurationNotFound(key)
+		}
+		return &cached.value, nil
 	}
 
 	c.incrementCacheMiss()
 
-	// Fetch from service
+	// Concurrent misses for the key share one request
+	value, err, shared := c.valueFlights.do(ctx, key, c.timeout, func(ctx context.Context) (*ConfigurationValue, error) {
+		return c.requestConfiguration(ctx, key)
+	})
+	if shared && value != nil {
+		copied := *value
+		value = &copied
+	}
+	return value, err
+}
+
+// requestConfiguration fetches key from the configuration service and caches the
+// value, or that there is none
+func (c *ConfigurationClient) requestConfiguration(ctx context.Context, key string) (*ConfigurationValue, error) {
 	url := fmt.Sprintf("%s/api/v1/configuration/%s", c.baseURL, key)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, newClientError(nil, configurationServiceName, "failed to create request", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -102,32 +187,37 @@ func (c *ConfigurationClient) GetConfiguration(ctx context.Context, key string)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.setConnectionStatus(false)
-		return nil, fmt.Errorf("failed to fetch configuration: %w", err)
+		return nil, newClientError(transportErrorKind(err), configurationServiceName, "failed to fetch configuration", err)
 	}
 	defer resp.Body.Close()
 
 	c.setConnectionStatus(true)
 
+	if resp.StatusCode == http.StatusNotFound {
+		c.cacheMissing(key)
+		return nil, configurationNotFound(key)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("configuration service returned status %d", resp.StatusCode)
+		return nil, configurationStatusError(resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, newClientError(transportErrorKind(err), configurationServiceName, "failed to read response body", err)
 	}
 
 	var configResp ConfigurationResponse
 	if err := json.Unmarshal(body, &configResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, newClientError(nil, configurationServiceName, "failed to parse response", err)
 	}
 
 	if !configResp.Success {
-		return nil, fmt.Errorf("configuration service error: %s", configResp.Error)
+		return nil, newClientError(nil, configurationServiceName, "configuration service error: "+configResp.Error, nil)
 	}
 
 	if len(configResp.Data) == 0 {
-		return nil, fmt.Errorf("configuration key not found: %s", key)
+		c.cacheMissing(key)
+		return nil, configurationNotFound(key)
 	}
 
 	configValue := configResp.Data[0]
@@ -166,7 +256,7 @@ func (c *ConfigurationClient) SetConfiguration(ctx context.Context, key string,
 	url := fmt.Sprintf("%s/api/v1/configuration", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return newClientError(nil, configurationServiceName, "failed to create request", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -175,14 +265,14 @@ func (c *ConfigurationClient) SetConfiguration(ctx context.Context, key string,
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.setConnectionStatus(false)
-		return fmt.Errorf("failed to set configuration: %w", err)
+		return newClientError(transportErrorKind(err), configurationServiceName, "failed to set configuration", err)
 	}
 	defer resp.Body.Close()
 
 	c.setConnectionStatus(true)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("configuration service returned status %d", resp.StatusCode)
+		return configurationStatusError(resp.StatusCode)
 	}
 
 	// Invalidate cache for this key
@@ -209,46 +299,98 @@ func (c *ConfigurationClient) IsHealthy() bool {
 	return c.metrics.IsConnected
 }
 
-func (c *ConfigurationClient) getCachedValue(key string) (ConfigurationValue, bool) {
-	c.cacheMutex.RLock()
-	defer c.cacheMutex.RUnlock()
+// Ping asks the configuration service for its health, recording whether it answered
+func (c *ConfigurationClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/health", nil)
+	if err != nil {
+		return newClientError(nil, configurationServiceName, "failed to create request", err)
+	}
+	req.Header.Set("X-Service-Name", c.config.ServiceName)
 
-	entry, exists := c.cache[key]
-	if !exists {
-		return ConfigurationValue{}, false
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.setConnectionStatus(false)
+		return newClientError(transportErrorKind(err), configurationServiceName, "health check failed", err)
 	}
+	defer resp.Body.Close()
 
-	if time.Now().After(entry.expiresAt) {
-		// Cache expired, remove it
-		c.cacheMutex.RUnlock()
-		c.cacheMutex.Lock()
-		delete(c.cache, key)
-		c.cacheMutex.Unlock()
-		c.cacheMutex.RLock()
-		return ConfigurationValue{}, false
+	c.setConnectionStatus(true)
+	if resp.StatusCode != http.StatusOK {
+		return configurationStatusError(resp.StatusCode)
 	}
+	return nil
+}
+
+var _ ports.CacheReporter = (*ConfigurationClient)(nil)
 
-	return entry.value, true
+// CacheStats reports how the client's value cache has served it
+func (c *ConfigurationClient) CacheStats() ports.CacheStats {
+	c.cacheMutex.RLock()
+	entries, capacity := c.cache.len(), c.cache.capacity
+	c.cacheMutex.RUnlock()
+	metrics := c.GetMetrics()
+	return ports.CacheStats{
+		Entries:       entries,
+		Capacity:      capacity,
+		Hits:          metrics.CacheHits,
+		Misses:        metrics.CacheMisses,
+		Evictions:     metrics.CacheEvictions,
+		FallbackReads: metrics.FallbackReads,
+		Connected:     metrics.IsConnected,
+		LastUpdate:    metrics.LastCacheUpdate,
+	}
 }
 
-func (c *ConfigurationClient) cacheValue(key string, value ConfigurationValue) {
+func (c *ConfigurationClient) getCachedEntry(key string) (configCacheEntry, bool) {
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
+	return c.cache.get(key, time.Now())
+}
 
-	c.cache[key] = configCacheEntry{
+func (c *ConfigurationClient) cacheValue(key string, value ConfigurationValue) {
+	c.storeCacheEntry(key, configCacheEntry{
 		value:     value,
 		expiresAt: time.Now().Add(c.cacheTTL),
+	})
+}
+
+// cacheMissing remembers, for the shorter negative TTL, that key is not held
+func (c *ConfigurationClient) cacheMissing(key string) {
+	ttl := c.negativeTTL
+	if ttl > c.cacheTTL {
+		ttl = c.cacheTTL
 	}
+	c.storeCacheEntry(key, configCacheEntry{
+		missing:   true,
+		expiresAt: time.Now().Add(ttl),
+	})
+}
+
+func (c *ConfigurationClient) storeCacheEntry(key string, entry configCacheEntry) {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	c.recordEvictions(c.cache.put(key, entry))
 
 	c.metricsMutex.Lock()
 	c.metrics.LastCacheUpdate = time.Now()
 	c.metricsMutex.Unlock()
 }
 
+// recordEvictions counts keys evicted to keep the cache within its capacity
+func (c *ConfigurationClient) recordEvictions(evicted int) {
+	if evicted == 0 {
+		return
+	}
+	c.metricsMutex.Lock()
+	c.metrics.CacheEvictions += int64(evicted)
+	c.metricsMutex.Unlock()
+}
+
 func (c *ConfigurationClient) invalidateCache(key string) {
 	c.cacheMutex.Lock()
 	defer c.cacheMutex.Unlock()
-	delete(c.cache, key)
+	c.cache.remove(key)
+	c.invalidatePrefixes(key)
 }
 
 func (c *ConfigurationClient) incrementCacheHit() {
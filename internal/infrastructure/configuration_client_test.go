@@ -5,6 +5,7 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -48,8 +49,7 @@ func TestConfigurationClient_GetConfiguration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -104,8 +104,7 @@ func TestConfigurationClient_GetConfiguration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -145,8 +144,7 @@ func TestConfigurationClient_GetConfiguration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -183,8 +181,7 @@ func TestConfigurationClient_SetConfiguration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -240,8 +237,7 @@ func TestConfigurationClient_SetConfiguration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -294,8 +290,7 @@ func TestConfigurationClient_Metrics(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		ctx := context.Background()
 
@@ -340,8 +335,7 @@ func TestConfigurationClient_HealthCheck(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{BaseURL: server.URL})
 
 		// Initially not healthy
 		if client.IsHealthy() {
@@ -359,6 +353,45 @@ func TestConfigurationClient_HealthCheck(t *testing.T) {
 	})
 }
 
+func TestConfigurationClient_Ping(t *testing.T) {
+	t.Run("answers_while_the_service_is_healthy", func(t *testing.T) {
+		// Given: A configuration service reporting itself healthy
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, ConfigurationClientOptions{BaseURL: server.URL})
+
+		// When/Then: It is pinged on its health endpoint, and recorded as connected
+		if err := client.Ping(context.Background()); err != nil || path != "/api/v1/health" {
+			t.Errorf("Expected a healthy service at /api/v1/health, got %v at %s", err, path)
+		}
+		if !client.IsHealthy() {
+			t.Error("Expected the client recorded as healthy")
+		}
+	})
+
+	t.Run("classifies_an_unhealthy_service", func(t *testing.T) {
+		// Given: A configuration service failing its health check
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, ConfigurationClientOptions{BaseURL: server.URL})
+
+		// When/Then: It is pinged
+		if err := client.Ping(context.Background()); !errors.Is(err, ErrUnavailable) {
+			t.Errorf("Expected an unavailable service, got %v", err)
+		}
+	})
+}
+
 func TestConfigurationClient_CacheExpiration(t *testing.T) {
 	t.Run("cache_expires_after_ttl", func(t *testing.T) {
 		requestCount := 0
@@ -386,9 +419,10 @@ func TestConfigurationClient_CacheExpiration(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
 
-		client := NewConfigurationClient(cfg, logger)
-		client.baseURL = server.URL
-		client.cacheTTL = 100 * time.Millisecond // Short TTL for testing
+		client := NewConfigurationClientWithOptions(cfg, logger, ConfigurationClientOptions{
+			BaseURL:  server.URL,
+			CacheTTL: 100 * time.Millisecond, // Short TTL for testing
+		})
 
 		ctx := context.Background()
 
@@ -411,4 +445,48 @@ func TestConfigurationClient_CacheExpiration(t *testing.T) {
 			t.Errorf("Expected 2 server requests due to cache expiration, got %d", requestCount)
 		}
 	})
-}
\ No newline at end of file
+}
+func TestConfigurationClient_Options(t *testing.T) {
+	t.Run("uses_the_configured_service_url_and_cache_ttl", func(t *testing.T) {
+		// Given: A configuration service at CONFIG_SERVICE_URL, with caching off
+		source := &configurationServer{values: map[string]interface{}{"exchange.halts": true}}
+		server := httptest.NewServer(source)
+		defer server.Close()
+		cfg := &config.Config{ServiceName: "exchange-simulator", ConfigurationServiceURL: server.URL + "/", CacheTTL: -1}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewConfigurationClient(cfg, logger)
+
+		// When: A key is read twice
+		first, err := client.GetConfiguration(context.Background(), "exchange.halts")
+		client.GetConfiguration(context.Background(), "exchange.halts")
+
+		// Then: Both reads reached the configured service
+		if err != nil || first.Value != true {
+			t.Fatalf("Expected true, got %v, %v", first, err)
+		}
+		if source.requests != 2 {
+			t.Errorf("Expected 2 requests, got %d", source.requests)
+		}
+	})
+
+	t.Run("lets_options_override_the_configuration", func(t *testing.T) {
+		// Given: Options naming another service, timeout and TTL
+		cfg := &config.Config{ConfigurationServiceURL: "http://localhost:8090", RequestTimeout: 5 * time.Second}
+
+		// When: The client is built with them
+		client := NewConfigurationClientWithOptions(cfg, logrus.New(), ConfigurationClientOptions{
+			BaseURL:  "http://config.test:9000",
+			Timeout:  time.Second,
+			CacheTTL: time.Minute,
+		})
+
+		// Then: The options win, and what they leave unset defaults
+		if client.baseURL != "http://config.test:9000" || client.httpClient.Timeout != time.Second || client.cacheTTL != time.Minute {
+			t.Errorf("Expected the options, got %s, %s, %s", client.baseURL, client.httpClient.Timeout, client.cacheTTL)
+		}
+		if defaulted := NewConfigurationClient(&config.Config{}, logrus.New()); defaulted.cacheTTL != defaultConfigurationCacheTTL || defaulted.httpClient.Timeout != defaultConfigurationTimeout {
+			t.Errorf("Expected the defaults, got %s and %s", defaulted.cacheTTL, defaulted.httpClient.Timeout)
+		}
+	})
+}
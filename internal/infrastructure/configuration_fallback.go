@@ -0,0 +1,225 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrConfigurationNotFound is returned for a key the configuration service does not
+// hold, as the cause of an ErrNotFound ClientError
+var ErrConfigurationNotFound = errors.New("configuration key not found")
+
+// configurationServiceName names the configuration service in ClientErrors
+const configurationServiceName = "configuration-service"
+
+func configurationNotFound(key string) error {
+	return newClientError(ErrNotFound, configurationServiceName, "", fmt.Errorf("%w: %s", ErrConfigurationNotFound, key))
+}
+
+func configurationStatusError(code int) error {
+	return newClientError(httpStatusKind(code), configurationServiceName, fmt.Sprintf("configuration service returned status %d", code), nil)
+}
+
+// configDefaultEnvPrefix starts the environment variables holding configuration defaults
+const configDefaultEnvPrefix = "CONFIG_DEFAULT_"
+
+// configSnapshots holds each snapshot file's values by path, shared by every client
+// in the process so that they write the file one at a time and from the same state
+var (
+	configSnapshots     = make(map[string]map[string]ConfigurationValue)
+	configSnapshotMutex sync.Mutex
+)
+
+// GetConfiguration reads key from the cache or the configuration service. While the
+// service cannot be reached, the last value it gave for key is read from the
+// snapshot file instead, and failing that the CONFIG_DEFAULT_ environment variable
+// named after key, such as CONFIG_DEFAULT_EXCHANGE_FEES_MAKER for
+// exchange.fees.maker. A key the service does not hold falls back to the
+// environment only.
+func (c *ConfigurationClient) GetConfiguration(ctx context.Context, key string) (*ConfigurationValue, error) {
+	value, err := c.fetchConfiguration(ctx, key)
+	if err == nil {
+		c.rememberConfiguration(map[string]ConfigurationValue{key: *value}, nil)
+		return value, nil
+	}
+
+	notFound := errors.Is(err, ErrConfigurationNotFound)
+	if notFound {
+		c.rememberConfiguration(nil, func(snapshotKey string) bool { return snapshotKey == key })
+	} else if snapshot, exists := c.snapshotValues()[key]; exists {
+		c.logFallback(err, "snapshot", logrus.Fields{"key": key})
+		return &snapshot, nil
+	}
+	if fallback, exists := os.LookupEnv(configDefaultEnv(key)); exists {
+		if notFound {
+			c.incrementFallbackRead()
+		} else {
+			c.logFallback(err, "environment", logrus.Fields{"key": key})
+		}
+		return &ConfigurationValue{Key: key, Value: fallback, Service: c.config.ServiceName}, nil
+	}
+	return nil, err
+}
+
+// GetConfigurationsByPrefix returns every key starting with prefix, such as
+// "exchange.fees.", by key, in one request. The values come from one read, so they
+// are consistent with each other, and are cached together: each key is then served
+// from the cache by GetConfiguration too. No matching key is an empty map. While the
+// service cannot be reached, the snapshot's keys under prefix are returned instead.
+func (c *ConfigurationClient) GetConfigurationsByPrefix(ctx context.Context, prefix string) (map[string]ConfigurationValue, error) {
+	values, err := c.fetchConfigurationsByPrefix(ctx, prefix)
+	underPrefix := func(key string) bool { return strings.HasPrefix(key, prefix) }
+	if err == nil {
+		c.rememberConfiguration(values, func(key string) bool {
+			_, current := values[key]
+			return underPrefix(key) && !current
+		})
+		return values, nil
+	}
+
+	snapshot := make(map[string]ConfigurationValue)
+	for key, value := range c.snapshotValues() {
+		if underPrefix(key) {
+			snapshot[key] = value
+		}
+	}
+	if len(snapshot) == 0 {
+		return nil, err
+	}
+	c.logFallback(err, "snapshot", logrus.Fields{"prefix": prefix})
+	return snapshot, nil
+}
+
+// configDefaultEnv names the environment variable holding key's default: key upper
+// cased with every character other than a letter or digit made an underscore
+func configDefaultEnv(key string) string {
+	return configDefaultEnvPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func (c *ConfigurationClient) logFallback(err error, source string, fields logrus.Fields) {
+	c.incrementFallbackRead()
+	c.logger.WithError(err).WithFields(fields).WithField("source", source).
+		Warn("Configuration service unavailable, using last-known-good configuration")
+}
+
+func (c *ConfigurationClient) incrementFallbackRead() {
+	c.metricsMutex.Lock()
+	c.metrics.FallbackReads++
+	c.metricsMutex.Unlock()
+}
+
+// snapshotValues returns a copy of the snapshot's values; none without a snapshot path
+func (c *ConfigurationClient) snapshotValues() map[string]ConfigurationValue {
+	path := c.config.ConfigSnapshotPath
+	if path == "" {
+		return nil
+	}
+	configSnapshotMutex.Lock()
+	defer configSnapshotMutex.Unlock()
+	snapshot := c.loadSnapshot(path)
+	values := make(map[string]ConfigurationValue, len(snapshot))
+	for key, value := range snapshot {
+		values[key] = value
+	}
+	return values
+}
+
+// rememberConfiguration records values in the snapshot and drops the keys forget
+// reports, writing the file only when that changes it
+func (c *ConfigurationClient) rememberConfiguration(values map[string]ConfigurationValue, forget func(key string) bool) {
+	path := c.config.ConfigSnapshotPath
+	if path == "" {
+		return
+	}
+	configSnapshotMutex.Lock()
+	defer configSnapshotMutex.Unlock()
+	snapshot := c.loadSnapshot(path)
+
+	changed := false
+	for key, value := range values {
+		if previous, exists := snapshot[key]; !exists || !reflect.DeepEqual(previous.Value, value.Value) {
+			snapshot[key] = value
+			changed = true
+		}
+	}
+	if forget != nil {
+		for key := range snapshot {
+			if forget(key) {
+				delete(snapshot, key)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := writeSnapshot(path, snapshot); err != nil {
+		c.logger.WithError(err).WithField("path", path).Warn("Failed to write configuration snapshot")
+	}
+}
+
+// loadSnapshot returns the values kept at path, reading the file on first use; callers
+// hold configSnapshotMutex. A missing or unreadable file is an empty snapshot.
+func (c *ConfigurationClient) loadSnapshot(path string) map[string]ConfigurationValue {
+	if snapshot, loaded := configSnapshots[path]; loaded {
+		return snapshot
+	}
+	snapshot := make(map[string]ConfigurationValue)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		c.logger.WithError(err).WithField("path", path).Warn("Ignoring unreadable configuration snapshot")
+	default:
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			c.logger.WithError(err).WithField("path", path).Warn("Ignoring corrupt configuration snapshot")
+			snapshot = make(map[string]ConfigurationValue)
+		}
+	}
+	configSnapshots[path] = snapshot
+	return snapshot
+}
+
+// writeSnapshot replaces the file at path with snapshot. The file is written beside
+// it and renamed into place, so a crash never leaves half a snapshot, and is only
+// readable by its owner, as it may hold TLS keys.
+func writeSnapshot(path string, snapshot map[string]ConfigurationValue) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration snapshot: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create configuration snapshot: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write configuration snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write configuration snapshot: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0o600); err != nil {
+		return fmt.Errorf("failed to write configuration snapshot: %w", err)
+	}
+	return os.Rename(file.Name(), path)
+}
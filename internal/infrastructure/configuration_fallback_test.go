@@ -0,0 +1,153 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestConfigurationClient_Fallback(t *testing.T) {
+	// newSnapshotClient returns a client keeping its snapshot at path, reading values
+	// from a configuration server the test can take down
+	newSnapshotClient := func(t *testing.T, path string, values map[string]interface{}, opts ConfigurationClientOptions) (*ConfigurationClient, *httptest.Server) {
+		server := httptest.NewServer(&configurationServer{values: values})
+		t.Cleanup(server.Close)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		if opts.BaseURL == "" {
+			opts.BaseURL = server.URL
+		}
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator", ConfigSnapshotPath: path}, logger, opts)
+		return client, server
+	}
+	// restart forgets the snapshots read so far, as a new process would
+	restart := func() {
+		configSnapshotMutex.Lock()
+		defer configSnapshotMutex.Unlock()
+		configSnapshots = make(map[string]map[string]ConfigurationValue)
+	}
+
+	t.Run("serves_the_last_known_good_value_after_a_restart_while_the_service_is_down", func(t *testing.T) {
+		// Given: A value read while the service was up, then a restart during an outage
+		path := filepath.Join(t.TempDir(), "configuration.json")
+		client, server := newSnapshotClient(t, path, map[string]interface{}{"exchange.fees.maker": 0.001}, ConfigurationClientOptions{})
+		if _, err := client.GetConfiguration(context.Background(), "exchange.fees.maker"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		server.Close()
+		restart()
+		restarted, _ := newSnapshotClient(t, path, nil, ConfigurationClientOptions{BaseURL: server.URL})
+
+		// When: The key is read
+		value, err := restarted.GetConfiguration(context.Background(), "exchange.fees.maker")
+
+		// Then: The snapshot's value is served, and counted
+		if err != nil || value.Value != 0.001 {
+			t.Errorf("Expected the snapshot's 0.001, got %v, %v", value, err)
+		}
+		if reads := restarted.GetMetrics().FallbackReads; reads != 1 {
+			t.Errorf("Expected 1 fallback read, got %d", reads)
+		}
+	})
+
+	t.Run("keeps_the_snapshot_readable_only_by_its_owner", func(t *testing.T) {
+		// Given: A client with a snapshot path
+		path := filepath.Join(t.TempDir(), "configuration.json")
+		client, _ := newSnapshotClient(t, path, map[string]interface{}{"tls.key": "PRIVATE"}, ConfigurationClientOptions{})
+
+		// When: A value is read
+		client.GetConfiguration(context.Background(), "tls.key")
+
+		// Then: The snapshot holds it, with owner-only permissions
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Expected a snapshot, got %v", err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("Expected mode 0600, got %o", mode)
+		}
+		var snapshot map[string]ConfigurationValue
+		data, _ := os.ReadFile(path)
+		if err := json.Unmarshal(data, &snapshot); err != nil || snapshot["tls.key"].Value != "PRIVATE" {
+			t.Errorf("Expected the value in the snapshot, got %s (%v)", data, err)
+		}
+	})
+
+	t.Run("falls_back_to_the_environment_default", func(t *testing.T) {
+		// Given: A down service, no snapshot, and an environment default
+		t.Setenv("CONFIG_DEFAULT_EXCHANGE_FEES_TAKER", "0.002")
+		client, server := newSnapshotClient(t, "", nil, ConfigurationClientOptions{})
+		server.Close()
+
+		// When: The key is read
+		rate, err := client.GetConfiguration(context.Background(), "exchange.fees.taker")
+
+		// Then: The environment's value is served
+		if err != nil || rate.Value != "0.002" {
+			t.Errorf("Expected the environment's 0.002, got %v, %v", rate, err)
+		}
+	})
+
+	t.Run("does_not_serve_a_snapshot_value_the_service_no_longer_holds", func(t *testing.T) {
+		// Given: A snapshotted key since removed from the service
+		path := filepath.Join(t.TempDir(), "configuration.json")
+		source := &configurationServer{values: map[string]interface{}{"exchange.halts": true}}
+		server := httptest.NewServer(source)
+		defer server.Close()
+		client, _ := newSnapshotClient(t, path, nil, ConfigurationClientOptions{BaseURL: server.URL})
+		client.Setting(context.Background(), "exchange.halts")
+		source.mu.Lock()
+		delete(source.values, "exchange.halts")
+		source.mu.Unlock()
+
+		// When: The key is read again, past the cache
+		_, err := client.Setting(context.Background(), "exchange.halts")
+
+		// Then: It is not found, and gone from the snapshot
+		if !errors.Is(err, ErrConfigurationNotFound) {
+			t.Errorf("Expected ErrConfigurationNotFound, got %v", err)
+		}
+		if _, exists := client.snapshotValues()["exchange.halts"]; exists {
+			t.Error("Expected the key to be dropped from the snapshot")
+		}
+	})
+
+	t.Run("serves_a_prefix_from_the_snapshot_while_the_service_is_down", func(t *testing.T) {
+		// Given: A prefix fetched while the service was up, then an outage
+		path := filepath.Join(t.TempDir(), "configuration.json")
+		client, server := newSnapshotClient(t, path, map[string]interface{}{
+			"exchange.fees.maker": 0.001,
+			"exchange.fees.taker": 0.002,
+		}, ConfigurationClientOptions{CacheTTL: -1})
+		client.GetConfigurationsByPrefix(context.Background(), "exchange.fees.")
+		server.Close()
+
+		// When: The prefix is fetched again
+		values, err := client.GetConfigurationsByPrefix(context.Background(), "exchange.fees.")
+
+		// Then: The snapshot's keys under it are served
+		if err != nil || len(values) != 2 || values["exchange.fees.taker"].Value != 0.002 {
+			t.Errorf("Expected both fee keys, got %v, %v", values, err)
+		}
+		if reads := client.GetMetrics().FallbackReads; reads != 1 {
+			t.Errorf("Expected 1 fallback read, got %d", reads)
+		}
+	})
+
+	t.Run("names_environment_defaults_after_the_key", func(t *testing.T) {
+		// Given/When/Then: Separators become underscores and letters are upper cased
+		if name := configDefaultEnv("exchange.rate-limits/v2"); name != "CONFIG_DEFAULT_EXCHANGE_RATE_LIMITS_V2" {
+			t.Errorf("Expected CONFIG_DEFAULT_EXCHANGE_RATE_LIMITS_V2, got %s", name)
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Setting reads key from the configuration service past the cache, so callers
+// polling it see changes as soon as they are made. A string value is taken to hold
+// the JSON itself.
+func (c *ConfigurationClient) Setting(ctx context.Context, key string) (json.RawMessage, error) {
+	c.invalidateCache(key)
+	value, err := c.GetConfiguration(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if text, ok := value.Value.(string); ok {
+		return json.RawMessage(text), nil
+	}
+	return json.Marshal(value.Value)
+}
@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// defaultConfigWatchInterval is how often watched keys are read when no interval is set
+const defaultConfigWatchInterval = 30 * time.Second
+
+var _ ports.SettingWatcher = (*ConfigurationClient)(nil)
+
+// settingSubscription is one subscriber to a watched key and the value it last got
+type settingSubscription struct {
+	apply   func(ctx context.Context, value json.RawMessage) error
+	applied json.RawMessage
+	seen    bool
+}
+
+// Subscribe calls apply with key's value once Watch first reads it, and again each
+// time it changes, until unsubscribe is called
+func (c *ConfigurationClient) Subscribe(key string, apply func(ctx context.Context, value json.RawMessage) error) func() {
+	c.watchMutex.Lock()
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]map[int]*settingSubscription)
+	}
+	if c.subscriptions[key] == nil {
+		c.subscriptions[key] = make(map[int]*settingSubscription)
+	}
+	c.subscriptionID++
+	id := c.subscriptionID
+	c.subscriptions[key][id] = &settingSubscription{apply: apply}
+	trigger := c.watchSignal()
+	c.watchMutex.Unlock()
+
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+	return func() {
+		c.watchMutex.Lock()
+		defer c.watchMutex.Unlock()
+		delete(c.subscriptions[key], id)
+		if len(c.subscriptions[key]) == 0 {
+			delete(c.subscriptions, key)
+		}
+	}
+}
+
+// Watch reads the subscribed keys now, as keys are subscribed and every interval
+// until ctx is done, and tells each subscriber of a value that differs from the one
+// it last got. A value a subscriber fails to apply is not offered to it again until
+// it changes; a key that cannot be read keeps its last value.
+func (c *ConfigurationClient) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultConfigWatchInterval
+	}
+	c.watchMutex.Lock()
+	trigger := c.watchSignal()
+	c.watchMutex.Unlock()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.refreshSubscriptions(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchSignal returns the channel that wakes Watch; callers hold watchMutex
+func (c *ConfigurationClient) watchSignal() chan struct{} {
+	if c.watchTrigger == nil {
+		c.watchTrigger = make(chan struct{}, 1)
+	}
+	return c.watchTrigger
+}
+
+// refreshSubscriptions reads each watched key and calls the subscribers it changed for
+func (c *ConfigurationClient) refreshSubscriptions(ctx context.Context) {
+	c.watchMutex.Lock()
+	keys := make([]string, 0, len(c.subscriptions))
+	for key := range c.subscriptions {
+		keys = append(keys, key)
+	}
+	c.watchMutex.Unlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := c.Setting(ctx, key)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.WithError(err).WithField("key", key).Warn("Failed to read watched configuration key")
+			}
+			continue
+		}
+
+		c.watchMutex.Lock()
+		var due []*settingSubscription
+		for _, subscription := range c.subscriptions[key] {
+			if !subscription.seen || !bytes.Equal(subscription.applied, value) {
+				subscription.applied, subscription.seen = value, true
+				due = append(due, subscription)
+			}
+		}
+		c.watchMutex.Unlock()
+		if len(due) == 0 {
+			continue
+		}
+
+		if metrics := c.config.GetMetricsPort(); metrics != nil {
+			metrics.IncCounter("configuration_changes_total", map[string]string{"key": key})
+		}
+		for _, subscription := range due {
+			if err := subscription.apply(ctx, value); err != nil {
+				c.logger.WithError(err).WithField("key", key).Warn("Failed to apply configuration change")
+				continue
+			}
+			c.logger.WithField("key", key).Info("Configuration change applied")
+		}
+	}
+}
@@ -0,0 +1,145 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// configurationServer serves configuration values a test can change, by key or by prefix
+type configurationServer struct {
+	mu       sync.Mutex
+	values   map[string]interface{}
+	requests int
+}
+
+func (s *configurationServer) set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *configurationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if prefix := r.URL.Query().Get("prefix"); r.URL.Path == "/api/v1/configuration" && prefix != "" {
+		response := ConfigurationResponse{Success: true}
+		for key, value := range s.values {
+			if strings.HasPrefix(key, prefix) {
+				response.Data = append(response.Data, ConfigurationValue{Key: key, Value: value})
+			}
+		}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/api/v1/configuration/")
+	value, exists := s.values[key]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(ConfigurationResponse{Success: true, Data: []ConfigurationValue{{Key: key, Value: value}}})
+}
+
+// appliedValues records the values a subscriber was called with
+type appliedValues struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func (a *appliedValues) apply(err error) func(ctx context.Context, value json.RawMessage) error {
+	return func(ctx context.Context, value json.RawMessage) error {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.values = append(a.values, string(value))
+		return err
+	}
+}
+
+func (a *appliedValues) count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.values)
+}
+
+func TestConfigurationClient_Watch(t *testing.T) {
+	newWatchedClient := func(t *testing.T, values map[string]interface{}) (*ConfigurationClient, *configurationServer) {
+		source := &configurationServer{values: values}
+		server := httptest.NewServer(source)
+		t.Cleanup(server.Close)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewConfigurationClientWithOptions(&config.Config{ServiceName: "exchange-simulator"}, logger, ConfigurationClientOptions{BaseURL: server.URL})
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go client.Watch(ctx, 5*time.Millisecond)
+		return client, source
+	}
+
+	t.Run("calls_subscribers_with_the_first_value_and_each_change", func(t *testing.T) {
+		// Given: A watched key holding a key weight of 100
+		client, source := newWatchedClient(t, map[string]interface{}{"exchange.rate_limits": `{"key_weight":100}`})
+		applied := &appliedValues{}
+		defer client.Subscribe("exchange.rate_limits", applied.apply(nil))()
+		waitFor(t, func() bool { return applied.count() == 1 })
+
+		// When: The value changes once, and is read several times over
+		source.set("exchange.rate_limits", `{"key_weight":200}`)
+		waitFor(t, func() bool { return applied.count() == 2 })
+		time.Sleep(30 * time.Millisecond)
+
+		// Then: The subscriber got the first value and the change, once each
+		applied.mu.Lock()
+		defer applied.mu.Unlock()
+		if len(applied.values) != 2 || applied.values[0] != `{"key_weight":100}` || applied.values[1] != `{"key_weight":200}` {
+			t.Errorf("Expected the first value then the change, got %v", applied.values)
+		}
+	})
+
+	t.Run("does_not_offer_a_rejected_value_again", func(t *testing.T) {
+		// Given: A subscriber that rejects the value it is given
+		client, _ := newWatchedClient(t, map[string]interface{}{"exchange.chaos": `{"degradation_mode":"sideways"}`})
+		applied := &appliedValues{}
+		defer client.Subscribe("exchange.chaos", applied.apply(errors.New("unknown mode")))()
+		waitFor(t, func() bool { return applied.count() == 1 })
+
+		// When: The unchanged value is read again
+		time.Sleep(30 * time.Millisecond)
+
+		// Then: It was only offered once
+		if count := applied.count(); count != 1 {
+			t.Errorf("Expected one attempt, got %d", count)
+		}
+	})
+
+	t.Run("stops_calling_once_unsubscribed", func(t *testing.T) {
+		// Given: A subscriber that has had the first value and unsubscribed
+		client, source := newWatchedClient(t, map[string]interface{}{"exchange.fees": `{"*":{"taker_fee_rate":0.002}}`})
+		applied := &appliedValues{}
+		unsubscribe := client.Subscribe("exchange.fees", applied.apply(nil))
+		waitFor(t, func() bool { return applied.count() == 1 })
+		unsubscribe()
+
+		// When: The value changes
+		source.set("exchange.fees", `{"*":{"taker_fee_rate":0.003}}`)
+		time.Sleep(30 * time.Millisecond)
+
+		// Then: The subscriber is not called
+		if count := applied.count(); count != 1 {
+			t.Errorf("Expected no call after unsubscribing, got %d calls", count)
+		}
+	})
+}
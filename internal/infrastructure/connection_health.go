@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// connectionHealthTimeout bounds the health check of one connection
+const connectionHealthTimeout = 2 * time.Second
+
+// monitorConnections health-checks every cached connection at HEALTH_CHECK_INTERVAL,
+// from the first connection until the manager closes, so stale connections are
+// replaced before a call finds them; callers hold connectionMutex
+func (m *InterServiceClientManager) monitorConnections() {
+	if m.monitoring || m.config.HealthCheckInterval <= 0 {
+		return
+	}
+	m.monitoring = true
+	go func() {
+		ticker := time.NewTicker(m.config.HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.checkConnections()
+			}
+		}
+	}()
+}
+
+// checkConnections health-checks each service's pool, closing the stale ones and
+// connecting again through service discovery
+func (m *InterServiceClientManager) checkConnections() {
+	m.connectionMutex.RLock()
+	pools := make(map[string]*connectionPool, len(m.connections))
+	for serviceName, pool := range m.connections {
+		pools[serviceName] = pool
+	}
+	m.connectionMutex.RUnlock()
+
+	for serviceName, pool := range pools {
+		err := m.checkPool(pool)
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			// An open breaker already keeps calls off the service; its probe decides
+			continue
+		}
+		m.replaceConnection(serviceName, pool, err)
+	}
+
+	m.connectionMutex.Lock()
+	m.updateActiveConnections(m.countConnections())
+	m.connectionMutex.Unlock()
+}
+
+// checkPool returns nil if any of pool's connections reports itself serving. Targets
+// without the health service are judged by the state of the connection alone.
+func (m *InterServiceClientManager) checkPool(pool *connectionPool) error {
+	var lastErr error
+	for _, conn := range pool.conns {
+		ctx, cancel := context.WithTimeout(m.ctx, connectionHealthTimeout)
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		cancel()
+		switch {
+		case status.Code(err) == codes.Unimplemented:
+			if usable(conn.GetState()) {
+				return nil
+			}
+			lastErr = fmt.Errorf("connection %s", conn.GetState())
+		case err != nil:
+			lastErr = err
+		case resp.Status != grpc_health_v1.HealthCheckResponse_SERVING:
+			lastErr = fmt.Errorf("target reports %s", resp.Status)
+		default:
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// replaceConnection closes serviceName's stale pool and connects again, to whichever
+// instance service discovery now picks, unless the pool was replaced meanwhile
+func (m *InterServiceClientManager) replaceConnection(serviceName string, pool *connectionPool, cause error) {
+	m.connectionMutex.Lock()
+	if current, exists := m.connections[serviceName]; !exists || current != pool {
+		m.connectionMutex.Unlock()
+		return
+	}
+	endpoint := pool.endpoint()
+	m.serviceDiscovery.ReportEndpointFailure(endpoint)
+	pool.Close()
+	delete(m.connections, serviceName)
+	m.updateActiveConnections(m.countConnections())
+	m.connectionMutex.Unlock()
+
+	// The client wraps the closed connection; the next request makes another
+	m.clientMutex.Lock()
+	delete(m.clients, serviceName)
+	m.clientMutex.Unlock()
+
+	if metrics := m.config.GetMetricsPort(); metrics != nil {
+		metrics.IncCounter("inter_service_stale_connections_total", map[string]string{"service": serviceName})
+	}
+	logger := m.logger.WithFields(logrus.Fields{"service": serviceName, "endpoint": endpoint})
+	logger.WithError(cause).Warn("Stale service connection closed")
+	if _, err := m.getOrCreateConnection(serviceName); err != nil {
+		logger.WithError(err).Warn("Failed to reconnect to service")
+		return
+	}
+	logger.Info("Service connection re-established")
+}
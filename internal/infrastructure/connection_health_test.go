@@ -0,0 +1,98 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"net"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// startCheckedServer starts a gRPC server whose health status the test sets
+func startCheckedServer(t *testing.T) (string, *health.Server) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen, got %v", err)
+	}
+	server := grpc.NewServer()
+	checks := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, checks)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), checks
+}
+
+func TestInterServiceClientManager_ConnectionHealth(t *testing.T) {
+	newManager := func(t *testing.T, endpoints ...string) *InterServiceClientManager {
+		discovery, mockRedis := newWatchedDiscovery(t)
+		discovery.strategy = StrategyLeastRecentFailure
+		for _, endpoint := range endpoints {
+			registerInstance(mockRedis, endpoint)
+		}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		manager := NewInterServiceClientManager(&config.Config{}, logger, discovery, &ConfigurationClient{})
+		t.Cleanup(func() { manager.Close() })
+		return manager
+	}
+	currentPool := func(manager *InterServiceClientManager) *connectionPool {
+		manager.connectionMutex.RLock()
+		defer manager.connectionMutex.RUnlock()
+		return manager.connections["target-service"]
+	}
+
+	t.Run("replaces_a_connection_whose_target_stops_serving", func(t *testing.T) {
+		// Given: A connection to one of two instances, and a client on it
+		first, firstChecks := startCheckedServer(t)
+		second, secondChecks := startCheckedServer(t)
+		manager := newManager(t, first, second)
+		pool, err := manager.getOrCreateConnection("target-service")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		manager.setClient("target-service", "client")
+		stale := pool.endpoint()
+		checks := map[string]*health.Server{first: firstChecks, second: secondChecks}
+
+		// When: That instance stops serving and the connections are checked
+		checks[stale].SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		manager.checkConnections()
+
+		// Then: The manager reconnects to the other instance and drops the stale client
+		replaced := currentPool(manager)
+		if replaced == nil || replaced.endpoint() == stale {
+			t.Fatalf("Expected a connection away from %s, got %v", stale, replaced)
+		}
+		if _, exists := manager.getClient("target-service"); exists {
+			t.Error("Expected the client on the stale connection to be dropped")
+		}
+		if active := manager.GetMetrics().ActiveConnections; active != 1 {
+			t.Errorf("Expected 1 active connection, got %d", active)
+		}
+	})
+
+	t.Run("keeps_a_serving_connection", func(t *testing.T) {
+		// Given: A connection to a serving instance
+		endpoint, _ := startCheckedServer(t)
+		manager := newManager(t, endpoint)
+		pool, err := manager.getOrCreateConnection("target-service")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: The connections are checked
+		manager.checkConnections()
+
+		// Then: The same connection is kept
+		if currentPool(manager) != pool {
+			t.Error("Expected the serving connection to be kept")
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// connectionPool spreads calls to one target round-robin across several connections,
+// so a burst of calls on one HTTP/2 connection does not hold up the others
+type connectionPool struct {
+	conns []*grpc.ClientConn
+	next  atomic.Uint64
+}
+
+var _ grpc.ClientConnInterface = (*connectionPool)(nil)
+
+func (p *connectionPool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return p.pick().Invoke(ctx, method, args, reply, opts...)
+}
+
+func (p *connectionPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick().NewStream(ctx, desc, method, opts...)
+}
+
+// pick returns the next usable connection in turn, skipping any that are failing
+// while others are not
+func (p *connectionPool) pick() *grpc.ClientConn {
+	start := p.next.Add(1) - 1
+	for i := 0; i < len(p.conns); i++ {
+		conn := p.conns[(start+uint64(i))%uint64(len(p.conns))]
+		if usable(conn.GetState()) {
+			return conn
+		}
+	}
+	return p.conns[start%uint64(len(p.conns))]
+}
+
+// endpoint is the target every connection dials
+func (p *connectionPool) endpoint() string {
+	return p.conns[0].Target()
+}
+
+// healthy reports whether any connection can carry calls
+func (p *connectionPool) healthy() bool {
+	for _, conn := range p.conns {
+		if usable(conn.GetState()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *connectionPool) Close() error {
+	var firstErr error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func usable(state connectivity.State) bool {
+	return state == connectivity.Ready || state == connectivity.Idle
+}
+
+// dialPool opens size connections to endpoint, closing any already opened if one fails
+func dialPool(ctx context.Context, endpoint string, size int, opts ...grpc.DialOption) (*connectionPool, error) {
+	pool := &connectionPool{conns: make([]*grpc.ClientConn, 0, size)}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.DialContext(ctx, endpoint, opts...)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+	return pool, nil
+}
+
+// keepaliveOption pings idle connections so dead peers are noticed before the next
+// call; nil when keepalive is disabled. Targets must permit pings at this interval,
+// or they close the connection with "too_many_pings".
+func keepaliveOption(cfg *config.Config) grpc.DialOption {
+	if cfg.ClientKeepaliveTime <= 0 {
+		return nil
+	}
+	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                cfg.ClientKeepaliveTime,
+		Timeout:             cfg.ClientKeepaliveTimeout,
+		PermitWithoutStream: true,
+	})
+}
+
+// parsePoolSizes reads per-service pool sizes in the GRPC_CLIENT_POOL_SIZES form
+// "service=size,...", e.g. "audit-correlator=4"
+func parsePoolSizes(spec string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		service, sizeSpec, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(service) == "" {
+			return nil, fmt.Errorf("invalid pool size %q: expected service=size", entry)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(sizeSpec))
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid pool size %q: size must be a positive integer", entry)
+		}
+		sizes[strings.TrimSpace(service)] = size
+	}
+	return sizes, nil
+}
@@ -0,0 +1,119 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func startHealthServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen, got %v", err)
+	}
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestConnectionPool(t *testing.T) {
+	t.Run("round_robins_calls_across_connections", func(t *testing.T) {
+		// Given: A pool of three connections to a healthy target
+		endpoint := startHealthServer(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		pool, err := dialPool(ctx, endpoint, 3,
+			grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer pool.Close()
+
+		// When: Connections are picked in turn
+		seen := make(map[*grpc.ClientConn]int)
+		for i := 0; i < 6; i++ {
+			seen[pool.pick()]++
+		}
+
+		// Then: Each connection takes an equal share, and calls succeed through the pool
+		if len(seen) != 3 {
+			t.Fatalf("Expected all 3 connections to be used, got %d", len(seen))
+		}
+		for _, calls := range seen {
+			if calls != 2 {
+				t.Errorf("Expected 2 picks per connection, got %v", seen)
+			}
+		}
+		resp, err := grpc_health_v1.NewHealthClient(pool).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		if err != nil || resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Errorf("Expected a serving health check through the pool, got %v, %v", resp, err)
+		}
+	})
+
+	t.Run("skips_closed_connections", func(t *testing.T) {
+		endpoint := startHealthServer(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		pool, _ := dialPool(ctx, endpoint, 2,
+			grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+		defer pool.Close()
+
+		pool.conns[0].Close()
+
+		for i := 0; i < 4; i++ {
+			if conn := pool.pick(); conn != pool.conns[1] {
+				t.Fatal("Expected every pick to skip the closed connection")
+			}
+		}
+		if !pool.healthy() {
+			t.Error("Expected the pool to stay healthy with one ready connection")
+		}
+	})
+}
+
+func TestInterServiceClientManager_PoolConfig(t *testing.T) {
+	t.Run("per_service_sizes_override_the_default", func(t *testing.T) {
+		cfg := &config.Config{ClientPoolSize: 2, ClientPoolSizes: "audit-correlator=4"}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		manager := NewInterServiceClientManager(cfg, logger, &ServiceDiscoveryClient{}, &ConfigurationClient{})
+
+		if size := manager.poolSize("audit-correlator"); size != 4 {
+			t.Errorf("Expected 4 connections for audit-correlator, got %d", size)
+		}
+		if size := manager.poolSize("custodian-simulator"); size != 2 {
+			t.Errorf("Expected the default of 2 for custodian-simulator, got %d", size)
+		}
+	})
+
+	t.Run("rejects_malformed_sizes", func(t *testing.T) {
+		for _, spec := range []string{"audit-correlator", "audit-correlator=0", "=2", "audit-correlator=x"} {
+			if _, err := parsePoolSizes(spec); err == nil {
+				t.Errorf("Expected %q to be rejected", spec)
+			}
+		}
+	})
+
+	t.Run("keepalive_is_off_unless_configured", func(t *testing.T) {
+		if keepaliveOption(&config.Config{}) != nil {
+			t.Error("Expected no keepalive option by default")
+		}
+		if keepaliveOption(&config.Config{ClientKeepaliveTime: 30 * time.Second}) == nil {
+			t.Error("Expected a keepalive option when a ping interval is set")
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+// CustodianSettlementGateway submits settlement batches to the custodian-simulator
+// found through service discovery, connecting on first use
+type CustodianSettlementGateway struct {
+	clients *InterServiceClientManager
+}
+
+func NewCustodianSettlementGateway(clients *InterServiceClientManager) *CustodianSettlementGateway {
+	return &CustodianSettlementGateway{clients: clients}
+}
+
+// Submit sends batch to the custodian and returns its acknowledgements
+func (g *CustodianSettlementGateway) Submit(ctx context.Context, batch settlement.Batch) ([]settlement.Ack, error) {
+	client, err := g.clients.GetCustodianSimulatorClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.ProcessSettlement(ctx, batch)
+}
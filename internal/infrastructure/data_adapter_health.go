@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/adapters"
+)
+
+const dataAdapterServiceName = "data-adapter" // Names the data adapter in ClientErrors
+
+// dataAdapterProbeTTL is how long the key PingDataAdapter writes outlives it
+const dataAdapterProbeTTL = time.Minute
+
+// PingDataAdapter checks the data adapter answers: through its own HealthCheck when
+// it has one, otherwise by writing key to its cache. A nil adapter, as in stub
+// mode, is unavailable.
+func PingDataAdapter(ctx context.Context, adapter adapters.DataAdapter, key string) error {
+	if adapter == nil {
+		return newClientError(ErrUnavailable, dataAdapterServiceName, "data adapter not connected", nil)
+	}
+	if checked, ok := adapter.(interface{ HealthCheck(context.Context) error }); ok {
+		if err := checked.HealthCheck(ctx); err != nil {
+			return newClientError(transportErrorKind(err), dataAdapterServiceName, "health check failed", err)
+		}
+		return nil
+	}
+	cache := adapter.CacheRepository()
+	if cache == nil {
+		return newClientError(ErrUnavailable, dataAdapterServiceName, "data adapter has no cache", nil)
+	}
+	if err := cache.Set(ctx, key, time.Now().UTC().Format(time.RFC3339Nano), dataAdapterProbeTTL); err != nil {
+		return newClientError(transportErrorKind(err), dataAdapterServiceName, "cache write failed", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// FileLog appends matching engine events to a file as JSON lines
+type FileLog struct {
+	path    string
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// OpenFileLog opens path for appending, creating it if needed
+func OpenFileLog(path string) (*FileLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	return &FileLog{path: path, file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (l *FileLog) Append(event matching.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.encoder.Encode(event)
+}
+
+// ReadAll reads back every event appended so far
+func (l *FileLog) ReadAll() ([]matching.Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ReadFile(l.path)
+}
+
+// Redact rewrites recorded events through a temporary file swapped in atomically,
+// then reopens the log for appending
+func (l *FileLog) Redact(rewrite func(matching.Event) (matching.Event, bool)) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events, err := ReadFile(l.path)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for i, event := range events {
+		if rewritten, ok := rewrite(event); ok {
+			events[i] = rewritten
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create redacted event log: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	encoder := json.NewEncoder(temp)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			temp.Close()
+			return 0, fmt.Errorf("failed to write redacted event log: %w", err)
+		}
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return 0, fmt.Errorf("failed to write redacted event log: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write redacted event log: %w", err)
+	}
+	if err := os.Rename(temp.Name(), l.path); err != nil {
+		return 0, fmt.Errorf("failed to replace event log: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return changed, fmt.Errorf("failed to reopen event log: %w", err)
+	}
+	l.file.Close()
+	l.file = file
+	l.encoder = json.NewEncoder(file)
+	return changed, nil
+}
+
+func (l *FileLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// ReadFile loads every event from a JSON lines log; a missing file is an empty log
+func ReadFile(path string) ([]matching.Event, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []matching.Event{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	events := make([]matching.Event, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event matching.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid event on line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,95 @@
+//go:build unit
+
+package eventlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestFileLog(t *testing.T) {
+	t.Run("round_trips_events_across_reopen", func(t *testing.T) {
+		// Given: A log written in two sessions
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+		first, err := OpenFileLog(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		first.Append(matching.Event{Sequence: 1, Type: matching.EventBookAdded, Time: at, Symbol: "BTC-USD", ReferencePrice: 100})
+		first.Close()
+
+		second, _ := OpenFileLog(path)
+		second.Append(matching.Event{
+			Sequence: 2,
+			Type:     matching.EventOrderSubmitted,
+			Time:     at,
+			OrderID:  "ord-1",
+			Order:    &models.Order{Symbol: "BTC-USD", Side: models.SideBuy, Quantity: 1, Price: 99},
+		})
+		second.Close()
+
+		// When: The log is read back
+		events, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both events survive with their payloads
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, got %d", len(events))
+		}
+		if !events[0].Time.Equal(at) || events[0].ReferencePrice != 100 {
+			t.Errorf("Unexpected first event: %+v", events[0])
+		}
+		if events[1].Order == nil || events[1].Order.Price != 99 {
+			t.Errorf("Expected order payload, got %+v", events[1])
+		}
+	})
+
+	t.Run("missing_file_is_empty_log", func(t *testing.T) {
+		events, err := ReadFile(filepath.Join(t.TempDir(), "missing.jsonl"))
+		if err != nil || len(events) != 0 {
+			t.Errorf("Expected empty log, got %d events, err %v", len(events), err)
+		}
+	})
+
+	t.Run("reports_corrupt_lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		os.WriteFile(path, []byte("{\"sequence\":1}\nnot-json\n"), 0o644)
+
+		if _, err := ReadFile(path); err == nil {
+			t.Error("Expected error for corrupt line")
+		}
+	})
+
+	t.Run("redacts_events_and_keeps_appending", func(t *testing.T) {
+		// Given: A log holding an order for an account
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		log, _ := OpenFileLog(path)
+		defer log.Close()
+		log.Append(matching.Event{Sequence: 1, Type: matching.EventOrderSubmitted, Order: &models.Order{AccountID: "acct-1"}})
+
+		// When: The account is redacted and another event is appended
+		redacted, err := log.Redact(matching.AnonymizeAccount("acct-1", "anon-1"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		log.Append(matching.Event{Sequence: 2, Type: matching.EventAccountPurged, AccountAlias: "anon-1"})
+
+		// Then: The file holds the alias and both events
+		events, _ := ReadFile(path)
+		if redacted != 1 || len(events) != 2 {
+			t.Fatalf("Expected 1 redaction and 2 events, got %d and %d", redacted, len(events))
+		}
+		if events[0].Order.AccountID != "anon-1" {
+			t.Errorf("Expected anonymized order, got %+v", events[0].Order)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// RedisListClient is the subset of the Redis client used by RedisLog
+type RedisListClient interface {
+	RPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	LSet(ctx context.Context, key string, index int64, value interface{}) *redis.StatusCmd
+	Close() error
+}
+
+// RedisLog appends matching engine events to a Redis list as JSON
+type RedisLog struct {
+	client  RedisListClient
+	key     string
+	timeout time.Duration
+}
+
+func NewRedisLog(client RedisListClient, key string, timeout time.Duration) *RedisLog {
+	return &RedisLog{client: client, key: key, timeout: timeout}
+}
+
+func (l *RedisLog) Append(event matching.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+	if err := l.client.RPush(ctx, l.key, data).Err(); err != nil {
+		return fmt.Errorf("failed to append event to redis: %w", err)
+	}
+	return nil
+}
+
+// ReadAll reads back every event in the list
+func (l *RedisLog) ReadAll() ([]matching.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	values, err := l.client.LRange(ctx, l.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events from redis: %w", err)
+	}
+
+	events := make([]matching.Event, 0, len(values))
+	for i, value := range values {
+		var event matching.Event
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			return nil, fmt.Errorf("invalid event at index %d: %w", i, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Redact rewrites recorded events by index; concurrent appends land past the
+// indexes being rewritten, so they are unaffected
+func (l *RedisLog) Redact(rewrite func(matching.Event) (matching.Event, bool)) (int, error) {
+	events, err := l.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	changed := 0
+	for i, event := range events {
+		rewritten, ok := rewrite(event)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(rewritten)
+		if err != nil {
+			return changed, fmt.Errorf("failed to encode event: %w", err)
+		}
+		if err := l.client.LSet(ctx, l.key, int64(i), data).Err(); err != nil {
+			return changed, fmt.Errorf("failed to redact event at index %d: %w", i, err)
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+func (l *RedisLog) Close() error {
+	return l.client.Close()
+}
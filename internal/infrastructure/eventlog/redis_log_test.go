@@ -0,0 +1,85 @@
+//go:build unit
+
+package eventlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+type mockListClient struct {
+	lists map[string][]string
+}
+
+func (m *mockListClient) RPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	for _, value := range values {
+		m.lists[key] = append(m.lists[key], string(value.([]byte)))
+	}
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(int64(len(m.lists[key])))
+	return cmd
+}
+
+func (m *mockListClient) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(ctx)
+	cmd.SetVal(append([]string(nil), m.lists[key]...))
+	return cmd
+}
+
+func (m *mockListClient) LSet(ctx context.Context, key string, index int64, value interface{}) *redis.StatusCmd {
+	m.lists[key][index] = string(value.([]byte))
+	cmd := redis.NewStatusCmd(ctx)
+	cmd.SetVal("OK")
+	return cmd
+}
+
+func (m *mockListClient) Close() error {
+	return nil
+}
+
+func TestRedisLog(t *testing.T) {
+	t.Run("appends_and_reads_back_in_order", func(t *testing.T) {
+		// Given: A Redis log backed by a mock client
+		client := &mockListClient{lists: make(map[string][]string)}
+		log := NewRedisLog(client, "exchange-simulator:test:event-log", time.Second)
+
+		// When: Two events are appended
+		log.Append(matching.Event{Sequence: 1, Type: matching.EventBookAdded, Symbol: "BTC-USD"})
+		log.Append(matching.Event{Sequence: 2, Type: matching.EventTradingHalted, Symbol: "BTC-USD"})
+
+		// Then: They are read back in order
+		events, err := log.ReadAll()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(events) != 2 || events[0].Sequence != 1 || events[1].Type != matching.EventTradingHalted {
+			t.Errorf("Unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("redacts_events_by_index", func(t *testing.T) {
+		// Given: A Redis log holding orders for two accounts
+		client := &mockListClient{lists: make(map[string][]string)}
+		log := NewRedisLog(client, "exchange-simulator:test:event-log", time.Second)
+		log.Append(matching.Event{Sequence: 1, Type: matching.EventOrderSubmitted, Order: &models.Order{AccountID: "acct-1"}})
+		log.Append(matching.Event{Sequence: 2, Type: matching.EventOrderSubmitted, Order: &models.Order{AccountID: "acct-2"}})
+
+		// When: One account is redacted
+		redacted, err := log.Redact(matching.AnonymizeAccount("acct-1", "anon-1"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Only its event is rewritten
+		events, _ := log.ReadAll()
+		if redacted != 1 || events[0].Order.AccountID != "anon-1" || events[1].Order.AccountID != "acct-2" {
+			t.Errorf("Unexpected redaction (%d): %+v, %+v", redacted, events[0].Order, events[1].Order)
+		}
+	})
+}
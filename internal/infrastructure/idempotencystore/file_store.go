@@ -0,0 +1,114 @@
+package idempotencystore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+)
+
+// FileStore appends idempotency records to a file as JSON lines
+type FileStore struct {
+	path    string
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// OpenFileStore opens path for appending, creating it if needed
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency store: %w", err)
+	}
+	return &FileStore{path: path, file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (s *FileStore) Append(record idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(record)
+}
+
+// Load reads back every record; a missing file holds none
+func (s *FileStore) Load() ([]idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []idempotency.Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency store: %w", err)
+	}
+	defer file.Close()
+
+	records := make([]idempotency.Record, 0)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record idempotency.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid idempotency record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read idempotency store: %w", err)
+	}
+	return records, nil
+}
+
+// Replace rewrites the file with records through a temporary file swapped in
+// atomically, then reopens it for appending
+func (s *FileStore) Replace(records []idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create compacted idempotency store: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	encoder := json.NewEncoder(temp)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			temp.Close()
+			return fmt.Errorf("failed to write compacted idempotency store: %w", err)
+		}
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write compacted idempotency store: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write compacted idempotency store: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace idempotency store: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen idempotency store: %w", err)
+	}
+	s.file.Close()
+	s.file = file
+	s.encoder = json.NewEncoder(file)
+	return nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,66 @@
+//go:build unit
+
+package idempotencystore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+)
+
+func TestFileStore(t *testing.T) {
+	seenAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("survives_reopen", func(t *testing.T) {
+		// Given: A store with two records
+		path := filepath.Join(t.TempDir(), "idempotency.jsonl")
+		store, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		store.Append(idempotency.Record{Key: "k1", Fingerprint: "place|a", OrderID: "ord-1", SeenAt: seenAt})
+		store.Append(idempotency.Record{Key: "k2", Fingerprint: "cancel|ord-1", OrderID: "ord-1", SeenAt: seenAt})
+		store.Close()
+
+		// When: It is reopened, as after a restart
+		reopened, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer reopened.Close()
+		records, err := reopened.Load()
+
+		// Then: Both records come back in order
+		if err != nil || len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %+v, err %v", records, err)
+		}
+		if records[0].Key != "k1" || records[1].OrderID != "ord-1" || !records[0].SeenAt.Equal(seenAt) {
+			t.Errorf("Unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("replace_compacts_and_keeps_appending", func(t *testing.T) {
+		dir := t.TempDir()
+		store, _ := OpenFileStore(filepath.Join(dir, "idempotency.jsonl"))
+		defer store.Close()
+		store.Append(idempotency.Record{Key: "old"})
+		store.Append(idempotency.Record{Key: "live"})
+
+		if err := store.Replace([]idempotency.Record{{Key: "live"}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		store.Append(idempotency.Record{Key: "new"})
+
+		records, _ := store.Load()
+		if len(records) != 2 || records[0].Key != "live" || records[1].Key != "new" {
+			t.Errorf("Expected live then new, got %+v", records)
+		}
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("Expected only the store file, got %d entries", len(entries))
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package idempotencystore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+)
+
+// RedisListClient is the subset of the Redis client used by RedisStore
+type RedisListClient interface {
+	RPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Rename(ctx context.Context, key, newkey string) *redis.StatusCmd
+}
+
+// RedisStore appends idempotency records to a Redis list as JSON
+type RedisStore struct {
+	client  RedisListClient
+	key     string
+	timeout time.Duration
+}
+
+func NewRedisStore(client RedisListClient, key string, timeout time.Duration) *RedisStore {
+	return &RedisStore{client: client, key: key, timeout: timeout}
+}
+
+func (s *RedisStore) Append(record idempotency.Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.client.RPush(ctx, s.key, data).Err(); err != nil {
+		return fmt.Errorf("failed to append idempotency record to redis: %w", err)
+	}
+	return nil
+}
+
+// Load reads back every record in the list
+func (s *RedisStore) Load() ([]idempotency.Record, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	values, err := s.client.LRange(ctx, s.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency records from redis: %w", err)
+	}
+
+	records := make([]idempotency.Record, 0, len(values))
+	for i, value := range values {
+		var record idempotency.Record
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, fmt.Errorf("invalid idempotency record at index %d: %w", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Replace builds the compacted list under a scratch key and renames it over the
+// live one, so readers never see a partial list
+func (s *RedisStore) Replace(records []idempotency.Record) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if len(records) == 0 {
+		if err := s.client.Del(ctx, s.key).Err(); err != nil {
+			return fmt.Errorf("failed to clear idempotency records in redis: %w", err)
+		}
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode idempotency record: %w", err)
+		}
+		values = append(values, data)
+	}
+
+	scratch := s.key + ":compacting"
+	if err := s.client.Del(ctx, scratch).Err(); err != nil {
+		return fmt.Errorf("failed to compact idempotency records in redis: %w", err)
+	}
+	if err := s.client.RPush(ctx, scratch, values...).Err(); err != nil {
+		return fmt.Errorf("failed to compact idempotency records in redis: %w", err)
+	}
+	if err := s.client.Rename(ctx, scratch, s.key).Err(); err != nil {
+		return fmt.Errorf("failed to replace idempotency records in redis: %w", err)
+	}
+	return nil
+}
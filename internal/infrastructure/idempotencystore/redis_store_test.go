@@ -0,0 +1,83 @@
+//go:build unit
+
+package idempotencystore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+)
+
+type mockListClient struct {
+	lists map[string][]string
+}
+
+func (m *mockListClient) RPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	for _, value := range values {
+		m.lists[key] = append(m.lists[key], string(value.([]byte)))
+	}
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(int64(len(m.lists[key])))
+	return cmd
+}
+
+func (m *mockListClient) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(ctx)
+	cmd.SetVal(append([]string(nil), m.lists[key]...))
+	return cmd
+}
+
+func (m *mockListClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	for _, key := range keys {
+		delete(m.lists, key)
+	}
+	return redis.NewIntCmd(ctx)
+}
+
+func (m *mockListClient) Rename(ctx context.Context, key, newkey string) *redis.StatusCmd {
+	m.lists[newkey] = m.lists[key]
+	delete(m.lists, key)
+	cmd := redis.NewStatusCmd(ctx)
+	cmd.SetVal("OK")
+	return cmd
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Run("appends_and_compacts", func(t *testing.T) {
+		// Given: A Redis store backed by a mock client holding two records
+		client := &mockListClient{lists: make(map[string][]string)}
+		store := NewRedisStore(client, "exchange-simulator:test:idempotency", time.Second)
+		store.Append(idempotency.Record{Key: "old", OrderID: "ord-1"})
+		store.Append(idempotency.Record{Key: "live", OrderID: "ord-2"})
+
+		// When: It is compacted down to the live record
+		if err := store.Replace([]idempotency.Record{{Key: "live", OrderID: "ord-2"}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Only that record is read back and the scratch key is gone
+		records, err := store.Load()
+		if err != nil || len(records) != 1 || records[0].Key != "live" {
+			t.Errorf("Expected only the live record, got %+v, err %v", records, err)
+		}
+		if _, exists := client.lists["exchange-simulator:test:idempotency:compacting"]; exists {
+			t.Error("Expected the scratch list to be renamed away")
+		}
+	})
+
+	t.Run("replace_with_nothing_clears_the_list", func(t *testing.T) {
+		client := &mockListClient{lists: make(map[string][]string)}
+		store := NewRedisStore(client, "idempotency", time.Second)
+		store.Append(idempotency.Record{Key: "old"})
+
+		store.Replace(nil)
+
+		if records, _ := store.Load(); len(records) != 0 {
+			t.Errorf("Expected no records, got %+v", records)
+		}
+	})
+}
@@ -3,36 +3,62 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"github.com/sirupsen/logrus"
 
+	auditv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1"
+	custodianv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/custodian/v1"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
 )
 
 // ServiceUnavailableError represents an error when a service is not available
 type ServiceUnavailableError struct {
 	ServiceName string
 	Message     string
+	Err         error // Cause, e.g. ErrCircuitOpen; may be nil
 }
 
 func (e *ServiceUnavailableError) Error() string {
 	return fmt.Sprintf("service %s unavailable: %s", e.ServiceName, e.Message)
 }
 
+func (e *ServiceUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is classifies the error as ErrUnavailable
+func (e *ServiceUnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
 // InterServiceClientManager manages gRPC clients for inter-service communication
 type InterServiceClientManager struct {
 	config              *config.Config
 	logger              *logrus.Logger
 	serviceDiscovery    *ServiceDiscoveryClient
 	configurationClient *ConfigurationClient
-	connections         map[string]*grpc.ClientConn
+	connections         map[string]*connectionPool
 	clients             map[string]interface{}
+	poolSizes           map[string]int  // Per-service overrides of config.ClientPoolSize
+	watched             map[string]bool // Services whose instances are watched; guarded by connectionMutex
+	monitoring          bool            // Connections are health-checked in the background; guarded by connectionMutex
+	breakers            map[string]*circuitBreaker
+	breakerConfig       BreakerConfig
+	breakerMutex        sync.Mutex
+	retryPolicy         RetryPolicy
+	credentials         credentials.TransportCredentials // Under GRPC_TLS_MODE; nil if the TLS settings are invalid
+	credentialsErr      error
 	connectionMutex     sync.RWMutex
 	clientMutex         sync.RWMutex
 	metrics             InterServiceMetrics
@@ -48,31 +74,39 @@ type InterServiceMetrics struct {
 	LastConnectionAttempt time.Time `json:"last_connection_attempt"`
 	ServiceCallCount      int64     `json:"service_call_count"`
 	ServiceCallErrors     int64     `json:"service_call_errors"`
+	ServiceCallRetries    int64     `json:"service_call_retries"`
 	CircuitBreakerTrips   int64     `json:"circuit_breaker_trips"`
+	CircuitBreakers       map[string]BreakerStatus `json:"circuit_breakers"` // By service
 }
 
 // AuditCorrelatorClient interface for audit-correlator service
 type AuditCorrelatorClient interface {
 	HealthCheck(ctx context.Context) error
-	SubmitAuditEvent(ctx context.Context, event interface{}) error
+	SubmitAuditEvents(ctx context.Context, events []audit.Event) error
+	SubmitFlag(ctx context.Context, event surveillance.AuditEvent) error
+	// StreamAuditEvents opens a StreamEvents call leaving at most window batches
+	// unacknowledged; it lasts until ctx is done or the stream is closed
+	StreamAuditEvents(ctx context.Context, window int) (*AuditEventStream, error)
 }
 
 // CustodianSimulatorClient interface for custodian-simulator service
 type CustodianSimulatorClient interface {
 	HealthCheck(ctx context.Context) error
-	ProcessSettlement(ctx context.Context, settlement interface{}) error
+	ProcessSettlement(ctx context.Context, batch settlement.Batch) ([]settlement.Ack, error)
 }
 
 type auditCorrelatorClientImpl struct {
-	conn         *grpc.ClientConn
+	conn         grpc.ClientConnInterface
 	healthClient grpc_health_v1.HealthClient
+	auditClient  auditv1.AuditServiceClient
 	logger       *logrus.Logger
 }
 
 type custodianSimulatorClientImpl struct {
-	conn         *grpc.ClientConn
-	healthClient grpc_health_v1.HealthClient
-	logger       *logrus.Logger
+	conn             grpc.ClientConnInterface
+	healthClient     grpc_health_v1.HealthClient
+	settlementClient custodianv1.SettlementServiceClient
+	logger           *logrus.Logger
 }
 
 func NewInterServiceClientManager(
@@ -83,13 +117,41 @@ func NewInterServiceClientManager(
 ) *InterServiceClientManager {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	poolSizes, err := parsePoolSizes(cfg.ClientPoolSizes)
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring GRPC_CLIENT_POOL_SIZES")
+		poolSizes = make(map[string]int)
+	}
+
+	retryPolicy, err := newRetryPolicy(cfg.ClientRetryAttempts, cfg.ClientRetryBackoff, cfg.ClientRetryMaxBackoff, cfg.ClientRetryCodes)
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring GRPC_CLIENT_RETRY_CODES; calls will not be retried")
+	}
+
+	// Never fall back to plaintext: with invalid TLS settings every connection fails
+	transportCredentials, credentialsErr := ClientCredentials(cfg)
+	if credentialsErr != nil {
+		logger.WithError(credentialsErr).Error("Invalid GRPC_TLS_* settings; inter-service connections will fail")
+	}
+
 	return &InterServiceClientManager{
 		config:              cfg,
 		logger:              logger,
 		serviceDiscovery:    serviceDiscovery,
 		configurationClient: configurationClient,
-		connections:         make(map[string]*grpc.ClientConn),
+		connections:         make(map[string]*connectionPool),
 		clients:             make(map[string]interface{}),
+		poolSizes:           poolSizes,
+		watched:             make(map[string]bool),
+		breakers:            make(map[string]*circuitBreaker),
+		breakerConfig: BreakerConfig{
+			FailureThreshold: cfg.ClientBreakerThreshold,
+			Backoff:          cfg.ClientBreakerBackoff,
+			MaxBackoff:       cfg.ClientBreakerMaxBackoff,
+		},
+		retryPolicy:         retryPolicy,
+		credentials:         transportCredentials,
+		credentialsErr:      credentialsErr,
 		ctx:                 ctx,
 		cancel:              cancel,
 		metrics: InterServiceMetrics{
@@ -114,12 +176,14 @@ func (m *InterServiceClientManager) GetAuditCorrelatorClient() (AuditCorrelatorC
 		return nil, &ServiceUnavailableError{
 			ServiceName: serviceName,
 			Message:     err.Error(),
+			Err:         err,
 		}
 	}
 
 	auditClient := &auditCorrelatorClientImpl{
 		conn:         conn,
 		healthClient: grpc_health_v1.NewHealthClient(conn),
+		auditClient:  auditv1.NewAuditServiceClient(conn),
 		logger:       m.logger,
 	}
 
@@ -145,13 +209,15 @@ func (m *InterServiceClientManager) GetCustodianSimulatorClient() (CustodianSimu
 		return nil, &ServiceUnavailableError{
 			ServiceName: serviceName,
 			Message:     err.Error(),
+			Err:         err,
 		}
 	}
 
 	custodianClient := &custodianSimulatorClientImpl{
-		conn:         conn,
-		healthClient: grpc_health_v1.NewHealthClient(conn),
-		logger:       m.logger,
+		conn:             conn,
+		healthClient:     grpc_health_v1.NewHealthClient(conn),
+		settlementClient: custodianv1.NewSettlementServiceClient(conn),
+		logger:           m.logger,
 	}
 
 	m.setClient(serviceName, custodianClient)
@@ -162,8 +228,16 @@ func (m *InterServiceClientManager) GetCustodianSimulatorClient() (CustodianSimu
 
 func (m *InterServiceClientManager) GetMetrics() InterServiceMetrics {
 	m.metricsMutex.RLock()
-	defer m.metricsMutex.RUnlock()
-	return m.metrics
+	metrics := m.metrics
+	m.metricsMutex.RUnlock()
+
+	m.breakerMutex.Lock()
+	defer m.breakerMutex.Unlock()
+	metrics.CircuitBreakers = make(map[string]BreakerStatus, len(m.breakers))
+	for serviceName, breaker := range m.breakers {
+		metrics.CircuitBreakers[serviceName] = breaker.status()
+	}
+	return metrics
 }
 
 func (m *InterServiceClientManager) Close() error {
@@ -176,9 +250,9 @@ func (m *InterServiceClientManager) Close() error {
 	m.connectionMutex.Lock()
 	defer m.connectionMutex.Unlock()
 
-	for serviceName, conn := range m.connections {
-		if conn != nil {
-			err := conn.Close()
+	for serviceName, pool := range m.connections {
+		if pool != nil {
+			err := pool.Close()
 			if err != nil {
 				m.logger.WithError(err).WithField("service", serviceName).Error("Failed to close connection")
 			} else {
@@ -188,7 +262,7 @@ func (m *InterServiceClientManager) Close() error {
 	}
 
 	// Clear connections and clients
-	m.connections = make(map[string]*grpc.ClientConn)
+	m.connections = make(map[string]*connectionPool)
 	m.clients = make(map[string]interface{})
 
 	m.updateActiveConnections(0)
@@ -196,54 +270,165 @@ func (m *InterServiceClientManager) Close() error {
 	return nil
 }
 
-func (m *InterServiceClientManager) getOrCreateConnection(serviceName string) (*grpc.ClientConn, error) {
+func (m *InterServiceClientManager) getOrCreateConnection(serviceName string) (*connectionPool, error) {
 	m.connectionMutex.Lock()
 	defer m.connectionMutex.Unlock()
 
-	// Check if connection already exists and is ready
-	if conn, exists := m.connections[serviceName]; exists {
-		state := conn.GetState()
-		if state == connectivity.Ready || state == connectivity.Idle {
-			return conn, nil
+	// Reuse the pool while any of its connections is ready; gRPC reconnects the rest
+	if pool, exists := m.connections[serviceName]; exists {
+		if pool.healthy() {
+			return pool, nil
 		}
-		// Close bad connection
-		conn.Close()
+		// Close bad connections, steering the next pick away from their instance
+		m.serviceDiscovery.ReportEndpointFailure(pool.endpoint())
+		pool.Close()
 		delete(m.connections, serviceName)
 	}
 
+	if m.credentialsErr != nil {
+		return nil, newClientError(nil, serviceName, "failed to secure connection to "+serviceName, m.credentialsErr)
+	}
+
+	// Fail fast while the service's breaker is open
+	breaker := m.breaker(serviceName)
+	if err := breaker.allow(); err != nil {
+		return nil, newClientError(ErrUnavailable, serviceName, "", err)
+	}
+
 	m.incrementConnectionAttempt()
 
 	// Discover service endpoint
 	endpoint, err := m.serviceDiscovery.GetServiceEndpoint(serviceName)
 	if err != nil {
 		m.incrementFailedConnection()
-		return nil, fmt.Errorf("failed to discover service %s: %w", serviceName, err)
+		m.recordOutcome(serviceName, breaker, err)
+		return nil, newClientError(errorKind(err), serviceName, "failed to discover service "+serviceName, err)
 	}
 
 	// Create new connection with timeout
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(m.unaryInterceptor),
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(m.credentials),
+		grpc.WithUnaryInterceptor(m.unaryInterceptor(serviceName)),
+		grpc.WithStreamInterceptor(m.streamInterceptor(serviceName)),
 		grpc.WithBlock(),
-	)
+	}
+	if keepalive := keepaliveOption(m.config); keepalive != nil {
+		opts = append(opts, keepalive)
+	}
+	// Calls join the caller's trace; each attempt is a client span of its own
+	if tracing := m.config.GetTracingPort(); tracing != nil {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(observability.TracingUnaryClientInterceptor(tracing, serviceName)),
+			grpc.WithChainStreamInterceptor(observability.TracingStreamClientInterceptor(tracing, serviceName)),
+		)
+	}
+
+	size := m.poolSize(serviceName)
+	pool, err := dialPool(ctx, endpoint, size, opts...)
 	if err != nil {
 		m.incrementFailedConnection()
-		return nil, fmt.Errorf("failed to connect to %s at %s: %w", serviceName, endpoint, err)
+		m.serviceDiscovery.ReportEndpointFailure(endpoint)
+		m.recordOutcome(serviceName, breaker, err)
+		return nil, newClientError(transportErrorKind(err), serviceName, fmt.Sprintf("failed to connect to %s at %s", serviceName, endpoint), err)
 	}
+	m.recordOutcome(serviceName, breaker, nil)
 
-	m.connections[serviceName] = conn
+	m.connections[serviceName] = pool
 	m.incrementTotalConnection()
-	m.updateActiveConnections(len(m.connections))
+	m.updateActiveConnections(m.countConnections())
+	m.watchService(serviceName)
+	m.monitorConnections()
 
 	m.logger.WithFields(logrus.Fields{
-		"service":  serviceName,
-		"endpoint": endpoint,
+		"service":   serviceName,
+		"endpoint":  endpoint,
+		"pool_size": size,
 	}).Info("Service connection established")
 
-	return conn, nil
+	return pool, nil
+}
+
+// watchService rebuilds serviceName's connection as its instances come and go, from
+// the first connection until the manager closes; callers hold connectionMutex
+func (m *InterServiceClientManager) watchService(serviceName string) {
+	if m.watched[serviceName] {
+		return
+	}
+	m.watched[serviceName] = true
+	events, stop := m.serviceDiscovery.Watch(serviceName)
+	go func() {
+		defer stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				m.onServiceEvent(serviceName, event)
+			}
+		}
+	}()
+}
+
+// onServiceEvent reconnects to serviceName when the instance it is connected to goes
+// down, or when an instance comes up while it has no working connection
+func (m *InterServiceClientManager) onServiceEvent(serviceName string, event ServiceEvent) {
+	m.connectionMutex.Lock()
+	pool, exists := m.connections[serviceName]
+	var broken bool
+	switch event.Type {
+	case ServiceDown:
+		broken = exists && pool.endpoint() == event.Endpoint
+	case ServiceUp:
+		broken = !exists || !pool.healthy()
+	}
+	if !broken || m.ctx.Err() != nil {
+		m.connectionMutex.Unlock()
+		return
+	}
+	if exists {
+		pool.Close()
+		delete(m.connections, serviceName)
+		m.updateActiveConnections(m.countConnections())
+	}
+	m.connectionMutex.Unlock()
+
+	// The client wraps the closed connection; the next request makes another
+	m.clientMutex.Lock()
+	delete(m.clients, serviceName)
+	m.clientMutex.Unlock()
+
+	logger := m.logger.WithFields(logrus.Fields{"service": serviceName, "endpoint": event.Endpoint, "event": event.Type})
+	if _, err := m.getOrCreateConnection(serviceName); err != nil {
+		logger.WithError(err).Warn("Failed to rebuild service connection")
+		return
+	}
+	logger.Info("Service connection rebuilt")
+}
+
+// poolSize returns how many connections to open to a service
+func (m *InterServiceClientManager) poolSize(serviceName string) int {
+	if size, exists := m.poolSizes[serviceName]; exists {
+		return size
+	}
+	if m.config.ClientPoolSize > 1 {
+		return m.config.ClientPoolSize
+	}
+	return 1
+}
+
+// countConnections totals the connections across pools; callers hold connectionMutex
+func (m *InterServiceClientManager) countConnections() int {
+	count := 0
+	for _, pool := range m.connections {
+		count += len(pool.conns)
+	}
+	return count
 }
 
 func (m *InterServiceClientManager) getClient(serviceName string) (interface{}, bool) {
@@ -259,37 +444,153 @@ func (m *InterServiceClientManager) setClient(serviceName string, client interfa
 	m.clients[serviceName] = client
 }
 
-func (m *InterServiceClientManager) unaryInterceptor(
-	ctx context.Context,
-	method string,
-	req, reply interface{},
-	cc *grpc.ClientConn,
-	invoker grpc.UnaryInvoker,
-	opts ...grpc.CallOption,
-) error {
-	start := time.Now()
+// unaryInterceptor counts and logs calls to serviceName, failing them fast while
+// its circuit breaker is open and retrying transient failures under the retry
+// policy. Calls without a deadline are given REQUEST_TIMEOUT, which bounds every
+// attempt together.
+func (m *InterServiceClientManager) unaryInterceptor(serviceName string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if _, ok := ctx.Deadline(); !ok && m.config.RequestTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, m.config.RequestTimeout)
+			defer cancel()
+		}
 
-	m.incrementServiceCall()
+		breaker := m.breaker(serviceName)
+		if err := breaker.allow(); err != nil {
+			return &ServiceUnavailableError{ServiceName: serviceName, Message: err.Error(), Err: err}
+		}
 
-	err := invoker(ctx, method, req, reply, cc, opts...)
+		start := time.Now()
 
-	duration := time.Since(start)
+		var err error
+		for attempt := 1; ; attempt++ {
+			m.incrementServiceCall()
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			m.recordOutcome(serviceName, breaker, err)
 
-	if err != nil {
-		m.incrementServiceCallError()
-		m.logger.WithFields(logrus.Fields{
-			"method":   method,
-			"duration": duration,
-			"error":    err.Error(),
-		}).Warn("Inter-service call failed")
-	} else {
-		m.logger.WithFields(logrus.Fields{
-			"method":   method,
-			"duration": duration,
-		}).Debug("Inter-service call completed")
+			delay, retry := m.retryPolicy.next(ctx, attempt, err)
+			if !retry {
+				break
+			}
+			m.recordRetry(serviceName, method, attempt, delay, err)
+			if !wait(ctx, delay) {
+				break
+			}
+			// A retry is a call like any other; once the breaker opens the last failure stands
+			if breaker.allow() != nil {
+				break
+			}
+		}
+
+		duration := time.Since(start)
+
+		if err != nil {
+			m.incrementServiceCallError()
+			m.logger.WithFields(logrus.Fields{
+				"method":   method,
+				"duration": duration,
+				"error":    err.Error(),
+			}).Warn("Inter-service call failed")
+		} else {
+			m.logger.WithFields(logrus.Fields{
+				"method":   method,
+				"duration": duration,
+			}).Debug("Inter-service call completed")
+		}
+
+		return err
+	}
+}
+
+// recordRetry counts and logs a retry of a failed call to serviceName
+func (m *InterServiceClientManager) recordRetry(serviceName, method string, attempt int, delay time.Duration, err error) {
+	m.metricsMutex.Lock()
+	m.metrics.ServiceCallRetries++
+	m.metricsMutex.Unlock()
+	m.logger.WithFields(logrus.Fields{
+		"service": serviceName,
+		"method":  method,
+		"attempt": attempt,
+		"delay":   delay,
+		"error":   err.Error(),
+	}).Debug("Retrying inter-service call")
+	if metrics := m.config.GetMetricsPort(); metrics != nil {
+		metrics.IncCounter("inter_service_retries_total", map[string]string{
+			"service": serviceName,
+			"code":    status.Code(err).String(),
+		})
+	}
+}
+
+// streamInterceptor fails streams to serviceName fast while its circuit breaker is
+// open; only opening the stream counts towards the breaker
+func (m *InterServiceClientManager) streamInterceptor(serviceName string) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		breaker := m.breaker(serviceName)
+		if err := breaker.allow(); err != nil {
+			return nil, &ServiceUnavailableError{ServiceName: serviceName, Message: err.Error(), Err: err}
+		}
+		m.incrementServiceCall()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		m.recordOutcome(serviceName, breaker, err)
+		if err != nil {
+			m.incrementServiceCallError()
+		}
+		return stream, err
+	}
+}
+
+// breaker returns serviceName's circuit breaker, made on first use
+func (m *InterServiceClientManager) breaker(serviceName string) *circuitBreaker {
+	m.breakerMutex.Lock()
+	defer m.breakerMutex.Unlock()
+	breaker, exists := m.breakers[serviceName]
+	if !exists {
+		breaker = newCircuitBreaker(m.breakerConfig)
+		m.breakers[serviceName] = breaker
 	}
+	return breaker
+}
 
-	return err
+// recordOutcome feeds a call's outcome to serviceName's breaker and reports the
+// breaker's state
+func (m *InterServiceClientManager) recordOutcome(serviceName string, breaker *circuitBreaker, err error) {
+	tripped := breaker.record(err)
+	status := breaker.status()
+	if tripped {
+		m.metricsMutex.Lock()
+		m.metrics.CircuitBreakerTrips++
+		m.metricsMutex.Unlock()
+		m.logger.WithFields(logrus.Fields{
+			"service":    serviceName,
+			"failures":   status.Failures,
+			"open_until": status.OpenUntil,
+		}).WithError(err).Warn("Circuit breaker opened")
+	}
+	metrics := m.config.GetMetricsPort()
+	if metrics == nil {
+		return
+	}
+	labels := map[string]string{"service": serviceName}
+	metrics.SetGauge("inter_service_circuit_breaker_state", breakerStateValue(status.State), labels)
+	if tripped {
+		metrics.IncCounter("inter_service_circuit_breaker_trips_total", labels)
+	}
 }
 
 func (m *InterServiceClientManager) incrementConnectionAttempt() {
@@ -334,40 +635,122 @@ func (c *auditCorrelatorClientImpl) HealthCheck(ctx context.Context) error {
 		Service: "audit-correlator",
 	})
 	if err != nil {
-		return err
+		return newClientError(grpcErrorKind(err), "audit-correlator", "", err)
 	}
 
 	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
-		return fmt.Errorf("audit-correlator service not serving")
+		return newClientError(ErrUnavailable, "audit-correlator", "audit-correlator service not serving", nil)
 	}
 
 	return nil
 }
 
-func (c *auditCorrelatorClientImpl) SubmitAuditEvent(ctx context.Context, event interface{}) error {
-	// In a real implementation, this would call the actual audit service gRPC method
-	c.logger.WithField("event", event).Debug("Audit event submitted")
+// SubmitFlag records a surveillance flag as an audit event
+func (c *auditCorrelatorClientImpl) SubmitFlag(ctx context.Context, event surveillance.AuditEvent) error {
+	flag := event.Flag
+	return c.SubmitAuditEvents(ctx, []audit.Event{{
+		ID:            flag.ID,
+		Type:          event.Type,
+		Source:        event.Source,
+		CorrelationID: audit.CorrelationID(ctx),
+		AccountID:     flag.AccountID,
+		Symbol:        flag.Symbol,
+		OrderID:       flag.OrderID,
+		TradeID:       flag.TradeID,
+		OccurredAt:    flag.RaisedAt,
+		Attributes: map[string]string{
+			"flag_type": string(flag.Type),
+			"value":     strconv.FormatFloat(flag.Value, 'f', -1, 64),
+			"threshold": strconv.FormatFloat(flag.Threshold, 'f', -1, 64),
+			"detail":    flag.Detail,
+		},
+	}})
+}
+
+// SubmitAuditEvents records a batch of events with the audit-correlator
+func (c *auditCorrelatorClientImpl) SubmitAuditEvents(ctx context.Context, events []audit.Event) error {
+	req := &auditv1.SubmitEventsRequest{Events: auditEventsProto(events)}
+	resp, err := c.auditClient.SubmitEvents(ctx, req)
+	if err != nil {
+		return newClientError(grpcErrorKind(err), "audit-correlator", fmt.Sprintf("failed to submit %d audit events", len(events)), err)
+	}
+	c.logger.WithFields(logrus.Fields{
+		"events":   len(events),
+		"accepted": resp.GetAccepted(),
+	}).Debug("Audit events submitted")
 	return nil
 }
 
+// StreamAuditEvents opens a stream of batches to the audit-correlator
+func (c *auditCorrelatorClientImpl) StreamAuditEvents(ctx context.Context, window int) (*AuditEventStream, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := c.auditClient.StreamEvents(streamCtx)
+	if err != nil {
+		cancel()
+		return nil, newClientError(grpcErrorKind(err), "audit-correlator", "failed to open audit event stream", err)
+	}
+	c.logger.WithField("window", window).Debug("Audit event stream opened")
+	return newAuditEventStream(stream, cancel, window), nil
+}
+
 // Implementation of CustodianSimulatorClient interface
 func (c *custodianSimulatorClientImpl) HealthCheck(ctx context.Context) error {
 	resp, err := c.healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{
 		Service: "custodian-simulator",
 	})
 	if err != nil {
-		return err
+		return newClientError(grpcErrorKind(err), "custodian-simulator", "", err)
 	}
 
 	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
-		return fmt.Errorf("custodian-simulator service not serving")
+		return newClientError(ErrUnavailable, "custodian-simulator", "custodian-simulator service not serving", nil)
 	}
 
 	return nil
 }
 
-func (c *custodianSimulatorClientImpl) ProcessSettlement(ctx context.Context, settlement interface{}) error {
-	// In a real implementation, this would call the actual custodian service gRPC method
-	c.logger.WithField("settlement", settlement).Debug("Settlement processed")
-	return nil
+// ProcessSettlement submits a batch of settlement instructions and returns the
+// custodian's acknowledgement of each
+func (c *custodianSimulatorClientImpl) ProcessSettlement(ctx context.Context, batch settlement.Batch) ([]settlement.Ack, error) {
+	req := &custodianv1.SubmitSettlementInstructionsRequest{
+		Venue:        batch.Venue,
+		BatchId:      batch.ID,
+		Instructions: make([]*custodianv1.SettlementInstruction, 0, len(batch.Instructions)),
+	}
+	for _, instruction := range batch.Instructions {
+		direction := custodianv1.SettlementDirection_SETTLEMENT_DIRECTION_RECEIVE
+		if instruction.Direction == settlement.DirectionDeliver {
+			direction = custodianv1.SettlementDirection_SETTLEMENT_DIRECTION_DELIVER
+		}
+		req.Instructions = append(req.Instructions, &custodianv1.SettlementInstruction{
+			InstructionId:         instruction.ID,
+			AccountId:             instruction.AccountID,
+			Asset:                 instruction.Asset,
+			Amount:                instruction.Amount,
+			Direction:             direction,
+			CounterpartyAccountId: instruction.Counterparty,
+			TradeIds:              instruction.TradeIDs,
+			CreatedAtMs:           instruction.CreatedAt.UnixMilli(),
+		})
+	}
+
+	resp, err := c.settlementClient.SubmitSettlementInstructions(ctx, req)
+	if err != nil {
+		return nil, newClientError(grpcErrorKind(err), "custodian-simulator", "failed to submit settlement batch "+batch.ID, err)
+	}
+	acks := make([]settlement.Ack, 0, len(resp.GetAcks()))
+	for _, ack := range resp.GetAcks() {
+		acks = append(acks, settlement.Ack{
+			InstructionID: ack.GetInstructionId(),
+			Accepted:      ack.GetStatus() == custodianv1.SettlementAckStatus_SETTLEMENT_ACK_STATUS_ACCEPTED,
+			Reference:     ack.GetCustodianReference(),
+			Reason:        ack.GetReason(),
+		})
+	}
+	c.logger.WithFields(logrus.Fields{
+		"batch":        batch.ID,
+		"instructions": len(batch.Instructions),
+		"acks":         len(acks),
+	}).Debug("Settlement batch submitted")
+	return acks, nil
 }
\ No newline at end of file
@@ -4,12 +4,19 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 
+	auditv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/audit/v1"
+	custodianv1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/custodian/v1"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
 )
 
 func TestInterServiceClientManager_Creation(t *testing.T) {
@@ -200,51 +207,117 @@ func TestInterServiceClientManager_Close(t *testing.T) {
 	})
 }
 
-func TestAuditCorrelatorClient_SubmitAuditEvent(t *testing.T) {
-	t.Run("submits_audit_event", func(t *testing.T) {
-		logger := logrus.New()
-		logger.SetLevel(logrus.ErrorLevel)
+// auditServer answers SubmitEvents in place of a connection, accepting every event
+type auditServer struct {
+	received *auditv1.SubmitEventsRequest
+}
+
+func (s *auditServer) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	s.received = args.(*auditv1.SubmitEventsRequest)
+	reply.(*auditv1.SubmitEventsResponse).Accepted = int32(len(s.received.GetEvents()))
+	return nil
+}
+
+func (s *auditServer) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestAuditCorrelatorClient_SubmitAuditEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
 
+	t.Run("submits_audit_events", func(t *testing.T) {
+		server := &auditServer{}
 		client := &auditCorrelatorClientImpl{
-			conn:   nil, // We're not testing the gRPC connection here
-			logger: logger,
+			conn:        server,
+			auditClient: auditv1.NewAuditServiceClient(server),
+			logger:      logger,
 		}
 
 		ctx := context.Background()
-		event := map[string]interface{}{
-			"type":      "trade",
-			"amount":    1000.0,
-			"timestamp": time.Now(),
+		occurredAt := time.Now()
+		err := client.SubmitAuditEvents(ctx, []audit.Event{
+			{ID: "e-1", Type: audit.TypeOrderPlaced, CorrelationID: "req-1", AccountID: "alice", OrderID: "o-1", OccurredAt: occurredAt},
+			{ID: "e-2", Type: audit.TypeTradeExecuted, CorrelationID: "req-1", TradeID: "t-1", Attributes: map[string]string{"price": "100"}},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		err := client.SubmitAuditEvent(ctx, event)
-		if err != nil {
-			t.Errorf("Expected no error, got %v", err)
+		sent := server.received.GetEvents()
+		if len(sent) != 2 || sent[0].GetCorrelationId() != "req-1" || sent[0].GetOccurredAtMs() != occurredAt.UnixMilli() || sent[1].GetAttributes()["price"] != "100" {
+			t.Errorf("Unexpected request: %+v", server.received)
+		}
+	})
+
+	t.Run("submits_surveillance_flags", func(t *testing.T) {
+		server := &auditServer{}
+		client := &auditCorrelatorClientImpl{conn: server, auditClient: auditv1.NewAuditServiceClient(server), logger: logger}
+
+		event := surveillance.AuditEvent{Type: surveillance.AuditEventType, Source: "exchange-simulator", Flag: surveillance.Flag{ID: "flag-1", AccountID: "alice", Value: 0.95}}
+		if err := client.SubmitFlag(context.Background(), event); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		sent := server.received.GetEvents()
+		if len(sent) != 1 || sent[0].GetEventId() != "flag-1" || sent[0].GetEventType() != surveillance.AuditEventType || sent[0].GetAttributes()["value"] != "0.95" {
+			t.Errorf("Unexpected request: %+v", server.received)
 		}
 	})
 }
 
+// settlementServer answers SubmitSettlementInstructions in place of a connection,
+// accepting every instruction but those for rejectAccount
+type settlementServer struct {
+	rejectAccount string
+	received      *custodianv1.SubmitSettlementInstructionsRequest
+}
+
+func (s *settlementServer) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	s.received = args.(*custodianv1.SubmitSettlementInstructionsRequest)
+	resp := reply.(*custodianv1.SubmitSettlementInstructionsResponse)
+	for _, instruction := range s.received.GetInstructions() {
+		ack := &custodianv1.SettlementAck{InstructionId: instruction.GetInstructionId(), Status: custodianv1.SettlementAckStatus_SETTLEMENT_ACK_STATUS_ACCEPTED, CustodianReference: "cust-" + instruction.GetInstructionId()}
+		if instruction.GetAccountId() == s.rejectAccount {
+			ack.Status, ack.CustodianReference, ack.Reason = custodianv1.SettlementAckStatus_SETTLEMENT_ACK_STATUS_REJECTED, "", "unknown account"
+		}
+		resp.Acks = append(resp.Acks, ack)
+	}
+	return nil
+}
+
+func (s *settlementServer) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
 func TestCustodianSimulatorClient_ProcessSettlement(t *testing.T) {
-	t.Run("processes_settlement", func(t *testing.T) {
+	t.Run("submits_instructions_and_returns_each_ack", func(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.ErrorLevel)
-
+		server := &settlementServer{rejectAccount: "bob"}
 		client := &custodianSimulatorClientImpl{
-			conn:   nil, // We're not testing the gRPC connection here
-			logger: logger,
+			conn:             server,
+			settlementClient: custodianv1.NewSettlementServiceClient(server),
+			logger:           logger,
 		}
 
 		ctx := context.Background()
-		settlement := map[string]interface{}{
-			"id":           "settlement-123",
-			"amount":       5000.0,
-			"currency":     "USD",
-			"counterparty": "BANK-A",
-		}
+		batch := settlement.Batch{ID: "batch-1", Venue: "exchange-simulator", Instructions: []settlement.Instruction{
+			{ID: "stl-2", AccountID: "alice", Asset: "BTC", Amount: 1, Direction: settlement.DirectionReceive, Counterparty: "bob", TradeIDs: []string{"t-1"}},
+			{ID: "stl-3", AccountID: "bob", Asset: "BTC", Amount: 1, Direction: settlement.DirectionDeliver, Counterparty: "alice", TradeIDs: []string{"t-1"}},
+		}}
 
-		err := client.ProcessSettlement(ctx, settlement)
+		acks, err := client.ProcessSettlement(ctx, batch)
 		if err != nil {
-			t.Errorf("Expected no error, got %v", err)
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		sent := server.received.GetInstructions()
+		if server.received.GetBatchId() != "batch-1" || len(sent) != 2 || sent[1].GetDirection() != custodianv1.SettlementDirection_SETTLEMENT_DIRECTION_DELIVER {
+			t.Errorf("Unexpected request: %+v", server.received)
+		}
+		if len(acks) != 2 || !acks[0].Accepted || acks[0].Reference != "cust-stl-2" || acks[1].Accepted || acks[1].Reason != "unknown account" {
+			t.Errorf("Unexpected acks: %+v", acks)
 		}
 	})
 }
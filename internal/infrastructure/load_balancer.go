@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoadBalancingStrategy is how GetServiceEndpoint picks among a service's instances
+type LoadBalancingStrategy string
+
+const (
+	StrategyRoundRobin         LoadBalancingStrategy = "round_robin"          // Each instance in turn, as often as its weight
+	StrategyRandom             LoadBalancingStrategy = "random"               // A draw weighted by each instance's weight
+	StrategyLeastRecentFailure LoadBalancingStrategy = "least_recent_failure" // The instance that failed longest ago, or never
+)
+
+// ParseLoadBalancingStrategy reads a strategy by name
+func ParseLoadBalancingStrategy(name string) (LoadBalancingStrategy, error) {
+	switch strategy := LoadBalancingStrategy(strings.TrimSpace(name)); strategy {
+	case StrategyRoundRobin, StrategyRandom, StrategyLeastRecentFailure:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown load balancing strategy %q: expected round_robin, random or least_recent_failure", name)
+}
+
+const (
+	// failurePenaltyWindow is how long an instance that failed keeps a reduced weight
+	failurePenaltyWindow = 30 * time.Second
+
+	healthyWeight  = 4
+	degradedWeight = 2
+	failedWeight   = 1
+)
+
+// WeightedInstance is a service instance as offered to a load balancer
+type WeightedInstance struct {
+	ServiceInfo
+	Endpoint    string
+	Weight      int       // Share of picks; 0 only when no instance is fit to pick
+	LastFailure time.Time // Zero when the instance has not failed
+}
+
+// LoadBalancer picks one of a service's instances, never an empty set
+type LoadBalancer interface {
+	Pick(service string, instances []WeightedInstance) WeightedInstance
+}
+
+// NewLoadBalancer returns a balancer for strategy
+func NewLoadBalancer(strategy LoadBalancingStrategy) LoadBalancer {
+	switch strategy {
+	case StrategyRandom:
+		return &randomBalancer{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	case StrategyLeastRecentFailure:
+		return &leastRecentFailureBalancer{ties: newRoundRobinBalancer()}
+	}
+	return newRoundRobinBalancer()
+}
+
+// weighInstances orders services by endpoint and weighs each by its registered
+// status, cut to failedWeight while a failure is recent. Instances that are neither
+// healthy nor degraded weigh nothing, unless none is, when all weigh alike.
+func weighInstances(services []ServiceInfo, failures map[string]time.Time, now time.Time) []WeightedInstance {
+	instances := make([]WeightedInstance, 0, len(services))
+	fit := false
+	for _, service := range services {
+		instance := WeightedInstance{ServiceInfo: service, Endpoint: fmt.Sprintf("%s:%d", service.Host, service.GRPCPort)}
+		instance.LastFailure = failures[instance.Endpoint]
+		switch service.Status {
+		case "", "healthy":
+			instance.Weight = healthyWeight
+		case "degraded":
+			instance.Weight = degradedWeight
+		}
+		if instance.Weight > 0 && !instance.LastFailure.IsZero() && now.Sub(instance.LastFailure) < failurePenaltyWindow {
+			instance.Weight = failedWeight
+		}
+		fit = fit || instance.Weight > 0
+		instances = append(instances, instance)
+	}
+	if !fit {
+		for i := range instances {
+			instances[i].Weight = failedWeight
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Endpoint < instances[j].Endpoint })
+	return instances
+}
+
+// roundRobinBalancer is smooth weighted round robin: each pick credits every
+// instance its weight and takes the most credited, spreading heavier instances'
+// extra turns evenly
+type roundRobinBalancer struct {
+	credit map[string]map[string]int // Service, then endpoint
+	mu     sync.Mutex
+}
+
+func newRoundRobinBalancer() *roundRobinBalancer {
+	return &roundRobinBalancer{credit: make(map[string]map[string]int)}
+}
+
+func (b *roundRobinBalancer) Pick(service string, instances []WeightedInstance) WeightedInstance {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	previous := b.credit[service]
+	credit := make(map[string]int, len(instances)) // Instances that left are forgotten
+	total, best := 0, -1
+	for i, instance := range instances {
+		credit[instance.Endpoint] = previous[instance.Endpoint] + instance.Weight
+		total += instance.Weight
+		if best < 0 || credit[instance.Endpoint] > credit[instances[best].Endpoint] {
+			best = i
+		}
+	}
+	credit[instances[best].Endpoint] -= total
+	b.credit[service] = credit
+	return instances[best]
+}
+
+type randomBalancer struct {
+	rng *rand.Rand
+	mu  sync.Mutex
+}
+
+func (b *randomBalancer) Pick(service string, instances []WeightedInstance) WeightedInstance {
+	total := 0
+	for _, instance := range instances {
+		total += instance.Weight
+	}
+	b.mu.Lock()
+	draw := b.rng.Intn(total)
+	b.mu.Unlock()
+	for _, instance := range instances {
+		if draw < instance.Weight {
+			return instance
+		}
+		draw -= instance.Weight
+	}
+	return instances[len(instances)-1]
+}
+
+// leastRecentFailureBalancer keeps to the instances that failed longest ago, those
+// that never failed first, taking turns among them by weight
+type leastRecentFailureBalancer struct {
+	ties *roundRobinBalancer
+}
+
+func (b *leastRecentFailureBalancer) Pick(service string, instances []WeightedInstance) WeightedInstance {
+	var oldest time.Time
+	candidates := make([]WeightedInstance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Weight == 0 {
+			continue
+		}
+		switch {
+		case len(candidates) == 0 || instance.LastFailure.Before(oldest):
+			oldest = instance.LastFailure
+			candidates = append(candidates[:0], instance)
+		case instance.LastFailure.Equal(oldest):
+			candidates = append(candidates, instance)
+		}
+	}
+	return b.ties.Pick(service, candidates)
+}
+
+// parseLoadBalancing reads per-service strategies in the
+// DISCOVERY_LOAD_BALANCING_SERVICES form "service=strategy,...", e.g.
+// "audit-correlator=least_recent_failure"
+func parseLoadBalancing(spec string) (map[string]LoadBalancingStrategy, error) {
+	strategies := make(map[string]LoadBalancingStrategy)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		service, name, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(service) == "" {
+			return nil, fmt.Errorf("invalid load balancing %q: expected service=strategy", entry)
+		}
+		strategy, err := ParseLoadBalancingStrategy(name)
+		if err != nil {
+			return nil, err
+		}
+		strategies[strings.TrimSpace(service)] = strategy
+	}
+	return strategies, nil
+}
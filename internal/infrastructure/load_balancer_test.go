@@ -0,0 +1,161 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func instancesAt(statuses ...string) []ServiceInfo {
+	services := make([]ServiceInfo, 0, len(statuses))
+	for i, status := range statuses {
+		services = append(services, ServiceInfo{ServiceName: "target-service", Host: fmt.Sprintf("host-%d", i), GRPCPort: 50051, Status: status, LastSeen: time.Now()})
+	}
+	return services
+}
+
+func countPicks(balancer LoadBalancer, instances []WeightedInstance, picks int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < picks; i++ {
+		counts[balancer.Pick("target-service", instances).Endpoint]++
+	}
+	return counts
+}
+
+func TestLoadBalancer(t *testing.T) {
+	t.Run("round_robin_takes_turns_by_health_weight", func(t *testing.T) {
+		// Given: Two healthy instances and one degraded
+		instances := weighInstances(instancesAt("healthy", "healthy", "degraded"), nil, time.Now())
+
+		// When: Ten picks are made
+		counts := countPicks(NewLoadBalancer(StrategyRoundRobin), instances, 10)
+
+		// Then: Each healthy instance takes twice the degraded one's turns
+		if counts["host-0:50051"] != 4 || counts["host-1:50051"] != 4 || counts["host-2:50051"] != 2 {
+			t.Errorf("Expected 4/4/2 picks, got %v", counts)
+		}
+	})
+
+	t.Run("random_never_picks_an_unfit_instance_while_others_are_fit", func(t *testing.T) {
+		// Given: A healthy instance and one registered as unhealthy
+		instances := weighInstances(instancesAt("healthy", "unhealthy"), nil, time.Now())
+
+		// When: Many picks are drawn
+		counts := countPicks(NewLoadBalancer(StrategyRandom), instances, 200)
+
+		// Then: Only the healthy instance is picked
+		if counts["host-0:50051"] != 200 {
+			t.Errorf("Expected every pick on the healthy instance, got %v", counts)
+		}
+	})
+
+	t.Run("falls_back_to_every_instance_when_none_is_fit", func(t *testing.T) {
+		// Given: Two instances, neither healthy nor degraded
+		instances := weighInstances(instancesAt("unhealthy", "draining"), nil, time.Now())
+
+		// When: Four picks are made
+		counts := countPicks(NewLoadBalancer(StrategyRoundRobin), instances, 4)
+
+		// Then: Both are still used rather than none
+		if counts["host-0:50051"] != 2 || counts["host-1:50051"] != 2 {
+			t.Errorf("Expected 2/2 picks, got %v", counts)
+		}
+	})
+
+	t.Run("least_recent_failure_prefers_instances_that_failed_longest_ago", func(t *testing.T) {
+		// Given: Three instances, two of which have failed, host-0 the more recently
+		now := time.Now()
+		failures := map[string]time.Time{"host-0:50051": now.Add(-time.Second), "host-1:50051": now.Add(-time.Hour)}
+		balancer := NewLoadBalancer(StrategyLeastRecentFailure)
+
+		// When: Picks are made before and after host-2 fails too
+		before := countPicks(balancer, weighInstances(instancesAt("healthy", "healthy", "healthy"), failures, now), 3)
+		failures["host-2:50051"] = now
+		after := countPicks(balancer, weighInstances(instancesAt("healthy", "healthy", "healthy"), failures, now), 3)
+
+		// Then: The instance that never failed is used, then the one that failed an hour ago
+		if before["host-2:50051"] != 3 {
+			t.Errorf("Expected every pick on the instance that never failed, got %v", before)
+		}
+		if after["host-1:50051"] != 3 {
+			t.Errorf("Expected every pick on the instance that failed longest ago, got %v", after)
+		}
+	})
+
+	t.Run("parses_per_service_strategies", func(t *testing.T) {
+		strategies, err := parseLoadBalancing("audit-correlator=random, custodian-simulator=least_recent_failure")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strategies["audit-correlator"] != StrategyRandom || strategies["custodian-simulator"] != StrategyLeastRecentFailure {
+			t.Errorf("Unexpected strategies: %v", strategies)
+		}
+		for _, spec := range []string{"audit-correlator", "=random", "audit-correlator=fastest"} {
+			if _, err := parseLoadBalancing(spec); err == nil {
+				t.Errorf("Expected %q to be rejected", spec)
+			}
+		}
+	})
+}
+
+func TestServiceDiscoveryClient_LoadBalancing(t *testing.T) {
+	newClient := func(cfg *config.Config, statuses ...string) *ServiceDiscoveryClient {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		cfg.ServiceName = "test-service"
+		cfg.RedisURL = "redis://localhost:6379"
+		client := NewServiceDiscoveryClient(cfg, logger)
+		mockRedis := newMockRedisClient()
+		for _, service := range instancesAt(statuses...) {
+			data, _ := json.Marshal(service)
+			mockRedis.data[fmt.Sprintf("services:target-service:%s:%d", service.Host, service.GRPCPort)] = string(data)
+		}
+		client.redisClient = mockRedis
+		return client
+	}
+
+	t.Run("spreads_endpoints_across_registered_instances", func(t *testing.T) {
+		// Given: Two healthy instances of a service
+		client := newClient(&config.Config{DiscoveryStrategy: "round_robin"}, "healthy", "healthy")
+
+		// When: The endpoint is resolved twice
+		first, err := client.GetServiceEndpoint("target-service")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, _ := client.GetServiceEndpoint("target-service")
+
+		// Then: Each instance is returned once
+		if first == second {
+			t.Errorf("Expected two different endpoints, got %s twice", first)
+		}
+	})
+
+	t.Run("steers_a_service_away_from_its_failed_instance", func(t *testing.T) {
+		// Given: A service balanced by least recent failure, whose first instance failed
+		client := newClient(&config.Config{DiscoveryStrategy: "round_robin", DiscoveryStrategies: "target-service=least_recent_failure"}, "healthy", "healthy")
+		client.ReportEndpointFailure("host-0:50051")
+
+		// When: The endpoint is resolved
+		endpoints := map[string]bool{}
+		for i := 0; i < 4; i++ {
+			endpoint, err := client.GetServiceEndpoint("target-service")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			endpoints[endpoint] = true
+		}
+
+		// Then: Only the instance that has not failed is returned
+		if len(endpoints) != 1 || !endpoints["host-1:50051"] {
+			t.Errorf("Expected only host-1:50051, got %v", endpoints)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package metricsstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// table holds one row per snapshot; volumes stay JSON since the symbols vary by venue
+const table = "exchange_metrics_snapshots"
+
+// Schema creates the table, as migration 000001_metrics_snapshots does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	run_id         TEXT             NOT NULL,
+	instance       TEXT             NOT NULL,
+	taken_at       TIMESTAMPTZ      NOT NULL,
+	orders         BIGINT           NOT NULL,
+	cancels        BIGINT           NOT NULL,
+	amends         BIGINT           NOT NULL,
+	trades         BIGINT           NOT NULL,
+	rejections     BIGINT           NOT NULL,
+	rejection_rate DOUBLE PRECISION NOT NULL,
+	latency_count  BIGINT           NOT NULL,
+	latency_p50_ms DOUBLE PRECISION NOT NULL,
+	latency_p95_ms DOUBLE PRECISION NOT NULL,
+	latency_p99_ms DOUBLE PRECISION NOT NULL,
+	latency_max_ms DOUBLE PRECISION NOT NULL,
+	volumes        JSONB            NOT NULL,
+	PRIMARY KEY (run_id, instance, taken_at)
+)`
+
+const snapshotColumns = `run_id, instance, taken_at, orders, cancels, amends, trades, rejections, rejection_rate,
+	latency_count, latency_p50_ms, latency_p95_ms, latency_p99_ms, latency_max_ms, volumes`
+
+// PostgresStore keeps metrics snapshots in Postgres keyed by run, instance and time
+type PostgresStore struct {
+	client  SQLClient
+	timeout time.Duration
+}
+
+func NewPostgresStore(client SQLClient, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, timeout: timeout}
+}
+
+// EnsureSchema creates the snapshot table when it does not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create metrics snapshot table: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Save(snapshot runmetrics.Snapshot) error {
+	volumes, err := json.Marshal(snapshot.Volumes)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics volumes: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	latency := snapshot.OrderLatency
+	_, err = s.client.ExecContext(ctx,
+		`INSERT INTO `+table+` (`+snapshotColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (run_id, instance, taken_at) DO NOTHING`,
+		snapshot.RunID, snapshot.Instance, snapshot.TakenAt.UTC(),
+		snapshot.Orders, snapshot.Cancels, snapshot.Amends, snapshot.Trades, snapshot.Rejections, snapshot.RejectionRate,
+		latency.Count, latency.P50Ms, latency.P95Ms, latency.P99Ms, latency.MaxMs, volumes)
+	if err != nil {
+		return fmt.Errorf("failed to save metrics snapshot to postgres: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns a run's snapshots from every instance, oldest first
+func (s *PostgresStore) Snapshots(query runmetrics.Query) ([]runmetrics.Snapshot, error) {
+	conditions := []string{"run_id = $1"}
+	args := []interface{}{query.RunID}
+	if !query.From.IsZero() {
+		args = append(args, query.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("taken_at >= $%d", len(args)))
+	}
+	if !query.To.IsZero() {
+		args = append(args, query.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("taken_at <= $%d", len(args)))
+	}
+	statement := `SELECT ` + snapshotColumns + ` FROM ` + table +
+		` WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY taken_at, instance`
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		statement += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics snapshots from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]runmetrics.Snapshot, 0)
+	for rows.Next() {
+		var snapshot runmetrics.Snapshot
+		var volumes []byte
+		latency := &snapshot.OrderLatency
+		if err := rows.Scan(&snapshot.RunID, &snapshot.Instance, &snapshot.TakenAt,
+			&snapshot.Orders, &snapshot.Cancels, &snapshot.Amends, &snapshot.Trades, &snapshot.Rejections, &snapshot.RejectionRate,
+			&latency.Count, &latency.P50Ms, &latency.P95Ms, &latency.P99Ms, &latency.MaxMs, &volumes); err != nil {
+			return nil, fmt.Errorf("failed to read metrics snapshot: %w", err)
+		}
+		if err := json.Unmarshal(volumes, &snapshot.Volumes); err != nil {
+			return nil, fmt.Errorf("invalid metrics volumes for run %s at %s: %w", snapshot.RunID, snapshot.TakenAt, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Runs lists every run with snapshots, most recent first
+func (s *PostgresStore) Runs() ([]runmetrics.Run, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx,
+		`SELECT run_id, instance, MIN(taken_at), MAX(taken_at), COUNT(*) FROM `+table+`
+		GROUP BY run_id, instance ORDER BY MAX(taken_at) DESC, run_id, instance`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics runs from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]runmetrics.Run, 0)
+	for rows.Next() {
+		var run runmetrics.Run
+		if err := rows.Scan(&run.RunID, &run.Instance, &run.FirstAt, &run.LastAt, &run.Snapshots); err != nil {
+			return nil, fmt.Errorf("failed to read metrics run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package metricsstore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	store := NewPostgresStore(db, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+
+	t.Run("queries_snapshots_by_run_and_time", func(t *testing.T) {
+		// Given: Three snapshots of a fresh run a minute apart
+		runID := fmt.Sprintf("test-run-%d", time.Now().UnixNano())
+		defer db.Exec(`DELETE FROM `+table+` WHERE run_id = $1`, runID)
+		start := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			snapshot := runmetrics.Snapshot{
+				RunID:        runID,
+				Instance:     "exchange-OKX",
+				TakenAt:      start.Add(time.Duration(i) * time.Minute),
+				Orders:       int64(10 * (i + 1)),
+				Volumes:      map[string]runmetrics.Volume{"BTC-USD": {Trades: int64(i), Notional: 60000}},
+				OrderLatency: runmetrics.Latency{Count: 5, P99Ms: 1.5},
+			}
+			if err := store.Save(snapshot); err != nil {
+				t.Fatalf("Expected the snapshot to save, got %v", err)
+			}
+		}
+
+		// When: The run is queried from its second minute
+		snapshots, err := store.Snapshots(runmetrics.Query{RunID: runID, From: start.Add(time.Minute), Limit: 10})
+
+		// Then: The later two come back oldest first with their volumes
+		if err != nil || len(snapshots) != 2 {
+			t.Fatalf("Expected two snapshots, got %+v, %v", snapshots, err)
+		}
+		if snapshots[0].Orders != 20 || snapshots[1].Volumes["BTC-USD"].Trades != 2 || snapshots[1].OrderLatency.P99Ms != 1.5 {
+			t.Errorf("Unexpected snapshots: %+v", snapshots)
+		}
+
+		// And: The run is listed with its span
+		runs, err := store.Runs()
+		if err != nil {
+			t.Fatalf("Expected runs, got %v", err)
+		}
+		for _, run := range runs {
+			if run.RunID == runID {
+				if run.Snapshots != 3 || !run.FirstAt.Equal(start) || !run.LastAt.Equal(start.Add(2*time.Minute)) {
+					t.Errorf("Unexpected run summary: %+v", run)
+				}
+				return
+			}
+		}
+		t.Errorf("Expected %s among the runs", runID)
+	})
+}
@@ -0,0 +1,205 @@
+// Package migrations versions the Postgres schema the venue's stores share. The
+// numbered files under sql/ follow golang-migrate's layout and its schema_migrations
+// table, so the migrate CLI can apply them instead of the venue; the first of them
+// take each table as its store created it, so a database the stores provisioned is
+// adopted as it is.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// ErrDirty is returned when a migration was left half applied, which only happens
+// outside this runner: each migration here commits with its version
+var ErrDirty = errors.New("schema is dirty")
+
+// table records the version applied, as golang-migrate does
+const table = "schema_migrations"
+
+const createTable = `CREATE TABLE IF NOT EXISTS ` + table + ` (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+// lockKey serialises instances migrating the same database
+const lockKey int64 = 0x65786368616e6765 // "exchange"
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+}
+
+// Status is the schema version a database is at against what this build knows
+type Status struct {
+	Version   uint      `json:"version"` // Last migration applied; 0 = none
+	Latest    uint      `json:"latest"`  // Last migration this build has
+	Pending   int       `json:"pending"`
+	Dirty     bool      `json:"dirty"`
+	Applied   int       `json:"applied"` // By this instance at startup
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Load reads the embedded migrations in version order
+func Load() ([]Migration, error) {
+	entries, err := files.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(entries)/2)
+	seen := make(map[uint]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		version, title, err := parseName(name)
+		if err != nil {
+			return nil, err
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+		up, err := files.ReadFile(path.Join("sql", name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: title, Up: string(up)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseName splits "000007_orders.up.sql" into its version and title
+func parseName(name string) (uint, string, error) {
+	base := strings.TrimSuffix(name, ".up.sql")
+	number, title, found := strings.Cut(base, "_")
+	if base == name || !found || title == "" {
+		return 0, "", fmt.Errorf("migration %s is not named <version>_<title>.up.sql", name)
+	}
+	version, err := strconv.ParseUint(number, 10, 64)
+	if err != nil || version == 0 {
+		return 0, "", fmt.Errorf("migration %s needs a positive version", name)
+	}
+	return uint(version), title, nil
+}
+
+// Runner applies the embedded migrations to one database
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewRunner(db *sql.DB) (*Runner, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, fmt.Errorf("invalid migrations: %w", err)
+	}
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Status reads the version the database is at without changing it
+func (r *Runner) Status(ctx context.Context) (Status, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to connect for migrations: %w", err)
+	}
+	defer conn.Close()
+	return r.status(ctx, conn)
+}
+
+// Up applies every migration past the database's version, each in a transaction
+// with its version, holding a lock so instances sharing the database take turns. A
+// database at a later version than this build knows is left as it is.
+func (r *Runner) Up(ctx context.Context) (Status, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to connect for migrations: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return Status{}, fmt.Errorf("failed to lock schema for migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return Status{}, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	status, err := r.status(ctx, conn)
+	if err != nil {
+		return Status{}, err
+	}
+	if status.Dirty {
+		return status, fmt.Errorf("%w at version %d; repair it and force the version with the migrate CLI", ErrDirty, status.Version)
+	}
+	for _, migration := range r.migrations {
+		if migration.Version <= status.Version {
+			continue
+		}
+		if err := apply(ctx, conn, migration); err != nil {
+			return status, err
+		}
+		status.Version = migration.Version
+		status.Applied++
+		status.Pending--
+	}
+	return status, nil
+}
+
+// apply runs one migration and records its version in the same transaction
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("failed to apply migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO `+table+` (version, dirty) VALUES ($1, false)`, migration.Version); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return tx.Commit()
+}
+
+// status reads the recorded version; a database without the table is at version 0
+func (r *Runner) status(ctx context.Context, conn *sql.Conn) (Status, error) {
+	status := Status{CheckedAt: time.Now().UTC()}
+	if n := len(r.migrations); n > 0 {
+		status.Latest = r.migrations[n-1].Version
+	}
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists); err != nil {
+		return Status{}, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if exists {
+		var version int64
+		err := conn.QueryRowContext(ctx, `SELECT version, dirty FROM `+table+` LIMIT 1`).Scan(&version, &status.Dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return Status{}, fmt.Errorf("failed to read schema version: %w", err)
+		}
+		status.Version = uint(version)
+	}
+	for _, migration := range r.migrations {
+		if migration.Version > status.Version {
+			status.Pending++
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+package migrations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/accountstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/balancestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/metricsstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/orderstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/outboxstore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/statestore"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/tradestore"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("numbers_migrations_without_gaps_each_with_a_down", func(t *testing.T) {
+		// When: The embedded migrations are loaded
+		migrations, err := Load()
+		if err != nil {
+			t.Fatalf("Expected the migrations loaded, got %v", err)
+		}
+
+		// Then: They run 1, 2, 3... with a statement each and a down file beside it
+		if len(migrations) == 0 {
+			t.Fatal("Expected migrations")
+		}
+		for i, migration := range migrations {
+			if migration.Version != uint(i+1) || strings.TrimSpace(migration.Up) == "" {
+				t.Errorf("Expected migration %d with a statement, got %d %s", i+1, migration.Version, migration.Name)
+			}
+			down := strings.Replace(nameOf(t, migration), ".up.sql", ".down.sql", 1)
+			if _, err := files.ReadFile("sql/" + down); err != nil {
+				t.Errorf("Expected %s beside the migration, got %v", down, err)
+			}
+		}
+	})
+
+	t.Run("creates_each_table_as_its_store_does", func(t *testing.T) {
+		// Given: The statement each store provisions its table with
+		schemas := map[uint]string{
+			1: metricsstore.Schema,
+			2: tradestore.Schema,
+			3: accountstore.Schema,
+			4: balancestore.Schema,
+			5: outboxstore.Schema,
+			6: statestore.Schema,
+			7: orderstore.Schema,
+		}
+
+		// When: The embedded migrations are loaded
+		migrations, err := Load()
+		if err != nil {
+			t.Fatalf("Expected the migrations loaded, got %v", err)
+		}
+
+		// Then: The migration creating each table is the store's statement, so a change to
+		// one without the other fails here
+		for _, migration := range migrations {
+			schema, ok := schemas[migration.Version]
+			if !ok {
+				continue
+			}
+			if migration.Up != schema+";\n" {
+				t.Errorf("Expected migration %d %s to match its store's schema, got\n%s\nwant\n%s;", migration.Version, migration.Name, migration.Up, schema)
+			}
+		}
+	})
+
+	t.Run("refuses_misnamed_files", func(t *testing.T) {
+		for _, name := range []string{"orders.up.sql", "000000_orders.up.sql", "x1_orders.up.sql", "000001_.up.sql", "000001_orders.sql"} {
+			// When: The name is parsed
+			_, _, err := parseName(name)
+
+			// Then: It is refused
+			if err == nil {
+				t.Errorf("%s: expected the name refused", name)
+			}
+		}
+	})
+}
+
+// nameOf finds the file a migration was loaded from
+func nameOf(t *testing.T, migration Migration) string {
+	t.Helper()
+	entries, _ := files.ReadDir("sql")
+	for _, entry := range entries {
+		if version, title, err := parseName(entry.Name()); err == nil && version == migration.Version && title == migration.Name {
+			return entry.Name()
+		}
+	}
+	t.Fatalf("Expected the file of migration %d", migration.Version)
+	return ""
+}
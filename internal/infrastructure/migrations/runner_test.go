@@ -0,0 +1,48 @@
+//go:build integration
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+func TestRunner(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Failed to open postgres: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	runner, err := NewRunner(db)
+	if err != nil {
+		t.Fatalf("Expected the runner, got %v", err)
+	}
+
+	t.Run("migrates_to_the_latest_version_once", func(t *testing.T) {
+		// Given: The database migrated up
+		first, err := runner.Up(ctx)
+		if err != nil {
+			t.Fatalf("Expected the migrations applied, got %v", err)
+		}
+
+		// When: It is migrated again
+		second, err := runner.Up(ctx)
+
+		// Then: Nothing more is applied and the status reads the latest version
+		if err != nil || second.Applied != 0 || second.Version != first.Latest || second.Pending != 0 {
+			t.Errorf("Expected the latest version with nothing applied, got %+v, %v", second, err)
+		}
+		if status, err := runner.Status(ctx); err != nil || status.Version != status.Latest || status.Dirty {
+			t.Errorf("Expected a clean schema at the latest version, got %+v, %v", status, err)
+		}
+	})
+}
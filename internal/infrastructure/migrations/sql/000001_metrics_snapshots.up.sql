@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS exchange_metrics_snapshots (
+	run_id         TEXT             NOT NULL,
+	instance       TEXT             NOT NULL,
+	taken_at       TIMESTAMPTZ      NOT NULL,
+	orders         BIGINT           NOT NULL,
+	cancels        BIGINT           NOT NULL,
+	amends         BIGINT           NOT NULL,
+	trades         BIGINT           NOT NULL,
+	rejections     BIGINT           NOT NULL,
+	rejection_rate DOUBLE PRECISION NOT NULL,
+	latency_count  BIGINT           NOT NULL,
+	latency_p50_ms DOUBLE PRECISION NOT NULL,
+	latency_p95_ms DOUBLE PRECISION NOT NULL,
+	latency_p99_ms DOUBLE PRECISION NOT NULL,
+	latency_max_ms DOUBLE PRECISION NOT NULL,
+	volumes        JSONB            NOT NULL,
+	PRIMARY KEY (run_id, instance, taken_at)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS exchange_trades (
+	instance        TEXT             NOT NULL,
+	trade_id        TEXT             NOT NULL,
+	symbol          TEXT             NOT NULL,
+	price           DOUBLE PRECISION NOT NULL,
+	quantity        DOUBLE PRECISION NOT NULL,
+	buy_order_id    TEXT             NOT NULL,
+	sell_order_id   TEXT             NOT NULL,
+	buy_account_id  TEXT             NOT NULL,
+	sell_account_id TEXT             NOT NULL,
+	taker_side      TEXT             NOT NULL,
+	auction         BOOLEAN          NOT NULL,
+	executed_at     TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, trade_id)
+);
+CREATE INDEX IF NOT EXISTS exchange_trades_symbol_time ON exchange_trades (instance, symbol, executed_at, trade_id);
+CREATE INDEX IF NOT EXISTS exchange_trades_time ON exchange_trades (instance, executed_at, trade_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS exchange_accounts (
+	instance     TEXT        NOT NULL,
+	account_id   TEXT        NOT NULL,
+	tier         TEXT        NOT NULL,
+	metadata     JSONB       NOT NULL,
+	status       TEXT        NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL,
+	closed_at    TIMESTAMPTZ,
+	balances     JSONB       NOT NULL DEFAULT '{}',
+	permissions  JSONB       NOT NULL DEFAULT '[]',
+	api_key_hash TEXT        NOT NULL DEFAULT '',
+	leverage     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	api_keys     JSONB       NOT NULL DEFAULT '[]',
+	PRIMARY KEY (instance, account_id)
+);
+ALTER TABLE exchange_accounts ADD COLUMN IF NOT EXISTS balances JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE exchange_accounts ADD COLUMN IF NOT EXISTS permissions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE exchange_accounts ADD COLUMN IF NOT EXISTS api_key_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE exchange_accounts ADD COLUMN IF NOT EXISTS leverage DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE exchange_accounts ADD COLUMN IF NOT EXISTS api_keys JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS exchange_balance_journal;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS exchange_balance_journal (
+	instance    TEXT             NOT NULL,
+	journal_id  TEXT             NOT NULL,
+	sequence    BIGINT           NOT NULL,
+	transaction BIGINT           NOT NULL,
+	kind        TEXT             NOT NULL,
+	reference   TEXT             NOT NULL,
+	account_id  TEXT             NOT NULL,
+	asset       TEXT             NOT NULL,
+	bucket      TEXT             NOT NULL,
+	amount      DOUBLE PRECISION NOT NULL,
+	posted_at   TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, journal_id, sequence)
+);
+CREATE INDEX IF NOT EXISTS exchange_balance_journal_account ON exchange_balance_journal (instance, account_id, posted_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS exchange_outbox (
+	instance        TEXT        NOT NULL,
+	message_id      TEXT        NOT NULL,
+	dedup_key       TEXT        NOT NULL,
+	destination     TEXT        NOT NULL,
+	topic           TEXT        NOT NULL,
+	payload         JSONB       NOT NULL,
+	created_at      TIMESTAMPTZ NOT NULL,
+	attempts        INTEGER     NOT NULL DEFAULT 0,
+	last_error      TEXT        NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMPTZ NOT NULL,
+	dispatched_at   TIMESTAMPTZ,
+	PRIMARY KEY (instance, message_id)
+);
+CREATE INDEX IF NOT EXISTS exchange_outbox_due ON exchange_outbox (instance, next_attempt_at, created_at) WHERE dispatched_at IS NULL;
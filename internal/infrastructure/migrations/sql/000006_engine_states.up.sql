@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS exchange_engine_states (
+	instance TEXT        NOT NULL PRIMARY KEY,
+	sequence BIGINT      NOT NULL,
+	taken_at TIMESTAMPTZ NOT NULL,
+	state    JSONB       NOT NULL
+);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS exchange_orders (
+	instance        TEXT             NOT NULL,
+	order_id        TEXT             NOT NULL,
+	client_order_id TEXT             NOT NULL,
+	account_id      TEXT             NOT NULL,
+	symbol          TEXT             NOT NULL,
+	side            TEXT             NOT NULL,
+	type            TEXT             NOT NULL,
+	time_in_force   TEXT             NOT NULL,
+	price           DOUBLE PRECISION NOT NULL,
+	quantity        DOUBLE PRECISION NOT NULL,
+	filled_quantity DOUBLE PRECISION NOT NULL,
+	average_price   DOUBLE PRECISION NOT NULL,
+	status          TEXT             NOT NULL,
+	expires_at      TIMESTAMPTZ,
+	created_at      TIMESTAMPTZ      NOT NULL,
+	updated_at      TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, order_id)
+);
+CREATE INDEX IF NOT EXISTS exchange_orders_account ON exchange_orders (instance, account_id, created_at);
+CREATE INDEX IF NOT EXISTS exchange_orders_symbol_time ON exchange_orders (instance, symbol, created_at, order_id);
+CREATE INDEX IF NOT EXISTS exchange_orders_time ON exchange_orders (instance, created_at, order_id);
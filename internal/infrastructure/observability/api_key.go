@@ -0,0 +1,57 @@
+package observability
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+)
+
+// APIKeyHeader carries the caller's API key on REST requests
+const APIKeyHeader = "X-API-Key"
+
+// BinanceAPIKeyHeader carries the API key of clients using the Binance-compatible API
+const BinanceAPIKeyHeader = "X-MBX-APIKEY"
+
+// IdempotencyKeyHeader lets a client retry an order action without repeating it
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// CorrelationIDHeader ties a request to the audit events it causes; one is generated
+// when the client sends none, and echoed on the response either way
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// APIKeyMiddleware tags each request with its API key, correlation ID, and idempotency
+// key if sent, and counts it as one message. Health probes and metric scrapes are
+// skipped.
+func APIKeyMiddleware(recorder *keystats.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.URL.Path {
+		case "/api/v1/health", "/api/v1/ready", "/metrics":
+			c.Next()
+			return
+		}
+
+		apiKey := c.GetHeader(APIKeyHeader)
+		if apiKey == "" {
+			apiKey = c.GetHeader(BinanceAPIKeyHeader)
+		}
+		if apiKey == "" {
+			apiKey = keystats.Anonymous
+		}
+		recorder.Message(apiKey, "http")
+		ctx := keystats.WithAPIKey(c.Request.Context(), apiKey)
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if correlationID == "" {
+			correlationID = audit.NewID()
+		}
+		c.Header(CorrelationIDHeader, correlationID)
+		ctx = audit.WithCorrelationID(ctx, correlationID)
+		if key := c.GetHeader(IdempotencyKeyHeader); key != "" {
+			ctx = idempotency.WithKey(ctx, key)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
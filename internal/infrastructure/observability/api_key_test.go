@@ -0,0 +1,112 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	t.Run("counts_messages_per_key_and_tags_the_context", func(t *testing.T) {
+		// Given: A router counting messages per API key
+		recorder := keystats.NewRecorder(keystats.Policy{}, nil)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(recorder))
+		var seen string
+		router.GET("/api/v1/tickers", func(c *gin.Context) {
+			seen = keystats.APIKey(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+		router.GET("/api/v1/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		// When: A keyed request, an unkeyed request and a health probe arrive
+		keyed := httptest.NewRequest(http.MethodGet, "/api/v1/tickers", nil)
+		keyed.Header.Set(observability.APIKeyHeader, "bot-1")
+		router.ServeHTTP(httptest.NewRecorder(), keyed)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/tickers", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+
+		// Then: Each client request counts once under its key and the probe is ignored
+		if stats, _ := recorder.Stats("bot-1"); stats.Messages != 1 {
+			t.Errorf("Expected 1 message for bot-1, got %d", stats.Messages)
+		}
+		if stats, _ := recorder.Stats(keystats.Anonymous); stats.Messages != 1 {
+			t.Errorf("Expected 1 anonymous message, got %d", stats.Messages)
+		}
+		if seen != keystats.Anonymous {
+			t.Errorf("Expected the last handler to see the anonymous key, got %q", seen)
+		}
+	})
+
+	t.Run("tags_the_idempotency_key", func(t *testing.T) {
+		recorder := keystats.NewRecorder(keystats.Policy{}, nil)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(recorder))
+		var seen string
+		router.POST("/api/v1/orders", func(c *gin.Context) {
+			seen = idempotency.KeyFrom(c.Request.Context())
+			c.Status(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+		req.Header.Set(observability.IdempotencyKeyHeader, "retry-7")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if seen != "retry-7" {
+			t.Errorf("Expected the handler to see idempotency key retry-7, got %q", seen)
+		}
+	})
+
+	t.Run("tags_and_echoes_the_correlation_id", func(t *testing.T) {
+		recorder := keystats.NewRecorder(keystats.Policy{}, nil)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(recorder))
+		var seen string
+		router.POST("/api/v1/orders", func(c *gin.Context) {
+			seen = audit.CorrelationID(c.Request.Context())
+			c.Status(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+		req.Header.Set(observability.CorrelationIDHeader, "req-42")
+		sent := httptest.NewRecorder()
+		router.ServeHTTP(sent, req)
+		generated := httptest.NewRecorder()
+		router.ServeHTTP(generated, httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil))
+
+		if sent.Header().Get(observability.CorrelationIDHeader) != "req-42" {
+			t.Errorf("Expected the client's correlation ID echoed, got %q", sent.Header().Get(observability.CorrelationIDHeader))
+		}
+		if id := generated.Header().Get(observability.CorrelationIDHeader); id == "" || id != seen {
+			t.Errorf("Expected a generated correlation ID seen by the handler, got %q and %q", id, seen)
+		}
+	})
+
+	t.Run("falls_back_to_the_binance_key_header", func(t *testing.T) {
+		recorder := keystats.NewRecorder(keystats.Policy{}, nil)
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.APIKeyMiddleware(recorder))
+		router.GET("/api/v3/account", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v3/account", nil)
+		req.Header.Set(observability.BinanceAPIKeyHeader, "binance-bot")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if stats, _ := recorder.Stats("binance-bot"); stats.Messages != 1 {
+			t.Errorf("Expected 1 message for binance-bot, got %d", stats.Messages)
+		}
+	})
+}
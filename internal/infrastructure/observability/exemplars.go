@@ -0,0 +1,21 @@
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDExemplar is the exemplar label linking an observation to its trace
+const TraceIDExemplar = "trace_id"
+
+// TraceExemplar returns the exemplar linking an observation to the sampled trace
+// ctx is part of, or nil when it is in none, so that a slow bucket on a dashboard
+// leads to a trace the collector kept
+func TraceExemplar(ctx context.Context) map[string]string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() || !spanContext.IsSampled() {
+		return nil
+	}
+	return map[string]string{TraceIDExemplar: spanContext.TraceID().String()}
+}
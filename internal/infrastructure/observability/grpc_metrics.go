@@ -0,0 +1,62 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// REDMetricsUnaryInterceptor instruments gRPC server calls with RED pattern metrics,
+// as REDMetricsMiddleware does HTTP requests:
+// - grpc_requests_total: Total number of calls (counter)
+// - grpc_request_duration_seconds: Call duration (histogram, with the trace ID as exemplar when traced)
+// - grpc_request_errors_total: Calls ending in a status other than OK (counter)
+//
+// Labels: method (the full method name, e.g. /exchange.v1.TradingService/PlaceOrder)
+// and code (the status code name, e.g. OK or InvalidArgument)
+func REDMetricsUnaryInterceptor(metricsPort ports.MetricsPort) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		recordGRPCRequest(ctx, metricsPort, info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// REDMetricsStreamInterceptor instruments gRPC server streams like
+// REDMetricsUnaryInterceptor, timing each stream from open to close
+func REDMetricsStreamInterceptor(metricsPort ports.MetricsPort) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		ctx := context.Background()
+		if stream != nil {
+			ctx = stream.Context()
+		}
+		recordGRPCRequest(ctx, metricsPort, info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+func recordGRPCRequest(ctx context.Context, metricsPort ports.MetricsPort, method string, duration time.Duration, err error) {
+	code := status.Code(err)
+	labels := map[string]string{
+		"method": method,
+		"code":   code.String(),
+	}
+
+	// RED Metric 1: Rate - Total calls
+	metricsPort.IncCounter("grpc_requests_total", labels)
+
+	// RED Metric 2: Duration - Call duration histogram, linked to its trace
+	metricsPort.ObserveHistogramWithExemplar("grpc_request_duration_seconds", duration.Seconds(), labels, TraceExemplar(ctx))
+
+	// RED Metric 3: Errors - Any status other than OK
+	if err != nil {
+		metricsPort.IncCounter("grpc_request_errors_total", labels)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// scrape returns the adapter's metrics as Prometheus would read them
+func scrape(metricsPort *observability.PrometheusMetricsAdapter) string {
+	w := httptest.NewRecorder()
+	metricsPort.GetHTTPHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return w.Body.String()
+}
+
+// TestREDMetricsInterceptors verifies RED pattern metrics for gRPC server calls
+// Following BDD Given/When/Then pattern
+func TestREDMetricsInterceptors(t *testing.T) {
+	constantLabels := map[string]string{"service": "exchange-simulator"}
+
+	t.Run("instruments_unary_calls_by_method_and_code", func(t *testing.T) {
+		// Given: The unary interceptor over a Prometheus metrics adapter
+		metricsPort := observability.NewPrometheusMetricsAdapter(constantLabels)
+		interceptor := observability.REDMetricsUnaryInterceptor(metricsPort)
+		info := &grpc.UnaryServerInfo{FullMethod: "/exchange.v1.TradingService/PlaceOrder"}
+
+		// When: One call succeeds and one is rejected
+		interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.InvalidArgument, "bad quantity")
+		})
+
+		// Then: The rejection passes through, and rate, duration and errors are recorded
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected INVALID_ARGUMENT, got %v", err)
+		}
+		output := scrape(metricsPort)
+		for _, expected := range []string{
+			`grpc_requests_total{code="OK",method="/exchange.v1.TradingService/PlaceOrder"`,
+			`grpc_requests_total{code="InvalidArgument",method="/exchange.v1.TradingService/PlaceOrder"`,
+			`grpc_request_duration_seconds_count{code="OK",method="/exchange.v1.TradingService/PlaceOrder"`,
+			`grpc_request_errors_total{code="InvalidArgument",method="/exchange.v1.TradingService/PlaceOrder"`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected %s in metrics", expected)
+			}
+		}
+		if strings.Contains(output, `grpc_request_errors_total{code="OK"`) {
+			t.Error("Expected successful calls not to count as errors")
+		}
+	})
+
+	t.Run("instruments_streams", func(t *testing.T) {
+		// Given: The stream interceptor over a Prometheus metrics adapter
+		metricsPort := observability.NewPrometheusMetricsAdapter(constantLabels)
+		interceptor := observability.REDMetricsStreamInterceptor(metricsPort)
+		info := &grpc.StreamServerInfo{FullMethod: "/exchange.v1.MarketDataService/StreamOrderBook", IsServerStream: true}
+
+		// When: A stream ends with the client gone
+		interceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+			return status.Error(codes.Canceled, "context canceled")
+		})
+
+		// Then: It is counted as a call that erred
+		if output := scrape(metricsPort); !strings.Contains(output, `grpc_request_errors_total{code="Canceled",method="/exchange.v1.MarketDataService/StreamOrderBook"`) {
+			t.Error("Expected the stream in grpc_request_errors_total")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+)
+
+// logSamplingTick is the window sampling counts entries over
+const logSamplingTick = time.Second
+
+// LogSettings are the log level, the components logging at another level and the
+// sampling of high-volume components
+type LogSettings struct {
+	Level      string                 `json:"level"`                // Of entries naming no component, or one not listed; "info"
+	Components map[string]string      `json:"components,omitempty"` // Level by component, e.g. {"matching_engine": "debug"}
+	Sampling   map[string]LogSampling `json:"sampling,omitempty"`   // By component
+}
+
+// LogSampling bounds how often one message is logged at debug or info level. Each
+// second the first Initial entries of a message are logged, then one in every
+// Thereafter; warnings and errors are never sampled.
+type LogSampling struct {
+	Initial    int `json:"initial"`
+	Thereafter int `json:"thereafter"` // 0 drops every entry after the first Initial
+}
+
+// LogStatus is the settings in force and what sampling has dropped since startup
+type LogStatus struct {
+	LogSettings
+	Dropped map[string]uint64 `json:"dropped"` // Entries sampled out, by component
+}
+
+// LogControl changes a logger's level, per-component levels and sampling at runtime.
+// It wraps the logger's formatter, dropping entries below their component's level
+// or sampled out, and keeps the logger's own level at the most verbose in force so
+// disabled levels still cost nothing. Components are read from the "component" field.
+type LogControl struct {
+	logger    *logrus.Logger
+	formatter logrus.Formatter
+
+	mu         sync.Mutex
+	settings   LogSettings
+	base       logrus.Level
+	components map[string]logrus.Level
+	counts     map[sampleKey]*sampleCount
+	dropped    map[string]uint64
+}
+
+type sampleKey struct {
+	component string
+	level     logrus.Level
+	message   string
+}
+
+type sampleCount struct {
+	tick time.Time // Start of the window counted
+	seen int
+}
+
+// NewLogControl takes over logger's formatter, keeping its current level
+func NewLogControl(logger *logrus.Logger) *LogControl {
+	c := &LogControl{
+		logger:     logger,
+		formatter:  logger.Formatter,
+		settings:   LogSettings{Level: logger.GetLevel().String()},
+		base:       logger.GetLevel(),
+		components: make(map[string]logrus.Level),
+		counts:     make(map[sampleKey]*sampleCount),
+		dropped:    make(map[string]uint64),
+	}
+	logger.SetFormatter(c)
+	return c
+}
+
+// Set replaces the settings in force. Invalid settings change nothing.
+func (c *LogControl) Set(settings LogSettings) (LogSettings, error) {
+	base, err := logrus.ParseLevel(settings.Level)
+	if err != nil {
+		return LogSettings{}, err
+	}
+	settings.Level = base.String()
+	components := make(map[string]logrus.Level, len(settings.Components))
+	names := make(map[string]string, len(settings.Components))
+	for component, name := range settings.Components {
+		level, err := logrus.ParseLevel(name)
+		if err != nil {
+			return LogSettings{}, fmt.Errorf("component %s: %w", component, err)
+		}
+		components[component], names[component] = level, level.String()
+	}
+	settings.Components = names
+	for component, sampling := range settings.Sampling {
+		if sampling.Initial < 0 || sampling.Thereafter < 0 {
+			return LogSettings{}, fmt.Errorf("sampling of %s must not be negative", component)
+		}
+	}
+
+	verbose := base
+	for _, level := range components {
+		if level > verbose {
+			verbose = level
+		}
+	}
+
+	c.mu.Lock()
+	c.settings, c.base, c.components = settings, base, components
+	c.counts = make(map[sampleKey]*sampleCount)
+	c.mu.Unlock()
+	c.logger.SetLevel(verbose)
+
+	c.logger.WithFields(logrus.Fields{
+		"level":      settings.Level,
+		"components": len(components),
+		"sampled":    len(settings.Sampling),
+	}).Info("Log settings changed")
+	return settings, nil
+}
+
+// Status returns the settings in force and the entries sampled out
+func (c *LogControl) Status() LogStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := make(map[string]uint64, len(c.dropped))
+	for component, count := range c.dropped {
+		dropped[component] = count
+	}
+	return LogStatus{LogSettings: c.settings, Dropped: dropped}
+}
+
+// Apply sets the settings held in the configuration service, as a watched setting
+func (c *LogControl) Apply(ctx context.Context, raw json.RawMessage) error {
+	var settings LogSettings
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return fmt.Errorf("invalid log settings: %w", err)
+	}
+	_, err := c.Set(settings)
+	return err
+}
+
+// Format formats the entries kept with the wrapped formatter; dropped entries are
+// written as nothing
+func (c *LogControl) Format(entry *logrus.Entry) ([]byte, error) {
+	if !c.keep(entry) {
+		return nil, nil
+	}
+	return c.formatter.Format(entry)
+}
+
+// keep decides whether entry is logged under the settings in force
+func (c *LogControl) keep(entry *logrus.Entry) bool {
+	component, _ := entry.Data[logfields.Component].(string)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	threshold, ok := c.components[component]
+	if !ok {
+		threshold = c.base
+	}
+	if entry.Level > threshold {
+		return false
+	}
+
+	sampling, ok := c.settings.Sampling[component]
+	if !ok || entry.Level < logrus.InfoLevel {
+		return true
+	}
+	key := sampleKey{component: component, level: entry.Level, message: entry.Message}
+	count := c.counts[key]
+	tick := entry.Time.Truncate(logSamplingTick)
+	if count == nil || !count.tick.Equal(tick) {
+		count = &sampleCount{tick: tick}
+		c.counts[key] = count
+	}
+	count.seen++
+	if count.seen <= sampling.Initial {
+		return true
+	}
+	if sampling.Thereafter > 0 && (count.seen-sampling.Initial)%sampling.Thereafter == 0 {
+		return true
+	}
+	c.dropped[component]++
+	return false
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// TestLogControl verifies levels and sampling change without a restart
+// Following BDD Given/When/Then pattern
+func TestLogControl(t *testing.T) {
+	newLogger := func() (*logrus.Logger, *bytes.Buffer) {
+		var out bytes.Buffer
+		logger := logrus.New()
+		logger.SetOutput(&out)
+		logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+		logger.SetLevel(logrus.InfoLevel)
+		return logger, &out
+	}
+	engine := func(logger *logrus.Logger) *logrus.Entry {
+		return logger.WithField(logfields.Component, logfields.ComponentMatchingEngine)
+	}
+
+	t.Run("logs_one_component_at_debug_leaving_others_at_the_base_level", func(t *testing.T) {
+		// Given: A logger at info level under log control
+		logger, out := newLogger()
+		control := observability.NewLogControl(logger)
+
+		// When: The matching engine is switched to debug
+		if _, err := control.Set(observability.LogSettings{Level: "info", Components: map[string]string{"matching_engine": "debug"}}); err != nil {
+			t.Fatalf("Expected the settings applied, got %v", err)
+		}
+		out.Reset()
+		engine(logger).Debug("Order matched")
+		logger.WithField(logfields.Component, logfields.ComponentHTTP).Debug("HTTP request completed")
+		logger.Debug("Unattributed detail")
+
+		// Then: Only the engine's debug entry is written
+		logged := out.String()
+		if !strings.Contains(logged, "Order matched") || strings.Contains(logged, "HTTP request completed") || strings.Contains(logged, "Unattributed detail") {
+			t.Errorf("Expected only the engine at debug, got %q", logged)
+		}
+	})
+
+	t.Run("samples_repeated_messages_but_never_warnings", func(t *testing.T) {
+		// Given: The matching engine sampled at 2 per second, then 1 in 3
+		logger, out := newLogger()
+		control := observability.NewLogControl(logger)
+		control.Set(observability.LogSettings{Level: "info", Sampling: map[string]observability.LogSampling{"matching_engine": {Initial: 2, Thereafter: 3}}})
+		out.Reset()
+
+		// When: Within one second it logs the same message 8 times, and a warning 3 times
+		at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		for i := 0; i < 8; i++ {
+			engine(logger).WithTime(at).Info("Order placed")
+		}
+		for i := 0; i < 3; i++ {
+			engine(logger).WithTime(at).Warn("Order book crossed")
+		}
+
+		// Then: 2, then the 3rd and 6th after them, are kept; every warning is
+		if placed := strings.Count(out.String(), "Order placed"); placed != 4 {
+			t.Errorf("Expected 4 of 8 entries kept, got %d", placed)
+		}
+		if crossed := strings.Count(out.String(), "Order book crossed"); crossed != 3 {
+			t.Errorf("Expected every warning kept, got %d", crossed)
+		}
+
+		// And: What was dropped is reported
+		if dropped := control.Status().Dropped["matching_engine"]; dropped != 4 {
+			t.Errorf("Expected 4 entries dropped, got %d", dropped)
+		}
+	})
+
+	t.Run("applies_settings_from_the_configuration_service_and_refuses_invalid_ones", func(t *testing.T) {
+		// Given: A logger at info level under log control
+		logger, _ := newLogger()
+		control := observability.NewLogControl(logger)
+
+		// When: The configuration service holds a debug level, then an unknown one
+		applied := control.Apply(context.Background(), json.RawMessage(`{"level": "debug"}`))
+		refused := control.Apply(context.Background(), json.RawMessage(`{"level": "verbose"}`))
+
+		// Then: The first applies and the second changes nothing
+		if applied != nil || refused == nil {
+			t.Fatalf("Expected the first applied and the second refused, got %v and %v", applied, refused)
+		}
+		if status := control.Status(); status.Level != "debug" || logger.GetLevel() != logrus.DebugLevel {
+			t.Errorf("Expected debug in force, got %s with the logger at %s", status.Level, logger.GetLevel())
+		}
+	})
+}
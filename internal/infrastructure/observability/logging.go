@@ -0,0 +1,63 @@
+package observability
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+)
+
+// RequestIDHeader identifies one request in the logs; one is generated when the
+// client sends none, and echoed on the response either way
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLoggingMiddleware tags each request with its request ID, and the account
+// and order IDs in its route (:account_id, :order_id), so the services log them on
+// every entry about the request; then logs the request's outcome with the same IDs
+// and, as APIKeyMiddleware runs after it, the correlation ID.
+//
+// Responses of 500 and above are logged as warnings, other errors as information
+// and successes at debug level.
+func RequestLoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = audit.NewID()
+		}
+		c.Header(RequestIDHeader, requestID)
+		ctx := logfields.WithRequestID(c.Request.Context(), requestID)
+		ctx = logfields.WithAccountID(ctx, c.Param("account_id"))
+		ctx = logfields.WithOrderID(ctx, c.Param("order_id"))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+		fields := logrus.Fields{
+			"method":            c.Request.Method,
+			"route":             route,
+			"code":              c.Writer.Status(),
+			"duration":          time.Since(start),
+			logfields.Component: logfields.ComponentHTTP,
+		}
+		for name, value := range logfields.Fields(c.Request.Context()) {
+			fields[name] = value
+		}
+		entry := logger.WithFields(fields)
+		switch code := c.Writer.Status(); {
+		case code >= 500:
+			entry.Warn("HTTP request failed")
+		case code >= 400:
+			entry.Info("HTTP request failed")
+		default:
+			entry.Debug("HTTP request completed")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// TestRequestLoggingMiddleware verifies requests are tagged with their IDs
+// Following BDD Given/When/Then pattern
+func TestRequestLoggingMiddleware(t *testing.T) {
+	newRouter := func(logger *logrus.Logger, seen *map[string]string) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(observability.RequestLoggingMiddleware(logger))
+		router.Use(observability.APIKeyMiddleware(keystats.NewRecorder(keystats.Policy{}, nil)))
+		router.DELETE("/api/v1/accounts/:account_id/orders/:order_id", func(c *gin.Context) {
+			*seen = logfields.Fields(c.Request.Context())
+			c.Status(http.StatusNotFound)
+		})
+		return router
+	}
+
+	t.Run("tags_the_request_context_and_its_outcome_with_the_ids", func(t *testing.T) {
+		// Given: A router logging requests, and a request carrying its request and correlation IDs
+		logger, hook := logtest.NewNullLogger()
+		var seen map[string]string
+		router := newRouter(logger, &seen)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/accounts/acct-1/orders/BTC-USD-7", nil)
+		req.Header.Set(observability.RequestIDHeader, "req-1")
+		req.Header.Set(observability.CorrelationIDHeader, "corr-1")
+
+		// When: The request is served
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Then: The handler's context and the logged outcome carry every ID
+		expected := map[string]string{"request_id": "req-1", "correlation_id": "corr-1", "account_id": "acct-1", "order_id": "BTC-USD-7"}
+		entry := hook.LastEntry()
+		if entry == nil || entry.Level != logrus.InfoLevel || entry.Data["code"] != http.StatusNotFound {
+			t.Fatalf("Expected the failed request logged as information, got %+v", entry)
+		}
+		for name, value := range expected {
+			if seen[name] != value || entry.Data[name] != value {
+				t.Errorf("Expected %s=%s, got %v in the context and %v logged", name, value, seen, entry.Data)
+			}
+		}
+		if w.Header().Get(observability.RequestIDHeader) != "req-1" {
+			t.Error("Expected the request ID echoed")
+		}
+	})
+
+	t.Run("generates_a_request_id_when_none_is_sent", func(t *testing.T) {
+		// Given: A router logging requests
+		logger, _ := logtest.NewNullLogger()
+		var seen map[string]string
+		router := newRouter(logger, &seen)
+
+		// When: A request without a request ID is served
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/accounts/acct-1/orders/BTC-USD-7", nil))
+
+		// Then: One is generated and echoed
+		if id := w.Header().Get(observability.RequestIDHeader); id == "" || seen["request_id"] != id {
+			t.Errorf("Expected a generated request ID, got %q and %v", id, seen)
+		}
+	})
+}
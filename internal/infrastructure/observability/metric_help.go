@@ -0,0 +1,73 @@
+package observability
+
+import "fmt"
+
+// metricHelp describes the metrics the venue reports, for their HELP lines
+var metricHelp = map[string]string{
+	// Requests
+	"http_requests_total":           "HTTP requests served, by method, route and status code",
+	"http_request_duration_seconds": "Time to serve an HTTP request, in seconds",
+	"http_request_errors_total":     "HTTP requests answered with a 4xx or 5xx status",
+	"grpc_requests_total":           "gRPC calls served, by method and status code",
+	"grpc_request_duration_seconds": "Time to serve a gRPC call, in seconds",
+	"grpc_request_errors_total":     "gRPC calls that ended with a status other than OK",
+	"service_dependency_ready":      "Whether a dependency passed the last readiness check (1) or not (0)",
+
+	// Orders and trades
+	"exchange_orders_placed_total":            "Orders accepted, by symbol and side",
+	"exchange_orders_rejected_total":          "Orders rejected, by symbol and reject reason",
+	"exchange_orders_canceled_total":          "Orders canceled on request, by symbol",
+	"exchange_trades_total":                   "Trades executed, by symbol",
+	"exchange_trade_volume_total":             "Base quantity traded, by symbol",
+	"exchange_trade_notional_total":           "Quote notional traded, by symbol",
+	"exchange_trade_notional":                 "Quote notional of each trade, by symbol",
+	"exchange_order_to_trade_latency_seconds": "Time from an order's creation to each of its fills, in seconds",
+	"exchange_fill_rate":                      "Share of the quantity ordered that has filled, by symbol",
+	"exchange_open_orders":                    "Orders resting on the book, by symbol",
+	"exchange_book_depth":                     "Quantity resting on the book, by symbol and side",
+	"exchange_spread":                         "Best ask less best bid, by symbol; 0 while a side is empty",
+	"exchange_engine_queue_depth":             "Commands queued for a symbol's matching engine when sampled",
+
+	// Accounts and API keys
+	"exchange_accounts":                   "Accounts on the venue, by state",
+	"exchange_top_account_notional_share": "Share of the notional traded by the most active account",
+	"api_key_messages_total":              "Messages received per API key, by channel",
+	"api_key_message_rate":                "Messages per second received per API key",
+	"api_key_orders_total":                "Orders placed per API key",
+	"api_key_cancels_total":               "Cancels requested per API key",
+	"api_key_amends_total":                "Amends requested per API key",
+	"api_key_rejections_total":            "Requests rejected per API key",
+	"api_key_order_to_trade_ratio":        "Orders placed per trade, per API key",
+	"api_key_rejection_ratio":             "Share of requests rejected, per API key",
+
+	// Venue operations
+	"exchange_degradation_mode":            "Whether the venue is in a degradation mode (1) or not (0), by mode",
+	"exchange_degradation_refused_total":   "Operations refused by the degradation mode, by mode and operation",
+	"exchange_misbehavior_total":           "Misbehaviors injected into responses, by kind",
+	"exchange_replay_requests_total":       "Requests replayed from a recorded log, by kind and outcome",
+	"exchange_write_behind_queued":         "Writes held by a write-behind queue, by queue",
+	"exchange_write_behind_waits_total":    "Writes that waited for room in a write-behind queue, by queue",
+	"exchange_write_behind_wait_seconds":   "Time a write waited for room in a write-behind queue, in seconds",
+	"exchange_write_behind_overflow_total": "Writes that gave up waiting for room in a write-behind queue, by queue",
+	"persistence_call_duration_seconds":    "Time a storage call took, in seconds, by component, operation and outcome",
+
+	// Audit and dependencies
+	"audit_events_published_total":              "Audit events queued for delivery, by event type",
+	"audit_events_dropped_total":                "Audit events dropped with the queue full, by event type",
+	"audit_events_delivered_total":              "Audit events delivered, by event type",
+	"audit_events_failed_total":                 "Audit events whose delivery failed, by event type",
+	"configuration_changes_total":               "Changes seen to watched configuration keys, by key",
+	"inter_service_retries_total":               "Calls to another service retried, by service and status code",
+	"inter_service_circuit_breaker_state":       "Circuit breaker state per service: 0 closed, 1 half open, 2 open",
+	"inter_service_circuit_breaker_trips_total": "Times a service's circuit breaker opened",
+	"inter_service_stale_connections_total":     "Pooled connections to a service found stale and closed",
+}
+
+// helpOf returns a metric's description; metrics not listed are described by their
+// kind and name, e.g. "Summary of fill_ratio"
+func helpOf(kind, name string) string {
+	if help, listed := metricHelp[name]; listed {
+		return help
+	}
+	return fmt.Sprintf("%s of %s", kind, name)
+}
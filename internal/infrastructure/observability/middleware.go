@@ -14,7 +14,7 @@ import (
 //
 // This middleware instruments all HTTP requests with:
 // - requests_total: Total number of requests (counter)
-// - request_duration_seconds: Request duration (histogram)
+// - request_duration_seconds: Request duration (histogram, with the trace ID as exemplar when traced)
 // - request_errors_total: Total number of errors (counter, 4xx/5xx)
 //
 // Labels: method, route, code (low cardinality)
@@ -44,8 +44,8 @@ func REDMetricsMiddleware(metricsPort ports.MetricsPort) gin.HandlerFunc {
 		// RED Metric 1: Rate - Total requests
 		metricsPort.IncCounter("http_requests_total", labels)
 
-		// RED Metric 2: Duration - Request duration histogram
-		metricsPort.ObserveHistogram("http_request_duration_seconds", duration, labels)
+		// RED Metric 2: Duration - Request duration histogram, linked to its trace
+		metricsPort.ObserveHistogramWithExemplar("http_request_duration_seconds", duration, labels, TraceExemplar(c.Request.Context()))
 
 		// RED Metric 3: Errors - Error counter (4xx, 5xx)
 		if c.Writer.Status() >= 400 {
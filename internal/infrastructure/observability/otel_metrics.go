@@ -0,0 +1,326 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// Compile-time check that OTelMetricsAdapter implements MetricsPort
+var _ ports.MetricsPort = (*OTelMetricsAdapter)(nil)
+
+// meterName names the instrumentation metrics are recorded by
+const meterName = tracerName
+
+// OTelMetricsAdapter implements MetricsPort using the OpenTelemetry metrics SDK,
+// for environments that standardize on an OTel collector. Metrics are pushed
+// through a reader rather than scraped, and carry the service's identity as
+// resource attributes instead of constant labels. Summaries are recorded as
+// base-2 exponential histograms, from which the backend derives percentiles;
+// exemplars are dropped, as this SDK version does not record them.
+type OTelMetricsAdapter struct {
+	provider *sdkmetric.MeterProvider
+	meter    metric.Meter
+
+	// Instruments (lazy-initialized)
+	counters   map[string]metric.Float64Counter
+	histograms map[string]metric.Float64Histogram
+	summaries  map[string]metric.Float64Histogram
+	gauges     map[string]*otelGauge
+
+	// Bucket upper bounds of histograms not using ports.DefaultDurationBuckets
+	buckets map[string][]float64
+
+	// Mutex for thread-safe lazy initialization
+	mu sync.RWMutex
+
+	// Aggregation of each histogram, stored before it is created for the view to read
+	aggregations sync.Map
+}
+
+// otelGauge holds the last value set for each label set of a gauge, reported
+// whenever the reader collects
+type otelGauge struct {
+	mu     sync.Mutex
+	values map[attribute.Distinct]gaugeValue
+}
+
+type gaugeValue struct {
+	attributes attribute.Set
+	value      float64
+}
+
+// NewOTelMetricsAdapter creates a metrics adapter collected by reader, e.g. one from
+// NewOTLPMetricReader. service identifies the metrics (service.name,
+// service.instance.id and service.version).
+func NewOTelMetricsAdapter(reader sdkmetric.Reader, service ports.MetricsLabels) *OTelMetricsAdapter {
+	a := &OTelMetricsAdapter{
+		counters:   make(map[string]metric.Float64Counter),
+		histograms: make(map[string]metric.Float64Histogram),
+		summaries:  make(map[string]metric.Float64Histogram),
+		gauges:     make(map[string]*otelGauge),
+		buckets:    make(map[string][]float64),
+	}
+	a.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(serviceResource(service)),
+		sdkmetric.WithView(a.histogramView),
+	)
+	a.meter = a.provider.Meter(meterName)
+	return a
+}
+
+// NewOTLPMetricReader creates a reader exporting metrics every interval over
+// OTLP/HTTP to the collector at endpoint, e.g. "http://otel-collector:4318". An
+// http endpoint is sent to in plain text; the path defaults to /v1/metrics.
+func NewOTLPMetricReader(ctx context.Context, endpoint string, interval time.Duration) (sdkmetric.Reader, error) {
+	parsed, err := parseOTLPEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(parsed.Host)}
+	if parsed.Scheme == "http" {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if path := strings.TrimRight(parsed.Path, "/"); path != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(path+"/v1/metrics"))
+	}
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval)), nil
+}
+
+// IncCounter increments a counter metric
+func (a *OTelMetricsAdapter) IncCounter(name string, labels map[string]string) {
+	a.AddCounter(name, 1, labels)
+}
+
+// AddCounter increases a counter metric by value
+func (a *OTelMetricsAdapter) AddCounter(name string, value float64, labels map[string]string) {
+	counter := a.getOrCreateCounter(name)
+	counter.Add(context.Background(), value, metric.WithAttributeSet(attributeSet(labels)))
+}
+
+// ObserveHistogram records a value in a histogram metric
+func (a *OTelMetricsAdapter) ObserveHistogram(name string, value float64, labels map[string]string) {
+	histogram := a.getOrCreateHistogram(name)
+	histogram.Record(context.Background(), value, metric.WithAttributeSet(attributeSet(labels)))
+}
+
+// ObserveHistogramWithExemplar records a value in a histogram metric; the exemplar
+// is dropped
+func (a *OTelMetricsAdapter) ObserveHistogramWithExemplar(name string, value float64, labels map[string]string, exemplar map[string]string) {
+	a.ObserveHistogram(name, value, labels)
+}
+
+// SetHistogramBuckets sets the bucket upper bounds a histogram is created with
+func (a *OTelMetricsAdapter) SetHistogramBuckets(name string, buckets []float64) error {
+	if err := validateBuckets(name, buckets); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.histograms[name]; exists {
+		if !slices.Equal(a.bucketsOf(name), buckets) {
+			return errHistogramObserved(name)
+		}
+		return nil
+	}
+	a.buckets[name] = slices.Clone(buckets)
+	return nil
+}
+
+// ObserveSummary records a value in an exponential histogram standing in for a summary
+func (a *OTelMetricsAdapter) ObserveSummary(name string, value float64, labels map[string]string) {
+	summary := a.getOrCreateSummary(name)
+	summary.Record(context.Background(), value, metric.WithAttributeSet(attributeSet(labels)))
+}
+
+// SetGauge sets a gauge metric to a specific value
+func (a *OTelMetricsAdapter) SetGauge(name string, value float64, labels map[string]string) {
+	gauge := a.getOrCreateGauge(name)
+	attributes := attributeSet(labels)
+
+	gauge.mu.Lock()
+	gauge.values[attributes.Equivalent()] = gaugeValue{attributes: attributes, value: value}
+	gauge.mu.Unlock()
+}
+
+// GetHTTPHandler answers 404: metrics are pushed to the collector, not scraped
+func (a *OTelMetricsAdapter) GetHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "metrics are exported over OTLP (METRICS_BACKEND=otel)", http.StatusNotFound)
+	})
+}
+
+// ForceFlush exports the metrics recorded so far
+func (a *OTelMetricsAdapter) ForceFlush(ctx context.Context) error {
+	return a.provider.ForceFlush(ctx)
+}
+
+// Shutdown exports the metrics recorded so far and stops exporting
+func (a *OTelMetricsAdapter) Shutdown(ctx context.Context) error {
+	return a.provider.Shutdown(ctx)
+}
+
+// histogramView gives each histogram the aggregation stored for it on creation
+func (a *OTelMetricsAdapter) histogramView(instrument sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+	aggregation, set := a.aggregations.Load(instrument.Name)
+	if !set {
+		return sdkmetric.Stream{}, false
+	}
+	return sdkmetric.Stream{
+		Name:        instrument.Name,
+		Description: instrument.Description,
+		Unit:        instrument.Unit,
+		Aggregation: aggregation.(sdkmetric.Aggregation),
+	}, true
+}
+
+// getOrCreateCounter gets or creates a counter instrument (thread-safe lazy initialization)
+func (a *OTelMetricsAdapter) getOrCreateCounter(name string) metric.Float64Counter {
+	a.mu.RLock()
+	counter, exists := a.counters[name]
+	a.mu.RUnlock()
+
+	if exists {
+		return counter
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if counter, exists := a.counters[name]; exists {
+		return counter
+	}
+
+	counter, err := a.meter.Float64Counter(name, metric.WithDescription(helpOf("Counter", name)))
+	if err != nil {
+		otel.Handle(err)
+	}
+	a.counters[name] = counter
+	return counter
+}
+
+// getOrCreateHistogram gets or creates a histogram instrument with the buckets set
+// for it, or the duration buckets (thread-safe lazy initialization)
+func (a *OTelMetricsAdapter) getOrCreateHistogram(name string) metric.Float64Histogram {
+	a.mu.RLock()
+	histogram, exists := a.histograms[name]
+	a.mu.RUnlock()
+
+	if exists {
+		return histogram
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if histogram, exists := a.histograms[name]; exists {
+		return histogram
+	}
+
+	a.aggregations.Store(name, sdkmetric.AggregationExplicitBucketHistogram{Boundaries: a.bucketsOf(name)})
+	histogram, err := a.meter.Float64Histogram(name, metric.WithDescription(helpOf("Histogram", name)))
+	if err != nil {
+		otel.Handle(err)
+	}
+	a.histograms[name] = histogram
+	return histogram
+}
+
+// bucketsOf returns the bucket upper bounds of a histogram; callers hold mu
+func (a *OTelMetricsAdapter) bucketsOf(name string) []float64 {
+	if buckets, set := a.buckets[name]; set {
+		return buckets
+	}
+	return ports.DefaultDurationBuckets
+}
+
+// getOrCreateSummary gets or creates an exponential histogram instrument
+// (thread-safe lazy initialization)
+func (a *OTelMetricsAdapter) getOrCreateSummary(name string) metric.Float64Histogram {
+	a.mu.RLock()
+	summary, exists := a.summaries[name]
+	a.mu.RUnlock()
+
+	if exists {
+		return summary
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if summary, exists := a.summaries[name]; exists {
+		return summary
+	}
+
+	// 160 buckets at the finest scale that fits the observed range, which keeps
+	// percentiles within a few percent of the true value
+	a.aggregations.Store(name, sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20})
+	summary, err := a.meter.Float64Histogram(name, metric.WithDescription(helpOf("Summary", name)))
+	if err != nil {
+		otel.Handle(err)
+	}
+	a.summaries[name] = summary
+	return summary
+}
+
+// getOrCreateGauge gets or creates an observable gauge reporting the values set on
+// it (thread-safe lazy initialization)
+func (a *OTelMetricsAdapter) getOrCreateGauge(name string) *otelGauge {
+	a.mu.RLock()
+	gauge, exists := a.gauges[name]
+	a.mu.RUnlock()
+
+	if exists {
+		return gauge
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if gauge, exists := a.gauges[name]; exists {
+		return gauge
+	}
+
+	gauge = &otelGauge{values: make(map[attribute.Distinct]gaugeValue)}
+	_, err := a.meter.Float64ObservableGauge(name, metric.WithDescription(helpOf("Gauge", name)), metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
+		gauge.mu.Lock()
+		defer gauge.mu.Unlock()
+		for _, v := range gauge.values {
+			observer.Observe(v.value, metric.WithAttributeSet(v.attributes))
+		}
+		return nil
+	}))
+	if err != nil {
+		otel.Handle(err)
+	}
+	a.gauges[name] = gauge
+	return gauge
+}
+
+// attributeSet converts labels to OTel attributes
+func attributeSet(labels map[string]string) attribute.Set {
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+	for key, value := range labels {
+		attributes = append(attributes, attribute.String(key, value))
+	}
+	return attribute.NewSet(attributes...)
+}
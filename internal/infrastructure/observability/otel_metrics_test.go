@@ -0,0 +1,172 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// newOTelMetrics returns an OTel metrics adapter and the reader collecting it on demand
+func newOTelMetrics(t *testing.T) (*observability.OTelMetricsAdapter, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	metrics := observability.NewOTelMetricsAdapter(reader, ports.MetricsLabels{Service: "exchange-simulator", Instance: "exchange-OKX"})
+	t.Cleanup(func() { metrics.Shutdown(context.Background()) })
+	return metrics, reader
+}
+
+// collect returns the metrics recorded so far, by name
+func collect(t *testing.T, reader *sdkmetric.ManualReader) (metricdata.ResourceMetrics, map[string]metricdata.Aggregation) {
+	var collected metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &collected); err != nil {
+		t.Fatalf("Expected metrics to collect, got %v", err)
+	}
+	byName := make(map[string]metricdata.Aggregation)
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			byName[m.Name] = m.Data
+		}
+	}
+	return collected, byName
+}
+
+func symbolIs(set attribute.Set, symbol string) bool {
+	value, ok := set.Value("symbol")
+	return ok && value.AsString() == symbol
+}
+
+func TestOTelMetricsAdapter(t *testing.T) {
+	t.Run("counters_sum_per_label_set", func(t *testing.T) {
+		// Given: An OTel metrics adapter
+		metrics, reader := newOTelMetrics(t)
+
+		// When: A counter is incremented and added to for two symbols
+		metrics.IncCounter("exchange_trades_total", map[string]string{"symbol": "BTC-USD"})
+		metrics.AddCounter("exchange_trades_total", 2, map[string]string{"symbol": "BTC-USD"})
+		metrics.IncCounter("exchange_trades_total", map[string]string{"symbol": "ETH-USD"})
+
+		// Then: Each symbol's total is exported as a monotonic sum
+		collected, byName := collect(t, reader)
+		sum, ok := byName["exchange_trades_total"].(metricdata.Sum[float64])
+		if !ok || !sum.IsMonotonic {
+			t.Fatalf("Expected a monotonic sum, got %T", byName["exchange_trades_total"])
+		}
+		for _, point := range sum.DataPoints {
+			if symbolIs(point.Attributes, "BTC-USD") && point.Value != 3 {
+				t.Errorf("Expected BTC-USD total 3, got %v", point.Value)
+			}
+		}
+
+		// And: The service identity is carried by the resource
+		if value, _ := collected.Resource.Set().Value("service.instance.id"); value.AsString() != "exchange-OKX" {
+			t.Errorf("Expected service.instance.id exchange-OKX, got %q", value.AsString())
+		}
+	})
+
+	t.Run("histograms_use_configured_buckets", func(t *testing.T) {
+		// Given: One histogram with its own buckets and one left on the defaults
+		metrics, reader := newOTelMetrics(t)
+		buckets := ports.ExponentialBuckets(10, 10, 3)
+		if err := metrics.SetHistogramBuckets("exchange_trade_notional", buckets); err != nil {
+			t.Fatalf("Expected buckets to be accepted, got %v", err)
+		}
+
+		// When: Both are observed
+		metrics.ObserveHistogram("exchange_trade_notional", 500, map[string]string{"symbol": "BTC-USD"})
+		metrics.ObserveHistogramWithExemplar("call_seconds", 0.2, nil, map[string]string{observability.TraceIDExemplar: "abc"})
+
+		// Then: Each is exported with its bucket bounds
+		_, byName := collect(t, reader)
+		notional := byName["exchange_trade_notional"].(metricdata.Histogram[float64])
+		if got := notional.DataPoints[0].Bounds; !slices.Equal(got, buckets) {
+			t.Errorf("Expected bounds %v, got %v", buckets, got)
+		}
+		if got := notional.DataPoints[0].BucketCounts; !slices.Equal(got, []uint64{0, 0, 1, 0}) {
+			t.Errorf("Expected the observation in the 1000 bucket, got %v", got)
+		}
+		calls := byName["call_seconds"].(metricdata.Histogram[float64])
+		if got := calls.DataPoints[0].Bounds; !slices.Equal(got, ports.DefaultDurationBuckets) {
+			t.Errorf("Expected the default duration buckets, got %v", got)
+		}
+
+		// And: Buckets can no longer change
+		if err := metrics.SetHistogramBuckets("exchange_trade_notional", []float64{1}); err == nil {
+			t.Error("Expected changing buckets after the first observation to fail")
+		}
+	})
+
+	t.Run("summaries_are_exponential_histograms", func(t *testing.T) {
+		// Given: An OTel metrics adapter
+		metrics, reader := newOTelMetrics(t)
+
+		// When: Values are recorded in a summary
+		for i := 1; i <= 100; i++ {
+			metrics.ObserveSummary("fill_ratio", float64(i), map[string]string{"symbol": "ETH-USD"})
+		}
+
+		// Then: They are exported as an exponential histogram with their count and sum
+		_, byName := collect(t, reader)
+		summary, ok := byName["fill_ratio"].(metricdata.ExponentialHistogram[float64])
+		if !ok {
+			t.Fatalf("Expected an exponential histogram, got %T", byName["fill_ratio"])
+		}
+		if point := summary.DataPoints[0]; point.Count != 100 || point.Sum != 5050 {
+			t.Errorf("Expected count 100 and sum 5050, got %d and %v", point.Count, point.Sum)
+		}
+	})
+
+	t.Run("gauges_report_last_value", func(t *testing.T) {
+		// Given: An OTel metrics adapter
+		metrics, reader := newOTelMetrics(t)
+
+		// When: A gauge is set twice for one symbol and once for another
+		metrics.SetGauge("exchange_open_orders", 4, map[string]string{"symbol": "BTC-USD"})
+		metrics.SetGauge("exchange_open_orders", 7, map[string]string{"symbol": "BTC-USD"})
+		metrics.SetGauge("exchange_open_orders", 1, map[string]string{"symbol": "ETH-USD"})
+
+		// Then: Each symbol reports its last value
+		_, byName := collect(t, reader)
+		gauge := byName["exchange_open_orders"].(metricdata.Gauge[float64])
+		if len(gauge.DataPoints) != 2 {
+			t.Fatalf("Expected 2 points, got %d", len(gauge.DataPoints))
+		}
+		for _, point := range gauge.DataPoints {
+			if symbolIs(point.Attributes, "BTC-USD") && point.Value != 7 {
+				t.Errorf("Expected BTC-USD at 7, got %v", point.Value)
+			}
+		}
+	})
+
+	t.Run("metrics_endpoint_is_not_served", func(t *testing.T) {
+		// Given: An OTel metrics adapter
+		metrics, _ := newOTelMetrics(t)
+
+		// When: /metrics is scraped
+		w := httptest.NewRecorder()
+		metrics.GetHTTPHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// Then: It answers that metrics are pushed instead
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestNewOTLPMetricReader(t *testing.T) {
+	t.Run("rejects_endpoint_without_scheme", func(t *testing.T) {
+		if _, err := observability.NewOTLPMetricReader(context.Background(), "otel-collector:4318", time.Minute); err == nil {
+			t.Error("Expected an endpoint without http(s) to be rejected")
+		}
+	})
+}
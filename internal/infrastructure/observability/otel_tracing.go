@@ -0,0 +1,142 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// tracerName names the instrumentation spans are recorded by
+const tracerName = "github.com/quantfidential/trading-ecosystem/exchange-simulator-go"
+
+// OTelTracingAdapter implements TracingPort using OpenTelemetry. Spans carry the
+// service's identity and are exported in batches; trace context travels in W3C
+// traceparent/tracestate and baggage headers, as across the rest of the ecosystem.
+type OTelTracingAdapter struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewOTelTracingAdapter creates a tracing adapter exporting spans through exporter.
+// service identifies the spans (service.name, service.instance.id and
+// service.version); sampleRatio is the share of new traces sampled, while calls
+// continuing a trace are sampled as the caller's span was.
+func NewOTelTracingAdapter(exporter sdktrace.SpanExporter, service ports.MetricsLabels, sampleRatio float64) *OTelTracingAdapter {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource(service)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	return &OTelTracingAdapter{
+		provider:   provider,
+		tracer:     provider.Tracer(tracerName),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+// NewOTLPTraceExporter creates an exporter sending spans over OTLP/HTTP to the
+// collector at endpoint, e.g. "http://otel-collector:4318". An http endpoint is
+// sent to in plain text; the path defaults to /v1/traces.
+func NewOTLPTraceExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	parsed, err := parseOTLPEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(parsed.Host)}
+	if parsed.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if path := strings.TrimRight(parsed.Path, "/"); path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(path+"/v1/traces"))
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
+
+// parseOTLPEndpoint checks a collector endpoint is an http(s)://host:port URL
+func parseOTLPEndpoint(endpoint string) (*url.URL, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: expected http(s)://host:port", endpoint)
+	}
+	return parsed, nil
+}
+
+// serviceResource identifies the service telemetry comes from (service.name,
+// service.instance.id and service.version)
+func serviceResource(service ports.MetricsLabels) *resource.Resource {
+	attributes := []attribute.KeyValue{attribute.String("service.name", service.Service)}
+	if service.Instance != "" {
+		attributes = append(attributes, attribute.String("service.instance.id", service.Instance))
+	}
+	if service.Version != "" {
+		attributes = append(attributes, attribute.String("service.version", service.Version))
+	}
+	return resource.NewSchemaless(attributes...)
+}
+
+func (a *OTelTracingAdapter) StartSpan(ctx context.Context, name string, kind ports.SpanKind, attributes map[string]string) (context.Context, ports.Span) {
+	ctx, span := a.tracer.Start(ctx, name, trace.WithSpanKind(otelSpanKind(kind)))
+	for key, value := range attributes {
+		span.SetAttributes(attribute.String(key, value))
+	}
+	return ctx, &otelSpan{span: span}
+}
+
+func (a *OTelTracingAdapter) Inject(ctx context.Context, carrier ports.TraceCarrier) {
+	a.propagator.Inject(ctx, carrier)
+}
+
+func (a *OTelTracingAdapter) Extract(ctx context.Context, carrier ports.TraceCarrier) context.Context {
+	return a.propagator.Extract(ctx, carrier)
+}
+
+// ForceFlush exports every span ended so far without waiting for the next batch
+func (a *OTelTracingAdapter) ForceFlush(ctx context.Context) error {
+	return a.provider.ForceFlush(ctx)
+}
+
+func (a *OTelTracingAdapter) Shutdown(ctx context.Context) error {
+	return a.provider.Shutdown(ctx)
+}
+
+func otelSpanKind(kind ports.SpanKind) trace.SpanKind {
+	switch kind {
+	case ports.SpanKindServer:
+		return trace.SpanKindServer
+	case ports.SpanKindClient:
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+// otelSpan implements Span over an OpenTelemetry span
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttribute(key, value string) {
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+func (s *otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
@@ -1,8 +1,11 @@
 package observability
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -22,6 +25,10 @@ type PrometheusMetricsAdapter struct {
 	counters   map[string]*prometheus.CounterVec
 	histograms map[string]*prometheus.HistogramVec
 	gauges     map[string]*prometheus.GaugeVec
+	summaries  map[string]*prometheus.SummaryVec
+
+	// Bucket upper bounds of histograms not using ports.DefaultDurationBuckets
+	buckets map[string][]float64
 
 	// Mutex for thread-safe lazy initialization
 	mu sync.RWMutex
@@ -44,6 +51,8 @@ func NewPrometheusMetricsAdapter(constantLabels map[string]string) *PrometheusMe
 		counters:       make(map[string]*prometheus.CounterVec),
 		histograms:     make(map[string]*prometheus.HistogramVec),
 		gauges:         make(map[string]*prometheus.GaugeVec),
+		summaries:      make(map[string]*prometheus.SummaryVec),
+		buckets:        make(map[string][]float64),
 		constantLabels: constantLabels,
 	}
 }
@@ -54,12 +63,73 @@ func (a *PrometheusMetricsAdapter) IncCounter(name string, labels map[string]str
 	counter.With(prometheus.Labels(labels)).Inc()
 }
 
+// AddCounter increases a counter metric by value
+func (a *PrometheusMetricsAdapter) AddCounter(name string, value float64, labels map[string]string) {
+	counter := a.getOrCreateCounter(name, labels)
+	counter.With(prometheus.Labels(labels)).Add(value)
+}
+
 // ObserveHistogram records a value in a histogram metric
 func (a *PrometheusMetricsAdapter) ObserveHistogram(name string, value float64, labels map[string]string) {
 	histogram := a.getOrCreateHistogram(name, labels)
 	histogram.With(prometheus.Labels(labels)).Observe(value)
 }
 
+// ObserveHistogramWithExemplar records a value in a histogram metric with an
+// exemplar, which /metrics serves in the OpenMetrics format. Exemplars whose labels
+// exceed prometheus.ExemplarMaxRunes are dropped rather than failing the observation.
+func (a *PrometheusMetricsAdapter) ObserveHistogramWithExemplar(name string, value float64, labels map[string]string, exemplar map[string]string) {
+	observer := a.getOrCreateHistogram(name, labels).With(prometheus.Labels(labels))
+	if withExemplar, ok := observer.(prometheus.ExemplarObserver); ok && validExemplar(exemplar) {
+		withExemplar.ObserveWithExemplar(value, prometheus.Labels(exemplar))
+		return
+	}
+	observer.Observe(value)
+}
+
+// SetHistogramBuckets sets the bucket upper bounds a histogram is created with
+func (a *PrometheusMetricsAdapter) SetHistogramBuckets(name string, buckets []float64) error {
+	if err := validateBuckets(name, buckets); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.histograms[name]; exists {
+		if !slices.Equal(a.bucketsOf(name), buckets) {
+			return errHistogramObserved(name)
+		}
+		return nil
+	}
+	a.buckets[name] = slices.Clone(buckets)
+	return nil
+}
+
+// validateBuckets checks a histogram's bucket upper bounds are increasing
+func validateBuckets(name string, buckets []float64) error {
+	if len(buckets) == 0 {
+		return fmt.Errorf("histogram %s needs at least one bucket", name)
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("histogram %s buckets must be in increasing order", name)
+		}
+	}
+	return nil
+}
+
+// errHistogramObserved reports buckets set too late to change a histogram
+func errHistogramObserved(name string) error {
+	return fmt.Errorf("histogram %s was observed before its buckets were set", name)
+}
+
+// ObserveSummary records a value in a summary metric
+func (a *PrometheusMetricsAdapter) ObserveSummary(name string, value float64, labels map[string]string) {
+	summary := a.getOrCreateSummary(name, labels)
+	summary.With(prometheus.Labels(labels)).Observe(value)
+}
+
 // SetGauge sets a gauge metric to a specific value
 func (a *PrometheusMetricsAdapter) SetGauge(name string, value float64, labels map[string]string) {
 	gauge := a.getOrCreateGauge(name, labels)
@@ -100,7 +170,7 @@ func (a *PrometheusMetricsAdapter) getOrCreateCounter(name string, labels map[st
 	counter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name:        name,
-			Help:        name, // TODO: Add proper help text
+			Help:        helpOf("Counter", name),
 			ConstLabels: prometheus.Labels(a.constantLabels),
 		},
 		labelNames,
@@ -135,14 +205,13 @@ func (a *PrometheusMetricsAdapter) getOrCreateHistogram(name string, labels map[
 	// Extract label names from the provided labels
 	labelNames := a.extractLabelNames(labels)
 
-	// Create new histogram with sensible buckets for request duration
-	// Buckets: 5ms, 10ms, 25ms, 50ms, 100ms, 250ms, 500ms, 1s, 2.5s, 5s, 10s
+	// Create new histogram with the buckets set for it, or the duration buckets
 	histogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:        name,
-			Help:        name, // TODO: Add proper help text
+			Help:        helpOf("Histogram", name),
 			ConstLabels: prometheus.Labels(a.constantLabels),
-			Buckets:     []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			Buckets:     a.bucketsOf(name),
 		},
 		labelNames,
 	)
@@ -153,6 +222,55 @@ func (a *PrometheusMetricsAdapter) getOrCreateHistogram(name string, labels map[
 	return histogram
 }
 
+// bucketsOf returns the bucket upper bounds of a histogram; callers hold mu
+func (a *PrometheusMetricsAdapter) bucketsOf(name string) []float64 {
+	if buckets, set := a.buckets[name]; set {
+		return buckets
+	}
+	return ports.DefaultDurationBuckets
+}
+
+// getOrCreateSummary gets or creates a summary metric (thread-safe lazy initialization)
+func (a *PrometheusMetricsAdapter) getOrCreateSummary(name string, labels map[string]string) *prometheus.SummaryVec {
+	// Fast path: read lock
+	a.mu.RLock()
+	summary, exists := a.summaries[name]
+	a.mu.RUnlock()
+
+	if exists {
+		return summary
+	}
+
+	// Slow path: write lock and create
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Double-check after acquiring write lock
+	if summary, exists := a.summaries[name]; exists {
+		return summary
+	}
+
+	// Extract label names from the provided labels
+	labelNames := a.extractLabelNames(labels)
+
+	// Create new summary of the median, 90th and 99th percentiles over ten minutes
+	summary = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:        name,
+			Help:        helpOf("Summary", name),
+			ConstLabels: prometheus.Labels(a.constantLabels),
+			Objectives:  map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:      prometheus.DefMaxAge,
+		},
+		labelNames,
+	)
+
+	a.registry.MustRegister(summary)
+	a.summaries[name] = summary
+
+	return summary
+}
+
 // getOrCreateGauge gets or creates a gauge metric (thread-safe lazy initialization)
 func (a *PrometheusMetricsAdapter) getOrCreateGauge(name string, labels map[string]string) *prometheus.GaugeVec {
 	// Fast path: read lock
@@ -180,7 +298,7 @@ func (a *PrometheusMetricsAdapter) getOrCreateGauge(name string, labels map[stri
 	gauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name:        name,
-			Help:        name, // TODO: Add proper help text
+			Help:        helpOf("Gauge", name),
 			ConstLabels: prometheus.Labels(a.constantLabels),
 		},
 		labelNames,
@@ -207,3 +325,16 @@ func (a *PrometheusMetricsAdapter) extractLabelNames(labels map[string]string) [
 
 	return labelNames
 }
+
+// validExemplar reports whether exemplar is non-empty and within the runes
+// Prometheus allows an exemplar's labels
+func validExemplar(exemplar map[string]string) bool {
+	if len(exemplar) == 0 {
+		return false
+	}
+	runes := 0
+	for name, value := range exemplar {
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	return runes <= prometheus.ExemplarMaxRunes
+}
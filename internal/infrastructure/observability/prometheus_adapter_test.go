@@ -0,0 +1,205 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// scrapeOpenMetrics returns the adapter's metrics in the OpenMetrics format, the
+// only one carrying exemplars
+func scrapeOpenMetrics(metricsPort *observability.PrometheusMetricsAdapter) string {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	w := httptest.NewRecorder()
+	metricsPort.GetHTTPHandler().ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestPrometheusMetricsAdapter_HistogramBuckets(t *testing.T) {
+	t.Run("histogram_uses_configured_buckets", func(t *testing.T) {
+		// Given: A histogram configured with size-scaled buckets
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		if err := metrics.SetHistogramBuckets("queue_size", ports.ExponentialBuckets(1, 10, 3)); err != nil {
+			t.Fatalf("Expected buckets to be accepted, got %v", err)
+		}
+
+		// When: A value is observed
+		metrics.ObserveHistogram("queue_size", 50, map[string]string{"symbol": "BTC-USD"})
+
+		// Then: The exposition uses the configured bounds instead of the duration defaults
+		body := scrape(metrics)
+		for _, want := range []string{
+			`queue_size_bucket{symbol="BTC-USD",le="10"} 0`,
+			`queue_size_bucket{symbol="BTC-USD",le="100"} 1`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected %q in metrics output", want)
+			}
+		}
+		if strings.Contains(body, `queue_size_bucket{symbol="BTC-USD",le="0.005"}`) {
+			t.Error("Expected default duration buckets not to be used")
+		}
+	})
+
+	t.Run("unconfigured_histogram_uses_duration_buckets", func(t *testing.T) {
+		// Given: A histogram nobody configured
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+
+		// When: A value is observed
+		metrics.ObserveHistogram("call_seconds", 0.2, nil)
+
+		// Then: The default duration buckets apply
+		if body := scrape(metrics); !strings.Contains(body, `call_seconds_bucket{le="0.25"} 1`) {
+			t.Error("Expected the default duration buckets")
+		}
+	})
+
+	t.Run("buckets_cannot_change_after_first_observation", func(t *testing.T) {
+		// Given: A histogram already observed with the default buckets
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		metrics.ObserveHistogram("call_seconds", 0.2, nil)
+
+		// When: Different buckets are set, then it is refused
+		if err := metrics.SetHistogramBuckets("call_seconds", []float64{1, 2}); err == nil {
+			t.Error("Expected changing buckets after the first observation to fail")
+		}
+
+		// And: Setting the buckets it already has succeeds
+		if err := metrics.SetHistogramBuckets("call_seconds", ports.DefaultDurationBuckets); err != nil {
+			t.Errorf("Expected unchanged buckets to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("invalid_buckets_are_rejected", func(t *testing.T) {
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+
+		for name, buckets := range map[string][]float64{
+			"empty":          nil,
+			"not_increasing": {1, 5, 5},
+			"decreasing":     {10, 1},
+		} {
+			if err := metrics.SetHistogramBuckets("sizes", buckets); err == nil {
+				t.Errorf("Expected %s buckets to be rejected", name)
+			}
+		}
+	})
+}
+
+func TestPrometheusMetricsAdapter_ObserveSummary(t *testing.T) {
+	t.Run("summary_reports_quantiles", func(t *testing.T) {
+		// Given: Observations recorded in a summary
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		for i := 1; i <= 100; i++ {
+			metrics.ObserveSummary("fill_ratio", float64(i), map[string]string{"symbol": "ETH-USD"})
+		}
+
+		// When: Metrics are scraped
+		body := scrape(metrics)
+
+		// Then: The tracked quantiles, sum and count are exposed
+		for _, want := range []string{
+			`fill_ratio{symbol="ETH-USD",quantile="0.5"}`,
+			`fill_ratio{symbol="ETH-USD",quantile="0.9"}`,
+			`fill_ratio{symbol="ETH-USD",quantile="0.99"}`,
+			`fill_ratio_sum{symbol="ETH-USD"} 5050`,
+			`fill_ratio_count{symbol="ETH-USD"} 100`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected %q in metrics output", want)
+			}
+		}
+	})
+}
+
+func TestPrometheusMetricsAdapter_Help(t *testing.T) {
+	t.Run("describes_each_metric_in_its_help_line", func(t *testing.T) {
+		// Given: A metric the venue reports and a summary it does not list
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		metrics.IncCounter("exchange_trades_total", map[string]string{"symbol": "BTC-USD"})
+		metrics.ObserveSummary("fill_ratio", 0.5, map[string]string{"symbol": "BTC-USD"})
+
+		// When: Metrics are scraped
+		body := scrape(metrics)
+
+		// Then: The venue's metric has its description, the other one its kind and name
+		for _, want := range []string{
+			"# HELP exchange_trades_total Trades executed, by symbol",
+			"# HELP fill_ratio Summary of fill_ratio",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected %q in metrics output", want)
+			}
+		}
+	})
+}
+
+func TestPrometheusMetricsAdapter_Exemplars(t *testing.T) {
+	t.Run("exemplar_is_served_to_openmetrics_scrapes", func(t *testing.T) {
+		// Given: An observation carrying a trace exemplar
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		metrics.ObserveHistogramWithExemplar("call_seconds", 0.2, nil, map[string]string{
+			observability.TraceIDExemplar: "4bf92f3577b34da6a3ce929d0e0e4736",
+		})
+
+		// When: Metrics are scraped in the OpenMetrics format
+		body := scrapeOpenMetrics(metrics)
+
+		// Then: The bucket carries the trace ID
+		if !strings.Contains(body, `# {trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.2`) {
+			t.Errorf("Expected the trace exemplar in metrics output, got:\n%s", body)
+		}
+	})
+
+	t.Run("oversize_exemplar_is_dropped", func(t *testing.T) {
+		// Given: An exemplar longer than OpenMetrics allows
+		metrics := observability.NewPrometheusMetricsAdapter(nil)
+		metrics.ObserveHistogramWithExemplar("call_seconds", 0.2, nil, map[string]string{
+			observability.TraceIDExemplar: strings.Repeat("a", 200),
+		})
+
+		// When: Metrics are scraped
+		body := scrapeOpenMetrics(metrics)
+
+		// Then: The observation is kept without the exemplar
+		if !strings.Contains(body, `call_seconds_count 1`) {
+			t.Error("Expected the observation to be recorded")
+		}
+		if strings.Contains(body, "trace_id") {
+			t.Error("Expected the oversize exemplar to be dropped")
+		}
+	})
+}
+
+func TestTraceExemplar(t *testing.T) {
+	t.Run("sampled_span_yields_trace_id", func(t *testing.T) {
+		// Given: A context carrying a sampled span
+		tracing, _ := newTracing(t)
+		ctx, span := tracing.StartSpan(context.Background(), "op", ports.SpanKindInternal, nil)
+		defer span.End()
+
+		// When: The exemplar is taken
+		exemplar := observability.TraceExemplar(ctx)
+
+		// Then: It carries the span's trace ID
+		traceID := trace.SpanContextFromContext(ctx).TraceID().String()
+		if exemplar[observability.TraceIDExemplar] != traceID {
+			t.Errorf("Expected trace ID %s, got %v", traceID, exemplar)
+		}
+	})
+
+	t.Run("no_span_yields_no_exemplar", func(t *testing.T) {
+		if exemplar := observability.TraceExemplar(context.Background()); exemplar != nil {
+			t.Errorf("Expected no exemplar, got %v", exemplar)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// TracingUnaryInterceptor starts a server span for each gRPC call, continuing the
+// trace the caller propagated in its metadata. The span is named after the full
+// method, e.g. /exchange.v1.TradingService/PlaceOrder, and records the status code.
+func TracingUnaryInterceptor(tracing ports.TracingPort) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startGRPCServerSpan(ctx, tracing, info.FullMethod)
+		defer span.End()
+		resp, err := handler(ctx, req)
+		endGRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// TracingStreamInterceptor traces gRPC server streams like TracingUnaryInterceptor,
+// with one span from open to close
+func TracingStreamInterceptor(tracing ports.TracingPort) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startGRPCServerSpan(stream.Context(), tracing, info.FullMethod)
+		defer span.End()
+		err := handler(srv, &tracedServerStream{ServerStream: stream, ctx: ctx})
+		endGRPCSpan(span, err)
+		return err
+	}
+}
+
+// TracingUnaryClientInterceptor starts a client span for each call to service and
+// propagates it in the outgoing metadata, so the service's spans join the trace
+func TracingUnaryClientInterceptor(tracing ports.TracingPort, service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startGRPCClientSpan(ctx, tracing, service, method)
+		defer span.End()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endGRPCSpan(span, err)
+		return err
+	}
+}
+
+// TracingStreamClientInterceptor traces streams to service like
+// TracingUnaryClientInterceptor; the span ends once the stream is finished
+func TracingStreamClientInterceptor(tracing ports.TracingPort, service string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startGRPCClientSpan(ctx, tracing, service, method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endGRPCSpan(span, err)
+			span.End()
+			return nil, err
+		}
+		return &tracedClientStream{ClientStream: stream, span: span, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+// TracingMiddleware creates Gin middleware starting a server span for each HTTP
+// request, continuing the trace the caller propagated in its headers. The span is
+// named after the method and route pattern (low cardinality), e.g.
+// "GET /api/v1/health"; responses of 500 and above mark it failed.
+func TracingMiddleware(tracing ports.TracingPort) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+		ctx := tracing.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.StartSpan(ctx, c.Request.Method+" "+route, ports.SpanKindServer, map[string]string{
+			"http.method": c.Request.Method,
+			"http.route":  route,
+		})
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		code := c.Writer.Status()
+		span.SetAttribute("http.status_code", strconv.Itoa(code))
+		if code >= 500 {
+			span.RecordError(fmt.Errorf("HTTP %d", code))
+		}
+	}
+}
+
+func startGRPCServerSpan(ctx context.Context, tracing ports.TracingPort, method string) (context.Context, ports.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = tracing.Extract(ctx, metadataCarrier(md))
+	return tracing.StartSpan(ctx, method, ports.SpanKindServer, map[string]string{
+		"rpc.system": "grpc",
+		"rpc.method": method,
+	})
+}
+
+func startGRPCClientSpan(ctx context.Context, tracing ports.TracingPort, service, method string) (context.Context, ports.Span) {
+	ctx, span := tracing.StartSpan(ctx, method, ports.SpanKindClient, map[string]string{
+		"rpc.system":   "grpc",
+		"rpc.method":   method,
+		"peer.service": service,
+	})
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracing.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endGRPCSpan records the call's status code, and err should it have failed
+func endGRPCSpan(span ports.Span, err error) {
+	span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// metadataCarrier carries trace context in gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// tracedServerStream replaces a server stream's context with one carrying its span
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// tracedClientStream ends its span once the stream is finished: on the first
+// failed receive, or on the one response of a stream the server does not stream
+type tracedClientStream struct {
+	grpc.ClientStream
+	span          ports.Span
+	serverStreams bool
+	ended         bool
+}
+
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case s.ended:
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	case err != nil:
+		s.end(err)
+	case !s.serverStreams:
+		s.end(nil)
+	}
+	return err
+}
+
+func (s *tracedClientStream) end(err error) {
+	s.ended = true
+	endGRPCSpan(s.span, err)
+	s.span.End()
+}
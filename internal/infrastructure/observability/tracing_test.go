@@ -0,0 +1,173 @@
+//go:build unit
+
+package observability_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+// newTracing returns a tracing adapter sampling every trace, and the exporter its
+// spans end up in once flushed
+func newTracing(t *testing.T) (*observability.OTelTracingAdapter, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracing := observability.NewOTelTracingAdapter(exporter, ports.MetricsLabels{Service: "exchange-simulator"}, 1)
+	t.Cleanup(func() { tracing.Shutdown(context.Background()) })
+	return tracing, exporter
+}
+
+// flushedSpans returns the spans ended so far
+func flushedSpans(t *testing.T, tracing *observability.OTelTracingAdapter, exporter *tracetest.InMemoryExporter) tracetest.SpanStubs {
+	if err := tracing.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("Expected spans to flush, got %v", err)
+	}
+	return exporter.GetSpans()
+}
+
+func spanAttribute(span tracetest.SpanStub, key string) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == attribute.Key(key) {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+// TestTracingInterceptors verifies trace context propagation across gRPC and HTTP
+// Following BDD Given/When/Then pattern
+func TestTracingInterceptors(t *testing.T) {
+	t.Run("continues_the_callers_trace_across_a_grpc_call", func(t *testing.T) {
+		// Given: A client span calling audit-correlator, whose outgoing metadata reaches the server
+		tracing, exporter := newTracing(t)
+		client := observability.TracingUnaryClientInterceptor(tracing, "audit-correlator")
+		server := observability.TracingUnaryInterceptor(tracing)
+		method := "/audit.v1.AuditService/SubmitEvents"
+
+		// When: The call is made
+		var serverCtx context.Context
+		err := client(context.Background(), method, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			_, err := server(metadata.NewIncomingContext(context.Background(), md), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				serverCtx = ctx
+				return "ok", nil
+			})
+			return err
+		})
+
+		// Then: The server span is a child of the client span, in the same trace
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		spans := flushedSpans(t, tracing, exporter)
+		if len(spans) != 2 {
+			t.Fatalf("Expected a server and a client span, got %d", len(spans))
+		}
+		serverSpan, clientSpan := spans[0], spans[1]
+		if serverSpan.SpanKind != trace.SpanKindServer || clientSpan.SpanKind != trace.SpanKindClient {
+			t.Fatalf("Expected the server span to end first, got %v then %v", serverSpan.SpanKind, clientSpan.SpanKind)
+		}
+		if serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() || serverSpan.SpanContext.TraceID() != clientSpan.SpanContext.TraceID() {
+			t.Error("Expected the server span to continue the client's trace")
+		}
+		if spanAttribute(clientSpan, "peer.service") != "audit-correlator" || serverSpan.Name != method {
+			t.Errorf("Expected spans named after the method, to audit-correlator, got %+v", clientSpan.Attributes)
+		}
+		if trace.SpanContextFromContext(serverCtx).SpanID() != serverSpan.SpanContext.SpanID() {
+			t.Error("Expected the handler's context to carry the server span")
+		}
+	})
+
+	t.Run("marks_failed_calls", func(t *testing.T) {
+		// Given: The server interceptor
+		tracing, exporter := newTracing(t)
+		interceptor := observability.TracingUnaryInterceptor(tracing)
+
+		// When: A call is rejected
+		interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/exchange.v1.TradingService/PlaceOrder"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.InvalidArgument, "bad quantity")
+		})
+
+		// Then: The span failed, with the status code
+		spans := flushedSpans(t, tracing, exporter)
+		if len(spans) != 1 || spans[0].Status.Code != otelcodes.Error || spanAttribute(spans[0], "rpc.grpc.status_code") != "InvalidArgument" {
+			t.Errorf("Expected one failed span with code InvalidArgument, got %+v", spans)
+		}
+	})
+
+	t.Run("ends_a_client_stream_span_on_its_response", func(t *testing.T) {
+		// Given: A client stream to custodian-simulator that the server does not stream back on
+		tracing, exporter := newTracing(t)
+		interceptor := observability.TracingStreamClientInterceptor(tracing, "custodian-simulator")
+		stream, err := interceptor(context.Background(), &grpc.StreamDesc{ClientStreams: true}, nil, "/custodian.v1.SettlementService/Stream",
+			func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &respondingStream{}, nil
+			})
+		if err != nil {
+			t.Fatalf("Expected the stream to open, got %v", err)
+		}
+		if spans := flushedSpans(t, tracing, exporter); len(spans) != 0 {
+			t.Fatalf("Expected no span before the response, got %d", len(spans))
+		}
+
+		// When: The response is received
+		stream.RecvMsg(nil)
+
+		// Then: The span has ended
+		if spans := flushedSpans(t, tracing, exporter); len(spans) != 1 || spanAttribute(spans[0], "rpc.grpc.status_code") != "OK" {
+			t.Errorf("Expected one successful span, got %+v", spans)
+		}
+	})
+
+	t.Run("continues_the_callers_trace_across_an_http_request", func(t *testing.T) {
+		// Given: A router traced by the middleware, and a request carrying a traceparent
+		gin.SetMode(gin.TestMode)
+		tracing, exporter := newTracing(t)
+		router := gin.New()
+		router.Use(observability.TracingMiddleware(tracing))
+		router.GET("/api/v1/orders/:id", func(c *gin.Context) {
+			c.Status(http.StatusInternalServerError)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/42", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		// When: The request is served
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Then: Its span joins the trace, named after the route, and failed
+		spans := flushedSpans(t, tracing, exporter)
+		if len(spans) != 1 {
+			t.Fatalf("Expected one span, got %d", len(spans))
+		}
+		span := spans[0]
+		if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("Expected the caller's trace, got %s", span.SpanContext.TraceID())
+		}
+		if span.Name != "GET /api/v1/orders/:id" || spanAttribute(span, "http.status_code") != "500" || span.Status.Code != otelcodes.Error {
+			t.Errorf("Unexpected span %s %+v", span.Name, span.Attributes)
+		}
+	})
+}
+
+// respondingStream is a client stream whose one response is already there
+type respondingStream struct {
+	grpc.ClientStream
+}
+
+func (s *respondingStream) RecvMsg(m interface{}) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// OrchestratorReporter posts scenario script progress as JSON to the orchestrator
+type OrchestratorReporter struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewOrchestratorReporter(url string, timeout time.Duration) *OrchestratorReporter {
+	return &OrchestratorReporter{url: url, httpClient: &http.Client{Timeout: timeout}}
+}
+
+// ReportProgress posts progress, failing unless the orchestrator answers with a 2xx
+func (r *OrchestratorReporter) ReportProgress(ctx context.Context, progress scenario.Progress) error {
+	body, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode scenario progress: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create progress request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to report scenario progress: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("orchestrator answered scenario progress with status %d", resp.StatusCode)
+	}
+	return nil
+}
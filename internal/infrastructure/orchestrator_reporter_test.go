@@ -0,0 +1,52 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+func TestOrchestratorReporter(t *testing.T) {
+	t.Run("posts_progress_as_json", func(t *testing.T) {
+		// Given: An orchestrator recording what it receives
+		var received scenario.Progress
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+		reporter := NewOrchestratorReporter(server.URL, time.Second)
+
+		// When: A run's progress is reported
+		err := reporter.ReportProgress(context.Background(), scenario.Progress{Script: "flash-crash", State: scenario.ScriptRunning, Steps: 3, Executed: 1})
+
+		// Then: The orchestrator has it
+		if err != nil || received.Script != "flash-crash" || received.Executed != 1 {
+			t.Errorf("Expected the progress delivered, got %+v, %v", received, err)
+		}
+	})
+
+	t.Run("fails_when_the_orchestrator_refuses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := NewOrchestratorReporter(server.URL, time.Second).ReportProgress(context.Background(), scenario.Progress{})
+
+		if err == nil {
+			t.Error("Expected a refused report to fail")
+		}
+	})
+}
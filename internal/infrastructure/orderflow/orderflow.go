@@ -0,0 +1,199 @@
+// Package orderflow reads captured order flow for the replayer: the venue's event
+// journal as JSON lines, a run bundle holding one, or length-delimited
+// exchange.v1.OrderFlowEvent messages.
+package orderflow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+)
+
+// maxEventSize bounds one protobuf event, so a file of another kind fails fast
+const maxEventSize = 1 << 20
+
+// Parse reads a recording in any supported format, told apart by its contents
+func Parse(data []byte) (replay.Recording, error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		bundle, err := runbundle.Read(bytes.NewReader(data))
+		if err != nil {
+			return replay.Recording{}, err
+		}
+		return replay.FromJournal(bundle.Journal)
+	}
+	// A protobuf file may happen to start with '{', so JSON is tried first and
+	// protobuf after it
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		recording, err := ParseJournal(data)
+		if err == nil {
+			return recording, nil
+		}
+		if recording, protoErr := ParseProto(data); protoErr == nil {
+			return recording, nil
+		}
+		return replay.Recording{}, err
+	}
+	return ParseProto(data)
+}
+
+// ParseJournal reads an event journal of JSON lines
+func ParseJournal(data []byte) (replay.Recording, error) {
+	events := make([]matching.Event, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event matching.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return replay.Recording{}, fmt.Errorf("invalid journal event on line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return replay.Recording{}, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return replay.FromJournal(events)
+}
+
+// ParseProto reads length-delimited OrderFlowEvents
+func ParseProto(data []byte) (replay.Recording, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	options := protodelim.UnmarshalOptions{MaxSize: maxEventSize}
+	entries := make([]replay.Entry, 0)
+	for n := 1; ; n++ {
+		var event exchangev1.OrderFlowEvent
+		err := options.UnmarshalFrom(reader, &event)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return replay.Recording{}, fmt.Errorf("invalid order flow event %d: %w", n, err)
+		}
+		entry, err := entryFromProto(&event)
+		if err != nil {
+			return replay.Recording{}, fmt.Errorf("invalid order flow event %d: %w", n, err)
+		}
+		entries = append(entries, entry)
+	}
+	return replay.NewRecording(entries)
+}
+
+// WriteProto writes a recording as length-delimited OrderFlowEvents
+func WriteProto(w io.Writer, recording replay.Recording) error {
+	for _, entry := range recording.Entries {
+		if _, err := protodelim.MarshalTo(w, entryToProto(entry)); err != nil {
+			return fmt.Errorf("failed to write order flow event: %w", err)
+		}
+	}
+	return nil
+}
+
+func entryFromProto(event *exchangev1.OrderFlowEvent) (replay.Entry, error) {
+	entry := replay.Entry{Time: time.UnixMilli(event.GetTimeMs()).UTC(), OrderID: event.GetOrderId()}
+	switch request := event.GetRequest().(type) {
+	case *exchangev1.OrderFlowEvent_Place:
+		entry.Kind = replay.KindPlace
+		entry.Order = orderFromSpec(request.Place)
+		entry.Order.ID = event.GetOrderId()
+	case *exchangev1.OrderFlowEvent_Cancel:
+		entry.Kind = replay.KindCancel
+	case *exchangev1.OrderFlowEvent_Amend:
+		entry.Kind = replay.KindAmend
+		entry.Amend = matching.AmendRequest{Price: request.Amend.GetPrice(), Quantity: request.Amend.GetQuantity()}
+	default:
+		return replay.Entry{}, errors.New("no place, cancel or amend request")
+	}
+	if entry.OrderID == "" {
+		return replay.Entry{}, errors.New("no order_id")
+	}
+	return entry, nil
+}
+
+func entryToProto(entry replay.Entry) *exchangev1.OrderFlowEvent {
+	event := &exchangev1.OrderFlowEvent{TimeMs: entry.Time.UnixMilli(), OrderId: entry.OrderID}
+	switch entry.Kind {
+	case replay.KindPlace:
+		event.Request = &exchangev1.OrderFlowEvent_Place{Place: specFromOrder(entry.Order)}
+	case replay.KindCancel:
+		event.Request = &exchangev1.OrderFlowEvent_Cancel{Cancel: &exchangev1.OrderFlowCancel{}}
+	case replay.KindAmend:
+		event.Request = &exchangev1.OrderFlowEvent_Amend{Amend: &exchangev1.OrderFlowAmend{Price: entry.Amend.Price, Quantity: entry.Amend.Quantity}}
+	}
+	return event
+}
+
+func orderFromSpec(spec *exchangev1.OrderSpec) models.Order {
+	order := models.Order{
+		ClientOrderID: spec.GetClientOrderId(),
+		AccountID:     spec.GetAccountId(),
+		Symbol:        spec.GetSymbol(),
+		Type:          models.OrderTypeLimit,
+		TimeInForce:   models.TimeInForceGTC,
+		Quantity:      spec.GetQuantity(),
+		Price:         spec.GetPrice(),
+	}
+	switch spec.GetSide() {
+	case exchangev1.Side_SIDE_BUY:
+		order.Side = models.SideBuy
+	case exchangev1.Side_SIDE_SELL:
+		order.Side = models.SideSell
+	}
+	if spec.GetType() == exchangev1.OrderType_ORDER_TYPE_MARKET {
+		order.Type, order.Price = models.OrderTypeMarket, 0
+	}
+	switch spec.GetTimeInForce() {
+	case exchangev1.TimeInForce_TIME_IN_FORCE_IOC:
+		order.TimeInForce = models.TimeInForceIOC
+	case exchangev1.TimeInForce_TIME_IN_FORCE_FOK:
+		order.TimeInForce = models.TimeInForceFOK
+	case exchangev1.TimeInForce_TIME_IN_FORCE_GTD:
+		order.TimeInForce = models.TimeInForceGTD
+		order.ExpiresAt = time.UnixMilli(spec.GetExpireTimeMs()).UTC()
+	}
+	return order
+}
+
+func specFromOrder(order models.Order) *exchangev1.OrderSpec {
+	spec := &exchangev1.OrderSpec{
+		AccountId:     order.AccountID,
+		Symbol:        order.Symbol,
+		Type:          exchangev1.OrderType_ORDER_TYPE_LIMIT,
+		TimeInForce:   exchangev1.TimeInForce_TIME_IN_FORCE_GTC,
+		Quantity:      order.Quantity,
+		Price:         order.Price,
+		ClientOrderId: order.ClientOrderID,
+	}
+	switch order.Side {
+	case models.SideBuy:
+		spec.Side = exchangev1.Side_SIDE_BUY
+	case models.SideSell:
+		spec.Side = exchangev1.Side_SIDE_SELL
+	}
+	if order.Type == models.OrderTypeMarket {
+		spec.Type = exchangev1.OrderType_ORDER_TYPE_MARKET
+	}
+	switch order.TimeInForce {
+	case models.TimeInForceIOC:
+		spec.TimeInForce = exchangev1.TimeInForce_TIME_IN_FORCE_IOC
+	case models.TimeInForceFOK:
+		spec.TimeInForce = exchangev1.TimeInForce_TIME_IN_FORCE_FOK
+	case models.TimeInForceGTD:
+		spec.TimeInForce = exchangev1.TimeInForce_TIME_IN_FORCE_GTD
+		spec.ExpireTimeMs = order.ExpiresAt.UnixMilli()
+	}
+	return spec
+}
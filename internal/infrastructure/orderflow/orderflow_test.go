@@ -0,0 +1,64 @@
+//go:build unit
+
+package orderflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+)
+
+func TestParse(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	order := models.Order{ID: "BTC-USD-1", ClientOrderID: "c-1", AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell,
+		Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTD, Quantity: 2, Price: 60000, ExpiresAt: start.Add(time.Minute)}
+
+	t.Run("reads_a_journal_of_json_lines", func(t *testing.T) {
+		// Given: A journal as the event log writes it
+		var journal bytes.Buffer
+		encoder := json.NewEncoder(&journal)
+		encoder.Encode(matching.Event{Sequence: 1, Type: matching.EventOrderSubmitted, Time: start, Order: &order})
+		encoder.Encode(matching.Event{Sequence: 2, Type: matching.EventOrderCanceled, Time: start.Add(time.Second), OrderID: order.ID})
+
+		// When: It is parsed
+		recording, err := Parse(journal.Bytes())
+
+		// Then: The place and cancel come back
+		if err != nil || len(recording.Entries) != 2 || recording.Entries[0].Order.ClientOrderID != "c-1" || recording.Entries[1].Kind != replay.KindCancel {
+			t.Errorf("Expected the place and cancel, got %+v, %v", recording.Entries, err)
+		}
+	})
+
+	t.Run("round_trips_length_delimited_protobuf", func(t *testing.T) {
+		// Given: A recording written as OrderFlowEvents
+		recording, _ := replay.NewRecording([]replay.Entry{
+			{Time: start, Kind: replay.KindPlace, OrderID: order.ID, Order: order},
+			{Time: start.Add(time.Second), Kind: replay.KindAmend, OrderID: order.ID, Amend: matching.AmendRequest{Quantity: 1}},
+			{Time: start.Add(2 * time.Second), Kind: replay.KindCancel, OrderID: order.ID},
+		})
+		var buf bytes.Buffer
+		if err := WriteProto(&buf, recording); err != nil {
+			t.Fatalf("Expected the recording written, got %v", err)
+		}
+
+		// When: The file is parsed back
+		parsed, err := Parse(buf.Bytes())
+
+		// Then: Every entry survives, the GTD expiry included
+		if err != nil || len(parsed.Entries) != 3 {
+			t.Fatalf("Expected three entries, got %+v, %v", parsed.Entries, err)
+		}
+		placed := parsed.Entries[0].Order
+		if placed != order || parsed.Entries[1].Amend.Quantity != 1 || parsed.Entries[2].Kind != replay.KindCancel {
+			t.Errorf("Expected the entries unchanged, got %+v", parsed.Entries)
+		}
+		if _, err := Parse([]byte("not a recording")); err == nil {
+			t.Error("Expected garbage refused")
+		}
+	})
+}
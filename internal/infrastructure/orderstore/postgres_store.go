@@ -0,0 +1,195 @@
+package orderstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// table holds one row per order, its latest state; order IDs are unique per venue instance
+const table = "exchange_orders"
+
+// Schema creates the table, as migration 000007_orders does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance        TEXT             NOT NULL,
+	order_id        TEXT             NOT NULL,
+	client_order_id TEXT             NOT NULL,
+	account_id      TEXT             NOT NULL,
+	symbol          TEXT             NOT NULL,
+	side            TEXT             NOT NULL,
+	type            TEXT             NOT NULL,
+	time_in_force   TEXT             NOT NULL,
+	price           DOUBLE PRECISION NOT NULL,
+	quantity        DOUBLE PRECISION NOT NULL,
+	filled_quantity DOUBLE PRECISION NOT NULL,
+	average_price   DOUBLE PRECISION NOT NULL,
+	status          TEXT             NOT NULL,
+	expires_at      TIMESTAMPTZ,
+	created_at      TIMESTAMPTZ      NOT NULL,
+	updated_at      TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, order_id)
+);
+CREATE INDEX IF NOT EXISTS ` + table + `_account ON ` + table + ` (instance, account_id, created_at);
+CREATE INDEX IF NOT EXISTS ` + table + `_symbol_time ON ` + table + ` (instance, symbol, created_at, order_id);
+CREATE INDEX IF NOT EXISTS ` + table + `_time ON ` + table + ` (instance, created_at, order_id)`
+
+const orderColumns = `order_id, client_order_id, account_id, symbol, side, type, time_in_force, price, quantity,
+	filled_quantity, average_price, status, expires_at, created_at, updated_at`
+
+// PostgresStore keeps the latest state of one venue instance's orders in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the order table and its indexes when they do not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create order table: %w", err)
+	}
+	return nil
+}
+
+// Save upserts the orders in one statement. A row is only replaced by a state at
+// least as recent, so a retried batch never rolls an order back.
+func (s *PostgresStore) Save(orders []models.Order) error {
+	orders = orderhistory.Latest(orders)
+	if len(orders) == 0 {
+		return nil
+	}
+	const columns = 16
+	values := make([]string, 0, len(orders))
+	args := make([]interface{}, 0, len(orders)*columns)
+	for i, order := range orders {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		var expiresAt interface{}
+		if !order.ExpiresAt.IsZero() {
+			expiresAt = order.ExpiresAt.UTC()
+		}
+		args = append(args, s.instance, order.ID, order.ClientOrderID, order.AccountID, order.Symbol, string(order.Side), string(order.Type),
+			string(order.TimeInForce), order.Price, order.Quantity, order.FilledQuantity, order.AveragePrice, string(order.Status),
+			expiresAt, order.CreatedAt.UTC(), order.UpdatedAt.UTC())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, `+orderColumns+`) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (instance, order_id) DO UPDATE SET
+			account_id = EXCLUDED.account_id, price = EXCLUDED.price, quantity = EXCLUDED.quantity,
+			filled_quantity = EXCLUDED.filled_quantity, average_price = EXCLUDED.average_price,
+			status = EXCLUDED.status, expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+		WHERE `+table+`.updated_at <= EXCLUDED.updated_at`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save %d orders to postgres: %w", len(orders), err)
+	}
+	return nil
+}
+
+// Reassign moves an erased account's orders to its alias
+func (s *PostgresStore) Reassign(accountID, alias string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`UPDATE `+table+` SET account_id = $3 WHERE instance = $1 AND account_id = $2`, s.instance, accountID, alias)
+	if err != nil {
+		return fmt.Errorf("failed to reassign orders in postgres: %w", err)
+	}
+	return nil
+}
+
+// Query returns one page of the instance's orders in creation order, or newest first.
+// Pages continue from the cursor's creation time and ID, so reading deep into a long
+// run costs the same as reading its first page.
+func (s *PostgresStore) Query(query orderhistory.Query) (orderhistory.Page, error) {
+	conditions := []string{"instance = $1"}
+	args := []interface{}{s.instance}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if query.AccountID != "" {
+		where("account_id = $%d", query.AccountID)
+	}
+	if query.Symbol != "" {
+		where("symbol = $%d", query.Symbol)
+	}
+	if len(query.Statuses) > 0 {
+		placeholders := make([]string, 0, len(query.Statuses))
+		for _, status := range query.Statuses {
+			args = append(args, string(status))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		conditions = append(conditions, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if !query.From.IsZero() {
+		where("created_at >= $%d", query.From.UTC())
+	}
+	if !query.To.IsZero() {
+		where("created_at < $%d", query.To.UTC())
+	}
+	comparison, order := ">", "created_at, order_id"
+	if query.Descending {
+		comparison, order = "<", "created_at DESC, order_id DESC"
+	}
+	if !query.After.IsZero() {
+		args = append(args, query.After.CreatedAt.UTC(), query.After.OrderID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, order_id) %s ($%d, $%d)", comparison, len(args)-1, len(args)))
+	}
+	args = append(args, query.Limit+1)
+	statement := `SELECT ` + orderColumns + ` FROM ` + table + ` WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d`, order, len(args))
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return orderhistory.Page{}, fmt.Errorf("failed to query orders from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]models.Order, 0, query.Limit+1)
+	for rows.Next() {
+		var order models.Order
+		var side, orderType, timeInForce, status string
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&order.ID, &order.ClientOrderID, &order.AccountID, &order.Symbol, &side, &orderType, &timeInForce,
+			&order.Price, &order.Quantity, &order.FilledQuantity, &order.AveragePrice, &status, &expiresAt,
+			&order.CreatedAt, &order.UpdatedAt); err != nil {
+			return orderhistory.Page{}, fmt.Errorf("failed to read order: %w", err)
+		}
+		order.Side, order.Type = models.Side(side), models.OrderType(orderType)
+		order.TimeInForce, order.Status = models.TimeInForce(timeInForce), models.OrderStatus(status)
+		if expiresAt.Valid {
+			order.ExpiresAt = expiresAt.Time.UTC()
+		}
+		order.CreatedAt, order.UpdatedAt = order.CreatedAt.UTC(), order.UpdatedAt.UTC()
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return orderhistory.Page{}, fmt.Errorf("failed to read orders: %w", err)
+	}
+	return orderhistory.NewPage(orders, query.Limit), nil
+}
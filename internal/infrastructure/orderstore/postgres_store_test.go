@@ -0,0 +1,116 @@
+//go:build integration
+
+package orderstore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("keeps_the_latest_state_and_reassigns_to_an_alias", func(t *testing.T) {
+		// Given: An order's new, partially filled and filled states, the fill saved
+		// before a retried batch holding the older states
+		at := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		order := models.Order{ID: "BTC-USD-1", AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit,
+			TimeInForce: models.TimeInForceGTC, Price: 100, Quantity: 2, Status: models.OrderStatusNew, CreatedAt: at, UpdatedAt: at}
+		partial := order
+		partial.FilledQuantity, partial.Status, partial.UpdatedAt = 1, models.OrderStatusPartiallyFilled, at.Add(time.Second)
+		filled := partial
+		filled.FilledQuantity, filled.Status, filled.UpdatedAt = 2, models.OrderStatusFilled, at.Add(2*time.Second)
+		if err := store.Save([]models.Order{order, partial, filled}); err != nil {
+			t.Fatalf("Expected to save, got %v", err)
+		}
+		if err := store.Save([]models.Order{order, partial}); err != nil {
+			t.Fatalf("Expected a retried batch to save, got %v", err)
+		}
+
+		// When: Account a is erased
+		if err := store.Reassign("a", "anon-1"); err != nil {
+			t.Fatalf("Expected to reassign, got %v", err)
+		}
+
+		// Then: One row holds the filled state under the alias
+		var rows int
+		var account, status string
+		err := db.QueryRow(`SELECT COUNT(*), MIN(account_id), MIN(status) FROM `+table+` WHERE instance = $1`, instance).Scan(&rows, &account, &status)
+		if err != nil || rows != 1 || account != "anon-1" || status != string(models.OrderStatusFilled) {
+			t.Errorf("Expected one filled row under the alias, got %d, %s, %s, %v", rows, account, status, err)
+		}
+	})
+
+	t.Run("pages_by_account_status_and_time_with_cursors", func(t *testing.T) {
+		// Given: Four orders of account q, two created at the same time, one a GTD order
+		start := time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)
+		orders := []models.Order{
+			{ID: "BTC-USD-11", AccountID: "q", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTC,
+				Price: 100, Quantity: 1, Status: models.OrderStatusNew, CreatedAt: start, UpdatedAt: start},
+			{ID: "BTC-USD-12", AccountID: "q", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTD,
+				Price: 101, Quantity: 1, Status: models.OrderStatusExpired, ExpiresAt: start.Add(time.Hour), CreatedAt: start, UpdatedAt: start.Add(time.Hour)},
+			{ID: "ETH-USD-11", AccountID: "q", Symbol: "ETH-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTC,
+				Price: 10, Quantity: 3, FilledQuantity: 3, AveragePrice: 10, Status: models.OrderStatusFilled, CreatedAt: start.Add(time.Minute), UpdatedAt: start.Add(time.Minute)},
+			{ID: "BTC-USD-13", AccountID: "q", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTC,
+				Price: 99, Quantity: 1, Status: models.OrderStatusCanceled, CreatedAt: start.Add(2 * time.Minute), UpdatedAt: start.Add(2 * time.Minute)},
+		}
+		if err := store.Save(orders); err != nil {
+			t.Fatalf("Expected to save, got %v", err)
+		}
+
+		// When: Account q's orders are read newest first, one at a time
+		query := orderhistory.Query{AccountID: "q", Descending: true, Limit: 1}
+		var ids []string
+		for {
+			page, err := store.Query(query)
+			if err != nil {
+				t.Fatalf("Expected a page, got %v", err)
+			}
+			for _, order := range page.Orders {
+				ids = append(ids, order.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			if query.After, err = orderhistory.ParseCursor(page.NextCursor); err != nil {
+				t.Fatalf("Expected the cursor to parse, got %v", err)
+			}
+		}
+
+		// Then: Each order is returned once, newest first
+		if len(ids) != 4 || ids[0] != "BTC-USD-13" || ids[1] != "ETH-USD-11" || ids[2] != "BTC-USD-12" || ids[3] != "BTC-USD-11" {
+			t.Errorf("Expected BTC-USD-13, ETH-USD-11, BTC-USD-12, BTC-USD-11, got %v", ids)
+		}
+
+		// And: Status and time range filters apply, with fields round-tripped
+		page, err := store.Query(orderhistory.Query{
+			AccountID: "q", Statuses: []models.OrderStatus{models.OrderStatusExpired, models.OrderStatusFilled},
+			From: start, To: start.Add(time.Minute), Limit: 10,
+		})
+		if err != nil || len(page.Orders) != 1 || page.Orders[0] != orders[1] {
+			t.Errorf("Expected BTC-USD-12 alone, got %+v, %v", page, err)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package outboxstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// execer runs statements on a connection or inside a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// table holds one row per message; message IDs are unique per venue instance
+const table = "exchange_outbox"
+
+// Schema creates the table, as migration 000005_outbox does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance        TEXT        NOT NULL,
+	message_id      TEXT        NOT NULL,
+	dedup_key       TEXT        NOT NULL,
+	destination     TEXT        NOT NULL,
+	topic           TEXT        NOT NULL,
+	payload         JSONB       NOT NULL,
+	created_at      TIMESTAMPTZ NOT NULL,
+	attempts        INTEGER     NOT NULL DEFAULT 0,
+	last_error      TEXT        NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMPTZ NOT NULL,
+	dispatched_at   TIMESTAMPTZ,
+	PRIMARY KEY (instance, message_id)
+);
+CREATE INDEX IF NOT EXISTS ` + table + `_due ON ` + table + ` (instance, next_attempt_at, created_at) WHERE dispatched_at IS NULL`
+
+const messageColumns = `message_id, dedup_key, destination, topic, payload, created_at, attempts, last_error, next_attempt_at, dispatched_at`
+
+// PostgresStore keeps one venue instance's outbox in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the outbox table and its index when they do not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	return nil
+}
+
+// Write adds messages in one statement; messages already written are skipped, so a
+// failed batch can be retried whole
+func (s *PostgresStore) Write(messages []outbox.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.write(ctx, s.client, messages)
+}
+
+// WriteTx adds messages inside a transaction another store opened, so they commit
+// with the state they announce
+func (s *PostgresStore) WriteTx(ctx context.Context, tx *sql.Tx, messages []outbox.Message) error {
+	return s.write(ctx, tx, messages)
+}
+
+func (s *PostgresStore) write(ctx context.Context, client execer, messages []outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	const columns = 10
+	values := make([]string, 0, len(messages))
+	args := make([]interface{}, 0, len(messages)*columns)
+	for i, message := range messages {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args, s.instance, message.ID, message.DedupKey, string(message.Destination), message.Topic,
+			string(message.Payload), message.CreatedAt.UTC(), message.Attempts, message.LastError, message.NextAttemptAt.UTC())
+	}
+	_, err := client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, message_id, dedup_key, destination, topic, payload, created_at, attempts, last_error, next_attempt_at)
+		VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (instance, message_id) DO NOTHING`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save %d outbox messages to postgres: %w", len(messages), err)
+	}
+	return nil
+}
+
+// Due returns up to limit undispatched messages due by now, oldest first
+func (s *PostgresStore) Due(now time.Time, limit int) ([]outbox.Message, error) {
+	statement := `SELECT ` + messageColumns + ` FROM ` + table + `
+		WHERE instance = $1 AND dispatched_at IS NULL AND next_attempt_at <= $2
+		ORDER BY created_at, message_id`
+	args := []interface{}{s.instance, now.UTC()}
+	if limit > 0 {
+		statement += ` LIMIT $3`
+		args = append(args, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox messages from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]outbox.Message, 0)
+	for rows.Next() {
+		var message outbox.Message
+		var destination, payload string
+		var dispatchedAt sql.NullTime
+		if err := rows.Scan(&message.ID, &message.DedupKey, &destination, &message.Topic, &payload, &message.CreatedAt,
+			&message.Attempts, &message.LastError, &message.NextAttemptAt, &dispatchedAt); err != nil {
+			return nil, fmt.Errorf("failed to read outbox message: %w", err)
+		}
+		message.Destination, message.Payload = outbox.Destination(destination), []byte(payload)
+		message.CreatedAt, message.NextAttemptAt = message.CreatedAt.UTC(), message.NextAttemptAt.UTC()
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// Dispatched marks messages taken by their destination
+func (s *PostgresStore) Dispatched(ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`UPDATE `+table+` SET dispatched_at = $3 WHERE instance = $1 AND message_id = ANY($2)`, s.instance, pq.Array(ids), at.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to mark %d outbox messages dispatched in postgres: %w", len(ids), err)
+	}
+	return nil
+}
+
+// Failed records each message's attempts, last error and next attempt in one statement
+func (s *PostgresStore) Failed(messages []outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(messages))
+	attempts := make([]int64, 0, len(messages))
+	errs := make([]string, 0, len(messages))
+	next := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+		attempts = append(attempts, int64(message.Attempts))
+		errs = append(errs, message.LastError)
+		next = append(next, message.NextAttemptAt.UTC().Format(time.RFC3339Nano))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.ExecContext(ctx,
+		`UPDATE `+table+` AS o SET attempts = f.attempts, last_error = f.last_error, next_attempt_at = f.next_attempt_at
+		FROM UNNEST($2::text[], $3::integer[], $4::text[], $5::timestamptz[]) AS f(message_id, attempts, last_error, next_attempt_at)
+		WHERE o.instance = $1 AND o.message_id = f.message_id`,
+		s.instance, pq.Array(ids), pq.Array(attempts), pq.Array(errs), pq.Array(next))
+	if err != nil {
+		return fmt.Errorf("failed to record %d failed outbox messages in postgres: %w", len(messages), err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package outboxstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("holds_messages_until_dispatched", func(t *testing.T) {
+		// Given: Two messages, one written twice, and one committed in a transaction
+		now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		first, _ := outbox.NewMessage("e-1", outbox.DestinationAudit, "order.placed", map[string]string{"event_id": "e-1"}, now)
+		second, _ := outbox.NewMessage("e-2", outbox.DestinationRedis, "trade.executed", map[string]string{"event_id": "e-2"}, now.Add(time.Second))
+		if err := store.Write([]outbox.Message{first}); err != nil {
+			t.Fatalf("Expected to write, got %v", err)
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("Expected a transaction, got %v", err)
+		}
+		if err := store.WriteTx(context.Background(), tx, []outbox.Message{first, second}); err != nil {
+			t.Fatalf("Expected to write in the transaction, got %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Expected to commit, got %v", err)
+		}
+
+		// When: The first fails and the second is dispatched
+		first.Attempts, first.LastError, first.NextAttemptAt = 1, "connection refused", now.Add(time.Minute)
+		if err := store.Failed([]outbox.Message{first}); err != nil {
+			t.Fatalf("Expected to record the failure, got %v", err)
+		}
+		if err := store.Dispatched([]string{second.ID}, now); err != nil {
+			t.Fatalf("Expected to mark dispatched, got %v", err)
+		}
+
+		// Then: Only the failed message comes due, once its backoff has passed
+		due, err := store.Due(now.Add(time.Second), 10)
+		if err != nil || len(due) != 0 {
+			t.Errorf("Expected nothing due during the backoff, got %+v, %v", due, err)
+		}
+		due, err = store.Due(now.Add(time.Minute), 10)
+		if err != nil || len(due) != 1 || due[0].ID != first.ID || due[0].Attempts != 1 || due[0].LastError != "connection refused" {
+			t.Fatalf("Expected the failed message due again, got %+v, %v", due, err)
+		}
+		if string(due[0].Payload) != `{"event_id": "e-1"}` && string(due[0].Payload) != `{"event_id":"e-1"}` {
+			t.Errorf("Unexpected payload %s", due[0].Payload)
+		}
+	})
+}
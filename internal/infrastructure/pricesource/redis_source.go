@@ -0,0 +1,95 @@
+package pricesource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+)
+
+// RedisPubSubClient is the subset of the Redis client used by RedisSource
+type RedisPubSubClient interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub
+}
+
+// RedisSource follows prices published as JSON on a Redis pub/sub channel, or on
+// every channel matching a pattern containing '*'
+type RedisSource struct {
+	client  RedisPubSubClient
+	channel string
+	symbols map[string]string // External symbol to listed symbol; unmapped symbols pass through
+	logger  *logrus.Logger
+}
+
+func NewRedisSource(client RedisPubSubClient, channel string, symbols map[string]string, logger *logrus.Logger) *RedisSource {
+	return &RedisSource{client: client, channel: channel, symbols: symbols, logger: logger}
+}
+
+// Follow subscribes and hands each decodable price to handle. Messages that do not
+// decode are logged and skipped rather than ending the subscription.
+func (s *RedisSource) Follow(ctx context.Context, handle func(pricefeed.Price)) error {
+	var sub *redis.PubSub
+	if strings.Contains(s.channel, "*") {
+		sub = s.client.PSubscribe(ctx, s.channel)
+	} else {
+		sub = s.client.Subscribe(ctx, s.channel)
+	}
+	defer sub.Close()
+	// Receive waits for the subscription to be confirmed, surfacing connection errors
+	if _, err := sub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to subscribe to %s: %w", s.channel, err)
+	}
+	return s.consume(ctx, sub.Channel(), handle)
+}
+
+// consume decodes messages until ctx is done or the channel closes
+func (s *RedisSource) consume(ctx context.Context, messages <-chan *redis.Message, handle func(pricefeed.Price)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("subscription to %s closed", s.channel)
+			}
+			price, err := pricefeed.Decode([]byte(msg.Payload))
+			if err != nil {
+				s.logger.WithError(err).WithField("channel", msg.Channel).Debug("Skipping undecodable price")
+				continue
+			}
+			if symbol, mapped := s.symbols[price.Symbol]; mapped {
+				price.Symbol = symbol
+			}
+			if price.Source == "" {
+				price.Source = msg.Channel
+			}
+			handle(price)
+		}
+	}
+}
+
+// ParseSymbolMap reads symbols in the PRICE_FEED_SYMBOL_MAP form
+// "external=listed,...", e.g. "BTCUSD=BTC-USD,ETHUSD=ETH-USD"
+func ParseSymbolMap(spec string) (map[string]string, error) {
+	symbols := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		external, listed, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(external) == "" || strings.TrimSpace(listed) == "" {
+			return nil, fmt.Errorf("invalid symbol mapping %q: expected external=listed", entry)
+		}
+		symbols[strings.TrimSpace(external)] = strings.TrimSpace(listed)
+	}
+	return symbols, nil
+}
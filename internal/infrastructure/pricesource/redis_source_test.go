@@ -0,0 +1,52 @@
+//go:build unit
+
+package pricesource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+)
+
+func TestRedisSource(t *testing.T) {
+	t.Run("maps_symbols_and_skips_bad_messages", func(t *testing.T) {
+		// Given: A source mapping BTCUSD onto BTC-USD and three published messages
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		symbols, err := ParseSymbolMap("BTCUSD=BTC-USD, ETHUSD = ETH-USD")
+		if err != nil {
+			t.Fatalf("Expected the symbol map to parse, got %v", err)
+		}
+		source := NewRedisSource(nil, "prices:*", symbols, logger)
+		messages := make(chan *redis.Message, 3)
+		messages <- &redis.Message{Channel: "prices:btc", Payload: `{"symbol":"BTCUSD","price":60000}`}
+		messages <- &redis.Message{Channel: "prices:btc", Payload: `garbage`}
+		messages <- &redis.Message{Channel: "prices:sol", Payload: `{"symbol":"SOL-USD","price":150,"source":"mds"}`}
+		close(messages)
+
+		// When: They are consumed until the subscription closes
+		received := make([]pricefeed.Price, 0)
+		err = source.consume(context.Background(), messages, func(price pricefeed.Price) { received = append(received, price) })
+
+		// Then: The decodable prices arrive under listed symbols, and the close is an error
+		if len(received) != 2 || received[0].Symbol != "BTC-USD" || received[0].Source != "prices:btc" ||
+			received[1].Symbol != "SOL-USD" || received[1].Source != "mds" {
+			t.Errorf("Unexpected prices: %+v", received)
+		}
+		if err == nil {
+			t.Error("Expected a closed subscription to be reported")
+		}
+	})
+
+	t.Run("rejects_malformed_symbol_maps", func(t *testing.T) {
+		for _, spec := range []string{"BTCUSD", "=BTC-USD", "BTCUSD="} {
+			if _, err := ParseSymbolMap(spec); err == nil {
+				t.Errorf("Expected %q to be rejected", spec)
+			}
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package publicid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrEmptyKey is returned when an HMAC obfuscator is built without a key
+var ErrEmptyKey = errors.New("public ID key must not be empty")
+
+// Mode names how internal IDs are published
+type Mode string
+
+const (
+	ModeInternal Mode = "internal"
+	ModeHMAC     Mode = "hmac"
+)
+
+// idBytes is how much of the HMAC digest a public ID keeps: 128 bits, hex encoded
+const idBytes = 16
+
+// Internal publishes IDs unchanged
+type Internal struct{}
+
+func NewInternal() *Internal {
+	return &Internal{}
+}
+
+func (o *Internal) External(id string) string {
+	return id
+}
+
+// HMAC publishes the HMAC-SHA256 of each ID under a secret key. The same key maps
+// an ID to the same public ID across restarts; without it the mapping can't be
+// reversed or reproduced.
+type HMAC struct {
+	key []byte
+}
+
+func NewHMAC(key []byte) (*HMAC, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	return &HMAC{key: append([]byte(nil), key...)}, nil
+}
+
+func (o *HMAC) External(id string) string {
+	if id == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil)[:idBytes])
+}
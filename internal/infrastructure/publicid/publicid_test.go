@@ -0,0 +1,51 @@
+//go:build unit
+
+package publicid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHMAC(t *testing.T) {
+	t.Run("maps_ids_stably_per_key", func(t *testing.T) {
+		// Given: Two obfuscators sharing a key and one with another key
+		first, _ := NewHMAC([]byte("venue-secret"))
+		second, _ := NewHMAC([]byte("venue-secret"))
+		other, _ := NewHMAC([]byte("other-secret"))
+
+		// When: The same trade ID is published by each
+		id := "trd-BTC-USD-42"
+		public := first.External(id)
+
+		// Then: The shared key reproduces it, another key doesn't, and it hides the input
+		if public != second.External(id) {
+			t.Errorf("Expected a stable public ID, got %s and %s", public, second.External(id))
+		}
+		if public == other.External(id) || public == id || len(public) != 2*idBytes {
+			t.Errorf("Unexpected public ID %q", public)
+		}
+		if public == first.External("trd-BTC-USD-43") {
+			t.Error("Expected distinct IDs to map apart")
+		}
+	})
+
+	t.Run("requires_a_key", func(t *testing.T) {
+		if _, err := NewHMAC(nil); !errors.Is(err, ErrEmptyKey) {
+			t.Errorf("Expected ErrEmptyKey, got %v", err)
+		}
+	})
+
+	t.Run("empty_ids_stay_empty", func(t *testing.T) {
+		obfuscator, _ := NewHMAC([]byte("venue-secret"))
+		if id := obfuscator.External(""); id != "" {
+			t.Errorf("Expected an empty ID, got %q", id)
+		}
+	})
+}
+
+func TestInternal(t *testing.T) {
+	if id := NewInternal().External("ord-BTC-USD-7"); id != "ord-BTC-USD-7" {
+		t.Errorf("Expected the internal ID, got %q", id)
+	}
+}
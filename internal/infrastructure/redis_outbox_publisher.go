@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+)
+
+// RedisPublishClient is the subset of the Redis client used by RedisOutboxPublisher
+type RedisPublishClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+}
+
+// outboxEnvelope is what subscribers receive; they drop repeats by dedup_key
+type outboxEnvelope struct {
+	DedupKey string          `json:"dedup_key"`
+	Topic    string          `json:"topic"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// RedisOutboxPublisher publishes outbox messages on a Redis channel
+type RedisOutboxPublisher struct {
+	client  RedisPublishClient
+	channel string
+}
+
+func NewRedisOutboxPublisher(client RedisPublishClient, channel string) *RedisOutboxPublisher {
+	return &RedisOutboxPublisher{client: client, channel: channel}
+}
+
+// Publish sends the messages in order. A failure part way fails the batch, whose
+// messages already sent are sent again on retry.
+func (p *RedisOutboxPublisher) Publish(ctx context.Context, messages []outbox.Message) error {
+	for _, message := range messages {
+		data, err := json.Marshal(outboxEnvelope{DedupKey: message.DedupKey, Topic: message.Topic, Payload: message.Payload})
+		if err != nil {
+			return fmt.Errorf("failed to encode outbox message %s: %w", message.ID, err)
+		}
+		if err := p.client.Publish(ctx, p.channel, data).Err(); err != nil {
+			return fmt.Errorf("failed to publish outbox message %s to redis: %w", message.ID, err)
+		}
+	}
+	return nil
+}
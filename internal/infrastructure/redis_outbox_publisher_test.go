@@ -0,0 +1,61 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+)
+
+type mockPublishClient struct {
+	failAfter int // Publishes accepted before failing; negative never fails
+	published []string
+}
+
+func (m *mockPublishClient) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	if m.failAfter >= 0 && len(m.published) == m.failAfter {
+		cmd.SetErr(errors.New("connection refused"))
+		return cmd
+	}
+	m.published = append(m.published, channel+" "+string(message.([]byte)))
+	cmd.SetVal(1)
+	return cmd
+}
+
+func TestRedisOutboxPublisher(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	first, _ := outbox.NewMessage("e-1", outbox.DestinationRedis, "order.placed", map[string]string{"order_id": "o-1"}, now)
+	second, _ := outbox.NewMessage("e-2", outbox.DestinationRedis, "trade.executed", map[string]string{"trade_id": "t-1"}, now)
+
+	t.Run("publishes_envelopes_in_order", func(t *testing.T) {
+		client := &mockPublishClient{failAfter: -1}
+		publisher := NewRedisOutboxPublisher(client, "exchange-events")
+
+		err := publisher.Publish(context.Background(), []outbox.Message{first, second})
+
+		if err != nil || len(client.published) != 2 {
+			t.Fatalf("Expected two messages published, got %v, %v", client.published, err)
+		}
+		if client.published[0] != `exchange-events {"dedup_key":"e-1","topic":"order.placed","payload":{"order_id":"o-1"}}` {
+			t.Errorf("Unexpected envelope %s", client.published[0])
+		}
+	})
+
+	t.Run("fails_the_batch_when_a_publish_fails", func(t *testing.T) {
+		client := &mockPublishClient{failAfter: 1}
+		publisher := NewRedisOutboxPublisher(client, "exchange-events")
+
+		err := publisher.Publish(context.Background(), []outbox.Message{first, second})
+
+		if err == nil || len(client.published) != 1 {
+			t.Errorf("Expected the batch failed after one publish, got %v, %v", client.published, err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy decides whether a failed unary call to another service is made again,
+// and after how long
+type RetryPolicy struct {
+	MaxAttempts int                 // Calls made in all, the first included (1 = no retries)
+	Backoff     time.Duration       // Wait before the first retry, doubling for each after
+	MaxBackoff  time.Duration       // Longest wait between attempts
+	Codes       map[codes.Code]bool // Status codes worth another attempt
+}
+
+// next reports whether the call that failed with err on attempt should be made
+// again, and the wait before it. The wait is jittered, between half and all of the
+// backoff, so callers that failed together do not retry together. No retry is made
+// that the caller's deadline would cut short.
+func (p RetryPolicy) next(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if err == nil || attempt >= p.MaxAttempts || !p.Codes[status.Code(err)] || ctx.Err() != nil {
+		return 0, false
+	}
+	backoff := p.Backoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return 0, false
+	}
+	return delay, true
+}
+
+// wait sleeps for delay, returning false if ctx ends first
+func wait(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseRetryCodes reads status codes in the GRPC_CLIENT_RETRY_CODES form
+// "CODE,...", e.g. "UNAVAILABLE,RESOURCE_EXHAUSTED"
+func parseRetryCodes(spec string) (map[codes.Code]bool, error) {
+	retryable := make(map[codes.Code]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil || code == codes.OK {
+			return nil, fmt.Errorf("invalid retry code %q: expected a gRPC status code name such as UNAVAILABLE", name)
+		}
+		retryable[code] = true
+	}
+	return retryable, nil
+}
+
+// newRetryPolicy reads the client retry settings, retrying nothing if the codes are invalid
+func newRetryPolicy(attempts int, backoff, maxBackoff time.Duration, codeSpec string) (RetryPolicy, error) {
+	policy := RetryPolicy{MaxAttempts: attempts, Backoff: backoff, MaxBackoff: maxBackoff}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff < policy.Backoff {
+		policy.MaxBackoff = policy.Backoff
+	}
+	retryable, err := parseRetryCodes(codeSpec)
+	if err != nil {
+		policy.MaxAttempts = 1
+		return policy, err
+	}
+	policy.Codes = retryable
+	return policy, nil
+}
@@ -0,0 +1,148 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy, err := newRetryPolicy(4, 100*time.Millisecond, 300*time.Millisecond, "UNAVAILABLE, aborted")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("backs_off_with_jitter_up_to_the_cap", func(t *testing.T) {
+		// Given: A policy backing off from 100ms to at most 300ms
+		// When: Each retry's wait is asked for, many times over
+		for i := 0; i < 100; i++ {
+			for attempt, backoff := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond} {
+				delay, retry := policy.next(context.Background(), attempt, unavailable)
+
+				// Then: Each wait falls between half and all of the attempt's backoff
+				if !retry || delay < backoff/2 || delay > backoff {
+					t.Fatalf("Expected a retry after %s-%s on attempt %d, got %v after %s", backoff/2, backoff, attempt, retry, delay)
+				}
+			}
+		}
+	})
+
+	t.Run("stops_at_the_last_attempt_and_on_other_codes", func(t *testing.T) {
+		// Given: The same policy
+		// When: The last attempt fails, and an earlier one fails with a code not listed
+		_, afterLast := policy.next(context.Background(), 4, unavailable)
+		_, afterInvalid := policy.next(context.Background(), 1, status.Error(codes.InvalidArgument, "bad order"))
+
+		// Then: Neither is retried
+		if afterLast || afterInvalid {
+			t.Errorf("Expected no retries, got %v and %v", afterLast, afterInvalid)
+		}
+	})
+
+	t.Run("does_not_retry_past_the_deadline", func(t *testing.T) {
+		// Given: A caller with less time left than the shortest wait
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// When: A call fails with a retryable code
+		_, retry := policy.next(ctx, 1, unavailable)
+
+		// Then: It is not retried
+		if retry {
+			t.Error("Expected no retry the deadline would cut short")
+		}
+	})
+
+	t.Run("rejects_unknown_codes", func(t *testing.T) {
+		// Given: A code list naming a code that does not exist
+		// When: The policy is made
+		policy, err := newRetryPolicy(3, time.Millisecond, time.Second, "UNAVAILABLE,FLAKY")
+
+		// Then: It is refused, and nothing is retried
+		if err == nil || policy.MaxAttempts != 1 {
+			t.Errorf("Expected an error and no retries, got %v and %+v", err, policy)
+		}
+	})
+}
+
+func TestInterServiceClientManager_Retry(t *testing.T) {
+	newManager := func(cfg *config.Config) *InterServiceClientManager {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		return NewInterServiceClientManager(cfg, logger, &ServiceDiscoveryClient{}, &ConfigurationClient{})
+	}
+
+	t.Run("retries_transient_failures_until_one_succeeds", func(t *testing.T) {
+		// Given: A service that is unavailable twice, then answers
+		manager := newManager(&config.Config{ClientRetryAttempts: 3, ClientRetryBackoff: time.Millisecond, ClientRetryCodes: "UNAVAILABLE"})
+		calls := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls < 3 {
+				return status.Error(codes.Unavailable, "connection reset")
+			}
+			return nil
+		}
+
+		// When: A call is made
+		err := manager.unaryInterceptor("audit-correlator")(context.Background(), "/audit.v1.AuditService/SubmitEvents", nil, nil, nil, invoker)
+
+		// Then: The third attempt's success is returned and the retries are counted
+		if err != nil || calls != 3 {
+			t.Errorf("Expected success on the third attempt, got %d calls and %v", calls, err)
+		}
+		if retries := manager.GetMetrics().ServiceCallRetries; retries != 2 {
+			t.Errorf("Expected 2 retries, got %d", retries)
+		}
+	})
+
+	t.Run("gives_calls_without_a_deadline_the_request_timeout", func(t *testing.T) {
+		// Given: A manager with a request timeout
+		manager := newManager(&config.Config{RequestTimeout: time.Minute})
+		var deadline time.Time
+		var ok bool
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			deadline, ok = ctx.Deadline()
+			return nil
+		}
+
+		// When: A call without a deadline is made
+		manager.unaryInterceptor("audit-correlator")(context.Background(), "/audit.v1.AuditService/SubmitEvents", nil, nil, nil, invoker)
+
+		// Then: The downstream call carries a deadline about a minute away
+		if !ok || time.Until(deadline) < 50*time.Second {
+			t.Errorf("Expected a deadline about a minute away, got %v (%v)", deadline, ok)
+		}
+	})
+
+	t.Run("stops_retrying_once_the_breaker_opens", func(t *testing.T) {
+		// Given: A breaker that trips on two failures and a policy allowing five attempts
+		manager := newManager(&config.Config{
+			ClientBreakerThreshold: 2, ClientBreakerBackoff: time.Minute,
+			ClientRetryAttempts: 5, ClientRetryBackoff: time.Millisecond, ClientRetryCodes: "UNAVAILABLE",
+		})
+		calls := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+
+		// When: A call is made to the service that keeps failing
+		err := manager.unaryInterceptor("audit-correlator")(context.Background(), "/audit.v1.AuditService/SubmitEvents", nil, nil, nil, invoker)
+
+		// Then: Attempts stop at the trip and the last failure is returned
+		if calls != 2 || status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected 2 calls and the Unavailable error, got %d calls and %v", calls, err)
+		}
+	})
+}
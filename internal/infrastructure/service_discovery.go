@@ -19,20 +19,25 @@ type RedisClient interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
-	Keys(ctx context.Context, pattern string) *redis.StringSliceCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+	SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
 	Close() error
 }
 
 type ServiceInfo struct {
-	ServiceName string            `json:"service_name"`
-	Host        string            `json:"host"`
-	GRPCPort    int               `json:"grpc_port"`
-	HTTPPort    int               `json:"http_port"`
-	Version     string            `json:"version"`
-	Environment string            `json:"environment"`
-	Status      string            `json:"status"`
-	LastSeen    time.Time         `json:"last_seen"`
-	Metadata    map[string]string `json:"metadata"`
+	ServiceName  string            `json:"service_name"`
+	InstanceName string            `json:"instance_name,omitempty"` // e.g. "exchange-OKX"; empty when only one instance runs
+	Host         string            `json:"host"`
+	GRPCPort     int               `json:"grpc_port"`
+	HTTPPort     int               `json:"http_port"`
+	Version      string            `json:"version"`
+	Environment  string            `json:"environment"`
+	Status       string            `json:"status"`
+	LastSeen     time.Time         `json:"last_seen"`
+	Metadata     map[string]string `json:"metadata"`
 }
 
 type ServiceDiscoveryMetrics struct {
@@ -59,6 +64,15 @@ type ServiceDiscoveryClient struct {
 	metricsMutex   sync.RWMutex
 	isRunning      bool
 	runningMutex   sync.RWMutex
+	strategy       LoadBalancingStrategy            // Strategy for services without their own
+	strategies     map[string]LoadBalancingStrategy // Per-service overrides
+	balancers      map[string]LoadBalancer
+	failures       map[string]time.Time // Last failure reported per endpoint
+	balancingMutex sync.Mutex
+	watches        map[string]*serviceWatch // nil until the first Watch starts the watch loop
+	watchTrigger   chan struct{}
+	watchID        int
+	watchMutex     sync.Mutex
 }
 
 const (
@@ -66,6 +80,13 @@ const (
 	heartbeatInterval    = 30 * time.Second
 	serviceTimeout       = 90 * time.Second
 	discoveryKeyPattern  = "services:*"
+	// Registrations are indexed so discovery reads sets instead of scanning the
+	// keyspace: a set of service names, and per service a set of registration keys.
+	// Keys still expire; index members whose key has gone are pruned when read.
+	serviceIndexKey      = "service-index"
+	serviceIndexPrefix   = "service-index:"
+	scanCount            = 100 // Keys examined per SCAN call
+	registryServiceName  = "redis" // Names the registry in ClientErrors
 )
 
 func NewServiceDiscoveryClient(cfg *config.Config, logger *logrus.Logger) *ServiceDiscoveryClient {
@@ -81,22 +102,49 @@ func NewServiceDiscoveryClient(cfg *config.Config, logger *logrus.Logger) *Servi
 
 	redisClient := redis.NewClient(opt)
 
+	host, hostSource := advertisedHost(cfg)
+	environment := cfg.Environment
+	if environment == "" {
+		environment = "development"
+	}
+	instanceID := cfg.ServiceInstanceName
+	if instanceID == "" {
+		instanceID = fmt.Sprintf("%s-%d", cfg.ServiceName, time.Now().Unix())
+	}
+	deployment := "local"
+	if hostSource == "orchestrator" || hostSource == "interface" {
+		deployment = "container"
+	}
+
 	serviceInfo := ServiceInfo{
-		ServiceName: cfg.ServiceName,
-		Host:        "localhost", // This could be made configurable
-		GRPCPort:    cfg.GRPCPort,
-		HTTPPort:    cfg.HTTPPort,
-		Version:     cfg.ServiceVersion,
-		Environment: "development", // This could be made configurable
-		Status:      "healthy",
-		LastSeen:    time.Now(),
+		ServiceName:  cfg.ServiceName,
+		InstanceName: cfg.ServiceInstanceName,
+		Host:         host,
+		GRPCPort:     cfg.GRPCPort,
+		HTTPPort:     cfg.HTTPPort,
+		Version:      cfg.ServiceVersion,
+		Environment:  environment,
+		Status:       "healthy",
+		LastSeen:     time.Now(),
 		Metadata: map[string]string{
 			"type":        "exchange-simulator",
-			"deployment":  "local",
-			"instance_id": fmt.Sprintf("%s-%d", cfg.ServiceName, time.Now().Unix()),
+			"deployment":  deployment,
+			"instance_id": instanceID,
+			"host_source": hostSource,
 		},
 	}
 
+	strategy, err := ParseLoadBalancingStrategy(cfg.DiscoveryStrategy)
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring DISCOVERY_LOAD_BALANCING")
+		strategy = StrategyRoundRobin
+	}
+	strategies, err := parseLoadBalancing(cfg.DiscoveryStrategies)
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring DISCOVERY_LOAD_BALANCING_SERVICES")
+		strategies = make(map[string]LoadBalancingStrategy)
+	}
+
 	return &ServiceDiscoveryClient{
 		config:      cfg,
 		logger:      logger,
@@ -107,6 +155,8 @@ func NewServiceDiscoveryClient(cfg *config.Config, logger *logrus.Logger) *Servi
 		metrics: ServiceDiscoveryMetrics{
 			IsConnected: false,
 		},
+		strategy:   strategy,
+		strategies: strategies,
 	}
 }
 
@@ -115,14 +165,14 @@ func (s *ServiceDiscoveryClient) Start() error {
 	defer s.runningMutex.Unlock()
 
 	if s.isRunning {
-		return fmt.Errorf("service discovery already running")
+		return newClientError(ErrConflict, "", "service discovery already running", nil)
 	}
 
 	// Test Redis connection
 	err := s.redisClient.Ping(s.ctx).Err()
 	if err != nil {
 		s.updateConnectionStatus(false)
-		return fmt.Errorf("failed to connect to Redis: %w", err)
+		return newClientError(transportErrorKind(err), registryServiceName, "failed to connect to Redis", err)
 	}
 
 	s.updateConnectionStatus(true)
@@ -130,7 +180,7 @@ func (s *ServiceDiscoveryClient) Start() error {
 	// Register service
 	err = s.registerService()
 	if err != nil {
-		return fmt.Errorf("failed to register service: %w", err)
+		return newClientError(errorKind(err), registryServiceName, "failed to register service", err)
 	}
 
 	// Start heartbeat
@@ -141,6 +191,8 @@ func (s *ServiceDiscoveryClient) Start() error {
 
 	s.logger.WithFields(logrus.Fields{
 		"service":     s.serviceInfo.ServiceName,
+		"instance":    s.serviceInfo.InstanceName,
+		"host":        s.serviceInfo.Host,
 		"grpc_port":   s.serviceInfo.GRPCPort,
 		"http_port":   s.serviceInfo.HTTPPort,
 		"environment": s.serviceInfo.Environment,
@@ -187,15 +239,10 @@ func (s *ServiceDiscoveryClient) Stop() error {
 func (s *ServiceDiscoveryClient) DiscoverServices(serviceName string) ([]ServiceInfo, error) {
 	s.incrementDiscoveryCount()
 
-	pattern := discoveryKeyPattern
-	if serviceName != "" {
-		pattern = fmt.Sprintf("services:%s:*", serviceName)
-	}
-
-	keys, err := s.redisClient.Keys(s.ctx, pattern).Result()
+	keys, err := s.registrationKeys(serviceName)
 	if err != nil {
 		s.incrementLookupError()
-		return nil, fmt.Errorf("failed to discover services: %w", err)
+		return nil, newClientError(transportErrorKind(err), registryServiceName, "failed to discover services", err)
 	}
 
 	if len(keys) == 0 {
@@ -207,6 +254,10 @@ func (s *ServiceDiscoveryClient) DiscoverServices(serviceName string) ([]Service
 
 	for _, key := range keys {
 		serviceData, err := s.redisClient.Get(s.ctx, key).Result()
+		if err == redis.Nil {
+			s.pruneIndex(key)
+			continue
+		}
 		if err != nil {
 			s.logger.WithError(err).WithField("key", key).Warn("Failed to get service data")
 			continue
@@ -227,7 +278,7 @@ func (s *ServiceDiscoveryClient) DiscoverServices(serviceName string) ([]Service
 	s.incrementLookupCount()
 
 	s.logger.WithFields(logrus.Fields{
-		"pattern":        pattern,
+		"service":        serviceName,
 		"keys_found":     len(keys),
 		"healthy_services": len(services),
 	}).Debug("Service discovery completed")
@@ -244,31 +295,76 @@ func (s *ServiceDiscoveryClient) GetServiceEndpoint(serviceName string) (string,
 
 	if len(services) == 0 {
 		s.incrementLookupError()
-		return "", fmt.Errorf("no healthy instances of service %s found", serviceName)
+		return "", newClientError(ErrUnavailable, serviceName, fmt.Sprintf("no healthy instances of service %s found", serviceName), nil)
 	}
 
-	// For simplicity, return the first healthy service
-	// In production, you might want load balancing logic here
-	service := services[0]
-	endpoint := fmt.Sprintf("%s:%d", service.Host, service.GRPCPort)
+	strategy, balancer := s.balancerFor(serviceName)
+	s.balancingMutex.Lock()
+	instances := weighInstances(services, s.failures, time.Now())
+	s.balancingMutex.Unlock()
+	service := balancer.Pick(serviceName, instances)
+	endpoint := service.Endpoint
 
 	s.incrementLookupCount()
 
 	s.logger.WithFields(logrus.Fields{
-		"service":  serviceName,
-		"endpoint": endpoint,
-		"version":  service.Version,
+		"service":   serviceName,
+		"endpoint":  endpoint,
+		"version":   service.Version,
+		"instances": len(instances),
+		"strategy":  strategy,
 	}).Debug("Service endpoint resolved")
 
 	return endpoint, nil
 }
 
+// ReportEndpointFailure notes that a call or connection to endpoint failed. The
+// instance weighs less for a while, and least_recent_failure passes it over while
+// others have failed less recently.
+func (s *ServiceDiscoveryClient) ReportEndpointFailure(endpoint string) {
+	s.balancingMutex.Lock()
+	defer s.balancingMutex.Unlock()
+	if s.failures == nil {
+		s.failures = make(map[string]time.Time)
+	}
+	s.failures[endpoint] = time.Now()
+}
+
+// balancerFor returns the strategy and balancer for serviceName, made on first use
+func (s *ServiceDiscoveryClient) balancerFor(serviceName string) (LoadBalancingStrategy, LoadBalancer) {
+	s.balancingMutex.Lock()
+	defer s.balancingMutex.Unlock()
+	strategy, exists := s.strategies[serviceName]
+	if !exists {
+		strategy = s.strategy
+	}
+	if s.balancers == nil {
+		s.balancers = make(map[string]LoadBalancer)
+	}
+	balancer, exists := s.balancers[serviceName]
+	if !exists {
+		balancer = NewLoadBalancer(strategy)
+		s.balancers[serviceName] = balancer
+	}
+	return strategy, balancer
+}
+
 func (s *ServiceDiscoveryClient) GetMetrics() ServiceDiscoveryMetrics {
 	s.metricsMutex.RLock()
 	defer s.metricsMutex.RUnlock()
 	return s.metrics
 }
 
+// Ping round-trips to the registry's Redis, recording whether it answered
+func (s *ServiceDiscoveryClient) Ping(ctx context.Context) error {
+	if err := s.redisClient.Ping(ctx).Err(); err != nil {
+		s.updateConnectionStatus(false)
+		return newClientError(transportErrorKind(err), registryServiceName, "registry did not answer", err)
+	}
+	s.updateConnectionStatus(true)
+	return nil
+}
+
 func (s *ServiceDiscoveryClient) IsRunning() bool {
 	s.runningMutex.RLock()
 	defer s.runningMutex.RUnlock()
@@ -287,7 +383,10 @@ func (s *ServiceDiscoveryClient) registerService() error {
 
 	err = s.redisClient.Set(s.ctx, key, data, serviceTimeout).Err()
 	if err != nil {
-		return fmt.Errorf("failed to register service in Redis: %w", err)
+		return newClientError(transportErrorKind(err), registryServiceName, "failed to register service in Redis", err)
+	}
+	if err := s.indexRegistration(key); err != nil {
+		return newClientError(transportErrorKind(err), registryServiceName, "failed to index service in Redis", err)
 	}
 
 	s.logger.WithField("key", key).Info("Service registered")
@@ -299,8 +398,9 @@ func (s *ServiceDiscoveryClient) unregisterService() error {
 
 	err := s.redisClient.Del(s.ctx, key).Err()
 	if err != nil {
-		return fmt.Errorf("failed to unregister service: %w", err)
+		return newClientError(transportErrorKind(err), registryServiceName, "failed to unregister service", err)
 	}
+	s.pruneIndex(key)
 
 	s.logger.WithField("key", key).Info("Service unregistered")
 	return nil
@@ -5,7 +5,9 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,12 +24,14 @@ type mockRedisClient struct {
 	setError  error
 	getError  error
 	delError  error
-	keysError error
+	scanError error
+	sets      map[string]map[string]bool
 }
 
 func newMockRedisClient() *mockRedisClient {
 	return &mockRedisClient{
 		data: make(map[string]string),
+		sets: make(map[string]map[string]bool),
 	}
 }
 
@@ -89,28 +93,58 @@ func (m *mockRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd
 	return cmd
 }
 
-func (m *mockRedisClient) Keys(ctx context.Context, pattern string) *redis.StringSliceCmd {
-	cmd := redis.NewStringSliceCmd(ctx, "keys", pattern)
-	if m.keysError != nil {
-		cmd.SetErr(m.keysError)
-	} else {
-		var keys []string
-		for key := range m.data {
-			// Simple pattern matching for testing
-			if pattern == "services:*" || pattern == discoveryKeyPattern {
-				if len(key) > 9 && key[:9] == "services:" {
-					keys = append(keys, key)
-				}
-			} else if len(pattern) > 2 && pattern[len(pattern)-1] == '*' {
-				// Handle patterns like "services:test-service:*" or "services:target-service:*"
-				prefix := pattern[:len(pattern)-1] // Remove the "*"
-				if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
-					keys = append(keys, key)
-				}
-			}
+func (m *mockRedisClient) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx, "expire", key)
+	cmd.SetVal(true)
+	return cmd
+}
+
+func (m *mockRedisClient) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx, "sadd", key)
+	if m.sets[key] == nil {
+		m.sets[key] = make(map[string]bool)
+	}
+	for _, member := range members {
+		m.sets[key][fmt.Sprint(member)] = true
+	}
+	cmd.SetVal(int64(len(members)))
+	return cmd
+}
+
+func (m *mockRedisClient) SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx, "srem", key)
+	for _, member := range members {
+		delete(m.sets[key], fmt.Sprint(member))
+	}
+	cmd.SetVal(int64(len(members)))
+	return cmd
+}
+
+func (m *mockRedisClient) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(ctx, "smembers", key)
+	members := []string{}
+	for member := range m.sets[key] {
+		members = append(members, member)
+	}
+	cmd.SetVal(members)
+	return cmd
+}
+
+// Scan returns every match in one page
+func (m *mockRedisClient) Scan(ctx context.Context, cursor uint64, pattern string, count int64) *redis.ScanCmd {
+	cmd := redis.NewScanCmd(ctx, nil, "scan", cursor, "match", pattern)
+	if m.scanError != nil {
+		cmd.SetErr(m.scanError)
+		return cmd
+	}
+	var keys []string
+	for key := range m.data {
+		// Simple pattern matching for testing: patterns end in "*"
+		if strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, key)
 		}
-		cmd.SetVal(keys)
 	}
+	cmd.SetVal(keys, 0)
 	return cmd
 }
 
@@ -214,6 +248,44 @@ func TestServiceDiscoveryClient_Start(t *testing.T) {
 	})
 }
 
+func TestServiceDiscoveryClient_Ping(t *testing.T) {
+	newClient := func(pingError error) *ServiceDiscoveryClient {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewServiceDiscoveryClient(&config.Config{ServiceName: "test-service", RedisURL: "redis://localhost:6379"}, logger)
+		mockRedis := newMockRedisClient()
+		mockRedis.pingError = pingError
+		client.redisClient = mockRedis
+		return client
+	}
+
+	t.Run("answers_while_the_registry_does", func(t *testing.T) {
+		// Given: A registry that answers
+		client := newClient(nil)
+
+		// When/Then: It is pinged, and recorded as connected
+		if err := client.Ping(context.Background()); err != nil {
+			t.Errorf("Expected the registry to answer, got %v", err)
+		}
+		if !client.GetMetrics().IsConnected {
+			t.Error("Expected the client recorded as connected")
+		}
+	})
+
+	t.Run("classifies_a_registry_that_does_not_answer", func(t *testing.T) {
+		// Given: A registry that refuses connections
+		client := newClient(fmt.Errorf("dial tcp: connection refused"))
+
+		// When: It is pinged
+		err := client.Ping(context.Background())
+
+		// Then: The failure is classified and recorded
+		if !errors.Is(err, ErrUnavailable) || client.GetMetrics().IsConnected {
+			t.Errorf("Expected an unavailable registry, got %v", err)
+		}
+	})
+}
+
 func TestServiceDiscoveryClient_Stop(t *testing.T) {
 	t.Run("successfully_stops_and_unregisters_service", func(t *testing.T) {
 		cfg := &config.Config{
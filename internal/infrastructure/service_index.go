@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// indexRegistration adds this instance's registration key to its service's index.
+// The index expires with the registration unless a heartbeat renews it.
+func (s *ServiceDiscoveryClient) indexRegistration(key string) error {
+	index := serviceIndexPrefix + s.serviceInfo.ServiceName
+	if err := s.redisClient.SAdd(s.ctx, index, key).Err(); err != nil {
+		return err
+	}
+	if err := s.redisClient.Expire(s.ctx, index, serviceTimeout).Err(); err != nil {
+		return err
+	}
+	return s.redisClient.SAdd(s.ctx, serviceIndexKey, s.serviceInfo.ServiceName).Err()
+}
+
+// pruneIndex removes a registration key that has gone from its service's index
+func (s *ServiceDiscoveryClient) pruneIndex(key string) {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) < 3 {
+		return
+	}
+	if err := s.redisClient.SRem(s.ctx, serviceIndexPrefix+parts[1], key).Err(); err != nil {
+		s.logger.WithError(err).WithField("key", key).Debug("Failed to prune service index")
+	}
+}
+
+// registrationKeys returns the registration keys of serviceName's instances, or of
+// every service's when it is empty, in order. They are read from the index; a
+// service with nothing indexed, e.g. one whose instances do not keep the index, is
+// found by scanning for its keys instead.
+func (s *ServiceDiscoveryClient) registrationKeys(serviceName string) ([]string, error) {
+	names := []string{serviceName}
+	if serviceName == "" {
+		indexed, err := s.redisClient.SMembers(s.ctx, serviceIndexKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		names = indexed
+	}
+
+	var keys []string
+	for _, name := range names {
+		members, err := s.redisClient.SMembers(s.ctx, serviceIndexPrefix+name).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 && serviceName == "" {
+			// Every instance has expired along with the index
+			s.redisClient.SRem(s.ctx, serviceIndexKey, name)
+		}
+		keys = append(keys, members...)
+	}
+	if len(keys) == 0 {
+		pattern := discoveryKeyPattern
+		if serviceName != "" {
+			pattern = fmt.Sprintf("%s%s:*", serviceKeyPrefix, serviceName)
+		}
+		return s.scanKeys(pattern)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// scanKeys walks the keyspace with SCAN, which unlike KEYS does not hold Redis up
+func (s *ServiceDiscoveryClient) scanKeys(pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	keys := []string{}
+	var cursor uint64
+	for {
+		page, next, err := s.redisClient.Scan(s.ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range page {
+			// SCAN may return a key more than once
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,85 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+func TestServiceDiscoveryClient_Index(t *testing.T) {
+	newClient := func(serviceName string, grpcPort int, mockRedis *mockRedisClient) *ServiceDiscoveryClient {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		client := NewServiceDiscoveryClient(&config.Config{ServiceName: serviceName, GRPCPort: grpcPort, RedisURL: "redis://localhost:6379"}, logger)
+		client.redisClient = mockRedis
+		return client
+	}
+
+	t.Run("discovers_registered_instances_without_scanning", func(t *testing.T) {
+		// Given: Two registered instances, and a Redis user not allowed to scan
+		mockRedis := newMockRedisClient()
+		for _, port := range []int{50051, 50052} {
+			if err := newClient("target-service", port, mockRedis).Start(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		mockRedis.scanError = errors.New("NOPERM this user has no permissions to run the 'scan' command")
+
+		// When: The service and then every service are discovered
+		services, err := newClient("test-service", 50060, mockRedis).DiscoverServices("target-service")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		all, err := newClient("test-service", 50060, mockRedis).DiscoverServices("")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both instances are found from the index
+		if len(services) != 2 || len(all) != 2 {
+			t.Errorf("Expected 2 instances each time, got %d and %d", len(services), len(all))
+		}
+	})
+
+	t.Run("prunes_registrations_that_expired", func(t *testing.T) {
+		// Given: A registered instance whose key has expired
+		mockRedis := newMockRedisClient()
+		if err := newClient("target-service", 50051, mockRedis).Start(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		delete(mockRedis.data, "services:target-service:localhost:50051")
+
+		// When: The service is discovered
+		services, err := newClient("test-service", 50060, mockRedis).DiscoverServices("target-service")
+
+		// Then: Nothing is found and the index no longer names the key
+		if err != nil || len(services) != 0 {
+			t.Fatalf("Expected no instances and no error, got %d, %v", len(services), err)
+		}
+		if len(mockRedis.sets[serviceIndexPrefix+"target-service"]) != 0 {
+			t.Errorf("Expected the expired key pruned, got %v", mockRedis.sets)
+		}
+	})
+
+	t.Run("unregistering_leaves_the_index", func(t *testing.T) {
+		// Given: A registered instance
+		mockRedis := newMockRedisClient()
+		client := newClient("target-service", 50051, mockRedis)
+		if err := client.Start(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: It stops
+		client.Stop()
+
+		// Then: Its key is gone from the index
+		if len(mockRedis.sets[serviceIndexPrefix+"target-service"]) != 0 {
+			t.Errorf("Expected the index emptied, got %v", mockRedis.sets)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultWatchInterval is how often watched services are re-read when
+	// DISCOVERY_WATCH_INTERVAL is not set
+	defaultWatchInterval = 5 * time.Second
+	// watchBuffer is how many events a subscriber may fall behind before events to
+	// it are dropped
+	watchBuffer = 64
+	// keyspacePattern matches Redis keyspace notifications for service registrations
+	keyspacePattern = "__keyspace@*__:" + discoveryKeyPattern
+)
+
+// ServiceEventType is whether an instance came up or went down
+type ServiceEventType string
+
+const (
+	ServiceUp   ServiceEventType = "up"
+	ServiceDown ServiceEventType = "down" // Unregistered, or its registration went stale
+)
+
+// ServiceEvent is a change to the instances registered for a watched service
+type ServiceEvent struct {
+	Type     ServiceEventType `json:"type"`
+	Endpoint string           `json:"endpoint"`
+	Service  ServiceInfo      `json:"service"`
+}
+
+// serviceWatch is what is known of one watched service and who is told of changes
+type serviceWatch struct {
+	instances   map[string]ServiceInfo // By endpoint, as last read
+	subscribers map[int]chan ServiceEvent
+}
+
+// keyspaceSubscriber is a Redis client that can subscribe to keyspace
+// notifications; the go-redis client is one
+type keyspaceSubscriber interface {
+	PSubscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// Watch pushes an up event for each instance of serviceName as it registers, then a
+// down event as it unregisters or goes stale, to the returned channel until stop is
+// called. Instances already registered are announced first. Changes are seen as
+// Redis keyspace notifications arrive, when the server sends them
+// (notify-keyspace-events including "K$gx"), and otherwise at the watch interval.
+func (s *ServiceDiscoveryClient) Watch(serviceName string) (<-chan ServiceEvent, func()) {
+	events := make(chan ServiceEvent, watchBuffer)
+
+	s.watchMutex.Lock()
+	if s.watches == nil {
+		s.watches = make(map[string]*serviceWatch)
+		s.watchTrigger = make(chan struct{}, 1)
+		go s.watchLoop()
+	}
+	watch, exists := s.watches[serviceName]
+	if !exists {
+		watch = &serviceWatch{instances: make(map[string]ServiceInfo), subscribers: make(map[int]chan ServiceEvent)}
+		s.watches[serviceName] = watch
+	}
+	s.watchID++
+	id := s.watchID
+	watch.subscribers[id] = events
+	for endpoint, service := range watch.instances {
+		select {
+		case events <- ServiceEvent{Type: ServiceUp, Endpoint: endpoint, Service: service}:
+		default:
+		}
+	}
+	s.watchMutex.Unlock()
+	s.triggerWatch()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			s.watchMutex.Lock()
+			defer s.watchMutex.Unlock()
+			delete(watch.subscribers, id)
+			close(events)
+		})
+	}
+	return events, stop
+}
+
+// triggerWatch asks the watch loop to re-read the watched services
+func (s *ServiceDiscoveryClient) triggerWatch() {
+	select {
+	case s.watchTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// watchLoop re-reads the watched services when triggered, on a keyspace
+// notification and at the watch interval, until the client stops
+func (s *ServiceDiscoveryClient) watchLoop() {
+	interval := s.config.DiscoveryWatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var notifications <-chan *redis.Message
+	if subscriber, ok := s.redisClient.(keyspaceSubscriber); ok {
+		pubsub := subscriber.PSubscribe(s.ctx, keyspacePattern)
+		defer pubsub.Close()
+		notifications = pubsub.Channel()
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-notifications:
+		case <-s.watchTrigger:
+		case <-ticker.C:
+		}
+		s.refreshWatches()
+	}
+}
+
+// refreshWatches reads each watched service and tells its subscribers what changed
+func (s *ServiceDiscoveryClient) refreshWatches() {
+	s.watchMutex.Lock()
+	names := make([]string, 0, len(s.watches))
+	for name := range s.watches {
+		names = append(names, name)
+	}
+	s.watchMutex.Unlock()
+
+	for _, name := range names {
+		services, err := s.DiscoverServices(name)
+		if err != nil {
+			s.logger.WithError(err).WithField("service", name).Debug("Failed to refresh watched service")
+			continue
+		}
+		current := make(map[string]ServiceInfo, len(services))
+		for _, service := range services {
+			current[fmt.Sprintf("%s:%d", service.Host, service.GRPCPort)] = service
+		}
+
+		s.watchMutex.Lock()
+		watch := s.watches[name]
+		var changes []ServiceEvent
+		for endpoint, service := range current {
+			if _, known := watch.instances[endpoint]; !known {
+				changes = append(changes, ServiceEvent{Type: ServiceUp, Endpoint: endpoint, Service: service})
+			}
+		}
+		for endpoint, service := range watch.instances {
+			if _, still := current[endpoint]; !still {
+				changes = append(changes, ServiceEvent{Type: ServiceDown, Endpoint: endpoint, Service: service})
+			}
+		}
+		watch.instances = current
+		for _, event := range changes {
+			s.logger.WithFields(logrus.Fields{
+				"service":  name,
+				"endpoint": event.Endpoint,
+				"event":    event.Type,
+			}).Info("Watched service instance changed")
+			for _, subscriber := range watch.subscribers {
+				select {
+				case subscriber <- event:
+				default:
+					s.logger.WithFields(logrus.Fields{"service": name, "endpoint": event.Endpoint}).Warn("Service watcher fell behind; event dropped")
+				}
+			}
+		}
+		s.watchMutex.Unlock()
+	}
+}
@@ -0,0 +1,142 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// newWatchedDiscovery returns a discovery client on a mock Redis that re-reads
+// watched services only when triggered
+func newWatchedDiscovery(t *testing.T) (*ServiceDiscoveryClient, *mockRedisClient) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	client := NewServiceDiscoveryClient(&config.Config{ServiceName: "test-service", RedisURL: "redis://localhost:6379", DiscoveryWatchInterval: time.Hour}, logger)
+	mockRedis := newMockRedisClient()
+	client.redisClient = mockRedis
+	t.Cleanup(client.cancel)
+	return client, mockRedis
+}
+
+func registerInstance(mockRedis *mockRedisClient, endpoint string) {
+	host, portSpec, _ := net.SplitHostPort(endpoint)
+	port, _ := strconv.Atoi(portSpec)
+	data, _ := json.Marshal(ServiceInfo{ServiceName: "target-service", Host: host, GRPCPort: port, Status: "healthy", LastSeen: time.Now()})
+	mockRedis.data[fmt.Sprintf("services:target-service:%s", endpoint)] = string(data)
+}
+
+func nextEvent(t *testing.T, events <-chan ServiceEvent) ServiceEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a service event")
+	}
+	return ServiceEvent{}
+}
+
+func TestServiceDiscoveryClient_Watch(t *testing.T) {
+	t.Run("pushes_instances_coming_up_and_going_down", func(t *testing.T) {
+		// Given: A watched service with one instance registered
+		client, mockRedis := newWatchedDiscovery(t)
+		registerInstance(mockRedis, "host-a:50051")
+		events, stop := client.Watch("target-service")
+		defer stop()
+
+		// When: The instance is announced, then a second replaces it
+		first := nextEvent(t, events)
+		delete(mockRedis.data, "services:target-service:host-a:50051")
+		registerInstance(mockRedis, "host-b:50051")
+		client.triggerWatch()
+		changes := map[ServiceEventType]string{}
+		for i := 0; i < 2; i++ {
+			event := nextEvent(t, events)
+			changes[event.Type] = event.Endpoint
+		}
+
+		// Then: Subscribers hear host-a come up, then host-b come up and host-a go down
+		if first.Type != ServiceUp || first.Endpoint != "host-a:50051" {
+			t.Errorf("Expected host-a up first, got %+v", first)
+		}
+		if changes[ServiceUp] != "host-b:50051" || changes[ServiceDown] != "host-a:50051" {
+			t.Errorf("Expected host-b up and host-a down, got %v", changes)
+		}
+	})
+
+	t.Run("stops_pushing_once_stopped", func(t *testing.T) {
+		// Given: A watch that has been stopped
+		client, mockRedis := newWatchedDiscovery(t)
+		registerInstance(mockRedis, "host-a:50051")
+		events, stop := client.Watch("target-service")
+		nextEvent(t, events)
+		stop()
+
+		// When: An instance comes up
+		registerInstance(mockRedis, "host-b:50051")
+		client.triggerWatch()
+
+		// Then: The channel is closed rather than sent to
+		if event, open := <-events; open {
+			t.Errorf("Expected the channel closed, got %+v", event)
+		}
+	})
+}
+
+func TestInterServiceClientManager_Watch(t *testing.T) {
+	t.Run("rebuilds_the_connection_when_its_instance_goes_down", func(t *testing.T) {
+		// Given: A manager connected to the only registered instance of a service
+		discovery, mockRedis := newWatchedDiscovery(t)
+		first, second := startHealthServer(t), startHealthServer(t)
+		registerInstance(mockRedis, first)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		manager := NewInterServiceClientManager(&config.Config{}, logger, discovery, &ConfigurationClient{})
+		defer manager.Close()
+		if _, err := manager.getOrCreateConnection("target-service"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		manager.setClient("target-service", "client")
+		waitFor(t, func() bool {
+			discovery.watchMutex.Lock()
+			defer discovery.watchMutex.Unlock()
+			return len(discovery.watches["target-service"].instances) == 1
+		})
+
+		// When: That instance unregisters and another registers
+		delete(mockRedis.data, "services:target-service:"+first)
+		registerInstance(mockRedis, second)
+		discovery.triggerWatch()
+
+		// Then: The manager reconnects to the new instance and drops the stale client
+		waitFor(t, func() bool {
+			manager.connectionMutex.Lock()
+			defer manager.connectionMutex.Unlock()
+			pool, exists := manager.connections["target-service"]
+			return exists && pool.endpoint() == second
+		})
+		if _, exists := manager.getClient("target-service"); exists {
+			t.Error("Expected the client on the old connection to be dropped")
+		}
+	})
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatal("Timed out waiting for the condition")
+}
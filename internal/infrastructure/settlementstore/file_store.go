@@ -0,0 +1,84 @@
+package settlementstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+// FileStore appends every state of every settlement instruction to a file as JSON
+// lines; reading it back, the last line for an ID is its state
+type FileStore struct {
+	path    string
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// OpenFileStore opens path for appending, creating it if needed
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open settlement outbox: %w", err)
+	}
+	return &FileStore{path: path, file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Save appends the instructions and syncs the file, so an instruction is not lost
+// once generated
+func (s *FileStore) Save(instructions ...settlement.Instruction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, instruction := range instructions {
+		if err := s.encoder.Encode(instruction); err != nil {
+			return fmt.Errorf("failed to write settlement outbox: %w", err)
+		}
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync settlement outbox: %w", err)
+	}
+	return nil
+}
+
+// Load reads back every saved state in order; a missing file holds none
+func (s *FileStore) Load() ([]settlement.Instruction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []settlement.Instruction{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open settlement outbox: %w", err)
+	}
+	defer file.Close()
+
+	instructions := make([]settlement.Instruction, 0)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var instruction settlement.Instruction
+		if err := json.Unmarshal(scanner.Bytes(), &instruction); err != nil {
+			return nil, fmt.Errorf("invalid settlement instruction on line %d: %w", line, err)
+		}
+		instructions = append(instructions, instruction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read settlement outbox: %w", err)
+	}
+	return instructions, nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
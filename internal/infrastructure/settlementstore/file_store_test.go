@@ -0,0 +1,45 @@
+//go:build unit
+
+package settlementstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("survives_reopen_with_every_state", func(t *testing.T) {
+		// Given: An instruction saved pending, then acknowledged
+		path := filepath.Join(t.TempDir(), "settlements.jsonl")
+		store, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		instruction := settlement.Instruction{ID: "stl-2", BatchID: "batch-1", AccountID: "alice", Asset: "BTC", Amount: 1,
+			Direction: settlement.DirectionReceive, TradeIDs: []string{"t-1"}, CreatedAt: createdAt, Status: settlement.StatusPending}
+		store.Save(instruction)
+		instruction.Status, instruction.Reference = settlement.StatusAcknowledged, "cust-1"
+		store.Save(instruction)
+		store.Close()
+
+		// When: It is reopened, as after a restart
+		reopened, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer reopened.Close()
+		saved, err := reopened.Load()
+
+		// Then: Both states come back in order
+		if err != nil || len(saved) != 2 {
+			t.Fatalf("Expected 2 saved states, got %+v, %v", saved, err)
+		}
+		if saved[0].Status != settlement.StatusPending || saved[1].Reference != "cust-1" || !saved[1].CreatedAt.Equal(createdAt) {
+			t.Errorf("Unexpected states: %+v", saved)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// State is where one component is in startup
+type State string
+
+const (
+	StatePending  State = "pending"  // Not tried yet
+	StateStarting State = "starting" // Being tried
+	StateUp       State = "up"
+	StateStub     State = "stub"   // Non-critical and did not start: the service runs without it
+	StateFailed   State = "failed" // Critical and did not start before the deadline
+)
+
+// Phase is where startup as a whole is
+type Phase string
+
+const (
+	PhaseStarting Phase = "starting"
+	PhaseReady    Phase = "ready"   // Every component is up
+	PhasePartial  Phase = "partial" // Critical components are up, others run in stub mode
+	PhaseFailed   Phase = "failed"  // A critical component did not start
+)
+
+// Component is one dependency brought up at startup
+type Component struct {
+	Name     string                          // Reported as, e.g. "data_adapter"
+	Critical bool                            // Retried until the deadline, failing startup if it never starts
+	Start    func(ctx context.Context) error // Nil once started; ctx carries the attempt's deadline
+}
+
+// ComponentStatus is how far one component got
+type ComponentStatus struct {
+	Name      string  `json:"name"`
+	Critical  bool    `json:"critical"`
+	State     State   `json:"state"`
+	Attempts  int     `json:"attempts"`
+	Error     string  `json:"error,omitempty"` // Last attempt's
+	ElapsedMs float64 `json:"elapsed_ms"`      // Spent starting it
+}
+
+// Report describes startup for readiness probes
+type Report struct {
+	Phase      Phase             `json:"phase"`
+	Serving    bool              `json:"serving"` // Startup finished and the servers accept traffic
+	StartedAt  time.Time         `json:"started_at"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// Coordinator starts components one at a time in the order added, each attempt
+// within the attempt timeout. A critical component is retried every retry
+// interval until it starts or the startup timeout runs out; a non-critical one is
+// tried once and left in stub mode if it fails, so an optional dependency never
+// holds startup up. Readiness stays off until Serve is called after a successful
+// Run.
+type Coordinator struct {
+	timeout        time.Duration
+	retryInterval  time.Duration
+	attemptTimeout time.Duration
+	logger         *logrus.Logger
+
+	mu         sync.RWMutex
+	components []Component
+	statuses   []ComponentStatus
+	phase      Phase
+	serving    bool
+	startedAt  time.Time
+	onServing  []func()
+}
+
+// NewCoordinator creates a coordinator giving every component timeout in total to
+// start and each attempt attemptTimeout, retrying critical ones every retryInterval
+func NewCoordinator(timeout, retryInterval, attemptTimeout time.Duration, logger *logrus.Logger) *Coordinator {
+	return &Coordinator{
+		timeout:        timeout,
+		retryInterval:  retryInterval,
+		attemptTimeout: attemptTimeout,
+		logger:         logger,
+		phase:          PhaseStarting,
+	}
+}
+
+// Add appends components to start after those already added
+func (c *Coordinator) Add(components ...Component) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, component := range components {
+		c.components = append(c.components, component)
+		c.statuses = append(c.statuses, ComponentStatus{Name: component.Name, Critical: component.Critical, State: StatePending})
+	}
+}
+
+// OnServing registers fn to run once Serve is called
+func (c *Coordinator) OnServing(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onServing = append(c.onServing, fn)
+}
+
+// Run starts every component in order. It returns an error naming the first
+// critical component that did not start before the startup timeout; components
+// after it are left pending.
+func (c *Coordinator) Run(ctx context.Context) error {
+	c.mu.Lock()
+	c.startedAt = time.Now()
+	components := c.components
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	stubbed := false
+	for i, component := range components {
+		if err := c.start(ctx, i, component); err != nil {
+			if component.Critical {
+				c.setPhase(PhaseFailed)
+				return fmt.Errorf("critical component %s did not start within %s: %w", component.Name, c.timeout, err)
+			}
+			stubbed = true
+		}
+	}
+	if stubbed {
+		c.setPhase(PhasePartial)
+	} else {
+		c.setPhase(PhaseReady)
+	}
+	return nil
+}
+
+// start tries one component until it starts, retrying a critical one until ctx ends
+func (c *Coordinator) start(ctx context.Context, i int, component Component) error {
+	began := time.Now()
+	entry := c.logger.WithField("component", component.Name)
+	for {
+		c.update(i, func(status *ComponentStatus) {
+			status.State = StateStarting
+			status.Attempts++
+		})
+		attemptCtx, cancel := context.WithTimeout(ctx, c.attemptTimeout)
+		err := component.Start(attemptCtx)
+		cancel()
+		if err == nil {
+			c.update(i, func(status *ComponentStatus) {
+				status.State, status.Error = StateUp, ""
+				status.ElapsedMs = float64(time.Since(began).Microseconds()) / 1000
+			})
+			entry.Info("Component started")
+			return nil
+		}
+
+		c.update(i, func(status *ComponentStatus) {
+			status.Error = err.Error()
+			status.ElapsedMs = float64(time.Since(began).Microseconds()) / 1000
+		})
+		if !component.Critical {
+			c.update(i, func(status *ComponentStatus) { status.State = StateStub })
+			entry.WithError(err).Warn("Component did not start, continuing in stub mode")
+			return err
+		}
+
+		entry.WithError(err).Warn("Critical component did not start, retrying")
+		retry := time.NewTimer(c.retryInterval)
+		select {
+		case <-ctx.Done():
+			retry.Stop()
+			c.update(i, func(status *ComponentStatus) { status.State = StateFailed })
+			return err
+		case <-retry.C:
+		}
+	}
+}
+
+// Serve marks startup finished once the servers accept traffic, letting readiness
+// pass and running the OnServing functions. It does nothing unless Run succeeded.
+func (c *Coordinator) Serve() {
+	c.mu.Lock()
+	if c.serving || (c.phase != PhaseReady && c.phase != PhasePartial) {
+		c.mu.Unlock()
+		return
+	}
+	c.serving = true
+	hooks := c.onServing
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// Ready is whether the servers should be reported ready
+func (c *Coordinator) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serving
+}
+
+// State returns one component's state, or "" when there is no such component
+func (c *Coordinator) State(name string) State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, status := range c.statuses {
+		if status.Name == name {
+			return status.State
+		}
+	}
+	return ""
+}
+
+// Report returns the phase and every component's status
+func (c *Coordinator) Report() Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	statuses := make([]ComponentStatus, len(c.statuses))
+	copy(statuses, c.statuses)
+	return Report{Phase: c.phase, Serving: c.serving, StartedAt: c.startedAt, Components: statuses}
+}
+
+func (c *Coordinator) update(i int, fn func(status *ComponentStatus)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.statuses[i])
+}
+
+func (c *Coordinator) setPhase(phase Phase) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phase = phase
+}
@@ -0,0 +1,165 @@
+//go:build unit
+
+package startup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCoordinator(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	failing := func(failures int) (func(context.Context) error, *int) {
+		attempts := 0
+		return func(context.Context) error {
+			attempts++
+			if attempts <= failures {
+				return errors.New("connection refused")
+			}
+			return nil
+		}, &attempts
+	}
+
+	t.Run("starts_components_in_order", func(t *testing.T) {
+		// Given: Three components recording when they start
+		c := NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		var order []string
+		for _, name := range []string{"data_adapter", "redis_discovery", "matching_engine"} {
+			c.Add(Component{Name: name, Critical: true, Start: func(context.Context) error {
+				order = append(order, name)
+				return nil
+			}})
+		}
+
+		// When: Startup runs
+		if err := c.Run(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+
+		// Then: They started in the order added, and startup is ready
+		if len(order) != 3 || order[0] != "data_adapter" || order[2] != "matching_engine" {
+			t.Errorf("Expected components in order, got %v", order)
+		}
+		if report := c.Report(); report.Phase != PhaseReady {
+			t.Errorf("Expected phase ready, got %s", report.Phase)
+		}
+	})
+
+	t.Run("retries_critical_component_until_it_starts", func(t *testing.T) {
+		// Given: A critical component failing twice
+		c := NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		start, attempts := failing(2)
+		c.Add(Component{Name: "matching_engine", Critical: true, Start: start})
+
+		// When: Startup runs
+		if err := c.Run(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+
+		// Then: It was tried until it started
+		if *attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", *attempts)
+		}
+		status := c.Report().Components[0]
+		if status.State != StateUp || status.Attempts != 3 || status.Error != "" {
+			t.Errorf("Expected up after 3 attempts without an error, got %+v", status)
+		}
+	})
+
+	t.Run("critical_component_fails_startup_at_timeout", func(t *testing.T) {
+		// Given: A critical component that never starts, followed by another
+		c := NewCoordinator(20*time.Millisecond, time.Millisecond, time.Second, logger)
+		start, _ := failing(1 << 30)
+		c.Add(
+			Component{Name: "data_adapter", Critical: true, Start: start},
+			Component{Name: "matching_engine", Critical: true, Start: func(context.Context) error { return nil }},
+		)
+
+		// When: Startup runs
+		err := c.Run(context.Background())
+
+		// Then: It fails naming the component, leaving the next one pending
+		if err == nil {
+			t.Fatal("Expected startup to fail")
+		}
+		report := c.Report()
+		if report.Phase != PhaseFailed {
+			t.Errorf("Expected phase failed, got %s", report.Phase)
+		}
+		if report.Components[0].State != StateFailed || report.Components[0].Error != "connection refused" {
+			t.Errorf("Expected data_adapter failed with its error, got %+v", report.Components[0])
+		}
+		if report.Components[1].State != StatePending {
+			t.Errorf("Expected matching_engine pending, got %s", report.Components[1].State)
+		}
+
+		// And: Serving is refused
+		c.Serve()
+		if c.Ready() {
+			t.Error("Expected a failed startup never to be ready")
+		}
+	})
+
+	t.Run("non_critical_component_is_tried_once_and_stubbed", func(t *testing.T) {
+		// Given: An optional component that fails, then a critical one
+		c := NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		start, attempts := failing(1 << 30)
+		c.Add(
+			Component{Name: "redis_discovery", Start: start},
+			Component{Name: "matching_engine", Critical: true, Start: func(context.Context) error { return nil }},
+		)
+
+		// When: Startup runs
+		if err := c.Run(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+
+		// Then: The optional component was tried once and runs in stub mode
+		if *attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", *attempts)
+		}
+		if state := c.State("redis_discovery"); state != StateStub {
+			t.Errorf("Expected redis_discovery in stub mode, got %s", state)
+		}
+		if state := c.State("matching_engine"); state != StateUp {
+			t.Errorf("Expected matching_engine up, got %s", state)
+		}
+
+		// And: Startup is partial
+		if report := c.Report(); report.Phase != PhasePartial {
+			t.Errorf("Expected phase partial, got %s", report.Phase)
+		}
+	})
+
+	t.Run("readiness_waits_for_serve", func(t *testing.T) {
+		// Given: A coordinator that started its components
+		c := NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		c.Add(Component{Name: "matching_engine", Critical: true, Start: func(context.Context) error { return nil }})
+		served := 0
+		c.OnServing(func() { served++ })
+		if err := c.Run(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+		if c.Ready() {
+			t.Fatal("Expected readiness to wait for Serve")
+		}
+
+		// When: The servers are marked serving, twice
+		c.Serve()
+		c.Serve()
+
+		// Then: Readiness passes and the serving functions ran once
+		if !c.Ready() || !c.Report().Serving {
+			t.Error("Expected the coordinator to be ready")
+		}
+		if served != 1 {
+			t.Errorf("Expected the serving functions to run once, got %d", served)
+		}
+	})
+}
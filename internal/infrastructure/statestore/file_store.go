@@ -0,0 +1,68 @@
+package statestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// FileStore keeps the latest matching engine state in a JSON file
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save writes the state to a temporary file and renames it into place, so a crash
+// mid-write leaves the previous state behind rather than a truncated one
+func (s *FileStore) Save(state matching.EngineState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode engine state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create engine state snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write engine state snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write engine state snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write engine state snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace engine state snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the last state; a missing file yields the zero state
+func (s *FileStore) Load() (matching.EngineState, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return matching.EngineState{}, nil
+	}
+	if err != nil {
+		return matching.EngineState{}, fmt.Errorf("failed to read engine state snapshot: %w", err)
+	}
+
+	var state matching.EngineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return matching.EngineState{}, fmt.Errorf("failed to decode engine state snapshot: %w", err)
+	}
+	return state, nil
+}
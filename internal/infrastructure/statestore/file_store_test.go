@@ -0,0 +1,71 @@
+//go:build unit
+
+package statestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("round_trips_state", func(t *testing.T) {
+		// Given: A state with one resting bid
+		store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		takenAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		bid := models.Order{ID: matching.OrderID("BTC-USD", 1), AccountID: "acct-1", Symbol: "BTC-USD", Side: models.SideBuy, Quantity: 1, Price: 99}
+		state := matching.EngineState{
+			Sequence: 7,
+			TakenAt:  takenAt,
+			Books: []matching.BookState{{
+				Symbol:      "BTC-USD",
+				Phase:       matching.PhaseContinuous,
+				NextOrderID: 1,
+				Orders:      []models.Order{bid},
+				Bids:        []string{bid.ID},
+			}},
+		}
+
+		// When: It is saved and loaded back
+		if err := store.Save(state); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		loaded, err := store.Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The sequence, time and book are preserved
+		if loaded.Sequence != 7 || !loaded.TakenAt.Equal(takenAt) || len(loaded.Books) != 1 {
+			t.Fatalf("Unexpected state after reload: %+v", loaded)
+		}
+		if book := loaded.Books[0]; len(book.Orders) != 1 || book.Orders[0].Price != 99 || book.Bids[0] != bid.ID {
+			t.Errorf("Unexpected book after reload: %+v", book)
+		}
+	})
+
+	t.Run("missing_file_is_the_zero_state", func(t *testing.T) {
+		store := NewFileStore(filepath.Join(t.TempDir(), "missing.json"))
+
+		state, err := store.Load()
+		if err != nil || !state.TakenAt.IsZero() || len(state.Books) != 0 {
+			t.Errorf("Expected the zero state, got %+v, err %v", state, err)
+		}
+	})
+
+	t.Run("leaves_no_temporary_files", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileStore(filepath.Join(dir, "state.json"))
+		store.Save(matching.EngineState{})
+		store.Save(matching.EngineState{})
+
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("Expected only the state file, got %d entries", len(entries))
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package statestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// table holds the latest state of each venue instance; the books stay JSON since
+// they are only ever read back whole
+const table = "exchange_engine_states"
+
+// Schema creates the table, as migration 000006_engine_states does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance TEXT        NOT NULL PRIMARY KEY,
+	sequence BIGINT      NOT NULL,
+	taken_at TIMESTAMPTZ NOT NULL,
+	state    JSONB       NOT NULL
+)`
+
+// PostgresStore keeps one venue instance's latest engine state in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the state table when it does not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create engine state table: %w", err)
+	}
+	return nil
+}
+
+// Save replaces the instance's state
+func (s *PostgresStore) Save(state matching.EngineState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode engine state: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err = s.client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, sequence, taken_at, state) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (instance) DO UPDATE SET sequence = EXCLUDED.sequence, taken_at = EXCLUDED.taken_at, state = EXCLUDED.state`,
+		s.instance, int64(state.Sequence), state.TakenAt.UTC(), data)
+	if err != nil {
+		return fmt.Errorf("failed to save engine state to postgres: %w", err)
+	}
+	return nil
+}
+
+// Load reads the instance's state; an instance without one yields the zero state
+func (s *PostgresStore) Load() (matching.EngineState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	var data []byte
+	err := s.client.QueryRowContext(ctx, `SELECT state FROM `+table+` WHERE instance = $1`, s.instance).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return matching.EngineState{}, nil
+	}
+	if err != nil {
+		return matching.EngineState{}, fmt.Errorf("failed to load engine state from postgres: %w", err)
+	}
+
+	var state matching.EngineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return matching.EngineState{}, fmt.Errorf("invalid engine state of %s: %w", s.instance, err)
+	}
+	return state, nil
+}
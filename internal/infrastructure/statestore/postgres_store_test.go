@@ -0,0 +1,59 @@
+//go:build integration
+
+package statestore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("keeps_the_latest_state", func(t *testing.T) {
+		// Given: An instance without a state
+		if state, err := store.Load(); err != nil || !state.TakenAt.IsZero() {
+			t.Fatalf("Expected the zero state, got %+v, %v", state, err)
+		}
+
+		// When: Two states are saved
+		takenAt := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		for i := 1; i <= 2; i++ {
+			state := matching.EngineState{
+				Sequence: uint64(10 * i),
+				TakenAt:  takenAt.Add(time.Duration(i) * time.Minute),
+				Books:    []matching.BookState{{Symbol: "BTC-USD", Phase: matching.PhaseContinuous, NextOrderID: uint64(i)}},
+			}
+			if err := store.Save(state); err != nil {
+				t.Fatalf("Expected the state to save, got %v", err)
+			}
+		}
+
+		// Then: The later one is loaded
+		state, err := store.Load()
+		if err != nil || state.Sequence != 20 || len(state.Books) != 1 || state.Books[0].NextOrderID != 2 {
+			t.Errorf("Expected the second state, got %+v, %v", state, err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package statsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// FileStore keeps the latest market data statistics snapshot in a JSON file
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save writes the snapshot to a temporary file and renames it into place,
+// so a crash mid-write never leaves a truncated snapshot behind
+func (s *FileStore) Save(state marketdata.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode statistics: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create statistics snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write statistics snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write statistics snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace statistics snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the last snapshot; a missing file yields empty statistics
+func (s *FileStore) Load() (marketdata.State, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return marketdata.State{Symbols: map[string]marketdata.SymbolState{}}, nil
+	}
+	if err != nil {
+		return marketdata.State{}, fmt.Errorf("failed to read statistics snapshot: %w", err)
+	}
+
+	var state marketdata.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return marketdata.State{}, fmt.Errorf("failed to decode statistics snapshot: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,71 @@
+//go:build unit
+
+package statsstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("round_trips_snapshot", func(t *testing.T) {
+		// Given: A snapshot with one candle
+		store := NewFileStore(filepath.Join(t.TempDir(), "stats.json"))
+		openTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		state := marketdata.State{
+			SavedAt: openTime,
+			Symbols: map[string]marketdata.SymbolState{
+				"BTC-USD": {
+					LastPrice:   101,
+					LastTradeAt: openTime,
+					Candles: map[marketdata.Interval][]marketdata.Candle{
+						marketdata.Interval1m: {{OpenTime: openTime, Open: 100, High: 102, Low: 99, Close: 101, Volume: 3, TradeCount: 2}},
+					},
+				},
+			},
+		}
+
+		// When: It is saved and loaded back
+		if err := store.Save(state); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		loaded, err := store.Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Candles and last price are preserved
+		candles := loaded.Symbols["BTC-USD"].Candles[marketdata.Interval1m]
+		if len(candles) != 1 || candles[0].High != 102 || !candles[0].OpenTime.Equal(openTime) {
+			t.Errorf("Unexpected candles after reload: %+v", candles)
+		}
+		if loaded.Symbols["BTC-USD"].LastPrice != 101 {
+			t.Errorf("Expected last price 101, got %v", loaded.Symbols["BTC-USD"].LastPrice)
+		}
+	})
+
+	t.Run("missing_file_is_empty_state", func(t *testing.T) {
+		store := NewFileStore(filepath.Join(t.TempDir(), "missing.json"))
+
+		state, err := store.Load()
+		if err != nil || len(state.Symbols) != 0 {
+			t.Errorf("Expected empty state, got %+v, err %v", state, err)
+		}
+	})
+
+	t.Run("leaves_no_temporary_files", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileStore(filepath.Join(dir, "stats.json"))
+		store.Save(marketdata.State{})
+		store.Save(marketdata.State{})
+
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("Expected only the snapshot file, got %d entries", len(entries))
+		}
+	})
+}
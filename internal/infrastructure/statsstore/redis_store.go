@@ -0,0 +1,64 @@
+package statsstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// RedisKeyValueClient is the subset of the Redis client used by RedisStore
+type RedisKeyValueClient interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+}
+
+// RedisStore keeps the latest market data statistics snapshot under one Redis key
+type RedisStore struct {
+	client  RedisKeyValueClient
+	key     string
+	timeout time.Duration
+}
+
+func NewRedisStore(client RedisKeyValueClient, key string, timeout time.Duration) *RedisStore {
+	return &RedisStore{client: client, key: key, timeout: timeout}
+}
+
+func (s *RedisStore) Save(state marketdata.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode statistics: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.client.Set(ctx, s.key, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save statistics to redis: %w", err)
+	}
+	return nil
+}
+
+// Load reads the last snapshot; a missing key yields empty statistics
+func (s *RedisStore) Load() (marketdata.State, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	data, err := s.client.Get(ctx, s.key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return marketdata.State{Symbols: map[string]marketdata.SymbolState{}}, nil
+	}
+	if err != nil {
+		return marketdata.State{}, fmt.Errorf("failed to load statistics from redis: %w", err)
+	}
+
+	var state marketdata.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return marketdata.State{}, fmt.Errorf("failed to decode statistics snapshot: %w", err)
+	}
+	return state, nil
+}
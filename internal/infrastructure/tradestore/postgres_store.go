@@ -0,0 +1,200 @@
+package tradestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+)
+
+// SQLClient is the subset of *sql.DB used by PostgresStore
+type SQLClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// execer runs statements on a connection or inside a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// OutboxWriter adds outbox messages inside a transaction the trade store opened
+type OutboxWriter interface {
+	WriteTx(ctx context.Context, tx *sql.Tx, messages []outbox.Message) error
+}
+
+// table holds one row per trade; trade IDs are unique per venue instance
+const table = "exchange_trades"
+
+// Schema creates the table, as migration 000002_trades does
+const Schema = `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	instance        TEXT             NOT NULL,
+	trade_id        TEXT             NOT NULL,
+	symbol          TEXT             NOT NULL,
+	price           DOUBLE PRECISION NOT NULL,
+	quantity        DOUBLE PRECISION NOT NULL,
+	buy_order_id    TEXT             NOT NULL,
+	sell_order_id   TEXT             NOT NULL,
+	buy_account_id  TEXT             NOT NULL,
+	sell_account_id TEXT             NOT NULL,
+	taker_side      TEXT             NOT NULL,
+	auction         BOOLEAN          NOT NULL,
+	executed_at     TIMESTAMPTZ      NOT NULL,
+	PRIMARY KEY (instance, trade_id)
+);
+CREATE INDEX IF NOT EXISTS ` + table + `_symbol_time ON ` + table + ` (instance, symbol, executed_at, trade_id);
+CREATE INDEX IF NOT EXISTS ` + table + `_time ON ` + table + ` (instance, executed_at, trade_id)`
+
+const tradeColumns = `trade_id, symbol, price, quantity, buy_order_id, sell_order_id,
+	buy_account_id, sell_account_id, taker_side, auction, executed_at`
+
+// PostgresStore keeps one venue instance's trade tape in Postgres
+type PostgresStore struct {
+	client   SQLClient
+	instance string
+	timeout  time.Duration
+	outbox   OutboxWriter // nil rejects AppendWithMessages
+}
+
+func NewPostgresStore(client SQLClient, instance string, timeout time.Duration) *PostgresStore {
+	return &PostgresStore{client: client, instance: instance, timeout: timeout}
+}
+
+// EnsureSchema creates the trade table and its indexes when they do not exist yet
+func (s *PostgresStore) EnsureSchema() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.client.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("failed to create trade table: %w", err)
+	}
+	return nil
+}
+
+// SetOutbox writes AppendWithMessages' messages to outbox; it must share the
+// trade store's database
+func (s *PostgresStore) SetOutbox(outbox OutboxWriter) {
+	s.outbox = outbox
+}
+
+// Append writes trades in one statement; trades already written are skipped, so
+// a failed batch can be retried whole
+func (s *PostgresStore) Append(trades []models.Trade) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.insert(ctx, s.client, trades)
+}
+
+// AppendWithMessages writes trades and the outbox messages announcing them in one
+// transaction; like Append, a failed batch can be retried whole
+func (s *PostgresStore) AppendWithMessages(trades []models.Trade, messages []outbox.Message) error {
+	if s.outbox == nil {
+		return fmt.Errorf("trade store has no outbox")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	tx, err := s.client.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin trade transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.insert(ctx, tx, trades); err != nil {
+		return err
+	}
+	if err := s.outbox.WriteTx(ctx, tx, messages); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %d trades and %d outbox messages: %w", len(trades), len(messages), err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) insert(ctx context.Context, client execer, trades []models.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+	const columns = 12
+	values := make([]string, 0, len(trades))
+	args := make([]interface{}, 0, len(trades)*columns)
+	for i, trade := range trades {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args, s.instance, trade.ID, trade.Symbol, trade.Price, trade.Quantity, trade.BuyOrderID, trade.SellOrderID,
+			trade.BuyAccountID, trade.SellAccountID, string(trade.TakerSide), trade.Auction, trade.ExecutedAt.UTC())
+	}
+	_, err := client.ExecContext(ctx,
+		`INSERT INTO `+table+` (instance, `+tradeColumns+`) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (instance, trade_id) DO NOTHING`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save %d trades to postgres: %w", len(trades), err)
+	}
+	return nil
+}
+
+// Query returns one page of the instance's trades in tape order, or newest first
+func (s *PostgresStore) Query(query tradetape.Query) (tradetape.Page, error) {
+	conditions := []string{"instance = $1"}
+	args := []interface{}{s.instance}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if query.Symbol != "" {
+		where("symbol = $%d", query.Symbol)
+	}
+	if query.AccountID != "" {
+		where("(buy_account_id = $%[1]d OR sell_account_id = $%[1]d)", query.AccountID)
+	}
+	if !query.From.IsZero() {
+		where("executed_at >= $%d", query.From.UTC())
+	}
+	if !query.To.IsZero() {
+		where("executed_at < $%d", query.To.UTC())
+	}
+	comparison, order := ">", "executed_at, trade_id"
+	if query.Descending {
+		comparison, order = "<", "executed_at DESC, trade_id DESC"
+	}
+	if !query.After.IsZero() {
+		args = append(args, query.After.ExecutedAt.UTC(), query.After.TradeID)
+		conditions = append(conditions, fmt.Sprintf("(executed_at, trade_id) %s ($%d, $%d)", comparison, len(args)-1, len(args)))
+	}
+	args = append(args, query.Limit+1)
+	statement := `SELECT ` + tradeColumns + ` FROM ` + table + ` WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d`, order, len(args))
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	rows, err := s.client.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return tradetape.Page{}, fmt.Errorf("failed to query trades from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	trades := make([]models.Trade, 0, query.Limit+1)
+	for rows.Next() {
+		var trade models.Trade
+		var takerSide string
+		if err := rows.Scan(&trade.ID, &trade.Symbol, &trade.Price, &trade.Quantity, &trade.BuyOrderID, &trade.SellOrderID,
+			&trade.BuyAccountID, &trade.SellAccountID, &takerSide, &trade.Auction, &trade.ExecutedAt); err != nil {
+			return tradetape.Page{}, fmt.Errorf("failed to read trade: %w", err)
+		}
+		trade.TakerSide = models.Side(takerSide)
+		trade.ExecutedAt = trade.ExecutedAt.UTC()
+		trades = append(trades, trade)
+	}
+	if err := rows.Err(); err != nil {
+		return tradetape.Page{}, fmt.Errorf("failed to read trades: %w", err)
+	}
+	return tradetape.NewPage(trades, query.Limit), nil
+}
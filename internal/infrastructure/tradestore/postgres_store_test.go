@@ -0,0 +1,92 @@
+//go:build integration
+
+package tradestore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+)
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Expected to open postgres, got %v", err)
+	}
+	defer db.Close()
+	instance := fmt.Sprintf("test-exchange-%d", time.Now().UnixNano())
+	store := NewPostgresStore(db, instance, 5*time.Second)
+	if err := store.EnsureSchema(); err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	defer db.Exec(`DELETE FROM `+table+` WHERE instance = $1`, instance)
+
+	t.Run("pages_by_account_and_time_with_cursors", func(t *testing.T) {
+		// Given: Four trades, two sharing an execution time, one appended twice
+		start := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		trades := []models.Trade{
+			{ID: "t-1", Symbol: "BTC-USD", Price: 60000, Quantity: 1, BuyOrderID: "o-1", SellOrderID: "o-2", BuyAccountID: "a", SellAccountID: "b", TakerSide: models.SideBuy, ExecutedAt: start},
+			{ID: "t-2", Symbol: "BTC-USD", Price: 60001, Quantity: 2, BuyOrderID: "o-3", SellOrderID: "o-4", BuyAccountID: "c", SellAccountID: "a", TakerSide: models.SideSell, ExecutedAt: start},
+			{ID: "t-3", Symbol: "ETH-USD", Price: 3000, Quantity: 1, BuyOrderID: "o-5", SellOrderID: "o-6", BuyAccountID: "a", SellAccountID: "c", TakerSide: models.SideBuy, ExecutedAt: start.Add(time.Minute)},
+			{ID: "t-4", Symbol: "BTC-USD", Price: 60002, Quantity: 1, BuyOrderID: "o-7", SellOrderID: "o-8", BuyAccountID: "b", SellAccountID: "c", TakerSide: models.SideBuy, ExecutedAt: start.Add(2 * time.Minute)},
+		}
+		if err := store.Append(trades[:2]); err != nil {
+			t.Fatalf("Expected to append, got %v", err)
+		}
+		if err := store.Append(trades[1:]); err != nil {
+			t.Fatalf("Expected a retried batch to append, got %v", err)
+		}
+
+		// When: Account a's trades are read one at a time
+		query := tradetape.Query{AccountID: "a", Limit: 1}
+		var ids []string
+		for {
+			page, err := store.Query(query)
+			if err != nil {
+				t.Fatalf("Expected a page, got %v", err)
+			}
+			for _, trade := range page.Trades {
+				ids = append(ids, trade.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			if query.After, err = tradetape.ParseCursor(page.NextCursor); err != nil {
+				t.Fatalf("Expected the cursor to parse, got %v", err)
+			}
+		}
+
+		// Then: Each of a's trades is returned once in tape order
+		if len(ids) != 3 || ids[0] != "t-1" || ids[1] != "t-2" || ids[2] != "t-3" {
+			t.Errorf("Expected t-1, t-2, t-3, got %v", ids)
+		}
+
+		// And: Symbol and time range filters apply, with fields round-tripped
+		page, err := store.Query(tradetape.Query{Symbol: "BTC-USD", From: start.Add(time.Second), To: start.Add(time.Hour), Limit: 10})
+		if err != nil || len(page.Trades) != 1 || page.Trades[0] != trades[3] {
+			t.Errorf("Expected t-4 alone, got %+v, %v", page, err)
+		}
+
+		// And: Newest first, the cursor continues backwards through the tape
+		newest, err := store.Query(tradetape.Query{AccountID: "a", Descending: true, Limit: 2})
+		if err != nil || len(newest.Trades) != 2 || newest.Trades[0].ID != "t-3" || newest.Trades[1].ID != "t-2" {
+			t.Fatalf("Expected t-3 then t-2, got %+v, %v", newest, err)
+		}
+		cursor, _ := tradetape.ParseCursor(newest.NextCursor)
+		oldest, err := store.Query(tradetape.Query{AccountID: "a", Descending: true, After: cursor, Limit: 2})
+		if err != nil || len(oldest.Trades) != 1 || oldest.Trades[0].ID != "t-1" || oldest.NextCursor != "" {
+			t.Errorf("Expected t-1 alone on the last page, got %+v, %v", oldest, err)
+		}
+	})
+}
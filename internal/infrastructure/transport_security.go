@@ -0,0 +1,255 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// TLSMode is how gRPC traffic is secured
+type TLSMode string
+
+const (
+	TLSOff    TLSMode = "off"  // Plaintext
+	TLSServer TLSMode = "tls"  // Servers present certificates and clients verify them
+	TLSMutual TLSMode = "mtls" // Clients present certificates too, and servers verify them
+)
+
+// ParseTLSMode reads a GRPC_TLS_MODE value; empty is off
+func ParseTLSMode(value string) (TLSMode, error) {
+	switch mode := TLSMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", TLSOff:
+		return TLSOff, nil
+	case TLSServer, TLSMutual:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown TLS mode %q: expected off, tls or mtls", value)
+}
+
+// ResolveTLSMaterial fetches from the configuration service whichever PEM the
+// certificate, key and, under mTLS, CA bundle is set neither inline nor as a file,
+// under GRPC_TLS_CONFIG_KEY followed by ".cert", ".key" and ".ca", and sets it
+// inline. Nothing is fetched while TLS is off or no key is configured.
+func ResolveTLSMaterial(ctx context.Context, cfg *config.Config, source ports.SettingSource) error {
+	mode, err := ParseTLSMode(cfg.TLSMode)
+	if err != nil || mode == TLSOff || cfg.TLSConfigKey == "" {
+		return err
+	}
+	fetch := func(inline *string, file, suffix string) error {
+		if *inline != "" || file != "" {
+			return nil
+		}
+		value, err := source.Setting(ctx, cfg.TLSConfigKey+suffix)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s%s: %w", cfg.TLSConfigKey, suffix, err)
+		}
+		*inline = string(value)
+		return nil
+	}
+	if err := fetch(&cfg.TLSCert, cfg.TLSCertFile, ".cert"); err != nil {
+		return err
+	}
+	if err := fetch(&cfg.TLSKey, cfg.TLSKeyFile, ".key"); err != nil {
+		return err
+	}
+	if mode == TLSMutual {
+		return fetch(&cfg.TLSCA, cfg.TLSCAFile, ".ca")
+	}
+	return nil
+}
+
+// ServerCredentials secures the gRPC server under GRPC_TLS_MODE. Under mTLS clients
+// must present a certificate issued by the CA bundle and, if GRPC_TLS_PEER_IDS is
+// set, carrying one of its SPIFFE IDs.
+func ServerCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	mode, err := ParseTLSMode(cfg.TLSMode)
+	if err != nil {
+		return nil, err
+	}
+	if mode == TLSOff {
+		return insecure.NewCredentials(), nil
+	}
+	certificate, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	if mode == TLSMutual {
+		roots, err := loadCAPool(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if roots == nil {
+			return nil, errors.New("mTLS needs a CA bundle to verify clients against: set GRPC_TLS_CA or GRPC_TLS_CA_FILE")
+		}
+		peerIDs, err := parsePeerIDs(cfg.TLSPeerIDs)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = roots
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if len(peerIDs) > 0 {
+			tlsConfig.VerifyPeerCertificate = func(_ [][]byte, chains [][]*x509.Certificate) error {
+				return verifyPeerID(chains[0][0], peerIDs)
+			}
+		}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// ClientCredentials secures calls to other services under GRPC_TLS_MODE. Servers are
+// verified against the CA bundle, or the system roots without one, and by hostname;
+// if GRPC_TLS_PEER_IDS is set they are verified by SPIFFE ID instead, as those name
+// workloads rather than hosts. Under mTLS the client presents its certificate.
+func ClientCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	mode, err := ParseTLSMode(cfg.TLSMode)
+	if err != nil {
+		return nil, err
+	}
+	if mode == TLSOff {
+		return insecure.NewCredentials(), nil
+	}
+	roots, err := loadCAPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	peerIDs, err := parsePeerIDs(cfg.TLSPeerIDs)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if mode == TLSMutual {
+		certificate, err := loadCertificate(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if len(peerIDs) > 0 {
+		// The chain is verified here, with the SPIFFE ID standing in for the hostname
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			leaf, err := verifyChain(rawCerts, roots)
+			if err != nil {
+				return err
+			}
+			return verifyPeerID(leaf, peerIDs)
+		}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// loadCertificate reads the certificate and key, inline PEM first, then files
+func loadCertificate(cfg *config.Config) (tls.Certificate, error) {
+	certPEM, err := readPEM(cfg.TLSCert, cfg.TLSCertFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := readPEM(cfg.TLSKey, cfg.TLSKeyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if certPEM == nil || keyPEM == nil {
+		return tls.Certificate{}, errors.New("TLS needs a certificate and key: set GRPC_TLS_CERT and GRPC_TLS_KEY, their _FILE forms or GRPC_TLS_CONFIG_KEY")
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid TLS certificate or key: %w", err)
+	}
+	return certificate, nil
+}
+
+// loadCAPool reads the CA bundle, inline PEM first, then the file; nil if neither is set
+func loadCAPool(cfg *config.Config) (*x509.CertPool, error) {
+	caPEM, err := readPEM(cfg.TLSCA, cfg.TLSCAFile)
+	if err != nil || caPEM == nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("invalid TLS CA bundle: no PEM certificates found")
+	}
+	return pool, nil
+}
+
+func readPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return data, nil
+}
+
+// verifyChain verifies the peer's chain against roots, or the system roots if nil,
+// returning its leaf
+func verifyChain(rawCerts [][]byte, roots *x509.CertPool) (*x509.Certificate, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.New("peer presented no certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+		return nil, fmt.Errorf("peer certificate not trusted: %w", err)
+	}
+	return certs[0], nil
+}
+
+// parsePeerIDs reads GRPC_TLS_PEER_IDS: SPIFFE IDs, or trust domains such as
+// "spiffe://trading.local" admitting any workload in them
+func parsePeerIDs(spec string) ([]*url.URL, error) {
+	var ids []*url.URL
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, err := url.Parse(entry)
+		if err != nil || id.Scheme != "spiffe" || id.Host == "" {
+			return nil, fmt.Errorf("invalid peer ID %q: expected spiffe://trust-domain[/path]", entry)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// verifyPeerID checks that cert carries a URI SAN the allowed IDs admit
+func verifyPeerID(cert *x509.Certificate, allowed []*url.URL) error {
+	for _, uri := range cert.URIs {
+		if uri.Scheme != "spiffe" {
+			continue
+		}
+		for _, id := range allowed {
+			if uri.Host == id.Host && (strings.TrimSuffix(id.Path, "/") == "" || uri.Path == id.Path) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("peer identity %v not among GRPC_TLS_PEER_IDS", cert.URIs)
+}
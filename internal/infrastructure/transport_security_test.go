@@ -0,0 +1,209 @@
+//go:build unit
+
+package infrastructure
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+)
+
+// testCA issues certificates for the tests' workloads
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "trading test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Expected a CA certificate, got %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// issue returns the PEM certificate and key of a workload with the given SPIFFE ID,
+// valid for 127.0.0.1
+func (ca *testCA) issue(t *testing.T, spiffeID string) (string, string) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	id, _ := url.Parse(spiffeID)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		URIs:         []*url.URL{id},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Expected a certificate, got %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// workloadConfig returns TLS settings for a workload holding a certificate for spiffeID
+func (ca *testCA) workloadConfig(t *testing.T, mode, spiffeID, peerIDs string) *config.Config {
+	cert, key := ca.issue(t, spiffeID)
+	return &config.Config{TLSMode: mode, TLSCert: cert, TLSKey: key, TLSCA: ca.pem, TLSPeerIDs: peerIDs}
+}
+
+// startSecuredServer serves gRPC health checks under cfg's server credentials
+func startSecuredServer(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+	creds, err := ServerCredentials(cfg)
+	if err != nil {
+		t.Fatalf("Expected server credentials, got %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen, got %v", err)
+	}
+	server := grpc.NewServer(grpc.Creds(creds))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// checkHealth makes one health check to endpoint under cfg's client credentials
+func checkHealth(t *testing.T, cfg *config.Config, endpoint string) error {
+	t.Helper()
+	creds, err := ClientCredentials(cfg)
+	if err != nil {
+		t.Fatalf("Expected client credentials, got %v", err)
+	}
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatalf("Expected a client, got %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	return err
+}
+
+type staticSettings map[string]string
+
+func (s staticSettings) Setting(_ context.Context, key string) (json.RawMessage, error) {
+	value, exists := s[key]
+	if !exists {
+		return nil, errors.New("configuration key not found: " + key)
+	}
+	return json.RawMessage(value), nil
+}
+
+func TestTransportSecurity(t *testing.T) {
+	ca := newTestCA(t)
+	const exchange = "spiffe://trading.local/exchange-simulator"
+	const custodian = "spiffe://trading.local/custodian-simulator"
+
+	t.Run("mtls_connects_peers_with_allowed_ids", func(t *testing.T) {
+		// Given: A custodian admitting the trust domain, and an exchange admitting only the custodian
+		endpoint := startSecuredServer(t, ca.workloadConfig(t, "mtls", custodian, "spiffe://trading.local"))
+
+		// When: The exchange calls the custodian
+		err := checkHealth(t, ca.workloadConfig(t, "mtls", exchange, custodian), endpoint)
+
+		// Then: The call goes through
+		if err != nil {
+			t.Errorf("Expected the call to succeed, got %v", err)
+		}
+	})
+
+	t.Run("client_refuses_a_server_with_another_id", func(t *testing.T) {
+		// Given: A server whose certificate names another workload
+		endpoint := startSecuredServer(t, ca.workloadConfig(t, "mtls", "spiffe://trading.local/impostor", ""))
+
+		// When: The exchange, expecting the custodian, calls it
+		err := checkHealth(t, ca.workloadConfig(t, "mtls", exchange, custodian), endpoint)
+
+		// Then: The handshake fails
+		if err == nil {
+			t.Error("Expected the call to fail")
+		}
+	})
+
+	t.Run("mtls_server_refuses_clients_without_certificates", func(t *testing.T) {
+		// Given: An mTLS server
+		endpoint := startSecuredServer(t, ca.workloadConfig(t, "mtls", custodian, ""))
+
+		// When: A client using server-only TLS calls it
+		err := checkHealth(t, &config.Config{TLSMode: "tls", TLSCA: ca.pem}, endpoint)
+
+		// Then: The call fails
+		if err == nil {
+			t.Error("Expected the call to fail")
+		}
+	})
+
+	t.Run("resolves_missing_pem_from_the_configuration_service", func(t *testing.T) {
+		// Given: Only a configuration key, under which the service holds the PEM
+		cert, key := ca.issue(t, exchange)
+		source := staticSettings{"tls.exchange.cert": cert, "tls.exchange.key": key, "tls.exchange.ca": ca.pem}
+		cfg := &config.Config{TLSMode: "mtls", TLSConfigKey: "tls.exchange", TLSKeyFile: "/etc/tls/key.pem"}
+
+		// When: The material is resolved
+		err := ResolveTLSMaterial(context.Background(), cfg, source)
+
+		// Then: What is not set locally is set inline, and the key file is left alone
+		if err != nil || cfg.TLSCert != cert || cfg.TLSCA != ca.pem || cfg.TLSKey != "" {
+			t.Errorf("Expected the cert and CA resolved, got %v and %+v", err, cfg)
+		}
+	})
+
+	t.Run("rejects_invalid_settings", func(t *testing.T) {
+		// Given: An unknown mode, mTLS without a CA, and a peer ID that is not SPIFFE
+		cert, key := ca.issue(t, exchange)
+		configs := map[string]*config.Config{
+			"unknown mode":   {TLSMode: "strict"},
+			"missing CA":     {TLSMode: "mtls", TLSCert: cert, TLSKey: key},
+			"non-SPIFFE IDs": {TLSMode: "tls", TLSPeerIDs: "https://trading.local"},
+		}
+		for name, cfg := range configs {
+			// When: Credentials are made
+			_, serverErr := ServerCredentials(cfg)
+			_, clientErr := ClientCredentials(cfg)
+
+			// Then: They are refused rather than falling back to plaintext
+			if serverErr == nil && clientErr == nil {
+				t.Errorf("Expected %s to be refused", name)
+			}
+		}
+	})
+}
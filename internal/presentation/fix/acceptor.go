@@ -0,0 +1,169 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// Acceptor is the venue's side of FIX 4.4 sessions: counterparties connect, log on
+// with a configured SenderCompID and trade as that counterparty's account
+type Acceptor struct {
+	compID          string
+	counterparties  map[string]*counterpartyState // By SenderCompID
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+
+	listener  net.Listener
+	pending   map[net.Conn]struct{} // Connected, not yet logged on
+	closing   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+}
+
+func NewAcceptor(compID string, counterparties []Counterparty, exchangeService *services.ExchangeService, logger *logrus.Logger) *Acceptor {
+	states := make(map[string]*counterpartyState, len(counterparties))
+	for _, counterparty := range counterparties {
+		states[counterparty.CompID] = newCounterpartyState(counterparty)
+	}
+	return &Acceptor{
+		compID:          compID,
+		counterparties:  states,
+		exchangeService: exchangeService,
+		logger:          logger,
+		pending:         make(map[net.Conn]struct{}),
+		closing:         make(chan struct{}),
+	}
+}
+
+// Serve accepts connections until Shutdown; it returns nil once shut down
+func (a *Acceptor) Serve(listener net.Listener) error {
+	a.mu.Lock()
+	a.listener = listener
+	a.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-a.closing:
+				return nil
+			default:
+				return err
+			}
+		}
+		a.mu.Lock()
+		a.pending[conn] = struct{}{}
+		a.mu.Unlock()
+
+		a.wg.Add(1)
+		go a.handle(conn)
+	}
+}
+
+// Shutdown logs every session out, stops accepting and waits for the sessions to end
+func (a *Acceptor) Shutdown(ctx context.Context) error {
+	a.closeOnce.Do(func() {
+		close(a.closing)
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.listener != nil {
+			a.listener.Close()
+		}
+		for conn := range a.pending {
+			conn.Close()
+		}
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handle waits for the Logon and runs the session it opens
+func (a *Acceptor) handle(conn net.Conn) {
+	defer a.wg.Done()
+	logger := a.logger.WithFields(logrus.Fields{"remote_addr": conn.RemoteAddr().String(), logfields.Component: logfields.ComponentFIX})
+
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(logonTimeout))
+	logon, err := ReadMessage(r)
+	conn.SetReadDeadline(time.Time{})
+
+	a.mu.Lock()
+	delete(a.pending, conn)
+	a.mu.Unlock()
+
+	var sess *session
+	if err == nil {
+		sess, err = a.logon(conn, logon)
+	}
+	if err != nil {
+		// Refused logons are dropped without a Logout, which would need a sequence
+		// number from a session that was never established
+		logger.WithError(err).Warn("FIX logon refused")
+		conn.Close()
+		return
+	}
+	sess.run(logon, r)
+}
+
+// logon validates the first message and claims its counterparty's session
+func (a *Acceptor) logon(conn net.Conn, msg *Message) (*session, error) {
+	if msg.MsgType() != msgLogon {
+		return nil, fmt.Errorf("first message must be Logon, got MsgType %q", msg.MsgType())
+	}
+	if target, _ := msg.Get(tagTargetCompID); target != a.compID {
+		return nil, fmt.Errorf("TargetCompID %q is not this venue", target)
+	}
+	if _, err := msg.Int(tagMsgSeqNum); err != nil {
+		return nil, errors.New("logon without a valid MsgSeqNum")
+	}
+	heartbeat, err := msg.Int(tagHeartBtInt)
+	if err != nil || heartbeat <= 0 {
+		return nil, errors.New("HeartBtInt must be a positive number of seconds")
+	}
+	sender, _ := msg.Get(tagSenderCompID)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	select {
+	case <-a.closing:
+		return nil, errors.New("venue shutting down")
+	default:
+	}
+	party, known := a.counterparties[sender]
+	if !known {
+		return nil, fmt.Errorf("unknown SenderCompID %q", sender)
+	}
+	if party.active {
+		return nil, fmt.Errorf("%s is already logged on", sender)
+	}
+	party.active = true
+	return newSession(a, conn, party, time.Duration(heartbeat)*time.Second), nil
+}
+
+// release frees a counterparty once its session ends
+func (a *Acceptor) release(s *session) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s.party.active = false
+}
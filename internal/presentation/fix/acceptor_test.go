@@ -0,0 +1,399 @@
+//go:build unit
+
+package fix
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newTestAcceptor(t *testing.T, spec string) (*Acceptor, string) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	counterparties, err := ParseCounterparties(spec)
+	if err != nil {
+		t.Fatalf("Expected valid counterparties, got %v", err)
+	}
+	exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+	acceptor := NewAcceptor("EXSIM", counterparties, exchangeService, logger)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected a listener, got %v", err)
+	}
+	go acceptor.Serve(listener)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		acceptor.Shutdown(ctx)
+	})
+	return acceptor, listener.Addr().String()
+}
+
+// testClient is the counterparty's side of a session
+type testClient struct {
+	t      *testing.T
+	compID string
+	conn   net.Conn
+	r      *bufio.Reader
+	seq    int
+}
+
+func dial(t *testing.T, addr, compID string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Expected to connect, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, compID: compID, conn: conn, r: bufio.NewReader(conn), seq: 1}
+}
+
+// send stamps the header and writes msg under the next sequence number
+func (c *testClient) send(msg *Message) {
+	c.sendAt(msg, c.seq)
+	c.seq++
+}
+
+func (c *testClient) sendAt(msg *Message, seq int) {
+	c.t.Helper()
+	out := NewMessage(msg.MsgType()).
+		Add(tagSenderCompID, c.compID).
+		Add(tagTargetCompID, "EXSIM").
+		AddInt(tagMsgSeqNum, seq).
+		AddTime(tagSendingTime, time.Now())
+	out.Fields = append(out.Fields, msg.Fields[1:]...)
+	if _, err := c.conn.Write(out.Bytes()); err != nil {
+		c.t.Fatalf("Expected to send %s, got %v", out, err)
+	}
+}
+
+// expect returns the next message that is not a Heartbeat and checks its type
+func (c *testClient) expect(msgType string) *Message {
+	c.t.Helper()
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		msg, err := ReadMessage(c.r)
+		if err != nil {
+			c.t.Fatalf("Expected MsgType %s, got %v", msgType, err)
+		}
+		if msg.MsgType() == msgHeartbeat && msgType != msgHeartbeat {
+			continue
+		}
+		if msg.MsgType() != msgType {
+			c.t.Fatalf("Expected MsgType %s, got %s", msgType, msg)
+		}
+		return msg
+	}
+}
+
+func (c *testClient) logon(heartbeat int, fields ...Field) *Message {
+	c.t.Helper()
+	msg := NewMessage(msgLogon).AddInt(tagEncryptMethod, 0).AddInt(tagHeartBtInt, heartbeat)
+	msg.Fields = append(msg.Fields, fields...)
+	c.send(msg)
+	return c.expect(msgLogon)
+}
+
+func (c *testClient) expectClosed() {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	for {
+		msg, err := ReadMessage(c.r)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				c.t.Fatal("Expected the venue to close the connection")
+			}
+			return
+		}
+		if msg.MsgType() != msgLogout && msg.MsgType() != msgHeartbeat {
+			c.t.Fatalf("Expected the connection to close, got %s", msg)
+		}
+	}
+}
+
+func get(msg *Message, tag int) string {
+	value, _ := msg.Get(tag)
+	return value
+}
+
+func newOrder(clOrdID, side, quantity, price, tif string) *Message {
+	return NewMessage(msgNewOrderSingle).
+		Add(tagClOrdID, clOrdID).
+		Add(tagSymbol, "BTC-USD").
+		Add(tagSide, side).
+		AddTime(tagTransactTime, time.Now()).
+		Add(tagOrderQty, quantity).
+		Add(tagOrdType, "2").
+		Add(tagPrice, price).
+		Add(tagTimeInForce, tif)
+}
+
+func TestAcceptor(t *testing.T) {
+	t.Run("trades_cancels_and_quotes_over_fix", func(t *testing.T) {
+		// Given: Two counterparties logged on as their own accounts
+		_, addr := newTestAcceptor(t, "MAKER=maker,TAKER=taker")
+		maker := dial(t, addr, "MAKER")
+		maker.logon(30)
+		taker := dial(t, addr, "TAKER")
+		taker.logon(30)
+
+		// When: The maker offers 1 BTC and the taker lifts 0.4 of it
+		maker.send(newOrder("ask-1", "2", "1", "60000", "1"))
+		ack := maker.expect(msgExecutionReport)
+		taker.send(newOrder("bid-1", "1", "0.4", "60000", "3"))
+
+		// Then: Each side gets New and then a fill report for its own order
+		if get(ack, tagExecType) != "0" || get(ack, tagClOrdID) != "ask-1" || get(ack, tagLeavesQty) != "1" {
+			t.Errorf("Expected the ask acknowledged as New, got %s", ack)
+		}
+		if report := taker.expect(msgExecutionReport); get(report, tagExecType) != "0" || get(report, tagClOrdID) != "bid-1" {
+			t.Errorf("Expected the bid acknowledged as New, got %s", report)
+		}
+		fill := taker.expect(msgExecutionReport)
+		if get(fill, tagExecType) != "F" || get(fill, tagOrdStatus) != "2" || get(fill, tagLastQty) != "0.4" || get(fill, tagLastPx) != "60000" {
+			t.Errorf("Expected the bid filled 0.4 at 60000, got %s", fill)
+		}
+		fill = maker.expect(msgExecutionReport)
+		if get(fill, tagExecType) != "F" || get(fill, tagOrdStatus) != "1" || get(fill, tagCumQty) != "0.4" || get(fill, tagLeavesQty) != "0.6" {
+			t.Errorf("Expected the ask partly filled, got %s", fill)
+		}
+
+		// And: A snapshot request sees the rest of the ask on top of the book
+		taker.send(NewMessage(msgMarketDataRequest).
+			Add(tagMDReqID, "md-1").Add(tagSubscriptionRequestType, "0").AddInt(tagMarketDepth, 1).
+			AddInt(tagNoMDEntryTypes, 1).Add(tagMDEntryType, "1").
+			AddInt(tagNoRelatedSym, 1).Add(tagSymbol, "BTC-USD"))
+		snapshot := taker.expect(msgMarketDataSnapshot)
+		if get(snapshot, tagNoMDEntries) != "1" || get(snapshot, tagMDEntryPx) != "60000" || get(snapshot, tagMDEntrySize) != "0.6" {
+			t.Errorf("Expected 0.6 offered at 60000, got %s", snapshot)
+		}
+
+		// And: The maker cancels by OrigClOrdID and hears back under the cancel's ClOrdID
+		maker.send(NewMessage(msgOrderCancelRequest).
+			Add(tagOrigClOrdID, "ask-1").Add(tagClOrdID, "cxl-1").Add(tagSymbol, "BTC-USD").Add(tagSide, "2").
+			AddTime(tagTransactTime, time.Now()))
+		canceled := maker.expect(msgExecutionReport)
+		if get(canceled, tagExecType) != "4" || get(canceled, tagClOrdID) != "cxl-1" || get(canceled, tagOrigClOrdID) != "ask-1" || get(canceled, tagLeavesQty) != "0" {
+			t.Errorf("Expected the ask canceled, got %s", canceled)
+		}
+	})
+
+	t.Run("streams_book_snapshots_to_subscribers", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "ALGO")
+		client := dial(t, addr, "ALGO")
+		client.logon(30)
+
+		// Given: A subscription to the full BTC-USD book
+		client.send(NewMessage(msgMarketDataRequest).
+			Add(tagMDReqID, "md-1").Add(tagSubscriptionRequestType, "1").AddInt(tagMarketDepth, 0).
+			AddInt(tagNoRelatedSym, 1).Add(tagSymbol, "BTC-USD"))
+		if initial := client.expect(msgMarketDataSnapshot); get(initial, tagNoMDEntries) != "0" {
+			t.Errorf("Expected an empty initial book, got %s", initial)
+		}
+
+		// When: A bid is placed
+		client.send(newOrder("bid-1", "1", "2", "59000", "1"))
+
+		// Then: The subscriber gets the changed book alongside the order's report, in
+		// either order
+		received := make(map[string]*Message)
+		for len(received) < 2 {
+			client.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+			msg, err := ReadMessage(client.r)
+			if err != nil {
+				t.Fatalf("Expected a report and a snapshot, got %v", err)
+			}
+			received[msg.MsgType()] = msg
+		}
+		if update := received[msgMarketDataSnapshot]; update == nil || get(update, tagMDEntryType) != "0" || get(update, tagMDEntrySize) != "2" {
+			t.Errorf("Expected a bid of 2 at 59000, got %v", update)
+		}
+		if report := received[msgExecutionReport]; report == nil || get(report, tagClOrdID) != "bid-1" {
+			t.Errorf("Expected the bid acknowledged, got %v", report)
+		}
+	})
+
+	t.Run("rejects_what_it_cannot_process", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "ALGO")
+		client := dial(t, addr, "ALGO")
+		client.logon(30)
+
+		unlisted := newOrder("bad-1", "1", "1", "10", "1")
+		unlisted.Fields[2].Value = "DOGE-USD"
+		client.send(unlisted)
+		if report := client.expect(msgExecutionReport); get(report, tagOrdStatus) != "8" || get(report, tagOrdRejReason) != "1" {
+			t.Errorf("Expected an unknown symbol rejection, got %s", report)
+		}
+
+		client.send(NewMessage(msgNewOrderSingle).Add(tagClOrdID, "bad-2").Add(tagSymbol, "BTC-USD").Add(tagSide, "1").Add(tagOrdType, "2"))
+		if reject := client.expect(msgReject); get(reject, tagRefTagID) != "38" || get(reject, tagSessionRejectReason) != "1" {
+			t.Errorf("Expected OrderQty reported missing, got %s", reject)
+		}
+
+		client.send(NewMessage("H").Add(tagClOrdID, "status-1"))
+		if reject := client.expect(msgBusinessMessageReject); get(reject, tagRefMsgType) != "H" || get(reject, tagBusinessRejectReason) != "3" {
+			t.Errorf("Expected OrderStatusRequest unsupported, got %s", reject)
+		}
+
+		client.send(NewMessage(msgOrderCancelRequest).Add(tagOrigClOrdID, "nope").Add(tagClOrdID, "cxl-1").Add(tagSymbol, "BTC-USD").Add(tagSide, "1"))
+		if reject := client.expect(msgOrderCancelReject); get(reject, tagCxlRejReason) != "1" || get(reject, tagOrderID) != "NONE" {
+			t.Errorf("Expected an unknown order cancel reject, got %s", reject)
+		}
+
+		client.send(NewMessage(msgMarketDataRequest).Add(tagMDReqID, "md-1").Add(tagSubscriptionRequestType, "0").Add(tagSymbol, "DOGE-USD"))
+		if reject := client.expect(msgMarketDataRequestReject); get(reject, tagMDReqRejReason) != "0" {
+			t.Errorf("Expected an unknown symbol market data reject, got %s", reject)
+		}
+	})
+
+	t.Run("recovers_sequence_gaps_in_both_directions", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "ALGO")
+		client := dial(t, addr, "ALGO")
+		client.logon(30)
+		client.send(newOrder("bid-1", "1", "1", "59000", "1"))
+		client.expect(msgExecutionReport) // MsgSeqNum 2
+
+		// When: The client skips sequence 3
+		client.seq = 4
+		client.send(NewMessage(msgTestRequest).Add(tagTestReqID, "lost"))
+
+		// Then: The venue asks for everything from 3
+		resendRequest := client.expect(msgResendRequest)
+		if get(resendRequest, tagBeginSeqNo) != "3" || get(resendRequest, tagEndSeqNo) != "0" {
+			t.Errorf("Expected a resend from 3, got %s", resendRequest)
+		}
+
+		// When: The client gap fills and asks for the venue's messages again
+		client.sendAt(NewMessage(msgSequenceReset).Add(tagGapFillFlag, "Y").AddInt(tagNewSeqNo, 5).Add(tagPossDupFlag, "Y"), 3)
+		client.seq = 5
+		client.send(NewMessage(msgResendRequest).AddInt(tagBeginSeqNo, 1).AddInt(tagEndSeqNo, 0))
+
+		// Then: The Logon is gap filled and the execution report resent as a possible duplicate
+		fill := client.expect(msgSequenceReset)
+		if get(fill, tagMsgSeqNum) != "1" || get(fill, tagNewSeqNo) != "2" || get(fill, tagGapFillFlag) != "Y" {
+			t.Errorf("Expected a gap fill over the Logon, got %s", fill)
+		}
+		resent := client.expect(msgExecutionReport)
+		if get(resent, tagMsgSeqNum) != "2" || get(resent, tagPossDupFlag) != "Y" || get(resent, tagOrigSendingTime) == "" || get(resent, tagClOrdID) != "bid-1" {
+			t.Errorf("Expected the report resent under MsgSeqNum 2, got %s", resent)
+		}
+		fill = client.expect(msgSequenceReset)
+		if get(fill, tagMsgSeqNum) != "3" || get(fill, tagNewSeqNo) != "4" {
+			t.Errorf("Expected a gap fill over the ResendRequest, got %s", fill)
+		}
+
+		// And: The session continues in sequence
+		client.send(NewMessage(msgTestRequest).Add(tagTestReqID, "after"))
+		if heartbeat := client.expect(msgHeartbeat); get(heartbeat, tagTestReqID) != "after" || get(heartbeat, tagMsgSeqNum) != "4" {
+			t.Errorf("Expected the TestRequest answered as MsgSeqNum 4, got %s", heartbeat)
+		}
+	})
+
+	t.Run("keeps_sequences_across_reconnects_unless_configured_to_reset", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "KEEP,FRESH=fresh:reset")
+		keep := dial(t, addr, "KEEP")
+		keep.logon(30)
+		keep.send(NewMessage(msgLogout))
+		keep.expect(msgLogout)
+		keep.expectClosed()
+
+		// When: KEEP reconnects continuing its sequence numbers
+		again := dial(t, addr, "KEEP")
+		again.seq = keep.seq
+		logon := again.logon(30)
+
+		// Then: The venue continues its own numbering too
+		if get(logon, tagMsgSeqNum) != "3" || get(logon, tagResetSeqNumFlag) != "" {
+			t.Errorf("Expected the venue's Logon as MsgSeqNum 3, got %s", logon)
+		}
+
+		// And: Restarting at 1 is refused unless reset is asked for
+		again.send(NewMessage(msgLogout))
+		again.expect(msgLogout)
+		again.expectClosed()
+		stale := dial(t, addr, "KEEP")
+		stale.send(NewMessage(msgLogon).AddInt(tagEncryptMethod, 0).AddInt(tagHeartBtInt, 30))
+		if logout := stale.expect(msgLogout); get(logout, tagText) == "" {
+			t.Errorf("Expected a too-low MsgSeqNum logout, got %s", logout)
+		}
+		stale.expectClosed()
+		reset := dial(t, addr, "KEEP")
+		if logon := reset.logon(30, Field{Tag: tagResetSeqNumFlag, Value: "Y"}); get(logon, tagMsgSeqNum) != "1" || get(logon, tagResetSeqNumFlag) != "Y" {
+			t.Errorf("Expected a reset Logon as MsgSeqNum 1, got %s", logon)
+		}
+
+		// And: A counterparty configured to reset starts at 1 every time
+		fresh := dial(t, addr, "FRESH")
+		fresh.logon(30)
+		fresh.send(NewMessage(msgLogout))
+		fresh.expect(msgLogout)
+		fresh.expectClosed()
+		fresh = dial(t, addr, "FRESH")
+		if logon := fresh.logon(30); get(logon, tagMsgSeqNum) != "1" {
+			t.Errorf("Expected FRESH to restart at 1, got %s", logon)
+		}
+	})
+
+	t.Run("refuses_unknown_and_duplicate_logons", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "ALGO")
+		stranger := dial(t, addr, "STRANGER")
+		stranger.send(NewMessage(msgLogon).AddInt(tagEncryptMethod, 0).AddInt(tagHeartBtInt, 30))
+		stranger.expectClosed()
+
+		first := dial(t, addr, "ALGO")
+		first.logon(30)
+		second := dial(t, addr, "ALGO")
+		second.send(NewMessage(msgLogon).AddInt(tagEncryptMethod, 0).AddInt(tagHeartBtInt, 30))
+		second.expectClosed()
+	})
+
+	t.Run("probes_a_silent_counterparty", func(t *testing.T) {
+		_, addr := newTestAcceptor(t, "ALGO")
+		client := dial(t, addr, "ALGO")
+		client.logon(1)
+
+		// When: The client sends nothing for longer than the heartbeat interval
+		probe := client.expect(msgTestRequest)
+
+		// Then: The venue probes, and answering keeps the session alive
+		client.send(NewMessage(msgHeartbeat).Add(tagTestReqID, get(probe, tagTestReqID)))
+		client.send(NewMessage(msgTestRequest).Add(tagTestReqID, "still-here"))
+		for {
+			if heartbeat := client.expect(msgHeartbeat); get(heartbeat, tagTestReqID) == "still-here" {
+				break
+			}
+		}
+	})
+
+	t.Run("logs_sessions_out_on_shutdown", func(t *testing.T) {
+		acceptor, addr := newTestAcceptor(t, "ALGO")
+		client := dial(t, addr, "ALGO")
+		client.logon(30)
+
+		done := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			done <- acceptor.Shutdown(ctx)
+		}()
+		if logout := client.expect(msgLogout); get(logout, tagText) != "venue shutting down" {
+			t.Errorf("Expected a shutdown Logout, got %s", logout)
+		}
+		client.send(NewMessage(msgLogout))
+		if err := <-done; err != nil {
+			t.Errorf("Expected Shutdown to finish once the session logged out, got %v", err)
+		}
+		client.expectClosed()
+	})
+}
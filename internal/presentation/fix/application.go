@@ -0,0 +1,582 @@
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// quantityEpsilon absorbs float noise when diffing fills
+const quantityEpsilon = 1e-9
+
+// OrdRejReason values (tag 103)
+const (
+	ordRejUnknownSymbol  = 1
+	ordRejExchangeClosed = 2
+	ordRejExceedsLimit   = 3
+	ordRejDuplicateOrder = 6
+	ordRejOther          = 99
+)
+
+// CxlRejReason values (tag 102)
+const (
+	cxlRejTooLate      = 0
+	cxlRejUnknownOrder = 1
+	cxlRejOther        = 99
+)
+
+// MDReqRejReason values (tag 281)
+const (
+	mdRejUnknownSymbol           = 0
+	mdRejDuplicateMDReqID        = 1
+	mdRejUnsupportedSubscription = 4
+	mdRejUnsupportedMarketDepth  = 5
+	mdRejUnsupportedMDEntryType  = 8
+)
+
+// businessRejectUnsupportedMsgType is BusinessRejectReason (tag 380) 3
+const businessRejectUnsupportedMsgType = 3
+
+// application dispatches an in-sequence application message
+func (s *session) application(msg *Message) error {
+	switch msg.MsgType() {
+	case msgNewOrderSingle:
+		return s.newOrderSingle(msg)
+	case msgOrderCancelRequest:
+		return s.orderCancelRequest(msg)
+	case msgMarketDataRequest:
+		return s.marketDataRequest(msg)
+	}
+	refSeq, _ := msg.Get(tagMsgSeqNum)
+	return s.send(NewMessage(msgBusinessMessageReject).
+		Add(tagRefSeqNum, refSeq).
+		Add(tagRefMsgType, msg.MsgType()).
+		AddInt(tagBusinessRejectReason, businessRejectUnsupportedMsgType).
+		Add(tagText, "unsupported message type"))
+}
+
+// missingTag returns the first of tags msg lacks, or 0
+func missingTag(msg *Message, tags ...int) int {
+	for _, tag := range tags {
+		if value, ok := msg.Get(tag); !ok || value == "" {
+			return tag
+		}
+	}
+	return 0
+}
+
+func (s *session) rejectMissing(msg *Message, tag int) error {
+	return s.sendReject(msg, tag, rejectRequiredTagMissing, fmt.Sprintf("required tag %d missing", tag))
+}
+
+func (s *session) rejectValue(msg *Message, tag int) error {
+	value, _ := msg.Get(tag)
+	return s.sendReject(msg, tag, rejectValueIncorrect, fmt.Sprintf("unsupported value %q for tag %d", value, tag))
+}
+
+// newOrderSingle places an order; its execution reports follow from the journal
+func (s *session) newOrderSingle(msg *Message) error {
+	if tag := missingTag(msg, tagClOrdID, tagSymbol, tagSide, tagOrderQty, tagOrdType); tag != 0 {
+		return s.rejectMissing(msg, tag)
+	}
+	clOrdID, _ := msg.Get(tagClOrdID)
+	symbol, _ := msg.Get(tagSymbol)
+	req := services.OrderRequest{AccountID: s.party.AccountID, ClientOrderID: clOrdID, Symbol: symbol}
+
+	side, _ := msg.Get(tagSide)
+	switch side {
+	case "1":
+		req.Side = models.SideBuy
+	case "2":
+		req.Side = models.SideSell
+	default:
+		return s.rejectValue(msg, tagSide)
+	}
+	ordType, _ := msg.Get(tagOrdType)
+	switch ordType {
+	case "1":
+		req.Type = models.OrderTypeMarket
+	case "2":
+		req.Type = models.OrderTypeLimit
+		if missingTag(msg, tagPrice) != 0 {
+			return s.rejectMissing(msg, tagPrice)
+		}
+	default:
+		return s.rejectValue(msg, tagOrdType)
+	}
+	quantity, _ := msg.Get(tagOrderQty)
+	var err error
+	if req.Quantity, err = strconv.ParseFloat(quantity, 64); err != nil {
+		return s.rejectValue(msg, tagOrderQty)
+	}
+	if price, ok := msg.Get(tagPrice); ok && req.Type == models.OrderTypeLimit {
+		if req.Price, err = strconv.ParseFloat(price, 64); err != nil {
+			return s.rejectValue(msg, tagPrice)
+		}
+	}
+	tif, _ := msg.Get(tagTimeInForce)
+	switch tif {
+	case "", "1":
+		req.TimeInForce = models.TimeInForceGTC
+	case "3":
+		req.TimeInForce = models.TimeInForceIOC
+	case "4":
+		req.TimeInForce = models.TimeInForceFOK
+	case "6":
+		req.TimeInForce = models.TimeInForceGTD
+		expireTime, ok := msg.Get(tagExpireTime)
+		if !ok {
+			return s.rejectMissing(msg, tagExpireTime)
+		}
+		if req.ExpiresAt, err = parseUTCTimestamp(expireTime); err != nil {
+			return s.rejectValue(msg, tagExpireTime)
+		}
+	default:
+		return s.rejectValue(msg, tagTimeInForce)
+	}
+
+	if account, ok := msg.Get(tagAccount); ok && account != s.party.AccountID {
+		return s.rejectOrder(msg, services.Rejection{
+			Reason:  services.RejectInvalidAccount,
+			Message: fmt.Sprintf("account %s is not traded by this session", account),
+		})
+	}
+	if _, err := s.acceptor.exchangeService.PlaceOrder(s.ctx, req); err != nil {
+		return s.rejectOrder(msg, services.RejectionOf(err))
+	}
+	return nil
+}
+
+// parseUTCTimestamp accepts UTCTimestamp with or without milliseconds
+func parseUTCTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(sendingTimeFormat, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102-15:04:05", value)
+}
+
+// rejectOrder answers a NewOrderSingle the venue refused before it entered the book
+func (s *session) rejectOrder(msg *Message, rejection services.Rejection) error {
+	clOrdID, _ := msg.Get(tagClOrdID)
+	symbol, _ := msg.Get(tagSymbol)
+	side, _ := msg.Get(tagSide)
+	quantity, _ := msg.Get(tagOrderQty)
+	refSeq, _ := msg.Get(tagMsgSeqNum)
+
+	reason := ordRejOther
+	switch rejection.Reason {
+	case services.RejectUnknownInstrument:
+		reason = ordRejUnknownSymbol
+	case services.RejectMarketClosed, services.RejectInstrumentHalted, services.RejectInvalidPhase:
+		reason = ordRejExchangeClosed
+	case services.RejectInsufficientBalance, services.RejectInsufficientMargin:
+		reason = ordRejExceedsLimit
+	case services.RejectDuplicateClientOrderID:
+		reason = ordRejDuplicateOrder
+	}
+	return s.send(NewMessage(msgExecutionReport).
+		Add(tagOrderID, "NONE").
+		Add(tagClOrdID, clOrdID).
+		Add(tagExecID, fmt.Sprintf("rej-%s-%s", s.party.CompID, refSeq)).
+		Add(tagExecType, "8").
+		Add(tagOrdStatus, "8").
+		Add(tagAccount, s.party.AccountID).
+		Add(tagSymbol, symbol).
+		Add(tagSide, side).
+		Add(tagOrderQty, quantity).
+		AddInt(tagLeavesQty, 0).
+		AddInt(tagCumQty, 0).
+		AddInt(tagAvgPx, 0).
+		AddInt(tagOrdRejReason, reason).
+		Add(tagText, fmt.Sprintf("%s: %s", rejection.Reason, rejection.Message)).
+		AddTime(tagTransactTime, s.acceptor.exchangeService.Now()))
+}
+
+// orderCancelRequest cancels one of the session account's orders by OrderID or by
+// OrigClOrdID; the Canceled report follows from the journal
+func (s *session) orderCancelRequest(msg *Message) error {
+	if tag := missingTag(msg, tagClOrdID, tagSymbol, tagSide); tag != 0 {
+		return s.rejectMissing(msg, tag)
+	}
+	orderID, _ := msg.Get(tagOrderID)
+	origClOrdID, _ := msg.Get(tagOrigClOrdID)
+	if orderID == "" && origClOrdID == "" {
+		return s.rejectMissing(msg, tagOrigClOrdID)
+	}
+
+	exchangeService := s.acceptor.exchangeService
+	var order models.Order
+	var err error
+	if orderID != "" {
+		order, err = exchangeService.GetOrder(s.ctx, orderID)
+	} else {
+		order, err = exchangeService.ClientOrder(s.ctx, s.party.AccountID, origClOrdID)
+	}
+	if err != nil || order.AccountID != s.party.AccountID {
+		return s.cancelReject(msg, nil, cxlRejUnknownOrder, "unknown order")
+	}
+
+	clOrdID, _ := msg.Get(tagClOrdID)
+	s.pendingCancels[order.ID] = clOrdID
+	if _, err := exchangeService.CancelOrder(s.ctx, order.ID); err != nil {
+		delete(s.pendingCancels, order.ID)
+		reason := cxlRejOther
+		switch services.RejectionOf(err).Reason {
+		case services.RejectOrderNotActive:
+			reason = cxlRejTooLate
+		case services.RejectOrderNotFound:
+			reason = cxlRejUnknownOrder
+		}
+		return s.cancelReject(msg, &order, reason, err.Error())
+	}
+	return nil
+}
+
+// cancelReject refuses an OrderCancelRequest; order is nil when it was not found
+func (s *session) cancelReject(msg *Message, order *models.Order, reason int, text string) error {
+	clOrdID, _ := msg.Get(tagClOrdID)
+	origClOrdID, _ := msg.Get(tagOrigClOrdID)
+	orderID, status := "NONE", "8"
+	if order != nil {
+		orderID, status = order.ID, ordStatus(order.Status)
+	}
+	return s.send(NewMessage(msgOrderCancelReject).
+		Add(tagOrderID, orderID).
+		Add(tagClOrdID, clOrdID).
+		Add(tagOrigClOrdID, origClOrdID).
+		Add(tagOrdStatus, status).
+		Add(tagCxlRejResponseTo, "1").
+		AddInt(tagCxlRejReason, reason).
+		Add(tagText, text))
+}
+
+// onExecution reports journal updates to the session account's orders
+func (s *session) onExecution(execution feed.Execution) error {
+	if execution.Sequence > s.party.lastExecution {
+		s.party.lastExecution = execution.Sequence
+	}
+	if execution.Order == nil || execution.Order.AccountID != s.party.AccountID {
+		return nil
+	}
+	return s.reportOrder(*execution.Order, execution.Sequence)
+}
+
+func trackOrder(order models.Order) *orderState {
+	clOrdID := order.ClientOrderID
+	if clOrdID == "" {
+		clOrdID = order.ID
+	}
+	return &orderState{
+		clOrdID:  clOrdID,
+		cumQty:   order.FilledQuantity,
+		avgPx:    order.AveragePrice,
+		quantity: order.Quantity,
+		price:    order.Price,
+	}
+}
+
+// reportOrder sends the execution reports that take the counterparty from what it
+// was last told about an order to the order's current state
+func (s *session) reportOrder(order models.Order, sequence uint64) error {
+	reports := 0
+	execID := func() string {
+		reports++
+		return fmt.Sprintf("%s-%d-%d", order.ID, sequence, reports)
+	}
+
+	state, known := s.party.orders[order.ID]
+	if !known {
+		state = trackOrder(order)
+		state.cumQty, state.avgPx = 0, 0
+		s.party.orders[order.ID] = state
+		if order.Status == models.OrderStatusRejected {
+			delete(s.party.orders, order.ID)
+			return s.send(s.executionReport(order, state, "8", "8", execID()))
+		}
+		if err := s.send(s.executionReport(order, state, "0", "0", execID())); err != nil {
+			return err
+		}
+	}
+
+	if order.FilledQuantity > state.cumQty+quantityEpsilon {
+		lastQty := order.FilledQuantity - state.cumQty
+		lastPx := (order.AveragePrice*order.FilledQuantity - state.avgPx*state.cumQty) / lastQty
+		state.cumQty, state.avgPx = order.FilledQuantity, order.AveragePrice
+		status := "1"
+		if state.cumQty >= order.Quantity-quantityEpsilon {
+			status = "2"
+		}
+		report := s.executionReport(order, state, "F", status, execID()).
+			AddFloat(tagLastQty, lastQty).
+			AddFloat(tagLastPx, lastPx)
+		if err := s.send(report); err != nil {
+			return err
+		}
+	}
+	if order.Quantity != state.quantity || order.Price != state.price {
+		state.quantity, state.price = order.Quantity, order.Price
+		if err := s.send(s.executionReport(order, state, "5", ordStatus(order.Status), execID())); err != nil {
+			return err
+		}
+	}
+
+	switch order.Status {
+	case models.OrderStatusCanceled:
+		delete(s.party.orders, order.ID)
+		cancelID, pending := s.pendingCancels[order.ID]
+		if !pending {
+			return s.send(s.executionReport(order, state, "4", "4", execID()))
+		}
+		delete(s.pendingCancels, order.ID)
+		canceled := *state
+		canceled.clOrdID = cancelID
+		return s.send(s.executionReport(order, &canceled, "4", "4", execID()).Add(tagOrigClOrdID, state.clOrdID))
+	case models.OrderStatusExpired:
+		delete(s.party.orders, order.ID)
+		return s.send(s.executionReport(order, state, "C", "C", execID()))
+	case models.OrderStatusRejected:
+		delete(s.party.orders, order.ID)
+		return s.send(s.executionReport(order, state, "8", "8", execID()))
+	case models.OrderStatusFilled:
+		delete(s.party.orders, order.ID)
+	}
+	return nil
+}
+
+// executionReport describes order as the counterparty has been told about it
+func (s *session) executionReport(order models.Order, state *orderState, execType, status, execID string) *Message {
+	leaves := state.quantity - state.cumQty
+	switch status {
+	case "2", "4", "8", "C":
+		leaves = 0
+	}
+	msg := NewMessage(msgExecutionReport).
+		Add(tagClOrdID, state.clOrdID).
+		Add(tagOrderID, order.ID).
+		Add(tagExecID, execID).
+		Add(tagExecType, execType).
+		Add(tagOrdStatus, status).
+		Add(tagAccount, order.AccountID).
+		Add(tagSymbol, order.Symbol).
+		Add(tagSide, fixSide(order.Side)).
+		Add(tagOrdType, fixOrdType(order.Type)).
+		Add(tagTimeInForce, fixTimeInForce(order.TimeInForce))
+	if order.Type == models.OrderTypeLimit {
+		msg.AddFloat(tagPrice, state.price)
+	}
+	if order.TimeInForce == models.TimeInForceGTD {
+		msg.AddTime(tagExpireTime, order.ExpiresAt)
+	}
+	return msg.
+		AddFloat(tagOrderQty, state.quantity).
+		AddFloat(tagLeavesQty, leaves).
+		AddFloat(tagCumQty, state.cumQty).
+		AddFloat(tagAvgPx, state.avgPx).
+		AddTime(tagTransactTime, order.UpdatedAt)
+}
+
+func fixSide(side models.Side) string {
+	if side == models.SideSell {
+		return "2"
+	}
+	return "1"
+}
+
+func fixOrdType(orderType models.OrderType) string {
+	if orderType == models.OrderTypeMarket {
+		return "1"
+	}
+	return "2"
+}
+
+func fixTimeInForce(tif models.TimeInForce) string {
+	switch tif {
+	case models.TimeInForceIOC:
+		return "3"
+	case models.TimeInForceFOK:
+		return "4"
+	case models.TimeInForceGTD:
+		return "6"
+	}
+	return "1"
+}
+
+func ordStatus(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPartiallyFilled:
+		return "1"
+	case models.OrderStatusFilled:
+		return "2"
+	case models.OrderStatusCanceled:
+		return "4"
+	case models.OrderStatusRejected:
+		return "8"
+	case models.OrderStatusExpired:
+		return "C"
+	}
+	return "0"
+}
+
+// marketDataRequest answers with a snapshot, or subscribes to one after every book
+// change, or ends a subscription
+func (s *session) marketDataRequest(msg *Message) error {
+	if tag := missingTag(msg, tagMDReqID, tagSubscriptionRequestType); tag != 0 {
+		return s.rejectMissing(msg, tag)
+	}
+	reqID, _ := msg.Get(tagMDReqID)
+	subscription, _ := msg.Get(tagSubscriptionRequestType)
+	switch subscription {
+	case "2":
+		if req, exists := s.mdRequests[reqID]; exists {
+			delete(s.mdRequests, reqID)
+			s.unsubscribeUnused(req.symbols)
+		}
+		return nil
+	case "0", "1":
+	default:
+		return s.mdReject(reqID, mdRejUnsupportedSubscription, "SubscriptionRequestType must be 0, 1 or 2")
+	}
+	if _, exists := s.mdRequests[reqID]; exists && subscription == "1" {
+		return s.mdReject(reqID, mdRejDuplicateMDReqID, "MDReqID already subscribed")
+	}
+
+	req := mdRequest{}
+	if value, ok := msg.Get(tagMarketDepth); ok {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return s.mdReject(reqID, mdRejUnsupportedMarketDepth, "MarketDepth must be 0 (full book) or a number of levels")
+		}
+		req.depth = depth
+	}
+	entryTypes := msg.All(tagMDEntryType)
+	for _, entryType := range entryTypes {
+		switch entryType {
+		case "0":
+			req.bids = true
+		case "1":
+			req.offers = true
+		default:
+			return s.mdReject(reqID, mdRejUnsupportedMDEntryType, fmt.Sprintf("MDEntryType %q is not published", entryType))
+		}
+	}
+	if len(entryTypes) == 0 {
+		req.bids, req.offers = true, true
+	}
+	req.symbols = msg.All(tagSymbol)
+	if len(req.symbols) == 0 {
+		return s.rejectMissing(msg, tagSymbol)
+	}
+	for _, symbol := range req.symbols {
+		if _, err := s.acceptor.exchangeService.Instruments().Get(symbol); err != nil {
+			return s.mdReject(reqID, mdRejUnknownSymbol, err.Error())
+		}
+	}
+
+	if subscription == "0" {
+		for _, symbol := range req.symbols {
+			if err := s.sendSnapshot(reqID, req, symbol); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// Subscribing delivers the current book, which onMarketData answers with the
+	// first snapshot
+	s.mdRequests[reqID] = req
+	if s.marketData == nil {
+		s.marketData = s.acceptor.exchangeService.Feed().Subscribe()
+	}
+	for _, symbol := range req.symbols {
+		topic := feed.Topic{Channel: feed.ChannelBookSnapshot, Key: symbol}
+		if err := s.acceptor.exchangeService.SubscribeFeed(s.ctx, s.marketData, topic); err != nil {
+			return s.mdReject(reqID, mdRejUnknownSymbol, err.Error())
+		}
+	}
+	return nil
+}
+
+// unsubscribeUnused drops book topics no remaining request needs
+func (s *session) unsubscribeUnused(symbols []string) {
+	if s.marketData == nil {
+		return
+	}
+	for _, symbol := range symbols {
+		if !s.subscribed(symbol) {
+			s.marketData.Remove(feed.Topic{Channel: feed.ChannelBookSnapshot, Key: symbol})
+		}
+	}
+}
+
+func (s *session) subscribed(symbol string) bool {
+	for _, req := range s.mdRequests {
+		for _, requested := range req.symbols {
+			if requested == symbol {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onMarketData sends a fresh snapshot to every request on a changed book
+func (s *session) onMarketData(msg feed.Message) error {
+	reqIDs := make([]string, 0, len(s.mdRequests))
+	for reqID, req := range s.mdRequests {
+		for _, symbol := range req.symbols {
+			if symbol == msg.Key {
+				reqIDs = append(reqIDs, reqID)
+				break
+			}
+		}
+	}
+	sort.Strings(reqIDs)
+	for _, reqID := range reqIDs {
+		if err := s.sendSnapshot(reqID, s.mdRequests[reqID], msg.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendSnapshot sends a MarketDataSnapshotFullRefresh of one book
+func (s *session) sendSnapshot(reqID string, req mdRequest, symbol string) error {
+	book, err := s.acceptor.exchangeService.OrderBook(s.ctx, symbol, req.depth)
+	if err != nil {
+		return s.mdReject(reqID, mdRejUnknownSymbol, err.Error())
+	}
+	entries := 0
+	if req.bids {
+		entries += len(book.Bids)
+	}
+	if req.offers {
+		entries += len(book.Asks)
+	}
+
+	msg := NewMessage(msgMarketDataSnapshot).
+		Add(tagMDReqID, reqID).
+		Add(tagSymbol, symbol).
+		AddInt(tagNoMDEntries, entries)
+	if req.bids {
+		for _, level := range book.Bids {
+			msg.Add(tagMDEntryType, "0").AddFloat(tagMDEntryPx, level.Price).AddFloat(tagMDEntrySize, level.Quantity)
+		}
+	}
+	if req.offers {
+		for _, level := range book.Asks {
+			msg.Add(tagMDEntryType, "1").AddFloat(tagMDEntryPx, level.Price).AddFloat(tagMDEntrySize, level.Quantity)
+		}
+	}
+	return s.send(msg)
+}
+
+func (s *session) mdReject(reqID string, reason int, text string) error {
+	return s.send(NewMessage(msgMarketDataRequestReject).
+		Add(tagMDReqID, reqID).
+		AddInt(tagMDReqRejReason, reason).
+		Add(tagText, text))
+}
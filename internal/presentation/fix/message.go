@@ -0,0 +1,289 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BeginString is the only protocol version the gateway speaks
+const BeginString = "FIX.4.4"
+
+const (
+	soh = '\x01'
+	// maxBodyLength bounds one message so a corrupt BodyLength cannot exhaust memory
+	maxBodyLength = 64 * 1024
+	// sendingTimeFormat is the UTCTimestamp format with milliseconds
+	sendingTimeFormat = "20060102-15:04:05.000"
+)
+
+// ErrGarbled marks a message whose framing, BodyLength or CheckSum is wrong. Per the
+// session protocol it is dropped without consuming a sequence number.
+var ErrGarbled = errors.New("garbled FIX message")
+
+// Tags used by the gateway
+const (
+	tagAccount                 = 1
+	tagAvgPx                   = 6
+	tagBeginSeqNo              = 7
+	tagBeginString             = 8
+	tagBodyLength              = 9
+	tagCheckSum                = 10
+	tagClOrdID                 = 11
+	tagCumQty                  = 14
+	tagEndSeqNo                = 16
+	tagExecID                  = 17
+	tagLastPx                  = 31
+	tagLastQty                 = 32
+	tagMsgSeqNum               = 34
+	tagMsgType                 = 35
+	tagNewSeqNo                = 36
+	tagOrderID                 = 37
+	tagOrderQty                = 38
+	tagOrdStatus               = 39
+	tagOrdType                 = 40
+	tagOrigClOrdID             = 41
+	tagPossDupFlag             = 43
+	tagPrice                   = 44
+	tagRefSeqNum               = 45
+	tagSenderCompID            = 49
+	tagSendingTime             = 52
+	tagSide                    = 54
+	tagSymbol                  = 55
+	tagTargetCompID            = 56
+	tagText                    = 58
+	tagTimeInForce             = 59
+	tagTransactTime            = 60
+	tagEncryptMethod           = 98
+	tagCxlRejReason            = 102
+	tagOrdRejReason            = 103
+	tagHeartBtInt              = 108
+	tagTestReqID               = 112
+	tagOrigSendingTime         = 122
+	tagGapFillFlag             = 123
+	tagExpireTime              = 126
+	tagResetSeqNumFlag         = 141
+	tagNoRelatedSym            = 146
+	tagExecType                = 150
+	tagLeavesQty               = 151
+	tagMDReqID                 = 262
+	tagSubscriptionRequestType = 263
+	tagMarketDepth             = 264
+	tagNoMDEntryTypes          = 267
+	tagNoMDEntries             = 268
+	tagMDEntryType             = 269
+	tagMDEntryPx               = 270
+	tagMDEntrySize             = 271
+	tagMDReqRejReason          = 281
+	tagRefTagID                = 371
+	tagRefMsgType              = 372
+	tagSessionRejectReason     = 373
+	tagBusinessRejectRefID     = 379
+	tagBusinessRejectReason    = 380
+	tagCxlRejResponseTo        = 434
+)
+
+// Message types handled or sent by the gateway
+const (
+	msgHeartbeat               = "0"
+	msgTestRequest             = "1"
+	msgResendRequest           = "2"
+	msgReject                  = "3"
+	msgSequenceReset           = "4"
+	msgLogout                  = "5"
+	msgExecutionReport         = "8"
+	msgOrderCancelReject       = "9"
+	msgLogon                   = "A"
+	msgNewOrderSingle          = "D"
+	msgOrderCancelRequest      = "F"
+	msgMarketDataRequest       = "V"
+	msgMarketDataSnapshot      = "W"
+	msgMarketDataRequestReject = "Y"
+	msgBusinessMessageReject   = "j"
+)
+
+// isAdmin reports whether a message type belongs to the session layer; admin
+// messages are gap filled rather than resent
+func isAdmin(msgType string) bool {
+	switch msgType {
+	case msgHeartbeat, msgTestRequest, msgResendRequest, msgReject, msgSequenceReset, msgLogout, msgLogon:
+		return true
+	}
+	return false
+}
+
+// Field is one tag=value pair
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// Message is a FIX message without its BeginString, BodyLength and CheckSum, which
+// are added on the wire. MsgType is always the first field.
+type Message struct {
+	Fields []Field
+}
+
+// NewMessage starts a message of the given type
+func NewMessage(msgType string) *Message {
+	return &Message{Fields: []Field{{Tag: tagMsgType, Value: msgType}}}
+}
+
+// MsgType returns the message's type
+func (m *Message) MsgType() string {
+	value, _ := m.Get(tagMsgType)
+	return value
+}
+
+// Add appends a field, keeping repeating groups in order
+func (m *Message) Add(tag int, value string) *Message {
+	m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+	return m
+}
+
+// AddInt appends an integer field
+func (m *Message) AddInt(tag int, value int) *Message {
+	return m.Add(tag, strconv.Itoa(value))
+}
+
+// AddFloat appends a decimal field in its shortest exact form
+func (m *Message) AddFloat(tag int, value float64) *Message {
+	return m.Add(tag, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// AddTime appends a UTCTimestamp field
+func (m *Message) AddTime(tag int, value time.Time) *Message {
+	return m.Add(tag, value.UTC().Format(sendingTimeFormat))
+}
+
+// Get returns the first value of tag
+func (m *Message) Get(tag int) (string, bool) {
+	for _, field := range m.Fields {
+		if field.Tag == tag {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+// All returns every value of tag, e.g. the entries of a one-field repeating group
+func (m *Message) All(tag int) []string {
+	values := make([]string, 0)
+	for _, field := range m.Fields {
+		if field.Tag == tag {
+			values = append(values, field.Value)
+		}
+	}
+	return values
+}
+
+// Int returns tag as an integer
+func (m *Message) Int(tag int) (int, error) {
+	value, ok := m.Get(tag)
+	if !ok {
+		return 0, fmt.Errorf("tag %d missing", tag)
+	}
+	return strconv.Atoi(value)
+}
+
+// Bytes encodes the message with its BeginString, BodyLength and CheckSum
+func (m *Message) Bytes() []byte {
+	var body bytes.Buffer
+	for _, field := range m.Fields {
+		body.WriteString(strconv.Itoa(field.Tag))
+		body.WriteByte('=')
+		body.WriteString(field.Value)
+		body.WriteByte(soh)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "8=%s\x019=%d\x01", BeginString, body.Len())
+	out.Write(body.Bytes())
+	fmt.Fprintf(&out, "10=%03d\x01", checksum(out.Bytes()))
+	return out.Bytes()
+}
+
+// String renders the message with | for SOH, for logs
+func (m *Message) String() string {
+	return strings.ReplaceAll(string(m.Bytes()), "\x01", "|")
+}
+
+func checksum(data []byte) int {
+	sum := 0
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
+
+// ReadMessage reads the next message from r. It returns ErrGarbled, with the stream
+// positioned after the bad message, when the message can be skipped, and any other
+// error when the stream is unusable.
+func ReadMessage(r *bufio.Reader) (*Message, error) {
+	begin, err := readField(r)
+	if err != nil {
+		return nil, err
+	}
+	if begin.Tag != tagBeginString || begin.Value != BeginString {
+		return nil, fmt.Errorf("%w: expected BeginString %s, got %d=%s", ErrGarbled, BeginString, begin.Tag, begin.Value)
+	}
+	length, err := readField(r)
+	if err != nil {
+		return nil, err
+	}
+	bodyLength, convErr := strconv.Atoi(length.Value)
+	if length.Tag != tagBodyLength || convErr != nil || bodyLength <= 0 || bodyLength > maxBodyLength {
+		return nil, fmt.Errorf("%w: bad BodyLength %q", ErrGarbled, length.Value)
+	}
+
+	body := make([]byte, bodyLength)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	trailer, err := readField(r)
+	if err != nil {
+		return nil, err
+	}
+	if trailer.Tag != tagCheckSum {
+		return nil, fmt.Errorf("%w: BodyLength does not end at CheckSum", ErrGarbled)
+	}
+	header := fmt.Sprintf("8=%s\x019=%s\x01", begin.Value, length.Value)
+	if fmt.Sprintf("%03d", checksum(append([]byte(header), body...))) != trailer.Value {
+		return nil, fmt.Errorf("%w: CheckSum mismatch", ErrGarbled)
+	}
+
+	msg := &Message{Fields: make([]Field, 0, 16)}
+	for _, pair := range bytes.Split(bytes.TrimSuffix(body, []byte{soh}), []byte{soh}) {
+		field, err := parseField(pair)
+		if err != nil {
+			return nil, err
+		}
+		msg.Fields = append(msg.Fields, field)
+	}
+	if len(msg.Fields) == 0 || msg.Fields[0].Tag != tagMsgType {
+		return nil, fmt.Errorf("%w: MsgType must follow BodyLength", ErrGarbled)
+	}
+	return msg, nil
+}
+
+func readField(r *bufio.Reader) (Field, error) {
+	pair, err := r.ReadBytes(soh)
+	if err != nil {
+		return Field{}, err
+	}
+	return parseField(bytes.TrimSuffix(pair, []byte{soh}))
+}
+
+func parseField(pair []byte) (Field, error) {
+	tag, value, ok := bytes.Cut(pair, []byte{'='})
+	number, err := strconv.Atoi(string(tag))
+	if !ok || err != nil || number <= 0 {
+		return Field{}, fmt.Errorf("%w: malformed field %q", ErrGarbled, pair)
+	}
+	return Field{Tag: number, Value: string(value)}, nil
+}
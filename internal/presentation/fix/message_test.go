@@ -0,0 +1,87 @@
+//go:build unit
+
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	t.Run("round_trips_through_the_wire_format", func(t *testing.T) {
+		// Given: A message with a repeating group
+		msg := NewMessage(msgMarketDataSnapshot).Add(tagMDReqID, "md-1").AddInt(tagNoMDEntries, 2).
+			Add(tagMDEntryType, "0").AddFloat(tagMDEntryPx, 60000.5).
+			Add(tagMDEntryType, "1").AddFloat(tagMDEntryPx, 60001)
+
+		// When: It is encoded and read back
+		encoded := msg.Bytes()
+		decoded, err := ReadMessage(bufio.NewReader(bytes.NewReader(encoded)))
+
+		// Then: The header and trailer frame the same fields in the same order
+		if err != nil {
+			t.Fatalf("Expected the message to decode, got %v", err)
+		}
+		if !strings.HasPrefix(string(encoded), "8=FIX.4.4\x019=") || !strings.HasSuffix(msg.String(), "|") {
+			t.Errorf("Expected a FIX.4.4 frame, got %s", msg.String())
+		}
+		if decoded.MsgType() != msgMarketDataSnapshot || len(decoded.Fields) != len(msg.Fields) {
+			t.Errorf("Expected %s, got %s", msg.String(), decoded.String())
+		}
+		if prices := decoded.All(tagMDEntryPx); len(prices) != 2 || prices[0] != "60000.5" || prices[1] != "60001" {
+			t.Errorf("Expected both entry prices in order, got %v", prices)
+		}
+	})
+
+	t.Run("skips_garbled_messages_and_keeps_reading", func(t *testing.T) {
+		// Given: A message with a corrupted CheckSum followed by a good one
+		bad := NewMessage(msgHeartbeat).Bytes()
+		bad[len(bad)-2] ^= 1
+		stream := append(bad, NewMessage(msgTestRequest).Add(tagTestReqID, "ping").Bytes()...)
+		r := bufio.NewReader(bytes.NewReader(stream))
+
+		// When: Both are read
+		_, first := ReadMessage(r)
+		second, err := ReadMessage(r)
+
+		// Then: The bad one is reported as garbled and the next one is intact
+		if !errors.Is(first, ErrGarbled) {
+			t.Errorf("Expected ErrGarbled, got %v", first)
+		}
+		if err != nil || second.MsgType() != msgTestRequest {
+			t.Errorf("Expected the TestRequest after it, got %v, %v", second, err)
+		}
+		if _, err := ReadMessage(r); err != io.EOF {
+			t.Errorf("Expected EOF at the end of the stream, got %v", err)
+		}
+	})
+
+	t.Run("rejects_other_protocol_versions", func(t *testing.T) {
+		stream := strings.ReplaceAll(string(NewMessage(msgHeartbeat).Bytes()), "FIX.4.4", "FIX.4.2")
+		if _, err := ReadMessage(bufio.NewReader(strings.NewReader(stream))); !errors.Is(err, ErrGarbled) {
+			t.Errorf("Expected a FIX.4.2 message to be garbled, got %v", err)
+		}
+	})
+}
+
+func TestParseCounterparties(t *testing.T) {
+	counterparties, err := ParseCounterparties("ALGO1=acct-1:reset, ALGO2=:nopersist ,ALGO3")
+	if err != nil || len(counterparties) != 3 {
+		t.Fatalf("Expected three counterparties, got %+v, %v", counterparties, err)
+	}
+	if counterparties[0].AccountID != "acct-1" || !counterparties[0].ResetOnLogon || counterparties[0].NoPersist {
+		t.Errorf("Expected ALGO1 trading acct-1 with reset, got %+v", counterparties[0])
+	}
+	if counterparties[1].AccountID != "ALGO2" || !counterparties[1].NoPersist || counterparties[2].AccountID != "ALGO3" {
+		t.Errorf("Expected accounts to default to the CompID, got %+v", counterparties[1:])
+	}
+	for _, spec := range []string{"ALGO1=a,ALGO1=b", "ALGO1=a:sometimes", "=a"} {
+		if _, err := ParseCounterparties(spec); err == nil {
+			t.Errorf("Expected %q to be invalid", spec)
+		}
+	}
+}
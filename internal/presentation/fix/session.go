@@ -0,0 +1,629 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// resendStoreSize bounds the application messages kept per counterparty for
+	// resend requests; older ones are gap filled
+	resendStoreSize = 10000
+	// logonTimeout is how long a connection may stay silent before its Logon
+	logonTimeout = 10 * time.Second
+	// logoutTimeout is how long to wait for the peer to confirm a Logout
+	logoutTimeout = 2 * time.Second
+	// maxTick bounds how late heartbeats and the logout timeout are noticed
+	maxTick = 500 * time.Millisecond
+)
+
+// Counterparty is one firm allowed to log on, identified by its SenderCompID and
+// trading as AccountID
+type Counterparty struct {
+	CompID       string
+	AccountID    string
+	ResetOnLogon bool // Restart both sequences at 1 on every logon
+	NoPersist    bool // Keep no sent messages; resend requests are answered with gap fills
+}
+
+// ParseCounterparties reads "compid=account[:reset][:nopersist],..."; the account
+// defaults to the CompID
+func ParseCounterparties(spec string) ([]Counterparty, error) {
+	counterparties := make([]Counterparty, 0)
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		compID, rest, _ := strings.Cut(entry, "=")
+		options := strings.Split(rest, ":")
+		if compID == "" || seen[compID] {
+			return nil, fmt.Errorf("invalid FIX counterparty %q: expected a unique compid=account[:reset][:nopersist]", entry)
+		}
+		seen[compID] = true
+
+		counterparty := Counterparty{CompID: compID, AccountID: options[0]}
+		if counterparty.AccountID == "" {
+			counterparty.AccountID = compID
+		}
+		for _, option := range options[1:] {
+			switch option {
+			case "reset":
+				counterparty.ResetOnLogon = true
+			case "nopersist":
+				counterparty.NoPersist = true
+			default:
+				return nil, fmt.Errorf("invalid FIX counterparty %q: unknown option %q", entry, option)
+			}
+		}
+		counterparties = append(counterparties, counterparty)
+	}
+	return counterparties, nil
+}
+
+// sentMessage is an application message kept for resend requests
+type sentMessage struct {
+	seq         int
+	sendingTime string
+	msg         *Message
+}
+
+// orderState is what the counterparty has been told about one order, so journal
+// updates can be turned into execution reports
+type orderState struct {
+	clOrdID  string
+	cumQty   float64
+	avgPx    float64
+	quantity float64
+	price    float64
+}
+
+// counterpartyState outlives connections: sequence numbers, sent messages and
+// order state carry over a reconnect unless the session is reset. It is owned by
+// the one session logged on for the counterparty.
+type counterpartyState struct {
+	Counterparty
+	active        bool
+	nextIn        int
+	nextOut       int
+	sent          []sentMessage
+	orders        map[string]*orderState
+	lastExecution uint64 // Journal sequence reported up to, to resume after a reconnect
+}
+
+func newCounterpartyState(counterparty Counterparty) *counterpartyState {
+	return &counterpartyState{
+		Counterparty: counterparty,
+		nextIn:       1,
+		nextOut:      1,
+		orders:       make(map[string]*orderState),
+	}
+}
+
+func (p *counterpartyState) resetSequences() {
+	p.nextIn = 1
+	p.nextOut = 1
+	p.sent = nil
+}
+
+func (p *counterpartyState) store(message sentMessage) {
+	if p.NoPersist {
+		return
+	}
+	p.sent = append(p.sent, message)
+	if len(p.sent) > resendStoreSize {
+		p.sent = p.sent[len(p.sent)-resendStoreSize:]
+	}
+}
+
+// inbound is what the reader goroutine hands the session loop
+type inbound struct {
+	msg *Message
+	err error
+}
+
+// mdRequest is one live MarketDataRequest subscription
+type mdRequest struct {
+	symbols []string
+	depth   int
+	bids    bool
+	offers  bool
+}
+
+// session is one logged-on connection. Only the run loop writes to the
+// connection or touches the counterparty state.
+type session struct {
+	acceptor  *Acceptor
+	conn      net.Conn
+	party     *counterpartyState
+	heartbeat time.Duration
+	logger    *logrus.Entry
+	ctx       context.Context
+
+	incoming       chan inbound
+	done           chan struct{}
+	executions     *feed.JournalSubscription
+	marketData     *feed.Subscriber
+	mdRequests     map[string]mdRequest // By MDReqID
+	pendingCancels map[string]string    // Cancel ClOrdID by order ID
+
+	lastSent       time.Time
+	lastReceived   time.Time
+	testRequest    string // Outstanding TestReqID
+	recoveringTo   int    // Highest MsgSeqNum seen while a resend is outstanding
+	logoutSent     bool
+	logoutDeadline time.Time
+}
+
+func newSession(a *Acceptor, conn net.Conn, party *counterpartyState, heartbeat time.Duration) *session {
+	return &session{
+		acceptor:       a,
+		conn:           conn,
+		party:          party,
+		heartbeat:      heartbeat,
+		logger:         a.logger.WithFields(logrus.Fields{"comp_id": party.CompID, logfields.Component: logfields.ComponentFIX}),
+		ctx:            keystats.WithAPIKey(context.Background(), party.CompID),
+		incoming:       make(chan inbound, 64),
+		done:           make(chan struct{}),
+		mdRequests:     make(map[string]mdRequest),
+		pendingCancels: make(map[string]string),
+	}
+}
+
+// read hands every message to the loop until the connection fails
+func (s *session) read(r *bufio.Reader) {
+	for {
+		msg, err := ReadMessage(r)
+		if errors.Is(err, ErrGarbled) {
+			s.logger.WithError(err).Warn("Dropped garbled FIX message")
+			continue
+		}
+		select {
+		case s.incoming <- inbound{msg: msg, err: err}:
+		case <-s.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// run serves the session until either side logs out or the connection drops
+func (s *session) run(logon *Message, r *bufio.Reader) {
+	defer s.close()
+	if err := s.start(logon); err != nil {
+		s.logger.WithError(err).Warn("FIX session could not start")
+		return
+	}
+	go s.read(r)
+
+	tick := s.heartbeat / 4
+	if tick > maxTick {
+		tick = maxTick
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	closing := s.acceptor.closing
+	executionsDone := s.executions.Done()
+	for {
+		var mdMessages <-chan feed.Message
+		var mdDone <-chan struct{}
+		if s.marketData != nil {
+			mdMessages, mdDone = s.marketData.Messages(), s.marketData.Done()
+		}
+
+		var err error
+		select {
+		case in := <-s.incoming:
+			if in.err != nil {
+				s.logger.WithError(in.err).Info("FIX connection closed")
+				return
+			}
+			s.lastReceived = time.Now()
+			s.testRequest = ""
+			var done bool
+			done, err = s.receive(in.msg)
+			if done {
+				return
+			}
+		case execution := <-s.executions.Executions():
+			err = s.onExecution(execution)
+		case <-executionsDone:
+			executionsDone = nil
+			s.logout("execution feed ended: " + errorText(s.executions.Err()))
+		case msg := <-mdMessages:
+			err = s.onMarketData(msg)
+		case <-mdDone:
+			s.logout("market data feed ended: " + errorText(s.marketData.Err()))
+			s.marketData = nil
+		case <-closing:
+			closing = nil
+			s.logout("venue shutting down")
+		case now := <-ticker.C:
+			if s.onTick(now) {
+				return
+			}
+		}
+		if err != nil {
+			s.logger.WithError(err).Warn("FIX connection failed")
+			return
+		}
+	}
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return "closed"
+	}
+	return err.Error()
+}
+
+// start answers the Logon and subscribes to the account's executions
+func (s *session) start(logon *Message) error {
+	party := s.party
+	requested, _ := logon.Get(tagResetSeqNumFlag)
+	reset := requested == "Y" || party.ResetOnLogon
+	if reset {
+		party.resetSequences()
+	}
+	seq, _ := logon.Int(tagMsgSeqNum)
+	if seq < party.nextIn {
+		s.sendLogout(fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", party.nextIn, seq))
+		return fmt.Errorf("logon MsgSeqNum %d below expected %d", seq, party.nextIn)
+	}
+
+	if err := s.subscribeExecutions(); err != nil {
+		return err
+	}
+	reply := NewMessage(msgLogon).AddInt(tagEncryptMethod, 0).AddInt(tagHeartBtInt, int(s.heartbeat/time.Second))
+	if reset {
+		reply.Add(tagResetSeqNumFlag, "Y")
+	}
+	if err := s.send(reply); err != nil {
+		return err
+	}
+	s.lastReceived = time.Now()
+	s.logger.WithFields(logrus.Fields{"next_in": party.nextIn, "next_out": party.nextOut}).Info("FIX session logged on")
+
+	if seq > party.nextIn {
+		return s.requestResend(seq)
+	}
+	party.nextIn++
+	return nil
+}
+
+// subscribeExecutions resumes the journal where the last connection stopped, or
+// starts live and takes the account's open orders as already reported
+func (s *session) subscribeExecutions() error {
+	journal := s.acceptor.exchangeService.Executions()
+	party := s.party
+	if party.lastExecution > 0 {
+		sub, backlog, err := journal.Subscribe(party.lastExecution + 1)
+		if err == nil {
+			s.executions = sub
+			for _, execution := range backlog {
+				if err := s.onExecution(execution); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if !errors.Is(err, feed.ErrSequenceExpired) {
+			return err
+		}
+		s.logger.Warn("Execution journal no longer covers the last session; reconciling open orders")
+	}
+
+	sub, _, err := journal.Subscribe(0)
+	if err != nil {
+		return err
+	}
+	s.executions = sub
+	open, err := s.acceptor.exchangeService.OpenOrders(s.ctx, party.AccountID, "")
+	if err != nil {
+		return err
+	}
+	if party.lastExecution == 0 {
+		for _, order := range open {
+			if _, tracked := party.orders[order.ID]; !tracked {
+				party.orders[order.ID] = trackOrder(order)
+			}
+		}
+	} else {
+		current := make(map[string]models.Order, len(open))
+		for _, order := range open {
+			current[order.ID] = order
+		}
+		for orderID := range party.orders {
+			if _, found := current[orderID]; !found {
+				if order, err := s.acceptor.exchangeService.GetOrder(s.ctx, orderID); err == nil {
+					current[orderID] = order
+				}
+			}
+		}
+		for _, order := range current {
+			if err := s.reportOrder(order, journal.Sequence()); err != nil {
+				return err
+			}
+		}
+	}
+	if sequence := journal.Sequence(); sequence > party.lastExecution {
+		party.lastExecution = sequence
+	}
+	return nil
+}
+
+// receive applies the session rules to one inbound message and reports whether
+// the session is over
+func (s *session) receive(msg *Message) (bool, error) {
+	party := s.party
+	msgType := msg.MsgType()
+	seq, err := msg.Int(tagMsgSeqNum)
+	if err != nil {
+		s.sendLogout("MsgSeqNum missing or invalid")
+		return true, nil
+	}
+	if sender, _ := msg.Get(tagSenderCompID); sender != party.CompID {
+		s.sendReject(msg, tagSenderCompID, rejectCompIDProblem, "SenderCompID does not match the session")
+		s.sendLogout("CompID problem")
+		return true, nil
+	}
+
+	// A ResendRequest is answered even when it arrives out of sequence, or both
+	// sides could wait on each other forever
+	if msgType == msgResendRequest {
+		if err := s.resend(msg); err != nil {
+			return false, err
+		}
+	}
+	if msgType == msgSequenceReset {
+		if gapFill, _ := msg.Get(tagGapFillFlag); gapFill != "Y" {
+			return false, s.sequenceReset(msg)
+		}
+	}
+
+	possDup, _ := msg.Get(tagPossDupFlag)
+	switch {
+	case seq > party.nextIn:
+		if msgType == msgLogout {
+			return true, nil
+		}
+		return false, s.requestResend(seq)
+	case seq < party.nextIn:
+		if possDup == "Y" {
+			return false, nil
+		}
+		s.sendLogout(fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", party.nextIn, seq))
+		return true, nil
+	}
+	party.nextIn++
+	if s.recoveringTo > 0 && party.nextIn > s.recoveringTo {
+		s.recoveringTo = 0
+	}
+
+	switch msgType {
+	case msgHeartbeat, msgResendRequest, msgReject:
+		return false, nil
+	case msgTestRequest:
+		id, _ := msg.Get(tagTestReqID)
+		return false, s.send(NewMessage(msgHeartbeat).Add(tagTestReqID, id))
+	case msgSequenceReset:
+		newSeq, err := msg.Int(tagNewSeqNo)
+		if err != nil {
+			return false, s.sendReject(msg, tagNewSeqNo, rejectRequiredTagMissing, "NewSeqNo is required")
+		}
+		if newSeq > party.nextIn {
+			party.nextIn = newSeq
+		}
+		return false, nil
+	case msgLogout:
+		if !s.logoutSent {
+			s.sendLogout("")
+		}
+		return true, nil
+	case msgLogon:
+		return false, s.sendReject(msg, tagMsgType, rejectInvalidMsgType, "already logged on")
+	}
+	return false, s.application(msg)
+}
+
+// sequenceReset applies a SequenceReset in reset mode, which ignores MsgSeqNum
+func (s *session) sequenceReset(msg *Message) error {
+	newSeq, err := msg.Int(tagNewSeqNo)
+	if err != nil {
+		return s.sendReject(msg, tagNewSeqNo, rejectRequiredTagMissing, "NewSeqNo is required")
+	}
+	if newSeq < s.party.nextIn {
+		return s.sendReject(msg, tagNewSeqNo, rejectValueIncorrect, fmt.Sprintf("NewSeqNo %d is below the expected %d", newSeq, s.party.nextIn))
+	}
+	s.party.nextIn = newSeq
+	s.recoveringTo = 0
+	return nil
+}
+
+// requestResend asks for everything from the first missing message, once per gap
+func (s *session) requestResend(seen int) error {
+	if s.recoveringTo > 0 {
+		if seen > s.recoveringTo {
+			s.recoveringTo = seen
+		}
+		return nil
+	}
+	s.recoveringTo = seen
+	s.logger.WithFields(logrus.Fields{"expected": s.party.nextIn, "received": seen}).Warn("FIX sequence gap, requesting resend")
+	return s.send(NewMessage(msgResendRequest).AddInt(tagBeginSeqNo, s.party.nextIn).AddInt(tagEndSeqNo, 0))
+}
+
+// resend answers a ResendRequest with the stored application messages, gap filling
+// admin messages and anything no longer stored
+func (s *session) resend(msg *Message) error {
+	begin, err := msg.Int(tagBeginSeqNo)
+	if err != nil || begin < 1 {
+		return s.sendReject(msg, tagBeginSeqNo, rejectValueIncorrect, "BeginSeqNo is required")
+	}
+	end, _ := msg.Int(tagEndSeqNo)
+	last := s.party.nextOut - 1
+	if end == 0 || end > last {
+		end = last
+	}
+
+	gapFrom := 0
+	flushGap := func(next int) error {
+		if gapFrom == 0 {
+			return nil
+		}
+		fill := NewMessage(msgSequenceReset).Add(tagGapFillFlag, "Y").AddInt(tagNewSeqNo, next)
+		err := s.write(fill, gapFrom, true, "")
+		gapFrom = 0
+		return err
+	}
+	for _, stored := range s.party.sent {
+		if stored.seq < begin || stored.seq > end {
+			continue
+		}
+		if gapFrom == 0 && stored.seq > begin {
+			gapFrom = begin
+		}
+		if err := flushGap(stored.seq); err != nil {
+			return err
+		}
+		if err := s.write(stored.msg, stored.seq, true, stored.sendingTime); err != nil {
+			return err
+		}
+		begin = stored.seq + 1
+	}
+	if begin <= end {
+		gapFrom = begin
+		return flushGap(end + 1)
+	}
+	return nil
+}
+
+// onTick sends heartbeats, probes a silent peer and drops a dead one
+func (s *session) onTick(now time.Time) bool {
+	if s.logoutSent && now.After(s.logoutDeadline) {
+		return true
+	}
+	silent := now.Sub(s.lastReceived)
+	grace := s.heartbeat / 5
+	switch {
+	case s.testRequest != "" && silent > 2*s.heartbeat+grace:
+		s.logger.Warn("FIX counterparty stopped responding, disconnecting")
+		return true
+	case s.testRequest == "" && silent > s.heartbeat+grace:
+		s.testRequest = strconv.FormatInt(now.UnixNano(), 10)
+		if err := s.send(NewMessage(msgTestRequest).Add(tagTestReqID, s.testRequest)); err != nil {
+			return true
+		}
+	case now.Sub(s.lastSent) >= s.heartbeat:
+		if err := s.send(NewMessage(msgHeartbeat)); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// logout starts a venue-initiated logout; the connection closes when the peer
+// confirms or after logoutTimeout
+func (s *session) logout(text string) {
+	if s.logoutSent {
+		return
+	}
+	s.sendLogout(text)
+}
+
+func (s *session) sendLogout(text string) {
+	msg := NewMessage(msgLogout)
+	if text != "" {
+		msg.Add(tagText, text)
+	}
+	s.logoutSent = true
+	s.logoutDeadline = time.Now().Add(logoutTimeout)
+	if err := s.send(msg); err != nil {
+		s.logger.WithError(err).Debug("Failed to send FIX Logout")
+	}
+}
+
+// Session-level reject reasons (tag 373)
+const (
+	rejectRequiredTagMissing = 1
+	rejectValueIncorrect     = 5
+	rejectCompIDProblem      = 9
+	rejectInvalidMsgType     = 11
+)
+
+// sendReject refuses a message at the session level
+func (s *session) sendReject(ref *Message, tag, reason int, text string) error {
+	refSeq, _ := ref.Get(tagMsgSeqNum)
+	return s.send(NewMessage(msgReject).
+		Add(tagRefSeqNum, refSeq).
+		AddInt(tagRefTagID, tag).
+		Add(tagRefMsgType, ref.MsgType()).
+		AddInt(tagSessionRejectReason, reason).
+		Add(tagText, text))
+}
+
+// send stamps msg with the next outgoing sequence number and writes it
+func (s *session) send(msg *Message) error {
+	seq := s.party.nextOut
+	s.party.nextOut++
+	sendingTime := time.Now().UTC().Format(sendingTimeFormat)
+	if !isAdmin(msg.MsgType()) {
+		s.party.store(sentMessage{seq: seq, sendingTime: sendingTime, msg: msg})
+	}
+	return s.writeAt(msg, seq, false, "", sendingTime)
+}
+
+// write sends msg under an existing sequence number, as a resend when possDup is set
+func (s *session) write(msg *Message, seq int, possDup bool, origSendingTime string) error {
+	return s.writeAt(msg, seq, possDup, origSendingTime, time.Now().UTC().Format(sendingTimeFormat))
+}
+
+func (s *session) writeAt(msg *Message, seq int, possDup bool, origSendingTime, sendingTime string) error {
+	out := NewMessage(msg.MsgType()).
+		Add(tagSenderCompID, s.acceptor.compID).
+		Add(tagTargetCompID, s.party.CompID).
+		AddInt(tagMsgSeqNum, seq)
+	if possDup {
+		out.Add(tagPossDupFlag, "Y")
+		if origSendingTime != "" {
+			out.Add(tagOrigSendingTime, origSendingTime)
+		}
+	}
+	out.Add(tagSendingTime, sendingTime)
+	out.Fields = append(out.Fields, msg.Fields[1:]...)
+
+	s.lastSent = time.Now()
+	s.conn.SetWriteDeadline(s.lastSent.Add(s.heartbeat))
+	_, err := s.conn.Write(out.Bytes())
+	return err
+}
+
+// close ends the connection and frees the counterparty for the next logon
+func (s *session) close() {
+	close(s.done)
+	s.conn.Close()
+	if s.executions != nil {
+		s.executions.Close()
+	}
+	if s.marketData != nil {
+		s.marketData.Close()
+	}
+	s.logger.WithFields(logrus.Fields{"next_in": s.party.nextIn, "next_out": s.party.nextOut}).Info("FIX session ended")
+	s.acceptor.release(s)
+}
@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+)
+
+// APIKeyMetadata carries the caller's API key on gRPC requests
+const APIKeyMetadata = "x-api-key"
+
+// TimestampMetadata and SignatureMetadata sign calls made with account API keys
+const (
+	TimestampMetadata = "x-api-timestamp"
+	SignatureMetadata = "x-api-signature"
+)
+
+// IdempotencyKeyMetadata lets a client retry an order RPC without repeating it
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// CorrelationIDMetadata ties an RPC to the audit events it causes; one is generated
+// when the client sends none, and returned in the response header either way
+const CorrelationIDMetadata = "x-correlation-id"
+
+// APIKeyInterceptor tags each exchange RPC with its API key, correlation ID, and
+// idempotency key if sent, and counts it as one message
+func APIKeyInterceptor(recorder *keystats.Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, "/exchange.v1.") {
+			return handler(ctx, req)
+		}
+
+		apiKey := keystats.Anonymous
+		if values := metadata.ValueFromIncomingContext(ctx, APIKeyMetadata); len(values) > 0 && values[0] != "" {
+			apiKey = values[0]
+		}
+		recorder.Message(apiKey, "grpc")
+		ctx = keystats.WithAPIKey(ctx, apiKey)
+		correlationID := incomingCorrelationID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDMetadata, correlationID))
+		ctx = audit.WithCorrelationID(ctx, correlationID)
+		if values := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyMetadata); len(values) > 0 && values[0] != "" {
+			ctx = idempotency.WithKey(ctx, values[0])
+		}
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyStreamInterceptor tags each exchange stream with its API key, so open
+// streams and their subscriptions count against the key's limits, and its
+// correlation ID, and counts opening it as one message
+func APIKeyStreamInterceptor(recorder *keystats.Recorder) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, "/exchange.v1.") {
+			return handler(srv, stream)
+		}
+
+		apiKey := keystats.Anonymous
+		if values := metadata.ValueFromIncomingContext(stream.Context(), APIKeyMetadata); len(values) > 0 && values[0] != "" {
+			apiKey = values[0]
+		}
+		recorder.Message(apiKey, "grpc")
+		correlationID := incomingCorrelationID(stream.Context())
+		grpc.SetHeader(stream.Context(), metadata.Pairs(CorrelationIDMetadata, correlationID))
+		ctx := audit.WithCorrelationID(keystats.WithAPIKey(stream.Context(), apiKey), correlationID)
+		return handler(srv, &taggedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// incomingCorrelationID is the caller's correlation ID, or a new one
+func incomingCorrelationID(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, CorrelationIDMetadata); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return audit.NewID()
+}
+
+// taggedStream replaces a server stream's context
+type taggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *taggedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,256 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ServiceInterceptor guards one gRPC service's unary and streaming methods
+type ServiceInterceptor struct {
+	Unary  grpc.UnaryServerInterceptor
+	Stream grpc.StreamServerInterceptor
+}
+
+// ServiceInterceptors runs each service's interceptor on that service's methods
+// only, so services sharing a server authenticate independently. Methods of
+// services without one pass straight through.
+type ServiceInterceptors struct {
+	byService map[string]ServiceInterceptor
+}
+
+func NewServiceInterceptors() *ServiceInterceptors {
+	return &ServiceInterceptors{byService: make(map[string]ServiceInterceptor)}
+}
+
+// Register guards service, named as in its service descriptor, e.g.
+// "exchange.v1.TradingService"; register before the server starts
+func (i *ServiceInterceptors) Register(service string, interceptor ServiceInterceptor) {
+	i.byService[service] = interceptor
+}
+
+// Unary dispatches unary calls to their service's interceptor
+func (i *ServiceInterceptors) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if interceptor, ok := i.byService[serviceOf(info.FullMethod)]; ok && interceptor.Unary != nil {
+			return interceptor.Unary(ctx, req, info, handler)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream dispatches streaming calls to their service's interceptor
+func (i *ServiceInterceptors) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if interceptor, ok := i.byService[serviceOf(info.FullMethod)]; ok && interceptor.Stream != nil {
+			return interceptor.Stream(srv, stream, info, handler)
+		}
+		return handler(srv, stream)
+	}
+}
+
+// serviceOf extracts the service from a full method, "/exchange.v1.TradingService/PlaceOrder"
+func serviceOf(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service
+}
+
+// ParseAPIKeys reads keys in the GRPC_TRADING_API_KEYS form "key,..."
+func ParseAPIKeys(spec string) []string {
+	keys := make([]string, 0)
+	for _, key := range strings.Split(spec, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// RequireAPIKey admits calls whose x-api-key metadata is one of keys, failing the
+// rest with UNAUTHENTICATED. Without keys every call is admitted, as in development.
+func RequireAPIKey(keys []string) ServiceInterceptor {
+	allowed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		allowed[key] = true
+	}
+	authenticate := func(ctx context.Context) error {
+		if len(allowed) == 0 {
+			return nil
+		}
+		values := metadata.ValueFromIncomingContext(ctx, APIKeyMetadata)
+		if len(values) == 0 || values[0] == "" {
+			return status.Errorf(codes.Unauthenticated, "%s metadata is required", APIKeyMetadata)
+		}
+		if !allowed[values[0]] {
+			return status.Error(codes.Unauthenticated, "API key is not authorized for trading")
+		}
+		return nil
+	}
+	return ServiceInterceptor{
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authenticate(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authenticate(stream.Context()); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		},
+	}
+}
+
+// AdminTokenMetadata carries the operator token as "Bearer <ADMIN_TOKEN>", as the
+// REST admin routes take it in the Authorization header
+const AdminTokenMetadata = "authorization"
+
+// RequireAdminToken admits calls presenting token in the authorization metadata,
+// failing the rest with UNAUTHENTICATED; trading keys do not stand in for it.
+// Without a token every call is admitted, as the REST admin routes are.
+func RequireAdminToken(token string) ServiceInterceptor {
+	authenticate := func(ctx context.Context) error {
+		if token == "" {
+			return nil
+		}
+		presented, ok := strings.CutPrefix(firstMetadata(ctx, AdminTokenMetadata), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return status.Error(codes.Unauthenticated, "admin token required")
+		}
+		return nil
+	}
+	return ServiceInterceptor{
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authenticate(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authenticate(stream.Context()); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		},
+	}
+}
+
+// RequestAuthenticator checks the signature of a request made with an account API key
+type RequestAuthenticator interface {
+	AuthenticateRequest(ctx context.Context, req services.SignedRequest) error
+}
+
+// SignatureInterceptor authenticates exchange RPCs made with keys issued through the
+// account key API: x-api-timestamp carries Unix milliseconds and x-api-signature the
+// hex HMAC-SHA256 of the timestamp, the full method name and the request message in
+// deterministic protobuf encoding, so a signature cannot be replayed with another
+// request. Streams are checked on their request before the handler runs. Other keys pass.
+func SignatureInterceptor(authenticator RequestAuthenticator) ServiceInterceptor {
+	authenticate := func(ctx context.Context, fullMethod string, req interface{}) error {
+		message, ok := req.(proto.Message)
+		if !ok {
+			return status.Errorf(codes.Internal, "%s request is not a protobuf message", fullMethod)
+		}
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to encode request: %v", err)
+		}
+		timestamp := firstMetadata(ctx, TimestampMetadata)
+		err = authenticator.AuthenticateRequest(ctx, services.SignedRequest{
+			KeyID:     firstMetadata(ctx, APIKeyMetadata),
+			Timestamp: timestamp,
+			Signature: firstMetadata(ctx, SignatureMetadata),
+			Payload:   accounts.SigningPayload(timestamp, "", fullMethod, body),
+			Transport: "grpc",
+		})
+		if err != nil {
+			return statusFromError(err)
+		}
+		return nil
+	}
+	return ServiceInterceptor{
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if !strings.HasPrefix(info.FullMethod, "/exchange.v1.") {
+				return handler(ctx, req)
+			}
+			if err := authenticate(ctx, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if !strings.HasPrefix(info.FullMethod, "/exchange.v1.") || info.IsClientStream {
+				return handler(srv, stream)
+			}
+			req, err := requestMessage(info.FullMethod)
+			if err != nil {
+				return err
+			}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			if err := authenticate(stream.Context(), info.FullMethod, req); err != nil {
+				return err
+			}
+			return handler(srv, &receivedStream{ServerStream: stream, request: req})
+		},
+	}
+}
+
+// requestMessage makes an empty request message for a method in the registered
+// descriptors, so a stream's request can be read before its handler runs
+func requestMessage(fullMethod string) (proto.Message, error) {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+	method, ok := descriptor.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unknown request type %s", method.Input().FullName())
+	}
+	return messageType.New().Interface(), nil
+}
+
+// receivedStream hands a handler the request already read off its stream
+type receivedStream struct {
+	grpc.ServerStream
+	request proto.Message
+}
+
+func (s *receivedStream) RecvMsg(m interface{}) error {
+	if s.request == nil {
+		return s.ServerStream.RecvMsg(m)
+	}
+	message, ok := m.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, "stream message is not a protobuf message")
+	}
+	proto.Reset(message)
+	proto.Merge(message, s.request)
+	s.request = nil
+	return nil
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// ChaosServiceServer implements the exchange.v1.ChaosService gRPC API, which injects
+// faults into the running venue. It is only served when chaos injection is enabled.
+type ChaosServiceServer struct {
+	exchangev1.UnimplementedChaosServiceServer
+
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewChaosServiceServer(exchangeService *services.ExchangeService, logger *logrus.Logger) *ChaosServiceServer {
+	return &ChaosServiceServer{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// InjectFault starts a fault
+func (s *ChaosServiceServer) InjectFault(ctx context.Context, req *exchangev1.InjectFaultRequest) (*exchangev1.InjectFaultResponse, error) {
+	fault, err := s.exchangeService.InjectFault(ctx, faultSpecFromProto(req.GetSpec()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.InjectFaultResponse{Fault: faultToProto(fault)}, nil
+}
+
+// ListFaults lists the faults in force
+func (s *ChaosServiceServer) ListFaults(ctx context.Context, req *exchangev1.ListFaultsRequest) (*exchangev1.ListFaultsResponse, error) {
+	faults, err := s.exchangeService.ChaosFaults(ctx)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	resp := &exchangev1.ListFaultsResponse{Faults: make([]*exchangev1.Fault, 0, len(faults))}
+	for _, fault := range faults {
+		resp.Faults = append(resp.Faults, faultToProto(fault))
+	}
+	return resp, nil
+}
+
+// ClearFault ends a fault early
+func (s *ChaosServiceServer) ClearFault(ctx context.Context, req *exchangev1.ClearFaultRequest) (*exchangev1.ClearFaultResponse, error) {
+	fault, err := s.exchangeService.ClearFault(ctx, req.GetFaultId())
+	if errors.Is(err, chaos.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.ClearFaultResponse{Fault: faultToProto(fault)}, nil
+}
+
+func faultSpecFromProto(spec *exchangev1.FaultSpec) chaos.Spec {
+	return chaos.Spec{
+		Kind:        chaos.Kind(spec.GetKind()),
+		Symbols:     spec.GetSymbols(),
+		Rate:        spec.GetRate(),
+		DelayMs:     spec.GetDelayMs(),
+		Reason:      spec.GetReason(),
+		AccountID:   spec.GetAccountId(),
+		Asset:       spec.GetAsset(),
+		Amount:      spec.GetAmount(),
+		OverfillPct: spec.GetOverfillPct(),
+		DurationMs:  spec.GetDurationMs(),
+	}
+}
+
+func faultToProto(fault chaos.Fault) *exchangev1.Fault {
+	out := &exchangev1.Fault{
+		FaultId: fault.ID,
+		Spec: &exchangev1.FaultSpec{
+			Kind:        string(fault.Kind),
+			Symbols:     fault.Symbols,
+			Rate:        fault.Rate,
+			DelayMs:     fault.DelayMs,
+			Reason:      fault.Reason,
+			AccountId:   fault.AccountID,
+			Asset:       fault.Asset,
+			Amount:      fault.Amount,
+			OverfillPct: fault.OverfillPct,
+			DurationMs:  fault.DurationMs,
+		},
+		InjectedTimeMs: fault.InjectedAt.UnixMilli(),
+		Hits:           fault.Hits,
+	}
+	if fault.ExpiresAt != nil {
+		out.ExpireTimeMs = fault.ExpiresAt.UnixMilli()
+	}
+	return out
+}
@@ -0,0 +1,337 @@
+package grpc
+
+import (
+	"time"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func sideFromProto(side exchangev1.Side) models.Side {
+	switch side {
+	case exchangev1.Side_SIDE_BUY:
+		return models.SideBuy
+	case exchangev1.Side_SIDE_SELL:
+		return models.SideSell
+	}
+	return ""
+}
+
+func orderTypeFromProto(orderType exchangev1.OrderType) models.OrderType {
+	if orderType == exchangev1.OrderType_ORDER_TYPE_MARKET {
+		return models.OrderTypeMarket
+	}
+	return models.OrderTypeLimit
+}
+
+func timeInForceFromProto(tif exchangev1.TimeInForce) models.TimeInForce {
+	switch tif {
+	case exchangev1.TimeInForce_TIME_IN_FORCE_IOC:
+		return models.TimeInForceIOC
+	case exchangev1.TimeInForce_TIME_IN_FORCE_FOK:
+		return models.TimeInForceFOK
+	case exchangev1.TimeInForce_TIME_IN_FORCE_GTD:
+		return models.TimeInForceGTD
+	}
+	return models.TimeInForceGTC
+}
+
+func orderRequestFromProto(spec *exchangev1.OrderSpec) services.OrderRequest {
+	if spec == nil {
+		return services.OrderRequest{}
+	}
+	var expiresAt time.Time
+	if spec.GetExpireTimeMs() > 0 {
+		expiresAt = time.UnixMilli(spec.GetExpireTimeMs()).UTC()
+	}
+	return services.OrderRequest{
+		AccountID:     spec.GetAccountId(),
+		ClientOrderID: spec.GetClientOrderId(),
+		Symbol:        spec.GetSymbol(),
+		Side:          sideFromProto(spec.GetSide()),
+		Type:          orderTypeFromProto(spec.GetType()),
+		TimeInForce:   timeInForceFromProto(spec.GetTimeInForce()),
+		Quantity:      spec.GetQuantity(),
+		Price:         spec.GetPrice(),
+		ExpiresAt:     expiresAt,
+	}
+}
+
+func sideToProto(side models.Side) exchangev1.Side {
+	switch side {
+	case models.SideBuy:
+		return exchangev1.Side_SIDE_BUY
+	case models.SideSell:
+		return exchangev1.Side_SIDE_SELL
+	}
+	return exchangev1.Side_SIDE_UNSPECIFIED
+}
+
+func orderTypeToProto(orderType models.OrderType) exchangev1.OrderType {
+	if orderType == models.OrderTypeMarket {
+		return exchangev1.OrderType_ORDER_TYPE_MARKET
+	}
+	return exchangev1.OrderType_ORDER_TYPE_LIMIT
+}
+
+func timeInForceToProto(tif models.TimeInForce) exchangev1.TimeInForce {
+	switch tif {
+	case models.TimeInForceIOC:
+		return exchangev1.TimeInForce_TIME_IN_FORCE_IOC
+	case models.TimeInForceFOK:
+		return exchangev1.TimeInForce_TIME_IN_FORCE_FOK
+	case models.TimeInForceGTD:
+		return exchangev1.TimeInForce_TIME_IN_FORCE_GTD
+	}
+	return exchangev1.TimeInForce_TIME_IN_FORCE_GTC
+}
+
+// orderStatusFromProto maps UNSPECIFIED to no status, which a query rejects
+func orderStatusFromProto(status exchangev1.OrderStatus) models.OrderStatus {
+	switch status {
+	case exchangev1.OrderStatus_ORDER_STATUS_NEW:
+		return models.OrderStatusNew
+	case exchangev1.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED:
+		return models.OrderStatusPartiallyFilled
+	case exchangev1.OrderStatus_ORDER_STATUS_FILLED:
+		return models.OrderStatusFilled
+	case exchangev1.OrderStatus_ORDER_STATUS_CANCELED:
+		return models.OrderStatusCanceled
+	case exchangev1.OrderStatus_ORDER_STATUS_REJECTED:
+		return models.OrderStatusRejected
+	case exchangev1.OrderStatus_ORDER_STATUS_EXPIRED:
+		return models.OrderStatusExpired
+	}
+	return ""
+}
+
+func orderStatusToProto(status models.OrderStatus) exchangev1.OrderStatus {
+	switch status {
+	case models.OrderStatusNew:
+		return exchangev1.OrderStatus_ORDER_STATUS_NEW
+	case models.OrderStatusPartiallyFilled:
+		return exchangev1.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED
+	case models.OrderStatusFilled:
+		return exchangev1.OrderStatus_ORDER_STATUS_FILLED
+	case models.OrderStatusCanceled:
+		return exchangev1.OrderStatus_ORDER_STATUS_CANCELED
+	case models.OrderStatusRejected:
+		return exchangev1.OrderStatus_ORDER_STATUS_REJECTED
+	case models.OrderStatusExpired:
+		return exchangev1.OrderStatus_ORDER_STATUS_EXPIRED
+	}
+	return exchangev1.OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+// unixMillis maps the zero time to 0 rather than a large negative value
+func unixMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func orderToProto(order models.Order) *exchangev1.Order {
+	return &exchangev1.Order{
+		Id:             order.ID,
+		ClientOrderId:  order.ClientOrderID,
+		AccountId:      order.AccountID,
+		Symbol:         order.Symbol,
+		Side:           sideToProto(order.Side),
+		Type:           orderTypeToProto(order.Type),
+		TimeInForce:    timeInForceToProto(order.TimeInForce),
+		Price:          order.Price,
+		Quantity:       order.Quantity,
+		FilledQuantity: order.FilledQuantity,
+		AveragePrice:   order.AveragePrice,
+		Status:         orderStatusToProto(order.Status),
+		ExpireTimeMs:   unixMillis(order.ExpiresAt),
+		CreatedTimeMs:  unixMillis(order.CreatedAt),
+		UpdatedTimeMs:  unixMillis(order.UpdatedAt),
+	}
+}
+
+func ordersToProto(orders []models.Order) []*exchangev1.Order {
+	converted := make([]*exchangev1.Order, 0, len(orders))
+	for _, order := range orders {
+		converted = append(converted, orderToProto(order))
+	}
+	return converted
+}
+
+func tradeToProto(trade models.Trade) *exchangev1.Trade {
+	return &exchangev1.Trade{
+		Id:             trade.ID,
+		Symbol:         trade.Symbol,
+		Price:          trade.Price,
+		Quantity:       trade.Quantity,
+		BuyOrderId:     trade.BuyOrderID,
+		SellOrderId:    trade.SellOrderID,
+		BuyAccountId:   trade.BuyAccountID,
+		SellAccountId:  trade.SellAccountID,
+		TakerSide:      sideToProto(trade.TakerSide),
+		Auction:        trade.Auction,
+		ExecutedTimeMs: unixMillis(trade.ExecutedAt),
+	}
+}
+
+func tradesToProto(trades []models.Trade) []*exchangev1.Trade {
+	converted := make([]*exchangev1.Trade, 0, len(trades))
+	for _, trade := range trades {
+		converted = append(converted, tradeToProto(trade))
+	}
+	return converted
+}
+
+func priceLevelsToProto(levels []matching.PriceLevel) []*exchangev1.PriceLevel {
+	converted := make([]*exchangev1.PriceLevel, 0, len(levels))
+	for _, lvl := range levels {
+		converted = append(converted, &exchangev1.PriceLevel{
+			Price:      lvl.Price,
+			Quantity:   lvl.Quantity,
+			OrderCount: int32(lvl.OrderCount),
+		})
+	}
+	return converted
+}
+
+func tickerToProto(ticker marketdata.Ticker) *exchangev1.GetTickerResponse {
+	return &exchangev1.GetTickerResponse{
+		Symbol:             ticker.Symbol,
+		LastPrice:          ticker.LastPrice,
+		BidPrice:           ticker.BidPrice,
+		BidQuantity:        ticker.BidQuantity,
+		AskPrice:           ticker.AskPrice,
+		AskQuantity:        ticker.AskQuantity,
+		OpenPrice:          ticker.OpenPrice,
+		HighPrice:          ticker.HighPrice,
+		LowPrice:           ticker.LowPrice,
+		PriceChange:        ticker.PriceChange,
+		PriceChangePercent: ticker.PriceChangePercent,
+		WeightedAvgPrice:   ticker.WeightedAvgPrice,
+		Volume:             ticker.Volume,
+		QuoteVolume:        ticker.QuoteVolume,
+		TradeCount:         int32(ticker.TradeCount),
+		LastTradeTimeMs:    unixMillis(ticker.LastTradeAt),
+		WindowStartMs:      unixMillis(ticker.WindowStart),
+		WindowEndMs:        unixMillis(ticker.WindowEnd),
+	}
+}
+
+func candlesToProto(candles []marketdata.Candle) []*exchangev1.Candle {
+	converted := make([]*exchangev1.Candle, 0, len(candles))
+	for _, candle := range candles {
+		converted = append(converted, &exchangev1.Candle{
+			OpenTimeMs:  unixMillis(candle.OpenTime),
+			CloseTimeMs: unixMillis(candle.CloseTime),
+			Open:        candle.Open,
+			High:        candle.High,
+			Low:         candle.Low,
+			Close:       candle.Close,
+			Volume:      candle.Volume,
+			QuoteVolume: candle.QuoteVolume,
+			TradeCount:  int32(candle.TradeCount),
+		})
+	}
+	return converted
+}
+
+func balancesToProto(positions []ledger.Position) []*exchangev1.Balance {
+	converted := make([]*exchangev1.Balance, 0, len(positions))
+	for _, position := range positions {
+		converted = append(converted, &exchangev1.Balance{
+			Asset:     position.Asset,
+			Quantity:  position.Quantity,
+			Received:  position.Received,
+			Delivered: position.Delivered,
+			Opening:   position.Opening,
+		})
+	}
+	return converted
+}
+
+func accountBalancesToProto(balances []ledger.Balance) []*exchangev1.AccountBalance {
+	converted := make([]*exchangev1.AccountBalance, 0, len(balances))
+	for _, balance := range balances {
+		converted = append(converted, &exchangev1.AccountBalance{
+			Asset:     balance.Asset,
+			Available: balance.Available,
+			Held:      balance.Held,
+			Total:     balance.Total,
+		})
+	}
+	return converted
+}
+
+func positionsToProto(positions []margin.Position) []*exchangev1.Position {
+	converted := make([]*exchangev1.Position, 0, len(positions))
+	for _, position := range positions {
+		converted = append(converted, &exchangev1.Position{
+			Symbol:        position.Symbol,
+			Quantity:      position.Quantity,
+			EntryPrice:    position.EntryPrice,
+			MarkPrice:     position.MarkPrice,
+			UnrealizedPnl: position.UnrealizedPnL,
+			RealizedPnl:   position.RealizedPnL,
+		})
+	}
+	return converted
+}
+
+func accountPositionsToProto(positions []services.AccountPosition) []*exchangev1.Position {
+	converted := make([]*exchangev1.Position, 0, len(positions))
+	for _, position := range positions {
+		converted = append(converted, &exchangev1.Position{
+			AccountId:     position.AccountID,
+			Symbol:        position.Symbol,
+			Asset:         position.Asset,
+			Quantity:      position.Quantity,
+			EntryPrice:    position.EntryPrice,
+			MarkPrice:     position.MarkPrice,
+			UnrealizedPnl: position.UnrealizedPnL,
+			RealizedPnl:   position.RealizedPnL,
+			UpdatedTimeMs: unixMillis(position.UpdatedAt),
+		})
+	}
+	return converted
+}
+
+// rejectionToProto maps a reason by name; reasons without a proto value are UNSPECIFIED
+func rejectionToProto(rejection services.Rejection) *exchangev1.Rejection {
+	return &exchangev1.Rejection{
+		Reason:  exchangev1.RejectReason(exchangev1.RejectReason_value["REJECT_REASON_"+string(rejection.Reason)]),
+		Message: rejection.Message,
+	}
+}
+
+// sessionEventToProto fills every field but the event type
+func orderAckToProto(ack feed.OrderAck) *exchangev1.OrderAck {
+	converted := &exchangev1.OrderAck{
+		RequestSequence: ack.RequestSequence,
+		AccountId:       ack.AccountID,
+		ClientOrderId:   ack.ClientOrderID,
+		Symbol:          ack.Symbol,
+		OrderId:         ack.OrderID,
+		Accepted:        ack.Accepted,
+	}
+	if !ack.Accepted {
+		converted.Rejection = rejectionToProto(services.Rejection{Reason: services.RejectReason(ack.Reason), Message: ack.Message})
+	}
+	return converted
+}
+
+func sessionEventToProto(session services.Session) *exchangev1.SessionEvent {
+	return &exchangev1.SessionEvent{
+		SessionId:          session.ID,
+		AccountId:          session.AccountID,
+		CancelOnDisconnect: session.CancelOnDisconnect,
+		GracePeriodMs:      session.GracePeriod.Milliseconds(),
+		TimestampMs:        time.Now().UnixMilli(),
+	}
+}
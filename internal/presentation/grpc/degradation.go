@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+)
+
+// ModeGate refuses what the venue's degradation mode does not serve
+type ModeGate interface {
+	Admit(op chaos.Operation) error
+}
+
+// DegradationInterceptor fails exchange RPCs the venue's degradation mode refuses
+// with UNAVAILABLE and ENGINE_UNAVAILABLE, streams once when opened. The chaos
+// service and health checks are always served.
+func DegradationInterceptor(gate ModeGate) ServiceInterceptor {
+	admit := func(fullMethod string) error {
+		if !strings.HasPrefix(fullMethod, "/exchange.v1.") ||
+			strings.HasPrefix(fullMethod, "/"+exchangev1.ChaosService_ServiceDesc.ServiceName+"/") {
+			return nil
+		}
+		if err := gate.Admit(methodOperation(fullMethod)); err != nil {
+			return statusFromError(err)
+		}
+		return nil
+	}
+	return ServiceInterceptor{
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := admit(info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := admit(info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		},
+	}
+}
+
+// methodOperation is what an exchange RPC does: lookups, dry runs and market data
+// streams read, CancelOrder cancels and the rest change the venue
+func methodOperation(fullMethod string) chaos.Operation {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	switch {
+	case method == "CancelOrder":
+		return chaos.OperationCancel
+	case method == "CheckOrder",
+		strings.HasPrefix(method, "Get"),
+		strings.HasPrefix(method, "List"),
+		strings.HasPrefix(method, "Stream"):
+		return chaos.OperationRead
+	}
+	return chaos.OperationChange
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// statusFromError converts a service error to a gRPC status with a Rejection detail
+func statusFromError(err error) error {
+	rejection := services.RejectionOf(err)
+
+	code := codes.InvalidArgument
+	switch rejection.Reason {
+	case services.RejectUnknownInstrument, services.RejectOrderNotFound:
+		code = codes.NotFound
+	case services.RejectOrderNotActive, services.RejectMarketClosed,
+		services.RejectInstrumentHalted, services.RejectInvalidPhase,
+		services.RejectInsufficientBalance, services.RejectInsufficientMargin:
+		code = codes.FailedPrecondition
+	case services.RejectDuplicateClientOrderID:
+		code = codes.AlreadyExists
+	case services.RejectIdempotencyKeyReused:
+		code = codes.FailedPrecondition
+	case services.RejectEngineUnavailable:
+		code = codes.Unavailable
+	case services.RejectSubscriptionLimit, services.RejectRateLimited:
+		code = codes.ResourceExhausted
+	case services.RejectPermissionDenied:
+		code = codes.PermissionDenied
+	case services.RejectUnauthenticated:
+		code = codes.Unauthenticated
+	case services.RejectUnknown:
+		code = codes.Internal
+	}
+
+	st, detailErr := status.New(code, rejection.Message).WithDetails(rejectionToProto(rejection))
+	if detailErr != nil {
+		return status.Error(code, rejection.Message)
+	}
+	return st.Err()
+}
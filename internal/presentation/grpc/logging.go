@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+)
+
+// RequestIDMetadata identifies one RPC in the logs; one is generated when the client
+// sends none, and returned in the response header either way
+const RequestIDMetadata = "x-request-id"
+
+// LoggingInterceptor tags each RPC with its request ID, and the account and order
+// IDs of requests naming them, so the services log them on every entry about the
+// call; then logs the call's outcome with the same IDs. It runs after
+// APIKeyInterceptor, so the outcome carries the correlation ID too.
+func LoggingInterceptor(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = tagRequest(ctx)
+		if named, ok := req.(interface{ GetAccountId() string }); ok {
+			ctx = logfields.WithAccountID(ctx, named.GetAccountId())
+		}
+		if named, ok := req.(interface{ GetOrderId() string }); ok {
+			ctx = logfields.WithOrderID(ctx, named.GetOrderId())
+		}
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor tags each stream with its request ID like
+// LoggingInterceptor, and logs how it ended
+func LoggingStreamInterceptor(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := tagRequest(stream.Context())
+		err := handler(srv, &taggedStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+// tagRequest tags ctx with the caller's request ID, or a new one, and returns it
+func tagRequest(ctx context.Context) context.Context {
+	requestID := audit.NewID()
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadata); len(values) > 0 && values[0] != "" {
+		requestID = values[0]
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, requestID))
+	return logfields.WithRequestID(ctx, requestID)
+}
+
+// logCall logs a finished call: failures the server is to blame for as warnings,
+// other failures as information and successes at debug level
+func logCall(ctx context.Context, logger *logrus.Logger, method string, duration time.Duration, err error) {
+	fields := logrus.Fields{
+		"method":            method,
+		"code":              status.Code(err).String(),
+		"duration":          duration,
+		logfields.Component: logfields.ComponentGRPC,
+	}
+	for name, value := range logfields.Fields(ctx) {
+		fields[name] = value
+	}
+	entry := logger.WithFields(fields)
+	switch status.Code(err) {
+	case codes.OK:
+		entry.Debug("gRPC call completed")
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		entry.WithError(err).Warn("gRPC call failed")
+	default:
+		entry.WithError(err).Info("gRPC call failed")
+	}
+}
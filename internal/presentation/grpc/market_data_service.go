@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// MarketDataServiceServer implements the exchange.v1.MarketDataService gRPC API. It
+// only reads public market data, so it is served without credentials.
+type MarketDataServiceServer struct {
+	exchangev1.UnimplementedMarketDataServiceServer
+
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewMarketDataServiceServer(exchangeService *services.ExchangeService, logger *logrus.Logger) *MarketDataServiceServer {
+	return &MarketDataServiceServer{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// GetOrderBook returns aggregated price levels for a symbol
+func (s *MarketDataServiceServer) GetOrderBook(ctx context.Context, req *exchangev1.GetOrderBookRequest) (*exchangev1.GetOrderBookResponse, error) {
+	snapshot, err := s.exchangeService.OrderBook(ctx, req.GetSymbol(), int(req.GetDepth()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetOrderBookResponse{
+		Symbol:   snapshot.Symbol,
+		Phase:    string(snapshot.Phase),
+		Bids:     priceLevelsToProto(snapshot.Bids),
+		Asks:     priceLevelsToProto(snapshot.Asks),
+		Sequence: snapshot.Sequence,
+	}, nil
+}
+
+// GetTicker returns a symbol's rolling 24h statistics and top of book
+func (s *MarketDataServiceServer) GetTicker(ctx context.Context, req *exchangev1.GetTickerRequest) (*exchangev1.GetTickerResponse, error) {
+	ticker, err := s.exchangeService.Ticker(ctx, req.GetSymbol())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return tickerToProto(ticker), nil
+}
+
+// GetCandles returns OHLCV bars for a symbol, merging archived and in-memory history
+func (s *MarketDataServiceServer) GetCandles(ctx context.Context, req *exchangev1.GetCandlesRequest) (*exchangev1.GetCandlesResponse, error) {
+	interval := marketdata.Interval1m
+	if req.GetInterval() != "" {
+		parsed, err := marketdata.ParseInterval(req.GetInterval())
+		if err != nil {
+			return nil, statusFromError(services.NewRejection(services.RejectInvalidRequest, err))
+		}
+		interval = parsed
+	}
+	query := services.CandleQuery{Symbol: req.GetSymbol(), Interval: interval, Limit: int(req.GetLimit())}
+	if req.GetStartTimeMs() > 0 {
+		query.Start = time.UnixMilli(req.GetStartTimeMs())
+	}
+	if req.GetEndTimeMs() > 0 {
+		query.End = time.UnixMilli(req.GetEndTimeMs())
+	}
+
+	candles, err := s.exchangeService.CandleHistory(ctx, query)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetCandlesResponse{
+		Symbol:   query.Symbol,
+		Interval: string(interval),
+		Candles:  candlesToProto(candles),
+	}, nil
+}
+
+// StreamOrderBook streams a book snapshot, then every change as the levels it moved,
+// until the client disconnects, falls behind or the venue shuts down. It counts as
+// one stream and one subscription against the caller's API key.
+func (s *MarketDataServiceServer) StreamOrderBook(req *exchangev1.StreamOrderBookRequest, stream exchangev1.MarketDataService_StreamOrderBookServer) error {
+	if req.GetDepth() < 0 {
+		return statusFromError(services.NewRejection(services.RejectInvalidRequest, errors.New("depth must not be negative")))
+	}
+	lease, err := s.exchangeService.OpenStream(stream.Context())
+	if err != nil {
+		return statusFromError(err)
+	}
+	defer lease.Close()
+	sub := s.exchangeService.Feed().Subscribe()
+	defer sub.Close()
+	if err := s.exchangeService.SubscribeStream(stream.Context(), lease, sub, feed.Topic{Channel: feed.ChannelBook, Key: req.GetSymbol()}); err != nil {
+		return statusFromError(err)
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-sub.Messages():
+			update := &exchangev1.OrderBookUpdate{
+				Symbol:      msg.Key,
+				Sequence:    msg.Sequence,
+				Snapshot:    msg.Type == feed.MessageSnapshot,
+				TimestampMs: unixMillis(msg.Time),
+			}
+			switch data := msg.Data.(type) {
+			case matching.BookSnapshot:
+				depth := int(req.GetDepth())
+				update.Phase, update.Bids, update.Asks = string(data.Phase), priceLevelsToProto(topLevels(data.Bids, depth)), priceLevelsToProto(topLevels(data.Asks, depth))
+			case feed.BookDelta:
+				update.Phase, update.Bids, update.Asks = string(data.Phase), priceLevelsToProto(data.Bids), priceLevelsToProto(data.Asks)
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case <-sub.Done():
+			return streamStatus(sub.Err())
+		}
+	}
+}
+
+// topLevels keeps the best depth levels of a side; zero keeps all
+func topLevels(levels []matching.PriceLevel, depth int) []matching.PriceLevel {
+	if depth > 0 && len(levels) > depth {
+		return levels[:depth]
+	}
+	return levels
+}
@@ -0,0 +1,149 @@
+//go:build unit
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+)
+
+// bookStream collects the updates StreamOrderBook sends
+type bookStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	updates chan *exchangev1.OrderBookUpdate
+}
+
+func (s *bookStream) Context() context.Context { return s.ctx }
+
+func (s *bookStream) Send(update *exchangev1.OrderBookUpdate) error {
+	s.updates <- update
+	return nil
+}
+
+// localBook is a client's copy of one side of a book, by price
+type localBook map[float64]*exchangev1.PriceLevel
+
+func (b localBook) apply(levels []*exchangev1.PriceLevel) {
+	for _, level := range levels {
+		if level.GetQuantity() == 0 {
+			delete(b, level.GetPrice())
+			continue
+		}
+		b[level.GetPrice()] = level
+	}
+}
+
+func (b localBook) matches(levels []*exchangev1.PriceLevel) bool {
+	if len(b) != len(levels) {
+		return false
+	}
+	for _, level := range levels {
+		local, exists := b[level.GetPrice()]
+		if !exists || local.GetQuantity() != level.GetQuantity() || local.GetOrderCount() != level.GetOrderCount() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMarketDataServiceServer_StreamOrderBook(t *testing.T) {
+	place := func(t *testing.T, trading *TradingServiceServer, side exchangev1.Side, quantity, price float64) {
+		t.Helper()
+		_, err := trading.PlaceOrder(context.Background(), &exchangev1.PlaceOrderRequest{Order: &exchangev1.OrderSpec{
+			AccountId: "acct-1", Symbol: "BTC-USD", Side: side, Quantity: quantity, Price: price,
+		}})
+		if err != nil {
+			t.Fatalf("Expected order to be placed, got %v", err)
+		}
+	}
+
+	t.Run("snapshot_and_updates_keep_a_local_book_in_sync", func(t *testing.T) {
+		// Given: A book with a level on each side, streamed from a snapshot
+		trading, server, _ := newTestServers()
+		place(t, trading, exchangev1.Side_SIDE_BUY, 1, 59990)
+		place(t, trading, exchangev1.Side_SIDE_SELL, 1, 60010)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := &bookStream{ctx: ctx, updates: make(chan *exchangev1.OrderBookUpdate, 16)}
+		done := make(chan error, 1)
+		go func() { done <- server.StreamOrderBook(&exchangev1.StreamOrderBookRequest{Symbol: "BTC-USD"}, stream) }()
+		snapshot := <-stream.updates
+		if !snapshot.GetSnapshot() || len(snapshot.GetBids()) != 1 || len(snapshot.GetAsks()) != 1 {
+			t.Fatalf("Expected a snapshot with both levels, got %+v", snapshot)
+		}
+		bids, asks := localBook{}, localBook{}
+		bids.apply(snapshot.GetBids())
+		asks.apply(snapshot.GetAsks())
+
+		// When: A level is added, another joined and the ask is lifted
+		place(t, trading, exchangev1.Side_SIDE_BUY, 2, 59980)
+		place(t, trading, exchangev1.Side_SIDE_BUY, 0.5, 59990)
+		place(t, trading, exchangev1.Side_SIDE_BUY, 1, 60010)
+
+		// Then: Each update follows the last with no gap
+		sequence := snapshot.GetSequence()
+		for i := 0; i < 3; i++ {
+			update := <-stream.updates
+			if update.GetSnapshot() || update.GetSequence() != sequence+1 {
+				t.Fatalf("Expected update %d after sequence %d, got %+v", i+1, sequence, update)
+			}
+			sequence = update.GetSequence()
+			bids.apply(update.GetBids())
+			asks.apply(update.GetAsks())
+		}
+
+		// And: The local book matches a fresh snapshot at the same sequence
+		book, err := server.GetOrderBook(context.Background(), &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"})
+		if err != nil {
+			t.Fatalf("Expected the book, got %v", err)
+		}
+		if book.GetSequence() != sequence {
+			t.Errorf("Expected the snapshot at sequence %d, got %d", sequence, book.GetSequence())
+		}
+		if !bids.matches(book.GetBids()) || !asks.matches(book.GetAsks()) || len(asks) != 0 {
+			t.Errorf("Expected the local book to match %+v, got bids %v asks %v", book, bids, asks)
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected the stream to end cleanly, got %v", err)
+		}
+	})
+
+	t.Run("snapshots_taken_without_subscribers_name_one_book_state", func(t *testing.T) {
+		// Given: A snapshot taken while nobody streams the book
+		trading, server, _ := newTestServers()
+		place(t, trading, exchangev1.Side_SIDE_BUY, 1, 59990)
+		first, _ := server.GetOrderBook(context.Background(), &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"})
+		again, _ := server.GetOrderBook(context.Background(), &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"})
+
+		// When: The book changes unstreamed and is snapshotted again
+		place(t, trading, exchangev1.Side_SIDE_BUY, 1, 59980)
+		second, err := server.GetOrderBook(context.Background(), &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD", Depth: 1})
+
+		// Then: The unchanged book kept its sequence and the changed one moved on
+		if err != nil || again.GetSequence() != first.GetSequence() || second.GetSequence() <= first.GetSequence() {
+			t.Errorf("Expected sequences %d, %d then higher, got %d (%v)", first.GetSequence(), again.GetSequence(), second.GetSequence(), err)
+		}
+		if len(second.GetBids()) != 1 || second.GetBids()[0].GetPrice() != 59990 {
+			t.Errorf("Expected only the best bid at depth 1, got %+v", second.GetBids())
+		}
+	})
+
+	t.Run("unknown_symbol_is_not_found", func(t *testing.T) {
+		_, server, _ := newTestServers()
+		stream := &bookStream{ctx: context.Background(), updates: make(chan *exchangev1.OrderBookUpdate, 1)}
+
+		err := server.StreamOrderBook(&exchangev1.StreamOrderBookRequest{Symbol: "NOPE"}, stream)
+
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+}
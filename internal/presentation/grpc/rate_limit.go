@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+)
+
+// Response metadata reporting the caller's tightest rate limit bucket, as the REST
+// API's X-RateLimit-* headers do
+const (
+	RateLimitLimitMetadata     = "x-ratelimit-limit"
+	RateLimitRemainingMetadata = "x-ratelimit-remaining"
+	RateLimitResetMetadata     = "x-ratelimit-reset"
+	RetryAfterMetadata         = "retry-after"
+)
+
+// RequestCharger spends a request's weight from its caller's rate limits
+type RequestCharger interface {
+	ChargeRequest(ctx context.Context, clientIP, endpoint string) (ratelimit.Decision, error)
+}
+
+// RateLimitInterceptor charges each exchange RPC, and each stream once when opened,
+// the weight of its full method name, failing it with RESOURCE_EXHAUSTED and
+// RATE_LIMITED beyond the caller's limit. Chain it after the API key and signature
+// interceptors, so a key is charged only once it is verified.
+func RateLimitInterceptor(charger RequestCharger) ServiceInterceptor {
+	charge := func(ctx context.Context, fullMethod string) (metadata.MD, error) {
+		if !strings.HasPrefix(fullMethod, "/exchange.v1.") {
+			return nil, nil
+		}
+		decision, err := charger.ChargeRequest(ctx, peerIP(ctx), fullMethod)
+		header := metadata.MD{}
+		if decision.Scope != "" {
+			header.Set(RateLimitLimitMetadata, strconv.Itoa(decision.Limit))
+			header.Set(RateLimitRemainingMetadata, strconv.Itoa(decision.Remaining))
+			header.Set(RateLimitResetMetadata, strconv.Itoa(int(math.Ceil(decision.Reset.Seconds()))))
+		}
+		if err != nil {
+			header.Set(RetryAfterMetadata, strconv.Itoa(int(math.Max(1, math.Ceil(decision.RetryAfter.Seconds())))))
+			return header, statusFromError(err)
+		}
+		return header, nil
+	}
+	return ServiceInterceptor{
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			header, err := charge(ctx, info.FullMethod)
+			if len(header) > 0 {
+				grpc.SetHeader(ctx, header)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			header, err := charge(stream.Context(), info.FullMethod)
+			if len(header) > 0 {
+				stream.SetHeader(header)
+			}
+			if err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		},
+	}
+}
+
+// peerIP is the caller's address without its port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -9,10 +9,15 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
 )
 
@@ -22,9 +27,12 @@ type ExchangeGRPCServer struct {
 	logger          *logrus.Logger
 
 	// Server management
-	grpcServer   *grpc.Server
-	healthServer *health.Server
-	listener     net.Listener
+	credentials    credentials.TransportCredentials // Plaintext when nil
+	startup        *startup.Coordinator             // Health is NOT_SERVING until it serves; nil serves at once
+	grpcServer     *grpc.Server
+	healthServer   *health.Server
+	healthServices []string // Reported by the health service, "" being the server as a whole
+	listener       net.Listener
 
 	// Metrics and monitoring
 	startTime         time.Time
@@ -58,6 +66,20 @@ func NewExchangeGRPCServer(cfg *config.Config, exchangeService *services.Exchang
 	}
 }
 
+// WithCredentials secures the server's connections, e.g. with the TLS or mTLS
+// credentials GRPC_TLS_MODE calls for; it must be called before Start
+func (s *ExchangeGRPCServer) WithCredentials(creds credentials.TransportCredentials) *ExchangeGRPCServer {
+	s.credentials = creds
+	return s
+}
+
+// WithStartup reports the health service NOT_SERVING until coordinator is
+// serving; it must be called before Start
+func (s *ExchangeGRPCServer) WithStartup(coordinator *startup.Coordinator) *ExchangeGRPCServer {
+	s.startup = coordinator
+	return s
+}
+
 func (s *ExchangeGRPCServer) Start(ctx context.Context) error {
 	// Create listener
 	address := fmt.Sprintf(":%d", s.config.GRPCPort)
@@ -67,18 +89,66 @@ func (s *ExchangeGRPCServer) Start(ctx context.Context) error {
 	}
 	s.listener = listener
 
-	// Create gRPC server with enhanced options
+	// Trading calls need a key and chaos calls the admin token; the venue's
+	// degradation mode, request signatures and rate limits apply to every call, limits
+	// last so only verified keys are charged
+	tradingKeys := ParseAPIKeys(s.config.GRPCTradingAPIKeys)
+	if len(tradingKeys) == 0 {
+		s.logger.Warn("GRPC_TRADING_API_KEYS is empty: the gRPC TradingService accepts unauthenticated calls")
+	}
+	if s.config.ChaosEnabled && s.config.AdminToken == "" {
+		s.logger.Warn("ADMIN_TOKEN not set; the gRPC ChaosService is open to any caller")
+	}
+	interceptors := NewServiceInterceptors()
+	interceptors.Register(exchangev1.TradingService_ServiceDesc.ServiceName, RequireAPIKey(tradingKeys))
+	interceptors.Register(exchangev1.ChaosService_ServiceDesc.ServiceName, RequireAdminToken(s.config.AdminToken))
+	signatures := SignatureInterceptor(s.exchangeService)
+	rateLimits := RateLimitInterceptor(s.exchangeService)
+	degradation := DegradationInterceptor(s.exchangeService)
+	creds := s.credentials
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	unary := []grpc.UnaryServerInterceptor{s.unaryInterceptor, interceptors.Unary(), APIKeyInterceptor(s.exchangeService.KeyStatistics()), LoggingInterceptor(s.logger), degradation.Unary, signatures.Unary, rateLimits.Unary}
+	stream := []grpc.StreamServerInterceptor{interceptors.Stream(), APIKeyStreamInterceptor(s.exchangeService.KeyStatistics()), LoggingStreamInterceptor(s.logger), degradation.Stream, signatures.Stream, rateLimits.Stream}
+	// RED metrics come first, so calls the other interceptors reject are counted too
+	if metricsPort := s.config.GetMetricsPort(); metricsPort != nil {
+		unary = append([]grpc.UnaryServerInterceptor{observability.REDMetricsUnaryInterceptor(metricsPort)}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{observability.REDMetricsStreamInterceptor(metricsPort)}, stream...)
+	}
+	// Tracing wraps everything else, so the span covers the whole call
+	if tracingPort := s.config.GetTracingPort(); tracingPort != nil {
+		unary = append([]grpc.UnaryServerInterceptor{observability.TracingUnaryInterceptor(tracingPort)}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{observability.TracingStreamInterceptor(tracingPort)}, stream...)
+	}
 	s.grpcServer = grpc.NewServer(
-		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	)
 
 	// Setup health service
 	s.healthServer = health.NewServer()
 	grpc_health_v1.RegisterHealthServer(s.grpcServer, s.healthServer)
 
-	// Set initial health status
-	s.healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
-	s.healthServer.SetServingStatus("exchange-simulator", grpc_health_v1.HealthCheckResponse_SERVING)
+	// Register the authenticated trading API and the public market data API
+	exchangev1.RegisterTradingServiceServer(s.grpcServer, NewTradingServiceServer(s.exchangeService, s.logger))
+	exchangev1.RegisterMarketDataServiceServer(s.grpcServer, NewMarketDataServiceServer(s.exchangeService, s.logger))
+	s.healthServices = []string{"", "exchange-simulator", exchangev1.TradingService_ServiceDesc.ServiceName, exchangev1.MarketDataService_ServiceDesc.ServiceName}
+	// Fault injection is only served when enabled
+	if s.config.ChaosEnabled {
+		exchangev1.RegisterChaosServiceServer(s.grpcServer, NewChaosServiceServer(s.exchangeService, s.logger))
+		s.healthServices = append(s.healthServices, exchangev1.ChaosService_ServiceDesc.ServiceName)
+	}
+
+	// Set initial health status: NOT_SERVING until startup finishes
+	if s.startup != nil {
+		s.setServingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		s.startup.OnServing(func() { s.setServingStatus(grpc_health_v1.HealthCheckResponse_SERVING) })
+	}
+	if s.startup == nil || s.startup.Ready() {
+		s.setServingStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+	}
 
 	s.isRunning = true
 	s.logger.WithFields(logrus.Fields{
@@ -111,12 +181,12 @@ func (s *ExchangeGRPCServer) Stop(ctx context.Context) error {
 
 	// Update health status
 	if s.healthServer != nil {
-		s.healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
-		s.healthServer.SetServingStatus("exchange-simulator", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		s.setServingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
 	}
 
 	// Signal shutdown
 	close(s.stopChan)
+	s.exchangeService.ShutdownSessions()
 
 	// Graceful stop with timeout
 	done := make(chan struct{})
@@ -142,6 +212,13 @@ func (s *ExchangeGRPCServer) Stop(ctx context.Context) error {
 	return nil
 }
 
+// setServingStatus reports every health service as status
+func (s *ExchangeGRPCServer) setServingStatus(status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	for _, service := range s.healthServices {
+		s.healthServer.SetServingStatus(service, status)
+	}
+}
+
 func (s *ExchangeGRPCServer) GetMetrics() ExchangeServerMetrics {
 	s.metricsLock.RLock()
 	defer s.metricsLock.RUnlock()
@@ -168,46 +245,20 @@ func (s *ExchangeGRPCServer) GetHealthStatus() grpc_health_v1.HealthCheckRespons
 	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
 }
 
-// Unary interceptor for metrics and logging
+// Unary interceptor for metrics; LoggingInterceptor logs each call
 func (s *ExchangeGRPCServer) unaryInterceptor(
 	ctx context.Context,
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	start := time.Now()
-
 	// Update metrics
 	s.metricsLock.Lock()
 	s.requestCount++
-	s.lastRequestTime = start
+	s.lastRequestTime = time.Now()
 	s.metricsLock.Unlock()
 
-	// Log request
-	s.logger.WithFields(logrus.Fields{
-		"method":    info.FullMethod,
-		"timestamp": start,
-	}).Debug("gRPC request received")
-
-	// Handle request
-	resp, err := handler(ctx, req)
-
-	// Log response
-	duration := time.Since(start)
-	logFields := logrus.Fields{
-		"method":   info.FullMethod,
-		"duration": duration,
-		"success":  err == nil,
-	}
-
-	if err != nil {
-		logFields["error"] = err.Error()
-		s.logger.WithFields(logFields).Warn("gRPC request failed")
-	} else {
-		s.logger.WithFields(logFields).Debug("gRPC request completed")
-	}
-
-	return resp, err
+	return handler(ctx, req)
 }
 
 // IsRunning returns the current running status
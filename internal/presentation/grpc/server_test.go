@@ -4,15 +4,28 @@ package grpc
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/startup"
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
 )
 
@@ -94,6 +107,50 @@ func TestExchangeGRPCServer_HealthService(t *testing.T) {
 			t.Error("Expected IsRunning() to return true")
 		}
 	})
+
+	t.Run("not_serving_until_startup_serves", func(t *testing.T) {
+		// Given: A server started while startup has not finished
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test"}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		coordinator := startup.NewCoordinator(time.Second, time.Millisecond, time.Second, logger)
+		server := NewExchangeGRPCServer(cfg, services.NewExchangeService(cfg, logger), logger).WithStartup(coordinator)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+		healthClient := grpc_health_v1.NewHealthClient(conn)
+		trading := &grpc_health_v1.HealthCheckRequest{Service: exchangev1.TradingService_ServiceDesc.ServiceName}
+
+		// When: Health is checked before and after startup serves
+		before, err := healthClient.Check(ctx, trading)
+		if err != nil {
+			t.Fatalf("Health check failed: %v", err)
+		}
+		if err := coordinator.Run(ctx); err != nil {
+			t.Fatalf("Expected startup to succeed, got %v", err)
+		}
+		coordinator.Serve()
+		after, err := healthClient.Check(ctx, trading)
+		if err != nil {
+			t.Fatalf("Health check failed: %v", err)
+		}
+
+		// Then: It is NOT_SERVING, then SERVING
+		if before.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("Expected NOT_SERVING during startup, got %v", before.Status)
+		}
+		if after.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Errorf("Expected SERVING once started, got %v", after.Status)
+		}
+	})
 }
 
 func TestExchangeGRPCServer_ExchangeService(t *testing.T) {
@@ -251,4 +308,314 @@ func TestExchangeGRPCServer_Metrics(t *testing.T) {
 			t.Errorf("Expected non-negative uptime, got %d", metrics.UptimeSeconds)
 		}
 	})
-}
\ No newline at end of file
+
+	t.Run("records_red_metrics_through_the_metrics_port", func(t *testing.T) {
+		// Given: A running server with a metrics port
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test", GRPCPort: 0}
+		metricsPort := observability.NewPrometheusMetricsAdapter(map[string]string{"service": "exchange-simulator"})
+		cfg.SetMetricsPort(metricsPort)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		server := NewExchangeGRPCServer(cfg, services.NewExchangeService(cfg, logger), logger)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+
+		// When: A call is made
+		if _, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{}); err != nil {
+			t.Fatalf("Health check failed: %v", err)
+		}
+
+		// Then: It is counted by method and code
+		w := httptest.NewRecorder()
+		metricsPort.GetHTTPHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if !strings.Contains(w.Body.String(), `grpc_requests_total{code="OK",method="/grpc.health.v1.Health/Check"`) {
+			t.Errorf("Expected the health check in grpc_requests_total, got %s", w.Body.String())
+		}
+	})
+}
+func TestExchangeGRPCServer_CancelOnDisconnect(t *testing.T) {
+	t.Run("cancels_open_orders_after_the_stream_drops", func(t *testing.T) {
+		// Given: A running server and an account holding a cancel-on-disconnect session
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test"}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(cfg, logger)
+		server := NewExchangeGRPCServer(cfg, exchangeService, logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+
+		streamCtx, dropStream := context.WithCancel(ctx)
+		stream, err := exchangev1.NewTradingServiceClient(conn).OpenSession(streamCtx, &exchangev1.OpenSessionRequest{
+			AccountId:          "mm-1",
+			CancelOnDisconnect: true,
+			GracePeriodMs:      20,
+		})
+		if err != nil {
+			t.Fatalf("Failed to open session: %v", err)
+		}
+		opened, err := stream.Recv()
+		if err != nil || opened.GetType() != exchangev1.SessionEventType_SESSION_EVENT_TYPE_OPENED {
+			t.Fatalf("Expected an opened event, got %v (%v)", opened, err)
+		}
+		report, err := exchangeService.PlaceOrder(ctx, services.OrderRequest{
+			AccountID: "mm-1", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000,
+		})
+		if err != nil {
+			t.Fatalf("Failed to place order: %v", err)
+		}
+
+		// When: The client drops the stream
+		dropStream()
+
+		// Then: The order is canceled once the grace period passes
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			order, _ := exchangeService.GetOrder(ctx, report.Order.ID)
+			if order.Status == models.OrderStatusCanceled {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected the order to be canceled on disconnect, status %s", order.Status)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if sessions := exchangeService.Sessions(ctx); len(sessions) != 0 {
+			t.Errorf("Expected no open sessions, got %+v", sessions)
+		}
+	})
+}
+
+func TestExchangeGRPCServer_Authentication(t *testing.T) {
+	t.Run("trading_needs_a_key_and_market_data_does_not", func(t *testing.T) {
+		// Given: A running server admitting one trading key
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test", GRPCTradingAPIKeys: "bot-1, bot-2"}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		server := NewExchangeGRPCServer(cfg, services.NewExchangeService(cfg, logger), logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+		trading := exchangev1.NewTradingServiceClient(conn)
+		order := &exchangev1.PlaceOrderRequest{Order: &exchangev1.OrderSpec{
+			AccountId: "acct-1", Symbol: "BTC-USD", Side: exchangev1.Side_SIDE_BUY, Quantity: 0.1, Price: 59000,
+		}}
+
+		// When: Trading calls arrive without a key, with an unknown key and with a configured one
+		_, missing := trading.PlaceOrder(ctx, order)
+		_, unknown := trading.PlaceOrder(metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, "bot-9"), order)
+		_, allowed := trading.PlaceOrder(metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, "bot-2"), order)
+		updates, err := trading.StreamOrderUpdates(ctx, &exchangev1.StreamOrderUpdatesRequest{})
+		if err == nil {
+			_, err = updates.Recv()
+		}
+
+		// Then: Only the configured key trades, streams included
+		if status.Code(missing) != codes.Unauthenticated || status.Code(unknown) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated without a valid key, got %v and %v", missing, unknown)
+		}
+		if allowed != nil {
+			t.Errorf("Expected the configured key to trade, got %v", allowed)
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected an unauthenticated stream to fail, got %v", err)
+		}
+
+		// And: Market data is served without credentials
+		book, err := exchangev1.NewMarketDataServiceClient(conn).GetOrderBook(ctx, &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"})
+		if err != nil || len(book.GetBids()) != 1 {
+			t.Errorf("Expected the public book with the bid, got %+v (%v)", book, err)
+		}
+	})
+
+	t.Run("chaos_needs_the_admin_token_not_a_trading_key", func(t *testing.T) {
+		// Given: A running server with chaos enabled, a trading key and an admin token
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test", GRPCTradingAPIKeys: "bot-1", AdminToken: "ops-secret", ChaosEnabled: true}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		server := NewExchangeGRPCServer(cfg, services.NewExchangeService(cfg, logger), logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+		chaosClient := exchangev1.NewChaosServiceClient(conn)
+
+		// When: Faults are listed with a trading key, a wrong token and the admin token
+		_, withKey := chaosClient.ListFaults(metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, "bot-1"), &exchangev1.ListFaultsRequest{})
+		_, wrongToken := chaosClient.ListFaults(metadata.AppendToOutgoingContext(ctx, AdminTokenMetadata, "Bearer bot-1"), &exchangev1.ListFaultsRequest{})
+		_, withToken := chaosClient.ListFaults(metadata.AppendToOutgoingContext(ctx, AdminTokenMetadata, "Bearer ops-secret"), &exchangev1.ListFaultsRequest{})
+
+		// Then: Only the admin token is admitted
+		if status.Code(withKey) != codes.Unauthenticated || status.Code(wrongToken) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated without the admin token, got %v and %v", withKey, wrongToken)
+		}
+		if status.Code(withToken) == codes.Unauthenticated {
+			t.Errorf("Expected the admin token admitted, got %v", withToken)
+		}
+	})
+
+	t.Run("forged_signatures_do_not_spend_the_key_owners_limit", func(t *testing.T) {
+		// Given: A running server limiting each account to two calls, and an account
+		// holding an issued key
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test", RateLimitWeight: 2}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(cfg, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		issued, err := exchangeService.CreateAPIKey(context.Background(), account.ID, services.NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionRead}})
+		if err != nil {
+			t.Fatalf("Failed to issue key: %v", err)
+		}
+		server := NewExchangeGRPCServer(cfg, exchangeService, logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+		marketData := exchangev1.NewMarketDataServiceClient(conn)
+		method := "/exchange.v1.MarketDataService/GetOrderBook"
+		request := &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"}
+		call := func(signature string) error {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			if signature == "" {
+				signature = signRequest(issued.Secret, timestamp, method, request)
+			}
+			signed := metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, issued.ID, TimestampMetadata, timestamp, SignatureMetadata, signature)
+			_, err := marketData.GetOrderBook(signed, request)
+			return err
+		}
+
+		// When: Another caller names the key with forged signatures, then the owner calls
+		forged := []error{call(strings.Repeat("ab", 32)), call(strings.Repeat("ab", 32)), call(strings.Repeat("ab", 32))}
+		owner := call("")
+
+		// Then: The forgeries fail authentication without being charged, so the owner
+		// still has its limit
+		for i, err := range forged {
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Expected forged call %d Unauthenticated, got %v", i+1, err)
+			}
+		}
+		if owner != nil {
+			t.Errorf("Expected the owner's call within its limit, got %v", owner)
+		}
+	})
+
+	t.Run("signatures_cover_the_request_message", func(t *testing.T) {
+		// Given: A running server and an account holding an issued key
+		cfg := &config.Config{ServiceName: "exchange-simulator", ServiceVersion: "test"}
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		exchangeService := services.NewExchangeService(cfg, logger)
+		account, _ := exchangeService.CreateAccount(context.Background(), services.NewAccountRequest{})
+		issued, err := exchangeService.CreateAPIKey(context.Background(), account.ID, services.NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionRead}})
+		if err != nil {
+			t.Fatalf("Failed to issue key: %v", err)
+		}
+		server := NewExchangeGRPCServer(cfg, exchangeService, logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("Failed to start server: %v", err)
+		}
+		defer server.Stop(ctx)
+
+		conn, err := grpc.Dial(server.GetAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to connect to server: %v", err)
+		}
+		defer conn.Close()
+		marketData := exchangev1.NewMarketDataServiceClient(conn)
+		trading := exchangev1.NewTradingServiceClient(conn)
+		signed := func(method string, req proto.Message) context.Context {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			return metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, issued.ID, TimestampMetadata, timestamp,
+				SignatureMetadata, signRequest(issued.Secret, timestamp, method, req))
+		}
+		openTrades := func(signedFor *exchangev1.StreamTradesRequest) error {
+			streamCtx, stop := context.WithTimeout(signed("/exchange.v1.TradingService/StreamTrades", signedFor), 200*time.Millisecond)
+			defer stop()
+			stream, err := trading.StreamTrades(streamCtx, &exchangev1.StreamTradesRequest{AccountId: account.ID})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}
+
+		// When: A signed call and stream are made as signed, and again with their
+		// signatures replayed over other requests
+		bookMethod := "/exchange.v1.MarketDataService/GetOrderBook"
+		_, asSigned := marketData.GetOrderBook(signed(bookMethod, &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"}), &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"})
+		_, replayed := marketData.GetOrderBook(signed(bookMethod, &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD"}), &exchangev1.GetOrderBookRequest{Symbol: "ETH-USD"})
+		streamAsSigned := openTrades(&exchangev1.StreamTradesRequest{AccountId: account.ID})
+		streamReplayed := openTrades(&exchangev1.StreamTradesRequest{AccountId: "someone-else"})
+
+		// Then: Only the requests that were signed are admitted
+		if asSigned != nil {
+			t.Errorf("Expected the signed call admitted, got %v", asSigned)
+		}
+		if status.Code(replayed) != codes.Unauthenticated {
+			t.Errorf("Expected the replayed call Unauthenticated, got %v", replayed)
+		}
+		if status.Code(streamAsSigned) != codes.DeadlineExceeded {
+			t.Errorf("Expected the signed stream to stay open, got %v", streamAsSigned)
+		}
+		if status.Code(streamReplayed) != codes.Unauthenticated {
+			t.Errorf("Expected the replayed stream Unauthenticated, got %v", streamReplayed)
+		}
+	})
+}
+
+// signRequest signs an exchange RPC as a client holding an issued key does
+func signRequest(secret, timestamp, method string, req proto.Message) string {
+	body, _ := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	return accounts.Key{Secret: secret}.Sign(accounts.SigningPayload(timestamp, "", method, body))
+}
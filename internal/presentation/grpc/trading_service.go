@@ -0,0 +1,400 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+// TradingServiceServer implements the exchange.v1.TradingService gRPC API
+type TradingServiceServer struct {
+	exchangev1.UnimplementedTradingServiceServer
+
+	exchangeService *services.ExchangeService
+	logger          *logrus.Logger
+}
+
+func NewTradingServiceServer(exchangeService *services.ExchangeService, logger *logrus.Logger) *TradingServiceServer {
+	return &TradingServiceServer{
+		exchangeService: exchangeService,
+		logger:          logger,
+	}
+}
+
+// PlaceOrder validates and submits an order, returning it with any trades it executed
+func (s *TradingServiceServer) PlaceOrder(ctx context.Context, req *exchangev1.PlaceOrderRequest) (*exchangev1.PlaceOrderResponse, error) {
+	if req.GetOrder() == nil {
+		return nil, statusFromError(services.NewRejection(services.RejectInvalidRequest, errors.New("order is required")))
+	}
+
+	if req.GetDryRun() {
+		result, err := s.exchangeService.DryRunOrder(ctx, orderRequestFromProto(req.GetOrder()))
+		if err != nil {
+			return nil, statusFromError(err)
+		}
+		return &exchangev1.PlaceOrderResponse{
+			Order:     orderToProto(result.Order),
+			Trades:    tradesToProto(result.Trades),
+			Duplicate: result.Duplicate,
+			DryRun:    true,
+		}, nil
+	}
+
+	report, err := s.exchangeService.PlaceOrder(ctx, orderRequestFromProto(req.GetOrder()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.PlaceOrderResponse{
+		Order:     orderToProto(report.Order),
+		Trades:    tradesToProto(report.Trades),
+		Duplicate: report.Duplicate,
+	}, nil
+}
+
+// SubmitOrder receives an order for asynchronous placement, acked on StreamOrderUpdates
+func (s *TradingServiceServer) SubmitOrder(ctx context.Context, req *exchangev1.SubmitOrderRequest) (*exchangev1.SubmitOrderResponse, error) {
+	if req.GetOrder() == nil {
+		return nil, statusFromError(services.NewRejection(services.RejectInvalidRequest, errors.New("order is required")))
+	}
+
+	receipt, err := s.exchangeService.SubmitOrder(ctx, orderRequestFromProto(req.GetOrder()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.SubmitOrderResponse{
+		RequestSequence: receipt.RequestSequence,
+		ReceivedTimeMs:  unixMillis(receipt.ReceivedAt),
+	}, nil
+}
+
+// CancelOrder cancels a working order
+func (s *TradingServiceServer) CancelOrder(ctx context.Context, req *exchangev1.CancelOrderRequest) (*exchangev1.CancelOrderResponse, error) {
+	order, err := s.exchangeService.CancelOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.CancelOrderResponse{Order: orderToProto(order)}, nil
+}
+
+// GetOrder returns the current state of an order
+func (s *TradingServiceServer) GetOrder(ctx context.Context, req *exchangev1.GetOrderRequest) (*exchangev1.GetOrderResponse, error) {
+	order, err := s.exchangeService.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetOrderResponse{Order: orderToProto(order)}, nil
+}
+
+// ListOpenOrders lists working orders, optionally for one account and/or symbol
+func (s *TradingServiceServer) ListOpenOrders(ctx context.Context, req *exchangev1.ListOpenOrdersRequest) (*exchangev1.ListOpenOrdersResponse, error) {
+	orders, err := s.exchangeService.OpenOrders(ctx, req.GetAccountId(), req.GetSymbol())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.ListOpenOrdersResponse{Orders: ordersToProto(orders)}, nil
+}
+
+// GetTrades returns the latest executions, oldest first
+func (s *TradingServiceServer) GetTrades(ctx context.Context, req *exchangev1.GetTradesRequest) (*exchangev1.GetTradesResponse, error) {
+	trades, err := s.exchangeService.Trades(ctx, req.GetAccountId(), req.GetSymbol(), int(req.GetLimit()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetTradesResponse{Trades: tradesToProto(trades)}, nil
+}
+
+// History pages hold 100 orders or trades unless asked for more, up to 1000
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+// historyLimit applies the default page size and rejects sizes over the maximum
+func historyLimit(limit int32) (int, error) {
+	if limit == 0 {
+		return defaultHistoryLimit, nil
+	}
+	if limit < 0 || limit > maxHistoryLimit {
+		return 0, statusFromError(services.NewRejection(services.RejectInvalidRequest, fmt.Errorf("limit must be between 1 and %d", maxHistoryLimit)))
+	}
+	return int(limit), nil
+}
+
+// unixMillisTime maps 0 to the zero time, leaving a bound unset
+func unixMillisTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+// ListOrderHistory pages through orders, working and terminal, in creation order
+func (s *TradingServiceServer) ListOrderHistory(ctx context.Context, req *exchangev1.ListOrderHistoryRequest) (*exchangev1.ListOrderHistoryResponse, error) {
+	limit, err := historyLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	query := orderhistory.Query{
+		AccountID:  req.GetAccountId(),
+		Symbol:     req.GetSymbol(),
+		From:       unixMillisTime(req.GetStartTimeMs()),
+		To:         unixMillisTime(req.GetEndTimeMs()),
+		Descending: req.GetSort() == exchangev1.SortOrder_SORT_ORDER_DESCENDING,
+		Limit:      limit,
+	}
+	for _, status := range req.GetStatuses() {
+		query.Statuses = append(query.Statuses, orderStatusFromProto(status))
+	}
+	if query.After, err = services.ParseOrderCursor(req.GetCursor()); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	page, err := s.exchangeService.OrderHistory(ctx, query)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.ListOrderHistoryResponse{Orders: ordersToProto(page.Orders), NextCursor: page.NextCursor}, nil
+}
+
+// ListTradeHistory pages through the trade tape in execution order
+func (s *TradingServiceServer) ListTradeHistory(ctx context.Context, req *exchangev1.ListTradeHistoryRequest) (*exchangev1.ListTradeHistoryResponse, error) {
+	limit, err := historyLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	query := tradetape.Query{
+		Symbol:     req.GetSymbol(),
+		AccountID:  req.GetAccountId(),
+		From:       unixMillisTime(req.GetStartTimeMs()),
+		To:         unixMillisTime(req.GetEndTimeMs()),
+		Descending: req.GetSort() == exchangev1.SortOrder_SORT_ORDER_DESCENDING,
+		Limit:      limit,
+	}
+	if query.After, err = services.ParseTradeCursor(req.GetCursor()); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	page, err := s.exchangeService.TradeHistory(ctx, query)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.ListTradeHistoryResponse{Trades: tradesToProto(page.Trades), NextCursor: page.NextCursor}, nil
+}
+
+// GetBalances returns an account's net position per asset across every spot book
+func (s *TradingServiceServer) GetBalances(ctx context.Context, req *exchangev1.GetBalancesRequest) (*exchangev1.GetBalancesResponse, error) {
+	accountLedger, err := s.exchangeService.AccountLedger(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetBalancesResponse{
+		AccountId: accountLedger.AccountID,
+		Balances:  balancesToProto(accountLedger.Positions),
+	}, nil
+}
+
+// GetAccountSnapshot returns an account's balances, positions and open orders as of one engine sequence
+func (s *TradingServiceServer) GetAccountSnapshot(ctx context.Context, req *exchangev1.GetAccountSnapshotRequest) (*exchangev1.GetAccountSnapshotResponse, error) {
+	snapshot, err := s.exchangeService.AccountSnapshot(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetAccountSnapshotResponse{
+		AccountId:   snapshot.AccountID,
+		Sequence:    snapshot.Sequence,
+		TakenTimeMs: unixMillis(snapshot.TakenAt),
+		Balances:    accountBalancesToProto(snapshot.Balances),
+		Positions:   positionsToProto(snapshot.Positions),
+		OpenOrders:  ordersToProto(snapshot.OpenOrders),
+	}, nil
+}
+
+// GetPositions returns net positions and PnL, optionally for one account and/or symbol
+func (s *TradingServiceServer) GetPositions(ctx context.Context, req *exchangev1.GetPositionsRequest) (*exchangev1.GetPositionsResponse, error) {
+	positions, err := s.exchangeService.Positions().Positions(ctx, req.GetAccountId(), req.GetSymbol())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &exchangev1.GetPositionsResponse{Positions: accountPositionsToProto(positions)}, nil
+}
+
+// CheckOrder runs the venue's pre-trade checks without placing the order
+func (s *TradingServiceServer) CheckOrder(ctx context.Context, req *exchangev1.CheckOrderRequest) (*exchangev1.CheckOrderResponse, error) {
+	if req.GetOrder() == nil {
+		return nil, statusFromError(services.NewRejection(services.RejectInvalidRequest, errors.New("order is required")))
+	}
+
+	result := s.exchangeService.CheckOrder(ctx, orderRequestFromProto(req.GetOrder()))
+
+	rejections := make([]*exchangev1.Rejection, 0, len(result.Rejections))
+	for _, rejection := range result.Rejections {
+		rejections = append(rejections, rejectionToProto(rejection))
+	}
+	return &exchangev1.CheckOrderResponse{
+		Accepted:   result.Accepted,
+		Reasons:    result.Reasons,
+		Rejections: rejections,
+	}, nil
+}
+
+// OpenSession keeps a session open until the client disconnects or the venue shuts down,
+// streaming a heartbeat every interval
+func (s *TradingServiceServer) OpenSession(req *exchangev1.OpenSessionRequest, stream exchangev1.TradingService_OpenSessionServer) error {
+	ctx := stream.Context()
+	session, err := s.exchangeService.OpenSession(ctx, req.GetAccountId(), services.SessionOptions{
+		CancelOnDisconnect: req.GetCancelOnDisconnect(),
+		GracePeriod:        time.Duration(req.GetGracePeriodMs()) * time.Millisecond,
+	})
+	if err != nil {
+		return statusFromError(err)
+	}
+	defer s.exchangeService.CloseSession(context.Background(), session.ID)
+
+	event := sessionEventToProto(session)
+	event.Type = exchangev1.SessionEventType_SESSION_EVENT_TYPE_OPENED
+	if err := stream.Send(event); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(session.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.exchangeService.SessionsClosing():
+			return status.Error(codes.Unavailable, "venue is shutting down")
+		case <-heartbeat.C:
+			event := sessionEventToProto(session)
+			event.Type = exchangev1.SessionEventType_SESSION_EVENT_TYPE_HEARTBEAT
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamOrderUpdates streams order state changes and acks for submitted orders,
+// optionally for one account and/or symbol
+func (s *TradingServiceServer) StreamOrderUpdates(req *exchangev1.StreamOrderUpdatesRequest, stream exchangev1.TradingService_StreamOrderUpdatesServer) error {
+	accountID, symbol := req.GetAccountId(), req.GetSymbol()
+	matches := func(orderAccountID, orderSymbol string) bool {
+		return (accountID == "" || orderAccountID == accountID) && (symbol == "" || orderSymbol == symbol)
+	}
+	topic := executionTopic("order_updates", accountID, symbol)
+	return s.streamExecutions(stream.Context(), topic, symbol, req.GetFromSequence(), func(execution feed.Execution) error {
+		update := &exchangev1.OrderUpdate{
+			Sequence:    execution.Sequence,
+			TimestampMs: unixMillis(execution.Time),
+		}
+		switch {
+		case execution.Order != nil && matches(execution.Order.AccountID, execution.Order.Symbol):
+			update.Order = orderToProto(*execution.Order)
+		case execution.Ack != nil && matches(execution.Ack.AccountID, execution.Ack.Symbol):
+			update.Ack = orderAckToProto(*execution.Ack)
+		default:
+			return nil
+		}
+		return stream.Send(update)
+	})
+}
+
+// StreamTrades streams executions, optionally for one account (either side) and/or symbol
+func (s *TradingServiceServer) StreamTrades(req *exchangev1.StreamTradesRequest, stream exchangev1.TradingService_StreamTradesServer) error {
+	accountID, symbol := req.GetAccountId(), req.GetSymbol()
+	topic := executionTopic("trades", accountID, symbol)
+	return s.streamExecutions(stream.Context(), topic, symbol, req.GetFromSequence(), func(execution feed.Execution) error {
+		trade := execution.Trade
+		if trade == nil || (symbol != "" && trade.Symbol != symbol) {
+			return nil
+		}
+		if accountID != "" && trade.BuyAccountID != accountID && trade.SellAccountID != accountID {
+			return nil
+		}
+		return stream.Send(&exchangev1.TradeEvent{
+			Sequence:    execution.Sequence,
+			TimestampMs: unixMillis(execution.Time),
+			Trade:       tradeToProto(*trade),
+		})
+	})
+}
+
+// streamExecutions replays the journal from a sequence, then follows it live until the
+// client disconnects, falls behind or the venue shuts down. The stream and its topic
+// count against the caller's API key.
+func (s *TradingServiceServer) streamExecutions(ctx context.Context, topic, symbol string, from uint64, send func(feed.Execution) error) error {
+	if symbol != "" {
+		if _, err := s.exchangeService.Instruments().Get(symbol); err != nil {
+			return statusFromError(err)
+		}
+	}
+	lease, err := s.exchangeService.OpenStream(ctx)
+	if err != nil {
+		return statusFromError(err)
+	}
+	defer lease.Close()
+	if err := lease.Subscribe(topic); err != nil {
+		return statusFromError(err)
+	}
+	sub, backlog, err := s.exchangeService.Executions().Subscribe(from)
+	if err != nil {
+		return streamStatus(err)
+	}
+	defer sub.Close()
+
+	for _, execution := range backlog {
+		if err := send(execution); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case execution := <-sub.Executions():
+			if err := send(execution); err != nil {
+				return err
+			}
+		case <-sub.Done():
+			return streamStatus(sub.Err())
+		}
+	}
+}
+
+// executionTopic names what an execution stream follows, "trades:acct-1/BTC-USD",
+// with "*" for filters left open
+func executionTopic(channel, accountID, symbol string) string {
+	if accountID == "" {
+		accountID = "*"
+	}
+	if symbol == "" {
+		symbol = "*"
+	}
+	return channel + ":" + accountID + "/" + symbol
+}
+
+// streamStatus converts why an execution stream could not start or ended to a status
+func streamStatus(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, feed.ErrSequenceExpired), errors.Is(err, feed.ErrSequenceAhead):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, feed.ErrSlowConsumer):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, feed.ErrHubClosed):
+		return status.Error(codes.Unavailable, "venue is shutting down")
+	}
+	return status.Error(codes.Internal, err.Error())
+}
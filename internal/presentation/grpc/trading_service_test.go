@@ -0,0 +1,546 @@
+//go:build unit
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/api/exchange/v1"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/services"
+)
+
+func newTestServers() (*TradingServiceServer, *MarketDataServiceServer, *services.ExchangeService) {
+	cfg := &config.Config{ServiceName: "exchange-simulator"}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	exchangeService := services.NewExchangeService(cfg, logger)
+	return NewTradingServiceServer(exchangeService, logger), NewMarketDataServiceServer(exchangeService, logger), exchangeService
+}
+
+func TestTradingServiceServer_CheckOrder(t *testing.T) {
+	t.Run("accepts_valid_order_without_placing_it", func(t *testing.T) {
+		// Given: An exchange service server
+		server, _, exchangeService := newTestServers()
+
+		// When: A valid order is checked
+		resp, err := server.CheckOrder(context.Background(), &exchangev1.CheckOrderRequest{
+			Order: &exchangev1.OrderSpec{
+				AccountId: "acct-1",
+				Symbol:    "BTC-USD",
+				Side:      exchangev1.Side_SIDE_BUY,
+				Type:      exchangev1.OrderType_ORDER_TYPE_LIMIT,
+				Quantity:  0.5,
+				Price:     60000,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: It is accepted with no reasons
+		if !resp.Accepted || len(resp.Reasons) != 0 {
+			t.Errorf("Expected accepted with no reasons, got %+v", resp)
+		}
+
+		// And: Nothing rests on the book
+		if orders := exchangeService.Engine().OpenOrders("acct-1", ""); len(orders) != 0 {
+			t.Errorf("Expected no orders placed, got %d", len(orders))
+		}
+	})
+
+	t.Run("returns_every_failed_check", func(t *testing.T) {
+		server, _, _ := newTestServers()
+
+		resp, err := server.CheckOrder(context.Background(), &exchangev1.CheckOrderRequest{
+			Order: &exchangev1.OrderSpec{
+				Symbol:   "BTC-USD",
+				Side:     exchangev1.Side_SIDE_SELL,
+				Quantity: 0.00015,
+				Price:    60000.001,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Missing account, bad lot size and bad tick size
+		if resp.Accepted || len(resp.Reasons) != 3 {
+			t.Errorf("Expected 3 rejection reasons, got %+v", resp.Reasons)
+		}
+		expected := []exchangev1.RejectReason{
+			exchangev1.RejectReason_REJECT_REASON_INVALID_ACCOUNT,
+			exchangev1.RejectReason_REJECT_REASON_INVALID_QUANTITY,
+			exchangev1.RejectReason_REJECT_REASON_INVALID_PRICE,
+		}
+		if len(resp.Rejections) != len(expected) {
+			t.Fatalf("Expected %d typed rejections, got %+v", len(expected), resp.Rejections)
+		}
+		for i, reason := range expected {
+			if resp.Rejections[i].Reason != reason {
+				t.Errorf("Expected rejection %d to be %s, got %s", i, reason, resp.Rejections[i].Reason)
+			}
+		}
+	})
+
+	t.Run("rejects_orders_for_halted_symbols", func(t *testing.T) {
+		server, _, exchangeService := newTestServers()
+		exchangeService.HaltTrading(context.Background(), "BTC-USD")
+
+		resp, _ := server.CheckOrder(context.Background(), &exchangev1.CheckOrderRequest{
+			Order: &exchangev1.OrderSpec{
+				AccountId: "acct-1",
+				Symbol:    "BTC-USD",
+				Side:      exchangev1.Side_SIDE_BUY,
+				Quantity:  1,
+				Price:     60000,
+			},
+		})
+
+		if resp.Accepted {
+			t.Error("Expected halted symbol to be rejected")
+		}
+		if len(resp.Rejections) != 1 || resp.Rejections[0].Reason != exchangev1.RejectReason_REJECT_REASON_INSTRUMENT_HALTED {
+			t.Errorf("Expected INSTRUMENT_HALTED, got %+v", resp.Rejections)
+		}
+	})
+
+	t.Run("applies_the_checks_placing_would", func(t *testing.T) {
+		// Given: A funded account holding a read-only key
+		server, _, exchangeService := newTestServers()
+		ctx := context.Background()
+		funded, _ := exchangeService.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}}, 1)
+		reader, _ := exchangeService.CreateAPIKey(ctx, funded[0].ID, services.NewAPIKeyRequest{Permissions: []accounts.Permission{accounts.PermissionRead}})
+		check := func(ctx context.Context, quantity float64) []exchangev1.RejectReason {
+			resp, _ := server.CheckOrder(ctx, &exchangev1.CheckOrderRequest{
+				Order: &exchangev1.OrderSpec{
+					AccountId: funded[0].ID,
+					Symbol:    "BTC-USD",
+					Side:      exchangev1.Side_SIDE_BUY,
+					Type:      exchangev1.OrderType_ORDER_TYPE_LIMIT,
+					Quantity:  quantity,
+					Price:     60000,
+				},
+			})
+			reasons := make([]exchangev1.RejectReason, 0, len(resp.Rejections))
+			for _, rejection := range resp.Rejections {
+				reasons = append(reasons, rejection.Reason)
+			}
+			return reasons
+		}
+
+		// When: An order beyond the balance is checked, then one within it with the
+		// read-only key, then again while the venue is read-only
+		overdrawn := check(ctx, 1)
+		withReader := check(keystats.WithAPIKey(ctx, reader.ID), 0.01)
+		exchangeService.SetDegradationMode(ctx, chaos.ModeReadOnly)
+		readOnly := check(ctx, 0.01)
+
+		// Then: Each is refused as placing it would be
+		if len(overdrawn) != 1 || overdrawn[0] != exchangev1.RejectReason_REJECT_REASON_INSUFFICIENT_BALANCE {
+			t.Errorf("Expected INSUFFICIENT_BALANCE, got %v", overdrawn)
+		}
+		if len(withReader) != 1 || withReader[0] != exchangev1.RejectReason_REJECT_REASON_PERMISSION_DENIED {
+			t.Errorf("Expected PERMISSION_DENIED, got %v", withReader)
+		}
+		if len(readOnly) != 1 || readOnly[0] != exchangev1.RejectReason_REJECT_REASON_ENGINE_UNAVAILABLE {
+			t.Errorf("Expected ENGINE_UNAVAILABLE, got %v", readOnly)
+		}
+
+		// And: Nothing is held against the balance
+		if balances, _ := exchangeService.AccountBalances(ctx, funded[0].ID); len(balances.Holds) != 0 {
+			t.Errorf("Expected no holds, got %+v", balances.Holds)
+		}
+	})
+
+	t.Run("requires_order", func(t *testing.T) {
+		server, _, _ := newTestServers()
+
+		_, err := server.CheckOrder(context.Background(), &exchangev1.CheckOrderRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestTradingServiceServer_Trading(t *testing.T) {
+	ctx := context.Background()
+	spec := func(account string, side exchangev1.Side, quantity, price float64) *exchangev1.OrderSpec {
+		return &exchangev1.OrderSpec{AccountId: account, Symbol: "BTC-USD", Side: side, Quantity: quantity, Price: price}
+	}
+
+	t.Run("places_matches_and_reports_orders_trades_balances_and_market_data", func(t *testing.T) {
+		// Given: A resting ask from one account
+		server, marketData, _ := newTestServers()
+		ask, err := server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{Order: spec("maker", exchangev1.Side_SIDE_SELL, 1, 60000)})
+		if err != nil {
+			t.Fatalf("Expected ask to be placed, got %v", err)
+		}
+
+		// When: Another account lifts part of it
+		bid, err := server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{Order: spec("taker", exchangev1.Side_SIDE_BUY, 0.4, 60000)})
+		if err != nil {
+			t.Fatalf("Expected bid to be placed, got %v", err)
+		}
+
+		// Then: The taker is filled by one trade and the maker keeps working
+		if bid.Order.Status != exchangev1.OrderStatus_ORDER_STATUS_FILLED || len(bid.Trades) != 1 {
+			t.Fatalf("Expected a filled bid with one trade, got %+v", bid)
+		}
+		if bid.Trades[0].TakerSide != exchangev1.Side_SIDE_BUY || bid.Trades[0].SellAccountId != "maker" {
+			t.Errorf("Expected a buyer-initiated trade against maker, got %+v", bid.Trades[0])
+		}
+		got, _ := server.GetOrder(ctx, &exchangev1.GetOrderRequest{OrderId: ask.Order.Id})
+		if got.Order.Status != exchangev1.OrderStatus_ORDER_STATUS_PARTIALLY_FILLED || got.Order.FilledQuantity != 0.4 {
+			t.Errorf("Expected the ask to be partially filled, got %+v", got.Order)
+		}
+		open, _ := server.ListOpenOrders(ctx, &exchangev1.ListOpenOrdersRequest{AccountId: "maker"})
+		if len(open.Orders) != 1 || open.Orders[0].Id != ask.Order.Id {
+			t.Errorf("Expected the ask as maker's only open order, got %+v", open.Orders)
+		}
+		book, _ := marketData.GetOrderBook(ctx, &exchangev1.GetOrderBookRequest{Symbol: "BTC-USD", Depth: 5})
+		if book.Phase != "continuous" || len(book.Asks) != 1 || book.Asks[0].Quantity != 0.6 || len(book.Bids) != 0 {
+			t.Errorf("Expected 0.6 left on the ask side, got %+v", book)
+		}
+		trades, _ := server.GetTrades(ctx, &exchangev1.GetTradesRequest{AccountId: "taker"})
+		if len(trades.Trades) != 1 || trades.Trades[0].Id != bid.Trades[0].Id {
+			t.Errorf("Expected taker's trade in history, got %+v", trades.Trades)
+		}
+		balances, _ := server.GetBalances(ctx, &exchangev1.GetBalancesRequest{AccountId: "taker"})
+		assets := make(map[string]float64)
+		for _, balance := range balances.Balances {
+			assets[balance.Asset] = balance.Quantity
+		}
+		if assets["BTC"] != 0.4 || assets["USD"] != -24000 {
+			t.Errorf("Expected +0.4 BTC and -24000 USD, got %+v", balances.Balances)
+		}
+
+		snapshot, err := server.GetAccountSnapshot(ctx, &exchangev1.GetAccountSnapshotRequest{AccountId: "maker"})
+		if err != nil || len(snapshot.OpenOrders) != 1 || snapshot.OpenOrders[0].Id != ask.Order.Id {
+			t.Fatalf("Expected the ask in maker's snapshot, got %+v, %v", snapshot, err)
+		}
+		held := make(map[string]*exchangev1.AccountBalance)
+		for _, balance := range snapshot.Balances {
+			held[balance.Asset] = balance
+		}
+		if held["BTC"].GetHeld() != 0.6 || held["USD"].GetTotal() != 24000 {
+			t.Errorf("Expected 0.6 BTC held by the ask and 24000 USD received, got %+v", snapshot.Balances)
+		}
+		positions, err := server.GetPositions(ctx, &exchangev1.GetPositionsRequest{Symbol: "BTC-USD"})
+		if err != nil || len(positions.Positions) != 2 || positions.Positions[0].AccountId != "maker" || positions.Positions[0].Quantity != -0.4 ||
+			positions.Positions[1].AccountId != "taker" || positions.Positions[1].EntryPrice != 60000 {
+			t.Errorf("Expected maker short and taker long 0.4 at 60000, got %+v, %v", positions, err)
+		}
+
+		ticker, _ := marketData.GetTicker(ctx, &exchangev1.GetTickerRequest{Symbol: "BTC-USD"})
+		if ticker.LastPrice != 60000 || ticker.WeightedAvgPrice != 60000 || ticker.AskPrice != 60000 || ticker.AskQuantity != 0.6 || ticker.BidPrice != 0 {
+			t.Errorf("Expected the trade and the remaining ask on the ticker, got %+v", ticker)
+		}
+		candles, _ := marketData.GetCandles(ctx, &exchangev1.GetCandlesRequest{Symbol: "BTC-USD", Interval: "1m"})
+		if len(candles.Candles) != 1 || candles.Candles[0].Close != 60000 || candles.Candles[0].Volume != 0.4 {
+			t.Errorf("Expected one 1m bar for the trade, got %+v", candles.Candles)
+		}
+
+		// And: Canceling the ask takes it off the book
+		canceled, err := server.CancelOrder(ctx, &exchangev1.CancelOrderRequest{OrderId: ask.Order.Id})
+		if err != nil || canceled.Order.Status != exchangev1.OrderStatus_ORDER_STATUS_CANCELED {
+			t.Errorf("Expected the ask to be canceled, got %+v, %v", canceled, err)
+		}
+	})
+
+	t.Run("pages_through_order_and_trade_history", func(t *testing.T) {
+		// Given: Two fills against one resting ask, then a canceled bid
+		server, _, _ := newTestServers()
+		server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{Order: spec("maker", exchangev1.Side_SIDE_SELL, 2, 60000)})
+		for i := 0; i < 2; i++ {
+			server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{Order: spec("taker", exchangev1.Side_SIDE_BUY, 1, 60000)})
+		}
+		resting, _ := server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{Order: spec("taker", exchangev1.Side_SIDE_BUY, 1, 59000)})
+		server.CancelOrder(ctx, &exchangev1.CancelOrderRequest{OrderId: resting.Order.Id})
+
+		// When: The taker's filled orders are read one per page, and its trades newest first
+		filled := &exchangev1.ListOrderHistoryRequest{AccountId: "taker", Statuses: []exchangev1.OrderStatus{exchangev1.OrderStatus_ORDER_STATUS_FILLED}, Limit: 1}
+		first, err := server.ListOrderHistory(ctx, filled)
+		if err != nil || len(first.Orders) != 1 || first.NextCursor == "" {
+			t.Fatalf("Expected a first page with a cursor, got %+v, %v", first, err)
+		}
+		filled.Cursor = first.NextCursor
+		second, err := server.ListOrderHistory(ctx, filled)
+		trades, tradesErr := server.ListTradeHistory(ctx, &exchangev1.ListTradeHistoryRequest{AccountId: "taker", Sort: exchangev1.SortOrder_SORT_ORDER_DESCENDING})
+
+		// Then: Both filled orders come back once, leaving out the canceled bid
+		if err != nil || len(second.Orders) != 1 || second.NextCursor != "" || second.Orders[0].Id == first.Orders[0].Id {
+			t.Errorf("Expected the other filled order alone on the last page, got %+v, %v", second, err)
+		}
+		// And: The trades newest first
+		if tradesErr != nil || len(trades.Trades) != 2 || trades.Trades[0].Id < trades.Trades[1].Id {
+			t.Errorf("Expected two trades newest first, got %+v, %v", trades, tradesErr)
+		}
+	})
+
+	t.Run("maps_failures_to_status_codes", func(t *testing.T) {
+		server, marketData, _ := newTestServers()
+
+		_, err := server.PlaceOrder(ctx, &exchangev1.PlaceOrderRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument without an order, got %v", err)
+		}
+		_, err = server.CancelOrder(ctx, &exchangev1.CancelOrderRequest{OrderId: "ord-BTC-USD-9"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound for an unknown order, got %v", err)
+		}
+		_, err = marketData.GetOrderBook(ctx, &exchangev1.GetOrderBookRequest{Symbol: "XRP-USD"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound for an unlisted symbol, got %v", err)
+		}
+		_, err = server.GetBalances(ctx, &exchangev1.GetBalancesRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument without an account, got %v", err)
+		}
+		_, err = marketData.GetCandles(ctx, &exchangev1.GetCandlesRequest{Symbol: "BTC-USD", Interval: "2m"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for an unsupported interval, got %v", err)
+		}
+		_, err = server.ListOrderHistory(ctx, &exchangev1.ListOrderHistoryRequest{Limit: maxHistoryLimit + 1})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for an oversized page, got %v", err)
+		}
+		_, err = server.ListTradeHistory(ctx, &exchangev1.ListTradeHistoryRequest{Cursor: "bm9wZQ"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for a foreign cursor, got %v", err)
+		}
+	})
+}
+
+func TestStatusFromError(t *testing.T) {
+	t.Run("attaches_typed_rejection_detail", func(t *testing.T) {
+		// Given: An order lookup that fails in the engine
+		_, _, exchangeService := newTestServers()
+		_, lookupErr := exchangeService.GetOrder(context.Background(), "ord-BTC-USD-42")
+
+		// When: It is converted to a gRPC status
+		st := status.Convert(statusFromError(lookupErr))
+
+		// Then: The code and the Rejection detail both identify the cause
+		if st.Code() != codes.NotFound {
+			t.Errorf("Expected NotFound, got %s", st.Code())
+		}
+		details := st.Details()
+		if len(details) != 1 {
+			t.Fatalf("Expected one detail, got %d", len(details))
+		}
+		rejection, ok := details[0].(*exchangev1.Rejection)
+		if !ok || rejection.Reason != exchangev1.RejectReason_REJECT_REASON_ORDER_NOT_FOUND {
+			t.Errorf("Expected ORDER_NOT_FOUND rejection, got %+v", details[0])
+		}
+	})
+}
+
+func TestAPIKeyInterceptor(t *testing.T) {
+	t.Run("counts_exchange_rpcs_per_key", func(t *testing.T) {
+		// Given: An interceptor and an incoming call carrying an API key
+		server, _, exchangeService := newTestServers()
+		interceptor := APIKeyInterceptor(exchangeService.KeyStatistics())
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyMetadata, "bot-1"))
+		info := &grpc.UnaryServerInfo{FullMethod: "/exchange.v1.TradingService/CheckOrder"}
+
+		// When: A CheckOrder call passes through it
+		_, err := interceptor(ctx, &exchangev1.CheckOrderRequest{Order: &exchangev1.OrderSpec{}}, info,
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return server.CheckOrder(ctx, req.(*exchangev1.CheckOrderRequest))
+			})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The message is counted under the key
+		stats, exists := exchangeService.KeyStatistics().Stats("bot-1")
+		if !exists || stats.Messages != 1 {
+			t.Errorf("Expected one message for bot-1, got %+v", stats)
+		}
+	})
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	t.Run("tags_every_entry_about_a_call_with_its_ids", func(t *testing.T) {
+		// Given: A resting order, and the API key and logging interceptors over a
+		// service logging at debug level
+		logger, hook := logtest.NewNullLogger()
+		logger.SetLevel(logrus.DebugLevel)
+		exchangeService := services.NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+		server := NewTradingServiceServer(exchangeService, logger)
+		placed, err := server.PlaceOrder(context.Background(), &exchangev1.PlaceOrderRequest{Order: &exchangev1.OrderSpec{AccountId: "maker", Symbol: "BTC-USD", Side: exchangev1.Side_SIDE_SELL, Quantity: 1, Price: 60000}})
+		if err != nil {
+			t.Fatalf("Expected the order placed, got %v", err)
+		}
+		keys, logging := APIKeyInterceptor(exchangeService.KeyStatistics()), LoggingInterceptor(logger)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadata, "req-1", CorrelationIDMetadata, "corr-1"))
+		info := &grpc.UnaryServerInfo{FullMethod: "/exchange.v1.TradingService/CancelOrder"}
+		hook.Reset()
+
+		// When: The order is canceled through them
+		_, err = keys(ctx, &exchangev1.CancelOrderRequest{OrderId: placed.Order.Id}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return logging(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return server.CancelOrder(ctx, req.(*exchangev1.CancelOrderRequest))
+			})
+		})
+
+		// Then: The service's entry and the call's outcome carry the call's IDs
+		if err != nil {
+			t.Fatalf("Expected the order canceled, got %v", err)
+		}
+		logged := make(map[string]bool)
+		for _, entry := range hook.AllEntries() {
+			logged[entry.Message] = true
+			if entry.Data["request_id"] != "req-1" || entry.Data["correlation_id"] != "corr-1" || entry.Data["order_id"] != placed.Order.Id {
+				t.Errorf("Expected %q tagged with the call's IDs, got %v", entry.Message, entry.Data)
+			}
+			// Only the service learns whose order it was
+			if entry.Message == "Order canceled" && entry.Data["account_id"] != "maker" {
+				t.Errorf("Expected the cancel tagged with the account, got %v", entry.Data)
+			}
+		}
+		if !logged["Order canceled"] || !logged["gRPC call completed"] {
+			t.Errorf("Expected the cancel and the call logged, got %v", logged)
+		}
+	})
+}
+
+// tradeStream collects the events StreamTrades sends
+type tradeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *exchangev1.TradeEvent
+}
+
+func (s *tradeStream) Context() context.Context { return s.ctx }
+
+func (s *tradeStream) Send(event *exchangev1.TradeEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestTradingServiceServer_StreamTrades(t *testing.T) {
+	place := func(t *testing.T, server *TradingServiceServer, account string, side exchangev1.Side) {
+		t.Helper()
+		_, err := server.PlaceOrder(context.Background(), &exchangev1.PlaceOrderRequest{Order: &exchangev1.OrderSpec{
+			AccountId: account, Symbol: "BTC-USD", Side: side, Quantity: 0.1, Price: 60000,
+		}})
+		if err != nil {
+			t.Fatalf("Expected order to be placed, got %v", err)
+		}
+	}
+
+	t.Run("replays_from_sequence_then_follows_live_for_one_account", func(t *testing.T) {
+		// Given: Two trades already printed, only the first involving acct-1
+		server, _, exchangeService := newTestServers()
+		place(t, server, "maker", exchangev1.Side_SIDE_SELL)
+		place(t, server, "acct-1", exchangev1.Side_SIDE_BUY)
+		place(t, server, "maker", exchangev1.Side_SIDE_SELL)
+		place(t, server, "acct-2", exchangev1.Side_SIDE_BUY)
+
+		// When: acct-1 streams trades from the first sequence and trades again
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &tradeStream{ctx: ctx, events: make(chan *exchangev1.TradeEvent, 8)}
+		done := make(chan error, 1)
+		go func() {
+			done <- server.StreamTrades(&exchangev1.StreamTradesRequest{AccountId: "acct-1", FromSequence: 1}, stream)
+		}()
+		replayed := <-stream.events
+		place(t, server, "acct-1", exchangev1.Side_SIDE_SELL)
+		place(t, server, "maker", exchangev1.Side_SIDE_BUY)
+		live := <-stream.events
+		cancel()
+
+		// Then: It gets its replayed trade and the live one, in sequence order
+		if err := <-done; err != nil {
+			t.Fatalf("Expected the stream to end cleanly, got %v", err)
+		}
+		if replayed.Trade.GetBuyAccountId() != "acct-1" || live.Trade.GetSellAccountId() != "acct-1" {
+			t.Errorf("Expected acct-1's trades only, got %+v then %+v", replayed.Trade, live.Trade)
+		}
+		if live.Sequence <= replayed.Sequence || live.Sequence != exchangeService.Executions().Sequence()-1 {
+			t.Errorf("Expected increasing venue sequences, got %d then %d", replayed.Sequence, live.Sequence)
+		}
+		if len(stream.events) != 0 {
+			t.Errorf("Expected no other trades, got %d", len(stream.events))
+		}
+	})
+
+	t.Run("unretained_sequence_is_out_of_range", func(t *testing.T) {
+		server, _, _ := newTestServers()
+		stream := &tradeStream{ctx: context.Background(), events: make(chan *exchangev1.TradeEvent, 1)}
+
+		err := server.StreamTrades(&exchangev1.StreamTradesRequest{FromSequence: 42}, stream)
+
+		if status.Code(err) != codes.OutOfRange {
+			t.Errorf("Expected OutOfRange, got %v", err)
+		}
+	})
+
+	t.Run("venue_shutdown_ends_the_stream", func(t *testing.T) {
+		server, _, exchangeService := newTestServers()
+		stream := &tradeStream{ctx: context.Background(), events: make(chan *exchangev1.TradeEvent, 1)}
+		done := make(chan error, 1)
+		go func() { done <- server.StreamTrades(&exchangev1.StreamTradesRequest{}, stream) }()
+
+		// Closing before or after the stream subscribes ends it the same way
+		exchangeService.CloseFeeds()
+
+		if err := <-done; status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable, got %v", err)
+		}
+	})
+	t.Run("streams_beyond_the_key_limit_are_resource_exhausted", func(t *testing.T) {
+		// Given: A key limited to one stream, holding it open
+		server, _, exchangeService := newTestServers()
+		exchangeService.Entitlements().SetLimits("bot", entitlements.Limits{MaxStreams: 1})
+		ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyMetadata, "bot")))
+		defer cancel()
+		interceptor := APIKeyStreamInterceptor(exchangeService.KeyStatistics())
+		info := &grpc.StreamServerInfo{FullMethod: "/exchange.v1.TradingService/StreamTrades"}
+		streamTrades := func(srv interface{}, stream grpc.ServerStream) error {
+			return server.StreamTrades(&exchangev1.StreamTradesRequest{}, &tradeStream{ctx: stream.Context(), events: make(chan *exchangev1.TradeEvent, 1)})
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- interceptor(nil, &tradeStream{ctx: ctx}, info, streamTrades)
+		}()
+		deadline := time.Now().Add(2 * time.Second)
+		for exchangeService.Entitlements().Usage("bot").Streams == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		// When: The key opens a second stream
+		err := interceptor(nil, &tradeStream{ctx: ctx}, info, streamTrades)
+
+		// Then: It is refused with a typed rejection, and the first stream's topic is counted
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Expected ResourceExhausted, got %v", err)
+		}
+		if usage := exchangeService.Entitlements().Usage("bot"); usage.Streams != 1 || usage.Subscriptions != 1 {
+			t.Errorf("Expected one stream with one subscription, got %+v", usage)
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected the first stream to end cleanly, got %v", err)
+		}
+		if usage := exchangeService.Entitlements().Usage("bot"); usage.Streams != 0 || usage.Subscriptions != 0 {
+			t.Errorf("Expected the stream to be released, got %+v", usage)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Account activity metrics reported through the MetricsPort. Per-account figures
+// are only served by the activity endpoint, keeping the metrics low-cardinality:
+// - exchange_accounts{state}: Accounts seen since startup, by state over the last
+//   accountActiveWindow: "trading" (traded), "quoting" (placed or canceled orders
+//   without trading) or "idle" (gauge)
+// - exchange_top_account_notional_share: Share of the notional traded since startup
+//   taken by the most active account (gauge)
+
+// accountActiveWindow is how recently an account must have acted to count as active
+const accountActiveWindow = 5 * time.Minute
+
+// Leaderboard orderings accepted by AccountActivityLeaderboard
+const (
+	ActivitySortNotional       = "notional"
+	ActivitySortTrades         = "trades"
+	ActivitySortOrders         = "orders"
+	ActivitySortRejectionRatio = "rejection_ratio"
+)
+
+// AccountActivity is what one account has done since startup
+type AccountActivity struct {
+	AccountID      string             `json:"account_id"`
+	Orders         int                `json:"orders"`     // Accepted
+	Rejections     int                `json:"rejections"` // Refused, by the venue or the engine
+	Cancels        int                `json:"cancels"`
+	Trades         int                `json:"trades"`
+	Volume         map[string]float64 `json:"volume"`          // Base quantity traded, by symbol
+	Notional       float64            `json:"notional"`        // Quote value traded across symbols
+	RejectionRatio float64            `json:"rejection_ratio"` // Rejections over orders requested
+	LastOrderAt    time.Time          `json:"last_order_at"`
+	LastTradeAt    time.Time          `json:"last_trade_at"`
+}
+
+// accountActivity tallies orders, rejections, cancels and fills by account
+type accountActivity struct {
+	mu       sync.Mutex
+	accounts map[string]*AccountActivity
+	traded   float64 // Notional of the trades counted, once per trade
+}
+
+func newAccountActivity() *accountActivity {
+	return &accountActivity{accounts: make(map[string]*AccountActivity)}
+}
+
+// account returns accountID's tally, starting one; callers hold mu
+func (a *accountActivity) account(accountID string) *AccountActivity {
+	activity, ok := a.accounts[accountID]
+	if !ok {
+		activity = &AccountActivity{AccountID: accountID, Volume: make(map[string]float64)}
+		a.accounts[accountID] = activity
+	}
+	return activity
+}
+
+// snapshot copies every account's tally, and the notional they traded between them
+func (a *accountActivity) snapshot() ([]AccountActivity, float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	activities := make([]AccountActivity, 0, len(a.accounts))
+	for _, activity := range a.accounts {
+		copied := *activity
+		copied.Volume = make(map[string]float64, len(activity.Volume))
+		for symbol, volume := range activity.Volume {
+			copied.Volume[symbol] = volume
+		}
+		if requested := copied.Orders + copied.Rejections; requested > 0 {
+			copied.RejectionRatio = float64(copied.Rejections) / float64(requested)
+		}
+		activities = append(activities, copied)
+	}
+	return activities, a.traded
+}
+
+// observeAccountPlacement counts a request to place an order against its account.
+// Duplicate submissions were counted the first time.
+func (s *ExchangeService) observeAccountPlacement(req OrderRequest, report *matching.ExecutionReport, err error) {
+	if req.AccountID == "" || (report != nil && report.Duplicate) {
+		return
+	}
+	a := s.activity
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	activity := a.account(req.AccountID)
+	if err != nil || report.Order.Status == models.OrderStatusRejected {
+		activity.Rejections++
+	} else {
+		activity.Orders++
+	}
+	activity.LastOrderAt = s.now()
+}
+
+// observeAccountCancel counts an order canceled on request against its account
+func (s *ExchangeService) observeAccountCancel(order models.Order) {
+	a := s.activity
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	activity := a.account(order.AccountID)
+	activity.Cancels++
+	activity.LastOrderAt = s.now()
+}
+
+// observeAccountTrades counts each trade once for each account on it. Deleveraging
+// trades are forced on their accounts, so they are not counted as trading.
+func (s *ExchangeService) observeAccountTrades(trades []models.Trade) {
+	a := s.activity
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, trade := range trades {
+		if trade.Deleverage {
+			continue
+		}
+		a.traded += trade.Notional()
+		accountIDs := []string{trade.BuyAccountID}
+		if trade.SellAccountID != trade.BuyAccountID {
+			accountIDs = append(accountIDs, trade.SellAccountID)
+		}
+		for _, accountID := range accountIDs {
+			activity := a.account(accountID)
+			activity.Trades++
+			activity.Volume[trade.Symbol] += trade.Quantity
+			activity.Notional += trade.Notional()
+			activity.LastTradeAt = trade.ExecutedAt
+		}
+	}
+}
+
+// AccountActivityLeaderboard returns accounts' activity since startup, most active
+// first by sortBy (notional when empty), at most limit of them (0 = all)
+func (s *ExchangeService) AccountActivityLeaderboard(ctx context.Context, sortBy string, limit int) ([]AccountActivity, error) {
+	if limit < 0 {
+		return nil, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	var score func(AccountActivity) float64
+	switch sortBy {
+	case ActivitySortNotional, "":
+		score = func(a AccountActivity) float64 { return a.Notional }
+	case ActivitySortTrades:
+		score = func(a AccountActivity) float64 { return float64(a.Trades) }
+	case ActivitySortOrders:
+		score = func(a AccountActivity) float64 { return float64(a.Orders) }
+	case ActivitySortRejectionRatio:
+		score = func(a AccountActivity) float64 { return a.RejectionRatio }
+	default:
+		return nil, rejectf(RejectInvalidRequest, "unknown sort %q: expected notional, trades, orders or rejection_ratio", sortBy)
+	}
+
+	activities, _ := s.activity.snapshot()
+	sort.Slice(activities, func(i, j int) bool {
+		if score(activities[i]) != score(activities[j]) {
+			return score(activities[i]) > score(activities[j])
+		}
+		return activities[i].AccountID < activities[j].AccountID
+	})
+	if limit > 0 && len(activities) > limit {
+		activities = activities[:limit]
+	}
+	return activities, nil
+}
+
+// reportAccountMetrics sets the aggregate account activity gauges
+func (s *ExchangeService) reportAccountMetrics() {
+	metrics := s.config.GetMetricsPort()
+	if metrics == nil {
+		return
+	}
+	since := s.now().Add(-accountActiveWindow)
+	states := map[string]int{"trading": 0, "quoting": 0, "idle": 0}
+	activities, traded := s.activity.snapshot()
+	top := 0.0
+	for _, activity := range activities {
+		switch {
+		case activity.LastTradeAt.After(since):
+			states["trading"]++
+		case activity.LastOrderAt.After(since):
+			states["quoting"]++
+		default:
+			states["idle"]++
+		}
+		if activity.Notional > top {
+			top = activity.Notional
+		}
+	}
+	for state, count := range states {
+		metrics.SetGauge("exchange_accounts", float64(count), map[string]string{"state": state})
+	}
+	share := 0.0
+	if traded > 0 {
+		share = top / traded
+	}
+	metrics.SetGauge("exchange_top_account_notional_share", share, nil)
+}
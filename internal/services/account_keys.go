@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+)
+
+const (
+	// maxKeysPerAccount bounds the keys, revoked ones included, one account holds
+	maxKeysPerAccount = 20
+
+	// maxAccessDenials bounds the access-control audit trail; the oldest are dropped first
+	maxAccessDenials = 1000
+
+	// defaultAuthRecvWindow is how old a signed request may be when unconfigured
+	defaultAuthRecvWindow = 5 * time.Second
+)
+
+// NewAPIKeyRequest issues a key to an account; no permissions means the defaults
+type NewAPIKeyRequest struct {
+	Label       string                `json:"label"`
+	Permissions []accounts.Permission `json:"permissions"`
+}
+
+// IssuedKey is a newly issued key with its secret, which is returned only here
+type IssuedKey struct {
+	accounts.Key
+	AccountID string `json:"account_id"`
+	Secret    string `json:"secret"`
+}
+
+// SignedRequest is what a caller presented to authenticate one request
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string // Unix milliseconds on the caller's clock
+	Signature string // Hex HMAC-SHA256 of the signing payload under the key's secret
+	Payload   string // accounts.SigningPayload of the request as received
+	Transport string // "http" or "grpc"
+}
+
+// AccessDenial is one request refused for failing authentication or lacking a
+// permission
+type AccessDenial struct {
+	At         time.Time           `json:"at"`
+	KeyID      string              `json:"key_id"`
+	AccountID  string              `json:"account_id,omitempty"` // The key's account, when the key is known
+	Transport  string              `json:"transport,omitempty"`
+	Reason     RejectReason        `json:"reason"` // UNAUTHENTICATED or PERMISSION_DENIED
+	Permission accounts.Permission `json:"permission,omitempty"`
+	Detail     string              `json:"detail"`
+}
+
+// accessLog is the audit trail of access-control failures, oldest first
+type accessLog struct {
+	denials []AccessDenial
+	mu      sync.Mutex
+}
+
+func newAccessLog() *accessLog {
+	return &accessLog{denials: make([]AccessDenial, 0)}
+}
+
+// operatorContextKey marks requests made with the admin token
+type operatorContextKey struct{}
+
+// AsOperator marks a request context as presenting the admin token
+func AsOperator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operatorContextKey{}, true)
+}
+
+// CreateAPIKey issues a signing key to an active account, for a key of the account
+// with the trade permission or an operator
+func (s *ExchangeService) CreateAPIKey(ctx context.Context, accountID string, req NewAPIKeyRequest) (IssuedKey, error) {
+	if err := s.authorizeOwner(ctx, accountID); err != nil {
+		return IssuedKey{}, err
+	}
+	if len(req.Permissions) == 0 {
+		req.Permissions = accounts.DefaultPermissions
+	}
+	key, err := accounts.NewKey(req.Label, req.Permissions, s.now())
+	if err != nil {
+		return IssuedKey{}, NewRejection(RejectInvalidRequest, err)
+	}
+
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	account, err := registry.directory.Get(accountID)
+	if err != nil {
+		return IssuedKey{}, err
+	}
+	if account.Status == accounts.StatusClosed {
+		return IssuedKey{}, fmt.Errorf("%w: %s", accounts.ErrClosed, accountID)
+	}
+	if len(account.Keys) >= maxKeysPerAccount {
+		return IssuedKey{}, rejectf(RejectInvalidRequest, "account %s already holds %d keys", accountID, maxKeysPerAccount)
+	}
+	account.Keys = append(append([]accounts.Key(nil), account.Keys...), key)
+	if err := s.saveAccounts(account); err != nil {
+		return IssuedKey{}, err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"account":     accountID,
+		"key_id":      key.ID,
+		"permissions": key.Permissions,
+	}).Info("API key issued")
+	return IssuedKey{Key: key, AccountID: accountID, Secret: key.Secret}, nil
+}
+
+// RevokeAPIKey stops a key authenticating from now on; revoking it again is a no-op.
+// It takes a key of the account with the trade permission or an operator.
+func (s *ExchangeService) RevokeAPIKey(ctx context.Context, accountID, keyID string) (accounts.Key, error) {
+	if err := s.authorizeOwner(ctx, accountID); err != nil {
+		return accounts.Key{}, err
+	}
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	account, err := registry.directory.Get(accountID)
+	if err != nil {
+		return accounts.Key{}, err
+	}
+
+	keys := append([]accounts.Key(nil), account.Keys...)
+	for i := range keys {
+		if keys[i].ID != keyID {
+			continue
+		}
+		if !keys[i].Active() {
+			return keys[i], nil
+		}
+		revokedAt := s.now()
+		keys[i].RevokedAt = &revokedAt
+		account.Keys = keys
+		if err := s.saveAccounts(account); err != nil {
+			return accounts.Key{}, err
+		}
+		s.logger.WithFields(logrus.Fields{"account": accountID, "key_id": keyID}).Info("API key revoked")
+		return keys[i], nil
+	}
+	return accounts.Key{}, fmt.Errorf("%w: %s", accounts.ErrKeyNotFound, keyID)
+}
+
+// APIKeys lists an account's keys oldest first, without their secrets
+func (s *ExchangeService) APIKeys(ctx context.Context, accountID string) ([]accounts.Key, error) {
+	account, err := s.accounts.directory.Get(accountID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]accounts.Key, len(account.Keys))
+	copy(keys, account.Keys)
+	return keys, nil
+}
+
+// AuthenticateRequest checks the signature of a request made with a key issued
+// through the key API. Other keys, and requests without one, are not signed and pass
+// as before; what they may do is up to the endpoint.
+func (s *ExchangeService) AuthenticateRequest(ctx context.Context, req SignedRequest) error {
+	if !accounts.IsKeyID(req.KeyID) {
+		return nil
+	}
+	account, key, issued := s.accounts.directory.ByKeyID(req.KeyID)
+	deny := func(format string, args ...interface{}) error {
+		err := rejectf(RejectUnauthenticated, format, args...)
+		s.recordDenial(AccessDenial{KeyID: req.KeyID, AccountID: account.ID, Transport: req.Transport, Reason: RejectUnauthenticated, Detail: err.Error()})
+		return err
+	}
+
+	if !issued {
+		return deny("unknown API key %s", req.KeyID)
+	}
+	if !key.Active() {
+		return deny("API key %s was revoked", req.KeyID)
+	}
+	if req.Signature == "" || req.Timestamp == "" {
+		return deny("API key %s requires a signature and timestamp", req.KeyID)
+	}
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return deny("timestamp must be Unix milliseconds")
+	}
+	// Clients stamp requests from their own wall clock, whatever the venue clock says
+	age := time.Since(time.UnixMilli(timestamp))
+	if window := authRecvWindow(s.config); age > window || age < -time.Second {
+		return deny("timestamp is outside the %s window", window)
+	}
+	if !key.Verify(req.Payload, req.Signature) {
+		return deny("signature is not valid")
+	}
+	return nil
+}
+
+// AccessDenials returns up to limit of the latest access-control failures, oldest
+// first, optionally for one key
+func (s *ExchangeService) AccessDenials(ctx context.Context, keyID string, limit int) ([]AccessDenial, error) {
+	if limit < 0 {
+		return nil, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	s.access.mu.Lock()
+	defer s.access.mu.Unlock()
+
+	denials := make([]AccessDenial, 0)
+	for _, denial := range s.access.denials {
+		if keyID == "" || denial.KeyID == keyID {
+			denials = append(denials, denial)
+		}
+	}
+	if limit > 0 && len(denials) > limit {
+		denials = denials[len(denials)-limit:]
+	}
+	return denials, nil
+}
+
+// authorizeKey checks that the caller's key may act on accountID with permission.
+// Keys the venue issued or provisioned must belong to the account and hold the
+// permission; a caller without one, or with a key the venue does not know, is refused
+// by an account holding active issued keys, unless it presented the admin token.
+func (s *ExchangeService) authorizeKey(ctx context.Context, accountID string, permission accounts.Permission) error {
+	apiKey := keystats.APIKey(ctx)
+	account, key, signed := s.accounts.directory.ByKeyID(apiKey)
+	if !signed {
+		var issued bool
+		if account, issued = s.accounts.directory.ByAPIKey(apiKey); !issued {
+			return s.requireAccountKey(ctx, accountID, permission)
+		}
+		key = accounts.Key{ID: apiKey, Permissions: account.Permissions}
+	}
+
+	var err error
+	switch {
+	case account.ID != accountID:
+		err = rejectf(RejectPermissionDenied, "API key is not issued to account %s", accountID)
+	case !key.Active():
+		err = rejectf(RejectPermissionDenied, "API key %s was revoked", key.ID)
+	case !key.Can(permission):
+		err = rejectf(RejectPermissionDenied, "API key lacks the %s permission", permission)
+	default:
+		return nil
+	}
+	keyID := apiKey
+	if !signed {
+		// Provisioned keys are secrets themselves; the trail names their account only
+		keyID = ""
+	}
+	s.recordDenial(AccessDenial{KeyID: keyID, AccountID: account.ID, Reason: RejectPermissionDenied, Permission: permission, Detail: err.Error()})
+	return err
+}
+
+// authorizeOwner checks the caller may manage accountID itself: the venue's own
+// calls, an operator presenting the admin token (any caller while ADMIN_TOKEN is
+// unset), or a key of the account with the trade permission
+func (s *ExchangeService) authorizeOwner(ctx context.Context, accountID string) error {
+	if !keystats.Tagged(ctx) || s.isOperator(ctx) {
+		return nil
+	}
+	apiKey := keystats.APIKey(ctx)
+	if _, _, issued := s.accounts.directory.ByKeyID(apiKey); !issued {
+		if _, provisioned := s.accounts.directory.ByAPIKey(apiKey); !provisioned {
+			err := rejectf(RejectUnauthenticated, "account %s is managed with its own API key or the admin token", accountID)
+			s.recordDenial(AccessDenial{AccountID: accountID, Reason: RejectUnauthenticated, Permission: accounts.PermissionTrade, Detail: err.Error()})
+			return err
+		}
+	}
+	return s.authorizeKey(ctx, accountID, accounts.PermissionTrade)
+}
+
+// requireAccountKey refuses a request reaching an account that holds active issued
+// keys without one of the venue's keys; the venue's own calls and operators pass
+func (s *ExchangeService) requireAccountKey(ctx context.Context, accountID string, permission accounts.Permission) error {
+	if !keystats.Tagged(ctx) || presentedAdminToken(ctx) {
+		return nil
+	}
+	account, err := s.accounts.directory.Get(accountID)
+	if err != nil || !slices.ContainsFunc(account.Keys, accounts.Key.Active) {
+		return nil
+	}
+	err = rejectf(RejectUnauthenticated, "account %s requires one of its API keys", accountID)
+	s.recordDenial(AccessDenial{AccountID: accountID, Reason: RejectUnauthenticated, Permission: permission, Detail: err.Error()})
+	return err
+}
+
+// isOperator reports whether a request presented the admin token, or none is set
+func (s *ExchangeService) isOperator(ctx context.Context) bool {
+	return s.config == nil || s.config.AdminToken == "" || presentedAdminToken(ctx)
+}
+
+func presentedAdminToken(ctx context.Context) bool {
+	operator, _ := ctx.Value(operatorContextKey{}).(bool)
+	return operator
+}
+
+func (s *ExchangeService) recordDenial(denial AccessDenial) {
+	denial.At = s.now()
+	s.logger.WithFields(logrus.Fields{
+		"key_id":     denial.KeyID,
+		"account":    denial.AccountID,
+		"transport":  denial.Transport,
+		"reason":     denial.Reason,
+		"permission": denial.Permission,
+	}).Warn("Access denied: " + denial.Detail)
+
+	log := s.access
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.denials = append(log.denials, denial)
+	if len(log.denials) > maxAccessDenials {
+		log.denials = log.denials[len(log.denials)-maxAccessDenials:]
+	}
+}
+
+func authRecvWindow(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.AuthRecvWindow <= 0 {
+		return defaultAuthRecvWindow
+	}
+	return cfg.AuthRecvWindow
+}
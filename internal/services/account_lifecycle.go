@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+)
+
+// maxProvisionedAccounts bounds one bulk provisioning call
+const maxProvisionedAccounts = 1000
+
+// accountRegistry serialises account changes so each is saved before it applies
+type accountRegistry struct {
+	directory *accounts.Directory
+	store     accounts.Store // nil keeps accounts in memory only
+	mu        sync.Mutex
+}
+
+func newAccountRegistry() *accountRegistry {
+	return &accountRegistry{directory: accounts.NewDirectory()}
+}
+
+// NewAccountRequest opens an account; the venue assigns its ID
+type NewAccountRequest struct {
+	Tier     string            `json:"tier"` // Default: standard
+	Metadata map[string]string `json:"metadata"`
+}
+
+// AccountUpdate reassigns an account's tier, metadata and leverage limit; nil fields
+// are kept
+type AccountUpdate struct {
+	Tier     *string           `json:"tier"`
+	Metadata map[string]string `json:"metadata"` // Replaces the metadata whole
+	Leverage *float64          `json:"leverage"` // 0 lifts the limit to each instrument's maximum
+}
+
+// ProvisionedAccount is an account opened from a template with its API key
+type ProvisionedAccount struct {
+	accounts.Account
+	APIKey string `json:"api_key"`
+}
+
+// AccountClosure is a closed account and the working orders canceled with it
+type AccountClosure struct {
+	accounts.Account
+	OrdersCanceled int `json:"orders_canceled"`
+}
+
+// SetAccountStore persists accounts to store and restores those it holds; set
+// before serving
+func (s *ExchangeService) SetAccountStore(store accounts.Store) error {
+	saved, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load accounts: %w", err)
+	}
+	for _, account := range saved {
+		s.accounts.directory.Put(account)
+	}
+	s.accounts.store = store
+	s.rebuildBalances()
+	s.logger.WithField("accounts", len(saved)).Info("Accounts restored")
+	return nil
+}
+
+// CreateAccount opens an active account under a newly generated UUID
+func (s *ExchangeService) CreateAccount(ctx context.Context, req NewAccountRequest) (accounts.Account, error) {
+	account, err := s.newAccount(accounts.Template{Tier: req.Tier, Metadata: req.Metadata})
+	if err != nil {
+		return accounts.Account{}, err
+	}
+
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if err := s.saveAccounts(account); err != nil {
+		return accounts.Account{}, err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"account": account.ID,
+		"tier":    account.Tier,
+	}).Info("Account created")
+	return account, nil
+}
+
+// ProvisionAccounts opens count accounts from one template, each with its own API
+// key. The keys are returned only here; the venue keeps their hashes. Either every
+// account is opened or none is.
+func (s *ExchangeService) ProvisionAccounts(ctx context.Context, template accounts.Template, count int) ([]ProvisionedAccount, error) {
+	if count <= 0 || count > maxProvisionedAccounts {
+		return nil, rejectf(RejectInvalidRequest, "count must be between 1 and %d", maxProvisionedAccounts)
+	}
+	if len(template.Permissions) == 0 {
+		template.Permissions = accounts.DefaultPermissions
+	}
+
+	provisioned := make([]ProvisionedAccount, 0, count)
+	batch := make([]accounts.Account, 0, count)
+	for i := 0; i < count; i++ {
+		account, err := s.newAccount(template)
+		if err != nil {
+			return nil, err
+		}
+		key, err := accounts.NewAPIKey()
+		if err != nil {
+			return nil, err
+		}
+		account.APIKeyHash = accounts.HashAPIKey(key)
+		provisioned = append(provisioned, ProvisionedAccount{Account: account, APIKey: key})
+		batch = append(batch, account)
+	}
+
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if err := s.saveAccounts(batch...); err != nil {
+		return nil, err
+	}
+	s.fundAccounts(ctx, batch...)
+	s.logger.WithFields(logrus.Fields{
+		"accounts": count,
+		"tier":     template.Tier,
+	}).Info("Accounts provisioned")
+	return provisioned, nil
+}
+
+// Account returns an account opened through CreateAccount
+func (s *ExchangeService) Account(ctx context.Context, accountID string) (accounts.Account, error) {
+	return s.accounts.directory.Get(accountID)
+}
+
+// Accounts lists opened accounts oldest first, optionally by status and tier
+func (s *ExchangeService) Accounts(ctx context.Context, status accounts.Status, tier string) ([]accounts.Account, error) {
+	if status != "" && status != accounts.StatusActive && status != accounts.StatusClosed {
+		return nil, rejectf(RejectInvalidRequest, "status must be %s or %s", accounts.StatusActive, accounts.StatusClosed)
+	}
+	return s.accounts.directory.List(status, tier), nil
+}
+
+// UpdateAccount reassigns an active account's tier, metadata and leverage limit. A
+// lower limit applies to new orders; positions already open are margined at it too.
+// It takes a key of the account with trade, or the admin token.
+func (s *ExchangeService) UpdateAccount(ctx context.Context, accountID string, update AccountUpdate) (accounts.Account, error) {
+	if err := s.authorizeOwner(ctx, accountID); err != nil {
+		return accounts.Account{}, err
+	}
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	account, err := registry.directory.Get(accountID)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	if account.Status == accounts.StatusClosed {
+		return accounts.Account{}, fmt.Errorf("%w: %s", accounts.ErrClosed, accountID)
+	}
+	if update.Tier != nil {
+		account.Tier = *update.Tier
+	}
+	if update.Metadata != nil {
+		account.Metadata = update.Metadata
+	}
+	if update.Leverage != nil {
+		account.Leverage = *update.Leverage
+	}
+	if err := account.Validate(); err != nil {
+		return accounts.Account{}, NewRejection(RejectInvalidRequest, err)
+	}
+	if err := s.saveAccounts(account); err != nil {
+		return accounts.Account{}, err
+	}
+	return account, nil
+}
+
+// CloseAccount closes an active account and cancels its working orders. Closed
+// accounts cannot place orders; their positions and history are kept. It takes a
+// key of the account with trade, or the admin token.
+func (s *ExchangeService) CloseAccount(ctx context.Context, accountID string) (AccountClosure, error) {
+	if err := s.authorizeOwner(ctx, accountID); err != nil {
+		return AccountClosure{}, err
+	}
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	account, err := registry.directory.Get(accountID)
+	if err != nil {
+		return AccountClosure{}, err
+	}
+	if account.Status == accounts.StatusClosed {
+		return AccountClosure{}, fmt.Errorf("%w: %s", accounts.ErrClosed, accountID)
+	}
+	closedAt := s.now()
+	account.Status, account.ClosedAt = accounts.StatusClosed, &closedAt
+	if err := s.saveAccounts(account); err != nil {
+		return AccountClosure{}, err
+	}
+
+	// Orders placed after the close are refused, so none are left working
+	canceled := s.cancelOpenOrders(ctx, accountID, cancelAccountClose)
+	s.logger.WithFields(logrus.Fields{
+		"account":  accountID,
+		"canceled": canceled,
+	}).Info("Account closed")
+	return AccountClosure{Account: account, OrdersCanceled: canceled}, nil
+}
+
+// forgetAccount deletes an erased account's record, including its metadata
+func (s *ExchangeService) forgetAccount(accountID string) error {
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.store != nil {
+		err := s.persist(componentAccounts, "delete", logrus.Fields{"account": accountID}, func() error {
+			return registry.store.Delete(accountID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	registry.directory.Remove(accountID)
+	return nil
+}
+
+// saveAccounts persists account changes, then applies them; hold the registry lock
+func (s *ExchangeService) saveAccounts(batch ...accounts.Account) error {
+	registry := s.accounts
+	if registry.store != nil {
+		err := s.persist(componentAccounts, "save", logrus.Fields{"accounts": len(batch)}, func() error {
+			return registry.store.Save(batch...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, account := range batch {
+		registry.directory.Put(account)
+	}
+	return nil
+}
+
+// newAccount builds an active account from a template under a new UUID
+func (s *ExchangeService) newAccount(template accounts.Template) (accounts.Account, error) {
+	if template.Tier == "" {
+		template.Tier = accounts.DefaultTier
+	}
+	if template.Metadata == nil {
+		template.Metadata = map[string]string{}
+	}
+	if err := template.Validate(); err != nil {
+		return accounts.Account{}, NewRejection(RejectInvalidRequest, err)
+	}
+	id, err := accounts.NewID()
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return accounts.Account{
+		ID:          id,
+		Tier:        template.Tier,
+		Metadata:    copyMetadata(template.Metadata),
+		Balances:    template.Balances,
+		Permissions: template.Permissions,
+		Leverage:    template.Leverage,
+		Status:      accounts.StatusActive,
+		CreatedAt:   s.now(),
+	}, nil
+}
+
+// copyMetadata gives each provisioned account its own metadata map to update
+func copyMetadata(metadata map[string]string) map[string]string {
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// AccountSnapshot is an account's balances, positions and working orders as of one
+// engine event, so a client can reconcile them against each other
+type AccountSnapshot struct {
+	AccountID  string            `json:"account_id"`
+	Sequence   uint64            `json:"sequence"` // Last engine event every part reflects; 0 while no event log is recorded
+	TakenAt    time.Time         `json:"taken_at"`
+	Balances   []ledger.Balance  `json:"balances"`  // Available and held per asset, from the orders below
+	Positions  []margin.Position `json:"positions"` // Derivative positions from the account's fills, valued at the current mark
+	OpenOrders []models.Order    `json:"open_orders"`
+}
+
+// AccountSnapshot reads an account's balances, positions and open orders at a single
+// engine sequence. Separate calls race with matching in between, so an order can
+// show filled while the balance it paid from does not; here every part is derived
+// from the same view of the books. Balances are opening balances plus spot fills,
+// less the holds of working orders, plus funding paid or received and liquidation
+// shortfalls the insurance fund covered.
+func (s *ExchangeService) AccountSnapshot(ctx context.Context, accountID string) (AccountSnapshot, error) {
+	if accountID == "" {
+		return AccountSnapshot{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return AccountSnapshot{}, err
+	}
+	view, err := s.engine.AccountView(accountID)
+	if err != nil {
+		return AccountSnapshot{}, err
+	}
+
+	now := s.now()
+	account, _ := s.accounts.directory.Get(accountID)
+	journal := ledger.NewJournal(s.balances.journal.ID())
+	for asset, amount := range account.Balances {
+		journal.Fund(accountID, asset, amount, now)
+	}
+	s.postOrderState(journal, view.FilledOrders, view.OpenOrders, now)
+	s.postInsurance(journal, accountID, now)
+	if payments, err := s.FundingPayments(ctx, accountID, ""); err == nil {
+		for _, payment := range payments {
+			if !payment.Posted {
+				continue
+			}
+			journal.Post(ledger.KindFunding, payment.Symbol, payment.FundingTime,
+				ledger.Leg{AccountID: accountID, Asset: payment.Asset, Bucket: ledger.BucketAvailable, Amount: -payment.Amount},
+				ledger.Leg{AccountID: ledger.SettlementAccount, Asset: payment.Asset, Bucket: ledger.BucketAvailable, Amount: payment.Amount})
+		}
+	}
+
+	book := margin.NewBook()
+	for _, order := range view.FilledOrders {
+		if instrument, err := s.instruments.Get(order.Symbol); err == nil && instrument.IsDerivative() {
+			book.Fill(order.Symbol, order.Side, order.FilledQuantity, order.AveragePrice)
+		}
+	}
+	positions := book.Positions()
+	for i := range positions {
+		instrument, _ := s.instruments.Get(positions[i].Symbol)
+		positions[i].Value(s.markOrReference(instrument), effectiveLeverage(account.Leverage, instrument), instrument.MaintenanceMarginRate)
+	}
+
+	return AccountSnapshot{
+		AccountID:  accountID,
+		Sequence:   view.Sequence,
+		TakenAt:    now,
+		Balances:   s.misreportBalances(ctx, accountID, journal.Balances(accountID)),
+		Positions:  positions,
+		OpenOrders: view.OpenOrders,
+	}, nil
+}
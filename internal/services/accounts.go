@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+var ErrPurgeIncomplete = errors.New("account purge incomplete")
+
+// AccountPurge is the tombstone of an erased account plus what was redacted in storage
+type AccountPurge struct {
+	matching.Tombstone
+	EventsRedacted int `json:"events_redacted"`
+}
+
+// PurgeAccount erases an account: its working orders are canceled, and every order
+// (in memory and in the event log) and surveillance flag is reassigned to a random
+// alias, and its account profile and record are dropped. Ledger positions are derived from orders,
+// so they follow the alias, as do journal balances and postings; statistics hold no account data. Purging again is safe and finishes a redaction that previously failed.
+func (s *ExchangeService) PurgeAccount(ctx context.Context, accountID string) (*AccountPurge, error) {
+	alias, err := newAccountAlias()
+	if err != nil {
+		return nil, err
+	}
+	open := s.engine.OpenOrders(accountID, "")
+	tombstone, err := s.engine.PurgeAccount(accountID, alias)
+	if err != nil {
+		return nil, err
+	}
+	purge := &AccountPurge{Tombstone: tombstone}
+	s.monitor.AnonymizeAccount(accountID, alias)
+	s.colocation.forget(accountID)
+	if err := s.forgetAccount(accountID); err != nil {
+		return purge, fmt.Errorf("%w: %v", ErrPurgeIncomplete, err)
+	}
+	if err := s.reassignBalances(accountID, alias); err != nil {
+		return purge, fmt.Errorf("%w: %v", ErrPurgeIncomplete, err)
+	}
+	canceled := make([]string, 0, len(open))
+	for _, order := range open {
+		canceled = append(canceled, order.ID)
+	}
+	if err := s.reassignOrders(accountID, alias, canceled); err != nil {
+		return purge, fmt.Errorf("%w: %v", ErrPurgeIncomplete, err)
+	}
+	s.positions.Reassign(accountID, alias)
+	s.reassignInsurance(accountID, alias)
+	// Canceled orders leave the books; order updates are not pushed for an erased account
+	for _, instrument := range s.instruments.List() {
+		s.publishBook(instrument.Symbol)
+	}
+
+	if s.eventLog != nil {
+		redacted, err := redactLog(s.eventLog, matching.AnonymizeAccount(accountID, alias))
+		purge.EventsRedacted = redacted
+		if err != nil {
+			return purge, err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"alias":             alias,
+		"orders_canceled":   purge.OrdersCanceled,
+		"orders_anonymized": purge.OrdersAnonymized,
+		"events_redacted":   purge.EventsRedacted,
+	}).Info("Account purged")
+	return purge, nil
+}
+
+// AccountTombstones lists every account purge since the event log began
+func (s *ExchangeService) AccountTombstones(ctx context.Context) []matching.Tombstone {
+	return s.engine.Tombstones()
+}
+
+// redactLog rewrites a log that supports redaction and fails for one that cannot
+func redactLog(log matching.EventLog, rewrite func(matching.Event) (matching.Event, bool)) (int, error) {
+	redactor, ok := log.(matching.EventRedactor)
+	if !ok {
+		return 0, fmt.Errorf("%w: event log cannot be redacted", ErrPurgeIncomplete)
+	}
+	redacted, err := redactor.Redact(rewrite)
+	if err != nil {
+		return redacted, fmt.Errorf("%w: %v", ErrPurgeIncomplete, err)
+	}
+	return redacted, nil
+}
+
+func newAccountAlias() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate account alias: %w", err)
+	}
+	return "anon-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/apiversion"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// APIVersionStatus is an API version's lifecycle, its phase on the venue clock and
+// the calls made to it since it was deprecated
+type APIVersionStatus struct {
+	apiversion.Lifecycle
+	Phase           apiversion.Phase `json:"phase"`
+	DeprecatedCalls int64            `json:"deprecated_calls"`
+	RetiredCalls    int64            `json:"retired_calls"` // Refused
+}
+
+// apiVersions holds the lifecycle scheduled for each API version; versions
+// without one are always active
+type apiVersions struct {
+	versions map[string]*APIVersionStatus
+	mu       sync.Mutex
+}
+
+func newAPIVersions() *apiVersions {
+	return &apiVersions{versions: make(map[string]*APIVersionStatus)}
+}
+
+// SetAPILifecycle schedules when an API version is deprecated and retired, replacing
+// any earlier schedule; the call counts are kept
+func (s *ExchangeService) SetAPILifecycle(ctx context.Context, lifecycle apiversion.Lifecycle) (APIVersionStatus, error) {
+	if err := lifecycle.Validate(); err != nil {
+		return APIVersionStatus{}, NewRejection(RejectInvalidRequest, err)
+	}
+	versions := s.apiVersions
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+
+	status, exists := versions.versions[lifecycle.Version]
+	if !exists {
+		status = &APIVersionStatus{}
+		versions.versions[lifecycle.Version] = status
+	}
+	status.Lifecycle = lifecycle
+	status.Phase = lifecycle.Phase(s.now())
+
+	s.logger.WithFields(logrus.Fields{
+		"version":       lifecycle.Version,
+		"deprecated_at": lifecycle.DeprecatedAt,
+		"sunset_at":     lifecycle.SunsetAt,
+		"successor":     lifecycle.Successor,
+	}).Info("API version lifecycle scheduled")
+	return *status, nil
+}
+
+// APIVersions lists every scheduled version's lifecycle, sorted by version
+func (s *ExchangeService) APIVersions(ctx context.Context) []APIVersionStatus {
+	versions := s.apiVersions
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+
+	now := s.now()
+	statuses := make([]APIVersionStatus, 0, len(versions.versions))
+	for _, status := range versions.versions {
+		status.Phase = status.Lifecycle.Phase(now)
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// CheckAPIVersion admits a call to an API version, counting calls to deprecated
+// versions and refusing those to retired ones with API_VERSION_RETIRED. Both are
+// recorded for scenario assertions.
+func (s *ExchangeService) CheckAPIVersion(ctx context.Context, version string) (APIVersionStatus, error) {
+	versions := s.apiVersions
+	versions.mu.Lock()
+	status, exists := versions.versions[version]
+	if !exists {
+		versions.mu.Unlock()
+		return APIVersionStatus{Lifecycle: apiversion.Lifecycle{Version: version}, Phase: apiversion.PhaseActive}, nil
+	}
+	now := s.now()
+	status.Phase = status.Lifecycle.Phase(now)
+	switch status.Phase {
+	case apiversion.PhaseDeprecated:
+		status.DeprecatedCalls++
+	case apiversion.PhaseRetired:
+		status.RetiredCalls++
+	}
+	checked := *status
+	versions.mu.Unlock()
+
+	if checked.Phase == apiversion.PhaseActive {
+		return checked, nil
+	}
+	s.recordScenario(scenario.Event{
+		Type:      scenario.EventAPICall,
+		Time:      now,
+		Component: "api:" + version,
+		Status:    string(checked.Phase),
+	})
+	if checked.Phase == apiversion.PhaseRetired {
+		return checked, rejectf(RejectAPIVersionRetired, "API %s was retired at %s", version, checked.SunsetAt.Format(time.RFC3339))
+	}
+	return checked, nil
+}
+
+// APILifecycleFromConfig builds v1's lifecycle from API_V1_* settings, with relative
+// times counted from start; false when neither time is set
+func APILifecycleFromConfig(cfg *config.Config, start time.Time) (apiversion.Lifecycle, bool, error) {
+	if cfg == nil || (cfg.APIV1DeprecatedAt == "" && cfg.APIV1SunsetAt == "") {
+		return apiversion.Lifecycle{}, false, nil
+	}
+	deprecatedAt, err := apiversion.ParseTime(cfg.APIV1DeprecatedAt, start)
+	if err != nil {
+		return apiversion.Lifecycle{}, false, err
+	}
+	sunsetAt, err := apiversion.ParseTime(cfg.APIV1SunsetAt, start)
+	if err != nil {
+		return apiversion.Lifecycle{}, false, err
+	}
+	return apiversion.Lifecycle{Version: "v1", DeprecatedAt: deprecatedAt, SunsetAt: sunsetAt, Successor: "v2"}, true, nil
+}
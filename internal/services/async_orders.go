@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// maxAsyncOrdersInFlight bounds submissions accepted but not yet acknowledged
+const maxAsyncOrdersInFlight = 1000
+
+// AsyncReceipt is returned as soon as an asynchronous order is received. Its ack,
+// carrying the same request sequence, follows on the execution stream.
+type AsyncReceipt struct {
+	RequestSequence uint64    `json:"request_sequence"`
+	ReceivedAt      time.Time `json:"received_at"`
+}
+
+type asyncOrders struct {
+	sequence atomic.Uint64
+	slots    chan struct{}
+	pending  sync.WaitGroup
+}
+
+func newAsyncOrders() *asyncOrders {
+	return &asyncOrders{slots: make(chan struct{}, maxAsyncOrdersInFlight)}
+}
+
+// asyncRequest rides the context of an asynchronous placement so placeOrder can ack
+// it ahead of the order updates and trades it causes
+type asyncRequest struct {
+	sequence uint64
+	acked    bool
+}
+
+type asyncRequestKey struct{}
+
+func asyncRequestFrom(ctx context.Context) *asyncRequest {
+	request, _ := ctx.Value(asyncRequestKey{}).(*asyncRequest)
+	return request
+}
+
+// SubmitOrder places an order without waiting for it: the receipt returns at once
+// and the order goes through the gateway, validation and matching afterwards. Acks
+// from one account may arrive out of submission order, as on a real venue.
+func (s *ExchangeService) SubmitOrder(ctx context.Context, req OrderRequest) (AsyncReceipt, error) {
+	if req.AccountID == "" {
+		return AsyncReceipt{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	select {
+	case s.asyncOrders.slots <- struct{}{}:
+	default:
+		return AsyncReceipt{}, rejectf(RejectEngineUnavailable, "%d asynchronous orders already awaiting ack", maxAsyncOrdersInFlight)
+	}
+
+	receipt := AsyncReceipt{RequestSequence: s.asyncOrders.sequence.Add(1), ReceivedAt: s.now()}
+	request := &asyncRequest{sequence: receipt.RequestSequence}
+	// The order outlives the call, but keeps its API and idempotency keys
+	placeCtx := context.WithValue(context.WithoutCancel(ctx), asyncRequestKey{}, request)
+
+	s.asyncOrders.pending.Add(1)
+	go func() {
+		defer s.asyncOrders.pending.Done()
+		defer func() { <-s.asyncOrders.slots }()
+
+		report, err := s.PlaceOrder(placeCtx, req)
+		if err != nil {
+			rejection := RejectionOf(err)
+			s.log(logfields.WithAccountID(placeCtx, req.AccountID)).WithFields(logrus.Fields{
+				"request_sequence": request.sequence,
+				"reason":           rejection.Reason,
+			}).Info("Asynchronous order rejected")
+			s.executions.AppendAck(s.now(), feed.OrderAck{
+				RequestSequence: request.sequence,
+				AccountID:       req.AccountID,
+				ClientOrderID:   req.ClientOrderID,
+				Symbol:          req.Symbol,
+				Reason:          string(rejection.Reason),
+				Message:         rejection.Message,
+			})
+			return
+		}
+		// Duplicates and idempotent replays never reach the publish step
+		if !request.acked {
+			s.ackAsync(placeCtx, report.Order)
+		}
+	}()
+	return receipt, nil
+}
+
+// WaitAsyncOrders blocks until every asynchronous order received so far is acked
+func (s *ExchangeService) WaitAsyncOrders() {
+	s.asyncOrders.pending.Wait()
+}
+
+// ackAsync journals the ack for an asynchronous placement; synchronous ones have none
+func (s *ExchangeService) ackAsync(ctx context.Context, order models.Order) {
+	request := asyncRequestFrom(ctx)
+	if request == nil {
+		return
+	}
+	request.acked = true
+	ack := feed.OrderAck{
+		RequestSequence: request.sequence,
+		AccountID:       order.AccountID,
+		ClientOrderID:   order.ClientOrderID,
+		Symbol:          order.Symbol,
+		OrderID:         order.ID,
+		Accepted:        order.Status != models.OrderStatusRejected,
+	}
+	if !ack.Accepted {
+		// The engine only rejects orders that cannot rest while a call auction runs
+		ack.Reason = string(RejectInvalidPhase)
+		ack.Message = "only resting orders are accepted during an auction"
+	}
+	s.executions.AppendAck(s.now(), ack)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// Why an order was canceled, on its audit event
+const (
+	cancelRequested    = "requested"
+	cancelLiquidation  = "liquidation"
+	cancelDisconnect   = "cancel_on_disconnect"
+	cancelAccountClose = "account_closed"
+	cancelAuction      = "auction_uncross"
+)
+
+// auditConfig sizes the audit pipeline from the configured buffer and batch
+func auditConfig(cfg *config.Config) audit.Config {
+	if cfg == nil {
+		return audit.DefaultConfig()
+	}
+	return audit.Config{
+		BufferSize:    cfg.AuditBufferSize,
+		BatchSize:     cfg.AuditBatchSize,
+		FlushInterval: cfg.AuditFlushInterval,
+		MaxAttempts:   cfg.AuditMaxAttempts,
+	}
+}
+
+// EnableAuditTrail sends an audit event for every order placement, amendment,
+// cancel and expiry, every fill and every deposit to sink. Events are buffered and
+// delivered by RunAuditTrail; set before serving.
+func (s *ExchangeService) EnableAuditTrail(sink audit.Sink) {
+	s.auditTrail = audit.NewPipeline(sink, auditConfig(s.config), s.config.GetMetricsPort())
+}
+
+// RunAuditTrail delivers buffered audit events until ctx is done, then flushes
+// what is left
+func (s *ExchangeService) RunAuditTrail(ctx context.Context) {
+	if s.auditTrail == nil {
+		return
+	}
+	s.auditTrail.Run(ctx, func(err error) {
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to deliver audit events")
+		}
+		s.reportOutcome(componentAuditSink, err)
+	})
+}
+
+// AuditTrailStats counts the audit events published, delivered, dropped and failed
+func (s *ExchangeService) AuditTrailStats(ctx context.Context) (audit.Stats, error) {
+	if s.auditTrail == nil {
+		return audit.Stats{}, rejectf(RejectInvalidRequest, "the audit trail is not enabled")
+	}
+	return s.auditTrail.Stats(), nil
+}
+
+// audit publishes events under the request's correlation ID, or a fresh one shared
+// by the events when the change was not caused by a request
+func (s *ExchangeService) audit(ctx context.Context, events ...audit.Event) {
+	if !s.auditing() || len(events) == 0 {
+		return
+	}
+	correlationID := audit.CorrelationID(ctx)
+	if correlationID == "" {
+		correlationID = audit.NewID()
+	}
+	now := s.now()
+	for i := range events {
+		events[i].ID, events[i].Source, events[i].CorrelationID = audit.NewID(), s.config.ServiceInstanceName, correlationID
+		if events[i].OccurredAt.IsZero() {
+			events[i].OccurredAt = now
+		}
+	}
+	if s.outbox != nil {
+		if err := s.outbox.queue(events); err != nil {
+			s.log(ctx).WithError(err).Error("Failed to queue audit events in the outbox")
+		}
+		return
+	}
+	for _, event := range events {
+		s.auditTrail.Publish(event)
+	}
+}
+
+// auditOrder publishes an order change; reason explains cancels
+func (s *ExchangeService) auditOrder(ctx context.Context, eventType string, order models.Order, reason string) {
+	if !s.auditing() {
+		return
+	}
+	attributes := map[string]string{
+		"side":            string(order.Side),
+		"type":            string(order.Type),
+		"time_in_force":   string(order.TimeInForce),
+		"status":          string(order.Status),
+		"quantity":        formatAmount(order.Quantity),
+		"price":           formatAmount(order.Price),
+		"filled_quantity": formatAmount(order.FilledQuantity),
+	}
+	if order.ClientOrderID != "" {
+		attributes["client_order_id"] = order.ClientOrderID
+	}
+	if reason != "" {
+		attributes["reason"] = reason
+	}
+	s.audit(ctx, audit.Event{
+		Type:       eventType,
+		AccountID:  order.AccountID,
+		Symbol:     order.Symbol,
+		OrderID:    order.ID,
+		Attributes: attributes,
+	})
+}
+
+// auditTrades publishes a fill per trade, attributed to the taker's order when it has
+// one; both sides are always in its attributes
+func (s *ExchangeService) auditTrades(ctx context.Context, trades []models.Trade) {
+	if !s.auditing() {
+		return
+	}
+	events := make([]audit.Event, 0, len(trades))
+	for _, trade := range trades {
+		event := audit.Event{
+			Type:       audit.TypeTradeExecuted,
+			Symbol:     trade.Symbol,
+			TradeID:    trade.ID,
+			OccurredAt: trade.ExecutedAt,
+			Attributes: map[string]string{
+				"price":           formatAmount(trade.Price),
+				"quantity":        formatAmount(trade.Quantity),
+				"buy_order_id":    trade.BuyOrderID,
+				"sell_order_id":   trade.SellOrderID,
+				"buy_account_id":  trade.BuyAccountID,
+				"sell_account_id": trade.SellAccountID,
+				"auction":         strconv.FormatBool(trade.Auction),
+				"deleverage":      strconv.FormatBool(trade.Deleverage),
+			},
+		}
+		switch trade.TakerSide {
+		case models.SideBuy:
+			event.AccountID, event.OrderID = trade.BuyAccountID, trade.BuyOrderID
+		case models.SideSell:
+			event.AccountID, event.OrderID = trade.SellAccountID, trade.SellOrderID
+		}
+		events = append(events, event)
+	}
+	s.audit(ctx, events...)
+}
+
+// auditDeposit publishes an account funding
+func (s *ExchangeService) auditDeposit(ctx context.Context, accountID, asset string, amount float64) {
+	s.audit(ctx, audit.Event{
+		Type:       audit.TypeDeposit,
+		AccountID:  accountID,
+		Attributes: map[string]string{"asset": asset, "amount": formatAmount(amount)},
+	})
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+// balanceJournal holds every account's balances as double-entry postings, and the
+// postings awaiting the balance store
+type balanceJournal struct {
+	journal *ledger.Journal
+	store   ledger.Store // nil keeps postings in memory only
+	pending []ledger.Posting
+	mu      sync.Mutex // Held for a whole flush so batches land in journal order
+	queueMu sync.Mutex // Guards pending; matching never waits on a flush
+	locks   sync.Map   // Account ID to *sync.Mutex; orders from a funded account are checked one at a time
+}
+
+func newBalanceJournal() *balanceJournal {
+	return &balanceJournal{journal: ledger.NewJournal(newJournalID())}
+}
+
+// newJournalID tells this run's postings apart from those of earlier runs
+func newJournalID() string {
+	id, err := accounts.NewID()
+	if err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000Z")
+	}
+	return id
+}
+
+// AccountBalances is an account's available and held balance per asset
+type AccountBalances struct {
+	AccountID string           `json:"account_id"`
+	Balances  []ledger.Balance `json:"balances"`
+	Holds     []ledger.Hold    `json:"holds"`
+}
+
+// TrialBalance sums every account's balances, contra accounts included, by asset
+type TrialBalance struct {
+	JournalID string           `json:"journal_id"`
+	Assets    []ledger.Balance `json:"assets"`
+	Balanced  bool             `json:"balanced"` // Every asset totals zero
+}
+
+// SetBalanceStore persists every journal posting to store; set before serving
+func (s *ExchangeService) SetBalanceStore(store ledger.Store) {
+	s.balances.store = store
+}
+
+// record queues postings for the next flush
+func (b *balanceJournal) record(postings []ledger.Posting) {
+	if b.store == nil || len(postings) == 0 {
+		return
+	}
+	b.queueMu.Lock()
+	b.pending = append(b.pending, postings...)
+	b.queueMu.Unlock()
+}
+
+// FlushBalanceJournal writes the postings made since the last flush. A failed batch
+// stays queued and is retried whole by the next flush.
+func (s *ExchangeService) FlushBalanceJournal() error {
+	balances := s.balances
+	balances.mu.Lock()
+	defer balances.mu.Unlock()
+	if balances.store == nil {
+		return nil
+	}
+
+	balances.queueMu.Lock()
+	batch := balances.pending
+	balances.pending = nil
+	balances.queueMu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := s.persist(componentBalances, "append", logrus.Fields{"postings": len(batch)}, func() error {
+		return balances.store.Append(batch)
+	})
+	if err != nil {
+		balances.queueMu.Lock()
+		balances.pending = append(batch, balances.pending...)
+		balances.queueMu.Unlock()
+	}
+	return err
+}
+
+// PersistBalanceJournal flushes the balance journal every interval until ctx is done
+func (s *ExchangeService) PersistBalanceJournal(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.FlushBalanceJournal(); err != nil {
+				s.logger.WithError(err).Warn("Failed to persist balance journal")
+			}
+		}
+	}
+}
+
+// AccountBalances returns an account's balances and the holds of its working orders
+func (s *ExchangeService) AccountBalances(ctx context.Context, accountID string) (AccountBalances, error) {
+	if accountID == "" {
+		return AccountBalances{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return AccountBalances{}, err
+	}
+	journal := s.balances.journal
+	balances := s.misreportBalances(ctx, accountID, journal.Balances(accountID))
+	return AccountBalances{AccountID: accountID, Balances: balances, Holds: journal.Holds(accountID)}, nil
+}
+
+// BalanceJournal returns up to limit of an account's latest postings, newest first;
+// empty accountID returns every account's
+func (s *ExchangeService) BalanceJournal(ctx context.Context, accountID string, limit int) ([]ledger.Posting, error) {
+	if limit < 0 {
+		return nil, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	if accountID != "" {
+		if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+	return s.balances.journal.Postings(accountID, limit), nil
+}
+
+// TrialBalance proves the journal balances: every asset sums to zero over all accounts
+func (s *ExchangeService) TrialBalance(ctx context.Context) TrialBalance {
+	journal := s.balances.journal
+	trial := TrialBalance{JournalID: journal.ID(), Assets: journal.TrialBalance(), Balanced: true}
+	for _, asset := range trial.Assets {
+		if math.Abs(asset.Total) > 1e-6 {
+			trial.Balanced = false
+		}
+	}
+	return trial
+}
+
+// fundAccounts credits accounts' opening balances
+func (s *ExchangeService) fundAccounts(ctx context.Context, batch ...accounts.Account) {
+	now := s.now()
+	for _, account := range batch {
+		for asset, amount := range account.Balances {
+			s.balances.record(s.balances.journal.Fund(account.ID, asset, amount, now))
+			s.auditDeposit(ctx, account.ID, asset, amount)
+		}
+	}
+}
+
+// lockBalance serialises a funded account's balance check with the hold its order
+// posts, so two orders cannot both commit the same balance. Accounts opened without
+// balances trade on unlimited credit and are not locked.
+func (s *ExchangeService) lockBalance(accountID string) (funded bool, unlock func()) {
+	if !s.isFunded(accountID) {
+		return false, func() {}
+	}
+	lock, _ := s.balances.locks.LoadOrStore(accountID, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return true, mu.Unlock
+}
+
+// reserveBalance refuses an order a funded account cannot pay for. The returned
+// unlock is called once the order's hold has been posted.
+func (s *ExchangeService) reserveBalance(req OrderRequest) (func(), error) {
+	funded, unlock := s.lockBalance(req.AccountID)
+	if !funded {
+		return unlock, nil
+	}
+	if err := s.checkOrderBalance(req); err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// checkOrderBalance refuses an order that would commit more than its account has
+// available; derivatives are checked against free margin instead
+func (s *ExchangeService) checkOrderBalance(req OrderRequest) error {
+	instrument, err := s.instruments.Get(req.Symbol)
+	if err != nil {
+		return nil
+	}
+	if instrument.IsDerivative() {
+		return s.checkOrderMargin(req, instrument, "")
+	}
+	asset, amount := instrument.BaseAsset, req.Quantity
+	if req.Side == models.SideBuy {
+		asset, amount = instrument.QuoteAsset, req.Quantity*req.Price
+		if req.Type == models.OrderTypeMarket {
+			amount = s.marketCost(req.Symbol, req.Quantity)
+		}
+	}
+	return s.checkAvailable(req.AccountID, asset, amount)
+}
+
+// isFunded reports whether an account is held to its opening balances
+func (s *ExchangeService) isFunded(accountID string) bool {
+	account, err := s.accounts.directory.Get(accountID)
+	return err == nil && len(account.Balances) > 0
+}
+
+// reserveAmend refuses an amendment that grows a funded account's hold past what it
+// has available, or its derivative order past the free margin
+func (s *ExchangeService) reserveAmend(orderID string, req matching.AmendRequest) (func(), error) {
+	order, err := s.engine.GetOrder(orderID)
+	if err != nil {
+		return func() {}, nil
+	}
+	funded, unlock := s.lockBalance(order.AccountID)
+	if !funded {
+		return unlock, nil
+	}
+	if req.Price != 0 {
+		order.Price = req.Price
+	}
+	if req.Quantity != 0 {
+		order.Quantity = req.Quantity
+	}
+	if instrument, err := s.instruments.Get(order.Symbol); err == nil && instrument.IsDerivative() {
+		amended := OrderRequest{AccountID: order.AccountID, Symbol: order.Symbol, Side: order.Side,
+			Type: order.Type, Quantity: order.RemainingQuantity(), Price: order.Price}
+		if amended.Quantity <= 0 {
+			return unlock, nil
+		}
+		if err := s.checkOrderMargin(amended, instrument, order.ID); err != nil {
+			unlock()
+			return nil, err
+		}
+		return unlock, nil
+	}
+	asset, amount := s.holdFor(order)
+	if asset == "" {
+		return unlock, nil
+	}
+	if err := s.checkAvailable(order.AccountID, asset, amount-s.balances.journal.Held(order.ID)); err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+func (s *ExchangeService) checkAvailable(accountID, asset string, amount float64) error {
+	available := s.balances.journal.Available(accountID, asset)
+	if amount > available+1e-9 {
+		return rejectf(RejectInsufficientBalance, "order needs %g %s but %g is available", amount, asset, available)
+	}
+	return nil
+}
+
+// marketCost estimates what a market buy pays by walking the resting asks
+func (s *ExchangeService) marketCost(symbol string, quantity float64) float64 {
+	book, err := s.engine.Snapshot(symbol, 0)
+	if err != nil {
+		return 0
+	}
+	cost := 0.0
+	for _, level := range book.Asks {
+		if quantity <= 0 {
+			break
+		}
+		filled := math.Min(quantity, level.Quantity)
+		cost += filled * level.Price
+		quantity -= filled
+	}
+	return cost
+}
+
+// holdFor is what a working order commits: the notional at its limit price for a
+// buy, the remaining quantity for a sell. Finished orders, market orders and
+// derivatives hold nothing.
+func (s *ExchangeService) holdFor(order models.Order) (string, float64) {
+	instrument, err := s.instruments.Get(order.Symbol)
+	if err != nil || instrument.IsDerivative() {
+		return "", 0
+	}
+	remaining := order.RemainingQuantity()
+	if order.Status.IsTerminal() || order.Type == models.OrderTypeMarket {
+		remaining = 0
+	}
+	if order.Side == models.SideBuy {
+		return instrument.QuoteAsset, remaining * order.Price
+	}
+	return instrument.BaseAsset, remaining
+}
+
+// syncHold moves an order's hold to what it still commits, releasing it once the
+// order leaves the book
+func (s *ExchangeService) syncHold(order models.Order) {
+	asset, amount := s.holdFor(order)
+	if asset == "" {
+		return
+	}
+	s.balances.record(s.balances.journal.Hold(order.ID, order.AccountID, asset, amount, s.now()))
+}
+
+// settleTrades posts spot trades between their buyers and sellers and queues them
+// for settlement
+func (s *ExchangeService) settleTrades(trades []models.Trade) {
+	for _, trade := range trades {
+		instrument, err := s.instruments.Get(trade.Symbol)
+		if err != nil || instrument.IsDerivative() {
+			continue
+		}
+		s.balances.record(s.balances.journal.Settle(ledger.Fill{
+			TradeID:       trade.ID,
+			BuyOrderID:    trade.BuyOrderID,
+			SellOrderID:   trade.SellOrderID,
+			BuyAccountID:  trade.BuyAccountID,
+			SellAccountID: trade.SellAccountID,
+			Base:          instrument.BaseAsset,
+			Quote:         instrument.QuoteAsset,
+			Quantity:      trade.Quantity,
+			Notional:      trade.Quantity * trade.Price,
+		}, trade.ExecutedAt))
+		s.queueSettlement(settlement.FillObligations(trade.ID, trade.BuyAccountID, trade.SellAccountID,
+			instrument.BaseAsset, instrument.QuoteAsset, trade.Quantity, trade.Quantity*trade.Price, trade.ExecutedAt))
+	}
+}
+
+// releaseOrders syncs the holds of orders that changed without an order update
+func (s *ExchangeService) releaseOrders(orderIDs []string) {
+	for _, orderID := range orderIDs {
+		if order, err := s.engine.GetOrder(orderID); err == nil {
+			s.syncHold(order)
+		}
+	}
+}
+
+// reassignBalances moves an erased account's balances and postings to its alias,
+// then releases the holds of the orders the purge canceled
+func (s *ExchangeService) reassignBalances(accountID, alias string) error {
+	balances := s.balances
+	balances.mu.Lock()
+	defer balances.mu.Unlock()
+
+	balances.journal.Reassign(accountID, alias)
+	balances.queueMu.Lock()
+	for i := range balances.pending {
+		if balances.pending[i].AccountID == accountID {
+			balances.pending[i].AccountID = alias
+		}
+	}
+	balances.queueMu.Unlock()
+	for _, hold := range balances.journal.Holds(alias) {
+		s.releaseOrders([]string{hold.OrderID})
+	}
+
+	if balances.store == nil {
+		return nil
+	}
+	return s.persist(componentBalances, "reassign", logrus.Fields{"account": alias}, func() error {
+		return balances.store.Reassign(accountID, alias)
+	})
+}
+
+// rebuildBalances replaces the journal with one rebuilt from order state: opening
+// balances, each order's fills through the clearing account and the holds of
+// working orders. Called as accounts or orders are restored, before serving.
+func (s *ExchangeService) rebuildBalances() {
+	journal := ledger.NewJournal(s.balances.journal.ID())
+	now := s.now()
+	postings := make([]ledger.Posting, 0)
+	for _, account := range s.accounts.directory.List("", "") {
+		for asset, amount := range account.Balances {
+			postings = append(postings, journal.Fund(account.ID, asset, amount, now)...)
+		}
+	}
+	postings = append(postings, s.postOrderState(journal, s.engine.FilledOrders(""), s.engine.OpenOrders("", ""), now)...)
+	postings = append(postings, s.postInsurance(journal, "", now)...)
+
+	balances := s.balances
+	balances.queueMu.Lock()
+	balances.journal = journal
+	balances.pending = nil
+	balances.queueMu.Unlock()
+	balances.record(postings)
+}
+
+// postOrderState posts spot fills through the clearing account and the holds of
+// working orders, as order state shows them
+func (s *ExchangeService) postOrderState(journal *ledger.Journal, filled, open []models.Order, at time.Time) []ledger.Posting {
+	postings := make([]ledger.Posting, 0)
+	for _, order := range filled {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || instrument.IsDerivative() {
+			continue
+		}
+		postings = append(postings, journal.Restore(order.ID, order.AccountID, instrument.BaseAsset, instrument.QuoteAsset,
+			order.Side, order.FilledQuantity, order.FilledQuantity*order.AveragePrice, at)...)
+	}
+	for _, order := range open {
+		if asset, amount := s.holdFor(order); asset != "" {
+			postings = append(postings, journal.Hold(order.ID, order.AccountID, asset, amount, at)...)
+		}
+	}
+	return postings
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// defaultCandleLimit is how many bars a query without a limit returns
+const defaultCandleLimit = 500
+
+// CandleQuery selects up to Limit bars of a symbol, defaulting to defaultCandleLimit. With Start set the earliest bars
+// from Start are returned, otherwise the latest bars up to End; a zero End is now.
+type CandleQuery struct {
+	Symbol   string
+	Interval marketdata.Interval
+	Start    time.Time
+	End      time.Time
+	Limit    int
+}
+
+// candleArchive remembers the newest bar archived per series, so each run appends
+// only bars that closed since the last
+type candleArchive struct {
+	archive  marketdata.CandleArchive // nil keeps history in memory only; set before serving
+	archived map[string]time.Time     // Open time of the newest archived bar, by series
+	mu       sync.Mutex               // Held for a whole run
+}
+
+func newCandleArchive() *candleArchive {
+	return &candleArchive{archived: make(map[string]time.Time)}
+}
+
+// SetCandleArchive keeps closed bars in archive once ArchiveCandles runs, and reads
+// history older than the tracker retains from it
+func (s *ExchangeService) SetCandleArchive(archive marketdata.CandleArchive) {
+	s.candles.archive = archive
+}
+
+// CandleHistory returns bars for a listed symbol, merging archived history with the
+// bars still held in memory
+func (s *ExchangeService) CandleHistory(ctx context.Context, query CandleQuery) ([]marketdata.Candle, error) {
+	if _, err := s.instruments.Get(query.Symbol); err != nil {
+		return nil, err
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultCandleLimit
+	}
+	if query.Limit < 0 || query.Limit > marketdata.MaxCandles {
+		return nil, rejectf(RejectInvalidRequest, "limit must be between 1 and %d", marketdata.MaxCandles)
+	}
+	if !query.Start.IsZero() && !query.End.IsZero() && query.End.Before(query.Start) {
+		return nil, rejectf(RejectInvalidRequest, "end must not be before start")
+	}
+
+	// Bars open on aligned slots, so limit bars from either end fit in limit slots
+	window := query.Interval.Duration() * time.Duration(query.Limit)
+	from, to := query.Start, query.End
+	if to.IsZero() {
+		to = s.now()
+	}
+	if from.IsZero() {
+		from = to.UTC().Truncate(query.Interval.Duration()).Add(-window + query.Interval.Duration())
+	} else if end := from.Add(window); end.Before(to) {
+		to = end
+	}
+
+	byOpen := make(map[int64]marketdata.Candle)
+	if archive := s.candles.archive; archive != nil {
+		var archived []marketdata.Candle
+		err := s.timeStorage(componentCandles, "query", logrus.Fields{"symbol": query.Symbol, "interval": query.Interval}, func() (err error) {
+			archived, err = archive.Candles(query.Symbol, query.Interval, from, to)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, candle := range archived {
+			byOpen[candle.OpenTime.UnixNano()] = candle
+		}
+	}
+	// Bars in memory are at least as recent as their archived copies
+	for _, candle := range s.statistics.Candles(query.Symbol, query.Interval, 0) {
+		if !candle.OpenTime.Before(from) && !candle.OpenTime.After(to) {
+			byOpen[candle.OpenTime.UnixNano()] = candle
+		}
+	}
+
+	candles := make([]marketdata.Candle, 0, len(byOpen))
+	for _, candle := range byOpen {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+	if len(candles) > query.Limit {
+		if query.Start.IsZero() {
+			candles = candles[len(candles)-query.Limit:]
+		} else {
+			candles = candles[:query.Limit]
+		}
+	}
+	return candles, nil
+}
+
+// ArchiveCandles appends every bar that closed since the last run to the archive
+func (s *ExchangeService) ArchiveCandles() error {
+	archive := s.candles
+	archive.mu.Lock()
+	defer archive.mu.Unlock()
+	if archive.archive == nil {
+		return nil
+	}
+
+	now := s.now()
+	var failed error
+	for _, instrument := range s.instruments.List() {
+		for _, interval := range marketdata.Intervals {
+			series := instrument.Symbol + ":" + string(interval)
+			closed := make([]marketdata.Candle, 0)
+			for _, candle := range s.statistics.Candles(instrument.Symbol, interval, 0) {
+				if candle.OpenTime.After(archive.archived[series]) && !candle.CloseTime.After(now) {
+					closed = append(closed, candle)
+				}
+			}
+			if len(closed) == 0 {
+				continue
+			}
+			err := s.timeStorage(componentCandles, "append", logrus.Fields{"series": series, "candles": len(closed)}, func() error {
+				return archive.archive.Append(instrument.Symbol, interval, closed)
+			})
+			if err != nil {
+				failed = err
+				continue
+			}
+			archive.archived[series] = closed[len(closed)-1].OpenTime
+		}
+	}
+	s.reportOutcome(componentCandles, failed)
+	return failed
+}
+
+// PersistCandles archives closed bars every interval until ctx is done
+func (s *ExchangeService) PersistCandles(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ArchiveCandles(); err != nil {
+				s.logger.WithError(err).Warn("Failed to archive candles")
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+)
+
+// chaosDesk holds the injected faults, the symbols each halt fault halted and the
+// reports misbehaviour faults are falsifying
+type chaosDesk struct {
+	injector   *chaos.Injector
+	seed       int64
+	halted     map[string][]string   // Fault ID to the symbols it halted, resumed when it ends
+	overfilled map[string]overfilled // Order ID to the overfill fault that drew it
+	release    chan struct{}         // Closed when a stall_cancels fault ends, releasing the cancels it held
+	mu         sync.Mutex
+}
+
+// EnableChaos serves the chaos API, drawing which orders and updates faults hit from
+// seed (0 = random); set before serving
+func (s *ExchangeService) EnableChaos(seed int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s.chaos = &chaosDesk{
+		injector:   chaos.NewInjector(seed),
+		seed:       seed,
+		halted:     make(map[string][]string),
+		overfilled: make(map[string]overfilled),
+		release:    make(chan struct{}),
+	}
+}
+
+// InjectFault starts a fault. Halting symbols and corrupting a balance take effect
+// straight away; the other faults act on the orders and updates that follow.
+// Misbehaviours are flagged on the audit trail when they start and end, and on each
+// report they falsify.
+func (s *ExchangeService) InjectFault(ctx context.Context, spec chaos.Spec) (chaos.Fault, error) {
+	if s.chaos == nil {
+		return chaos.Fault{}, rejectf(RejectInvalidRequest, "chaos injection is not enabled")
+	}
+	for _, symbol := range spec.Symbols {
+		if _, err := s.instruments.Get(symbol); err != nil {
+			return chaos.Fault{}, err
+		}
+	}
+	fault, err := s.chaos.injector.Inject(spec, s.now())
+	if err != nil {
+		return chaos.Fault{}, NewRejection(RejectInvalidRequest, err)
+	}
+
+	switch fault.Kind {
+	case chaos.KindHaltSymbol:
+		halted := make([]string, 0, len(fault.Symbols))
+		for _, symbol := range fault.Symbols {
+			// Symbols already halted stay as they were when the fault ends
+			if _, err := s.HaltTrading(ctx, symbol); err == nil {
+				halted = append(halted, symbol)
+			}
+		}
+		s.chaos.mu.Lock()
+		s.chaos.halted[fault.ID] = halted
+		s.chaos.mu.Unlock()
+	case chaos.KindCorruptBalances:
+		s.balances.record(s.balances.journal.Corrupt(fault.AccountID, fault.Asset, fault.Amount, s.now()))
+	}
+	if fault.Kind.Misbehavior() {
+		s.startMisbehavior(ctx, fault)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"fault_id": fault.ID,
+		"kind":     fault.Kind,
+		"symbols":  fault.Symbols,
+		"rate":     fault.Rate,
+		"expires":  fault.ExpiresAt,
+	}).Warn("Fault injected")
+	return fault, nil
+}
+
+// ChaosFaults lists the faults in force
+func (s *ExchangeService) ChaosFaults(ctx context.Context) ([]chaos.Fault, error) {
+	if s.chaos == nil {
+		return nil, rejectf(RejectInvalidRequest, "chaos injection is not enabled")
+	}
+	return s.chaos.injector.Active(s.now()), nil
+}
+
+// ClearFault ends a fault early
+func (s *ExchangeService) ClearFault(ctx context.Context, faultID string) (chaos.Fault, error) {
+	if s.chaos == nil {
+		return chaos.Fault{}, rejectf(RejectInvalidRequest, "chaos injection is not enabled")
+	}
+	fault, err := s.chaos.injector.Clear(faultID)
+	if err != nil {
+		return chaos.Fault{}, err
+	}
+	s.endFault(ctx, fault, "cleared")
+	return fault, nil
+}
+
+// expireFaults ends the faults that have run their course
+func (s *ExchangeService) expireFaults(ctx context.Context) {
+	if s.chaos == nil {
+		return
+	}
+	for _, fault := range s.chaos.injector.Expire(s.now()) {
+		s.endFault(ctx, fault, "expired")
+	}
+}
+
+// endFault resumes the symbols a fault halted and flags the end of a misbehaviour
+func (s *ExchangeService) endFault(ctx context.Context, fault chaos.Fault, how string) {
+	s.chaos.mu.Lock()
+	halted := s.chaos.halted[fault.ID]
+	delete(s.chaos.halted, fault.ID)
+	s.chaos.mu.Unlock()
+	for _, symbol := range halted {
+		if err := s.ResumeTrading(ctx, symbol); err != nil {
+			s.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to resume a symbol halted by a fault")
+		}
+	}
+	if fault.Kind.Misbehavior() {
+		s.endMisbehavior(ctx, fault)
+	}
+	s.logger.WithFields(logrus.Fields{
+		"fault_id": fault.ID,
+		"kind":     fault.Kind,
+		"hits":     fault.Hits,
+	}).Info("Fault " + how)
+}
+
+// injectOrderFaults rejects a new order or holds it back before matching when a
+// fault draws it
+func (s *ExchangeService) injectOrderFaults(ctx context.Context, symbol string) error {
+	if s.chaos == nil {
+		return nil
+	}
+	if fault, rejected := s.chaos.injector.Reject(symbol, s.now()); rejected {
+		reason := fault.Reason
+		if reason == "" {
+			reason = "order rejected by injected fault " + fault.ID
+		}
+		return rejectf(RejectEngineUnavailable, "%s", reason)
+	}
+	delay := s.chaos.injector.Delay(symbol, s.now())
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dropMarketData reports whether a fault or frozen market data loses a public market
+// data update for symbol
+func (s *ExchangeService) dropMarketData(symbol string) bool {
+	if s.frozenMarketData() {
+		return true
+	}
+	return s.chaos != nil && s.chaos.injector.Drop(symbol, s.now())
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrAccountProfileNotFound is returned for accounts trading on the default profile
+var ErrAccountProfileNotFound = errors.New("account profile not found")
+
+// maxGatewayLatency bounds the delay one profile can add to each order action
+const maxGatewayLatency = 5 * time.Second
+
+// AccountProfile places a participant relative to the matching engine. A colocated
+// account has little or no gateway latency and may sit in a higher queue tier; remote
+// accounts see their orders, cancels and amends arrive later.
+type AccountProfile struct {
+	AccountID string        `json:"account_id"`
+	Latency   time.Duration `json:"latency"`  // Added before each order action reaches the engine
+	Priority  int           `json:"priority"` // Queue tier stamped on new orders; 0 is plain price-time
+}
+
+type colocation struct {
+	profiles map[string]AccountProfile
+	venue    LatencyProfile // Latency of accounts without a profile
+	mu       sync.RWMutex
+}
+
+func newColocation() *colocation {
+	return &colocation{profiles: make(map[string]AccountProfile)}
+}
+
+func (c *colocation) get(accountID string) AccountProfile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.profiles[accountID]
+}
+
+// latency draws the gateway latency of one of accountID's order actions
+func (c *colocation) latency(accountID string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if profile, exists := c.profiles[accountID]; exists {
+		return profile.Latency
+	}
+	return c.venue.draw()
+}
+
+func (c *colocation) setVenueLatency(profile LatencyProfile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.venue = profile
+}
+
+func (c *colocation) forget(accountID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, exists := c.profiles[accountID]
+	delete(c.profiles, accountID)
+	return exists
+}
+
+// SetAccountProfile assigns an account's gateway latency and queue tier. The tier
+// applies to orders placed afterwards; orders already resting keep their place.
+func (s *ExchangeService) SetAccountProfile(ctx context.Context, profile AccountProfile) (AccountProfile, error) {
+	if profile.AccountID == "" {
+		return AccountProfile{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if profile.Latency < 0 || profile.Latency > maxGatewayLatency {
+		return AccountProfile{}, rejectf(RejectInvalidRequest, "latency must be between 0 and %s", maxGatewayLatency)
+	}
+	if profile.Priority < 0 {
+		return AccountProfile{}, rejectf(RejectInvalidRequest, "priority must not be negative")
+	}
+
+	s.colocation.mu.Lock()
+	s.colocation.profiles[profile.AccountID] = profile
+	s.colocation.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"account":  profile.AccountID,
+		"latency":  profile.Latency,
+		"priority": profile.Priority,
+	}).Info("Account profile set")
+	return profile, nil
+}
+
+// RemoveAccountProfile returns an account to zero latency and plain price-time priority
+func (s *ExchangeService) RemoveAccountProfile(ctx context.Context, accountID string) error {
+	if !s.colocation.forget(accountID) {
+		return fmt.Errorf("%w: %s", ErrAccountProfileNotFound, accountID)
+	}
+	s.logger.WithField("account", accountID).Info("Account profile removed")
+	return nil
+}
+
+// AccountProfiles lists every account not on the default profile, sorted by account
+func (s *ExchangeService) AccountProfiles(ctx context.Context) []AccountProfile {
+	s.colocation.mu.RLock()
+	defer s.colocation.mu.RUnlock()
+
+	profiles := make([]AccountProfile, 0, len(s.colocation.profiles))
+	for _, profile := range s.colocation.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].AccountID < profiles[j].AccountID })
+	return profiles
+}
+
+// traverseGateway holds an order action for the account's latency, or the venue's
+// for accounts without a profile. The delay runs on the wall clock, since it models
+// transport rather than venue time.
+func (s *ExchangeService) traverseGateway(ctx context.Context, accountID string) error {
+	latency := s.colocation.latency(accountID)
+	if latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ParseAccountProfiles reads profiles in the ACCOUNT_PROFILES form
+// "account=latency[:priority],...", e.g. "mm-colo=50us:1,retail-1=20ms"
+func ParseAccountProfiles(spec string) ([]AccountProfile, error) {
+	profiles := make([]AccountProfile, 0)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		accountID, settings, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(accountID) == "" {
+			return nil, fmt.Errorf("invalid account profile %q: expected account=latency[:priority]", entry)
+		}
+		latencySpec, prioritySpec, hasPriority := strings.Cut(settings, ":")
+		latency, err := time.ParseDuration(strings.TrimSpace(latencySpec))
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency in account profile %q: %w", entry, err)
+		}
+		profile := AccountProfile{AccountID: strings.TrimSpace(accountID), Latency: latency}
+		if hasPriority {
+			if profile.Priority, err = strconv.Atoi(strings.TrimSpace(prioritySpec)); err != nil {
+				return nil, fmt.Errorf("invalid priority in account profile %q: %w", entry, err)
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
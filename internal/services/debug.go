@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// EngineStats is how the matching engine is keeping up, for operators debugging a
+// long-running session
+type EngineStats struct {
+	Sequence   uint64                `json:"sequence"` // Events the engine has recorded
+	Books      int                   `json:"books"`
+	Queues     []matching.QueueDepth `json:"queues"`     // Commands waiting per book
+	Goroutines int                   `json:"goroutines"` // In the whole process
+}
+
+// DebugState is what is being done to the venue right now: its degradation mode,
+// the faults and halts in force and the scenario, script and replay under way.
+// Parts not enabled or never started are left out.
+type DebugState struct {
+	Degradation DegradationStatus   `json:"degradation"`
+	Faults      []chaos.Fault       `json:"faults,omitempty"`
+	Halts       []matching.HaltInfo `json:"halts"`
+	Verdict     *scenario.Verdict   `json:"verdict,omitempty"`
+	Script      *scenario.Progress  `json:"script,omitempty"`
+	Replay      *replay.Progress    `json:"replay,omitempty"`
+}
+
+// EngineStats reports the engine's sequence and how far each book is behind
+func (s *ExchangeService) EngineStats(ctx context.Context) EngineStats {
+	queues := s.engine.QueueDepths()
+	return EngineStats{
+		Sequence:   s.engine.Sequence(),
+		Books:      len(queues),
+		Queues:     queues,
+		Goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// DumpBook copies every order resting in a listed symbol's book, in priority order
+func (s *ExchangeService) DumpBook(ctx context.Context, symbol string) (matching.BookDump, error) {
+	if _, err := s.instruments.Get(symbol); err != nil {
+		return matching.BookDump{}, err
+	}
+	return s.engine.Dump(symbol)
+}
+
+// DebugState gathers the chaos and scenario state in force
+func (s *ExchangeService) DebugState(ctx context.Context) DebugState {
+	state := DebugState{
+		Degradation: s.DegradationMode(ctx),
+		Halts:       s.engine.Halts(),
+	}
+	if faults, err := s.ChaosFaults(ctx); err == nil {
+		state.Faults = faults
+	}
+	if verdict, err := s.ScenarioVerdict(ctx, false); err == nil {
+		state.Verdict = &verdict
+	}
+	if progress, err := s.ScriptProgress(ctx); err == nil {
+		state.Script = &progress
+	}
+	if progress, err := s.ReplayProgress(ctx); err == nil {
+		state.Replay = &progress
+	}
+	return state
+}
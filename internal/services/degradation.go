@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+)
+
+// ComponentVenueMode is the venue's degradation mode on the incident timeline
+const ComponentVenueMode = "venue:mode"
+
+// degradation is the partial outage the venue is simulating, with the market data
+// frozen when the mode began
+type degradation struct {
+	mode    chaos.Mode
+	since   time.Time
+	refused int64
+	books   map[string]OrderBookSnapshot // Full depth; nil unless market data is frozen
+	tickers map[string]marketdata.Ticker
+	mu      sync.RWMutex
+}
+
+// DegradationStatus is the mode in force and how many requests it has refused
+type DegradationStatus struct {
+	Mode    chaos.Mode `json:"mode"`
+	Since   time.Time  `json:"since"`
+	Refused int64      `json:"refused"` // Since the mode began
+}
+
+func newDegradation() *degradation {
+	return &degradation{mode: chaos.ModeNormal}
+}
+
+// SetDegradationMode switches the venue into mode. Market data frozen by the mode
+// is the book and ticker of each symbol as it stands now.
+func (s *ExchangeService) SetDegradationMode(ctx context.Context, mode chaos.Mode) (DegradationStatus, error) {
+	if _, err := chaos.ParseMode(string(mode)); err != nil {
+		return DegradationStatus{}, NewRejection(RejectInvalidRequest, err)
+	}
+	var books map[string]OrderBookSnapshot
+	var tickers map[string]marketdata.Ticker
+	if mode.FreezesMarketData() {
+		books, tickers = s.freezeMarketData(ctx)
+	}
+
+	d := s.degradation
+	d.mu.Lock()
+	previous := d.mode
+	if mode != previous {
+		d.mode, d.since, d.refused = mode, s.now(), 0
+		// Market data already frozen stays as it was when it froze
+		if d.books == nil || !mode.FreezesMarketData() {
+			d.books, d.tickers = books, tickers
+		}
+	}
+	status := DegradationStatus{Mode: d.mode, Since: d.since, Refused: d.refused}
+	d.mu.Unlock()
+	if mode == previous {
+		return status, nil
+	}
+
+	s.reportModeMetrics(mode)
+	switch {
+	case mode == chaos.ModeNormal:
+		s.ReportHealth(ctx, ComponentVenueMode, incidents.StatusUp, "")
+	case mode == chaos.ModeBlackout:
+		s.ReportHealth(ctx, ComponentVenueMode, incidents.StatusDown, string(mode))
+	default:
+		s.ReportHealth(ctx, ComponentVenueMode, incidents.StatusDegraded, string(mode))
+	}
+	s.logger.WithFields(logrus.Fields{"mode": mode, "previous": previous}).Warn("Venue degradation mode switched")
+	return status, nil
+}
+
+// DegradationMode reports the mode in force
+func (s *ExchangeService) DegradationMode(ctx context.Context) DegradationStatus {
+	d := s.degradation
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return DegradationStatus{Mode: d.mode, Since: d.since, Refused: d.refused}
+}
+
+// Admit refuses op with ENGINE_UNAVAILABLE while the degradation mode does not serve it
+func (s *ExchangeService) Admit(op chaos.Operation) error {
+	mode, err := s.admission(op)
+	if err == nil {
+		return nil
+	}
+	d := s.degradation
+	d.mu.Lock()
+	d.refused++
+	d.mu.Unlock()
+	if metrics := s.config.GetMetricsPort(); metrics != nil {
+		metrics.IncCounter("exchange_degradation_refused_total", map[string]string{"mode": string(mode), "operation": string(op)})
+	}
+	return err
+}
+
+// admission is Admit's verdict on op without counting a refusal, for checks that
+// carry nothing out
+func (s *ExchangeService) admission(op chaos.Operation) (chaos.Mode, error) {
+	d := s.degradation
+	d.mu.RLock()
+	mode := d.mode
+	d.mu.RUnlock()
+	if mode.Allows(op) {
+		return mode, nil
+	}
+	switch mode {
+	case chaos.ModeReadOnly:
+		return mode, rejectf(RejectEngineUnavailable, "the venue is read-only")
+	case chaos.ModeCancelOnly:
+		return mode, rejectf(RejectEngineUnavailable, "the venue is accepting cancels only")
+	}
+	return mode, rejectf(RejectEngineUnavailable, "the venue is unavailable")
+}
+
+// reportModeMetrics sets the mode gauge to 1 for the mode in force and 0 for the rest
+func (s *ExchangeService) reportModeMetrics(mode chaos.Mode) {
+	metrics := s.config.GetMetricsPort()
+	if metrics == nil {
+		return
+	}
+	for _, each := range chaos.Modes {
+		value := 0.0
+		if each == mode {
+			value = 1
+		}
+		metrics.SetGauge("exchange_degradation_mode", value, map[string]string{"mode": string(each)})
+	}
+}
+
+// freezeMarketData captures every symbol's full book and ticker
+func (s *ExchangeService) freezeMarketData(ctx context.Context) (map[string]OrderBookSnapshot, map[string]marketdata.Ticker) {
+	instruments := s.instruments.List()
+	books := make(map[string]OrderBookSnapshot, len(instruments))
+	tickers := make(map[string]marketdata.Ticker, len(instruments))
+	for _, instrument := range instruments {
+		if book, err := s.OrderBook(ctx, instrument.Symbol, 0); err == nil {
+			books[instrument.Symbol] = book
+		}
+		tickers[instrument.Symbol] = s.ticker(instrument.Symbol)
+	}
+	return books, tickers
+}
+
+// frozenMarketData reports whether public market data is frozen
+func (s *ExchangeService) frozenMarketData() bool {
+	d := s.degradation
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.mode.FreezesMarketData()
+}
+
+// frozenBook returns symbol's book as it was when market data froze
+func (s *ExchangeService) frozenBook(symbol string, depth int) (OrderBookSnapshot, bool) {
+	d := s.degradation
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	book, ok := d.books[symbol]
+	if !ok || !d.mode.FreezesMarketData() {
+		return OrderBookSnapshot{}, false
+	}
+	return OrderBookSnapshot{BookSnapshot: truncateBook(book.BookSnapshot, depth), Sequence: book.Sequence}, true
+}
+
+// frozenTicker returns symbol's ticker as it was when market data froze
+func (s *ExchangeService) frozenTicker(symbol string) (marketdata.Ticker, bool) {
+	d := s.degradation
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	ticker, ok := d.tickers[symbol]
+	return ticker, ok && d.mode.FreezesMarketData()
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// DryRunResult is what placing an order would do now. The order is as it would
+// stand after matching, and the trades are the fills it would take from the book
+// as it stands; neither carries an ID because nothing was placed.
+type DryRunResult struct {
+	matching.ExecutionReport
+	DryRun bool `json:"dry_run"`
+}
+
+// DryRunOrder runs the key, pre-trade and balance checks PlaceOrder would apply
+// and estimates the order's fills against the current book, changing nothing: no
+// order is placed, no balance is held and no key statistics are counted
+func (s *ExchangeService) DryRunOrder(ctx context.Context, req OrderRequest) (*DryRunResult, error) {
+	if err := s.authorizeKey(ctx, req.AccountID, accounts.PermissionTrade); err != nil {
+		return nil, err
+	}
+	if err := s.validateOrder(req); err != nil {
+		return nil, err
+	}
+	if s.isFunded(req.AccountID) {
+		if err := s.checkOrderBalance(req); err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := s.engine.DryRun(models.Order{
+		ClientOrderID: req.ClientOrderID,
+		AccountID:     req.AccountID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   req.TimeInForce,
+		Quantity:      req.Quantity,
+		Price:         req.Price,
+		ExpiresAt:     req.ExpiresAt,
+		Priority:      s.colocation.get(req.AccountID).Priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DryRunResult{ExecutionReport: *report, DryRun: true}, nil
+}
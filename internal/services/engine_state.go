@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// RestoreFromState replaces the engine with one rebuilt from a saved state and the
+// events recorded after it, then records every further mutation to log, which may
+// be nil when nothing journals the engine. Positions and balances are rebuilt from
+// the restored orders' fills, as after a replay. A zero state, as a store holding
+// none returns, restores from the events alone.
+func (s *ExchangeService) RestoreFromState(state matching.EngineState, events []matching.Event, log matching.EventLog) error {
+	if state.TakenAt.IsZero() {
+		if log == nil {
+			return nil
+		}
+		return s.RestoreFromEventLog(events, log)
+	}
+
+	engine, err := matching.Restore(state, events)
+	if err != nil {
+		return err
+	}
+	if err := s.useRestoredEngine(engine, log); err != nil {
+		return err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"taken_at":    state.TakenAt,
+		"books":       len(state.Books),
+		"replayed":    engine.Sequence() - state.Sequence,
+		"sequence":    engine.Sequence(),
+		"open_orders": len(engine.OpenOrders("", "")),
+	}).Info("Matching engine restored from state snapshot")
+	return nil
+}
+
+// SaveState saves the engine's books, orders and trade history to store
+func (s *ExchangeService) SaveState(store matching.StateStore) error {
+	state, err := s.engine.State()
+	if err != nil {
+		return err
+	}
+	return s.persist(componentState, "save", logrus.Fields{"sequence": state.Sequence, "books": len(state.Books)}, func() error {
+		return store.Save(state)
+	})
+}
+
+// PersistState saves the engine state every interval until ctx is done
+func (s *ExchangeService) PersistState(ctx context.Context, store matching.StateStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SaveState(store); err != nil {
+				s.logger.WithError(err).Warn("Failed to persist matching engine state")
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+)
+
+// Entitlements returns the registry counting each API key's open streams and subscriptions
+func (s *ExchangeService) Entitlements() *entitlements.Registry {
+	return s.entitlements
+}
+
+// OpenStream counts a WebSocket or gRPC stream against the caller's API key,
+// refusing it with SUBSCRIPTION_LIMIT_EXCEEDED beyond the key's stream limit.
+// Close the lease when the stream ends.
+func (s *ExchangeService) OpenStream(ctx context.Context) (*entitlements.Lease, error) {
+	lease, err := s.entitlements.Open(keystats.APIKey(ctx))
+	if err != nil {
+		return nil, NewRejection(RejectSubscriptionLimit, err)
+	}
+	return lease, nil
+}
+
+// SubscribeStream subscribes a stream to a feed topic once it fits the key's
+// subscription limit; a topic the feed refuses is not counted
+func (s *ExchangeService) SubscribeStream(ctx context.Context, lease *entitlements.Lease, sub *feed.Subscriber, topic feed.Topic) error {
+	name := topicName(topic)
+	if err := lease.Subscribe(name); err != nil {
+		return NewRejection(RejectSubscriptionLimit, err)
+	}
+	if err := s.SubscribeFeed(ctx, sub, topic); err != nil {
+		lease.Unsubscribe(name)
+		return err
+	}
+	return nil
+}
+
+// UnsubscribeStream removes a feed topic from a stream and releases it
+func (s *ExchangeService) UnsubscribeStream(lease *entitlements.Lease, sub *feed.Subscriber, topic feed.Topic) {
+	sub.Remove(topic)
+	lease.Unsubscribe(topicName(topic))
+}
+
+// SubscriptionUsage reports the streams and subscriptions the caller's API key holds
+func (s *ExchangeService) SubscriptionUsage(ctx context.Context) entitlements.Usage {
+	return s.entitlements.Usage(keystats.APIKey(ctx))
+}
+
+// SetStreamLimits gives each API key its own limits in place of the configured defaults
+func (s *ExchangeService) SetStreamLimits(limits map[string]entitlements.Limits) {
+	for apiKey, keyLimits := range limits {
+		s.entitlements.SetLimits(apiKey, keyLimits)
+	}
+}
+
+// topicName is how a feed topic is counted, "trades:BTC-USD"
+func topicName(topic feed.Topic) string {
+	return string(topic.Channel) + ":" + topic.Key
+}
+
+// streamLimits builds the default per-key limits from configuration
+func streamLimits(cfg *config.Config) entitlements.Limits {
+	if cfg == nil {
+		return entitlements.Limits{}
+	}
+	return entitlements.Limits{
+		MaxStreams:       cfg.StreamLimit,
+		MaxSubscriptions: cfg.SubscriptionLimit,
+	}
+}
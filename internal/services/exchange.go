@@ -1,34 +1,820 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/storagelatency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
 )
 
 type ExchangeService struct {
-	config *config.Config
-	logger *logrus.Logger
+	config      *config.Config
+	logger      *logrus.Logger
+	instruments *InstrumentRegistry
+	engine      *matching.Engine
+	eventLog    matching.EventLog
+	statistics  *marketdata.Tracker
+	candles     *candleArchive
+	monitor     *surveillance.Monitor
+	auditSink   surveillance.AuditSink
+	keyStats    *keystats.Recorder
+	schedule    *instrumentSchedule
+	sessions    *sessionRegistry
+	transitions *transitionSchedule
+	funding     *fundingSchedule
+	colocation  *colocation
+	asyncOrders *asyncOrders
+	idempotency *idempotency.Cache
+	runMetrics  *runmetrics.Collector
+	market      *marketMetrics
+	activity    *accountActivity
+	incidents   *incidents.Timeline
+	feed        *feed.Hub
+	bookFeed    *bookFeed
+	tickerFeed  *tickerFeed
+	executions  *feed.Journal
+	now         func() time.Time
+
+	idempotencyStore idempotency.Store       // nil keeps keys in memory only
+	metricsStore     runmetrics.Store        // nil when metrics snapshots are not persisted
+	publicIDs        ports.IDObfuscator      // nil publishes internal IDs
+	scenario         *scenarioRun            // nil when no scenario is loaded
+	synthetic        *syntheticMarket        // nil when no synthetic market is enabled
+	marketMaker      *marketMaker            // nil when no background liquidity is quoted
+	referencePrices  *pricefeed.Board        // External prices anchoring each symbol's mark
+	entitlements     *entitlements.Registry  // Open streams and subscriptions per API key
+	apiVersions      *apiVersions            // Deprecation schedule per API version
+	tradeTape        *tradeTape              // Trades awaiting the trade store
+	orderJournal     *orderJournal           // Order updates awaiting the order store
+	accounts         *accountRegistry        // Accounts opened through the account API
+	balances         *balanceJournal         // Holds and double-entry postings per account
+	exchangeStats    *exchangeStatsFeed      // When open interest and volume are next pushed
+	liquidations     *liquidationLog         // Positions closed out for breaching maintenance margin
+	storageLatency   *storagelatency.Monitor // Recent call latency per storage component
+	positions        *PositionService        // Net position and PnL per account and symbol, booked on each fill
+	insurance        *insuranceFund          // Backstop for liquidation shortfalls, then auto-deleveraging
+	run              *runRecord              // nil until the run's manifest is fixed
+	access           *accessLog              // Requests refused for failed authentication or missing permissions
+	rateLimits       *ratelimit.Limiter      // Request weight spent per API key and client address
+	settlement       *settlementDesk         // Settlement instructions generated from spot fills for the custodian
+	eodCycles        *settlementCycles       // Each trading day's spot trades netted at the settlement cutoff
+	auditTrail       *audit.Pipeline         // nil when changes are not reported to the audit-correlator
+	outbox           *eventOutbox            // nil when audit events are not written to an outbox
+	chaos            *chaosDesk              // nil when the chaos API is disabled
+	scripts          *scriptPlayer           // Scenario script being played and where its progress is reported
+	degradation      *degradation            // Partial outage the venue is simulating
+	replays          *orderFlowReplayer      // Captured order flow being resubmitted
+	personality      *venuePersonality       // Fees, latency, listings and API flavor of the venue being run
+}
+
+// maxClientOrderIDLength bounds caller-assigned order IDs
+const maxClientOrderIDLength = 64
+
+// OrderRequest is a validated-on-entry request to place an order
+type OrderRequest struct {
+	AccountID     string             `json:"account_id"`
+	ClientOrderID string             `json:"client_order_id"` // Optional; resubmitting the same order with it is idempotent
+	Symbol        string             `json:"symbol"`
+	Side          models.Side        `json:"side"`
+	Type          models.OrderType   `json:"type"`
+	TimeInForce   models.TimeInForce `json:"time_in_force"`
+	Quantity      float64            `json:"quantity"`
+	Price         float64            `json:"price"`
+	ExpiresAt     time.Time          `json:"expires_at"` // Required for GTD, rejected otherwise
 }
 
 func NewExchangeService(cfg *config.Config, logger *logrus.Logger) *ExchangeService {
-	return &ExchangeService{
-		config: cfg,
-		logger: logger,
+	instruments := NewInstrumentRegistry(DefaultInstruments()...)
+	engine := matching.NewEngine()
+	now := time.Now
+	if clock := cfg.GetClock(); clock != nil {
+		engine.SetClock(clock)
+		now = clock.Now
+	}
+	if err := engine.SetDefaultCircuitBreaker(circuitBreakerConfig(cfg)); err != nil {
+		logger.WithError(err).Error("Failed to set the default circuit breaker")
+	}
+	engine.SetExpiryPrecision(expiryPrecision(cfg))
+	for _, instrument := range instruments.List() {
+		engine.AddBook(instrument.Symbol, instrument.ReferencePrice)
+	}
+
+	service := &ExchangeService{
+		config:      cfg,
+		logger:      logger,
+		instruments: instruments,
+		engine:      engine,
+		statistics:  marketdata.NewTracker(),
+		candles:     newCandleArchive(),
+		monitor:     surveillance.NewMonitor(surveillanceConfig(cfg)),
+		keyStats:    keystats.NewRecorder(messagingPolicy(cfg), cfg.GetMetricsPort()),
+		schedule:    newInstrumentSchedule(),
+		sessions:    newSessionRegistry(),
+		transitions: newTransitionSchedule(now()),
+		funding:     newFundingSchedule(),
+		colocation:  newColocation(),
+		asyncOrders: newAsyncOrders(),
+		idempotency: idempotency.NewCache(idempotencyWindow(cfg)),
+		runMetrics:  runmetrics.NewCollector(),
+		market:      newMarketMetrics(),
+		activity:    newAccountActivity(),
+		incidents:   incidents.NewTimeline(incidentHistory(cfg)),
+		feed:        feed.NewHub(feedBufferSize),
+		bookFeed:    newBookFeed(),
+		tickerFeed:  newTickerFeed(),
+		executions:  feed.NewJournal(executionJournalSize, feedBufferSize),
+		now:         now,
+
+		referencePrices: pricefeed.NewBoard(priceFeedMaxAge(cfg)),
+		entitlements:    entitlements.NewRegistry(streamLimits(cfg)),
+		apiVersions:     newAPIVersions(),
+		tradeTape:       newTradeTape(writeBehindConfig(cfg)),
+		orderJournal:    newOrderJournal(writeBehindConfig(cfg)),
+		accounts:        newAccountRegistry(),
+		balances:        newBalanceJournal(),
+		exchangeStats:   newExchangeStatsFeed(exchangeStatsInterval(cfg)),
+		liquidations:    newLiquidationLog(),
+		insurance:       newInsuranceFund(),
+		access:          newAccessLog(),
+		rateLimits:      ratelimit.NewLimiter(rateLimits(cfg)),
+		settlement:      newSettlementDesk(cfg),
+		eodCycles:       newSettlementCycles(),
+		scripts:         &scriptPlayer{},
+		degradation:     newDegradation(),
+		replays:         &orderFlowReplayer{},
+		personality:     &venuePersonality{},
+		storageLatency:  storagelatency.NewMonitor(storageLatencyConfig(cfg), cfg.GetMetricsPort()),
+	}
+	service.positions = NewPositionService(instruments, service.markOrReference)
+	return service
+}
+
+// Instruments returns the registry of instruments listed on this venue
+func (s *ExchangeService) Instruments() *InstrumentRegistry {
+	return s.instruments
+}
+
+// Positions returns the per-account position and PnL tracker
+func (s *ExchangeService) Positions() *PositionService {
+	return s.positions
+}
+
+// Now returns the current time on the venue clock
+func (s *ExchangeService) Now() time.Time {
+	return s.now()
+}
+
+// KeyStatistics returns the per-API-key message and order statistics
+func (s *ExchangeService) KeyStatistics() *keystats.Recorder {
+	return s.keyStats
+}
+
+// Engine returns the matching engine backing this service
+func (s *ExchangeService) Engine() *matching.Engine {
+	return s.engine
+}
+
+// RestoreFromEventLog replaces the engine with one rebuilt from recorded events, then
+// records every further mutation to log. Instruments missing from the log are listed.
+func (s *ExchangeService) RestoreFromEventLog(events []matching.Event, log matching.EventLog) error {
+	engine, err := matching.Replay(events)
+	if err != nil {
+		return err
+	}
+	if err := s.useRestoredEngine(engine, log); err != nil {
+		return err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"events":   len(events),
+		"sequence": engine.Sequence(),
+	}).Info("Matching engine restored from event log")
+	return nil
+}
+
+// useRestoredEngine configures a rebuilt engine, lists any instrument it lacks and
+// puts it in place of the empty one built at startup, rebuilding balances and
+// positions from its orders. A nil log leaves the engine unrecorded.
+func (s *ExchangeService) useRestoredEngine(engine *matching.Engine, log matching.EventLog) error {
+	if clock := s.config.GetClock(); clock != nil {
+		engine.SetClock(clock)
+	}
+	if log != nil {
+		engine.SetEventLog(timedEventLog{log: log, service: s})
+	}
+	engine.SetExpiryPrecision(expiryPrecision(s.config))
+	if err := engine.SetDefaultCircuitBreaker(circuitBreakerConfig(s.config)); err != nil {
+		engine.Close()
+		return err
+	}
+	for _, instrument := range s.instruments.List() {
+		if err := engine.AddBook(instrument.Symbol, instrument.ReferencePrice); err != nil {
+			engine.Close()
+			return err
+		}
+	}
+
+	s.engine.Close()
+	s.engine = engine
+	s.eventLog = log
+	s.rebuildBalances()
+	s.positions.Rebuild(s.engine.FilledOrders(""))
+	return nil
+}
+
+// PlaceOrder validates an order against instrument rules and submits it for matching.
+// A retry carrying the same idempotency key returns the original order as a duplicate.
+func (s *ExchangeService) PlaceOrder(ctx context.Context, req OrderRequest) (*matching.ExecutionReport, error) {
+	ctx = logfields.WithAccountID(ctx, req.AccountID)
+	claim, record, err := s.beginKeyed(ctx, placeFingerprint(req))
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return s.replayedReport(record)
+	}
+	report, err := s.placeOrder(ctx, req)
+	s.finishKeyed(claim, orderIDOf(report), err)
+	s.observePlacement(req, report, err)
+	s.observeAccountPlacement(req, report, err)
+	if err != nil {
+		return report, err
+	}
+	return s.overfillReport(ctx, report), nil
+}
+
+func (s *ExchangeService) placeOrder(ctx context.Context, req OrderRequest) (*matching.ExecutionReport, error) {
+	apiKey := keystats.APIKey(ctx)
+	started := time.Now()
+	if err := s.Admit(chaos.OperationChange); err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	if err := s.authorizeKey(ctx, req.AccountID, accounts.PermissionTrade); err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	if err := s.traverseGateway(ctx, req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := s.validateOrder(req); err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	if err := s.injectOrderFaults(ctx, req.Symbol); err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	unlock, err := s.reserveBalance(req)
+	if err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	defer unlock()
+
+	report, err := s.engine.Submit(models.Order{
+		ClientOrderID: req.ClientOrderID,
+		AccountID:     req.AccountID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   req.TimeInForce,
+		Quantity:      req.Quantity,
+		Price:         req.Price,
+		ExpiresAt:     req.ExpiresAt,
+		Priority:      s.colocation.get(req.AccountID).Priority,
+	})
+	if err != nil {
+		s.keyStats.Rejected(apiKey)
+		return nil, err
+	}
+	ctx = logfields.WithOrderID(ctx, report.Order.ID)
+	if report.Duplicate {
+		s.engineLog(ctx).WithField("client_order_id", req.ClientOrderID).Info("Duplicate order submission returned existing order")
+		return report, nil
+	}
+	if report.Order.Status == models.OrderStatusRejected {
+		s.keyStats.Rejected(apiKey)
+	} else {
+		s.keyStats.OrderPlaced(apiKey, len(report.Trades))
+		s.runMetrics.ObserveOrder(time.Since(started))
+	}
+	s.monitor.OnOrderPlaced(report.Order)
+	s.auditOrder(ctx, audit.TypeOrderPlaced, report.Order, "")
+	s.recordTrades(ctx, report.Trades)
+	s.ackAsync(ctx, report.Order)
+	s.publishActivity(req.Symbol, []models.Order{report.Order}, report.Trades)
+
+	s.engineLog(ctx).WithFields(logrus.Fields{
+		"symbol":   req.Symbol,
+		"side":     req.Side,
+		"quantity": req.Quantity,
+		"price":    req.Price,
+		"status":   report.Order.Status,
+		"trades":   len(report.Trades),
+	}).Info("Order placed")
+
+	return report, nil
+}
+
+// CancelOrder cancels a working order. A retry carrying the same idempotency key
+// returns the order rather than failing because it is no longer active.
+func (s *ExchangeService) CancelOrder(ctx context.Context, orderID string) (models.Order, error) {
+	ctx = logfields.WithOrderID(ctx, orderID)
+	claim, record, err := s.beginKeyed(ctx, cancelFingerprint(orderID))
+	if err != nil {
+		return models.Order{}, err
+	}
+	if record != nil {
+		return s.engine.GetOrder(record.OrderID)
+	}
+	order, err := s.cancelOrder(ctx, orderID)
+	s.finishKeyed(claim, orderID, err)
+	return order, err
+}
+
+func (s *ExchangeService) cancelOrder(ctx context.Context, orderID string) (models.Order, error) {
+	if err := s.Admit(chaos.OperationCancel); err != nil {
+		s.keyStats.Rejected(keystats.APIKey(ctx))
+		return models.Order{}, err
+	}
+	if err := s.orderGateway(ctx, orderID); err != nil {
+		return models.Order{}, err
+	}
+	order, err := s.engine.Cancel(orderID)
+	if err != nil {
+		s.keyStats.Rejected(keystats.APIKey(ctx))
+		return order, err
+	}
+	ctx = logfields.WithAccountID(ctx, order.AccountID)
+	s.keyStats.OrderCanceled(keystats.APIKey(ctx))
+	s.observeCancel(order)
+	s.observeAccountCancel(order)
+	s.publishFlags(ctx, s.monitor.OnOrderCanceled(order))
+	s.auditOrder(ctx, audit.TypeOrderCanceled, order, cancelRequested)
+	s.publishActivity(order.Symbol, []models.Order{order}, nil)
+	s.engineLog(ctx).Info("Order canceled")
+	if err := s.stallCancel(ctx, order); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+// AmendOrder changes the price and/or quantity of a working order without a cancel/replace
+// round trip. A retry carrying the same idempotency key is not applied twice.
+func (s *ExchangeService) AmendOrder(ctx context.Context, orderID string, req matching.AmendRequest) (*matching.ExecutionReport, error) {
+	ctx = logfields.WithOrderID(ctx, orderID)
+	claim, record, err := s.beginKeyed(ctx, amendFingerprint(orderID, req))
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return s.replayedReport(record)
+	}
+	if err := s.Admit(chaos.OperationChange); err != nil {
+		s.finishKeyed(claim, orderID, err)
+		s.keyStats.Rejected(keystats.APIKey(ctx))
+		return nil, err
+	}
+	if err := s.orderGateway(ctx, orderID); err != nil {
+		s.finishKeyed(claim, orderID, err)
+		return nil, err
+	}
+	unlock, err := s.reserveAmend(orderID, req)
+	if err != nil {
+		s.finishKeyed(claim, orderID, err)
+		s.keyStats.Rejected(keystats.APIKey(ctx))
+		return nil, err
+	}
+	defer unlock()
+	report, err := s.amendOrder(orderID, req)
+	s.finishKeyed(claim, orderID, err)
+	if err != nil {
+		s.keyStats.Rejected(keystats.APIKey(ctx))
+		return nil, err
+	}
+	s.keyStats.OrderAmended(keystats.APIKey(ctx), len(report.Trades))
+	ctx = logfields.WithAccountID(ctx, report.Order.AccountID)
+	s.auditOrder(ctx, audit.TypeOrderAmended, report.Order, "")
+	s.recordTrades(ctx, report.Trades)
+	s.publishActivity(report.Order.Symbol, []models.Order{report.Order}, report.Trades)
+
+	s.engineLog(ctx).WithFields(logrus.Fields{
+		"price":    report.Order.Price,
+		"quantity": report.Order.Quantity,
+		"status":   report.Order.Status,
+		"trades":   len(report.Trades),
+	}).Info("Order amended")
+
+	return s.overfillReport(ctx, report), nil
+}
+
+// amendOrder validates the new price and quantity against instrument rules, then amends
+func (s *ExchangeService) amendOrder(orderID string, req matching.AmendRequest) (*matching.ExecutionReport, error) {
+	s.ActivateInstrumentChanges(context.Background())
+	order, err := s.engine.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	instrument, err := s.instruments.Get(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if req.Price != 0 {
+		if err := instrument.ValidatePrice(req.Price); err != nil {
+			return nil, NewRejection(RejectInvalidPrice, err)
+		}
+	}
+	if req.Quantity != 0 {
+		if err := instrument.ValidateQuantity(req.Quantity); err != nil {
+			return nil, NewRejection(RejectInvalidQuantity, err)
+		}
+	}
+
+	return s.engine.Amend(orderID, req)
+}
+
+// orderGateway checks the caller's API key may act on an existing order, then delays
+// the action by its owner's latency; unknown orders pass straight through and fail in
+// the engine
+func (s *ExchangeService) orderGateway(ctx context.Context, orderID string) error {
+	order, err := s.engine.GetOrder(orderID)
+	if err != nil {
+		return nil
+	}
+	if err := s.authorizeKey(ctx, order.AccountID, accounts.PermissionTrade); err != nil {
+		return err
+	}
+	return s.traverseGateway(ctx, order.AccountID)
+}
+
+// GetOrder returns the current state of an order
+func (s *ExchangeService) GetOrder(ctx context.Context, orderID string) (models.Order, error) {
+	return s.engine.GetOrder(orderID)
+}
+
+// ClientOrder returns the order an account placed under a client order ID
+func (s *ExchangeService) ClientOrder(ctx context.Context, accountID, clientOrderID string) (models.Order, error) {
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return models.Order{}, err
+	}
+	return s.engine.ClientOrder(accountID, clientOrderID)
+}
+
+// OpenOrders lists working orders, optionally for one account and/or symbol
+func (s *ExchangeService) OpenOrders(ctx context.Context, accountID, symbol string) ([]models.Order, error) {
+	if accountID != "" {
+		if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+	if symbol != "" {
+		if _, err := s.instruments.Get(symbol); err != nil {
+			return nil, err
+		}
+	}
+	return s.engine.OpenOrders(accountID, symbol), nil
+}
+
+// Trades returns the latest executions, optionally for one account and/or symbol
+func (s *ExchangeService) Trades(ctx context.Context, accountID, symbol string, limit int) ([]models.Trade, error) {
+	if limit < 0 {
+		return nil, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	if accountID != "" {
+		if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+			return nil, err
+		}
 	}
+	if symbol != "" {
+		if _, err := s.instruments.Get(symbol); err != nil {
+			return nil, err
+		}
+	}
+	return s.engine.Trades(symbol, accountID, limit)
 }
 
-func (s *ExchangeService) PlaceOrder(symbol string, quantity float64, price float64, side string) (string, error) {
+// OrderBook returns up to depth aggregated price levels per side (0 = all), as of
+// the sequence of the symbol's book channel
+func (s *ExchangeService) OrderBook(ctx context.Context, symbol string, depth int) (OrderBookSnapshot, error) {
+	if depth < 0 {
+		return OrderBookSnapshot{}, rejectf(RejectInvalidRequest, "depth must not be negative")
+	}
+	if _, err := s.instruments.Get(symbol); err != nil {
+		return OrderBookSnapshot{}, err
+	}
+	if frozen, ok := s.frozenBook(symbol, depth); ok {
+		return frozen, nil
+	}
+
+	books := s.bookFeed
+	books.mu.Lock()
+	defer books.mu.Unlock()
+	book, sequence, err := s.syncBook(symbol)
+	if err != nil {
+		return OrderBookSnapshot{}, err
+	}
+	return OrderBookSnapshot{BookSnapshot: truncateBook(book, depth), Sequence: sequence}, nil
+}
+
+// StartAuction moves a symbol into a call auction
+func (s *ExchangeService) StartAuction(ctx context.Context, symbol string, kind matching.AuctionKind) error {
+	if err := s.engine.StartAuction(symbol, kind); err != nil {
+		return err
+	}
+	s.publishBook(symbol)
 	s.logger.WithFields(logrus.Fields{
-		"symbol":   symbol,
-		"quantity": quantity,
-		"price":    price,
-		"side":     side,
-	}).Info("Placing order")
-	return "order-123", nil
-}
-
-func (s *ExchangeService) GetOrderStatus(orderID string) (string, error) {
-	s.logger.WithField("orderID", orderID).Info("Getting order status")
-	return "filled", nil
-}
\ No newline at end of file
+		"symbol": symbol,
+		"kind":   kind,
+	}).Info("Call auction started")
+	return nil
+}
+
+// IndicativeAuction returns the current indicative uncrossing price and volume
+func (s *ExchangeService) IndicativeAuction(ctx context.Context, symbol string) (matching.AuctionIndication, error) {
+	return s.engine.IndicativeAuction(symbol)
+}
+
+// UncrossAuction executes the call auction and prints the auction trades
+func (s *ExchangeService) UncrossAuction(ctx context.Context, symbol string) (*matching.AuctionResult, error) {
+	result, err := s.engine.Uncross(symbol)
+	if err != nil {
+		return nil, err
+	}
+	s.recordTrades(ctx, result.Trades)
+	s.publishActivity(symbol, nil, result.Trades)
+	s.releaseOrders(result.Canceled)
+	for _, orderID := range result.Canceled {
+		if order, err := s.engine.GetOrder(orderID); err == nil {
+			s.journalOrder(order)
+			s.auditOrder(ctx, audit.TypeOrderCanceled, order, cancelAuction)
+		}
+	}
+	s.logger.WithFields(logrus.Fields{
+		"symbol":      symbol,
+		"kind":        result.Kind,
+		"price":       result.Price,
+		"volume":      result.Volume,
+		"imbalance":   result.Imbalance,
+		"trades":      len(result.Trades),
+		"phase_after": result.PhaseAfter,
+	}).Info("Call auction uncrossed")
+	return result, nil
+}
+
+// HaltTrading manually halts matching for a symbol
+func (s *ExchangeService) HaltTrading(ctx context.Context, symbol string) (matching.HaltInfo, error) {
+	info, err := s.engine.Halt(symbol)
+	if err != nil {
+		return info, err
+	}
+	s.publishBook(symbol)
+	s.reconcileHalts(ctx)
+	s.logger.WithField("symbol", symbol).Warn("Trading halted manually")
+	return info, nil
+}
+
+// ResumeTrading ends a halt on a symbol
+func (s *ExchangeService) ResumeTrading(ctx context.Context, symbol string) error {
+	if err := s.engine.Resume(symbol); err != nil {
+		return err
+	}
+	s.publishBook(symbol)
+	s.reconcileHalts(ctx)
+	s.logger.WithField("symbol", symbol).Info("Trading resumed")
+	return nil
+}
+
+// ActiveHalts lists symbols currently halted
+func (s *ExchangeService) ActiveHalts(ctx context.Context) []matching.HaltInfo {
+	return s.engine.Halts()
+}
+
+// Ticker returns the rolling 24h statistics and top of book for a listed symbol
+func (s *ExchangeService) Ticker(ctx context.Context, symbol string) (marketdata.Ticker, error) {
+	if _, err := s.instruments.Get(symbol); err != nil {
+		return marketdata.Ticker{}, err
+	}
+	return s.ticker(symbol), nil
+}
+
+// Tickers returns rolling 24h statistics and top of book for every listed symbol
+func (s *ExchangeService) Tickers(ctx context.Context) []marketdata.Ticker {
+	instruments := s.instruments.List()
+	tickers := make([]marketdata.Ticker, 0, len(instruments))
+	for _, instrument := range instruments {
+		tickers = append(tickers, s.ticker(instrument.Symbol))
+	}
+	return tickers
+}
+
+// RestoreStatistics loads persisted tickers and candles so a restart does not reset them
+func (s *ExchangeService) RestoreStatistics(store marketdata.Store) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	s.statistics.Restore(state)
+	s.logger.WithFields(logrus.Fields{
+		"symbols":  len(state.Symbols),
+		"saved_at": state.SavedAt,
+	}).Info("Market data statistics restored")
+	return nil
+}
+
+// SaveStatistics persists the current tickers and candles
+func (s *ExchangeService) SaveStatistics(store marketdata.Store) error {
+	snapshot := s.statistics.Snapshot()
+	return s.persist(componentStatistics, "save", logrus.Fields{"symbols": len(snapshot.Symbols)}, func() error {
+		return store.Save(snapshot)
+	})
+}
+
+// PersistStatistics saves statistics every interval until ctx is done
+func (s *ExchangeService) PersistStatistics(ctx context.Context, store marketdata.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SaveStatistics(store); err != nil {
+				s.logger.WithError(err).Warn("Failed to persist market data statistics")
+			}
+		}
+	}
+}
+
+// expiryPrecision is the configured expiry wheel slot width; zero keeps the wheel's default
+func expiryPrecision(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	return cfg.ExpiryPrecision
+}
+
+// circuitBreakerConfig builds breaker rules from config, falling back to defaults when unset
+func circuitBreakerConfig(cfg *config.Config) matching.CircuitBreakerConfig {
+	breaker := matching.DefaultCircuitBreakerConfig()
+	if cfg == nil || cfg.CircuitBreakerWindow <= 0 {
+		return breaker
+	}
+	breaker.Enabled = cfg.CircuitBreakerEnabled
+	breaker.ThresholdPercent = cfg.CircuitBreakerThreshold
+	breaker.Window = cfg.CircuitBreakerWindow
+	breaker.Cooldown = cfg.CircuitBreakerCooldown
+	return breaker
+}
+
+// idempotencyWindow is how long keys are remembered, falling back to a day
+func idempotencyWindow(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.IdempotencyWindow <= 0 {
+		return defaultIdempotencyWindow
+	}
+	return cfg.IdempotencyWindow
+}
+
+// messagingPolicy builds the per-key messaging limits from config
+func messagingPolicy(cfg *config.Config) keystats.Policy {
+	if cfg == nil {
+		return keystats.Policy{}
+	}
+	return keystats.Policy{
+		MaxMessageRate:       cfg.MessageRateLimit,
+		MaxOrderToTradeRatio: cfg.OrderToTradeLimit,
+	}
+}
+
+// CheckOrder runs every check that PlaceOrder would apply, without placing the order
+// or holding its balance: degradation admission, the key's trade permission,
+// validation and risk rules, then, for a valid order, the account's balance or margin.
+// Injected faults and gateway latency are left out.
+func (s *ExchangeService) CheckOrder(ctx context.Context, req OrderRequest) PreTradeCheckResult {
+	failures := make([]error, 0)
+	if _, err := s.admission(chaos.OperationChange); err != nil {
+		failures = append(failures, err)
+	}
+	if err := s.authorizeKey(ctx, req.AccountID, accounts.PermissionTrade); err != nil {
+		failures = append(failures, err)
+	}
+	if invalid := s.preTradeChecks(req); len(invalid) > 0 {
+		failures = append(failures, invalid...)
+	} else if unlock, err := s.reserveBalance(req); err != nil {
+		failures = append(failures, err)
+	} else {
+		unlock()
+	}
+
+	result := PreTradeCheckResult{
+		Accepted:   len(failures) == 0,
+		Reasons:    make([]string, 0, len(failures)),
+		Rejections: make([]Rejection, 0, len(failures)),
+	}
+	for _, failure := range failures {
+		result.Reasons = append(result.Reasons, failure.Error())
+		result.Rejections = append(result.Rejections, RejectionOf(failure))
+	}
+	return result
+}
+
+// PreTradeCheckResult is the accept/reject verdict of the pre-trade checks
+type PreTradeCheckResult struct {
+	Accepted   bool        `json:"accepted"`
+	Reasons    []string    `json:"reasons"`
+	Rejections []Rejection `json:"rejections"`
+}
+
+// preTradeChecks evaluates validation and risk rules, returning every failure
+func (s *ExchangeService) preTradeChecks(req OrderRequest) []error {
+	s.ActivateInstrumentChanges(context.Background())
+	failures := make([]error, 0)
+
+	if req.AccountID == "" {
+		failures = append(failures, rejectf(RejectInvalidAccount, "account id is required"))
+	} else if s.accounts.directory.IsClosed(req.AccountID) {
+		failures = append(failures, rejectf(RejectInvalidAccount, "%w: %s", accounts.ErrClosed, req.AccountID))
+	}
+	if len(req.ClientOrderID) > maxClientOrderIDLength {
+		failures = append(failures, rejectf(RejectInvalidRequest, "client order id must be at most %d characters", maxClientOrderIDLength))
+	}
+	if req.Side != models.SideBuy && req.Side != models.SideSell {
+		failures = append(failures, rejectf(RejectInvalidSide, "invalid side: %q", req.Side))
+	}
+	if req.TimeInForce == models.TimeInForceGTD {
+		if req.ExpiresAt.IsZero() {
+			failures = append(failures, rejectf(RejectInvalidRequest, "GTD orders require an expiry time"))
+		} else if !req.ExpiresAt.After(s.now()) {
+			failures = append(failures, rejectf(RejectInvalidRequest, "expiry time %s has already passed", req.ExpiresAt.Format(time.RFC3339)))
+		}
+	} else if !req.ExpiresAt.IsZero() {
+		failures = append(failures, rejectf(RejectInvalidRequest, "only GTD orders take an expiry time"))
+	}
+
+	instrument, err := s.instruments.Get(req.Symbol)
+	if err != nil {
+		return append(failures, err)
+	}
+
+	if err := instrument.ValidateQuantity(req.Quantity); err != nil {
+		failures = append(failures, NewRejection(RejectInvalidQuantity, err))
+	}
+	switch req.Type {
+	case models.OrderTypeLimit:
+		if err := instrument.ValidatePrice(req.Price); err != nil {
+			failures = append(failures, NewRejection(RejectInvalidPrice, err))
+		}
+	case models.OrderTypeMarket:
+		if req.Price != 0 {
+			failures = append(failures, rejectf(RejectInvalidPrice, "market orders must not specify a price"))
+		}
+	default:
+		failures = append(failures, rejectf(RejectInvalidOrderType, "invalid order type: %q", req.Type))
+	}
+
+	phase, err := s.engine.Phase(req.Symbol)
+	if err != nil {
+		return append(failures, err)
+	}
+	switch phase {
+	case matching.PhaseHalted:
+		failures = append(failures, fmt.Errorf("%w: %s", matching.ErrHalted, req.Symbol))
+	case matching.PhaseClosed:
+		failures = append(failures, fmt.Errorf("%w: %s", matching.ErrMarketClosed, req.Symbol))
+	case matching.PhaseAuction:
+		if req.TimeInForce != "" && !req.TimeInForce.Rests() {
+			failures = append(failures, fmt.Errorf("%w: only GTC and GTD orders are accepted during an auction", matching.ErrInvalidPhase))
+		}
+	}
+
+	return failures
+}
+
+// validateOrder returns the first failed pre-trade check
+func (s *ExchangeService) validateOrder(req OrderRequest) error {
+	if failures := s.preTradeChecks(req); len(failures) > 0 {
+		return failures[0]
+	}
+	return nil
+}
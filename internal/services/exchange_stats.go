@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ContractStatistics is a derivative's open interest and rolling 24h volume
+type ContractStatistics struct {
+	Symbol            string  `json:"symbol"`
+	OpenInterest      float64 `json:"open_interest"`       // Contracts held long, matched by as many held short
+	OpenInterestValue float64 `json:"open_interest_value"` // At the mark price, in the quote asset
+	MarkPrice         float64 `json:"mark_price"`
+	Volume            float64 `json:"volume"`
+	QuoteVolume       float64 `json:"quote_volume"`
+	TradeCount        int     `json:"trade_count"`
+}
+
+// AssetVolume is the rolling 24h volume of every book quoted in one asset
+type AssetVolume struct {
+	QuoteAsset  string  `json:"quote_asset"`
+	QuoteVolume float64 `json:"quote_volume"`
+	TradeCount  int     `json:"trade_count"`
+}
+
+// ExchangeStatistics is the market context a venue publishes: open interest per
+// contract and volume across the whole exchange
+type ExchangeStatistics struct {
+	Contracts []ContractStatistics `json:"contracts"`
+	Volume    []AssetVolume        `json:"volume"` // Spot and derivatives, by quote asset
+	Time      time.Time            `json:"time"`
+}
+
+// exchangeStatsFeed tracks when exchange statistics are next pushed to stream clients
+type exchangeStatsFeed struct {
+	interval time.Duration // Zero disables the push; the REST endpoint still serves them
+	next     time.Time
+	mu       sync.Mutex
+}
+
+func newExchangeStatsFeed(interval time.Duration) *exchangeStatsFeed {
+	return &exchangeStatsFeed{interval: interval}
+}
+
+// exchangeStatsInterval is how often exchange statistics are pushed; no config disables it
+func exchangeStatsInterval(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.ExchangeStatsInterval < 0 {
+		return 0
+	}
+	return cfg.ExchangeStatsInterval
+}
+
+// ExchangeStatistics computes open interest for every listed derivative and 24h
+// volume by quote asset. Open interest is derived from order state, so it survives
+// event log replay like account positions do.
+func (s *ExchangeService) ExchangeStatistics(ctx context.Context) ExchangeStatistics {
+	openInterest := s.openInterest()
+	stats := ExchangeStatistics{
+		Contracts: make([]ContractStatistics, 0),
+		Volume:    make([]AssetVolume, 0),
+		Time:      s.now(),
+	}
+
+	volumes := make(map[string]*AssetVolume)
+	for _, instrument := range s.instruments.List() {
+		ticker := s.statistics.Ticker(instrument.Symbol)
+		volume, ok := volumes[instrument.QuoteAsset]
+		if !ok {
+			volume = &AssetVolume{QuoteAsset: instrument.QuoteAsset}
+			volumes[instrument.QuoteAsset] = volume
+		}
+		volume.QuoteVolume += ticker.QuoteVolume
+		volume.TradeCount += ticker.TradeCount
+
+		if !instrument.IsDerivative() {
+			continue
+		}
+		mark, err := s.markPrice(instrument.Symbol)
+		if err != nil {
+			mark = instrument.ReferencePrice
+		}
+		stats.Contracts = append(stats.Contracts, ContractStatistics{
+			Symbol:            instrument.Symbol,
+			OpenInterest:      openInterest[instrument.Symbol],
+			OpenInterestValue: openInterest[instrument.Symbol] * mark,
+			MarkPrice:         mark,
+			Volume:            ticker.Volume,
+			QuoteVolume:       ticker.QuoteVolume,
+			TradeCount:        ticker.TradeCount,
+		})
+	}
+
+	for _, volume := range volumes {
+		stats.Volume = append(stats.Volume, *volume)
+	}
+	sort.Slice(stats.Volume, func(i, j int) bool { return stats.Volume[i].QuoteAsset < stats.Volume[j].QuoteAsset })
+	return stats
+}
+
+// openInterest nets each account's fills per derivative and sums the long positions
+func (s *ExchangeService) openInterest() map[string]float64 {
+	positions := make(map[string]map[string]float64) // Symbol to signed contracts per account
+	for _, order := range s.engine.FilledOrders("") {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || !instrument.IsDerivative() {
+			continue
+		}
+		accounts, ok := positions[order.Symbol]
+		if !ok {
+			accounts = make(map[string]float64)
+			positions[order.Symbol] = accounts
+		}
+		if order.Side == models.SideBuy {
+			accounts[order.AccountID] += order.FilledQuantity
+		} else {
+			accounts[order.AccountID] -= order.FilledQuantity
+		}
+	}
+
+	openInterest := make(map[string]float64, len(positions))
+	for symbol, accounts := range positions {
+		for _, contracts := range accounts {
+			if contracts > 1e-12 {
+				openInterest[symbol] += contracts
+			}
+		}
+	}
+	return openInterest
+}
+
+// publishExchangeStatistics pushes exchange statistics at every multiple of the feed
+// interval the venue clock has passed. The first run only arms the next one, and
+// pushes missed during a clock jump are not replayed.
+func (s *ExchangeService) publishExchangeStatistics(ctx context.Context, now time.Time) bool {
+	schedule := s.exchangeStats
+	if schedule.interval <= 0 {
+		return false
+	}
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	due := !schedule.next.IsZero() && !now.Before(schedule.next)
+	if schedule.next.IsZero() || due {
+		schedule.next = now.Truncate(schedule.interval).Add(schedule.interval)
+	}
+	topic := feed.Topic{Channel: feed.ChannelExchangeStats}
+	if !due || !s.feed.Subscribed(topic) {
+		return false
+	}
+	s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: topic.Channel, Time: now, Data: s.ExchangeStatistics(ctx)})
+	return true
+}
@@ -0,0 +1,3828 @@
+//go:build unit
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/apiversion"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/fixture"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/liquidity"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/synthetic"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/infrastructure/observability"
+)
+
+func newTestExchangeService() *ExchangeService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewExchangeService(&config.Config{ServiceName: "exchange-simulator"}, logger)
+}
+
+func TestExchangeService_RestoreFromEventLog(t *testing.T) {
+	t.Run("restarts_with_recorded_orders", func(t *testing.T) {
+		// Given: A service recording to an event log
+		ctx := context.Background()
+		log := matching.NewMemoryEventLog()
+		first := newTestExchangeService()
+		if err := first.RestoreFromEventLog(nil, log); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		report, err := first.PlaceOrder(ctx, OrderRequest{
+			AccountID: "acct-1",
+			Symbol:    "BTC-USD",
+			Side:      models.SideBuy,
+			Type:      models.OrderTypeLimit,
+			Quantity:  1,
+			Price:     60000,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: A new service is restored from the same log
+		second := newTestExchangeService()
+		if err := second.RestoreFromEventLog(log.Events(), matching.NewMemoryEventLog()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The resting order is back on the book
+		order, err := second.GetOrder(ctx, report.Order.ID)
+		if err != nil {
+			t.Fatalf("Expected order after restore, got %v", err)
+		}
+		if order.Status != models.OrderStatusNew || order.Price != 60000 {
+			t.Errorf("Unexpected restored order: %+v", order)
+		}
+		if second.Engine().Sequence() <= first.Engine().Sequence() {
+			t.Errorf("Expected restored engine to continue the sequence past %d, got %d",
+				first.Engine().Sequence(), second.Engine().Sequence())
+		}
+	})
+}
+
+func TestExchangeService_PurgeAccount(t *testing.T) {
+	t.Run("erases_the_account_from_memory_and_event_log", func(t *testing.T) {
+		// Given: A recording service with a resting order for an account
+		ctx := context.Background()
+		log := matching.NewMemoryEventLog()
+		service := newTestExchangeService()
+		service.RestoreFromEventLog(nil, log)
+		report, _ := service.PlaceOrder(ctx, OrderRequest{
+			AccountID: "acct-erased",
+			Symbol:    "BTC-USD",
+			Side:      models.SideBuy,
+			Type:      models.OrderTypeLimit,
+			Quantity:  1,
+			Price:     60000,
+		})
+
+		// When: The account is purged
+		purge, err := service.PurgeAccount(ctx, "acct-erased")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The order is canceled under the alias and the log no longer names the account
+		order, _ := service.GetOrder(ctx, report.Order.ID)
+		if order.AccountID != purge.Alias || order.Status != models.OrderStatusCanceled {
+			t.Errorf("Unexpected order after purge: %+v", order)
+		}
+		if purge.EventsRedacted != 1 {
+			t.Errorf("Expected 1 redacted event, got %d", purge.EventsRedacted)
+		}
+		for _, event := range log.Events() {
+			if event.Order != nil && event.Order.AccountID == "acct-erased" {
+				t.Errorf("Event %d still holds the erased account", event.Sequence)
+			}
+		}
+		if tombstones := service.AccountTombstones(ctx); len(tombstones) != 1 {
+			t.Errorf("Expected one tombstone, got %d", len(tombstones))
+		}
+	})
+}
+
+func TestExchangeService_ClientOrderID(t *testing.T) {
+	t.Run("retry_is_idempotent_and_reuse_is_rejected", func(t *testing.T) {
+		// Given: An order placed with a client order ID
+		ctx := context.Background()
+		service := newTestExchangeService()
+		req := OrderRequest{
+			AccountID:     "acct-1",
+			ClientOrderID: "strategy-42",
+			Symbol:        "BTC-USD",
+			Side:          models.SideBuy,
+			Type:          models.OrderTypeLimit,
+			Quantity:      1,
+			Price:         60000,
+		}
+		first, err := service.PlaceOrder(ctx, req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// When: It is retried, then the ID is reused at another price
+		retry, retryErr := service.PlaceOrder(ctx, req)
+		req.Price = 59000
+		_, reuseErr := service.PlaceOrder(ctx, req)
+
+		// Then: The retry returns the original and the reuse is a typed rejection
+		if retryErr != nil || retry.Order.ID != first.Order.ID {
+			t.Errorf("Expected retry to return %s, got %+v, %v", first.Order.ID, retry, retryErr)
+		}
+		if RejectionOf(reuseErr).Reason != RejectDuplicateClientOrderID {
+			t.Errorf("Expected %s, got %v", RejectDuplicateClientOrderID, reuseErr)
+		}
+	})
+}
+
+type memoryIdempotencyStore struct {
+	records []idempotency.Record
+}
+
+func (m *memoryIdempotencyStore) Append(record idempotency.Record) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Load() ([]idempotency.Record, error) {
+	return append([]idempotency.Record(nil), m.records...), nil
+}
+
+func (m *memoryIdempotencyStore) Replace(records []idempotency.Record) error {
+	m.records = append([]idempotency.Record(nil), records...)
+	return nil
+}
+
+func TestExchangeService_IdempotencyKey(t *testing.T) {
+	t.Run("retries_after_restart_are_not_repeated", func(t *testing.T) {
+		// Given: A recording service that placed and canceled orders under idempotency keys
+		log := matching.NewMemoryEventLog()
+		store := &memoryIdempotencyStore{}
+		first := newTestExchangeService()
+		first.RestoreFromEventLog(nil, log)
+		first.SetIdempotencyStore(store)
+		client := keystats.WithAPIKey(context.Background(), "key-a")
+		req := OrderRequest{
+			AccountID: "acct-1",
+			Symbol:    "BTC-USD",
+			Side:      models.SideBuy,
+			Type:      models.OrderTypeLimit,
+			Quantity:  1,
+			Price:     60000,
+		}
+		placed, err := first.PlaceOrder(idempotency.WithKey(client, "place-1"), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		first.CancelOrder(idempotency.WithKey(client, "cancel-1"), placed.Order.ID)
+
+		// When: The venue restarts and the client replays both requests
+		second := newTestExchangeService()
+		second.RestoreFromEventLog(log.Events(), matching.NewMemoryEventLog())
+		if err := second.SetIdempotencyStore(store); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		retry, retryErr := second.PlaceOrder(idempotency.WithKey(client, "place-1"), req)
+		canceled, cancelErr := second.CancelOrder(idempotency.WithKey(client, "cancel-1"), placed.Order.ID)
+
+		// Then: Both are answered from the original outcome instead of running again
+		if retryErr != nil || !retry.Duplicate || retry.Order.ID != placed.Order.ID {
+			t.Errorf("Expected the retry to return %s as a duplicate, got %+v, %v", placed.Order.ID, retry, retryErr)
+		}
+		if cancelErr != nil || canceled.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected the replayed cancel to return the canceled order, got %+v, %v", canceled, cancelErr)
+		}
+		if orders := second.Engine().OpenOrders("acct-1", ""); len(orders) != 0 {
+			t.Errorf("Expected no duplicate order on the book, got %+v", orders)
+		}
+	})
+
+	t.Run("key_is_scoped_to_request_and_api_key", func(t *testing.T) {
+		// Given: An order placed under a key
+		service := newTestExchangeService()
+		ctx := idempotency.WithKey(keystats.WithAPIKey(context.Background(), "key-a"), "k1")
+		req := OrderRequest{
+			AccountID: "acct-1",
+			Symbol:    "BTC-USD",
+			Side:      models.SideBuy,
+			Type:      models.OrderTypeLimit,
+			Quantity:  1,
+			Price:     60000,
+		}
+		first, _ := service.PlaceOrder(ctx, req)
+
+		// When: The key is reused for another price, and by another API key
+		changed := req
+		changed.Price = 59000
+		_, reuseErr := service.PlaceOrder(ctx, changed)
+		other, otherErr := service.PlaceOrder(idempotency.WithKey(keystats.WithAPIKey(context.Background(), "key-b"), "k1"), req)
+
+		// Then: The reuse is rejected and the other caller's key is independent
+		if RejectionOf(reuseErr).Reason != RejectIdempotencyKeyReused {
+			t.Errorf("Expected %s, got %v", RejectIdempotencyKeyReused, reuseErr)
+		}
+		if otherErr != nil || other.Duplicate || other.Order.ID == first.Order.ID {
+			t.Errorf("Expected a new order for key-b, got %+v, %v", other, otherErr)
+		}
+	})
+
+	t.Run("failed_request_frees_its_key", func(t *testing.T) {
+		service := newTestExchangeService()
+		ctx := idempotency.WithKey(context.Background(), "k1")
+		req := OrderRequest{AccountID: "acct-1", Symbol: "DOGE-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 1}
+
+		_, firstErr := service.PlaceOrder(ctx, req)
+		req.Symbol = "BTC-USD"
+		req.Price = 60000
+		report, retryErr := service.PlaceOrder(ctx, req)
+
+		if firstErr == nil || retryErr != nil || report.Duplicate {
+			t.Errorf("Expected the corrected retry to be placed, got %+v, %v (first: %v)", report, retryErr, firstErr)
+		}
+	})
+
+	t.Run("expired_keys_are_compacted_away", func(t *testing.T) {
+		store := &memoryIdempotencyStore{records: []idempotency.Record{
+			{Key: "key-a/old", Fingerprint: "place", OrderID: "ord-1", SeenAt: time.Now().Add(-48 * time.Hour)},
+			{Key: "key-a/live", Fingerprint: "place", OrderID: "ord-2", SeenAt: time.Now()},
+		}}
+		service := newTestExchangeService()
+
+		if err := service.SetIdempotencyStore(store); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(store.records) != 1 || store.records[0].Key != "key-a/live" {
+			t.Errorf("Expected only the live key to remain, got %+v", store.records)
+		}
+	})
+}
+
+type recordingAuditSink struct {
+	events []surveillance.AuditEvent
+}
+
+func (s *recordingAuditSink) SubmitFlag(ctx context.Context, event surveillance.AuditEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestExchangeService_Surveillance(t *testing.T) {
+	t.Run("self_cross_is_flagged_and_audited", func(t *testing.T) {
+		// Given: A service forwarding flags to an audit sink
+		ctx := context.Background()
+		service := newTestExchangeService()
+		sink := &recordingAuditSink{}
+		service.SetAuditSink(sink)
+
+		// When: An account crosses its own resting order
+		order := OrderRequest{AccountID: "wash", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.Side = models.SideBuy
+		service.PlaceOrder(ctx, order)
+
+		// Then: The account is flagged and the flag is sent as an audit event
+		flags := service.SurveillanceFlags(ctx, "wash")
+		if len(flags) != 1 || flags[0].Type != surveillance.FlagSelfCross {
+			t.Fatalf("Expected one self-cross flag, got %+v", flags)
+		}
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected one audit event, got %d", len(sink.events))
+		}
+		if event := sink.events[0]; event.Type != surveillance.AuditEventType || event.Flag.ID != flags[0].ID {
+			t.Errorf("Unexpected audit event: %+v", sink.events[0])
+		}
+	})
+}
+
+// prefixObfuscator publishes IDs with a marker so tests can tell them apart
+type prefixObfuscator struct{}
+
+func (prefixObfuscator) External(id string) string {
+	return "pub-" + id
+}
+
+func TestExchangeService_PublicIDs(t *testing.T) {
+	t.Run("public_trades_carry_external_ids_and_no_accounts", func(t *testing.T) {
+		// Given: A trades subscriber on a venue publishing obfuscated IDs
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.SetIDObfuscator(prefixObfuscator{})
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		if err := service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelTrades, Key: "BTC-USD"}); err != nil {
+			t.Fatalf("Expected subscribe to succeed, got %v", err)
+		}
+
+		// When: Two accounts trade
+		order := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		ask, _ := service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "taker", models.SideBuy
+		bid, _ := service.PlaceOrder(ctx, order)
+
+		// Then: The print maps every ID and names no account, while storage keeps internal IDs
+		msg := <-sub.Messages()
+		trade, ok := msg.Data.(feed.PublicTrade)
+		if !ok {
+			t.Fatalf("Expected a public trade, got %T", msg.Data)
+		}
+		if trade.ID != "pub-"+bid.Trades[0].ID || trade.SellOrderID != "pub-"+ask.Order.ID || trade.BuyOrderID != "pub-"+bid.Order.ID {
+			t.Errorf("Expected external IDs, got %+v", trade)
+		}
+		stored, _ := service.Trades(ctx, "taker", "BTC-USD", 1)
+		if len(stored) != 1 || stored[0].ID != bid.Trades[0].ID || stored[0].BuyAccountID != "taker" {
+			t.Errorf("Expected the stored trade to keep internal IDs, got %+v", stored)
+		}
+	})
+}
+
+func TestExchangeService_TickerFeed(t *testing.T) {
+	t.Run("pushes_tickers_when_quotes_move_and_after_trades", func(t *testing.T) {
+		// Given: A ticker subscriber on an empty book
+		ctx := context.Background()
+		service := newTestExchangeService()
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelTicker, Key: "BTC-USD"})
+		<-sub.Messages()
+
+		// When: A bid rests, a worse bid joins behind it, and an ask trades against the best
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.Price = 59000
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side, order.Price = "b", models.SideSell, 60000
+		service.PlaceOrder(ctx, order)
+
+		// Then: The new best bid and the trade are pushed, but not the unchanged top
+		quoted := (<-sub.Messages()).Data.(marketdata.Ticker)
+		if quoted.BidPrice != 60000 || quoted.BidQuantity != 1 || quoted.TradeCount != 0 {
+			t.Errorf("Expected the first bid on top, got %+v", quoted)
+		}
+		traded := (<-sub.Messages()).Data.(marketdata.Ticker)
+		if traded.LastPrice != 60000 || traded.BidPrice != 59000 || traded.WeightedAvgPrice != 60000 {
+			t.Errorf("Expected the trade and the next bid, got %+v", traded)
+		}
+		select {
+		case msg := <-sub.Messages():
+			t.Errorf("Expected no further ticker, got %+v", msg.Data)
+		default:
+		}
+	})
+}
+
+func TestExchangeService_KeyStatistics(t *testing.T) {
+	t.Run("counts_orders_trades_and_rejections_per_key", func(t *testing.T) {
+		// Given: A request context tagged with an API key
+		service := newTestExchangeService()
+		ctx := keystats.WithAPIKey(context.Background(), "bot-1")
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+
+		// When: Two crossing orders and one invalid order are placed
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "b", models.SideBuy
+		service.PlaceOrder(ctx, order)
+		order.Price = 60000.001
+		service.PlaceOrder(ctx, order)
+
+		// Then: The key's statistics count them
+		stats, _ := service.KeyStatistics().Stats("bot-1")
+		if stats.Orders != 2 || stats.Trades != 1 || stats.Rejections != 1 {
+			t.Errorf("Unexpected statistics: %+v", stats)
+		}
+	})
+}
+
+// memoryCandleArchive records every bar appended to it
+type memoryCandleArchive struct {
+	appended map[marketdata.Interval][]marketdata.Candle
+}
+
+func (a *memoryCandleArchive) Append(symbol string, interval marketdata.Interval, candles []marketdata.Candle) error {
+	a.appended[interval] = append(a.appended[interval], candles...)
+	return nil
+}
+
+func (a *memoryCandleArchive) Candles(symbol string, interval marketdata.Interval, from, to time.Time) ([]marketdata.Candle, error) {
+	return nil, nil
+}
+
+func TestExchangeService_ArchiveCandles(t *testing.T) {
+	t.Run("archives_each_bar_once_it_closes", func(t *testing.T) {
+		// Given: One trade on BTC-USD and an archive
+		ctx := context.Background()
+		service := newTestExchangeService()
+		archive := &memoryCandleArchive{appended: make(map[marketdata.Interval][]marketdata.Candle)}
+		service.SetCandleArchive(archive)
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "b", models.SideBuy
+		result, _ := service.PlaceOrder(ctx, order)
+		executedAt := result.Trades[0].ExecutedAt
+
+		// When: Archiving runs while the bars are open, then twice after every bar closed
+		service.now = func() time.Time { return executedAt }
+		service.ArchiveCandles()
+		open := len(archive.appended)
+		service.now = func() time.Time { return executedAt.Add(48 * time.Hour) }
+		service.ArchiveCandles()
+		service.ArchiveCandles()
+
+		// Then: Open bars wait, and each closed bar is appended exactly once
+		if open != 0 {
+			t.Errorf("Expected no open bars archived, got %d intervals", open)
+		}
+		for _, interval := range marketdata.Intervals {
+			if bars := archive.appended[interval]; len(bars) != 1 || bars[0].Volume != 1 {
+				t.Errorf("Expected one %s bar, got %+v", interval, bars)
+			}
+		}
+	})
+}
+
+func TestExchangeService_InstrumentChanges(t *testing.T) {
+	t.Run("announces_then_activates_at_effective_time", func(t *testing.T) {
+		// Given: A tick size and price band change scheduled an hour ahead
+		ctx := context.Background()
+		service := newTestExchangeService()
+		now := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+		change, err := service.ScheduleInstrumentChange(ctx, "BTC-USD", InstrumentChangeRequest{
+			TickSize:         0.5,
+			PriceBandPercent: 5,
+			EffectiveAt:      now.Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Expected change to be scheduled, got %v", err)
+		}
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000.25}
+
+		// When: An off-tick order is checked before and after the effective time
+		before := service.CheckOrder(ctx, order)
+		now = now.Add(time.Hour)
+		after := service.CheckOrder(ctx, order)
+
+		// Then: The old tick applies until activation, then the new tick and band are in force
+		if !before.Accepted {
+			t.Errorf("Expected the order to pass before activation, got %v", before.Reasons)
+		}
+		if after.Accepted || after.Rejections[0].Reason != RejectInvalidPrice {
+			t.Errorf("Expected an invalid price rejection after activation, got %+v", after)
+		}
+		breaker, _ := service.Engine().CircuitBreaker("BTC-USD")
+		if breaker.ThresholdPercent != 5 {
+			t.Errorf("Expected price band 5%%, got %v", breaker.ThresholdPercent)
+		}
+		events := service.InstrumentEvents(ctx, 0)
+		if len(events) != 2 || events[0].Type != InstrumentEventAnnounced || events[1].Type != InstrumentEventActivated {
+			t.Fatalf("Expected announced then activated events, got %+v", events)
+		}
+		if events[1].Change.ID != change.ID || events[1].Instrument.TickSize != 0.5 {
+			t.Errorf("Expected the activation to carry the new specification, got %+v", events[1])
+		}
+	})
+
+	t.Run("canceled_changes_never_activate", func(t *testing.T) {
+		// Given: A scheduled lot size change that is withdrawn
+		ctx := context.Background()
+		service := newTestExchangeService()
+		now := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+		change, _ := service.ScheduleInstrumentChange(ctx, "ETH-USD", InstrumentChangeRequest{LotSize: 0.1, EffectiveAt: now.Add(time.Minute)})
+		if _, err := service.CancelInstrumentChange(ctx, change.ID); err != nil {
+			t.Fatalf("Expected cancel to succeed, got %v", err)
+		}
+
+		// When: The effective time passes
+		now = now.Add(time.Hour)
+		activated := service.ActivateInstrumentChanges(ctx)
+
+		// Then: The instrument keeps its lot size
+		instrument, _ := service.Instruments().Get("ETH-USD")
+		if activated != 0 || instrument.LotSize != 0.001 {
+			t.Errorf("Expected no activation, got %d and lot size %v", activated, instrument.LotSize)
+		}
+		if _, err := service.CancelInstrumentChange(ctx, change.ID); err == nil {
+			t.Error("Expected a second cancel to fail")
+		}
+	})
+
+	t.Run("rejects_changes_not_in_the_future", func(t *testing.T) {
+		service := newTestExchangeService()
+		_, err := service.ScheduleInstrumentChange(context.Background(), "BTC-USD", InstrumentChangeRequest{TickSize: 1, EffectiveAt: time.Now().Add(-time.Minute)})
+		if RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected an invalid request, got %v", err)
+		}
+	})
+}
+
+func TestExchangeService_Sessions(t *testing.T) {
+	placeResting := func(t *testing.T, service *ExchangeService, accountID string) string {
+		report, err := service.PlaceOrder(context.Background(), OrderRequest{
+			AccountID: accountID, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000,
+		})
+		if err != nil {
+			t.Fatalf("Failed to place order: %v", err)
+		}
+		return report.Order.ID
+	}
+
+	t.Run("reconnect_within_grace_keeps_orders", func(t *testing.T) {
+		// Given: A cancel-on-disconnect session with a resting order
+		ctx := context.Background()
+		service := newTestExchangeService()
+		session, _ := service.OpenSession(ctx, "mm-1", SessionOptions{CancelOnDisconnect: true, GracePeriod: 50 * time.Millisecond})
+		orderID := placeResting(t, service, "mm-1")
+
+		// When: The session drops and the account reconnects before the grace period ends
+		if err := service.CloseSession(ctx, session.ID); err != nil {
+			t.Fatalf("Expected close to succeed, got %v", err)
+		}
+		if _, err := service.OpenSession(ctx, "mm-1", SessionOptions{}); err != nil {
+			t.Fatalf("Expected reopen to succeed, got %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		// Then: The order keeps working
+		if order, _ := service.GetOrder(ctx, orderID); order.Status != models.OrderStatusNew {
+			t.Errorf("Expected the order to stay working, got %s", order.Status)
+		}
+	})
+
+	t.Run("sessions_without_the_flag_leave_orders", func(t *testing.T) {
+		// Given: A plain session with a resting order
+		ctx := context.Background()
+		service := newTestExchangeService()
+		session, _ := service.OpenSession(ctx, "mm-1", SessionOptions{GracePeriod: time.Millisecond})
+		orderID := placeResting(t, service, "mm-1")
+
+		// When: The session drops
+		service.CloseSession(ctx, session.ID)
+		time.Sleep(20 * time.Millisecond)
+
+		// Then: Nothing is canceled
+		if order, _ := service.GetOrder(ctx, orderID); order.Status != models.OrderStatusNew {
+			t.Errorf("Expected the order to stay working, got %s", order.Status)
+		}
+	})
+
+	t.Run("only_the_last_session_arms_the_cancel", func(t *testing.T) {
+		// Given: Two sessions for the account, one asking for cancel-on-disconnect
+		ctx := context.Background()
+		service := newTestExchangeService()
+		flagged, _ := service.OpenSession(ctx, "mm-1", SessionOptions{CancelOnDisconnect: true, GracePeriod: time.Millisecond})
+		service.OpenSession(ctx, "mm-1", SessionOptions{})
+		orderID := placeResting(t, service, "mm-1")
+
+		// When: The flagged session drops while the other stays open
+		service.CloseSession(ctx, flagged.ID)
+		time.Sleep(20 * time.Millisecond)
+
+		// Then: The account is still connected, so nothing is canceled
+		if order, _ := service.GetOrder(ctx, orderID); order.Status != models.OrderStatusNew {
+			t.Errorf("Expected the order to stay working, got %s", order.Status)
+		}
+		if err := service.CloseSession(ctx, flagged.ID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("refuses_a_session_for_another_account", func(t *testing.T) {
+		// Given: Two accounts, the victim with a resting order, and a trading key of the other
+		ctx := context.Background()
+		service := newTestExchangeService()
+		victim, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		other, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		issued, err := service.CreateAPIKey(ctx, other.ID, NewAPIKeyRequest{Label: "bot", Permissions: []accounts.Permission{accounts.PermissionTrade}})
+		if err != nil {
+			t.Fatalf("Expected a key, got %v", err)
+		}
+		orderID := placeResting(t, service, victim.ID)
+
+		// When: The key opens a cancel-on-disconnect session for the victim
+		_, err = service.OpenSession(keystats.WithAPIKey(ctx, issued.ID), victim.ID, SessionOptions{CancelOnDisconnect: true, GracePeriod: time.Millisecond})
+
+		// Then: It is refused, so no session can drop and cancel the victim's orders
+		if err == nil || RejectionOf(err).Reason != RejectPermissionDenied {
+			t.Errorf("Expected PERMISSION_DENIED, got %v", err)
+		}
+		if sessions := service.Sessions(ctx); len(sessions) != 0 {
+			t.Errorf("Expected no session registered, got %+v", sessions)
+		}
+		if order, _ := service.GetOrder(ctx, orderID); order.Status != models.OrderStatusNew {
+			t.Errorf("Expected the victim's order to keep working, got %s", order.Status)
+		}
+	})
+}
+
+func TestExchangeService_AccountLedger(t *testing.T) {
+	t.Run("nets_a_triangle_across_quote_currencies", func(t *testing.T) {
+		// Given: A trader buying BTC for USD, selling it for EUR and selling the EUR for USD
+		ctx := context.Background()
+		service := newTestExchangeService()
+		trade := func(symbol string, side models.Side, quantity, price float64) {
+			maker := OrderRequest{AccountID: "mm", Symbol: symbol, Side: side.Opposite(), Type: models.OrderTypeLimit, Quantity: quantity, Price: price}
+			if _, err := service.PlaceOrder(ctx, maker); err != nil {
+				t.Fatalf("Failed to rest %s order: %v", symbol, err)
+			}
+			taker := OrderRequest{AccountID: "arb", Symbol: symbol, Side: side, Type: models.OrderTypeLimit, Quantity: quantity, Price: price}
+			if report, err := service.PlaceOrder(ctx, taker); err != nil || len(report.Trades) != 1 {
+				t.Fatalf("Expected %s to trade, got %v", symbol, err)
+			}
+		}
+		trade("BTC-USD", models.SideBuy, 1, 60000)
+		trade("BTC-EUR", models.SideSell, 1, 55500)
+		trade("EUR-USD", models.SideSell, 55500, 1.0909)
+
+		// When: Its ledger is read
+		ledger, err := service.AccountLedger(ctx, "arb")
+		if err != nil {
+			t.Fatalf("Expected ledger, got %v", err)
+		}
+
+		// Then: BTC and EUR are flat and the arbitrage profit sits in USD
+		positions := make(map[string]float64)
+		for _, position := range ledger.Positions {
+			positions[position.Asset] = position.Quantity
+		}
+		if positions["BTC"] != 0 || positions["EUR"] != 0 {
+			t.Errorf("Expected flat BTC and EUR, got %v", positions)
+		}
+		if usd := positions["USD"]; usd < 544.9 || usd > 545 {
+			t.Errorf("Expected about 544.95 USD profit, got %v", usd)
+		}
+	})
+
+	t.Run("lists_books_sharing_an_asset", func(t *testing.T) {
+		service := newTestExchangeService()
+		symbols := make([]string, 0)
+		for _, instrument := range service.Instruments().ByAsset("EUR") {
+			symbols = append(symbols, instrument.Symbol)
+		}
+		if len(symbols) != 3 || symbols[0] != "BTC-EUR" || symbols[1] != "ETH-EUR" || symbols[2] != "EUR-USD" {
+			t.Errorf("Expected the EUR books, got %v", symbols)
+		}
+	})
+}
+
+func TestExchangeService_AccountValuation(t *testing.T) {
+	t.Run("values_positions_in_the_requested_currency", func(t *testing.T) {
+		// Given: An account that bought 2 BTC for USD at 60000
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "mm", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 2, Price: 60000})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "fund", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 2, Price: 60000})
+
+		// When: It is valued in USD and in EUR
+		inUSD, err := service.AccountValuation(ctx, "fund", "")
+		if err != nil {
+			t.Fatalf("Expected a USD valuation, got %v", err)
+		}
+		inEUR, err := service.AccountValuation(ctx, "fund", "EUR")
+		if err != nil {
+			t.Fatalf("Expected a EUR valuation, got %v", err)
+		}
+
+		// Then: The USD view nets to zero at the traded mark
+		if inUSD.Currency != defaultReportingCurrency || math.Abs(inUSD.Total) > 1e-6 {
+			t.Errorf("Expected a flat USD total, got %+v", inUSD)
+		}
+
+		// And: The EUR view prices BTC on BTC-EUR and USD through EUR-USD
+		rates := make(map[string]ValuedPosition)
+		for _, position := range inEUR.Positions {
+			rates[position.Asset] = position
+		}
+		if btc := rates["BTC"]; btc.Rate != 55000 || btc.Value != 110000 || len(btc.Path) != 1 || btc.Path[0] != "BTC-EUR" {
+			t.Errorf("Expected BTC at 55000 EUR via BTC-EUR, got %+v", btc)
+		}
+		if usd := rates["USD"]; math.Abs(usd.Rate-1/1.0909) > 1e-12 || len(usd.Path) != 1 || usd.Path[0] != "EUR-USD" {
+			t.Errorf("Expected USD through EUR-USD, got %+v", usd)
+		}
+		if expected := 110000 - 120000/1.0909; math.Abs(inEUR.Total-expected) > 1e-6 {
+			t.Errorf("Expected total %v, got %v", expected, inEUR.Total)
+		}
+	})
+
+	t.Run("rejects_unpriced_reporting_currencies", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		_, err := service.AccountValuation(context.Background(), "fund", "GBP")
+		if RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected INVALID_REQUEST for GBP, got %v", err)
+		}
+		_, err = service.AccountValuation(context.Background(), "", "USD")
+		if RejectionOf(err).Reason != RejectInvalidAccount {
+			t.Errorf("Expected INVALID_ACCOUNT without an account, got %v", err)
+		}
+	})
+}
+
+func TestExchangeService_AccountProfiles(t *testing.T) {
+	ask := func(account string) OrderRequest {
+		return OrderRequest{AccountID: account, Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+	}
+
+	t.Run("colocated_priority_accounts_fill_first_at_a_price", func(t *testing.T) {
+		// Given: A remote ask resting before a colocated account's ask at the same price
+		ctx := context.Background()
+		service := newTestExchangeService()
+		if _, err := service.SetAccountProfile(ctx, AccountProfile{AccountID: "colo", Priority: 1}); err != nil {
+			t.Fatalf("Expected profile to be set, got %v", err)
+		}
+		service.PlaceOrder(ctx, ask("remote"))
+		colocated, _ := service.PlaceOrder(ctx, ask("colo"))
+
+		// When: One unit is bought
+		report, err := service.PlaceOrder(ctx, OrderRequest{AccountID: "t", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		if err != nil || len(report.Trades) != 1 {
+			t.Fatalf("Expected one trade, got %v", err)
+		}
+
+		// Then: The colocated ask fills despite arriving second
+		if report.Trades[0].SellOrderID != colocated.Order.ID || colocated.Order.Priority != 1 {
+			t.Errorf("Expected the tier-1 ask to fill, got %+v", report.Trades[0])
+		}
+	})
+
+	t.Run("delays_order_actions_by_gateway_latency", func(t *testing.T) {
+		// Given: A remote account 30ms from the venue
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.SetAccountProfile(ctx, AccountProfile{AccountID: "remote", Latency: 30 * time.Millisecond})
+
+		// When: It places and cancels an order
+		start := time.Now()
+		report, _ := service.PlaceOrder(ctx, ask("remote"))
+		service.CancelOrder(ctx, report.Order.ID)
+
+		// Then: Each action waited for the gateway
+		if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+			t.Errorf("Expected at least 60ms for two actions, took %s", elapsed)
+		}
+
+		// And: A canceled context abandons the wait
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := service.PlaceOrder(canceled, ask("remote")); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("validates_parses_and_removes_profiles", func(t *testing.T) {
+		ctx := context.Background()
+		service := newTestExchangeService()
+
+		if _, err := service.SetAccountProfile(ctx, AccountProfile{AccountID: "a", Latency: time.Minute}); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected a minute of latency to be rejected, got %v", err)
+		}
+		profiles, err := ParseAccountProfiles("mm-colo=50us:1, retail-1=20ms")
+		if err != nil || len(profiles) != 2 {
+			t.Fatalf("Expected two profiles, got %+v, %v", profiles, err)
+		}
+		if profiles[0] != (AccountProfile{AccountID: "mm-colo", Latency: 50 * time.Microsecond, Priority: 1}) ||
+			profiles[1] != (AccountProfile{AccountID: "retail-1", Latency: 20 * time.Millisecond}) {
+			t.Errorf("Unexpected profiles %+v", profiles)
+		}
+		if _, err := ParseAccountProfiles("mm-colo=fast"); err == nil {
+			t.Error("Expected an invalid latency to fail")
+		}
+
+		service.SetAccountProfile(ctx, profiles[0])
+		service.PurgeAccount(ctx, "mm-colo")
+		if err := service.RemoveAccountProfile(ctx, "mm-colo"); !errors.Is(err, ErrAccountProfileNotFound) {
+			t.Errorf("Expected the purge to drop the profile, got %v", err)
+		}
+	})
+}
+
+func TestExchangeService_Scheduler(t *testing.T) {
+	start := time.Date(2024, 1, 2, 7, 30, 0, 0, time.UTC)
+
+	t.Run("expires_gtd_orders_on_the_venue_clock", func(t *testing.T) {
+		// Given: A service on a stepped venue clock holding a GTD order
+		ctx := context.Background()
+		clock := &stepClock{now: start}
+		cfg := &config.Config{ServiceName: "exchange-simulator"}
+		cfg.SetClock(clock)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(cfg, logger)
+
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit,
+			TimeInForce: models.TimeInForceGTD, Quantity: 1, Price: 59000, ExpiresAt: start.Add(time.Hour)}
+		report, err := service.PlaceOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("Expected GTD order to be placed, got %v", err)
+		}
+		order.ExpiresAt = time.Time{}
+		if _, err := service.PlaceOrder(ctx, order); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected a GTD order without expiry to be rejected, got %v", err)
+		}
+
+		// When: The clock passes the expiry and scheduled work runs
+		clock.now = start.Add(2 * time.Hour)
+		service.RunScheduledWork(ctx)
+
+		// Then: The order is expired
+		if expired, _ := service.GetOrder(ctx, report.Order.ID); expired.Status != models.OrderStatusExpired {
+			t.Errorf("Expected expired, got %s", expired.Status)
+		}
+	})
+
+	t.Run("publishes_funding_at_each_interval_boundary", func(t *testing.T) {
+		// Given: A scheduler first run at 07:30
+		service := newTestExchangeService()
+		service.publishFunding(start)
+
+		// When: The clock moves past 08:00 and 16:00
+		service.publishFunding(start.Add(time.Hour))
+		service.publishFunding(start.Add(9 * time.Hour))
+
+		// Then: Each perpetual funds at both boundaries
+		events := service.FundingEvents(context.Background(), "BTC-USD-PERP")
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 funding events, got %+v", events)
+		}
+		if !events[0].FundingTime.Equal(time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)) ||
+			!events[1].FundingTime.Equal(time.Date(2024, 1, 2, 16, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected funding times: %+v", events)
+		}
+		if events[0].Rate != 0.0001 || events[0].MarkPrice != 60000 {
+			t.Errorf("Unexpected funding event: %+v", events[0])
+		}
+	})
+
+	t.Run("transfers_funding_from_longs_to_shorts_at_the_premium_driven_rate", func(t *testing.T) {
+		// Given: Funded accounts long and short 0.1 contracts at 60000, and a book whose
+		// mid of 60315 trades 0.525% over the 60000 index
+		ctx := context.Background()
+		service := newTestExchangeService()
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 10000}}, 2)
+		long, short := funded[0].ID, funded[1].ID
+		order := OrderRequest{AccountID: short, Symbol: "BTC-USD-PERP", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = long, models.SideBuy
+		if _, err := service.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("Expected the long to open, got %v", err)
+		}
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "mm", Symbol: "BTC-USD-PERP", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60300})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "mm", Symbol: "BTC-USD-PERP", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60330})
+
+		// When: The scheduler samples the premium at 07:30 and funds at 08:00
+		service.publishFunding(start)
+		service.publishFunding(start.Add(time.Hour))
+
+		// Then: The rate is the premium less the 0.05% interest clamp, the long pays
+		// 0.1 × 60000 × 0.475% to the short, and the journal stays balanced
+		events := service.FundingEvents(ctx, "BTC-USD-PERP")
+		if len(events) != 1 || !approxEqual(events[0].Rate, 0.00475) || events[0].Positions != 2 || !approxEqual(events[0].Transferred, 28.5) {
+			t.Fatalf("Expected one 0.475%% funding between two positions, got %+v", events)
+		}
+		payments, _ := service.FundingPayments(ctx, long, "")
+		if len(payments) != 1 || !approxEqual(payments[0].Amount, 28.5) || !payments[0].Posted {
+			t.Errorf("Expected the long to pay 28.5, got %+v", payments)
+		}
+		if available := service.balances.journal.Available(long, "USD"); !approxEqual(available, 10000-28.5) {
+			t.Errorf("Expected the long to have 9971.5 USD, got %v", available)
+		}
+		if available := service.balances.journal.Available(short, "USD"); !approxEqual(available, 10000+28.5) {
+			t.Errorf("Expected the short to have 10028.5 USD, got %v", available)
+		}
+		if trial := service.TrialBalance(ctx); !trial.Balanced {
+			t.Errorf("Expected a balanced journal, got %+v", trial)
+		}
+	})
+
+	t.Run("runs_session_transitions_when_due", func(t *testing.T) {
+		// Given: A closing auction and its uncross scheduled for 16:00
+		ctx := context.Background()
+		service := newTestExchangeService()
+		now := start
+		service.now = func() time.Time { return now }
+		closeAt := time.Date(2024, 1, 2, 16, 0, 0, 0, time.UTC)
+		service.ScheduleTransition(ctx, "ETH-USD", TransitionClosingAuction, closeAt)
+		service.ScheduleTransition(ctx, "ETH-USD", TransitionUncross, closeAt.Add(5*time.Minute))
+
+		// When: The clock passes both
+		now = closeAt.Add(10 * time.Minute)
+		service.RunScheduledWork(ctx)
+
+		// Then: The book went through its closing auction and is closed
+		if phase, _ := service.Engine().Phase("ETH-USD"); phase != matching.PhaseClosed {
+			t.Errorf("Expected the book to be closed, got %s", phase)
+		}
+		for _, transition := range service.PhaseTransitions(ctx, "ETH-USD") {
+			if transition.Status != TransitionDone {
+				t.Errorf("Expected %s to be done, got %+v", transition.Action, transition)
+			}
+		}
+	})
+}
+
+func TestExchangeService_SubmitOrder(t *testing.T) {
+	order := func(account string, side models.Side) OrderRequest {
+		return OrderRequest{AccountID: account, Symbol: "BTC-USD", Side: side, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+	}
+	acks := func(sub *feed.JournalSubscription, n int) []feed.OrderAck {
+		received := make([]feed.OrderAck, 0, n)
+		for len(received) < n {
+			if execution := <-sub.Executions(); execution.Ack != nil {
+				received = append(received, *execution.Ack)
+			}
+		}
+		return received
+	}
+
+	t.Run("acks_on_the_execution_stream_ahead_of_fills", func(t *testing.T) {
+		// Given: A stream following the journal and a resting ask
+		ctx := context.Background()
+		service := newTestExchangeService()
+		sub, _, _ := service.Executions().Subscribe(0)
+		defer sub.Close()
+		service.PlaceOrder(ctx, order("maker", models.SideSell))
+		<-sub.Executions()
+
+		// When: A crossing bid is submitted
+		bid, err := service.SubmitOrder(ctx, order("taker", models.SideBuy))
+		if err != nil {
+			t.Fatalf("Expected a receipt, got %v", err)
+		}
+		service.WaitAsyncOrders()
+
+		// Then: Its ack comes first, then its update, the trade and the maker's fill
+		first := <-sub.Executions()
+		if first.Ack == nil || first.Ack.RequestSequence != bid.RequestSequence || !first.Ack.Accepted || first.Ack.OrderID == "" {
+			t.Fatalf("Expected the bid's ack first, got %+v", first)
+		}
+		if update := <-sub.Executions(); update.Order == nil || update.Order.ID != first.Ack.OrderID {
+			t.Errorf("Expected the bid's update next, got %+v", update)
+		}
+		if trade := <-sub.Executions(); trade.Trade == nil {
+			t.Errorf("Expected the trade next, got %+v", trade)
+		}
+		if fill := <-sub.Executions(); fill.Order == nil || fill.Order.AccountID != "maker" {
+			t.Errorf("Expected the maker's fill last, got %+v", fill)
+		}
+
+		// And: An order for an unlisted symbol is rejected on the stream, not by the call
+		unlisted := order("taker", models.SideBuy)
+		unlisted.Symbol = "DOGE-USD"
+		rejected, err := service.SubmitOrder(ctx, unlisted)
+		if err != nil {
+			t.Fatalf("Expected a receipt, got %v", err)
+		}
+		if ack := acks(sub, 1)[0]; ack.RequestSequence != rejected.RequestSequence || ack.Accepted || ack.Reason != string(RejectUnknownInstrument) {
+			t.Errorf("Expected an UNKNOWN_INSTRUMENT ack, got %+v", ack)
+		}
+	})
+
+	t.Run("latency_lets_acks_overtake_earlier_submissions", func(t *testing.T) {
+		// Given: A remote account 30ms from the venue
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.SetAccountProfile(ctx, AccountProfile{AccountID: "remote", Latency: 30 * time.Millisecond})
+		sub, _, _ := service.Executions().Subscribe(0)
+		defer sub.Close()
+
+		// When: It submits before a colocated account
+		first, _ := service.SubmitOrder(ctx, order("remote", models.SideSell))
+		second, _ := service.SubmitOrder(ctx, order("colo", models.SideSell))
+
+		// Then: The later submission is acked first
+		received := acks(sub, 2)
+		if received[0].RequestSequence != second.RequestSequence || received[1].RequestSequence != first.RequestSequence {
+			t.Errorf("Expected request %d acked before %d, got %+v", second.RequestSequence, first.RequestSequence, received)
+		}
+	})
+
+	t.Run("requires_an_account", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		_, err := service.SubmitOrder(context.Background(), order("", models.SideBuy))
+
+		if RejectionOf(err).Reason != RejectInvalidAccount {
+			t.Errorf("Expected INVALID_ACCOUNT, got %v", err)
+		}
+	})
+}
+
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	return c.now
+}
+
+func TestExchangeService_SyntheticMarket(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	market := SyntheticMarketConfig{
+		Symbols:       []string{"BTC-USD"},
+		Dynamics:      synthetic.Dynamics{Model: synthetic.ModelGBM, Volatility: 0.6},
+		Ladder:        liquidity.Ladder{Levels: 3, SpreadBps: 10, StepBps: 5, LevelNotional: 10000},
+		TradeNotional: 1000,
+		Seed:          7,
+	}
+
+	t.Run("quotes_and_prints_around_the_path", func(t *testing.T) {
+		// Given: A synthetic market on BTC-USD with a fixed seed
+		ctx := context.Background()
+		service := newTestExchangeService()
+		now := start
+		service.now = func() time.Time { return now }
+		if err := service.EnableSyntheticMarket(market); err != nil {
+			t.Fatalf("Expected the synthetic market to be enabled, got %v", err)
+		}
+
+		// When: It steps twice, a second apart
+		now = start.Add(time.Second)
+		service.StepSyntheticMarket(ctx)
+		now = start.Add(2 * time.Second)
+		service.StepSyntheticMarket(ctx)
+
+		// Then: One ladder rests around the path and a trade printed each step
+		report := service.SyntheticMarket(ctx)
+		if len(report) != 1 || report[0].Steps != 2 || report[0].Quotes != 6 || report[0].Price == 60000 {
+			t.Fatalf("Unexpected synthetic report: %+v", report)
+		}
+		quotes, _ := service.OpenOrders(ctx, SyntheticMakerAccount, "BTC-USD")
+		if len(quotes) != 6 {
+			t.Errorf("Expected the first ladder to be replaced, got %d resting orders", len(quotes))
+		}
+		book, _ := service.Engine().Snapshot("BTC-USD", 1)
+		if len(book.Bids) != 1 || len(book.Asks) != 1 || book.Bids[0].Price >= report[0].Price || book.Asks[0].Price <= report[0].Price {
+			t.Errorf("Expected the book to straddle %v, got %+v", report[0].Price, book)
+		}
+		if trades, _ := service.Trades(ctx, SyntheticTakerAccount, "BTC-USD", 10); len(trades) != 2 {
+			t.Errorf("Expected a print per step, got %d", len(trades))
+		}
+	})
+
+	t.Run("rejects_unknown_symbols_and_models", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		unknown := market
+		unknown.Symbols = []string{"DOGE-USD"}
+		if err := service.EnableSyntheticMarket(unknown); RejectionOf(err).Reason != RejectUnknownInstrument {
+			t.Errorf("Expected an unknown instrument, got %v", err)
+		}
+		invalid := market
+		invalid.Dynamics.Model = "ou"
+		if err := service.EnableSyntheticMarket(invalid); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected an invalid request, got %v", err)
+		}
+		if report := service.SyntheticMarket(context.Background()); len(report) != 0 {
+			t.Errorf("Expected no synthetic market, got %+v", report)
+		}
+	})
+}
+
+func TestExchangeService_MarketMaker(t *testing.T) {
+	t.Run("replenishes_fills_and_follows_the_mark", func(t *testing.T) {
+		// Given: Two levels a side of 0.5 BTC quoted around BTC-USD
+		ctx := context.Background()
+		service := newTestExchangeService()
+		quotes, err := ParseMarketMakerQuotes("BTC-USD=10:0.5:2:5")
+		if err != nil {
+			t.Fatalf("Expected the quotes to parse, got %v", err)
+		}
+		if err := service.EnableMarketMaker(MarketMakerConfig{Account: "mm", Quotes: quotes, RequoteBps: 10}); err != nil {
+			t.Fatalf("Expected the market maker to be enabled, got %v", err)
+		}
+		service.RefreshMarketMaker(ctx)
+		first, _ := service.OpenOrders(ctx, "mm", "BTC-USD")
+
+		// When: Nothing trades before the next refresh
+		service.RefreshMarketMaker(ctx)
+
+		// Then: The same orders keep their place in the queue
+		if second, _ := service.OpenOrders(ctx, "mm", "BTC-USD"); len(first) != 4 || !sameOrders(first, second) {
+			t.Fatalf("Expected the four quotes to stay, got %d then %d", len(first), len(second))
+		}
+
+		// When: A strategy lifts part of the best ask
+		buy := OrderRequest{AccountID: "strategy", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeMarket, Quantity: 0.2}
+		if report, err := service.PlaceOrder(ctx, buy); err != nil || report.Order.Status != models.OrderStatusFilled {
+			t.Fatalf("Expected the strategy order to fill, got %+v, %v", report, err)
+		}
+		service.RefreshMarketMaker(ctx)
+
+		// Then: The ask is back to full size at the same price
+		book, _ := service.Engine().Snapshot("BTC-USD", 1)
+		if book.Asks[0].Price != 60030 || book.Asks[0].Quantity != 0.5 {
+			t.Errorf("Expected 0.5 at 60030, got %+v", book.Asks)
+		}
+
+		// When: The market trades away at 61000
+		sweep := OrderRequest{AccountID: "strategy", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 2, Price: 61000}
+		service.PlaceOrder(ctx, sweep)
+		sweep.AccountID, sweep.Side, sweep.Quantity = "seller", models.SideSell, 1
+		service.PlaceOrder(ctx, sweep)
+		service.RefreshMarketMaker(ctx)
+
+		// Then: Filled asks are replenished and the bids move up with the ladder
+		status := service.MarketMaker(ctx)
+		if len(status) != 1 || status[0].Reference != 61000 || status[0].Resting != 4 || status[0].Replenished != 3 || status[0].Requoted != 2 {
+			t.Errorf("Unexpected market maker status: %+v", status)
+		}
+		book, _ = service.Engine().Snapshot("BTC-USD", 1)
+		if book.Bids[0].Price != 60969.5 || book.Asks[0].Price != 61030.5 {
+			t.Errorf("Expected the ladder around 61000, got %+v", book)
+		}
+	})
+
+	t.Run("rejects_invalid_quotes", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		for _, spec := range []string{"BTC-USD", "BTC-USD=10", "BTC-USD=ten:1", "BTC-USD=10:1:2:5:9"} {
+			if _, err := ParseMarketMakerQuotes(spec); err == nil {
+				t.Errorf("Expected %q to be rejected", spec)
+			}
+		}
+		quotes, _ := ParseMarketMakerQuotes("DOGE-USD=10:1")
+		if err := service.EnableMarketMaker(MarketMakerConfig{Account: "mm", Quotes: quotes}); RejectionOf(err).Reason != RejectUnknownInstrument {
+			t.Errorf("Expected an unknown instrument, got %v", err)
+		}
+		quotes, _ = ParseMarketMakerQuotes("BTC-USD=0:1")
+		if err := service.EnableMarketMaker(MarketMakerConfig{Account: "mm", Quotes: quotes}); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected an invalid ladder, got %v", err)
+		}
+	})
+}
+
+func sameOrders(a, b []models.Order) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[string]bool, len(a))
+	for _, order := range a {
+		ids[order.ID] = true
+	}
+	for _, order := range b {
+		if !ids[order.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// flakyPriceSource fails its first subscription, then delivers prices and waits
+type flakyPriceSource struct {
+	prices []pricefeed.Price
+	calls  int
+}
+
+func (s *flakyPriceSource) Follow(ctx context.Context, handle func(pricefeed.Price)) error {
+	s.calls++
+	if s.calls == 1 {
+		return errors.New("connection refused")
+	}
+	for _, price := range s.prices {
+		handle(price)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestExchangeService_ReferencePrices(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("anchor_the_market_maker_until_stale", func(t *testing.T) {
+		// Given: An external BTC-USD price 2000 above the listing reference
+		ctx := context.Background()
+		service := newTestExchangeService()
+		now := start
+		service.now = func() time.Time { return now }
+		if _, err := service.UpdateReferencePrice(ctx, pricefeed.Price{Symbol: "BTC-USD", Price: 62000, Source: "mds"}); err != nil {
+			t.Fatalf("Expected the price to be recorded, got %v", err)
+		}
+		quotes, _ := ParseMarketMakerQuotes("BTC-USD=10:0.5")
+		service.EnableMarketMaker(MarketMakerConfig{Account: "mm", Quotes: quotes})
+
+		// When: The market maker quotes while the price is fresh, and again once it has aged out
+		service.RefreshMarketMaker(ctx)
+		anchored := service.MarketMaker(ctx)[0].Reference
+		now = start.Add(defaultPriceFeedMaxAge + time.Second)
+		service.RefreshMarketMaker(ctx)
+
+		// Then: It followed the external price, then fell back to the listing reference
+		if anchored != 62000 {
+			t.Errorf("Expected quotes around 62000, got %v", anchored)
+		}
+		if reference := service.MarketMaker(ctx)[0].Reference; reference != 60000 {
+			t.Errorf("Expected quotes around 60000 once stale, got %v", reference)
+		}
+		if prices := service.ReferencePrices(ctx); len(prices) != 1 || prices[0].Fresh || !prices[0].ReceivedAt.Equal(start) {
+			t.Errorf("Expected one stale price received at start, got %+v", prices)
+		}
+		if _, err := service.UpdateReferencePrice(ctx, pricefeed.Price{Symbol: "DOGE-USD", Price: 1}); RejectionOf(err).Reason != RejectUnknownInstrument {
+			t.Errorf("Expected an unknown instrument, got %v", err)
+		}
+	})
+
+	t.Run("resubscribe_after_the_feed_fails", func(t *testing.T) {
+		// Given: A feed that refuses its first subscription
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		service := newTestExchangeService()
+		source := &flakyPriceSource{prices: []pricefeed.Price{{Symbol: "ETH-USD", Price: 3100}}}
+
+		// When: The service follows it
+		done := make(chan struct{})
+		go func() {
+			service.FollowPriceFeed(ctx, source, time.Millisecond)
+			close(done)
+		}()
+		deadline := time.Now().Add(2 * time.Second)
+		for len(service.ReferencePrices(ctx)) == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		<-done
+
+		// Then: The price arrived on the second subscription and the outage is on the timeline
+		if prices := service.ReferencePrices(context.Background()); len(prices) != 1 || prices[0].Price != 3100 {
+			t.Fatalf("Expected the ETH-USD price, got %+v", prices)
+		}
+		report, _ := service.Incidents(context.Background(), incidents.Query{Component: ComponentPriceFeed})
+		if len(report.Incidents) != 2 || report.Incidents[0].Status != incidents.StatusDegraded || report.Incidents[1].Status != incidents.StatusUp {
+			t.Errorf("Expected the feed degraded then up, got %+v", report.Incidents)
+		}
+	})
+}
+
+func TestExchangeService_APIVersions(t *testing.T) {
+	t.Run("counts_and_records_calls_after_deprecation", func(t *testing.T) {
+		// Given: v1 deprecated ten minutes and retired twenty after startup, under a scenario
+		ctx := context.Background()
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		service := newTestExchangeService()
+		service.now = func() time.Time { return start }
+		loaded, err := scenario.Parse([]byte(`{"name": "migration", "assertions": [
+			{"name": "no retired calls", "kind": "count", "event": {"type": "api_call", "component": "api:v1", "status": "retired"}, "max": 0}
+		]}`))
+		if err != nil {
+			t.Fatalf("Expected the scenario to parse, got %v", err)
+		}
+		service.SetScenario(loaded)
+		lifecycle, ok, err := APILifecycleFromConfig(&config.Config{APIV1DeprecatedAt: "10m", APIV1SunsetAt: "20m"}, start)
+		if err != nil || !ok {
+			t.Fatalf("Expected a v1 lifecycle, got %v, %v", ok, err)
+		}
+		if _, err := service.SetAPILifecycle(ctx, lifecycle); err != nil {
+			t.Fatalf("SetAPILifecycle failed: %v", err)
+		}
+
+		// When: A client keeps calling v1 through deprecation and past its sunset
+		for _, at := range []time.Duration{0, 15 * time.Minute, 25 * time.Minute} {
+			service.now = func() time.Time { return start.Add(at) }
+			service.CheckAPIVersion(ctx, "v1")
+		}
+		_, retiredErr := service.CheckAPIVersion(ctx, "v1")
+
+		// Then: Deprecated and retired calls are counted, and the retired ones fail the scenario
+		if RejectionOf(retiredErr).Reason != RejectAPIVersionRetired {
+			t.Errorf("Expected API_VERSION_RETIRED, got %v", retiredErr)
+		}
+		versions := service.APIVersions(ctx)
+		if len(versions) != 1 || versions[0].Phase != apiversion.PhaseRetired || versions[0].Successor != "v2" {
+			t.Fatalf("Expected retired v1 succeeded by v2, got %+v", versions)
+		}
+		if versions[0].DeprecatedCalls != 1 || versions[0].RetiredCalls != 2 {
+			t.Errorf("Expected 1 deprecated and 2 retired calls, got %+v", versions[0])
+		}
+		verdict, _ := service.ScenarioVerdict(ctx, true)
+		if verdict.Passed || verdict.Results[0].Observed != 2 {
+			t.Errorf("Expected the retired calls to fail the scenario, got %+v", verdict)
+		}
+		if status, err := service.CheckAPIVersion(ctx, "v2"); err != nil || status.Phase != apiversion.PhaseActive {
+			t.Errorf("Expected unscheduled v2 to be active, got %+v, %v", status, err)
+		}
+	})
+}
+
+// memoryTradeStore keeps the trade tape in memory, failing appends while down
+type memoryTradeStore struct {
+	trades []models.Trade
+	down   bool
+}
+
+func (s *memoryTradeStore) Append(trades []models.Trade) error {
+	if s.down {
+		return errors.New("connection refused")
+	}
+	s.trades = append(s.trades, trades...)
+	return nil
+}
+
+func (s *memoryTradeStore) Query(query tradetape.Query) (tradetape.Page, error) {
+	return tradetape.Paginate(s.trades, query), nil
+}
+
+func TestExchangeService_TradeHistory(t *testing.T) {
+	trade := func(service *ExchangeService, buyer, seller string) {
+		ctx := context.Background()
+		order := OrderRequest{AccountID: seller, Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = buyer, models.SideBuy
+		service.PlaceOrder(ctx, order)
+	}
+
+	t.Run("persists_trades_and_retries_a_failed_flush", func(t *testing.T) {
+		// Given: A trade store that is down while the first trade executes
+		service := newTestExchangeService()
+		store := &memoryTradeStore{down: true}
+		service.SetTradeStore(store)
+		trade(service, "a", "b")
+		failed := service.FlushTradeTape()
+
+		// When: The store recovers, a second trade executes and history is queried
+		store.down = false
+		trade(service, "c", "a")
+		page, err := service.TradeHistory(context.Background(), tradetape.Query{AccountID: "a", Limit: 10})
+
+		// Then: The failed batch was kept, both trades reached the store once, and the
+		// outage showed on the storage component
+		if failed == nil || err != nil {
+			t.Fatalf("Expected the first flush to fail and the query to succeed, got %v, %v", failed, err)
+		}
+		if len(store.trades) != 2 || len(page.Trades) != 2 || page.NextCursor != "" {
+			t.Errorf("Expected two stored trades on one page, got %d stored, %+v", len(store.trades), page)
+		}
+		report, _ := service.Incidents(context.Background(), incidents.Query{Component: componentTrades})
+		if len(report.Unhealthy) != 0 || len(report.Incidents) != 2 {
+			t.Errorf("Expected the trade store to have degraded then recovered, got %+v", report)
+		}
+	})
+
+	t.Run("pages_through_engine_history_without_a_store", func(t *testing.T) {
+		// Given: Three trades and no trade store
+		service := newTestExchangeService()
+		for i := 0; i < 3; i++ {
+			trade(service, "a", "b")
+		}
+
+		// When: History is read two trades at a time
+		first, err := service.TradeHistory(context.Background(), tradetape.Query{Symbol: "BTC-USD", Limit: 2})
+		if err != nil || first.NextCursor == "" {
+			t.Fatalf("Expected a first page with a cursor, got %+v, %v", first, err)
+		}
+		cursor, err := ParseTradeCursor(first.NextCursor)
+		if err != nil {
+			t.Fatalf("Expected the issued cursor to parse, got %v", err)
+		}
+		second, err := service.TradeHistory(context.Background(), tradetape.Query{Symbol: "BTC-USD", After: cursor, Limit: 2})
+
+		// Then: The second page holds the last trade and ends the tape
+		if err != nil || len(second.Trades) != 1 || second.NextCursor != "" || second.Trades[0].ID == first.Trades[1].ID {
+			t.Errorf("Expected the third trade alone on the last page, got %+v, %v", second, err)
+		}
+	})
+
+	t.Run("rejects_foreign_cursors_and_unlisted_symbols", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		if _, err := ParseTradeCursor("bm9wZQ"); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected INVALID_REQUEST for a foreign cursor, got %v", err)
+		}
+		if _, err := service.TradeHistory(context.Background(), tradetape.Query{Symbol: "DOGE-USD", Limit: 1}); err == nil {
+			t.Error("Expected an unlisted symbol to be rejected")
+		}
+	})
+}
+
+// memoryOrderStore keeps the latest state of each order and the batches it was saved in
+type memoryOrderStore struct {
+	orders  map[string]models.Order
+	batches []int
+	down    bool
+}
+
+func (s *memoryOrderStore) Save(orders []models.Order) error {
+	if s.down {
+		return errors.New("connection refused")
+	}
+	for _, order := range orderhistory.Latest(orders) {
+		s.orders[order.ID] = order
+	}
+	s.batches = append(s.batches, len(orders))
+	return nil
+}
+
+func (s *memoryOrderStore) Query(query orderhistory.Query) (orderhistory.Page, error) {
+	orders := make([]models.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		orders = append(orders, order)
+	}
+	return orderhistory.Paginate(orders, query), nil
+}
+
+func (s *memoryOrderStore) Reassign(accountID, alias string) error {
+	for id, order := range s.orders {
+		if order.AccountID == accountID {
+			order.AccountID = alias
+			s.orders[id] = order
+		}
+	}
+	return nil
+}
+
+func TestExchangeService_OrderStore(t *testing.T) {
+	t.Run("writes_the_latest_state_of_each_order_in_batches", func(t *testing.T) {
+		// Given: Queues writing two updates at a time and an order store that is down
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", WriteBehindBatchSize: 2}, logger)
+		store := &memoryOrderStore{orders: make(map[string]models.Order), down: true}
+		service.SetOrderStore(store)
+
+		// When: A resting order fills against another, the first flush fails and the
+		// store recovers
+		ctx := context.Background()
+		ask, _ := service.PlaceOrder(ctx, OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		bid, _ := service.PlaceOrder(ctx, OrderRequest{AccountID: "b", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		failed := service.FlushOrders()
+		store.down = false
+		err := service.FlushOrders()
+
+		// Then: Nothing was lost to the outage and both orders are stored filled,
+		// written no more than two updates at a time
+		if failed == nil || err != nil {
+			t.Fatalf("Expected the first flush to fail and the second to succeed, got %v, %v", failed, err)
+		}
+		for _, id := range []string{ask.Order.ID, bid.Order.ID} {
+			if order := store.orders[id]; order.Status != models.OrderStatusFilled {
+				t.Errorf("Expected %s stored filled, got %+v", id, order)
+			}
+		}
+		for _, batch := range store.batches {
+			if batch > 2 {
+				t.Errorf("Expected batches of at most 2, got %v", store.batches)
+			}
+		}
+		stats := service.WriteBehindStats(ctx)[queueOrders]
+		if stats.Queued != 0 || stats.Failed != 1 || stats.Written != stats.Enqueued {
+			t.Errorf("Expected a drained queue with one failed batch, got %+v", stats)
+		}
+	})
+
+	t.Run("history_reads_the_store_with_queued_updates_flushed", func(t *testing.T) {
+		// Given: An order store and an order whose cancel is still queued
+		ctx := context.Background()
+		service := newTestExchangeService()
+		store := &memoryOrderStore{orders: make(map[string]models.Order)}
+		service.SetOrderStore(store)
+		placed, _ := service.PlaceOrder(ctx, OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000})
+		service.FlushOrders()
+		service.CancelOrder(ctx, placed.Order.ID)
+
+		// When: Canceled orders are queried
+		page, err := service.OrderHistory(ctx, orderhistory.Query{Statuses: []models.OrderStatus{models.OrderStatusCanceled}, Limit: 10})
+
+		// Then: The store answers with the order in its latest state
+		if err != nil || len(page.Orders) != 1 || page.Orders[0].ID != placed.Order.ID {
+			t.Errorf("Expected the canceled order, got %+v, %v", page, err)
+		}
+		if _, err := service.OrderHistory(ctx, orderhistory.Query{Symbol: "DOGE-USD", Limit: 1}); err == nil {
+			t.Error("Expected an unlisted symbol to be rejected")
+		}
+	})
+
+	t.Run("purge_moves_stored_orders_to_the_alias", func(t *testing.T) {
+		// Given: A stored resting order and an update to it still queued
+		ctx := context.Background()
+		service := newTestExchangeService()
+		store := &memoryOrderStore{orders: make(map[string]models.Order)}
+		service.SetOrderStore(store)
+		order := OrderRequest{AccountID: "acct-erased", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000}
+		first, _ := service.PlaceOrder(ctx, order)
+		service.FlushOrders()
+		second, _ := service.PlaceOrder(ctx, order)
+
+		// When: The account is purged and the queue flushed
+		purge, err := service.PurgeAccount(ctx, "acct-erased")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		service.FlushOrders()
+
+		// Then: Both orders are stored canceled under the alias
+		for _, id := range []string{first.Order.ID, second.Order.ID} {
+			if stored := store.orders[id]; stored.AccountID != purge.Alias || stored.Status != models.OrderStatusCanceled {
+				t.Errorf("Expected %s canceled under the alias, got %+v", id, stored)
+			}
+		}
+	})
+}
+
+// memoryAccountStore keeps saved accounts by ID
+type memoryAccountStore struct {
+	saved map[string]accounts.Account
+}
+
+func (s *memoryAccountStore) Save(batch ...accounts.Account) error {
+	for _, account := range batch {
+		s.saved[account.ID] = account
+	}
+	return nil
+}
+
+func (s *memoryAccountStore) Delete(id string) error {
+	delete(s.saved, id)
+	return nil
+}
+
+func (s *memoryAccountStore) Load() ([]accounts.Account, error) {
+	loaded := make([]accounts.Account, 0, len(s.saved))
+	for _, account := range s.saved {
+		loaded = append(loaded, account)
+	}
+	return loaded, nil
+}
+
+func TestExchangeService_AccountLifecycle(t *testing.T) {
+	t.Run("closing_cancels_working_orders_and_survives_a_restart", func(t *testing.T) {
+		// Given: A persisted account with a resting order
+		ctx := context.Background()
+		store := &memoryAccountStore{saved: make(map[string]accounts.Account)}
+		service := newTestExchangeService()
+		service.SetAccountStore(store)
+		account, err := service.CreateAccount(ctx, NewAccountRequest{Tier: "vip"})
+		if err != nil {
+			t.Fatalf("Expected the account to be created, got %v", err)
+		}
+		order := OrderRequest{AccountID: account.ID, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000}
+		if _, err := service.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("Expected the open account to trade, got %v", err)
+		}
+
+		// When: The account is closed and the venue restarts from the store
+		closure, err := service.CloseAccount(ctx, account.ID)
+		restarted := newTestExchangeService()
+		restarted.SetAccountStore(store)
+		_, placeErr := restarted.PlaceOrder(ctx, order)
+
+		// Then: Its order was canceled and it stays closed after the restart
+		if err != nil || closure.OrdersCanceled != 1 || len(service.Engine().OpenOrders(account.ID, "")) != 0 {
+			t.Errorf("Expected one order canceled on close, got %+v, %v", closure, err)
+		}
+		if !errors.Is(placeErr, accounts.ErrClosed) || RejectionOf(placeErr).Reason != RejectInvalidAccount {
+			t.Errorf("Expected the closed account to be refused after a restart, got %v", placeErr)
+		}
+	})
+
+	t.Run("updating_and_closing_take_the_accounts_key_or_the_admin_token", func(t *testing.T) {
+		// Given: A venue with an admin token, and two accounts each holding a key
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", AdminToken: "ops"}, logger)
+		owner, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		other, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		ownerKey, _ := service.CreateAPIKey(ctx, owner.ID, NewAPIKeyRequest{})
+		otherKey, _ := service.CreateAPIKey(ctx, other.ID, NewAPIKeyRequest{})
+		vip := "vip"
+
+		// When: The account is updated and closed anonymously and with another
+		// account's key, then updated with its own key and closed by the operator
+		_, anonymousUpdate := service.UpdateAccount(keystats.WithAPIKey(ctx, keystats.Anonymous), owner.ID, AccountUpdate{Tier: &vip})
+		_, anonymousClose := service.CloseAccount(keystats.WithAPIKey(ctx, keystats.Anonymous), owner.ID)
+		_, foreignUpdate := service.UpdateAccount(keystats.WithAPIKey(ctx, otherKey.ID), owner.ID, AccountUpdate{Tier: &vip})
+		_, foreignClose := service.CloseAccount(keystats.WithAPIKey(ctx, otherKey.ID), owner.ID)
+		updated, ownUpdate := service.UpdateAccount(keystats.WithAPIKey(ctx, ownerKey.ID), owner.ID, AccountUpdate{Tier: &vip})
+		_, operatorClose := service.CloseAccount(AsOperator(keystats.WithAPIKey(ctx, keystats.Anonymous)), owner.ID)
+
+		// Then: Only the account's own key and the operator change it
+		if anonymousUpdate == nil || anonymousClose == nil ||
+			RejectionOf(anonymousUpdate).Reason != RejectUnauthenticated || RejectionOf(anonymousClose).Reason != RejectUnauthenticated {
+			t.Errorf("Expected an anonymous caller to be UNAUTHENTICATED, got %v and %v", anonymousUpdate, anonymousClose)
+		}
+		if foreignUpdate == nil || foreignClose == nil ||
+			RejectionOf(foreignUpdate).Reason != RejectPermissionDenied || RejectionOf(foreignClose).Reason != RejectPermissionDenied {
+			t.Errorf("Expected another account's key to be PERMISSION_DENIED, got %v and %v", foreignUpdate, foreignClose)
+		}
+		if ownUpdate != nil || updated.Tier != vip || operatorClose != nil {
+			t.Errorf("Expected the account's key to update and the operator to close, got %v and %v", ownUpdate, operatorClose)
+		}
+	})
+
+	t.Run("purging_drops_the_account_record", func(t *testing.T) {
+		ctx := context.Background()
+		store := &memoryAccountStore{saved: make(map[string]accounts.Account)}
+		service := newTestExchangeService()
+		service.SetAccountStore(store)
+		account, _ := service.CreateAccount(ctx, NewAccountRequest{Metadata: map[string]string{"owner": "jane"}})
+
+		if _, err := service.PurgeAccount(ctx, account.ID); err != nil {
+			t.Fatalf("Expected the purge to succeed, got %v", err)
+		}
+
+		if _, err := service.Account(ctx, account.ID); !errors.Is(err, accounts.ErrNotFound) || len(store.saved) != 0 {
+			t.Errorf("Expected the account and its metadata to be gone, got %v with %d saved", err, len(store.saved))
+		}
+	})
+}
+
+func TestExchangeService_APIKeys(t *testing.T) {
+	t.Run("keys_carry_their_own_permissions_until_revoked", func(t *testing.T) {
+		// Given: An account with a read-only key
+		ctx := context.Background()
+		service := newTestExchangeService()
+		account, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		issued, err := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{Label: "dashboard", Permissions: []accounts.Permission{accounts.PermissionRead}})
+		if err != nil || issued.Secret == "" {
+			t.Fatalf("Expected a key with its secret, got %+v, %v", issued, err)
+		}
+		client := keystats.WithAPIKey(ctx, issued.ID)
+
+		// When: It reads balances, then tries to trade
+		_, readErr := service.AccountBalances(client, account.ID)
+		_, tradeErr := service.PlaceOrder(client, OrderRequest{AccountID: account.ID, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000})
+
+		// Then: Reading is allowed and trading is denied and audited
+		if readErr != nil {
+			t.Errorf("Expected the read key to read balances, got %v", readErr)
+		}
+		if RejectionOf(tradeErr).Reason != RejectPermissionDenied {
+			t.Errorf("Expected PERMISSION_DENIED, got %v", tradeErr)
+		}
+		denials, _ := service.AccessDenials(ctx, issued.ID, 0)
+		if len(denials) != 1 || denials[0].Permission != accounts.PermissionTrade || denials[0].AccountID != account.ID {
+			t.Errorf("Expected the trade denial recorded, got %+v", denials)
+		}
+
+		// And: Once revoked, the key can no longer read or authenticate
+		if _, err := service.RevokeAPIKey(ctx, account.ID, issued.ID); err != nil {
+			t.Fatalf("Expected the key to be revoked, got %v", err)
+		}
+		if _, err := service.AccountBalances(client, account.ID); RejectionOf(err).Reason != RejectPermissionDenied {
+			t.Errorf("Expected a revoked key to be denied, got %v", err)
+		}
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		payload := accounts.SigningPayload(timestamp, "GET", "/api/v1/accounts/"+account.ID+"/balances", nil)
+		signed := SignedRequest{KeyID: issued.ID, Timestamp: timestamp, Signature: issued.Key.Sign(payload), Payload: payload}
+		if err := service.AuthenticateRequest(ctx, signed); RejectionOf(err).Reason != RejectUnauthenticated {
+			t.Errorf("Expected a revoked key to fail authentication, got %v", err)
+		}
+	})
+
+	t.Run("rejects_stale_and_forged_signatures", func(t *testing.T) {
+		ctx := context.Background()
+		service := newTestExchangeService()
+		account, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		issued, _ := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{})
+		sign := func(at time.Time) SignedRequest {
+			timestamp := strconv.FormatInt(at.UnixMilli(), 10)
+			payload := accounts.SigningPayload(timestamp, "POST", "/api/v1/orders", []byte(`{}`))
+			return SignedRequest{KeyID: issued.ID, Timestamp: timestamp, Signature: issued.Key.Sign(payload), Payload: payload}
+		}
+
+		fresh := sign(time.Now())
+		stale := sign(time.Now().Add(-time.Minute))
+		forged := sign(time.Now())
+		forged.Payload = accounts.SigningPayload(forged.Timestamp, "POST", "/api/v1/orders", []byte(`{"quantity":100}`))
+
+		if err := service.AuthenticateRequest(ctx, fresh); err != nil {
+			t.Errorf("Expected a fresh signature to pass, got %v", err)
+		}
+		if err := service.AuthenticateRequest(ctx, stale); RejectionOf(err).Reason != RejectUnauthenticated {
+			t.Errorf("Expected a stale timestamp to fail, got %v", err)
+		}
+		if err := service.AuthenticateRequest(ctx, forged); RejectionOf(err).Reason != RejectUnauthenticated {
+			t.Errorf("Expected a changed body to fail, got %v", err)
+		}
+		if denials, _ := service.AccessDenials(ctx, "", 0); len(denials) != 2 {
+			t.Errorf("Expected both failures audited, got %+v", denials)
+		}
+	})
+
+	t.Run("issuing_and_revoking_take_the_accounts_key_or_the_admin_token", func(t *testing.T) {
+		// Given: A venue with an admin token, and two accounts each holding a key
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", AdminToken: "ops"}, logger)
+		owner, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		other, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		operator := AsOperator(keystats.WithAPIKey(ctx, keystats.Anonymous))
+		ownerKey, err := service.CreateAPIKey(operator, owner.ID, NewAPIKeyRequest{})
+		if err != nil {
+			t.Fatalf("Expected the operator to issue a key, got %v", err)
+		}
+		otherKey, _ := service.CreateAPIKey(operator, other.ID, NewAPIKeyRequest{})
+
+		// When: Keys are issued and revoked anonymously, with another account's key and
+		// with the account's own key
+		_, anonymous := service.CreateAPIKey(keystats.WithAPIKey(ctx, keystats.Anonymous), owner.ID, NewAPIKeyRequest{Permissions: []accounts.Permission{accounts.PermissionWithdraw}})
+		_, foreign := service.CreateAPIKey(keystats.WithAPIKey(ctx, otherKey.ID), owner.ID, NewAPIKeyRequest{})
+		_, foreignRevoke := service.RevokeAPIKey(keystats.WithAPIKey(ctx, otherKey.ID), owner.ID, ownerKey.ID)
+		issued, own := service.CreateAPIKey(keystats.WithAPIKey(ctx, ownerKey.ID), owner.ID, NewAPIKeyRequest{})
+		_, ownRevoke := service.RevokeAPIKey(keystats.WithAPIKey(ctx, ownerKey.ID), owner.ID, issued.ID)
+
+		// Then: Only the account's own key manages its keys
+		if RejectionOf(anonymous).Reason != RejectUnauthenticated {
+			t.Errorf("Expected an anonymous caller to be UNAUTHENTICATED, got %v", anonymous)
+		}
+		if RejectionOf(foreign).Reason != RejectPermissionDenied || RejectionOf(foreignRevoke).Reason != RejectPermissionDenied {
+			t.Errorf("Expected another account's key to be PERMISSION_DENIED, got %v and %v", foreign, foreignRevoke)
+		}
+		if own != nil || ownRevoke != nil {
+			t.Errorf("Expected the account's key to issue and revoke, got %v and %v", own, ownRevoke)
+		}
+	})
+
+	t.Run("accounts_holding_keys_refuse_callers_without_one", func(t *testing.T) {
+		// Given: A venue with an admin token, one account holding a key and one without
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", AdminToken: "ops"}, logger)
+		keyed, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		open, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		key, _ := service.CreateAPIKey(ctx, keyed.ID, NewAPIKeyRequest{})
+
+		// When: Balances are read anonymously, with an unknown key, with the account's
+		// key and with the admin token
+		_, anonymous := service.AccountBalances(keystats.WithAPIKey(ctx, keystats.Anonymous), keyed.ID)
+		_, unknown := service.AccountBalances(keystats.WithAPIKey(ctx, "stranger"), keyed.ID)
+		_, own := service.AccountBalances(keystats.WithAPIKey(ctx, key.ID), keyed.ID)
+		_, operator := service.AccountBalances(AsOperator(keystats.WithAPIKey(ctx, keystats.Anonymous)), keyed.ID)
+		_, keyless := service.AccountBalances(keystats.WithAPIKey(ctx, keystats.Anonymous), open.ID)
+
+		// Then: The keyed account answers only its key and the operator
+		if anonymous == nil || RejectionOf(anonymous).Reason != RejectUnauthenticated {
+			t.Errorf("Expected an anonymous caller to be UNAUTHENTICATED, got %v", anonymous)
+		}
+		if unknown == nil || RejectionOf(unknown).Reason != RejectUnauthenticated {
+			t.Errorf("Expected an unknown key to be UNAUTHENTICATED, got %v", unknown)
+		}
+		if own != nil || operator != nil {
+			t.Errorf("Expected the account's key and the operator to read, got %v and %v", own, operator)
+		}
+
+		// And: An account without keys stays open
+		if keyless != nil {
+			t.Errorf("Expected an account without keys to stay open, got %v", keyless)
+		}
+	})
+
+	t.Run("account_queries_take_the_read_permission", func(t *testing.T) {
+		// Given: An account holding a trade-only key and a read-only key
+		ctx := context.Background()
+		service := newTestExchangeService()
+		account, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		trader, _ := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{Permissions: []accounts.Permission{accounts.PermissionTrade}})
+		reader, _ := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{Permissions: []accounts.Permission{accounts.PermissionRead}})
+		queries := map[string]func(context.Context) error{
+			"journal": func(ctx context.Context) error { _, err := service.BalanceJournal(ctx, account.ID, 10); return err },
+			"ledger":  func(ctx context.Context) error { _, err := service.AccountLedger(ctx, account.ID); return err },
+			"margin":  func(ctx context.Context) error { _, err := service.AccountMargin(ctx, account.ID); return err },
+			"funding": func(ctx context.Context) error { _, err := service.FundingPayments(ctx, account.ID, ""); return err },
+			"valuation": func(ctx context.Context) error {
+				_, err := service.AccountValuation(ctx, account.ID, "USD")
+				return err
+			},
+			"settlements": func(ctx context.Context) error { _, err := service.Settlements(ctx, "", account.ID, 10); return err },
+			"open_orders": func(ctx context.Context) error { _, err := service.OpenOrders(ctx, account.ID, ""); return err },
+			"trades":      func(ctx context.Context) error { _, err := service.Trades(ctx, account.ID, "", 10); return err },
+			"order_history": func(ctx context.Context) error {
+				_, err := service.OrderHistory(ctx, orderhistory.Query{AccountID: account.ID, Limit: 10})
+				return err
+			},
+			"trade_history": func(ctx context.Context) error {
+				_, err := service.TradeHistory(ctx, tradetape.Query{AccountID: account.ID, Limit: 10})
+				return err
+			},
+		}
+
+		for name, query := range queries {
+			// When: The account is queried with each key
+			withTrader := query(keystats.WithAPIKey(ctx, trader.ID))
+			withReader := query(keystats.WithAPIKey(ctx, reader.ID))
+
+			// Then: Only the key holding read is answered
+			if withTrader == nil || RejectionOf(withTrader).Reason != RejectPermissionDenied {
+				t.Errorf("Expected %s to deny the trade-only key, got %v", name, withTrader)
+			}
+			if withReader != nil {
+				t.Errorf("Expected %s to answer the read key, got %v", name, withReader)
+			}
+		}
+	})
+}
+
+// settingSource serves one setting that a test can change
+type settingSource struct {
+	mu    sync.Mutex
+	value json.RawMessage
+	reads int
+}
+
+func (s *settingSource) Setting(ctx context.Context, key string) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads++
+	return s.value, nil
+}
+
+func TestExchangeService_RateLimits(t *testing.T) {
+	t.Run("keys_issued_to_one_account_share_its_bucket", func(t *testing.T) {
+		// Given: Accounts may spend 3 per minute and one account holds two keys
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", RateLimitWeight: 3}, logger)
+		account, _ := service.CreateAccount(ctx, NewAccountRequest{})
+		first, _ := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{})
+		second, _ := service.CreateAPIKey(ctx, account.ID, NewAPIKeyRequest{})
+
+		// When: Each key sends requests in turn
+		for _, key := range []string{first.ID, second.ID, first.ID} {
+			if _, err := service.ChargeRequest(keystats.WithAPIKey(ctx, key), "", "POST /api/v1/orders"); err != nil {
+				t.Fatalf("Expected the request admitted, got %v", err)
+			}
+		}
+		decision, err := service.ChargeRequest(keystats.WithAPIKey(ctx, second.ID), "", "POST /api/v1/orders")
+
+		// Then: The fourth is refused across both keys, and anonymous callers are not keyed
+		if RejectionOf(err).Reason != RejectRateLimited || decision.RetryAfter <= 0 {
+			t.Errorf("Expected RATE_LIMITED with a retry, got %+v, %v", decision, err)
+		}
+		if _, err := service.ChargeRequest(ctx, "", "POST /api/v1/orders"); err != nil {
+			t.Errorf("Expected an anonymous caller without an address limit to pass, got %v", err)
+		}
+	})
+
+	t.Run("follows_limits_set_in_the_configuration_service", func(t *testing.T) {
+		// Given: The configuration service holds a key weight of 100
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		service := newTestExchangeService()
+		source := &settingSource{value: json.RawMessage(`{"key_weight": 100, "window": "10s"}`)}
+
+		// When: The venue follows it
+		go service.FollowRateLimits(ctx, source, "exchange.rate_limits", time.Millisecond)
+		deadline := time.Now().Add(time.Second)
+		for service.RateLimits().KeyWeight != 100 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		// Then: The limits apply, and a limit set by an operator stands until the source changes
+		if limits := service.RateLimits(); limits.KeyWeight != 100 || limits.Window != 10*time.Second {
+			t.Fatalf("Expected the configured limits applied, got %+v", limits)
+		}
+		service.SetRateLimits(ctx, ratelimit.Limits{KeyWeight: 7, Window: time.Minute})
+		source.mu.Lock()
+		reads := source.reads
+		source.mu.Unlock()
+		for time.Now().Before(deadline) {
+			source.mu.Lock()
+			polled := source.reads > reads+2
+			source.mu.Unlock()
+			if polled {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if limits := service.RateLimits(); limits.KeyWeight != 7 {
+			t.Errorf("Expected the operator's limit to stand, got %+v", limits)
+		}
+	})
+}
+
+func TestExchangeService_ProvisionAccounts(t *testing.T) {
+	t.Run("provisions_funded_accounts_with_their_own_keys", func(t *testing.T) {
+		// Given: A template funding USD with read and trade permissions
+		ctx := context.Background()
+		store := &memoryAccountStore{saved: make(map[string]accounts.Account)}
+		service := newTestExchangeService()
+		service.SetAccountStore(store)
+		template := accounts.Template{Tier: "load-test", Balances: map[string]float64{"USD": 100000}}
+
+		// When: Three hundred accounts are provisioned from it
+		provisioned, err := service.ProvisionAccounts(ctx, template, 300)
+
+		// Then: Each has a distinct ID and key, only key hashes are saved, and the
+		// opening balance shows in its ledger
+		if err != nil || len(provisioned) != 300 || len(store.saved) != 300 {
+			t.Fatalf("Expected 300 saved accounts, got %d (%d saved), %v", len(provisioned), len(store.saved), err)
+		}
+		keys := make(map[string]bool)
+		for _, account := range provisioned {
+			if keys[account.APIKey] || account.APIKeyHash == account.APIKey || !account.Can(accounts.PermissionTrade) {
+				t.Fatalf("Expected a fresh hashed key with trade permission, got %+v", account)
+			}
+			keys[account.APIKey] = true
+		}
+		ledger, _ := service.AccountLedger(ctx, provisioned[0].ID)
+		if len(ledger.Positions) != 1 || ledger.Positions[0].Asset != "USD" || ledger.Positions[0].Quantity != 100000 {
+			t.Errorf("Expected the opening USD balance, got %+v", ledger.Positions)
+		}
+		if _, err := service.ProvisionAccounts(ctx, template, 1001); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected an oversized batch to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("issued_keys_act_only_for_their_account_and_permissions", func(t *testing.T) {
+		// Given: A trading account and a read-only account, each with a key
+		ctx := context.Background()
+		service := newTestExchangeService()
+		traders, _ := service.ProvisionAccounts(ctx, accounts.Template{}, 1)
+		readers, _ := service.ProvisionAccounts(ctx, accounts.Template{Permissions: []accounts.Permission{accounts.PermissionRead}}, 1)
+		trader, reader := traders[0], readers[0]
+		order := OrderRequest{AccountID: trader.ID, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000}
+
+		// When: Each key places an order for the trading account, and an unissued key
+		// cancels the order the trader placed
+		placed, placeErr := service.PlaceOrder(keystats.WithAPIKey(ctx, trader.APIKey), order)
+		_, crossErr := service.PlaceOrder(keystats.WithAPIKey(ctx, reader.APIKey), order)
+		order.AccountID = reader.ID
+		_, readOnlyErr := service.PlaceOrder(keystats.WithAPIKey(ctx, reader.APIKey), order)
+		_, cancelCrossErr := service.CancelOrder(keystats.WithAPIKey(ctx, reader.APIKey), placed.Order.ID)
+		_, cancelErr := service.CancelOrder(keystats.WithAPIKey(ctx, "shared-desk-key"), placed.Order.ID)
+
+		// Then: Only the trader's own key and keys the venue did not issue may trade it
+		if placeErr != nil || cancelErr != nil {
+			t.Errorf("Expected the owner's key and an unissued key to be admitted, got %v, %v", placeErr, cancelErr)
+		}
+		for name, err := range map[string]error{"cross-account order": crossErr, "read-only order": readOnlyErr, "cross-account cancel": cancelCrossErr} {
+			if RejectionOf(err).Reason != RejectPermissionDenied {
+				t.Errorf("Expected %s to be PERMISSION_DENIED, got %v", name, err)
+			}
+		}
+	})
+}
+
+type memoryBalanceStore struct {
+	postings []ledger.Posting
+}
+
+func (s *memoryBalanceStore) Append(postings []ledger.Posting) error {
+	s.postings = append(s.postings, postings...)
+	return nil
+}
+
+func (s *memoryBalanceStore) Reassign(accountID, alias string) error {
+	for i := range s.postings {
+		if s.postings[i].AccountID == accountID {
+			s.postings[i].AccountID = alias
+		}
+	}
+	return nil
+}
+
+func TestExchangeService_BalanceJournal(t *testing.T) {
+	balanceOf := func(service *ExchangeService, accountID, asset string) ledger.Balance {
+		balances, _ := service.AccountBalances(context.Background(), accountID)
+		for _, balance := range balances.Balances {
+			if balance.Asset == asset {
+				return balance
+			}
+		}
+		return ledger.Balance{Asset: asset}
+	}
+
+	t.Run("orders_hold_fills_settle_and_cancels_release", func(t *testing.T) {
+		// Given: A buyer funded with USD and a seller funded with BTC
+		ctx := context.Background()
+		store := &memoryBalanceStore{}
+		service := newTestExchangeService()
+		service.SetBalanceStore(store)
+		buyers, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 100000}}, 1)
+		sellers, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"BTC": 2}}, 1)
+		buyer, seller := buyers[0].ID, sellers[0].ID
+		bid := OrderRequest{AccountID: buyer, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+
+		// When: The buyer bids, bids again beyond its balance, half its bid is hit
+		// and the rest canceled
+		placed, err := service.PlaceOrder(ctx, bid)
+		if err != nil {
+			t.Fatalf("Expected the first bid to be placed, got %v", err)
+		}
+		held := balanceOf(service, buyer, "USD")
+		_, overdrawn := service.PlaceOrder(ctx, bid)
+		if _, err := service.PlaceOrder(ctx, OrderRequest{AccountID: seller, Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeMarket, Quantity: 0.5}); err != nil {
+			t.Fatalf("Expected the sell to be placed, got %v", err)
+		}
+		partial := balanceOf(service, buyer, "USD")
+		if _, err := service.CancelOrder(ctx, placed.Order.ID); err != nil {
+			t.Fatalf("Expected the cancel to succeed, got %v", err)
+		}
+
+		// Then: The bid held its notional and the second was refused
+		if held.Available != 40000 || held.Held != 60000 {
+			t.Errorf("Expected 40000 available and 60000 held, got %+v", held)
+		}
+		if RejectionOf(overdrawn).Reason != RejectInsufficientBalance {
+			t.Errorf("Expected INSUFFICIENT_BALANCE, got %v", overdrawn)
+		}
+
+		// And: The fill was paid from the hold, the cancel released the rest, and
+		// the seller delivered BTC for USD
+		if partial.Held != 30000 {
+			t.Errorf("Expected 30000 still held after the fill, got %+v", partial)
+		}
+		if usd := balanceOf(service, buyer, "USD"); usd.Available != 70000 || usd.Held != 0 {
+			t.Errorf("Expected 70000 USD available once released, got %+v", usd)
+		}
+		if btc := balanceOf(service, buyer, "BTC"); btc.Available != 0.5 {
+			t.Errorf("Expected the buyer to receive 0.5 BTC, got %+v", btc)
+		}
+		if usd, btc := balanceOf(service, seller, "USD"), balanceOf(service, seller, "BTC"); usd.Available != 30000 || btc.Available != 1.5 {
+			t.Errorf("Expected the seller to hold 30000 USD and 1.5 BTC, got %+v, %+v", usd, btc)
+		}
+
+		// And: Every asset balances across accounts, and every posting reached the store
+		trial := service.TrialBalance(ctx)
+		if !trial.Balanced || len(trial.Assets) != 2 {
+			t.Errorf("Expected a balanced trial over BTC and USD, got %+v", trial)
+		}
+		if err := service.FlushBalanceJournal(); err != nil {
+			t.Fatalf("Expected the journal to flush, got %v", err)
+		}
+		journal, _ := service.BalanceJournal(ctx, "", 0)
+		if len(store.postings) != len(journal) {
+			t.Errorf("Expected %d stored postings, got %d", len(journal), len(store.postings))
+		}
+		kinds := make(map[ledger.Kind]bool)
+		postings, _ := service.BalanceJournal(ctx, buyer, 0)
+		for _, posting := range postings {
+			kinds[posting.Kind] = true
+		}
+		for _, kind := range []ledger.Kind{ledger.KindFund, ledger.KindHold, ledger.KindFill, ledger.KindRelease} {
+			if !kinds[kind] {
+				t.Errorf("Expected a %s posting for the buyer, got %+v", kind, postings)
+			}
+		}
+	})
+
+	t.Run("unfunded_accounts_trade_on_credit", func(t *testing.T) {
+		// Given: Accounts never opened through the account API
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+
+		// When: One buys from the other
+		_, err := service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeMarket, Quantity: 1})
+
+		// Then: The trade settles into negative balances that still balance
+		if err != nil {
+			t.Fatalf("Expected the order to be placed, got %v", err)
+		}
+		if usd := balanceOf(service, "taker", "USD"); usd.Available != -60000 {
+			t.Errorf("Expected the taker to owe 60000 USD, got %+v", usd)
+		}
+		if !service.TrialBalance(ctx).Balanced {
+			t.Errorf("Expected a balanced trial, got %+v", service.TrialBalance(ctx))
+		}
+	})
+
+	t.Run("rebuilds_balances_from_restored_orders", func(t *testing.T) {
+		// Given: A recording service where a funded buyer partly filled a bid
+		ctx := context.Background()
+		log := matching.NewMemoryEventLog()
+		accountStore := &memoryAccountStore{saved: make(map[string]accounts.Account)}
+		first := newTestExchangeService()
+		first.RestoreFromEventLog(nil, log)
+		first.SetAccountStore(accountStore)
+		buyers, _ := first.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 100000}}, 1)
+		buyer := buyers[0].ID
+		first.PlaceOrder(ctx, OrderRequest{AccountID: buyer, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		first.PlaceOrder(ctx, OrderRequest{AccountID: "seller", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.25, Price: 60000})
+
+		// When: A new service restores the orders, then the accounts
+		second := newTestExchangeService()
+		second.RestoreFromEventLog(log.Events(), matching.NewMemoryEventLog())
+		second.SetAccountStore(accountStore)
+
+		// Then: The buyer's balances match, its hold included, under a new journal
+		for _, asset := range []string{"USD", "BTC"} {
+			if before, after := balanceOf(first, buyer, asset), balanceOf(second, buyer, asset); before != after {
+				t.Errorf("Expected %s balance %+v after restore, got %+v", asset, before, after)
+			}
+		}
+		if before, after := first.TrialBalance(ctx), second.TrialBalance(ctx); !after.Balanced || after.JournalID == before.JournalID {
+			t.Errorf("Expected a balanced new journal, got %+v", after)
+		}
+	})
+}
+
+func TestExchangeService_DryRunOrder(t *testing.T) {
+	t.Run("applies_balance_checks_without_holding", func(t *testing.T) {
+		// Given: An account funded with 1000 USD
+		ctx := context.Background()
+		service := newTestExchangeService()
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}}, 1)
+		bid := OrderRequest{AccountID: funded[0].ID, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 0.01, Price: 60000}
+		postings, _ := service.BalanceJournal(ctx, "", 0)
+
+		// When: A bid it can afford and one it cannot are dry-run
+		affordable, err := service.DryRunOrder(ctx, bid)
+		bid.Quantity = 1
+		_, overdrawn := service.DryRunOrder(ctx, bid)
+
+		// Then: Only the affordable bid passes, and nothing was held or placed
+		if err != nil || !affordable.DryRun || affordable.Order.Status != models.OrderStatusNew {
+			t.Errorf("Expected the affordable bid to pass, got %+v, %v", affordable, err)
+		}
+		if RejectionOf(overdrawn).Reason != RejectInsufficientBalance {
+			t.Errorf("Expected INSUFFICIENT_BALANCE, got %v", overdrawn)
+		}
+		if after, _ := service.BalanceJournal(ctx, "", 0); len(after) != len(postings) {
+			t.Errorf("Expected no postings, got %d more", len(after)-len(postings))
+		}
+		if open, _ := service.OpenOrders(ctx, "", "BTC-USD"); len(open) != 0 {
+			t.Errorf("Expected no orders placed, got %+v", open)
+		}
+	})
+}
+
+func TestExchangeService_ExchangeStatistics(t *testing.T) {
+	t.Run("nets_positions_into_open_interest_and_sums_volume", func(t *testing.T) {
+		// Given: a sells 2 contracts to b, then b sells 1 on to c, and a spot trade prints
+		ctx := context.Background()
+		service := newTestExchangeService()
+		trade := func(seller, buyer, symbol string, quantity, price float64) {
+			order := OrderRequest{AccountID: seller, Symbol: symbol, Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: quantity, Price: price}
+			service.PlaceOrder(ctx, order)
+			order.AccountID, order.Side = buyer, models.SideBuy
+			service.PlaceOrder(ctx, order)
+		}
+		trade("a", "b", "BTC-USD-PERP", 2, 60000)
+		trade("b", "c", "BTC-USD-PERP", 1, 60000)
+		trade("a", "b", "BTC-USD", 0.5, 60000)
+
+		// When: Exchange statistics are computed
+		stats := service.ExchangeStatistics(ctx)
+
+		// Then: b and c are long 1 each, and USD volume covers both books
+		var perp ContractStatistics
+		for _, contract := range stats.Contracts {
+			if contract.Symbol == "BTC-USD-PERP" {
+				perp = contract
+			}
+		}
+		if perp.OpenInterest != 2 || perp.OpenInterestValue != 120000 || perp.Volume != 3 || perp.TradeCount != 2 {
+			t.Errorf("Expected 2 contracts open after 3 traded, got %+v", perp)
+		}
+		for _, volume := range stats.Volume {
+			if volume.QuoteAsset == "USD" && (volume.QuoteVolume != 210000 || volume.TradeCount != 3) {
+				t.Errorf("Expected 210000 USD over 3 trades, got %+v", volume)
+			}
+		}
+	})
+
+	t.Run("pushes_to_subscribers_at_each_interval_boundary", func(t *testing.T) {
+		// Given: An exchange stats subscriber on a venue pushing every minute
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", ExchangeStatsInterval: time.Minute}, logger)
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		if err := service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelExchangeStats}); err != nil {
+			t.Fatalf("Expected subscribe to succeed, got %v", err)
+		}
+		if snapshot := <-sub.Messages(); snapshot.Type != feed.MessageSnapshot {
+			t.Errorf("Expected a snapshot first, got %+v", snapshot)
+		}
+		start := time.Date(2024, 1, 2, 9, 0, 30, 0, time.UTC)
+
+		// When: The scheduler arms at 09:00:30 and runs again at 09:00:50 and 09:01:10
+		pushed := []bool{
+			service.publishExchangeStatistics(ctx, start),
+			service.publishExchangeStatistics(ctx, start.Add(20*time.Second)),
+			service.publishExchangeStatistics(ctx, start.Add(40*time.Second)),
+		}
+
+		// Then: Only the run past 09:01 pushes
+		if pushed[0] || pushed[1] || !pushed[2] {
+			t.Errorf("Expected a push only at the boundary, got %v", pushed)
+		}
+		if update := <-sub.Messages(); update.Type != feed.MessageUpdate {
+			t.Errorf("Expected an update, got %+v", update)
+		} else if _, ok := update.Data.(ExchangeStatistics); !ok {
+			t.Errorf("Expected exchange statistics, got %T", update.Data)
+		}
+	})
+}
+
+func TestExchangeService_Margin(t *testing.T) {
+	t.Run("refuses_derivative_orders_beyond_free_margin_at_the_account_leverage", func(t *testing.T) {
+		// Given: An account with 1000 USD limited to 5x
+		ctx := context.Background()
+		service := newTestExchangeService()
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}, Leverage: 5}, 1)
+		bid := OrderRequest{AccountID: funded[0].ID, Symbol: "BTC-USD-PERP", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 0.1, Price: 60000}
+
+		// When: It bids for 6000 USD of contracts, then twice for 3000
+		_, tooLarge := service.PlaceOrder(ctx, bid)
+		bid.Quantity = 0.05
+		_, first := service.PlaceOrder(ctx, bid)
+		_, second := service.PlaceOrder(ctx, bid)
+
+		// Then: 1200 USD of margin is refused, 600 is accepted, and a second 600 is not free
+		if RejectionOf(tooLarge).Reason != RejectInsufficientMargin {
+			t.Errorf("Expected INSUFFICIENT_MARGIN, got %v", tooLarge)
+		}
+		if first != nil {
+			t.Errorf("Expected the first 600 to be accepted, got %v", first)
+		}
+		if RejectionOf(second).Reason != RejectInsufficientMargin {
+			t.Errorf("Expected the working bid to commit its margin, got %v", second)
+		}
+	})
+
+	t.Run("liquidates_positions_when_equity_falls_below_maintenance", func(t *testing.T) {
+		// Given: A funded account long 0.3 contracts at 60000 on 1000 USD, and a bid at 57000
+		ctx := context.Background()
+		service := newTestExchangeService()
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}}, 1)
+		long := funded[0].ID
+		order := OrderRequest{AccountID: "mm", Symbol: "BTC-USD-PERP", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.3, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = long, models.SideBuy
+		if _, err := service.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("Expected the long to open, got %v", err)
+		}
+		if before, _ := service.AccountMargin(ctx, long); before.Pools[0].Breached || before.Pools[0].Positions[0].Quantity != 0.3 {
+			t.Fatalf("Expected a healthy long, got %+v", before)
+		}
+
+		// When: The market trades down to 57000 and scheduled work runs
+		order = OrderRequest{AccountID: "bidder", Symbol: "BTC-USD-PERP", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 57000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side, order.Quantity = "seller", models.SideSell, 0.001
+		service.PlaceOrder(ctx, order)
+		service.RunScheduledWork(ctx)
+
+		// Then: The long is sold into the bid, realizing its loss
+		liquidations := service.Liquidations(ctx, long)
+		if len(liquidations) != 1 || liquidations[0].Side != models.SideSell || liquidations[0].FilledQuantity != 0.3 {
+			t.Fatalf("Expected the long to be liquidated, got %+v", liquidations)
+		}
+		after, _ := service.AccountMargin(ctx, long)
+		if len(after.Pools) != 1 || len(after.Pools[0].Positions) != 0 || math.Abs(after.Pools[0].Equity-100) > 1e-6 {
+			t.Errorf("Expected a flat account with 100 USD equity, got %+v", after)
+		}
+		service.RunScheduledWork(ctx)
+		if again := service.Liquidations(ctx, ""); len(again) != 1 {
+			t.Errorf("Expected no further liquidations, got %+v", again)
+		}
+	})
+
+	t.Run("covers_a_shortfall_beyond_the_bankruptcy_price_from_the_insurance_fund", func(t *testing.T) {
+		// Given: An insurance fund of 10000 USD and a 1000 USD account long 0.3 at
+		// 60000, marked down to 55000 with only a bid at 55000 to sell into
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.SetInsuranceFund(map[string]float64{"USD": 10000})
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}}, 1)
+		long := funded[0].ID
+		order := OrderRequest{AccountID: "mm", Symbol: "BTC-USD-PERP", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.3, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = long, models.SideBuy
+		service.PlaceOrder(ctx, order)
+		order = OrderRequest{AccountID: "bidder", Symbol: "BTC-USD-PERP", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 55000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side, order.Quantity = "seller", models.SideSell, 0.001
+		service.PlaceOrder(ctx, order)
+
+		// When: Scheduled work liquidates the long
+		service.RunScheduledWork(ctx)
+
+		// Then: The 500 USD lost below the bankruptcy price is paid by the fund,
+		// leaving the account with no equity rather than a deficit
+		liquidations := service.Liquidations(ctx, long)
+		if len(liquidations) != 1 || math.Abs(liquidations[0].Shortfall-500) > 1e-6 || math.Abs(liquidations[0].InsuranceCovered-500) > 1e-6 {
+			t.Fatalf("Expected a 500 USD shortfall covered by the fund, got %+v", liquidations)
+		}
+		fund := service.InsuranceFund(ctx, "")
+		if len(fund.Balances) != 1 || math.Abs(fund.Balances[0].Balance-9500) > 1e-6 || fund.Balances[0].Exhausted {
+			t.Errorf("Expected 9500 USD left in the fund, got %+v", fund.Balances)
+		}
+		if after, _ := service.AccountMargin(ctx, long); math.Abs(after.Pools[0].Equity) > 1e-6 {
+			t.Errorf("Expected the account to end with zero equity, got %+v", after.Pools)
+		}
+	})
+
+	t.Run("deleverages_the_most_profitable_shorts_once_the_fund_is_exhausted", func(t *testing.T) {
+		// Given: No insurance fund, a 1000 USD account long 0.3 bought from shorts at
+		// 59000 and 60000, and a mark of 55000 with only a bid at 55000
+		ctx := context.Background()
+		service := newTestExchangeService()
+		funded, _ := service.ProvisionAccounts(ctx, accounts.Template{Balances: map[string]float64{"USD": 1000}}, 1)
+		long := funded[0].ID
+		order := OrderRequest{AccountID: "short-b", Symbol: "BTC-USD-PERP", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.1, Price: 59000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Quantity, order.Price = "short-a", 0.2, 60000
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side, order.Quantity = long, models.SideBuy, 0.3
+		service.PlaceOrder(ctx, order)
+		order = OrderRequest{AccountID: "bidder", Symbol: "BTC-USD-PERP", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 55000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side, order.Quantity = "seller", models.SideSell, 0.001
+		service.PlaceOrder(ctx, order)
+
+		// When: Scheduled work liquidates the long
+		service.RunScheduledWork(ctx)
+
+		// Then: Nothing sells below the bankruptcy price; both shorts are closed
+		// against the long there, the more profitable first
+		liquidations := service.Liquidations(ctx, long)
+		if len(liquidations) != 1 || liquidations[0].FilledQuantity != 0 || math.Abs(liquidations[0].Deleveraged-0.3) > 1e-12 {
+			t.Fatalf("Expected the whole long to be deleveraged, got %+v", liquidations)
+		}
+		deleveragings := service.InsuranceFund(ctx, long).Deleveragings
+		if len(deleveragings) != 2 || deleveragings[0].AccountID != "short-a" || math.Abs(deleveragings[0].Quantity-0.2) > 1e-9 ||
+			deleveragings[1].AccountID != "short-b" || deleveragings[1].Rank != 2 || deleveragings[0].Price < liquidations[0].BankruptcyPrice {
+			t.Errorf("Expected short-a then short-b deleveraged at the bankruptcy price, got %+v", deleveragings)
+		}
+		if positions, _ := service.Positions().Positions(ctx, "short-a", "BTC-USD-PERP"); len(positions) != 1 || math.Abs(positions[0].Quantity) > 1e-9 {
+			t.Errorf("Expected short-a flat, got %+v", positions)
+		}
+		if after, _ := service.AccountMargin(ctx, long); len(after.Pools[0].Positions) != 0 || after.Pools[0].Equity < -1e-6 {
+			t.Errorf("Expected a flat long without a deficit, got %+v", after.Pools)
+		}
+		if snapshot, _ := service.engine.Snapshot("BTC-USD-PERP", 0); len(snapshot.Bids) != 1 || snapshot.Bids[0].Quantity != 0.999 {
+			t.Errorf("Expected the bid at 55000 untouched, got %+v", snapshot.Bids)
+		}
+	})
+}
+
+func TestExchangeService_StorageLatency(t *testing.T) {
+	t.Run("degrades_storage_latency_while_a_component_p99_is_over_the_limit", func(t *testing.T) {
+		// Given: A venue judging calls of 1ms or more slow and a p99 above 1ms a breach
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{
+			ServiceName:         "exchange-simulator",
+			SlowQueryThreshold:  time.Millisecond,
+			PersistenceP99Limit: time.Millisecond,
+		}, logger)
+
+		// When: The trade store answers 20 writes slowly, then a full window quickly
+		for i := 0; i < 20; i++ {
+			service.persist(componentTrades, "append", nil, func() error {
+				time.Sleep(2 * time.Millisecond)
+				return nil
+			})
+		}
+		degraded := service.incidents.Status(componentLatency)
+		report := service.StorageLatency(context.Background())
+		for i := 0; i < 1000; i++ {
+			service.persist(componentTrades, "append", nil, func() error { return nil })
+		}
+
+		// Then: storage:latency degraded while the slow calls filled the window, and
+		// recovered once they aged out
+		if degraded != incidents.StatusDegraded {
+			t.Errorf("Expected storage:latency to be degraded, got %v", degraded)
+		}
+		if len(report.Components) != 1 || report.Components[0].Slow != 20 || !report.Components[0].Breached {
+			t.Errorf("Expected 20 slow trade store calls and a breach, got %+v", report)
+		}
+		if status := service.incidents.Status(componentLatency); status != incidents.StatusUp {
+			t.Errorf("Expected storage:latency to recover, got %v", status)
+		}
+	})
+}
+
+func TestExchangeService_Positions(t *testing.T) {
+	t.Run("books_every_fill_into_entry_price_and_realized_pnl", func(t *testing.T) {
+		// Given: A taker that buys 1 BTC at 60000 and 1 at 60100, then sells 1 at 60300
+		ctx := context.Background()
+		service := newTestExchangeService()
+		trade := func(side models.Side, price float64) {
+			maker := OrderRequest{AccountID: "mm", Symbol: "BTC-USD", Side: side.Opposite(), Type: models.OrderTypeLimit, Quantity: 1, Price: price}
+			service.PlaceOrder(ctx, maker)
+			taker := OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: side, Type: models.OrderTypeMarket, Quantity: 1}
+			if _, err := service.PlaceOrder(ctx, taker); err != nil {
+				t.Fatalf("Expected the taker to trade, got %v", err)
+			}
+		}
+		trade(models.SideBuy, 60000)
+		trade(models.SideBuy, 60100)
+		trade(models.SideSell, 60300)
+
+		// When: Its positions are read with the last trade as the mark
+		positions, err := service.Positions().Positions(ctx, "taker", "")
+
+		// Then: It is long 1 from 60050, has realized 250 and is up 250 at the mark
+		if err != nil || len(positions) != 1 {
+			t.Fatalf("Expected one position, got %+v, %v", positions, err)
+		}
+		position := positions[0]
+		if position.Quantity != 1 || position.EntryPrice != 60050 || position.RealizedPnL != 250 ||
+			position.MarkPrice != 60300 || position.UnrealizedPnL != 250 || position.Asset != "USD" {
+			t.Errorf("Expected long 1 from 60050 with 250 realized and 250 unrealized, got %+v", position)
+		}
+		if maker, _ := service.Positions().Positions(ctx, "mm", "BTC-USD"); len(maker) != 1 || maker[0].Quantity != -1 {
+			t.Errorf("Expected the maker short the other side, got %+v", maker)
+		}
+	})
+
+	t.Run("rebuilds_positions_from_filled_orders_on_restore", func(t *testing.T) {
+		// Given: A service recording a trade to an event log
+		ctx := context.Background()
+		log := matching.NewMemoryEventLog()
+		first := newTestExchangeService()
+		first.RestoreFromEventLog(nil, log)
+		first.PlaceOrder(ctx, OrderRequest{AccountID: "mm", Symbol: "ETH-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 2, Price: 3000})
+		first.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "ETH-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 2, Price: 3000})
+
+		// When: A new service is restored from the log
+		second := newTestExchangeService()
+		if err := second.RestoreFromEventLog(log.Events(), matching.NewMemoryEventLog()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both sides of the trade hold the same positions as before
+		before, _ := first.Positions().Positions(ctx, "", "ETH-USD")
+		after, _ := second.Positions().Positions(ctx, "", "ETH-USD")
+		if len(after) != 2 || after[1].Quantity != 2 || after[1].EntryPrice != 3000 {
+			t.Fatalf("Expected taker long 2 at 3000 after restore, got %+v", after)
+		}
+		for i := range after {
+			if after[i].AccountID != before[i].AccountID || after[i].Quantity != before[i].Quantity || after[i].EntryPrice != before[i].EntryPrice {
+				t.Errorf("Expected %+v, got %+v", before[i], after[i])
+			}
+		}
+	})
+}
+
+func TestExchangeService_RunBundle(t *testing.T) {
+	t.Run("manifest_records_the_seed_scenario_and_redacted_settings", func(t *testing.T) {
+		// Given: A service with a synthetic market drawing its own seed, a secret and a scenario file
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		cfg := &config.Config{ServiceName: "exchange-simulator", ScenarioRunID: "run-7", PostgresURL: "postgres://user:secret@db/exchange"}
+		service := NewExchangeService(cfg, logger)
+		if _, err := service.RunManifest(ctx); !errors.Is(err, runbundle.ErrNotStarted) {
+			t.Fatalf("Expected ErrNotStarted before the run starts, got %v", err)
+		}
+		market, _ := SyntheticMarketFromConfig(&config.Config{SyntheticSymbols: "BTC-USD", SyntheticModel: "gbm", SyntheticVolatility: 0.6, SyntheticLevels: 1, SyntheticSpreadBps: 10, SyntheticStepBps: 5, SyntheticLevelNotional: 10000})
+		if err := service.EnableSyntheticMarket(market); err != nil {
+			t.Fatalf("Expected the synthetic market to be enabled, got %v", err)
+		}
+		scenarioFile := []byte(`{"name":"smoke"}`)
+
+		// When: The run starts
+		service.StartRun("abc123", map[string][]byte{"/scenarios/smoke.json": scenarioFile})
+		manifest, err := service.RunManifest(ctx)
+
+		// Then: The drawn seed, the build, the scenario fingerprint and the instruments are
+		// recorded, and the Postgres credentials are not
+		if err != nil || manifest.RunID != "run-7" || manifest.GitSHA != "abc123" || manifest.Seed != market.Seed || manifest.Seed == 0 {
+			t.Fatalf("Unexpected manifest: %+v, %v", manifest, err)
+		}
+		if manifest.Config["PostgresURL"] != config.Redacted || manifest.Config["ScenarioRunID"] != "run-7" {
+			t.Errorf("Expected the secret redacted and the rest kept, got %v", manifest.Config)
+		}
+		if len(manifest.Files) != 1 || manifest.Files[0] != runbundle.NewFile("/scenarios/smoke.json", scenarioFile) {
+			t.Errorf("Expected the scenario fingerprinted, got %+v", manifest.Files)
+		}
+		if len(manifest.Instruments) != len(service.Instruments().List()) {
+			t.Errorf("Expected every instrument listed, got %d", len(manifest.Instruments))
+		}
+	})
+
+	t.Run("journal_replays_to_the_same_book", func(t *testing.T) {
+		// Given: A run recording to an in-memory journal, with an order resting and a trade
+		ctx := context.Background()
+		service := newTestExchangeService()
+		if err := service.RestoreFromEventLog(nil, matching.NewMemoryEventLog()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		service.StartRun("", nil)
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "mm", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 2, Price: 60000})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeMarket, Quantity: 1})
+
+		// When: The run is bundled, archived, read back and its journal replayed
+		bundle, err := service.RunBundle(ctx)
+		if err != nil {
+			t.Fatalf("Expected a bundle, got %v", err)
+		}
+		var archive bytes.Buffer
+		if err := runbundle.Write(&archive, bundle); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		read, err := runbundle.Read(&archive)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		replayed, err := matching.Replay(read.Journal)
+		if err != nil {
+			t.Fatalf("Expected the journal to replay, got %v", err)
+		}
+		defer replayed.Close()
+
+		// Then: The replayed engine holds the same open order and the metrics saw the trade
+		open := replayed.OpenOrders("mm", "BTC-USD")
+		if len(open) != 1 || open[0].FilledQuantity != 1 || replayed.Sequence() != service.Engine().Sequence() {
+			t.Errorf("Expected the maker's half-filled order at the same sequence, got %+v", open)
+		}
+		if read.Metrics.Volumes["BTC-USD"].Trades != 1 {
+			t.Errorf("Expected one trade in the metrics, got %+v", read.Metrics.Volumes)
+		}
+	})
+
+	t.Run("refuses_to_bundle_without_a_journal", func(t *testing.T) {
+		// Given: A started run whose engine records to no event log
+		service := newTestExchangeService()
+		service.StartRun("", nil)
+
+		// When: It is bundled
+		_, err := service.RunBundle(context.Background())
+
+		// Then: There is no journal to reproduce it from
+		if !errors.Is(err, runbundle.ErrNoJournal) {
+			t.Errorf("Expected ErrNoJournal, got %v", err)
+		}
+	})
+}
+
+// settlementGateway is a custodian that fails while down and otherwise accepts every
+// instruction but those for rejectAccount
+type settlementGateway struct {
+	down          bool
+	rejectAccount string
+	batches       []settlement.Batch
+}
+
+func (g *settlementGateway) Submit(ctx context.Context, batch settlement.Batch) ([]settlement.Ack, error) {
+	g.batches = append(g.batches, batch)
+	if g.down {
+		return nil, errors.New("connection refused")
+	}
+	acks := make([]settlement.Ack, 0, len(batch.Instructions))
+	for _, instruction := range batch.Instructions {
+		ack := settlement.Ack{InstructionID: instruction.ID, Accepted: instruction.AccountID != g.rejectAccount, Reference: "cust-" + instruction.ID}
+		if !ack.Accepted {
+			ack.Reference, ack.Reason = "", "unknown account"
+		}
+		acks = append(acks, ack)
+	}
+	return acks, nil
+}
+
+// settlementStore keeps every saved instruction state in memory
+type settlementStore struct {
+	saved []settlement.Instruction
+}
+
+func (s *settlementStore) Save(instructions ...settlement.Instruction) error {
+	s.saved = append(s.saved, instructions...)
+	return nil
+}
+
+func (s *settlementStore) Load() ([]settlement.Instruction, error) {
+	return s.saved, nil
+}
+
+func TestExchangeService_Settlement(t *testing.T) {
+	trade := func(t *testing.T, service *ExchangeService, buyer, seller string, quantity, price float64) {
+		ctx := context.Background()
+		service.PlaceOrder(ctx, OrderRequest{AccountID: seller, Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: quantity, Price: price})
+		if _, err := service.PlaceOrder(ctx, OrderRequest{AccountID: buyer, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: quantity, Price: price}); err != nil {
+			t.Fatalf("Expected the buyer to trade, got %v", err)
+		}
+	}
+
+	t.Run("submits_gross_instructions_per_fill", func(t *testing.T) {
+		// Given: Settlement without netting, and Alice buying 1 BTC from Bob at 60000
+		ctx := context.Background()
+		service := newTestExchangeService()
+		gateway := &settlementGateway{rejectAccount: "bob"}
+		service.EnableSettlement(gateway, nil)
+		trade(t, service, "alice", "bob", 1, 60000)
+
+		// When: The settlement cycle runs
+		run, err := service.SettleNow(ctx)
+
+		// Then: Each side's movement of each asset is one instruction against the other side
+		if err != nil || run.Generated != 4 || run.Submitted != 4 || run.Acknowledged != 2 || run.Rejected != 2 {
+			t.Fatalf("Expected four instructions, two acknowledged and two rejected, got %+v, %v", run, err)
+		}
+		report, _ := service.Settlements(ctx, "", "alice", 0)
+		if len(report.Instructions) != 2 || report.Counts[settlement.StatusAcknowledged] != 2 {
+			t.Fatalf("Expected alice's two instructions acknowledged, got %+v", report)
+		}
+		usd, btc := report.Instructions[0], report.Instructions[1]
+		if btc.Asset != "BTC" || btc.Amount != 1 || btc.Direction != settlement.DirectionReceive || btc.Counterparty != "bob" || btc.Reference == "" {
+			t.Errorf("Unexpected BTC instruction: %+v", btc)
+		}
+		if usd.Asset != "USD" || usd.Amount != 60000 || usd.Direction != settlement.DirectionDeliver {
+			t.Errorf("Unexpected USD instruction: %+v", usd)
+		}
+		if gateway.batches[0].Venue != "exchange-simulator" {
+			t.Errorf("Expected the venue named on the batch, got %q", gateway.batches[0].Venue)
+		}
+	})
+
+	t.Run("nets_fills_and_retries_while_the_custodian_is_down", func(t *testing.T) {
+		// Given: Netted settlement, a custodian that is down, and two opposite trades
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", SettlementNetting: time.Minute, SettlementRetryInterval: time.Nanosecond}, logger)
+		gateway := &settlementGateway{down: true}
+		store := &settlementStore{}
+		service.EnableSettlement(gateway, store)
+		trade(t, service, "alice", "bob", 1, 60000)
+		trade(t, service, "bob", "alice", 0.25, 62000)
+
+		// When: The first submission fails and the next succeeds
+		failed, _ := service.SettleNow(ctx)
+		degraded, _ := service.Incidents(ctx, incidents.Query{Component: ComponentCustodian})
+		gateway.down = false
+		time.Sleep(time.Millisecond)
+		retried, _ := service.SettleNow(ctx)
+
+		// Then: One instruction per account and asset is retried until acknowledged
+		if failed.Generated != 4 || failed.Failed != 4 || len(degraded.Unhealthy) != 1 {
+			t.Fatalf("Expected four netted instructions failed with the custodian degraded, got %+v and %+v", failed, degraded.Unhealthy)
+		}
+		if retried.Generated != 0 || retried.Acknowledged != 4 {
+			t.Fatalf("Expected the four retried and acknowledged, got %+v", retried)
+		}
+		report, _ := service.Settlements(ctx, settlement.StatusAcknowledged, "alice", 0)
+		if len(report.Instructions) != 2 || report.Instructions[1].Amount != 0.75 || report.Instructions[1].Counterparty != settlement.VenueAccount || report.Instructions[1].Attempts != 2 {
+			t.Errorf("Expected alice to receive a net 0.75 BTC on the second attempt, got %+v", report.Instructions)
+		}
+
+		// And: A service restored from the store resumes where this one left off
+		restored := newTestExchangeService()
+		restored.EnableSettlement(&settlementGateway{}, store)
+		if run, _ := restored.SettleNow(ctx); run.Submitted != 0 {
+			t.Errorf("Expected nothing left to submit after restore, got %+v", run)
+		}
+		if report, _ := restored.Settlements(ctx, "", "", 0); report.Counts[settlement.StatusAcknowledged] != 4 {
+			t.Errorf("Expected four acknowledged instructions restored, got %v", report.Counts)
+		}
+	})
+
+	t.Run("rejects_runs_while_disabled_and_unknown_statuses", func(t *testing.T) {
+		service := newTestExchangeService()
+
+		_, runErr := service.SettleNow(context.Background())
+		_, listErr := service.Settlements(context.Background(), "settled", "", 0)
+
+		if RejectionOf(runErr).Reason != RejectInvalidRequest || RejectionOf(listErr).Reason != RejectInvalidRequest {
+			t.Errorf("Expected INVALID_REQUEST for both, got %v and %v", runErr, listErr)
+		}
+	})
+}
+
+func TestExchangeService_SettlementCycles(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	newCycleService := func() (*ExchangeService, *stepClock) {
+		clock := &stepClock{now: start}
+		cfg := &config.Config{ServiceName: "exchange-simulator"}
+		cfg.SetClock(clock)
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		return NewExchangeService(cfg, logger), clock
+	}
+	trade := func(t *testing.T, service *ExchangeService, buyer, seller string, quantity, price float64) {
+		ctx := context.Background()
+		service.PlaceOrder(ctx, OrderRequest{AccountID: seller, Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: quantity, Price: price})
+		if _, err := service.PlaceOrder(ctx, OrderRequest{AccountID: buyer, Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: quantity, Price: price}); err != nil {
+			t.Fatalf("Expected the buyer to trade, got %v", err)
+		}
+	}
+
+	t.Run("nets_the_day_at_the_cutoff_and_settles_t_plus_one_through_the_custodian", func(t *testing.T) {
+		// Given: T+1 cycles with a 17:00 cutoff, a custodian, and two trades during the day
+		ctx := context.Background()
+		service, clock := newCycleService()
+		gateway := &settlementGateway{}
+		service.EnableSettlement(gateway, nil)
+		service.EnableSettlementCycles(settlement.Schedule{Cutoff: 17 * time.Hour, Lag: 1})
+		trade(t, service, "alice", "bob", 1, 60000)
+		trade(t, service, "bob", "alice", 0.5, 61000)
+
+		// When: The cutoff passes, a trade follows, and the next day's cutoff passes
+		clock.now = start.Add(8 * time.Hour)
+		service.RunScheduledWork(ctx)
+		trade(t, service, "alice", "bob", 1, 62000)
+		netted, _ := service.SettlementCycle(ctx, "eod-20240102")
+		early, _ := service.SettleNow(ctx)
+		clock.now = start.Add(32 * time.Hour)
+		service.RunScheduledWork(ctx)
+		run, _ := service.SettleNow(ctx)
+		service.RunScheduledWork(ctx)
+
+		// Then: The day nets without the later trade, and settles the next day once acknowledged
+		if netted.Status != settlement.CycleNetted || netted.Trades != 2 || netted.SettlementDate != "2024-01-03" {
+			t.Fatalf("Expected the first day's two trades netted to settle 2024-01-03, got %+v", netted)
+		}
+		if early.Submitted != 0 || run.Submitted != 4 || run.Acknowledged != 4 || len(gateway.batches) != 1 {
+			t.Errorf("Expected one batch of four net instructions on the settlement date, got %+v", run)
+		}
+		settled, _ := service.SettlementCycle(ctx, "eod-20240102")
+		statuses := make([]settlement.CycleStatus, 0)
+		for _, transition := range settled.Transitions {
+			statuses = append(statuses, transition.Status)
+		}
+		if len(statuses) != 3 || statuses[1] != settlement.CycleInstructed || statuses[2] != settlement.CycleSettled {
+			t.Errorf("Expected netted, instructed then settled, got %v", statuses)
+		}
+		cycles, _ := service.SettlementCycles(ctx, SettlementCycleQuery{AccountID: "alice"})
+		if len(cycles) != 2 || cycles[0].TradeDate != "2024-01-03" || cycles[0].Status != settlement.CycleNetted || len(cycles[1].Positions) != 2 {
+			t.Errorf("Expected alice's two days newest first, the second still netted, got %+v", cycles)
+		}
+		if btc := cycles[1].Positions[0]; btc.Asset != "BTC" || btc.Amount != 0.5 {
+			t.Errorf("Expected alice to receive a net 0.5 BTC, got %+v", btc)
+		}
+	})
+
+	t.Run("settles_on_the_books_without_a_custodian_and_fails_on_rejection", func(t *testing.T) {
+		// Given: T+0 cycles, one venue without a custodian and one whose custodian rejects bob
+		ctx := context.Background()
+		books, booksClock := newCycleService()
+		books.EnableSettlementCycles(settlement.Schedule{Cutoff: 17 * time.Hour})
+		trade(t, books, "alice", "bob", 1, 60000)
+		rejecting, rejectingClock := newCycleService()
+		rejecting.EnableSettlement(&settlementGateway{rejectAccount: "bob"}, nil)
+		rejecting.EnableSettlementCycles(settlement.Schedule{Cutoff: 17 * time.Hour})
+		trade(t, rejecting, "alice", "bob", 1, 60000)
+
+		// When: Both pass the cutoff
+		booksClock.now, rejectingClock.now = start.Add(8*time.Hour), start.Add(8*time.Hour)
+		books.RunScheduledWork(ctx)
+		rejecting.RunScheduledWork(ctx)
+		rejecting.SettleNow(ctx)
+		rejecting.RunScheduledWork(ctx)
+
+		// Then: The first settles at the cutoff and the second fails
+		if cycles, _ := books.SettlementCycles(ctx, SettlementCycleQuery{Status: settlement.CycleSettled}); len(cycles) != 1 {
+			t.Errorf("Expected the day settled on the books, got %+v", cycles)
+		}
+		failed, _ := rejecting.SettlementCycles(ctx, SettlementCycleQuery{Status: settlement.CycleFailed})
+		if len(failed) != 1 || failed[0].Transitions[2].Detail != "2 of 4 instructions rejected by the custodian" {
+			t.Errorf("Expected the day failed on bob's rejected instructions, got %+v", failed)
+		}
+		if _, err := rejecting.SettlementCycle(ctx, "eod-19990101"); !errors.Is(err, ErrSettlementCycleNotFound) {
+			t.Errorf("Expected ErrSettlementCycleNotFound, got %v", err)
+		}
+	})
+}
+
+// auditSink keeps every delivered audit event
+type auditSink struct {
+	events []audit.Event
+}
+
+func (s *auditSink) SubmitAuditEvents(ctx context.Context, events []audit.Event) error {
+	s.events = append(s.events, events...)
+	return nil
+}
+
+// drainAudit stops the audit pipeline, delivering everything buffered
+func drainAudit(service *ExchangeService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service.RunAuditTrail(ctx)
+}
+
+func TestExchangeService_AuditTrail(t *testing.T) {
+	t.Run("audits_each_order_change_and_fill_under_its_correlation_id", func(t *testing.T) {
+		// Given: An audited venue and a resting sell order
+		service := newTestExchangeService()
+		sink := &auditSink{}
+		service.EnableAuditTrail(sink)
+		sell, _ := service.PlaceOrder(audit.WithCorrelationID(context.Background(), "req-sell"), OrderRequest{AccountID: "bob", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 2, Price: 60000})
+
+		// When: A buy fills part of it, and it is amended and canceled
+		ctx := audit.WithCorrelationID(context.Background(), "req-buy")
+		buy, err := service.PlaceOrder(ctx, OrderRequest{AccountID: "alice", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		if err != nil {
+			t.Fatalf("Expected the buy to fill, got %v", err)
+		}
+		service.AmendOrder(context.Background(), sell.Order.ID, matching.AmendRequest{Price: 61000})
+		service.CancelOrder(context.Background(), sell.Order.ID)
+		drainAudit(service)
+
+		// Then: Every change is one event, the buy and its fill sharing the request's correlation ID
+		types := make([]string, 0, len(sink.events))
+		for _, event := range sink.events {
+			types = append(types, event.Type)
+		}
+		want := []string{audit.TypeOrderPlaced, audit.TypeOrderPlaced, audit.TypeTradeExecuted, audit.TypeOrderAmended, audit.TypeOrderCanceled}
+		if len(types) != len(want) {
+			t.Fatalf("Expected events %v, got %v", want, types)
+		}
+		for i := range want {
+			if types[i] != want[i] {
+				t.Fatalf("Expected events %v, got %v", want, types)
+			}
+		}
+		placed, fill, canceled := sink.events[1], sink.events[2], sink.events[4]
+		if placed.CorrelationID != "req-buy" || fill.CorrelationID != "req-buy" || sink.events[0].CorrelationID != "req-sell" {
+			t.Errorf("Expected the buy and its fill under req-buy, got %+v and %+v", placed, fill)
+		}
+		if fill.TradeID != buy.Trades[0].ID || fill.AccountID != "alice" || fill.OrderID != buy.Order.ID || fill.Attributes["sell_account_id"] != "bob" {
+			t.Errorf("Unexpected fill event: %+v", fill)
+		}
+		if canceled.CorrelationID == "" || canceled.CorrelationID == "req-buy" || canceled.Attributes["reason"] != cancelRequested {
+			t.Errorf("Expected the cancel under a fresh correlation ID, got %+v", canceled)
+		}
+		if placed.ID == "" || placed.ID == fill.ID || placed.OccurredAt.IsZero() {
+			t.Errorf("Expected distinct, timestamped event IDs, got %+v", placed)
+		}
+	})
+
+	t.Run("audits_deposits_and_counts_dropped_events", func(t *testing.T) {
+		// Given: An audit buffer holding one event, with nothing delivering it
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		service := NewExchangeService(&config.Config{ServiceName: "exchange-simulator", AuditBufferSize: 1}, logger)
+		sink := &auditSink{}
+		service.EnableAuditTrail(sink)
+
+		// When: Two funded accounts are opened
+		_, err := service.ProvisionAccounts(context.Background(), accounts.Template{Balances: map[string]float64{"USD": 10000}}, 2)
+		if err != nil {
+			t.Fatalf("Expected accounts, got %v", err)
+		}
+		stats, _ := service.AuditTrailStats(context.Background())
+		drainAudit(service)
+
+		// Then: The first deposit is audited and the second dropped rather than blocking
+		if stats.Published != 1 || stats.Dropped != 1 || stats.Pending != 1 {
+			t.Errorf("Expected one event buffered and one dropped, got %+v", stats)
+		}
+		if len(sink.events) != 1 || sink.events[0].Type != audit.TypeDeposit || sink.events[0].Attributes["amount"] != "10000" {
+			t.Errorf("Expected the first deposit delivered, got %+v", sink.events)
+		}
+		if _, err := newTestExchangeService().AuditTrailStats(context.Background()); err == nil {
+			t.Error("Expected stats to be refused while the audit trail is disabled")
+		}
+	})
+}
+
+// outboxTradeStore writes trades and outbox messages together, failing both while down
+type outboxTradeStore struct {
+	memoryTradeStore
+	messages *outbox.MemoryStore
+}
+
+func (s *outboxTradeStore) AppendWithMessages(trades []models.Trade, messages []outbox.Message) error {
+	if err := s.Append(trades); err != nil {
+		return err
+	}
+	return s.messages.Write(messages)
+}
+
+// outboxPublisher keeps every published message, failing while down
+type outboxPublisher struct {
+	published []outbox.Message
+	down      bool
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, messages []outbox.Message) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	p.published = append(p.published, messages...)
+	return nil
+}
+
+// drainOutbox stops the outbox, writing and dispatching everything queued
+func drainOutbox(service *ExchangeService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service.RunOutbox(ctx)
+}
+
+func TestExchangeService_Outbox(t *testing.T) {
+	t.Run("writes_trades_with_their_events_and_dispatches_once_written", func(t *testing.T) {
+		// Given: A venue whose trades and events share a store that is down
+		service := newTestExchangeService()
+		store := &outboxTradeStore{memoryTradeStore: memoryTradeStore{down: true}, messages: outbox.NewMemoryStore()}
+		service.SetTradeStore(store)
+		publisher := &outboxPublisher{}
+		service.EnableOutbox(store.messages, map[outbox.Destination]outbox.Publisher{outbox.DestinationAudit: publisher})
+		service.PlaceOrder(context.Background(), OrderRequest{AccountID: "bob", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		service.PlaceOrder(context.Background(), OrderRequest{AccountID: "alice", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+
+		// When: A flush fails, then the store recovers before the outbox stops
+		failed := service.FlushOutbox()
+		queued, _ := service.OutboxStats(context.Background())
+		store.down = false
+		drainOutbox(service)
+		stats, _ := service.OutboxStats(context.Background())
+
+		// Then: Nothing was kept by the failed flush; afterwards the trade and its three
+		// events were written together and each event published once
+		if failed == nil || queued.Queued != 3 || queued.Written != 0 {
+			t.Fatalf("Expected the failed flush to keep three messages queued, got %+v, %v", queued, failed)
+		}
+		if len(store.trades) != 1 || len(store.messages.Messages()) != 3 {
+			t.Fatalf("Expected one trade and three messages stored, got %d and %d", len(store.trades), len(store.messages.Messages()))
+		}
+		if len(publisher.published) != 3 || stats.Written != 3 || stats.Dispatched != 3 || stats.Queued != 0 {
+			t.Fatalf("Expected three messages dispatched, got %+v after %d published", stats, len(publisher.published))
+		}
+		var fill audit.Event
+		if err := json.Unmarshal(publisher.published[2].Payload, &fill); err != nil || fill.Type != audit.TypeTradeExecuted || fill.TradeID != store.trades[0].ID {
+			t.Errorf("Expected the fill event last, got %s, %v", publisher.published[2].Payload, err)
+		}
+		if publisher.published[2].DedupKey != fill.ID || publisher.published[2].Topic != audit.TypeTradeExecuted {
+			t.Errorf("Expected the message keyed by its event ID, got %+v", publisher.published[2])
+		}
+	})
+
+	t.Run("keeps_messages_a_destination_did_not_take", func(t *testing.T) {
+		// Given: An outbox of its own whose Redis destination is down
+		service := newTestExchangeService()
+		store := outbox.NewMemoryStore()
+		correlator, redis := &outboxPublisher{}, &outboxPublisher{down: true}
+		service.EnableOutbox(store, map[outbox.Destination]outbox.Publisher{outbox.DestinationAudit: correlator, outbox.DestinationRedis: redis})
+
+		// When: An account is funded and the outbox stops
+		if _, err := service.ProvisionAccounts(context.Background(), accounts.Template{Balances: map[string]float64{"USD": 10000}}, 1); err != nil {
+			t.Fatalf("Expected an account, got %v", err)
+		}
+		drainOutbox(service)
+		stats, _ := service.OutboxStats(context.Background())
+
+		// Then: The correlator took the deposit; Redis's copy waits for a retry and the
+		// failure shows on the audit sink
+		messages := store.Messages()
+		if len(correlator.published) != 1 || len(messages) != 2 || stats.Dispatched != 1 || stats.Failed != 1 {
+			t.Fatalf("Expected one message dispatched and one failed, got %+v", stats)
+		}
+		if messages[1].Destination != outbox.DestinationRedis || messages[1].DispatchedAt != nil || messages[1].Attempts != 1 || messages[1].DedupKey != messages[0].DedupKey {
+			t.Errorf("Expected Redis's copy kept for a retry, got %+v", messages[1])
+		}
+		report, _ := service.Incidents(context.Background(), incidents.Query{Component: componentAuditSink})
+		if len(report.Unhealthy) != 1 {
+			t.Errorf("Expected the audit sink degraded, got %+v", report)
+		}
+		if _, err := newTestExchangeService().OutboxStats(context.Background()); err == nil {
+			t.Error("Expected stats to be refused while the outbox is disabled")
+		}
+	})
+}
+
+func TestExchangeService_Chaos(t *testing.T) {
+	t.Run("rejects_orders_and_halts_symbols_until_cleared", func(t *testing.T) {
+		// Given: A venue rejecting every BTC-USD order and halting ETH-USD
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.EnableChaos(7)
+		reject, err := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindRejectOrders, Symbols: []string{"BTC-USD"}, Reason: "matching offline"})
+		if err != nil {
+			t.Fatalf("Expected the fault injected, got %v", err)
+		}
+		halt, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindHaltSymbol, Symbols: []string{"ETH-USD"}})
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+
+		// When: Orders arrive while both faults are in force, then again once cleared
+		_, rejected := service.PlaceOrder(ctx, order)
+		eth := OrderRequest{AccountID: "a", Symbol: "ETH-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 3000}
+		_, halted := service.PlaceOrder(ctx, eth)
+		faults, _ := service.ChaosFaults(ctx)
+		service.ClearFault(ctx, reject.ID)
+		service.ClearFault(ctx, halt.ID)
+		_, accepted := service.PlaceOrder(ctx, order)
+		_, resumed := service.PlaceOrder(ctx, eth)
+
+		// Then: The faults hit while listed and the venue trades normally afterwards
+		if rejection := RejectionOf(rejected); rejection.Reason != RejectEngineUnavailable || rejection.Message != "matching offline" {
+			t.Errorf("Expected the injected rejection, got %v", rejected)
+		}
+		if halted == nil || len(faults) != 2 || faults[0].Hits != 1 {
+			t.Errorf("Expected ETH-USD halted and both faults listed, got %v and %+v", halted, faults)
+		}
+		if accepted != nil || resumed != nil || len(service.ActiveHalts(ctx)) != 0 {
+			t.Errorf("Expected orders accepted once cleared, got %v and %v", accepted, resumed)
+		}
+		if _, err := service.ClearFault(ctx, halt.ID); !errors.Is(err, chaos.ErrNotFound) {
+			t.Errorf("Expected a cleared fault to be gone, got %v", err)
+		}
+	})
+
+	t.Run("drops_market_data_and_corrupts_balances", func(t *testing.T) {
+		// Given: A trades subscriber on a venue losing every BTC-USD update
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.EnableChaos(7)
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelTrades, Key: "BTC-USD"})
+		drop, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindDropMarketData, Symbols: []string{"BTC-USD"}})
+
+		// When: Two accounts trade and a balance is corrupted
+		order := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "taker", models.SideBuy
+		service.PlaceOrder(ctx, order)
+		corrupt, err := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindCorruptBalances, AccountID: "taker", Asset: "USD", Amount: 500})
+
+		// Then: The print never reached the subscriber, and the trial balance is off by 500
+		select {
+		case msg := <-sub.Messages():
+			t.Errorf("Expected the trade dropped, got %+v", msg.Data)
+		default:
+		}
+		faults, _ := service.ChaosFaults(ctx)
+		if len(faults) != 1 || faults[0].ID != drop.ID || faults[0].Hits == 0 {
+			t.Errorf("Expected the drop fault counting hits, got %+v", faults)
+		}
+		if err != nil || corrupt.Hits != 1 {
+			t.Fatalf("Expected the corruption applied, got %+v, %v", corrupt, err)
+		}
+		if trial := service.TrialBalance(ctx); trial.Balanced {
+			t.Errorf("Expected an unbalanced 500 USD credit, got %+v", trial)
+		}
+		if _, err := newTestExchangeService().InjectFault(ctx, chaos.Spec{Kind: chaos.KindHaltSymbol, Symbols: []string{"BTC-USD"}}); err == nil {
+			t.Error("Expected faults to be refused while chaos injection is disabled")
+		}
+	})
+
+	t.Run("overfills_and_misreports_balances_flagging_each_lie", func(t *testing.T) {
+		// Given: An audited venue over-reporting fills by half and the taker's USD by 1000
+		ctx := context.Background()
+		service := newTestExchangeService()
+		sink := &auditSink{}
+		service.EnableAuditTrail(sink)
+		service.EnableChaos(7)
+		overfill, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindOverfill, Symbols: []string{"BTC-USD"}, OverfillPct: 50})
+		misreport, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindMisreportBalances, AccountID: "taker", Asset: "USD", Amount: 1000})
+
+		// When: The taker buys one BTC and asks for its balances
+		order := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, order)
+		order.AccountID, order.Side = "taker", models.SideBuy
+		report, err := service.PlaceOrder(ctx, order)
+		told, _ := service.AccountBalances(ctx, "taker")
+		service.ClearFault(ctx, overfill.ID)
+		service.ClearFault(ctx, misreport.ID)
+		truth, _ := service.AccountBalances(ctx, "taker")
+		drainAudit(service)
+
+		// Then: The taker was told of 1.5 BTC and 1000 USD more, and each lie is on the audit trail
+		if err != nil || report.Order.FilledQuantity != 1.5 || report.Trades[0].Quantity != 1 {
+			t.Fatalf("Expected 1.5 reported filled of 1 traded, got %+v, %v", report, err)
+		}
+		usd := func(balances AccountBalances) float64 {
+			for _, balance := range balances.Balances {
+				if balance.Asset == "USD" {
+					return balance.Available
+				}
+			}
+			return 0
+		}
+		if usd(told)-usd(truth) != 1000 {
+			t.Errorf("Expected USD misreported by 1000, got %v against %v", usd(told), usd(truth))
+		}
+		counts := make(map[string]int)
+		flagged := false
+		for _, event := range sink.events {
+			counts[event.Type]++
+			if event.Type == audit.TypeMisbehaved && event.Attributes["kind"] == string(chaos.KindOverfill) && event.OrderID == report.Order.ID {
+				flagged = true
+			}
+		}
+		if !flagged || counts[audit.TypeMisbehaviorStarted] != 2 || counts[audit.TypeMisbehaviorEnded] != 2 {
+			t.Errorf("Expected both misbehaviours flagged from start to end and the buy's overfill flagged, got %v", counts)
+		}
+		if report, _ := service.Incidents(ctx, incidents.Query{Component: ComponentMisbehavior}); len(report.Unhealthy) != 0 || len(report.Incidents) < 2 {
+			t.Errorf("Expected the misbehaviour degraded then up again, got %+v", report)
+		}
+	})
+
+	t.Run("duplicates_updates_and_stalls_cancel_acks_until_cleared", func(t *testing.T) {
+		// Given: A venue sending order updates twice, watched on the maker's order stream
+		ctx := context.Background()
+		service := newTestExchangeService()
+		service.EnableChaos(7)
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelOrders, Key: "maker"})
+		duplicate, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindDuplicateReports})
+		order := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		placed, _ := service.PlaceOrder(ctx, order)
+		service.ClearFault(ctx, duplicate.ID)
+		updates := 0
+		for len(sub.Messages()) > 0 {
+			<-sub.Messages()
+			updates++
+		}
+
+		// When: The order is canceled while cancel acknowledgements stall
+		stall, _ := service.InjectFault(ctx, chaos.Spec{Kind: chaos.KindStallCancels})
+		acked := make(chan error, 1)
+		go func() {
+			_, err := service.CancelOrder(ctx, placed.Order.ID)
+			acked <- err
+		}()
+		var early bool
+		select {
+		case <-acked:
+			early = true
+		case <-time.After(20 * time.Millisecond):
+		}
+		service.ClearFault(ctx, stall.ID)
+
+		// Then: The new order was reported twice, and the cancel applied but acknowledged only once cleared
+		if updates != 2 {
+			t.Errorf("Expected the order update sent twice, got %d", updates)
+		}
+		if early {
+			t.Fatal("Expected the cancel acknowledgement held back")
+		}
+		if err := <-acked; err != nil {
+			t.Errorf("Expected the cancel acknowledged once cleared, got %v", err)
+		}
+		if canceled, _ := service.GetOrder(ctx, placed.Order.ID); canceled.Status != models.OrderStatusCanceled {
+			t.Errorf("Expected the order canceled, got %+v", canceled)
+		}
+	})
+}
+
+func TestExchangeService_Degradation(t *testing.T) {
+	t.Run("refuses_what_the_mode_does_not_serve", func(t *testing.T) {
+		// Given: A resting order on a venue switched to cancel-only
+		ctx := context.Background()
+		service := newTestExchangeService()
+		order := OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		resting, err := service.PlaceOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("Expected the order accepted, got %v", err)
+		}
+		if _, err := service.SetDegradationMode(ctx, chaos.ModeCancelOnly); err != nil {
+			t.Fatalf("Expected the mode switched, got %v", err)
+		}
+
+		// When: A new order and a cancel arrive, then the venue goes read-only
+		_, refused := service.PlaceOrder(ctx, order)
+		_, cancelErr := service.CancelOrder(ctx, resting.Order.ID)
+		service.SetDegradationMode(ctx, chaos.ModeReadOnly)
+		_, readOnly := service.PlaceOrder(ctx, order)
+		status := service.DegradationMode(ctx)
+
+		// Then: Only the cancel got through, and the mode shows on the incident timeline
+		if RejectionOf(refused).Reason != RejectEngineUnavailable || cancelErr != nil {
+			t.Errorf("Expected the order refused and the cancel accepted, got %v and %v", refused, cancelErr)
+		}
+		if RejectionOf(readOnly).Reason != RejectEngineUnavailable || status.Mode != chaos.ModeReadOnly || status.Refused != 1 {
+			t.Errorf("Expected one refusal while read-only, got %v with %+v", readOnly, status)
+		}
+		if report, _ := service.Incidents(ctx, incidents.Query{Component: ComponentVenueMode}); len(report.Unhealthy) != 1 {
+			t.Errorf("Expected the venue mode degraded, got %+v", report)
+		}
+		if _, err := service.SetDegradationMode(ctx, chaos.Mode("maintenance")); err == nil {
+			t.Error("Expected an unknown mode refused")
+		}
+	})
+
+	t.Run("serves_frozen_market_data_while_stale", func(t *testing.T) {
+		// Given: A bid on the book and a trades subscriber when market data goes stale
+		ctx := context.Background()
+		service := newTestExchangeService()
+		bid := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, bid)
+		sub := service.Feed().Subscribe()
+		defer sub.Close()
+		service.SubscribeFeed(ctx, sub, feed.Topic{Channel: feed.ChannelTrades, Key: "BTC-USD"})
+		service.SetDegradationMode(ctx, chaos.ModeStaleMarketData)
+
+		// When: Trading goes on underneath
+		ask := OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		_, err := service.PlaceOrder(ctx, ask)
+		stale, _ := service.OrderBook(ctx, "BTC-USD", 10)
+		service.SetDegradationMode(ctx, chaos.ModeNormal)
+		live, _ := service.OrderBook(ctx, "BTC-USD", 10)
+
+		// Then: The fill went unpublished and the book stayed as it was until the mode ended
+		if err != nil {
+			t.Fatalf("Expected trading to go on, got %v", err)
+		}
+		select {
+		case msg := <-sub.Messages():
+			t.Errorf("Expected the trade unpublished, got %+v", msg.Data)
+		default:
+		}
+		if len(stale.Bids) != 1 || len(live.Bids) != 0 {
+			t.Errorf("Expected the frozen bid until the mode ended, got %+v then %+v", stale.Bids, live.Bids)
+		}
+	})
+}
+
+func TestExchangeService_Replay(t *testing.T) {
+	t.Run("resubmits_a_recording_following_live_order_ids", func(t *testing.T) {
+		// Given: A recording of a cross, a resting bid canceled later and a cancel of an
+		// order placed before the capture began
+		ctx := context.Background()
+		service := newTestExchangeService()
+		start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		order := func(id, account string, side models.Side, price float64) models.Order {
+			return models.Order{ID: id, AccountID: account, Symbol: "BTC-USD", Side: side, Type: models.OrderTypeLimit, TimeInForce: models.TimeInForceGTC, Quantity: 1, Price: price}
+		}
+		recording, _ := replay.NewRecording([]replay.Entry{
+			{Time: start, Kind: replay.KindPlace, OrderID: "BTC-USD-100", Order: order("BTC-USD-100", "maker", models.SideSell, 60000)},
+			{Time: start.Add(time.Second), Kind: replay.KindPlace, OrderID: "BTC-USD-101", Order: order("BTC-USD-101", "taker", models.SideBuy, 60000)},
+			{Time: start.Add(2 * time.Second), Kind: replay.KindPlace, OrderID: "BTC-USD-102", Order: order("BTC-USD-102", "maker", models.SideBuy, 59000)},
+			{Time: start.Add(3 * time.Second), Kind: replay.KindCancel, OrderID: "BTC-USD-102"},
+			{Time: start.Add(4 * time.Second), Kind: replay.KindCancel, OrderID: "BTC-USD-99"},
+		})
+
+		// When: It is replayed as fast as the venue takes it
+		if _, err := service.StartReplay(ctx, recording, "capture.jsonl", 0); err != nil {
+			t.Fatalf("Expected the replay started, got %v", err)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		progress, _ := service.ReplayProgress(ctx)
+		for progress.State == replay.StateRunning && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+			progress, _ = service.ReplayProgress(ctx)
+		}
+
+		// Then: The cross traded, the bid was canceled under its live ID, and the
+		// unknown order was skipped
+		if progress.State != replay.StateCompleted || progress.Placed != 3 || progress.Trades != 1 || progress.Canceled != 1 || progress.Skipped != 1 {
+			t.Fatalf("Expected the recording replayed, got %+v", progress)
+		}
+		if open, _ := service.OpenOrders(ctx, "maker", "BTC-USD"); len(open) != 0 {
+			t.Errorf("Expected the maker's bid canceled, got %+v", open)
+		}
+		if _, err := service.StopReplay(ctx); err == nil {
+			t.Error("Expected a finished replay not to stop")
+		}
+		if _, err := service.StartReplay(ctx, recording, "capture.jsonl", -1); err == nil {
+			t.Error("Expected a negative speed refused")
+		}
+	})
+}
+
+type progressReporter struct {
+	reports []scenario.Progress
+}
+
+func (r *progressReporter) ReportProgress(ctx context.Context, progress scenario.Progress) error {
+	r.reports = append(r.reports, progress)
+	return nil
+}
+
+func TestExchangeService_ScenarioScript(t *testing.T) {
+	t.Run("plays_steps_as_the_clock_reaches_them", func(t *testing.T) {
+		// Given: A venue with a market maker and chaos injection playing a crash script
+		ctx := context.Background()
+		now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		service := newTestExchangeService()
+		service.now = func() time.Time { return now }
+		service.EnableChaos(1)
+		quotes, _ := ParseMarketMakerQuotes("BTC-USD=10:0.5")
+		service.EnableMarketMaker(MarketMakerConfig{Account: "mm", Quotes: quotes})
+		reporter := &progressReporter{}
+		service.SetScenarioReporter(reporter)
+		script, err := scenario.ParseScript([]byte(`
+name: crash
+steps:
+  - {action: price_move, symbol: BTC-USD, price: 60000}
+  - {at: 1m, action: price_move, symbol: BTC-USD, move_pct: -10}
+  - {at: 1m, action: liquidity, symbol: BTC-USD, scale: 0.2, spread_bps: 50}
+  - {at: 1m, action: halt, symbol: BTC-USD}
+  - {at: 2m, action: resume, symbol: BTC-USD}
+  - {at: 2m, action: outage, fault: {kind: reject_orders, symbols: [ETH-USD]}}
+`))
+		if err != nil {
+			t.Fatalf("Expected the script to parse, got %v", err)
+		}
+
+		// When: It starts, then the scheduled work runs at one and two minutes in
+		started, err := service.StartScript(ctx, script)
+		_, busy := service.StartScript(ctx, script)
+		now = now.Add(time.Minute)
+		service.RunScheduledWork(ctx)
+		halted := len(service.ActiveHalts(ctx))
+		service.RefreshMarketMaker(ctx)
+		maker := service.MarketMaker(ctx)
+		now = now.Add(time.Minute)
+		service.RunScheduledWork(ctx)
+		progress, _ := service.ScriptProgress(ctx)
+
+		// Then: Each step ran when due, and the orchestrator heard after each batch
+		if err != nil || started.Executed != 1 || started.State != scenario.ScriptRunning || busy == nil {
+			t.Fatalf("Expected the first step played on start and a second start refused, got %+v, %v, %v", started, err, busy)
+		}
+		if prices := service.ReferencePrices(ctx); len(prices) != 1 || math.Abs(prices[0].Price-54000) > 1e-6 {
+			t.Errorf("Expected the mark moved 10%% down to 54000, got %+v", prices)
+		}
+		if halted != 1 || len(maker) != 1 || maker[0].Ladder.LevelQuantity != 0.1 || maker[0].Ladder.SpreadBps != 50 {
+			t.Errorf("Expected BTC-USD halted with thinner, wider quotes, got %d halts and %+v", halted, maker)
+		}
+		if len(service.ActiveHalts(ctx)) != 0 {
+			t.Error("Expected BTC-USD resumed")
+		}
+		if _, err := service.PlaceOrder(ctx, OrderRequest{AccountID: "a", Symbol: "ETH-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 3000}); RejectionOf(err).Reason != RejectEngineUnavailable {
+			t.Errorf("Expected the outage to reject ETH-USD orders, got %v", err)
+		}
+		if progress.State != scenario.ScriptCompleted || progress.Executed != 6 || progress.Failed != 0 || len(reporter.reports) != 3 {
+			t.Errorf("Expected the script completed in three reports, got %+v after %d", progress, len(reporter.reports))
+		}
+		if reporter.reports[2].State != scenario.ScriptCompleted {
+			t.Errorf("Expected the last report to mark completion, got %+v", reporter.reports[2])
+		}
+	})
+
+	t.Run("refuses_steps_the_venue_cannot_play", func(t *testing.T) {
+		service := newTestExchangeService()
+		outage, _ := scenario.ParseScript([]byte(`{"steps": [{"action": "outage", "fault": {"kind": "drop_market_data"}}]}`))
+		unlisted, _ := scenario.ParseScript([]byte(`{"steps": [{"action": "halt", "symbol": "DOGE-USD"}]}`))
+
+		if _, err := service.StartScript(context.Background(), outage); err == nil {
+			t.Error("Expected an outage refused while chaos injection is disabled")
+		}
+		if _, err := service.StartScript(context.Background(), unlisted); RejectionOf(err).Reason != RejectUnknownInstrument {
+			t.Errorf("Expected an unlisted symbol refused, got %v", err)
+		}
+		if _, err := service.ScriptProgress(context.Background()); !errors.Is(err, scenario.ErrNoScript) {
+			t.Errorf("Expected no script, got %v", err)
+		}
+	})
+}
+
+// settingWatcher hands each subscriber's apply to the test, which plays the changes
+type settingWatcher struct {
+	subscribers map[string]func(ctx context.Context, value json.RawMessage) error
+}
+
+func (w *settingWatcher) Subscribe(key string, apply func(ctx context.Context, value json.RawMessage) error) func() {
+	w.subscribers[key] = apply
+	return func() { delete(w.subscribers, key) }
+}
+
+func TestExchangeService_ReloadSettings(t *testing.T) {
+	t.Run("applies_rate_limits_fees_and_chaos_as_they_change", func(t *testing.T) {
+		// Given: A venue reloading all three settings
+		ctx := context.Background()
+		service := newTestExchangeService()
+		watcher := &settingWatcher{subscribers: make(map[string]func(context.Context, json.RawMessage) error)}
+		unsubscribe := service.ReloadSettings(watcher, HotSettings{RateLimits: "limits", FeeSchedule: "fees", Chaos: "chaos"})
+
+		// When: Each changes at the configuration service
+		errs := []error{
+			watcher.subscribers["limits"](ctx, json.RawMessage(`{"key_weight": 50, "window": "1m"}`)),
+			watcher.subscribers["fees"](ctx, json.RawMessage(`{"BTC-USD": {"maker_fee_rate": -0.0001, "taker_fee_rate": 0.0004}, "*": {"maker_fee_rate": 0, "taker_fee_rate": 0.001}}`)),
+			watcher.subscribers["chaos"](ctx, json.RawMessage(`{"degradation_mode": "cancel_only"}`)),
+		}
+
+		// Then: The limits, the named and the other instruments' fees, and the mode apply
+		if err := errors.Join(errs...); err != nil {
+			t.Fatalf("Expected the changes applied, got %v", err)
+		}
+		if limits := service.RateLimits(); limits.KeyWeight != 50 {
+			t.Errorf("Expected a key weight of 50, got %+v", limits)
+		}
+		btc, _ := service.instruments.Get("BTC-USD")
+		eth, _ := service.instruments.Get("ETH-USD")
+		if btc.MakerFeeRate != -0.0001 || btc.TakerFeeRate != 0.0004 || eth.TakerFeeRate != 0.001 {
+			t.Errorf("Expected the fee schedule applied, got %+v and %+v", btc, eth)
+		}
+		if mode := service.DegradationMode(ctx).Mode; mode != chaos.ModeCancelOnly {
+			t.Errorf("Expected cancel_only, got %s", mode)
+		}
+		unsubscribe()
+		if len(watcher.subscribers) != 0 {
+			t.Errorf("Expected every key unsubscribed, got %v", watcher.subscribers)
+		}
+	})
+
+	t.Run("refuses_a_fee_schedule_naming_an_unknown_instrument", func(t *testing.T) {
+		// Given: A venue
+		service := newTestExchangeService()
+		before, _ := service.instruments.Get("BTC-USD")
+
+		// When: A schedule names BTC-USD and an instrument the venue does not list
+		err := service.SetFeeSchedule(context.Background(), FeeSchedule{
+			"BTC-USD":  {MakerFeeRate: 0.01, TakerFeeRate: 0.01},
+			"DOGE-EUR": {MakerFeeRate: 0.01, TakerFeeRate: 0.01},
+		})
+
+		// Then: It is refused and no rate changes
+		after, _ := service.instruments.Get("BTC-USD")
+		if RejectionOf(err).Reason != RejectInvalidRequest || after.TakerFeeRate != before.TakerFeeRate {
+			t.Errorf("Expected INVALID_REQUEST and no change, got %v and %+v", err, after)
+		}
+	})
+}
+
+func TestExchangeService_MarketMetrics(t *testing.T) {
+	t.Run("reports_order_flow_trades_and_the_book", func(t *testing.T) {
+		// Given: A venue reporting to a Prometheus metrics adapter
+		ctx := context.Background()
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		cfg := &config.Config{ServiceName: "exchange-simulator"}
+		metricsPort := observability.NewPrometheusMetricsAdapter(nil)
+		cfg.SetMetricsPort(metricsPort)
+		service := NewExchangeService(cfg, logger)
+
+		// When: Two bids rest, an ask fills one of them, the other is canceled after a
+		// new bid and ask rest, and an order is refused
+		bid := OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}
+		service.PlaceOrder(ctx, bid)
+		second, _ := service.PlaceOrder(ctx, OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		service.CancelOrder(ctx, second.Order.ID)
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 2, Price: 59900})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 60100})
+		service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: -1, Price: 60000})
+		service.RunScheduledWork(ctx)
+
+		// Then: Order flow, the trade and the book are reported by symbol
+		w := httptest.NewRecorder()
+		metricsPort.GetHTTPHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		output := w.Body.String()
+		for _, expected := range []string{
+			`exchange_orders_placed_total{side="buy",symbol="BTC-USD"} 3`,
+			`exchange_orders_placed_total{side="sell",symbol="BTC-USD"} 2`,
+			`exchange_orders_canceled_total{symbol="BTC-USD"} 1`,
+			`exchange_orders_rejected_total{reason="INVALID_QUANTITY",symbol="BTC-USD"} 1`,
+			`exchange_trades_total{symbol="BTC-USD"} 1`,
+			`exchange_trade_volume_total{symbol="BTC-USD"} 1`,
+			`exchange_trade_notional_total{symbol="BTC-USD"} 60000`,
+			`exchange_fill_rate{symbol="BTC-USD"} 0.3333333333333333`,
+			`exchange_order_to_trade_latency_seconds_count{symbol="BTC-USD"} 2`,
+			`exchange_open_orders{symbol="BTC-USD"} 2`,
+			`exchange_book_depth{side="buy",symbol="BTC-USD"} 2`,
+			`exchange_spread{symbol="BTC-USD"} 200`,
+			`exchange_trade_notional_bucket{symbol="BTC-USD",le="100000"} 1`,
+			`exchange_trade_notional_bucket{symbol="BTC-USD",le="10000"} 0`,
+			`exchange_order_to_trade_latency_seconds_bucket{symbol="BTC-USD",le="0.001"}`,
+			`exchange_engine_queue_depth_count{symbol="BTC-USD"} 1`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected %s in metrics", expected)
+			}
+		}
+	})
+}
+
+func TestExchangeService_AccountActivity(t *testing.T) {
+	// Given: A venue reporting to a Prometheus metrics adapter, where a maker's bid
+	// fills against a taker, the maker cancels a second bid, the taker has an order
+	// refused and a third account only quotes
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	cfg := &config.Config{ServiceName: "exchange-simulator"}
+	metricsPort := observability.NewPrometheusMetricsAdapter(nil)
+	cfg.SetMetricsPort(metricsPort)
+	service := NewExchangeService(cfg, logger)
+
+	service.PlaceOrder(ctx, OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+	second, _ := service.PlaceOrder(ctx, OrderRequest{AccountID: "maker", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 59000})
+	service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 0.5, Price: 60000})
+	service.CancelOrder(ctx, second.Order.ID)
+	service.PlaceOrder(ctx, OrderRequest{AccountID: "taker", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: -1, Price: 60000})
+	service.PlaceOrder(ctx, OrderRequest{AccountID: "quoter", Symbol: "BTC-USD", Side: models.SideSell, Type: models.OrderTypeLimit, Quantity: 1, Price: 61000})
+
+	t.Run("ranks_accounts_by_notional", func(t *testing.T) {
+		// When: The leaderboard is read with the default ordering
+		accounts, err := service.AccountActivityLeaderboard(ctx, "", 0)
+		if err != nil {
+			t.Fatalf("Expected the leaderboard, got %v", err)
+		}
+
+		// Then: Both sides of the trade lead, with their order flow tallied
+		if len(accounts) != 3 || accounts[0].AccountID != "maker" || accounts[1].AccountID != "taker" || accounts[2].AccountID != "quoter" {
+			t.Fatalf("Expected maker, taker then quoter, got %+v", accounts)
+		}
+		maker, taker := accounts[0], accounts[1]
+		if maker.Orders != 2 || maker.Cancels != 1 || maker.Trades != 1 || maker.Notional != 30000 || maker.Volume["BTC-USD"] != 0.5 {
+			t.Errorf("Unexpected maker activity %+v", maker)
+		}
+		if taker.Orders != 1 || taker.Rejections != 1 || taker.RejectionRatio != 0.5 {
+			t.Errorf("Unexpected taker activity %+v", taker)
+		}
+	})
+
+	t.Run("sorts_and_limits", func(t *testing.T) {
+		// When: The leaderboard is ranked by rejection ratio, top one only
+		accounts, err := service.AccountActivityLeaderboard(ctx, ActivitySortRejectionRatio, 1)
+
+		// Then: The account with refused orders is returned alone
+		if err != nil || len(accounts) != 1 || accounts[0].AccountID != "taker" {
+			t.Errorf("Expected only the taker, got %+v (%v)", accounts, err)
+		}
+	})
+
+	t.Run("rejects_unknown_sort", func(t *testing.T) {
+		if _, err := service.AccountActivityLeaderboard(ctx, "pnl", 0); RejectionOf(err).Reason != RejectInvalidRequest {
+			t.Errorf("Expected INVALID_REQUEST, got %v", err)
+		}
+	})
+
+	t.Run("reports_aggregate_metrics", func(t *testing.T) {
+		// When: Scheduled work refreshes the metrics
+		service.RunScheduledWork(ctx)
+
+		// Then: Accounts are counted by state, without per-account labels
+		w := httptest.NewRecorder()
+		metricsPort.GetHTTPHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		output := w.Body.String()
+		for _, expected := range []string{
+			`exchange_accounts{state="trading"} 2`,
+			`exchange_accounts{state="quoting"} 1`,
+			`exchange_accounts{state="idle"} 0`,
+			`exchange_top_account_notional_share 1`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected %s in metrics", expected)
+			}
+		}
+	})
+}
+
+func TestExchangeService_Personality(t *testing.T) {
+	t.Run("runs_as_the_named_venue", func(t *testing.T) {
+		// Given: A venue and the personality of exchange-OKX as the configuration service holds it
+		ctx := context.Background()
+		service := newTestExchangeService()
+		personality, err := ParsePersonality(json.RawMessage(`{
+			"venue": "OKX",
+			"fee_schedule": {"*": {"maker_fee_rate": 0.0008, "taker_fee_rate": 0.001}},
+			"latency": {"base": "5ms"},
+			"instruments": ["BTC-USDT", "ETH-USDT"],
+			"api_flavor": "binance"
+		}`))
+		if err != nil {
+			t.Fatalf("Expected the personality parsed, got %v", err)
+		}
+
+		// When: The venue takes it on
+		set, err := service.SetPersonality(ctx, personality)
+		if err != nil {
+			t.Fatalf("Expected the personality set, got %v", err)
+		}
+
+		// Then: Only the named instruments are listed, at the venue's fees
+		if len(set.Instruments) != 2 || set.Instruments[0] != "BTC-USDT" || set.Venue != "OKX" || set.APIFlavor != APIFlavorBinance {
+			t.Errorf("Expected OKX listing BTC-USDT and ETH-USDT with the binance flavor, got %+v", set)
+		}
+		if btc, _ := service.instruments.Get("BTC-USDT"); btc.TakerFeeRate != 0.001 {
+			t.Errorf("Expected the venue's taker fee, got %+v", btc)
+		}
+		_, err = service.PlaceOrder(ctx, OrderRequest{AccountID: "a", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+		if !errors.Is(err, ErrUnknownInstrument) {
+			t.Errorf("Expected a delisted symbol refused as unknown, got %v", err)
+		}
+
+		// And: Accounts without a profile see the venue's latency
+		started := time.Now()
+		if _, err := service.PlaceOrder(ctx, OrderRequest{AccountID: "a", Symbol: "BTC-USDT", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000}); err != nil {
+			t.Fatalf("Expected the order placed, got %v", err)
+		}
+		if elapsed := time.Since(started); elapsed < 5*time.Millisecond {
+			t.Errorf("Expected at least 5ms of gateway latency, got %s", elapsed)
+		}
+	})
+
+	t.Run("refuses_an_invalid_personality_without_changes", func(t *testing.T) {
+		// Given: A venue
+		service := newTestExchangeService()
+		listed := len(service.instruments.List())
+
+		// When: Personalities with a fee schedule naming a delisted symbol, an unknown
+		// flavor or too much latency are set
+		errs := make([]error, 0, 3)
+		for _, personality := range []Personality{
+			{Instruments: []string{"BTC-USDT"}, FeeSchedule: FeeSchedule{"BTC-USD": {TakerFeeRate: 0.001}}},
+			{Instruments: []string{"BTC-USDT"}, APIFlavor: "fix-only"},
+			{Instruments: []string{"BTC-USDT"}, Latency: LatencyProfile{Base: 5 * time.Second, Jitter: time.Second}},
+		} {
+			_, err := service.SetPersonality(context.Background(), personality)
+			errs = append(errs, err)
+		}
+
+		// Then: Each is refused and every instrument stays listed
+		for i, err := range errs {
+			if RejectionOf(err).Reason != RejectInvalidRequest {
+				t.Errorf("Expected personality %d refused as INVALID_REQUEST, got %v", i, err)
+			}
+		}
+		if got := len(service.instruments.List()); got != listed {
+			t.Errorf("Expected %d instruments listed, got %d", listed, got)
+		}
+	})
+}
+
+// memoryStateStore keeps the last saved engine state
+type memoryStateStore struct {
+	state matching.EngineState
+	saves int
+}
+
+func (m *memoryStateStore) Save(state matching.EngineState) error {
+	m.state = state
+	m.saves++
+	return nil
+}
+
+func (m *memoryStateStore) Load() (matching.EngineState, error) {
+	return m.state, nil
+}
+
+func TestExchangeService_RestoreFromState(t *testing.T) {
+	t.Run("restarts_with_open_orders_and_positions", func(t *testing.T) {
+		// Given: A recording service whose state was saved after a trade and a resting bid
+		ctx := context.Background()
+		log := matching.NewMemoryEventLog()
+		store := &memoryStateStore{}
+		first := newTestExchangeService()
+		first.RestoreFromEventLog(nil, log)
+		order := func(account string, side models.Side, price float64) OrderRequest {
+			return OrderRequest{AccountID: account, Symbol: "BTC-USD", Side: side, Type: models.OrderTypeLimit, Quantity: 1, Price: price}
+		}
+		first.PlaceOrder(ctx, order("maker", models.SideSell, 60000))
+		first.PlaceOrder(ctx, order("taker", models.SideBuy, 60000))
+		resting, _ := first.PlaceOrder(ctx, order("maker", models.SideBuy, 59000))
+		if err := first.SaveState(store); err != nil {
+			t.Fatalf("Expected the state to save, got %v", err)
+		}
+
+		// And: An order journaled after the save
+		later, _ := first.PlaceOrder(ctx, order("maker", models.SideBuy, 58000))
+
+		// When: The venue restarts from the saved state and the journal
+		second := newTestExchangeService()
+		state, _ := store.Load()
+		journal := matching.NewMemoryEventLog()
+		if err := second.RestoreFromState(state, log.Events(), journal); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Both resting bids are back and the taker still holds its position
+		open := second.Engine().OpenOrders("maker", "")
+		if len(open) != 2 || open[0].ID != resting.Order.ID && open[1].ID != resting.Order.ID {
+			t.Errorf("Expected %s and %s open, got %+v", resting.Order.ID, later.Order.ID, open)
+		}
+		if positions, _ := second.Positions().Positions(ctx, "taker", "BTC-USD"); len(positions) != 1 || positions[0].Quantity != 1 {
+			t.Errorf("Expected the taker long 1, got %+v", positions)
+		}
+
+		// And: Events journaled after the restart continue the sequence
+		if events := journal.Events(); len(events) == 0 || events[0].Sequence != first.Engine().Sequence()+1 {
+			t.Errorf("Expected the journal to continue from %d, got %+v", first.Engine().Sequence(), events)
+		}
+	})
+
+	t.Run("without_a_saved_state_replays_the_journal", func(t *testing.T) {
+		// Given: A journal and a store holding no state
+		log := matching.NewMemoryEventLog()
+		first := newTestExchangeService()
+		first.RestoreFromEventLog(nil, log)
+		first.PlaceOrder(context.Background(), OrderRequest{AccountID: "acct-1", Symbol: "BTC-USD", Side: models.SideBuy, Type: models.OrderTypeLimit, Quantity: 1, Price: 60000})
+
+		// When: The venue restarts
+		second := newTestExchangeService()
+		state, _ := (&memoryStateStore{}).Load()
+		err := second.RestoreFromState(state, log.Events(), matching.NewMemoryEventLog())
+
+		// Then: The order is back from the journal alone
+		if err != nil || len(second.Engine().OpenOrders("acct-1", "")) != 1 {
+			t.Errorf("Expected the order replayed, got %v", err)
+		}
+	})
+}
+
+func TestExchangeService_SeedFixture(t *testing.T) {
+	seed := func(t *testing.T) fixture.Fixture {
+		t.Helper()
+		parsed, err := fixture.Parse([]byte(`
+name: thin-book
+instruments:
+  - {symbol: SOL-USD, base_asset: SOL, quote_asset: USD, tick_size: 0.01, lot_size: 0.1, reference_price: 150}
+accounts:
+  - {account_id: mm-1, balances: {USD: 10000, SOL: 100}, api_key: sk_fixture}
+  - {account_id: taker-1, tier: vip}
+orders:
+  - {account_id: mm-1, symbol: SOL-USD, side: sell, quantity: 10, price: 151}
+  - {account_id: mm-1, symbol: SOL-USD, side: buy, quantity: 10, price: 149}
+`))
+		if err != nil {
+			t.Fatalf("Expected the fixture parsed, got %v", err)
+		}
+		return parsed
+	}
+
+	t.Run("seeds_a_cold_venue", func(t *testing.T) {
+		// Given: A venue holding no accounts or orders
+		ctx := context.Background()
+		service := newTestExchangeService()
+
+		// When: The fixture is seeded
+		report, err := service.SeedFixture(ctx, seed(t))
+		if err != nil {
+			t.Fatalf("Expected the fixture seeded, got %v", err)
+		}
+
+		// Then: The instrument is listed, the accounts opened under their own IDs and
+		// the orders rest on the new book
+		if report != (SeedReport{Fixture: "thin-book", Instruments: 1, Accounts: 2, Orders: 2}) {
+			t.Errorf("Expected one instrument, two accounts and two orders, got %+v", report)
+		}
+		if _, err := service.instruments.Get("SOL-USD"); err != nil {
+			t.Errorf("Expected SOL-USD listed, got %v", err)
+		}
+		if taker, err := service.Account(ctx, "taker-1"); err != nil || taker.Tier != "vip" {
+			t.Errorf("Expected taker-1 opened as vip, got %+v, %v", taker, err)
+		}
+		if open := service.Engine().OpenOrders("mm-1", "SOL-USD"); len(open) != 2 {
+			t.Errorf("Expected both orders resting, got %+v", open)
+		}
+
+		// And: The maker is funded, with its resting orders held against it, and its
+		// key is issued
+		balances, _ := service.AccountBalances(ctx, "mm-1")
+		for _, balance := range balances.Balances {
+			if balance.Asset == "SOL" && (balance.Total != 100 || balance.Held != 10) {
+				t.Errorf("Expected 100 SOL with 10 held, got %+v", balance)
+			}
+		}
+		if account, issued := service.accounts.directory.ByAPIKey("sk_fixture"); !issued || account.ID != "mm-1" {
+			t.Errorf("Expected the fixture key issued to mm-1, got %+v", account)
+		}
+	})
+
+	t.Run("lists_instruments_only_on_a_warm_venue", func(t *testing.T) {
+		// Given: A venue already seeded once, as a restart restores it
+		ctx := context.Background()
+		service := newTestExchangeService()
+		if _, err := service.SeedFixture(ctx, seed(t)); err != nil {
+			t.Fatalf("Expected the fixture seeded, got %v", err)
+		}
+
+		// When: The fixture is seeded again
+		report, err := service.SeedFixture(ctx, seed(t))
+
+		// Then: Nothing is opened or placed twice
+		if err != nil || !report.Skipped || report.Accounts != 0 || report.Orders != 0 {
+			t.Errorf("Expected accounts and orders skipped, got %+v, %v", report, err)
+		}
+		if open := service.Engine().OpenOrders("mm-1", ""); len(open) != 2 {
+			t.Errorf("Expected the two original orders only, got %d", len(open))
+		}
+	})
+
+	t.Run("stops_at_a_refused_order", func(t *testing.T) {
+		// Given: A fixture whose second order the maker cannot pay for
+		ctx := context.Background()
+		service := newTestExchangeService()
+		broke := seed(t)
+		broke.Orders[1].Quantity = 1000
+
+		// When: It is seeded
+		report, err := service.SeedFixture(ctx, broke)
+
+		// Then: The refusal names the order, and what came before it stays
+		if err == nil || !strings.Contains(err.Error(), "fixture order 2") {
+			t.Errorf("Expected fixture order 2 refused, got %v", err)
+		}
+		if report.Orders != 1 || len(service.Engine().OpenOrders("mm-1", "")) != 1 {
+			t.Errorf("Expected the first order placed, got %+v", report)
+		}
+	})
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/funding"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// maxFundingEvents bounds the funding feed; the oldest events are dropped first
+	maxFundingEvents = 1000
+
+	// maxFundingPayments bounds the payments kept for inspection; the oldest are dropped first
+	maxFundingPayments = 10000
+
+	// maxFundingCatchUp bounds the funding times published per symbol in one run,
+	// so a large clock jump cannot stall the scheduler
+	maxFundingCatchUp = 100
+)
+
+// FundingEvent is a perpetual funding settlement at a funding time
+type FundingEvent struct {
+	Symbol       string    `json:"symbol"`
+	Rate         float64   `json:"rate"`
+	PremiumIndex float64   `json:"premium_index"` // Average premium of the fair price over the index since the last funding time
+	IndexPrice   float64   `json:"index_price"`
+	MarkPrice    float64   `json:"mark_price"`
+	Positions    int       `json:"positions"`   // Accounts holding the contract at the funding time
+	Transferred  float64   `json:"transferred"` // Paid from one side to the other, in the quote asset
+	FundingTime  time.Time `json:"funding_time"`
+}
+
+// FundingPayment is what one position paid (positive) or received (negative) at a
+// funding time. Payments of accounts without opening balances are not posted.
+type FundingPayment struct {
+	AccountID   string    `json:"account_id"`
+	Symbol      string    `json:"symbol"`
+	Asset       string    `json:"asset"`
+	Position    float64   `json:"position"` // Signed contracts; negative is short
+	MarkPrice   float64   `json:"mark_price"`
+	Rate        float64   `json:"rate"`
+	Amount      float64   `json:"amount"`
+	Posted      bool      `json:"posted"` // Settled through the balance journal
+	FundingTime time.Time `json:"funding_time"`
+}
+
+// fundingSchedule tracks each perpetual's next funding time, the premium sampled
+// towards it and the published feed
+type fundingSchedule struct {
+	next     map[string]time.Time
+	premiums map[string]*funding.Index
+	events   []FundingEvent
+	payments []FundingPayment
+	mu       sync.Mutex
+}
+
+func newFundingSchedule() *fundingSchedule {
+	return &fundingSchedule{
+		next:     make(map[string]time.Time),
+		premiums: make(map[string]*funding.Index),
+		events:   make([]FundingEvent, 0),
+		payments: make([]FundingPayment, 0),
+	}
+}
+
+// FundingEvents returns published funding oldest first, optionally for one symbol
+func (s *ExchangeService) FundingEvents(ctx context.Context, symbol string) []FundingEvent {
+	s.funding.mu.Lock()
+	defer s.funding.mu.Unlock()
+
+	events := make([]FundingEvent, 0)
+	for _, event := range s.funding.events {
+		if symbol == "" || event.Symbol == symbol {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// FundingPayments returns an account's funding payments oldest first, optionally for one symbol
+func (s *ExchangeService) FundingPayments(ctx context.Context, accountID, symbol string) ([]FundingPayment, error) {
+	if accountID == "" {
+		return nil, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return nil, err
+	}
+	s.funding.mu.Lock()
+	defer s.funding.mu.Unlock()
+
+	payments := make([]FundingPayment, 0)
+	for _, payment := range s.funding.payments {
+		if payment.AccountID == accountID && (symbol == "" || payment.Symbol == symbol) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+// predictedFundingRate is the rate a perpetual would fund at from the premium
+// sampled so far
+func (s *ExchangeService) predictedFundingRate(instrument models.Instrument) float64 {
+	s.funding.mu.Lock()
+	defer s.funding.mu.Unlock()
+	premium := 0.0
+	if index, ok := s.funding.premiums[instrument.Symbol]; ok {
+		premium = index.Value()
+	}
+	return funding.Rate(premium, instrument.FundingRate)
+}
+
+// fundingInterval is the configured interval between funding times, or the instrument's own
+func (s *ExchangeService) fundingInterval(instrument models.Instrument) time.Duration {
+	if s.config != nil && s.config.FundingInterval > 0 {
+		return s.config.FundingInterval
+	}
+	return instrument.FundingInterval
+}
+
+// publishFunding samples each perpetual's premium, then settles every funding time
+// it has passed. Funding times fall on multiples of the interval (00:00, 08:00 and
+// 16:00 UTC for eight hours); the first run only arms the next one. The rate is
+// the premium index averaged since the last funding time, pulled towards the
+// instrument's funding (interest) rate, and positions pay it on their mark value:
+// longs pay shorts when it is positive.
+func (s *ExchangeService) publishFunding(now time.Time) int {
+	schedule := s.funding
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	published := 0
+	var positions map[string]map[string]float64 // Built once the first funding time is due
+	for _, instrument := range s.instruments.List() {
+		interval := s.fundingInterval(instrument)
+		if !instrument.IsDerivative() || interval <= 0 {
+			continue
+		}
+		index, ok := schedule.premiums[instrument.Symbol]
+		if !ok {
+			index = &funding.Index{}
+			schedule.premiums[instrument.Symbol] = index
+		}
+		indexPrice := s.indexPrice(instrument)
+		index.Sample(funding.Premium(s.fairPrice(instrument), indexPrice))
+
+		next, armed := schedule.next[instrument.Symbol]
+		if !armed {
+			schedule.next[instrument.Symbol] = now.Truncate(interval).Add(interval)
+			continue
+		}
+
+		mark := s.markOrReference(instrument)
+		for caughtUp := 0; !now.Before(next) && caughtUp < maxFundingCatchUp; caughtUp++ {
+			if positions == nil {
+				positions = s.perpetualPositions()
+			}
+			event := FundingEvent{
+				Symbol:       instrument.Symbol,
+				Rate:         funding.Rate(index.Value(), instrument.FundingRate),
+				PremiumIndex: index.Value(),
+				IndexPrice:   indexPrice,
+				MarkPrice:    mark,
+				FundingTime:  next,
+			}
+			index.Reset()
+			for _, payment := range s.settleFunding(instrument, positions[instrument.Symbol], event) {
+				event.Positions++
+				if payment.Amount > 0 {
+					event.Transferred += payment.Amount
+				}
+			}
+			schedule.events = append(schedule.events, event)
+			published++
+			next = next.Add(interval)
+
+			s.logger.WithFields(logrus.Fields{
+				"symbol":        event.Symbol,
+				"rate":          event.Rate,
+				"premium_index": event.PremiumIndex,
+				"mark_price":    event.MarkPrice,
+				"positions":     event.Positions,
+				"transferred":   event.Transferred,
+				"funding_time":  event.FundingTime,
+			}).Info("Funding event published")
+		}
+		if !now.Before(next) {
+			// Skipped funding times are not replayed after a jump beyond the catch-up bound
+			next = now.Truncate(interval).Add(interval)
+		}
+		schedule.next[instrument.Symbol] = next
+	}
+	if len(schedule.events) > maxFundingEvents {
+		schedule.events = schedule.events[len(schedule.events)-maxFundingEvents:]
+	}
+	if len(schedule.payments) > maxFundingPayments {
+		schedule.payments = schedule.payments[len(schedule.payments)-maxFundingPayments:]
+	}
+	return published
+}
+
+// settleFunding charges every position in a perpetual its funding payment. Funded
+// accounts pay from and receive into their available quote balance in one journal
+// transaction, balanced through the settlement account; hold schedule.mu.
+func (s *ExchangeService) settleFunding(instrument models.Instrument, positions map[string]float64, event FundingEvent) []FundingPayment {
+	accountIDs := make([]string, 0, len(positions))
+	for accountID := range positions {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	payments := make([]FundingPayment, 0, len(positions))
+	legs := make([]ledger.Leg, 0, len(positions)+1)
+	settled := 0.0
+	for _, accountID := range accountIDs {
+		quantity := positions[accountID]
+		payment := FundingPayment{
+			AccountID:   accountID,
+			Symbol:      instrument.Symbol,
+			Asset:       instrument.QuoteAsset,
+			Position:    quantity,
+			MarkPrice:   event.MarkPrice,
+			Rate:        event.Rate,
+			Amount:      funding.Payment(quantity, event.MarkPrice, event.Rate),
+			Posted:      s.isFunded(accountID),
+			FundingTime: event.FundingTime,
+		}
+		if payment.Posted {
+			legs = append(legs, ledger.Leg{AccountID: accountID, Asset: payment.Asset, Bucket: ledger.BucketAvailable, Amount: -payment.Amount})
+			settled += payment.Amount
+		}
+		payments = append(payments, payment)
+	}
+	if len(legs) > 0 {
+		legs = append(legs, ledger.Leg{AccountID: ledger.SettlementAccount, Asset: instrument.QuoteAsset, Bucket: ledger.BucketAvailable, Amount: settled})
+		reference := instrument.Symbol + "@" + event.FundingTime.UTC().Format(time.RFC3339)
+		postings, err := s.balances.journal.Post(ledger.KindFunding, reference, event.FundingTime, legs...)
+		if err != nil {
+			s.logger.WithError(err).WithField("symbol", instrument.Symbol).Error("Failed to post funding")
+		}
+		s.balances.record(postings)
+	}
+	s.funding.payments = append(s.funding.payments, payments...)
+	return payments
+}
+
+// perpetualPositions nets every account's derivative fills into positions, by
+// symbol then account; flat positions are left out
+func (s *ExchangeService) perpetualPositions() map[string]map[string]float64 {
+	books := make(map[string]*margin.Book)
+	for _, order := range s.engine.FilledOrders("") {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || !instrument.IsDerivative() {
+			continue
+		}
+		book, ok := books[order.AccountID]
+		if !ok {
+			book = margin.NewBook()
+			books[order.AccountID] = book
+		}
+		book.Fill(order.Symbol, order.Side, order.FilledQuantity, order.AveragePrice)
+	}
+
+	positions := make(map[string]map[string]float64)
+	for accountID, book := range books {
+		for _, position := range book.Positions() {
+			if position.Quantity == 0 {
+				continue
+			}
+			if positions[position.Symbol] == nil {
+				positions[position.Symbol] = make(map[string]float64)
+			}
+			positions[position.Symbol][accountID] = position.Quantity
+		}
+	}
+	return positions
+}
+
+// indexPrice is the external price a perpetual tracks, or its reference price
+// while no fresh one has arrived
+func (s *ExchangeService) indexPrice(instrument models.Instrument) float64 {
+	if price, fresh := s.referencePrices.Latest(instrument.Symbol, s.now()); fresh {
+		return price.Price
+	}
+	return instrument.ReferencePrice
+}
+
+// fairPrice is where a perpetual trades: the mid of its best bid and ask, or its
+// last price while either side is empty
+func (s *ExchangeService) fairPrice(instrument models.Instrument) float64 {
+	if snapshot, err := s.engine.Snapshot(instrument.Symbol, 1); err == nil && len(snapshot.Bids) > 0 && len(snapshot.Asks) > 0 {
+		return (snapshot.Bids[0].Price + snapshot.Asks[0].Price) / 2
+	}
+	if last, err := s.engine.LastPrice(instrument.Symbol); err == nil {
+		return last
+	}
+	return instrument.ReferencePrice
+}
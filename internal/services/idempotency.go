@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// defaultIdempotencyWindow is how long keys are remembered when none is configured
+const defaultIdempotencyWindow = 24 * time.Hour
+
+// maxIdempotencyKeyLength bounds caller-assigned idempotency keys
+const maxIdempotencyKeyLength = 128
+
+// SetIdempotencyStore loads keys persisted before a restart, compacts the store down
+// to those still inside the window, and persists every further keyed request to it
+func (s *ExchangeService) SetIdempotencyStore(store idempotency.Store) error {
+	records, err := store.Load()
+	if err != nil {
+		return err
+	}
+	kept := s.idempotency.Restore(records, s.now())
+	if len(kept) != len(records) {
+		if err := store.Replace(kept); err != nil {
+			return err
+		}
+	}
+	s.idempotencyStore = store
+	s.logger.WithFields(logrus.Fields{
+		"restored": len(kept),
+		"expired":  len(records) - len(kept),
+	}).Info("Idempotency keys restored")
+	return nil
+}
+
+// beginKeyed claims the request's idempotency key, scoped to the caller's API key.
+// It returns no claim and no record for unkeyed requests, and the remembered record
+// when a retry of a completed request arrives.
+func (s *ExchangeService) beginKeyed(ctx context.Context, fingerprint string) (*idempotency.Claim, *idempotency.Record, error) {
+	key := idempotency.KeyFrom(ctx)
+	if key == "" {
+		return nil, nil, nil
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, nil, rejectf(RejectInvalidRequest, "idempotency key exceeds %d characters", maxIdempotencyKeyLength)
+	}
+	return s.idempotency.Begin(keystats.APIKey(ctx)+"/"+key, fingerprint, s.now())
+}
+
+// finishKeyed remembers a keyed request's outcome. Failed requests free the key so
+// a retry runs again; a failed write to the store is logged rather than failing a
+// request that already executed.
+func (s *ExchangeService) finishKeyed(claim *idempotency.Claim, orderID string, err error) {
+	if claim == nil {
+		return
+	}
+	if err != nil {
+		claim.Abandon()
+		return
+	}
+	record := claim.Complete(orderID)
+	if s.idempotencyStore == nil {
+		return
+	}
+	err = s.persist(componentIdempotency, "append", logrus.Fields{"order_id": orderID}, func() error {
+		return s.idempotencyStore.Append(record)
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", orderID).Error("Failed to persist idempotency key")
+	}
+}
+
+// replayedReport answers a retried place or amend with the order's current state
+func (s *ExchangeService) replayedReport(record *idempotency.Record) (*matching.ExecutionReport, error) {
+	order, err := s.engine.GetOrder(record.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"order_id":        record.OrderID,
+		"idempotency_key": record.Key,
+	}).Info("Retried request answered from idempotency key")
+	return &matching.ExecutionReport{Order: order, Duplicate: true}, nil
+}
+
+func placeFingerprint(req OrderRequest) string {
+	return fmt.Sprintf("place|%s|%s|%s|%s|%s|%s|%g|%g|%d",
+		req.AccountID, req.ClientOrderID, req.Symbol, req.Side, req.Type, req.TimeInForce,
+		req.Quantity, req.Price, req.ExpiresAt.UnixNano())
+}
+
+func cancelFingerprint(orderID string) string {
+	return "cancel|" + orderID
+}
+
+func amendFingerprint(orderID string, req matching.AmendRequest) string {
+	return fmt.Sprintf("amend|%s|%g|%g", orderID, req.Price, req.Quantity)
+}
+
+// orderIDOf returns the order a report is about, or none when there is no report
+func orderIDOf(report *matching.ExecutionReport) string {
+	if report == nil {
+		return ""
+	}
+	return report.Order.ID
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// defaultIncidentHistory is how many health transitions are kept when unconfigured
+const defaultIncidentHistory = 1000
+
+// Components whose health the venue tracks besides its instruments
+const (
+	ComponentDataAdapter = "dependency:data-adapter"
+	componentAuditSink   = "dependency:audit-sink"
+	componentStatistics  = "storage:statistics"
+	componentMetrics     = "storage:metrics"
+	componentCandles     = "storage:candles"
+	componentTrades      = "storage:trades"
+	componentIdempotency = "storage:idempotency"
+	componentAccounts    = "storage:accounts"
+	componentBalances    = "storage:balances"
+	componentEvents      = "storage:events"
+	componentLatency     = "storage:latency"
+	componentOutbox      = "storage:outbox"
+	componentState       = "storage:state"
+	componentOrders      = "storage:orders"
+	instrumentPrefix     = "instrument:"
+)
+
+// IncidentReport is the venue's current health and the transitions that led to it
+type IncidentReport struct {
+	Unhealthy []incidents.Component  `json:"unhealthy"`
+	Incidents []incidents.Transition `json:"incidents"`
+	Capacity  int                    `json:"capacity"` // Older transitions have been dropped
+}
+
+// ReportHealth records a component's status on the incident timeline, logging it
+// when it changed
+func (s *ExchangeService) ReportHealth(ctx context.Context, component string, status incidents.Status, detail string) {
+	transition, changed := s.incidents.Record(component, status, detail, s.now())
+	if !changed {
+		return
+	}
+	event := scenario.Event{Type: scenario.EventHealth, Time: transition.At, Component: component, Status: string(status)}
+	if strings.HasPrefix(component, instrumentPrefix) {
+		event.Symbol = strings.TrimPrefix(component, instrumentPrefix)
+	}
+	s.recordScenario(event)
+	entry := s.logger.WithFields(logrus.Fields{
+		"component": component,
+		"status":    status,
+		"previous":  transition.Previous,
+		"detail":    detail,
+	})
+	if status == incidents.StatusUp {
+		entry.Info("Component recovered")
+		return
+	}
+	entry.Warn("Component health changed")
+}
+
+// Incidents returns the unhealthy components and the retained transitions matching query
+func (s *ExchangeService) Incidents(ctx context.Context, query incidents.Query) (IncidentReport, error) {
+	if query.Limit < 0 {
+		return IncidentReport{}, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	if query.Component != "" && !strings.Contains(query.Component, ":") {
+		return IncidentReport{}, rejectf(RejectInvalidRequest, "component must be <kind>:<name> or a <kind>: prefix, got %q", query.Component)
+	}
+	s.reconcileHalts(ctx)
+	return IncidentReport{
+		Unhealthy: s.incidents.Unhealthy(),
+		Incidents: s.incidents.Transitions(query),
+		Capacity:  s.incidents.Capacity(),
+	}, nil
+}
+
+// reportOutcome marks a component degraded while err is set and up once it clears
+func (s *ExchangeService) reportOutcome(component string, err error) {
+	if err != nil {
+		s.ReportHealth(context.Background(), component, incidents.StatusDegraded, err.Error())
+		return
+	}
+	s.ReportHealth(context.Background(), component, incidents.StatusUp, "")
+}
+
+// reconcileHalts records halts and resumptions, including the circuit breaker's,
+// which the engine applies on its own
+func (s *ExchangeService) reconcileHalts(ctx context.Context) {
+	halted := make(map[string]bool)
+	for _, halt := range s.engine.Halts() {
+		halted[instrumentPrefix+halt.Symbol] = true
+		s.ReportHealth(ctx, instrumentPrefix+halt.Symbol, incidents.StatusHalted, haltDetail(halt))
+	}
+	for _, component := range s.incidents.Unhealthy() {
+		if strings.HasPrefix(component.Component, instrumentPrefix) && !halted[component.Component] {
+			s.ReportHealth(ctx, component.Component, incidents.StatusUp, "trading resumed")
+		}
+	}
+}
+
+func haltDetail(halt matching.HaltInfo) string {
+	detail := string(halt.Reason) + " halt"
+	if halt.Reason == matching.HaltReasonVolatility {
+		detail += fmt.Sprintf(": %.2f%% move from %g to %g", halt.MovePercent, halt.ReferencePrice, halt.TriggerPrice)
+	}
+	if !halt.ResumesAt.IsZero() {
+		detail += ", resumes at " + halt.ResumesAt.Format(time.RFC3339)
+	}
+	return detail
+}
+
+// incidentHistory is how many transitions to keep, falling back to the default when unset
+func incidentHistory(cfg *config.Config) int {
+	if cfg == nil || cfg.IncidentHistorySize <= 0 {
+		return defaultIncidentHistory
+	}
+	return cfg.IncidentHistorySize
+}
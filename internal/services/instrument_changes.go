@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrInstrumentChangeNotFound is returned for an unknown scheduled change ID
+var ErrInstrumentChangeNotFound = errors.New("instrument change not found")
+
+// maxInstrumentEvents bounds the announcement feed; the oldest events are dropped first
+const maxInstrumentEvents = 1000
+
+// InstrumentChangeStatus is the lifecycle state of a scheduled change
+type InstrumentChangeStatus string
+
+const (
+	InstrumentChangeScheduled InstrumentChangeStatus = "scheduled"
+	InstrumentChangeActive    InstrumentChangeStatus = "active"
+	InstrumentChangeCanceled  InstrumentChangeStatus = "canceled"
+)
+
+// InstrumentEventType names an entry in the announcement feed
+type InstrumentEventType string
+
+const (
+	InstrumentEventAnnounced InstrumentEventType = "instrument_change_announced"
+	InstrumentEventActivated InstrumentEventType = "instrument_change_activated"
+	InstrumentEventCanceled  InstrumentEventType = "instrument_change_canceled"
+)
+
+// InstrumentChangeRequest schedules new contract specification values; zero fields stay unchanged
+type InstrumentChangeRequest struct {
+	TickSize         float64   `json:"tick_size"`
+	LotSize          float64   `json:"lot_size"`
+	PriceBandPercent float64   `json:"price_band_percent"` // Circuit breaker threshold, e.g. 10 = 10%
+	EffectiveAt      time.Time `json:"effective_at"`
+}
+
+// InstrumentChange is a contract specification change announced ahead of its activation
+type InstrumentChange struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	InstrumentChangeRequest
+	Status      InstrumentChangeStatus `json:"status"`
+	AnnouncedAt time.Time              `json:"announced_at"`
+	ActivatedAt time.Time              `json:"activated_at,omitempty"`
+}
+
+// InstrumentEvent is one entry in the announcement feed strategies watch for specification changes
+type InstrumentEvent struct {
+	Sequence   uint64              `json:"sequence"`
+	Type       InstrumentEventType `json:"type"`
+	Change     InstrumentChange    `json:"change"`
+	Instrument *models.Instrument  `json:"instrument,omitempty"` // Specification in force after activation
+	At         time.Time           `json:"at"`
+}
+
+// instrumentSchedule holds scheduled changes in effective order and the announcement feed
+type instrumentSchedule struct {
+	changes  []*InstrumentChange
+	events   []InstrumentEvent
+	nextID   uint64
+	sequence uint64
+	mu       sync.Mutex
+}
+
+func newInstrumentSchedule() *instrumentSchedule {
+	return &instrumentSchedule{
+		changes: make([]*InstrumentChange, 0),
+		events:  make([]InstrumentEvent, 0),
+	}
+}
+
+// publish appends to the feed; callers hold mu
+func (sc *instrumentSchedule) publish(eventType InstrumentEventType, change InstrumentChange, instrument *models.Instrument, at time.Time) {
+	sc.sequence++
+	sc.events = append(sc.events, InstrumentEvent{
+		Sequence:   sc.sequence,
+		Type:       eventType,
+		Change:     change,
+		Instrument: instrument,
+		At:         at,
+	})
+	if len(sc.events) > maxInstrumentEvents {
+		sc.events = sc.events[len(sc.events)-maxInstrumentEvents:]
+	}
+}
+
+// ScheduleInstrumentChange announces a tick size, lot size or price band change that
+// takes effect at req.EffectiveAt in simulation time
+func (s *ExchangeService) ScheduleInstrumentChange(ctx context.Context, symbol string, req InstrumentChangeRequest) (InstrumentChange, error) {
+	if _, err := s.instruments.Get(symbol); err != nil {
+		return InstrumentChange{}, err
+	}
+	if req.TickSize < 0 || req.LotSize < 0 || req.PriceBandPercent < 0 {
+		return InstrumentChange{}, rejectf(RejectInvalidRequest, "tick size, lot size and price band must not be negative")
+	}
+	if req.TickSize == 0 && req.LotSize == 0 && req.PriceBandPercent == 0 {
+		return InstrumentChange{}, rejectf(RejectInvalidRequest, "change must set a tick size, lot size or price band")
+	}
+	now := s.now()
+	if !req.EffectiveAt.After(now) {
+		return InstrumentChange{}, rejectf(RejectInvalidRequest, "effective time %s is not in the future", req.EffectiveAt.Format(time.RFC3339))
+	}
+
+	schedule := s.schedule
+	schedule.mu.Lock()
+	schedule.nextID++
+	change := &InstrumentChange{
+		ID:                      fmt.Sprintf("chg-%d", schedule.nextID),
+		Symbol:                  symbol,
+		InstrumentChangeRequest: req,
+		Status:                  InstrumentChangeScheduled,
+		AnnouncedAt:             now,
+	}
+	schedule.changes = append(schedule.changes, change)
+	sort.SliceStable(schedule.changes, func(i, j int) bool {
+		return schedule.changes[i].EffectiveAt.Before(schedule.changes[j].EffectiveAt)
+	})
+	schedule.publish(InstrumentEventAnnounced, *change, nil, now)
+	schedule.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"change_id":    change.ID,
+		"symbol":       symbol,
+		"tick_size":    req.TickSize,
+		"lot_size":     req.LotSize,
+		"price_band":   req.PriceBandPercent,
+		"effective_at": req.EffectiveAt,
+	}).Info("Instrument change announced")
+	return *change, nil
+}
+
+// CancelInstrumentChange withdraws a change that has not taken effect yet
+func (s *ExchangeService) CancelInstrumentChange(ctx context.Context, changeID string) (InstrumentChange, error) {
+	s.ActivateInstrumentChanges(ctx)
+
+	schedule := s.schedule
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	for _, change := range schedule.changes {
+		if change.ID != changeID {
+			continue
+		}
+		if change.Status != InstrumentChangeScheduled {
+			return *change, rejectf(RejectInvalidRequest, "instrument change %s is %s", changeID, change.Status)
+		}
+		change.Status = InstrumentChangeCanceled
+		schedule.publish(InstrumentEventCanceled, *change, nil, s.now())
+		s.logger.WithFields(logrus.Fields{
+			"change_id": changeID,
+			"symbol":    change.Symbol,
+		}).Info("Instrument change canceled")
+		return *change, nil
+	}
+	return InstrumentChange{}, fmt.Errorf("%w: %s", ErrInstrumentChangeNotFound, changeID)
+}
+
+// InstrumentChanges lists changes in effective order, optionally for one symbol
+func (s *ExchangeService) InstrumentChanges(ctx context.Context, symbol string) []InstrumentChange {
+	s.ActivateInstrumentChanges(ctx)
+
+	s.schedule.mu.Lock()
+	defer s.schedule.mu.Unlock()
+
+	changes := make([]InstrumentChange, 0)
+	for _, change := range s.schedule.changes {
+		if symbol == "" || change.Symbol == symbol {
+			changes = append(changes, *change)
+		}
+	}
+	return changes
+}
+
+// InstrumentEvents returns announcement feed entries with a sequence above since, oldest first
+func (s *ExchangeService) InstrumentEvents(ctx context.Context, since uint64) []InstrumentEvent {
+	s.ActivateInstrumentChanges(ctx)
+
+	s.schedule.mu.Lock()
+	defer s.schedule.mu.Unlock()
+
+	events := make([]InstrumentEvent, 0)
+	for _, event := range s.schedule.events {
+		if event.Sequence > since {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// ActivateInstrumentChanges applies every scheduled change whose effective time has
+// passed and returns how many took effect. Order entry calls it, so a change is in
+// force for the first order after its effective time even between scheduler runs.
+func (s *ExchangeService) ActivateInstrumentChanges(ctx context.Context) int {
+	schedule := s.schedule
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	now := s.now()
+	activated := 0
+	for _, change := range schedule.changes {
+		if change.EffectiveAt.After(now) {
+			break
+		}
+		if change.Status != InstrumentChangeScheduled {
+			continue
+		}
+		instrument, err := s.applyInstrumentChange(*change)
+		if err != nil {
+			// Left scheduled so the next run retries it
+			s.logger.WithError(err).WithField("change_id", change.ID).Error("Failed to activate instrument change")
+			continue
+		}
+		change.Status = InstrumentChangeActive
+		change.ActivatedAt = now
+		schedule.publish(InstrumentEventActivated, *change, &instrument, now)
+		activated++
+
+		s.logger.WithFields(logrus.Fields{
+			"change_id":  change.ID,
+			"symbol":     change.Symbol,
+			"tick_size":  instrument.TickSize,
+			"lot_size":   instrument.LotSize,
+			"price_band": change.PriceBandPercent,
+		}).Info("Instrument change activated")
+	}
+	return activated
+}
+
+// applyInstrumentChange updates the band on the engine first, so a failure leaves the
+// instrument untouched
+func (s *ExchangeService) applyInstrumentChange(change InstrumentChange) (models.Instrument, error) {
+	instrument, err := s.instruments.Get(change.Symbol)
+	if err != nil {
+		return instrument, err
+	}
+	if change.PriceBandPercent > 0 {
+		breaker, err := s.engine.CircuitBreaker(change.Symbol)
+		if err != nil {
+			return instrument, err
+		}
+		breaker.ThresholdPercent = change.PriceBandPercent
+		if err := s.engine.SetCircuitBreaker(change.Symbol, breaker); err != nil {
+			return instrument, err
+		}
+	}
+	if change.TickSize > 0 {
+		instrument.TickSize = change.TickSize
+	}
+	if change.LotSize > 0 {
+		instrument.LotSize = change.LotSize
+		instrument.MinQuantity = math.Max(instrument.MinQuantity, change.LotSize)
+	}
+	s.instruments.Upsert(instrument)
+	return instrument, nil
+}
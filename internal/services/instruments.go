@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrUnknownInstrument is returned when a symbol is not listed on this venue
+var ErrUnknownInstrument = errors.New("unknown instrument")
+
+// InstrumentRegistry holds the instruments listed on this venue
+type InstrumentRegistry struct {
+	instruments map[string]*models.Instrument
+	mu          sync.RWMutex
+}
+
+// NewInstrumentRegistry creates a registry seeded with the given instruments
+func NewInstrumentRegistry(instruments ...models.Instrument) *InstrumentRegistry {
+	r := &InstrumentRegistry{
+		instruments: make(map[string]*models.Instrument),
+	}
+	for _, instrument := range instruments {
+		r.Upsert(instrument)
+	}
+	return r
+}
+
+// DefaultInstruments returns the instrument set listed by default
+func DefaultInstruments() []models.Instrument {
+	spot := func(symbol, base, quote string, tick, lot, ref float64) models.Instrument {
+		return models.Instrument{
+			Symbol:         symbol,
+			BaseAsset:      base,
+			QuoteAsset:     quote,
+			Kind:           models.InstrumentKindSpot,
+			TickSize:       tick,
+			LotSize:        lot,
+			MinQuantity:    lot,
+			MakerFeeRate:   0.001,
+			TakerFeeRate:   0.002,
+			ReferencePrice: ref,
+		}
+	}
+	perp := func(symbol, base, quote string, tick, lot, ref float64) models.Instrument {
+		return models.Instrument{
+			Symbol:                symbol,
+			BaseAsset:             base,
+			QuoteAsset:            quote,
+			Kind:                  models.InstrumentKindPerpetual,
+			TickSize:              tick,
+			LotSize:               lot,
+			MinQuantity:           lot,
+			MakerFeeRate:          0.0002,
+			TakerFeeRate:          0.0005,
+			InitialMarginRate:     0.05,
+			MaintenanceMarginRate: 0.025,
+			FundingRate:           0.0001,
+			FundingInterval:       8 * time.Hour,
+			ReferencePrice:        ref,
+		}
+	}
+
+	return []models.Instrument{
+		spot("BTC-USD", "BTC", "USD", 0.01, 0.0001, 60000),
+		spot("ETH-USD", "ETH", "USD", 0.01, 0.001, 3000),
+		spot("BTC-USDT", "BTC", "USDT", 0.01, 0.0001, 60000),
+		spot("ETH-USDT", "ETH", "USDT", 0.01, 0.001, 3000),
+		spot("ETH-BTC", "ETH", "BTC", 0.00001, 0.001, 0.05),
+		spot("BTC-EUR", "BTC", "EUR", 0.01, 0.0001, 55000),
+		spot("ETH-EUR", "ETH", "EUR", 0.01, 0.001, 2750),
+		spot("EUR-USD", "EUR", "USD", 0.0001, 0.01, 1.0909),
+		spot("USDT-USD", "USDT", "USD", 0.0001, 0.01, 1),
+		perp("BTC-USD-PERP", "BTC", "USD", 0.5, 0.001, 60000),
+		perp("ETH-USD-PERP", "ETH", "USD", 0.05, 0.01, 3000),
+	}
+}
+
+// Get returns a copy of the instrument for symbol
+func (r *InstrumentRegistry) Get(symbol string) (models.Instrument, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instrument, exists := r.instruments[symbol]
+	if !exists {
+		return models.Instrument{}, fmt.Errorf("%w: %s", ErrUnknownInstrument, symbol)
+	}
+	return *instrument, nil
+}
+
+// List returns all instruments sorted by symbol
+func (r *InstrumentRegistry) List() []models.Instrument {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instruments := make([]models.Instrument, 0, len(r.instruments))
+	for _, instrument := range r.instruments {
+		instruments = append(instruments, *instrument)
+	}
+	sort.Slice(instruments, func(i, j int) bool {
+		return instruments[i].Symbol < instruments[j].Symbol
+	})
+	return instruments
+}
+
+// ByAsset returns the instruments quoting asset as base or quote, sorted by symbol.
+// Books sharing an asset settle into the same ledger position.
+func (r *InstrumentRegistry) ByAsset(asset string) []models.Instrument {
+	instruments := make([]models.Instrument, 0)
+	for _, instrument := range r.List() {
+		if instrument.BaseAsset == asset || instrument.QuoteAsset == asset {
+			instruments = append(instruments, instrument)
+		}
+	}
+	return instruments
+}
+
+// Upsert adds or replaces an instrument definition
+func (r *InstrumentRegistry) Upsert(instrument models.Instrument) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instruments[instrument.Symbol] = &instrument
+}
+
+// Remove delists an instrument, reporting whether it was listed
+func (r *InstrumentRegistry) Remove(symbol string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.instruments[symbol]
+	delete(r.instruments, symbol)
+	return exists
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// maxDeleveragings bounds the auto-deleveraging feed; the oldest are dropped first
+	maxDeleveragings = 1000
+
+	// insurancePrefix names the incident component of each settlement asset's fund
+	insurancePrefix = "insurance:"
+)
+
+// InsuranceBalance is the insurance fund's holding of one settlement asset
+type InsuranceBalance struct {
+	Asset     string  `json:"asset"`
+	Starting  float64 `json:"starting"`
+	Balance   float64 `json:"balance"`
+	Paid      float64 `json:"paid"`      // Liquidation shortfalls covered so far
+	Exhausted bool    `json:"exhausted"` // Liquidations close at their bankruptcy price and deleverage the rest
+}
+
+// Deleveraging is part of a profitable position closed against a bankrupt one at the
+// bankruptcy price, once the insurance fund could no longer absorb the loss
+type Deleveraging struct {
+	AccountID       string      `json:"account_id"` // The counterparty deleveraged
+	BankruptAccount string      `json:"bankrupt_account"`
+	Symbol          string      `json:"symbol"`
+	Side            models.Side `json:"side"` // The counterparty's closing side
+	Quantity        float64     `json:"quantity"`
+	Price           float64     `json:"price"` // The bankruptcy price
+	Rank            int         `json:"rank"`  // Place in the queue; 1 is deleveraged first
+	Score           float64     `json:"score"` // Profit ratio times leverage, or over it at a loss
+	OrderID         string      `json:"order_id"`
+	DeleveragedAt   time.Time   `json:"deleveraged_at"`
+}
+
+// InsuranceFundReport is the fund's balances and the positions deleveraged so far
+type InsuranceFundReport struct {
+	Balances      []InsuranceBalance `json:"balances"`
+	Deleveragings []Deleveraging     `json:"deleveragings"`
+}
+
+// insuranceCover is a shortfall the fund paid, kept so a rebuilt journal posts it again
+type insuranceCover struct {
+	AccountID string
+	Asset     string
+	Amount    float64
+	Reference string // The liquidation order
+	At        time.Time
+}
+
+// insuranceFund holds the fund's starting balances, the shortfalls it covered and
+// the deleveraging feed
+type insuranceFund struct {
+	starting      map[string]float64
+	covers        []insuranceCover
+	deleveragings []Deleveraging
+	mu            sync.Mutex
+}
+
+func newInsuranceFund() *insuranceFund {
+	return &insuranceFund{starting: make(map[string]float64), deleveragings: make([]Deleveraging, 0)}
+}
+
+// ParseInsuranceFund reads starting balances in the INSURANCE_FUND form
+// "asset=amount,...", e.g. "USD=1000000,USDT=500000"
+func ParseInsuranceFund(spec string) (map[string]float64, error) {
+	balances := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		asset, amount, ok := strings.Cut(entry, "=")
+		value, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+		if !ok || strings.TrimSpace(asset) == "" || err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid insurance fund balance %q: expected asset=amount", entry)
+		}
+		balances[strings.TrimSpace(asset)] = value
+	}
+	return balances, nil
+}
+
+// SetInsuranceFund credits the insurance fund's starting balance in each settlement
+// asset; set before serving. Assets without a balance start exhausted, so their
+// liquidations deleverage from the first shortfall.
+func (s *ExchangeService) SetInsuranceFund(balances map[string]float64) {
+	fund := s.insurance
+	fund.mu.Lock()
+	now := s.now()
+	for asset, amount := range balances {
+		fund.starting[asset] += amount
+		if amount > 0 {
+			s.balances.record(s.balances.journal.Fund(ledger.InsuranceFundAccount, asset, amount, now))
+		}
+	}
+	fund.mu.Unlock()
+	s.logger.WithField("balances", balances).Info("Insurance fund set")
+}
+
+// InsuranceFund reports the fund's balance per settlement asset and the positions
+// deleveraged once it ran out, optionally for one account on either side
+func (s *ExchangeService) InsuranceFund(ctx context.Context, accountID string) InsuranceFundReport {
+	fund := s.insurance
+	fund.mu.Lock()
+	defer fund.mu.Unlock()
+
+	paid := make(map[string]float64)
+	for _, cover := range fund.covers {
+		paid[cover.Asset] += cover.Amount
+	}
+	assets := make(map[string]bool)
+	for asset := range fund.starting {
+		assets[asset] = true
+	}
+	for asset := range paid {
+		assets[asset] = true
+	}
+	report := InsuranceFundReport{Balances: make([]InsuranceBalance, 0, len(assets)), Deleveragings: make([]Deleveraging, 0)}
+	for asset := range assets {
+		balance := s.balances.journal.Available(ledger.InsuranceFundAccount, asset)
+		report.Balances = append(report.Balances, InsuranceBalance{
+			Asset:     asset,
+			Starting:  fund.starting[asset],
+			Balance:   balance,
+			Paid:      paid[asset],
+			Exhausted: balance <= 1e-9,
+		})
+	}
+	sort.Slice(report.Balances, func(i, j int) bool { return report.Balances[i].Asset < report.Balances[j].Asset })
+	for _, deleveraging := range fund.deleveragings {
+		if accountID == "" || deleveraging.AccountID == accountID || deleveraging.BankruptAccount == accountID {
+			report.Deleveragings = append(report.Deleveragings, deleveraging)
+		}
+	}
+	return report
+}
+
+// insuranceExhausted reports whether the fund has nothing left in asset
+func (s *ExchangeService) insuranceExhausted(asset string) bool {
+	return s.balances.journal.Available(ledger.InsuranceFundAccount, asset) <= 1e-9
+}
+
+// bankruptcyPrice is where a position leaves its pool with no equity: the pool's
+// equity is shared across its positions by notional, and each position's share is
+// what the price can move against it from the mark
+func bankruptcyPrice(position margin.Position, pool margin.Summary) float64 {
+	if position.Quantity == 0 || pool.Notional <= 0 {
+		return position.MarkPrice
+	}
+	share := pool.Equity * position.Notional / pool.Notional
+	return math.Max(0, position.MarkPrice-share/position.Quantity)
+}
+
+// closingPrice rounds a bankruptcy price to the tick, never beyond it: up for a
+// sell closing a long, down for a buy closing a short
+func closingPrice(price float64, side models.Side, instrument models.Instrument) float64 {
+	if instrument.TickSize <= 0 {
+		return price
+	}
+	ticks := price / instrument.TickSize
+	if side == models.SideSell {
+		return math.Ceil(ticks-1e-9) * instrument.TickSize
+	}
+	return math.Floor(ticks+1e-9) * instrument.TickSize
+}
+
+// coverShortfall pays what a liquidation lost beyond its bankruptcy price out of the
+// insurance fund, as far as its balance goes, and returns the amount paid. A
+// shortfall the fund cannot cover stays with the account as negative equity.
+func (s *ExchangeService) coverShortfall(ctx context.Context, accountID, asset, reference string, shortfall float64, at time.Time) float64 {
+	if shortfall <= 0 {
+		return 0
+	}
+	fund := s.insurance
+	fund.mu.Lock()
+	defer fund.mu.Unlock()
+
+	covered := math.Min(shortfall, math.Max(0, s.balances.journal.Available(ledger.InsuranceFundAccount, asset)))
+	if covered > 0 {
+		postings, err := s.balances.journal.Post(ledger.KindInsurance, reference, at,
+			ledger.Leg{AccountID: ledger.InsuranceFundAccount, Asset: asset, Bucket: ledger.BucketAvailable, Amount: -covered},
+			ledger.Leg{AccountID: accountID, Asset: asset, Bucket: ledger.BucketAvailable, Amount: covered})
+		if err != nil {
+			s.logger.WithError(err).WithField("account", accountID).Error("Failed to post insurance cover")
+			return 0
+		}
+		s.balances.record(postings)
+		fund.covers = append(fund.covers, insuranceCover{AccountID: accountID, Asset: asset, Amount: covered, Reference: reference, At: at})
+	}
+	if s.insuranceExhausted(asset) {
+		s.ReportHealth(ctx, insurancePrefix+asset, incidents.StatusDegraded,
+			fmt.Sprintf("insurance fund exhausted; %g %s of shortfall uncovered, liquidations now deleverage", shortfall-covered, asset))
+	}
+	return covered
+}
+
+// deleverageCandidate is an opposing position queued for auto-deleveraging
+type deleverageCandidate struct {
+	accountID string
+	quantity  float64 // Contracts the counterparty can give up
+	score     float64
+}
+
+// autoDeleverage closes what is left of a bankrupt position against the opposing
+// positions at the bankruptcy price, the most profitable and most leveraged first,
+// and returns the quantity closed. Counterparties lose the profit beyond the
+// bankruptcy price instead of the venue.
+func (s *ExchangeService) autoDeleverage(ctx context.Context, accountID string, instrument models.Instrument, side models.Side, quantity, price float64, at time.Time) float64 {
+	queue := s.deleverageQueue(accountID, instrument, side)
+	closed := 0.0
+	for rank, candidate := range queue {
+		if quantity-closed <= 1e-12 {
+			break
+		}
+		size := math.Min(quantity-closed, candidate.quantity)
+		report, err := s.engine.Deleverage(instrument.Symbol, matching.Deleverage{
+			AccountID:    accountID,
+			Counterparty: candidate.accountID,
+			Side:         side,
+			Quantity:     size,
+			Price:        price,
+		})
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"account":      accountID,
+				"counterparty": candidate.accountID,
+				"symbol":       instrument.Symbol,
+			}).Error("Failed to deleverage position")
+			continue
+		}
+		closed += size
+
+		trade := report.Trades[0]
+		counterpartyOrder := trade.BuyOrderID
+		if side == models.SideBuy {
+			counterpartyOrder = trade.SellOrderID
+		}
+		s.publishDeleverage(ctx, report.Order, counterpartyOrder, trade, at)
+		s.recordDeleveraging(Deleveraging{
+			AccountID:       candidate.accountID,
+			BankruptAccount: accountID,
+			Symbol:          instrument.Symbol,
+			Side:            side.Opposite(),
+			Quantity:        size,
+			Price:           price,
+			Rank:            rank + 1,
+			Score:           candidate.score,
+			OrderID:         counterpartyOrder,
+			DeleveragedAt:   at,
+		})
+		s.logger.WithFields(logrus.Fields{
+			"account":      accountID,
+			"counterparty": candidate.accountID,
+			"symbol":       instrument.Symbol,
+			"quantity":     size,
+			"price":        price,
+			"rank":         rank + 1,
+		}).Warn("Position auto-deleveraged")
+	}
+	return closed
+}
+
+// deleverageQueue ranks the positions opposing a bankrupt one: by profit ratio
+// times leverage when in profit, and profit ratio over leverage when not, highest
+// first, then by account
+func (s *ExchangeService) deleverageQueue(accountID string, instrument models.Instrument, side models.Side) []deleverageCandidate {
+	holders := s.perpetualPositions()[instrument.Symbol]
+	queue := make([]deleverageCandidate, 0, len(holders))
+	for holder, quantity := range holders {
+		// The bankrupt account sells to close a long, so longs are not counterparties
+		if holder == accountID || quantity*side.Sign() <= 0 {
+			continue
+		}
+		// Accounts trading on credit have no equity of their own, so their leverage is
+		// the limit they trade at rather than what their pool shows
+		account, _ := s.accounts.directory.Get(holder)
+		leverage := effectiveLeverage(account.Leverage, instrument)
+		var position margin.Position
+		for _, pool := range s.marginPools(holder, s.engine.FilledOrders(holder)) {
+			for _, held := range pool.Positions {
+				if held.Symbol != instrument.Symbol {
+					continue
+				}
+				position = held
+				if s.isFunded(holder) && pool.Leverage > 0 {
+					leverage = pool.Leverage
+				}
+			}
+		}
+		ratio := 0.0
+		if entry := math.Abs(position.Quantity) * position.EntryPrice; entry > 0 {
+			ratio = position.UnrealizedPnL / entry
+		}
+		score := ratio * leverage
+		if ratio < 0 {
+			score = ratio / leverage
+		}
+		queue = append(queue, deleverageCandidate{accountID: holder, quantity: math.Abs(quantity), score: score})
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if queue[i].score != queue[j].score {
+			return queue[i].score > queue[j].score
+		}
+		return queue[i].accountID < queue[j].accountID
+	})
+	return queue
+}
+
+// publishDeleverage pushes both sides of a deleverage as order updates and books
+// the fill into positions. It stays off the public trade feed, tickers and candles:
+// the price was set by the venue, not the market.
+func (s *ExchangeService) publishDeleverage(ctx context.Context, bankrupt models.Order, counterpartyOrder string, trade models.Trade, at time.Time) {
+	s.publishOrder(bankrupt, at)
+	if order, err := s.engine.GetOrder(counterpartyOrder); err == nil {
+		s.publishOrder(order, at)
+	}
+	s.executions.AppendTrade(at, trade)
+	s.tapeTrade(trade)
+	s.positions.Fill(trade)
+	s.auditTrades(ctx, []models.Trade{trade})
+}
+
+func (s *ExchangeService) recordDeleveraging(deleveraging Deleveraging) {
+	fund := s.insurance
+	fund.mu.Lock()
+	defer fund.mu.Unlock()
+	fund.deleveragings = append(fund.deleveragings, deleveraging)
+	if len(fund.deleveragings) > maxDeleveragings {
+		fund.deleveragings = fund.deleveragings[len(fund.deleveragings)-maxDeleveragings:]
+	}
+}
+
+// reassignInsurance moves an erased account's covers and deleveragings to its alias
+func (s *ExchangeService) reassignInsurance(accountID, alias string) {
+	fund := s.insurance
+	fund.mu.Lock()
+	defer fund.mu.Unlock()
+	for i := range fund.covers {
+		if fund.covers[i].AccountID == accountID {
+			fund.covers[i].AccountID = alias
+		}
+	}
+	for i := range fund.deleveragings {
+		if fund.deleveragings[i].AccountID == accountID {
+			fund.deleveragings[i].AccountID = alias
+		}
+		if fund.deleveragings[i].BankruptAccount == accountID {
+			fund.deleveragings[i].BankruptAccount = alias
+		}
+	}
+}
+
+// postInsurance posts into a rebuilt journal the shortfalls the fund covered for
+// accountID, or for everyone along with the fund's starting balances when it is empty
+func (s *ExchangeService) postInsurance(journal *ledger.Journal, accountID string, at time.Time) []ledger.Posting {
+	fund := s.insurance
+	fund.mu.Lock()
+	defer fund.mu.Unlock()
+
+	postings := make([]ledger.Posting, 0)
+	if accountID == "" {
+		for asset, amount := range fund.starting {
+			if amount > 0 {
+				postings = append(postings, journal.Fund(ledger.InsuranceFundAccount, asset, amount, at)...)
+			}
+		}
+	}
+	for _, cover := range fund.covers {
+		if accountID != "" && cover.AccountID != accountID {
+			continue
+		}
+		posted, _ := journal.Post(ledger.KindInsurance, cover.Reference, cover.At,
+			ledger.Leg{AccountID: ledger.InsuranceFundAccount, Asset: cover.Asset, Bucket: ledger.BucketAvailable, Amount: -cover.Amount},
+			ledger.Leg{AccountID: cover.AccountID, Asset: cover.Asset, Bucket: ledger.BucketAvailable, Amount: cover.Amount})
+		postings = append(postings, posted...)
+	}
+	return postings
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+)
+
+// AccountLedger is an account's per-asset positions across every spot book
+type AccountLedger struct {
+	AccountID string            `json:"account_id"`
+	Positions []ledger.Position `json:"positions"`
+}
+
+// AccountLedger nets an account's opening balances and spot fills by asset, so a base asset bought against
+// one quote currency and sold against another offsets. Derivatives hold margined
+// exposure rather than the asset and are left out. Positions are derived from order
+// state, so they survive event log replay and follow an account purge to its alias.
+func (s *ExchangeService) AccountLedger(ctx context.Context, accountID string) (AccountLedger, error) {
+	if accountID == "" {
+		return AccountLedger{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return AccountLedger{}, err
+	}
+
+	book := ledger.New()
+	if account, err := s.accounts.directory.Get(accountID); err == nil {
+		for asset, amount := range account.Balances {
+			book.Fund(asset, amount)
+		}
+	}
+	for _, order := range s.engine.FilledOrders(accountID) {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || instrument.IsDerivative() {
+			continue
+		}
+		book.Post(order.Symbol, instrument.BaseAsset, instrument.QuoteAsset, order.Side,
+			order.FilledQuantity, order.FilledQuantity*order.AveragePrice)
+	}
+	return AccountLedger{AccountID: accountID, Positions: s.misreportPositions(ctx, accountID, book.Positions())}, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+)
+
+// log returns the service's logger tagged with the request, correlation, account and
+// order IDs ctx carries, so entries about one order can be followed across components
+func (s *ExchangeService) log(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+	for name, value := range logfields.Fields(ctx) {
+		fields[name] = value
+	}
+	return s.logger.WithFields(fields)
+}
+
+// engineLog is log for the order flow through the matching engine, which may be
+// logged at its own level and sampled
+func (s *ExchangeService) engineLog(ctx context.Context) *logrus.Entry {
+	return s.log(ctx).WithField(logfields.Component, logfields.ComponentMatchingEngine)
+}
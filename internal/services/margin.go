@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// maxLiquidations bounds the liquidation feed; the oldest are dropped first
+	maxLiquidations = 1000
+
+	// liquidationRetry is how much venue time passes before a pool still breached
+	// after its close-out orders is offered to the book again
+	liquidationRetry = time.Second
+)
+
+// AccountMargin is an account's derivative positions margined against its balances,
+// one pool per settlement (quote) asset
+type AccountMargin struct {
+	AccountID string           `json:"account_id"`
+	Margined  bool             `json:"margined"` // Accounts without opening balances trade on credit and are never liquidated
+	Leverage  float64          `json:"leverage"` // The account's limit; 0 = each instrument's maximum
+	Pools     []margin.Summary `json:"pools"`
+}
+
+// Liquidation is a position the venue closed out after its pool fell below
+// maintenance margin
+type Liquidation struct {
+	AccountID         string      `json:"account_id"`
+	Symbol            string      `json:"symbol"`
+	OrderID           string      `json:"order_id"`
+	Side              models.Side `json:"side"`
+	Quantity          float64     `json:"quantity"` // The position offered to the book
+	FilledQuantity    float64     `json:"filled_quantity"`
+	AveragePrice      float64     `json:"average_price"`
+	MarkPrice         float64     `json:"mark_price"`
+	Equity            float64     `json:"equity"` // Of the pool when it breached
+	MaintenanceMargin float64     `json:"maintenance_margin"`
+	BankruptcyPrice   float64     `json:"bankruptcy_price"` // Where the position's share of the pool's equity runs out
+	Shortfall         float64     `json:"shortfall"`        // Lost on the fills beyond the bankruptcy price
+	InsuranceCovered  float64     `json:"insurance_covered"`
+	Deleveraged       float64     `json:"deleveraged"` // Quantity closed against opposing positions at the bankruptcy price
+	LiquidatedAt      time.Time   `json:"liquidated_at"`
+}
+
+// liquidationLog holds published liquidations and when each pool was last closed out
+type liquidationLog struct {
+	events []Liquidation
+	last   map[string]time.Time // Keyed by account and pool asset
+	mu     sync.Mutex
+}
+
+func newLiquidationLog() *liquidationLog {
+	return &liquidationLog{events: make([]Liquidation, 0), last: make(map[string]time.Time)}
+}
+
+// AccountMargin reports an account's derivative positions, equity, borrowing and
+// margin ratio per settlement asset
+func (s *ExchangeService) AccountMargin(ctx context.Context, accountID string) (AccountMargin, error) {
+	if accountID == "" {
+		return AccountMargin{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+		return AccountMargin{}, err
+	}
+	account, _ := s.accounts.directory.Get(accountID)
+	return AccountMargin{
+		AccountID: accountID,
+		Margined:  s.isFunded(accountID),
+		Leverage:  account.Leverage,
+		Pools:     s.marginPools(accountID, s.engine.FilledOrders(accountID)),
+	}, nil
+}
+
+// Liquidations returns published liquidations oldest first, optionally for one account
+func (s *ExchangeService) Liquidations(ctx context.Context, accountID string) []Liquidation {
+	s.liquidations.mu.Lock()
+	defer s.liquidations.mu.Unlock()
+
+	events := make([]Liquidation, 0)
+	for _, event := range s.liquidations.events {
+		if accountID == "" || event.AccountID == accountID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// marginPools books an account's derivative fills into positions and margins them
+// against its available balance in each quote asset. Unfunded accounts have no
+// collateral.
+func (s *ExchangeService) marginPools(accountID string, filled []models.Order) []margin.Summary {
+	book := margin.NewBook()
+	for _, order := range filled {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || !instrument.IsDerivative() || order.AccountID != accountID {
+			continue
+		}
+		book.Fill(order.Symbol, order.Side, order.FilledQuantity, order.AveragePrice)
+	}
+
+	account, _ := s.accounts.directory.Get(accountID)
+	funded := s.isFunded(accountID)
+	byAsset := make(map[string][]margin.Position)
+	for _, position := range book.Positions() {
+		instrument, _ := s.instruments.Get(position.Symbol)
+		position.Value(s.markOrReference(instrument), effectiveLeverage(account.Leverage, instrument), instrument.MaintenanceMarginRate)
+		byAsset[instrument.QuoteAsset] = append(byAsset[instrument.QuoteAsset], position)
+	}
+
+	pools := make([]margin.Summary, 0, len(byAsset))
+	for asset, positions := range byAsset {
+		collateral := 0.0
+		if funded {
+			collateral = s.balances.journal.Available(accountID, asset)
+		}
+		pools = append(pools, margin.Summarize(asset, collateral, positions))
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Asset < pools[j].Asset })
+	return pools
+}
+
+// markOrReference is the instrument's mark price, or its reference price before any
+func (s *ExchangeService) markOrReference(instrument models.Instrument) float64 {
+	if mark, err := s.markPrice(instrument.Symbol); err == nil && mark > 0 {
+		return mark
+	}
+	return instrument.ReferencePrice
+}
+
+// effectiveLeverage caps an account's leverage limit at the instrument's maximum
+func effectiveLeverage(limit float64, instrument models.Instrument) float64 {
+	maximum := instrument.MaxLeverage()
+	if limit <= 0 || limit > maximum {
+		return maximum
+	}
+	return limit
+}
+
+// checkOrderMargin refuses a derivative order whose initial margin, at the account's
+// leverage, exceeds the free margin of its pool. Orders that only reduce a position
+// are always accepted, so an account can trade out of a breach. An amendment names
+// the order it replaces, whose margin is no longer committed.
+func (s *ExchangeService) checkOrderMargin(req OrderRequest, instrument models.Instrument, replacing string) error {
+	pools := s.marginPools(req.AccountID, s.engine.FilledOrders(req.AccountID))
+	pool := margin.Summarize(instrument.QuoteAsset, s.balances.journal.Available(req.AccountID, instrument.QuoteAsset), nil)
+	for _, candidate := range pools {
+		if candidate.Asset == instrument.QuoteAsset {
+			pool = candidate
+		}
+	}
+	for _, position := range pool.Positions {
+		if position.Symbol == req.Symbol && position.Quantity*req.Side.Sign() < 0 && req.Quantity <= math.Abs(position.Quantity)+1e-12 {
+			return nil
+		}
+	}
+
+	account, _ := s.accounts.directory.Get(req.AccountID)
+	committed := 0.0
+	for _, order := range s.engine.OpenOrders(req.AccountID, "") {
+		working, err := s.instruments.Get(order.Symbol)
+		if err != nil || !working.IsDerivative() || working.QuoteAsset != instrument.QuoteAsset || order.ID == replacing {
+			continue
+		}
+		committed += order.RemainingQuantity() * order.Price / effectiveLeverage(account.Leverage, working)
+	}
+
+	price := req.Price
+	if req.Type == models.OrderTypeMarket {
+		price = s.markOrReference(instrument)
+	}
+	leverage := effectiveLeverage(account.Leverage, instrument)
+	required := req.Quantity * price / leverage
+	if free := pool.FreeMargin(committed); required > free+1e-9 {
+		return rejectf(RejectInsufficientMargin, "order needs %g %s of margin at %gx but %g is free", required, instrument.QuoteAsset, leverage, math.Max(free, 0))
+	}
+	return nil
+}
+
+// liquidate closes out every pool of a funded, active account whose equity has
+// fallen below its maintenance margin: the account's working derivative orders in
+// the pool are canceled, then each position is offered to the book at market.
+// Fills land like any other order's; a pool left breached is retried after
+// liquidationRetry.
+func (s *ExchangeService) liquidate(ctx context.Context) int {
+	byAccount := make(map[string][]models.Order)
+	for _, order := range s.engine.FilledOrders("") {
+		if instrument, err := s.instruments.Get(order.Symbol); err == nil && instrument.IsDerivative() {
+			byAccount[order.AccountID] = append(byAccount[order.AccountID], order)
+		}
+	}
+
+	liquidated := 0
+	now := s.now()
+	for accountID, filled := range byAccount {
+		if !s.isFunded(accountID) || s.accounts.directory.IsClosed(accountID) {
+			continue
+		}
+		for _, pool := range s.marginPools(accountID, filled) {
+			if !pool.Breached || !s.claimLiquidation(accountID, pool.Asset, now) {
+				continue
+			}
+			liquidated += s.closeOut(ctx, accountID, pool, now)
+		}
+	}
+	return liquidated
+}
+
+// claimLiquidation reports whether a pool is due to be closed out, marking it if so
+func (s *ExchangeService) claimLiquidation(accountID, asset string, now time.Time) bool {
+	log := s.liquidations
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	key := accountID + "/" + asset
+	if last, ok := log.last[key]; ok && now.Sub(last) < liquidationRetry {
+		return false
+	}
+	log.last[key] = now
+	return true
+}
+
+// closeOut cancels a breached pool's working orders and sends its positions to market.
+// The insurance fund pays whatever a fill loses beyond the bankruptcy price. Once the
+// fund is exhausted, positions are offered only down to the bankruptcy price and the
+// rest is deleveraged against opposing positions.
+func (s *ExchangeService) closeOut(ctx context.Context, accountID string, pool margin.Summary, now time.Time) int {
+	for _, order := range s.engine.OpenOrders(accountID, "") {
+		instrument, err := s.instruments.Get(order.Symbol)
+		if err != nil || !instrument.IsDerivative() || instrument.QuoteAsset != pool.Asset {
+			continue
+		}
+		if canceled, err := s.engine.Cancel(order.ID); err == nil {
+			s.auditOrder(ctx, audit.TypeOrderCanceled, canceled, cancelLiquidation)
+			s.publishActivity(canceled.Symbol, []models.Order{canceled}, nil)
+		}
+	}
+
+	closed := 0
+	for _, position := range pool.Positions {
+		side := models.SideSell
+		if position.Quantity < 0 {
+			side = models.SideBuy
+		}
+		quantity := math.Abs(position.Quantity)
+		instrument, _ := s.instruments.Get(position.Symbol)
+		bankruptcy := bankruptcyPrice(position, pool)
+		closing := closingPrice(bankruptcy, side, instrument)
+		req := OrderRequest{
+			AccountID: accountID,
+			Symbol:    position.Symbol,
+			Side:      side,
+			Type:      models.OrderTypeMarket,
+			Quantity:  quantity,
+		}
+		exhausted := s.insuranceExhausted(pool.Asset)
+		if exhausted && closing > 0 {
+			req.Type, req.TimeInForce, req.Price = models.OrderTypeLimit, models.TimeInForceIOC, closing
+		}
+		report, err := s.PlaceOrder(ctx, req)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"account": accountID,
+				"symbol":  position.Symbol,
+			}).Error("Failed to liquidate position")
+			continue
+		}
+
+		event := Liquidation{
+			AccountID:         accountID,
+			Symbol:            position.Symbol,
+			OrderID:           report.Order.ID,
+			Side:              side,
+			Quantity:          quantity,
+			FilledQuantity:    report.Order.FilledQuantity,
+			AveragePrice:      report.Order.AveragePrice,
+			MarkPrice:         position.MarkPrice,
+			Equity:            pool.Equity,
+			MaintenanceMargin: pool.MaintenanceMargin,
+			BankruptcyPrice:   bankruptcy,
+			LiquidatedAt:      now,
+		}
+		// A long sold below, or a short bought above, the bankruptcy price loses more than the pool holds
+		event.Shortfall = math.Max(0, (bankruptcy-event.AveragePrice)*side.Opposite().Sign()*event.FilledQuantity)
+		event.InsuranceCovered = s.coverShortfall(ctx, accountID, pool.Asset, event.OrderID, event.Shortfall, now)
+		if remaining := quantity - event.FilledQuantity; remaining > 1e-12 && s.insuranceExhausted(pool.Asset) && closing > 0 {
+			event.Deleveraged = s.autoDeleverage(ctx, accountID, instrument, side, remaining, closing, now)
+		}
+		s.recordLiquidation(event)
+		closed++
+
+		s.logger.WithFields(logrus.Fields{
+			"account":            accountID,
+			"symbol":             position.Symbol,
+			"order_id":           event.OrderID,
+			"quantity":           quantity,
+			"filled":             event.FilledQuantity,
+			"bankruptcy_price":   bankruptcy,
+			"shortfall":          event.Shortfall,
+			"insurance_covered":  event.InsuranceCovered,
+			"deleveraged":        event.Deleveraged,
+			"equity":             pool.Equity,
+			"maintenance_margin": pool.MaintenanceMargin,
+		}).Warn("Position liquidated")
+	}
+	return closed
+}
+
+func (s *ExchangeService) recordLiquidation(event Liquidation) {
+	log := s.liquidations
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.events = append(log.events, event)
+	if len(log.events) > maxLiquidations {
+		log.events = log.events[len(log.events)-maxLiquidations:]
+	}
+}
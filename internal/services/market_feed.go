@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/feed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+const (
+	// feedBufferSize is how many messages a subscriber may fall behind before it is dropped
+	feedBufferSize = 1024
+	// feedSnapshotDepth bounds the levels per side on the book_snapshot channel
+	feedSnapshotDepth = 20
+	// executionJournalSize is how many order updates and trades a reconnecting
+	// execution stream can resume across
+	executionJournalSize = 10000
+)
+
+// bookFeed remembers the last book published per symbol, so each change goes out
+// as a delta against what subscribers already hold
+type bookFeed struct {
+	books     map[string]matching.BookSnapshot // Dropped while a symbol has no book subscribers
+	sequences map[string]uint64
+	mu        sync.Mutex
+}
+
+func newBookFeed() *bookFeed {
+	return &bookFeed{
+		books:     make(map[string]matching.BookSnapshot),
+		sequences: make(map[string]uint64),
+	}
+}
+
+// topOfBook is the best level on each side as a ticker reports it
+type topOfBook struct {
+	bidPrice, bidQuantity, askPrice, askQuantity float64
+}
+
+// tickerFeed remembers the top of book last pushed per symbol, so the ticker
+// channel updates when quotes move as well as after trades
+type tickerFeed struct {
+	tops map[string]topOfBook
+	mu   sync.Mutex
+}
+
+func newTickerFeed() *tickerFeed {
+	return &tickerFeed{tops: make(map[string]topOfBook)}
+}
+
+// Feed returns the hub that pushes market data and order updates to stream clients
+func (s *ExchangeService) Feed() *feed.Hub {
+	return s.feed
+}
+
+// Executions returns the journal behind the gRPC order update and trade streams
+func (s *ExchangeService) Executions() *feed.Journal {
+	return s.executions
+}
+
+// CloseFeeds ends every WebSocket and execution stream subscription
+func (s *ExchangeService) CloseFeeds() {
+	s.feed.Close()
+	s.executions.Close()
+}
+
+// SubscribeFeed adds a topic to a stream subscriber after checking the symbol is
+// listed. Book, ticker and exchange stats topics start with a snapshot of the current
+// state.
+func (s *ExchangeService) SubscribeFeed(ctx context.Context, sub *feed.Subscriber, topic feed.Topic) error {
+	if topic.Channel == feed.ChannelOrders {
+		if topic.Key == "" {
+			return rejectf(RejectInvalidAccount, "account id is required for the orders channel")
+		}
+		sub.Add(topic)
+		return nil
+	}
+	if topic.Channel == feed.ChannelExchangeStats {
+		sub.Add(topic)
+		sub.Deliver(feed.Message{
+			Type:    feed.MessageSnapshot,
+			Channel: topic.Channel,
+			Time:    s.now(),
+			Data:    s.ExchangeStatistics(ctx),
+		})
+		return nil
+	}
+	if _, err := s.instruments.Get(topic.Key); err != nil {
+		return err
+	}
+
+	switch topic.Channel {
+	case feed.ChannelBook, feed.ChannelBookSnapshot:
+		books := s.bookFeed
+		books.mu.Lock()
+		defer books.mu.Unlock()
+
+		book, sequence, err := s.syncBook(topic.Key)
+		if err != nil {
+			return err
+		}
+		// Subscribing under the lock keeps the snapshot ahead of the next delta
+		sub.Add(topic)
+		data := book
+		if topic.Channel == feed.ChannelBookSnapshot {
+			data = truncateBook(book, feedSnapshotDepth)
+		}
+		sub.Deliver(feed.Message{
+			Type:     feed.MessageSnapshot,
+			Channel:  topic.Channel,
+			Key:      topic.Key,
+			Sequence: sequence,
+			Time:     s.now(),
+			Data:     data,
+		})
+
+	case feed.ChannelTicker:
+		tickers := s.tickerFeed
+		tickers.mu.Lock()
+		defer tickers.mu.Unlock()
+
+		ticker := s.ticker(topic.Key)
+		tickers.tops[topic.Key] = topOf(ticker)
+		sub.Add(topic)
+		sub.Deliver(feed.Message{
+			Type:    feed.MessageSnapshot,
+			Channel: topic.Channel,
+			Key:     topic.Key,
+			Time:    s.now(),
+			Data:    ticker,
+		})
+
+	default:
+		sub.Add(topic)
+	}
+	return nil
+}
+
+// publishActivity pushes what an order action changed: the orders it touched, the
+// trades it printed with the ticker they moved, and the resulting book
+func (s *ExchangeService) publishActivity(symbol string, orders []models.Order, trades []models.Trade) {
+	now := s.now()
+	// Trades settle before holds are synced, so a fill is taken from the hold it used
+	s.settleTrades(trades)
+	published := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		s.syncHold(order)
+		s.publishOrder(order, now)
+		published[order.ID] = true
+	}
+
+	for _, trade := range trades {
+		s.executions.AppendTrade(now, trade)
+		s.recordTradeEvent(trade)
+		s.positions.Fill(trade)
+		if !s.dropMarketData(trade.Symbol) {
+			s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: feed.ChannelTrades, Key: trade.Symbol, Time: now, Data: feed.NewPublicTrade(trade, s.publicID)})
+		}
+		// Resting orders filled by the trade are updates for their accounts too
+		for _, orderID := range []string{trade.BuyOrderID, trade.SellOrderID} {
+			if published[orderID] {
+				continue
+			}
+			if order, err := s.engine.GetOrder(orderID); err == nil {
+				s.syncHold(order)
+				s.publishOrder(order, now)
+				published[orderID] = true
+			}
+		}
+	}
+	s.publishTicker(symbol, len(trades) > 0, now)
+	s.publishBook(symbol)
+}
+
+// publishTicker pushes a symbol's ticker after a trade, or when its top of book moved
+func (s *ExchangeService) publishTicker(symbol string, traded bool, now time.Time) {
+	topic := feed.Topic{Channel: feed.ChannelTicker, Key: symbol}
+	if !s.feed.Subscribed(topic) {
+		return
+	}
+	tickers := s.tickerFeed
+	tickers.mu.Lock()
+	defer tickers.mu.Unlock()
+
+	ticker := s.ticker(symbol)
+	top := topOf(ticker)
+	if !traded && tickers.tops[symbol] == top {
+		return
+	}
+	tickers.tops[symbol] = top
+	if s.dropMarketData(symbol) {
+		return
+	}
+	s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: topic.Channel, Key: symbol, Time: now, Data: ticker})
+}
+
+// ticker is a symbol's 24h statistics with the engine's current top of book
+func (s *ExchangeService) ticker(symbol string) marketdata.Ticker {
+	if frozen, ok := s.frozenTicker(symbol); ok {
+		return frozen
+	}
+	ticker := s.statistics.Ticker(symbol)
+	book, err := s.engine.Snapshot(symbol, 1)
+	if err != nil {
+		return ticker
+	}
+	if len(book.Bids) > 0 {
+		ticker.BidPrice, ticker.BidQuantity = book.Bids[0].Price, book.Bids[0].Quantity
+	}
+	if len(book.Asks) > 0 {
+		ticker.AskPrice, ticker.AskQuantity = book.Asks[0].Price, book.Asks[0].Quantity
+	}
+	return ticker
+}
+
+func topOf(ticker marketdata.Ticker) topOfBook {
+	return topOfBook{ticker.BidPrice, ticker.BidQuantity, ticker.AskPrice, ticker.AskQuantity}
+}
+
+// publishOrder journals an order update and pushes it to the account's stream
+// clients, as misbehaviour faults falsify it
+func (s *ExchangeService) publishOrder(order models.Order, now time.Time) {
+	s.recordOrderEvent(order)
+	s.journalOrder(order)
+	told := s.overfill(context.Background(), order)
+	sends := 1
+	if s.duplicateReport(order) {
+		sends = 2
+	}
+	for i := 0; i < sends; i++ {
+		s.executions.AppendOrder(now, told)
+		s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: feed.ChannelOrders, Key: order.AccountID, Time: now, Data: told})
+	}
+}
+
+// publishBook pushes a symbol's book as a delta and as a top-of-book snapshot if it
+// changed. Symbols nobody watches are not tracked.
+func (s *ExchangeService) publishBook(symbol string) {
+	deltas := feed.Topic{Channel: feed.ChannelBook, Key: symbol}
+	snapshots := feed.Topic{Channel: feed.ChannelBookSnapshot, Key: symbol}
+
+	books := s.bookFeed
+	books.mu.Lock()
+	defer books.mu.Unlock()
+
+	if !s.feed.Subscribed(deltas) && !s.feed.Subscribed(snapshots) {
+		delete(books.books, symbol)
+		return
+	}
+	s.syncBook(symbol)
+}
+
+// syncBook brings the tracked book up to the engine's, publishing any change as the
+// next delta, and returns it with its sequence; callers hold books.mu. Once tracked,
+// the book is exactly what applying every delta up to its sequence yields.
+func (s *ExchangeService) syncBook(symbol string) (matching.BookSnapshot, uint64, error) {
+	books := s.bookFeed
+	next, err := s.engine.Snapshot(symbol, 0)
+	if err != nil {
+		return matching.BookSnapshot{}, 0, err
+	}
+	prev, tracked := books.books[symbol]
+	if !tracked {
+		// The book may have changed unpublished while untracked; moving the sequence
+		// on keeps it naming one book state
+		if _, seen := books.sequences[symbol]; seen {
+			books.sequences[symbol]++
+		} else {
+			books.sequences[symbol] = 0
+		}
+		books.books[symbol] = next
+		return next, books.sequences[symbol], nil
+	}
+	delta := feed.DiffBook(prev, next)
+	if delta.Empty() && prev.Phase == next.Phase {
+		return prev, books.sequences[symbol], nil
+	}
+	books.books[symbol] = next
+	books.sequences[symbol]++
+	sequence := books.sequences[symbol]
+
+	// A dropped update still takes its sequence, so consumers see the gap
+	if s.dropMarketData(symbol) {
+		return next, sequence, nil
+	}
+	now := s.now()
+	s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: feed.ChannelBook, Key: symbol, Sequence: sequence, Time: now, Data: delta})
+	s.feed.Publish(feed.Message{Type: feed.MessageUpdate, Channel: feed.ChannelBookSnapshot, Key: symbol, Sequence: sequence, Time: now, Data: truncateBook(next, feedSnapshotDepth)})
+	return next, sequence, nil
+}
+
+// OrderBookSnapshot is a book as of a sequence on the book channel: applying that
+// channel's deltas numbered above Sequence keeps it current
+type OrderBookSnapshot struct {
+	matching.BookSnapshot
+	Sequence uint64 `json:"sequence"`
+}
+
+// truncateBook keeps the best depth levels per side; zero keeps all
+func truncateBook(book matching.BookSnapshot, depth int) matching.BookSnapshot {
+	if depth <= 0 {
+		return book
+	}
+	if len(book.Bids) > depth {
+		book.Bids = book.Bids[:depth]
+	}
+	if len(book.Asks) > depth {
+		book.Asks = book.Asks[:depth]
+	}
+	return book
+}
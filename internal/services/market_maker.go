@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/liquidity"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// MarketMakerQuote is the ladder the market maker keeps resting on one symbol
+type MarketMakerQuote struct {
+	Symbol string           `json:"symbol"`
+	Ladder liquidity.Ladder `json:"ladder"`
+}
+
+// MarketMakerConfig is the background liquidity one account keeps resting
+type MarketMakerConfig struct {
+	Account    string             `json:"account"`
+	Quotes     []MarketMakerQuote `json:"quotes"`
+	RequoteBps float64            `json:"requote_bps"` // Mark price move that moves the ladder; smaller moves keep queue position
+}
+
+// MarketMakerStatus is one symbol's quoting state
+type MarketMakerStatus struct {
+	Symbol      string           `json:"symbol"`
+	Account     string           `json:"account"`
+	Reference   float64          `json:"reference"` // Price the ladder is laid around
+	Ladder      liquidity.Ladder `json:"ladder"`
+	Resting     int              `json:"resting"`     // Quotes on the book after the last refresh
+	Replenished int              `json:"replenished"` // Quotes replaced after filling
+	Requoted    int              `json:"requoted"`    // Quotes moved after the reference did
+	LastRefresh time.Time        `json:"last_refresh"`
+}
+
+// marketMaker keeps a ladder resting around each symbol's mark price, anchored to
+// the external price feed while it is fresh
+type marketMaker struct {
+	config MarketMakerConfig
+	books  map[string]*makerBook
+	mu     sync.Mutex // Held for a whole refresh
+}
+
+type makerBook struct {
+	ladder      liquidity.Ladder
+	resting     map[string]liquidity.Quote // As placed, by order ID
+	reference   float64
+	replenished int
+	requoted    int
+	lastRefresh time.Time
+}
+
+// EnableMarketMaker quotes each ladder from the configured account. Quotes rest
+// from the first refresh.
+func (s *ExchangeService) EnableMarketMaker(cfg MarketMakerConfig) error {
+	if cfg.Account == "" {
+		return rejectf(RejectInvalidAccount, "market maker account is required")
+	}
+	if len(cfg.Quotes) == 0 {
+		return rejectf(RejectInvalidRequest, "market maker needs at least one symbol")
+	}
+	if cfg.RequoteBps < 0 {
+		return rejectf(RejectInvalidRequest, "requote threshold must not be negative")
+	}
+	books := make(map[string]*makerBook, len(cfg.Quotes))
+	for _, quote := range cfg.Quotes {
+		if _, err := s.instruments.Get(quote.Symbol); err != nil {
+			return err
+		}
+		if _, exists := books[quote.Symbol]; exists {
+			return rejectf(RejectInvalidRequest, "market maker quotes %s twice", quote.Symbol)
+		}
+		if err := quote.Ladder.Validate(); err != nil {
+			return rejectf(RejectInvalidRequest, "invalid market maker ladder for %s: %v", quote.Symbol, err)
+		}
+		books[quote.Symbol] = &makerBook{ladder: quote.Ladder, resting: make(map[string]liquidity.Quote)}
+	}
+	s.marketMaker = &marketMaker{config: cfg, books: books}
+	return nil
+}
+
+// RefreshMarketMaker tops each ladder back up around the symbol's mark price:
+// quotes that filled, fully or in part, are replaced, the ladder moves once the mark
+// moves further than the requote threshold, and quotes still wanted keep their
+// queue position
+func (s *ExchangeService) RefreshMarketMaker(ctx context.Context) {
+	maker := s.marketMaker
+	if maker == nil {
+		return
+	}
+	maker.mu.Lock()
+	defer maker.mu.Unlock()
+
+	now := s.now()
+	for _, symbol := range maker.symbols() {
+		book := maker.books[symbol]
+		mark, err := s.markPrice(symbol)
+		if err != nil || mark <= 0 {
+			continue
+		}
+		if book.reference == 0 || math.Abs(mark-book.reference)/book.reference*10000 > maker.config.RequoteBps {
+			book.reference = mark
+		}
+		book.lastRefresh = now
+		wanted := book.ladder.Quotes(book.reference, s.mustInstrument(symbol))
+
+		for orderID, placed := range book.resting {
+			order, err := s.GetOrder(ctx, orderID)
+			switch {
+			case err != nil || order.Status.IsTerminal():
+				if order.Status == models.OrderStatusFilled {
+					book.replenished++
+				}
+				delete(book.resting, orderID)
+				continue
+			case order.FilledQuantity > 0:
+				book.replenished++
+			case takeQuote(&wanted, placed):
+				continue
+			default:
+				book.requoted++
+			}
+			s.CancelOrder(ctx, orderID)
+			delete(book.resting, orderID)
+		}
+
+		for _, quote := range wanted {
+			report, err := s.PlaceOrder(ctx, OrderRequest{
+				AccountID:   maker.config.Account,
+				Symbol:      symbol,
+				Side:        quote.Side,
+				Type:        models.OrderTypeLimit,
+				TimeInForce: models.TimeInForceGTC,
+				Quantity:    quote.Quantity,
+				Price:       quote.Price,
+			})
+			if err != nil {
+				s.logger.WithError(err).WithFields(logrus.Fields{"symbol": symbol, "side": quote.Side}).Debug("Market maker quote refused")
+				continue
+			}
+			if !report.Order.Status.IsTerminal() {
+				book.resting[report.Order.ID] = quote
+			}
+		}
+	}
+}
+
+// RunMarketMaker refreshes the market maker's quotes every interval until ctx is done
+func (s *ExchangeService) RunMarketMaker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RefreshMarketMaker(ctx)
+		}
+	}
+}
+
+// MarketMaker reports each quoted symbol, or nothing when the market maker is off
+func (s *ExchangeService) MarketMaker(ctx context.Context) []MarketMakerStatus {
+	maker := s.marketMaker
+	if maker == nil {
+		return []MarketMakerStatus{}
+	}
+	maker.mu.Lock()
+	defer maker.mu.Unlock()
+
+	statuses := make([]MarketMakerStatus, 0, len(maker.books))
+	for _, symbol := range maker.symbols() {
+		book := maker.books[symbol]
+		statuses = append(statuses, MarketMakerStatus{
+			Symbol:      symbol,
+			Account:     maker.config.Account,
+			Reference:   book.reference,
+			Ladder:      book.ladder,
+			Resting:     len(book.resting),
+			Replenished: book.replenished,
+			Requoted:    book.requoted,
+			LastRefresh: book.lastRefresh,
+		})
+	}
+	return statuses
+}
+
+// rescaleMarketMaker quotes symbol's configured ladder with its level size scaled by
+// scale and, when spreadBps is set, at that spread; 1 and 0 restore the configured
+// ladder. Quotes move on the next refresh.
+func (s *ExchangeService) rescaleMarketMaker(symbol string, scale, spreadBps float64) error {
+	maker := s.marketMaker
+	if maker == nil {
+		return rejectf(RejectInvalidRequest, "the market maker is not enabled")
+	}
+	maker.mu.Lock()
+	defer maker.mu.Unlock()
+
+	book, ok := maker.books[symbol]
+	if !ok {
+		return rejectf(RejectInvalidRequest, "the market maker does not quote %s", symbol)
+	}
+	for _, quote := range maker.config.Quotes {
+		if quote.Symbol != symbol {
+			continue
+		}
+		ladder := quote.Ladder
+		ladder.LevelNotional *= scale
+		ladder.LevelQuantity *= scale
+		if spreadBps > 0 {
+			ladder.SpreadBps = spreadBps
+		}
+		book.ladder = ladder
+	}
+	return nil
+}
+
+func (m *marketMaker) symbols() []string {
+	symbols := make([]string, 0, len(m.books))
+	for symbol := range m.books {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// takeQuote removes placed from wanted, reporting whether the ladder still wants it
+func takeQuote(wanted *[]liquidity.Quote, placed liquidity.Quote) bool {
+	for i, quote := range *wanted {
+		if quote.Side == placed.Side && math.Abs(quote.Price-placed.Price) < 1e-9 && math.Abs(quote.Quantity-placed.Quantity) < 1e-9 {
+			*wanted = append((*wanted)[:i], (*wanted)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMarketMakerQuotes reads ladders in the MARKET_MAKER_QUOTES form
+// "symbol=spread_bps:quantity[:levels[:step_bps]],...", e.g. "BTC-USD=10:0.5:3:5,ETH-USD=20:5".
+// Quantity is base units per level; one level is quoted unless levels are given.
+func ParseMarketMakerQuotes(spec string) ([]MarketMakerQuote, error) {
+	quotes := make([]MarketMakerQuote, 0)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		symbol, settings, ok := strings.Cut(entry, "=")
+		fields := strings.Split(settings, ":")
+		if !ok || strings.TrimSpace(symbol) == "" || len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("invalid market maker quote %q: expected symbol=spread_bps:quantity[:levels[:step_bps]]", entry)
+		}
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number in market maker quote %q: %w", entry, err)
+			}
+			values[i] = value
+		}
+		ladder := liquidity.Ladder{SpreadBps: values[0], LevelQuantity: values[1], Levels: 1}
+		if len(values) > 2 {
+			ladder.Levels = int(values[2])
+		}
+		if len(values) > 3 {
+			ladder.StepBps = values[3]
+		}
+		quotes = append(quotes, MarketMakerQuote{Symbol: strings.TrimSpace(symbol), Ladder: ladder})
+	}
+	return quotes, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// Market health metrics reported through the MetricsPort, by symbol:
+// - exchange_orders_placed_total{symbol,side}: Orders accepted (counter)
+// - exchange_orders_canceled_total{symbol}: Orders canceled on request (counter)
+// - exchange_orders_rejected_total{symbol,reason}: Orders refused, by reject reason (counter)
+// - exchange_trades_total{symbol}: Trades executed (counter)
+// - exchange_trade_volume_total{symbol}: Base quantity traded (counter)
+// - exchange_trade_notional_total{symbol}: Quote value traded (counter)
+// - exchange_fill_rate{symbol}: Share of the quantity ordered since startup that has filled (gauge)
+// - exchange_order_to_trade_latency_seconds{symbol}: Time from an order's arrival to each of its fills (histogram, 1ms to 70m)
+// - exchange_trade_notional{symbol}: Quote value of each trade (histogram, 10 to 10M)
+// - exchange_open_orders{symbol}: Orders resting in the book (gauge)
+// - exchange_book_depth{symbol,side}: Quantity resting in the book (gauge)
+// - exchange_spread{symbol}: Best ask minus best bid; NaN while either side is empty (gauge)
+// - exchange_engine_queue_depth{symbol}: Commands waiting on the book's goroutine (histogram, 1 to 1024)
+//
+// The book gauges and queue depths are sampled by the scheduler rather than on
+// every order.
+
+// marketHistogramBuckets are the buckets of the market histograms, none of which
+// suit the default request duration buckets
+var marketHistogramBuckets = map[string][]float64{
+	"exchange_order_to_trade_latency_seconds": ports.ExponentialBuckets(0.001, 4, 12),
+	"exchange_trade_notional":                 ports.ExponentialBuckets(10, 10, 7),
+	"exchange_engine_queue_depth":             ports.ExponentialBuckets(1, 2, 11),
+}
+
+// marketMetrics keeps the running totals the fill rate is derived from
+type marketMetrics struct {
+	mu         sync.Mutex
+	ordered    map[string]float64 // Quantity of accepted orders by symbol
+	filled     map[string]float64 // Quantity of those orders filled, both sides of each trade
+	configured ports.MetricsPort  // Port the histogram buckets were last set on
+}
+
+func newMarketMetrics() *marketMetrics {
+	return &marketMetrics{
+		ordered: make(map[string]float64),
+		filled:  make(map[string]float64),
+	}
+}
+
+// fillRate adds to symbol's totals and returns its fill rate, at most 1: orders
+// resting since before a restart may fill without having been counted as ordered
+func (m *marketMetrics) fillRate(symbol string, ordered, filled float64) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ordered[symbol] += ordered
+	m.filled[symbol] += filled
+	if m.ordered[symbol] <= 0 {
+		return 0
+	}
+	return math.Min(m.filled[symbol]/m.ordered[symbol], 1)
+}
+
+// marketMetricsPort returns the port market metrics are reported through, or nil,
+// setting the market histograms' buckets on it the first time it is seen
+func (s *ExchangeService) marketMetricsPort() ports.MetricsPort {
+	metrics := s.config.GetMetricsPort()
+	if metrics == nil {
+		return nil
+	}
+	m := s.market
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.configured != metrics {
+		for name, buckets := range marketHistogramBuckets {
+			if err := metrics.SetHistogramBuckets(name, buckets); err != nil {
+				s.logger.WithError(err).Warn("Market histogram keeps its earlier buckets")
+			}
+		}
+		m.configured = metrics
+	}
+	return metrics
+}
+
+// observePlacement counts a request to place an order by its outcome. Duplicate
+// submissions were counted the first time.
+func (s *ExchangeService) observePlacement(req OrderRequest, report *matching.ExecutionReport, err error) {
+	metrics := s.marketMetricsPort()
+	if metrics == nil || (report != nil && report.Duplicate) {
+		return
+	}
+	switch {
+	case err != nil:
+		metrics.IncCounter("exchange_orders_rejected_total", map[string]string{"symbol": req.Symbol, "reason": string(RejectionOf(err).Reason)})
+	case report.Order.Status == models.OrderStatusRejected:
+		// The engine only rejects orders that cannot rest during a call auction
+		metrics.IncCounter("exchange_orders_rejected_total", map[string]string{"symbol": req.Symbol, "reason": string(RejectInvalidPhase)})
+	default:
+		metrics.IncCounter("exchange_orders_placed_total", map[string]string{"symbol": req.Symbol, "side": string(req.Side)})
+		metrics.SetGauge("exchange_fill_rate", s.market.fillRate(req.Symbol, report.Order.Quantity, 0), map[string]string{"symbol": req.Symbol})
+	}
+}
+
+// observeCancel counts an order canceled on request
+func (s *ExchangeService) observeCancel(order models.Order) {
+	if metrics := s.marketMetricsPort(); metrics != nil {
+		metrics.IncCounter("exchange_orders_canceled_total", map[string]string{"symbol": order.Symbol})
+	}
+}
+
+// observeTrades counts trades, their volume and the fills they made. Deleveraging
+// trades close positions outside the book, so neither side was an order.
+func (s *ExchangeService) observeTrades(trades []models.Trade) {
+	metrics := s.marketMetricsPort()
+	if metrics == nil {
+		return
+	}
+	for _, trade := range trades {
+		labels := map[string]string{"symbol": trade.Symbol}
+		metrics.IncCounter("exchange_trades_total", labels)
+		metrics.AddCounter("exchange_trade_volume_total", trade.Quantity, labels)
+		metrics.AddCounter("exchange_trade_notional_total", trade.Notional(), labels)
+		metrics.ObserveHistogram("exchange_trade_notional", trade.Notional(), labels)
+		if trade.Deleverage {
+			continue
+		}
+		metrics.SetGauge("exchange_fill_rate", s.market.fillRate(trade.Symbol, 0, 2*trade.Quantity), labels)
+		for _, orderID := range []string{trade.BuyOrderID, trade.SellOrderID} {
+			if order, err := s.engine.GetOrder(orderID); err == nil {
+				metrics.ObserveHistogram("exchange_order_to_trade_latency_seconds", trade.ExecutedAt.Sub(order.CreatedAt).Seconds(), labels)
+			}
+		}
+	}
+}
+
+// reportBookMetrics sets the book gauges of every listed symbol and samples how
+// far behind each book's goroutine is
+func (s *ExchangeService) reportBookMetrics() {
+	metrics := s.marketMetricsPort()
+	if metrics == nil {
+		return
+	}
+	for _, depth := range s.engine.QueueDepths() {
+		metrics.ObserveHistogram("exchange_engine_queue_depth", float64(depth.Queued), map[string]string{"symbol": depth.Symbol})
+	}
+	for _, instrument := range s.instruments.List() {
+		book, err := s.engine.Snapshot(instrument.Symbol, 0)
+		if err != nil {
+			continue
+		}
+		reportBook(metrics, book)
+	}
+}
+
+func reportBook(metrics ports.MetricsPort, book matching.BookSnapshot) {
+	openOrders := 0
+	for side, levels := range map[models.Side][]matching.PriceLevel{models.SideBuy: book.Bids, models.SideSell: book.Asks} {
+		depth := 0.0
+		for _, level := range levels {
+			depth += level.Quantity
+			openOrders += level.OrderCount
+		}
+		metrics.SetGauge("exchange_book_depth", depth, map[string]string{"symbol": book.Symbol, "side": string(side)})
+	}
+	metrics.SetGauge("exchange_open_orders", float64(openOrders), map[string]string{"symbol": book.Symbol})
+
+	spread := math.NaN()
+	if len(book.Bids) > 0 && len(book.Asks) > 0 {
+		spread = book.Asks[0].Price - book.Bids[0].Price
+	}
+	metrics.SetGauge("exchange_spread", spread, map[string]string{"symbol": book.Symbol})
+}
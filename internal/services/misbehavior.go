@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/logfields"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ComponentMisbehavior is the venue misbehaving on purpose on the incident timeline
+const ComponentMisbehavior = "chaos:misbehavior"
+
+// overfilled is an order an overfill fault drew, whose fills are over-reported for as
+// long as the fault lasts
+type overfilled struct {
+	faultID string
+	pct     float64
+}
+
+// startMisbehavior flags a misbehaviour fault on the audit trail and the incident
+// timeline
+func (s *ExchangeService) startMisbehavior(ctx context.Context, fault chaos.Fault) {
+	s.audit(ctx, audit.Event{Type: audit.TypeMisbehaviorStarted, Attributes: misbehaviorAttributes(fault)})
+	s.reportMisbehavior(ctx)
+}
+
+// endMisbehavior flags the end of a misbehaviour fault, releasing the cancels it
+// stalled and forgetting the orders it overfilled
+func (s *ExchangeService) endMisbehavior(ctx context.Context, fault chaos.Fault) {
+	desk := s.chaos
+	desk.mu.Lock()
+	for orderID, drawn := range desk.overfilled {
+		if drawn.faultID == fault.ID {
+			delete(desk.overfilled, orderID)
+		}
+	}
+	if fault.Kind == chaos.KindStallCancels {
+		close(desk.release)
+		desk.release = make(chan struct{})
+	}
+	desk.mu.Unlock()
+
+	attributes := misbehaviorAttributes(fault)
+	attributes["hits"] = strconv.FormatInt(fault.Hits, 10)
+	s.audit(ctx, audit.Event{Type: audit.TypeMisbehaviorEnded, Attributes: attributes})
+	s.reportMisbehavior(ctx)
+}
+
+// reportMisbehavior degrades the misbehaviour component while any misbehaviour is in
+// force, naming their kinds
+func (s *ExchangeService) reportMisbehavior(ctx context.Context) {
+	kinds := make(map[string]bool)
+	for _, fault := range s.chaos.injector.Active(s.now()) {
+		if fault.Kind.Misbehavior() {
+			kinds[string(fault.Kind)] = true
+		}
+	}
+	if len(kinds) == 0 {
+		s.ReportHealth(ctx, ComponentMisbehavior, incidents.StatusUp, "")
+		return
+	}
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	s.ReportHealth(ctx, ComponentMisbehavior, incidents.StatusDegraded, strings.Join(names, ","))
+}
+
+// flagMisbehaved records one falsified report on the audit trail
+func (s *ExchangeService) flagMisbehaved(ctx context.Context, fault chaos.Fault, accountID, orderID, detail string) {
+	attributes := misbehaviorAttributes(fault)
+	attributes["detail"] = detail
+	s.audit(ctx, audit.Event{Type: audit.TypeMisbehaved, AccountID: accountID, OrderID: orderID, Attributes: attributes})
+	if metrics := s.config.GetMetricsPort(); metrics != nil {
+		metrics.IncCounter("exchange_misbehavior_total", map[string]string{"kind": string(fault.Kind)})
+	}
+	s.log(logfields.WithOrderID(logfields.WithAccountID(ctx, accountID), orderID)).WithFields(logrus.Fields{
+		"fault_id": fault.ID,
+		"kind":     fault.Kind,
+	}).Debug("Venue misbehaved")
+}
+
+func misbehaviorAttributes(fault chaos.Fault) map[string]string {
+	return map[string]string{"fault_id": fault.ID, "kind": string(fault.Kind)}
+}
+
+// overfill reports an order filled beyond what traded once an overfill fault draws
+// it. Every later report of the order is inflated alike until the fault ends.
+func (s *ExchangeService) overfill(ctx context.Context, order models.Order) models.Order {
+	if s.chaos == nil || order.FilledQuantity <= 0 {
+		return order
+	}
+	desk := s.chaos
+	desk.mu.Lock()
+	drawn, ok := desk.overfilled[order.ID]
+	desk.mu.Unlock()
+	if !ok {
+		fault, hit := desk.injector.Overfill(order.Symbol, s.now())
+		if !hit {
+			return order
+		}
+		drawn = overfilled{faultID: fault.ID, pct: fault.OverfillPct}
+		desk.mu.Lock()
+		desk.overfilled[order.ID] = drawn
+		desk.mu.Unlock()
+		s.flagMisbehaved(ctx, fault, order.AccountID, order.ID, "filled_quantity +"+formatAmount(fault.OverfillPct)+"%")
+	}
+	order.FilledQuantity *= 1 + drawn.pct/100
+	return order
+}
+
+// overfillReport is an execution report as told to the client
+func (s *ExchangeService) overfillReport(ctx context.Context, report *matching.ExecutionReport) *matching.ExecutionReport {
+	if s.chaos == nil || report == nil {
+		return report
+	}
+	told := *report
+	told.Order = s.overfill(ctx, report.Order)
+	return &told
+}
+
+// duplicateReport reports whether an order update goes out twice
+func (s *ExchangeService) duplicateReport(order models.Order) bool {
+	if s.chaos == nil {
+		return false
+	}
+	fault, hit := s.chaos.injector.Duplicate(order.Symbol, s.now())
+	if hit {
+		s.flagMisbehaved(context.Background(), fault, order.AccountID, order.ID, "order update sent twice")
+	}
+	return hit
+}
+
+// stallCancel holds back the acknowledgement of a cancel already applied, for the
+// fault's delay or until it ends, unless the caller gives up first
+func (s *ExchangeService) stallCancel(ctx context.Context, order models.Order) error {
+	if s.chaos == nil {
+		return nil
+	}
+	fault, hit := s.chaos.injector.Stall(order.Symbol, s.now())
+	if !hit {
+		return nil
+	}
+	s.flagMisbehaved(ctx, fault, order.AccountID, order.ID, "cancel acknowledgement held back")
+	s.chaos.mu.Lock()
+	release := s.chaos.release
+	s.chaos.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if fault.DelayMs > 0 {
+		timer := time.NewTimer(time.Duration(fault.DelayMs) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-timeout:
+		return nil
+	case <-release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// misreportBalances adds the misreport_balances faults drawn to an account's balances
+// as reported
+func (s *ExchangeService) misreportBalances(ctx context.Context, accountID string, balances []ledger.Balance) []ledger.Balance {
+	if s.chaos == nil {
+		return balances
+	}
+	told := append([]ledger.Balance(nil), balances...)
+	for i := range told {
+		amount, faults := s.chaos.injector.Misreport(accountID, told[i].Asset, s.now())
+		if len(faults) == 0 {
+			continue
+		}
+		told[i].Available += amount
+		told[i].Total += amount
+		for _, fault := range faults {
+			s.flagMisbehaved(ctx, fault, accountID, "", told[i].Asset+" "+formatAmount(fault.Amount))
+		}
+	}
+	return told
+}
+
+// misreportPositions adds the misreport_balances faults drawn to an account's net
+// asset positions as reported
+func (s *ExchangeService) misreportPositions(ctx context.Context, accountID string, positions []ledger.Position) []ledger.Position {
+	if s.chaos == nil {
+		return positions
+	}
+	told := append([]ledger.Position(nil), positions...)
+	for i := range told {
+		amount, faults := s.chaos.injector.Misreport(accountID, told[i].Asset, s.now())
+		if len(faults) == 0 {
+			continue
+		}
+		told[i].Quantity += amount
+		for _, fault := range faults {
+			s.flagMisbehaved(ctx, fault, accountID, "", told[i].Asset+" "+formatAmount(fault.Amount))
+		}
+	}
+	return told
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/orderhistory"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/writebehind"
+)
+
+// orderJournal queues order updates between flushes to the order store
+type orderJournal struct {
+	store orderhistory.Store // nil when orders are not persisted
+	queue *writebehind.Queue[models.Order]
+	mu    sync.Mutex // Held for a whole flush so an older state never lands after a newer one
+}
+
+func newOrderJournal(config writebehind.Config) *orderJournal {
+	return &orderJournal{queue: writebehind.NewQueue[models.Order](config)}
+}
+
+// SetOrderStore persists the latest state of every order to store; set before serving
+func (s *ExchangeService) SetOrderStore(store orderhistory.Store) {
+	s.orderJournal.store = store
+}
+
+// journalOrder queues an order's new state for the next flush, waiting for room while
+// the store is behind
+func (s *ExchangeService) journalOrder(order models.Order) {
+	if s.orderJournal.store == nil {
+		return
+	}
+	s.observeWait(queueOrders, s.orderJournal.queue.Put(order))
+}
+
+// FlushOrders writes the order updates queued when it is called, a batch at a time.
+// A failed batch stays queued and is retried whole by the next flush.
+func (s *ExchangeService) FlushOrders() error {
+	journal := s.orderJournal
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	if journal.store == nil {
+		return nil
+	}
+	defer reportQueueDepth(s, queueOrders, journal.queue)
+
+	return journal.queue.Flush(func(batch []models.Order) error {
+		return s.persist(componentOrders, "save", logrus.Fields{"orders": len(batch)}, func() error {
+			return journal.store.Save(batch)
+		})
+	})
+}
+
+// PersistOrders flushes order updates every interval, and as soon as a full batch is
+// queued, until ctx is done
+func (s *ExchangeService) PersistOrders(ctx context.Context, interval time.Duration) {
+	s.orderJournal.queue.Run(ctx, interval, s.FlushOrders, func(err error) {
+		s.logger.WithError(err).Warn("Failed to persist orders")
+	})
+}
+
+// reassignOrders moves an erased account's stored orders to its alias. Updates still
+// queued carry the account ID, so they are flushed first; the orders the purge
+// canceled, which are not published, are queued under the alias.
+func (s *ExchangeService) reassignOrders(accountID, alias string, canceled []string) error {
+	journal := s.orderJournal
+	if journal.store == nil {
+		return nil
+	}
+	if err := s.FlushOrders(); err != nil {
+		return err
+	}
+	err := s.persist(componentOrders, "reassign", logrus.Fields{"account": alias}, func() error {
+		return journal.store.Reassign(accountID, alias)
+	})
+	if err != nil {
+		return err
+	}
+	for _, orderID := range canceled {
+		if order, err := s.engine.GetOrder(orderID); err == nil {
+			s.journalOrder(order)
+		}
+	}
+	return nil
+}
+
+// OrderHistory pages through orders, working and terminal, in creation order or newest
+// first. With an order store set every order the venue has taken is queried,
+// otherwise only those the engine still holds.
+func (s *ExchangeService) OrderHistory(ctx context.Context, query orderhistory.Query) (orderhistory.Page, error) {
+	if query.Symbol != "" {
+		if _, err := s.instruments.Get(query.Symbol); err != nil {
+			return orderhistory.Page{}, err
+		}
+	}
+	if err := query.Validate(); err != nil {
+		return orderhistory.Page{}, rejectf(RejectInvalidRequest, "%s", err.Error())
+	}
+	if query.AccountID != "" {
+		if err := s.authorizeKey(ctx, query.AccountID, accounts.PermissionRead); err != nil {
+			return orderhistory.Page{}, err
+		}
+	}
+
+	if s.orderJournal.store == nil {
+		return orderhistory.Paginate(s.engine.Orders(query.AccountID, query.Symbol), query), nil
+	}
+	// Updates not yet flushed would otherwise show orders in a stale state
+	if err := s.FlushOrders(); err != nil {
+		return orderhistory.Page{}, err
+	}
+	var page orderhistory.Page
+	err := s.timeStorage(componentOrders, "query", logrus.Fields{"symbol": query.Symbol, "account": query.AccountID}, func() (err error) {
+		page, err = s.orderJournal.store.Query(query)
+		return err
+	})
+	return page, err
+}
+
+// ParseOrderCursor decodes a cursor from a previous page of order history
+func ParseOrderCursor(cursor string) (orderhistory.Cursor, error) {
+	parsed, err := orderhistory.ParseCursor(cursor)
+	if errors.Is(err, orderhistory.ErrInvalidCursor) {
+		return orderhistory.Cursor{}, rejectf(RejectInvalidRequest, "cursor was not issued by this venue")
+	}
+	return parsed, err
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+)
+
+// outboxShutdownTimeout bounds the last dispatch when the venue stops
+const outboxShutdownTimeout = 5 * time.Second
+
+// eventOutbox queues audit events as outbox messages between writes to the store
+type eventOutbox struct {
+	store        outbox.Store
+	publishers   map[outbox.Destination]outbox.Publisher
+	destinations []outbox.Destination // Sorted keys of publishers
+	interval     time.Duration
+	batchSize    int
+	backoff      outbox.Backoff
+	pending      []outbox.Message
+	stats        OutboxStats
+	mu           sync.Mutex // Held for a whole write or dispatch
+	queueMu      sync.Mutex // Guards pending; orders never wait on a write
+}
+
+// OutboxStats counts the messages written to the outbox and dispatched from it
+type OutboxStats struct {
+	Queued       int        `json:"queued"` // Awaiting their write to the store
+	Written      int64      `json:"written"`
+	Dispatched   int64      `json:"dispatched"`
+	Failed       int64      `json:"failed"` // Attempts that failed; the messages are retried
+	LastDispatch outbox.Run `json:"last_dispatch"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+func newEventOutbox(cfg *config.Config, store outbox.Store, publishers map[outbox.Destination]outbox.Publisher) *eventOutbox {
+	o := &eventOutbox{
+		store:      store,
+		publishers: publishers,
+		interval:   time.Second,
+		batchSize:  100,
+		backoff:    outbox.Backoff{Base: time.Second, Max: time.Minute},
+	}
+	// Unset values keep their defaults
+	if cfg != nil && cfg.OutboxInterval > 0 {
+		o.interval, o.backoff.Base = cfg.OutboxInterval, cfg.OutboxInterval
+	}
+	if cfg != nil && cfg.OutboxBatchSize > 0 {
+		o.batchSize = cfg.OutboxBatchSize
+	}
+	if cfg != nil && cfg.OutboxRetryMax > 0 {
+		o.backoff.Max = cfg.OutboxRetryMax
+	}
+	for destination := range publishers {
+		o.destinations = append(o.destinations, destination)
+	}
+	sort.Slice(o.destinations, func(i, j int) bool { return o.destinations[i] < o.destinations[j] })
+	return o
+}
+
+// EnableOutbox writes every audit event to store, one message per publisher, before
+// any is sent, so events survive a restart and are delivered at least once. With a
+// trade store that implements outbox.TradeStore, trades and their events are written
+// in one transaction. Replaces the audit trail pipeline; set before serving.
+func (s *ExchangeService) EnableOutbox(store outbox.Store, publishers map[outbox.Destination]outbox.Publisher) {
+	s.outbox = newEventOutbox(s.config, store, publishers)
+}
+
+// auditing reports whether audit events are wanted by the pipeline or the outbox
+func (s *ExchangeService) auditing() bool {
+	return s.auditTrail != nil || s.outbox != nil
+}
+
+// queue adds one message per destination for each event, keyed by the event's ID
+func (o *eventOutbox) queue(events []audit.Event) error {
+	messages := make([]outbox.Message, 0, len(events)*len(o.destinations))
+	for _, event := range events {
+		for _, destination := range o.destinations {
+			message, err := outbox.NewMessage(event.ID, destination, event.Type, event, event.OccurredAt)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+	}
+	o.queueMu.Lock()
+	o.pending = append(o.pending, messages...)
+	o.queueMu.Unlock()
+	return nil
+}
+
+// take empties the queue
+func (o *eventOutbox) take() []outbox.Message {
+	o.queueMu.Lock()
+	defer o.queueMu.Unlock()
+	messages := o.pending
+	o.pending = nil
+	return messages
+}
+
+// requeue puts back messages whose write failed, ahead of those queued since
+func (o *eventOutbox) requeue(messages []outbox.Message) {
+	o.queueMu.Lock()
+	o.pending = append(messages, o.pending...)
+	o.queueMu.Unlock()
+}
+
+func (o *eventOutbox) written(count int) {
+	o.queueMu.Lock()
+	o.stats.Written += int64(count)
+	o.queueMu.Unlock()
+}
+
+// outboxTrades returns the trade store that writes outbox messages with trades, or
+// nil when the outbox is written on its own
+func (s *ExchangeService) outboxTrades() outbox.TradeStore {
+	if s.outbox == nil {
+		return nil
+	}
+	store, _ := s.tradeTape.store.(outbox.TradeStore)
+	return store
+}
+
+// FlushOutbox writes the messages queued since the last flush. With the trade tape
+// in the same transaction it flushes the tape instead, so that no message is written
+// before the trade it announces. A failed batch stays queued and is retried whole.
+func (s *ExchangeService) FlushOutbox() error {
+	if s.outbox == nil {
+		return nil
+	}
+	if s.outboxTrades() != nil {
+		return s.FlushTradeTape()
+	}
+	o := s.outbox
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	batch := o.take()
+	if len(batch) == 0 {
+		return nil
+	}
+	err := s.persist(componentOutbox, "write", logrus.Fields{"messages": len(batch)}, func() error {
+		return o.store.Write(batch)
+	})
+	if err != nil {
+		o.requeue(batch)
+		return err
+	}
+	o.written(len(batch))
+	return nil
+}
+
+// DispatchOutbox publishes one batch of due messages to their destinations
+func (s *ExchangeService) DispatchOutbox(ctx context.Context) (outbox.Run, error) {
+	if s.outbox == nil {
+		return outbox.Run{}, rejectf(RejectInvalidRequest, "the outbox is not enabled")
+	}
+	o := s.outbox
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var run outbox.Run
+	err := s.persist(componentOutbox, "dispatch", logrus.Fields{"limit": o.batchSize}, func() (err error) {
+		run, err = outbox.Dispatch(ctx, o.store, o.publishers, o.backoff, s.now(), o.batchSize)
+		return err
+	})
+	o.queueMu.Lock()
+	o.stats.Dispatched += int64(run.Dispatched)
+	o.stats.Failed += int64(run.Failed)
+	o.stats.LastDispatch = run
+	if err != nil {
+		o.stats.LastError = err.Error()
+	}
+	o.queueMu.Unlock()
+	if err != nil {
+		return run, err
+	}
+	// A destination that is down is a dependency problem, not a storage one
+	if run.Failed > 0 {
+		s.reportOutcome(componentAuditSink, fmt.Errorf("%d outbox messages failed to publish", run.Failed))
+	} else if run.Dispatched > 0 {
+		s.reportOutcome(componentAuditSink, nil)
+	}
+	return run, nil
+}
+
+// RunOutbox writes queued events and dispatches due messages every interval until
+// ctx is done, then does both once more
+func (s *ExchangeService) RunOutbox(ctx context.Context) {
+	if s.outbox == nil {
+		return
+	}
+	ticker := time.NewTicker(s.outbox.interval)
+	defer ticker.Stop()
+
+	run := func(ctx context.Context) {
+		if err := s.FlushOutbox(); err != nil {
+			s.logger.WithError(err).Warn("Failed to write outbox messages")
+		}
+		// Dispatch drains a backlog one batch at a time until a batch falls short
+		for {
+			result, err := s.DispatchOutbox(ctx)
+			if err != nil {
+				s.logger.WithError(err).Warn("Failed to dispatch outbox messages")
+				return
+			}
+			if result.Due < s.outbox.batchSize || result.Failed > 0 {
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), outboxShutdownTimeout)
+			run(shutdownCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			run(ctx)
+		}
+	}
+}
+
+// OutboxStats counts the messages queued, written, dispatched and failed
+func (s *ExchangeService) OutboxStats(ctx context.Context) (OutboxStats, error) {
+	if s.outbox == nil {
+		return OutboxStats{}, rejectf(RejectInvalidRequest, "the outbox is not enabled")
+	}
+	o := s.outbox
+	o.queueMu.Lock()
+	defer o.queueMu.Unlock()
+	stats := o.stats
+	stats.Queued = len(o.pending)
+	return stats, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// APIFlavor is the exchange API a venue speaks beside the native one
+type APIFlavor string
+
+const (
+	APIFlavorNative  APIFlavor = "native"  // The simulator's own REST, gRPC and FIX APIs only
+	APIFlavorBinance APIFlavor = "binance" // Also the Binance-compatible /api/v3
+)
+
+// LatencyProfile is the gateway latency of accounts without a profile of their own
+type LatencyProfile struct {
+	Base   time.Duration // Added before each order action reaches the engine
+	Jitter time.Duration // Up to this much more, drawn for each action
+}
+
+type latencyProfileJSON struct {
+	Base   string `json:"base"`
+	Jitter string `json:"jitter"`
+}
+
+// MarshalJSON writes the latencies as duration strings, "20ms"
+func (l LatencyProfile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(latencyProfileJSON{Base: l.Base.String(), Jitter: l.Jitter.String()})
+}
+
+// UnmarshalJSON reads the latencies as duration strings; an empty one is zero
+func (l *LatencyProfile) UnmarshalJSON(data []byte) error {
+	var raw latencyProfileJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parse := func(name, value string) (time.Duration, error) {
+		if value == "" {
+			return 0, nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s latency %q: %w", name, value, err)
+		}
+		return parsed, nil
+	}
+	base, err := parse("base", raw.Base)
+	if err != nil {
+		return err
+	}
+	jitter, err := parse("jitter", raw.Jitter)
+	if err != nil {
+		return err
+	}
+	*l = LatencyProfile{Base: base, Jitter: jitter}
+	return nil
+}
+
+// draw returns one action's latency
+func (l LatencyProfile) draw() time.Duration {
+	if l.Jitter <= 0 {
+		return l.Base
+	}
+	return l.Base + time.Duration(rand.Int63n(int64(l.Jitter)+1))
+}
+
+// Personality is what sets one named venue apart from another run of the same
+// simulator, e.g. exchange-OKX from exchange-BINANCE
+type Personality struct {
+	Venue       string         `json:"venue"`                  // Display name, e.g. "OKX"; defaults to the instance name
+	FeeSchedule FeeSchedule    `json:"fee_schedule,omitempty"` // As FEE_SCHEDULE_CONFIG_KEY holds it
+	Latency     LatencyProfile `json:"latency"`
+	Instruments []string       `json:"instruments,omitempty"` // Symbols listed; empty lists every default instrument
+	APIFlavor   APIFlavor      `json:"api_flavor"`
+}
+
+// venuePersonality is the personality the venue runs with
+type venuePersonality struct {
+	mu          sync.RWMutex
+	personality Personality
+}
+
+// ParsePersonality reads a personality as the configuration service holds it
+func ParsePersonality(raw json.RawMessage) (Personality, error) {
+	var personality Personality
+	if err := json.Unmarshal(raw, &personality); err != nil {
+		return Personality{}, err
+	}
+	return personality, nil
+}
+
+// SetPersonality runs the venue as personality: it delists the instruments not
+// named, applies the fee schedule, and delays order actions of accounts without a
+// profile by the latency profile. It is meant for startup, before any order is
+// taken; a delisted instrument is not listed again. A personality naming an unknown
+// instrument, an out-of-range rate or latency, or an unknown API flavor changes
+// nothing.
+func (s *ExchangeService) SetPersonality(ctx context.Context, personality Personality) (Personality, error) {
+	if personality.Venue == "" {
+		personality.Venue = s.config.ServiceInstanceName
+	}
+	switch personality.APIFlavor {
+	case "":
+		personality.APIFlavor = APIFlavorNative
+	case APIFlavorNative, APIFlavorBinance:
+	default:
+		return Personality{}, rejectf(RejectInvalidRequest, "unknown API flavor %q: expected native or binance", personality.APIFlavor)
+	}
+	latency := personality.Latency
+	if latency.Base < 0 || latency.Jitter < 0 || latency.Base+latency.Jitter > maxGatewayLatency {
+		return Personality{}, rejectf(RejectInvalidRequest, "latency must be between 0 and %s, jitter included", maxGatewayLatency)
+	}
+
+	listed := make(map[string]bool, len(personality.Instruments))
+	for _, symbol := range personality.Instruments {
+		if _, err := s.instruments.Get(symbol); err != nil {
+			return Personality{}, NewRejection(RejectInvalidRequest, err)
+		}
+		listed[symbol] = true
+	}
+	if err := personality.FeeSchedule.validateRates(); err != nil {
+		return Personality{}, err
+	}
+	for symbol := range personality.FeeSchedule {
+		if symbol != anyInstrument && len(listed) > 0 && !listed[symbol] {
+			return Personality{}, rejectf(RejectInvalidRequest, "fee schedule names %s, which the venue does not list", symbol)
+		}
+	}
+
+	if len(listed) > 0 {
+		for _, instrument := range s.instruments.List() {
+			if !listed[instrument.Symbol] {
+				s.instruments.Remove(instrument.Symbol)
+			}
+		}
+	}
+	if len(personality.FeeSchedule) > 0 {
+		if err := s.SetFeeSchedule(ctx, personality.FeeSchedule); err != nil {
+			return Personality{}, err
+		}
+	}
+	s.colocation.setVenueLatency(latency)
+
+	s.personality.mu.Lock()
+	s.personality.personality = personality
+	s.personality.mu.Unlock()
+
+	personality = s.Personality(ctx)
+	s.logger.WithFields(logrus.Fields{
+		"venue":       personality.Venue,
+		"instruments": len(personality.Instruments),
+		"latency":     latency.Base,
+		"jitter":      latency.Jitter,
+		"api_flavor":  personality.APIFlavor,
+	}).Info("Venue personality set")
+	return personality, nil
+}
+
+// Personality returns the personality the venue runs with, listing the instruments
+// it lists now
+func (s *ExchangeService) Personality(ctx context.Context) Personality {
+	s.personality.mu.RLock()
+	personality := s.personality.personality
+	s.personality.mu.RUnlock()
+
+	if personality.Venue == "" {
+		personality.Venue = s.config.ServiceInstanceName
+	}
+	if personality.APIFlavor == "" {
+		personality.APIFlavor = APIFlavorNative
+	}
+	instruments := s.instruments.List()
+	personality.Instruments = make([]string, 0, len(instruments))
+	for _, instrument := range instruments {
+		personality.Instruments = append(personality.Instruments, instrument.Symbol)
+	}
+	return personality
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/margin"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// AccountPosition is an account's net holding in one instrument and its PnL, in the
+// instrument's quote asset, at the current mark
+type AccountPosition struct {
+	AccountID     string    `json:"account_id"`
+	Symbol        string    `json:"symbol"`
+	Asset         string    `json:"asset"`       // Quote asset the prices and PnL are in
+	Quantity      float64   `json:"quantity"`    // Base units or contracts; negative is a short
+	EntryPrice    float64   `json:"entry_price"` // Average price the open quantity was taken at
+	MarkPrice     float64   `json:"mark_price"`
+	RealizedPnL   float64   `json:"realized_pnl"` // From quantity closed so far
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	UpdatedAt     time.Time `json:"updated_at"` // Last fill
+}
+
+// PositionService keeps every account's net position per symbol, spot and
+// derivative alike, booking each fill as it happens: adding to a position moves its
+// entry to the average price, reducing it realizes the PnL of the closed quantity.
+type PositionService struct {
+	instruments *InstrumentRegistry
+	mark        func(models.Instrument) float64
+
+	books   map[string]*margin.Book // Keyed by account
+	updated map[string]map[string]time.Time
+	mu      sync.RWMutex
+}
+
+// NewPositionService values positions with mark, the price of an instrument now
+func NewPositionService(instruments *InstrumentRegistry, mark func(models.Instrument) float64) *PositionService {
+	return &PositionService{
+		instruments: instruments,
+		mark:        mark,
+		books:       make(map[string]*margin.Book),
+		updated:     make(map[string]map[string]time.Time),
+	}
+}
+
+// Fill books a trade for its buyer and its seller
+func (s *PositionService) Fill(trade models.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.book(trade.BuyAccountID, trade.Symbol, models.SideBuy, trade.Quantity, trade.Price, trade.ExecutedAt)
+	s.book(trade.SellAccountID, trade.Symbol, models.SideSell, trade.Quantity, trade.Price, trade.ExecutedAt)
+}
+
+// Rebuild replaces every position with those of filled orders, each booked once at
+// its average price. Trade history is bounded, so this is how positions come back
+// after a restart; PnL realized within one order that flipped a position is then
+// taken at the order's average rather than each fill's price.
+func (s *PositionService) Rebuild(filled []models.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.books = make(map[string]*margin.Book)
+	s.updated = make(map[string]map[string]time.Time)
+	for _, order := range filled {
+		s.book(order.AccountID, order.Symbol, order.Side, order.FilledQuantity, order.AveragePrice, order.UpdatedAt)
+	}
+}
+
+// Reassign moves an erased account's positions to its alias
+func (s *PositionService) Reassign(accountID, alias string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if book, ok := s.books[accountID]; ok {
+		s.books[alias], s.updated[alias] = book, s.updated[accountID]
+		delete(s.books, accountID)
+		delete(s.updated, accountID)
+	}
+}
+
+// Positions lists positions sorted by account then symbol, optionally for one account
+// and/or one symbol. Flat positions are kept for the PnL they realized.
+func (s *PositionService) Positions(ctx context.Context, accountID, symbol string) ([]AccountPosition, error) {
+	if symbol != "" {
+		if _, err := s.instruments.Get(symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.RLock()
+	positions := make([]AccountPosition, 0)
+	for account, book := range s.books {
+		if accountID != "" && account != accountID {
+			continue
+		}
+		for _, position := range book.Positions() {
+			if symbol != "" && position.Symbol != symbol {
+				continue
+			}
+			positions = append(positions, AccountPosition{
+				AccountID:   account,
+				Symbol:      position.Symbol,
+				Quantity:    position.Quantity,
+				EntryPrice:  position.EntryPrice,
+				RealizedPnL: position.RealizedPnL,
+				UpdatedAt:   s.updated[account][position.Symbol],
+			})
+		}
+	}
+	s.mu.RUnlock()
+
+	for i := range positions {
+		instrument, _ := s.instruments.Get(positions[i].Symbol)
+		positions[i].Asset = instrument.QuoteAsset
+		positions[i].MarkPrice = s.mark(instrument)
+		if positions[i].Quantity != 0 {
+			positions[i].UnrealizedPnL = positions[i].Quantity * (positions[i].MarkPrice - positions[i].EntryPrice)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].AccountID != positions[j].AccountID {
+			return positions[i].AccountID < positions[j].AccountID
+		}
+		return positions[i].Symbol < positions[j].Symbol
+	})
+	return positions, nil
+}
+
+func (s *PositionService) book(accountID, symbol string, side models.Side, quantity, price float64, at time.Time) {
+	if accountID == "" || quantity <= 0 {
+		return
+	}
+	book, ok := s.books[accountID]
+	if !ok {
+		book = margin.NewBook()
+		s.books[accountID] = book
+		s.updated[accountID] = make(map[string]time.Time)
+	}
+	book.Fill(symbol, side, quantity, price)
+	s.updated[accountID][symbol] = at
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// PreviewRequest describes a hypothetical order or position to evaluate
+type PreviewRequest struct {
+	Symbol    string           `json:"symbol"`
+	Side      models.Side      `json:"side"`
+	OrderType models.OrderType `json:"order_type"`
+	Quantity  float64          `json:"quantity"`
+	Price     float64          `json:"price"`    // Optional for market orders (reference price used)
+	Leverage  float64          `json:"leverage"` // Optional, defaults to 1 (spot) or max leverage (derivatives)
+
+	// Liquidity overrides the maker/taker assumption; empty means infer from order type
+	Liquidity models.LiquidityRole `json:"liquidity,omitempty"`
+
+	// HoldingPeriod is how long the resulting position is held, used for funding estimates
+	HoldingPeriod time.Duration `json:"holding_period"`
+}
+
+// PreviewResult is the expected cost of a hypothetical order under current venue rules
+type PreviewResult struct {
+	Symbol   string      `json:"symbol"`
+	Side     models.Side `json:"side"`
+	Quantity float64     `json:"quantity"`
+	Price    float64     `json:"price"`
+	Notional float64     `json:"notional"`
+	FeeAsset string      `json:"fee_asset"`
+
+	// Fees
+	Liquidity models.LiquidityRole `json:"liquidity"`
+	FeeRate   float64              `json:"fee_rate"`
+	Fee       float64              `json:"fee"`
+
+	// Margin
+	Leverage          float64 `json:"leverage"`
+	InitialMargin     float64 `json:"initial_margin"`
+	MaintenanceMargin float64 `json:"maintenance_margin"`
+
+	// Funding (positive cost is paid by the position holder, negative is received)
+	FundingRate      float64 `json:"funding_rate"`
+	FundingIntervals int     `json:"funding_intervals"`
+	FundingCost      float64 `json:"funding_cost"`
+
+	// TotalCost is fee + funding, excluding margin which is collateral rather than cost
+	TotalCost float64 `json:"total_cost"`
+}
+
+// PreviewService evaluates hypothetical orders against simulator rules without executing them
+type PreviewService struct {
+	instruments *InstrumentRegistry
+	logger      *logrus.Logger
+}
+
+func NewPreviewService(instruments *InstrumentRegistry, logger *logrus.Logger) *PreviewService {
+	return &PreviewService{
+		instruments: instruments,
+		logger:      logger,
+	}
+}
+
+// Preview returns expected fees, margin requirement and funding cost for req
+func (s *PreviewService) Preview(req PreviewRequest) (*PreviewResult, error) {
+	instrument, err := s.instruments.Get(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Side != models.SideBuy && req.Side != models.SideSell {
+		return nil, fmt.Errorf("invalid side: %q", req.Side)
+	}
+	if req.OrderType == "" {
+		req.OrderType = models.OrderTypeLimit
+	}
+	if err := instrument.ValidateQuantity(req.Quantity); err != nil {
+		return nil, err
+	}
+	if req.HoldingPeriod < 0 {
+		return nil, fmt.Errorf("holding period cannot be negative")
+	}
+
+	price := req.Price
+	if req.OrderType == models.OrderTypeMarket && price == 0 {
+		price = instrument.ReferencePrice
+	}
+	if err := instrument.ValidatePrice(price); err != nil {
+		return nil, err
+	}
+
+	leverage, err := resolveLeverage(&instrument, req.Leverage)
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity := req.Liquidity
+	if liquidity == "" {
+		liquidity = models.LiquidityMaker
+		if req.OrderType == models.OrderTypeMarket {
+			liquidity = models.LiquidityTaker
+		}
+	}
+
+	feeRate := instrument.MakerFeeRate
+	if liquidity == models.LiquidityTaker {
+		feeRate = instrument.TakerFeeRate
+	}
+
+	notional := req.Quantity * price
+	result := &PreviewResult{
+		Symbol:    instrument.Symbol,
+		Side:      req.Side,
+		Quantity:  req.Quantity,
+		Price:     price,
+		Notional:  notional,
+		FeeAsset:  instrument.QuoteAsset,
+		Liquidity: liquidity,
+		FeeRate:   feeRate,
+		Fee:       notional * feeRate,
+		Leverage:  leverage,
+	}
+
+	if instrument.IsDerivative() {
+		result.InitialMargin = notional / leverage
+		result.MaintenanceMargin = notional * instrument.MaintenanceMarginRate
+		result.FundingRate = instrument.FundingRate
+		if instrument.FundingInterval > 0 {
+			result.FundingIntervals = int(req.HoldingPeriod / instrument.FundingInterval)
+		}
+		// Longs pay shorts when the funding rate is positive
+		result.FundingCost = req.Side.Sign() * notional * instrument.FundingRate * float64(result.FundingIntervals)
+	} else {
+		// Spot orders must be fully funded
+		result.InitialMargin = notional
+	}
+
+	result.TotalCost = result.Fee + result.FundingCost
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":   result.Symbol,
+		"side":     result.Side,
+		"quantity": result.Quantity,
+		"notional": result.Notional,
+	}).Debug("Order preview computed")
+
+	return result, nil
+}
+
+func resolveLeverage(instrument *models.Instrument, requested float64) (float64, error) {
+	if requested < 0 {
+		return 0, fmt.Errorf("leverage cannot be negative")
+	}
+	maxLeverage := instrument.MaxLeverage()
+	if requested == 0 {
+		return maxLeverage, nil
+	}
+	if requested > maxLeverage+1e-9 {
+		return 0, fmt.Errorf("leverage %v exceeds maximum %v for %s", requested, math.Floor(maxLeverage*100)/100, instrument.Symbol)
+	}
+	return requested, nil
+}
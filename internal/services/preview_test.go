@@ -0,0 +1,162 @@
+//go:build unit
+
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+func newTestPreviewService() *PreviewService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewPreviewService(NewInstrumentRegistry(DefaultInstruments()...), logger)
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPreviewService_Preview(t *testing.T) {
+	t.Run("spot_market_order_charges_taker_fee_and_full_notional", func(t *testing.T) {
+		// Given: A preview service with default instruments
+		service := newTestPreviewService()
+
+		// When: Previewing a spot market buy without a price
+		result, err := service.Preview(PreviewRequest{
+			Symbol:    "BTC-USD",
+			Side:      models.SideBuy,
+			OrderType: models.OrderTypeMarket,
+			Quantity:  0.5,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: The reference price is used and the taker fee applies
+		if result.Price != 60000 {
+			t.Errorf("Expected reference price 60000, got %v", result.Price)
+		}
+		if result.Liquidity != models.LiquidityTaker {
+			t.Errorf("Expected taker liquidity, got %s", result.Liquidity)
+		}
+		if !approxEqual(result.Fee, 30000*0.002) {
+			t.Errorf("Expected fee 60, got %v", result.Fee)
+		}
+
+		// And: Spot requires the full notional as margin
+		if !approxEqual(result.InitialMargin, 30000) {
+			t.Errorf("Expected initial margin 30000, got %v", result.InitialMargin)
+		}
+		if result.FundingCost != 0 {
+			t.Errorf("Expected no funding cost for spot, got %v", result.FundingCost)
+		}
+	})
+
+	t.Run("perpetual_position_accrues_funding_per_interval", func(t *testing.T) {
+		// Given: A preview service with default instruments
+		service := newTestPreviewService()
+
+		// When: Previewing a 10x long perpetual held for 24 hours
+		result, err := service.Preview(PreviewRequest{
+			Symbol:        "BTC-USD-PERP",
+			Side:          models.SideBuy,
+			Quantity:      1,
+			Price:         60000,
+			Leverage:      10,
+			HoldingPeriod: 24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Then: Limit orders are assumed to add liquidity
+		if result.Liquidity != models.LiquidityMaker {
+			t.Errorf("Expected maker liquidity, got %s", result.Liquidity)
+		}
+
+		// And: Margin reflects the requested leverage
+		if !approxEqual(result.InitialMargin, 6000) {
+			t.Errorf("Expected initial margin 6000, got %v", result.InitialMargin)
+		}
+		if !approxEqual(result.MaintenanceMargin, 1500) {
+			t.Errorf("Expected maintenance margin 1500, got %v", result.MaintenanceMargin)
+		}
+
+		// And: Three funding intervals are paid by the long
+		if result.FundingIntervals != 3 {
+			t.Errorf("Expected 3 funding intervals, got %d", result.FundingIntervals)
+		}
+		if !approxEqual(result.FundingCost, 60000*0.0001*3) {
+			t.Errorf("Expected funding cost 18, got %v", result.FundingCost)
+		}
+	})
+
+	t.Run("short_perpetual_receives_positive_funding", func(t *testing.T) {
+		service := newTestPreviewService()
+
+		result, err := service.Preview(PreviewRequest{
+			Symbol:        "BTC-USD-PERP",
+			Side:          models.SideSell,
+			Quantity:      1,
+			Price:         60000,
+			HoldingPeriod: 8 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.FundingCost >= 0 {
+			t.Errorf("Expected shorts to receive funding, got cost %v", result.FundingCost)
+		}
+	})
+
+	t.Run("rejects_leverage_above_instrument_maximum", func(t *testing.T) {
+		service := newTestPreviewService()
+
+		_, err := service.Preview(PreviewRequest{
+			Symbol:   "BTC-USD-PERP",
+			Side:     models.SideBuy,
+			Quantity: 1,
+			Price:    60000,
+			Leverage: 50,
+		})
+		if err == nil {
+			t.Error("Expected leverage error, got nil")
+		}
+	})
+
+	t.Run("rejects_unknown_instrument", func(t *testing.T) {
+		service := newTestPreviewService()
+
+		_, err := service.Preview(PreviewRequest{
+			Symbol:   "DOGE-USD",
+			Side:     models.SideBuy,
+			Quantity: 1,
+			Price:    1,
+		})
+		if !errors.Is(err, ErrUnknownInstrument) {
+			t.Errorf("Expected ErrUnknownInstrument, got %v", err)
+		}
+	})
+
+	t.Run("rejects_quantity_not_aligned_to_lot_size", func(t *testing.T) {
+		service := newTestPreviewService()
+
+		_, err := service.Preview(PreviewRequest{
+			Symbol:   "BTC-USD",
+			Side:     models.SideBuy,
+			Quantity: 0.00015,
+			Price:    60000,
+		})
+		if err == nil {
+			t.Error("Expected lot size error, got nil")
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package services
+
+import "github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+
+// SetIDObfuscator publishes order and trade IDs on public market data through
+// obfuscator; set before serving. Without one, public IDs are the internal IDs.
+func (s *ExchangeService) SetIDObfuscator(obfuscator ports.IDObfuscator) {
+	s.publicIDs = obfuscator
+}
+
+// publicID returns the ID an internal order or trade ID is published under
+func (s *ExchangeService) publicID(id string) string {
+	if s.publicIDs == nil {
+		return id
+	}
+	return s.publicIDs.External(id)
+}
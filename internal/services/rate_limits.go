@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/keystats"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ratelimit"
+)
+
+// defaultRateLimitRefresh is how often rate limits are read from their source when unconfigured
+const defaultRateLimitRefresh = 30 * time.Second
+
+// RateLimits returns the request rate limits in force
+func (s *ExchangeService) RateLimits() ratelimit.Limits {
+	return s.rateLimits.Limits()
+}
+
+// SetRateLimits replaces the request rate limits; callers keep what their buckets hold
+func (s *ExchangeService) SetRateLimits(ctx context.Context, limits ratelimit.Limits) (ratelimit.Limits, error) {
+	if err := limits.Validate(); err != nil {
+		return ratelimit.Limits{}, NewRejection(RejectInvalidRequest, err)
+	}
+	s.rateLimits.SetLimits(limits)
+	s.logger.WithFields(logrus.Fields{
+		"key_weight": limits.KeyWeight,
+		"ip_weight":  limits.IPWeight,
+		"window":     limits.Window,
+		"endpoints":  len(limits.Weights),
+	}).Info("Rate limits updated")
+	return limits, nil
+}
+
+// FollowRateLimits reads the limits held under key from source now and every
+// interval until ctx is done. A value is applied when it changes, so limits set
+// through the admin API stand until the next change at the source; a value that
+// cannot be read or is invalid leaves the limits as they are.
+func (s *ExchangeService) FollowRateLimits(ctx context.Context, source ports.SettingSource, key string, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRateLimitRefresh
+	}
+	var applied json.RawMessage
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		raw, err := source.Setting(ctx, key)
+		if err == nil && !bytes.Equal(raw, applied) {
+			err = s.applyRateLimits(ctx, raw)
+			applied = raw
+		}
+		if err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).WithField("key", key).Warn("Failed to read rate limits from the configuration service")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ExchangeService) applyRateLimits(ctx context.Context, raw json.RawMessage) error {
+	var limits ratelimit.Limits
+	if err := json.Unmarshal(raw, &limits); err != nil {
+		return fmt.Errorf("invalid rate limits: %w", err)
+	}
+	_, err := s.SetRateLimits(ctx, limits)
+	return err
+}
+
+// ChargeRequest spends a request's endpoint weight from its caller's buckets: the
+// API key, or the account a venue-issued key belongs to, and the client address.
+// Beyond either it is refused with RATE_LIMITED; the decision says when to retry.
+func (s *ExchangeService) ChargeRequest(ctx context.Context, clientIP, endpoint string) (ratelimit.Decision, error) {
+	decision := s.rateLimits.Take(s.rateLimitKey(keystats.APIKey(ctx)), clientIP, endpoint, s.now())
+	if decision.Allowed {
+		return decision, nil
+	}
+	s.logger.WithFields(logrus.Fields{
+		"api_key":  keystats.APIKey(ctx),
+		"client":   clientIP,
+		"endpoint": endpoint,
+		"scope":    decision.Scope,
+	}).Debug("Request rate limited")
+	return decision, rejectf(RejectRateLimited, "%s: %d weight per %s for this %s, retry after %s",
+		ratelimit.ErrLimited, decision.Limit, s.rateLimits.Limits().Window, decision.Scope, decision.RetryAfter)
+}
+
+// rateLimitKey is the bucket a key's requests are charged to. Keys the venue issued
+// share their account's bucket, so an account cannot multiply its limit with keys;
+// anonymous callers are limited by address only.
+func (s *ExchangeService) rateLimitKey(apiKey string) string {
+	if apiKey == keystats.Anonymous {
+		return ""
+	}
+	if account, _, ok := s.accounts.directory.ByKeyID(apiKey); ok {
+		return "account:" + account.ID
+	}
+	if !accounts.IsKeyID(apiKey) {
+		if account, ok := s.accounts.directory.ByAPIKey(apiKey); ok {
+			return "account:" + account.ID
+		}
+	}
+	return apiKey
+}
+
+// rateLimits builds the default request rate limits from configuration; endpoint
+// weights are parsed and set at startup
+func rateLimits(cfg *config.Config) ratelimit.Limits {
+	if cfg == nil {
+		return ratelimit.Limits{Window: ratelimit.DefaultWindow}
+	}
+	limits := ratelimit.Limits{KeyWeight: cfg.RateLimitWeight, IPWeight: cfg.RateLimitIPWeight, Window: cfg.RateLimitWindow}
+	if limits.Window <= 0 {
+		limits.Window = ratelimit.DefaultWindow
+	}
+	return limits
+}
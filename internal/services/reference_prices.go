@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+)
+
+// defaultPriceFeedMaxAge is how long an external price anchors the venue when unconfigured
+const defaultPriceFeedMaxAge = 30 * time.Second
+
+// ComponentPriceFeed is the external price feed on the incident timeline
+const ComponentPriceFeed = "dependency:price-feed"
+
+// UpdateReferencePrice records an external price for a listed symbol. While it is
+// fresh it is the symbol's mark: the market maker quotes around it, and funding,
+// valuation and a starting synthetic path use it.
+func (s *ExchangeService) UpdateReferencePrice(ctx context.Context, price pricefeed.Price) (pricefeed.Price, error) {
+	if _, err := s.instruments.Get(price.Symbol); err != nil {
+		return pricefeed.Price{}, err
+	}
+	if price.Price <= 0 {
+		return pricefeed.Price{}, rejectf(RejectInvalidPrice, "reference price must be positive")
+	}
+	price.ReceivedAt = s.now()
+	if !s.referencePrices.Update(price) {
+		return pricefeed.Price{}, rejectf(RejectInvalidRequest, "a later price for %s was already received", price.Symbol)
+	}
+	return price, nil
+}
+
+// ReferencePrices returns the latest external price per symbol and whether it still anchors the venue
+func (s *ExchangeService) ReferencePrices(ctx context.Context) []pricefeed.Quote {
+	return s.referencePrices.Quotes(s.now())
+}
+
+// FollowPriceFeed applies prices from source until ctx is done, resubscribing after
+// retry whenever it fails. The feed is marked degraded on the incident timeline while
+// it is down and up once prices flow again.
+func (s *ExchangeService) FollowPriceFeed(ctx context.Context, source ports.PriceSource, retry time.Duration) {
+	for {
+		flowing := false
+		err := source.Follow(ctx, func(price pricefeed.Price) {
+			if !flowing {
+				flowing = true
+				s.ReportHealth(ctx, ComponentPriceFeed, incidents.StatusUp, "")
+			}
+			if _, err := s.UpdateReferencePrice(ctx, price); err != nil {
+				s.logger.WithError(err).WithField("symbol", price.Symbol).Debug("Ignoring external price")
+			}
+		})
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		s.ReportHealth(ctx, ComponentPriceFeed, incidents.StatusDegraded, err.Error())
+		s.logger.WithError(err).WithField("retry", retry).Warn("Price feed failed, resubscribing")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
+
+// markPrice is a symbol's fresh external price, falling back to its last trade and
+// then its listing reference price
+func (s *ExchangeService) markPrice(symbol string) (float64, error) {
+	if price, fresh := s.referencePrices.Latest(symbol, s.now()); fresh {
+		return price.Price, nil
+	}
+	return s.engine.LastPrice(symbol)
+}
+
+// priceFeedMaxAge is how long an external price anchors the venue, falling back to the default when unset
+func priceFeedMaxAge(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.PriceFeedMaxAge <= 0 {
+		return defaultPriceFeedMaxAge
+	}
+	return cfg.PriceFeedMaxAge
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/entitlements"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/idempotency"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+// RejectReason is a stable, machine-readable cause for refusing a request, so
+// callers can assert on why an order was rejected rather than on error text
+type RejectReason string
+
+const (
+	RejectInvalidRequest         RejectReason = "INVALID_REQUEST"
+	RejectInvalidAccount         RejectReason = "INVALID_ACCOUNT"
+	RejectInvalidSide            RejectReason = "INVALID_SIDE"
+	RejectInvalidOrderType       RejectReason = "INVALID_ORDER_TYPE"
+	RejectInvalidQuantity        RejectReason = "INVALID_QUANTITY"
+	RejectInvalidPrice           RejectReason = "INVALID_PRICE"
+	RejectPriceOutOfBand         RejectReason = "PRICE_OUT_OF_BAND"
+	RejectUnknownInstrument      RejectReason = "UNKNOWN_INSTRUMENT"
+	RejectInstrumentHalted       RejectReason = "INSTRUMENT_HALTED"
+	RejectMarketClosed           RejectReason = "MARKET_CLOSED"
+	RejectInvalidPhase           RejectReason = "INVALID_PHASE"
+	RejectOrderNotFound          RejectReason = "ORDER_NOT_FOUND"
+	RejectOrderNotActive         RejectReason = "ORDER_NOT_ACTIVE"
+	RejectInvalidAmend           RejectReason = "INVALID_AMEND"
+	RejectDuplicateClientOrderID RejectReason = "DUPLICATE_CLIENT_ORDER_ID"
+	RejectIdempotencyKeyReused   RejectReason = "IDEMPOTENCY_KEY_REUSED"
+	RejectInsufficientBalance    RejectReason = "INSUFFICIENT_BALANCE"
+	RejectInsufficientMargin     RejectReason = "INSUFFICIENT_MARGIN"
+	RejectEngineUnavailable      RejectReason = "ENGINE_UNAVAILABLE"
+	RejectSubscriptionLimit      RejectReason = "SUBSCRIPTION_LIMIT_EXCEEDED"
+	RejectAPIVersionRetired      RejectReason = "API_VERSION_RETIRED"
+	RejectPermissionDenied       RejectReason = "PERMISSION_DENIED"
+	RejectUnauthenticated        RejectReason = "UNAUTHENTICATED"
+	RejectRateLimited            RejectReason = "RATE_LIMITED"
+	RejectUnknown                RejectReason = "UNKNOWN"
+)
+
+// Rejection is an error tagged with its reason code
+type Rejection struct {
+	Reason  RejectReason `json:"code"`
+	Message string       `json:"message"`
+	err     error
+}
+
+// NewRejection wraps err with a reason; errors.Is still sees err
+func NewRejection(reason RejectReason, err error) *Rejection {
+	return &Rejection{Reason: reason, Message: err.Error(), err: err}
+}
+
+func (r *Rejection) Error() string {
+	return r.Message
+}
+
+func (r *Rejection) Unwrap() error {
+	return r.err
+}
+
+// rejectf builds a rejection from a formatted message
+func rejectf(reason RejectReason, format string, args ...interface{}) *Rejection {
+	return NewRejection(reason, fmt.Errorf(format, args...))
+}
+
+// RejectionOf classifies any error returned by this package or the matching engine
+func RejectionOf(err error) Rejection {
+	var rejection *Rejection
+	if errors.As(err, &rejection) {
+		return *rejection
+	}
+
+	reason := RejectUnknown
+	switch {
+	case errors.Is(err, ErrUnknownInstrument), errors.Is(err, matching.ErrUnknownSymbol):
+		reason = RejectUnknownInstrument
+	case errors.Is(err, matching.ErrHalted):
+		reason = RejectInstrumentHalted
+	case errors.Is(err, matching.ErrMarketClosed):
+		reason = RejectMarketClosed
+	case errors.Is(err, matching.ErrInvalidPhase):
+		reason = RejectInvalidPhase
+	case errors.Is(err, matching.ErrOrderNotFound):
+		reason = RejectOrderNotFound
+	case errors.Is(err, matching.ErrOrderNotActive):
+		reason = RejectOrderNotActive
+	case errors.Is(err, matching.ErrDuplicateClientOrderID):
+		reason = RejectDuplicateClientOrderID
+	case errors.Is(err, idempotency.ErrKeyReused):
+		reason = RejectIdempotencyKeyReused
+	case errors.Is(err, matching.ErrInvalidAmend):
+		reason = RejectInvalidAmend
+	case errors.Is(err, matching.ErrInvalidPurge), errors.Is(err, accounts.ErrNotFound), errors.Is(err, accounts.ErrClosed):
+		reason = RejectInvalidAccount
+	case errors.Is(err, matching.ErrEngineClosed):
+		reason = RejectEngineUnavailable
+	case errors.Is(err, entitlements.ErrStreamLimit), errors.Is(err, entitlements.ErrSubscriptionLimit):
+		reason = RejectSubscriptionLimit
+	}
+	return Rejection{Reason: reason, Message: err.Error(), err: err}
+}
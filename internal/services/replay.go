@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/replay"
+)
+
+// orderFlowReplayer resubmits one captured order flow at a time
+type orderFlowReplayer struct {
+	run    *replay.Run        // nil until a replay starts
+	cancel context.CancelFunc // Stops the run's pacing
+	mu     sync.Mutex
+}
+
+// StartReplay resubmits recording to the venue from source on a goroutine of its
+// own. Entries are paced on the wall clock at speed times the recorded pace, or
+// sent as fast as the venue takes them when speed is 0. Orders are placed under
+// their recorded accounts and client order IDs, and cancels and amends follow the
+// IDs the venue gives them. A replay still running must be stopped first.
+func (s *ExchangeService) StartReplay(ctx context.Context, recording replay.Recording, source string, speed float64) (replay.Progress, error) {
+	if speed < 0 {
+		return replay.Progress{}, rejectf(RejectInvalidRequest, "replay speed must not be negative, got %v", speed)
+	}
+	if len(recording.Entries) == 0 {
+		return replay.Progress{}, NewRejection(RejectInvalidRequest, replay.ErrEmptyRecording)
+	}
+	r := s.replays
+	r.mu.Lock()
+	if r.run != nil && !r.run.Done() {
+		r.mu.Unlock()
+		return replay.Progress{}, rejectf(RejectInvalidRequest, "order flow replay of %s is still running", r.run.Progress().Source)
+	}
+	run := replay.NewRun(recording, source, speed, s.now())
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.run, r.cancel = run, cancel
+	r.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"source":  source,
+		"entries": len(recording.Entries),
+		"speed":   speed,
+	}).Info("Order flow replay started")
+	go s.playReplay(runCtx, run)
+	return run.Progress(), nil
+}
+
+// ReplayProgress reports the replay running, or the last one
+func (s *ExchangeService) ReplayProgress(ctx context.Context) (replay.Progress, error) {
+	s.replays.mu.Lock()
+	run := s.replays.run
+	s.replays.mu.Unlock()
+	if run == nil {
+		return replay.Progress{}, replay.ErrNoReplay
+	}
+	return run.Progress(), nil
+}
+
+// StopReplay ends the replay before its remaining entries; orders it placed stay
+// on the book
+func (s *ExchangeService) StopReplay(ctx context.Context) (replay.Progress, error) {
+	r := s.replays
+	r.mu.Lock()
+	run, cancel := r.run, r.cancel
+	r.mu.Unlock()
+	if run == nil {
+		return replay.Progress{}, replay.ErrNoReplay
+	}
+	if !run.Stop(s.now()) {
+		return replay.Progress{}, rejectf(RejectInvalidRequest, "order flow replay of %s has already ended", run.Progress().Source)
+	}
+	cancel()
+	progress := run.Progress()
+	s.logger.WithFields(logrus.Fields{"source": progress.Source, "replayed": progress.Replayed}).Warn("Order flow replay stopped")
+	return progress, nil
+}
+
+// playReplay resubmits each entry once it is due, until the recording ends or the
+// replay is stopped
+func (s *ExchangeService) playReplay(ctx context.Context, run *replay.Run) {
+	started := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i, entry := range run.Recording().Entries {
+		due := started.Add(run.Due(i))
+		if wait := time.Until(due); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		outcome := s.replayEntry(ctx, run, entry)
+		outcome.Behind = time.Since(due)
+		run.Record(i, outcome, s.now())
+		if metrics := s.config.GetMetricsPort(); metrics != nil {
+			metrics.IncCounter("exchange_replay_requests_total", map[string]string{"kind": string(entry.Kind), "outcome": replayOutcome(outcome)})
+		}
+	}
+	progress := run.Progress()
+	s.logger.WithFields(logrus.Fields{
+		"source":   progress.Source,
+		"replayed": progress.Replayed,
+		"rejected": progress.Rejected,
+		"trades":   progress.Trades,
+	}).Info("Order flow replay completed")
+}
+
+func (s *ExchangeService) replayEntry(ctx context.Context, run *replay.Run, entry replay.Entry) replay.Outcome {
+	var outcome replay.Outcome
+	var err error
+	switch entry.Kind {
+	case replay.KindPlace:
+		var report *matching.ExecutionReport
+		report, err = s.PlaceOrder(ctx, s.replayedOrderRequest(run, entry))
+		if err == nil {
+			outcome.LiveOrderID, outcome.Trades, outcome.Duplicate = report.Order.ID, len(report.Trades), report.Duplicate
+		}
+	case replay.KindCancel:
+		live, ok := run.LiveOrderID(entry.OrderID)
+		if !ok {
+			return replay.Outcome{Skipped: true}
+		}
+		_, err = s.CancelOrder(ctx, live)
+	case replay.KindAmend:
+		live, ok := run.LiveOrderID(entry.OrderID)
+		if !ok {
+			return replay.Outcome{Skipped: true}
+		}
+		var report *matching.ExecutionReport
+		report, err = s.AmendOrder(ctx, live, entry.Amend)
+		if err == nil {
+			outcome.Trades = len(report.Trades)
+		}
+	}
+	if err != nil {
+		outcome.Rejection = string(RejectionOf(err).Reason)
+	}
+	return outcome
+}
+
+// replayedOrderRequest is a recorded order as placed again. A GTD order lives as
+// long as it was recorded to, scaled by the replay's speed.
+func (s *ExchangeService) replayedOrderRequest(run *replay.Run, entry replay.Entry) OrderRequest {
+	order := entry.Order
+	req := OrderRequest{
+		AccountID:     order.AccountID,
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Type:          order.Type,
+		TimeInForce:   order.TimeInForce,
+		Quantity:      order.Quantity,
+		Price:         order.Price,
+	}
+	if order.TimeInForce == models.TimeInForceGTD {
+		req.ExpiresAt = s.now().Add(run.Lifetime(order.ExpiresAt.Sub(entry.Time)))
+	}
+	return req
+}
+
+func replayOutcome(outcome replay.Outcome) string {
+	switch {
+	case outcome.Skipped:
+		return "skipped"
+	case outcome.Rejection != "":
+		return "rejected"
+	case outcome.Duplicate:
+		return "duplicate"
+	}
+	return "accepted"
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runbundle"
+)
+
+// runRecord is the manifest fixed at run start and the input files it names
+type runRecord struct {
+	manifest runbundle.Manifest
+	files    map[string][]byte
+}
+
+// StartRun fixes this run's manifest: the build, every setting, the synthetic seed in
+// use, the scenario files read at startup and the instruments listed. Call it once
+// startup configuration is complete and before serving.
+func (s *ExchangeService) StartRun(gitSHA string, files map[string][]byte) runbundle.Manifest {
+	manifest := runbundle.Manifest{
+		RunID:          s.config.ScenarioRunID,
+		Instance:       s.config.ServiceInstanceName,
+		StartedAt:      s.now(),
+		GitSHA:         gitSHA,
+		ServiceVersion: s.config.ServiceVersion,
+		Config:         s.config.Snapshot(),
+		Files:          make([]runbundle.File, 0, len(files)),
+		Instruments:    s.instruments.List(),
+	}
+	if s.synthetic != nil {
+		manifest.Seed = s.synthetic.config.Seed
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		manifest.Files = append(manifest.Files, runbundle.NewFile(path, files[path]))
+	}
+
+	s.run = &runRecord{manifest: manifest, files: files}
+	return manifest
+}
+
+// RunManifest returns the manifest fixed at run start
+func (s *ExchangeService) RunManifest(ctx context.Context) (runbundle.Manifest, error) {
+	if s.run == nil {
+		return runbundle.Manifest{}, runbundle.ErrNotStarted
+	}
+	return s.run.manifest, nil
+}
+
+// RunBundle gathers the manifest, every event the engine has recorded and the
+// metrics so far. The journal is read back from the event log, so a run can only be
+// bundled when it has one.
+func (s *ExchangeService) RunBundle(ctx context.Context) (runbundle.Bundle, error) {
+	if s.run == nil {
+		return runbundle.Bundle{}, runbundle.ErrNotStarted
+	}
+	journal, err := s.readJournal()
+	if err != nil {
+		return runbundle.Bundle{}, err
+	}
+	return runbundle.Bundle{
+		Manifest: s.run.manifest,
+		Journal:  journal,
+		Metrics:  s.CurrentMetrics(ctx),
+		Files:    s.run.files,
+	}, nil
+}
+
+func (s *ExchangeService) readJournal() ([]matching.Event, error) {
+	switch log := s.eventLog.(type) {
+	case EventLogBackend:
+		return log.ReadAll()
+	case *matching.MemoryEventLog:
+		return log.Events(), nil
+	}
+	return nil, runbundle.ErrNoJournal
+}
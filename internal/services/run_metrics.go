@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/runmetrics"
+)
+
+// maxMetricsSnapshots bounds one snapshot query when the caller sets no limit
+const maxMetricsSnapshots = 10000
+
+// RunID identifies the scenario run this venue's metrics are saved under
+func (s *ExchangeService) RunID() string {
+	return s.config.ScenarioRunID
+}
+
+// SetMetricsStore persists metrics snapshots to store and answers queries from it
+func (s *ExchangeService) SetMetricsStore(store runmetrics.Store) {
+	s.metricsStore = store
+}
+
+// CurrentMetrics reports the run's metrics so far without persisting them
+func (s *ExchangeService) CurrentMetrics(ctx context.Context) runmetrics.Snapshot {
+	return s.identify(s.runMetrics.Current(s.now(), s.metricsCounts()))
+}
+
+// SaveMetricsSnapshot persists the metrics since the last snapshot
+func (s *ExchangeService) SaveMetricsSnapshot() error {
+	if s.metricsStore == nil {
+		return runmetrics.ErrNotPersisted
+	}
+	snapshot := s.identify(s.runMetrics.Take(s.now(), s.metricsCounts()))
+	return s.persist(componentMetrics, "save", logrus.Fields{"run_id": snapshot.RunID}, func() error {
+		return s.metricsStore.Save(snapshot)
+	})
+}
+
+// PersistMetricsSnapshots saves a snapshot every interval until ctx is done
+func (s *ExchangeService) PersistMetricsSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SaveMetricsSnapshot(); err != nil {
+				s.logger.WithError(err).Warn("Failed to persist metrics snapshot")
+			}
+		}
+	}
+}
+
+// MetricsRuns lists the runs with persisted snapshots, most recent first
+func (s *ExchangeService) MetricsRuns(ctx context.Context) ([]runmetrics.Run, error) {
+	if s.metricsStore == nil {
+		return nil, runmetrics.ErrNotPersisted
+	}
+	return s.metricsStore.Runs()
+}
+
+// MetricsSnapshots returns a run's persisted snapshots, oldest first
+func (s *ExchangeService) MetricsSnapshots(ctx context.Context, query runmetrics.Query) ([]runmetrics.Snapshot, error) {
+	if s.metricsStore == nil {
+		return nil, runmetrics.ErrNotPersisted
+	}
+	if query.RunID == "" {
+		return nil, rejectf(RejectInvalidRequest, "run ID is required")
+	}
+	if query.Limit < 0 {
+		return nil, rejectf(RejectInvalidRequest, "limit must not be negative")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, rejectf(RejectInvalidRequest, "to must not be before from")
+	}
+	if query.Limit == 0 || query.Limit > maxMetricsSnapshots {
+		query.Limit = maxMetricsSnapshots
+	}
+	var snapshots []runmetrics.Snapshot
+	err := s.timeStorage(componentMetrics, "query", logrus.Fields{"run_id": query.RunID}, func() (err error) {
+		snapshots, err = s.metricsStore.Snapshots(query)
+		return err
+	})
+	return snapshots, err
+}
+
+// metricsCounts totals order actions across every API key
+func (s *ExchangeService) metricsCounts() runmetrics.Counts {
+	var counts runmetrics.Counts
+	for _, stats := range s.keyStats.All() {
+		counts.Orders += stats.Orders
+		counts.Cancels += stats.Cancels
+		counts.Amends += stats.Amends
+		counts.Trades += stats.Trades
+		counts.Rejections += stats.Rejections
+	}
+	return counts
+}
+
+func (s *ExchangeService) identify(snapshot runmetrics.Snapshot) runmetrics.Snapshot {
+	snapshot.RunID = s.config.ScenarioRunID
+	snapshot.Instance = s.config.ServiceInstanceName
+	return snapshot
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
+)
+
+// defaultScenarioEvents is how many events a scenario run keeps when unconfigured
+const defaultScenarioEvents = 100000
+
+// scenarioRun is the loaded scenario and the events recorded for its assertions
+type scenarioRun struct {
+	scenario scenario.Scenario
+	recorder *scenario.Recorder
+}
+
+// SetScenario records venue events from now on and judges them against the
+// scenario's assertions; set before serving
+func (s *ExchangeService) SetScenario(loaded scenario.Scenario) {
+	s.scenario = &scenarioRun{scenario: loaded, recorder: scenario.NewRecorder(scenarioEvents(s.config))}
+}
+
+// ScenarioVerdict evaluates the scenario's assertions against the events so far.
+// final marks the verdict taken at scenario end.
+func (s *ExchangeService) ScenarioVerdict(ctx context.Context, final bool) (scenario.Verdict, error) {
+	if s.scenario == nil {
+		return scenario.Verdict{}, scenario.ErrNoScenario
+	}
+	events, dropped := s.scenario.recorder.Events()
+	verdict := s.scenario.scenario.Evaluate(events, s.now())
+	verdict.RunID = s.config.ScenarioRunID
+	verdict.Instance = s.config.ServiceInstanceName
+	verdict.Final = final
+	verdict.Dropped = dropped
+	return verdict, nil
+}
+
+func (s *ExchangeService) recordScenario(event scenario.Event) {
+	if s.scenario == nil {
+		return
+	}
+	s.scenario.recorder.Record(event)
+}
+
+func (s *ExchangeService) recordOrderEvent(order models.Order) {
+	s.recordScenario(scenario.Event{
+		Type:     scenario.EventOrder,
+		Time:     s.now(),
+		Symbol:   order.Symbol,
+		Accounts: []string{order.AccountID},
+		Status:   string(order.Status),
+	})
+}
+
+func (s *ExchangeService) recordTradeEvent(trade models.Trade) {
+	s.recordScenario(scenario.Event{
+		Type:     scenario.EventTrade,
+		Time:     trade.ExecutedAt,
+		Symbol:   trade.Symbol,
+		Accounts: []string{trade.BuyAccountID, trade.SellAccountID},
+	})
+}
+
+func (s *ExchangeService) recordFlagEvent(flag surveillance.Flag) {
+	s.recordScenario(scenario.Event{
+		Type:     scenario.EventSurveillanceFlag,
+		Time:     flag.RaisedAt,
+		Symbol:   flag.Symbol,
+		Accounts: []string{flag.AccountID},
+		Status:   string(flag.Type),
+	})
+}
+
+// scenarioEvents is how many events to keep, falling back to the default when unset
+func scenarioEvents(cfg *config.Config) int {
+	if cfg == nil || cfg.ScenarioEventHistory <= 0 {
+		return defaultScenarioEvents
+	}
+	return cfg.ScenarioEventHistory
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/pricefeed"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/scenario"
+)
+
+// ComponentOrchestrator is the orchestrator receiving script progress on the incident timeline
+const ComponentOrchestrator = "dependency:orchestrator"
+
+// scriptReportTimeout bounds each progress report to the orchestrator
+const scriptReportTimeout = 5 * time.Second
+
+// scriptPlayer plays the scenario script an orchestrator provided
+type scriptPlayer struct {
+	run      *scenario.ScriptRun    // nil until a script starts
+	reporter ports.ScenarioReporter // nil keeps progress to the API
+	mu       sync.Mutex             // Held while steps execute, so each runs once and in order
+}
+
+// SetScenarioReporter sends script progress to the orchestrator; set before serving
+func (s *ExchangeService) SetScenarioReporter(reporter ports.ScenarioReporter) {
+	s.scripts.reporter = reporter
+}
+
+// StartScript plays script from the current venue time. Steps due straight away run
+// before it returns; the rest run with the scheduled work once due, so a simulated
+// clock paces them. A script still running must be stopped first.
+func (s *ExchangeService) StartScript(ctx context.Context, script scenario.Script) (scenario.Progress, error) {
+	if err := s.checkScript(script); err != nil {
+		return scenario.Progress{}, err
+	}
+	p := s.scripts
+	p.mu.Lock()
+	if p.run != nil && !p.run.Done() {
+		p.mu.Unlock()
+		return scenario.Progress{}, rejectf(RejectInvalidRequest, "scenario script %q is still running", p.run.Progress().Script)
+	}
+	p.run = scenario.NewScriptRun(script, s.now())
+	p.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"script": script.Name,
+		"steps":  len(script.Steps),
+	}).Info("Scenario script started")
+	s.playScript(ctx)
+	return s.ScriptProgress(ctx)
+}
+
+// checkScript refuses a script whose steps name unlisted symbols or need a part of
+// the venue that is off
+func (s *ExchangeService) checkScript(script scenario.Script) error {
+	for i, step := range script.Steps {
+		if step.Symbol != "" {
+			if _, err := s.instruments.Get(step.Symbol); err != nil {
+				return err
+			}
+		}
+		switch step.Action {
+		case scenario.ActionOutage:
+			if s.chaos == nil {
+				return rejectf(RejectInvalidRequest, "step %d is an outage, which needs chaos injection enabled", i+1)
+			}
+		case scenario.ActionLiquidity:
+			if s.marketMaker == nil {
+				return rejectf(RejectInvalidRequest, "step %d changes liquidity, which needs the market maker enabled", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// ScriptProgress reports the script being played, or the last one
+func (s *ExchangeService) ScriptProgress(ctx context.Context) (scenario.Progress, error) {
+	s.scripts.mu.Lock()
+	run := s.scripts.run
+	s.scripts.mu.Unlock()
+	if run == nil {
+		return scenario.Progress{}, scenario.ErrNoScript
+	}
+	return s.scriptProgress(run), nil
+}
+
+// StopScript ends the script before its remaining steps. What it changed stays
+// changed: halted symbols stay halted and faults stay injected.
+func (s *ExchangeService) StopScript(ctx context.Context) (scenario.Progress, error) {
+	p := s.scripts
+	p.mu.Lock()
+	run := p.run
+	stopped := run != nil && run.Stop(s.now())
+	p.mu.Unlock()
+	if run == nil {
+		return scenario.Progress{}, scenario.ErrNoScript
+	}
+	if !stopped {
+		return scenario.Progress{}, rejectf(RejectInvalidRequest, "scenario script %q has already ended", run.Progress().Script)
+	}
+	progress := s.scriptProgress(run)
+	s.logger.WithFields(logrus.Fields{"script": progress.Script, "executed": progress.Executed}).Warn("Scenario script stopped")
+	s.reportScript(ctx, progress)
+	return progress, nil
+}
+
+// playScript runs the steps that have come due, then reports the progress they made
+func (s *ExchangeService) playScript(ctx context.Context) {
+	p := s.scripts
+	p.mu.Lock()
+	run := p.run
+	if run == nil || run.Done() {
+		p.mu.Unlock()
+		return
+	}
+	steps, first := run.Due(s.now())
+	for i, step := range steps {
+		err := s.playStep(ctx, step)
+		run.Record(first+i, s.now(), err)
+		entry := s.logger.WithFields(logrus.Fields{"step": first + i + 1, "action": step.Action, "symbol": step.Symbol})
+		if err != nil {
+			entry.WithError(err).Warn("Scenario script step failed")
+		} else {
+			entry.Info("Scenario script step executed")
+		}
+	}
+	p.mu.Unlock()
+
+	if len(steps) == 0 {
+		return
+	}
+	progress := s.scriptProgress(run)
+	if progress.State == scenario.ScriptCompleted {
+		s.logger.WithFields(logrus.Fields{"script": progress.Script, "failed": progress.Failed}).Info("Scenario script completed")
+	}
+	s.reportScript(ctx, progress)
+}
+
+func (s *ExchangeService) playStep(ctx context.Context, step scenario.Step) error {
+	switch step.Action {
+	case scenario.ActionPriceMove:
+		price := step.Price
+		if step.MovePct != 0 {
+			mark, err := s.markPrice(step.Symbol)
+			if err != nil {
+				return err
+			}
+			price = mark * (1 + step.MovePct/100)
+		}
+		_, err := s.UpdateReferencePrice(ctx, pricefeed.Price{Symbol: step.Symbol, Price: price, Source: "scenario", Time: s.now()})
+		return err
+	case scenario.ActionHalt:
+		_, err := s.HaltTrading(ctx, step.Symbol)
+		return err
+	case scenario.ActionResume:
+		return s.ResumeTrading(ctx, step.Symbol)
+	case scenario.ActionLiquidity:
+		return s.rescaleMarketMaker(step.Symbol, step.Scale, step.SpreadBps)
+	case scenario.ActionOutage:
+		_, err := s.InjectFault(ctx, *step.Fault)
+		return err
+	}
+	return rejectf(RejectInvalidRequest, "unknown scenario action %q", step.Action)
+}
+
+func (s *ExchangeService) scriptProgress(run *scenario.ScriptRun) scenario.Progress {
+	progress := run.Progress()
+	progress.RunID = s.config.ScenarioRunID
+	progress.Instance = s.config.ServiceInstanceName
+	return progress
+}
+
+// reportScript sends progress to the orchestrator, which shows on the incident
+// timeline while it cannot be reached
+func (s *ExchangeService) reportScript(ctx context.Context, progress scenario.Progress) {
+	reporter := s.scripts.reporter
+	if reporter == nil {
+		return
+	}
+	reportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), scriptReportTimeout)
+	defer cancel()
+	err := reporter.ReportProgress(reportCtx, progress)
+	if err != nil {
+		s.logger.WithError(err).WithField("script", progress.Script).Warn("Failed to report scenario script progress")
+	}
+	s.reportOutcome(ComponentOrchestrator, err)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/timerwheel"
+)
+
+// TransitionAction is a session phase change the scheduler can run
+type TransitionAction string
+
+const (
+	TransitionOpeningAuction TransitionAction = "opening_auction" // Start the opening call auction
+	TransitionClosingAuction TransitionAction = "closing_auction" // Start the closing call auction
+	TransitionUncross        TransitionAction = "uncross"         // Uncross the running auction
+)
+
+// TransitionStatus is the lifecycle state of a scheduled transition
+type TransitionStatus string
+
+const (
+	TransitionScheduled TransitionStatus = "scheduled"
+	TransitionDone      TransitionStatus = "done"
+	TransitionFailed    TransitionStatus = "failed"
+)
+
+// PhaseTransition is a session transition due at a venue time
+type PhaseTransition struct {
+	ID         string           `json:"id"`
+	Symbol     string           `json:"symbol"`
+	Action     TransitionAction `json:"action"`
+	At         time.Time        `json:"at"`
+	Status     TransitionStatus `json:"status"`
+	Error      string           `json:"error,omitempty"`
+	ExecutedAt time.Time        `json:"executed_at,omitempty"`
+}
+
+// transitionSchedule holds transitions in due order, with the ones still to run on a
+// timer wheel so a run only touches what has come due
+type transitionSchedule struct {
+	transitions []*PhaseTransition
+	pending     *timerwheel.Wheel[*PhaseTransition]
+	nextID      uint64
+	mu          sync.Mutex
+}
+
+func newTransitionSchedule(now time.Time) *transitionSchedule {
+	return &transitionSchedule{
+		transitions: make([]*PhaseTransition, 0),
+		pending:     timerwheel.New[*PhaseTransition](timerwheel.DefaultPrecision, now),
+	}
+}
+
+// ScheduleTransition queues a session transition for a symbol at a future venue time
+func (s *ExchangeService) ScheduleTransition(ctx context.Context, symbol string, action TransitionAction, at time.Time) (PhaseTransition, error) {
+	if _, err := s.instruments.Get(symbol); err != nil {
+		return PhaseTransition{}, err
+	}
+	switch action {
+	case TransitionOpeningAuction, TransitionClosingAuction, TransitionUncross:
+	default:
+		return PhaseTransition{}, rejectf(RejectInvalidRequest, "invalid transition action: %q", action)
+	}
+	if !at.After(s.now()) {
+		return PhaseTransition{}, rejectf(RejectInvalidRequest, "transition time %s is not in the future", at.Format(time.RFC3339))
+	}
+
+	schedule := s.transitions
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	schedule.nextID++
+	transition := &PhaseTransition{
+		ID:     fmt.Sprintf("trn-%d", schedule.nextID),
+		Symbol: symbol,
+		Action: action,
+		At:     at,
+		Status: TransitionScheduled,
+	}
+	schedule.transitions = append(schedule.transitions, transition)
+	sort.SliceStable(schedule.transitions, func(i, j int) bool {
+		return schedule.transitions[i].At.Before(schedule.transitions[j].At)
+	})
+	schedule.pending.Schedule(at, transition)
+
+	s.logger.WithFields(logrus.Fields{
+		"transition_id": transition.ID,
+		"symbol":        symbol,
+		"action":        action,
+		"at":            at,
+	}).Info("Session transition scheduled")
+	return *transition, nil
+}
+
+// PhaseTransitions lists transitions in due order, optionally for one symbol
+func (s *ExchangeService) PhaseTransitions(ctx context.Context, symbol string) []PhaseTransition {
+	s.transitions.mu.Lock()
+	defer s.transitions.mu.Unlock()
+
+	transitions := make([]PhaseTransition, 0)
+	for _, transition := range s.transitions.transitions {
+		if symbol == "" || transition.Symbol == symbol {
+			transitions = append(transitions, *transition)
+		}
+	}
+	return transitions
+}
+
+// RunScheduledWork does everything due at the current venue time: expiring GTD
+// orders, running session transitions, activating instrument changes, publishing
+// funding and exchange statistics, liquidating accounts below maintenance margin,
+// playing scenario script steps, noting circuit breaker halts and running settlement
+// cycles, then refreshes the book and account activity metrics. Orchestrators stepping a simulated clock
+// call it after each step.
+func (s *ExchangeService) RunScheduledWork(ctx context.Context) {
+	s.expireOrders(ctx)
+	s.runTransitions(ctx)
+	s.ActivateInstrumentChanges(ctx)
+	s.publishFunding(s.now())
+	s.publishExchangeStatistics(ctx, s.now())
+	s.liquidate(ctx)
+	s.playScript(ctx)
+	s.expireFaults(ctx)
+	s.reconcileHalts(ctx)
+	s.runSettlementCycles(ctx)
+	s.reportBookMetrics()
+	s.reportAccountMetrics()
+}
+
+// RunScheduler runs scheduled work every interval of wall time until ctx is done.
+// Under an accelerated clock each run covers interval times the clock speed.
+func (s *ExchangeService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunScheduledWork(ctx)
+		}
+	}
+}
+
+func (s *ExchangeService) expireOrders(ctx context.Context) {
+	expired, err := s.engine.ExpireOrders()
+	for _, order := range expired {
+		s.auditOrder(ctx, audit.TypeOrderExpired, order, "")
+		s.publishActivity(order.Symbol, []models.Order{order}, nil)
+		s.logger.WithFields(logrus.Fields{
+			"order_id":   order.ID,
+			"account":    order.AccountID,
+			"symbol":     order.Symbol,
+			"expires_at": order.ExpiresAt,
+		}).Info("Order expired")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to expire GTD orders")
+	}
+}
+
+func (s *ExchangeService) runTransitions(ctx context.Context) {
+	schedule := s.transitions
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	now := s.now()
+	for _, transition := range schedule.pending.Advance(now) {
+		var err error
+		switch transition.Action {
+		case TransitionOpeningAuction:
+			err = s.StartAuction(ctx, transition.Symbol, matching.AuctionOpening)
+		case TransitionClosingAuction:
+			err = s.StartAuction(ctx, transition.Symbol, matching.AuctionClosing)
+		case TransitionUncross:
+			_, err = s.UncrossAuction(ctx, transition.Symbol)
+		}
+		transition.ExecutedAt = now
+		transition.Status = TransitionDone
+		if err != nil {
+			// A transition that no longer fits the book's phase is not retried
+			transition.Status = TransitionFailed
+			transition.Error = err.Error()
+			s.logger.WithError(err).WithField("transition_id", transition.ID).Warn("Session transition failed")
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/fixture"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// SeedReport is what a fixture seeded
+type SeedReport struct {
+	Fixture     string `json:"fixture"`
+	Instruments int    `json:"instruments"`
+	Accounts    int    `json:"accounts"`
+	Orders      int    `json:"orders"`
+	Skipped     bool   `json:"skipped,omitempty"` // The venue already held accounts or orders, so only instruments were listed
+}
+
+// SeedFixture lists the fixture's instruments, then, on a cold venue that holds no
+// accounts and no orders, opens its accounts with their opening balances and rests
+// its orders in file order. Instruments live in memory only, so they are listed on
+// every start; a warm venue keeps the accounts and orders it restored. An order the
+// venue refuses stops the seeding, leaving what was seeded before it.
+func (s *ExchangeService) SeedFixture(ctx context.Context, seed fixture.Fixture) (SeedReport, error) {
+	report := SeedReport{Fixture: seed.Name}
+	for _, listed := range seed.Instruments {
+		instrument := listed.Definition()
+		s.instruments.Upsert(instrument)
+		if err := s.engine.AddBook(instrument.Symbol, instrument.ReferencePrice); err != nil {
+			return report, fmt.Errorf("failed to open book for %s: %w", instrument.Symbol, err)
+		}
+		report.Instruments++
+	}
+
+	if len(s.accounts.directory.List("", "")) > 0 || len(s.engine.Orders("", "")) > 0 {
+		report.Skipped = true
+		s.logger.WithFields(logrus.Fields{
+			"fixture":     seed.Name,
+			"instruments": report.Instruments,
+		}).Info("Venue restored its state; fixture accounts and orders skipped")
+		return report, nil
+	}
+
+	if err := s.seedAccounts(ctx, seed.Accounts); err != nil {
+		return report, err
+	}
+	report.Accounts = len(seed.Accounts)
+
+	for i, order := range seed.Orders {
+		_, err := s.PlaceOrder(ctx, OrderRequest{
+			AccountID:     order.AccountID,
+			ClientOrderID: order.ClientOrderID,
+			Symbol:        order.Symbol,
+			Side:          order.Side,
+			Type:          models.OrderTypeLimit,
+			TimeInForce:   order.TimeInForce,
+			Quantity:      order.Quantity,
+			Price:         order.Price,
+			ExpiresAt:     order.ExpiresAt,
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to place fixture order %d: %w", i+1, err)
+		}
+		report.Orders++
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"fixture":     seed.Name,
+		"instruments": report.Instruments,
+		"accounts":    report.Accounts,
+		"orders":      report.Orders,
+	}).Info("Fixture seeded")
+	return report, nil
+}
+
+// seedAccounts opens the fixture's accounts under their own IDs and funds them
+func (s *ExchangeService) seedAccounts(ctx context.Context, seeded []fixture.Account) error {
+	if len(seeded) == 0 {
+		return nil
+	}
+	batch := make([]accounts.Account, 0, len(seeded))
+	for _, listed := range seeded {
+		account, err := s.newAccount(listed.Template())
+		if err != nil {
+			return fmt.Errorf("invalid fixture account %s: %w", listed.ID, err)
+		}
+		account.ID = listed.ID
+		if listed.APIKey != "" {
+			account.APIKeyHash = accounts.HashAPIKey(listed.APIKey)
+		}
+		batch = append(batch, account)
+	}
+
+	registry := s.accounts
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if err := s.saveAccounts(batch...); err != nil {
+		return err
+	}
+	s.fundAccounts(ctx, batch...)
+	return nil
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/audit"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+)
+
+// ErrSessionNotFound is returned when closing a session that is not open
+var ErrSessionNotFound = errors.New("session not found")
+
+const (
+	defaultSessionGracePeriod = 5 * time.Second
+	defaultSessionHeartbeat   = 10 * time.Second
+)
+
+// SessionOptions are chosen by the client when it opens a session
+type SessionOptions struct {
+	CancelOnDisconnect bool          // Cancel the account's open orders when the session drops
+	GracePeriod        time.Duration // Wait for a reconnect before canceling; zero uses the venue default
+}
+
+// Session is a live connection of an account to the venue, e.g. a gRPC stream
+type Session struct {
+	ID                 string        `json:"id"`
+	AccountID          string        `json:"account_id"`
+	CancelOnDisconnect bool          `json:"cancel_on_disconnect"`
+	GracePeriod        time.Duration `json:"grace_period"`
+	Heartbeat          time.Duration `json:"heartbeat"`
+	OpenedAt           time.Time     `json:"opened_at"`
+}
+
+// sessionRegistry tracks open sessions and the cancel-on-disconnect timers of dropped ones
+type sessionRegistry struct {
+	sessions map[string]*Session
+	pending  map[string]*time.Timer // Account ID to its armed cancel-on-disconnect
+	nextID   uint64
+	closing  chan struct{}
+	closed   bool
+	mu       sync.Mutex
+}
+
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{
+		sessions: make(map[string]*Session),
+		pending:  make(map[string]*time.Timer),
+		closing:  make(chan struct{}),
+	}
+}
+
+// OpenSession registers a session for an account. Opening one while a dropped
+// session's grace period is running withdraws the pending cancel.
+func (s *ExchangeService) OpenSession(ctx context.Context, accountID string, opts SessionOptions) (Session, error) {
+	if accountID == "" {
+		return Session{}, rejectf(RejectInvalidAccount, "account id is required")
+	}
+	// A session can cancel the account's orders when it drops, so it takes the trade permission
+	if err := s.authorizeKey(ctx, accountID, accounts.PermissionTrade); err != nil {
+		return Session{}, err
+	}
+	if opts.GracePeriod < 0 {
+		return Session{}, rejectf(RejectInvalidRequest, "grace period must not be negative")
+	}
+	if opts.GracePeriod == 0 {
+		opts.GracePeriod = sessionGracePeriod(s.config)
+	}
+
+	registry := s.sessions
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.closed {
+		return Session{}, rejectf(RejectEngineUnavailable, "venue is shutting down")
+	}
+	registry.nextID++
+	session := &Session{
+		ID:                 fmt.Sprintf("sess-%d", registry.nextID),
+		AccountID:          accountID,
+		CancelOnDisconnect: opts.CancelOnDisconnect,
+		GracePeriod:        opts.GracePeriod,
+		Heartbeat:          sessionHeartbeat(s.config),
+		OpenedAt:           time.Now(),
+	}
+	registry.sessions[session.ID] = session
+
+	if timer, armed := registry.pending[accountID]; armed {
+		timer.Stop()
+		delete(registry.pending, accountID)
+		s.logger.WithField("account", accountID).Info("Session reopened within grace period, cancel-on-disconnect withdrawn")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"session_id":           session.ID,
+		"account":              accountID,
+		"cancel_on_disconnect": session.CancelOnDisconnect,
+		"grace_period":         session.GracePeriod,
+	}).Info("Session opened")
+	return *session, nil
+}
+
+// CloseSession ends a session. If it asked for cancel-on-disconnect and it was the
+// account's last open session, the account's open orders are canceled once the grace
+// period passes without a new session.
+func (s *ExchangeService) CloseSession(ctx context.Context, sessionID string) error {
+	registry := s.sessions
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	session, exists := registry.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	delete(registry.sessions, sessionID)
+	s.logger.WithFields(logrus.Fields{
+		"session_id": sessionID,
+		"account":    session.AccountID,
+	}).Info("Session closed")
+
+	if !session.CancelOnDisconnect || registry.closed {
+		return nil
+	}
+	for _, other := range registry.sessions {
+		if other.AccountID == session.AccountID {
+			return nil
+		}
+	}
+
+	accountID := session.AccountID
+	if timer, armed := registry.pending[accountID]; armed {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(session.GracePeriod, func() {
+		registry.mu.Lock()
+		current := registry.pending[accountID] == timer
+		if current {
+			delete(registry.pending, accountID)
+		}
+		registry.mu.Unlock()
+		if current {
+			s.cancelOnDisconnect(accountID)
+		}
+	})
+	registry.pending[accountID] = timer
+	return nil
+}
+
+// Sessions lists open sessions in the order they were opened
+func (s *ExchangeService) Sessions(ctx context.Context) []Session {
+	s.sessions.mu.Lock()
+	defer s.sessions.mu.Unlock()
+
+	sessions := make([]Session, 0, len(s.sessions.sessions))
+	for _, session := range s.sessions.sessions {
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].OpenedAt.Before(sessions[j].OpenedAt) ||
+			(sessions[i].OpenedAt.Equal(sessions[j].OpenedAt) && sessions[i].ID < sessions[j].ID)
+	})
+	return sessions
+}
+
+// SessionsClosing is closed by ShutdownSessions; session transports end their streams on it
+func (s *ExchangeService) SessionsClosing() <-chan struct{} {
+	return s.sessions.closing
+}
+
+// ShutdownSessions ends every session for a venue shutdown. Orders are left working,
+// since a restart restores them from the event log; armed cancels are dropped.
+func (s *ExchangeService) ShutdownSessions() {
+	registry := s.sessions
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.closed {
+		return
+	}
+	registry.closed = true
+	close(registry.closing)
+	for accountID, timer := range registry.pending {
+		timer.Stop()
+		delete(registry.pending, accountID)
+	}
+}
+
+// cancelOnDisconnect cancels every open order of an account whose session dropped
+func (s *ExchangeService) cancelOnDisconnect(accountID string) int {
+	canceled := s.cancelOpenOrders(context.Background(), accountID, cancelDisconnect)
+	s.logger.WithFields(logrus.Fields{
+		"account":  accountID,
+		"canceled": canceled,
+	}).Warn("Session dropped, open orders canceled on disconnect")
+	return canceled
+}
+
+// cancelOpenOrders cancels every open order of an account across books, auditing
+// each with reason
+func (s *ExchangeService) cancelOpenOrders(ctx context.Context, accountID, reason string) int {
+	canceled := 0
+	for _, order := range s.engine.OpenOrders(accountID, "") {
+		canceledOrder, err := s.engine.Cancel(order.ID)
+		if err != nil {
+			// Filled or canceled since it was listed
+			continue
+		}
+		s.auditOrder(ctx, audit.TypeOrderCanceled, canceledOrder, reason)
+		s.publishActivity(canceledOrder.Symbol, []models.Order{canceledOrder}, nil)
+		canceled++
+	}
+	return canceled
+}
+
+func sessionGracePeriod(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.SessionGracePeriod <= 0 {
+		return defaultSessionGracePeriod
+	}
+	return cfg.SessionGracePeriod
+}
+
+func sessionHeartbeat(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.SessionHeartbeat <= 0 {
+		return defaultSessionHeartbeat
+	}
+	return cfg.SessionHeartbeat
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/chaos"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+)
+
+// anyInstrument keys the fee rates of instruments a fee schedule does not name
+const anyInstrument = "*"
+
+// HotSettings names the configuration keys the venue reloads as they change; a key
+// left empty is not watched
+type HotSettings struct {
+	RateLimits  string // ratelimit.Limits
+	FeeSchedule string // FeeSchedule
+	Chaos       string // ChaosSettings
+}
+
+// FeeRates are an instrument's maker and taker fee rates; negative rates are rebates
+type FeeRates struct {
+	MakerFeeRate float64 `json:"maker_fee_rate"`
+	TakerFeeRate float64 `json:"taker_fee_rate"`
+}
+
+// FeeSchedule sets fee rates by symbol, with "*" covering instruments not named
+type FeeSchedule map[string]FeeRates
+
+// ChaosSettings are the chaos settings the configuration service may change
+type ChaosSettings struct {
+	DegradationMode chaos.Mode `json:"degradation_mode,omitempty"` // Empty leaves the mode as it is
+}
+
+// ReloadSettings applies the value under each of settings' keys as watcher reports
+// it, and again whenever it changes, until unsubscribe is called. Settings changed
+// through the admin API stand until the next change at the source.
+func (s *ExchangeService) ReloadSettings(watcher ports.SettingWatcher, settings HotSettings) (unsubscribe func()) {
+	var unsubscribes []func()
+	follow := func(key string, apply func(ctx context.Context, value json.RawMessage) error) {
+		if key != "" {
+			unsubscribes = append(unsubscribes, watcher.Subscribe(key, apply))
+		}
+	}
+	follow(settings.RateLimits, s.applyRateLimits)
+	follow(settings.FeeSchedule, s.applyFeeSchedule)
+	follow(settings.Chaos, s.applyChaosSettings)
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
+
+// SetFeeSchedule replaces the fee rates of the instruments schedule covers. A
+// schedule naming an unknown instrument or an out-of-range rate changes nothing.
+func (s *ExchangeService) SetFeeSchedule(ctx context.Context, schedule FeeSchedule) error {
+	if err := schedule.validateRates(); err != nil {
+		return err
+	}
+	for symbol := range schedule {
+		if symbol != anyInstrument {
+			if _, err := s.instruments.Get(symbol); err != nil {
+				return NewRejection(RejectInvalidRequest, err)
+			}
+		}
+	}
+	updated := 0
+	for _, instrument := range s.instruments.List() {
+		rates, exists := schedule[instrument.Symbol]
+		if !exists {
+			if rates, exists = schedule[anyInstrument]; !exists {
+				continue
+			}
+		}
+		instrument.MakerFeeRate, instrument.TakerFeeRate = rates.MakerFeeRate, rates.TakerFeeRate
+		s.instruments.Upsert(instrument)
+		updated++
+	}
+	s.logger.WithField("instruments", updated).Info("Fee schedule updated")
+	return nil
+}
+
+// validateRates checks every rate is a fraction between -1 and 1
+func (schedule FeeSchedule) validateRates() error {
+	for symbol, rates := range schedule {
+		if rates.MakerFeeRate <= -1 || rates.MakerFeeRate >= 1 || rates.TakerFeeRate <= -1 || rates.TakerFeeRate >= 1 {
+			return rejectf(RejectInvalidRequest, "fee rates for %s must be fractions between -1 and 1", symbol)
+		}
+	}
+	return nil
+}
+
+func (s *ExchangeService) applyFeeSchedule(ctx context.Context, raw json.RawMessage) error {
+	var schedule FeeSchedule
+	if err := json.Unmarshal(raw, &schedule); err != nil {
+		return fmt.Errorf("invalid fee schedule: %w", err)
+	}
+	return s.SetFeeSchedule(ctx, schedule)
+}
+
+func (s *ExchangeService) applyChaosSettings(ctx context.Context, raw json.RawMessage) error {
+	var settings ChaosSettings
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return fmt.Errorf("invalid chaos settings: %w", err)
+	}
+	if settings.DegradationMode == "" {
+		return nil
+	}
+	_, err := s.SetDegradationMode(ctx, settings.DegradationMode)
+	return err
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ports"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+// ComponentCustodian is the custodian-simulator settlement instructions are submitted to
+const ComponentCustodian = "dependency:custodian"
+
+// componentSettlement is the outbox settlement instructions are kept in until acknowledged
+const componentSettlement = "storage:settlement"
+
+// defaultSettlementRetry is the dispatch interval and first retry backoff when unconfigured
+const defaultSettlementRetry = time.Second
+
+// settlementDesk turns spot fills into settlement instructions and submits them to
+// the custodian until each is acknowledged or rejected
+type settlementDesk struct {
+	outbox    *settlement.Outbox
+	gateway   ports.SettlementGateway // nil when fills are not settled with a custodian
+	store     settlement.Store        // nil keeps the outbox in memory only
+	netting   time.Duration           // Between netted batches (0 = gross instructions per fill)
+	batchSize int                     // Instructions per submission (0 = all that are due)
+	pending   []settlement.Obligation // Fills not yet turned into instructions
+	mu        sync.Mutex              // Held for a whole cycle so batches go out in order
+	queueMu   sync.Mutex              // Guards pending; matching never waits on the custodian
+}
+
+func newSettlementDesk(cfg *config.Config) *settlementDesk {
+	desk := &settlementDesk{}
+	backoff := settlement.Backoff{Base: defaultSettlementRetry}
+	if cfg != nil {
+		desk.netting, desk.batchSize = cfg.SettlementNetting, cfg.SettlementBatchSize
+		if cfg.SettlementRetryInterval > 0 {
+			backoff.Base = cfg.SettlementRetryInterval
+		}
+		backoff.Max = cfg.SettlementRetryMax
+	}
+	desk.outbox = settlement.NewOutbox(backoff)
+	return desk
+}
+
+// SettlementRun is what one settlement cycle generated and submitted
+type SettlementRun struct {
+	Generated    int `json:"generated"`
+	Submitted    int `json:"submitted"`
+	Acknowledged int `json:"acknowledged"`
+	Rejected     int `json:"rejected"`
+	Failed       int `json:"failed"`
+}
+
+// SettlementReport is the outbox's instructions, newest first, with how many are in
+// each status
+type SettlementReport struct {
+	Counts       map[settlement.Status]int `json:"counts"`
+	Instructions []settlement.Instruction  `json:"instructions"`
+}
+
+// EnableSettlement submits settlement instructions for every spot fill to gateway,
+// keeping the outbox in store and resuming what it held; set before serving
+func (s *ExchangeService) EnableSettlement(gateway ports.SettlementGateway, store settlement.Store) error {
+	desk := s.settlement
+	if store != nil {
+		var saved []settlement.Instruction
+		err := s.timeStorage(componentSettlement, "load", nil, func() (err error) {
+			saved, err = store.Load()
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load the settlement outbox: %w", err)
+		}
+		desk.outbox.Restore(saved)
+	}
+	desk.gateway, desk.store = gateway, store
+	return nil
+}
+
+// record queues a fill's obligations for the next cycle
+func (d *settlementDesk) record(obligations []settlement.Obligation) {
+	if d.gateway == nil {
+		return
+	}
+	d.queueMu.Lock()
+	d.pending = append(d.pending, obligations...)
+	d.queueMu.Unlock()
+}
+
+// RunSettlement submits due instructions every retry interval until ctx is done.
+// Fills become gross instructions at each dispatch, or netted ones every netting
+// interval when one is configured.
+func (s *ExchangeService) RunSettlement(ctx context.Context) {
+	desk := s.settlement
+	retry := defaultSettlementRetry
+	if s.config != nil && s.config.SettlementRetryInterval > 0 {
+		retry = s.config.SettlementRetryInterval
+	}
+	dispatch := time.NewTicker(retry)
+	defer dispatch.Stop()
+	var netting <-chan time.Time
+	if desk.netting > 0 {
+		ticker := time.NewTicker(desk.netting)
+		defer ticker.Stop()
+		netting = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-netting:
+			s.settle(ctx, true)
+		case <-dispatch.C:
+			s.settle(ctx, desk.netting == 0)
+		}
+	}
+}
+
+// SettleNow turns every queued fill into instructions, netted when netting is
+// configured, and submits everything due without waiting for the schedule
+func (s *ExchangeService) SettleNow(ctx context.Context) (SettlementRun, error) {
+	if s.settlement.gateway == nil {
+		return SettlementRun{}, rejectf(RejectInvalidRequest, "settlement with the custodian is not enabled")
+	}
+	return s.settle(ctx, true), nil
+}
+
+// Settlements lists the outbox's instructions newest first, optionally with one
+// status or for one account, up to limit; zero means no limit
+func (s *ExchangeService) Settlements(ctx context.Context, status settlement.Status, accountID string, limit int) (SettlementReport, error) {
+	switch status {
+	case "", settlement.StatusPending, settlement.StatusFailed, settlement.StatusAcknowledged, settlement.StatusRejected:
+	default:
+		return SettlementReport{}, rejectf(RejectInvalidRequest, "unknown settlement status %q", status)
+	}
+	if accountID != "" {
+		if err := s.authorizeKey(ctx, accountID, accounts.PermissionRead); err != nil {
+			return SettlementReport{}, err
+		}
+	}
+	outbox := s.settlement.outbox
+	return SettlementReport{Counts: outbox.Counts(), Instructions: outbox.List(status, accountID, limit)}, nil
+}
+
+// settle generates instructions from queued fills when generate is set, then
+// submits what is due, one batch at a time
+func (s *ExchangeService) settle(ctx context.Context, generate bool) SettlementRun {
+	desk := s.settlement
+	desk.mu.Lock()
+	defer desk.mu.Unlock()
+	if desk.gateway == nil {
+		return SettlementRun{}
+	}
+
+	var run SettlementRun
+	now := s.now()
+	if generate {
+		desk.queueMu.Lock()
+		obligations := desk.pending
+		desk.pending = nil
+		desk.queueMu.Unlock()
+		if len(obligations) > 0 {
+			var created []settlement.Instruction
+			if desk.netting > 0 {
+				created = desk.outbox.Net(obligations, now)
+			} else {
+				created = desk.outbox.Gross(obligations, now)
+			}
+			run.Generated = len(created)
+			s.saveSettlement("generate", created)
+		}
+	}
+
+	for _, batch := range s.dueBatches(desk.outbox.Due(now, desk.batchSize)) {
+		run.Submitted += len(batch.Instructions)
+		acks, err := desk.gateway.Submit(ctx, batch)
+		if err != nil {
+			s.saveSettlement("fail", desk.outbox.Fail(batch.Instructions, err, s.now()))
+			s.ReportHealth(ctx, ComponentCustodian, incidents.StatusDegraded, err.Error())
+			run.Failed += len(batch.Instructions)
+			// The rest would meet the same custodian; they wait for the next cycle
+			break
+		}
+		s.ReportHealth(ctx, ComponentCustodian, incidents.StatusUp, "")
+		changed := desk.outbox.Acknowledge(batch.Instructions, acks, s.now())
+		for _, instruction := range changed {
+			switch instruction.Status {
+			case settlement.StatusAcknowledged:
+				run.Acknowledged++
+			case settlement.StatusRejected:
+				run.Rejected++
+				s.logger.WithFields(logrus.Fields{
+					"instruction": instruction.ID,
+					"account":     instruction.AccountID,
+					"asset":       instruction.Asset,
+					"reason":      instruction.LastError,
+				}).Warn("Custodian rejected settlement instruction")
+			default:
+				run.Failed++
+			}
+		}
+		s.saveSettlement("acknowledge", changed)
+	}
+	return run
+}
+
+// dueBatches groups due instructions by the batch they were generated in, so a
+// retry resubmits them together
+func (s *ExchangeService) dueBatches(due []settlement.Instruction) []settlement.Batch {
+	batches := make([]settlement.Batch, 0)
+	index := make(map[string]int)
+	for _, instruction := range due {
+		i, ok := index[instruction.BatchID]
+		if !ok {
+			i = len(batches)
+			index[instruction.BatchID] = i
+			batches = append(batches, settlement.Batch{ID: instruction.BatchID, Venue: s.config.ServiceName})
+		}
+		batches[i].Instructions = append(batches[i].Instructions, instruction)
+	}
+	return batches
+}
+
+// saveSettlement writes changed instructions to the outbox store, if any
+func (s *ExchangeService) saveSettlement(operation string, changed []settlement.Instruction) {
+	store := s.settlement.store
+	if store == nil || len(changed) == 0 {
+		return
+	}
+	err := s.persist(componentSettlement, operation, logrus.Fields{"instructions": len(changed)}, func() error {
+		return store.Save(changed...)
+	})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to persist settlement instructions")
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/settlement"
+)
+
+const (
+	// maxSettlementCycles bounds the cycles kept for inspection; the oldest are dropped first
+	maxSettlementCycles = 1000
+
+	// maxSettlementCatchUp bounds the cutoffs closed in one run, so a large clock jump
+	// cannot stall the scheduler
+	maxSettlementCatchUp = 100
+)
+
+// ErrSettlementCycleNotFound is returned for an unknown settlement cycle ID
+var ErrSettlementCycleNotFound = errors.New("settlement cycle not found")
+
+// settlementCycles nets each trading day's spot trades at the cutoff and follows the
+// day through to settlement
+type settlementCycles struct {
+	schedule settlement.Schedule
+	enabled  bool
+	next     time.Time               // Next cutoff
+	pending  []settlement.Obligation // Trades since the last cutoff
+	cycles   []*settlement.Cycle     // Oldest first
+	mu       sync.Mutex              // Held for a whole run
+	queueMu  sync.Mutex              // Guards pending; matching never waits on a run
+}
+
+func newSettlementCycles() *settlementCycles {
+	return &settlementCycles{cycles: make([]*settlement.Cycle, 0)}
+}
+
+// SettlementCycleQuery filters settlement cycles; empty fields match everything
+type SettlementCycleQuery struct {
+	Status    settlement.CycleStatus
+	AccountID string // Cycles with a position for the account, showing only its positions
+	TradeDate string // 2006-01-02
+}
+
+// EnableSettlementCycles nets spot trades per account and asset at each daily cutoff
+// instead of settling them as they fill, settling each day's positions on the
+// schedule's cycle; set before serving
+func (s *ExchangeService) EnableSettlementCycles(schedule settlement.Schedule) {
+	cycles := s.eodCycles
+	cycles.mu.Lock()
+	defer cycles.mu.Unlock()
+	cycles.schedule, cycles.enabled = schedule, true
+	cycles.next = schedule.NextCutoff(s.now())
+}
+
+// queueSettlement holds a fill's obligations for the day's cutoff when settlement
+// cycles run, otherwise for the custodian straight away
+func (s *ExchangeService) queueSettlement(obligations []settlement.Obligation) {
+	cycles := s.eodCycles
+	if !cycles.enabled {
+		s.settlement.record(obligations)
+		return
+	}
+	cycles.queueMu.Lock()
+	cycles.pending = append(cycles.pending, obligations...)
+	cycles.queueMu.Unlock()
+}
+
+// SettlementCycles lists settlement cycles newest first
+func (s *ExchangeService) SettlementCycles(ctx context.Context, query SettlementCycleQuery) ([]settlement.Cycle, error) {
+	switch query.Status {
+	case "", settlement.CycleNetted, settlement.CycleInstructed, settlement.CycleSettled, settlement.CycleFailed:
+	default:
+		return nil, rejectf(RejectInvalidRequest, "unknown settlement cycle status %q", query.Status)
+	}
+	cycles := s.eodCycles
+	cycles.mu.Lock()
+	defer cycles.mu.Unlock()
+
+	listed := make([]settlement.Cycle, 0)
+	for i := len(cycles.cycles) - 1; i >= 0; i-- {
+		cycle := *cycles.cycles[i]
+		if (query.Status != "" && cycle.Status != query.Status) || (query.TradeDate != "" && cycle.TradeDate != query.TradeDate) {
+			continue
+		}
+		if query.AccountID != "" {
+			positions := make([]settlement.Position, 0)
+			for _, position := range cycle.Positions {
+				if position.AccountID == query.AccountID {
+					positions = append(positions, position)
+				}
+			}
+			if len(positions) == 0 {
+				continue
+			}
+			cycle.Positions = positions
+		}
+		listed = append(listed, cycle)
+	}
+	return listed, nil
+}
+
+// SettlementCycle returns one settlement cycle with its status transitions
+func (s *ExchangeService) SettlementCycle(ctx context.Context, cycleID string) (settlement.Cycle, error) {
+	cycles := s.eodCycles
+	cycles.mu.Lock()
+	defer cycles.mu.Unlock()
+	for _, cycle := range cycles.cycles {
+		if cycle.ID == cycleID {
+			return *cycle, nil
+		}
+	}
+	return settlement.Cycle{}, fmt.Errorf("%w: %s", ErrSettlementCycleNotFound, cycleID)
+}
+
+// runSettlementCycles nets every cutoff that has passed, instructs the cycles whose
+// settlement date has come and settles those the custodian has answered for
+func (s *ExchangeService) runSettlementCycles(ctx context.Context) {
+	cycles := s.eodCycles
+	cycles.mu.Lock()
+	defer cycles.mu.Unlock()
+	if !cycles.enabled {
+		return
+	}
+
+	now := s.now()
+	for i := 0; i < maxSettlementCatchUp && !cycles.next.After(now); i++ {
+		s.closeTradingDay(cycles.next)
+		cycles.next = cycles.next.AddDate(0, 0, 1)
+	}
+	for _, cycle := range cycles.cycles {
+		switch {
+		case cycle.Status == settlement.CycleNetted && !cycle.SettlesAt.After(now):
+			s.instructCycle(cycle, now)
+		case cycle.Status == settlement.CycleInstructed:
+			s.trackCycle(cycle, now)
+		}
+	}
+}
+
+// closeTradingDay nets the trades executed up to cutoff into a new cycle; callers
+// hold the cycles' mu
+func (s *ExchangeService) closeTradingDay(cutoff time.Time) {
+	cycles := s.eodCycles
+	cycles.queueMu.Lock()
+	day := make([]settlement.Obligation, 0, len(cycles.pending))
+	later := make([]settlement.Obligation, 0)
+	for _, obligation := range cycles.pending {
+		if obligation.At.After(cutoff) {
+			later = append(later, obligation)
+		} else {
+			day = append(day, obligation)
+		}
+	}
+	cycles.pending = later
+	cycles.queueMu.Unlock()
+
+	cycle := settlement.NewCycle("eod-"+cutoff.Format("20060102"), cycles.schedule, cutoff, day)
+	cycles.cycles = append(cycles.cycles, &cycle)
+	if len(cycles.cycles) > maxSettlementCycles {
+		cycles.cycles = cycles.cycles[len(cycles.cycles)-maxSettlementCycles:]
+	}
+	s.logger.WithFields(logrus.Fields{
+		"cycle_id":        cycle.ID,
+		"trades":          cycle.Trades,
+		"positions":       len(cycle.Positions),
+		"settlement_date": cycle.SettlementDate,
+	}).Info("Trading day netted for settlement")
+}
+
+// instructCycle sends a cycle's positions to the custodian on its settlement date.
+// Without a custodian, or with nothing to move, the cycle settles on the venue's books.
+func (s *ExchangeService) instructCycle(cycle *settlement.Cycle, now time.Time) {
+	desk := s.settlement
+	if desk.gateway == nil || len(cycle.Positions) == 0 {
+		s.transitionCycle(cycle, settlement.CycleSettled, now, fmt.Sprintf("%d positions settled on the venue's books", len(cycle.Positions)))
+		return
+	}
+	instructions := desk.outbox.Instruct(cycle.Positions, now)
+	s.saveSettlement("generate", instructions)
+	for _, instruction := range instructions {
+		cycle.InstructionIDs = append(cycle.InstructionIDs, instruction.ID)
+	}
+	s.transitionCycle(cycle, settlement.CycleInstructed, now,
+		fmt.Sprintf("%d instructions in %s sent to the custodian", len(instructions), instructions[0].BatchID))
+}
+
+// trackCycle settles a cycle once the custodian has acknowledged every instruction,
+// or fails it once any is rejected
+func (s *ExchangeService) trackCycle(cycle *settlement.Cycle, now time.Time) {
+	acknowledged, rejected := 0, 0
+	for _, id := range cycle.InstructionIDs {
+		switch status, _ := s.settlement.outbox.Status(id); status {
+		case settlement.StatusAcknowledged:
+			acknowledged++
+		case settlement.StatusRejected:
+			rejected++
+		}
+	}
+	switch {
+	case rejected > 0:
+		s.transitionCycle(cycle, settlement.CycleFailed, now, fmt.Sprintf("%d of %d instructions rejected by the custodian", rejected, len(cycle.InstructionIDs)))
+	case acknowledged == len(cycle.InstructionIDs):
+		s.transitionCycle(cycle, settlement.CycleSettled, now, fmt.Sprintf("%d instructions acknowledged by the custodian", acknowledged))
+	}
+}
+
+func (s *ExchangeService) transitionCycle(cycle *settlement.Cycle, status settlement.CycleStatus, at time.Time, detail string) {
+	cycle.Transition(status, at, detail)
+	entry := s.logger.WithFields(logrus.Fields{
+		"cycle_id": cycle.ID,
+		"status":   status,
+		"detail":   detail,
+	})
+	if status == settlement.CycleFailed {
+		entry.Warn("Settlement cycle failed")
+		return
+	}
+	entry.Info("Settlement cycle advanced")
+}
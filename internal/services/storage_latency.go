@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/incidents"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/storagelatency"
+)
+
+// StorageLatencyReport is the recent latency of every storage component's calls
+type StorageLatencyReport struct {
+	SlowThresholdMs float64                `json:"slow_threshold_ms"` // 0 = slow calls are not logged
+	P99LimitMs      float64                `json:"p99_limit_ms"`      // 0 = never degraded
+	Components      []storagelatency.Stats `json:"components"`
+}
+
+// StorageLatency reports each storage component's call counts and percentiles
+func (s *ExchangeService) StorageLatency(ctx context.Context) StorageLatencyReport {
+	config := s.storageLatency.Config()
+	return StorageLatencyReport{
+		SlowThresholdMs: float64(config.SlowThreshold) / float64(time.Millisecond),
+		P99LimitMs:      float64(config.P99Limit) / float64(time.Millisecond),
+		Components:      s.storageLatency.Stats(),
+	}
+}
+
+// timeStorage runs one call to a storage component, logging it with fields when it
+// is slow and reporting storage:latency degraded while any component's p99 is over
+// the limit
+func (s *ExchangeService) timeStorage(component, operation string, fields logrus.Fields, call func() error) error {
+	started := time.Now()
+	err := call()
+	latency := time.Since(started)
+
+	observation := s.storageLatency.Observe(component, operation, latency, err)
+	if observation.Slow {
+		entry := s.logger.WithFields(fields).WithFields(logrus.Fields{
+			"component":    component,
+			"operation":    operation,
+			"duration_ms":  latency.Milliseconds(),
+			"threshold_ms": s.storageLatency.Config().SlowThreshold.Milliseconds(),
+		})
+		if err != nil {
+			entry = entry.WithError(err)
+		}
+		entry.Warn("Slow storage call")
+	}
+	if observation.Changed {
+		s.reportStorageLatency()
+	}
+	return err
+}
+
+// persist times a write to a storage component and reports its health from the outcome
+func (s *ExchangeService) persist(component, operation string, fields logrus.Fields, call func() error) error {
+	err := s.timeStorage(component, operation, fields, call)
+	s.reportOutcome(component, err)
+	return err
+}
+
+func (s *ExchangeService) reportStorageLatency() {
+	breached := s.storageLatency.Breached()
+	if len(breached) == 0 {
+		s.ReportHealth(context.Background(), componentLatency, incidents.StatusUp, "")
+		return
+	}
+	slow := make([]string, 0, len(breached))
+	for _, stats := range s.storageLatency.Stats() {
+		if stats.Breached {
+			slow = append(slow, fmt.Sprintf("%s %.0fms", stats.Component, stats.P99Ms))
+		}
+	}
+	detail := fmt.Sprintf("p99 over %s: %s", s.storageLatency.Config().P99Limit, strings.Join(slow, ", "))
+	s.ReportHealth(context.Background(), componentLatency, incidents.StatusDegraded, detail)
+}
+
+// storageLatencyConfig takes the slow call threshold and p99 limit from config
+func storageLatencyConfig(cfg *config.Config) storagelatency.Config {
+	if cfg == nil {
+		return storagelatency.Config{}
+	}
+	return storagelatency.Config{SlowThreshold: cfg.SlowQueryThreshold, P99Limit: cfg.PersistenceP99Limit}
+}
+
+// timedEventLog times the engine's appends to the event log
+type timedEventLog struct {
+	log     matching.EventLog
+	service *ExchangeService
+}
+
+func (l timedEventLog) Append(event matching.Event) error {
+	return l.service.timeStorage(componentEvents, "append", logrus.Fields{"sequence": event.Sequence, "type": event.Type},
+		func() error { return l.log.Append(event) })
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+var ErrStorageDiverged = errors.New("migration target has diverged from source")
+
+// maxReportedMismatches bounds the sequence list in a verification report
+const maxReportedMismatches = 20
+
+// EventLogBackend is an event log that can be read back in full
+type EventLogBackend interface {
+	matching.EventLog
+	ReadAll() ([]matching.Event, error)
+}
+
+// MigrationReport compares the source and target backends of a storage migration
+type MigrationReport struct {
+	CheckedAt              time.Time `json:"checked_at"`
+	SourceEvents           int       `json:"source_events"`
+	TargetEvents           int       `json:"target_events"`
+	MissingEvents          int       `json:"missing_events"`
+	MismatchedSequences    []uint64  `json:"mismatched_sequences"`
+	SecondaryWriteFailures int64     `json:"secondary_write_failures"`
+	StatisticsInSync       bool      `json:"statistics_in_sync"`
+	InSync                 bool      `json:"in_sync"`
+}
+
+// StorageMigration dual-writes scenario state to an old (source) and new (target)
+// backend. The source stays authoritative: reads come from it and only its write
+// failures reject commands, so the target can be dropped at any point.
+type StorageMigration struct {
+	sourceLog   EventLogBackend
+	targetLog   EventLogBackend
+	sourceStats marketdata.Store
+	targetStats marketdata.Store
+	logger      *logrus.Logger
+
+	secondaryFailures atomic.Int64
+}
+
+// NewStorageMigration pairs source and target backends; either stats store may be nil
+func NewStorageMigration(sourceLog, targetLog EventLogBackend, sourceStats, targetStats marketdata.Store, logger *logrus.Logger) *StorageMigration {
+	return &StorageMigration{
+		sourceLog:   sourceLog,
+		targetLog:   targetLog,
+		sourceStats: sourceStats,
+		targetStats: targetStats,
+		logger:      logger,
+	}
+}
+
+// Backfill copies source events the target is missing and the latest statistics snapshot.
+// It refuses to write if the target already holds events that differ from the source.
+func (m *StorageMigration) Backfill() (int, error) {
+	source, err := m.sourceLog.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source event log: %w", err)
+	}
+	target, err := m.targetLog.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read target event log: %w", err)
+	}
+	if len(target) > len(source) {
+		return 0, fmt.Errorf("%w: target has %d events, source has %d", ErrStorageDiverged, len(target), len(source))
+	}
+	for i := range target {
+		if !sameEvent(source[i], target[i]) {
+			return 0, fmt.Errorf("%w: event %d differs", ErrStorageDiverged, source[i].Sequence)
+		}
+	}
+
+	copied := 0
+	for _, event := range source[len(target):] {
+		if err := m.targetLog.Append(event); err != nil {
+			return copied, fmt.Errorf("failed to backfill event %d: %w", event.Sequence, err)
+		}
+		copied++
+	}
+
+	if m.sourceStats != nil && m.targetStats != nil {
+		state, err := m.sourceStats.Load()
+		if err != nil {
+			return copied, fmt.Errorf("failed to read source statistics: %w", err)
+		}
+		if err := m.targetStats.Save(state); err != nil {
+			return copied, fmt.Errorf("failed to backfill statistics: %w", err)
+		}
+	}
+
+	m.logger.WithField("events", copied).Info("Storage migration backfill complete")
+	return copied, nil
+}
+
+// EventLog returns a log that appends to the source, then mirrors to the target
+func (m *StorageMigration) EventLog() matching.EventLog {
+	return &dualWriteLog{migration: m}
+}
+
+// StatisticsStore returns a store that saves to both backends and loads from the source,
+// or nil when the source has no statistics store
+func (m *StorageMigration) StatisticsStore() marketdata.Store {
+	if m.sourceStats == nil {
+		return nil
+	}
+	return &dualWriteStore{migration: m}
+}
+
+// Verify compares both backends event by event and checks the statistics snapshots match
+func (m *StorageMigration) Verify() (MigrationReport, error) {
+	source, err := m.sourceLog.ReadAll()
+	if err != nil {
+		return MigrationReport{}, fmt.Errorf("failed to read source event log: %w", err)
+	}
+	target, err := m.targetLog.ReadAll()
+	if err != nil {
+		return MigrationReport{}, fmt.Errorf("failed to read target event log: %w", err)
+	}
+
+	report := MigrationReport{
+		CheckedAt:              time.Now().UTC(),
+		SourceEvents:           len(source),
+		TargetEvents:           len(target),
+		MismatchedSequences:    make([]uint64, 0),
+		SecondaryWriteFailures: m.secondaryFailures.Load(),
+		StatisticsInSync:       true,
+	}
+
+	targetBySequence := make(map[uint64]matching.Event, len(target))
+	for _, event := range target {
+		targetBySequence[event.Sequence] = event
+	}
+	mismatches := 0
+	for _, event := range source {
+		mirrored, exists := targetBySequence[event.Sequence]
+		if !exists {
+			report.MissingEvents++
+			continue
+		}
+		if !sameEvent(event, mirrored) {
+			mismatches++
+			if len(report.MismatchedSequences) < maxReportedMismatches {
+				report.MismatchedSequences = append(report.MismatchedSequences, event.Sequence)
+			}
+		}
+	}
+
+	if m.sourceStats != nil && m.targetStats != nil {
+		inSync, err := sameStatistics(m.sourceStats, m.targetStats)
+		if err != nil {
+			return report, err
+		}
+		report.StatisticsInSync = inSync
+	}
+
+	report.InSync = report.MissingEvents == 0 && mismatches == 0 &&
+		report.SourceEvents == report.TargetEvents && report.StatisticsInSync
+	return report, nil
+}
+
+// mirrorFailed counts a failed write to the target without failing the command
+func (m *StorageMigration) mirrorFailed(what string, err error) {
+	m.secondaryFailures.Add(1)
+	m.logger.WithError(err).WithField("write", what).Warn("Storage migration target write failed")
+}
+
+type dualWriteLog struct {
+	migration *StorageMigration
+	mu        sync.Mutex
+}
+
+func (l *dualWriteLog) Append(event matching.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.migration.sourceLog.Append(event); err != nil {
+		return err
+	}
+	if err := l.migration.targetLog.Append(event); err != nil {
+		l.migration.mirrorFailed("event", err)
+	}
+	return nil
+}
+
+// Redact erases from both backends; unlike appends, a target failure is an error
+// because erasure must not leave copies behind
+func (l *dualWriteLog) Redact(rewrite func(matching.Event) (matching.Event, bool)) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	changed, err := redactLog(l.migration.sourceLog, rewrite)
+	if err != nil {
+		return changed, err
+	}
+	if _, err := redactLog(l.migration.targetLog, rewrite); err != nil {
+		return changed, fmt.Errorf("failed to redact migration target: %w", err)
+	}
+	return changed, nil
+}
+
+type dualWriteStore struct {
+	migration *StorageMigration
+}
+
+func (s *dualWriteStore) Save(state marketdata.State) error {
+	if err := s.migration.sourceStats.Save(state); err != nil {
+		return err
+	}
+	if s.migration.targetStats != nil {
+		if err := s.migration.targetStats.Save(state); err != nil {
+			s.migration.mirrorFailed("statistics", err)
+		}
+	}
+	return nil
+}
+
+func (s *dualWriteStore) Load() (marketdata.State, error) {
+	return s.migration.sourceStats.Load()
+}
+
+// sameEvent compares events by their serialized form, which is what each backend stores
+func sameEvent(a, b matching.Event) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func sameStatistics(source, target marketdata.Store) (bool, error) {
+	sourceState, err := source.Load()
+	if err != nil {
+		return false, fmt.Errorf("failed to read source statistics: %w", err)
+	}
+	targetState, err := target.Load()
+	if err != nil {
+		return false, fmt.Errorf("failed to read target statistics: %w", err)
+	}
+	encodedSource, _ := json.Marshal(sourceState)
+	encodedTarget, _ := json.Marshal(targetState)
+	return bytes.Equal(encodedSource, encodedTarget), nil
+}
@@ -0,0 +1,126 @@
+//go:build unit
+
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/marketdata"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/matching"
+)
+
+type memoryBackend struct {
+	events []matching.Event
+	fail   bool
+}
+
+func (b *memoryBackend) Append(event matching.Event) error {
+	if b.fail {
+		return errors.New("backend unavailable")
+	}
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *memoryBackend) ReadAll() ([]matching.Event, error) {
+	return append([]matching.Event(nil), b.events...), nil
+}
+
+type memoryStatsStore struct {
+	state marketdata.State
+}
+
+func (s *memoryStatsStore) Save(state marketdata.State) error {
+	s.state = state
+	return nil
+}
+
+func (s *memoryStatsStore) Load() (marketdata.State, error) {
+	return s.state, nil
+}
+
+func bookAdded(sequence uint64, symbol string) matching.Event {
+	return matching.Event{Sequence: sequence, Type: matching.EventBookAdded, Time: time.Unix(int64(sequence), 0).UTC(), Symbol: symbol}
+}
+
+func newTestMigration(source, target *memoryBackend, sourceStats, targetStats marketdata.Store) *StorageMigration {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewStorageMigration(source, target, sourceStats, targetStats, logger)
+}
+
+func TestStorageMigration(t *testing.T) {
+	t.Run("backfills_then_dual_writes_in_sync", func(t *testing.T) {
+		// Given: A source with history and an empty target
+		source := &memoryBackend{events: []matching.Event{bookAdded(1, "BTC-USD"), bookAdded(2, "ETH-USD")}}
+		target := &memoryBackend{}
+		sourceStats := &memoryStatsStore{state: marketdata.State{Symbols: map[string]marketdata.SymbolState{"BTC-USD": {LastPrice: 100}}}}
+		targetStats := &memoryStatsStore{}
+		migration := newTestMigration(source, target, sourceStats, targetStats)
+
+		// When: The target is backfilled and new writes go through the dual-writers
+		copied, err := migration.Backfill()
+		if err != nil || copied != 2 {
+			t.Fatalf("Expected 2 events backfilled, got %d (err %v)", copied, err)
+		}
+		migration.EventLog().Append(bookAdded(3, "SOL-USD"))
+		migration.StatisticsStore().Save(marketdata.State{Symbols: map[string]marketdata.SymbolState{"BTC-USD": {LastPrice: 101}}})
+
+		// Then: Verification reports both backends in sync
+		report, err := migration.Verify()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !report.InSync || report.SourceEvents != 3 || report.TargetEvents != 3 || !report.StatisticsInSync {
+			t.Errorf("Expected in-sync report, got %+v", report)
+		}
+	})
+
+	t.Run("target_failures_do_not_reject_writes", func(t *testing.T) {
+		source := &memoryBackend{}
+		target := &memoryBackend{fail: true}
+		migration := newTestMigration(source, target, nil, nil)
+
+		if err := migration.EventLog().Append(bookAdded(1, "BTC-USD")); err != nil {
+			t.Fatalf("Expected source write to succeed, got %v", err)
+		}
+
+		report, _ := migration.Verify()
+		if report.InSync || report.MissingEvents != 1 || report.SecondaryWriteFailures != 1 {
+			t.Errorf("Expected one missing event and one failure, got %+v", report)
+		}
+	})
+
+	t.Run("source_failures_reject_writes", func(t *testing.T) {
+		migration := newTestMigration(&memoryBackend{fail: true}, &memoryBackend{}, nil, nil)
+
+		if err := migration.EventLog().Append(bookAdded(1, "BTC-USD")); err == nil {
+			t.Error("Expected source failure to be returned")
+		}
+	})
+
+	t.Run("reports_mismatched_events", func(t *testing.T) {
+		source := &memoryBackend{events: []matching.Event{bookAdded(1, "BTC-USD")}}
+		target := &memoryBackend{events: []matching.Event{bookAdded(1, "ETH-USD")}}
+		migration := newTestMigration(source, target, nil, nil)
+
+		report, _ := migration.Verify()
+		if report.InSync || len(report.MismatchedSequences) != 1 || report.MismatchedSequences[0] != 1 {
+			t.Errorf("Expected sequence 1 mismatched, got %+v", report)
+		}
+	})
+
+	t.Run("refuses_to_backfill_diverged_target", func(t *testing.T) {
+		source := &memoryBackend{events: []matching.Event{bookAdded(1, "BTC-USD")}}
+		target := &memoryBackend{events: []matching.Event{bookAdded(1, "ETH-USD")}}
+		migration := newTestMigration(source, target, nil, nil)
+
+		if _, err := migration.Backfill(); !errors.Is(err, ErrStorageDiverged) {
+			t.Errorf("Expected ErrStorageDiverged, got %v", err)
+		}
+	})
+}
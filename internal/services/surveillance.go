@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/surveillance"
+)
+
+// SetAuditSink forwards every surveillance flag to sink as an audit event
+func (s *ExchangeService) SetAuditSink(sink surveillance.AuditSink) {
+	s.auditSink = sink
+}
+
+// SurveillanceFlags returns flags raised by the venue's detectors, optionally for one account
+func (s *ExchangeService) SurveillanceFlags(ctx context.Context, accountID string) []surveillance.Flag {
+	return s.monitor.Flags(accountID)
+}
+
+// FlaggedAccounts summarizes the accounts the detectors have flagged
+func (s *ExchangeService) FlaggedAccounts(ctx context.Context) []surveillance.AccountSummary {
+	return s.monitor.FlaggedAccounts()
+}
+
+// recordTrades folds executions into market data statistics, run metrics, the trade
+// tape, the audit trail and surveillance
+func (s *ExchangeService) recordTrades(ctx context.Context, trades []models.Trade) {
+	s.statistics.Record(trades...)
+	s.runMetrics.ObserveTrades(trades...)
+	s.observeTrades(trades)
+	s.observeAccountTrades(trades)
+	// Taped before they are audited, so no outbox flush sees an event before its trade
+	for _, trade := range trades {
+		s.tapeTrade(trade)
+	}
+	s.auditTrades(ctx, trades)
+	s.publishFlags(ctx, s.monitor.OnTrades(trades...))
+}
+
+// publishFlags writes each flag to the audit log and, when configured, the audit sink
+func (s *ExchangeService) publishFlags(ctx context.Context, flags []surveillance.Flag) {
+	for _, flag := range flags {
+		s.recordFlagEvent(flag)
+		s.log(ctx).WithFields(logrus.Fields{
+			"audit_event": surveillance.AuditEventType,
+			"flag_id":     flag.ID,
+			"flag_type":   flag.Type,
+			"account":     flag.AccountID,
+			"symbol":      flag.Symbol,
+			"value":       flag.Value,
+			"threshold":   flag.Threshold,
+		}).Warn("Surveillance flag raised")
+
+		if s.auditSink == nil {
+			continue
+		}
+		event := surveillance.AuditEvent{Type: surveillance.AuditEventType, Source: s.config.ServiceInstanceName, Flag: flag}
+		err := s.auditSink.SubmitFlag(ctx, event)
+		if err != nil {
+			s.log(ctx).WithError(err).WithField("flag_id", flag.ID).Error("Failed to submit surveillance audit event")
+		}
+		s.reportOutcome(componentAuditSink, err)
+	}
+}
+
+// surveillanceConfig watches the configured circuit breaker band with the configured detector limits
+func surveillanceConfig(cfg *config.Config) surveillance.Config {
+	monitor := surveillance.DefaultConfig()
+	breaker := circuitBreakerConfig(cfg)
+	monitor.BandThresholdPercent = breaker.ThresholdPercent
+	monitor.BandWindow = breaker.Window
+	if !breaker.Enabled {
+		monitor.BandThresholdPercent = 0
+	}
+	if cfg == nil || cfg.SurveillanceWindow <= 0 {
+		return monitor
+	}
+	monitor.CancelRatio = cfg.SurveillanceCancelRatio
+	monitor.CancelMinOrders = cfg.SurveillanceMinOrders
+	monitor.CancelWindow = cfg.SurveillanceWindow
+	monitor.BandProximity = cfg.SurveillanceProximity
+	return monitor
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/liquidity"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/synthetic"
+)
+
+const (
+	// SyntheticMakerAccount rests the synthetic liquidity ladders
+	SyntheticMakerAccount = "synthetic-mm"
+	// SyntheticTakerAccount prints trades against the ladders so last prices follow the paths
+	SyntheticTakerAccount = "synthetic-taker"
+)
+
+// SyntheticMarketConfig drives the synthetic market: which symbols get a price
+// path, how the paths move, and the liquidity quoted around them
+type SyntheticMarketConfig struct {
+	Symbols       []string           `json:"symbols"`
+	Dynamics      synthetic.Dynamics `json:"dynamics"`
+	Ladder        liquidity.Ladder   `json:"ladder"`
+	TradeNotional float64            `json:"trade_notional"` // Quote currency printed per step (0 = quotes only)
+	Seed          int64              `json:"seed"`           // Same seed, same paths
+}
+
+// SyntheticInstrument is one symbol's synthetic path and the liquidity resting for it
+type SyntheticInstrument struct {
+	Symbol   string    `json:"symbol"`
+	Price    float64   `json:"price"` // Where the path is now
+	Steps    int       `json:"steps"`
+	Jumps    int       `json:"jumps"`
+	Quotes   int       `json:"quotes"` // Ladder orders resting after the last step
+	LastStep time.Time `json:"last_step"`
+}
+
+// syntheticMarket holds a price path per symbol and the ladder orders quoting it
+type syntheticMarket struct {
+	config      SyntheticMarketConfig
+	instruments map[string]*syntheticInstrument
+	mu          sync.Mutex // Held for a whole step
+}
+
+type syntheticInstrument struct {
+	path     *synthetic.Path
+	quotes   []string // Resting ladder order IDs
+	steps    int
+	lastStep time.Time
+}
+
+// EnableSyntheticMarket starts a price path at each symbol's mark price and
+// quotes liquidity around it on every step
+func (s *ExchangeService) EnableSyntheticMarket(cfg SyntheticMarketConfig) error {
+	if len(cfg.Symbols) == 0 {
+		return rejectf(RejectInvalidRequest, "synthetic market needs at least one symbol")
+	}
+	if err := cfg.Dynamics.Validate(); err != nil {
+		return rejectf(RejectInvalidRequest, "invalid synthetic dynamics: %v", err)
+	}
+	if err := cfg.Ladder.Validate(); err != nil {
+		return rejectf(RejectInvalidRequest, "invalid synthetic ladder: %v", err)
+	}
+	if cfg.TradeNotional < 0 {
+		return rejectf(RejectInvalidRequest, "trade notional must not be negative")
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	now := s.now()
+	instruments := make(map[string]*syntheticInstrument, len(cfg.Symbols))
+	symbols := append([]string(nil), cfg.Symbols...)
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		instrument, err := s.instruments.Get(symbol)
+		if err != nil {
+			return err
+		}
+		start, err := s.markPrice(symbol)
+		if err != nil || start <= 0 {
+			start = instrument.ReferencePrice
+		}
+		// Each path draws from its own source, so adding a symbol leaves the others' paths alone
+		pathRNG := rand.New(rand.NewSource(rng.Int63()))
+		instruments[symbol] = &syntheticInstrument{path: synthetic.NewPath(start, cfg.Dynamics, pathRNG), lastStep: now}
+	}
+	s.synthetic = &syntheticMarket{config: cfg, instruments: instruments}
+	return nil
+}
+
+// StepSyntheticMarket advances every path to the current venue time, requotes its
+// ladder and prints a trade towards it. Orchestrators stepping a simulated clock
+// call it after each step.
+func (s *ExchangeService) StepSyntheticMarket(ctx context.Context) {
+	market := s.synthetic
+	if market == nil {
+		return
+	}
+	market.mu.Lock()
+	defer market.mu.Unlock()
+
+	now := s.now()
+	symbols := make([]string, 0, len(market.instruments))
+	for symbol := range market.instruments {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		state := market.instruments[symbol]
+		price := state.path.Step(now.Sub(state.lastStep))
+		state.lastStep = now
+		state.steps++
+		s.requote(ctx, symbol, state, market.config.Ladder.Quotes(price, s.mustInstrument(symbol)))
+		if market.config.TradeNotional > 0 {
+			s.printTowards(ctx, symbol, price, market.config.TradeNotional)
+		}
+	}
+}
+
+// RunSyntheticMarket steps the synthetic market every interval of wall time until ctx is done
+func (s *ExchangeService) RunSyntheticMarket(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.StepSyntheticMarket(ctx)
+		}
+	}
+}
+
+// SyntheticMarket reports each synthetic path, or nothing when the market is off
+func (s *ExchangeService) SyntheticMarket(ctx context.Context) []SyntheticInstrument {
+	market := s.synthetic
+	if market == nil {
+		return []SyntheticInstrument{}
+	}
+	market.mu.Lock()
+	defer market.mu.Unlock()
+
+	report := make([]SyntheticInstrument, 0, len(market.instruments))
+	for symbol, state := range market.instruments {
+		report = append(report, SyntheticInstrument{
+			Symbol:   symbol,
+			Price:    state.path.Price(),
+			Steps:    state.steps,
+			Jumps:    state.path.Jumps(),
+			Quotes:   len(state.quotes),
+			LastStep: state.lastStep,
+		})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Symbol < report[j].Symbol })
+	return report
+}
+
+// requote replaces a symbol's resting ladder with quotes. Orders the venue refuses,
+// e.g. while the symbol is halted, are skipped until the next step.
+func (s *ExchangeService) requote(ctx context.Context, symbol string, state *syntheticInstrument, quotes []liquidity.Quote) {
+	for _, orderID := range state.quotes {
+		// Ladder orders may have filled since the last step
+		s.CancelOrder(ctx, orderID)
+	}
+	state.quotes = state.quotes[:0]
+
+	for _, quote := range quotes {
+		report, err := s.PlaceOrder(ctx, OrderRequest{
+			AccountID:   SyntheticMakerAccount,
+			Symbol:      symbol,
+			Side:        quote.Side,
+			Type:        models.OrderTypeLimit,
+			TimeInForce: models.TimeInForceGTC,
+			Quantity:    quote.Quantity,
+			Price:       quote.Price,
+		})
+		if err != nil {
+			s.logger.WithError(err).WithField("symbol", symbol).Debug("Synthetic quote refused")
+			continue
+		}
+		if !report.Order.Status.IsTerminal() {
+			state.quotes = append(state.quotes, report.Order.ID)
+		}
+	}
+}
+
+// printTowards trades against the ladder in the direction the path moved from the
+// last print, so the last price follows the path
+func (s *ExchangeService) printTowards(ctx context.Context, symbol string, price, notional float64) {
+	instrument := s.mustInstrument(symbol)
+	side := models.SideSell
+	if last, err := s.engine.LastPrice(symbol); err == nil && price >= last {
+		side = models.SideBuy
+	}
+	_, err := s.PlaceOrder(ctx, OrderRequest{
+		AccountID: SyntheticTakerAccount,
+		Symbol:    symbol,
+		Side:      side,
+		Type:      models.OrderTypeMarket,
+		Quantity:  liquidity.Quantity(notional, price, instrument),
+	})
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{"symbol": symbol, "side": side}).Debug("Synthetic print refused")
+	}
+}
+
+// mustInstrument returns a symbol checked when the synthetic market was enabled
+func (s *ExchangeService) mustInstrument(symbol string) models.Instrument {
+	instrument, _ := s.instruments.Get(symbol)
+	return instrument
+}
+
+// SyntheticMarketFromConfig reads the SYNTHETIC_* settings, reporting whether any
+// symbols were configured; an unset seed draws one from the wall clock
+func SyntheticMarketFromConfig(cfg *config.Config) (SyntheticMarketConfig, bool) {
+	if cfg == nil || strings.TrimSpace(cfg.SyntheticSymbols) == "" {
+		return SyntheticMarketConfig{}, false
+	}
+	symbols := make([]string, 0)
+	for _, symbol := range strings.Split(cfg.SyntheticSymbols, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	seed := cfg.SyntheticSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return SyntheticMarketConfig{
+		Symbols: symbols,
+		Dynamics: synthetic.Dynamics{
+			Model:         synthetic.Model(cfg.SyntheticModel),
+			Drift:         cfg.SyntheticDrift,
+			Volatility:    cfg.SyntheticVolatility,
+			JumpIntensity: cfg.SyntheticJumpIntensity,
+			JumpMean:      cfg.SyntheticJumpMean,
+			JumpStdDev:    cfg.SyntheticJumpStdDev,
+		},
+		Ladder: liquidity.Ladder{
+			Levels:        cfg.SyntheticLevels,
+			SpreadBps:     cfg.SyntheticSpreadBps,
+			StepBps:       cfg.SyntheticStepBps,
+			LevelNotional: cfg.SyntheticLevelNotional,
+		},
+		TradeNotional: cfg.SyntheticTradeNotional,
+		Seed:          seed,
+	}, true
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/accounts"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/outbox"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/tradetape"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/writebehind"
+)
+
+// tradeTape queues executed trades between flushes to the store
+type tradeTape struct {
+	store tradetape.Store // nil pages through the engine's bounded history instead
+	queue *writebehind.Queue[models.Trade]
+	mu    sync.Mutex // Held for a whole flush so batches land in tape order
+}
+
+func newTradeTape(config writebehind.Config) *tradeTape {
+	return &tradeTape{queue: writebehind.NewQueue[models.Trade](config)}
+}
+
+// SetTradeStore persists every executed trade to store and answers history queries
+// from it; set before serving
+func (s *ExchangeService) SetTradeStore(store tradetape.Store) {
+	s.tradeTape.store = store
+}
+
+// tapeTrade queues a trade for the next flush, waiting for room while the store is behind
+func (s *ExchangeService) tapeTrade(trade models.Trade) {
+	if s.tradeTape.store == nil {
+		return
+	}
+	s.observeWait(queueTrades, s.tradeTape.queue.Put(trade))
+}
+
+// FlushTradeTape writes the trades queued when it is called, a batch per transaction,
+// and with an outbox the messages queued since the last flush in the last of them. A
+// failed batch stays queued and is retried whole by the next flush.
+func (s *ExchangeService) FlushTradeTape() error {
+	tape := s.tradeTape
+	tape.mu.Lock()
+	defer tape.mu.Unlock()
+	if tape.store == nil {
+		return nil
+	}
+	defer reportQueueDepth(s, queueTrades, tape.queue)
+
+	// Messages are taken first: a trade is queued before the events announcing it, so
+	// every message taken has its trade in the batches this flush writes, or earlier
+	withMessages := s.outboxTrades()
+	var messages []outbox.Message
+	if withMessages != nil {
+		messages = s.outbox.take()
+	}
+	for remaining := tape.queue.Len(); ; {
+		batch := tape.queue.Take()
+		remaining -= len(batch)
+		var carried []outbox.Message
+		if remaining <= 0 {
+			carried = messages
+		}
+		if len(batch) == 0 && len(carried) == 0 {
+			return nil
+		}
+
+		err := s.persist(componentTrades, "append", logrus.Fields{"trades": len(batch), "messages": len(carried)}, func() error {
+			if withMessages != nil {
+				return withMessages.AppendWithMessages(batch, carried)
+			}
+			return tape.store.Append(batch)
+		})
+		tape.queue.Done(batch, err)
+		if err != nil {
+			if withMessages != nil {
+				s.outbox.requeue(messages)
+			}
+			return err
+		}
+		if remaining <= 0 {
+			if withMessages != nil {
+				s.outbox.written(len(messages))
+			}
+			return nil
+		}
+	}
+}
+
+// PersistTrades flushes the trade tape every interval, and as soon as a full batch is
+// queued, until ctx is done
+func (s *ExchangeService) PersistTrades(ctx context.Context, interval time.Duration) {
+	s.tradeTape.queue.Run(ctx, interval, s.FlushTradeTape, func(err error) {
+		s.logger.WithError(err).Warn("Failed to persist trades")
+	})
+}
+
+// TradeHistory pages through executed trades oldest first. With a trade store set
+// the whole tape is queried, otherwise only the trades the engine still holds.
+func (s *ExchangeService) TradeHistory(ctx context.Context, query tradetape.Query) (tradetape.Page, error) {
+	if query.Symbol != "" {
+		if _, err := s.instruments.Get(query.Symbol); err != nil {
+			return tradetape.Page{}, err
+		}
+	}
+	if err := query.Validate(); err != nil {
+		return tradetape.Page{}, rejectf(RejectInvalidRequest, "%s", err.Error())
+	}
+	if query.AccountID != "" {
+		if err := s.authorizeKey(ctx, query.AccountID, accounts.PermissionRead); err != nil {
+			return tradetape.Page{}, err
+		}
+	}
+
+	if s.tradeTape.store == nil {
+		trades, err := s.engine.Trades(query.Symbol, query.AccountID, 0)
+		if err != nil {
+			return tradetape.Page{}, err
+		}
+		return tradetape.Paginate(trades, query), nil
+	}
+	// Trades not yet flushed would otherwise be missing from the latest page
+	if err := s.FlushTradeTape(); err != nil {
+		return tradetape.Page{}, err
+	}
+	var page tradetape.Page
+	err := s.timeStorage(componentTrades, "query", logrus.Fields{"symbol": query.Symbol, "account": query.AccountID}, func() (err error) {
+		page, err = s.tradeTape.store.Query(query)
+		return err
+	})
+	return page, err
+}
+
+// ParseTradeCursor decodes a cursor from a previous page of trade history
+func ParseTradeCursor(cursor string) (tradetape.Cursor, error) {
+	parsed, err := tradetape.ParseCursor(cursor)
+	if errors.Is(err, tradetape.ErrInvalidCursor) {
+		return tradetape.Cursor{}, rejectf(RejectInvalidRequest, "cursor was not issued by this venue")
+	}
+	return parsed, err
+}
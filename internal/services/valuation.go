@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/fx"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/ledger"
+)
+
+// defaultReportingCurrency applies when neither the request nor config names one
+const defaultReportingCurrency = "USD"
+
+// ValuedPosition is a ledger position converted into the reporting currency
+type ValuedPosition struct {
+	ledger.Position
+	Rate  float64  `json:"rate"`  // Reporting currency per unit of the asset
+	Value float64  `json:"value"` // Quantity * Rate
+	Path  []string `json:"path"`  // Books the rate was derived through
+}
+
+// AccountValuation is an account's positions and their total in one currency
+type AccountValuation struct {
+	AccountID string           `json:"account_id"`
+	Currency  string           `json:"currency"`
+	Positions []ValuedPosition `json:"positions"`
+	Total     float64          `json:"total"`
+	Unpriced  []string         `json:"unpriced"` // Assets with no marked path to the currency; left out of Total
+	ValuedAt  time.Time        `json:"valued_at"`
+}
+
+// AccountValuation values an account's net positions in currency (empty = the configured
+// reporting currency) at current marks, chaining books where no direct one is listed
+func (s *ExchangeService) AccountValuation(ctx context.Context, accountID, currency string) (AccountValuation, error) {
+	if currency == "" {
+		currency = s.config.ReportingCurrency
+	}
+	if currency == "" {
+		currency = defaultReportingCurrency
+	}
+	accountLedger, err := s.AccountLedger(ctx, accountID)
+	if err != nil {
+		return AccountValuation{}, err
+	}
+	converter := s.converter()
+	if !converter.Knows(currency) {
+		return AccountValuation{}, rejectf(RejectInvalidRequest, "no listed book prices %s", currency)
+	}
+
+	valuation := AccountValuation{
+		AccountID: accountID,
+		Currency:  currency,
+		Positions: make([]ValuedPosition, 0, len(accountLedger.Positions)),
+		Unpriced:  make([]string, 0),
+		ValuedAt:  s.now(),
+	}
+	for _, position := range accountLedger.Positions {
+		conversion, err := converter.Rate(position.Asset, currency)
+		if errors.Is(err, fx.ErrNoRate) {
+			valuation.Unpriced = append(valuation.Unpriced, position.Asset)
+			continue
+		}
+		value := position.Quantity * conversion.Rate
+		valuation.Positions = append(valuation.Positions, ValuedPosition{
+			Position: position,
+			Rate:     conversion.Rate,
+			Value:    value,
+			Path:     conversion.Path,
+		})
+		valuation.Total += value
+	}
+	return valuation, nil
+}
+
+// converter builds an FX graph from the mark of every spot book; derivatives track
+// their underlying and would only duplicate it
+func (s *ExchangeService) converter() *fx.Converter {
+	marks := make([]fx.Mark, 0)
+	for _, instrument := range s.instruments.List() {
+		if instrument.IsDerivative() {
+			continue
+		}
+		price, err := s.markPrice(instrument.Symbol)
+		if err != nil {
+			continue
+		}
+		marks = append(marks, fx.Mark{
+			Symbol: instrument.Symbol,
+			Base:   instrument.BaseAsset,
+			Quote:  instrument.QuoteAsset,
+			Price:  price,
+		})
+	}
+	return fx.NewConverter(marks...)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/exchange-simulator-go/internal/domain/writebehind"
+)
+
+// Write-behind queues, as labeled in metrics and stats
+const (
+	queueTrades = "trades"
+	queueOrders = "orders"
+)
+
+func writeBehindConfig(cfg *config.Config) writebehind.Config {
+	if cfg == nil {
+		return writebehind.DefaultConfig()
+	}
+	return writebehind.Config{Capacity: cfg.WriteBehindQueueSize, BatchSize: cfg.WriteBehindBatchSize, MaxWait: cfg.WriteBehindMaxWait}
+}
+
+// observeWait reports a producer that found a write-behind queue full: matching was
+// held back until the store caught up, or queued past the bound when it did not
+func (s *ExchangeService) observeWait(queue string, wait writebehind.Wait) {
+	if wait.Waited == 0 && !wait.Overflowed {
+		return
+	}
+	metrics := s.config.GetMetricsPort()
+	if metrics == nil {
+		return
+	}
+	labels := map[string]string{"queue": queue}
+	metrics.IncCounter("exchange_write_behind_waits_total", labels)
+	metrics.ObserveHistogram("exchange_write_behind_wait_seconds", wait.Waited.Seconds(), labels)
+	if wait.Overflowed {
+		metrics.IncCounter("exchange_write_behind_overflow_total", labels)
+	}
+}
+
+// reportQueueDepth sets the gauge of what a write-behind queue still holds
+func reportQueueDepth[T any](s *ExchangeService, queue string, q *writebehind.Queue[T]) {
+	if metrics := s.config.GetMetricsPort(); metrics != nil {
+		metrics.SetGauge("exchange_write_behind_queued", float64(q.Len()), map[string]string{"queue": queue})
+	}
+}
+
+// WriteBehindStats reports the depth and throughput of each write-behind queue in use
+func (s *ExchangeService) WriteBehindStats(ctx context.Context) map[string]writebehind.Stats {
+	stats := make(map[string]writebehind.Stats, 2)
+	if s.tradeTape.store != nil {
+		stats[queueTrades] = s.tradeTape.queue.Stats()
+	}
+	if s.orderJournal.store != nil {
+		stats[queueOrders] = s.orderJournal.queue.Stats()
+	}
+	return stats
+}